@@ -0,0 +1,161 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package account
+
+import (
+	"hcm/cmd/cloud-server/service/sync/lock"
+	syncflow "hcm/cmd/cloud-server/service/sync/sync-flow"
+	proto "hcm/pkg/api/cloud-server/account"
+	"hcm/pkg/api/core"
+	protocloud "hcm/pkg/api/data-service/cloud"
+	hcsync "hcm/pkg/api/hc-service/sync"
+	"hcm/pkg/cc"
+	"hcm/pkg/client"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/runtime/filter"
+	"hcm/pkg/tools/slice"
+)
+
+// SelectiveSync 账号定向同步，按地域、资源类型或指定的云ID、名称创建定向同步任务流，返回任务流ID，同步在 task-server 中异步执行。
+// 定向同步不占用账号同步锁，与全量同步的频率限制相互独立，同一账号在 selectiveSyncLimitingTimeSec 内只能发起一次定向同步。
+func SelectiveSync(kt *kit.Kit, cli *client.ClientSet, vendor enumor.Vendor, accountID string,
+	req *proto.SelectiveSyncReq) (*proto.SelectiveSyncResult, error) {
+
+	result := new(proto.SelectiveSyncResult)
+	opt := &syncflow.SelectiveOption{AccountID: accountID, Regions: req.Regions, ResTypes: req.ResTypes}
+	if len(req.CloudIDs) != 0 || len(req.Names) != 0 {
+		target, unresolved, err := buildCloudIDSyncTarget(kt, cli.DataService().Global.Cloud, vendor, accountID, req)
+		if err != nil {
+			return nil, err
+		}
+		result.UnresolvedNames = unresolved
+
+		if len(target.CloudIDs) == 0 {
+			return nil, errf.Newf(errf.RecordNotFound, "%s of names: %v not found", req.ResTypes[0], req.Names)
+		}
+
+		if err = target.Validate(vendor); err != nil {
+			return nil, errf.NewFromErr(errf.InvalidParameter, err)
+		}
+		opt.Targets = []hcsync.CloudIDSyncReq{*target}
+	}
+
+	limit := int64(cc.CloudServer().CloudResource.Sync.SelectiveSyncLimitingTimeSec)
+	if err := lock.Manager.TryRateLimit(accountID, limit); err != nil {
+		if err == lock.ErrRateLimited {
+			return nil, errf.Newf(errf.TooManyRequest, "account: %s selective sync is limited to once every %d "+
+				"seconds", accountID, limit)
+		}
+		return nil, err
+	}
+
+	flowID, err := syncflow.CreateSelectiveSyncFlow(kt, cli, vendor, opt)
+	if err != nil {
+		logs.Errorf("create selective sync flow failed, err: %v, account: %s, req: %+v, rid: %s", err, accountID,
+			req, kt.Rid)
+		return nil, err
+	}
+	result.FlowID = flowID
+
+	return result, nil
+}
+
+// resBasicInfoLister 按条件查询资源的基础信息，用于将资源名称解析为云ID。
+type resBasicInfoLister interface {
+	ListResBasicInfoByFilter(kt *kit.Kit, req *protocloud.ListResourceBasicInfoByFilterReq) (
+		*protocloud.ListResourceBasicInfoByFilterResult, error)
+}
+
+// buildCloudIDSyncTarget 构建按云ID定向同步的目标，名称根据已同步到本地的资源解析为云ID，返回未解析到资源的名称。
+func buildCloudIDSyncTarget(kt *kit.Kit, lister resBasicInfoLister, vendor enumor.Vendor, accountID string,
+	req *proto.SelectiveSyncReq) (*hcsync.CloudIDSyncReq, []string, error) {
+
+	region := ""
+	if len(req.Regions) != 0 {
+		region = req.Regions[0]
+	}
+
+	target := &hcsync.CloudIDSyncReq{
+		AccountID:  accountID,
+		ResType:    req.ResTypes[0],
+		Zone:       req.Zone,
+		CloudVpcID: req.CloudVpcID,
+		CloudIDs:   slice.Unique(req.CloudIDs),
+	}
+	// azure 的同步范围为资源组
+	if vendor == enumor.Azure {
+		target.ResourceGroupName = region
+	} else {
+		target.Region = region
+	}
+
+	if len(req.Names) == 0 {
+		return target, nil, nil
+	}
+
+	names := slice.Unique(req.Names)
+	rules := []filter.RuleFactory{
+		&filter.AtomRule{Field: "account_id", Op: filter.Equal.Factory(), Value: accountID},
+		&filter.AtomRule{Field: "name", Op: filter.In.Factory(), Value: names},
+	}
+	// tcloud/aws/huawei 的资源均按地域划分，只解析指定地域下的资源
+	if len(region) != 0 && (vendor == enumor.TCloud || vendor == enumor.Aws || vendor == enumor.HuaWei) {
+		rules = append(rules, &filter.AtomRule{Field: "region", Op: filter.Equal.Factory(), Value: region})
+	}
+
+	listReq := &protocloud.ListResourceBasicInfoByFilterReq{
+		ResourceType: target.ResType,
+		Filter:       &filter.Expression{Op: filter.And, Rules: rules},
+		Fields:       []string{"cloud_id", "name"},
+		Page:         core.NewDefaultBasePage(),
+	}
+	resolved := make(map[string]struct{}, len(names))
+	for {
+		infos, err := lister.ListResBasicInfoByFilter(kt, listReq)
+		if err != nil {
+			logs.Errorf("list %s by names failed, err: %v, account: %s, names: %v, rid: %s", target.ResType, err,
+				accountID, names, kt.Rid)
+			return nil, nil, err
+		}
+
+		for _, info := range infos.Details {
+			resolved[info.Name] = struct{}{}
+			target.CloudIDs = append(target.CloudIDs, info.CloudID)
+		}
+
+		if uint(len(infos.Details)) < listReq.Page.Limit {
+			break
+		}
+		listReq.Page.Start += uint32(listReq.Page.Limit)
+	}
+	target.CloudIDs = slice.Unique(target.CloudIDs)
+
+	unresolved := make([]string, 0)
+	for _, name := range names {
+		if _, exists := resolved[name]; !exists {
+			unresolved = append(unresolved, name)
+		}
+	}
+
+	return target, unresolved, nil
+}
@@ -0,0 +1,241 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package account
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"testing"
+
+	proto "hcm/pkg/api/cloud-server/account"
+	"hcm/pkg/api/core"
+	protocloud "hcm/pkg/api/data-service/cloud"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/dal/dao/types"
+	"hcm/pkg/kit"
+	"hcm/pkg/runtime/filter"
+	"hcm/pkg/tools/slice"
+)
+
+// fakeResLister 按 account_id、name、region 条件从 infos 中分页查询资源，记录每次查询的请求。
+type fakeResLister struct {
+	infos []types.CloudResourceBasicInfo
+	err   error
+	reqs  []protocloud.ListResourceBasicInfoByFilterReq
+}
+
+// ListResBasicInfoByFilter ...
+func (f *fakeResLister) ListResBasicInfoByFilter(_ *kit.Kit, req *protocloud.ListResourceBasicInfoByFilterReq) (
+	*protocloud.ListResourceBasicInfoByFilterResult, error) {
+
+	page := *req.Page
+	one := *req
+	one.Page = &page
+	f.reqs = append(f.reqs, one)
+	if f.err != nil {
+		return nil, f.err
+	}
+
+	matched := make([]types.CloudResourceBasicInfo, 0)
+	for _, info := range f.infos {
+		if f.match(req.Filter, info) {
+			matched = append(matched, info)
+		}
+	}
+
+	start := int(req.Page.Start)
+	if start > len(matched) {
+		start = len(matched)
+	}
+	end := start + int(req.Page.Limit)
+	if end > len(matched) {
+		end = len(matched)
+	}
+	return &protocloud.ListResourceBasicInfoByFilterResult{Details: matched[start:end]}, nil
+}
+
+func (f *fakeResLister) match(expr *filter.Expression, info types.CloudResourceBasicInfo) bool {
+	for _, rule := range expr.Rules {
+		atom := rule.(*filter.AtomRule)
+		switch atom.Field {
+		case "account_id":
+			if info.AccountID != atom.Value {
+				return false
+			}
+		case "region":
+			if info.Region != atom.Value {
+				return false
+			}
+		case "name":
+			if !slice.IsItemInSlice(atom.Value.([]string), info.Name) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// regionRule 返回查询请求中的地域条件，不存在时返回空。
+func regionRule(req protocloud.ListResourceBasicInfoByFilterReq) string {
+	for _, rule := range req.Filter.Rules {
+		if atom := rule.(*filter.AtomRule); atom.Field == "region" {
+			return atom.Value.(string)
+		}
+	}
+	return ""
+}
+
+func TestBuildCloudIDSyncTarget(t *testing.T) {
+	infos := []types.CloudResourceBasicInfo{
+		{AccountID: "0001", Region: "ap-guangzhou", CloudID: "ins-1", Name: "web"},
+		{AccountID: "0001", Region: "ap-guangzhou", CloudID: "ins-2", Name: "web"},
+		{AccountID: "0001", Region: "ap-shanghai", CloudID: "ins-3", Name: "db"},
+		{AccountID: "0002", Region: "ap-guangzhou", CloudID: "ins-4", Name: "db"},
+	}
+
+	cases := []struct {
+		name   string
+		vendor enumor.Vendor
+		req    *proto.SelectiveSyncReq
+		// expectCloudIDs 期望的同步云ID，expectUnresolved 期望未解析的名称
+		expectCloudIDs   []string
+		expectUnresolved []string
+		// expectRegion 期望目标中的地域，expectRG 期望目标中的资源组
+		expectRegion string
+		expectRG     string
+		// expectCalls 期望查询资源的次数，expectRegionRule 期望查询条件中的地域
+		expectCalls      int
+		expectRegionRule string
+	}{
+		{
+			name:   "cloud ids only",
+			vendor: enumor.TCloud,
+			req: &proto.SelectiveSyncReq{Regions: []string{"ap-guangzhou"},
+				ResTypes: []enumor.CloudResourceType{enumor.CvmCloudResType}, CloudIDs: []string{"ins-9", "ins-9"}},
+			expectCloudIDs:   []string{"ins-9"},
+			expectUnresolved: nil,
+			expectRegion:     "ap-guangzhou",
+			expectCalls:      0,
+		},
+		{
+			name:   "names in region",
+			vendor: enumor.TCloud,
+			req: &proto.SelectiveSyncReq{Regions: []string{"ap-guangzhou"},
+				ResTypes: []enumor.CloudResourceType{enumor.CvmCloudResType}, CloudIDs: []string{"ins-1"},
+				Names: []string{"web", "db", "cache", "web"}},
+			expectCloudIDs:   []string{"ins-1", "ins-2"},
+			expectUnresolved: []string{"db", "cache"},
+			expectRegion:     "ap-guangzhou",
+			expectCalls:      1,
+			expectRegionRule: "ap-guangzhou",
+		},
+		{
+			name:   "names without region",
+			vendor: enumor.Aws,
+			req: &proto.SelectiveSyncReq{ResTypes: []enumor.CloudResourceType{enumor.CvmCloudResType},
+				Names: []string{"db"}},
+			expectCloudIDs:   []string{"ins-3"},
+			expectUnresolved: []string{},
+			expectCalls:      1,
+		},
+		{
+			name:   "azure resource group",
+			vendor: enumor.Azure,
+			req: &proto.SelectiveSyncReq{Regions: []string{"rg-1"},
+				ResTypes: []enumor.CloudResourceType{enumor.CvmCloudResType}, Names: []string{"db"}},
+			expectCloudIDs:   []string{"ins-3"},
+			expectUnresolved: []string{},
+			expectRG:         "rg-1",
+			expectCalls:      1,
+		},
+	}
+
+	for _, c := range cases {
+		lister := &fakeResLister{infos: infos}
+		target, unresolved, err := buildCloudIDSyncTarget(kit.New(), lister, c.vendor, "0001", c.req)
+		if err != nil {
+			t.Errorf("case %s build target failed, err: %v", c.name, err)
+			continue
+		}
+
+		if !reflect.DeepEqual(target.CloudIDs, c.expectCloudIDs) {
+			t.Errorf("case %s expect cloud ids %v, got %v", c.name, c.expectCloudIDs, target.CloudIDs)
+		}
+		if !reflect.DeepEqual(unresolved, c.expectUnresolved) {
+			t.Errorf("case %s expect unresolved %v, got %v", c.name, c.expectUnresolved, unresolved)
+		}
+		if target.Region != c.expectRegion || target.ResourceGroupName != c.expectRG {
+			t.Errorf("case %s expect region %q rg %q, got %q %q", c.name, c.expectRegion, c.expectRG,
+				target.Region, target.ResourceGroupName)
+		}
+		if target.AccountID != "0001" || target.ResType != enumor.CvmCloudResType {
+			t.Errorf("case %s target account or res type is invalid: %+v", c.name, target)
+		}
+
+		if len(lister.reqs) != c.expectCalls {
+			t.Errorf("case %s expect %d list calls, got %d", c.name, c.expectCalls, len(lister.reqs))
+			continue
+		}
+		for _, req := range lister.reqs {
+			if got := regionRule(req); got != c.expectRegionRule {
+				t.Errorf("case %s expect region rule %q, got %q", c.name, c.expectRegionRule, got)
+			}
+		}
+	}
+}
+
+func TestBuildCloudIDSyncTargetPaging(t *testing.T) {
+	lister := &fakeResLister{}
+	req := &proto.SelectiveSyncReq{ResTypes: []enumor.CloudResourceType{enumor.CvmCloudResType},
+		Names: []string{"web"}}
+
+	// 第一页查满时继续查询下一页
+	limit := int(core.DefaultMaxPageLimit)
+	for i := 0; i < limit+1; i++ {
+		lister.infos = append(lister.infos, types.CloudResourceBasicInfo{AccountID: "0001", Name: "web",
+			CloudID: "ins-" + strconv.Itoa(i)})
+	}
+
+	target, unresolved, err := buildCloudIDSyncTarget(kit.New(), lister, enumor.Gcp, "0001", req)
+	if err != nil {
+		t.Fatalf("build target failed, err: %v", err)
+	}
+
+	if len(lister.reqs) != 2 {
+		t.Fatalf("expect 2 list calls, got %d", len(lister.reqs))
+	}
+	if lister.reqs[1].Page.Start != uint32(limit) {
+		t.Errorf("expect second page start %d, got %d", limit, lister.reqs[1].Page.Start)
+	}
+	if len(target.CloudIDs) != limit+1 || len(unresolved) != 0 {
+		t.Errorf("expect %d cloud ids and no unresolved, got %d, %v", limit+1, len(target.CloudIDs), unresolved)
+	}
+}
+
+func TestBuildCloudIDSyncTargetError(t *testing.T) {
+	lister := &fakeResLister{err: errors.New("list failed")}
+	req := &proto.SelectiveSyncReq{ResTypes: []enumor.CloudResourceType{enumor.CvmCloudResType},
+		Names: []string{"web"}}
+
+	if _, _, err := buildCloudIDSyncTarget(kit.New(), lister, enumor.TCloud, "0001", req); err == nil {
+		t.Errorf("expect list error returned, got nil")
+	}
+}
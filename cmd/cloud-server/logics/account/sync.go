@@ -22,17 +22,19 @@ package account
 import (
 	"errors"
 	"fmt"
-	"strings"
 
+	"hcm/cmd/cloud-server/logics/assign"
 	"hcm/cmd/cloud-server/service/sync/aws"
 	"hcm/cmd/cloud-server/service/sync/azure"
 	"hcm/cmd/cloud-server/service/sync/gcp"
 	"hcm/cmd/cloud-server/service/sync/huawei"
 	"hcm/cmd/cloud-server/service/sync/lock"
+	syncflow "hcm/cmd/cloud-server/service/sync/sync-flow"
 	"hcm/cmd/cloud-server/service/sync/tcloud"
 	"hcm/pkg/api/core"
 	protoregion "hcm/pkg/api/data-service/cloud/region"
 	protocloud "hcm/pkg/api/data-service/cloud/zone"
+	"hcm/pkg/cc"
 	"hcm/pkg/client"
 	dataservice "hcm/pkg/client/data-service"
 	"hcm/pkg/criteria/constant"
@@ -40,8 +42,6 @@ import (
 	"hcm/pkg/dal/dao/tools"
 	"hcm/pkg/kit"
 	"hcm/pkg/logs"
-
-	etcd3 "go.etcd.io/etcd/client/v3"
 )
 
 // Sync 账号同步。该操作同一账号不可并行执行，且是异步同步。
@@ -54,7 +54,7 @@ func Sync(kt *kit.Kit, cli *client.ClientSet, vendor enumor.Vendor, accountID st
 		return err
 	}
 
-	leaseID, err := lock.Manager.TryLock(lock.Key(accountID))
+	syncLock, err := lock.Manager.TryLock(accountID)
 	if err != nil {
 		if err == lock.ErrLockFailed {
 			return errors.New("synchronization is in progress")
@@ -63,25 +63,27 @@ func Sync(kt *kit.Kit, cli *client.ClientSet, vendor enumor.Vendor, accountID st
 		return err
 	}
 
-	go func(leaseID etcd3.LeaseID) {
+	go func(syncLock *lock.Lock) {
 		defer func() {
-			if err := lock.Manager.UnLock(leaseID); err != nil {
-				// 锁已经超时释放了
-				if strings.Contains(err.Error(), "requested lease not found") {
-					return
-				}
-
-				logs.Errorf("%s: unlock account sync lock failed, err: %v, accountID: %s, leaseID: %d, rid: %s",
-					constant.AccountSyncFailed, err, accountID, leaseID, kt.Rid)
+			if err := syncLock.UnLock(); err != nil {
+				logs.Errorf("%s: unlock account sync lock failed, err: %v, accountID: %s, token: %s, rid: %s",
+					constant.AccountSyncFailed, err, accountID, syncLock.Token().String(), kt.Rid)
 			}
 		}()
 
-		err = SyncAllResource(kt, cli, vendor, accountID, isNeedSyncPublicResFlag)
+		lockKt := syncLock.Kit(kt)
+		err = SyncAllResource(lockKt, cli, vendor, accountID, isNeedSyncPublicResFlag)
+		if lockErr := syncLock.Err(); lockErr != nil {
+			err = lockErr
+		}
 		if err != nil {
 			logs.Errorf("sync account: %s failed, err: %v, rid: %s", accountID, err, kt.Rid)
+			return
 		}
 
-	}(leaseID)
+		assign.EvaluateRuleAfterSync(lockKt, cli.DataService(), accountID)
+
+	}(syncLock)
 
 	return nil
 }
@@ -214,6 +216,17 @@ func isNeedSyncRegion(kt *kit.Kit, dataCli *dataservice.Client, vendor enumor.Ve
 func SyncAllResource(kt *kit.Kit, cli *client.ClientSet, vendor enumor.Vendor,
 	accountID string, isNeed bool) error {
 
+	if cc.CloudServer().CloudResource.Sync.RunAsFlow {
+		opt := &syncflow.Option{AccountID: accountID, SyncPublicResource: isNeed}
+		flowID, err := syncflow.SyncAccount(kt, cli, vendor, opt)
+		if err != nil {
+			logs.Errorf("sync account by flow failed, err: %v, flow: %s, account: %s, rid: %s", err, flowID,
+				accountID, kt.Rid)
+			return err
+		}
+		return nil
+	}
+
 	var resType enumor.CloudResourceType
 	var err error
 	switch vendor {
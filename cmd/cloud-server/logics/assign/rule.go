@@ -0,0 +1,45 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package assign ...
+package assign
+
+import (
+	protocloud "hcm/pkg/api/data-service/cloud"
+	dataservice "hcm/pkg/client/data-service"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+)
+
+// EvaluateRuleAfterSync 账号资源同步完成后，根据业务自动分配规则将账号下新同步的未分配资源分配到业务下。
+// 规则评估失败不影响同步结果，只记录日志。
+func EvaluateRuleAfterSync(kt *kit.Kit, cli *dataservice.Client, accountID string) {
+	req := &protocloud.BizAssignRuleEvaluateReq{AccountID: accountID}
+	result, err := cli.Global.BizAssignRule.Evaluate(kt, req)
+	if err != nil {
+		logs.Errorf("evaluate biz assign rule after sync failed, err: %v, account: %s, rid: %s", err, accountID,
+			kt.Rid)
+		return
+	}
+
+	for _, one := range result.Details {
+		logs.Infof("biz assign rule %s assigned %d %s to biz %d, account: %s, rid: %s", one.RuleID, len(one.ResIDs),
+			one.ResType, one.BkBizID, accountID, kt.Rid)
+	}
+}
@@ -49,7 +49,7 @@ func WaitTaskToEnd(kt *kit.Kit, cli *taskserver.Client, id string) error {
 			return err
 		}
 
-		if flow.State == enumor.FlowFailed {
+		if flow.State == enumor.FlowFailed || flow.State == enumor.FlowRolledBack {
 			// 临时方案，选取一个错误当作错误原因
 			req := &core.ListReq{
 				Filter: tools.EqualWithOpExpression(filter.And, map[string]interface{}{
@@ -305,7 +305,7 @@ func (c *cvm) checkAndUnbindCvmRelated(kt *kit.Kit, cvmBasicInfo map[string]type
 	for cvmId, now := range cvmStatus {
 		origin := originDetails[cvmId]
 		if origin.WithEip {
-			newData, changed, deleted := common.Diff(now.EipList, origin.EipList,
+			newData, changed, deleted := common.Diff(kt, enumor.EipCloudResType, now.EipList, origin.EipList,
 				func(now corerecord.EipBindInfo, origin corerecord.EipBindInfo) bool {
 					return origin.NicID != now.NicID
 				})
@@ -315,7 +315,7 @@ func (c *cvm) checkAndUnbindCvmRelated(kt *kit.Kit, cvmBasicInfo map[string]type
 			}
 		}
 		if origin.WithDisk {
-			newData, changed, deleted := common.Diff(now.DiskList, origin.DiskList,
+			newData, changed, deleted := common.Diff(kt, enumor.DiskCloudResType, now.DiskList, origin.DiskList,
 				func(now corerecord.DiskAttachInfo, origin corerecord.DiskAttachInfo) bool {
 					return origin.DeviceName != now.DeviceName || origin.CachingType != now.CachingType
 				})
@@ -134,6 +134,11 @@ func (a *accountSvc) getAccountSyncDetail(cts *rest.Contexts, accountID string,
 	status := ""
 	failedReason := ""
 	for _, one := range accountSyncDetail.Details {
+		// 增量同步检查点不是资源的同步状态
+		if one.ResName == enumor.IncrementalSyncCheckpoint {
+			continue
+		}
+
 		status = one.ResStatus
 		if one.ResStatus == string(enumor.SyncFailed) {
 			failedReason = string(one.ResFailedReason)
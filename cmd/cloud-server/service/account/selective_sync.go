@@ -0,0 +1,132 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package account
+
+import (
+	"encoding/json"
+
+	logicsaccount "hcm/cmd/cloud-server/logics/account"
+	proto "hcm/pkg/api/cloud-server/account"
+	"hcm/pkg/api/core"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/iam/meta"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+)
+
+// SelectiveSync 账号定向同步，返回定向同步任务流ID。
+func (a *accountSvc) SelectiveSync(cts *rest.Contexts) (interface{}, error) {
+	accountID := cts.PathParameter("account_id").String()
+
+	req := new(proto.SelectiveSyncReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	// 校验用户有该账号的更新权限
+	if err := a.checkPermission(cts, meta.Update, accountID); err != nil {
+		return nil, err
+	}
+
+	baseInfo, err := a.client.DataService().Global.Cloud.GetResBasicInfo(cts.Kit,
+		enumor.AccountCloudResType, accountID)
+	if err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	return logicsaccount.SelectiveSync(cts.Kit, a.client, baseInfo.Vendor, accountID, req)
+}
+
+// GetSelectiveSync 查询账号定向同步任务流的执行进度。
+func (a *accountSvc) GetSelectiveSync(cts *rest.Contexts) (interface{}, error) {
+	accountID := cts.PathParameter("account_id").String()
+	flowID := cts.PathParameter("flow_id").String()
+
+	// 校验用户有该账号的查看权限
+	if err := a.checkPermission(cts, meta.Find, accountID); err != nil {
+		return nil, err
+	}
+
+	flow, err := a.client.TaskServer().GetFlow(cts.Kit, flowID)
+	if err != nil {
+		return nil, err
+	}
+
+	if flow.Name != enumor.FlowSelectiveSyncAccount {
+		return nil, errf.Newf(errf.RecordNotFound, "selective sync: %s not found", flowID)
+	}
+
+	listReq := &core.ListReq{
+		Filter: tools.EqualExpression("flow_id", flowID),
+		Page:   core.NewDefaultBasePage(),
+	}
+	tasks := make([]proto.SelectiveSyncTaskRun, 0)
+	for {
+		result, err := a.client.TaskServer().ListTask(cts.Kit, listReq)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, one := range result.Details {
+			// 定向同步的两类任务参数中都包含账号ID、资源类型和地域
+			params := new(struct {
+				AccountID         string                   `json:"account_id"`
+				ResType           enumor.CloudResourceType `json:"res_type"`
+				Region            string                   `json:"region"`
+				ResourceGroupName string                   `json:"resource_group_name"`
+			})
+			if err = json.Unmarshal([]byte(one.Params), params); err != nil {
+				logs.Errorf("unmarshal task params failed, err: %v, task: %s, rid: %s", err, one.ID, cts.Kit.Rid)
+				return nil, err
+			}
+
+			if params.AccountID != accountID {
+				return nil, errf.Newf(errf.RecordNotFound, "selective sync: %s not found", flowID)
+			}
+
+			task := proto.SelectiveSyncTaskRun{
+				ActionID: one.ActionID,
+				ResType:  params.ResType,
+				Region:   params.Region,
+				State:    one.State,
+			}
+			if len(task.Region) == 0 {
+				task.Region = params.ResourceGroupName
+			}
+			if one.Reason != nil {
+				task.Reason = one.Reason.Message
+			}
+			tasks = append(tasks, task)
+		}
+
+		if len(result.Details) < int(listReq.Page.Limit) {
+			break
+		}
+		listReq.Page.Start += uint32(listReq.Page.Limit)
+	}
+
+	return &proto.SelectiveSyncStatus{FlowID: flow.ID, State: flow.State, Tasks: tasks}, nil
+}
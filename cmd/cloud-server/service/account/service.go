@@ -49,8 +49,20 @@ func InitAccountService(c *capability.Capability) {
 	h.Add("ResourceList", http.MethodPost, "/accounts/resources/accounts/list", svc.ResourceList)
 	h.Add("Get", http.MethodGet, "/accounts/{account_id}", svc.Get)
 	h.Add("GetSyncDetail", http.MethodGet, "/accounts/sync_details/{account_id}", svc.GetSyncDetail)
+	h.Add("ListSyncSchedule", http.MethodPost, "/accounts/sync_schedules/list", svc.ListSyncSchedule)
+	h.Add("ListSyncLock", http.MethodPost, "/accounts/sync_locks/list", svc.ListSyncLock)
+	h.Add("ReleaseSyncLock", http.MethodDelete, "/accounts/{account_id}/sync_lock", svc.ReleaseSyncLock)
+	h.Add("ListSyncQuarantine", http.MethodPost, "/accounts/{account_id}/sync_quarantines/list",
+		svc.ListSyncQuarantine)
+	h.Add("ApproveSyncQuarantine", http.MethodPatch, "/accounts/{account_id}/sync_quarantines/approve",
+		svc.ApproveSyncQuarantine)
+	h.Add("DismissSyncQuarantine", http.MethodDelete, "/accounts/{account_id}/sync_quarantines/batch",
+		svc.DismissSyncQuarantine)
 	h.Add("Update", http.MethodPatch, "/accounts/{account_id}", svc.Update)
 	h.Add("SyncCloudResource", http.MethodPost, "/accounts/{account_id}/sync", svc.SyncCloudResource)
+	h.Add("SelectiveSync", http.MethodPost, "/accounts/{account_id}/selective_sync", svc.SelectiveSync)
+	h.Add("GetSelectiveSync", http.MethodGet, "/accounts/{account_id}/selective_syncs/{flow_id}",
+		svc.GetSelectiveSync)
 	h.Add("DeleteAccount", http.MethodDelete, "/accounts/{account_id}", svc.DeleteAccount)
 	h.Add("DeleteValidate", http.MethodPost, "/accounts/{account_id}/delete/validate", svc.DeleteValidate)
 
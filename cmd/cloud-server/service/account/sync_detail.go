@@ -22,9 +22,11 @@ package account
 import (
 	"hcm/pkg/api/cloud-server/account"
 	"hcm/pkg/api/core"
+	coresync "hcm/pkg/api/core/cloud/sync"
 	"hcm/pkg/criteria/enumor"
 	"hcm/pkg/criteria/errf"
 	"hcm/pkg/iam/meta"
+	"hcm/pkg/kit"
 	"hcm/pkg/rest"
 	"hcm/pkg/runtime/filter"
 )
@@ -71,18 +73,91 @@ func (a *accountSvc) GetSyncDetail(cts *rest.Contexts) (interface{}, error) {
 		return nil, err
 	}
 
+	resName := cts.Request.QueryParameter("res_name")
+	history, err := a.listSyncHistory(cts.Kit, accountID, resName)
+	if err != nil {
+		return nil, err
+	}
+	lastSyncMap := lastSyncStatistic(history)
+
 	iassRes := make([]account.IassResItem, 0, len(accountSyncDetail.Details))
 	for _, one := range accountSyncDetail.Details {
+		// 增量同步检查点不是资源的同步状态
+		if one.ResName == enumor.IncrementalSyncCheckpoint {
+			continue
+		}
+
 		item := account.IassResItem{
 			ResName:         one.ResName,
 			ResStatus:       one.ResStatus,
 			ResEndTime:      one.ResEndTime,
 			ResFailedReason: string(one.ResFailedReason),
+			LastSync:        lastSyncMap[one.ResName],
 		}
 		iassRes = append(iassRes, item)
 	}
 
 	return account.SyncDetailRsp{
 		IassRes: iassRes,
+		History: history,
 	}, nil
 }
+
+// listSyncHistory 查询账号最近的资源同步历史，resName 不为空时只查询该资源的同步历史。
+func (a *accountSvc) listSyncHistory(kt *kit.Kit, accountID, resName string) ([]coresync.AccountSyncHistory,
+	error) {
+
+	expr := &filter.Expression{
+		Op: filter.And,
+		Rules: []filter.RuleFactory{
+			&filter.AtomRule{Field: "account_id", Op: filter.Equal.Factory(), Value: accountID},
+		},
+	}
+	if len(resName) != 0 {
+		expr.Rules = append(expr.Rules, &filter.AtomRule{Field: "res_name", Op: filter.Equal.Factory(),
+			Value: resName})
+	}
+
+	listReq := &core.ListReq{
+		Filter: expr,
+		Page: &core.BasePage{
+			Start: 0,
+			Limit: core.DefaultMaxPageLimit,
+			Sort:  "created_at",
+			Order: core.Descending,
+		},
+	}
+	result, err := a.client.DataService().Global.AccountSyncHistory.List(kt, listReq)
+	if err != nil {
+		return nil, err
+	}
+
+	return result.Details, nil
+}
+
+// lastSyncStatistic 按资源汇总最近一次同步的统计，同一次同步的各地域记录拥有相同的 sync_rid。
+// history 需按同步时间倒序排列。
+func lastSyncStatistic(history []coresync.AccountSyncHistory) map[string]*account.SyncStatistic {
+	result := make(map[string]*account.SyncStatistic)
+	for _, one := range history {
+		stat, exist := result[one.ResName]
+		if !exist {
+			stat = &account.SyncStatistic{SyncRid: one.SyncRid}
+			result[one.ResName] = stat
+		}
+
+		if stat.SyncRid != one.SyncRid {
+			continue
+		}
+
+		stat.RegionCount++
+		stat.CreatedCount += one.CreatedCount
+		stat.UpdatedCount += one.UpdatedCount
+		stat.DeletedCount += one.DeletedCount
+		stat.QuarantinedCount += one.QuarantinedCount
+		stat.CloudApiCount += one.CloudApiCount
+		stat.CostMs += one.CostMs
+	}
+
+	return result
+}
@@ -0,0 +1,92 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package account
+
+import (
+	"hcm/cmd/cloud-server/service/sync/lock"
+	proto "hcm/pkg/api/cloud-server/account"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/iam/meta"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+	"hcm/pkg/tools/slice"
+)
+
+// ListSyncLock list account sync locks.
+func (a *accountSvc) ListSyncLock(cts *rest.Contexts) (interface{}, error) {
+	req := new(proto.ListSyncLockReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, err
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	// 校验用户是否有查看权限，有权限的ID列表
+	authIDs, isAny, err := a.listAuthorized(cts, meta.Find, meta.Account)
+	if err != nil {
+		return nil, err
+	}
+
+	accountIDs := req.AccountIDs
+	if !isAny {
+		if len(accountIDs) == 0 {
+			accountIDs = authIDs
+		} else {
+			accountIDs = slice.Filter(accountIDs, func(id string) bool {
+				return slice.IsItemInSlice(authIDs, id)
+			})
+		}
+
+		// 无任何账号权限
+		if len(accountIDs) == 0 {
+			return &proto.ListSyncLockResult{Details: make([]proto.SyncLock, 0)}, nil
+		}
+	}
+
+	details, err := lock.Manager.List(cts.Kit.Ctx, accountIDs)
+	if err != nil {
+		logs.Errorf("list account sync lock failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return &proto.ListSyncLockResult{Details: details}, nil
+}
+
+// ReleaseSyncLock force release account sync lock, the running sync of account will be aborted.
+func (a *accountSvc) ReleaseSyncLock(cts *rest.Contexts) (interface{}, error) {
+	accountID := cts.PathParameter("account_id").String()
+
+	if err := a.checkPermission(cts, meta.Update, accountID); err != nil {
+		return nil, err
+	}
+
+	if err := lock.Manager.ForceUnLock(cts.Kit.Ctx, accountID); err != nil {
+		logs.Errorf("force release account sync lock failed, err: %v, account: %s, rid: %s", err, accountID,
+			cts.Kit.Rid)
+		return nil, err
+	}
+
+	logs.Infof("account sync lock is force released by %s, account: %s, rid: %s", cts.Kit.User, accountID,
+		cts.Kit.Rid)
+
+	return nil, nil
+}
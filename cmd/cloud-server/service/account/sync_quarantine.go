@@ -0,0 +1,155 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+package account
+
+import (
+	"fmt"
+
+	"hcm/pkg/api/cloud-server/account"
+	"hcm/pkg/api/core"
+	dataservice "hcm/pkg/api/data-service"
+	dssync "hcm/pkg/api/data-service/cloud/sync"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/iam/meta"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+	"hcm/pkg/runtime/filter"
+	"hcm/pkg/tools/slice"
+)
+
+// ListSyncQuarantine list resources quarantined by sync deletion guard of account.
+func (a *accountSvc) ListSyncQuarantine(cts *rest.Contexts) (interface{}, error) {
+	accountID := cts.PathParameter("account_id").String()
+
+	req := new(core.ListReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	if err := a.checkPermission(cts, meta.Find, accountID); err != nil {
+		return nil, err
+	}
+
+	expr, err := tools.And(req.Filter, &filter.AtomRule{Field: "account_id", Op: filter.Equal.Factory(),
+		Value: accountID})
+	if err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+	req.Filter = expr
+
+	return a.client.DataService().Global.AccountSyncQuarantine.List(cts.Kit, req)
+}
+
+// ApproveSyncQuarantine approve deletion of quarantined resources, they will be deleted in next sync.
+func (a *accountSvc) ApproveSyncQuarantine(cts *rest.Contexts) (interface{}, error) {
+	accountID := cts.PathParameter("account_id").String()
+
+	req := new(account.SyncQuarantineOperateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	if err := a.checkPermission(cts, meta.Update, accountID); err != nil {
+		return nil, err
+	}
+
+	if err := a.checkSyncQuarantineBelongs(cts.Kit, accountID, req.IDs); err != nil {
+		return nil, err
+	}
+
+	items := make([]dssync.QuarantineUpdateField, 0, len(req.IDs))
+	for _, id := range req.IDs {
+		items = append(items, dssync.QuarantineUpdateField{ID: id, Status: enumor.SyncQuarantineApproved})
+	}
+	updateReq := &dssync.QuarantineUpdateReq{Items: items}
+	if err := a.client.DataService().Global.AccountSyncQuarantine.BatchUpdate(cts.Kit, updateReq); err != nil {
+		logs.Errorf("approve sync quarantine failed, err: %v, ids: %v, rid: %s", err, req.IDs, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// DismissSyncQuarantine dismiss quarantine records, resources will be checked again in next sync.
+func (a *accountSvc) DismissSyncQuarantine(cts *rest.Contexts) (interface{}, error) {
+	accountID := cts.PathParameter("account_id").String()
+
+	req := new(account.SyncQuarantineOperateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	if err := a.checkPermission(cts, meta.Update, accountID); err != nil {
+		return nil, err
+	}
+
+	if err := a.checkSyncQuarantineBelongs(cts.Kit, accountID, req.IDs); err != nil {
+		return nil, err
+	}
+
+	deleteReq := &dataservice.BatchDeleteReq{Filter: tools.ContainersExpression("id", req.IDs)}
+	if err := a.client.DataService().Global.AccountSyncQuarantine.BatchDelete(cts.Kit, deleteReq); err != nil {
+		logs.Errorf("dismiss sync quarantine failed, err: %v, ids: %v, rid: %s", err, req.IDs, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// checkSyncQuarantineBelongs 校验隔离记录都属于该账号。
+func (a *accountSvc) checkSyncQuarantineBelongs(kt *kit.Kit, accountID string, ids []string) error {
+	ids = slice.Unique(ids)
+	listReq := &core.ListReq{
+		Filter: &filter.Expression{
+			Op: filter.And,
+			Rules: []filter.RuleFactory{
+				&filter.AtomRule{Field: "account_id", Op: filter.Equal.Factory(), Value: accountID},
+				&filter.AtomRule{Field: "id", Op: filter.In.Factory(), Value: ids},
+			},
+		},
+		Page: &core.BasePage{Count: true},
+	}
+	result, err := a.client.DataService().Global.AccountSyncQuarantine.List(kt, listReq)
+	if err != nil {
+		logs.Errorf("count sync quarantine failed, err: %v, ids: %v, rid: %s", err, ids, kt.Rid)
+		return err
+	}
+
+	if int(result.Count) != len(ids) {
+		return errf.NewFromErr(errf.InvalidParameter, fmt.Errorf("sync quarantines %v not all belong to account %s",
+			ids, accountID))
+	}
+
+	return nil
+}
@@ -0,0 +1,69 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+package account
+
+import (
+	"hcm/cmd/cloud-server/service/sync/scheduler"
+	proto "hcm/pkg/api/cloud-server/account"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/iam/meta"
+	"hcm/pkg/rest"
+	"hcm/pkg/tools/slice"
+)
+
+// ListSyncSchedule list account sync schedule queue.
+func (a *accountSvc) ListSyncSchedule(cts *rest.Contexts) (interface{}, error) {
+	req := new(proto.ListSyncScheduleReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, err
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	if scheduler.Manager == nil {
+		return nil, errf.New(errf.Aborted, "cloud resource sync scheduler is not enabled")
+	}
+
+	// 校验用户是否有查看权限，有权限的ID列表
+	authIDs, isAny, err := a.listAuthorized(cts, meta.Find, meta.Account)
+	if err != nil {
+		return nil, err
+	}
+
+	accountIDs := req.AccountIDs
+	if !isAny {
+		if len(accountIDs) == 0 {
+			accountIDs = authIDs
+		} else {
+			accountIDs = slice.Filter(accountIDs, func(id string) bool {
+				return slice.IsItemInSlice(authIDs, id)
+			})
+		}
+
+		// 无任何账号权限
+		if len(accountIDs) == 0 {
+			return &proto.ListSyncScheduleResult{IsMaster: scheduler.Manager.IsMaster(),
+				Details: make([]*proto.SyncSchedule, 0)}, nil
+		}
+	}
+
+	return scheduler.Manager.List(req.Vendor, accountIDs), nil
+}
@@ -39,6 +39,7 @@ import (
 	gcpvpchandler "hcm/cmd/cloud-server/service/application/handlers/vpc/gcp"
 	huaweivpchandler "hcm/cmd/cloud-server/service/application/handlers/vpc/huawei"
 	tcloudvpchandler "hcm/cmd/cloud-server/service/application/handlers/vpc/tcloud"
+	"hcm/cmd/cloud-server/service/common"
 	proto "hcm/pkg/api/cloud-server/application"
 	cscvm "hcm/pkg/api/cloud-server/cvm"
 	csdisk "hcm/pkg/api/cloud-server/disk"
@@ -167,6 +168,14 @@ func parseReqFromRequestBody[T any](cts *rest.Contexts) (*T, error) {
 	return req, nil
 }
 
+func parseReqFromBytes[T any](body []byte) (*T, error) {
+	req := new(T)
+	if err := json.Unmarshal(body, req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	return req, nil
+}
+
 // CreateForAddAccount ...
 func (a *applicationSvc) CreateForAddAccount(cts *rest.Contexts) (interface{}, error) {
 	commReq, err := decodeCommonReqAndValidate(cts)
@@ -206,39 +215,52 @@ func (a *applicationSvc) CreateForCreateCvm(cts *rest.Contexts) (interface{}, er
 		return nil, err
 	}
 
+	// 引用了启动模版时，将模版配置与请求参数合并，申请单中记录合并后的完整参数
+	body, err := cts.RequestBody()
+	if err != nil {
+		logs.Errorf("get request body failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	body, _, err = common.ApplyCvmLaunchTemplate(cts.Kit, a.client.DataService(), vendor, body)
+	if err != nil {
+		logs.Errorf("apply launch template failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
 	opt := a.getHandlerOption(cts)
 
 	switch vendor {
 	case enumor.TCloud:
-		req, err := parseReqFromRequestBody[cscvm.TCloudCvmCreateReq](cts)
+		req, err := parseReqFromBytes[cscvm.TCloudCvmCreateReq](body)
 		if err != nil {
 			return nil, err
 		}
 		handler := tcloudcvmhandler.NewApplicationOfCreateTCloudCvm(opt, req)
 		return a.create(cts, commReq, handler)
 	case enumor.Aws:
-		req, err := parseReqFromRequestBody[cscvm.AwsCvmCreateReq](cts)
+		req, err := parseReqFromBytes[cscvm.AwsCvmCreateReq](body)
 		if err != nil {
 			return nil, err
 		}
 		handler := awscvmhandler.NewApplicationOfCreateAwsCvm(opt, req)
 		return a.create(cts, commReq, handler)
 	case enumor.HuaWei:
-		req, err := parseReqFromRequestBody[cscvm.HuaWeiCvmCreateReq](cts)
+		req, err := parseReqFromBytes[cscvm.HuaWeiCvmCreateReq](body)
 		if err != nil {
 			return nil, err
 		}
 		handler := huaweicvmhandler.NewApplicationOfCreateHuaWeiCvm(opt, req)
 		return a.create(cts, commReq, handler)
 	case enumor.Gcp:
-		req, err := parseReqFromRequestBody[cscvm.GcpCvmCreateReq](cts)
+		req, err := parseReqFromBytes[cscvm.GcpCvmCreateReq](body)
 		if err != nil {
 			return nil, err
 		}
 		handler := gcpcvmhandler.NewApplicationOfCreateGcpCvm(opt, req)
 		return a.create(cts, commReq, handler)
 	case enumor.Azure:
-		req, err := parseReqFromRequestBody[cscvm.AzureCvmCreateReq](cts)
+		req, err := parseReqFromBytes[cscvm.AzureCvmCreateReq](body)
 		if err != nil {
 			return nil, err
 		}
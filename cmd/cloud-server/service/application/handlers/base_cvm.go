@@ -20,7 +20,9 @@
 package handlers
 
 import (
+	"hcm/cmd/cloud-server/service/common"
 	"hcm/pkg/api/core"
+	corecloud "hcm/pkg/api/core/cloud"
 	corecvm "hcm/pkg/api/core/cloud/cvm"
 	"hcm/pkg/criteria/enumor"
 	"hcm/pkg/runtime/filter"
@@ -52,3 +54,42 @@ func (a *BaseApplicationHandler) ListCvm(
 
 	return resp.Details, nil
 }
+
+// EncryptUserDataVariables 加密主机自定义数据模版的变量值，避免敏感变量明文存储在申请单内容中
+func (a *BaseApplicationHandler) EncryptUserDataVariables(ref *corecloud.UserDataTemplateRef) {
+	if ref == nil {
+		return
+	}
+
+	for name, value := range ref.Variables {
+		ref.Variables[name] = a.Cipher.EncryptToBase64(value)
+	}
+}
+
+// DecryptUserDataVariables 解密申请单内容中的主机自定义数据模版变量值
+func (a *BaseApplicationHandler) DecryptUserDataVariables(ref *corecloud.UserDataTemplateRef) error {
+	if ref == nil {
+		return nil
+	}
+
+	for name, value := range ref.Variables {
+		plain, err := a.Cipher.DecryptFromBase64(value)
+		if err != nil {
+			return err
+		}
+		ref.Variables[name] = plain
+	}
+
+	return nil
+}
+
+// RenderCvmUserData 渲染申请单引用的主机自定义数据模版，apply为true时记录模版应用审计
+func (a *BaseApplicationHandler) RenderCvmUserData(opt *common.CvmUserDataOption, apply bool) (*string, error) {
+	if opt == nil || opt.Template == nil {
+		return nil, nil
+	}
+
+	opt.Apply = apply
+	opt.Source = common.UserDataSourceApplication
+	return common.RenderCvmUserData(a.Cts.Kit, a.Client.DataService(), opt)
+}
@@ -50,3 +50,16 @@ func (a *ApplicationOfCreateAwsCvm) toHcProtoAwsBatchCreateReq(dryRun bool) *hcp
 
 	return createReq
 }
+
+func (a *ApplicationOfCreateAwsCvm) userDataOption() *common.CvmUserDataOption {
+	return &common.CvmUserDataOption{
+		Vendor:       enumor.Aws,
+		BkBizID:      a.req.BkBizID,
+		AccountID:    a.req.AccountID,
+		Region:       a.req.Region,
+		Zone:         a.req.Zone,
+		Name:         a.req.Name,
+		InstanceType: a.req.InstanceType,
+		Template:     a.req.UserDataTemplate,
+	}
+}
@@ -31,5 +31,10 @@ func (a *ApplicationOfCreateAzureCvm) CheckReq() error {
 		return err
 	}
 
+	// 校验主机自定义数据模版能否正常渲染
+	if _, err := a.RenderCvmUserData(a.userDataOption(), false); err != nil {
+		return err
+	}
+
 	return nil
 }
@@ -47,3 +47,16 @@ func NewApplicationOfCreateAzureCvm(opt *handlers.HandlerOption, req *proto.Azur
 func (a *ApplicationOfCreateAzureCvm) toHcProtoAzureBatchCreateReq() *hcproto.AzureCreateReq {
 	return common.ConvAzureCvmCreateReq(a.req)
 }
+
+func (a *ApplicationOfCreateAzureCvm) userDataOption() *common.CvmUserDataOption {
+	return &common.CvmUserDataOption{
+		Vendor:       enumor.Azure,
+		BkBizID:      a.req.BkBizID,
+		AccountID:    a.req.AccountID,
+		Region:       a.req.Region,
+		Zone:         a.req.Zone,
+		Name:         a.req.Name,
+		InstanceType: a.req.InstanceType,
+		Template:     a.req.UserDataTemplate,
+	}
+}
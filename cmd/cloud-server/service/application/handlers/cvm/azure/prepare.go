@@ -34,6 +34,9 @@ func (a *ApplicationOfCreateAzureCvm) PrepareReq() error {
 	a.req.Password = encryptedPassword
 	a.req.ConfirmedPassword = encryptedPassword
 
+	// 主机自定义数据模版变量加密
+	a.EncryptUserDataVariables(a.req.UserDataTemplate)
+
 	return nil
 }
 
@@ -59,6 +62,11 @@ func (a *ApplicationOfCreateAzureCvm) PrepareReqFromContent() error {
 	a.req.Password = password
 	a.req.ConfirmedPassword = password
 
+	// 解密主机自定义数据模版变量
+	if err := a.DecryptUserDataVariables(a.req.UserDataTemplate); err != nil {
+		return fmt.Errorf("decrypt user data template variables failed, err: %w", err)
+	}
+
 	return nil
 }
 
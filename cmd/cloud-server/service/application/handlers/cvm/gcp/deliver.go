@@ -36,7 +36,15 @@ import (
 // Deliver 执行资源交付
 func (a *ApplicationOfCreateGcpCvm) Deliver() (enumor.ApplicationStatus, map[string]interface{}, error) {
 
+	userData, err := a.RenderCvmUserData(a.userDataOption(), true)
+	if err != nil {
+		logs.Errorf("render cvm user data template failed, err: %v, rid: %s", err, a.Cts.Kit.Rid)
+		return enumor.DeliverError, map[string]interface{}{"error": fmt.Sprintf("render user data template failed, err: %v",
+			err)}, err
+	}
+
 	req := a.toHcProtoGcpBatchCreateReq()
+	req.UserData = userData
 	tasks := actioncvm.BuildCreateCvmTasks(req.RequiredCount, a.req.BkBizID, constant.BatchCreateCvmFromCloudMaxLimit,
 		func(actionID action.ActIDType, count int64) ts.CustomFlowTask {
 			req.RequiredCount = count
@@ -47,3 +47,16 @@ func NewApplicationOfCreateGcpCvm(
 func (a *ApplicationOfCreateGcpCvm) toHcProtoGcpBatchCreateReq() *hcproto.GcpBatchCreateReq {
 	return common.ConvGcpCvmCreateReq(a.req)
 }
+
+func (a *ApplicationOfCreateGcpCvm) userDataOption() *common.CvmUserDataOption {
+	return &common.CvmUserDataOption{
+		Vendor:       enumor.Gcp,
+		BkBizID:      a.req.BkBizID,
+		AccountID:    a.req.AccountID,
+		Region:       a.req.Region,
+		Zone:         a.req.Zone,
+		Name:         a.req.Name,
+		InstanceType: a.req.InstanceType,
+		Template:     a.req.UserDataTemplate,
+	}
+}
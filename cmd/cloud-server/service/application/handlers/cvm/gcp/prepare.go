@@ -20,6 +20,8 @@
 package gcp
 
 import (
+	"fmt"
+
 	proto "hcm/pkg/api/cloud-server/cvm"
 	"hcm/pkg/criteria/enumor"
 	"hcm/pkg/thirdparty/itsm"
@@ -28,6 +30,10 @@ import (
 // PrepareReq 预处理请求参数，比如敏感数据加密
 func (a *ApplicationOfCreateGcpCvm) PrepareReq() error {
 	// GCP 主机公钥无需加密
+
+	// 主机自定义数据模版变量加密
+	a.EncryptUserDataVariables(a.req.UserDataTemplate)
+
 	return nil
 }
 
@@ -46,6 +52,12 @@ func (a *ApplicationOfCreateGcpCvm) GenerateApplicationContent() interface{} {
 // PrepareReqFromContent 预处理请求参数，对于申请内容来着DB，其实入库前是加密了的
 func (a *ApplicationOfCreateGcpCvm) PrepareReqFromContent() error {
 	// GCP 主机公钥无需解密
+
+	// 解密主机自定义数据模版变量
+	if err := a.DecryptUserDataVariables(a.req.UserDataTemplate); err != nil {
+		return fmt.Errorf("decrypt user data template variables failed, err: %w", err)
+	}
+
 	return nil
 }
 
@@ -50,3 +50,16 @@ func (a *ApplicationOfCreateHuaWeiCvm) toHcProtoHuaWeiBatchCreateReq(dryRun bool
 
 	return createReq
 }
+
+func (a *ApplicationOfCreateHuaWeiCvm) userDataOption() *common.CvmUserDataOption {
+	return &common.CvmUserDataOption{
+		Vendor:       enumor.HuaWei,
+		BkBizID:      a.req.BkBizID,
+		AccountID:    a.req.AccountID,
+		Region:       a.req.Region,
+		Zone:         a.req.Zone,
+		Name:         a.req.Name,
+		InstanceType: a.req.InstanceType,
+		Template:     a.req.UserDataTemplate,
+	}
+}
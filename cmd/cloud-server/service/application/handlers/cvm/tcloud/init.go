@@ -50,3 +50,16 @@ func (a *ApplicationOfCreateTCloudCvm) toHcProtoTCloudBatchCreateReq(dryRun bool
 
 	return createReq
 }
+
+func (a *ApplicationOfCreateTCloudCvm) userDataOption() *common.CvmUserDataOption {
+	return &common.CvmUserDataOption{
+		Vendor:       enumor.TCloud,
+		BkBizID:      a.req.BkBizID,
+		AccountID:    a.req.AccountID,
+		Region:       a.req.Region,
+		Zone:         a.req.Zone,
+		Name:         a.req.Name,
+		InstanceType: a.req.InstanceType,
+		Template:     a.req.UserDataTemplate,
+	}
+}
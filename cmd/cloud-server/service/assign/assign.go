@@ -34,6 +34,7 @@ import (
 	"hcm/pkg/dal/dao/tools"
 	"hcm/pkg/iam/auth"
 	"hcm/pkg/iam/meta"
+	"hcm/pkg/kit"
 	"hcm/pkg/logs"
 	"hcm/pkg/rest"
 	"hcm/pkg/runtime/filter"
@@ -52,6 +53,13 @@ func InitService(c *capability.Capability) {
 
 	h.Add("AssignResourceToBiz", http.MethodPost, "/resources/assign/bizs", s.AssignResourceToBiz)
 
+	h.Add("CreateBizAssignRule", http.MethodPost, "/resources/assign/rules/create", s.CreateBizAssignRule)
+	h.Add("UpdateBizAssignRule", http.MethodPatch, "/resources/assign/rules/{id}", s.UpdateBizAssignRule)
+	h.Add("ListBizAssignRule", http.MethodPost, "/resources/assign/rules/list", s.ListBizAssignRule)
+	h.Add("BatchDeleteBizAssignRule", http.MethodDelete, "/resources/assign/rules/batch", s.BatchDeleteBizAssignRule)
+	h.Add("EvaluateBizAssignRule", http.MethodPost, "/resources/assign/rules/evaluate", s.EvaluateBizAssignRule)
+	h.Add("ListBizAssignRecord", http.MethodPost, "/resources/assign/records/list", s.ListBizAssignRecord)
+
 	h.Load(c.WebService)
 }
 
@@ -81,22 +89,10 @@ func (svc *svc) AssignResourceToBiz(cts *rest.Contexts) (interface{}, error) {
 	}
 
 	// check if account is related to assigned biz
-	accountBizReq := &core.ListReq{
-		Filter: tools.EqualExpression("account_id", req.AccountID),
-		Page:   core.NewDefaultBasePage(),
-		Fields: []string{"bk_biz_id"},
-	}
-	accountBizRes, err := svc.client.DataService().Global.Account.ListAccountBizRel(cts.Kit.Ctx, cts.Kit.Header(),
-		accountBizReq)
-	if err != nil {
-		logs.Errorf("get account biz relation failed, err: %v, req: %+v, rid: %s", err, accountBizReq, cts.Kit.Rid)
+	if err = svc.checkAccountBizRel(cts.Kit, req.AccountID, req.BkBizID); err != nil {
 		return nil, err
 	}
 
-	if len(accountBizRes.Details) == 0 || accountBizRes.Details[0].BkBizID != req.BkBizID {
-		return nil, errf.Newf(errf.InvalidParameter, "account(%s) and biz(%d) not matches", req.AccountID, req.BkBizID)
-	}
-
 	// compatible for assign all resource scenario
 	if req.IsAllResType {
 		req.ResTypes = []enumor.CloudResourceType{enumor.CvmCloudResType, enumor.DiskCloudResType,
@@ -140,3 +136,24 @@ func (svc *svc) AssignResourceToBiz(cts *rest.Contexts) (interface{}, error) {
 
 	return nil, nil
 }
+
+// checkAccountBizRel check if account is related to the biz that resource will be assigned to.
+func (svc *svc) checkAccountBizRel(kt *kit.Kit, accountID string, bizID int64) error {
+	accountBizReq := &core.ListReq{
+		Filter: tools.EqualExpression("account_id", accountID),
+		Page:   core.NewDefaultBasePage(),
+		Fields: []string{"bk_biz_id"},
+	}
+	accountBizRes, err := svc.client.DataService().Global.Account.ListAccountBizRel(kt.Ctx, kt.Header(),
+		accountBizReq)
+	if err != nil {
+		logs.Errorf("get account biz relation failed, err: %v, req: %+v, rid: %s", err, accountBizReq, kt.Rid)
+		return err
+	}
+
+	if len(accountBizRes.Details) == 0 || accountBizRes.Details[0].BkBizID != bizID {
+		return errf.Newf(errf.InvalidParameter, "account(%s) and biz(%d) not matches", accountID, bizID)
+	}
+
+	return nil
+}
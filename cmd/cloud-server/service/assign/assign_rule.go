@@ -0,0 +1,282 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package assign
+
+import (
+	proto "hcm/pkg/api/cloud-server/assign"
+	"hcm/pkg/api/core"
+	corecloud "hcm/pkg/api/core/cloud"
+	dataservice "hcm/pkg/api/data-service"
+	"hcm/pkg/api/data-service/cloud"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/iam/meta"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+	"hcm/pkg/runtime/filter"
+	"hcm/pkg/tools/hooks/handler"
+)
+
+// CreateBizAssignRule create biz assign rule.
+func (svc *svc) CreateBizAssignRule(cts *rest.Contexts) (interface{}, error) {
+	req := new(proto.CreateBizAssignRuleReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, err
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	if err := svc.authorizeAssignRule(cts.Kit, req.AccountID, req.BkBizID); err != nil {
+		return nil, err
+	}
+
+	createReq := &cloud.BizAssignRuleBatchCreateReq{Rules: []cloud.BizAssignRuleCreate{req.BizAssignRuleCreate}}
+	result, err := svc.client.DataService().Global.BizAssignRule.BatchCreate(cts.Kit, createReq)
+	if err != nil {
+		logs.Errorf("create biz assign rule failed, err: %v, req: %+v, rid: %s", err, req, cts.Kit.Rid)
+		return nil, err
+	}
+
+	if len(result.IDs) != 1 {
+		return nil, errf.Newf(errf.Unknown, "create biz assign rule but return %d ids", len(result.IDs))
+	}
+
+	return &core.CreateResult{ID: result.IDs[0]}, nil
+}
+
+// UpdateBizAssignRule update biz assign rule.
+func (svc *svc) UpdateBizAssignRule(cts *rest.Contexts) (interface{}, error) {
+	id := cts.PathParameter("id").String()
+	if len(id) == 0 {
+		return nil, errf.New(errf.InvalidParameter, "id is required")
+	}
+
+	req := new(proto.UpdateBizAssignRuleReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, err
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	rules, err := svc.listAssignRule(cts.Kit, []string{id})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(rules) == 0 {
+		return nil, errf.Newf(errf.RecordNotFound, "biz assign rule: %s not found", id)
+	}
+
+	// 修改分配业务时，需要同时具有原业务与新业务的分配权限
+	if err = svc.authorizeAssignRule(cts.Kit, rules[0].AccountID, rules[0].BkBizID); err != nil {
+		return nil, err
+	}
+
+	if req.BkBizID != 0 && req.BkBizID != rules[0].BkBizID {
+		if err = svc.authorizeAssignRule(cts.Kit, rules[0].AccountID, req.BkBizID); err != nil {
+			return nil, err
+		}
+	}
+
+	err = svc.client.DataService().Global.BizAssignRule.Update(cts.Kit, id, &req.BizAssignRuleUpdateReq)
+	if err != nil {
+		logs.Errorf("update biz assign rule failed, err: %v, id: %s, rid: %s", err, id, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// ListBizAssignRule list biz assign rule.
+func (svc *svc) ListBizAssignRule(cts *rest.Contexts) (interface{}, error) {
+	req := new(core.ListReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, err
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	expr, noPermFlag, err := handler.ListResourceAuthRes(cts, &handler.ListAuthResOption{Authorizer: svc.authorizer,
+		ResType: meta.Account, Action: meta.Find, Filter: req.Filter})
+	if err != nil {
+		logs.Errorf("list biz assign rule auth failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	if noPermFlag {
+		return &cloud.BizAssignRuleListResult{Count: 0, Details: make([]corecloud.BizAssignRule, 0)}, nil
+	}
+
+	req.Filter = expr
+	return svc.client.DataService().Global.BizAssignRule.List(cts.Kit, req)
+}
+
+// BatchDeleteBizAssignRule batch delete biz assign rule.
+func (svc *svc) BatchDeleteBizAssignRule(cts *rest.Contexts) (interface{}, error) {
+	req := new(proto.BatchDeleteBizAssignRuleReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, err
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	rules, err := svc.listAssignRule(cts.Kit, req.IDs)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(rules) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]string, 0, len(rules))
+	for _, rule := range rules {
+		if err = svc.authorizeAssignRule(cts.Kit, rule.AccountID, rule.BkBizID); err != nil {
+			return nil, err
+		}
+		ids = append(ids, rule.ID)
+	}
+
+	deleteReq := &dataservice.BatchDeleteReq{Filter: tools.ContainersExpression("id", ids)}
+	if err = svc.client.DataService().Global.BizAssignRule.BatchDelete(cts.Kit, deleteReq); err != nil {
+		logs.Errorf("batch delete biz assign rule failed, err: %v, ids: %v, rid: %s", err, ids, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// EvaluateBizAssignRule evaluate biz assign rule on demand, dry run only returns the matched resources.
+func (svc *svc) EvaluateBizAssignRule(cts *rest.Contexts) (interface{}, error) {
+	req := new(proto.EvaluateBizAssignRuleReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, err
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	rules := make([]filter.RuleFactory, 0)
+	if len(req.AccountID) != 0 {
+		rules = append(rules, &filter.AtomRule{Field: "account_id", Op: filter.Equal.Factory(), Value: req.AccountID})
+	}
+	if len(req.RuleIDs) != 0 {
+		rules = append(rules, &filter.AtomRule{Field: "id", Op: filter.In.Factory(), Value: req.RuleIDs})
+	}
+
+	listReq := &core.ListReq{
+		Filter: &filter.Expression{Op: filter.And, Rules: rules},
+		Page:   core.NewDefaultBasePage(),
+		Fields: []string{"id", "account_id", "bk_biz_id"},
+	}
+	for {
+		result, err := svc.client.DataService().Global.BizAssignRule.List(cts.Kit, listReq)
+		if err != nil {
+			logs.Errorf("list biz assign rule failed, err: %v, rid: %s", err, cts.Kit.Rid)
+			return nil, err
+		}
+
+		for _, rule := range result.Details {
+			if err = svc.authorizeAssignRule(cts.Kit, rule.AccountID, rule.BkBizID); err != nil {
+				return nil, err
+			}
+		}
+
+		if uint(len(result.Details)) < listReq.Page.Limit {
+			break
+		}
+		listReq.Page.Start += uint32(listReq.Page.Limit)
+	}
+
+	evaluateReq := &cloud.BizAssignRuleEvaluateReq{
+		AccountID: req.AccountID,
+		RuleIDs:   req.RuleIDs,
+		DryRun:    req.DryRun,
+	}
+	result, err := svc.client.DataService().Global.BizAssignRule.Evaluate(cts.Kit, evaluateReq)
+	if err != nil {
+		logs.Errorf("evaluate biz assign rule failed, err: %v, req: %+v, rid: %s", err, req, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// ListBizAssignRecord list the resource assign records created by biz assign rules.
+func (svc *svc) ListBizAssignRecord(cts *rest.Contexts) (interface{}, error) {
+	req := new(core.ListReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, err
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	expr, noPermFlag, err := handler.ListResourceAuthRes(cts, &handler.ListAuthResOption{Authorizer: svc.authorizer,
+		ResType: meta.Account, Action: meta.Find, Filter: req.Filter})
+	if err != nil {
+		logs.Errorf("list biz assign record auth failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	if noPermFlag {
+		return &cloud.BizAssignRecordListResult{Count: 0, Details: make([]corecloud.BizAssignRecord, 0)}, nil
+	}
+
+	req.Filter = expr
+	return svc.client.DataService().Global.BizAssignRule.ListRecord(cts.Kit, req)
+}
+
+// authorizeAssignRule authorize the permission to assign account's resource to biz, and check if account is
+// related to the biz.
+func (svc *svc) authorizeAssignRule(kt *kit.Kit, accountID string, bizID int64) error {
+	authRes := meta.ResourceAttribute{Basic: &meta.Basic{Type: meta.CloudResource, Action: meta.Assign,
+		ResourceID: accountID}, BizID: bizID}
+	if err := svc.authorizer.AuthorizeWithPerm(kt, authRes); err != nil {
+		return err
+	}
+
+	return svc.checkAccountBizRel(kt, accountID, bizID)
+}
+
+func (svc *svc) listAssignRule(kt *kit.Kit, ids []string) ([]corecloud.BizAssignRule, error) {
+	listReq := &core.ListReq{
+		Filter: tools.ContainersExpression("id", ids),
+		Page:   core.NewDefaultBasePage(),
+	}
+	result, err := svc.client.DataService().Global.BizAssignRule.List(kt, listReq)
+	if err != nil {
+		logs.Errorf("list biz assign rule failed, err: %v, ids: %v, rid: %s", err, ids, kt.Rid)
+		return nil, err
+	}
+
+	return result.Details, nil
+}
@@ -0,0 +1,248 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+package common
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"hcm/pkg/api/core"
+	corecloud "hcm/pkg/api/core/cloud"
+	dataservice "hcm/pkg/client/data-service"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/criteria/validator"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/runtime/filter"
+)
+
+// launchTemplateBoundFields 启动模版绑定的字段，请求中指定时需要与模版一致。
+var launchTemplateBoundFields = []string{"account_id", "region"}
+
+// ApplyCvmLaunchTemplate merge the launch template referenced by cvm create request body with the request, fields
+// set in request override the template spec, and returns the merged body with the launch template used. returns
+// the origin body and nil template if no launch template is referenced. see mergeLaunchTemplateSpec for the merge
+// semantics.
+func ApplyCvmLaunchTemplate(kt *kit.Kit, cli *dataservice.Client, vendor enumor.Vendor, body json.RawMessage) (
+	json.RawMessage, *corecloud.LaunchTemplate, error) {
+
+	fields := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(body, &fields); err != nil {
+		return nil, nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if isJsonNullOrEmpty(fields["launch_template"]) {
+		return body, nil, nil
+	}
+
+	ref := new(corecloud.LaunchTemplateRef)
+	if err := json.Unmarshal(fields["launch_template"], ref); err != nil {
+		return nil, nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := validator.Validate.Struct(ref); err != nil {
+		return nil, nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	var bizID int64
+	if !isJsonNullOrEmpty(fields["bk_biz_id"]) {
+		if err := json.Unmarshal(fields["bk_biz_id"], &bizID); err != nil {
+			return nil, nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+		}
+	}
+	if bizID <= 0 {
+		return nil, nil, errf.New(errf.InvalidParameter, "bk_biz_id is required when launch_template is set")
+	}
+
+	template, version, err := GetLaunchTemplateVersion(kt, cli, bizID, ref)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	mergedBody, err := mergeCvmLaunchTemplate(template, version, vendor, fields)
+	if err != nil {
+		logs.Errorf("merge launch template failed, err: %v, id: %s, version: %d, rid: %s", err, template.ID,
+			version.Version, kt.Rid)
+		return nil, nil, err
+	}
+
+	return mergedBody, template, nil
+}
+
+// mergeCvmLaunchTemplate validate the request fields against the launch template, and merge them with the
+// template version spec.
+func mergeCvmLaunchTemplate(template *corecloud.LaunchTemplate, version *corecloud.LaunchTemplateVersion,
+	vendor enumor.Vendor, fields map[string]json.RawMessage) (json.RawMessage, error) {
+
+	if template.Vendor != vendor {
+		return nil, errf.Newf(errf.InvalidParameter, "launch template: %s vendor is %s, not %s", template.ID,
+			template.Vendor, vendor)
+	}
+
+	spec := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(version.Spec, &spec); err != nil {
+		return nil, fmt.Errorf("unmarshal launch template spec failed, err: %v", err)
+	}
+
+	bound := map[string]string{"account_id": template.AccountID, "region": template.Region}
+	for _, name := range launchTemplateBoundFields {
+		if !isJsonNullOrEmpty(fields[name]) {
+			var value string
+			if err := json.Unmarshal(fields[name], &value); err != nil {
+				return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+			}
+
+			if value != bound[name] {
+				return nil, errf.Newf(errf.InvalidParameter, "%s should be %s as launch template: %s defined",
+					name, bound[name], template.ID)
+			}
+		}
+
+		spec[name], _ = json.Marshal(bound[name])
+	}
+
+	merged, err := mergeLaunchTemplateSpec(spec, fields)
+	if err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	// 记录实际使用的模版版本
+	merged["launch_template"], err = json.Marshal(&corecloud.LaunchTemplateRef{
+		TemplateID: template.ID,
+		Version:    version.Version,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(merged)
+}
+
+// mergeLaunchTemplateSpec merge the request fields into the launch template spec, the merge semantics is:
+//  1. fields that are null or empty string in request are ignored, the template spec is used.
+//  2. if both the template spec and the request of a field are json objects, they are merged recursively with the
+//     same semantics, so that the request can override part of a nested object, e.g. system_disk.disk_size_gb.
+//  3. otherwise, including arrays, the request value replaces the template spec as a whole.
+func mergeLaunchTemplateSpec(spec, fields map[string]json.RawMessage) (map[string]json.RawMessage, error) {
+	merged := make(map[string]json.RawMessage, len(spec)+len(fields))
+	for name, value := range spec {
+		merged[name] = value
+	}
+
+	for name, value := range fields {
+		if isJsonNullOrEmpty(value) {
+			continue
+		}
+
+		origin, exists := merged[name]
+		if !exists || !isJsonObject(origin) || !isJsonObject(value) {
+			merged[name] = value
+			continue
+		}
+
+		originFields := make(map[string]json.RawMessage)
+		if err := json.Unmarshal(origin, &originFields); err != nil {
+			return nil, fmt.Errorf("unmarshal launch template field %s failed, err: %v", name, err)
+		}
+
+		valueFields := make(map[string]json.RawMessage)
+		if err := json.Unmarshal(value, &valueFields); err != nil {
+			return nil, fmt.Errorf("unmarshal request field %s failed, err: %v", name, err)
+		}
+
+		nested, err := mergeLaunchTemplateSpec(originFields, valueFields)
+		if err != nil {
+			return nil, err
+		}
+
+		if merged[name], err = json.Marshal(nested); err != nil {
+			return nil, err
+		}
+	}
+
+	return merged, nil
+}
+
+// GetLaunchTemplateVersion get the biz launch template and the version referenced, latest version is used if
+// version is not specified.
+func GetLaunchTemplateVersion(kt *kit.Kit, cli *dataservice.Client, bizID int64, ref *corecloud.LaunchTemplateRef) (
+	*corecloud.LaunchTemplate, *corecloud.LaunchTemplateVersion, error) {
+
+	listReq := &core.ListReq{
+		Filter: tools.EqualWithOpExpression(filter.And, map[string]interface{}{
+			"id":        ref.TemplateID,
+			"bk_biz_id": bizID,
+		}),
+		Page: core.NewDefaultBasePage(),
+	}
+	templates, err := cli.Global.LaunchTemplate.List(kt, listReq)
+	if err != nil {
+		logs.Errorf("list launch template failed, err: %v, id: %s, rid: %s", err, ref.TemplateID, kt.Rid)
+		return nil, nil, err
+	}
+
+	if len(templates.Details) == 0 {
+		return nil, nil, errf.Newf(errf.RecordNotFound, "launch template: %s not found in biz: %d", ref.TemplateID,
+			bizID)
+	}
+	template := templates.Details[0]
+
+	versionNum := ref.Version
+	if versionNum == 0 {
+		versionNum = template.LatestVersion
+	}
+
+	listReq = &core.ListReq{
+		Filter: tools.EqualWithOpExpression(filter.And, map[string]interface{}{
+			"template_id": template.ID,
+			"version":     versionNum,
+		}),
+		Page: core.NewDefaultBasePage(),
+	}
+	versions, err := cli.Global.LaunchTemplate.ListVersion(kt, listReq)
+	if err != nil {
+		logs.Errorf("list launch template version failed, err: %v, id: %s, version: %d, rid: %s", err,
+			template.ID, versionNum, kt.Rid)
+		return nil, nil, err
+	}
+
+	if len(versions.Details) == 0 {
+		return nil, nil, errf.Newf(errf.RecordNotFound, "launch template: %s version: %d not found", template.ID,
+			versionNum)
+	}
+
+	return &template, &versions.Details[0], nil
+}
+
+func isJsonObject(value json.RawMessage) bool {
+	trimmed := bytes.TrimSpace(value)
+	return len(trimmed) != 0 && trimmed[0] == '{'
+}
+
+func isJsonNullOrEmpty(value json.RawMessage) bool {
+	switch string(value) {
+	case "", "null", `""`:
+		return true
+	default:
+		return false
+	}
+}
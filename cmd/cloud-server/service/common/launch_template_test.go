@@ -0,0 +1,156 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package common
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	corecloud "hcm/pkg/api/core/cloud"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/kit"
+)
+
+func testLaunchTemplate() (*corecloud.LaunchTemplate, *corecloud.LaunchTemplateVersion) {
+	template := &corecloud.LaunchTemplate{
+		ID:        "lt-1",
+		Vendor:    enumor.TCloud,
+		AccountID: "account",
+		Region:    "ap-guangzhou",
+	}
+	version := &corecloud.LaunchTemplateVersion{
+		TemplateID: "lt-1",
+		Version:    2,
+		Spec: json.RawMessage(`{"zone":"ap-guangzhou-3","instance_type":"S5.SMALL1",` +
+			`"system_disk":{"disk_type":"CLOUD_PREMIUM","disk_size_gb":50},` +
+			`"data_disk":[{"disk_type":"CLOUD_SSD","disk_size_gb":100}],"cloud_security_group_ids":["sg-1"]}`),
+	}
+	return template, version
+}
+
+func decodeFields(t *testing.T, raw string) map[string]json.RawMessage {
+	fields := make(map[string]json.RawMessage)
+	if err := json.Unmarshal([]byte(raw), &fields); err != nil {
+		t.Fatalf("unmarshal fields failed, err: %v", err)
+	}
+	return fields
+}
+
+func TestMergeCvmLaunchTemplateOverride(t *testing.T) {
+	template, version := testLaunchTemplate()
+	fields := decodeFields(t, `{"bk_biz_id":1,"name":"web","instance_type":"S5.MEDIUM2","zone":null,`+
+		`"system_disk":{"disk_size_gb":100},"data_disk":[],"cloud_security_group_ids":["sg-2","sg-3"],`+
+		`"launch_template":{"template_id":"lt-1"}}`)
+
+	raw, err := mergeCvmLaunchTemplate(template, version, enumor.TCloud, fields)
+	if err != nil {
+		t.Fatalf("merge launch template failed, err: %v", err)
+	}
+
+	merged := new(struct {
+		AccountID  string `json:"account_id"`
+		Region     string `json:"region"`
+		Name       string `json:"name"`
+		Zone       string `json:"zone"`
+		Type       string `json:"instance_type"`
+		SystemDisk struct {
+			DiskType string `json:"disk_type"`
+			DiskSize int64  `json:"disk_size_gb"`
+		} `json:"system_disk"`
+		DataDisk       []json.RawMessage            `json:"data_disk"`
+		SecurityGroups []string                     `json:"cloud_security_group_ids"`
+		Ref            *corecloud.LaunchTemplateRef `json:"launch_template"`
+	})
+	if err = json.Unmarshal(raw, merged); err != nil {
+		t.Fatalf("unmarshal merged body failed, err: %v", err)
+	}
+
+	if merged.AccountID != "account" || merged.Region != "ap-guangzhou" {
+		t.Errorf("bound fields should be set by template, account: %s, region: %s", merged.AccountID, merged.Region)
+	}
+
+	if merged.Name != "web" || merged.Type != "S5.MEDIUM2" {
+		t.Errorf("request fields should override template, name: %s, type: %s", merged.Name, merged.Type)
+	}
+
+	if merged.Zone != "ap-guangzhou-3" {
+		t.Errorf("null request field should keep template value, zone: %s", merged.Zone)
+	}
+
+	if merged.SystemDisk.DiskType != "CLOUD_PREMIUM" || merged.SystemDisk.DiskSize != 100 {
+		t.Errorf("nested object should be merged deeply, system disk: %+v", merged.SystemDisk)
+	}
+
+	if len(merged.DataDisk) != 0 || !reflect.DeepEqual(merged.SecurityGroups, []string{"sg-2", "sg-3"}) {
+		t.Errorf("array should be replaced as a whole, data disk: %s, security groups: %v", merged.DataDisk,
+			merged.SecurityGroups)
+	}
+
+	if merged.Ref == nil || merged.Ref.TemplateID != "lt-1" || merged.Ref.Version != 2 {
+		t.Errorf("the launch template version used should be recorded, ref: %+v", merged.Ref)
+	}
+}
+
+func TestMergeCvmLaunchTemplateValidate(t *testing.T) {
+	cases := []struct {
+		name   string
+		vendor enumor.Vendor
+		fields string
+	}{
+		{name: "vendor mismatch", vendor: enumor.Aws, fields: `{}`},
+		{name: "account mismatch", vendor: enumor.TCloud, fields: `{"account_id":"other"}`},
+		{name: "region mismatch", vendor: enumor.TCloud, fields: `{"region":"ap-shanghai"}`},
+		{name: "invalid bound field type", vendor: enumor.TCloud, fields: `{"region":1}`},
+	}
+
+	for _, c := range cases {
+		template, version := testLaunchTemplate()
+		if _, err := mergeCvmLaunchTemplate(template, version, c.vendor, decodeFields(t, c.fields)); err == nil {
+			t.Errorf("%s: merge launch template should fail", c.name)
+		}
+	}
+
+	template, version := testLaunchTemplate()
+	fields := decodeFields(t, `{"account_id":"account","region":"ap-guangzhou"}`)
+	if _, err := mergeCvmLaunchTemplate(template, version, enumor.TCloud, fields); err != nil {
+		t.Errorf("bound fields same as template should be allowed, err: %v", err)
+	}
+}
+
+func TestApplyCvmLaunchTemplateValidate(t *testing.T) {
+	body := json.RawMessage(`{"name":"web"}`)
+	merged, template, err := ApplyCvmLaunchTemplate(kit.New(), nil, enumor.TCloud, body)
+	if err != nil || template != nil || string(merged) != string(body) {
+		t.Errorf("body without launch template should not be changed, err: %v", err)
+	}
+
+	invalid := []string{
+		`{"launch_template":{"version":1},"bk_biz_id":1}`,
+		`{"launch_template":{"template_id":"lt-1"}}`,
+		`{"launch_template":{"template_id":"lt-1"},"bk_biz_id":-1}`,
+		`{"launch_template":"lt-1","bk_biz_id":1}`,
+	}
+	for _, one := range invalid {
+		if _, _, err = ApplyCvmLaunchTemplate(kit.New(), nil, enumor.TCloud, json.RawMessage(one)); err == nil {
+			t.Errorf("apply launch template with body %s should fail", one)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package common
+
+import (
+	"strconv"
+
+	corecloud "hcm/pkg/api/core/cloud"
+	protocloud "hcm/pkg/api/data-service/cloud"
+	dataservice "hcm/pkg/client/data-service"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/tools/userdata"
+)
+
+// 主机自定义数据模版的应用来源
+const (
+	UserDataSourceCreateCvm   = "create_cvm"
+	UserDataSourceApplication = "application"
+)
+
+// CvmUserDataOption define the option to render user data template for cvm creation.
+type CvmUserDataOption struct {
+	Vendor       enumor.Vendor
+	BkBizID      int64
+	AccountID    string
+	Region       string
+	Zone         string
+	Name         string
+	InstanceType string
+	Template     *corecloud.UserDataTemplateRef
+	// Apply 为true时表示模版被实际用于创建主机，会记录模版应用审计，为false时只校验模版能否渲染
+	Apply  bool
+	Source string
+}
+
+// RenderCvmUserData render the user data template referenced by cvm creation, and return the user data encoded
+// as the vendor required. returns nil if no user data template is referenced.
+func RenderCvmUserData(kt *kit.Kit, cli *dataservice.Client, opt *CvmUserDataOption) (*string, error) {
+	if opt == nil || opt.Template == nil {
+		return nil, nil
+	}
+
+	req := &protocloud.UserDataTemplateRenderReq{
+		UserDataTemplateRef: *opt.Template,
+		BkBizID:             opt.BkBizID,
+		Vendor:              opt.Vendor,
+		Builtin: map[string]string{
+			userdata.HostnameVar:     opt.Name,
+			userdata.BkBizIDVar:      strconv.FormatInt(opt.BkBizID, 10),
+			userdata.RegionVar:       opt.Region,
+			userdata.ZoneVar:         opt.Zone,
+			userdata.AccountIDVar:    opt.AccountID,
+			userdata.VendorVar:       string(opt.Vendor),
+			userdata.InstanceTypeVar: opt.InstanceType,
+		},
+	}
+	if opt.Apply {
+		req.Apply = &protocloud.UserDataTemplateApply{
+			AccountID: opt.AccountID,
+			ResName:   opt.Name,
+			Source:    opt.Source,
+		}
+	}
+
+	result, err := cli.Global.UserDataTemplate.Render(kt, req)
+	if err != nil {
+		logs.Errorf("render cvm user data template failed, err: %v, template: %s, rid: %s", err,
+			opt.Template.TemplateID, kt.Rid)
+		return nil, err
+	}
+
+	return &result.UserData, nil
+}
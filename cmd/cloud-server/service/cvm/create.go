@@ -34,6 +34,7 @@ import (
 	"hcm/pkg/criteria/enumor"
 	"hcm/pkg/criteria/errf"
 	"hcm/pkg/iam/meta"
+	"hcm/pkg/kit"
 	"hcm/pkg/logs"
 	"hcm/pkg/rest"
 )
@@ -61,18 +62,25 @@ func (svc *cvmSvc) CreateCvm(cts *rest.Contexts) (interface{}, error) {
 		return nil, err
 	}
 
+	// 引用了启动模版时，将模版配置与请求参数合并
+	body, err := svc.applyLaunchTemplate(cts.Kit, info.Vendor, req.AccountID, req.Data)
+	if err != nil {
+		logs.Errorf("apply launch template failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
 	tasks := make([]ts.CustomFlowTask, 0)
 	switch info.Vendor {
 	case enumor.TCloud:
-		tasks, err = svc.buildCreateTCloudCvmTasks(req.Data)
+		tasks, err = svc.buildCreateTCloudCvmTasks(cts.Kit, body)
 	case enumor.Aws:
-		tasks, err = svc.buildCreateAwsCvmTasks(req.Data)
+		tasks, err = svc.buildCreateAwsCvmTasks(cts.Kit, body)
 	case enumor.HuaWei:
-		tasks, err = svc.buildCreateHuaWeiCvmTasks(req.Data)
+		tasks, err = svc.buildCreateHuaWeiCvmTasks(cts.Kit, body)
 	case enumor.Gcp:
-		tasks, err = svc.buildCreateGcpCvmTasks(req.Data)
+		tasks, err = svc.buildCreateGcpCvmTasks(cts.Kit, body)
 	case enumor.Azure:
-		tasks, err = svc.buildCreateAzureCvmTasks(req.Data)
+		tasks, err = svc.buildCreateAzureCvmTasks(cts.Kit, body)
 	default:
 		return nil, fmt.Errorf("vendor: %s not support", info.Vendor)
 	}
@@ -94,7 +102,7 @@ func (svc *cvmSvc) CreateCvm(cts *rest.Contexts) (interface{}, error) {
 	return result, async.WaitTaskToEnd(cts.Kit, svc.client.TaskServer(), result.ID)
 }
 
-func (svc *cvmSvc) buildCreateAzureCvmTasks(body json.RawMessage) ([]ts.CustomFlowTask, error) {
+func (svc *cvmSvc) buildCreateAzureCvmTasks(kt *kit.Kit, body json.RawMessage) ([]ts.CustomFlowTask, error) {
 
 	req := new(cscvm.AzureCvmCreateReq)
 	if err := json.Unmarshal(body, req); err != nil {
@@ -105,15 +113,31 @@ func (svc *cvmSvc) buildCreateAzureCvmTasks(body json.RawMessage) ([]ts.CustomFl
 		return nil, errf.NewFromErr(errf.InvalidParameter, err)
 	}
 
+	userData, err := svc.renderCvmUserData(kt, &common.CvmUserDataOption{
+		Vendor:       enumor.Azure,
+		BkBizID:      req.BkBizID,
+		AccountID:    req.AccountID,
+		Region:       req.Region,
+		Zone:         req.Zone,
+		Name:         req.Name,
+		InstanceType: req.InstanceType,
+		Template:     req.UserDataTemplate,
+	})
+	if err != nil {
+		return nil, err
+	}
+
 	tasks := actioncvm.BuildCreateCvmTasks(req.RequiredCount, constant.UnassignedBiz, 1,
 		func(actionID action.ActIDType, count int64) ts.CustomFlowTask {
 			req.RequiredCount = count
+			createReq := common.ConvAzureCvmCreateReq(req)
+			createReq.UserData = userData
 			return ts.CustomFlowTask{
 				ActionID:   actionID,
 				ActionName: enumor.ActionCreateCvm,
 				Params: &actioncvm.CreateOption{
 					Vendor:         enumor.Azure,
-					AzureCreateReq: *common.ConvAzureCvmCreateReq(req),
+					AzureCreateReq: *createReq,
 				},
 			}
 		})
@@ -121,7 +145,7 @@ func (svc *cvmSvc) buildCreateAzureCvmTasks(body json.RawMessage) ([]ts.CustomFl
 	return tasks, nil
 }
 
-func (svc *cvmSvc) buildCreateHuaWeiCvmTasks(body json.RawMessage) ([]ts.CustomFlowTask, error) {
+func (svc *cvmSvc) buildCreateHuaWeiCvmTasks(kt *kit.Kit, body json.RawMessage) ([]ts.CustomFlowTask, error) {
 
 	req := new(cscvm.HuaWeiCvmCreateReq)
 	if err := json.Unmarshal(body, req); err != nil {
@@ -132,16 +156,32 @@ func (svc *cvmSvc) buildCreateHuaWeiCvmTasks(body json.RawMessage) ([]ts.CustomF
 		return nil, errf.NewFromErr(errf.InvalidParameter, err)
 	}
 
+	userData, err := svc.renderCvmUserData(kt, &common.CvmUserDataOption{
+		Vendor:       enumor.HuaWei,
+		BkBizID:      req.BkBizID,
+		AccountID:    req.AccountID,
+		Region:       req.Region,
+		Zone:         req.Zone,
+		Name:         req.Name,
+		InstanceType: req.InstanceType,
+		Template:     req.UserDataTemplate,
+	})
+	if err != nil {
+		return nil, err
+	}
+
 	tasks := actioncvm.BuildCreateCvmTasks(req.RequiredCount, constant.UnassignedBiz,
 		constant.BatchCreateCvmFromCloudMaxLimit,
 		func(actionID action.ActIDType, count int64) ts.CustomFlowTask {
 			req.RequiredCount = count
+			createReq := common.ConvHuaWeiCvmCreateReq(req)
+			createReq.UserData = userData
 			return ts.CustomFlowTask{
 				ActionID:   actionID,
 				ActionName: enumor.ActionCreateCvm,
 				Params: &actioncvm.CreateOption{
 					Vendor:               enumor.HuaWei,
-					HuaWeiBatchCreateReq: *common.ConvHuaWeiCvmCreateReq(req),
+					HuaWeiBatchCreateReq: *createReq,
 				},
 			}
 		})
@@ -149,7 +189,7 @@ func (svc *cvmSvc) buildCreateHuaWeiCvmTasks(body json.RawMessage) ([]ts.CustomF
 	return tasks, nil
 }
 
-func (svc *cvmSvc) buildCreateGcpCvmTasks(body json.RawMessage) ([]ts.CustomFlowTask, error) {
+func (svc *cvmSvc) buildCreateGcpCvmTasks(kt *kit.Kit, body json.RawMessage) ([]ts.CustomFlowTask, error) {
 
 	req := new(cscvm.GcpCvmCreateReq)
 	if err := json.Unmarshal(body, req); err != nil {
@@ -160,16 +200,32 @@ func (svc *cvmSvc) buildCreateGcpCvmTasks(body json.RawMessage) ([]ts.CustomFlow
 		return nil, errf.NewFromErr(errf.InvalidParameter, err)
 	}
 
+	userData, err := svc.renderCvmUserData(kt, &common.CvmUserDataOption{
+		Vendor:       enumor.Gcp,
+		BkBizID:      req.BkBizID,
+		AccountID:    req.AccountID,
+		Region:       req.Region,
+		Zone:         req.Zone,
+		Name:         req.Name,
+		InstanceType: req.InstanceType,
+		Template:     req.UserDataTemplate,
+	})
+	if err != nil {
+		return nil, err
+	}
+
 	tasks := actioncvm.BuildCreateCvmTasks(req.RequiredCount, constant.UnassignedBiz,
 		constant.BatchCreateCvmFromCloudMaxLimit,
 		func(actionID action.ActIDType, count int64) ts.CustomFlowTask {
 			req.RequiredCount = count
+			createReq := common.ConvGcpCvmCreateReq(req)
+			createReq.UserData = userData
 			return ts.CustomFlowTask{
 				ActionID:   actionID,
 				ActionName: enumor.ActionCreateCvm,
 				Params: &actioncvm.CreateOption{
 					Vendor:            enumor.Gcp,
-					GcpBatchCreateReq: *common.ConvGcpCvmCreateReq(req),
+					GcpBatchCreateReq: *createReq,
 				},
 			}
 		})
@@ -177,7 +233,7 @@ func (svc *cvmSvc) buildCreateGcpCvmTasks(body json.RawMessage) ([]ts.CustomFlow
 	return tasks, nil
 }
 
-func (svc *cvmSvc) buildCreateAwsCvmTasks(body json.RawMessage) ([]ts.CustomFlowTask, error) {
+func (svc *cvmSvc) buildCreateAwsCvmTasks(kt *kit.Kit, body json.RawMessage) ([]ts.CustomFlowTask, error) {
 
 	req := new(cscvm.AwsCvmCreateReq)
 	if err := json.Unmarshal(body, req); err != nil {
@@ -188,16 +244,32 @@ func (svc *cvmSvc) buildCreateAwsCvmTasks(body json.RawMessage) ([]ts.CustomFlow
 		return nil, errf.NewFromErr(errf.InvalidParameter, err)
 	}
 
+	userData, err := svc.renderCvmUserData(kt, &common.CvmUserDataOption{
+		Vendor:       enumor.Aws,
+		BkBizID:      req.BkBizID,
+		AccountID:    req.AccountID,
+		Region:       req.Region,
+		Zone:         req.Zone,
+		Name:         req.Name,
+		InstanceType: req.InstanceType,
+		Template:     req.UserDataTemplate,
+	})
+	if err != nil {
+		return nil, err
+	}
+
 	tasks := actioncvm.BuildCreateCvmTasks(req.RequiredCount, constant.UnassignedBiz,
 		constant.BatchCreateCvmFromCloudMaxLimit,
 		func(actionID action.ActIDType, count int64) ts.CustomFlowTask {
 			req.RequiredCount = count
+			createReq := common.ConvAwsCvmCreateReq(req)
+			createReq.UserData = userData
 			return ts.CustomFlowTask{
 				ActionID:   actionID,
 				ActionName: enumor.ActionCreateCvm,
 				Params: &actioncvm.CreateOption{
 					Vendor:            enumor.Aws,
-					AwsBatchCreateReq: *common.ConvAwsCvmCreateReq(req),
+					AwsBatchCreateReq: *createReq,
 				},
 			}
 		})
@@ -205,7 +277,7 @@ func (svc *cvmSvc) buildCreateAwsCvmTasks(body json.RawMessage) ([]ts.CustomFlow
 	return tasks, nil
 }
 
-func (svc *cvmSvc) buildCreateTCloudCvmTasks(body json.RawMessage) ([]ts.CustomFlowTask, error) {
+func (svc *cvmSvc) buildCreateTCloudCvmTasks(kt *kit.Kit, body json.RawMessage) ([]ts.CustomFlowTask, error) {
 
 	req := new(cscvm.TCloudCvmCreateReq)
 	if err := json.Unmarshal(body, req); err != nil {
@@ -216,19 +288,51 @@ func (svc *cvmSvc) buildCreateTCloudCvmTasks(body json.RawMessage) ([]ts.CustomF
 		return nil, errf.NewFromErr(errf.InvalidParameter, err)
 	}
 
+	userData, err := svc.renderCvmUserData(kt, &common.CvmUserDataOption{
+		Vendor:       enumor.TCloud,
+		BkBizID:      req.BkBizID,
+		AccountID:    req.AccountID,
+		Region:       req.Region,
+		Zone:         req.Zone,
+		Name:         req.Name,
+		InstanceType: req.InstanceType,
+		Template:     req.UserDataTemplate,
+	})
+	if err != nil {
+		return nil, err
+	}
+
 	tasks := actioncvm.BuildCreateCvmTasks(req.RequiredCount, constant.UnassignedBiz,
 		constant.BatchCreateCvmFromCloudMaxLimit,
 		func(actionID action.ActIDType, count int64) ts.CustomFlowTask {
 			req.RequiredCount = count
+			createReq := common.ConvTCloudCvmCreateReq(req)
+			createReq.UserData = userData
 			return ts.CustomFlowTask{
 				ActionID:   actionID,
 				ActionName: enumor.ActionCreateCvm,
 				Params: &actioncvm.CreateOption{
 					Vendor:               enumor.TCloud,
-					TCloudBatchCreateReq: *common.ConvTCloudCvmCreateReq(req),
+					TCloudBatchCreateReq: *createReq,
 				},
 			}
 		})
 
 	return tasks, nil
 }
+
+// renderCvmUserData render the user data template referenced by cvm creation, user should have the access
+// permission of the biz that template belongs to.
+func (svc *cvmSvc) renderCvmUserData(kt *kit.Kit, opt *common.CvmUserDataOption) (*string, error) {
+	if opt.Template == nil {
+		return nil, nil
+	}
+
+	if err := svc.authorizeBizCvmTemplate(kt, opt.BkBizID, meta.Find); err != nil {
+		return nil, err
+	}
+
+	opt.Apply = true
+	opt.Source = common.UserDataSourceCreateCvm
+	return common.RenderCvmUserData(kt, svc.client.DataService(), opt)
+}
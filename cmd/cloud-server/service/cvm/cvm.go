@@ -79,6 +79,32 @@ func InitCvmService(c *capability.Capability) {
 	h.Add("BatchDeleteBizRecycledCvm", http.MethodDelete, "/bizs/{bk_biz_id}/recycled/cvms/batch",
 		svc.BatchDeleteBizRecycledCvm)
 
+	// 业务下主机自定义数据模版接口
+	h.Add("CreateBizUserDataTemplate", http.MethodPost, "/bizs/{bk_biz_id}/user_data_templates/create",
+		svc.CreateBizUserDataTemplate)
+	h.Add("UpdateBizUserDataTemplate", http.MethodPatch, "/bizs/{bk_biz_id}/user_data_templates/{id}",
+		svc.UpdateBizUserDataTemplate)
+	h.Add("ListBizUserDataTemplate", http.MethodPost, "/bizs/{bk_biz_id}/user_data_templates/list",
+		svc.ListBizUserDataTemplate)
+	h.Add("BatchDeleteBizUserDataTemplate", http.MethodDelete, "/bizs/{bk_biz_id}/user_data_templates/batch",
+		svc.BatchDeleteBizUserDataTemplate)
+	h.Add("ListBizUserDataTemplateVersion", http.MethodPost,
+		"/bizs/{bk_biz_id}/user_data_templates/{id}/versions/list", svc.ListBizUserDataTemplateVersion)
+	h.Add("PreviewBizUserDataTemplate", http.MethodPost, "/bizs/{bk_biz_id}/user_data_templates/{id}/preview",
+		svc.PreviewBizUserDataTemplate)
+
+	// 业务下主机启动模版接口
+	h.Add("CreateBizLaunchTemplate", http.MethodPost, "/bizs/{bk_biz_id}/launch_templates/create",
+		svc.CreateBizLaunchTemplate)
+	h.Add("UpdateBizLaunchTemplate", http.MethodPatch, "/bizs/{bk_biz_id}/launch_templates/{id}",
+		svc.UpdateBizLaunchTemplate)
+	h.Add("ListBizLaunchTemplate", http.MethodPost, "/bizs/{bk_biz_id}/launch_templates/list",
+		svc.ListBizLaunchTemplate)
+	h.Add("BatchDeleteBizLaunchTemplate", http.MethodDelete, "/bizs/{bk_biz_id}/launch_templates/batch",
+		svc.BatchDeleteBizLaunchTemplate)
+	h.Add("ListBizLaunchTemplateVersion", http.MethodPost,
+		"/bizs/{bk_biz_id}/launch_templates/{id}/versions/list", svc.ListBizLaunchTemplateVersion)
+
 	h.Load(c.WebService)
 }
 
@@ -0,0 +1,468 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+package cvm
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"hcm/cmd/cloud-server/service/common"
+	cloudserver "hcm/pkg/api/cloud-server"
+	cscvm "hcm/pkg/api/cloud-server/cvm"
+	"hcm/pkg/api/core"
+	corecloud "hcm/pkg/api/core/cloud"
+	dataservice "hcm/pkg/api/data-service"
+	protocloud "hcm/pkg/api/data-service/cloud"
+	dataprotozone "hcm/pkg/api/data-service/cloud/zone"
+	hcprotoinstancetype "hcm/pkg/api/hc-service/instance-type"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/iam/meta"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+	"hcm/pkg/runtime/filter"
+)
+
+// CreateBizLaunchTemplate create launch template in biz, vendor of template is the same as the account.
+func (svc *cvmSvc) CreateBizLaunchTemplate(cts *rest.Contexts) (interface{}, error) {
+	bizID, err := cts.PathParameter("bk_biz_id").Int64()
+	if err != nil {
+		return nil, err
+	}
+
+	req := new(cscvm.CreateLaunchTemplateReq)
+	if err = cts.DecodeInto(req); err != nil {
+		return nil, err
+	}
+
+	if err = req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	if err = svc.authorizeBizCvmTemplate(cts.Kit, bizID, meta.Create); err != nil {
+		return nil, err
+	}
+
+	info, err := svc.client.DataService().Global.Cloud.GetResBasicInfo(cts.Kit, enumor.AccountCloudResType,
+		req.AccountID)
+	if err != nil {
+		logs.Errorf("get account basic info failed, err: %v, account: %s, rid: %s", err, req.AccountID, cts.Kit.Rid)
+		return nil, err
+	}
+
+	if err = svc.validateLaunchTemplateSpec(cts.Kit, info.Vendor, req.AccountID, req.Region, req.Spec); err != nil {
+		return nil, err
+	}
+
+	createReq := &protocloud.LaunchTemplateCreateReq{
+		Name:      req.Name,
+		Vendor:    info.Vendor,
+		BkBizID:   bizID,
+		AccountID: req.AccountID,
+		Region:    req.Region,
+		Spec:      req.Spec,
+		Memo:      req.Memo,
+	}
+	result, err := svc.client.DataService().Global.LaunchTemplate.Create(cts.Kit, createReq)
+	if err != nil {
+		logs.Errorf("create launch template failed, err: %v, biz: %d, rid: %s", err, bizID, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// UpdateBizLaunchTemplate update launch template in biz, a new version will be created if spec is changed.
+func (svc *cvmSvc) UpdateBizLaunchTemplate(cts *rest.Contexts) (interface{}, error) {
+	bizID, err := cts.PathParameter("bk_biz_id").Int64()
+	if err != nil {
+		return nil, err
+	}
+
+	id := cts.PathParameter("id").String()
+	if len(id) == 0 {
+		return nil, errf.New(errf.InvalidParameter, "id is required")
+	}
+
+	req := new(cscvm.UpdateLaunchTemplateReq)
+	if err = cts.DecodeInto(req); err != nil {
+		return nil, err
+	}
+
+	if err = req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	if err = svc.authorizeBizCvmTemplate(cts.Kit, bizID, meta.Update); err != nil {
+		return nil, err
+	}
+
+	template, err := svc.getBizLaunchTemplate(cts.Kit, bizID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(req.Spec) != 0 {
+		if err = svc.validateLaunchTemplateSpec(cts.Kit, template.Vendor, template.AccountID, template.Region,
+			req.Spec); err != nil {
+			return nil, err
+		}
+	}
+
+	if err = svc.client.DataService().Global.LaunchTemplate.Update(cts.Kit, id,
+		&req.LaunchTemplateUpdateReq); err != nil {
+		logs.Errorf("update launch template failed, err: %v, id: %s, rid: %s", err, id, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// ListBizLaunchTemplate list launch template in biz.
+func (svc *cvmSvc) ListBizLaunchTemplate(cts *rest.Contexts) (interface{}, error) {
+	bizID, err := cts.PathParameter("bk_biz_id").Int64()
+	if err != nil {
+		return nil, err
+	}
+
+	req := new(cloudserver.ListReq)
+	if err = cts.DecodeInto(req); err != nil {
+		return nil, err
+	}
+
+	if err = req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	if err = svc.authorizeBizCvmTemplate(cts.Kit, bizID, meta.Find); err != nil {
+		return nil, err
+	}
+
+	expr, err := tools.And(req.Filter, tools.EqualExpression("bk_biz_id", bizID))
+	if err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	listReq := &core.ListReq{
+		Filter: expr,
+		Page:   req.Page,
+	}
+	return svc.client.DataService().Global.LaunchTemplate.List(cts.Kit, listReq)
+}
+
+// BatchDeleteBizLaunchTemplate batch delete launch template in biz.
+func (svc *cvmSvc) BatchDeleteBizLaunchTemplate(cts *rest.Contexts) (interface{}, error) {
+	bizID, err := cts.PathParameter("bk_biz_id").Int64()
+	if err != nil {
+		return nil, err
+	}
+
+	req := new(cscvm.BatchDeleteLaunchTemplateReq)
+	if err = cts.DecodeInto(req); err != nil {
+		return nil, err
+	}
+
+	if err = req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	if err = svc.authorizeBizCvmTemplate(cts.Kit, bizID, meta.Delete); err != nil {
+		return nil, err
+	}
+
+	// 只删除属于该业务的模版
+	deleteReq := &dataservice.BatchDeleteReq{
+		Filter: &filter.Expression{
+			Op: filter.And,
+			Rules: []filter.RuleFactory{
+				&filter.AtomRule{Field: "id", Op: filter.In.Factory(), Value: req.IDs},
+				&filter.AtomRule{Field: "bk_biz_id", Op: filter.Equal.Factory(), Value: bizID},
+			},
+		},
+	}
+	if err = svc.client.DataService().Global.LaunchTemplate.BatchDelete(cts.Kit, deleteReq); err != nil {
+		logs.Errorf("batch delete launch template failed, err: %v, ids: %v, rid: %s", err, req.IDs, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// ListBizLaunchTemplateVersion list launch template versions in biz.
+func (svc *cvmSvc) ListBizLaunchTemplateVersion(cts *rest.Contexts) (interface{}, error) {
+	bizID, err := cts.PathParameter("bk_biz_id").Int64()
+	if err != nil {
+		return nil, err
+	}
+
+	id := cts.PathParameter("id").String()
+	if len(id) == 0 {
+		return nil, errf.New(errf.InvalidParameter, "id is required")
+	}
+
+	req := new(cloudserver.ListReq)
+	if err = cts.DecodeInto(req); err != nil {
+		return nil, err
+	}
+
+	if err = req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	if err = svc.authorizeBizCvmTemplate(cts.Kit, bizID, meta.Find); err != nil {
+		return nil, err
+	}
+
+	if _, err = svc.getBizLaunchTemplate(cts.Kit, bizID, id); err != nil {
+		return nil, err
+	}
+
+	expr, err := tools.And(req.Filter, tools.EqualExpression("template_id", id))
+	if err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	listReq := &core.ListReq{
+		Filter: expr,
+		Page:   req.Page,
+	}
+	return svc.client.DataService().Global.LaunchTemplate.ListVersion(cts.Kit, listReq)
+}
+
+// applyLaunchTemplate merge the launch template referenced by cvm create request, template should belong to the
+// account that cvm created in, and user should have the access permission of the biz that template belongs to.
+func (svc *cvmSvc) applyLaunchTemplate(kt *kit.Kit, vendor enumor.Vendor, accountID string, body json.RawMessage) (
+	json.RawMessage, error) {
+
+	merged, template, err := common.ApplyCvmLaunchTemplate(kt, svc.client.DataService(), vendor, body)
+	if err != nil {
+		return nil, err
+	}
+
+	if template == nil {
+		return body, nil
+	}
+
+	if template.AccountID != accountID {
+		return nil, errf.Newf(errf.InvalidParameter, "launch template: %s not belongs to account: %s", template.ID,
+			accountID)
+	}
+
+	if err = svc.authorizeBizCvmTemplate(kt, template.BkBizID, meta.Find); err != nil {
+		return nil, err
+	}
+
+	return merged, nil
+}
+
+func (svc *cvmSvc) getBizLaunchTemplate(kt *kit.Kit, bizID int64, id string) (*corecloud.LaunchTemplate, error) {
+	listReq := &core.ListReq{
+		Filter: tools.EqualWithOpExpression(filter.And, map[string]interface{}{"id": id, "bk_biz_id": bizID}),
+		Page:   core.NewDefaultBasePage(),
+	}
+	result, err := svc.client.DataService().Global.LaunchTemplate.List(kt, listReq)
+	if err != nil {
+		logs.Errorf("list launch template failed, err: %v, id: %s, rid: %s", err, id, kt.Rid)
+		return nil, err
+	}
+
+	if len(result.Details) == 0 {
+		return nil, errf.Newf(errf.RecordNotFound, "launch template: %s not found in biz: %d", id, bizID)
+	}
+
+	return &result.Details[0], nil
+}
+
+// launchTemplateSpecRes 启动模版中需要与已同步的云资源数据进行校验的字段
+type launchTemplateSpecRes struct {
+	Zone               string `json:"zone"`
+	InstanceType       string `json:"instance_type"`
+	CloudImageID       string `json:"cloud_image_id"`
+	InstanceChargeType string `json:"instance_charge_type"`
+}
+
+// validateLaunchTemplateSpec validate spec fields according to vendor, and validate the zone, image and instance
+// type in spec exists in the account's region.
+func (svc *cvmSvc) validateLaunchTemplateSpec(kt *kit.Kit, vendor enumor.Vendor, accountID, region string,
+	spec json.RawMessage) error {
+
+	if err := cscvm.ValidateLaunchTemplateSpec(vendor, spec); err != nil {
+		return errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	res := new(launchTemplateSpecRes)
+	if err := json.Unmarshal(spec, res); err != nil {
+		return errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	if len(res.Zone) != 0 {
+		if err := svc.validateLaunchTemplateZone(kt, vendor, region, res.Zone); err != nil {
+			return err
+		}
+	}
+
+	if len(res.CloudImageID) != 0 {
+		if err := svc.validateLaunchTemplateImage(kt, vendor, res.CloudImageID); err != nil {
+			return err
+		}
+	}
+
+	if len(res.InstanceType) != 0 {
+		if err := svc.validateLaunchTemplateInstanceType(kt, vendor, accountID, region, res); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (svc *cvmSvc) validateLaunchTemplateZone(kt *kit.Kit, vendor enumor.Vendor, region, zone string) error {
+	listReq := &dataprotozone.ZoneListReq{
+		Filter: tools.EqualWithOpExpression(filter.And, map[string]interface{}{
+			"vendor": vendor,
+			"region": region,
+			"name":   zone,
+		}),
+		Page: &core.BasePage{Count: true},
+	}
+	result, err := svc.client.DataService().Global.Zone.ListZone(kt.Ctx, kt.Header(), listReq)
+	if err != nil {
+		logs.Errorf("list zone failed, err: %v, region: %s, zone: %s, rid: %s", err, region, zone, kt.Rid)
+		return err
+	}
+
+	if result.Count == 0 {
+		return errf.Newf(errf.InvalidParameter, "zone: %s not found in %s region: %s", zone, vendor, region)
+	}
+
+	return nil
+}
+
+func (svc *cvmSvc) validateLaunchTemplateImage(kt *kit.Kit, vendor enumor.Vendor, cloudImageID string) error {
+	listReq := &core.ListReq{
+		Filter: tools.EqualWithOpExpression(filter.And, map[string]interface{}{
+			"vendor":   vendor,
+			"cloud_id": cloudImageID,
+		}),
+		Page: &core.BasePage{Count: true},
+	}
+	result, err := svc.client.DataService().Global.ListImage(kt, listReq)
+	if err != nil {
+		logs.Errorf("list image failed, err: %v, image: %s, rid: %s", err, cloudImageID, kt.Rid)
+		return err
+	}
+
+	if result.Count == 0 {
+		return errf.Newf(errf.InvalidParameter, "%s image: %s not found", vendor, cloudImageID)
+	}
+
+	return nil
+}
+
+// validateLaunchTemplateInstanceType 机型未同步到本地，通过 hc-service 查询账号在对应地域/可用区下可用的机型进行校验
+func (svc *cvmSvc) validateLaunchTemplateInstanceType(kt *kit.Kit, vendor enumor.Vendor, accountID, region string,
+	res *launchTemplateSpecRes) error {
+
+	switch vendor {
+	case enumor.TCloud, enumor.HuaWei, enumor.Gcp:
+		if len(res.Zone) == 0 {
+			return errf.Newf(errf.InvalidParameter, "zone is required when instance_type is set for %s", vendor)
+		}
+	}
+
+	if vendor == enumor.TCloud && len(res.InstanceChargeType) == 0 {
+		return errf.New(errf.InvalidParameter, "instance_charge_type is required when instance_type is set")
+	}
+
+	instanceTypes, err := svc.listInstanceTypes(kt, vendor, accountID, region, res)
+	if err != nil {
+		logs.Errorf("list %s instance type failed, err: %v, account: %s, region: %s, rid: %s", vendor, err,
+			accountID, region, kt.Rid)
+		return err
+	}
+
+	for _, one := range instanceTypes {
+		if one == res.InstanceType {
+			return nil
+		}
+	}
+
+	return errf.Newf(errf.InvalidParameter, "instance_type: %s not found in %s region: %s, zone: %s",
+		res.InstanceType, vendor, region, res.Zone)
+}
+
+func (svc *cvmSvc) listInstanceTypes(kt *kit.Kit, vendor enumor.Vendor, accountID, region string,
+	res *launchTemplateSpecRes) ([]string, error) {
+
+	instanceTypes := make([]string, 0)
+	switch vendor {
+	case enumor.TCloud:
+		req := &hcprotoinstancetype.TCloudInstanceTypeListReq{AccountID: accountID, Region: region, Zone: res.Zone,
+			InstanceChargeType: res.InstanceChargeType}
+		list, err := svc.client.HCService().TCloud.InstanceType.List(kt, req)
+		if err != nil {
+			return nil, err
+		}
+		for _, one := range list {
+			instanceTypes = append(instanceTypes, one.InstanceType)
+		}
+	case enumor.Aws:
+		req := &hcprotoinstancetype.AwsInstanceTypeListReq{AccountID: accountID, Region: region}
+		list, err := svc.client.HCService().Aws.InstanceType.List(kt, req)
+		if err != nil {
+			return nil, err
+		}
+		for _, one := range list {
+			instanceTypes = append(instanceTypes, one.InstanceType)
+		}
+	case enumor.HuaWei:
+		req := &hcprotoinstancetype.HuaWeiInstanceTypeListReq{AccountID: accountID, Region: region, Zone: res.Zone}
+		list, err := svc.client.HCService().HuaWei.InstanceType.List(kt, req)
+		if err != nil {
+			return nil, err
+		}
+		for _, one := range list {
+			instanceTypes = append(instanceTypes, one.InstanceType)
+		}
+	case enumor.Gcp:
+		req := &hcprotoinstancetype.GcpInstanceTypeListReq{AccountID: accountID, Zone: res.Zone}
+		list, err := svc.client.HCService().Gcp.InstanceType.List(kt, req)
+		if err != nil {
+			return nil, err
+		}
+		for _, one := range list {
+			instanceTypes = append(instanceTypes, one.InstanceType)
+		}
+	case enumor.Azure:
+		req := &hcprotoinstancetype.AzureInstanceTypeListReq{AccountID: accountID, Region: region}
+		list, err := svc.client.HCService().Azure.InstanceType.List(kt, req)
+		if err != nil {
+			return nil, err
+		}
+		for _, one := range list {
+			instanceTypes = append(instanceTypes, one.InstanceType)
+		}
+	default:
+		return nil, fmt.Errorf("vendor: %s not support", vendor)
+	}
+
+	return instanceTypes, nil
+}
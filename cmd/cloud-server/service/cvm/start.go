@@ -21,6 +21,7 @@ package cvm
 
 import (
 	"fmt"
+	"sort"
 	"strconv"
 
 	"hcm/cmd/cloud-server/logics/async"
@@ -36,6 +37,7 @@ import (
 	"hcm/pkg/iam/meta"
 	"hcm/pkg/logs"
 	"hcm/pkg/rest"
+	"hcm/pkg/tools/converter"
 	"hcm/pkg/tools/hooks/handler"
 )
 
@@ -131,9 +133,15 @@ func buildOperationTasks(actionName enumor.ActionName, basicInfoMap map[string]t
 		}
 	}
 
+	// 按照 key 排序生成任务，保证相同请求生成的任务流内容一致，重复请求可以命中幂等键
+	keys := converter.MapKeyToStringSlice(paramMaps)
+	sort.Strings(keys)
+
 	tasks := make([]ts.CustomFlowTask, 0, len(paramMaps))
 	count := 1
-	for _, one := range paramMaps {
+	for _, key := range keys {
+		one := paramMaps[key]
+		sort.Strings(one.IDs)
 		tasks = append(tasks, ts.CustomFlowTask{
 			ActionID:   action.ActIDType(strconv.Itoa(count)),
 			ActionName: actionName,
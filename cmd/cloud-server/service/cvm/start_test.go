@@ -0,0 +1,95 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package cvm
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	actioncvm "hcm/cmd/task-server/logics/action/cvm"
+	ts "hcm/pkg/api/task-server"
+	"hcm/pkg/async/action"
+	"hcm/pkg/async/backend"
+	"hcm/pkg/async/producer"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/dal/dao/types"
+	"hcm/pkg/kit"
+	"hcm/pkg/tools/json"
+)
+
+func TestBatchOperationIdempotent(t *testing.T) {
+	action.RegisterAction(actioncvm.NewStartAction())
+
+	bd := backend.NewMemory()
+	pro, err := producer.NewProducer(bd, prometheus.NewRegistry(), &producer.Option{IdempotencyKeyTTLSec: 60})
+	if err != nil {
+		t.Fatalf("new producer failed, err: %v", err)
+	}
+
+	infos := []types.CloudResourceBasicInfo{
+		{ID: "cvm-1", Vendor: enumor.TCloud, AccountID: "0001", Region: "ap-guangzhou"},
+		{ID: "cvm-2", Vendor: enumor.TCloud, AccountID: "0001", Region: "ap-shanghai"},
+		{ID: "cvm-3", Vendor: enumor.TCloud, AccountID: "0001", Region: "ap-guangzhou"},
+		{ID: "cvm-4", Vendor: enumor.Gcp, AccountID: "0002"},
+		{ID: "cvm-5", Vendor: enumor.Gcp, AccountID: "0002"},
+	}
+
+	// 相同的批量请求多次提交，每次按照不同顺序查询到资源，使用相同的幂等键时返回同一个任务流
+	flowID := ""
+	for round := 0; round < 5; round++ {
+		basicInfoMap := make(map[string]types.CloudResourceBasicInfo, len(infos))
+		for i := range infos {
+			one := infos[(i+round)%len(infos)]
+			basicInfoMap[one.ID] = one
+		}
+
+		tasks, err := buildOperationTasks(enumor.ActionStartCvm, basicInfoMap)
+		if err != nil {
+			t.Fatalf("build operation tasks failed, err: %v", err)
+		}
+
+		// 模拟 cloud-server 请求 task-server 创建任务流时的序列化
+		raw, err := json.Marshal(&ts.AddCustomFlowReq{Name: enumor.FlowStartCvm, Tasks: tasks})
+		if err != nil {
+			t.Fatalf("marshal add flow request failed, err: %v", err)
+		}
+		opt := new(producer.AddCustomFlowOption)
+		if err = json.Unmarshal(raw, opt); err != nil {
+			t.Fatalf("unmarshal add flow request failed, err: %v", err)
+		}
+
+		kt := kit.New()
+		kt.User = "start_test"
+		kt.AppCode = "hcm"
+		kt.IdempotencyKey = "start-cvm"
+		id, err := pro.AddCustomFlow(kt, opt)
+		if err != nil {
+			t.Fatalf("round %d add custom flow failed, err: %v", round, err)
+		}
+
+		if len(flowID) == 0 {
+			flowID = id
+		}
+		if id != flowID {
+			t.Fatalf("round %d expect flow %s, got %s", round, flowID, id)
+		}
+	}
+}
@@ -0,0 +1,297 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package cvm
+
+import (
+	cloudserver "hcm/pkg/api/cloud-server"
+	cscvm "hcm/pkg/api/cloud-server/cvm"
+	"hcm/pkg/api/core"
+	corecloud "hcm/pkg/api/core/cloud"
+	dataservice "hcm/pkg/api/data-service"
+	protocloud "hcm/pkg/api/data-service/cloud"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/iam/meta"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+	"hcm/pkg/runtime/filter"
+)
+
+// CreateBizUserDataTemplate create user data template in biz.
+func (svc *cvmSvc) CreateBizUserDataTemplate(cts *rest.Contexts) (interface{}, error) {
+	bizID, err := cts.PathParameter("bk_biz_id").Int64()
+	if err != nil {
+		return nil, err
+	}
+
+	req := new(cscvm.CreateUserDataTemplateReq)
+	if err = cts.DecodeInto(req); err != nil {
+		return nil, err
+	}
+
+	if err = req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	if err = svc.authorizeBizCvmTemplate(cts.Kit, bizID, meta.Create); err != nil {
+		return nil, err
+	}
+
+	createReq := &protocloud.UserDataTemplateCreateReq{
+		Name:      req.Name,
+		BkBizID:   bizID,
+		Content:   req.Content,
+		Variables: req.Variables,
+		Memo:      req.Memo,
+	}
+	result, err := svc.client.DataService().Global.UserDataTemplate.Create(cts.Kit, createReq)
+	if err != nil {
+		logs.Errorf("create user data template failed, err: %v, biz: %d, rid: %s", err, bizID, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// UpdateBizUserDataTemplate update user data template in biz, a new version will be created if content is changed.
+func (svc *cvmSvc) UpdateBizUserDataTemplate(cts *rest.Contexts) (interface{}, error) {
+	bizID, err := cts.PathParameter("bk_biz_id").Int64()
+	if err != nil {
+		return nil, err
+	}
+
+	id := cts.PathParameter("id").String()
+	if len(id) == 0 {
+		return nil, errf.New(errf.InvalidParameter, "id is required")
+	}
+
+	req := new(cscvm.UpdateUserDataTemplateReq)
+	if err = cts.DecodeInto(req); err != nil {
+		return nil, err
+	}
+
+	if err = req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	if err = svc.authorizeBizCvmTemplate(cts.Kit, bizID, meta.Update); err != nil {
+		return nil, err
+	}
+
+	if _, err = svc.getBizUserDataTemplate(cts.Kit, bizID, id); err != nil {
+		return nil, err
+	}
+
+	if err = svc.client.DataService().Global.UserDataTemplate.Update(cts.Kit, id,
+		&req.UserDataTemplateUpdateReq); err != nil {
+		logs.Errorf("update user data template failed, err: %v, id: %s, rid: %s", err, id, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// ListBizUserDataTemplate list user data template in biz.
+func (svc *cvmSvc) ListBizUserDataTemplate(cts *rest.Contexts) (interface{}, error) {
+	bizID, err := cts.PathParameter("bk_biz_id").Int64()
+	if err != nil {
+		return nil, err
+	}
+
+	req := new(cloudserver.ListReq)
+	if err = cts.DecodeInto(req); err != nil {
+		return nil, err
+	}
+
+	if err = req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	if err = svc.authorizeBizCvmTemplate(cts.Kit, bizID, meta.Find); err != nil {
+		return nil, err
+	}
+
+	expr, err := tools.And(req.Filter, tools.EqualExpression("bk_biz_id", bizID))
+	if err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	listReq := &core.ListReq{
+		Filter: expr,
+		Page:   req.Page,
+	}
+	return svc.client.DataService().Global.UserDataTemplate.List(cts.Kit, listReq)
+}
+
+// BatchDeleteBizUserDataTemplate batch delete user data template in biz.
+func (svc *cvmSvc) BatchDeleteBizUserDataTemplate(cts *rest.Contexts) (interface{}, error) {
+	bizID, err := cts.PathParameter("bk_biz_id").Int64()
+	if err != nil {
+		return nil, err
+	}
+
+	req := new(cscvm.BatchDeleteUserDataTemplateReq)
+	if err = cts.DecodeInto(req); err != nil {
+		return nil, err
+	}
+
+	if err = req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	if err = svc.authorizeBizCvmTemplate(cts.Kit, bizID, meta.Delete); err != nil {
+		return nil, err
+	}
+
+	// 只删除属于该业务的模版
+	deleteReq := &dataservice.BatchDeleteReq{
+		Filter: &filter.Expression{
+			Op: filter.And,
+			Rules: []filter.RuleFactory{
+				&filter.AtomRule{Field: "id", Op: filter.In.Factory(), Value: req.IDs},
+				&filter.AtomRule{Field: "bk_biz_id", Op: filter.Equal.Factory(), Value: bizID},
+			},
+		},
+	}
+	if err = svc.client.DataService().Global.UserDataTemplate.BatchDelete(cts.Kit, deleteReq); err != nil {
+		logs.Errorf("batch delete user data template failed, err: %v, ids: %v, rid: %s", err, req.IDs, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// ListBizUserDataTemplateVersion list user data template versions in biz.
+func (svc *cvmSvc) ListBizUserDataTemplateVersion(cts *rest.Contexts) (interface{}, error) {
+	bizID, err := cts.PathParameter("bk_biz_id").Int64()
+	if err != nil {
+		return nil, err
+	}
+
+	id := cts.PathParameter("id").String()
+	if len(id) == 0 {
+		return nil, errf.New(errf.InvalidParameter, "id is required")
+	}
+
+	req := new(cloudserver.ListReq)
+	if err = cts.DecodeInto(req); err != nil {
+		return nil, err
+	}
+
+	if err = req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	if err = svc.authorizeBizCvmTemplate(cts.Kit, bizID, meta.Find); err != nil {
+		return nil, err
+	}
+
+	if _, err = svc.getBizUserDataTemplate(cts.Kit, bizID, id); err != nil {
+		return nil, err
+	}
+
+	expr, err := tools.And(req.Filter, tools.EqualExpression("template_id", id))
+	if err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	listReq := &core.ListReq{
+		Filter: expr,
+		Page:   req.Page,
+	}
+	return svc.client.DataService().Global.UserDataTemplate.ListVersion(cts.Kit, listReq)
+}
+
+// PreviewBizUserDataTemplate preview the rendered user data template in biz, secret variables are redacted.
+func (svc *cvmSvc) PreviewBizUserDataTemplate(cts *rest.Contexts) (interface{}, error) {
+	bizID, err := cts.PathParameter("bk_biz_id").Int64()
+	if err != nil {
+		return nil, err
+	}
+
+	id := cts.PathParameter("id").String()
+	if len(id) == 0 {
+		return nil, errf.New(errf.InvalidParameter, "id is required")
+	}
+
+	req := new(cscvm.PreviewUserDataTemplateReq)
+	if err = cts.DecodeInto(req); err != nil {
+		return nil, err
+	}
+
+	if err = req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	if err = svc.authorizeBizCvmTemplate(cts.Kit, bizID, meta.Find); err != nil {
+		return nil, err
+	}
+
+	renderReq := &protocloud.UserDataTemplateRenderReq{
+		UserDataTemplateRef: corecloud.UserDataTemplateRef{
+			TemplateID: id,
+			Version:    req.Version,
+			Variables:  req.Variables,
+		},
+		BkBizID: bizID,
+		Vendor:  req.Vendor,
+		Builtin: req.Builtin,
+	}
+	result, err := svc.client.DataService().Global.UserDataTemplate.Render(cts.Kit, renderReq)
+	if err != nil {
+		logs.Errorf("render user data template failed, err: %v, id: %s, rid: %s", err, id, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// authorizeBizCvmTemplate 主机自定义数据模版、启动模版均属于业务，使用业务的权限鉴权。
+func (svc *cvmSvc) authorizeBizCvmTemplate(kt *kit.Kit, bizID int64, action meta.Action) error {
+	authRes := meta.ResourceAttribute{Basic: &meta.Basic{Type: meta.Biz, Action: action}, BizID: bizID}
+	if err := svc.authorizer.AuthorizeWithPerm(kt, authRes); err != nil {
+		logs.Errorf("authorize biz cvm template failed, err: %v, biz: %d, action: %s, rid: %s", err, bizID,
+			action, kt.Rid)
+		return err
+	}
+
+	return nil
+}
+
+func (svc *cvmSvc) getBizUserDataTemplate(kt *kit.Kit, bizID int64, id string) (*corecloud.UserDataTemplate,
+	error) {
+
+	listReq := &core.ListReq{
+		Filter: tools.EqualWithOpExpression(filter.And, map[string]interface{}{"id": id, "bk_biz_id": bizID}),
+		Page:   core.NewDefaultBasePage(),
+	}
+	result, err := svc.client.DataService().Global.UserDataTemplate.List(kt, listReq)
+	if err != nil {
+		logs.Errorf("list user data template failed, err: %v, id: %s, rid: %s", err, id, kt.Rid)
+		return nil, err
+	}
+
+	if len(result.Details) == 0 {
+		return nil, errf.Newf(errf.RecordNotFound, "user data template: %s not found in biz: %d", id, bizID)
+	}
+
+	return &result.Details[0], nil
+}
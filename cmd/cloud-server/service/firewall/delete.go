@@ -30,8 +30,8 @@ import (
 	"hcm/pkg/logs"
 	"hcm/pkg/rest"
 	"hcm/pkg/tools/converter"
+	"hcm/pkg/tools/counter"
 	"hcm/pkg/tools/hooks/handler"
-	"hcm/pkg/tools/uuid"
 )
 
 // BatchDeleteGcpFirewallRule batch delete gcp firewall rule.
@@ -75,9 +75,10 @@ func (svc *firewallSvc) batchDeleteGcpFirewallRule(cts *rest.Contexts, validHand
 	}
 
 	tasks := make([]ts.CustomFlowTask, 0, len(req.IDs))
+	nextID := counter.NewNumStringCounter(1, 10)
 	for _, id := range req.IDs {
 		tasks = append(tasks, ts.CustomFlowTask{
-			ActionID:   action.ActIDType(uuid.UUID()),
+			ActionID:   action.ActIDType(nextID()),
 			ActionName: enumor.ActionDeleteFirewallRule,
 			Params:     converter.ValToPtr(id),
 		})
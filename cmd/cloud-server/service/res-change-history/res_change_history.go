@@ -0,0 +1,157 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+// Package reschangehistory ...
+package reschangehistory
+
+import (
+	"net/http"
+
+	"hcm/cmd/cloud-server/service/capability"
+	proto "hcm/pkg/api/cloud-server"
+	"hcm/pkg/api/core"
+	corecloud "hcm/pkg/api/core/cloud"
+	protocloud "hcm/pkg/api/data-service/cloud"
+	"hcm/pkg/client"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/iam/auth"
+	"hcm/pkg/iam/meta"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+	"hcm/pkg/tools/hooks/handler"
+)
+
+// InitService initialize the resource change history service.
+func InitService(c *capability.Capability) {
+	svc := &svc{
+		client:     c.ApiClient,
+		authorizer: c.Authorizer,
+	}
+
+	h := rest.NewHandler()
+
+	h.Add("ListResChangeHistory", http.MethodPost, "/res_change_histories/list", svc.ListResChangeHistory)
+	h.Add("ListResChangeHistoryAsOf", http.MethodPost, "/res_change_histories/as_of/list",
+		svc.ListResChangeHistoryAsOf)
+
+	// biz resource change history apis
+	h.Add("ListBizResChangeHistory", http.MethodPost, "/bizs/{bk_biz_id}/res_change_histories/list",
+		svc.ListBizResChangeHistory)
+	h.Add("ListBizResChangeHistoryAsOf", http.MethodPost, "/bizs/{bk_biz_id}/res_change_histories/as_of/list",
+		svc.ListBizResChangeHistoryAsOf)
+
+	h.Load(c.WebService)
+}
+
+type svc struct {
+	client     *client.ClientSet
+	authorizer auth.Authorizer
+}
+
+// ListResChangeHistory list resource change history.
+func (svc svc) ListResChangeHistory(cts *rest.Contexts) (interface{}, error) {
+	return svc.listResChangeHistory(cts, handler.ListResourceAuthRes)
+}
+
+// ListBizResChangeHistory list biz resource change history.
+func (svc svc) ListBizResChangeHistory(cts *rest.Contexts) (interface{}, error) {
+	return svc.listResChangeHistory(cts, handler.ListBizAuthRes)
+}
+
+func (svc svc) listResChangeHistory(cts *rest.Contexts, authHandler handler.ListAuthResHandler) (interface{},
+	error) {
+
+	req := new(proto.ResChangeHistoryListReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, err
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	// 变更历史与审计记录同属操作记录，使用审计的查看权限鉴权
+	expr, noPermFlag, err := authHandler(cts, &handler.ListAuthResOption{Authorizer: svc.authorizer,
+		ResType: meta.Audit, Action: meta.Find, Filter: req.Filter})
+	if err != nil {
+		return nil, err
+	}
+
+	if noPermFlag {
+		return &protocloud.ResChangeHistoryListResult{Count: 0, Details: make([]corecloud.ResChangeHistory, 0)}, nil
+	}
+
+	listReq := &core.ListReq{
+		Filter: expr,
+		Page:   req.Page,
+	}
+	result, err := svc.client.DataService().Global.ResChangeHistory.List(cts.Kit, listReq)
+	if err != nil {
+		logs.Errorf("list resource change history failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// ListResChangeHistoryAsOf list the snapshot of resources as of a timestamp.
+func (svc svc) ListResChangeHistoryAsOf(cts *rest.Contexts) (interface{}, error) {
+	return svc.listResChangeHistoryAsOf(cts, handler.ListResourceAuthRes)
+}
+
+// ListBizResChangeHistoryAsOf list the snapshot of biz resources as of a timestamp.
+func (svc svc) ListBizResChangeHistoryAsOf(cts *rest.Contexts) (interface{}, error) {
+	return svc.listResChangeHistoryAsOf(cts, handler.ListBizAuthRes)
+}
+
+func (svc svc) listResChangeHistoryAsOf(cts *rest.Contexts, authHandler handler.ListAuthResHandler) (interface{},
+	error) {
+
+	req := new(proto.ResChangeHistoryAsOfListReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, err
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	expr, noPermFlag, err := authHandler(cts, &handler.ListAuthResOption{Authorizer: svc.authorizer,
+		ResType: meta.Audit, Action: meta.Find, Filter: req.Filter})
+	if err != nil {
+		return nil, err
+	}
+
+	if noPermFlag {
+		return &protocloud.ResChangeHistoryListResult{Count: 0, Details: make([]corecloud.ResChangeHistory, 0)}, nil
+	}
+
+	listReq := &protocloud.ResChangeHistoryAsOfListReq{
+		ResType: req.ResType,
+		AsOf:    req.AsOf,
+		Filter:  expr,
+		Page:    req.Page,
+	}
+	result, err := svc.client.DataService().Global.ResChangeHistory.ListAsOf(cts.Kit, listReq)
+	if err != nil {
+		logs.Errorf("list %s as of %s failed, err: %v, rid: %s", req.ResType, req.AsOf, err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return result, nil
+}
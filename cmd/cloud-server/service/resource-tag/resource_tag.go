@@ -0,0 +1,320 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package resourcetag ...
+package resourcetag
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+
+	"hcm/cmd/cloud-server/logics/audit"
+	"hcm/cmd/cloud-server/service/capability"
+	cloudserver "hcm/pkg/api/cloud-server"
+	"hcm/pkg/api/core"
+	corecloud "hcm/pkg/api/core/cloud"
+	protoaudit "hcm/pkg/api/data-service/audit"
+	dataproto "hcm/pkg/api/data-service/cloud"
+	hctag "hcm/pkg/api/hc-service/tag"
+	"hcm/pkg/client"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/dal/dao/types"
+	"hcm/pkg/iam/auth"
+	"hcm/pkg/iam/meta"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+	"hcm/pkg/tools/hooks/handler"
+)
+
+// InitResourceTagService initialize the resource tag service.
+func InitResourceTagService(c *capability.Capability) {
+	svc := &resTagSvc{
+		client:     c.ApiClient,
+		authorizer: c.Authorizer,
+		audit:      c.Audit,
+	}
+
+	h := rest.NewHandler()
+
+	for _, res := range tagResources {
+		h.Add(fmt.Sprintf("List%sTag", res.name), http.MethodPost, fmt.Sprintf("/%s/tags/list", res.path),
+			svc.listResTag(res, handler.ResOperateAuth))
+		h.Add(fmt.Sprintf("BatchUpsert%sTag", res.name), http.MethodPost,
+			fmt.Sprintf("/%s/tags/batch/upsert", res.path), svc.batchUpsertResTag(res, handler.ResOperateAuth))
+		h.Add(fmt.Sprintf("BatchDelete%sTag", res.name), http.MethodDelete, fmt.Sprintf("/%s/tags/batch", res.path),
+			svc.batchDeleteResTag(res, handler.ResOperateAuth))
+
+		// resource tag apis in biz
+		h.Add(fmt.Sprintf("ListBiz%sTag", res.name), http.MethodPost,
+			fmt.Sprintf("/bizs/{bk_biz_id}/%s/tags/list", res.path), svc.listResTag(res, handler.BizOperateAuth))
+		h.Add(fmt.Sprintf("BatchUpsertBiz%sTag", res.name), http.MethodPost,
+			fmt.Sprintf("/bizs/{bk_biz_id}/%s/tags/batch/upsert", res.path),
+			svc.batchUpsertResTag(res, handler.BizOperateAuth))
+		h.Add(fmt.Sprintf("BatchDeleteBiz%sTag", res.name), http.MethodDelete,
+			fmt.Sprintf("/bizs/{bk_biz_id}/%s/tags/batch", res.path),
+			svc.batchDeleteResTag(res, handler.BizOperateAuth))
+	}
+
+	h.Load(c.WebService)
+}
+
+type resTagSvc struct {
+	client     *client.ClientSet
+	authorizer auth.Authorizer
+	audit      audit.Interface
+}
+
+type restHandler func(cts *rest.Contexts) (interface{}, error)
+
+// tagResource 支持标签操作的资源及其对应的路由、鉴权及审计类型
+type tagResource struct {
+	name      string
+	path      string
+	resType   enumor.CloudResourceType
+	iamType   meta.ResourceType
+	auditType enumor.AuditResourceType
+}
+
+var tagResources = []tagResource{
+	{name: "Cvm", path: "cvms", resType: enumor.CvmCloudResType, iamType: meta.Cvm,
+		auditType: enumor.CvmAuditResType},
+	{name: "Disk", path: "disks", resType: enumor.DiskCloudResType, iamType: meta.Disk,
+		auditType: enumor.DiskAuditResType},
+	{name: "Eip", path: "eips", resType: enumor.EipCloudResType, iamType: meta.Eip,
+		auditType: enumor.EipAuditResType},
+	{name: "Vpc", path: "vpcs", resType: enumor.VpcCloudResType, iamType: meta.Vpc,
+		auditType: enumor.VpcCloudAuditResType},
+	{name: "Subnet", path: "subnets", resType: enumor.SubnetCloudResType, iamType: meta.Subnet,
+		auditType: enumor.SubnetAuditResType},
+}
+
+func (svc *resTagSvc) listResTag(res tagResource, validHandler handler.ValidWithAuthHandler) restHandler {
+	return func(cts *rest.Contexts) (interface{}, error) {
+		req := new(cloudserver.ListResTagReq)
+		if err := cts.DecodeInto(req); err != nil {
+			return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+		}
+
+		if err := req.Validate(); err != nil {
+			return nil, errf.NewFromErr(errf.InvalidParameter, err)
+		}
+
+		basicInfoReq := dataproto.ListResourceBasicInfoReq{
+			ResourceType: res.resType,
+			IDs:          req.IDs,
+		}
+		basicInfoMap, err := svc.client.DataService().Global.Cloud.ListResBasicInfo(cts.Kit, basicInfoReq)
+		if err != nil {
+			return nil, err
+		}
+
+		// validate biz and authorize
+		err = validHandler(cts, &handler.ValidWithAuthOption{Authorizer: svc.authorizer, ResType: res.iamType,
+			Action: meta.Find, BasicInfos: basicInfoMap})
+		if err != nil {
+			return nil, err
+		}
+
+		result := make(cloudserver.ListResTagResult, len(req.IDs))
+		for _, id := range req.IDs {
+			result[id] = make([]corecloud.TagPair, 0)
+		}
+
+		listReq := &core.ListReq{
+			Filter: tools.ResourceTagExpression(res.resType, req.IDs),
+			Page:   core.NewDefaultBasePage(),
+		}
+		for {
+			tags, err := svc.client.DataService().Global.ResourceTag.List(cts.Kit, listReq)
+			if err != nil {
+				logs.Errorf("list %s tag failed, err: %v, ids: %v, rid: %s", res.resType, err, req.IDs, cts.Kit.Rid)
+				return nil, err
+			}
+
+			for _, one := range tags.Details {
+				result[one.ResID] = append(result[one.ResID], corecloud.TagPair{Key: one.TagKey, Value: one.TagValue})
+			}
+
+			if len(tags.Details) < int(core.DefaultMaxPageLimit) {
+				break
+			}
+			listReq.Page.Start += uint32(core.DefaultMaxPageLimit)
+		}
+
+		return result, nil
+	}
+}
+
+func (svc *resTagSvc) batchUpsertResTag(res tagResource, validHandler handler.ValidWithAuthHandler) restHandler {
+	return func(cts *rest.Contexts) (interface{}, error) {
+		req := new(cloudserver.BatchUpsertResTagReq)
+		if err := cts.DecodeInto(req); err != nil {
+			return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+		}
+
+		if err := req.Validate(); err != nil {
+			return nil, errf.NewFromErr(errf.InvalidParameter, err)
+		}
+
+		groups, err := svc.authResTagOperation(cts, validHandler, res, req.IDs, protoaudit.Tag)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, group := range groups {
+			hcReq := &hctag.BatchUpsertTagReq{
+				ResType:   res.resType,
+				AccountID: group.accountID,
+				IDs:       group.ids,
+				Tags:      req.Tags,
+			}
+			if err = svc.upsertResTag(cts.Kit, group.vendor, hcReq); err != nil {
+				logs.Errorf("upsert %s %s tag failed, err: %v, ids: %v, rid: %s", group.vendor, res.resType, err,
+					group.ids, cts.Kit.Rid)
+				return nil, err
+			}
+		}
+
+		return nil, nil
+	}
+}
+
+func (svc *resTagSvc) upsertResTag(kt *kit.Kit, vendor enumor.Vendor, req *hctag.BatchUpsertTagReq) error {
+	switch vendor {
+	case enumor.TCloud:
+		return svc.client.HCService().TCloud.ResourceTag.BatchUpsert(kt, req)
+	case enumor.Aws:
+		return svc.client.HCService().Aws.ResourceTag.BatchUpsert(kt, req)
+	case enumor.HuaWei:
+		return svc.client.HCService().HuaWei.ResourceTag.BatchUpsert(kt, req)
+	case enumor.Azure:
+		return svc.client.HCService().Azure.ResourceTag.BatchUpsert(kt, req)
+	case enumor.Gcp:
+		return svc.client.HCService().Gcp.ResourceTag.BatchUpsert(kt, req)
+	default:
+		return fmt.Errorf("vendor: %s not support", vendor)
+	}
+}
+
+func (svc *resTagSvc) batchDeleteResTag(res tagResource, validHandler handler.ValidWithAuthHandler) restHandler {
+	return func(cts *rest.Contexts) (interface{}, error) {
+		req := new(cloudserver.BatchDeleteResTagReq)
+		if err := cts.DecodeInto(req); err != nil {
+			return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+		}
+
+		if err := req.Validate(); err != nil {
+			return nil, errf.NewFromErr(errf.InvalidParameter, err)
+		}
+
+		groups, err := svc.authResTagOperation(cts, validHandler, res, req.IDs, protoaudit.UnTag)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, group := range groups {
+			hcReq := &hctag.BatchDeleteTagReq{
+				ResType:   res.resType,
+				AccountID: group.accountID,
+				IDs:       group.ids,
+				TagKeys:   req.TagKeys,
+			}
+			if err = svc.deleteResTag(cts.Kit, group.vendor, hcReq); err != nil {
+				logs.Errorf("delete %s %s tag failed, err: %v, ids: %v, rid: %s", group.vendor, res.resType, err,
+					group.ids, cts.Kit.Rid)
+				return nil, err
+			}
+		}
+
+		return nil, nil
+	}
+}
+
+func (svc *resTagSvc) deleteResTag(kt *kit.Kit, vendor enumor.Vendor, req *hctag.BatchDeleteTagReq) error {
+	switch vendor {
+	case enumor.TCloud:
+		return svc.client.HCService().TCloud.ResourceTag.BatchDelete(kt, req)
+	case enumor.Aws:
+		return svc.client.HCService().Aws.ResourceTag.BatchDelete(kt, req)
+	case enumor.HuaWei:
+		return svc.client.HCService().HuaWei.ResourceTag.BatchDelete(kt, req)
+	case enumor.Azure:
+		return svc.client.HCService().Azure.ResourceTag.BatchDelete(kt, req)
+	case enumor.Gcp:
+		return svc.client.HCService().Gcp.ResourceTag.BatchDelete(kt, req)
+	default:
+		return fmt.Errorf("vendor: %s not support", vendor)
+	}
+}
+
+type accountResGroup struct {
+	vendor    enumor.Vendor
+	accountID string
+	ids       []string
+}
+
+// authResTagOperation 鉴权并记录审计，返回按云厂商和账号分组后的资源ID。
+func (svc *resTagSvc) authResTagOperation(cts *rest.Contexts, validHandler handler.ValidWithAuthHandler,
+	res tagResource, ids []string, action protoaudit.OperationAction) ([]*accountResGroup, error) {
+
+	basicInfoReq := dataproto.ListResourceBasicInfoReq{
+		ResourceType: res.resType,
+		IDs:          ids,
+		Fields:       types.CommonBasicInfoFields,
+	}
+	basicInfoMap, err := svc.client.DataService().Global.Cloud.ListResBasicInfo(cts.Kit, basicInfoReq)
+	if err != nil {
+		return nil, err
+	}
+
+	// validate biz and authorize
+	err = validHandler(cts, &handler.ValidWithAuthOption{Authorizer: svc.authorizer, ResType: res.iamType,
+		Action: meta.Update, BasicInfos: basicInfoMap})
+	if err != nil {
+		return nil, err
+	}
+
+	if err = svc.audit.ResBaseOperationAudit(cts.Kit, res.auditType, action, ids); err != nil {
+		logs.Errorf("create operation audit failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	groupMap := make(map[string]*accountResGroup)
+	for _, info := range basicInfoMap {
+		group, exist := groupMap[info.AccountID]
+		if !exist {
+			group = &accountResGroup{vendor: info.Vendor, accountID: info.AccountID, ids: make([]string, 0)}
+			groupMap[info.AccountID] = group
+		}
+		group.ids = append(group.ids, info.ID)
+	}
+
+	groups := make([]*accountResGroup, 0, len(groupMap))
+	for _, group := range groupMap {
+		sort.Strings(group.ids)
+		groups = append(groups, group)
+	}
+	sort.Slice(groups, func(i, j int) bool { return groups[i].accountID < groups[j].accountID })
+
+	return groups, nil
+}
@@ -20,6 +20,8 @@
 package securitygroup
 
 import (
+	"sort"
+
 	"hcm/cmd/cloud-server/logics/async"
 	actionsg "hcm/cmd/task-server/logics/action/security-group"
 	proto "hcm/pkg/api/cloud-server"
@@ -31,6 +33,7 @@ import (
 	"hcm/pkg/iam/meta"
 	"hcm/pkg/logs"
 	"hcm/pkg/rest"
+	"hcm/pkg/tools/converter"
 	"hcm/pkg/tools/counter"
 	"hcm/pkg/tools/hooks/handler"
 )
@@ -79,10 +82,14 @@ func (svc *securityGroupSvc) batchDeleteSecurityGroup(cts *rest.Contexts, validH
 		return nil, err
 	}
 
-	tasks := make([]ts.CustomFlowTask, 0, len(req.IDs))
+	// 按照 id 排序生成任务，保证相同请求生成的任务流内容一致，重复请求可以命中幂等键
+	ids := converter.MapKeyToStringSlice(basicInfoMap)
+	sort.Strings(ids)
 
+	tasks := make([]ts.CustomFlowTask, 0, len(ids))
 	nextID := counter.NewNumStringCounter(1, 10)
-	for _, info := range basicInfoMap {
+	for _, id := range ids {
+		info := basicInfoMap[id]
 		tasks = append(tasks, ts.CustomFlowTask{
 			ActionID:   action.ActIDType(nextID()),
 			ActionName: enumor.ActionDeleteSecurityGroup,
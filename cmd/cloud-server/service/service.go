@@ -47,6 +47,8 @@ import (
 	networkinterface "hcm/cmd/cloud-server/service/network-interface"
 	"hcm/cmd/cloud-server/service/recycle"
 	"hcm/cmd/cloud-server/service/region"
+	reschangehistory "hcm/cmd/cloud-server/service/res-change-history"
+	resourcetag "hcm/cmd/cloud-server/service/resource-tag"
 	resourcegroup "hcm/cmd/cloud-server/service/resource-group"
 	routetable "hcm/cmd/cloud-server/service/route-table"
 	securitygroup "hcm/cmd/cloud-server/service/security-group"
@@ -54,6 +56,7 @@ import (
 	"hcm/cmd/cloud-server/service/subnet"
 	"hcm/cmd/cloud-server/service/sync"
 	"hcm/cmd/cloud-server/service/sync/lock"
+	"hcm/cmd/cloud-server/service/sync/scheduler"
 	"hcm/cmd/cloud-server/service/user"
 	"hcm/cmd/cloud-server/service/vpc"
 	"hcm/cmd/cloud-server/service/zone"
@@ -155,8 +158,17 @@ func NewService(sd serviced.ServiceDiscover) (*Service, error) {
 	}
 
 	if cc.CloudServer().CloudResource.Sync.Enable {
-		interval := time.Duration(cc.CloudServer().CloudResource.Sync.SyncIntervalMin) * time.Minute
-		go sync.CloudResourceSync(interval, sd, apiClientSet)
+		if cc.CloudServer().CloudResource.Sync.Scheduler.Enable {
+			scheduler.InitScheduler(cc.CloudServer().CloudResource.Sync.Scheduler, sd, apiClientSet)
+			go scheduler.Manager.Run()
+		} else {
+			interval := time.Duration(cc.CloudServer().CloudResource.Sync.SyncIntervalMin) * time.Minute
+			go sync.CloudResourceSync(interval, sd, apiClientSet)
+		}
+	}
+
+	if cc.CloudServer().CloudResource.IncrementalSync.Enable {
+		go sync.IncrementalSync(cc.CloudServer().CloudResource.IncrementalSync, sd, apiClientSet)
 	}
 
 	if cc.CloudServer().BillConfig.Enable {
@@ -165,6 +177,8 @@ func NewService(sd serviced.ServiceDiscover) (*Service, error) {
 	}
 
 	recycle.RecycleTiming(apiClientSet, sd, cc.CloudServer().Recycle, esbClient)
+	reschangehistory.CleanTiming(apiClientSet, sd, cc.CloudServer().ResChangeHistory)
+	sync.CleanSyncHistoryTiming(apiClientSet, sd, cc.CloudServer().CloudResource.Sync.HistoryRetentionDays)
 
 	go appcvm.TimingHandleDeliverApplication(svr.client, 2*time.Second)
 
@@ -277,6 +291,8 @@ func (s *Service) apiSet(bkHcmUrl string) *restful.Container {
 	user.InitService(c)
 
 	approvalprocess.InitService(c)
+	reschangehistory.InitService(c)
+	resourcetag.InitResourceTagService(c)
 
 	return restful.NewContainer().Add(c.WebService)
 }
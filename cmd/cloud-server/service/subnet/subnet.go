@@ -47,8 +47,8 @@ import (
 	"hcm/pkg/runtime/filter"
 	"hcm/pkg/tools/assert"
 	"hcm/pkg/tools/converter"
+	"hcm/pkg/tools/counter"
 	"hcm/pkg/tools/hooks/handler"
-	"hcm/pkg/tools/uuid"
 )
 
 // InitSubnetService initialize the subnet service.
@@ -528,6 +528,7 @@ func (svc *subnetSvc) batchDeleteSubnet(cts *rest.Contexts, validHandler handler
 
 	// delete subnets
 	tasks := make([]ts.CustomFlowTask, 0, len(req.IDs))
+	nextID := counter.NewNumStringCounter(1, 10)
 	for _, id := range req.IDs {
 		basicInfo, exists := basicInfoMap[id]
 		if !exists {
@@ -535,7 +536,7 @@ func (svc *subnetSvc) batchDeleteSubnet(cts *rest.Contexts, validHandler handler
 		}
 
 		tasks = append(tasks, ts.CustomFlowTask{
-			ActionID:   action.ActIDType(uuid.UUID()),
+			ActionID:   action.ActIDType(nextID()),
 			ActionName: enumor.ActionDeleteSubnet,
 			Params: &actionsubnet.DeleteSubnetOption{
 				Vendor: basicInfo.Vendor,
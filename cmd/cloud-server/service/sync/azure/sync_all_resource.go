@@ -29,6 +29,7 @@ import (
 	"hcm/pkg/criteria/validator"
 	"hcm/pkg/kit"
 	"hcm/pkg/logs"
+	"hcm/pkg/tools/slice"
 )
 
 var syncConcurrencyCount = 10
@@ -38,6 +39,8 @@ type SyncAllResourceOption struct {
 	AccountID string `json:"account_id" validate:"required"`
 	// SyncPublicResource 是否同步公共资源
 	SyncPublicResource bool `json:"sync_public_resource" validate:"omitempty"`
+	// ResTypes 需要同步的资源类型，为空时同步全部资源
+	ResTypes []enumor.CloudResourceType `json:"res_types" validate:"omitempty"`
 }
 
 // Validate SyncAllResourceOption
@@ -45,6 +48,11 @@ func (opt *SyncAllResourceOption) Validate() error {
 	return validator.Validate.Struct(opt)
 }
 
+// needSync 判断是否需要同步该类资源
+func (opt *SyncAllResourceOption) needSync(resType enumor.CloudResourceType) bool {
+	return len(opt.ResTypes) == 0 || slice.IsItemInSlice(opt.ResTypes, resType)
+}
+
 // SyncAllResource sync resource.
 func SyncAllResource(kt *kit.Kit, cliSet *client.ClientSet,
 	opt *SyncAllResourceOption) (enumor.CloudResourceType, error) {
@@ -103,40 +111,58 @@ func SyncAllResource(kt *kit.Kit, cliSet *client.ClientSet,
 		Vendor:    string(enumor.Azure),
 	}
 
-	if hitErr = SyncDisk(kt, cliSet, opt.AccountID, resourceGroupNames, sd); hitErr != nil {
-		return enumor.DiskCloudResType, hitErr
+	if opt.needSync(enumor.DiskCloudResType) {
+		if hitErr = SyncDisk(kt, cliSet, opt.AccountID, resourceGroupNames, sd); hitErr != nil {
+			return enumor.DiskCloudResType, hitErr
+		}
 	}
 
-	if hitErr = SyncSG(kt, cliSet, opt.AccountID, resourceGroupNames, sd); hitErr != nil {
-		return enumor.SecurityGroupCloudResType, hitErr
+	if opt.needSync(enumor.SecurityGroupCloudResType) {
+		if hitErr = SyncSG(kt, cliSet, opt.AccountID, resourceGroupNames, sd); hitErr != nil {
+			return enumor.SecurityGroupCloudResType, hitErr
+		}
 	}
 
-	if hitErr = SyncVpc(kt, cliSet, opt.AccountID, resourceGroupNames, sd); hitErr != nil {
-		return enumor.VpcCloudResType, hitErr
+	if opt.needSync(enumor.VpcCloudResType) {
+		if hitErr = SyncVpc(kt, cliSet, opt.AccountID, resourceGroupNames, sd); hitErr != nil {
+			return enumor.VpcCloudResType, hitErr
+		}
 	}
 
-	if hitErr = SyncSubnet(kt, cliSet, opt.AccountID, resourceGroupNames, sd); hitErr != nil {
-		return enumor.SubnetCloudResType, hitErr
+	if opt.needSync(enumor.SubnetCloudResType) {
+		if hitErr = SyncSubnet(kt, cliSet, opt.AccountID, resourceGroupNames, sd); hitErr != nil {
+			return enumor.SubnetCloudResType, hitErr
+		}
 	}
 
-	if hitErr = SyncEip(kt, cliSet, opt.AccountID, resourceGroupNames, sd); hitErr != nil {
-		return enumor.EipCloudResType, hitErr
+	if opt.needSync(enumor.EipCloudResType) {
+		if hitErr = SyncEip(kt, cliSet, opt.AccountID, resourceGroupNames, sd); hitErr != nil {
+			return enumor.EipCloudResType, hitErr
+		}
 	}
 
-	if hitErr = SyncCvm(kt, cliSet, opt.AccountID, resourceGroupNames, sd); hitErr != nil {
-		return enumor.CvmCloudResType, hitErr
+	if opt.needSync(enumor.CvmCloudResType) {
+		if hitErr = SyncCvm(kt, cliSet, opt.AccountID, resourceGroupNames, sd); hitErr != nil {
+			return enumor.CvmCloudResType, hitErr
+		}
 	}
 
-	if hitErr = SyncRouteTable(kt, cliSet, opt.AccountID, resourceGroupNames, sd); hitErr != nil {
-		return enumor.RouteTableCloudResType, hitErr
+	if opt.needSync(enumor.RouteTableCloudResType) {
+		if hitErr = SyncRouteTable(kt, cliSet, opt.AccountID, resourceGroupNames, sd); hitErr != nil {
+			return enumor.RouteTableCloudResType, hitErr
+		}
 	}
 
-	if hitErr = SyncNetworkInterface(kt, cliSet, opt.AccountID, resourceGroupNames, sd); hitErr != nil {
-		return enumor.NetworkInterfaceCloudResType, hitErr
+	if opt.needSync(enumor.NetworkInterfaceCloudResType) {
+		if hitErr = SyncNetworkInterface(kt, cliSet, opt.AccountID, resourceGroupNames, sd); hitErr != nil {
+			return enumor.NetworkInterfaceCloudResType, hitErr
+		}
 	}
 
-	if hitErr = SyncSubAccount(kt, cliSet, opt.AccountID, sd); hitErr != nil {
-		return enumor.SubAccountCloudResType, hitErr
+	if opt.needSync(enumor.SubAccountCloudResType) {
+		if hitErr = SyncSubAccount(kt, cliSet, opt.AccountID, sd); hitErr != nil {
+			return enumor.SubAccountCloudResType, hitErr
+		}
 	}
 
 	return "", nil
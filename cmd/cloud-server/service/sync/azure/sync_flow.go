@@ -0,0 +1,115 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+package azure
+
+import (
+	"fmt"
+
+	"hcm/cmd/cloud-server/service/sync/detail"
+	"hcm/pkg/client"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/kit"
+)
+
+// PrepareSyncFlow 以任务流方式同步账号前的准备，同步地域、资源组、公共资源并返回各类资源需要同步的资源组，
+// azure 资源按资源组同步，返回结果中的地域即为资源组名称。
+func PrepareSyncFlow(kt *kit.Kit, cliSet *client.ClientSet, opt *SyncAllResourceOption) (
+	map[enumor.CloudResourceType][]string, error) {
+
+	if err := opt.Validate(); err != nil {
+		return nil, err
+	}
+
+	if opt.SyncPublicResource {
+		if err := SyncRegion(kt, cliSet.HCService(), opt.AccountID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := SyncResourceGroup(kt, cliSet.HCService(), opt.AccountID); err != nil {
+		return nil, err
+	}
+
+	resourceGroupNames, err := ListResourceGroup(kt, cliSet.DataService(), opt.AccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	if opt.SyncPublicResource {
+		syncOpt := &SyncPublicResourceOption{
+			AccountID:          opt.AccountID,
+			ResourceGroupNames: resourceGroupNames,
+		}
+		if err = SyncPublicResource(kt, cliSet, syncOpt); err != nil {
+			return nil, err
+		}
+	}
+
+	scopes := make(map[enumor.CloudResourceType][]string)
+	groupResTypes := []enumor.CloudResourceType{enumor.DiskCloudResType, enumor.SecurityGroupCloudResType,
+		enumor.VpcCloudResType, enumor.SubnetCloudResType, enumor.EipCloudResType, enumor.CvmCloudResType,
+		enumor.RouteTableCloudResType, enumor.NetworkInterfaceCloudResType}
+	for _, resType := range groupResTypes {
+		if opt.needSync(resType) {
+			scopes[resType] = resourceGroupNames
+		}
+	}
+
+	if opt.needSync(enumor.SubAccountCloudResType) {
+		scopes[enumor.SubAccountCloudResType] = nil
+	}
+
+	return scopes, nil
+}
+
+// SyncResource 同步账号下指定资源组的一类资源，供账号同步任务流中的单个任务执行。
+func SyncResource(kt *kit.Kit, cliSet *client.ClientSet, accountID string, resType enumor.CloudResourceType,
+	resourceGroupName string) error {
+
+	sd := &detail.SyncDetail{
+		Kt:        kt,
+		DataCli:   cliSet.DataService(),
+		AccountID: accountID,
+		Vendor:    string(enumor.Azure),
+	}
+	groups := []string{resourceGroupName}
+
+	switch resType {
+	case enumor.DiskCloudResType:
+		return SyncDisk(kt, cliSet, accountID, groups, sd)
+	case enumor.SecurityGroupCloudResType:
+		return SyncSG(kt, cliSet, accountID, groups, sd)
+	case enumor.VpcCloudResType:
+		return SyncVpc(kt, cliSet, accountID, groups, sd)
+	case enumor.SubnetCloudResType:
+		return SyncSubnet(kt, cliSet, accountID, groups, sd)
+	case enumor.EipCloudResType:
+		return SyncEip(kt, cliSet, accountID, groups, sd)
+	case enumor.CvmCloudResType:
+		return SyncCvm(kt, cliSet, accountID, groups, sd)
+	case enumor.RouteTableCloudResType:
+		return SyncRouteTable(kt, cliSet, accountID, groups, sd)
+	case enumor.NetworkInterfaceCloudResType:
+		return SyncNetworkInterface(kt, cliSet, accountID, groups, sd)
+	case enumor.SubAccountCloudResType:
+		return SyncSubAccount(kt, cliSet, accountID, sd)
+	default:
+		return fmt.Errorf("azure not support sync %s", resType)
+	}
+}
@@ -0,0 +1,67 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package sync
+
+import (
+	"time"
+
+	"hcm/pkg/api/core"
+	dssync "hcm/pkg/api/data-service/cloud/sync"
+	"hcm/pkg/client"
+	"hcm/pkg/logs"
+	"hcm/pkg/serviced"
+	"hcm/pkg/tools/times"
+)
+
+// CleanSyncHistoryTiming timing clean expired account sync history.
+func CleanSyncHistoryTiming(c *client.ClientSet, state serviced.State, retentionDays uint) {
+	if retentionDays == 0 {
+		logs.Infof("account sync history retention days is not set, skip clean")
+		return
+	}
+
+	go cleanSyncHistoryTiming(c, state, retentionDays)
+}
+
+func cleanSyncHistoryTiming(c *client.ClientSet, state serviced.State, retentionDays uint) {
+	for {
+		kt := core.NewBackendKit()
+
+		if !state.IsMaster() {
+			logs.Infof("clean account sync history, but is not master, skip")
+			time.Sleep(time.Minute)
+			continue
+		}
+
+		before := time.Now().AddDate(0, 0, -int(retentionDays))
+		req := &dssync.HistoryDeleteExpiredReq{Before: times.ConvStdTimeFormat(before)}
+		result, err := c.DataService().Global.AccountSyncHistory.DeleteExpired(kt, req)
+		if err != nil {
+			logs.Errorf("clean expired account sync history failed, err: %v, before: %s, rid: %s", err,
+				req.Before, kt.Rid)
+			time.Sleep(time.Minute * 10)
+			continue
+		}
+
+		logs.Infof("clean expired account sync history success, before: %s, count: %d, rid: %s", req.Before,
+			result.DeletedCount, kt.Rid)
+		time.Sleep(time.Hour)
+	}
+}
@@ -0,0 +1,90 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+package huawei
+
+import (
+	"fmt"
+
+	"hcm/cmd/cloud-server/service/sync/detail"
+	"hcm/pkg/client"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/kit"
+)
+
+// PrepareSyncFlow 以任务流方式同步账号前的准备，同步公共资源并返回需要同步的资源。
+// 华为云各类资源按服务查询支持的地域并在资源同步内部遍历，因此每类资源只有一个不区分地域的任务。
+func PrepareSyncFlow(kt *kit.Kit, cliSet *client.ClientSet, opt *SyncAllResourceOption) (
+	map[enumor.CloudResourceType][]string, error) {
+
+	if err := opt.Validate(); err != nil {
+		return nil, err
+	}
+
+	if opt.SyncPublicResource {
+		syncOpt := &SyncPublicResourceOption{AccountID: opt.AccountID}
+		if err := SyncPublicResource(kt, cliSet, syncOpt); err != nil {
+			return nil, err
+		}
+	}
+
+	scopes := make(map[enumor.CloudResourceType][]string)
+	resTypes := []enumor.CloudResourceType{enumor.DiskCloudResType, enumor.VpcCloudResType,
+		enumor.SubnetCloudResType, enumor.EipCloudResType, enumor.SecurityGroupCloudResType, enumor.CvmCloudResType,
+		enumor.RouteTableCloudResType, enumor.SubAccountCloudResType}
+	for _, resType := range resTypes {
+		if opt.needSync(resType) {
+			scopes[resType] = nil
+		}
+	}
+
+	return scopes, nil
+}
+
+// SyncResource 同步账号下的一类资源，供账号同步任务流中的单个任务执行，华为云资源不区分地域，忽略 region。
+func SyncResource(kt *kit.Kit, cliSet *client.ClientSet, accountID string, resType enumor.CloudResourceType,
+	_ string) error {
+
+	sd := &detail.SyncDetail{
+		Kt:        kt,
+		DataCli:   cliSet.DataService(),
+		AccountID: accountID,
+		Vendor:    string(enumor.HuaWei),
+	}
+
+	switch resType {
+	case enumor.DiskCloudResType:
+		return SyncDisk(kt, cliSet, accountID, sd)
+	case enumor.VpcCloudResType:
+		return SyncVpc(kt, cliSet, accountID, sd)
+	case enumor.SubnetCloudResType:
+		return SyncSubnet(kt, cliSet, accountID, sd)
+	case enumor.EipCloudResType:
+		return SyncEip(kt, cliSet, accountID, sd)
+	case enumor.SecurityGroupCloudResType:
+		return SyncSG(kt, cliSet, accountID, sd)
+	case enumor.CvmCloudResType:
+		return SyncCvm(kt, cliSet, accountID, sd)
+	case enumor.RouteTableCloudResType:
+		return SyncRouteTable(kt, cliSet, accountID, sd)
+	case enumor.SubAccountCloudResType:
+		return SyncSubAccount(kt, cliSet, accountID, sd)
+	default:
+		return fmt.Errorf("huawei not support sync %s", resType)
+	}
+}
@@ -0,0 +1,274 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package sync
+
+import (
+	"fmt"
+	"time"
+
+	"hcm/cmd/cloud-server/service/sync/aws"
+	"hcm/cmd/cloud-server/service/sync/huawei"
+	"hcm/cmd/cloud-server/service/sync/tcloud"
+	adhuawei "hcm/pkg/adaptor/huawei"
+	"hcm/pkg/api/core"
+	protocloud "hcm/pkg/api/data-service/cloud"
+	dssync "hcm/pkg/api/data-service/cloud/sync"
+	hcsync "hcm/pkg/api/hc-service/sync"
+	"hcm/pkg/cc"
+	"hcm/pkg/client"
+	dataservice "hcm/pkg/client/data-service"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/runtime/filter"
+	"hcm/pkg/serviced"
+	"hcm/pkg/tools/times"
+)
+
+// auditEventMaxLookback 各云厂商审计事件查询接口支持查询的最长历史时间，超过后的事件无法查询。
+var auditEventMaxLookback = map[enumor.Vendor]time.Duration{
+	// 云审计 LookUpEvents 仅支持查询近90天的事件
+	enumor.TCloud: 90 * 24 * time.Hour,
+	// CloudTrail LookupEvents 仅支持查询近90天的管理事件
+	enumor.Aws: 90 * 24 * time.Hour,
+	// 云审计服务 ListTraces 仅支持查询近7天的事件
+	enumor.HuaWei: 7 * 24 * time.Hour,
+	// 管理活动审计日志保留400天
+	enumor.Gcp: 400 * 24 * time.Hour,
+	// 活动日志仅保留近90天
+	enumor.Azure: 90 * 24 * time.Hour,
+}
+
+// IncrementalSync 定时基于云上审计事件增量同步云资源。账号的同步检查点保存在账号同步详情中，服务重启或主节点切换后
+// 从检查点继续同步；检查点早于审计事件可查询的最长历史时间时，从可查询的最早时间开始，期间遗漏的变更由定时全量同步兜底。
+func IncrementalSync(conf cc.CloudResourceIncrementalSync, sd serviced.ServiceDiscover, cliSet *client.ClientSet) {
+	logs.Infof("cloud resource incremental sync enable, intervalMin: %d, delayMin: %d", conf.IntervalMin,
+		conf.DelayMin)
+
+	interval := time.Duration(conf.IntervalMin) * time.Minute
+	delay := time.Duration(conf.DelayMin) * time.Minute
+	for {
+		time.Sleep(interval)
+
+		if !sd.IsMaster() {
+			continue
+		}
+
+		kt := core.NewBackendKit()
+		now := time.Now()
+		end := now.Add(-delay).Truncate(time.Second)
+		for _, vendor := range []enumor.Vendor{enumor.TCloud, enumor.Aws, enumor.HuaWei, enumor.Azure, enumor.Gcp} {
+			incrementalSyncVendor(kt, cliSet, vendor, end.Add(-interval), end, now)
+		}
+	}
+}
+
+// incrementalSyncVendor 增量同步云厂商下所有资源账号，首次同步的账号从 defaultStart 开始，同步成功后推进检查点。
+func incrementalSyncVendor(kt *kit.Kit, cliSet *client.ClientSet, vendor enumor.Vendor, defaultStart,
+	end, now time.Time) {
+
+	checkpoints, err := listSyncCheckpoint(kt, cliSet.DataService(), vendor)
+	if err != nil {
+		logs.Errorf("list %s incremental sync checkpoint failed, err: %v, rid: %s", vendor, err, kt.Rid)
+		return
+	}
+
+	listReq := &protocloud.AccountListReq{
+		Filter: &filter.Expression{Op: filter.And, Rules: []filter.RuleFactory{
+			&filter.AtomRule{Field: "vendor", Op: filter.Equal.Factory(), Value: vendor},
+			&filter.AtomRule{Field: "type", Op: filter.Equal.Factory(), Value: enumor.ResourceAccount}}},
+		Page: &core.BasePage{Start: 0, Limit: core.DefaultMaxPageLimit},
+	}
+	for {
+		accounts, err := listAccountWithRetry(kt, cliSet.DataService(), listReq)
+		if err != nil {
+			logs.Errorf("list account failed, err: %v, vendor: %s, rid: %s", err, vendor, kt.Rid)
+			return
+		}
+
+		for _, one := range accounts {
+			start := defaultStart
+			checkpoint := checkpoints[one.ID]
+			if !checkpoint.time.IsZero() {
+				start = checkpoint.time
+			}
+
+			if capped := capAuditEventStart(vendor, start, now); capped.After(start) {
+				logs.Warnf("%s account incremental sync checkpoint %v exceeds audit event max lookback, start from "+
+					"%v, account: %s, rid: %s", vendor, start, capped, one.ID, kt.Rid)
+				start = capped
+			}
+			if !end.After(start) {
+				continue
+			}
+
+			subKt := kt.NewSubKit()
+			result, err := syncAccountByAuditEvent(subKt, cliSet, vendor, one.ID, start, end)
+			if err != nil {
+				logs.Errorf("%s account incremental sync failed, err: %v, account: %s, start: %v, end: %v, rid: %s",
+					vendor, err, one.ID, start, end, subKt.Rid)
+				continue
+			}
+
+			// 检查点保存失败时下次从原检查点重新同步，定向同步可重复执行
+			if err = saveSyncCheckpoint(subKt, cliSet.DataService(), vendor, one.ID, checkpoint.id, end); err != nil {
+				logs.Errorf("save %s account incremental sync checkpoint failed, err: %v, account: %s, rid: %s",
+					vendor, err, one.ID, subKt.Rid)
+			}
+			logs.V(3).Infof("%s account incremental sync success, account: %s, start: %v, end: %v, result: %+v, "+
+				"rid: %s", vendor, one.ID, start, end, result, subKt.Rid)
+		}
+
+		if len(accounts) < int(core.DefaultMaxPageLimit) {
+			return
+		}
+		listReq.Page.Start += uint32(core.DefaultMaxPageLimit)
+	}
+}
+
+// capAuditEventStart 将增量同步的开始时间限制在审计事件可查询的最长历史时间内。
+func capAuditEventStart(vendor enumor.Vendor, start, now time.Time) time.Time {
+	lookback, exists := auditEventMaxLookback[vendor]
+	if !exists {
+		return start
+	}
+
+	earliest := now.Add(-lookback).Truncate(time.Second)
+	// 查询接口按请求时间计算可查询的范围，预留一分钟避免请求到达云上时开始时间已超出范围
+	earliest = earliest.Add(time.Minute)
+	if start.Before(earliest) {
+		return earliest
+	}
+
+	return start
+}
+
+// syncCheckpoint 账号增量同步检查点，id 为记录检查点的账号同步详情ID。
+type syncCheckpoint struct {
+	id   string
+	time time.Time
+}
+
+// listSyncCheckpoint 查询云厂商下所有账号的增量同步检查点，key为账号ID。
+func listSyncCheckpoint(kt *kit.Kit, dataCli *dataservice.Client, vendor enumor.Vendor) (
+	map[string]syncCheckpoint, error) {
+
+	listReq := &core.ListReq{
+		Filter: &filter.Expression{Op: filter.And, Rules: []filter.RuleFactory{
+			&filter.AtomRule{Field: "vendor", Op: filter.Equal.Factory(), Value: vendor},
+			&filter.AtomRule{Field: "res_name", Op: filter.Equal.Factory(), Value: enumor.IncrementalSyncCheckpoint}}},
+		Page: &core.BasePage{Start: 0, Limit: core.DefaultMaxPageLimit},
+	}
+
+	checkpoints := make(map[string]syncCheckpoint)
+	for {
+		result, err := dataCli.Global.AccountSyncDetail.List(kt, listReq)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, one := range result.Details {
+			checkpoint := syncCheckpoint{id: one.ID}
+			// 检查点时间无法解析时按首次同步处理，同步成功后覆盖
+			if t, err := time.Parse(constant.TimeStdFormat, one.ResEndTime); err == nil {
+				checkpoint.time = t
+			}
+			checkpoints[one.AccountID] = checkpoint
+		}
+
+		if len(result.Details) < int(core.DefaultMaxPageLimit) {
+			break
+		}
+		listReq.Page.Start += uint32(core.DefaultMaxPageLimit)
+	}
+
+	return checkpoints, nil
+}
+
+// saveSyncCheckpoint 保存账号增量同步检查点，id 为空时新增检查点记录，否则更新该记录。
+func saveSyncCheckpoint(kt *kit.Kit, dataCli *dataservice.Client, vendor enumor.Vendor, accountID, id string,
+	checkpoint time.Time) error {
+
+	endTime := times.ConvStdTimeFormat(checkpoint)
+	if len(id) == 0 {
+		createReq := &dssync.CreateReq{
+			Items: []dssync.CreateField{{
+				Vendor:     vendor,
+				AccountID:  accountID,
+				ResName:    enumor.IncrementalSyncCheckpoint,
+				ResStatus:  string(enumor.SyncSuccess),
+				ResEndTime: endTime,
+			}},
+		}
+		_, err := dataCli.Global.AccountSyncDetail.BatchCreate(kt, createReq)
+		return err
+	}
+
+	updateReq := &dssync.UpdateReq{
+		Items: []dssync.UpdateField{{
+			ID:         id,
+			ResStatus:  string(enumor.SyncSuccess),
+			ResEndTime: endTime,
+		}},
+	}
+	return dataCli.Global.AccountSyncDetail.BatchUpdate(kt, updateReq)
+}
+
+// syncAccountByAuditEvent 请求hc-service拉取账号在时间窗口内的审计事件并定向同步。
+func syncAccountByAuditEvent(kt *kit.Kit, cliSet *client.ClientSet, vendor enumor.Vendor, accountID string,
+	start, end time.Time) (*hcsync.AuditEventSyncResult, error) {
+
+	req := &hcsync.AuditEventSyncReq{
+		AccountID: accountID,
+		StartTime: start.Format(time.RFC3339),
+		EndTime:   end.Format(time.RFC3339),
+	}
+
+	var err error
+	switch vendor {
+	case enumor.TCloud:
+		if req.Regions, err = tcloud.ListRegion(kt, cliSet.DataService()); err != nil {
+			return nil, err
+		}
+		return cliSet.HCService().TCloud.AuditEvent.SyncByAuditEvent(kt, req)
+
+	case enumor.Aws:
+		if req.Regions, err = aws.ListRegion(kt, cliSet.DataService(), accountID); err != nil {
+			return nil, err
+		}
+		return cliSet.HCService().Aws.AuditEvent.SyncByAuditEvent(kt, req)
+
+	case enumor.HuaWei:
+		if req.Regions, err = huawei.ListRegionByService(kt, cliSet.DataService(), adhuawei.Ecs); err != nil {
+			return nil, err
+		}
+		return cliSet.HCService().HuaWei.AuditEvent.SyncByAuditEvent(kt, req)
+
+	case enumor.Gcp:
+		return cliSet.HCService().Gcp.AuditEvent.SyncByAuditEvent(kt, req)
+
+	case enumor.Azure:
+		return cliSet.HCService().Azure.AuditEvent.SyncByAuditEvent(kt, req)
+
+	default:
+		return nil, fmt.Errorf("unsupported vendor: %s", vendor)
+	}
+}
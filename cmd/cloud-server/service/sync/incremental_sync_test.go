@@ -0,0 +1,55 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package sync
+
+import (
+	"testing"
+	"time"
+
+	"hcm/pkg/criteria/enumor"
+)
+
+func TestCapAuditEventStart(t *testing.T) {
+	now := time.Date(2023, 12, 20, 10, 0, 0, 0, time.Local)
+	huaweiEarliest := now.Add(-7*24*time.Hour + time.Minute)
+
+	cases := []struct {
+		name   string
+		vendor enumor.Vendor
+		start  time.Time
+		expect time.Time
+	}{
+		{name: "in lookback", vendor: enumor.HuaWei, start: now.Add(-time.Hour), expect: now.Add(-time.Hour)},
+		{name: "exceed lookback", vendor: enumor.HuaWei, start: now.Add(-30 * 24 * time.Hour),
+			expect: huaweiEarliest},
+		{name: "at lookback boundary", vendor: enumor.HuaWei, start: now.Add(-7 * 24 * time.Hour),
+			expect: huaweiEarliest},
+		{name: "longer lookback", vendor: enumor.Aws, start: now.Add(-30 * 24 * time.Hour),
+			expect: now.Add(-30 * 24 * time.Hour)},
+		{name: "unknown vendor", vendor: enumor.Vendor("unknown"), start: now.Add(-365 * 24 * time.Hour),
+			expect: now.Add(-365 * 24 * time.Hour)},
+	}
+
+	for _, c := range cases {
+		if got := capAuditEventStart(c.vendor, c.start, now); !got.Equal(c.expect) {
+			t.Errorf("%s: expect start %v, but got %v", c.name, c.expect, got)
+		}
+	}
+}
@@ -22,10 +22,20 @@ package lock
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
 
+	proto "hcm/pkg/api/cloud-server/account"
 	"hcm/pkg/cc"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/runtime/fencing"
+	"hcm/pkg/tools/slice"
 
 	etcd3 "go.etcd.io/etcd/client/v3"
 )
@@ -33,6 +43,12 @@ import (
 // ErrLockFailed lock grabbing failed err
 var ErrLockFailed = errors.New("lock grabbing failed")
 
+// ErrLockLost 同步过程中锁租约丢失（续约失败、超时或被强制释放），同步已被终止。
+var ErrLockLost = errors.New("account sync lock lost")
+
+// ErrRateLimited 请求频率超过限制
+var ErrRateLimited = errors.New("rate limited")
+
 // Manager lock manager.
 var Manager *EtcdMutex
 
@@ -44,10 +60,16 @@ func InitManger(cfg etcd3.Config, ttl int64) error {
 		return err
 	}
 
+	holder, err := os.Hostname()
+	if err != nil {
+		return err
+	}
+
 	ctx, cancelFunc := context.WithCancel(context.Background())
 
 	Manager = &EtcdMutex{
 		ttl:    ttl,
+		holder: holder,
 		cli:    client,
 		ctx:    ctx,
 		cancel: cancelFunc,
@@ -59,9 +81,10 @@ func InitManger(cfg etcd3.Config, ttl int64) error {
 
 // EtcdMutex ...
 type EtcdMutex struct {
-	ttl   int64
-	cli   *etcd3.Client
-	lease etcd3.Lease
+	ttl    int64
+	holder string
+	cli    *etcd3.Client
+	lease  etcd3.Lease
 
 	ctx    context.Context
 	cancel context.CancelFunc
@@ -72,30 +95,152 @@ func (mux *EtcdMutex) Close() {
 	mux.cancel()
 }
 
-// TryLock try lock key, failed return error.
-func (mux *EtcdMutex) TryLock(key string) (etcd3.LeaseID, error) {
+// lockValue 锁持有者信息，作为锁的值保存在etcd中。
+type lockValue struct {
+	Holder    string `json:"holder"`
+	StartTime string `json:"start_time"`
+}
+
+// TryLock try lock account sync lock, failed return error. 加锁成功后锁租约会自动续约，直到调用 UnLock 或租约丢失。
+func (mux *EtcdMutex) TryLock(accountID string) (*Lock, error) {
 	grant, err := mux.lease.Grant(mux.ctx, mux.ttl)
 	if err != nil {
-		return 0, err
+		return nil, err
+	}
+
+	value, err := json.Marshal(lockValue{Holder: mux.holder, StartTime: time.Now().Format(time.RFC3339)})
+	if err != nil {
+		return nil, err
 	}
 
+	key := Key(accountID)
 	txn := etcd3.NewKV(mux.cli).Txn(mux.ctx).If(etcd3.Compare(etcd3.CreateRevision(key), "=", 0)).
-		Then(etcd3.OpPut(key, "", etcd3.WithLease(grant.ID))).Else()
+		Then(etcd3.OpPut(key, string(value), etcd3.WithLease(grant.ID))).Else()
 	txnResp, err := txn.Commit()
 	if err != nil {
-		return 0, err
+		mux.revoke(grant.ID)
+		return nil, err
 	}
 
 	if !txnResp.Succeeded {
-		return 0, ErrLockFailed
+		mux.revoke(grant.ID)
+		return nil, ErrLockFailed
 	}
 
-	return grant.ID, nil
+	ctx, cancel := context.WithCancel(mux.ctx)
+	keepAlive, err := mux.lease.KeepAlive(ctx, grant.ID)
+	if err != nil {
+		cancel()
+		mux.revoke(grant.ID)
+		return nil, err
+	}
+
+	lock := &Lock{
+		mux:     mux,
+		leaseID: grant.ID,
+		token:   fencing.Token{AccountID: accountID, Revision: txnResp.Header.Revision},
+		ctx:     ctx,
+		cancel:  cancel,
+	}
+	go lock.keepAlive(keepAlive)
+
+	return lock, nil
 }
 
-// UnLock ...
-func (mux *EtcdMutex) UnLock(leaseID etcd3.LeaseID) error {
+func (mux *EtcdMutex) revoke(leaseID etcd3.LeaseID) {
 	if _, err := mux.lease.Revoke(mux.ctx, leaseID); err != nil {
+		logs.Errorf("revoke account sync lock lease failed, err: %v, leaseID: %d", err, leaseID)
+	}
+}
+
+// Lock 账号同步锁，持有期间自动续约租约，租约丢失后取消锁的上下文，使用该上下文的同步随之终止。
+type Lock struct {
+	mux     *EtcdMutex
+	leaseID etcd3.LeaseID
+	token   fencing.Token
+
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	lock     sync.Mutex
+	released bool
+	lost     bool
+}
+
+// keepAlive 消费续约响应，续约通道关闭且锁未被主动释放时，说明锁租约已丢失。
+func (l *Lock) keepAlive(ch <-chan *etcd3.LeaseKeepAliveResponse) {
+	for range ch {
+	}
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	if l.released {
+		return
+	}
+
+	l.lost = true
+	l.cancel()
+	logs.Errorf("account sync lock lost, account: %s, leaseID: %d, token: %s", l.token.AccountID, l.leaseID,
+		l.token.String())
+}
+
+// Token 返回锁的 fencing token。
+func (l *Lock) Token() fencing.Token {
+	return l.token
+}
+
+// Err 锁租约丢失时返回 ErrLockLost。
+func (l *Lock) Err() error {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	if l.lost {
+		return ErrLockLost
+	}
+
+	return nil
+}
+
+// Kit 返回持有锁的子kit，锁租约丢失时其上下文会被取消，下游请求会携带锁的 fencing token。
+func (l *Lock) Kit(kt *kit.Kit) *kit.Kit {
+	ctx, cancel := context.WithCancel(kt.Ctx)
+	go func() {
+		defer cancel()
+		select {
+		case <-l.ctx.Done():
+		case <-ctx.Done():
+		}
+	}()
+
+	newKit := *kt
+	newKit.Ctx = ctx
+	newKit.SyncFencingToken = l.token.String()
+	return &newKit
+}
+
+// UnLock 释放锁，停止续约并撤销租约，锁租约已丢失时直接返回。
+func (l *Lock) UnLock() error {
+	l.lock.Lock()
+	if l.released {
+		l.lock.Unlock()
+		return nil
+	}
+	l.released = true
+	lost := l.lost
+	l.lock.Unlock()
+
+	l.cancel()
+	if lost {
+		return nil
+	}
+
+	if _, err := l.mux.lease.Revoke(l.mux.ctx, l.leaseID); err != nil {
+		// 锁已经超时释放了
+		if strings.Contains(err.Error(), "requested lease not found") {
+			return nil
+		}
+
 		return err
 	}
 
@@ -104,5 +249,98 @@ func (mux *EtcdMutex) UnLock(leaseID etcd3.LeaseID) error {
 
 // Key return key.
 func Key(accountID string) string {
-	return fmt.Sprintf("/hcm/lock/%s/sync/%s", cc.CloudServerName, accountID)
+	return fencing.SyncLockKey(accountID)
+}
+
+// List 查询账号的同步锁，accountIDs 为空时查询全部账号的同步锁。
+func (mux *EtcdMutex) List(ctx context.Context, accountIDs []string) ([]proto.SyncLock, error) {
+	resp, err := mux.cli.Get(ctx, fencing.SyncLockPrefix(), etcd3.WithPrefix())
+	if err != nil {
+		return nil, err
+	}
+
+	locks := make([]proto.SyncLock, 0, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		accountID := strings.TrimPrefix(string(kv.Key), fencing.SyncLockPrefix())
+		if len(accountIDs) != 0 && !slice.IsItemInSlice(accountIDs, accountID) {
+			continue
+		}
+
+		value := new(lockValue)
+		if err = json.Unmarshal(kv.Value, value); err != nil {
+			// 旧版本的锁没有记录持有者信息
+			value = new(lockValue)
+		}
+
+		one := proto.SyncLock{
+			AccountID:    accountID,
+			Holder:       value.Holder,
+			StartTime:    value.StartTime,
+			FencingToken: kv.CreateRevision,
+		}
+		if kv.Lease != 0 {
+			ttl, err := mux.lease.TimeToLive(ctx, etcd3.LeaseID(kv.Lease))
+			if err != nil {
+				return nil, err
+			}
+			one.TTL = ttl.TTL
+		}
+
+		locks = append(locks, one)
+	}
+
+	return locks, nil
+}
+
+// ForceUnLock 强制释放账号同步锁，撤销锁租约后持有者续约失败，正在进行的同步会被终止。
+func (mux *EtcdMutex) ForceUnLock(ctx context.Context, accountID string) error {
+	key := Key(accountID)
+	resp, err := mux.cli.Get(ctx, key)
+	if err != nil {
+		return err
+	}
+
+	if len(resp.Kvs) == 0 {
+		return nil
+	}
+
+	kv := resp.Kvs[0]
+	if kv.Lease == 0 {
+		_, err = mux.cli.Delete(ctx, key)
+		return err
+	}
+
+	if _, err = mux.lease.Revoke(ctx, etcd3.LeaseID(kv.Lease)); err != nil {
+		if strings.Contains(err.Error(), "requested lease not found") {
+			return nil
+		}
+		return err
+	}
+
+	return nil
+}
+
+// TryRateLimit 账号的定向同步频率限制，ttl 秒内同一账号只允许发起一次定向同步，超过限制时返回 ErrRateLimited。
+// 限制与账号全量同步锁相互独立，限制的key随租约过期自动删除，不会主动释放。
+func (mux *EtcdMutex) TryRateLimit(accountID string, ttl int64) error {
+	grant, err := mux.lease.Grant(mux.ctx, ttl)
+	if err != nil {
+		return err
+	}
+
+	key := fmt.Sprintf("/hcm/lock/%s/selective_sync/%s", cc.CloudServerName, accountID)
+	txn := etcd3.NewKV(mux.cli).Txn(mux.ctx).If(etcd3.Compare(etcd3.CreateRevision(key), "=", 0)).
+		Then(etcd3.OpPut(key, mux.holder, etcd3.WithLease(grant.ID))).Else()
+	txnResp, err := txn.Commit()
+	if err != nil {
+		mux.revoke(grant.ID)
+		return err
+	}
+
+	if !txnResp.Succeeded {
+		mux.revoke(grant.ID)
+		return ErrRateLimited
+	}
+
+	return nil
 }
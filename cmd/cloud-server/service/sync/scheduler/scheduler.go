@@ -0,0 +1,571 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+// Package scheduler 云资源同步调度器，按账号维度调度同步任务，支持账号级别的同步间隔和优先级、
+// 云厂商维度的并发控制、同步失败的指数退避以及资源类型级别的同步间隔。
+package scheduler
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"hcm/cmd/cloud-server/logics/assign"
+	"hcm/cmd/cloud-server/service/sync/aws"
+	"hcm/cmd/cloud-server/service/sync/azure"
+	"hcm/cmd/cloud-server/service/sync/detail"
+	"hcm/cmd/cloud-server/service/sync/gcp"
+	"hcm/cmd/cloud-server/service/sync/huawei"
+	"hcm/cmd/cloud-server/service/sync/lock"
+	syncflow "hcm/cmd/cloud-server/service/sync/sync-flow"
+	"hcm/cmd/cloud-server/service/sync/tcloud"
+	"hcm/pkg/api/cloud-server/account"
+	"hcm/pkg/api/core"
+	protocloud "hcm/pkg/api/data-service/cloud"
+	"hcm/pkg/cc"
+	"hcm/pkg/client"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/runtime/filter"
+	"hcm/pkg/serviced"
+	"hcm/pkg/tools/times"
+)
+
+// Manager sync scheduler, it's nil when scheduler is not enabled.
+var Manager *Scheduler
+
+// tickInterval 调度器检查待同步账号的间隔
+const tickInterval = time.Minute
+
+// publicResType 公共资源（地域、可用区、镜像）的调度类型，公共资源同一云厂商只需通过一个账号同步。
+const publicResType = enumor.ImageCloudResType
+
+var vendors = []enumor.Vendor{enumor.TCloud, enumor.Aws, enumor.HuaWei, enumor.Azure, enumor.Gcp}
+
+// vendorResTypes 各云厂商全量同步的资源类型，需要和各云厂商 SyncAllResource 中同步的资源保持一致。
+var vendorResTypes = map[enumor.Vendor][]enumor.CloudResourceType{
+	enumor.TCloud: {enumor.DiskCloudResType, enumor.VpcCloudResType, enumor.SubnetCloudResType,
+		enumor.EipCloudResType, enumor.SecurityGroupCloudResType, enumor.CvmCloudResType,
+		enumor.RouteTableCloudResType, enumor.SubAccountCloudResType},
+	enumor.Aws: {enumor.DiskCloudResType, enumor.VpcCloudResType, enumor.SubnetCloudResType,
+		enumor.EipCloudResType, enumor.SecurityGroupCloudResType, enumor.CvmCloudResType,
+		enumor.RouteTableCloudResType},
+	enumor.HuaWei: {enumor.DiskCloudResType, enumor.VpcCloudResType, enumor.SubnetCloudResType,
+		enumor.EipCloudResType, enumor.SecurityGroupCloudResType, enumor.CvmCloudResType,
+		enumor.RouteTableCloudResType, enumor.SubAccountCloudResType},
+	enumor.Gcp: {enumor.DiskCloudResType, enumor.VpcCloudResType, enumor.SubnetCloudResType,
+		enumor.EipCloudResType, enumor.GcpFirewallRuleCloudResType, enumor.CvmCloudResType,
+		enumor.RouteTableCloudResType, enumor.SubAccountCloudResType},
+	enumor.Azure: {enumor.DiskCloudResType, enumor.SecurityGroupCloudResType, enumor.VpcCloudResType,
+		enumor.SubnetCloudResType, enumor.EipCloudResType, enumor.CvmCloudResType, enumor.RouteTableCloudResType,
+		enumor.NetworkInterfaceCloudResType, enumor.SubAccountCloudResType},
+}
+
+// InitScheduler init sync scheduler manager.
+func InitScheduler(conf cc.SyncScheduler, sd serviced.ServiceDiscover, cliSet *client.ClientSet) {
+	accountConf := make(map[string]cc.AccountSyncSchedule, len(conf.Accounts))
+	for _, one := range conf.Accounts {
+		accountConf[one.AccountID] = one
+	}
+
+	Manager = &Scheduler{
+		conf:        conf,
+		accountConf: accountConf,
+		sd:          sd,
+		cliSet:      cliSet,
+		tasks:       make(map[string]*task),
+		running:     make(map[enumor.Vendor]uint),
+		publicSync:  make(map[enumor.Vendor]time.Time),
+	}
+	Manager.runTask = Manager.run
+}
+
+// Scheduler 云资源同步调度器，调度状态仅保存在主节点内存中，主节点切换后新的主节点会重新同步全部账号。
+type Scheduler struct {
+	conf        cc.SyncScheduler
+	accountConf map[string]cc.AccountSyncSchedule
+	sd          serviced.ServiceDiscover
+	cliSet      *client.ClientSet
+
+	lock sync.Mutex
+	// tasks 账号同步任务，key 为账号ID
+	tasks map[string]*task
+	// running 各云厂商正在同步的账号数量
+	running map[enumor.Vendor]uint
+	// publicSync 各云厂商公共资源最近一次同步成功的时间
+	publicSync map[enumor.Vendor]time.Time
+	// runTask 执行派发的账号同步任务，默认为 run
+	runTask func(t *task, resTypes []enumor.CloudResourceType, syncPublic bool)
+}
+
+// task 账号同步任务
+type task struct {
+	accountID       string
+	vendor          enumor.Vendor
+	priority        int
+	interval        time.Duration
+	running         bool
+	nextRunTime     time.Time
+	lastRunTime     time.Time
+	lastSuccessTime time.Time
+	failedCount     uint
+	lastError       string
+	// lastSync 各类资源最近一次同步成功的时间
+	lastSync map[enumor.CloudResourceType]time.Time
+}
+
+// Run 循环调度账号同步任务，非主节点不执行同步。
+func (s *Scheduler) Run() {
+	logs.Infof("cloud resource sync scheduler enable, conf: %+v", s.conf)
+
+	for {
+		time.Sleep(tickInterval)
+
+		if !s.sd.IsMaster() {
+			s.reset()
+			continue
+		}
+
+		kt := core.NewBackendKit()
+		for _, vendor := range vendors {
+			accounts, err := s.listAccount(kt, vendor)
+			if err != nil {
+				logs.Errorf("sync scheduler list %s account failed, err: %v, rid: %s", vendor, err, kt.Rid)
+				continue
+			}
+			s.refresh(vendor, accounts)
+		}
+
+		s.dispatch()
+	}
+}
+
+// reset 清空调度状态，节点不再是主节点时调用。
+func (s *Scheduler) reset() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if len(s.tasks) == 0 {
+		return
+	}
+
+	for id, one := range s.tasks {
+		// 正在同步的账号等待同步结束后再移除
+		if !one.running {
+			delete(s.tasks, id)
+		}
+	}
+	s.publicSync = make(map[enumor.Vendor]time.Time)
+}
+
+// refresh 根据云厂商下最新的账号列表增删同步任务。
+func (s *Scheduler) refresh(vendor enumor.Vendor, accountIDs []string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	now := time.Now()
+	exists := make(map[string]struct{}, len(accountIDs))
+	for _, id := range accountIDs {
+		exists[id] = struct{}{}
+		if _, ok := s.tasks[id]; ok {
+			continue
+		}
+
+		interval := s.conf.DefaultIntervalMin
+		priority := 0
+		if conf, ok := s.accountConf[id]; ok {
+			priority = conf.Priority
+			if conf.IntervalMin != 0 {
+				interval = conf.IntervalMin
+			}
+		}
+
+		s.tasks[id] = &task{
+			accountID:   id,
+			vendor:      vendor,
+			priority:    priority,
+			interval:    time.Duration(interval) * time.Minute,
+			nextRunTime: now,
+			lastSync:    make(map[enumor.CloudResourceType]time.Time),
+		}
+	}
+
+	for id, one := range s.tasks {
+		if one.vendor != vendor || one.running {
+			continue
+		}
+
+		if _, ok := exists[id]; !ok {
+			delete(s.tasks, id)
+		}
+	}
+}
+
+// dispatch 按优先级从高到低启动到期的同步任务，每个云厂商同时同步的账号数量不超过并发上限。
+func (s *Scheduler) dispatch() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	now := time.Now()
+	due := make([]*task, 0)
+	for _, one := range s.tasks {
+		if !one.running && !one.nextRunTime.After(now) {
+			due = append(due, one)
+		}
+	}
+
+	sort.Slice(due, func(i, j int) bool {
+		if due[i].priority != due[j].priority {
+			return due[i].priority > due[j].priority
+		}
+		return due[i].nextRunTime.Before(due[j].nextRunTime)
+	})
+
+	for _, one := range due {
+		if s.running[one.vendor] >= s.conf.ConcurrencyPerVendor {
+			continue
+		}
+
+		resTypes, syncPublic := s.dueResTypes(one, now)
+		one.running = true
+		one.lastRunTime = now
+		s.running[one.vendor]++
+		if syncPublic {
+			// 预占公共资源同步，避免同一云厂商的多个账号同时同步公共资源
+			s.publicSync[one.vendor] = now
+		}
+
+		go s.runTask(one, resTypes, syncPublic)
+	}
+}
+
+// resTypeInterval 返回资源类型的同步间隔，未单独配置时使用账号的同步间隔。
+func (s *Scheduler) resTypeInterval(t *task, resType enumor.CloudResourceType) time.Duration {
+	if interval, ok := s.conf.ResTypeIntervalMin[string(resType)]; ok {
+		return time.Duration(interval) * time.Minute
+	}
+
+	return t.interval
+}
+
+// dueResTypes 返回账号下到期需要同步的资源类型，全部到期时返回空，表示全量同步。
+func (s *Scheduler) dueResTypes(t *task, now time.Time) ([]enumor.CloudResourceType, bool) {
+	all := vendorResTypes[t.vendor]
+	resTypes := make([]enumor.CloudResourceType, 0, len(all))
+	for _, resType := range all {
+		last, ok := t.lastSync[resType]
+		if !ok || !last.Add(s.resTypeInterval(t, resType)).After(now) {
+			resTypes = append(resTypes, resType)
+		}
+	}
+
+	last, ok := s.publicSync[t.vendor]
+	syncPublic := !ok || !last.Add(s.resTypeInterval(t, publicResType)).After(now)
+
+	if len(resTypes) == len(all) {
+		return nil, syncPublic
+	}
+
+	return resTypes, syncPublic
+}
+
+// run 执行账号同步，并根据同步结果计算下次同步时间。
+func (s *Scheduler) run(t *task, resTypes []enumor.CloudResourceType, syncPublic bool) {
+	kt := core.NewBackendKit()
+
+	err := s.syncAccount(kt, t, resTypes, syncPublic)
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	t.running = false
+	s.running[t.vendor]--
+	now := time.Now()
+
+	if err != nil {
+		if syncPublic {
+			// 释放预占的公共资源同步，由其他账号重新同步
+			delete(s.publicSync, t.vendor)
+		}
+
+		if errors.Is(err, lock.ErrLockFailed) {
+			// 账号正在手动同步，稍后再调度
+			t.nextRunTime = now.Add(tickInterval)
+			return
+		}
+
+		t.failedCount++
+		t.lastError = err.Error()
+		t.nextRunTime = now.Add(s.backoff(t.failedCount))
+		logs.Errorf("sync scheduler sync account failed, err: %v, account: %s, failed count: %d, next run time: %v, "+
+			"rid: %s", err, t.accountID, t.failedCount, t.nextRunTime, kt.Rid)
+		return
+	}
+
+	if syncPublic {
+		s.publicSync[t.vendor] = now
+	}
+	t.failedCount = 0
+	t.lastError = ""
+	t.lastSuccessTime = now
+	if len(resTypes) == 0 {
+		resTypes = vendorResTypes[t.vendor]
+	}
+	for _, resType := range resTypes {
+		t.lastSync[resType] = now
+	}
+	t.nextRunTime = s.nextRunTime(t)
+}
+
+// backoff 计算第 failedCount 次连续失败后的退避时间。
+func (s *Scheduler) backoff(failedCount uint) time.Duration {
+	backoff := s.conf.BackoffBaseMin
+	for i := uint(1); i < failedCount && backoff < s.conf.BackoffMaxMin; i++ {
+		backoff *= 2
+	}
+
+	if backoff > s.conf.BackoffMaxMin {
+		backoff = s.conf.BackoffMaxMin
+	}
+
+	return time.Duration(backoff) * time.Minute
+}
+
+// nextRunTime 账号下一次同步时间为各类资源下一次同步时间的最小值。
+func (s *Scheduler) nextRunTime(t *task) time.Time {
+	var next time.Time
+	for _, resType := range vendorResTypes[t.vendor] {
+		one := t.lastSync[resType].Add(s.resTypeInterval(t, resType))
+		if next.IsZero() || one.Before(next) {
+			next = one
+		}
+	}
+
+	return next
+}
+
+// syncAccount 同步账号下指定类型的资源，和手动同步共用账号同步锁，避免同一账号并行同步。
+func (s *Scheduler) syncAccount(kt *kit.Kit, t *task, resTypes []enumor.CloudResourceType, syncPublic bool) (
+	err error) {
+	syncLock, err := lock.Manager.TryLock(t.accountID)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err := syncLock.UnLock(); err != nil {
+			logs.Errorf("%s: unlock account sync lock failed, err: %v, accountID: %s, token: %s, rid: %s",
+				constant.AccountSyncFailed, err, t.accountID, syncLock.Token().String(), kt.Rid)
+		}
+	}()
+
+	// 锁租约丢失时终止同步，并以锁丢失作为同步失败原因
+	kt = syncLock.Kit(kt)
+	defer func() {
+		if lockErr := syncLock.Err(); lockErr != nil {
+			err = lockErr
+		}
+	}()
+
+	logs.Infof("sync scheduler start sync account: %s, vendor: %s, res types: %v, sync public: %v, rid: %s",
+		t.accountID, t.vendor, resTypes, syncPublic, kt.Rid)
+
+	if cc.CloudServer().CloudResource.Sync.RunAsFlow {
+		opt := &syncflow.Option{AccountID: t.accountID, SyncPublicResource: syncPublic, ResTypes: resTypes}
+		if _, err = syncflow.SyncAccount(kt, s.cliSet, t.vendor, opt); err != nil {
+			return err
+		}
+
+		assign.EvaluateRuleAfterSync(kt, s.cliSet.DataService(), t.accountID)
+		return nil
+	}
+
+	var resType enumor.CloudResourceType
+	switch t.vendor {
+	case enumor.TCloud:
+		opt := &tcloud.SyncAllResourceOption{AccountID: t.accountID, SyncPublicResource: syncPublic,
+			ResTypes: resTypes}
+		resType, err = tcloud.SyncAllResource(kt, s.cliSet, opt)
+
+	case enumor.Aws:
+		opt := &aws.SyncAllResourceOption{AccountID: t.accountID, SyncPublicResource: syncPublic,
+			ResTypes: resTypes}
+		resType, err = aws.SyncAllResource(kt, s.cliSet, opt)
+
+	case enumor.HuaWei:
+		opt := &huawei.SyncAllResourceOption{AccountID: t.accountID, SyncPublicResource: syncPublic,
+			ResTypes: resTypes}
+		resType, err = huawei.SyncAllResource(kt, s.cliSet, opt)
+
+	case enumor.Azure:
+		opt := &azure.SyncAllResourceOption{AccountID: t.accountID, SyncPublicResource: syncPublic,
+			ResTypes: resTypes}
+		resType, err = azure.SyncAllResource(kt, s.cliSet, opt)
+
+	case enumor.Gcp:
+		opt := &gcp.SyncAllResourceOption{AccountID: t.accountID, SyncPublicResource: syncPublic,
+			ResTypes: resTypes}
+		resType, err = gcp.SyncAllResource(kt, s.cliSet, opt)
+
+	default:
+		return fmt.Errorf("vendor: %s not support", t.vendor)
+	}
+	if err != nil {
+		if resType != "" {
+			sd := &detail.SyncDetail{Kt: kt, DataCli: s.cliSet.DataService(), AccountID: t.accountID,
+				Vendor: string(t.vendor)}
+			if err := sd.ResSyncStatusFailed(resType, err); err != nil {
+				logs.Errorf("%s sync %s res detail failed, err: %v, accountID: %s, rid: %s", t.vendor, resType,
+					err, t.accountID, kt.Rid)
+			}
+			return fmt.Errorf("sync %s failed, err: %v", resType, err)
+		}
+		return err
+	}
+
+	assign.EvaluateRuleAfterSync(kt, s.cliSet.DataService(), t.accountID)
+
+	return nil
+}
+
+// listAccount 查询云厂商下的全部资源账号ID
+func (s *Scheduler) listAccount(kt *kit.Kit, vendor enumor.Vendor) ([]string, error) {
+	listReq := &protocloud.AccountListReq{
+		Filter: &filter.Expression{Op: filter.And, Rules: []filter.RuleFactory{
+			&filter.AtomRule{Field: "vendor", Op: filter.Equal.Factory(), Value: vendor},
+			&filter.AtomRule{Field: "type", Op: filter.Equal.Factory(), Value: enumor.ResourceAccount}}},
+		Page: &core.BasePage{Start: 0, Limit: core.DefaultMaxPageLimit},
+	}
+
+	accountIDs := make([]string, 0)
+	for {
+		result, err := s.cliSet.DataService().Global.Account.List(kt.Ctx, kt.Header(), listReq)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, one := range result.Details {
+			accountIDs = append(accountIDs, one.ID)
+		}
+
+		if len(result.Details) < int(core.DefaultMaxPageLimit) {
+			break
+		}
+		listReq.Page.Start += uint32(core.DefaultMaxPageLimit)
+	}
+
+	return accountIDs, nil
+}
+
+// IsMaster 调度器是否在当前节点运行
+func (s *Scheduler) IsMaster() bool {
+	return s.sd.IsMaster()
+}
+
+// List 查询账号同步调度队列，按下次同步时间排序，vendor 和 accountIDs 为空时不过滤。
+func (s *Scheduler) List(vendor enumor.Vendor, accountIDs []string) *account.ListSyncScheduleResult {
+	result := &account.ListSyncScheduleResult{
+		IsMaster: s.IsMaster(),
+		Details:  make([]*account.SyncSchedule, 0),
+	}
+
+	idMap := make(map[string]struct{}, len(accountIDs))
+	for _, id := range accountIDs {
+		idMap[id] = struct{}{}
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	tasks := make([]*task, 0, len(s.tasks))
+	for id, one := range s.tasks {
+		if len(vendor) != 0 && one.vendor != vendor {
+			continue
+		}
+
+		if _, ok := idMap[id]; len(idMap) != 0 && !ok {
+			continue
+		}
+		tasks = append(tasks, one)
+	}
+
+	sort.Slice(tasks, func(i, j int) bool {
+		if !tasks[i].nextRunTime.Equal(tasks[j].nextRunTime) {
+			return tasks[i].nextRunTime.Before(tasks[j].nextRunTime)
+		}
+		return tasks[i].priority > tasks[j].priority
+	})
+
+	for _, one := range tasks {
+		result.Details = append(result.Details, s.convSchedule(one))
+	}
+
+	return result
+}
+
+func (s *Scheduler) convSchedule(t *task) *account.SyncSchedule {
+	schedule := &account.SyncSchedule{
+		AccountID:   t.accountID,
+		Vendor:      t.vendor,
+		Priority:    t.priority,
+		IntervalMin: uint64(t.interval / time.Minute),
+		Status:      account.SyncScheduleWaiting,
+		NextRunTime: times.ConvStdTimeFormat(t.nextRunTime),
+		FailedCount: t.failedCount,
+		LastError:   t.lastError,
+		ResTypes:    make([]account.ResTypeSchedule, 0, len(vendorResTypes[t.vendor])),
+	}
+
+	switch {
+	case t.running:
+		schedule.Status = account.SyncScheduleRunning
+	case t.failedCount > 0:
+		schedule.Status = account.SyncScheduleBackoff
+	}
+
+	if !t.lastRunTime.IsZero() {
+		schedule.LastRunTime = times.ConvStdTimeFormat(t.lastRunTime)
+	}
+
+	if !t.lastSuccessTime.IsZero() {
+		schedule.LastSuccessTime = times.ConvStdTimeFormat(t.lastSuccessTime)
+	}
+
+	for _, resType := range vendorResTypes[t.vendor] {
+		interval := s.resTypeInterval(t, resType)
+		one := account.ResTypeSchedule{
+			ResType:      resType,
+			IntervalMin:  uint64(interval / time.Minute),
+			NextSyncTime: schedule.NextRunTime,
+		}
+
+		if last, ok := t.lastSync[resType]; ok {
+			one.LastSyncTime = times.ConvStdTimeFormat(last)
+			if next := last.Add(interval); next.After(t.nextRunTime) {
+				one.NextSyncTime = times.ConvStdTimeFormat(next)
+			}
+		}
+		schedule.ResTypes = append(schedule.ResTypes, one)
+	}
+
+	return schedule
+}
@@ -0,0 +1,208 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package scheduler
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"hcm/pkg/cc"
+	"hcm/pkg/criteria/enumor"
+)
+
+func newTestScheduler(conf cc.SyncScheduler) *Scheduler {
+	s := &Scheduler{
+		conf:       conf,
+		tasks:      make(map[string]*task),
+		running:    make(map[enumor.Vendor]uint),
+		publicSync: make(map[enumor.Vendor]time.Time),
+	}
+	s.runTask = func(*task, []enumor.CloudResourceType, bool) {}
+	return s
+}
+
+func TestBackoff(t *testing.T) {
+	cases := []struct {
+		name        string
+		baseMin     uint64
+		maxMin      uint64
+		failedCount uint
+		expect      time.Duration
+	}{
+		{name: "first failure", baseMin: 1, maxMin: 30, failedCount: 1, expect: time.Minute},
+		{name: "second failure", baseMin: 1, maxMin: 30, failedCount: 2, expect: 2 * time.Minute},
+		{name: "fifth failure", baseMin: 1, maxMin: 30, failedCount: 5, expect: 16 * time.Minute},
+		{name: "reach max", baseMin: 1, maxMin: 30, failedCount: 6, expect: 30 * time.Minute},
+		{name: "exceed max", baseMin: 1, maxMin: 30, failedCount: 100, expect: 30 * time.Minute},
+		{name: "base larger than max", baseMin: 60, maxMin: 30, failedCount: 1, expect: 30 * time.Minute},
+		{name: "no backoff", baseMin: 0, maxMin: 30, failedCount: 3, expect: 0},
+	}
+
+	for _, c := range cases {
+		s := newTestScheduler(cc.SyncScheduler{BackoffBaseMin: c.baseMin, BackoffMaxMin: c.maxMin})
+		if got := s.backoff(c.failedCount); got != c.expect {
+			t.Errorf("%s: expect backoff %v, but got %v", c.name, c.expect, got)
+		}
+	}
+}
+
+func TestDueResTypes(t *testing.T) {
+	now := time.Now()
+	conf := cc.SyncScheduler{ResTypeIntervalMin: map[string]uint64{
+		string(enumor.CvmCloudResType): 10,
+		string(publicResType):          24 * 60,
+	}}
+
+	syncedAt := func(at time.Time) map[enumor.CloudResourceType]time.Time {
+		lastSync := make(map[enumor.CloudResourceType]time.Time)
+		for _, resType := range vendorResTypes[enumor.Aws] {
+			lastSync[resType] = at
+		}
+		return lastSync
+	}
+
+	cases := []struct {
+		name       string
+		lastSync   map[enumor.CloudResourceType]time.Time
+		publicSync *time.Time
+		resTypes   []enumor.CloudResourceType
+		syncPublic bool
+	}{
+		{name: "never synced", lastSync: map[enumor.CloudResourceType]time.Time{}, syncPublic: true},
+		{name: "only res type with shorter interval due", lastSync: syncedAt(now.Add(-30 * time.Minute)),
+			publicSync: timePtr(now.Add(-30 * time.Minute)),
+			resTypes:   []enumor.CloudResourceType{enumor.CvmCloudResType}},
+		{name: "all due", lastSync: syncedAt(now.Add(-2 * time.Hour)), publicSync: timePtr(now.Add(-2 * time.Hour))},
+		{name: "due at interval boundary", lastSync: syncedAt(now.Add(-time.Hour)),
+			publicSync: timePtr(now.Add(-24 * time.Hour)), syncPublic: true},
+		{name: "nothing due", lastSync: syncedAt(now.Add(-time.Minute)), publicSync: timePtr(now.Add(-time.Minute)),
+			resTypes: []enumor.CloudResourceType{}},
+	}
+
+	for _, c := range cases {
+		s := newTestScheduler(conf)
+		if c.publicSync != nil {
+			s.publicSync[enumor.Aws] = *c.publicSync
+		}
+		one := &task{accountID: "account", vendor: enumor.Aws, interval: time.Hour, lastSync: c.lastSync}
+
+		resTypes, syncPublic := s.dueResTypes(one, now)
+		if !reflect.DeepEqual(resTypes, c.resTypes) {
+			t.Errorf("%s: expect res types %v, but got %v", c.name, c.resTypes, resTypes)
+		}
+
+		if syncPublic != c.syncPublic {
+			t.Errorf("%s: expect sync public %v, but got %v", c.name, c.syncPublic, syncPublic)
+		}
+	}
+}
+
+func timePtr(t time.Time) *time.Time {
+	return &t
+}
+
+func TestDispatch(t *testing.T) {
+	now := time.Now()
+	newTask := func(id string, vendor enumor.Vendor, priority int, nextRunTime time.Time) *task {
+		return &task{accountID: id, vendor: vendor, priority: priority, interval: time.Hour,
+			nextRunTime: nextRunTime, lastSync: make(map[enumor.CloudResourceType]time.Time)}
+	}
+
+	cases := []struct {
+		name        string
+		concurrency uint
+		tasks       []*task
+		running     map[enumor.Vendor]uint
+		dispatched  []string
+	}{
+		{
+			name:        "higher priority first",
+			concurrency: 1,
+			tasks: []*task{newTask("low", enumor.TCloud, 0, now.Add(-time.Hour)),
+				newTask("high", enumor.TCloud, 10, now.Add(-time.Minute))},
+			dispatched: []string{"high"},
+		},
+		{
+			name:        "earlier next run time first with same priority",
+			concurrency: 1,
+			tasks: []*task{newTask("later", enumor.TCloud, 0, now.Add(-time.Minute)),
+				newTask("earlier", enumor.TCloud, 0, now.Add(-time.Hour))},
+			dispatched: []string{"earlier"},
+		},
+		{
+			name:        "not due",
+			concurrency: 2,
+			tasks: []*task{newTask("due", enumor.TCloud, 0, now.Add(-time.Minute)),
+				newTask("future", enumor.TCloud, 10, now.Add(time.Hour))},
+			dispatched: []string{"due"},
+		},
+		{
+			name:        "concurrency per vendor",
+			concurrency: 1,
+			tasks: []*task{newTask("tcloud", enumor.TCloud, 0, now.Add(-time.Minute)),
+				newTask("aws", enumor.Aws, 0, now.Add(-time.Minute))},
+			running:    map[enumor.Vendor]uint{enumor.Aws: 1},
+			dispatched: []string{"tcloud"},
+		},
+	}
+
+	for _, c := range cases {
+		s := newTestScheduler(cc.SyncScheduler{ConcurrencyPerVendor: c.concurrency})
+		for vendor, count := range c.running {
+			s.running[vendor] = count
+		}
+		for _, one := range c.tasks {
+			s.tasks[one.accountID] = one
+		}
+
+		runCh := make(chan string, len(c.tasks))
+		s.runTask = func(t *task, _ []enumor.CloudResourceType, _ bool) { runCh <- t.accountID }
+
+		s.dispatch()
+
+		dispatched := make(map[string]struct{})
+		for range c.dispatched {
+			select {
+			case id := <-runCh:
+				dispatched[id] = struct{}{}
+			case <-time.After(time.Second):
+				t.Fatalf("%s: wait dispatched task timeout", c.name)
+			}
+		}
+
+		for _, id := range c.dispatched {
+			one := s.tasks[id]
+			if _, ok := dispatched[id]; !ok || !one.running || one.lastRunTime.IsZero() {
+				t.Errorf("%s: task %s should be dispatched", c.name, id)
+			}
+
+			if _, ok := s.publicSync[one.vendor]; !ok {
+				t.Errorf("%s: public resource sync of %s should be occupied", c.name, one.vendor)
+			}
+		}
+
+		for _, one := range c.tasks {
+			if _, ok := dispatched[one.accountID]; !ok && one.running {
+				t.Errorf("%s: task %s should not be dispatched", c.name, one.accountID)
+			}
+		}
+	}
+}
@@ -0,0 +1,276 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+// Package syncflow 以异步任务流的方式在 task-server 中同步账号下的云资源
+package syncflow
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"hcm/cmd/cloud-server/service/sync/aws"
+	"hcm/cmd/cloud-server/service/sync/azure"
+	"hcm/cmd/cloud-server/service/sync/gcp"
+	"hcm/cmd/cloud-server/service/sync/huawei"
+	"hcm/cmd/cloud-server/service/sync/tcloud"
+	actionsync "hcm/cmd/task-server/logics/action/sync"
+	"hcm/pkg/api/core"
+	hcsync "hcm/pkg/api/hc-service/sync"
+	ts "hcm/pkg/api/task-server"
+	"hcm/pkg/client"
+	taskserver "hcm/pkg/client/task-server"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/runtime/filter"
+	"hcm/pkg/tools/slice"
+)
+
+const (
+	// waitInterval 查询任务流状态的间隔
+	waitInterval = 5 * time.Second
+	// waitTimeout 等待任务流执行结束的最长时间
+	waitTimeout = 6 * time.Hour
+)
+
+// Option 账号同步任务流选项
+type Option struct {
+	AccountID string
+	// SyncPublicResource 是否同步公共资源，公共资源在创建任务流前同步
+	SyncPublicResource bool
+	// ResTypes 需要同步的资源类型，为空时同步全部资源
+	ResTypes []enumor.CloudResourceType
+}
+
+// SyncAccount 创建账号同步任务流并等待执行结束，返回任务流ID。
+func SyncAccount(kt *kit.Kit, cliSet *client.ClientSet, vendor enumor.Vendor, opt *Option) (string, error) {
+	flowID, err := CreateSyncAccountFlow(kt, cliSet, vendor, opt)
+	if err != nil {
+		return "", err
+	}
+
+	return flowID, WaitFlowEnd(kt, cliSet.TaskServer(), flowID)
+}
+
+// CreateSyncAccountFlow 创建账号同步任务流，每个地域的每类资源一个任务，任务失败后按任务重试。
+func CreateSyncAccountFlow(kt *kit.Kit, cliSet *client.ClientSet, vendor enumor.Vendor, opt *Option) (string,
+	error) {
+
+	scopes, err := prepareSyncScopes(kt, cliSet, vendor, opt)
+	if err != nil {
+		return "", err
+	}
+
+	tasks := actionsync.BuildSyncAccountTasks(vendor, opt.AccountID, scopes)
+	if len(tasks) == 0 {
+		return "", fmt.Errorf("account: %s has no resource to sync", opt.AccountID)
+	}
+
+	// 任务在 task-server 中执行，需要透传账号同步锁的 fencing token
+	for _, task := range tasks {
+		if params, ok := task.Params.(*actionsync.SyncResourceOption); ok {
+			params.FencingToken = kt.SyncFencingToken
+		}
+	}
+
+	return createFlow(kt, cliSet.TaskServer(), enumor.FlowSyncAccount, vendor, opt.AccountID, tasks)
+}
+
+// SelectiveOption 账号定向同步任务流选项
+type SelectiveOption struct {
+	AccountID string
+	// Regions 需要同步的地域，为空时同步全部地域，只对区分地域的资源生效，指定地域时不区分地域的资源需要在 ResTypes 中显式指定
+	Regions []string
+	// ResTypes 需要同步的资源类型，为空时同步全部资源
+	ResTypes []enumor.CloudResourceType
+	// Targets 按云ID定向同步的目标，不为空时只同步指定云ID的资源，忽略 Regions 和 ResTypes
+	Targets []hcsync.CloudIDSyncReq
+}
+
+// CreateSelectiveSyncFlow 创建账号定向同步任务流并返回任务流ID，不等待任务流执行结束，也不同步公共资源。
+func CreateSelectiveSyncFlow(kt *kit.Kit, cliSet *client.ClientSet, vendor enumor.Vendor, opt *SelectiveOption) (
+	string, error) {
+
+	var scopes map[enumor.CloudResourceType][]string
+	if len(opt.Targets) == 0 {
+		var err error
+		scopes, err = prepareSyncScopes(kt, cliSet, vendor, &Option{AccountID: opt.AccountID,
+			ResTypes: opt.ResTypes})
+		if err != nil {
+			return "", err
+		}
+	}
+
+	tasks := buildSelectiveSyncTasks(vendor, opt, scopes)
+	if len(tasks) == 0 {
+		return "", fmt.Errorf("account: %s has no resource to sync in specified scope", opt.AccountID)
+	}
+
+	return createFlow(kt, cliSet.TaskServer(), enumor.FlowSelectiveSyncAccount, vendor, opt.AccountID, tasks)
+}
+
+// buildSelectiveSyncTasks 生成定向同步任务，指定了同步目标时按云ID生成任务，否则将账号下指定资源类型的同步范围 scopes
+// 按地域过滤后生成任务。
+func buildSelectiveSyncTasks(vendor enumor.Vendor, opt *SelectiveOption,
+	scopes map[enumor.CloudResourceType][]string) []ts.CustomFlowTask {
+
+	if len(opt.Targets) != 0 {
+		return actionsync.BuildSyncByCloudIDsTasks(vendor, opt.Targets)
+	}
+
+	if len(opt.Regions) != 0 {
+		filterScopeRegions(scopes, opt.Regions, len(opt.ResTypes) != 0)
+	}
+
+	return actionsync.BuildSyncAccountTasks(vendor, opt.AccountID, scopes)
+}
+
+// filterScopeRegions 只保留指定地域的同步范围，keepGlobal 为 false 时去掉不区分地域的资源。
+func filterScopeRegions(scopes map[enumor.CloudResourceType][]string, regions []string, keepGlobal bool) {
+	for resType, scopeRegions := range scopes {
+		if len(scopeRegions) == 0 {
+			if !keepGlobal {
+				delete(scopes, resType)
+			}
+			continue
+		}
+
+		filtered := slice.Filter(scopeRegions, func(region string) bool {
+			return slice.IsItemInSlice(regions, region)
+		})
+		if len(filtered) == 0 {
+			delete(scopes, resType)
+			continue
+		}
+		scopes[resType] = filtered
+	}
+}
+
+// prepareSyncScopes 同步前的准备，返回各类资源需要同步的地域。
+func prepareSyncScopes(kt *kit.Kit, cliSet *client.ClientSet, vendor enumor.Vendor, opt *Option) (
+	map[enumor.CloudResourceType][]string, error) {
+
+	var scopes map[enumor.CloudResourceType][]string
+	var err error
+	switch vendor {
+	case enumor.TCloud:
+		scopes, err = tcloud.PrepareSyncFlow(kt, cliSet, &tcloud.SyncAllResourceOption{AccountID: opt.AccountID,
+			SyncPublicResource: opt.SyncPublicResource, ResTypes: opt.ResTypes})
+	case enumor.Aws:
+		scopes, err = aws.PrepareSyncFlow(kt, cliSet, &aws.SyncAllResourceOption{AccountID: opt.AccountID,
+			SyncPublicResource: opt.SyncPublicResource, ResTypes: opt.ResTypes})
+	case enumor.HuaWei:
+		scopes, err = huawei.PrepareSyncFlow(kt, cliSet, &huawei.SyncAllResourceOption{AccountID: opt.AccountID,
+			SyncPublicResource: opt.SyncPublicResource, ResTypes: opt.ResTypes})
+	case enumor.Gcp:
+		scopes, err = gcp.PrepareSyncFlow(kt, cliSet, &gcp.SyncAllResourceOption{AccountID: opt.AccountID,
+			SyncPublicResource: opt.SyncPublicResource, ResTypes: opt.ResTypes})
+	case enumor.Azure:
+		scopes, err = azure.PrepareSyncFlow(kt, cliSet, &azure.SyncAllResourceOption{AccountID: opt.AccountID,
+			SyncPublicResource: opt.SyncPublicResource, ResTypes: opt.ResTypes})
+	default:
+		return nil, fmt.Errorf("vendor: %s not support", vendor)
+	}
+	if err != nil {
+		logs.Errorf("prepare sync account flow failed, err: %v, vendor: %s, account: %s, rid: %s", err, vendor,
+			opt.AccountID, kt.Rid)
+		return nil, err
+	}
+
+	return scopes, nil
+}
+
+// createFlow 在 task-server 中创建账号同步任务流
+func createFlow(kt *kit.Kit, cli *taskserver.Client, name enumor.FlowName, vendor enumor.Vendor, accountID string,
+	tasks []ts.CustomFlowTask) (string, error) {
+
+	addReq := &ts.AddCustomFlowReq{
+		Name:  name,
+		Memo:  fmt.Sprintf("%s %s account %s", name, vendor, accountID),
+		Tasks: tasks,
+	}
+	result, err := cli.CreateCustomFlow(kt, addReq)
+	if err != nil {
+		logs.Errorf("call taskserver to create %s flow failed, err: %v, account: %s, rid: %s", name, err,
+			accountID, kt.Rid)
+		return "", err
+	}
+
+	logs.Infof("create %s flow success, flow: %s, vendor: %s, account: %s, task count: %d, rid: %s", name,
+		result.ID, vendor, accountID, len(tasks), kt.Rid)
+
+	return result.ID, nil
+}
+
+// WaitFlowEnd 等待账号同步任务流执行结束，任务流失败时返回失败任务的原因。
+func WaitFlowEnd(kt *kit.Kit, cli *taskserver.Client, id string) error {
+	end := time.Now().Add(waitTimeout)
+	for {
+		if time.Now().After(end) {
+			return fmt.Errorf("wait timeout, sync account flow: %s is running", id)
+		}
+
+		flow, err := cli.GetFlow(kt, id)
+		if err != nil {
+			logs.Errorf("get flow failed, err: %v, id: %s, rid: %s", err, id, kt.Rid)
+			return err
+		}
+
+		switch flow.State {
+		case enumor.FlowSuccess:
+			return nil
+		case enumor.FlowCancel:
+			return fmt.Errorf("sync account flow: %s is canceled", id)
+		case enumor.FlowFailed, enumor.FlowRolledBack:
+			return failedReason(kt, cli, id)
+		}
+
+		time.Sleep(waitInterval)
+	}
+}
+
+// failedReason 汇总任务流中失败任务的原因
+func failedReason(kt *kit.Kit, cli *taskserver.Client, id string) error {
+	req := &core.ListReq{
+		Filter: tools.EqualWithOpExpression(filter.And, map[string]interface{}{
+			"flow_id": id,
+			"state":   enumor.TaskFailed,
+		}),
+		Page: core.NewDefaultBasePage(),
+	}
+	result, err := cli.ListTask(kt, req)
+	if err != nil {
+		logs.Errorf("list task failed, err: %v, flow: %s, rid: %s", err, id, kt.Rid)
+		return err
+	}
+
+	if len(result.Details) == 0 {
+		return fmt.Errorf("sync account flow: %s failed", id)
+	}
+
+	reasons := make([]string, 0, len(result.Details))
+	for _, one := range result.Details {
+		if one.Reason != nil {
+			reasons = append(reasons, fmt.Sprintf("%s: %s", one.ActionID, one.Reason.Message))
+		}
+	}
+
+	return fmt.Errorf("sync account flow: %s failed, %s", id, strings.Join(reasons, "; "))
+}
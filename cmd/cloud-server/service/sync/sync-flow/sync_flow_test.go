@@ -0,0 +1,120 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package syncflow
+
+import (
+	"testing"
+
+	actionsync "hcm/cmd/task-server/logics/action/sync"
+	hcsync "hcm/pkg/api/hc-service/sync"
+	"hcm/pkg/criteria/enumor"
+)
+
+func TestBuildSelectiveSyncTasks(t *testing.T) {
+	// newScopes 模拟 prepareSyncScopes 返回的同步范围，每个用例单独生成，过滤会修改同步范围
+	newScopes := func() map[enumor.CloudResourceType][]string {
+		return map[enumor.CloudResourceType][]string{
+			enumor.SubAccountCloudResType: nil,
+			enumor.VpcCloudResType:        {"ap-guangzhou", "ap-shanghai"},
+			enumor.CvmCloudResType:        {"ap-guangzhou", "ap-shanghai", "ap-beijing"},
+		}
+	}
+
+	cases := []struct {
+		name string
+		opt  *SelectiveOption
+		// expects 期望生成的任务ID及其同步的地域
+		expects map[string]string
+	}{
+		{
+			name: "no filter",
+			opt:  &SelectiveOption{AccountID: "0001"},
+			expects: map[string]string{"sub_account": "", "vpc-1": "ap-guangzhou", "vpc-2": "ap-shanghai",
+				"cvm-1": "ap-guangzhou", "cvm-2": "ap-shanghai", "cvm-3": "ap-beijing"},
+		},
+		{
+			name:    "regions without res types drop global resource",
+			opt:     &SelectiveOption{AccountID: "0001", Regions: []string{"ap-shanghai"}},
+			expects: map[string]string{"vpc-1": "ap-shanghai", "cvm-1": "ap-shanghai"},
+		},
+		{
+			name: "regions with res types keep global resource",
+			opt: &SelectiveOption{AccountID: "0001", Regions: []string{"ap-beijing"},
+				ResTypes: []enumor.CloudResourceType{enumor.SubAccountCloudResType, enumor.CvmCloudResType}},
+			expects: map[string]string{"sub_account": "", "cvm-1": "ap-beijing"},
+		},
+		{
+			name:    "regions out of scopes",
+			opt:     &SelectiveOption{AccountID: "0001", Regions: []string{"ap-hongkong"}},
+			expects: map[string]string{},
+		},
+	}
+
+	for _, c := range cases {
+		tasks := buildSelectiveSyncTasks(enumor.TCloud, c.opt, newScopes())
+		if len(tasks) != len(c.expects) {
+			t.Errorf("case %s expect %d tasks, got %d", c.name, len(c.expects), len(tasks))
+			continue
+		}
+
+		for _, one := range tasks {
+			region, exist := c.expects[string(one.ActionID)]
+			if !exist {
+				t.Errorf("case %s got unexpected task %s", c.name, one.ActionID)
+				continue
+			}
+
+			opt, ok := one.Params.(*actionsync.SyncResourceOption)
+			if !ok {
+				t.Fatalf("case %s task %s params type %T is invalid", c.name, one.ActionID, one.Params)
+			}
+			if opt.Region != region || opt.AccountID != c.opt.AccountID {
+				t.Errorf("case %s task %s expect region %s, got %+v", c.name, one.ActionID, region, *opt)
+			}
+		}
+	}
+}
+
+func TestBuildSelectiveSyncTasksByTargets(t *testing.T) {
+	opt := &SelectiveOption{
+		AccountID: "0001",
+		Regions:   []string{"ap-guangzhou"},
+		Targets: []hcsync.CloudIDSyncReq{{AccountID: "0001", ResType: enumor.CvmCloudResType,
+			Region: "ap-guangzhou", CloudIDs: []string{"ins-1", "ins-2"}}},
+	}
+
+	// 指定同步目标时按云ID生成任务，不使用同步范围
+	tasks := buildSelectiveSyncTasks(enumor.TCloud, opt, nil)
+	if len(tasks) != 1 {
+		t.Fatalf("expect 1 task, got %d", len(tasks))
+	}
+
+	if tasks[0].ActionName != enumor.ActionSyncResourceByCloudIDs {
+		t.Errorf("expect action %s, got %s", enumor.ActionSyncResourceByCloudIDs, tasks[0].ActionName)
+	}
+
+	params, ok := tasks[0].Params.(*actionsync.SyncByCloudIDsOption)
+	if !ok {
+		t.Fatalf("params type %T is invalid", tasks[0].Params)
+	}
+	if len(params.CloudIDs) != 2 || params.Region != "ap-guangzhou" {
+		t.Errorf("expect target %+v, got %+v", opt.Targets[0], params.CloudIDSyncReq)
+	}
+}
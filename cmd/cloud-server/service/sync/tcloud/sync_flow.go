@@ -0,0 +1,100 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+package tcloud
+
+import (
+	"fmt"
+
+	"hcm/cmd/cloud-server/service/sync/detail"
+	"hcm/pkg/client"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/kit"
+)
+
+// PrepareSyncFlow 以任务流方式同步账号前的准备，同步公共资源并返回各类资源需要同步的地域，
+// 地域为空的资源不区分地域，在一个任务中同步。
+func PrepareSyncFlow(kt *kit.Kit, cliSet *client.ClientSet, opt *SyncAllResourceOption) (
+	map[enumor.CloudResourceType][]string, error) {
+
+	if err := opt.Validate(); err != nil {
+		return nil, err
+	}
+
+	if opt.SyncPublicResource {
+		syncOpt := &SyncPublicResourceOption{AccountID: opt.AccountID}
+		if err := SyncPublicResource(kt, cliSet, syncOpt); err != nil {
+			return nil, err
+		}
+	}
+
+	regions, err := ListRegion(kt, cliSet.DataService())
+	if err != nil {
+		return nil, err
+	}
+
+	scopes := make(map[enumor.CloudResourceType][]string)
+	regionResTypes := []enumor.CloudResourceType{enumor.DiskCloudResType, enumor.VpcCloudResType,
+		enumor.SubnetCloudResType, enumor.EipCloudResType, enumor.SecurityGroupCloudResType, enumor.CvmCloudResType,
+		enumor.RouteTableCloudResType}
+	for _, resType := range regionResTypes {
+		if opt.needSync(resType) {
+			scopes[resType] = regions
+		}
+	}
+
+	if opt.needSync(enumor.SubAccountCloudResType) {
+		scopes[enumor.SubAccountCloudResType] = nil
+	}
+
+	return scopes, nil
+}
+
+// SyncResource 同步账号下指定地域的一类资源，供账号同步任务流中的单个任务执行。
+func SyncResource(kt *kit.Kit, cliSet *client.ClientSet, accountID string, resType enumor.CloudResourceType,
+	region string) error {
+
+	sd := &detail.SyncDetail{
+		Kt:        kt,
+		DataCli:   cliSet.DataService(),
+		AccountID: accountID,
+		Vendor:    string(enumor.TCloud),
+	}
+	regions := []string{region}
+
+	switch resType {
+	case enumor.DiskCloudResType:
+		return SyncDisk(kt, cliSet, accountID, regions, sd)
+	case enumor.VpcCloudResType:
+		return SyncVpc(kt, cliSet, accountID, regions, sd)
+	case enumor.SubnetCloudResType:
+		return SyncSubnet(kt, cliSet, accountID, regions, sd)
+	case enumor.EipCloudResType:
+		return SyncEip(kt, cliSet, accountID, regions, sd)
+	case enumor.SecurityGroupCloudResType:
+		return SyncSG(kt, cliSet, accountID, regions, sd)
+	case enumor.CvmCloudResType:
+		return SyncCvm(kt, cliSet, accountID, regions, sd)
+	case enumor.RouteTableCloudResType:
+		return SyncRouteTable(kt, cliSet, accountID, regions, sd)
+	case enumor.SubAccountCloudResType:
+		return SyncSubAccount(kt, cliSet, accountID, sd)
+	default:
+		return fmt.Errorf("tcloud not support sync %s", resType)
+	}
+}
@@ -24,15 +24,18 @@ import (
 	"sync"
 	"time"
 
+	"hcm/cmd/cloud-server/logics/assign"
 	"hcm/cmd/cloud-server/service/sync/aws"
 	"hcm/cmd/cloud-server/service/sync/azure"
 	"hcm/cmd/cloud-server/service/sync/detail"
 	"hcm/cmd/cloud-server/service/sync/gcp"
 	"hcm/cmd/cloud-server/service/sync/huawei"
+	syncflow "hcm/cmd/cloud-server/service/sync/sync-flow"
 	"hcm/cmd/cloud-server/service/sync/tcloud"
 	"hcm/pkg/api/core"
 	corecloud "hcm/pkg/api/core/cloud"
 	protocloud "hcm/pkg/api/data-service/cloud"
+	"hcm/pkg/cc"
 	"hcm/pkg/client"
 	dataservice "hcm/pkg/client/data-service"
 	"hcm/pkg/criteria/enumor"
@@ -109,6 +112,19 @@ func allAccountSync(kt *kit.Kit, cliSet *client.ClientSet, vendor enumor.Vendor)
 				Vendor:    string(one.Vendor),
 			}
 
+			if cc.CloudServer().CloudResource.Sync.RunAsFlow {
+				opt := &syncflow.Option{AccountID: one.ID, SyncPublicResource: syncPublicResource}
+				if _, err = syncflow.SyncAccount(kt, cliSet, one.Vendor, opt); err != nil {
+					logs.Errorf("sync %s account by flow failed, err: %v, accountID: %s, rid: %s", vendor, err,
+						one.ID, kt.Rid)
+					continue
+				}
+
+				assign.EvaluateRuleAfterSync(kt, cliSet.DataService(), one.ID)
+				syncPublicResource = false
+				continue
+			}
+
 			switch one.Vendor {
 			case enumor.TCloud:
 				opt := &tcloud.SyncAllResourceOption{AccountID: one.ID, SyncPublicResource: syncPublicResource}
@@ -147,6 +163,8 @@ func allAccountSync(kt *kit.Kit, cliSet *client.ClientSet, vendor enumor.Vendor)
 				continue
 			}
 
+			assign.EvaluateRuleAfterSync(kt, cliSet.DataService(), one.ID)
+
 			// 公共资源仅需要同步一次即可
 			syncPublicResource = false
 		}
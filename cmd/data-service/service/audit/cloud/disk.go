@@ -93,9 +93,12 @@ func (ad Audit) diskOperationAuditBuild(kt *kit.Kit, ops []protoaudit.CloudResou
 	[]*tableaudit.AuditTable, error,
 ) {
 	assCvmOps := make([]protoaudit.CloudResourceOperationInfo, 0)
+	tagOps := make([]protoaudit.CloudResourceOperationInfo, 0)
 
 	for _, op := range ops {
 		switch op.Action {
+		case protoaudit.Tag, protoaudit.UnTag:
+			tagOps = append(tagOps, op)
 		case protoaudit.Associate, protoaudit.Disassociate:
 			switch op.AssociatedResType {
 			case enumor.CvmAuditResType:
@@ -109,13 +112,21 @@ func (ad Audit) diskOperationAuditBuild(kt *kit.Kit, ops []protoaudit.CloudResou
 	}
 
 	audits := make([]*tableaudit.AuditTable, 0, len(ops))
-	if len(ops) != 0 {
+	if len(assCvmOps) != 0 {
 		audit, err := ad.diskAssCvmOperationAuditBuild(kt, assCvmOps)
 		if err != nil {
 			return nil, err
 		}
 		audits = append(audits, audit...)
 	}
+
+	if len(tagOps) != 0 {
+		audit, err := ad.tagOperationAuditBuild(kt, enumor.DiskAuditResType, tagOps)
+		if err != nil {
+			return nil, err
+		}
+		audits = append(audits, audit...)
+	}
 	return audits, nil
 }
 
@@ -90,9 +90,12 @@ func (ad Audit) eipOperationAuditBuild(kt *kit.Kit, ops []protoaudit.CloudResour
 ) {
 	assCvmOps := make([]protoaudit.CloudResourceOperationInfo, 0)
 	assNetworkOps := make([]protoaudit.CloudResourceOperationInfo, 0)
+	tagOps := make([]protoaudit.CloudResourceOperationInfo, 0)
 
 	for _, op := range ops {
 		switch op.Action {
+		case protoaudit.Tag, protoaudit.UnTag:
+			tagOps = append(tagOps, op)
 		case protoaudit.Associate, protoaudit.Disassociate:
 			switch op.AssociatedResType {
 			case enumor.CvmAuditResType:
@@ -127,6 +130,15 @@ func (ad Audit) eipOperationAuditBuild(kt *kit.Kit, ops []protoaudit.CloudResour
 		audits = append(audits, audit...)
 	}
 
+	if len(tagOps) != 0 {
+		audit, err := ad.tagOperationAuditBuild(kt, enumor.EipAuditResType, tagOps)
+		if err != nil {
+			return nil, err
+		}
+
+		audits = append(audits, audit...)
+	}
+
 	return audits, nil
 }
 
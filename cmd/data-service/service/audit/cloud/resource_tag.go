@@ -0,0 +1,140 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+package cloud
+
+import (
+	"fmt"
+
+	"hcm/cmd/data-service/service/audit/cloud/subnet"
+	protoaudit "hcm/pkg/api/data-service/audit"
+	"hcm/pkg/criteria/enumor"
+	tableaudit "hcm/pkg/dal/table/audit"
+	"hcm/pkg/kit"
+	"hcm/pkg/tools/converter"
+)
+
+// tagAuditRes 标签操作审计需要记录的资源基础信息
+type tagAuditRes struct {
+	cloudID   string
+	name      string
+	bkBizID   int64
+	vendor    enumor.Vendor
+	accountID string
+}
+
+// tagOperationAuditBuild 资源标签操作审计，标签操作没有关联资源，仅记录被操作资源的基础信息。
+func (ad Audit) tagOperationAuditBuild(kt *kit.Kit, resType enumor.AuditResourceType,
+	ops []protoaudit.CloudResourceOperationInfo) ([]*tableaudit.AuditTable, error) {
+
+	ids := make([]string, 0, len(ops))
+	for _, one := range ops {
+		if one.Action != protoaudit.Tag && one.Action != protoaudit.UnTag {
+			return nil, fmt.Errorf("audit action: %s not support", one.Action)
+		}
+		ids = append(ids, one.ResID)
+	}
+
+	resMap, err := ad.listTagAuditRes(kt, resType, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	audits := make([]*tableaudit.AuditTable, 0, len(ops))
+	for _, one := range ops {
+		res, exist := resMap[one.ResID]
+		if !exist {
+			continue
+		}
+
+		action, err := one.Action.ConvAuditAction()
+		if err != nil {
+			return nil, err
+		}
+
+		audits = append(audits, &tableaudit.AuditTable{
+			ResID:      one.ResID,
+			CloudResID: res.cloudID,
+			ResName:    res.name,
+			ResType:    resType,
+			Action:     action,
+			BkBizID:    res.bkBizID,
+			Vendor:     res.vendor,
+			AccountID:  res.accountID,
+			Operator:   kt.User,
+			Source:     kt.GetRequestSource(),
+			Rid:        kt.Rid,
+			AppCode:    kt.AppCode,
+			Detail:     &tableaudit.BasicDetail{},
+		})
+	}
+
+	return audits, nil
+}
+
+func (ad Audit) listTagAuditRes(kt *kit.Kit, resType enumor.AuditResourceType, ids []string) (
+	map[string]tagAuditRes, error) {
+
+	result := make(map[string]tagAuditRes, len(ids))
+	switch resType {
+	case enumor.DiskAuditResType:
+		disks, err := ad.listDisk(kt, ids)
+		if err != nil {
+			return nil, err
+		}
+		for id, one := range disks {
+			result[id] = tagAuditRes{cloudID: one.CloudID, name: one.Name, bkBizID: one.BkBizID,
+				vendor: enumor.Vendor(one.Vendor), accountID: one.AccountID}
+		}
+
+	case enumor.EipAuditResType:
+		eips, err := ad.listEip(kt, ids)
+		if err != nil {
+			return nil, err
+		}
+		for id, one := range eips {
+			result[id] = tagAuditRes{cloudID: one.CloudID, name: converter.PtrToVal(one.Name), bkBizID: one.BkBizID,
+				vendor: enumor.Vendor(one.Vendor), accountID: one.AccountID}
+		}
+
+	case enumor.VpcCloudAuditResType:
+		vpcs, err := ad.listVpc(kt, ids)
+		if err != nil {
+			return nil, err
+		}
+		for id, one := range vpcs {
+			result[id] = tagAuditRes{cloudID: one.CloudID, name: converter.PtrToVal(one.Name), bkBizID: one.BkBizID,
+				vendor: one.Vendor, accountID: one.AccountID}
+		}
+
+	case enumor.SubnetAuditResType:
+		subnets, err := subnet.ListSubnet(kt, ad.dao, ids)
+		if err != nil {
+			return nil, err
+		}
+		for id, one := range subnets {
+			result[id] = tagAuditRes{cloudID: one.CloudID, name: converter.PtrToVal(one.Name), bkBizID: one.BkBizID,
+				vendor: one.Vendor, accountID: one.AccountID}
+		}
+
+	default:
+		return nil, fmt.Errorf("audit resource type: %s not support tag", resType)
+	}
+
+	return result, nil
+}
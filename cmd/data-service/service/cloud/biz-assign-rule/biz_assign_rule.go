@@ -0,0 +1,356 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package bizassignrule
+
+import (
+	"fmt"
+
+	"hcm/pkg/api/core"
+	corecloud "hcm/pkg/api/core/cloud"
+	dataservice "hcm/pkg/api/data-service"
+	protocloud "hcm/pkg/api/data-service/cloud"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/orm"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/dal/dao/types"
+	tablecloud "hcm/pkg/dal/table/cloud"
+	tabletype "hcm/pkg/dal/table/types"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+	"hcm/pkg/tools/converter"
+	"hcm/pkg/tools/json"
+	"hcm/pkg/tools/slice"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// BatchCreateBizAssignRule batch create biz assign rule.
+func (svc *service) BatchCreateBizAssignRule(cts *rest.Contexts) (interface{}, error) {
+	req := new(protocloud.BizAssignRuleBatchCreateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	accountIDs := make([]string, 0, len(req.Rules))
+	for _, rule := range req.Rules {
+		accountIDs = append(accountIDs, rule.AccountID)
+	}
+
+	vendorMap, err := svc.getAccountVendorMap(cts.Kit, slice.Unique(accountIDs))
+	if err != nil {
+		return nil, err
+	}
+
+	models := make([]tablecloud.BizAssignRuleTable, 0, len(req.Rules))
+	for _, rule := range req.Rules {
+		vendor, exists := vendorMap[rule.AccountID]
+		if !exists {
+			return nil, errf.Newf(errf.RecordNotFound, "account: %s not found", rule.AccountID)
+		}
+
+		tags, err := tabletype.NewJsonField(sliceOrEmpty(rule.Tags))
+		if err != nil {
+			return nil, errf.NewFromErr(errf.InvalidParameter, err)
+		}
+
+		models = append(models, tablecloud.BizAssignRuleTable{
+			Name:        rule.Name,
+			Priority:    converter.ValToPtr(rule.Priority),
+			Enabled:     converter.ValToPtr(rule.Enabled),
+			Vendor:      vendor,
+			AccountID:   rule.AccountID,
+			Regions:     sliceOrEmpty(rule.Regions),
+			CloudVpcIDs: sliceOrEmpty(rule.CloudVpcIDs),
+			NamePattern: converter.ValToPtr(rule.NamePattern),
+			Tags:        tags,
+			ResTypes:    resTypesToStringArray(rule.ResTypes),
+			BkBizID:     rule.BkBizID,
+			BkCloudID:   bkCloudIDOrUnbind(rule.BkCloudID),
+			Memo:        converter.ValToPtr(rule.Memo),
+			Creator:     cts.Kit.User,
+			Reviser:     cts.Kit.User,
+		})
+	}
+
+	ids, err := svc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		return svc.dao.BizAssignRule().BatchCreateWithTx(cts.Kit, txn, models)
+	})
+	if err != nil {
+		logs.Errorf("batch create biz assign rule failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	createdIDs, ok := ids.([]string)
+	if !ok {
+		return nil, fmt.Errorf("create biz assign rule but return id type is not []string, id type: %T", ids)
+	}
+
+	return &core.BatchCreateResult{IDs: createdIDs}, nil
+}
+
+// UpdateBizAssignRule update biz assign rule.
+func (svc *service) UpdateBizAssignRule(cts *rest.Contexts) (interface{}, error) {
+	id := cts.PathParameter("id").String()
+	if len(id) == 0 {
+		return nil, errf.New(errf.InvalidParameter, "id is required")
+	}
+
+	req := new(protocloud.BizAssignRuleUpdateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	model := &tablecloud.BizAssignRuleTable{
+		Name:        req.Name,
+		Priority:    req.Priority,
+		Enabled:     req.Enabled,
+		Regions:     req.Regions,
+		CloudVpcIDs: req.CloudVpcIDs,
+		NamePattern: req.NamePattern,
+		BkBizID:     req.BkBizID,
+		BkCloudID:   req.BkCloudID,
+		Memo:        req.Memo,
+		Reviser:     cts.Kit.User,
+	}
+
+	if len(req.ResTypes) != 0 {
+		model.ResTypes = resTypesToStringArray(req.ResTypes)
+		// 资源类型变更时，重新设置vpc条件，避免vpc条件与资源类型不匹配
+		model.CloudVpcIDs = sliceOrEmpty(req.CloudVpcIDs)
+	}
+
+	if req.Tags != nil {
+		tags, err := tabletype.NewJsonField(req.Tags)
+		if err != nil {
+			return nil, errf.NewFromErr(errf.InvalidParameter, err)
+		}
+		model.Tags = tags
+	}
+
+	_, err := svc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		return nil, svc.dao.BizAssignRule().UpdateByIDWithTx(cts.Kit, txn, id, model)
+	})
+	if err != nil {
+		logs.Errorf("update biz assign rule failed, err: %v, id: %s, rid: %s", err, id, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// ListBizAssignRule list biz assign rule.
+func (svc *service) ListBizAssignRule(cts *rest.Contexts) (interface{}, error) {
+	req := new(core.ListReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	opt := &types.ListOption{
+		Filter: req.Filter,
+		Page:   req.Page,
+		Fields: req.Fields,
+	}
+	result, err := svc.dao.BizAssignRule().List(cts.Kit, opt)
+	if err != nil {
+		logs.Errorf("list biz assign rule failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, fmt.Errorf("list biz assign rule failed, err: %v", err)
+	}
+
+	if req.Page.Count {
+		return &protocloud.BizAssignRuleListResult{Count: result.Count}, nil
+	}
+
+	details := make([]corecloud.BizAssignRule, 0, len(result.Details))
+	for _, one := range result.Details {
+		rule, err := convBizAssignRule(one)
+		if err != nil {
+			logs.Errorf("convert biz assign rule failed, err: %v, id: %s, rid: %s", err, one.ID, cts.Kit.Rid)
+			return nil, err
+		}
+		details = append(details, *rule)
+	}
+
+	return &protocloud.BizAssignRuleListResult{Details: details}, nil
+}
+
+// BatchDeleteBizAssignRule batch delete biz assign rule.
+func (svc *service) BatchDeleteBizAssignRule(cts *rest.Contexts) (interface{}, error) {
+	req := new(dataservice.BatchDeleteReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	_, err := svc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		return nil, svc.dao.BizAssignRule().DeleteWithTx(cts.Kit, txn, req.Filter)
+	})
+	if err != nil {
+		logs.Errorf("batch delete biz assign rule failed, err: %v, filter: %+v, rid: %s", err, req.Filter,
+			cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// ListBizAssignRecord list biz assign record.
+func (svc *service) ListBizAssignRecord(cts *rest.Contexts) (interface{}, error) {
+	req := new(core.ListReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	opt := &types.ListOption{
+		Filter: req.Filter,
+		Page:   req.Page,
+		Fields: req.Fields,
+	}
+	result, err := svc.dao.BizAssignRecord().List(cts.Kit, opt)
+	if err != nil {
+		logs.Errorf("list biz assign record failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, fmt.Errorf("list biz assign record failed, err: %v", err)
+	}
+
+	if req.Page.Count {
+		return &protocloud.BizAssignRecordListResult{Count: result.Count}, nil
+	}
+
+	details := make([]corecloud.BizAssignRecord, 0, len(result.Details))
+	for _, one := range result.Details {
+		details = append(details, corecloud.BizAssignRecord{
+			ID:        one.ID,
+			RuleID:    one.RuleID,
+			Vendor:    one.Vendor,
+			AccountID: one.AccountID,
+			ResType:   one.ResType,
+			ResID:     one.ResID,
+			BkBizID:   one.BkBizID,
+			BkCloudID: one.BkCloudID,
+			CreatedRevision: &core.CreatedRevision{
+				Creator:   one.Creator,
+				CreatedAt: one.CreatedAt.String(),
+			},
+		})
+	}
+
+	return &protocloud.BizAssignRecordListResult{Details: details}, nil
+}
+
+func (svc *service) getAccountVendorMap(kt *kit.Kit, accountIDs []string) (map[string]enumor.Vendor, error) {
+	opt := &types.ListOption{
+		Fields: []string{"id", "vendor"},
+		Filter: tools.ContainersExpression("id", accountIDs),
+		Page:   core.NewDefaultBasePage(),
+	}
+	result, err := svc.dao.Account().List(kt, opt)
+	if err != nil {
+		logs.Errorf("list account failed, err: %v, ids: %v, rid: %s", err, accountIDs, kt.Rid)
+		return nil, err
+	}
+
+	vendorMap := make(map[string]enumor.Vendor, len(result.Details))
+	for _, one := range result.Details {
+		vendorMap[one.ID] = enumor.Vendor(one.Vendor)
+	}
+
+	return vendorMap, nil
+}
+
+func convBizAssignRule(one tablecloud.BizAssignRuleTable) (*corecloud.BizAssignRule, error) {
+	tags := make([]corecloud.TagPair, 0)
+	if !one.Tags.IsEmpty() {
+		if err := json.UnmarshalFromString(string(one.Tags), &tags); err != nil {
+			return nil, fmt.Errorf("unmarshal biz assign rule tags failed, err: %v", err)
+		}
+	}
+
+	resTypes := make([]enumor.CloudResourceType, 0, len(one.ResTypes))
+	for _, resType := range one.ResTypes {
+		resTypes = append(resTypes, enumor.CloudResourceType(resType))
+	}
+
+	return &corecloud.BizAssignRule{
+		ID:          one.ID,
+		Name:        one.Name,
+		Priority:    converter.PtrToVal(one.Priority),
+		Enabled:     converter.PtrToVal(one.Enabled),
+		Vendor:      one.Vendor,
+		AccountID:   one.AccountID,
+		Regions:     one.Regions,
+		CloudVpcIDs: one.CloudVpcIDs,
+		NamePattern: converter.PtrToVal(one.NamePattern),
+		Tags:        tags,
+		ResTypes:    resTypes,
+		BkBizID:     one.BkBizID,
+		BkCloudID:   one.BkCloudID,
+		Memo:        converter.PtrToVal(one.Memo),
+		Revision: &core.Revision{
+			Creator:   one.Creator,
+			Reviser:   one.Reviser,
+			CreatedAt: one.CreatedAt.String(),
+			UpdatedAt: one.UpdatedAt.String(),
+		},
+	}, nil
+}
+
+func resTypesToStringArray(resTypes []enumor.CloudResourceType) tabletype.StringArray {
+	result := make(tabletype.StringArray, 0, len(resTypes))
+	for _, resType := range slice.Unique(resTypes) {
+		result = append(result, string(resType))
+	}
+	return result
+}
+
+func bkCloudIDOrUnbind(bkCloudID int64) int64 {
+	if bkCloudID <= 0 {
+		return constant.UnbindBkCloudID
+	}
+	return bkCloudID
+}
+
+// sliceOrEmpty 将nil切片转换为空切片，避免json字段存储为null
+func sliceOrEmpty[T any](list []T) []T {
+	if list == nil {
+		return make([]T, 0)
+	}
+	return list
+}
@@ -0,0 +1,496 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package bizassignrule
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+
+	"hcm/cmd/data-service/service/cloud/cvm"
+	"hcm/pkg/api/core"
+	corecloud "hcm/pkg/api/core/cloud"
+	"hcm/pkg/api/data-service/audit"
+	protocloud "hcm/pkg/api/data-service/cloud"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/orm"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/dal/dao/types"
+	tableaudit "hcm/pkg/dal/table/audit"
+	tablecloud "hcm/pkg/dal/table/cloud"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+	"hcm/pkg/runtime/filter"
+	"hcm/pkg/tools/slice"
+
+	"github.com/jmoiron/sqlx"
+)
+
+var assignRuleAuditTypeMap = map[enumor.CloudResourceType]enumor.AuditResourceType{
+	enumor.VpcCloudResType:           enumor.VpcCloudAuditResType,
+	enumor.SubnetCloudResType:        enumor.SubnetAuditResType,
+	enumor.SecurityGroupCloudResType: enumor.SecurityGroupAuditResType,
+	enumor.EipCloudResType:           enumor.EipAuditResType,
+	enumor.DiskCloudResType:          enumor.DiskAuditResType,
+	enumor.CvmCloudResType:           enumor.CvmAuditResType,
+}
+
+// EvaluateBizAssignRule evaluate biz assign rules, assign the matched unassigned resources to biz if not dry run.
+// 规则按优先级从高到低依次匹配，一个资源只会被第一个匹配到的规则分配。
+func (svc *service) EvaluateBizAssignRule(cts *rest.Contexts) (interface{}, error) {
+	req := new(protocloud.BizAssignRuleEvaluateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	rules, err := svc.listEvaluateRules(cts.Kit, req)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &protocloud.BizAssignRuleEvaluateResult{
+		DryRun:  req.DryRun,
+		Details: make([]protocloud.BizAssignRuleEvaluateMatch, 0),
+	}
+
+	claimed := make(map[enumor.CloudResourceType]map[string]struct{})
+	for _, rule := range rules {
+		matches, err := svc.matchRule(cts.Kit, rule, claimed)
+		if err != nil {
+			logs.Errorf("match biz assign rule failed, err: %v, rule: %s, rid: %s", err, rule.ID, cts.Kit.Rid)
+			return nil, err
+		}
+
+		if len(matches) == 0 {
+			continue
+		}
+
+		if !req.DryRun {
+			// 只返回实际分配成功的资源，评估期间被手动分配的资源不会被规则覆盖
+			matches, err = svc.assignByRule(cts.Kit, rule, matches)
+			if err != nil {
+				logs.Errorf("assign resource by rule failed, err: %v, rule: %s, rid: %s", err, rule.ID, cts.Kit.Rid)
+				return nil, err
+			}
+		}
+
+		result.Details = append(result.Details, matches...)
+	}
+
+	return result, nil
+}
+
+// listEvaluateRules list the rules to be evaluated, sorted by priority desc.
+func (svc *service) listEvaluateRules(kt *kit.Kit, req *protocloud.BizAssignRuleEvaluateReq) (
+	[]corecloud.BizAssignRule, error) {
+
+	rules := make([]filter.RuleFactory, 0)
+	if len(req.AccountID) != 0 {
+		rules = append(rules, &filter.AtomRule{Field: "account_id", Op: filter.Equal.Factory(), Value: req.AccountID})
+	}
+
+	// 指定规则时允许预览未启用的规则
+	if len(req.RuleIDs) != 0 {
+		rules = append(rules, &filter.AtomRule{Field: "id", Op: filter.In.Factory(), Value: req.RuleIDs})
+	} else {
+		rules = append(rules, &filter.AtomRule{Field: "enabled", Op: filter.Equal.Factory(), Value: true})
+	}
+
+	opt := &types.ListOption{
+		Filter: &filter.Expression{Op: filter.And, Rules: rules},
+		Page:   core.NewDefaultBasePage(),
+	}
+
+	result := make([]corecloud.BizAssignRule, 0)
+	for {
+		list, err := svc.dao.BizAssignRule().List(kt, opt)
+		if err != nil {
+			logs.Errorf("list biz assign rule failed, err: %v, rid: %s", err, kt.Rid)
+			return nil, err
+		}
+
+		for _, one := range list.Details {
+			rule, err := convBizAssignRule(one)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, *rule)
+		}
+
+		if len(list.Details) < int(opt.Page.Limit) {
+			break
+		}
+		opt.Page.Start += uint32(opt.Page.Limit)
+	}
+
+	sortRulesByPriority(result)
+
+	return result, nil
+}
+
+// sortRulesByPriority sort rules by priority desc, rules with the same priority are sorted by id asc.
+func sortRulesByPriority(rules []corecloud.BizAssignRule) {
+	sort.SliceStable(rules, func(i, j int) bool {
+		if rules[i].Priority != rules[j].Priority {
+			return rules[i].Priority > rules[j].Priority
+		}
+		return rules[i].ID < rules[j].ID
+	})
+}
+
+// matchRule list the unassigned resources matched by rule, and mark them as claimed.
+func (svc *service) matchRule(kt *kit.Kit, rule corecloud.BizAssignRule,
+	claimed map[enumor.CloudResourceType]map[string]struct{}) ([]protocloud.BizAssignRuleEvaluateMatch, error) {
+
+	var namePattern *regexp.Regexp
+	if len(rule.NamePattern) != 0 {
+		var err error
+		if namePattern, err = regexp.Compile(rule.NamePattern); err != nil {
+			return nil, fmt.Errorf("name pattern %s is invalid, err: %v", rule.NamePattern, err)
+		}
+	}
+
+	matches := make([]protocloud.BizAssignRuleEvaluateMatch, 0, len(rule.ResTypes))
+	for _, resType := range rule.ResTypes {
+		// 规则指定了vpc时跳过没有vpc字段的资源类型，避免单个资源类型导致整个评估失败
+		if _, exists := corecloud.BizAssignRuleVpcResTypes[resType]; len(rule.CloudVpcIDs) != 0 && !exists {
+			logs.Warnf("%s can not be matched by vpc, skip it, rule: %s, rid: %s", resType, rule.ID, kt.Rid)
+			continue
+		}
+
+		expr, err := buildRuleExpr(rule, resType)
+		if err != nil {
+			return nil, err
+		}
+
+		infos, err := svc.listRuleResources(kt, resType, expr)
+		if err != nil {
+			return nil, err
+		}
+
+		ids := claimResources(resType, infos, namePattern, claimed)
+		if len(ids) == 0 {
+			continue
+		}
+
+		matches = append(matches, protocloud.BizAssignRuleEvaluateMatch{
+			RuleID:    rule.ID,
+			RuleName:  rule.Name,
+			AccountID: rule.AccountID,
+			ResType:   resType,
+			ResIDs:    ids,
+			BkBizID:   rule.BkBizID,
+			BkCloudID: rule.BkCloudID,
+		})
+	}
+
+	return matches, nil
+}
+
+// listRuleResources list all the resources matched by rule expr page by page.
+func (svc *service) listRuleResources(kt *kit.Kit, resType enumor.CloudResourceType, expr *filter.Expression) (
+	[]types.CloudResourceBasicInfo, error) {
+
+	result := make([]types.CloudResourceBasicInfo, 0)
+	page := core.NewDefaultBasePage()
+	for {
+		infos, err := svc.dao.Cloud().ListResourceBasicInfoByFilter(kt, resType, expr, page, "id", "name")
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, infos...)
+
+		if uint(len(infos)) < page.Limit {
+			break
+		}
+		page.Start += uint32(page.Limit)
+	}
+
+	return result, nil
+}
+
+// claimResources returns the ids of resources whose name matches the pattern and are not claimed by the rules of
+// higher priority, and mark them as claimed.
+func claimResources(resType enumor.CloudResourceType, infos []types.CloudResourceBasicInfo,
+	namePattern *regexp.Regexp, claimed map[enumor.CloudResourceType]map[string]struct{}) []string {
+
+	if _, exists := claimed[resType]; !exists {
+		claimed[resType] = make(map[string]struct{})
+	}
+
+	ids := make([]string, 0, len(infos))
+	for _, info := range infos {
+		if _, exists := claimed[resType][info.ID]; exists {
+			continue
+		}
+
+		if namePattern != nil && !namePattern.MatchString(info.Name) {
+			continue
+		}
+
+		claimed[resType][info.ID] = struct{}{}
+		ids = append(ids, info.ID)
+	}
+
+	return ids
+}
+
+// buildRuleExpr build the unassigned resource filter of rule, name pattern is matched after query.
+func buildRuleExpr(rule corecloud.BizAssignRule, resType enumor.CloudResourceType) (*filter.Expression, error) {
+	if _, exists := corecloud.BizAssignRuleSupportedResTypes[resType]; !exists {
+		return nil, fmt.Errorf("resource type %s can not be assigned by rule", resType)
+	}
+
+	rules := []filter.RuleFactory{
+		&filter.AtomRule{Field: "account_id", Op: filter.Equal.Factory(), Value: rule.AccountID},
+		&filter.AtomRule{Field: "vendor", Op: filter.Equal.Factory(), Value: rule.Vendor},
+		&filter.AtomRule{Field: "bk_biz_id", Op: filter.Equal.Factory(), Value: constant.UnassignedBiz},
+	}
+
+	if len(rule.Regions) != 0 {
+		rules = append(rules, &filter.AtomRule{Field: "region", Op: filter.In.Factory(), Value: rule.Regions})
+	}
+
+	if len(rule.CloudVpcIDs) != 0 {
+		switch resType {
+		case enumor.VpcCloudResType:
+			rules = append(rules, &filter.AtomRule{Field: "cloud_id", Op: filter.In.Factory(),
+				Value: rule.CloudVpcIDs})
+		case enumor.SubnetCloudResType:
+			rules = append(rules, &filter.AtomRule{Field: "cloud_vpc_id", Op: filter.In.Factory(),
+				Value: rule.CloudVpcIDs})
+		case enumor.CvmCloudResType:
+			rules = append(rules, &filter.AtomRule{Field: "cloud_vpc_ids", Op: filter.JSONOverlaps.Factory(),
+				Value: rule.CloudVpcIDs})
+		default:
+			return nil, fmt.Errorf("resource type %s can not be matched by vpc", resType)
+		}
+	}
+
+	// 规则未指定管控区域时，只能分配已绑定管控区域的vpc
+	if resType == enumor.VpcCloudResType && rule.BkCloudID <= 0 {
+		rules = append(rules, &filter.AtomRule{Field: "bk_cloud_id", Op: filter.GreaterThan.Factory(), Value: 0})
+	}
+
+	for _, tag := range rule.Tags {
+		rules = append(rules, &filter.AtomRule{Field: filter.TagRuleField + "." + tag.Key,
+			Op: filter.Equal.Factory(), Value: tag.Value})
+	}
+
+	return &filter.Expression{Op: filter.And, Rules: rules}, nil
+}
+
+// assignByRule assign the matched resources to rule's biz, bind the unbound vpc with rule's cloud area,
+// and record the assignments and audits. returns the matches of resources that are actually assigned.
+func (svc *service) assignByRule(kt *kit.Kit, rule corecloud.BizAssignRule,
+	matches []protocloud.BizAssignRuleEvaluateMatch) ([]protocloud.BizAssignRuleEvaluateMatch, error) {
+
+	result, err := svc.dao.Txn().AutoTxn(kt, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		assigned := make([]protocloud.BizAssignRuleEvaluateMatch, 0, len(matches))
+		auditOpts := make([]audit.CloudResourceAssignInfo, 0)
+		records := make([]tablecloud.BizAssignRecordTable, 0)
+
+		for _, match := range matches {
+			assignedIDs := make([]string, 0, len(match.ResIDs))
+			for _, ids := range slice.Split(match.ResIDs, constant.BatchOperationMaxLimit) {
+				batch, err := svc.assignBatch(kt, txn, rule, match.ResType, ids)
+				if err != nil {
+					return nil, err
+				}
+
+				assignedIDs = append(assignedIDs, batch.ids...)
+				auditOpts = append(auditOpts, batch.auditOpts...)
+				records = append(records, batch.records...)
+			}
+
+			if len(assignedIDs) == 0 {
+				continue
+			}
+
+			match.ResIDs = assignedIDs
+			assigned = append(assigned, match)
+		}
+
+		if err := svc.createAudit(kt, txn, auditOpts); err != nil {
+			return nil, err
+		}
+
+		for _, batch := range slice.Split(records, constant.BatchOperationMaxLimit) {
+			if _, err := svc.dao.BizAssignRecord().BatchCreateWithTx(kt, txn, batch); err != nil {
+				return nil, err
+			}
+		}
+
+		return assigned, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	assigned, ok := result.([]protocloud.BizAssignRuleEvaluateMatch)
+	if !ok {
+		return nil, fmt.Errorf("assign by rule result type %T is invalid", result)
+	}
+
+	for _, match := range assigned {
+		if match.ResType != enumor.CvmCloudResType {
+			continue
+		}
+
+		if err = cvm.SyncCvmToCmdb(kt, rule.AccountID, rule.BkBizID); err != nil {
+			logs.Errorf("sync cvm to cmdb failed, err: %v, accountID: %s, bkBizID: %d, rid: %s", err,
+				rule.AccountID, rule.BkBizID, kt.Rid)
+			return nil, err
+		}
+		break
+	}
+
+	return assigned, nil
+}
+
+type assignBatchResult struct {
+	ids       []string
+	auditOpts []audit.CloudResourceAssignInfo
+	records   []tablecloud.BizAssignRecordTable
+}
+
+// assignBatch assign a batch of matched resources to rule's biz in transaction.
+// 在事务中锁定并重新查询仍未分配的资源，只分配、审计和记录这些资源，避免覆盖评估期间被手动分配的资源。
+func (svc *service) assignBatch(kt *kit.Kit, txn *sqlx.Tx, rule corecloud.BizAssignRule,
+	resType enumor.CloudResourceType, ids []string) (*assignBatchResult, error) {
+
+	lockFilter := &filter.Expression{
+		Op: filter.And,
+		Rules: []filter.RuleFactory{
+			&filter.AtomRule{Field: "id", Op: filter.In.Factory(), Value: ids},
+			&filter.AtomRule{Field: "bk_biz_id", Op: filter.Equal.Factory(), Value: constant.UnassignedBiz},
+		},
+	}
+	unassignedIDs, err := svc.dao.Cloud().ListResourceIDsForUpdateWithTx(kt, txn, resType, lockFilter)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &assignBatchResult{ids: unassignedIDs}
+	if len(unassignedIDs) == 0 {
+		return result, nil
+	}
+
+	if result.auditOpts, err = svc.bindVpcCloudArea(kt, txn, rule, resType, unassignedIDs); err != nil {
+		return nil, err
+	}
+
+	err = svc.dao.Cloud().AssignResourceToBiz(kt, txn, resType, tools.ContainersExpression("id", unassignedIDs),
+		rule.BkBizID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, id := range unassignedIDs {
+		result.auditOpts = append(result.auditOpts, audit.CloudResourceAssignInfo{
+			ResType:         assignRuleAuditTypeMap[resType],
+			ResID:           id,
+			AssignedResType: enumor.BizAuditAssignedResType,
+			AssignedResID:   rule.BkBizID,
+		})
+
+		result.records = append(result.records, tablecloud.BizAssignRecordTable{
+			RuleID:    rule.ID,
+			Vendor:    rule.Vendor,
+			AccountID: rule.AccountID,
+			ResType:   resType,
+			ResID:     id,
+			BkBizID:   rule.BkBizID,
+			BkCloudID: rule.BkCloudID,
+			Creator:   kt.User,
+		})
+	}
+
+	return result, nil
+}
+
+// bindVpcCloudArea bind the unbound vpc with rule's cloud area in transaction, returns the cloud area bind audit
+// options.
+func (svc *service) bindVpcCloudArea(kt *kit.Kit, txn *sqlx.Tx, rule corecloud.BizAssignRule,
+	resType enumor.CloudResourceType, ids []string) ([]audit.CloudResourceAssignInfo, error) {
+
+	if resType != enumor.VpcCloudResType || rule.BkCloudID <= 0 {
+		return nil, nil
+	}
+
+	expr := &filter.Expression{
+		Op: filter.And,
+		Rules: []filter.RuleFactory{
+			&filter.AtomRule{Field: "id", Op: filter.In.Factory(), Value: ids},
+			&filter.AtomRule{Field: "bk_cloud_id", Op: filter.Equal.Factory(), Value: constant.UnbindBkCloudID},
+		},
+	}
+	unboundIDs, err := svc.dao.Cloud().ListResourceIDsForUpdateWithTx(kt, txn, resType, expr)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(unboundIDs) == 0 {
+		return nil, nil
+	}
+
+	auditOpts := make([]audit.CloudResourceAssignInfo, 0, len(unboundIDs))
+	for _, id := range unboundIDs {
+		auditOpts = append(auditOpts, audit.CloudResourceAssignInfo{
+			ResType:         enumor.VpcCloudAuditResType,
+			ResID:           id,
+			AssignedResType: enumor.CloudAreaAuditAssignedResType,
+			AssignedResID:   rule.BkCloudID,
+		})
+	}
+
+	err = svc.dao.Cloud().BindResourceCloudArea(kt, txn, resType, tools.ContainersExpression("id", unboundIDs),
+		rule.BkCloudID)
+	if err != nil {
+		return nil, err
+	}
+
+	return auditOpts, nil
+}
+
+func (svc *service) createAudit(kt *kit.Kit, txn *sqlx.Tx, auditOpts []audit.CloudResourceAssignInfo) error {
+	if len(auditOpts) == 0 {
+		return nil
+	}
+
+	allAudits := make([]*tableaudit.AuditTable, 0, len(auditOpts))
+	for _, opts := range slice.Split(auditOpts, constant.BatchOperationMaxLimit) {
+		audits, err := svc.audit.GenCloudResAssignAudit(kt, &audit.CloudResourceAssignAuditReq{Assigns: opts})
+		if err != nil {
+			return err
+		}
+		allAudits = append(allAudits, audits...)
+	}
+
+	return svc.dao.Audit().BatchCreateWithTx(kt, txn, allAudits)
+}
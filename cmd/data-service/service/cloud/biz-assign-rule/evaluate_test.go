@@ -0,0 +1,217 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package bizassignrule
+
+import (
+	"reflect"
+	"regexp"
+	"testing"
+
+	corecloud "hcm/pkg/api/core/cloud"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/dal/dao/types"
+	"hcm/pkg/kit"
+	"hcm/pkg/runtime/filter"
+)
+
+func TestSortRulesByPriority(t *testing.T) {
+	rules := []corecloud.BizAssignRule{
+		{ID: "3", Priority: 1},
+		{ID: "2", Priority: 10},
+		{ID: "1", Priority: 1},
+		{ID: "4", Priority: 5},
+	}
+
+	sortRulesByPriority(rules)
+
+	ids := make([]string, 0, len(rules))
+	for _, rule := range rules {
+		ids = append(ids, rule.ID)
+	}
+
+	if expect := []string{"2", "4", "1", "3"}; !reflect.DeepEqual(ids, expect) {
+		t.Errorf("rules should be sorted by priority desc and id asc, expect: %v, actual: %v", expect, ids)
+	}
+}
+
+func TestClaimResources(t *testing.T) {
+	infos := []types.CloudResourceBasicInfo{
+		{ID: "1", Name: "web-1"},
+		{ID: "2", Name: "db-1"},
+		{ID: "3", Name: "web-2"},
+	}
+
+	cases := []struct {
+		name    string
+		pattern string
+		claimed map[enumor.CloudResourceType]map[string]struct{}
+		expect  []string
+	}{
+		{
+			name:    "match all without name pattern",
+			claimed: map[enumor.CloudResourceType]map[string]struct{}{},
+			expect:  []string{"1", "2", "3"},
+		},
+		{
+			name:    "match by name pattern",
+			pattern: "^web-",
+			claimed: map[enumor.CloudResourceType]map[string]struct{}{},
+			expect:  []string{"1", "3"},
+		},
+		{
+			name:    "skip resources claimed by higher priority rules",
+			pattern: "^web-",
+			claimed: map[enumor.CloudResourceType]map[string]struct{}{enumor.CvmCloudResType: {"1": {}}},
+			expect:  []string{"3"},
+		},
+		{
+			name: "claimed resources of other resource type do not affect",
+			claimed: map[enumor.CloudResourceType]map[string]struct{}{
+				enumor.DiskCloudResType: {"1": {}, "2": {}, "3": {}}},
+			expect: []string{"1", "2", "3"},
+		},
+	}
+
+	for _, c := range cases {
+		var pattern *regexp.Regexp
+		if len(c.pattern) != 0 {
+			pattern = regexp.MustCompile(c.pattern)
+		}
+
+		ids := claimResources(enumor.CvmCloudResType, infos, pattern, c.claimed)
+		if !reflect.DeepEqual(ids, c.expect) {
+			t.Errorf("%s: expect: %v, actual: %v", c.name, c.expect, ids)
+			continue
+		}
+
+		for _, id := range c.expect {
+			if _, exists := c.claimed[enumor.CvmCloudResType][id]; !exists {
+				t.Errorf("%s: resource %s should be marked as claimed", c.name, id)
+			}
+		}
+	}
+}
+
+func TestClaimResourcesByPriority(t *testing.T) {
+	infos := []types.CloudResourceBasicInfo{{ID: "1", Name: "web-1"}, {ID: "2", Name: "db-1"}}
+	claimed := make(map[enumor.CloudResourceType]map[string]struct{})
+
+	// 高优先级规则先匹配，低优先级规则只能分配剩余的资源
+	high := claimResources(enumor.CvmCloudResType, infos, regexp.MustCompile("^web-"), claimed)
+	low := claimResources(enumor.CvmCloudResType, infos, nil, claimed)
+
+	if !reflect.DeepEqual(high, []string{"1"}) || !reflect.DeepEqual(low, []string{"2"}) {
+		t.Errorf("resource should only be claimed by the first matched rule, high: %v, low: %v", high, low)
+	}
+}
+
+func findAtomRule(expr *filter.Expression, field string) *filter.AtomRule {
+	for _, rule := range expr.Rules {
+		atom, ok := rule.(*filter.AtomRule)
+		if ok && atom.Field == field {
+			return atom
+		}
+	}
+	return nil
+}
+
+func TestBuildRuleExpr(t *testing.T) {
+	rule := corecloud.BizAssignRule{
+		Vendor:      enumor.TCloud,
+		AccountID:   "account",
+		Regions:     []string{"ap-guangzhou"},
+		CloudVpcIDs: []string{"vpc-1"},
+		Tags:        []corecloud.TagPair{{Key: "env", Value: "prod"}},
+	}
+
+	cases := []struct {
+		resType  enumor.CloudResourceType
+		vpcField string
+		vpcOp    filter.OpFactory
+	}{
+		{resType: enumor.VpcCloudResType, vpcField: "cloud_id", vpcOp: filter.In.Factory()},
+		{resType: enumor.SubnetCloudResType, vpcField: "cloud_vpc_id", vpcOp: filter.In.Factory()},
+		{resType: enumor.CvmCloudResType, vpcField: "cloud_vpc_ids", vpcOp: filter.JSONOverlaps.Factory()},
+	}
+
+	for _, c := range cases {
+		expr, err := buildRuleExpr(rule, c.resType)
+		if err != nil {
+			t.Errorf("build %s rule expr failed, err: %v", c.resType, err)
+			continue
+		}
+
+		biz := findAtomRule(expr, "bk_biz_id")
+		if biz == nil || biz.Value != constant.UnassignedBiz {
+			t.Errorf("%s rule expr should only match unassigned resources", c.resType)
+		}
+
+		if region := findAtomRule(expr, "region"); region == nil || !reflect.DeepEqual(region.Value, rule.Regions) {
+			t.Errorf("%s rule expr should match regions", c.resType)
+		}
+
+		if vpc := findAtomRule(expr, c.vpcField); vpc == nil || vpc.Op != c.vpcOp {
+			t.Errorf("%s rule expr should match vpc by %s", c.resType, c.vpcField)
+		}
+
+		if tag := findAtomRule(expr, filter.TagRuleField+".env"); tag == nil || tag.Value != "prod" {
+			t.Errorf("%s rule expr should match tags", c.resType)
+		}
+
+		cloudArea := findAtomRule(expr, "bk_cloud_id")
+		if c.resType == enumor.VpcCloudResType && cloudArea == nil {
+			t.Errorf("vpc rule expr without cloud area should only match vpc bound with cloud area")
+		}
+		if c.resType != enumor.VpcCloudResType && cloudArea != nil {
+			t.Errorf("%s rule expr should not match cloud area", c.resType)
+		}
+	}
+
+	if _, err := buildRuleExpr(rule, enumor.DiskCloudResType); err == nil {
+		t.Errorf("disk can not be matched by vpc, build rule expr should fail")
+	}
+
+	if _, err := buildRuleExpr(corecloud.BizAssignRule{}, enumor.RouteTableCloudResType); err == nil {
+		t.Errorf("route table can not be assigned by rule, build rule expr should fail")
+	}
+}
+
+func TestMatchRuleSkipResTypeWithoutVpc(t *testing.T) {
+	rule := corecloud.BizAssignRule{
+		ID:          "rule",
+		Vendor:      enumor.TCloud,
+		AccountID:   "account",
+		CloudVpcIDs: []string{"vpc-1"},
+		ResTypes: []enumor.CloudResourceType{enumor.DiskCloudResType, enumor.EipCloudResType,
+			enumor.SecurityGroupCloudResType},
+	}
+
+	// 没有vpc字段的资源类型被跳过，不会查询资源
+	svc := new(service)
+	matches, err := svc.matchRule(kit.New(), rule, make(map[enumor.CloudResourceType]map[string]struct{}))
+	if err != nil {
+		t.Fatalf("match rule with vpc should skip resource types without vpc, err: %v", err)
+	}
+
+	if len(matches) != 0 {
+		t.Errorf("resource types without vpc should not be matched, matches: %v", matches)
+	}
+}
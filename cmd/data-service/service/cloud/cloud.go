@@ -60,6 +60,11 @@ func InitCloudService(cap *capability.Capability) {
 	h.Add("BatchListResBasicInfo", http.MethodPost, "/cloud/resources/basics/batch/list",
 		svc.BatchListResourceBasicInfo)
 	h.Add("AssignResourceToBiz", http.MethodPost, "/cloud/resources/assign/bizs", svc.AssignResourceToBiz)
+	h.Add("CountResource", http.MethodPost, "/cloud/resources/count", svc.CountResource)
+	h.Add("UpdateResourceSyncStatus", http.MethodPatch, "/cloud/resources/sync_status/update",
+		svc.UpdateResourceSyncStatus)
+	h.Add("ListResBasicInfoByFilter", http.MethodPost, "/cloud/resources/basics/list_by_filter",
+		svc.ListResourceBasicInfoByFilter)
 
 	h.Load(cap.WebService)
 }
@@ -137,6 +142,70 @@ func (svc cloudSvc) ListResourceBasicInfo(cts *rest.Contexts) (interface{}, erro
 	return result, nil
 }
 
+// CountResource count resource by filter.
+func (svc cloudSvc) CountResource(cts *rest.Contexts) (interface{}, error) {
+	req := new(protocloud.CountResourceReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	count, err := svc.dao.Cloud().CountResource(cts.Kit, req.ResourceType, req.Filter)
+	if err != nil {
+		return nil, err
+	}
+
+	return &protocloud.CountResourceResult{Count: count}, nil
+}
+
+// UpdateResourceSyncStatus update cloud resource sync status.
+func (svc cloudSvc) UpdateResourceSyncStatus(cts *rest.Contexts) (interface{}, error) {
+	req := new(protocloud.UpdateResourceSyncStatusReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	expr := &filter.Expression{
+		Op: filter.And,
+		Rules: []filter.RuleFactory{
+			filter.AtomRule{Field: "account_id", Op: filter.Equal.Factory(), Value: req.AccountID},
+			filter.AtomRule{Field: "cloud_id", Op: filter.In.Factory(), Value: req.CloudIDs},
+		},
+	}
+	if err := svc.dao.Cloud().UpdateResourceSyncStatus(cts.Kit, req.ResourceType, expr, req.SyncStatus); err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// ListResourceBasicInfoByFilter list resource basic info by filter.
+func (svc cloudSvc) ListResourceBasicInfoByFilter(cts *rest.Contexts) (interface{}, error) {
+	req := new(protocloud.ListResourceBasicInfoByFilterReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	list, err := svc.dao.Cloud().ListResourceBasicInfoByFilter(cts.Kit, req.ResourceType, req.Filter, req.Page,
+		req.Fields...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &protocloud.ListResourceBasicInfoByFilterResult{Details: list}, nil
+}
+
 // BatchListResourceBasicInfo batch list resource basic info.
 func (svc cloudSvc) BatchListResourceBasicInfo(cts *rest.Contexts) (interface{}, error) {
 	req := new(protocloud.BatchListResourceBasicInfoReq)
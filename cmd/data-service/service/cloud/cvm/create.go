@@ -117,6 +117,11 @@ func batchCreateCvm[T corecvm.Extension](cts *rest.Contexts, svc *cvmSvc, vendor
 			return nil, fmt.Errorf("batch create cvm failed, err: %v", err)
 		}
 
+		err = svc.dao.ResChangeHistory().RecordWithTx(cts.Kit, txn, enumor.CvmCloudResType, enumor.Create, ids)
+		if err != nil {
+			return nil, err
+		}
+
 		// create cmdb cloud hosts
 		// 如果主机同步Cmdb失败，但写入HCM成功，忽略该错误。
 		err = upsertCmdbHosts[T](svc, cts.Kit, vendor, models)
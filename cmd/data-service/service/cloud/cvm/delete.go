@@ -24,6 +24,7 @@ import (
 
 	"hcm/pkg/api/core"
 	protocloud "hcm/pkg/api/data-service/cloud"
+	"hcm/pkg/criteria/enumor"
 	"hcm/pkg/criteria/errf"
 	"hcm/pkg/dal/dao/orm"
 	"hcm/pkg/dal/dao/tools"
@@ -81,11 +82,21 @@ func (svc *cvmSvc) BatchDeleteCvm(cts *rest.Contexts) (interface{}, error) {
 			}
 		}
 
+		err := svc.dao.ResChangeHistory().RecordWithTx(cts.Kit, txn, enumor.CvmCloudResType, enumor.Delete, delIDs)
+		if err != nil {
+			return nil, err
+		}
+
 		delFilter := tools.ContainersExpression("id", delIDs)
 		if err := svc.dao.Cvm().DeleteWithTx(cts.Kit, txn, delFilter); err != nil {
 			return nil, err
 		}
 
+		tagFilter := tools.ResourceTagExpression(enumor.CvmCloudResType, delIDs)
+		if err := svc.dao.ResourceTag().DeleteWithTx(cts.Kit, txn, tagFilter); err != nil {
+			return nil, err
+		}
+
 		// delete cmdb cloud hosts
 		if err = deleteCmdbHosts(svc, cts.Kit, listResp.Details); err != nil {
 			logs.Errorf("delete cmdb hosts failed, err: %v, rid: %s", err, cts.Kit.Rid)
@@ -150,6 +150,11 @@ func batchUpdateCvm[T corecvm.Extension](cts *rest.Contexts, svc *cvmSvc, vendor
 			models = append(models, update)
 		}
 
+		err = svc.dao.ResChangeHistory().RecordWithTx(cts.Kit, txn, enumor.CvmCloudResType, enumor.Update, ids)
+		if err != nil {
+			return nil, err
+		}
+
 		// upsert cmdb cloud hosts
 		err = upsertCmdbHosts[T](svc, cts.Kit, vendor, models)
 		if err != nil {
@@ -206,6 +211,12 @@ func (svc *cvmSvc) BatchUpdateCvmCommonInfo(cts *rest.Contexts) (interface{}, er
 		return nil, err
 	}
 
+	err := svc.dao.ResChangeHistory().Record(cts.Kit, enumor.CvmCloudResType, enumor.Update, req.IDs)
+	if err != nil {
+		logs.Errorf("record cvm change history failed, err: %v, ids: %v, rid: %s", err, req.IDs, cts.Kit.Rid)
+		return nil, err
+	}
+
 	// upsert cmdb cloud hosts
 	opt := &types.ListOption{
 		Filter: updateFilter,
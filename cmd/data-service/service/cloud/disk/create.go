@@ -103,7 +103,17 @@ func batchCreateDiskExt[T coredisk.Extension](
 				Reviser:      cts.Kit.User,
 			}
 		}
-		return dSvc.dao.Disk().BatchCreateWithTx(cts.Kit, txn, disks)
+		ids, err := dSvc.dao.Disk().BatchCreateWithTx(cts.Kit, txn, disks)
+		if err != nil {
+			return nil, err
+		}
+
+		err = dSvc.dao.ResChangeHistory().RecordWithTx(cts.Kit, txn, enumor.DiskCloudResType, enumor.Create, ids)
+		if err != nil {
+			return nil, err
+		}
+
+		return ids, nil
 	})
 	if err != nil {
 		return nil, err
@@ -24,6 +24,7 @@ import (
 
 	"hcm/pkg/api/core"
 	dataproto "hcm/pkg/api/data-service/cloud/disk"
+	"hcm/pkg/criteria/enumor"
 	"hcm/pkg/criteria/errf"
 	"hcm/pkg/dal/dao/orm"
 	"hcm/pkg/dal/dao/tools"
@@ -66,11 +67,21 @@ func (dSvc *diskSvc) BatchDeleteDisk(cts *rest.Contexts) (interface{}, error) {
 	}
 
 	_, err = dSvc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		err := dSvc.dao.ResChangeHistory().RecordWithTx(cts.Kit, txn, enumor.DiskCloudResType, enumor.Delete, delIDs)
+		if err != nil {
+			return nil, err
+		}
+
 		delFilter := tools.ContainersExpression("id", delIDs)
 		if err := dSvc.dao.Disk().DeleteWithTx(cts.Kit, txn, delFilter); err != nil {
 			return nil, err
 		}
 
+		tagFilter := tools.ResourceTagExpression(enumor.DiskCloudResType, delIDs)
+		if err := dSvc.dao.ResourceTag().DeleteWithTx(cts.Kit, txn, tagFilter); err != nil {
+			return nil, err
+		}
+
 		return nil, nil
 	})
 	if err != nil {
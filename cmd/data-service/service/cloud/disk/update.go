@@ -32,6 +32,7 @@ import (
 	"hcm/pkg/dal/dao/types"
 	tablecloud "hcm/pkg/dal/table/cloud/disk"
 	tabletype "hcm/pkg/dal/table/types"
+	"hcm/pkg/logs"
 	"hcm/pkg/rest"
 	"hcm/pkg/runtime/filter"
 	"hcm/pkg/tools/json"
@@ -79,6 +80,12 @@ func (dSvc *diskSvc) BatchUpdateDisk(cts *rest.Contexts) (interface{}, error) {
 	if err := dSvc.dao.Disk().Update(cts.Kit, tools.ContainersExpression("id", req.IDs), updateData); err != nil {
 		return nil, err
 	}
+
+	if err := dSvc.dao.ResChangeHistory().Record(cts.Kit, enumor.DiskCloudResType, enumor.Update, req.IDs); err != nil {
+		logs.Errorf("record disk change history failed, err: %v, ids: %v, rid: %s", err, req.IDs, cts.Kit.Rid)
+		return nil, err
+	}
+
 	return nil, nil
 }
 
@@ -130,6 +137,13 @@ func batchUpdateDiskExt[T coredisk.Extension](cts *rest.Contexts,
 				return nil, fmt.Errorf("update disk failed, err: %v", err)
 			}
 		}
+
+		err := dSvc.dao.ResChangeHistory().RecordWithTx(cts.Kit, txn, enumor.DiskCloudResType, enumor.Update,
+			queryIDs)
+		if err != nil {
+			return nil, err
+		}
+
 		return nil, nil
 	})
 	if err != nil {
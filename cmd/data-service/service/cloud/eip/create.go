@@ -96,7 +96,17 @@ func batchCreateEipExt[T dataproto.EipExtensionCreateReq](
 				Reviser:   cts.Kit.User,
 			}
 		}
-		return svc.dao.Eip().BatchCreateWithTx(cts.Kit, txn, eips)
+		ids, err := svc.dao.Eip().BatchCreateWithTx(cts.Kit, txn, eips)
+		if err != nil {
+			return nil, err
+		}
+
+		err = svc.dao.ResChangeHistory().RecordWithTx(cts.Kit, txn, enumor.EipCloudResType, enumor.Create, ids)
+		if err != nil {
+			return nil, err
+		}
+
+		return ids, nil
 	})
 	if err != nil {
 		return nil, err
@@ -20,10 +20,19 @@
 package eip
 
 import (
+	"fmt"
+
+	"hcm/pkg/api/core"
 	dataproto "hcm/pkg/api/data-service/cloud/eip"
+	"hcm/pkg/criteria/enumor"
 	"hcm/pkg/criteria/errf"
 	"hcm/pkg/dal/dao/orm"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/dal/dao/types"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
 	"hcm/pkg/rest"
+	"hcm/pkg/runtime/filter"
 
 	"github.com/jmoiron/sqlx"
 )
@@ -39,11 +48,63 @@ func (svc *eipSvc) BatchDeleteEip(cts *rest.Contexts) (interface{}, error) {
 		return nil, errf.NewFromErr(errf.InvalidParameter, err)
 	}
 
-	_, err := svc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
-		return nil, svc.dao.Eip().DeleteWithTx(cts.Kit, txn, req.Filter)
+	delIDs, err := svc.listEipID(cts.Kit, req.Filter)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = svc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		err := svc.dao.ResChangeHistory().RecordWithTx(cts.Kit, txn, enumor.EipCloudResType, enumor.Delete, delIDs)
+		if err != nil {
+			return nil, err
+		}
+
+		if err = svc.dao.Eip().DeleteWithTx(cts.Kit, txn, req.Filter); err != nil {
+			return nil, err
+		}
+
+		if len(delIDs) == 0 {
+			return nil, nil
+		}
+
+		tagFilter := tools.ResourceTagExpression(enumor.EipCloudResType, delIDs)
+		if err = svc.dao.ResourceTag().DeleteWithTx(cts.Kit, txn, tagFilter); err != nil {
+			return nil, err
+		}
+
+		return nil, nil
 	})
 	if err != nil {
 		return nil, err
 	}
 	return nil, nil
 }
+
+// listEipID 查询需要删除的 eip id，用于记录变更历史。
+func (svc *eipSvc) listEipID(kt *kit.Kit, expr *filter.Expression) ([]string, error) {
+	ids := make([]string, 0)
+	opt := &types.ListOption{
+		Fields: []string{"id"},
+		Filter: expr,
+		Page:   core.NewDefaultBasePage(),
+	}
+	for {
+		result, err := svc.dao.Eip().List(kt, opt)
+		if err != nil {
+			logs.Errorf("list eip failed, err: %v, rid: %s", err, kt.Rid)
+			return nil, fmt.Errorf("list eip failed, err: %v", err)
+		}
+
+		for _, one := range result.Details {
+			ids = append(ids, one.ID)
+		}
+
+		if uint(len(result.Details)) < opt.Page.Limit {
+			break
+		}
+
+		opt.Page.Start += uint32(opt.Page.Limit)
+	}
+
+	return ids, nil
+}
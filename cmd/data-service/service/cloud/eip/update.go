@@ -31,6 +31,7 @@ import (
 	"hcm/pkg/dal/dao/types"
 	tablecloud "hcm/pkg/dal/table/cloud/eip"
 	tabletype "hcm/pkg/dal/table/types"
+	"hcm/pkg/logs"
 	"hcm/pkg/rest"
 	"hcm/pkg/runtime/filter"
 	"hcm/pkg/tools/json"
@@ -77,6 +78,12 @@ func (svc *eipSvc) BatchUpdateEip(cts *rest.Contexts) (interface{}, error) {
 	if err := svc.dao.Eip().Update(cts.Kit, tools.ContainersExpression("id", req.IDs), updateData); err != nil {
 		return nil, err
 	}
+
+	if err := svc.dao.ResChangeHistory().Record(cts.Kit, enumor.EipCloudResType, enumor.Update, req.IDs); err != nil {
+		logs.Errorf("record eip change history failed, err: %v, ids: %v, rid: %s", err, req.IDs, cts.Kit.Rid)
+		return nil, err
+	}
+
 	return nil, nil
 }
 
@@ -123,6 +130,12 @@ func batchUpdateEipExt[T dataproto.EipExtensionUpdateReq](cts *rest.Contexts, sv
 				return nil, fmt.Errorf("update eip failed, err: %v", err)
 			}
 		}
+
+		err := svc.dao.ResChangeHistory().RecordWithTx(cts.Kit, txn, enumor.EipCloudResType, enumor.Update, queryIDs)
+		if err != nil {
+			return nil, err
+		}
+
 		return nil, nil
 	})
 	if err != nil {
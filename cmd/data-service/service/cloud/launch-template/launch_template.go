@@ -0,0 +1,384 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+package launchtemplate
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"hcm/pkg/api/core"
+	corecloud "hcm/pkg/api/core/cloud"
+	dataservice "hcm/pkg/api/data-service"
+	protocloud "hcm/pkg/api/data-service/cloud"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/orm"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/dal/dao/types"
+	tableaudit "hcm/pkg/dal/table/audit"
+	tablecloud "hcm/pkg/dal/table/cloud"
+	tabletype "hcm/pkg/dal/table/types"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+	"hcm/pkg/tools/converter"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// CreateLaunchTemplate create launch template with the first version.
+func (svc *service) CreateLaunchTemplate(cts *rest.Contexts) (interface{}, error) {
+	req := new(protocloud.LaunchTemplateCreateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	template := tablecloud.LaunchTemplateTable{
+		Name:          req.Name,
+		Vendor:        req.Vendor,
+		BkBizID:       req.BkBizID,
+		AccountID:     req.AccountID,
+		Region:        req.Region,
+		LatestVersion: 1,
+		Memo:          converter.ValToPtr(req.Memo),
+		Creator:       cts.Kit.User,
+		Reviser:       cts.Kit.User,
+	}
+
+	id, err := svc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		ids, err := svc.dao.LaunchTemplate().BatchCreateWithTx(cts.Kit, txn,
+			[]tablecloud.LaunchTemplateTable{template})
+		if err != nil {
+			return nil, err
+		}
+
+		version := tablecloud.LaunchTemplateVersionTable{
+			TemplateID: ids[0],
+			Version:    template.LatestVersion,
+			Spec:       tabletype.JsonField(req.Spec),
+			Memo:       converter.ValToPtr(""),
+			Creator:    cts.Kit.User,
+		}
+		if _, err = svc.dao.LaunchTemplateVersion().BatchCreateWithTx(cts.Kit, txn,
+			[]tablecloud.LaunchTemplateVersionTable{version}); err != nil {
+			return nil, err
+		}
+
+		template.ID = ids[0]
+		detail := &tableaudit.BasicDetail{
+			Data: map[string]interface{}{
+				"name":       req.Name,
+				"bk_biz_id":  req.BkBizID,
+				"account_id": req.AccountID,
+				"region":     req.Region,
+				"version":    version.Version,
+				"spec":       req.Spec,
+				"memo":       req.Memo,
+			},
+		}
+		audit := genAudit(cts.Kit, &template, enumor.Create, detail)
+		if err = svc.dao.Audit().BatchCreateWithTx(cts.Kit, txn, []*tableaudit.AuditTable{audit}); err != nil {
+			return nil, err
+		}
+
+		return ids[0], nil
+	})
+	if err != nil {
+		logs.Errorf("create launch template failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	createdID, ok := id.(string)
+	if !ok {
+		return nil, fmt.Errorf("create launch template but return id type is not string, id type: %T", id)
+	}
+
+	return &core.CreateResult{ID: createdID}, nil
+}
+
+// UpdateLaunchTemplate update launch template, 配置变更时生成新的版本。
+func (svc *service) UpdateLaunchTemplate(cts *rest.Contexts) (interface{}, error) {
+	id := cts.PathParameter("id").String()
+	if len(id) == 0 {
+		return nil, errf.New(errf.InvalidParameter, "id is required")
+	}
+
+	req := new(protocloud.LaunchTemplateUpdateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	template, err := svc.getTemplate(cts.Kit, id)
+	if err != nil {
+		return nil, err
+	}
+
+	model := &tablecloud.LaunchTemplateTable{
+		Name:    req.Name,
+		Memo:    req.Memo,
+		Reviser: cts.Kit.User,
+	}
+
+	changed := make(map[string]interface{})
+	if len(req.Name) != 0 {
+		changed["name"] = req.Name
+	}
+	if req.Memo != nil {
+		changed["memo"] = *req.Memo
+	}
+
+	var version *tablecloud.LaunchTemplateVersionTable
+	if len(req.Spec) != 0 {
+		version = &tablecloud.LaunchTemplateVersionTable{
+			TemplateID: id,
+			Version:    template.LatestVersion + 1,
+			Spec:       tabletype.JsonField(req.Spec),
+			Memo:       converter.ValToPtr(req.VersionMemo),
+			Creator:    cts.Kit.User,
+		}
+		model.LatestVersion = version.Version
+		changed["latest_version"] = version.Version
+		changed["spec"] = req.Spec
+	}
+
+	_, err = svc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		if version != nil {
+			// 模版id和版本号有唯一索引，并发更新时只有一个请求能够生成新版本
+			if _, err := svc.dao.LaunchTemplateVersion().BatchCreateWithTx(cts.Kit, txn,
+				[]tablecloud.LaunchTemplateVersionTable{*version}); err != nil {
+				return nil, err
+			}
+		}
+
+		if err := svc.dao.LaunchTemplate().UpdateByIDWithTx(cts.Kit, txn, id, model); err != nil {
+			return nil, err
+		}
+
+		detail := &tableaudit.BasicDetail{Data: convLaunchTemplate(template), Changed: changed}
+		audit := genAudit(cts.Kit, template, enumor.Update, detail)
+		return nil, svc.dao.Audit().BatchCreateWithTx(cts.Kit, txn, []*tableaudit.AuditTable{audit})
+	})
+	if err != nil {
+		logs.Errorf("update launch template failed, err: %v, id: %s, rid: %s", err, id, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// ListLaunchTemplate list launch template.
+func (svc *service) ListLaunchTemplate(cts *rest.Contexts) (interface{}, error) {
+	req := new(core.ListReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	opt := &types.ListOption{
+		Filter: req.Filter,
+		Page:   req.Page,
+		Fields: req.Fields,
+	}
+	result, err := svc.dao.LaunchTemplate().List(cts.Kit, opt)
+	if err != nil {
+		logs.Errorf("list launch template failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, fmt.Errorf("list launch template failed, err: %v", err)
+	}
+
+	if req.Page.Count {
+		return &protocloud.LaunchTemplateListResult{Count: result.Count}, nil
+	}
+
+	details := make([]corecloud.LaunchTemplate, 0, len(result.Details))
+	for _, one := range result.Details {
+		details = append(details, *convLaunchTemplate(&one))
+	}
+
+	return &protocloud.LaunchTemplateListResult{Details: details}, nil
+}
+
+// BatchDeleteLaunchTemplate batch delete launch template and all of its versions.
+func (svc *service) BatchDeleteLaunchTemplate(cts *rest.Contexts) (interface{}, error) {
+	req := new(dataservice.BatchDeleteReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	opt := &types.ListOption{
+		Filter: req.Filter,
+		Page:   core.NewDefaultBasePage(),
+	}
+	result, err := svc.dao.LaunchTemplate().List(cts.Kit, opt)
+	if err != nil {
+		logs.Errorf("list launch template failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	if len(result.Details) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]string, 0, len(result.Details))
+	audits := make([]*tableaudit.AuditTable, 0, len(result.Details))
+	for index := range result.Details {
+		one := &result.Details[index]
+		ids = append(ids, one.ID)
+		detail := &tableaudit.BasicDetail{Data: convLaunchTemplate(one)}
+		audits = append(audits, genAudit(cts.Kit, one, enumor.Delete, detail))
+	}
+
+	_, err = svc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		if err := svc.dao.LaunchTemplateVersion().DeleteWithTx(cts.Kit, txn,
+			tools.ContainersExpression("template_id", ids)); err != nil {
+			return nil, err
+		}
+
+		if err := svc.dao.LaunchTemplate().DeleteWithTx(cts.Kit, txn,
+			tools.ContainersExpression("id", ids)); err != nil {
+			return nil, err
+		}
+
+		return nil, svc.dao.Audit().BatchCreateWithTx(cts.Kit, txn, audits)
+	})
+	if err != nil {
+		logs.Errorf("batch delete launch template failed, err: %v, ids: %v, rid: %s", err, ids, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// ListLaunchTemplateVersion list launch template version.
+func (svc *service) ListLaunchTemplateVersion(cts *rest.Contexts) (interface{}, error) {
+	req := new(core.ListReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	opt := &types.ListOption{
+		Filter: req.Filter,
+		Page:   req.Page,
+		Fields: req.Fields,
+	}
+	result, err := svc.dao.LaunchTemplateVersion().List(cts.Kit, opt)
+	if err != nil {
+		logs.Errorf("list launch template version failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, fmt.Errorf("list launch template version failed, err: %v", err)
+	}
+
+	if req.Page.Count {
+		return &protocloud.LaunchTemplateVersionListResult{Count: result.Count}, nil
+	}
+
+	details := make([]corecloud.LaunchTemplateVersion, 0, len(result.Details))
+	for _, one := range result.Details {
+		details = append(details, *convLaunchTemplateVersion(&one))
+	}
+
+	return &protocloud.LaunchTemplateVersionListResult{Details: details}, nil
+}
+
+func (svc *service) getTemplate(kt *kit.Kit, id string) (*tablecloud.LaunchTemplateTable, error) {
+	opt := &types.ListOption{
+		Filter: tools.EqualExpression("id", id),
+		Page:   core.NewDefaultBasePage(),
+	}
+	result, err := svc.dao.LaunchTemplate().List(kt, opt)
+	if err != nil {
+		logs.Errorf("list launch template failed, err: %v, id: %s, rid: %s", err, id, kt.Rid)
+		return nil, err
+	}
+
+	if len(result.Details) == 0 {
+		return nil, errf.Newf(errf.RecordNotFound, "launch template: %s not found", id)
+	}
+
+	return &result.Details[0], nil
+}
+
+func genAudit(kt *kit.Kit, template *tablecloud.LaunchTemplateTable, action enumor.AuditAction,
+	detail *tableaudit.BasicDetail) *tableaudit.AuditTable {
+
+	return &tableaudit.AuditTable{
+		ResID:     template.ID,
+		ResName:   template.Name,
+		ResType:   enumor.LaunchTemplateAuditResType,
+		Action:    action,
+		BkBizID:   template.BkBizID,
+		Vendor:    template.Vendor,
+		AccountID: template.AccountID,
+		Operator:  kt.User,
+		Source:    kt.GetRequestSource(),
+		Rid:       kt.Rid,
+		AppCode:   kt.AppCode,
+		Detail:    detail,
+	}
+}
+
+func convLaunchTemplate(one *tablecloud.LaunchTemplateTable) *corecloud.LaunchTemplate {
+	return &corecloud.LaunchTemplate{
+		ID:            one.ID,
+		Name:          one.Name,
+		Vendor:        one.Vendor,
+		BkBizID:       one.BkBizID,
+		AccountID:     one.AccountID,
+		Region:        one.Region,
+		LatestVersion: one.LatestVersion,
+		Memo:          converter.PtrToVal(one.Memo),
+		Revision: &core.Revision{
+			Creator:   one.Creator,
+			Reviser:   one.Reviser,
+			CreatedAt: one.CreatedAt.String(),
+			UpdatedAt: one.UpdatedAt.String(),
+		},
+	}
+}
+
+func convLaunchTemplateVersion(one *tablecloud.LaunchTemplateVersionTable) *corecloud.LaunchTemplateVersion {
+	return &corecloud.LaunchTemplateVersion{
+		ID:         one.ID,
+		TemplateID: one.TemplateID,
+		Version:    one.Version,
+		Spec:       json.RawMessage(one.Spec),
+		Memo:       converter.PtrToVal(one.Memo),
+		CreatedRevision: &core.CreatedRevision{
+			Creator:   one.Creator,
+			CreatedAt: one.CreatedAt.String(),
+		},
+	}
+}
@@ -0,0 +1,167 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+package reschangehistory
+
+import (
+	"fmt"
+	"time"
+
+	"hcm/pkg/api/core"
+	corecloud "hcm/pkg/api/core/cloud"
+	protocloud "hcm/pkg/api/data-service/cloud"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/orm"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/dal/dao/types"
+	tablecloud "hcm/pkg/dal/table/cloud"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// ListResChangeHistory list resource change history.
+func (svc *service) ListResChangeHistory(cts *rest.Contexts) (interface{}, error) {
+	req := new(core.ListReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	opt := &types.ListOption{
+		Filter: req.Filter,
+		Page:   req.Page,
+		Fields: req.Fields,
+	}
+	result, err := svc.dao.ResChangeHistory().List(cts.Kit, opt)
+	if err != nil {
+		logs.Errorf("list resource change history failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, fmt.Errorf("list resource change history failed, err: %v", err)
+	}
+
+	if req.Page.Count {
+		return &protocloud.ResChangeHistoryListResult{Count: result.Count}, nil
+	}
+
+	return &protocloud.ResChangeHistoryListResult{Details: convResChangeHistory(result.Details)}, nil
+}
+
+// ListResChangeHistoryAsOf list the snapshot of resources as of a timestamp.
+func (svc *service) ListResChangeHistoryAsOf(cts *rest.Contexts) (interface{}, error) {
+	req := new(protocloud.ResChangeHistoryAsOfListReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	opt := &types.ListOption{
+		Filter: req.Filter,
+		Page:   req.Page,
+		Fields: req.Fields,
+	}
+	result, err := svc.dao.ResChangeHistory().ListAsOf(cts.Kit, req.ResType, req.AsOf, opt)
+	if err != nil {
+		logs.Errorf("list %s as of %s failed, err: %v, rid: %s", req.ResType, req.AsOf, err, cts.Kit.Rid)
+		return nil, fmt.Errorf("list %s as of %s failed, err: %v", req.ResType, req.AsOf, err)
+	}
+
+	if req.Page.Count {
+		return &protocloud.ResChangeHistoryListResult{Count: result.Count}, nil
+	}
+
+	return &protocloud.ResChangeHistoryListResult{Details: convResChangeHistory(result.Details)}, nil
+}
+
+func convResChangeHistory(details []tablecloud.ResChangeHistoryTable) []corecloud.ResChangeHistory {
+	result := make([]corecloud.ResChangeHistory, 0, len(details))
+	for _, one := range details {
+		result = append(result, corecloud.ResChangeHistory{
+			ID:            one.ID,
+			ResType:       one.ResType,
+			ResID:         one.ResID,
+			CloudID:       one.CloudID,
+			Vendor:        one.Vendor,
+			AccountID:     one.AccountID,
+			BkBizID:       one.BkBizID,
+			Version:       one.Version,
+			Action:        one.Action,
+			Snapshot:      one.Snapshot,
+			ChangedFields: one.ChangedFields,
+			Rid:           one.Rid,
+			Creator:       one.Creator,
+			CreatedAt:     one.CreatedAt.String(),
+		})
+	}
+
+	return result
+}
+
+// DeleteExpiredResChangeHistory delete expired resource change history, 分批删除直到没有过期的历史版本。
+func (svc *service) DeleteExpiredResChangeHistory(cts *rest.Contexts) (interface{}, error) {
+	req := new(protocloud.ResChangeHistoryDeleteExpiredReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	before, err := time.Parse(constant.TimeStdFormat, req.Before)
+	if err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	deleted := uint64(0)
+	for {
+		ids, err := svc.dao.ResChangeHistory().ListExpiredID(cts.Kit, before, core.DefaultMaxPageLimit)
+		if err != nil {
+			logs.Errorf("list expired resource change history failed, err: %v, before: %s, rid: %s", err,
+				req.Before, cts.Kit.Rid)
+			return nil, err
+		}
+
+		if len(ids) == 0 {
+			break
+		}
+
+		_, err = svc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+			return nil, svc.dao.ResChangeHistory().DeleteWithTx(cts.Kit, txn, tools.ContainersExpression("id", ids))
+		})
+		if err != nil {
+			logs.Errorf("delete expired resource change history failed, err: %v, ids: %v, rid: %s", err, ids,
+				cts.Kit.Rid)
+			return nil, err
+		}
+
+		deleted += uint64(len(ids))
+		if len(ids) < int(core.DefaultMaxPageLimit) {
+			break
+		}
+	}
+
+	return &protocloud.ResChangeHistoryDeleteExpiredResult{DeletedCount: deleted}, nil
+}
@@ -0,0 +1,281 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package resourcetag
+
+import (
+	"fmt"
+
+	"hcm/pkg/api/core"
+	corecloud "hcm/pkg/api/core/cloud"
+	protocloud "hcm/pkg/api/data-service/cloud"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/orm"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/dal/dao/types"
+	tablecloud "hcm/pkg/dal/table/cloud"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+	"hcm/pkg/runtime/filter"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// ListResourceTag list resource tag.
+func (svc *service) ListResourceTag(cts *rest.Contexts) (interface{}, error) {
+	req := new(core.ListReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	opt := &types.ListOption{
+		Filter: req.Filter,
+		Page:   req.Page,
+		Fields: req.Fields,
+	}
+	result, err := svc.dao.ResourceTag().List(cts.Kit, opt)
+	if err != nil {
+		logs.Errorf("list resource tag failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, fmt.Errorf("list resource tag failed, err: %v", err)
+	}
+
+	if req.Page.Count {
+		return &protocloud.ResourceTagListResult{Count: result.Count}, nil
+	}
+
+	details := make([]corecloud.ResourceTag, 0, len(result.Details))
+	for _, one := range result.Details {
+		details = append(details, corecloud.ResourceTag{
+			ID:         one.ID,
+			Vendor:     one.Vendor,
+			AccountID:  one.AccountID,
+			ResType:    one.ResType,
+			ResID:      one.ResID,
+			ResCloudID: one.ResCloudID,
+			TagKey:     one.TagKey,
+			TagValue:   one.TagValue,
+			Revision: &core.Revision{
+				Creator:   one.Creator,
+				Reviser:   one.Reviser,
+				CreatedAt: one.CreatedAt.String(),
+				UpdatedAt: one.UpdatedAt.String(),
+			},
+		})
+	}
+
+	return &protocloud.ResourceTagListResult{Details: details}, nil
+}
+
+// SyncResourceTag sync resource tag, the tags of the resources will be overwritten by the tags from cloud.
+func (svc *service) SyncResourceTag(cts *rest.Contexts) (interface{}, error) {
+	req := new(protocloud.ResourceTagSyncReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	cloudIDs := make([]string, 0, len(req.Resources))
+	for _, one := range req.Resources {
+		cloudIDs = append(cloudIDs, one.ResCloudID)
+	}
+
+	cloudIDMap, err := svc.dao.Cloud().ListResourceCloudIDMap(cts.Kit, req.ResType, req.AccountID, cloudIDs)
+	if err != nil {
+		logs.Errorf("list resource cloud id map failed, err: %v, res type: %s, rid: %s", err, req.ResType,
+			cts.Kit.Rid)
+		return nil, err
+	}
+
+	resIDs := make([]string, 0, len(cloudIDMap))
+	models := make([]tablecloud.ResourceTagTable, 0)
+	for _, one := range req.Resources {
+		resID, exist := cloudIDMap[one.ResCloudID]
+		if !exist {
+			logs.Warnf("sync %s tag but resource(%s) not found in db, skip it, rid: %s", req.ResType, one.ResCloudID,
+				cts.Kit.Rid)
+			continue
+		}
+		resIDs = append(resIDs, resID)
+
+		for _, tag := range one.Tags {
+			models = append(models, tablecloud.ResourceTagTable{
+				Vendor:     req.Vendor,
+				AccountID:  req.AccountID,
+				ResType:    req.ResType,
+				ResID:      resID,
+				ResCloudID: one.ResCloudID,
+				TagKey:     tag.Key,
+				TagValue:   tag.Value,
+				Creator:    cts.Kit.User,
+				Reviser:    cts.Kit.User,
+			})
+		}
+	}
+
+	if len(resIDs) == 0 {
+		return nil, nil
+	}
+
+	_, err = svc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		if err := svc.dao.ResourceTag().DeleteWithTx(cts.Kit, txn, resTagExpr(req.ResType, resIDs)); err != nil {
+			return nil, err
+		}
+
+		if len(models) == 0 {
+			return nil, nil
+		}
+
+		if _, err := svc.dao.ResourceTag().BatchCreateWithTx(cts.Kit, txn, models); err != nil {
+			return nil, err
+		}
+
+		return nil, nil
+	})
+	if err != nil {
+		logs.Errorf("sync resource tag failed, err: %v, res type: %s, rid: %s", err, req.ResType, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// BatchUpsertResourceTag batch upsert resource tag.
+func (svc *service) BatchUpsertResourceTag(cts *rest.Contexts) (interface{}, error) {
+	req := new(protocloud.ResourceTagBatchUpsertReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	models, err := svc.buildResourceTagModels(cts.Kit, req)
+	if err != nil {
+		return nil, err
+	}
+
+	tagKeys := make([]string, 0, len(req.Tags))
+	for _, tag := range req.Tags {
+		tagKeys = append(tagKeys, tag.Key)
+	}
+
+	_, err = svc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		tagKeyRule := &filter.AtomRule{Field: "tag_key", Op: filter.In.Factory(), Value: tagKeys}
+		expr := resTagExpr(req.ResType, req.ResIDs, tagKeyRule)
+		if err := svc.dao.ResourceTag().DeleteWithTx(cts.Kit, txn, expr); err != nil {
+			return nil, err
+		}
+
+		if _, err := svc.dao.ResourceTag().BatchCreateWithTx(cts.Kit, txn, models); err != nil {
+			return nil, err
+		}
+
+		return nil, nil
+	})
+	if err != nil {
+		logs.Errorf("batch upsert resource tag failed, err: %v, req: %+v, rid: %s", err, req, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+func (svc *service) buildResourceTagModels(kt *kit.Kit, req *protocloud.ResourceTagBatchUpsertReq) (
+	[]tablecloud.ResourceTagTable, error) {
+
+	infos, err := svc.dao.Cloud().ListResourceBasicInfo(kt, req.ResType, req.ResIDs, "id", "vendor", "account_id",
+		"cloud_id")
+	if err != nil {
+		logs.Errorf("list resource basic info failed, err: %v, res type: %s, ids: %v, rid: %s", err, req.ResType,
+			req.ResIDs, kt.Rid)
+		return nil, err
+	}
+
+	if len(infos) != len(req.ResIDs) {
+		return nil, errf.Newf(errf.RecordNotFound, "some %s of %v not found", req.ResType, req.ResIDs)
+	}
+
+	models := make([]tablecloud.ResourceTagTable, 0, len(infos)*len(req.Tags))
+	for _, info := range infos {
+		for _, tag := range req.Tags {
+			models = append(models, tablecloud.ResourceTagTable{
+				Vendor:     info.Vendor,
+				AccountID:  info.AccountID,
+				ResType:    req.ResType,
+				ResID:      info.ID,
+				ResCloudID: info.CloudID,
+				TagKey:     tag.Key,
+				TagValue:   tag.Value,
+				Creator:    kt.User,
+				Reviser:    kt.User,
+			})
+		}
+	}
+
+	return models, nil
+}
+
+// BatchDeleteResourceTag batch delete resource tag.
+func (svc *service) BatchDeleteResourceTag(cts *rest.Contexts) (interface{}, error) {
+	req := new(protocloud.ResourceTagBatchDeleteReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	rules := make([]filter.RuleFactory, 0)
+	if len(req.TagKeys) != 0 {
+		rules = append(rules, &filter.AtomRule{Field: "tag_key", Op: filter.In.Factory(), Value: req.TagKeys})
+	}
+
+	_, err := svc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		expr := resTagExpr(req.ResType, req.ResIDs, rules...)
+		if err := svc.dao.ResourceTag().DeleteWithTx(cts.Kit, txn, expr); err != nil {
+			return nil, err
+		}
+
+		return nil, nil
+	})
+	if err != nil {
+		logs.Errorf("batch delete resource tag failed, err: %v, req: %+v, rid: %s", err, req, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// resTagExpr 生成指定资源的标签查询条件。
+func resTagExpr(resType enumor.CloudResourceType, resIDs []string, rules ...filter.RuleFactory) *filter.Expression {
+	expr := tools.ResourceTagExpression(resType, resIDs)
+	expr.Rules = append(expr.Rules, rules...)
+	return expr
+}
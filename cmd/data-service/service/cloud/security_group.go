@@ -205,6 +205,12 @@ func (svc *securityGroupSvc) BatchDeleteSecurityGroup(cts *rest.Contexts) (inter
 			return nil, err
 		}
 
+		err := svc.dao.ResChangeHistory().RecordWithTx(cts.Kit, txn, enumor.SecurityGroupCloudResType, enumor.Delete,
+			delIDs)
+		if err != nil {
+			return nil, err
+		}
+
 		delFilter := tools.ContainersExpression("id", delIDs)
 		if err := svc.dao.SecurityGroup().DeleteWithTx(cts.Kit, txn, delFilter); err != nil {
 			return nil, err
@@ -367,6 +373,12 @@ func batchUpdateSecurityGroup[T corecloud.SecurityGroupExtension](cts *rest.Cont
 			}
 		}
 
+		err := svc.dao.ResChangeHistory().RecordWithTx(cts.Kit, txn, enumor.SecurityGroupCloudResType, enumor.Update,
+			ids)
+		if err != nil {
+			return nil, err
+		}
+
 		return nil, nil
 	})
 	if err != nil {
@@ -457,6 +469,12 @@ func batchCreateSecurityGroup[T corecloud.SecurityGroupExtension](vendor enumor.
 			return nil, fmt.Errorf("create security group failed, err: %v", err)
 		}
 
+		err = svc.dao.ResChangeHistory().RecordWithTx(cts.Kit, txn, enumor.SecurityGroupCloudResType, enumor.Create,
+			ids)
+		if err != nil {
+			return nil, err
+		}
+
 		return ids, nil
 	})
 	if err != nil {
@@ -491,6 +509,13 @@ func (svc *securityGroupSvc) BatchUpdateSecurityGroupCommonInfo(cts *rest.Contex
 		return nil, err
 	}
 
+	err := svc.dao.ResChangeHistory().Record(cts.Kit, enumor.SecurityGroupCloudResType, enumor.Update, req.IDs)
+	if err != nil {
+		logs.Errorf("record security group change history failed, err: %v, ids: %v, rid: %s", err, req.IDs,
+			cts.Kit.Rid)
+		return nil, err
+	}
+
 	return nil, nil
 }
 
@@ -150,6 +150,11 @@ func batchCreateSubnet[T protocloud.SubnetCreateExtension](cts *rest.Contexts, v
 			return nil, fmt.Errorf("create subnet failed, err: %v", err)
 		}
 
+		err = svc.dao.ResChangeHistory().RecordWithTx(cts.Kit, txn, enumor.SubnetCloudResType, enumor.Create, subnetID)
+		if err != nil {
+			return nil, err
+		}
+
 		return subnetID, nil
 	})
 
@@ -255,6 +260,12 @@ func batchUpdateSubnet[T protocloud.SubnetUpdateExtension](cts *rest.Contexts, s
 			return nil, fmt.Errorf("update subnet failed, err: %v", err)
 		}
 	}
+
+	if err = svc.dao.ResChangeHistory().Record(cts.Kit, enumor.SubnetCloudResType, enumor.Update, ids); err != nil {
+		logs.Errorf("record subnet change history failed, err: %v, ids: %v, rid: %s", err, ids, cts.Kit.Rid)
+		return nil, err
+	}
+
 	return nil, nil
 }
 
@@ -315,6 +326,11 @@ func (svc *subnetSvc) BatchUpdateSubnetBaseInfo(cts *rest.Contexts) (interface{}
 
 	}
 
+	if err = svc.dao.ResChangeHistory().Record(cts.Kit, enumor.SubnetCloudResType, enumor.Update, ids); err != nil {
+		logs.Errorf("record subnet change history failed, err: %v, ids: %v, rid: %s", err, ids, cts.Kit.Rid)
+		return nil, err
+	}
+
 	return nil, nil
 }
 
@@ -480,11 +496,22 @@ func (svc *subnetSvc) BatchDeleteSubnet(cts *rest.Contexts) (interface{}, error)
 	}
 
 	_, err = svc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		err := svc.dao.ResChangeHistory().RecordWithTx(cts.Kit, txn, enumor.SubnetCloudResType, enumor.Delete,
+			delSubnetIDs)
+		if err != nil {
+			return nil, err
+		}
+
 		delSubnetFilter := tools.ContainersExpression("id", delSubnetIDs)
 		if err := svc.dao.Subnet().BatchDeleteWithTx(cts.Kit, txn, delSubnetFilter); err != nil {
 			return nil, err
 		}
 
+		tagFilter := tools.ResourceTagExpression(enumor.SubnetCloudResType, delSubnetIDs)
+		if err := svc.dao.ResourceTag().DeleteWithTx(cts.Kit, txn, tagFilter); err != nil {
+			return nil, err
+		}
+
 		return nil, nil
 	})
 	if err != nil {
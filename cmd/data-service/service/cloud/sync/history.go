@@ -0,0 +1,173 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package sync
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+
+	"hcm/pkg/api/core"
+	coresync "hcm/pkg/api/core/cloud/sync"
+	dssync "hcm/pkg/api/data-service/cloud/sync"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/orm"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/dal/dao/types"
+	tablesync "hcm/pkg/dal/table/cloud/sync"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// BatchCreateAccountSyncHistory create account sync history.
+func (svc *service) BatchCreateAccountSyncHistory(cts *rest.Contexts) (interface{}, error) {
+	req := new(dssync.HistoryCreateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	historyIDs, err := svc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		models := make([]tablesync.AccountSyncHistoryTable, 0, len(req.Items))
+		for _, item := range req.Items {
+			models = append(models, tablesync.AccountSyncHistoryTable{
+				Vendor:           item.Vendor,
+				AccountID:        item.AccountID,
+				Region:           item.Region,
+				ResName:          item.ResName,
+				SyncRid:          item.SyncRid,
+				Status:           string(item.Status),
+				CreatedCount:     item.CreatedCount,
+				UpdatedCount:     item.UpdatedCount,
+				DeletedCount:     item.DeletedCount,
+				QuarantinedCount: item.QuarantinedCount,
+				CloudApiCount:    item.CloudApiCount,
+				CostMs:           item.CostMs,
+				StartTime:        item.StartTime,
+				FailedReason:     item.FailedReason,
+				Creator:          cts.Kit.User,
+			})
+		}
+		ids, err := svc.dao.AccountSyncHistory().BatchCreateWithTx(cts.Kit, txn, models)
+		if err != nil {
+			return nil, fmt.Errorf("batch create account sync history failed, err: %v", err)
+		}
+
+		return ids, nil
+	})
+	if err != nil {
+		logs.Errorf("batch create account sync history commit txn failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	ids, ok := historyIDs.([]string)
+	if !ok {
+		return nil, fmt.Errorf("create account sync history but return id type not string, id type: %v",
+			reflect.TypeOf(historyIDs).String())
+	}
+
+	return &core.BatchCreateResult{IDs: ids}, nil
+}
+
+// ListAccountSyncHistory list account sync history.
+func (svc *service) ListAccountSyncHistory(cts *rest.Contexts) (interface{}, error) {
+	req := new(core.ListReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, err
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	opt := &types.ListOption{
+		Filter: req.Filter,
+		Page:   req.Page,
+		Fields: req.Fields,
+	}
+	daoResp, err := svc.dao.AccountSyncHistory().List(cts.Kit, opt)
+	if err != nil {
+		logs.Errorf("list account sync history failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, fmt.Errorf("list account sync history failed, err: %v", err)
+	}
+	if req.Page.Count {
+		return &dssync.HistoryListResult{Count: daoResp.Count}, nil
+	}
+
+	details := make([]coresync.AccountSyncHistory, 0, len(daoResp.Details))
+	for _, one := range daoResp.Details {
+		details = append(details, coresync.AccountSyncHistory(one))
+	}
+
+	return &dssync.HistoryListResult{Details: details}, nil
+}
+
+// DeleteExpiredAccountSyncHistory delete expired account sync history, 分批删除直到没有过期的同步历史。
+func (svc *service) DeleteExpiredAccountSyncHistory(cts *rest.Contexts) (interface{}, error) {
+	req := new(dssync.HistoryDeleteExpiredReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	before, err := time.Parse(constant.TimeStdFormat, req.Before)
+	if err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	deleted := uint64(0)
+	for {
+		ids, err := svc.dao.AccountSyncHistory().ListExpiredID(cts.Kit, before, core.DefaultMaxPageLimit)
+		if err != nil {
+			logs.Errorf("list expired account sync history failed, err: %v, before: %s, rid: %s", err, req.Before,
+				cts.Kit.Rid)
+			return nil, err
+		}
+
+		if len(ids) == 0 {
+			break
+		}
+
+		_, err = svc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+			return nil, svc.dao.AccountSyncHistory().DeleteWithTx(cts.Kit, txn, tools.ContainersExpression("id", ids))
+		})
+		if err != nil {
+			logs.Errorf("delete expired account sync history failed, err: %v, ids: %v, rid: %s", err, ids,
+				cts.Kit.Rid)
+			return nil, err
+		}
+
+		deleted += uint64(len(ids))
+		if len(ids) < int(core.DefaultMaxPageLimit) {
+			break
+		}
+	}
+
+	return &dssync.HistoryDeleteExpiredResult{DeletedCount: deleted}, nil
+}
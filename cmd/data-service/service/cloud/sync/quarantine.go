@@ -0,0 +1,199 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+package sync
+
+import (
+	"fmt"
+	"reflect"
+
+	"hcm/pkg/api/core"
+	coresync "hcm/pkg/api/core/cloud/sync"
+	dataservice "hcm/pkg/api/data-service"
+	dssync "hcm/pkg/api/data-service/cloud/sync"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/orm"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/dal/dao/types"
+	tablesync "hcm/pkg/dal/table/cloud/sync"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// BatchCreateAccountSyncQuarantine create account sync quarantine.
+func (svc *service) BatchCreateAccountSyncQuarantine(cts *rest.Contexts) (interface{}, error) {
+	req := new(dssync.QuarantineCreateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	quarantineIDs, err := svc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		models := make([]tablesync.AccountSyncQuarantineTable, 0, len(req.Items))
+		for _, item := range req.Items {
+			models = append(models, tablesync.AccountSyncQuarantineTable{
+				Vendor:    item.Vendor,
+				AccountID: item.AccountID,
+				Region:    item.Region,
+				ResType:   item.ResType,
+				CloudID:   item.CloudID,
+				Status:    item.Status,
+				SyncRid:   item.SyncRid,
+				Creator:   cts.Kit.User,
+				Reviser:   cts.Kit.User,
+			})
+		}
+		ids, err := svc.dao.AccountSyncQuarantine().BatchCreateWithTx(cts.Kit, txn, models)
+		if err != nil {
+			return nil, fmt.Errorf("batch create account sync quarantine failed, err: %v", err)
+		}
+
+		return ids, nil
+	})
+	if err != nil {
+		logs.Errorf("batch create account sync quarantine commit txn failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	ids, ok := quarantineIDs.([]string)
+	if !ok {
+		return nil, fmt.Errorf("create account sync quarantine but return id type not string, id type: %v",
+			reflect.TypeOf(quarantineIDs).String())
+	}
+
+	return &core.BatchCreateResult{IDs: ids}, nil
+}
+
+// BatchUpdateAccountSyncQuarantine update account sync quarantine.
+func (svc *service) BatchUpdateAccountSyncQuarantine(cts *rest.Contexts) (interface{}, error) {
+	req := new(dssync.QuarantineUpdateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	_, err := svc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		for _, item := range req.Items {
+			model := &tablesync.AccountSyncQuarantineTable{
+				Status:  item.Status,
+				Reviser: cts.Kit.User,
+			}
+
+			if err := svc.dao.AccountSyncQuarantine().UpdateByIDWithTx(cts.Kit, txn, item.ID, model); err != nil {
+				logs.Errorf("update account sync quarantine by id: %s failed, err: %v, model: %+v, rid: %s",
+					item.ID, err, model, cts.Kit.Rid)
+				return nil, err
+			}
+		}
+		return nil, nil
+	})
+	if err != nil {
+		logs.Errorf("batch update account sync quarantine commit txn failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// ListAccountSyncQuarantine list account sync quarantine.
+func (svc *service) ListAccountSyncQuarantine(cts *rest.Contexts) (interface{}, error) {
+	req := new(core.ListReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, err
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	opt := &types.ListOption{
+		Filter: req.Filter,
+		Page:   req.Page,
+		Fields: req.Fields,
+	}
+	daoResp, err := svc.dao.AccountSyncQuarantine().List(cts.Kit, opt)
+	if err != nil {
+		logs.Errorf("list account sync quarantine failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, fmt.Errorf("list account sync quarantine failed, err: %v", err)
+	}
+	if req.Page.Count {
+		return &dssync.QuarantineListResult{Count: daoResp.Count}, nil
+	}
+
+	details := make([]coresync.AccountSyncQuarantine, 0, len(daoResp.Details))
+	for _, one := range daoResp.Details {
+		details = append(details, coresync.AccountSyncQuarantine(one))
+	}
+
+	return &dssync.QuarantineListResult{Details: details}, nil
+}
+
+// BatchDeleteAccountSyncQuarantine delete account sync quarantine with filter.
+func (svc *service) BatchDeleteAccountSyncQuarantine(cts *rest.Contexts) (interface{}, error) {
+	req := new(dataservice.BatchDeleteReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, err
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	opt := &types.ListOption{
+		Filter: req.Filter,
+		Page:   core.NewDefaultBasePage(),
+		Fields: []string{"id"},
+	}
+	listResp, err := svc.dao.AccountSyncQuarantine().List(cts.Kit, opt)
+	if err != nil {
+		logs.Errorf("list account sync quarantine failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, fmt.Errorf("list account sync quarantine failed, err: %v", err)
+	}
+
+	if len(listResp.Details) == 0 {
+		return nil, nil
+	}
+
+	delIDs := make([]string, len(listResp.Details))
+	for index, one := range listResp.Details {
+		delIDs[index] = one.ID
+	}
+
+	_, err = svc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		delFilter := tools.ContainersExpression("id", delIDs)
+		if err = svc.dao.AccountSyncQuarantine().DeleteWithTx(cts.Kit, txn, delFilter); err != nil {
+			return nil, err
+		}
+
+		return nil, nil
+	})
+	if err != nil {
+		logs.Errorf("delete account sync quarantine failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
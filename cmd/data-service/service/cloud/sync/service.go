@@ -40,6 +40,21 @@ func InitService(cap *capability.Capability) {
 	h.Add("BatchCreateAccountSD", http.MethodPost, "/account_sync_details/batch/create", svc.BatchCreateAccountSD)
 	h.Add("BatchUpdateAccountSD", http.MethodPatch, "/account_sync_details/batch/update", svc.BatchUpdateAccountSD)
 
+	h.Add("ListAccountSyncHistory", http.MethodPost, "/account_sync_histories/list", svc.ListAccountSyncHistory)
+	h.Add("BatchCreateAccountSyncHistory", http.MethodPost, "/account_sync_histories/batch/create",
+		svc.BatchCreateAccountSyncHistory)
+	h.Add("DeleteExpiredAccountSyncHistory", http.MethodDelete, "/account_sync_histories/expired",
+		svc.DeleteExpiredAccountSyncHistory)
+
+	h.Add("ListAccountSyncQuarantine", http.MethodPost, "/account_sync_quarantines/list",
+		svc.ListAccountSyncQuarantine)
+	h.Add("BatchCreateAccountSyncQuarantine", http.MethodPost, "/account_sync_quarantines/batch/create",
+		svc.BatchCreateAccountSyncQuarantine)
+	h.Add("BatchUpdateAccountSyncQuarantine", http.MethodPatch, "/account_sync_quarantines/batch/update",
+		svc.BatchUpdateAccountSyncQuarantine)
+	h.Add("BatchDeleteAccountSyncQuarantine", http.MethodDelete, "/account_sync_quarantines/batch",
+		svc.BatchDeleteAccountSyncQuarantine)
+
 	h.Load(cap.WebService)
 }
 
@@ -0,0 +1,147 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package userdatatemplate
+
+import (
+	"fmt"
+
+	corecloud "hcm/pkg/api/core/cloud"
+	protocloud "hcm/pkg/api/data-service/cloud"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	tableaudit "hcm/pkg/dal/table/audit"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+	"hcm/pkg/tools/userdata"
+)
+
+// RenderUserDataTemplate render user data template with variables, and encode it as the vendor required.
+func (svc *service) RenderUserDataTemplate(cts *rest.Contexts) (interface{}, error) {
+	req := new(protocloud.UserDataTemplateRenderReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	template, err := svc.getTemplate(cts.Kit, req.TemplateID)
+	if err != nil {
+		return nil, err
+	}
+
+	if template.BkBizID != req.BkBizID {
+		return nil, errf.Newf(errf.InvalidParameter, "user data template: %s not belongs to biz: %d",
+			req.TemplateID, req.BkBizID)
+	}
+
+	versionNum := req.Version
+	if versionNum == 0 {
+		versionNum = template.LatestVersion
+	}
+
+	tableVersion, err := svc.getTemplateVersion(cts.Kit, template.ID, versionNum)
+	if err != nil {
+		return nil, err
+	}
+
+	version, err := convUserDataTemplateVersion(tableVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	values, secrets, err := buildRenderValues(req.Builtin, req.Variables, version.Variables)
+	if err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	content := userdata.Render(version.Content, values)
+	encoded, err := userdata.Encode(req.Vendor, content)
+	if err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	result := &protocloud.UserDataTemplateRenderResult{
+		TemplateID: template.ID,
+		Version:    version.Version,
+		Content:    content,
+		UserData:   encoded,
+		Variables:  userdata.Redact(values, secrets),
+	}
+
+	if req.Apply == nil {
+		return result, nil
+	}
+
+	audit := genAudit(cts.Kit, template.ID, template.Name, template.BkBizID, enumor.Apply, &tableaudit.BasicDetail{
+		Data: map[string]interface{}{
+			"version":   version.Version,
+			"res_name":  req.Apply.ResName,
+			"source":    req.Apply.Source,
+			"variables": result.Variables,
+		},
+	})
+	audit.Vendor = req.Vendor
+	audit.AccountID = req.Apply.AccountID
+	if err = svc.dao.Audit().Create(cts.Kit, audit); err != nil {
+		logs.Errorf("create user data template apply audit failed, err: %v, id: %s, rid: %s", err, template.ID,
+			cts.Kit.Rid)
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// buildRenderValues 合并内置变量和自定义变量的值，返回渲染使用的变量以及敏感变量名称。
+func buildRenderValues(builtin map[string]string, custom map[string]string,
+	variables []corecloud.UserDataVariable) (map[string]string, map[string]struct{}, error) {
+
+	values := make(map[string]string, len(builtin)+len(variables))
+	for name, value := range builtin {
+		values[name] = value
+	}
+
+	declared := make(map[string]struct{}, len(variables))
+	secrets := make(map[string]struct{})
+	for _, one := range variables {
+		declared[one.Name] = struct{}{}
+		if one.Secret {
+			secrets[one.Name] = struct{}{}
+		}
+
+		value, exists := custom[one.Name]
+		if !exists {
+			if one.Required {
+				return nil, nil, fmt.Errorf("variable %s is required", one.Name)
+			}
+			value = one.Default
+		}
+		values[one.Name] = value
+	}
+
+	for name := range custom {
+		if _, exists := declared[name]; !exists {
+			return nil, nil, fmt.Errorf("variable %s is not declared in template", name)
+		}
+	}
+
+	return values, secrets, nil
+}
@@ -0,0 +1,448 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package userdatatemplate
+
+import (
+	"fmt"
+
+	"hcm/pkg/api/core"
+	corecloud "hcm/pkg/api/core/cloud"
+	dataservice "hcm/pkg/api/data-service"
+	protocloud "hcm/pkg/api/data-service/cloud"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/orm"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/dal/dao/types"
+	tableaudit "hcm/pkg/dal/table/audit"
+	tablecloud "hcm/pkg/dal/table/cloud"
+	tabletype "hcm/pkg/dal/table/types"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+	"hcm/pkg/runtime/filter"
+	"hcm/pkg/tools/converter"
+	"hcm/pkg/tools/json"
+	"hcm/pkg/tools/userdata"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// CreateUserDataTemplate create user data template with the first version.
+func (svc *service) CreateUserDataTemplate(cts *rest.Contexts) (interface{}, error) {
+	req := new(protocloud.UserDataTemplateCreateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	variables, err := tabletype.NewJsonField(sliceOrEmpty(req.Variables))
+	if err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	template := tablecloud.UserDataTemplateTable{
+		Name:          req.Name,
+		BkBizID:       req.BkBizID,
+		LatestVersion: 1,
+		Memo:          converter.ValToPtr(req.Memo),
+		Creator:       cts.Kit.User,
+		Reviser:       cts.Kit.User,
+	}
+
+	id, err := svc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		ids, err := svc.dao.UserDataTemplate().BatchCreateWithTx(cts.Kit, txn,
+			[]tablecloud.UserDataTemplateTable{template})
+		if err != nil {
+			return nil, err
+		}
+
+		version := tablecloud.UserDataTemplateVersionTable{
+			TemplateID: ids[0],
+			Version:    template.LatestVersion,
+			Content:    req.Content,
+			Variables:  variables,
+			Memo:       converter.ValToPtr(""),
+			Creator:    cts.Kit.User,
+		}
+		if _, err = svc.dao.UserDataTemplateVersion().BatchCreateWithTx(cts.Kit, txn,
+			[]tablecloud.UserDataTemplateVersionTable{version}); err != nil {
+			return nil, err
+		}
+
+		detail := &tableaudit.BasicDetail{
+			Data: map[string]interface{}{
+				"name":      req.Name,
+				"bk_biz_id": req.BkBizID,
+				"version":   version.Version,
+				"variables": redactVariables(req.Variables),
+				"memo":      req.Memo,
+			},
+		}
+		audit := genAudit(cts.Kit, ids[0], req.Name, req.BkBizID, enumor.Create, detail)
+		if err = svc.dao.Audit().BatchCreateWithTx(cts.Kit, txn, []*tableaudit.AuditTable{audit}); err != nil {
+			return nil, err
+		}
+
+		return ids[0], nil
+	})
+	if err != nil {
+		logs.Errorf("create user data template failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	createdID, ok := id.(string)
+	if !ok {
+		return nil, fmt.Errorf("create user data template but return id type is not string, id type: %T", id)
+	}
+
+	return &core.CreateResult{ID: createdID}, nil
+}
+
+// UpdateUserDataTemplate update user data template, 内容变更时生成新的版本。
+func (svc *service) UpdateUserDataTemplate(cts *rest.Contexts) (interface{}, error) {
+	id := cts.PathParameter("id").String()
+	if len(id) == 0 {
+		return nil, errf.New(errf.InvalidParameter, "id is required")
+	}
+
+	req := new(protocloud.UserDataTemplateUpdateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	template, err := svc.getTemplate(cts.Kit, id)
+	if err != nil {
+		return nil, err
+	}
+
+	model := &tablecloud.UserDataTemplateTable{
+		Name:    req.Name,
+		Memo:    req.Memo,
+		Reviser: cts.Kit.User,
+	}
+
+	changed := make(map[string]interface{})
+	if len(req.Name) != 0 {
+		changed["name"] = req.Name
+	}
+	if req.Memo != nil {
+		changed["memo"] = *req.Memo
+	}
+
+	var version *tablecloud.UserDataTemplateVersionTable
+	if len(req.Content) != 0 {
+		variables, err := tabletype.NewJsonField(sliceOrEmpty(req.Variables))
+		if err != nil {
+			return nil, errf.NewFromErr(errf.InvalidParameter, err)
+		}
+
+		version = &tablecloud.UserDataTemplateVersionTable{
+			TemplateID: id,
+			Version:    template.LatestVersion + 1,
+			Content:    req.Content,
+			Variables:  variables,
+			Memo:       converter.ValToPtr(req.VersionMemo),
+			Creator:    cts.Kit.User,
+		}
+		model.LatestVersion = version.Version
+		changed["latest_version"] = version.Version
+		changed["variables"] = redactVariables(req.Variables)
+	}
+
+	_, err = svc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		if version != nil {
+			// 模版id和版本号有唯一索引，并发更新时只有一个请求能够生成新版本
+			if _, err := svc.dao.UserDataTemplateVersion().BatchCreateWithTx(cts.Kit, txn,
+				[]tablecloud.UserDataTemplateVersionTable{*version}); err != nil {
+				return nil, err
+			}
+		}
+
+		if err := svc.dao.UserDataTemplate().UpdateByIDWithTx(cts.Kit, txn, id, model); err != nil {
+			return nil, err
+		}
+
+		detail := &tableaudit.BasicDetail{Data: convUserDataTemplate(template), Changed: changed}
+		audit := genAudit(cts.Kit, id, template.Name, template.BkBizID, enumor.Update, detail)
+		return nil, svc.dao.Audit().BatchCreateWithTx(cts.Kit, txn, []*tableaudit.AuditTable{audit})
+	})
+	if err != nil {
+		logs.Errorf("update user data template failed, err: %v, id: %s, rid: %s", err, id, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// ListUserDataTemplate list user data template.
+func (svc *service) ListUserDataTemplate(cts *rest.Contexts) (interface{}, error) {
+	req := new(core.ListReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	opt := &types.ListOption{
+		Filter: req.Filter,
+		Page:   req.Page,
+		Fields: req.Fields,
+	}
+	result, err := svc.dao.UserDataTemplate().List(cts.Kit, opt)
+	if err != nil {
+		logs.Errorf("list user data template failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, fmt.Errorf("list user data template failed, err: %v", err)
+	}
+
+	if req.Page.Count {
+		return &protocloud.UserDataTemplateListResult{Count: result.Count}, nil
+	}
+
+	details := make([]corecloud.UserDataTemplate, 0, len(result.Details))
+	for _, one := range result.Details {
+		details = append(details, *convUserDataTemplate(&one))
+	}
+
+	return &protocloud.UserDataTemplateListResult{Details: details}, nil
+}
+
+// BatchDeleteUserDataTemplate batch delete user data template and all of its versions.
+func (svc *service) BatchDeleteUserDataTemplate(cts *rest.Contexts) (interface{}, error) {
+	req := new(dataservice.BatchDeleteReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	opt := &types.ListOption{
+		Filter: req.Filter,
+		Page:   core.NewDefaultBasePage(),
+	}
+	result, err := svc.dao.UserDataTemplate().List(cts.Kit, opt)
+	if err != nil {
+		logs.Errorf("list user data template failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	if len(result.Details) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]string, 0, len(result.Details))
+	audits := make([]*tableaudit.AuditTable, 0, len(result.Details))
+	for _, one := range result.Details {
+		ids = append(ids, one.ID)
+		detail := &tableaudit.BasicDetail{Data: convUserDataTemplate(&one)}
+		audits = append(audits, genAudit(cts.Kit, one.ID, one.Name, one.BkBizID, enumor.Delete, detail))
+	}
+
+	_, err = svc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		if err := svc.dao.UserDataTemplateVersion().DeleteWithTx(cts.Kit, txn,
+			tools.ContainersExpression("template_id", ids)); err != nil {
+			return nil, err
+		}
+
+		if err := svc.dao.UserDataTemplate().DeleteWithTx(cts.Kit, txn,
+			tools.ContainersExpression("id", ids)); err != nil {
+			return nil, err
+		}
+
+		return nil, svc.dao.Audit().BatchCreateWithTx(cts.Kit, txn, audits)
+	})
+	if err != nil {
+		logs.Errorf("batch delete user data template failed, err: %v, ids: %v, rid: %s", err, ids, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// ListUserDataTemplateVersion list user data template version.
+func (svc *service) ListUserDataTemplateVersion(cts *rest.Contexts) (interface{}, error) {
+	req := new(core.ListReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	opt := &types.ListOption{
+		Filter: req.Filter,
+		Page:   req.Page,
+		Fields: req.Fields,
+	}
+	result, err := svc.dao.UserDataTemplateVersion().List(cts.Kit, opt)
+	if err != nil {
+		logs.Errorf("list user data template version failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, fmt.Errorf("list user data template version failed, err: %v", err)
+	}
+
+	if req.Page.Count {
+		return &protocloud.UserDataTemplateVersionListResult{Count: result.Count}, nil
+	}
+
+	details := make([]corecloud.UserDataTemplateVersion, 0, len(result.Details))
+	for _, one := range result.Details {
+		version, err := convUserDataTemplateVersion(&one)
+		if err != nil {
+			logs.Errorf("convert user data template version failed, err: %v, id: %s, rid: %s", err, one.ID,
+				cts.Kit.Rid)
+			return nil, err
+		}
+		details = append(details, *version)
+	}
+
+	return &protocloud.UserDataTemplateVersionListResult{Details: details}, nil
+}
+
+func (svc *service) getTemplate(kt *kit.Kit, id string) (*tablecloud.UserDataTemplateTable, error) {
+	opt := &types.ListOption{
+		Filter: tools.EqualExpression("id", id),
+		Page:   core.NewDefaultBasePage(),
+	}
+	result, err := svc.dao.UserDataTemplate().List(kt, opt)
+	if err != nil {
+		logs.Errorf("list user data template failed, err: %v, id: %s, rid: %s", err, id, kt.Rid)
+		return nil, err
+	}
+
+	if len(result.Details) == 0 {
+		return nil, errf.Newf(errf.RecordNotFound, "user data template: %s not found", id)
+	}
+
+	return &result.Details[0], nil
+}
+
+func (svc *service) getTemplateVersion(kt *kit.Kit, templateID string, version int64) (
+	*tablecloud.UserDataTemplateVersionTable, error) {
+
+	opt := &types.ListOption{
+		Filter: tools.EqualWithOpExpression(filter.And, map[string]interface{}{
+			"template_id": templateID,
+			"version":     version,
+		}),
+		Page: core.NewDefaultBasePage(),
+	}
+	result, err := svc.dao.UserDataTemplateVersion().List(kt, opt)
+	if err != nil {
+		logs.Errorf("list user data template version failed, err: %v, template: %s, version: %d, rid: %s", err,
+			templateID, version, kt.Rid)
+		return nil, err
+	}
+
+	if len(result.Details) == 0 {
+		return nil, errf.Newf(errf.RecordNotFound, "user data template: %s version: %d not found", templateID,
+			version)
+	}
+
+	return &result.Details[0], nil
+}
+
+func genAudit(kt *kit.Kit, id, name string, bizID int64, action enumor.AuditAction,
+	detail *tableaudit.BasicDetail) *tableaudit.AuditTable {
+
+	return &tableaudit.AuditTable{
+		ResID:    id,
+		ResName:  name,
+		ResType:  enumor.UserDataTemplateAuditResType,
+		Action:   action,
+		BkBizID:  bizID,
+		Operator: kt.User,
+		Source:   kt.GetRequestSource(),
+		Rid:      kt.Rid,
+		AppCode:  kt.AppCode,
+		Detail:   detail,
+	}
+}
+
+// redactVariables 敏感变量的默认值不记录到审计中
+func redactVariables(variables []corecloud.UserDataVariable) []corecloud.UserDataVariable {
+	redacted := make([]corecloud.UserDataVariable, 0, len(variables))
+	for _, one := range variables {
+		if one.Secret && len(one.Default) != 0 {
+			one.Default = userdata.RedactedValue
+		}
+		redacted = append(redacted, one)
+	}
+	return redacted
+}
+
+func convUserDataTemplate(one *tablecloud.UserDataTemplateTable) *corecloud.UserDataTemplate {
+	return &corecloud.UserDataTemplate{
+		ID:            one.ID,
+		Name:          one.Name,
+		BkBizID:       one.BkBizID,
+		LatestVersion: one.LatestVersion,
+		Memo:          converter.PtrToVal(one.Memo),
+		Revision: &core.Revision{
+			Creator:   one.Creator,
+			Reviser:   one.Reviser,
+			CreatedAt: one.CreatedAt.String(),
+			UpdatedAt: one.UpdatedAt.String(),
+		},
+	}
+}
+
+func convUserDataTemplateVersion(one *tablecloud.UserDataTemplateVersionTable) (
+	*corecloud.UserDataTemplateVersion, error) {
+
+	variables := make([]corecloud.UserDataVariable, 0)
+	if !one.Variables.IsEmpty() {
+		if err := json.UnmarshalFromString(string(one.Variables), &variables); err != nil {
+			return nil, fmt.Errorf("unmarshal user data template variables failed, err: %v", err)
+		}
+	}
+
+	return &corecloud.UserDataTemplateVersion{
+		ID:         one.ID,
+		TemplateID: one.TemplateID,
+		Version:    one.Version,
+		Content:    one.Content,
+		Variables:  variables,
+		Memo:       converter.PtrToVal(one.Memo),
+		CreatedRevision: &core.CreatedRevision{
+			Creator:   one.Creator,
+			CreatedAt: one.CreatedAt.String(),
+		},
+	}, nil
+}
+
+// sliceOrEmpty 将nil切片转换为空切片，避免json字段存储为null
+func sliceOrEmpty[T any](list []T) []T {
+	if list == nil {
+		return make([]T, 0)
+	}
+	return list
+}
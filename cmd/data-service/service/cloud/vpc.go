@@ -137,6 +137,11 @@ func batchCreateVpc[T protocloud.VpcCreateExtension](cts *rest.Contexts, vendor
 			return nil, fmt.Errorf("create vpc failed, err: %v", err)
 		}
 
+		err = svc.dao.ResChangeHistory().RecordWithTx(cts.Kit, txn, enumor.VpcCloudResType, enumor.Create, vpcID)
+		if err != nil {
+			return nil, err
+		}
+
 		return vpcID, nil
 	})
 
@@ -255,6 +260,11 @@ func (svc *vpcSvc) updateVpc(kt *kit.Kit, ids []string, vpc *tablecloud.VpcTable
 		return fmt.Errorf("update vpc failed, err: %v", err)
 	}
 
+	if err = svc.dao.ResChangeHistory().Record(kt, enumor.VpcCloudResType, enumor.Update, ids); err != nil {
+		logs.Errorf("record vpc change history failed, err: %v, ids: %v, rid: %s", err, ids, kt.Rid)
+		return err
+	}
+
 	// update host cloud area in vpc
 	cvmFilter := &filter.Expression{
 		Op: filter.And,
@@ -480,7 +490,26 @@ func (svc *vpcSvc) BatchDeleteVpc(cts *rest.Contexts) (interface{}, error) {
 		delVpcIDs[index] = one.ID
 	}
 
+	// 删除vpc时会级联删除其下的子网，需要同时清理子网的标签
+	delSubnetIDs, err := svc.dao.Cloud().ListResourceIDs(cts.Kit, enumor.SubnetCloudResType,
+		tools.ContainersExpression("vpc_id", delVpcIDs))
+	if err != nil {
+		logs.Errorf("list vpc subnet ids failed, err: %v, vpc ids: %v, rid: %s", err, delVpcIDs, cts.Kit.Rid)
+		return nil, err
+	}
+
 	_, err = svc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		err := svc.dao.ResChangeHistory().RecordWithTx(cts.Kit, txn, enumor.VpcCloudResType, enumor.Delete, delVpcIDs)
+		if err != nil {
+			return nil, err
+		}
+
+		err = svc.dao.ResChangeHistory().RecordByFieldWithTx(cts.Kit, txn, enumor.SubnetCloudResType, enumor.Delete,
+			"vpc_id", delVpcIDs)
+		if err != nil {
+			return nil, err
+		}
+
 		delVpcFilter := tools.ContainersExpression("id", delVpcIDs)
 		if err := svc.dao.Vpc().BatchDeleteWithTx(cts.Kit, txn, delVpcFilter); err != nil {
 			return nil, err
@@ -491,6 +520,18 @@ func (svc *vpcSvc) BatchDeleteVpc(cts *rest.Contexts) (interface{}, error) {
 			return nil, err
 		}
 
+		vpcTagFilter := tools.ResourceTagExpression(enumor.VpcCloudResType, delVpcIDs)
+		if err := svc.dao.ResourceTag().DeleteWithTx(cts.Kit, txn, vpcTagFilter); err != nil {
+			return nil, err
+		}
+
+		if len(delSubnetIDs) > 0 {
+			subnetTagFilter := tools.ResourceTagExpression(enumor.SubnetCloudResType, delSubnetIDs)
+			if err := svc.dao.ResourceTag().DeleteWithTx(cts.Kit, txn, subnetTagFilter); err != nil {
+				return nil, err
+			}
+		}
+
 		return nil, nil
 	})
 	if err != nil {
@@ -0,0 +1,56 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package service
+
+import (
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+	"hcm/pkg/runtime/fencing"
+
+	"github.com/emicklei/go-restful/v3"
+)
+
+// restFilter 校验同步请求携带的账号同步锁 fencing token，拒绝已经失去同步锁的同步请求，避免并行同步互相覆盖数据。
+func (s *Service) restFilter() restful.FilterFunction {
+	return func(req *restful.Request, resp *restful.Response, chain *restful.FilterChain) {
+		value := req.Request.Header.Get(constant.SyncFencingTokenKey)
+		if len(value) == 0 {
+			chain.ProcessFilter(req, resp)
+			return
+		}
+
+		token, err := fencing.Parse(value)
+		if err != nil {
+			rest.WriteResp(resp.ResponseWriter, rest.NewBaseResp(errf.InvalidParameter, err.Error()))
+			return
+		}
+
+		if err = fencing.Check(req.Request.Context(), s.etcdCli, token); err != nil {
+			logs.Errorf("check sync fencing token failed, err: %v, token: %s, path: %s, rid: %s", err, value,
+				req.Request.URL.Path, req.Request.Header.Get(constant.RidKey))
+			rest.WriteResp(resp.ResponseWriter, rest.NewBaseResp(errf.Aborted, err.Error()))
+			return
+		}
+
+		chain.ProcessFilter(req, resp)
+	}
+}
@@ -36,20 +36,26 @@ import (
 	"hcm/cmd/data-service/service/cloud/account"
 	accountbizrel "hcm/cmd/data-service/service/cloud/account-biz-rel"
 	"hcm/cmd/data-service/service/cloud/bill"
+	bizassignrule "hcm/cmd/data-service/service/cloud/biz-assign-rule"
 	"hcm/cmd/data-service/service/cloud/cvm"
 	"hcm/cmd/data-service/service/cloud/disk"
 	diskcvmrel "hcm/cmd/data-service/service/cloud/disk-cvm-rel"
 	"hcm/cmd/data-service/service/cloud/eip"
 	eipcvmrel "hcm/cmd/data-service/service/cloud/eip-cvm-rel"
 	"hcm/cmd/data-service/service/cloud/image"
+	launchtemplate "hcm/cmd/data-service/service/cloud/launch-template"
 	networkinterface "hcm/cmd/data-service/service/cloud/network-interface"
 	networkcvmrel "hcm/cmd/data-service/service/cloud/network-interface-cvm-rel"
 	"hcm/cmd/data-service/service/cloud/region"
+	reschangehistory "hcm/cmd/data-service/service/cloud/res-change-history"
 	resourcegroup "hcm/cmd/data-service/service/cloud/resource-group"
+	resourcetag "hcm/cmd/data-service/service/cloud/resource-tag"
 	routetable "hcm/cmd/data-service/service/cloud/route-table"
+	sgcomrel "hcm/cmd/data-service/service/cloud/security-group-common-rel"
 	sgcvmrel "hcm/cmd/data-service/service/cloud/security-group-cvm-rel"
 	subaccount "hcm/cmd/data-service/service/cloud/sub-account"
 	sync "hcm/cmd/data-service/service/cloud/sync"
+	userdatatemplate "hcm/cmd/data-service/service/cloud/user-data-template"
 	"hcm/cmd/data-service/service/cloud/zone"
 	recyclerecord "hcm/cmd/data-service/service/recycle-record"
 	"hcm/cmd/data-service/service/user"
@@ -67,6 +73,7 @@ import (
 	"hcm/pkg/tools/ssl"
 
 	"github.com/emicklei/go-restful/v3"
+	etcd3 "go.etcd.io/etcd/client/v3"
 )
 
 // Service do all the data service's work
@@ -75,6 +82,8 @@ type Service struct {
 	dao       dao.Set
 	cipher    cryptography.Crypto
 	esbClient esb.Client
+	// etcdCli 用于校验同步请求的账号同步锁 fencing token
+	etcdCli *etcd3.Client
 }
 
 // NewService create a service instance.
@@ -97,10 +106,20 @@ func NewService() (*Service, error) {
 		return nil, err
 	}
 
+	etcdCfg, err := cc.DataService().Service.Etcd.ToConfig()
+	if err != nil {
+		return nil, err
+	}
+	etcdCli, err := etcd3.New(etcdCfg)
+	if err != nil {
+		return nil, err
+	}
+
 	svr := &Service{
 		dao:       dao,
 		cipher:    cipher,
 		esbClient: esbClient,
+		etcdCli:   etcdCli,
 	}
 
 	return svr, nil
@@ -174,6 +193,7 @@ func (s *Service) apiSet() *restful.Container {
 	ws := new(restful.WebService)
 	ws.Path("/api/v1/data")
 	ws.Produces(restful.MIME_JSON)
+	ws.Filter(s.restFilter())
 
 	capability := &capability.Capability{
 		WebService: ws,
@@ -199,6 +219,7 @@ func (s *Service) apiSet() *restful.Container {
 	image.InitService(capability)
 	cvm.InitService(capability)
 	sgcvmrel.InitService(capability)
+	sgcomrel.InitService(capability)
 	routetable.InitRouteTableService(capability)
 	application.InitApplicationService(capability)
 	application.InitApprovalProcessService(capability)
@@ -211,6 +232,11 @@ func (s *Service) apiSet() *restful.Container {
 	subaccount.InitService(capability)
 	sync.InitService(capability)
 	user.InitService(capability)
+	resourcetag.InitService(capability)
+	reschangehistory.InitService(capability)
+	bizassignrule.InitService(capability)
+	userdatatemplate.InitService(capability)
+	launchtemplate.InitService(capability)
 
 	return restful.NewContainer().Add(capability.WebService)
 }
@@ -27,6 +27,7 @@ import (
 	"hcm/pkg/adaptor/aws"
 	typescvm "hcm/pkg/adaptor/types/cvm"
 	"hcm/pkg/api/core"
+	corecloud "hcm/pkg/api/core/cloud"
 	"hcm/pkg/api/core/cloud/cvm"
 	corecvm "hcm/pkg/api/core/cloud/cvm"
 	dataproto "hcm/pkg/api/data-service/cloud"
@@ -61,6 +62,8 @@ func (cli *client) Cvm(kt *kit.Kit, params *SyncBaseParams, opt *SyncCvmOption)
 		return nil, errf.NewFromErr(errf.InvalidParameter, err)
 	}
 
+	kt, stat := common.ForkSyncStat(kt)
+
 	cvmFromCloud, err := cli.listCvmFromCloud(kt, params)
 	if err != nil {
 		return nil, err
@@ -72,11 +75,11 @@ func (cli *client) Cvm(kt *kit.Kit, params *SyncBaseParams, opt *SyncCvmOption)
 	}
 
 	if len(cvmFromCloud) == 0 && len(cvmFromDB) == 0 {
-		return new(SyncResult), nil
+		return &SyncResult{Counts: stat.Counts()}, nil
 	}
 
 	addSlice, updateMap, delCloudIDs := common.Diff[typescvm.AwsCvm, corecvm.Cvm[cvm.AwsCvmExtension]](
-		cvmFromCloud, cvmFromDB, isCvmChange)
+		kt, enumor.CvmCloudResType, cvmFromCloud, cvmFromDB, isCvmChange)
 
 	if len(delCloudIDs) > 0 {
 		if err = cli.deleteCvm(kt, params.AccountID, params.Region, delCloudIDs); err != nil {
@@ -96,7 +99,11 @@ func (cli *client) Cvm(kt *kit.Kit, params *SyncBaseParams, opt *SyncCvmOption)
 		}
 	}
 
-	return new(SyncResult), nil
+	if err = cli.syncCvmTag(kt, params.AccountID, cvmFromCloud); err != nil {
+		return nil, err
+	}
+
+	return &SyncResult{Counts: stat.Counts()}, nil
 }
 
 func (cli *client) createCvm(kt *kit.Kit, accountID string, region string,
@@ -236,6 +243,7 @@ func (cli *client) createCvm(kt *kit.Kit, accountID string, region string,
 
 	logs.Infof("[%s] sync cvm to create cvm success, accountID: %s, count: %d, rid: %s", enumor.Aws,
 		accountID, len(addSlice), kt.Rid)
+	common.RecordSyncCreated(kt, enumor.CvmCloudResType, len(addSlice))
 
 	return nil
 }
@@ -371,6 +379,7 @@ func (cli *client) updateCvm(kt *kit.Kit, accountID string, region string,
 	}
 
 	logs.Infof("[%s] sync cvm to update cvm success, count: %d, rid: %s", enumor.Aws, len(updateMap), kt.Rid)
+	common.RecordSyncUpdated(kt, enumor.CvmCloudResType, len(updateMap))
 
 	return nil
 }
@@ -505,6 +514,23 @@ func (cli *client) deleteCvm(kt *kit.Kit, accountID string, region string, delCl
 		return fmt.Errorf("validate cvm not exist failed, before delete")
 	}
 
+	guardOpt := &common.SyncDeletionGuardOption{
+		Vendor:      enumor.Aws,
+		ResType:     enumor.CvmCloudResType,
+		AccountID:   accountID,
+		Region:      region,
+		RegionField: "region",
+		CloudIDs:    delCloudIDs,
+	}
+	delCloudIDs, err = common.GuardSyncDeletion(kt, cli.dbCli, guardOpt)
+	if err != nil {
+		return err
+	}
+
+	if len(delCloudIDs) == 0 {
+		return nil
+	}
+
 	deleteReq := &dataproto.CvmBatchDeleteReq{
 		Filter: tools.ContainersExpression("cloud_id", delCloudIDs),
 	}
@@ -516,6 +542,11 @@ func (cli *client) deleteCvm(kt *kit.Kit, accountID string, region string, delCl
 
 	logs.Infof("[%s] sync cvm to delete cvm success, accountID: %s, count: %d, rid: %s", enumor.Aws,
 		accountID, len(delCloudIDs), kt.Rid)
+	common.RecordSyncDeleted(kt, enumor.CvmCloudResType, len(delCloudIDs))
+
+	if err = common.ConfirmSyncDeletion(kt, cli.dbCli, guardOpt, delCloudIDs); err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -529,6 +560,7 @@ func (cli *client) listCvmFromCloud(kt *kit.Kit, params *SyncBaseParams) ([]type
 		Region:   params.Region,
 		CloudIDs: params.CloudIDs,
 	}
+	common.RecordCloudApiCall(kt)
 	result, _, err := cli.cloudCli.ListCvm(kt, opt)
 	if err != nil {
 		if strings.Contains(err.Error(), aws.ErrCvmNotFound) {
@@ -660,6 +692,7 @@ func (cli *client) listRemoveCvmID(kt *kit.Kit, params *SyncBaseParams) ([]strin
 			Region:   params.Region,
 			CloudIDs: cloudIDs,
 		}
+		common.RecordCloudApiCall(kt)
 		cvms, _, err := cli.cloudCli.ListCvm(kt, opt)
 		if err != nil {
 			if strings.Contains(err.Error(), aws.ErrCvmNotFound) {
@@ -839,3 +872,20 @@ func isCvmChange(cloud typescvm.AwsCvm, db corecvm.Cvm[cvm.AwsCvmExtension]) boo
 
 	return false
 }
+
+// syncCvmTag 使用云上主机标签覆盖db中的主机标签
+func (cli *client) syncCvmTag(kt *kit.Kit, accountID string, cvms []typescvm.AwsCvm) error {
+	tagMap := make(map[string][]corecloud.TagPair, len(cvms))
+	for _, one := range cvms {
+		tags := make([]corecloud.TagPair, 0, len(one.Tags))
+		for _, tag := range one.Tags {
+			tags = append(tags, corecloud.TagPair{
+				Key:   converter.PtrToVal(tag.Key),
+				Value: converter.PtrToVal(tag.Value),
+			})
+		}
+		tagMap[one.GetCloudID()] = tags
+	}
+
+	return common.SyncResourceTag(kt, cli.dbCli, enumor.Aws, accountID, enumor.CvmCloudResType, tagMap)
+}
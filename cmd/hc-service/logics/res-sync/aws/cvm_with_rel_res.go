@@ -21,6 +21,7 @@ package aws
 
 import (
 	"fmt"
+	"hcm/cmd/hc-service/logics/res-sync/common"
 
 	cvmrelmgr "hcm/cmd/hc-service/logics/res-sync/cvm-rel-manager"
 	typecvm "hcm/pkg/adaptor/types/cvm"
@@ -61,6 +62,8 @@ func (opt SyncCvmWithRelResOption) Validate() error {
 func (cli *client) CvmWithRelRes(kt *kit.Kit, params *SyncBaseParams, opt *SyncCvmWithRelResOption) (
 	*SyncResult, error) {
 
+	kt, stat := common.ForkSyncStat(kt)
+
 	cvmFromCloud, err := cli.listCvmFromCloud(kt, params)
 	if err != nil {
 		return nil, err
@@ -72,7 +75,7 @@ func (cli *client) CvmWithRelRes(kt *kit.Kit, params *SyncBaseParams, opt *SyncC
 			return nil, err
 		}
 
-		return new(SyncResult), nil
+		return &SyncResult{Counts: stat.Counts()}, nil
 	}
 
 	// step2: 获取cvm和关联资源的关联关系
@@ -212,7 +215,7 @@ func (cli *client) CvmWithRelRes(kt *kit.Kit, params *SyncBaseParams, opt *SyncC
 		return nil, err
 	}
 
-	return new(SyncResult), nil
+	return &SyncResult{Counts: stat.Counts()}, nil
 }
 
 // getEipMapFromCloudCvm 查询主机所对应的Eip信息。
@@ -239,6 +242,7 @@ func (cli *client) getEipMapFromCloudCvm(kt *kit.Kit, region string, cvmFromClou
 			Region: region,
 			Ips:    partIPs,
 		}
+		common.RecordCloudApiCall(kt)
 		resp, err := cli.cloudCli.ListEip(kt, opt)
 		if err != nil {
 			logs.Errorf("[%s] list eip by ip from cloud failed, err: %v, account: %s, opt: %v, rid: %s",
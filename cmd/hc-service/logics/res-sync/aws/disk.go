@@ -27,6 +27,7 @@ import (
 	"hcm/pkg/adaptor/aws"
 	adaptordisk "hcm/pkg/adaptor/types/disk"
 	"hcm/pkg/api/core"
+	corecloud "hcm/pkg/api/core/cloud"
 	coredisk "hcm/pkg/api/core/cloud/disk"
 	"hcm/pkg/api/data-service/cloud/disk"
 	"hcm/pkg/criteria/constant"
@@ -58,6 +59,8 @@ func (cli *client) Disk(kt *kit.Kit, params *SyncBaseParams, opt *SyncDiskOption
 		return nil, errf.NewFromErr(errf.InvalidParameter, err)
 	}
 
+	kt, stat := common.ForkSyncStat(kt)
+
 	diskFromCloud, err := cli.listDiskFromCloud(kt, params)
 	if err != nil {
 		return nil, err
@@ -69,7 +72,7 @@ func (cli *client) Disk(kt *kit.Kit, params *SyncBaseParams, opt *SyncDiskOption
 	}
 
 	if len(diskFromCloud) == 0 && len(diskFromDB) == 0 {
-		return new(SyncResult), nil
+		return &SyncResult{Counts: stat.Counts()}, nil
 	}
 	if opt.BootMap != nil {
 		// 标记启动盘
@@ -79,7 +82,7 @@ func (cli *client) Disk(kt *kit.Kit, params *SyncBaseParams, opt *SyncDiskOption
 		}
 	}
 	addSlice, updateMap, delCloudIDs := common.Diff[adaptordisk.AwsDisk, *coredisk.Disk[coredisk.AwsExtension]](
-		diskFromCloud, diskFromDB, isDiskChange)
+		kt, enumor.DiskCloudResType, diskFromCloud, diskFromDB, isDiskChange)
 
 	if len(delCloudIDs) > 0 {
 		if err := cli.deleteDisk(kt, params.AccountID, params.Region, delCloudIDs); err != nil {
@@ -99,7 +102,11 @@ func (cli *client) Disk(kt *kit.Kit, params *SyncBaseParams, opt *SyncDiskOption
 		}
 	}
 
-	return new(SyncResult), nil
+	if err = cli.syncDiskTag(kt, params.AccountID, diskFromCloud); err != nil {
+		return nil, err
+	}
+
+	return &SyncResult{Counts: stat.Counts()}, nil
 }
 
 func (cli *client) updateDisk(kt *kit.Kit, accountID string, updateMap map[string]adaptordisk.AwsDisk) error {
@@ -163,6 +170,7 @@ func (cli *client) updateDisk(kt *kit.Kit, accountID string, updateMap map[strin
 
 	logs.Infof("[%s] sync disk to update disk success, accountID: %s, count: %d, rid: %s", enumor.Aws,
 		accountID, len(updateMap), kt.Rid)
+	common.RecordSyncUpdated(kt, enumor.DiskCloudResType, len(updateMap))
 
 	return nil
 }
@@ -232,6 +240,7 @@ func (cli *client) createDisk(kt *kit.Kit, accountID string, region string, addS
 
 	logs.Infof("[%s] sync disk to create disk success, accountID: %s, count: %d, rid: %s", enumor.Aws,
 		accountID, len(addSlice), kt.Rid)
+	common.RecordSyncCreated(kt, enumor.DiskCloudResType, len(addSlice))
 
 	return nil
 }
@@ -257,6 +266,23 @@ func (cli *client) deleteDisk(kt *kit.Kit, accountID string, region string, delC
 		return fmt.Errorf("validate disk not exist failed, before delete")
 	}
 
+	guardOpt := &common.SyncDeletionGuardOption{
+		Vendor:      enumor.Aws,
+		ResType:     enumor.DiskCloudResType,
+		AccountID:   accountID,
+		Region:      region,
+		RegionField: "region",
+		CloudIDs:    delCloudIDs,
+	}
+	delCloudIDs, err = common.GuardSyncDeletion(kt, cli.dbCli, guardOpt)
+	if err != nil {
+		return err
+	}
+
+	if len(delCloudIDs) == 0 {
+		return nil
+	}
+
 	deleteReq := &disk.DiskDeleteReq{
 		Filter: tools.ContainersExpression("cloud_id", delCloudIDs),
 	}
@@ -268,6 +294,11 @@ func (cli *client) deleteDisk(kt *kit.Kit, accountID string, region string, delC
 
 	logs.Infof("[%s] sync disk to delete disk success, accountID: %s, count: %d, rid: %s", enumor.Aws,
 		accountID, len(delCloudIDs), kt.Rid)
+	common.RecordSyncDeleted(kt, enumor.DiskCloudResType, len(delCloudIDs))
+
+	if err = common.ConfirmSyncDeletion(kt, cli.dbCli, guardOpt, delCloudIDs); err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -282,6 +313,7 @@ func (cli *client) listDiskFromCloud(kt *kit.Kit, params *SyncBaseParams) ([]ada
 		CloudIDs: params.CloudIDs,
 	}
 
+	common.RecordCloudApiCall(kt)
 	result, _, err := cli.cloudCli.ListDisk(kt, opt)
 	if err != nil {
 		if strings.Contains(err.Error(), aws.ErrDiskNotFound) {
@@ -410,6 +442,7 @@ func (cli *client) listRemoveDiskID(kt *kit.Kit, params *SyncBaseParams) ([]stri
 			Region:   params.Region,
 			CloudIDs: cloudIDs,
 		}
+		common.RecordCloudApiCall(kt)
 		_, _, err := cli.cloudCli.ListDisk(kt, opt)
 		if err != nil {
 			if strings.Contains(err.Error(), aws.ErrDiskNotFound) {
@@ -468,3 +501,20 @@ func isDiskChange(cloud adaptordisk.AwsDisk, db *coredisk.Disk[coredisk.AwsExten
 
 	return false
 }
+
+// syncDiskTag 使用云上硬盘标签覆盖db中的硬盘标签
+func (cli *client) syncDiskTag(kt *kit.Kit, accountID string, diskFromCloud []adaptordisk.AwsDisk) error {
+	tagMap := make(map[string][]corecloud.TagPair, len(diskFromCloud))
+	for _, one := range diskFromCloud {
+		tags := make([]corecloud.TagPair, 0, len(one.Tags))
+		for _, tag := range one.Tags {
+			tags = append(tags, corecloud.TagPair{
+				Key:   converter.PtrToVal(tag.Key),
+				Value: converter.PtrToVal(tag.Value),
+			})
+		}
+		tagMap[one.GetCloudID()] = tags
+	}
+
+	return common.SyncResourceTag(kt, cli.dbCli, enumor.Aws, accountID, enumor.DiskCloudResType, tagMap)
+}
@@ -25,6 +25,7 @@ import (
 	"hcm/cmd/hc-service/logics/res-sync/common"
 	typeseip "hcm/pkg/adaptor/types/eip"
 	"hcm/pkg/api/core"
+	corecloud "hcm/pkg/api/core/cloud"
 	dataeip "hcm/pkg/api/data-service/cloud/eip"
 	"hcm/pkg/criteria/constant"
 	"hcm/pkg/criteria/enumor"
@@ -55,6 +56,8 @@ func (cli *client) Eip(kt *kit.Kit, params *SyncBaseParams, opt *SyncEipOption)
 		return nil, errf.NewFromErr(errf.InvalidParameter, err)
 	}
 
+	kt, stat := common.ForkSyncStat(kt)
+
 	eipFromCloud, err := cli.listEipFromCloud(kt, params)
 	if err != nil {
 		return nil, err
@@ -66,11 +69,12 @@ func (cli *client) Eip(kt *kit.Kit, params *SyncBaseParams, opt *SyncEipOption)
 	}
 
 	if len(eipFromCloud) == 0 && len(eipFromDB) == 0 {
-		return new(SyncResult), nil
+		return &SyncResult{Counts: stat.Counts()}, nil
 	}
 
 	addEip, updateMap, delCloudIDs := common.Diff[*typeseip.AwsEip,
-		*dataeip.EipExtResult[dataeip.AwsEipExtensionResult]](eipFromCloud, eipFromDB, isEipChange)
+		*dataeip.EipExtResult[dataeip.AwsEipExtensionResult]](
+		kt, enumor.EipCloudResType, eipFromCloud, eipFromDB, isEipChange)
 
 	if len(delCloudIDs) > 0 {
 		if err = cli.deleteEip(kt, params.AccountID, params.Region, delCloudIDs); err != nil {
@@ -90,7 +94,11 @@ func (cli *client) Eip(kt *kit.Kit, params *SyncBaseParams, opt *SyncEipOption)
 		}
 	}
 
-	return new(SyncResult), nil
+	if err = cli.syncEipTag(kt, params.AccountID, eipFromCloud); err != nil {
+		return nil, err
+	}
+
+	return &SyncResult{Counts: stat.Counts()}, nil
 }
 
 // RemoveEipDeleteFromCloud ...
@@ -184,6 +192,23 @@ func (cli *client) deleteEip(kt *kit.Kit, accountID string, region string, delCl
 		return fmt.Errorf("validate eip not exist failed, before delete")
 	}
 
+	guardOpt := &common.SyncDeletionGuardOption{
+		Vendor:      enumor.Aws,
+		ResType:     enumor.EipCloudResType,
+		AccountID:   accountID,
+		Region:      region,
+		RegionField: "region",
+		CloudIDs:    delCloudIDs,
+	}
+	delCloudIDs, err = common.GuardSyncDeletion(kt, cli.dbCli, guardOpt)
+	if err != nil {
+		return err
+	}
+
+	if len(delCloudIDs) == 0 {
+		return nil
+	}
+
 	deleteReq := &dataeip.EipDeleteReq{
 		Filter: tools.ContainersExpression("cloud_id", delCloudIDs),
 	}
@@ -194,6 +219,11 @@ func (cli *client) deleteEip(kt *kit.Kit, accountID string, region string, delCl
 
 	logs.Infof("[%s] sync eip to delete eip success, accountID: %s, count: %d, rid: %s", enumor.Aws,
 		accountID, len(delCloudIDs), kt.Rid)
+	common.RecordSyncDeleted(kt, enumor.EipCloudResType, len(delCloudIDs))
+
+	if err = common.ConfirmSyncDeletion(kt, cli.dbCli, guardOpt, delCloudIDs); err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -229,6 +259,7 @@ func (cli *client) updateEip(kt *kit.Kit, accountID string, updateMap map[string
 
 	logs.Infof("[%s] sync eip to update eip success, accountID: %s, count: %d, rid: %s", enumor.Aws,
 		accountID, len(updateMap), kt.Rid)
+	common.RecordSyncUpdated(kt, enumor.EipCloudResType, len(updateMap))
 
 	return nil
 }
@@ -269,6 +300,7 @@ func (cli *client) createEip(kt *kit.Kit, accountID string, addEip []*typeseip.A
 
 	logs.Infof("[%s] sync eip to create eip success, accountID: %s, count: %d, rid: %s", enumor.Aws,
 		accountID, len(addEip), kt.Rid)
+	common.RecordSyncCreated(kt, enumor.EipCloudResType, len(addEip))
 
 	return nil
 }
@@ -282,6 +314,7 @@ func (cli *client) listEipFromCloud(kt *kit.Kit, params *SyncBaseParams) ([]*typ
 		Region:   params.Region,
 		CloudIDs: params.CloudIDs,
 	}
+	common.RecordCloudApiCall(kt)
 	result, err := cli.cloudCli.ListEip(kt, opt)
 	if err != nil {
 		logs.Errorf("[%s] list eip from cloud failed, err: %v, account: %s, opt: %v, rid: %s", enumor.Aws, err,
@@ -363,3 +396,13 @@ func isEipChange(cloud *typeseip.AwsEip, db *dataeip.EipExtResult[dataeip.AwsEip
 
 	return false
 }
+
+// syncEipTag 使用云上eip标签覆盖db中的eip标签
+func (cli *client) syncEipTag(kt *kit.Kit, accountID string, eipFromCloud []*typeseip.AwsEip) error {
+	tagMap := make(map[string][]corecloud.TagPair, len(eipFromCloud))
+	for _, one := range eipFromCloud {
+		tagMap[one.GetCloudID()] = one.Tags
+	}
+
+	return common.SyncResourceTag(kt, cli.dbCli, enumor.Aws, accountID, enumor.EipCloudResType, tagMap)
+}
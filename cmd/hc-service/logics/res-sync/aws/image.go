@@ -55,6 +55,8 @@ func (cli *client) Image(kt *kit.Kit, params *SyncBaseParams, opt *SyncImageOpti
 		return nil, errf.NewFromErr(errf.InvalidParameter, err)
 	}
 
+	kt, stat := common.ForkSyncStat(kt)
+
 	imageFromCloud, err := cli.listImageFromCloud(kt, params)
 	if err != nil {
 		return nil, err
@@ -66,11 +68,11 @@ func (cli *client) Image(kt *kit.Kit, params *SyncBaseParams, opt *SyncImageOpti
 	}
 
 	if len(imageFromCloud) == 0 && len(imageFromDB) == 0 {
-		return new(SyncResult), nil
+		return &SyncResult{Counts: stat.Counts()}, nil
 	}
 
 	addSlice, updateMap, delCloudIDs := common.Diff[typesimage.AwsImage, coreimage.Image[coreimage.AwsExtension]](
-		imageFromCloud, imageFromDB, isImageChange)
+		kt, enumor.ImageCloudResType, imageFromCloud, imageFromDB, isImageChange)
 
 	if len(delCloudIDs) > 0 {
 		if err := cli.deleteImage(kt, params.AccountID, params.Region, delCloudIDs); err != nil {
@@ -90,7 +92,7 @@ func (cli *client) Image(kt *kit.Kit, params *SyncBaseParams, opt *SyncImageOpti
 		}
 	}
 
-	return new(SyncResult), nil
+	return &SyncResult{Counts: stat.Counts()}, nil
 }
 
 func (cli *client) updateImage(kt *kit.Kit, accountID string, region string,
@@ -120,6 +122,7 @@ func (cli *client) updateImage(kt *kit.Kit, accountID string, region string,
 
 	logs.Infof("[%s] sync image to update image success, accountID: %s, count: %d, rid: %s", enumor.Aws,
 		accountID, len(updateMap), kt.Rid)
+	common.RecordSyncUpdated(kt, enumor.ImageCloudResType, len(updateMap))
 
 	return nil
 }
@@ -159,6 +162,7 @@ func (cli *client) createImage(kt *kit.Kit, accountID string, region string,
 
 	logs.Infof("[%s] sync image to create image success, accountID: %s, count: %d, rid: %s", enumor.Aws,
 		accountID, len(addSlice), kt.Rid)
+	common.RecordSyncCreated(kt, enumor.ImageCloudResType, len(addSlice))
 
 	return nil
 }
@@ -168,6 +172,10 @@ func (cli *client) deleteImage(kt *kit.Kit, accountID string, region string, del
 		return fmt.Errorf("image delCloudIDs is <= 0, not delete")
 	}
 
+	if common.RecordDryRunDelete(kt, enumor.ImageCloudResType, delCloudIDs) {
+		return nil
+	}
+
 	checkParams := &SyncBaseParams{
 		AccountID: accountID,
 		Region:    region,
@@ -194,6 +202,7 @@ func (cli *client) deleteImage(kt *kit.Kit, accountID string, region string, del
 
 	logs.Infof("[%s] sync image to delete image success, accountID: %s, count: %d, rid: %s", enumor.Aws,
 		accountID, len(delCloudIDs), kt.Rid)
+	common.RecordSyncDeleted(kt, enumor.ImageCloudResType, len(delCloudIDs))
 
 	return nil
 }
@@ -207,6 +216,7 @@ func (cli *client) listImageFromCloud(kt *kit.Kit, params *SyncBaseParams) ([]ty
 		Region:   params.Region,
 		CloudIDs: params.CloudIDs,
 	}
+	common.RecordCloudApiCall(kt)
 	result, err := cli.cloudCli.ListImage(kt, opt)
 	if err != nil {
 		if strings.Contains(err.Error(), aws.ErrImageNotFound) {
@@ -337,6 +347,7 @@ func (cli *client) listRemoveImageID(kt *kit.Kit, params *SyncBaseParams) ([]str
 			Region:   params.Region,
 			CloudIDs: []string{id},
 		}
+		common.RecordCloudApiCall(kt)
 		_, err := cli.cloudCli.ListImage(kt, opt)
 		if err != nil {
 			if strings.Contains(err.Error(), aws.ErrImageNotFound) {
@@ -56,6 +56,8 @@ func (cli *client) Region(kt *kit.Kit, opt *SyncRegionOption) (*SyncResult, erro
 		return nil, errf.NewFromErr(errf.InvalidParameter, err)
 	}
 
+	kt, stat := common.ForkSyncStat(kt)
+
 	regionFromCloud, err := cli.listRegionFromCloud(kt, opt)
 	if err != nil {
 		return nil, err
@@ -67,11 +69,11 @@ func (cli *client) Region(kt *kit.Kit, opt *SyncRegionOption) (*SyncResult, erro
 	}
 
 	if len(regionFromCloud) == 0 && len(regionFromDB) == 0 {
-		return new(SyncResult), nil
+		return &SyncResult{Counts: stat.Counts()}, nil
 	}
 
 	addSlice, updateMap, delCloudIDs := common.Diff[typesregion.AwsRegion, cloudcore.AwsRegion](
-		regionFromCloud, regionFromDB, isRegionChange)
+		kt, enumor.RegionCloudResType, regionFromCloud, regionFromDB, isRegionChange)
 
 	if len(delCloudIDs) > 0 {
 		if err := cli.deleteRegion(kt, opt, delCloudIDs); err != nil {
@@ -91,7 +93,7 @@ func (cli *client) Region(kt *kit.Kit, opt *SyncRegionOption) (*SyncResult, erro
 		}
 	}
 
-	return new(SyncResult), nil
+	return &SyncResult{Counts: stat.Counts()}, nil
 }
 
 func (cli *client) createRegion(kt *kit.Kit, opt *SyncRegionOption,
@@ -126,6 +128,7 @@ func (cli *client) createRegion(kt *kit.Kit, opt *SyncRegionOption,
 
 	logs.Infof("[%s] sync region to create region success, accountID: %s, count: %d, rid: %s", enumor.Aws,
 		opt.AccountID, len(addSlice), kt.Rid)
+	common.RecordSyncCreated(kt, enumor.RegionCloudResType, len(addSlice))
 
 	return nil
 }
@@ -162,6 +165,7 @@ func (cli *client) updateRegion(kt *kit.Kit, opt *SyncRegionOption,
 
 	logs.Infof("[%s] sync region to update region success, accountID: %s, count: %d, rid: %s", enumor.Aws,
 		opt.AccountID, len(updateMap), kt.Rid)
+	common.RecordSyncUpdated(kt, enumor.RegionCloudResType, len(updateMap))
 
 	return nil
 }
@@ -171,6 +175,10 @@ func (cli *client) deleteRegion(kt *kit.Kit, opt *SyncRegionOption, delCloudIDs
 		return errors.New("region delCloudIDs is <= 0, not delete")
 	}
 
+	if common.RecordDryRunDelete(kt, enumor.RegionCloudResType, delCloudIDs) {
+		return nil
+	}
+
 	delRegionFromCloud, err := cli.listRegionFromCloud(kt, opt)
 	if err != nil {
 		return err
@@ -202,6 +210,7 @@ func (cli *client) deleteRegion(kt *kit.Kit, opt *SyncRegionOption, delCloudIDs
 
 	logs.Infof("[%s] sync region to delete region success, accountID: %s, count: %d, rid: %s", enumor.Aws,
 		opt.AccountID, len(delCloudIDs), kt.Rid)
+	common.RecordSyncDeleted(kt, enumor.RegionCloudResType, len(delCloudIDs))
 
 	return nil
 }
@@ -211,6 +220,7 @@ func (cli *client) listRegionFromCloud(kt *kit.Kit, opt *SyncRegionOption) ([]ty
 		return nil, errf.NewFromErr(errf.InvalidParameter, err)
 	}
 
+	common.RecordCloudApiCall(kt)
 	results, err := cli.cloudCli.ListRegion(kt)
 	if err != nil {
 		logs.Errorf("[%s] list region from cloud failed, err: %v, account: %s, opt: %v, rid: %s", enumor.Aws,
@@ -24,6 +24,7 @@ import (
 	"strings"
 
 	"hcm/cmd/hc-service/logics/res-sync/common"
+	relmgr "hcm/cmd/hc-service/logics/res-sync/rel-manager"
 	"hcm/pkg/adaptor/aws"
 	adcore "hcm/pkg/adaptor/types/core"
 	typesroutetable "hcm/pkg/adaptor/types/route-table"
@@ -57,6 +58,8 @@ func (cli *client) RouteTable(kt *kit.Kit, params *SyncBaseParams, opt *SyncRout
 		return nil, errf.NewFromErr(errf.InvalidParameter, err)
 	}
 
+	kt, stat := common.ForkSyncStat(kt)
+
 	routeTableFromCloud, err := cli.listRouteTableFromCloud(kt, params)
 	if err != nil {
 		return nil, err
@@ -68,61 +71,44 @@ func (cli *client) RouteTable(kt *kit.Kit, params *SyncBaseParams, opt *SyncRout
 	}
 
 	if len(routeTableFromCloud) == 0 && len(routeTableFromDB) == 0 {
-		return new(SyncResult), nil
+		return &SyncResult{Counts: stat.Counts()}, nil
 	}
 
 	addSlice, updateMap, delCloudIDs := common.Diff[typesroutetable.AwsRouteTable,
-		routetable.AwsRouteTable](routeTableFromCloud, routeTableFromDB, isRouteTableChange)
-
-	subnetMap := make(map[string]dataproto.RouteTableSubnetReq, 0)
+		routetable.AwsRouteTable](
+		kt, enumor.RouteTableCloudResType, routeTableFromCloud, routeTableFromDB, isRouteTableChange)
 
 	if len(delCloudIDs) > 0 {
-		err = common.CancelRouteTableSubnetRel(kt, cli.dbCli, enumor.Aws, delCloudIDs)
-		if err != nil {
-			logs.Errorf("[%s] routetable batch cancel subnet rel failed. deleteIDs: %v, err: %v",
-				enumor.Aws, delCloudIDs, err)
-			return nil, err
-		}
 		if err = cli.deleteRouteTable(kt, params.AccountID, params.Region, delCloudIDs); err != nil {
 			return nil, err
 		}
 	}
 
 	if len(addSlice) > 0 {
-		addSubnetMap, err := cli.createRouteTable(kt, params.AccountID, params.Region, addSlice)
-		if err != nil {
+		if err = cli.createRouteTable(kt, params.AccountID, params.Region, addSlice); err != nil {
 			return nil, err
 		}
-		for k, v := range addSubnetMap {
-			subnetMap[k] = v
-		}
 	}
 
 	if len(updateMap) > 0 {
-		updateSubnetMap, err := cli.updateRouteTalbe(kt, params.AccountID, params.Region, updateMap)
-		if err != nil {
+		if err = cli.updateRouteTalbe(kt, params.AccountID, params.Region, updateMap); err != nil {
 			return nil, err
 		}
-		for k, v := range updateSubnetMap {
-			subnetMap[k] = v
-		}
 	}
 
-	// 更新子网的路由表信息
-	if len(subnetMap) > 0 {
-		err = common.UpdateSubnetRouteTableByIDs(kt, enumor.Aws, subnetMap, cli.dbCli)
-		if err != nil {
-			logs.Errorf("[%s] routetable update subnet's route_table failed. accountID: %s, region: %s, err: %v",
-				enumor.Aws, params.AccountID, params.Region, err)
-			return nil, err
-		}
+	// 以云上路由表关联的子网为准，对账子网的路由表信息
+	if err = relmgr.ReconcileFromCloud(kt, cli.dbCli, enumor.Aws, relmgr.RouteTableSubnetRel,
+		routeTableFromCloud); err != nil {
+		logs.Errorf("[%s] reconcile route table subnet rel failed, accountID: %s, err: %v, rid: %s",
+			enumor.Aws, params.AccountID, err, kt.Rid)
+		return nil, err
 	}
 
 	if err = cli.syncCloud(kt, params); err != nil {
 		return nil, err
 	}
 
-	return new(SyncResult), nil
+	return &SyncResult{Counts: stat.Counts()}, nil
 }
 
 func (cli *client) syncCloud(kt *kit.Kit, params *SyncBaseParams) error {
@@ -151,13 +137,12 @@ func (cli *client) syncCloud(kt *kit.Kit, params *SyncBaseParams) error {
 }
 
 func (cli *client) createRouteTable(kt *kit.Kit, accountID string, resGroupName string,
-	addSlice []typesroutetable.AwsRouteTable) (map[string]dataproto.RouteTableSubnetReq, error) {
+	addSlice []typesroutetable.AwsRouteTable) error {
 
 	if len(addSlice) <= 0 {
-		return nil, fmt.Errorf("routeTable addSlice is <= 0, not create")
+		return fmt.Errorf("routeTable addSlice is <= 0, not create")
 	}
 
-	subnetMap := make(map[string]dataproto.RouteTableSubnetReq, 0)
 	createResources := make([]dataproto.RouteTableCreateReq[dataproto.AwsRouteTableCreateExt], 0, len(addSlice))
 
 	for _, one := range addSlice {
@@ -174,11 +159,6 @@ func (cli *client) createRouteTable(kt *kit.Kit, accountID string, resGroupName
 			tmpRes.Extension = &dataproto.AwsRouteTableCreateExt{
 				Main: one.Extension.Main,
 			}
-			for _, subnetItem := range one.Extension.Associations {
-				subnetMap[converter.PtrToVal(subnetItem.CloudSubnetID)] = dataproto.RouteTableSubnetReq{
-					CloudRouteTableID: one.CloudID,
-				}
-			}
 		}
 
 		createResources = append(createResources, tmpRes)
@@ -191,23 +171,23 @@ func (cli *client) createRouteTable(kt *kit.Kit, accountID string, resGroupName
 	if err != nil {
 		logs.Errorf("[%s] routetable batch compare db create failed. accountID: %s, resGroupName: %s, err: %v",
 			enumor.Aws, accountID, resGroupName, err)
-		return subnetMap, err
+		return err
 	}
 
 	logs.Infof("[%s] sync routeTable to create routeTable success, accountID: %s, count: %d, rid: %s", enumor.Aws,
 		accountID, len(addSlice), kt.Rid)
+	common.RecordSyncCreated(kt, enumor.RouteTableCloudResType, len(addSlice))
 
-	return subnetMap, nil
+	return nil
 }
 
 func (cli *client) updateRouteTalbe(kt *kit.Kit, accountID string, resGroupName string,
-	updateMap map[string]typesroutetable.AwsRouteTable) (map[string]dataproto.RouteTableSubnetReq, error) {
+	updateMap map[string]typesroutetable.AwsRouteTable) error {
 
 	if len(updateMap) <= 0 {
-		return nil, fmt.Errorf("routeTable updateMap is <= 0, not update")
+		return fmt.Errorf("routeTable updateMap is <= 0, not update")
 	}
 
-	subnetMap := make(map[string]dataproto.RouteTableSubnetReq, 0)
 	updateResources := make([]dataproto.RouteTableBaseInfoUpdateReq, 0, len(updateMap))
 
 	for id, one := range updateMap {
@@ -218,14 +198,6 @@ func (cli *client) updateRouteTalbe(kt *kit.Kit, accountID string, resGroupName
 			Name: converter.ValToPtr(one.Name),
 			Memo: one.Memo,
 		}
-		if one.Extension != nil && len(one.Extension.Associations) > 0 {
-			for _, subnetItem := range one.Extension.Associations {
-				subnetMap[converter.PtrToVal(subnetItem.CloudSubnetID)] = dataproto.RouteTableSubnetReq{
-					RouteTableID:      id,
-					CloudRouteTableID: one.CloudID,
-				}
-			}
-		}
 
 		updateResources = append(updateResources, tmpRes)
 	}
@@ -236,13 +208,14 @@ func (cli *client) updateRouteTalbe(kt *kit.Kit, accountID string, resGroupName
 	if err := cli.dbCli.Global.RouteTable.BatchUpdateBaseInfo(kt.Ctx, kt.Header(), updateReq); err != nil {
 		logs.Errorf("[%s] routetable batch compare db update failed. accountID: %s, resGroupName: %s, err: %v",
 			enumor.Aws, accountID, resGroupName, err)
-		return subnetMap, err
+		return err
 	}
 
 	logs.Infof("[%s] sync routeTable to update routeTable success, accountID: %s, count: %d, rid: %s", enumor.Aws,
 		accountID, len(updateMap), kt.Rid)
+	common.RecordSyncUpdated(kt, enumor.RouteTableCloudResType, len(updateMap))
 
-	return subnetMap, nil
+	return nil
 }
 
 func (cli *client) deleteRouteTable(kt *kit.Kit, accountID string, region string, delCloudIDs []string) error {
@@ -266,6 +239,29 @@ func (cli *client) deleteRouteTable(kt *kit.Kit, accountID string, region string
 		return fmt.Errorf("validate routeTable not exist failed, before delete")
 	}
 
+	guardOpt := &common.SyncDeletionGuardOption{
+		Vendor:      enumor.Aws,
+		ResType:     enumor.RouteTableCloudResType,
+		AccountID:   accountID,
+		Region:      region,
+		RegionField: "region",
+		CloudIDs:    delCloudIDs,
+	}
+	delCloudIDs, err = common.GuardSyncDeletion(kt, cli.dbCli, guardOpt)
+	if err != nil {
+		return err
+	}
+
+	if len(delCloudIDs) == 0 {
+		return nil
+	}
+
+	// 删除路由表前需要先清理子网上的路由表关联关系
+	if err = relmgr.Remove(kt, cli.dbCli, enumor.Aws, relmgr.RouteTableSubnetRel, delCloudIDs); err != nil {
+		logs.Errorf("[%s] remove route table subnet rel failed, err: %v, rid: %s", enumor.Aws, err, kt.Rid)
+		return err
+	}
+
 	deleteReq := &dataservice.BatchDeleteReq{
 		Filter: tools.ContainersExpression("cloud_id", delCloudIDs),
 	}
@@ -276,6 +272,11 @@ func (cli *client) deleteRouteTable(kt *kit.Kit, accountID string, region string
 
 	logs.Infof("[%s] sync routeTable to delete routeTable success, accountID: %s, count: %d, rid: %s", enumor.Aws,
 		accountID, len(delCloudIDs), kt.Rid)
+	common.RecordSyncDeleted(kt, enumor.RouteTableCloudResType, len(delCloudIDs))
+
+	if err = common.ConfirmSyncDeletion(kt, cli.dbCli, guardOpt, delCloudIDs); err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -291,6 +292,7 @@ func (cli *client) listRouteTableFromCloud(kt *kit.Kit, params *SyncBaseParams)
 			CloudIDs: params.CloudIDs,
 		},
 	}
+	common.RecordCloudApiCall(kt)
 	result, err := cli.cloudCli.ListRouteTable(kt, opt)
 	if err != nil {
 		if strings.Contains(err.Error(), aws.ErrRouteTableNotFound) {
@@ -423,6 +425,7 @@ func (cli *client) listRemoveRouteTableID(kt *kit.Kit, params *SyncBaseParams) (
 				CloudIDs: []string{one},
 			},
 		}
+		common.RecordCloudApiCall(kt)
 		_, err := cli.cloudCli.ListRouteTable(kt, opt)
 		if err != nil {
 			if strings.Contains(err.Error(), aws.ErrRouteTableNotFound) {
@@ -56,6 +56,8 @@ func (cli *client) SecurityGroup(kt *kit.Kit, params *SyncBaseParams, opt *SyncS
 		return nil, errf.NewFromErr(errf.InvalidParameter, err)
 	}
 
+	kt, stat := common.ForkSyncStat(kt)
+
 	sgFromCloud, err := cli.listSGFromCloud(kt, params)
 	if err != nil {
 		return nil, err
@@ -67,11 +69,11 @@ func (cli *client) SecurityGroup(kt *kit.Kit, params *SyncBaseParams, opt *SyncS
 	}
 
 	if len(sgFromCloud) == 0 && len(sgFromDB) == 0 {
-		return new(SyncResult), nil
+		return &SyncResult{Counts: stat.Counts()}, nil
 	}
 
 	addSlice, updateMap, delCloudIDs := common.Diff[securitygroup.AwsSG, cloudcore.SecurityGroup[cloudcore.AwsSecurityGroupExtension]](
-		sgFromCloud, sgFromDB, isSGChange)
+		kt, enumor.SecurityGroupCloudResType, sgFromCloud, sgFromDB, isSGChange)
 
 	if len(delCloudIDs) > 0 {
 		if err := cli.deleteSG(kt, params.AccountID, params.Region, delCloudIDs); err != nil {
@@ -92,6 +94,10 @@ func (cli *client) SecurityGroup(kt *kit.Kit, params *SyncBaseParams, opt *SyncS
 		}
 	}
 
+	if err = cli.syncSGTag(kt, params.AccountID, sgFromCloud); err != nil {
+		return nil, err
+	}
+
 	// 同步安全组规则
 	sgFromDB, err = cli.listSGFromDB(kt, params)
 	if err != nil {
@@ -115,7 +121,7 @@ func (cli *client) SecurityGroup(kt *kit.Kit, params *SyncBaseParams, opt *SyncS
 		return nil, err
 	}
 
-	return new(SyncResult), nil
+	return &SyncResult{Counts: stat.Counts()}, nil
 }
 
 func (cli *client) updateSG(kt *kit.Kit, accountID string, region string,
@@ -171,6 +177,7 @@ func (cli *client) updateSG(kt *kit.Kit, accountID string, region string,
 
 	logs.Infof("[%s] sync sg to update sg success, accountID: %s, count: %d, rid: %s", enumor.Aws,
 		accountID, len(updateMap), kt.Rid)
+	common.RecordSyncUpdated(kt, enumor.SecurityGroupCloudResType, len(updateMap))
 
 	return nil
 }
@@ -229,6 +236,7 @@ func (cli *client) createSG(kt *kit.Kit, accountID string, region string,
 
 	logs.Infof("[%s] sync sg to create sg success, accountID: %s, count: %d, rid: %s", enumor.Aws,
 		accountID, len(addSlice), kt.Rid)
+	common.RecordSyncCreated(kt, enumor.SecurityGroupCloudResType, len(addSlice))
 
 	return results.IDs, nil
 }
@@ -254,6 +262,23 @@ func (cli *client) deleteSG(kt *kit.Kit, accountID string, region string, delClo
 		return fmt.Errorf("validate sg not exist failed, before delete")
 	}
 
+	guardOpt := &common.SyncDeletionGuardOption{
+		Vendor:      enumor.Aws,
+		ResType:     enumor.SecurityGroupCloudResType,
+		AccountID:   accountID,
+		Region:      region,
+		RegionField: "region",
+		CloudIDs:    delCloudIDs,
+	}
+	delCloudIDs, err = common.GuardSyncDeletion(kt, cli.dbCli, guardOpt)
+	if err != nil {
+		return err
+	}
+
+	if len(delCloudIDs) == 0 {
+		return nil
+	}
+
 	deleteReq := &protocloud.SecurityGroupBatchDeleteReq{
 		Filter: tools.ContainersExpression("cloud_id", delCloudIDs),
 	}
@@ -265,6 +290,11 @@ func (cli *client) deleteSG(kt *kit.Kit, accountID string, region string, delClo
 
 	logs.Infof("[%s] sync sg to delete sg success, accountID: %s, count: %d, rid: %s", enumor.Aws,
 		accountID, len(delCloudIDs), kt.Rid)
+	common.RecordSyncDeleted(kt, enumor.SecurityGroupCloudResType, len(delCloudIDs))
+
+	if err = common.ConfirmSyncDeletion(kt, cli.dbCli, guardOpt, delCloudIDs); err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -278,6 +308,7 @@ func (cli *client) listSGFromCloud(kt *kit.Kit, params *SyncBaseParams) ([]secur
 		Region:   params.Region,
 		CloudIDs: params.CloudIDs,
 	}
+	common.RecordCloudApiCall(kt)
 	result, _, err := cli.cloudCli.ListSecurityGroup(kt, opt)
 	if err != nil {
 		if strings.Contains(err.Error(), aws.ErrSGNotFound) {
@@ -411,6 +442,7 @@ func (cli *client) listRemoveSGID(kt *kit.Kit, params *SyncBaseParams) ([]string
 			Region:   params.Region,
 			CloudIDs: []string{one},
 		}
+		common.RecordCloudApiCall(kt)
 		_, _, err := cli.cloudCli.ListSecurityGroup(kt, opt)
 		if err != nil {
 			if strings.Contains(err.Error(), aws.ErrSGNotFound) {
@@ -514,3 +546,20 @@ func convVpcCloudIDMap(result []cloudcore.Vpc[cloudcore.AwsVpcExtension]) map[st
 	}
 	return m
 }
+
+// syncSGTag 使用云上安全组标签覆盖db中的安全组标签
+func (cli *client) syncSGTag(kt *kit.Kit, accountID string, sgFromCloud []securitygroup.AwsSG) error {
+	tagMap := make(map[string][]cloudcore.TagPair, len(sgFromCloud))
+	for _, one := range sgFromCloud {
+		tags := make([]cloudcore.TagPair, 0, len(one.Tags))
+		for _, tag := range one.Tags {
+			tags = append(tags, cloudcore.TagPair{
+				Key:   converter.PtrToVal(tag.Key),
+				Value: converter.PtrToVal(tag.Value),
+			})
+		}
+		tagMap[one.GetCloudID()] = tags
+	}
+
+	return common.SyncResourceTag(kt, cli.dbCli, enumor.Aws, accountID, enumor.SecurityGroupCloudResType, tagMap)
+}
@@ -55,6 +55,8 @@ func (cli *client) SecurityGroupRule(kt *kit.Kit, params *SyncBaseParams, opt *S
 		return nil, errf.NewFromErr(errf.InvalidParameter, err)
 	}
 
+	kt, stat := common.ForkSyncStat(kt)
+
 	sgFromDB, err := cli.listSGFromDB(kt, params)
 	if err != nil {
 		return nil, err
@@ -88,7 +90,7 @@ func (cli *client) SecurityGroupRule(kt *kit.Kit, params *SyncBaseParams, opt *S
 		return nil, err
 	}
 
-	return new(SyncResult), nil
+	return &SyncResult{Counts: stat.Counts()}, nil
 }
 
 type syncSGRuleOption struct {
@@ -124,7 +126,8 @@ func (cli *client) securityGroupRule(kt *kit.Kit, opt *syncSGRuleOption) (*SyncR
 	}
 
 	addSlice, updateMap, delCloudIDs := common.Diff[securitygrouprule.AwsSGRule,
-		corecloud.AwsSecurityGroupRule](sgRuleFromCloud, sgRuleFromDB, isSGRuleChange)
+		corecloud.AwsSecurityGroupRule](
+		kt, enumor.SecurityGroupRuleCloudResType, sgRuleFromCloud, sgRuleFromDB, isSGRuleChange)
 
 	if len(delCloudIDs) > 0 {
 		err := cli.deleteSGRule(kt, opt, delCloudIDs)
@@ -300,6 +303,10 @@ func (cli *client) deleteSGRule(kt *kit.Kit, opt *syncSGRuleOption, delCloudIDs
 		return fmt.Errorf("sgRule delCloudIDs is <= 0, not delete")
 	}
 
+	if common.RecordDryRunDelete(kt, enumor.SecurityGroupRuleCloudResType, delCloudIDs) {
+		return nil
+	}
+
 	if _, exsit := opt.SGMap[opt.CloudSGID]; !exsit {
 		return fmt.Errorf("cloud_sgid: %s can not find hcm sgid", opt.CloudSGID)
 	}
@@ -354,6 +361,7 @@ func (cli *client) listSGRuleFromCloud(kt *kit.Kit, opt *syncSGRuleOption) ([]se
 		CloudSecurityGroupID: opt.CloudSGID,
 	}
 
+	common.RecordCloudApiCall(kt)
 	rules, err := cli.cloudCli.ListSecurityGroupRule(kt, sgRuleopt)
 	if err != nil {
 		logs.Errorf("[%s] request adaptor to list aws security group rule failed, err: %v, rid: %s", enumor.Aws,
@@ -57,6 +57,8 @@ func (cli *client) SubAccount(kt *kit.Kit, opt *SyncSubAccountOption) (*SyncResu
 		return nil, errf.NewFromErr(errf.InvalidParameter, err)
 	}
 
+	kt, stat := common.ForkSyncStat(kt)
+
 	fromCloud, err := cli.listSubAccountFromCloud(kt, opt)
 	if err != nil {
 		return nil, err
@@ -68,11 +70,12 @@ func (cli *client) SubAccount(kt *kit.Kit, opt *SyncSubAccountOption) (*SyncResu
 	}
 
 	if len(fromCloud) == 0 && len(fromDB) == 0 {
-		return new(SyncResult), nil
+		return &SyncResult{Counts: stat.Counts()}, nil
 	}
 
 	addSlice, updateMap, delCloudIDs := common.Diff[account.AwsAccount,
-		coresubaccount.SubAccount[coresubaccount.AwsExtension]](fromCloud, fromDB, isSubAccountChange)
+		coresubaccount.SubAccount[coresubaccount.AwsExtension]](
+		kt, enumor.SubAccountCloudResType, fromCloud, fromDB, isSubAccountChange)
 
 	if len(delCloudIDs) > 0 {
 		if err = cli.deleteSubAccount(kt, opt, delCloudIDs); err != nil {
@@ -92,7 +95,7 @@ func (cli *client) SubAccount(kt *kit.Kit, opt *SyncSubAccountOption) (*SyncResu
 		}
 	}
 
-	return new(SyncResult), nil
+	return &SyncResult{Counts: stat.Counts()}, nil
 }
 
 func (cli *client) updateSubAccount(kt *kit.Kit, opt *SyncSubAccountOption,
@@ -156,6 +159,7 @@ func (cli *client) updateSubAccount(kt *kit.Kit, opt *SyncSubAccountOption,
 
 	logs.Infof("[%s] sync sub account to update sub account success, accountID: %s, count: %d, rid: %s", enumor.Aws,
 		opt.AccountID, len(updateMap), kt.Rid)
+	common.RecordSyncUpdated(kt, enumor.SubAccountCloudResType, len(updateMap))
 
 	return nil
 }
@@ -220,6 +224,7 @@ func (cli *client) createSubAccount(kt *kit.Kit, opt *SyncSubAccountOption, addS
 
 	logs.Infof("[%s] sync sub account to create sub account success, accountID: %s, count: %d, rid: %s", enumor.Aws,
 		opt.AccountID, len(addSlice), kt.Rid)
+	common.RecordSyncCreated(kt, enumor.SubAccountCloudResType, len(addSlice))
 
 	return nil
 }
@@ -230,6 +235,10 @@ func (cli *client) deleteSubAccount(kt *kit.Kit, opt *SyncSubAccountOption, delC
 		return errors.New("delCloudIDs is required")
 	}
 
+	if common.RecordDryRunDelete(kt, enumor.SubAccountCloudResType, delCloudIDs) {
+		return nil
+	}
+
 	delFromCloud, err := cli.listSubAccountFromCloud(kt, opt)
 	if err != nil {
 		return err
@@ -261,6 +270,7 @@ func (cli *client) deleteSubAccount(kt *kit.Kit, opt *SyncSubAccountOption, delC
 
 	logs.Infof("[%s] sync sub account to delete sub account success, accountID: %s, count: %d, rid: %s", enumor.Aws,
 		opt.AccountID, len(delCloudIDs), kt.Rid)
+	common.RecordSyncDeleted(kt, enumor.SubAccountCloudResType, len(delCloudIDs))
 
 	return nil
 }
@@ -287,6 +297,7 @@ func (cli *client) listSubAccountFromCloud(kt *kit.Kit, opt *SyncSubAccountOptio
 		return nil, errf.NewFromErr(errf.InvalidParameter, err)
 	}
 
+	common.RecordCloudApiCall(kt)
 	results, err := cli.cloudCli.ListAccount(kt)
 	if err != nil {
 		logs.Errorf("[%s] list sub account from cloud failed, err: %v, account: %s, opt: %v, rid: %s", enumor.Aws,
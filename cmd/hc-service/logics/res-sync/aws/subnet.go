@@ -58,6 +58,8 @@ func (cli *client) Subnet(kt *kit.Kit, params *SyncBaseParams, opt *SyncSubnetOp
 		return nil, errf.NewFromErr(errf.InvalidParameter, err)
 	}
 
+	kt, stat := common.ForkSyncStat(kt)
+
 	subnetFromCloud, err := cli.listSubnetFromCloud(kt, params)
 	if err != nil {
 		return nil, err
@@ -69,11 +71,11 @@ func (cli *client) Subnet(kt *kit.Kit, params *SyncBaseParams, opt *SyncSubnetOp
 	}
 
 	if len(subnetFromCloud) == 0 && len(subnetFromDB) == 0 {
-		return new(SyncResult), nil
+		return &SyncResult{Counts: stat.Counts()}, nil
 	}
 
 	addSubnet, updateMap, delCloudIDs := common.Diff[adtysubnet.AwsSubnet, cloudcore.Subnet[cloudcore.AwsSubnetExtension]](
-		subnetFromCloud, subnetFromDB, isAwsSubnetChange)
+		kt, enumor.SubnetCloudResType, subnetFromCloud, subnetFromDB, isAwsSubnetChange)
 
 	if len(delCloudIDs) > 0 {
 		if err = cli.deleteSubnet(kt, params.AccountID, params.Region, delCloudIDs); err != nil {
@@ -93,6 +95,10 @@ func (cli *client) Subnet(kt *kit.Kit, params *SyncBaseParams, opt *SyncSubnetOp
 		}
 	}
 
+	if err = cli.syncSubnetTag(kt, params.AccountID, subnetFromCloud); err != nil {
+		return nil, err
+	}
+
 	return nil, nil
 }
 
@@ -117,6 +123,23 @@ func (cli *client) deleteSubnet(kt *kit.Kit, accountID string, region string, de
 		return fmt.Errorf("validate subnet not exist failed, before delete")
 	}
 
+	guardOpt := &common.SyncDeletionGuardOption{
+		Vendor:      enumor.Aws,
+		ResType:     enumor.SubnetCloudResType,
+		AccountID:   accountID,
+		Region:      region,
+		RegionField: "region",
+		CloudIDs:    delCloudIDs,
+	}
+	delCloudIDs, err = common.GuardSyncDeletion(kt, cli.dbCli, guardOpt)
+	if err != nil {
+		return err
+	}
+
+	if len(delCloudIDs) == 0 {
+		return nil
+	}
+
 	deleteReq := &dataservice.BatchDeleteReq{
 		Filter: tools.ContainersExpression("cloud_id", delCloudIDs),
 	}
@@ -127,6 +150,11 @@ func (cli *client) deleteSubnet(kt *kit.Kit, accountID string, region string, de
 
 	logs.Infof("[%s] sync subnet to delete subnet success, accountID: %s, count: %d, rid: %s", enumor.Aws,
 		accountID, len(delCloudIDs), kt.Rid)
+	common.RecordSyncDeleted(kt, enumor.SubnetCloudResType, len(delCloudIDs))
+
+	if err = common.ConfirmSyncDeletion(kt, cli.dbCli, guardOpt, delCloudIDs); err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -172,6 +200,7 @@ func (cli *client) updateSubnet(kt *kit.Kit, accountID string, updateMap map[str
 
 	logs.Infof("[%s] sync subnet to update subnet success, accountID: %s, count: %d, rid: %s", enumor.Aws,
 		accountID, len(updateMap), kt.Rid)
+	common.RecordSyncUpdated(kt, enumor.SubnetCloudResType, len(updateMap))
 
 	return nil
 }
@@ -245,6 +274,7 @@ func (cli *client) createSubnet(kt *kit.Kit, accountID, region string, addSubnet
 
 	logs.Infof("[%s] sync subnet to create subnet success, accountID: %s, count: %d, rid: %s", enumor.Aws,
 		accountID, len(addSubnets), kt.Rid)
+	common.RecordSyncCreated(kt, enumor.SubnetCloudResType, len(addSubnets))
 
 	return nil
 }
@@ -372,6 +402,7 @@ func (cli *client) listRemoveSubnetID(kt *kit.Kit, params *SyncBaseParams) ([]st
 			Region:   params.Region,
 			CloudIDs: cloudIDs,
 		}
+		common.RecordCloudApiCall(kt)
 		_, err := cli.cloudCli.ListSubnet(kt, opt)
 		if err != nil {
 			if strings.Contains(err.Error(), aws.ErrSubnetNotFound) {
@@ -406,6 +437,7 @@ func (cli *client) listSubnetFromCloud(kt *kit.Kit, params *SyncBaseParams) ([]a
 		Region:   params.Region,
 		CloudIDs: params.CloudIDs,
 	}
+	common.RecordCloudApiCall(kt)
 	result, err := cli.cloudCli.ListSubnet(kt, opt)
 	if err != nil {
 		if strings.Contains(err.Error(), aws.ErrSubnetNotFound) {
@@ -459,3 +491,13 @@ func (cli *client) listSubnetFromDB(kt *kit.Kit, params *SyncBaseParams) (
 
 	return result.Details, nil
 }
+
+// syncSubnetTag 使用云上子网标签覆盖db中的子网标签
+func (cli *client) syncSubnetTag(kt *kit.Kit, accountID string, subnetFromCloud []adtysubnet.AwsSubnet) error {
+	tagMap := make(map[string][]cloudcore.TagPair, len(subnetFromCloud))
+	for _, one := range subnetFromCloud {
+		tagMap[one.GetCloudID()] = one.Tags
+	}
+
+	return common.SyncResourceTag(kt, cli.dbCli, enumor.Aws, accountID, enumor.SubnetCloudResType, tagMap)
+}
@@ -58,6 +58,8 @@ func (cli *client) Vpc(kt *kit.Kit, params *SyncBaseParams, opt *SyncVpcOption)
 		return nil, errf.NewFromErr(errf.InvalidParameter, err)
 	}
 
+	kt, stat := common.ForkSyncStat(kt)
+
 	vpcFromCloud, err := cli.listVpcFromCloud(kt, params)
 	if err != nil {
 		return nil, err
@@ -69,11 +71,11 @@ func (cli *client) Vpc(kt *kit.Kit, params *SyncBaseParams, opt *SyncVpcOption)
 	}
 
 	if len(vpcFromCloud) == 0 && len(vpcFromDB) == 0 {
-		return new(SyncResult), nil
+		return &SyncResult{Counts: stat.Counts()}, nil
 	}
 
 	addVpc, updateMap, delCloudIDs := common.Diff[types.AwsVpc, cloudcore.Vpc[cloudcore.AwsVpcExtension]](
-		vpcFromCloud, vpcFromDB, isAwsVpcChange)
+		kt, enumor.VpcCloudResType, vpcFromCloud, vpcFromDB, isAwsVpcChange)
 
 	if len(delCloudIDs) > 0 {
 		if err = cli.deleteVpc(kt, params.AccountID, params.Region, delCloudIDs); err != nil {
@@ -93,6 +95,10 @@ func (cli *client) Vpc(kt *kit.Kit, params *SyncBaseParams, opt *SyncVpcOption)
 		}
 	}
 
+	if err = cli.syncVpcTag(kt, params.AccountID, vpcFromCloud); err != nil {
+		return nil, err
+	}
+
 	return nil, nil
 }
 
@@ -117,6 +123,23 @@ func (cli *client) deleteVpc(kt *kit.Kit, accountID string, region string, delCl
 		return fmt.Errorf("validate vpc not exist failed, before delete")
 	}
 
+	guardOpt := &common.SyncDeletionGuardOption{
+		Vendor:      enumor.Aws,
+		ResType:     enumor.VpcCloudResType,
+		AccountID:   accountID,
+		Region:      region,
+		RegionField: "region",
+		CloudIDs:    delCloudIDs,
+	}
+	delCloudIDs, err = common.GuardSyncDeletion(kt, cli.dbCli, guardOpt)
+	if err != nil {
+		return err
+	}
+
+	if len(delCloudIDs) == 0 {
+		return nil
+	}
+
 	deleteReq := &dataservice.BatchDeleteReq{
 		Filter: tools.ContainersExpression("cloud_id", delCloudIDs),
 	}
@@ -127,6 +150,11 @@ func (cli *client) deleteVpc(kt *kit.Kit, accountID string, region string, delCl
 
 	logs.Infof("[%s] sync vpc to delete vpc success, accountID: %s, count: %d, rid: %s", enumor.Aws,
 		accountID, len(delCloudIDs), kt.Rid)
+	common.RecordSyncDeleted(kt, enumor.VpcCloudResType, len(delCloudIDs))
+
+	if err = common.ConfirmSyncDeletion(kt, cli.dbCli, guardOpt, delCloudIDs); err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -179,6 +207,7 @@ func (cli *client) updateVpc(kt *kit.Kit, accountID string, updateMap map[string
 
 	logs.Infof("[%s] sync vpc to update vpc success, accountID: %s, count: %d, rid: %s", enumor.Aws,
 		accountID, len(updateMap), kt.Rid)
+	common.RecordSyncUpdated(kt, enumor.VpcCloudResType, len(updateMap))
 
 	return nil
 }
@@ -235,6 +264,7 @@ func (cli *client) createVpc(kt *kit.Kit, accountID string, addVpcs []types.AwsV
 
 	logs.Infof("[%s] sync vpc to create vpc success, accountID: %s, count: %d, rid: %s", enumor.Aws,
 		accountID, len(addVpcs), kt.Rid)
+	common.RecordSyncCreated(kt, enumor.VpcCloudResType, len(addVpcs))
 
 	return nil
 }
@@ -369,6 +399,7 @@ func (cli *client) listRemoveVpcID(kt *kit.Kit, params *SyncBaseParams) ([]strin
 			Region:   params.Region,
 			CloudIDs: cloudIDs,
 		}
+		common.RecordCloudApiCall(kt)
 		_, err := cli.cloudCli.ListVpc(kt, opt)
 		if err != nil {
 			if strings.Contains(err.Error(), aws.ErrVpcNotFound) {
@@ -403,6 +434,7 @@ func (cli *client) listVpcFromCloud(kt *kit.Kit, params *SyncBaseParams) ([]type
 		Region:   params.Region,
 		CloudIDs: params.CloudIDs,
 	}
+	common.RecordCloudApiCall(kt)
 	result, err := cli.cloudCli.ListVpc(kt, opt)
 	if err != nil {
 		if strings.Contains(err.Error(), aws.ErrVpcNotFound) {
@@ -456,3 +488,13 @@ func (cli *client) listVpcFromDB(kt *kit.Kit, params *SyncBaseParams) (
 
 	return result.Details, nil
 }
+
+// syncVpcTag 使用云上vpc标签覆盖db中的vpc标签
+func (cli *client) syncVpcTag(kt *kit.Kit, accountID string, vpcFromCloud []types.AwsVpc) error {
+	tagMap := make(map[string][]cloudcore.TagPair, len(vpcFromCloud))
+	for _, one := range vpcFromCloud {
+		tagMap[one.GetCloudID()] = one.Tags
+	}
+
+	return common.SyncResourceTag(kt, cli.dbCli, enumor.Aws, accountID, enumor.VpcCloudResType, tagMap)
+}
@@ -58,6 +58,8 @@ func (cli *client) Zone(kt *kit.Kit, opt *SyncZoneOption) (*SyncResult, error) {
 		return nil, errf.NewFromErr(errf.InvalidParameter, err)
 	}
 
+	kt, stat := common.ForkSyncStat(kt)
+
 	zoneFromCloud, err := cli.listZoneFromCloud(kt, opt)
 	if err != nil {
 		return nil, err
@@ -69,11 +71,11 @@ func (cli *client) Zone(kt *kit.Kit, opt *SyncZoneOption) (*SyncResult, error) {
 	}
 
 	if len(zoneFromCloud) == 0 && len(zoneFromDB) == 0 {
-		return new(SyncResult), nil
+		return &SyncResult{Counts: stat.Counts()}, nil
 	}
 
 	addSlice, updateMap, delCloudIDs := common.Diff[typeszone.AwsZone, corezone.BaseZone](
-		zoneFromCloud, zoneFromDB, isZoneChange)
+		kt, enumor.ZoneCloudResType, zoneFromCloud, zoneFromDB, isZoneChange)
 
 	if len(delCloudIDs) > 0 {
 		if err := cli.deleteZone(kt, opt, delCloudIDs); err != nil {
@@ -93,7 +95,7 @@ func (cli *client) Zone(kt *kit.Kit, opt *SyncZoneOption) (*SyncResult, error) {
 		}
 	}
 
-	return new(SyncResult), nil
+	return &SyncResult{Counts: stat.Counts()}, nil
 }
 
 func (cli *client) createZone(kt *kit.Kit, opt *SyncZoneOption, addSlice []typeszone.AwsZone) error {
@@ -126,6 +128,7 @@ func (cli *client) createZone(kt *kit.Kit, opt *SyncZoneOption, addSlice []types
 
 	logs.Infof("[%s] sync zone to create zone success, accountID: %s, count: %d, rid: %s", enumor.Aws,
 		opt.AccountID, len(addSlice), kt.Rid)
+	common.RecordSyncCreated(kt, enumor.ZoneCloudResType, len(addSlice))
 
 	return nil
 }
@@ -158,6 +161,7 @@ func (cli *client) updateZone(kt *kit.Kit, opt *SyncZoneOption,
 
 	logs.Infof("[%s] sync zone to update zone success, accountID: %s, count: %d, rid: %s", enumor.Aws,
 		opt.AccountID, len(updateMap), kt.Rid)
+	common.RecordSyncUpdated(kt, enumor.ZoneCloudResType, len(updateMap))
 
 	return nil
 }
@@ -167,6 +171,10 @@ func (cli *client) deleteZone(kt *kit.Kit, opt *SyncZoneOption, delCloudIDs []st
 		return errors.New("zone delCloudIDs is <= 0, not delete")
 	}
 
+	if common.RecordDryRunDelete(kt, enumor.ZoneCloudResType, delCloudIDs) {
+		return nil
+	}
+
 	delZoneFromCloud, err := cli.listZoneFromCloud(kt, opt)
 	if err != nil {
 		return err
@@ -197,6 +205,7 @@ func (cli *client) deleteZone(kt *kit.Kit, opt *SyncZoneOption, delCloudIDs []st
 
 	logs.Infof("[%s] sync zone to delete zone success, accountID: %s, count: %d, rid: %s", enumor.Aws,
 		opt.AccountID, len(delCloudIDs), kt.Rid)
+	common.RecordSyncDeleted(kt, enumor.ZoneCloudResType, len(delCloudIDs))
 
 	return nil
 }
@@ -209,6 +218,7 @@ func (cli *client) listZoneFromCloud(kt *kit.Kit, opt *SyncZoneOption) ([]typesz
 	zoneOpt := &typeszone.AwsZoneListOption{
 		Region: opt.Region,
 	}
+	common.RecordCloudApiCall(kt)
 	results, err := cli.cloudCli.ListZone(kt, zoneOpt)
 	if err != nil {
 		logs.Errorf("[%s] list zone from cloud failed, err: %v, account: %s, opt: %v, rid: %s", enumor.Aws,
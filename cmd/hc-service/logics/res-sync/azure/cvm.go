@@ -26,6 +26,7 @@ import (
 	typescore "hcm/pkg/adaptor/types/core"
 	typescvm "hcm/pkg/adaptor/types/cvm"
 	"hcm/pkg/api/core"
+	corecloud "hcm/pkg/api/core/cloud"
 	"hcm/pkg/api/core/cloud/cvm"
 	corecvm "hcm/pkg/api/core/cloud/cvm"
 	dataproto "hcm/pkg/api/data-service/cloud"
@@ -58,6 +59,8 @@ func (cli *client) Cvm(kt *kit.Kit, params *SyncBaseParams, opt *SyncCvmOption)
 		return nil, errf.NewFromErr(errf.InvalidParameter, err)
 	}
 
+	kt, stat := common.ForkSyncStat(kt)
+
 	cvmFromCloud, err := cli.listCvmFromCloud(kt, params)
 	if err != nil {
 		return nil, err
@@ -69,11 +72,11 @@ func (cli *client) Cvm(kt *kit.Kit, params *SyncBaseParams, opt *SyncCvmOption)
 	}
 
 	if len(cvmFromCloud) == 0 && len(cvmFromDB) == 0 {
-		return new(SyncResult), nil
+		return &SyncResult{Counts: stat.Counts()}, nil
 	}
 
 	addSlice, updateMap, delCloudIDs := common.Diff[typescvm.AzureCvm, corecvm.Cvm[cvm.AzureCvmExtension]](
-		cvmFromCloud, cvmFromDB, isCvmChange)
+		kt, enumor.CvmCloudResType, cvmFromCloud, cvmFromDB, isCvmChange)
 
 	if len(delCloudIDs) > 0 {
 		if err := cli.deleteCvm(kt, params.AccountID, params.ResourceGroupName, delCloudIDs); err != nil {
@@ -93,7 +96,11 @@ func (cli *client) Cvm(kt *kit.Kit, params *SyncBaseParams, opt *SyncCvmOption)
 		}
 	}
 
-	return new(SyncResult), nil
+	if err = cli.syncCvmTag(kt, params.AccountID, cvmFromCloud); err != nil {
+		return nil, err
+	}
+
+	return &SyncResult{Counts: stat.Counts()}, nil
 }
 
 func (cli *client) listCvmFromCloud(kt *kit.Kit, params *SyncBaseParams) ([]typescvm.AzureCvm, error) {
@@ -105,6 +112,7 @@ func (cli *client) listCvmFromCloud(kt *kit.Kit, params *SyncBaseParams) ([]type
 		ResourceGroupName: params.ResourceGroupName,
 		CloudIDs:          params.CloudIDs,
 	}
+	common.RecordCloudApiCall(kt)
 	result, err := cli.cloudCli.ListCvmByID(kt, opt)
 	if err != nil {
 		logs.Errorf("[%s] list cvm from cloud failed, err: %v, account: %s, opt: %v, rid: %s", enumor.Azure,
@@ -286,6 +294,7 @@ func (cli *client) createCvm(kt *kit.Kit, accountID string, resGroupName string,
 
 	logs.Infof("[%s] sync cvm to create cvm success, accountID: %s, count: %d, rid: %s", enumor.Azure,
 		accountID, len(addSlice), kt.Rid)
+	common.RecordSyncCreated(kt, enumor.CvmCloudResType, len(addSlice))
 
 	return nil
 }
@@ -407,6 +416,7 @@ func (cli *client) updateCvm(kt *kit.Kit, accountID string, resGroupName string,
 
 	logs.Infof("[%s] sync cvm to update cvm success, accountID: %s, count: %d, rid: %s", enumor.Azure,
 		accountID, len(updateMap), kt.Rid)
+	common.RecordSyncUpdated(kt, enumor.CvmCloudResType, len(updateMap))
 
 	return nil
 }
@@ -469,6 +479,7 @@ func (cli *client) getNIAssResMapFromNI(kt *kit.Kit, niIDs []string, resGroupNam
 		ResourceGroupName: resGroupName,
 		CloudIDs:          niIDs,
 	}
+	common.RecordCloudApiCall(kt)
 	netInterDatas, err := cli.cloudCli.ListNetworkInterfaceByID(kt, netInterOpt)
 	if err != nil {
 		logs.Errorf("[%s] request adaptor to list azure net interface failed, err: %v, rid: %s", enumor.Azure,
@@ -590,6 +601,22 @@ func (cli *client) deleteCvm(kt *kit.Kit, accountID string, resGroupName string,
 		return fmt.Errorf("validate cvm not exist failed, before delete")
 	}
 
+	guardOpt := &common.SyncDeletionGuardOption{
+		Vendor:    enumor.Azure,
+		ResType:   enumor.CvmCloudResType,
+		AccountID: accountID,
+		Region:    resGroupName,
+		CloudIDs:  delCloudIDs,
+	}
+	delCloudIDs, err = common.GuardSyncDeletion(kt, cli.dbCli, guardOpt)
+	if err != nil {
+		return err
+	}
+
+	if len(delCloudIDs) == 0 {
+		return nil
+	}
+
 	deleteReq := &dataproto.CvmBatchDeleteReq{
 		Filter: tools.ContainersExpression("cloud_id", delCloudIDs),
 	}
@@ -601,6 +628,11 @@ func (cli *client) deleteCvm(kt *kit.Kit, accountID string, resGroupName string,
 
 	logs.Infof("[%s] sync cvm to delete cvm success, accountID: %s, count: %d, rid: %s", enumor.Azure,
 		accountID, len(delCloudIDs), kt.Rid)
+	common.RecordSyncDeleted(kt, enumor.CvmCloudResType, len(delCloudIDs))
+
+	if err = common.ConfirmSyncDeletion(kt, cli.dbCli, guardOpt, delCloudIDs); err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -761,3 +793,17 @@ func isCvmChange(cloud typescvm.AzureCvm, db corecvm.Cvm[cvm.AzureCvmExtension])
 
 	return false
 }
+
+// syncCvmTag 使用云上主机标签覆盖db中的主机标签
+func (cli *client) syncCvmTag(kt *kit.Kit, accountID string, cvms []typescvm.AzureCvm) error {
+	tagMap := make(map[string][]corecloud.TagPair, len(cvms))
+	for _, one := range cvms {
+		tags := make([]corecloud.TagPair, 0, len(one.Tags))
+		for key, value := range one.Tags {
+			tags = append(tags, corecloud.TagPair{Key: key, Value: converter.PtrToVal(value)})
+		}
+		tagMap[one.GetCloudID()] = tags
+	}
+
+	return common.SyncResourceTag(kt, cli.dbCli, enumor.Azure, accountID, enumor.CvmCloudResType, tagMap)
+}
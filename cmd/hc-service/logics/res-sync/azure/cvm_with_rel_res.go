@@ -21,6 +21,7 @@ package azure
 
 import (
 	"fmt"
+	"hcm/cmd/hc-service/logics/res-sync/common"
 
 	cvmrelmgr "hcm/cmd/hc-service/logics/res-sync/cvm-rel-manager"
 	typescore "hcm/pkg/adaptor/types/core"
@@ -62,6 +63,8 @@ func (opt SyncCvmWithRelResOption) Validate() error {
 func (cli *client) CvmWithRelRes(kt *kit.Kit, params *SyncBaseParams, opt *SyncCvmWithRelResOption) (
 	*SyncResult, error) {
 
+	kt, stat := common.ForkSyncStat(kt)
+
 	cvmFromCloud, err := cli.listCvmFromCloud(kt, params)
 	if err != nil {
 		return nil, err
@@ -73,7 +76,7 @@ func (cli *client) CvmWithRelRes(kt *kit.Kit, params *SyncBaseParams, opt *SyncC
 			return nil, err
 		}
 
-		return new(SyncResult), nil
+		return &SyncResult{Counts: stat.Counts()}, nil
 	}
 
 	// step2: 获取cvm和关联资源的关联关系
@@ -252,7 +255,7 @@ func (cli *client) CvmWithRelRes(kt *kit.Kit, params *SyncBaseParams, opt *SyncC
 		return nil, err
 	}
 
-	return new(SyncResult), nil
+	return &SyncResult{Counts: stat.Counts()}, nil
 }
 
 func (cli *client) buildCvmRelManger(kt *kit.Kit, resGroupName string, cvmFromCloud []typecvm.AzureCvm) (
@@ -343,6 +346,7 @@ func (cli *client) getEipMapFromCloudByIP(kt *kit.Kit, resGroupName string, cvmF
 		ResourceGroupName: resGroupName,
 		CloudIDs:          converter.MapKeyToStringSlice(nis),
 	}
+	common.RecordCloudApiCall(kt)
 	resp, err := cli.cloudCli.ListNetworkInterfaceByID(kt, opt)
 	if err != nil {
 		logs.Errorf("[%s] list eip by ip from cloud failed, err: %v, account: %s, opt: %v, rid: %s",
@@ -26,6 +26,7 @@ import (
 	typescore "hcm/pkg/adaptor/types/core"
 	typesdisk "hcm/pkg/adaptor/types/disk"
 	"hcm/pkg/api/core"
+	corecloud "hcm/pkg/api/core/cloud"
 	coredisk "hcm/pkg/api/core/cloud/disk"
 	"hcm/pkg/api/data-service/cloud/disk"
 	"hcm/pkg/criteria/constant"
@@ -56,6 +57,8 @@ func (cli *client) Disk(kt *kit.Kit, params *SyncBaseParams, opt *SyncDiskOption
 		return nil, errf.NewFromErr(errf.InvalidParameter, err)
 	}
 
+	kt, stat := common.ForkSyncStat(kt)
+
 	diskFromCloud, err := cli.listDiskFromCloud(kt, params)
 	if err != nil {
 		return nil, err
@@ -67,7 +70,7 @@ func (cli *client) Disk(kt *kit.Kit, params *SyncBaseParams, opt *SyncDiskOption
 	}
 
 	if len(diskFromCloud) == 0 && len(diskFromDB) == 0 {
-		return new(SyncResult), nil
+		return &SyncResult{Counts: stat.Counts()}, nil
 	}
 
 	if opt.BootMap != nil {
@@ -78,7 +81,7 @@ func (cli *client) Disk(kt *kit.Kit, params *SyncBaseParams, opt *SyncDiskOption
 		}
 	}
 	addSlice, updateMap, delCloudIDs := common.Diff[typesdisk.AzureDisk, *coredisk.Disk[coredisk.AzureExtension]](
-		diskFromCloud, diskFromDB, isDiskChange)
+		kt, enumor.DiskCloudResType, diskFromCloud, diskFromDB, isDiskChange)
 
 	if len(delCloudIDs) > 0 {
 		if err := cli.deleteDisk(kt, params.AccountID, params.ResourceGroupName, delCloudIDs); err != nil {
@@ -98,7 +101,11 @@ func (cli *client) Disk(kt *kit.Kit, params *SyncBaseParams, opt *SyncDiskOption
 		}
 	}
 
-	return new(SyncResult), nil
+	if err = cli.syncDiskTag(kt, params.AccountID, diskFromCloud); err != nil {
+		return nil, err
+	}
+
+	return &SyncResult{Counts: stat.Counts()}, nil
 }
 
 func (cli *client) updateDisk(kt *kit.Kit, accountID string, resGroupName string,
@@ -139,6 +146,7 @@ func (cli *client) updateDisk(kt *kit.Kit, accountID string, resGroupName string
 
 	logs.Infof("[%s] sync disk to update disk success, accountID: %s, count: %d, rid: %s", enumor.Azure,
 		accountID, len(updateMap), kt.Rid)
+	common.RecordSyncUpdated(kt, enumor.DiskCloudResType, len(updateMap))
 
 	return nil
 }
@@ -186,6 +194,7 @@ func (cli *client) createDisk(kt *kit.Kit, accountID string, resGroupName string
 
 	logs.Infof("[%s] sync disk to create disk success, accountID: %s, count: %d, rid: %s", enumor.Azure,
 		accountID, len(addSlice), kt.Rid)
+	common.RecordSyncCreated(kt, enumor.DiskCloudResType, len(addSlice))
 
 	return nil
 }
@@ -213,6 +222,22 @@ func (cli *client) deleteDisk(kt *kit.Kit, accountID string, resGroupName string
 		return fmt.Errorf("validate disk not exist failed, before delete")
 	}
 
+	guardOpt := &common.SyncDeletionGuardOption{
+		Vendor:    enumor.Azure,
+		ResType:   enumor.DiskCloudResType,
+		AccountID: accountID,
+		Region:    resGroupName,
+		CloudIDs:  delCloudIDs,
+	}
+	delCloudIDs, err = common.GuardSyncDeletion(kt, cli.dbCli, guardOpt)
+	if err != nil {
+		return err
+	}
+
+	if len(delCloudIDs) == 0 {
+		return nil
+	}
+
 	deleteReq := &disk.DiskDeleteReq{
 		Filter: tools.ContainersExpression("cloud_id", delCloudIDs),
 	}
@@ -224,6 +249,11 @@ func (cli *client) deleteDisk(kt *kit.Kit, accountID string, resGroupName string
 
 	logs.Infof("[%s] sync disk to delete disk success, accountID: %s, count: %d, rid: %s", enumor.Azure,
 		accountID, len(delCloudIDs), kt.Rid)
+	common.RecordSyncDeleted(kt, enumor.DiskCloudResType, len(delCloudIDs))
+
+	if err = common.ConfirmSyncDeletion(kt, cli.dbCli, guardOpt, delCloudIDs); err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -237,6 +267,7 @@ func (cli *client) listDiskFromCloud(kt *kit.Kit, params *SyncBaseParams) ([]typ
 		ResourceGroupName: params.ResourceGroupName,
 		CloudIDs:          params.CloudIDs,
 	}
+	common.RecordCloudApiCall(kt)
 	result, err := cli.cloudCli.ListDiskByID(kt, opt)
 	if err != nil {
 		logs.Errorf("[%s] list disk from cloud failed, err: %v, account: %s, opt: %v, rid: %s", enumor.Azure,
@@ -386,3 +417,13 @@ func isDiskChange(cloud typesdisk.AzureDisk, db *coredisk.Disk[coredisk.AzureExt
 
 	return false
 }
+
+// syncDiskTag 使用云上硬盘标签覆盖db中的硬盘标签
+func (cli *client) syncDiskTag(kt *kit.Kit, accountID string, diskFromCloud []typesdisk.AzureDisk) error {
+	tagMap := make(map[string][]corecloud.TagPair, len(diskFromCloud))
+	for _, one := range diskFromCloud {
+		tagMap[one.GetCloudID()] = one.Tags
+	}
+
+	return common.SyncResourceTag(kt, cli.dbCli, enumor.Azure, accountID, enumor.DiskCloudResType, tagMap)
+}
@@ -26,6 +26,7 @@ import (
 	adcore "hcm/pkg/adaptor/types/core"
 	typeseip "hcm/pkg/adaptor/types/eip"
 	"hcm/pkg/api/core"
+	corecloud "hcm/pkg/api/core/cloud"
 	dataeip "hcm/pkg/api/data-service/cloud/eip"
 	"hcm/pkg/criteria/constant"
 	"hcm/pkg/criteria/enumor"
@@ -56,6 +57,8 @@ func (cli *client) Eip(kt *kit.Kit, params *SyncBaseParams, opt *SyncEipOption)
 		return nil, errf.NewFromErr(errf.InvalidParameter, err)
 	}
 
+	kt, stat := common.ForkSyncStat(kt)
+
 	eipFromCloud, err := cli.listEipFromCloud(kt, params)
 	if err != nil {
 		return nil, err
@@ -67,11 +70,12 @@ func (cli *client) Eip(kt *kit.Kit, params *SyncBaseParams, opt *SyncEipOption)
 	}
 
 	if len(eipFromCloud) == 0 && len(eipFromDB) == 0 {
-		return new(SyncResult), nil
+		return &SyncResult{Counts: stat.Counts()}, nil
 	}
 
 	addEip, updateMap, delCloudIDs := common.Diff[*typeseip.AzureEip,
-		*dataeip.EipExtResult[dataeip.AzureEipExtensionResult]](eipFromCloud, eipFromDB, isEipChange)
+		*dataeip.EipExtResult[dataeip.AzureEipExtensionResult]](
+		kt, enumor.EipCloudResType, eipFromCloud, eipFromDB, isEipChange)
 
 	if len(delCloudIDs) > 0 {
 		if err = cli.deleteEip(kt, params.AccountID, params.ResourceGroupName, delCloudIDs); err != nil {
@@ -91,7 +95,11 @@ func (cli *client) Eip(kt *kit.Kit, params *SyncBaseParams, opt *SyncEipOption)
 		}
 	}
 
-	return new(SyncResult), nil
+	if err = cli.syncEipTag(kt, params.AccountID, eipFromCloud); err != nil {
+		return nil, err
+	}
+
+	return &SyncResult{Counts: stat.Counts()}, nil
 }
 
 // RemoveEipDeleteFromCloud ...
@@ -186,6 +194,22 @@ func (cli *client) deleteEip(kt *kit.Kit, accountID string, resGroupName string,
 		return fmt.Errorf("validate eip not exist failed, before delete")
 	}
 
+	guardOpt := &common.SyncDeletionGuardOption{
+		Vendor:    enumor.Azure,
+		ResType:   enumor.EipCloudResType,
+		AccountID: accountID,
+		Region:    resGroupName,
+		CloudIDs:  delCloudIDs,
+	}
+	delCloudIDs, err = common.GuardSyncDeletion(kt, cli.dbCli, guardOpt)
+	if err != nil {
+		return err
+	}
+
+	if len(delCloudIDs) == 0 {
+		return nil
+	}
+
 	deleteReq := &dataeip.EipDeleteReq{
 		Filter: tools.ContainersExpression("cloud_id", delCloudIDs),
 	}
@@ -196,6 +220,11 @@ func (cli *client) deleteEip(kt *kit.Kit, accountID string, resGroupName string,
 
 	logs.Infof("[%s] sync eip to delete eip success, accountID: %s, count: %d, rid: %s", enumor.Azure,
 		accountID, len(delCloudIDs), kt.Rid)
+	common.RecordSyncDeleted(kt, enumor.EipCloudResType, len(delCloudIDs))
+
+	if err = common.ConfirmSyncDeletion(kt, cli.dbCli, guardOpt, delCloudIDs); err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -232,6 +261,7 @@ func (cli *client) updateEip(kt *kit.Kit, accountID string, updateMap map[string
 
 	logs.Infof("[%s] sync eip to update eip success, accountID: %s, count: %d, rid: %s", enumor.Azure,
 		accountID, len(updateMap), kt.Rid)
+	common.RecordSyncUpdated(kt, enumor.EipCloudResType, len(updateMap))
 
 	return nil
 }
@@ -274,6 +304,7 @@ func (cli *client) createEip(kt *kit.Kit, accountID string, bizID int64, addEip
 
 	logs.Infof("[%s] sync eip to create eip success, accountID: %s, count: %d, rid: %s", enumor.Azure,
 		accountID, len(addEip), kt.Rid)
+	common.RecordSyncCreated(kt, enumor.EipCloudResType, len(addEip))
 
 	return nil
 }
@@ -287,6 +318,7 @@ func (cli *client) listEipFromCloud(kt *kit.Kit, params *SyncBaseParams) ([]*typ
 		ResourceGroupName: params.ResourceGroupName,
 		CloudIDs:          params.CloudIDs,
 	}
+	common.RecordCloudApiCall(kt)
 	result, err := cli.cloudCli.ListEipByID(kt, opt)
 	if err != nil {
 		logs.Errorf("[%s] list eip from cloud failed, err: %v, account: %s, opt: %v, rid: %s", enumor.Azure, err,
@@ -372,3 +404,13 @@ func isEipChange(item *typeseip.AzureEip, info *dataeip.EipExtResult[dataeip.Azu
 
 	return false
 }
+
+// syncEipTag 使用云上eip标签覆盖db中的eip标签
+func (cli *client) syncEipTag(kt *kit.Kit, accountID string, eipFromCloud []*typeseip.AzureEip) error {
+	tagMap := make(map[string][]corecloud.TagPair, len(eipFromCloud))
+	for _, one := range eipFromCloud {
+		tagMap[one.GetCloudID()] = one.Tags
+	}
+
+	return common.SyncResourceTag(kt, cli.dbCli, enumor.Azure, accountID, enumor.EipCloudResType, tagMap)
+}
@@ -58,6 +58,8 @@ func (cli *client) Image(kt *kit.Kit, opt *SyncImageOption) (*SyncResult, error)
 		return nil, errf.NewFromErr(errf.InvalidParameter, err)
 	}
 
+	kt, stat := common.ForkSyncStat(kt)
+
 	imageFromCloud, err := cli.listImageFromCloud(kt, opt)
 	if err != nil {
 		return nil, err
@@ -69,11 +71,11 @@ func (cli *client) Image(kt *kit.Kit, opt *SyncImageOption) (*SyncResult, error)
 	}
 
 	if len(imageFromCloud) == 0 && len(imageFromDB) == 0 {
-		return new(SyncResult), nil
+		return &SyncResult{Counts: stat.Counts()}, nil
 	}
 
 	addSlice, updateMap, delCloudIDs := common.Diff[typesimage.AzureImage, coreimage.Image[coreimage.AzureExtension]](
-		imageFromCloud, imageFromDB, isImageChange)
+		kt, enumor.ImageCloudResType, imageFromCloud, imageFromDB, isImageChange)
 
 	if len(delCloudIDs) > 0 {
 		if err = cli.deleteImage(kt, opt, delCloudIDs); err != nil {
@@ -93,7 +95,7 @@ func (cli *client) Image(kt *kit.Kit, opt *SyncImageOption) (*SyncResult, error)
 		}
 	}
 
-	return new(SyncResult), nil
+	return &SyncResult{Counts: stat.Counts()}, nil
 }
 
 func (cli *client) updateImage(kt *kit.Kit, opt *SyncImageOption,
@@ -127,6 +129,7 @@ func (cli *client) updateImage(kt *kit.Kit, opt *SyncImageOption,
 
 	logs.Infof("[%s] sync image to update image success, accountID: %s, count: %d, rid: %s", enumor.Azure,
 		opt.AccountID, len(updateMap), kt.Rid)
+	common.RecordSyncUpdated(kt, enumor.ImageCloudResType, len(updateMap))
 
 	return nil
 }
@@ -172,6 +175,7 @@ func (cli *client) createImage(kt *kit.Kit, opt *SyncImageOption,
 
 	logs.Infof("[%s] sync image to create image success, accountID: %s, count: %d, rid: %s", enumor.Azure,
 		opt.AccountID, len(addSlice), kt.Rid)
+	common.RecordSyncCreated(kt, enumor.ImageCloudResType, len(addSlice))
 
 	return nil
 }
@@ -181,6 +185,10 @@ func (cli *client) deleteImage(kt *kit.Kit, opt *SyncImageOption, delCloudIDs []
 		return fmt.Errorf("image delCloudIDs is <= 0, not delete")
 	}
 
+	if common.RecordDryRunDelete(kt, enumor.ImageCloudResType, delCloudIDs) {
+		return nil
+	}
+
 	checkParams := &SyncImageOption{
 		AccountID: opt.AccountID,
 		Region:    opt.Region,
@@ -220,6 +228,7 @@ func (cli *client) deleteImage(kt *kit.Kit, opt *SyncImageOption, delCloudIDs []
 
 	logs.Infof("[%s] sync image to delete image success, accountID: %s, count: %d, rid: %s", enumor.Azure,
 		opt.AccountID, len(delCloudIDs), kt.Rid)
+	common.RecordSyncDeleted(kt, enumor.ImageCloudResType, len(delCloudIDs))
 
 	return nil
 }
@@ -234,6 +243,7 @@ func (cli *client) listImageFromCloud(kt *kit.Kit, opt *SyncImageOption) ([]type
 		Publisher: opt.Publisher,
 		Offer:     opt.Offer,
 	}
+	common.RecordCloudApiCall(kt)
 	result, err := cli.cloudCli.ListImage(kt, imageOpt)
 	if err != nil {
 		logs.Errorf("[%s] list image from cloud failed, err: %v, account: %s, opt: %v, rid: %s", enumor.Azure,
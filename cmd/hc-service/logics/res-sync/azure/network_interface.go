@@ -23,6 +23,7 @@ import (
 	"fmt"
 
 	"hcm/cmd/hc-service/logics/res-sync/common"
+	relmgr "hcm/cmd/hc-service/logics/res-sync/rel-manager"
 	adcore "hcm/pkg/adaptor/types/core"
 	typesni "hcm/pkg/adaptor/types/network-interface"
 	"hcm/pkg/api/core"
@@ -58,6 +59,8 @@ func (cli *client) NetworkInterface(kt *kit.Kit, params *SyncBaseParams, opt *Sy
 		return nil, errf.NewFromErr(errf.InvalidParameter, err)
 	}
 
+	kt, stat := common.ForkSyncStat(kt)
+
 	niFromCloud, err := cli.listNetworkInterfaceFromCloud(kt, params)
 	if err != nil {
 		return nil, err
@@ -69,11 +72,12 @@ func (cli *client) NetworkInterface(kt *kit.Kit, params *SyncBaseParams, opt *Sy
 	}
 
 	if len(niFromCloud) == 0 && len(niFromDB) == 0 {
-		return new(SyncResult), nil
+		return &SyncResult{Counts: stat.Counts()}, nil
 	}
 
 	addNetworkInterface, updateMap, delCloudIDs := common.Diff[typesni.AzureNI,
-		coreni.NetworkInterface[coreni.AzureNIExtension]](niFromCloud, niFromDB, isNIChange)
+		coreni.NetworkInterface[coreni.AzureNIExtension]](
+		kt, enumor.NetworkInterfaceCloudResType, niFromCloud, niFromDB, isNIChange)
 
 	if len(delCloudIDs) > 0 {
 		if err = cli.deleteNetworkInterface(kt, params.AccountID, params.ResourceGroupName, delCloudIDs); err != nil {
@@ -94,7 +98,13 @@ func (cli *client) NetworkInterface(kt *kit.Kit, params *SyncBaseParams, opt *Sy
 		}
 	}
 
-	return new(SyncResult), nil
+	// 以云上网络接口绑定的安全组为准，对账网络接口和安全组的关联关系
+	if err = relmgr.ReconcileFromCloud(kt, cli.dbCli, enumor.Azure, relmgr.NetworkInterfaceSecurityGroupRel, niFromCloud); err != nil {
+		logs.Errorf("[%s] reconcile network interface security group rel failed, err: %v, rid: %s", enumor.Azure, err, kt.Rid)
+		return nil, err
+	}
+
+	return &SyncResult{Counts: stat.Counts()}, nil
 }
 
 // RemoveNetworkInterfaceDeleteFromCloud ...
@@ -190,6 +200,28 @@ func (cli *client) deleteNetworkInterface(kt *kit.Kit, accountID string, resGrou
 		return fmt.Errorf("validate ni not exist failed, before delete")
 	}
 
+	guardOpt := &common.SyncDeletionGuardOption{
+		Vendor:    enumor.Azure,
+		ResType:   enumor.NetworkInterfaceCloudResType,
+		AccountID: accountID,
+		Region:    resGroupName,
+		CloudIDs:  delCloudIDs,
+	}
+	delCloudIDs, err = common.GuardSyncDeletion(kt, cli.dbCli, guardOpt)
+	if err != nil {
+		return err
+	}
+
+	if len(delCloudIDs) == 0 {
+		return nil
+	}
+
+	// 删除网络接口前清理网络接口和安全组的关联关系
+	if err = relmgr.Remove(kt, cli.dbCli, enumor.Azure, relmgr.NetworkInterfaceSecurityGroupRel, delCloudIDs); err != nil {
+		logs.Errorf("[%s] remove network interface security group rel failed, err: %v, rid: %s", enumor.Azure, err, kt.Rid)
+		return err
+	}
+
 	deleteReq := &dataservice.BatchDeleteReq{
 		Filter: tools.ContainersExpression("cloud_id", delCloudIDs),
 	}
@@ -200,6 +232,11 @@ func (cli *client) deleteNetworkInterface(kt *kit.Kit, accountID string, resGrou
 
 	logs.Infof("[%s] sync ni to delete ni success, accountID: %s, count: %d, rid: %s", enumor.Azure,
 		accountID, len(delCloudIDs), kt.Rid)
+	common.RecordSyncDeleted(kt, enumor.NetworkInterfaceCloudResType, len(delCloudIDs))
+
+	if err = common.ConfirmSyncDeletion(kt, cli.dbCli, guardOpt, delCloudIDs); err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -267,6 +304,7 @@ func (cli *client) updateNetworkInterface(kt *kit.Kit, accountID string, updateM
 
 	logs.Infof("[%s] sync ni to update ni success, accountID: %s, count: %d, rid: %s", enumor.Azure,
 		accountID, len(updateMap), kt.Rid)
+	common.RecordSyncUpdated(kt, enumor.NetworkInterfaceCloudResType, len(updateMap))
 
 	return nil
 }
@@ -346,6 +384,7 @@ func (cli *client) createNetworkInterface(kt *kit.Kit, accountID, resGroupName s
 
 	logs.Infof("[%s] sync ni to create ni success, accountID: %s, count: %d, rid: %s", enumor.Azure,
 		accountID, len(adds), kt.Rid)
+	common.RecordSyncCreated(kt, enumor.NetworkInterfaceCloudResType, len(adds))
 
 	return nil
 }
@@ -392,6 +431,7 @@ func (cli *client) listNetworkInterfaceFromCloud(kt *kit.Kit, params *SyncBasePa
 		ResourceGroupName: params.ResourceGroupName,
 		CloudIDs:          params.CloudIDs,
 	}
+	common.RecordCloudApiCall(kt)
 	result, err := cli.cloudCli.ListNetworkInterfaceByID(kt, opt)
 	if err != nil {
 		logs.Errorf("[%s] list ni from cloud failed, err: %v, account: %s, opt: %v, rid: %s", enumor.Azure, err,
@@ -54,6 +54,8 @@ func (cli *client) Region(kt *kit.Kit, opt *SyncRegionOption) (*SyncResult, erro
 		return nil, errf.NewFromErr(errf.InvalidParameter, err)
 	}
 
+	kt, stat := common.ForkSyncStat(kt)
+
 	regionFromCloud, err := cli.listRegionFromCloud(kt, opt)
 	if err != nil {
 		return nil, err
@@ -65,11 +67,11 @@ func (cli *client) Region(kt *kit.Kit, opt *SyncRegionOption) (*SyncResult, erro
 	}
 
 	if len(regionFromCloud) == 0 && len(regionFromDB) == 0 {
-		return new(SyncResult), nil
+		return &SyncResult{Counts: stat.Counts()}, nil
 	}
 
 	addSlice, updateMap, delCloudIDs := common.Diff[typesregion.AzureRegion, coreregion.AzureRegion](
-		regionFromCloud, regionFromDB, isRegionChange)
+		kt, enumor.RegionCloudResType, regionFromCloud, regionFromDB, isRegionChange)
 
 	if len(delCloudIDs) > 0 {
 		if err := cli.deleteRegion(kt, opt, delCloudIDs); err != nil {
@@ -89,7 +91,7 @@ func (cli *client) Region(kt *kit.Kit, opt *SyncRegionOption) (*SyncResult, erro
 		}
 	}
 
-	return new(SyncResult), nil
+	return &SyncResult{Counts: stat.Counts()}, nil
 }
 
 func (cli *client) createRegion(kt *kit.Kit, opt *SyncRegionOption,
@@ -124,6 +126,7 @@ func (cli *client) createRegion(kt *kit.Kit, opt *SyncRegionOption,
 
 	logs.Infof("[%s] sync region to create region success, accountID: %s, count: %d, rid: %s", enumor.Azure,
 		opt.AccountID, len(addSlice), kt.Rid)
+	common.RecordSyncCreated(kt, enumor.RegionCloudResType, len(addSlice))
 
 	return nil
 }
@@ -156,6 +159,7 @@ func (cli *client) updateRegion(kt *kit.Kit, opt *SyncRegionOption,
 
 	logs.Infof("[%s] sync region to update region success, accountID: %s, count: %d, rid: %s", enumor.Azure,
 		opt.AccountID, len(updateMap), kt.Rid)
+	common.RecordSyncUpdated(kt, enumor.RegionCloudResType, len(updateMap))
 
 	return nil
 }
@@ -165,6 +169,10 @@ func (cli *client) deleteRegion(kt *kit.Kit, opt *SyncRegionOption, delCloudIDs
 		return errors.New("region delCloudIDs is <= 0, not delete")
 	}
 
+	if common.RecordDryRunDelete(kt, enumor.RegionCloudResType, delCloudIDs) {
+		return nil
+	}
+
 	delRegionFromCloud, err := cli.listRegionFromCloud(kt, opt)
 	if err != nil {
 		return err
@@ -196,6 +204,7 @@ func (cli *client) deleteRegion(kt *kit.Kit, opt *SyncRegionOption, delCloudIDs
 
 	logs.Infof("[%s] sync region to delete region success, accountID: %s, count: %d, rid: %s", enumor.Azure,
 		opt.AccountID, len(delCloudIDs), kt.Rid)
+	common.RecordSyncDeleted(kt, enumor.RegionCloudResType, len(delCloudIDs))
 
 	return nil
 }
@@ -205,6 +214,7 @@ func (cli *client) listRegionFromCloud(kt *kit.Kit, opt *SyncRegionOption) ([]ty
 		return nil, errf.NewFromErr(errf.InvalidParameter, err)
 	}
 
+	common.RecordCloudApiCall(kt)
 	regions, err := cli.cloudCli.ListRegion(kt)
 	if err != nil {
 		logs.Errorf("[%s] list region from cloud failed, err: %v, account: %s, opt: %v, rid: %s", enumor.Azure,
@@ -55,6 +55,8 @@ func (cli *client) ResourceGroup(kt *kit.Kit, opt *SyncRGOption) (*SyncResult, e
 		return nil, errf.NewFromErr(errf.InvalidParameter, err)
 	}
 
+	kt, stat := common.ForkSyncStat(kt)
+
 	resourcegroupFromCloud, err := cli.listResourceGroupFromCloud(kt, opt)
 	if err != nil {
 		return nil, err
@@ -66,11 +68,11 @@ func (cli *client) ResourceGroup(kt *kit.Kit, opt *SyncRGOption) (*SyncResult, e
 	}
 
 	if len(resourcegroupFromCloud) == 0 && len(resourcegroupFromDB) == 0 {
-		return new(SyncResult), nil
+		return &SyncResult{Counts: stat.Counts()}, nil
 	}
 
 	addSlice, updateMap, delCloudIDs := common.Diff[typesrg.AzureResourceGroup, corerg.AzureRG](
-		resourcegroupFromCloud, resourcegroupFromDB, isResourceGroupChange)
+		kt, enumor.AzureResourceGroup, resourcegroupFromCloud, resourcegroupFromDB, isResourceGroupChange)
 
 	if len(delCloudIDs) > 0 {
 		if err := cli.deleteResourceGroup(kt, opt, delCloudIDs); err != nil {
@@ -90,7 +92,7 @@ func (cli *client) ResourceGroup(kt *kit.Kit, opt *SyncRGOption) (*SyncResult, e
 		}
 	}
 
-	return new(SyncResult), nil
+	return &SyncResult{Counts: stat.Counts()}, nil
 }
 
 func (cli *client) createResourceGroup(kt *kit.Kit, opt *SyncRGOption,
@@ -125,6 +127,7 @@ func (cli *client) createResourceGroup(kt *kit.Kit, opt *SyncRGOption,
 
 	logs.Infof("[%s] sync resourcegroup to create resourcegroup success, accountID: %s, count: %d, rid: %s", enumor.Azure,
 		opt.AccountID, len(addSlice), kt.Rid)
+	common.RecordSyncCreated(kt, enumor.AzureResourceGroup, len(addSlice))
 
 	return nil
 }
@@ -156,6 +159,7 @@ func (cli *client) updateResourceGroup(kt *kit.Kit, opt *SyncRGOption,
 
 	logs.Infof("[%s] sync resourcegroup to update resourcegroup success, accountID: %s, count: %d, rid: %s", enumor.Azure,
 		opt.AccountID, len(updateMap), kt.Rid)
+	common.RecordSyncUpdated(kt, enumor.AzureResourceGroup, len(updateMap))
 
 	return nil
 }
@@ -165,6 +169,10 @@ func (cli *client) deleteResourceGroup(kt *kit.Kit, opt *SyncRGOption, delCloudI
 		return errors.New("resourcegroup delCloudIDs is <= 0, not delete")
 	}
 
+	if common.RecordDryRunDelete(kt, enumor.AzureResourceGroup, delCloudIDs) {
+		return nil
+	}
+
 	delResourceGroupFromCloud, err := cli.listResourceGroupFromCloud(kt, opt)
 	if err != nil {
 		return err
@@ -194,6 +202,7 @@ func (cli *client) deleteResourceGroup(kt *kit.Kit, opt *SyncRGOption, delCloudI
 
 	logs.Infof("[%s] sync resourcegroup to delete resourcegroup success, accountID: %s, count: %d, rid: %s", enumor.Azure,
 		opt.AccountID, len(delCloudIDs), kt.Rid)
+	common.RecordSyncDeleted(kt, enumor.AzureResourceGroup, len(delCloudIDs))
 
 	return nil
 }
@@ -205,6 +214,7 @@ func (cli *client) listResourceGroupFromCloud(kt *kit.Kit,
 		return nil, errf.NewFromErr(errf.InvalidParameter, err)
 	}
 
+	common.RecordCloudApiCall(kt)
 	resourcegroups, err := cli.cloudCli.ListResourceGroup(kt)
 	if err != nil {
 		logs.Errorf("[%s] list resourcegroup from cloud failed, err: %v, account: %s, opt: %v, rid: %s", enumor.Azure,
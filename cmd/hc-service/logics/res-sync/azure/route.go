@@ -56,6 +56,8 @@ func (cli *client) Route(kt *kit.Kit, params *SyncBaseParams, opt *SyncRouteOpti
 		return nil, errf.NewFromErr(errf.InvalidParameter, err)
 	}
 
+	kt, stat := common.ForkSyncStat(kt)
+
 	err := concurrence.BaseExec(constant.SyncConcurrencyDefaultMaxLimit, params.CloudIDs, func(param string) error {
 		syncOpt := &syncRouteOption{
 			AccountID:         params.AccountID,
@@ -74,7 +76,7 @@ func (cli *client) Route(kt *kit.Kit, params *SyncBaseParams, opt *SyncRouteOpti
 		return nil, err
 	}
 
-	return new(SyncResult), nil
+	return &SyncResult{Counts: stat.Counts()}, nil
 }
 
 type syncRouteOption struct {
@@ -123,7 +125,7 @@ func (cli *client) route(kt *kit.Kit, opt *syncRouteOption) (*SyncResult, error)
 	}
 
 	addSlice, updateMap, delCloudIDs := common.Diff[typesroutetable.AzureRoute,
-		routetable.AzureRoute](routeFromCloud, routeFromDB, isRouteChange)
+		routetable.AzureRoute](kt, enumor.RouteCloudResType, routeFromCloud, routeFromDB, isRouteChange)
 
 	if len(delCloudIDs) > 0 {
 		if err = cli.deleteRoute(kt, opt.AccountID, opt.ResourceGroupName, opt.CloudRouteTableID, routeTable.ID,
@@ -183,6 +185,7 @@ func (cli *client) createRoute(kt *kit.Kit, accountID string, resGroupName strin
 
 	logs.Infof("[%s] sync route to create route success, accountID: %s, count: %d, rid: %s", enumor.Azure,
 		accountID, len(addSlice), kt.Rid)
+	common.RecordSyncCreated(kt, enumor.RouteCloudResType, len(addSlice))
 
 	return nil
 }
@@ -218,6 +221,7 @@ func (cli *client) updateRoute(kt *kit.Kit, accountID, resGroupName, routeTableI
 	}
 	logs.Infof("[%s] sync route to update route success, accountID: %s, count: %d, rid: %s", enumor.Azure,
 		accountID, len(updateMap), kt.Rid)
+	common.RecordSyncUpdated(kt, enumor.RouteCloudResType, len(updateMap))
 
 	return nil
 }
@@ -229,6 +233,10 @@ func (cli *client) deleteRoute(kt *kit.Kit, accountID, resGroupName, cloudRTID,
 		return fmt.Errorf("route delCloudIDs is <= 0, not delete")
 	}
 
+	if common.RecordDryRunDelete(kt, enumor.RouteCloudResType, delCloudIDs) {
+		return nil
+	}
+
 	checkParams := &syncRouteOption{
 		AccountID:         accountID,
 		ResourceGroupName: resGroupName,
@@ -259,6 +267,7 @@ func (cli *client) deleteRoute(kt *kit.Kit, accountID, resGroupName, cloudRTID,
 
 	logs.Infof("[%s] sync route to delete route success, accountID: %s, count: %d, rid: %s", enumor.Azure,
 		accountID, len(delCloudIDs), kt.Rid)
+	common.RecordSyncDeleted(kt, enumor.RouteCloudResType, len(delCloudIDs))
 
 	return nil
 }
@@ -273,6 +282,7 @@ func (cli *client) listRouteFromCloud(kt *kit.Kit, opt *syncRouteOption) ([]type
 		ResourceGroupName: opt.ResourceGroupName,
 		CloudIDs:          []string{opt.CloudRouteTableID},
 	}
+	common.RecordCloudApiCall(kt)
 	routeTables, err := cli.cloudCli.ListRouteTableByID(kt, routeOpt)
 	if err != nil {
 		logs.Errorf("[%s] list routeTable from cloud failed, err: %v, account: %s, opt: %v, rid: %s", enumor.Azure, err,
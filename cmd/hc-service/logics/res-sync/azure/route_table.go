@@ -23,6 +23,7 @@ import (
 	"fmt"
 
 	"hcm/cmd/hc-service/logics/res-sync/common"
+	relmgr "hcm/cmd/hc-service/logics/res-sync/rel-manager"
 	adcore "hcm/pkg/adaptor/types/core"
 	typesroutetable "hcm/pkg/adaptor/types/route-table"
 	"hcm/pkg/api/core"
@@ -55,6 +56,8 @@ func (cli *client) RouteTable(kt *kit.Kit, params *SyncBaseParams, opt *SyncRout
 		return nil, errf.NewFromErr(errf.InvalidParameter, err)
 	}
 
+	kt, stat := common.ForkSyncStat(kt)
+
 	routeTableFromCloud, err := cli.listRouteTableFromCloud(kt, params)
 	if err != nil {
 		return nil, err
@@ -66,61 +69,44 @@ func (cli *client) RouteTable(kt *kit.Kit, params *SyncBaseParams, opt *SyncRout
 	}
 
 	if len(routeTableFromCloud) == 0 && len(routeTableFromDB) == 0 {
-		return new(SyncResult), nil
+		return &SyncResult{Counts: stat.Counts()}, nil
 	}
 
 	addSlice, updateMap, delCloudIDs := common.Diff[typesroutetable.AzureRouteTable,
-		routetable.AzureRouteTable](routeTableFromCloud, routeTableFromDB, isRouteTableChange)
-
-	subnetMap := make(map[string]dataproto.RouteTableSubnetReq, 0)
+		routetable.AzureRouteTable](
+		kt, enumor.RouteTableCloudResType, routeTableFromCloud, routeTableFromDB, isRouteTableChange)
 
 	if len(delCloudIDs) > 0 {
-		err = common.CancelRouteTableSubnetRel(kt, cli.dbCli, enumor.Azure, delCloudIDs)
-		if err != nil {
-			logs.Errorf("[%s] routetable batch cancel subnet rel failed. deleteIDs: %v, err: %v",
-				enumor.Azure, delCloudIDs, err)
-			return nil, err
-		}
 		if err = cli.deleteRouteTable(kt, params.AccountID, params.ResourceGroupName, delCloudIDs); err != nil {
 			return nil, err
 		}
 	}
 
 	if len(addSlice) > 0 {
-		addSubnetMap, err := cli.createRouteTable(kt, params.AccountID, params.ResourceGroupName, addSlice)
-		if err != nil {
+		if err = cli.createRouteTable(kt, params.AccountID, params.ResourceGroupName, addSlice); err != nil {
 			return nil, err
 		}
-		for k, v := range addSubnetMap {
-			subnetMap[k] = v
-		}
 	}
 
 	if len(updateMap) > 0 {
-		updateSubnetMap, err := cli.updateRouteTalbe(kt, params.AccountID, params.ResourceGroupName, updateMap)
-		if err != nil {
+		if err = cli.updateRouteTalbe(kt, params.AccountID, params.ResourceGroupName, updateMap); err != nil {
 			return nil, err
 		}
-		for k, v := range updateSubnetMap {
-			subnetMap[k] = v
-		}
 	}
 
-	// 更新子网的路由表信息
-	if len(subnetMap) > 0 {
-		err = common.UpdateSubnetRouteTableByIDs(kt, enumor.Azure, subnetMap, cli.dbCli)
-		if err != nil {
-			logs.Errorf("[%s] routetable update subnet's route_table failed. accountID: %s, resGroupName: %s, err: %v",
-				enumor.Azure, params.AccountID, params.ResourceGroupName, err)
-			return nil, err
-		}
+	// 以云上路由表关联的子网为准，对账子网的路由表信息
+	if err = relmgr.ReconcileFromCloud(kt, cli.dbCli, enumor.Azure, relmgr.RouteTableSubnetRel,
+		routeTableFromCloud); err != nil {
+		logs.Errorf("[%s] reconcile route table subnet rel failed, accountID: %s, err: %v, rid: %s",
+			enumor.Azure, params.AccountID, err, kt.Rid)
+		return nil, err
 	}
 
 	if err = cli.syncRoute(kt, params); err != nil {
 		return nil, err
 	}
 
-	return new(SyncResult), nil
+	return &SyncResult{Counts: stat.Counts()}, nil
 }
 
 func (cli *client) syncRoute(kt *kit.Kit, params *SyncBaseParams) error {
@@ -158,6 +144,7 @@ func (cli *client) listRouteTableFromCloud(kt *kit.Kit, params *SyncBaseParams)
 		ResourceGroupName: params.ResourceGroupName,
 		CloudIDs:          params.CloudIDs,
 	}
+	common.RecordCloudApiCall(kt)
 	result, err := cli.cloudCli.ListRouteTableByID(kt, opt)
 	if err != nil {
 		logs.Errorf("[%s] list routeTable from cloud failed, err: %v, account: %s, opt: %v, rid: %s", enumor.Azure, err,
@@ -214,13 +201,12 @@ func (cli *client) listRouteTableFromDB(kt *kit.Kit, params *SyncBaseParams) (
 }
 
 func (cli *client) createRouteTable(kt *kit.Kit, accountID string, resGroupName string,
-	addSlice []typesroutetable.AzureRouteTable) (map[string]dataproto.RouteTableSubnetReq, error) {
+	addSlice []typesroutetable.AzureRouteTable) error {
 
 	if len(addSlice) <= 0 {
-		return nil, fmt.Errorf("routeTable addSlice is <= 0, not create")
+		return fmt.Errorf("routeTable addSlice is <= 0, not create")
 	}
 
-	subnetMap := make(map[string]dataproto.RouteTableSubnetReq, 0)
 	createResources := make([]dataproto.RouteTableCreateReq[dataproto.AzureRouteTableCreateExt], 0, len(addSlice))
 
 	for _, one := range addSlice {
@@ -237,11 +223,6 @@ func (cli *client) createRouteTable(kt *kit.Kit, accountID string, resGroupName
 			tmpRes.Extension = &dataproto.AzureRouteTableCreateExt{
 				ResourceGroupName: one.Extension.ResourceGroupName,
 			}
-			for _, tmpSubnetID := range one.Extension.CloudSubnetIDs {
-				subnetMap[tmpSubnetID] = dataproto.RouteTableSubnetReq{
-					CloudRouteTableID: one.CloudID,
-				}
-			}
 		}
 
 		createResources = append(createResources, tmpRes)
@@ -254,23 +235,23 @@ func (cli *client) createRouteTable(kt *kit.Kit, accountID string, resGroupName
 	if err != nil {
 		logs.Errorf("[%s] routetable batch compare db create failed. accountID: %s, resGroupName: %s, err: %v",
 			enumor.Azure, accountID, resGroupName, err)
-		return subnetMap, err
+		return err
 	}
 
 	logs.Infof("[%s] sync routeTable to create routeTable success, accountID: %s, count: %d, rid: %s", enumor.Azure,
 		accountID, len(addSlice), kt.Rid)
+	common.RecordSyncCreated(kt, enumor.RouteTableCloudResType, len(addSlice))
 
-	return subnetMap, nil
+	return nil
 }
 
 func (cli *client) updateRouteTalbe(kt *kit.Kit, accountID string, resGroupName string,
-	updateMap map[string]typesroutetable.AzureRouteTable) (map[string]dataproto.RouteTableSubnetReq, error) {
+	updateMap map[string]typesroutetable.AzureRouteTable) error {
 
 	if len(updateMap) <= 0 {
-		return nil, fmt.Errorf("routeTable updateMap is <= 0, not update")
+		return fmt.Errorf("routeTable updateMap is <= 0, not update")
 	}
 
-	subnetMap := make(map[string]dataproto.RouteTableSubnetReq, 0)
 	updateResources := make([]dataproto.RouteTableBaseInfoUpdateReq, 0, len(updateMap))
 
 	for id, one := range updateMap {
@@ -281,14 +262,6 @@ func (cli *client) updateRouteTalbe(kt *kit.Kit, accountID string, resGroupName
 			Name: converter.ValToPtr(one.Name),
 			Memo: one.Memo,
 		}
-		if one.Extension != nil && len(one.Extension.CloudSubnetIDs) > 0 {
-			for _, tmpSubnetID := range one.Extension.CloudSubnetIDs {
-				subnetMap[tmpSubnetID] = dataproto.RouteTableSubnetReq{
-					RouteTableID:      id,
-					CloudRouteTableID: one.CloudID,
-				}
-			}
-		}
 		updateResources = append(updateResources, tmpRes)
 	}
 
@@ -298,13 +271,14 @@ func (cli *client) updateRouteTalbe(kt *kit.Kit, accountID string, resGroupName
 	if err := cli.dbCli.Global.RouteTable.BatchUpdateBaseInfo(kt.Ctx, kt.Header(), updateReq); err != nil {
 		logs.Errorf("[%s] routetable batch compare db update failed. accountID: %s, resGroupName: %s, err: %v",
 			enumor.Azure, accountID, resGroupName, err)
-		return subnetMap, err
+		return err
 	}
 
 	logs.Infof("[%s] sync routeTable to update routeTable success, accountID: %s, count: %d, rid: %s", enumor.Azure,
 		accountID, len(updateMap), kt.Rid)
+	common.RecordSyncUpdated(kt, enumor.RouteTableCloudResType, len(updateMap))
 
-	return subnetMap, nil
+	return nil
 }
 
 func (cli *client) deleteRouteTable(kt *kit.Kit, accountID string, resGroupName string, delCloudIDs []string) error {
@@ -328,6 +302,28 @@ func (cli *client) deleteRouteTable(kt *kit.Kit, accountID string, resGroupName
 		return fmt.Errorf("validate routeTable not exist failed, before delete")
 	}
 
+	guardOpt := &common.SyncDeletionGuardOption{
+		Vendor:    enumor.Azure,
+		ResType:   enumor.RouteTableCloudResType,
+		AccountID: accountID,
+		Region:    resGroupName,
+		CloudIDs:  delCloudIDs,
+	}
+	delCloudIDs, err = common.GuardSyncDeletion(kt, cli.dbCli, guardOpt)
+	if err != nil {
+		return err
+	}
+
+	if len(delCloudIDs) == 0 {
+		return nil
+	}
+
+	// 删除路由表前需要先清理子网上的路由表关联关系
+	if err = relmgr.Remove(kt, cli.dbCli, enumor.Azure, relmgr.RouteTableSubnetRel, delCloudIDs); err != nil {
+		logs.Errorf("[%s] remove route table subnet rel failed, err: %v, rid: %s", enumor.Azure, err, kt.Rid)
+		return err
+	}
+
 	deleteReq := &dataservice.BatchDeleteReq{
 		Filter: tools.ContainersExpression("cloud_id", delCloudIDs),
 	}
@@ -338,6 +334,11 @@ func (cli *client) deleteRouteTable(kt *kit.Kit, accountID string, resGroupName
 
 	logs.Infof("[%s] sync routeTable to delete routeTable success, accountID: %s, count: %d, rid: %s", enumor.Azure,
 		accountID, len(delCloudIDs), kt.Rid)
+	common.RecordSyncDeleted(kt, enumor.RouteTableCloudResType, len(delCloudIDs))
+
+	if err = common.ConfirmSyncDeletion(kt, cli.dbCli, guardOpt, delCloudIDs); err != nil {
+		return err
+	}
 
 	return nil
 }
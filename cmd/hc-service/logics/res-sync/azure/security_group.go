@@ -54,6 +54,8 @@ func (cli *client) SecurityGroup(kt *kit.Kit, params *SyncBaseParams, opt *SyncS
 		return nil, errf.NewFromErr(errf.InvalidParameter, err)
 	}
 
+	kt, stat := common.ForkSyncStat(kt)
+
 	sgFromCloud, err := cli.listSGFromCloud(kt, params)
 	if err != nil {
 		return nil, err
@@ -65,11 +67,11 @@ func (cli *client) SecurityGroup(kt *kit.Kit, params *SyncBaseParams, opt *SyncS
 	}
 
 	if len(sgFromCloud) == 0 && len(sgFromDB) == 0 {
-		return new(SyncResult), nil
+		return &SyncResult{Counts: stat.Counts()}, nil
 	}
 
 	addSlice, updateMap, delCloudIDs := common.Diff[securitygroup.AzureSecurityGroup, cloudcore.SecurityGroup[cloudcore.AzureSecurityGroupExtension]](
-		sgFromCloud, sgFromDB, isSGChange)
+		kt, enumor.SecurityGroupCloudResType, sgFromCloud, sgFromDB, isSGChange)
 
 	if len(delCloudIDs) > 0 {
 		if err := cli.deleteSG(kt, params.AccountID, params.ResourceGroupName, delCloudIDs); err != nil {
@@ -90,6 +92,10 @@ func (cli *client) SecurityGroup(kt *kit.Kit, params *SyncBaseParams, opt *SyncS
 		}
 	}
 
+	if err = cli.syncSGTag(kt, params.AccountID, sgFromCloud); err != nil {
+		return nil, err
+	}
+
 	// 同步安全组规则
 	sgFromDB, err = cli.listSGFromDB(kt, params)
 	if err != nil {
@@ -113,7 +119,7 @@ func (cli *client) SecurityGroup(kt *kit.Kit, params *SyncBaseParams, opt *SyncS
 		return nil, err
 	}
 
-	return new(SyncResult), nil
+	return &SyncResult{Counts: stat.Counts()}, nil
 }
 
 func (cli *client) createSG(kt *kit.Kit, accountID string, resGroupName string,
@@ -154,6 +160,7 @@ func (cli *client) createSG(kt *kit.Kit, accountID string, resGroupName string,
 
 	logs.Infof("[%s] sync sg to create sg success, accountID: %s, count: %d, rid: %s", enumor.Azure,
 		accountID, len(addSlice), kt.Rid)
+	common.RecordSyncCreated(kt, enumor.SecurityGroupCloudResType, len(addSlice))
 
 	return results.IDs, nil
 }
@@ -194,6 +201,7 @@ func (cli *client) updateSG(kt *kit.Kit, accountID string, resGroupName string,
 
 	logs.Infof("[%s] sync sg to update sg success, accountID: %s, count: %d, rid: %s", enumor.Azure,
 		accountID, len(updateMap), kt.Rid)
+	common.RecordSyncUpdated(kt, enumor.SecurityGroupCloudResType, len(updateMap))
 
 	return nil
 }
@@ -219,6 +227,22 @@ func (cli *client) deleteSG(kt *kit.Kit, accountID string, resGroupName string,
 		return fmt.Errorf("validate sg not exist failed, before delete")
 	}
 
+	guardOpt := &common.SyncDeletionGuardOption{
+		Vendor:    enumor.Azure,
+		ResType:   enumor.SecurityGroupCloudResType,
+		AccountID: accountID,
+		Region:    resGroupName,
+		CloudIDs:  delCloudIDs,
+	}
+	delCloudIDs, err = common.GuardSyncDeletion(kt, cli.dbCli, guardOpt)
+	if err != nil {
+		return err
+	}
+
+	if len(delCloudIDs) == 0 {
+		return nil
+	}
+
 	deleteReq := &protocloud.SecurityGroupBatchDeleteReq{
 		Filter: tools.ContainersExpression("cloud_id", delCloudIDs),
 	}
@@ -230,6 +254,11 @@ func (cli *client) deleteSG(kt *kit.Kit, accountID string, resGroupName string,
 
 	logs.Infof("[%s] sync sg to delete sg success, accountID: %s, count: %d, rid: %s", enumor.Azure,
 		accountID, len(delCloudIDs), kt.Rid)
+	common.RecordSyncDeleted(kt, enumor.SecurityGroupCloudResType, len(delCloudIDs))
+
+	if err = common.ConfirmSyncDeletion(kt, cli.dbCli, guardOpt, delCloudIDs); err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -243,6 +272,7 @@ func (cli *client) listSGFromCloud(kt *kit.Kit, params *SyncBaseParams) ([]secur
 		ResourceGroupName: params.ResourceGroupName,
 		CloudIDs:          params.CloudIDs,
 	}
+	common.RecordCloudApiCall(kt)
 	result, err := cli.cloudCli.ListSecurityGroupByID(kt, opt)
 	if err != nil {
 		logs.Errorf("[%s] list sg from cloud failed, err: %v, account: %s, opt: %v, rid: %s", enumor.Azure,
@@ -390,3 +420,13 @@ func isSGChange(cloud securitygroup.AzureSecurityGroup, db cloudcore.SecurityGro
 
 	return false
 }
+
+// syncSGTag 使用云上安全组标签覆盖db中的安全组标签
+func (cli *client) syncSGTag(kt *kit.Kit, accountID string, sgFromCloud []securitygroup.AzureSecurityGroup) error {
+	tagMap := make(map[string][]cloudcore.TagPair, len(sgFromCloud))
+	for _, one := range sgFromCloud {
+		tagMap[one.GetCloudID()] = one.Tags
+	}
+
+	return common.SyncResourceTag(kt, cli.dbCli, enumor.Azure, accountID, enumor.SecurityGroupCloudResType, tagMap)
+}
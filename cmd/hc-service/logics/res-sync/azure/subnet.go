@@ -23,6 +23,7 @@ import (
 	"fmt"
 
 	"hcm/cmd/hc-service/logics/res-sync/common"
+	relmgr "hcm/cmd/hc-service/logics/res-sync/rel-manager"
 	adcore "hcm/pkg/adaptor/types/core"
 	"hcm/pkg/adaptor/types/subnet"
 	"hcm/pkg/api/core"
@@ -57,6 +58,8 @@ func (cli *client) Subnet(kt *kit.Kit, params *SyncBaseParams, opt *SyncSubnetOp
 		return nil, errf.NewFromErr(errf.InvalidParameter, err)
 	}
 
+	kt, stat := common.ForkSyncStat(kt)
+
 	subnetFromCloud, err := cli.listSubnetFromCloud(kt, params, opt.CloudVpcID)
 	if err != nil {
 		return nil, err
@@ -68,11 +71,12 @@ func (cli *client) Subnet(kt *kit.Kit, params *SyncBaseParams, opt *SyncSubnetOp
 	}
 
 	if len(subnetFromCloud) == 0 && len(subnetFromDB) == 0 {
-		return new(SyncResult), nil
+		return &SyncResult{Counts: stat.Counts()}, nil
 	}
 
 	addSubnet, updateMap, delCloudIDs := common.Diff[adtysubnet.AzureSubnet,
-		cloudcore.Subnet[cloudcore.AzureSubnetExtension]](subnetFromCloud, subnetFromDB, isSubnetChange)
+		cloudcore.Subnet[cloudcore.AzureSubnetExtension]](
+		kt, enumor.SubnetCloudResType, subnetFromCloud, subnetFromDB, isSubnetChange)
 
 	if len(delCloudIDs) > 0 {
 		if err = cli.deleteSubnet(kt, params.AccountID, params.ResourceGroupName, opt.CloudVpcID,
@@ -94,7 +98,13 @@ func (cli *client) Subnet(kt *kit.Kit, params *SyncBaseParams, opt *SyncSubnetOp
 		}
 	}
 
-	return new(SyncResult), nil
+	// 以云上子网绑定的安全组为准，对账子网和安全组的关联关系
+	if err = relmgr.ReconcileFromCloud(kt, cli.dbCli, enumor.Azure, relmgr.SubnetSecurityGroupRel, subnetFromCloud); err != nil {
+		logs.Errorf("[%s] reconcile subnet security group rel failed, err: %v, rid: %s", enumor.Azure, err, kt.Rid)
+		return nil, err
+	}
+
+	return &SyncResult{Counts: stat.Counts()}, nil
 }
 
 // RemoveSubnetDeleteFromCloud ...
@@ -191,6 +201,28 @@ func (cli *client) deleteSubnet(kt *kit.Kit, accountID, resGroupName, cloudVpcID
 		return fmt.Errorf("validate subnet not exist failed, before delete")
 	}
 
+	guardOpt := &common.SyncDeletionGuardOption{
+		Vendor:    enumor.Azure,
+		ResType:   enumor.SubnetCloudResType,
+		AccountID: accountID,
+		Region:    resGroupName,
+		CloudIDs:  delCloudIDs,
+	}
+	delCloudIDs, err = common.GuardSyncDeletion(kt, cli.dbCli, guardOpt)
+	if err != nil {
+		return err
+	}
+
+	if len(delCloudIDs) == 0 {
+		return nil
+	}
+
+	// 删除子网前清理子网和安全组的关联关系
+	if err = relmgr.Remove(kt, cli.dbCli, enumor.Azure, relmgr.SubnetSecurityGroupRel, delCloudIDs); err != nil {
+		logs.Errorf("[%s] remove subnet security group rel failed, err: %v, rid: %s", enumor.Azure, err, kt.Rid)
+		return err
+	}
+
 	deleteReq := &dataservice.BatchDeleteReq{
 		Filter: tools.ContainersExpression("cloud_id", delCloudIDs),
 	}
@@ -202,6 +234,11 @@ func (cli *client) deleteSubnet(kt *kit.Kit, accountID, resGroupName, cloudVpcID
 
 	logs.Infof("[%s] sync subnet to delete subnet success, accountID: %s, count: %d, rid: %s", enumor.Azure,
 		accountID, len(delCloudIDs), kt.Rid)
+	common.RecordSyncDeleted(kt, enumor.SubnetCloudResType, len(delCloudIDs))
+
+	if err = common.ConfirmSyncDeletion(kt, cli.dbCli, guardOpt, delCloudIDs); err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -244,6 +281,7 @@ func (cli *client) updateSubnet(kt *kit.Kit, accountID string, updateMap map[str
 
 	logs.Infof("[%s] sync subnet to update subnet success, accountID: %s, count: %d, rid: %s", enumor.Azure,
 		accountID, len(updateMap), kt.Rid)
+	common.RecordSyncUpdated(kt, enumor.SubnetCloudResType, len(updateMap))
 
 	return nil
 }
@@ -306,6 +344,7 @@ func (cli *client) createSubnet(kt *kit.Kit, accountID, resGroupName, cloudVpcID
 
 	logs.Infof("[%s] sync subnet to create subnet success, accountID: %s, count: %d, rid: %s", enumor.Azure,
 		accountID, len(addSubnet), kt.Rid)
+	common.RecordSyncCreated(kt, enumor.SubnetCloudResType, len(addSubnet))
 
 	return nil
 }
@@ -324,6 +363,7 @@ func (cli *client) listSubnetFromCloud(kt *kit.Kit, params *SyncBaseParams, clou
 		},
 		CloudVpcID: cloudVpcId,
 	}
+	common.RecordCloudApiCall(kt)
 	result, err := cli.cloudCli.ListSubnetByID(kt, opt)
 	if err != nil {
 		logs.Errorf("[%s] list subnet from cloud failed, err: %v, account: %s, opt: %v, rid: %s", enumor.Azure, err,
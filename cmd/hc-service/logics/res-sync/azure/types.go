@@ -22,7 +22,9 @@ package azure
 import (
 	"fmt"
 
+	"hcm/cmd/hc-service/logics/res-sync/common"
 	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/enumor"
 	"hcm/pkg/criteria/validator"
 )
 
@@ -45,6 +47,8 @@ func (opt SyncBaseParams) Validate() error {
 
 // SyncResult sync result.
 type SyncResult struct {
+	// Counts 本次同步各类资源的新增、更新、删除以及删除被隔离的数量
+	Counts map[enumor.CloudResourceType]common.SyncCount
 }
 
 // CloudData
@@ -56,6 +56,8 @@ func (cli *client) Vpc(kt *kit.Kit, params *SyncBaseParams, opt *SyncVpcOption)
 		return nil, errf.NewFromErr(errf.InvalidParameter, err)
 	}
 
+	kt, stat := common.ForkSyncStat(kt)
+
 	vpcFromCloud, err := cli.listVpcFromCloud(kt, params)
 	if err != nil {
 		return nil, err
@@ -67,11 +69,11 @@ func (cli *client) Vpc(kt *kit.Kit, params *SyncBaseParams, opt *SyncVpcOption)
 	}
 
 	if len(vpcFromCloud) == 0 && len(vpcFromDB) == 0 {
-		return new(SyncResult), nil
+		return &SyncResult{Counts: stat.Counts()}, nil
 	}
 
 	addVpc, updateMap, delCloudIDs := common.Diff[types.AzureVpc, cloudcore.Vpc[cloudcore.AzureVpcExtension]](
-		vpcFromCloud, vpcFromDB, isVpcChange)
+		kt, enumor.VpcCloudResType, vpcFromCloud, vpcFromDB, isVpcChange)
 
 	if len(delCloudIDs) > 0 {
 		if err = cli.deleteVpc(kt, params.AccountID, params.ResourceGroupName, delCloudIDs); err != nil {
@@ -91,7 +93,11 @@ func (cli *client) Vpc(kt *kit.Kit, params *SyncBaseParams, opt *SyncVpcOption)
 		}
 	}
 
-	return new(SyncResult), nil
+	if err = cli.syncVpcTag(kt, params.AccountID, vpcFromCloud); err != nil {
+		return nil, err
+	}
+
+	return &SyncResult{Counts: stat.Counts()}, nil
 }
 
 // RemoveVpcDeleteFromCloud ...
@@ -187,6 +193,22 @@ func (cli *client) deleteVpc(kt *kit.Kit, accountID string, resGroupName string,
 		return fmt.Errorf("validate vpc not exist failed, before delete")
 	}
 
+	guardOpt := &common.SyncDeletionGuardOption{
+		Vendor:    enumor.Azure,
+		ResType:   enumor.VpcCloudResType,
+		AccountID: accountID,
+		Region:    resGroupName,
+		CloudIDs:  delCloudIDs,
+	}
+	delCloudIDs, err = common.GuardSyncDeletion(kt, cli.dbCli, guardOpt)
+	if err != nil {
+		return err
+	}
+
+	if len(delCloudIDs) == 0 {
+		return nil
+	}
+
 	deleteReq := &dataservice.BatchDeleteReq{
 		Filter: tools.ContainersExpression("cloud_id", delCloudIDs),
 	}
@@ -197,6 +219,11 @@ func (cli *client) deleteVpc(kt *kit.Kit, accountID string, resGroupName string,
 
 	logs.Infof("[%s] sync vpc to delete vpc success, accountID: %s, count: %d, rid: %s", enumor.Azure,
 		accountID, len(delCloudIDs), kt.Rid)
+	common.RecordSyncDeleted(kt, enumor.VpcCloudResType, len(delCloudIDs))
+
+	if err = common.ConfirmSyncDeletion(kt, cli.dbCli, guardOpt, delCloudIDs); err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -244,6 +271,7 @@ func (cli *client) updateVpc(kt *kit.Kit, accountID string, updateMap map[string
 
 	logs.Infof("[%s] sync vpc to update vpc success, accountID: %s, count: %d, rid: %s", enumor.Azure,
 		accountID, len(updateMap), kt.Rid)
+	common.RecordSyncUpdated(kt, enumor.VpcCloudResType, len(updateMap))
 
 	return nil
 }
@@ -295,6 +323,7 @@ func (cli *client) createVpc(kt *kit.Kit, accountID string, addVpc []types.Azure
 
 	logs.Infof("[%s] sync vpc to create vpc success, accountID: %s, count: %d, rid: %s", enumor.Azure,
 		accountID, len(addVpc), kt.Rid)
+	common.RecordSyncCreated(kt, enumor.VpcCloudResType, len(addVpc))
 
 	return nil
 }
@@ -308,6 +337,7 @@ func (cli *client) listVpcFromCloud(kt *kit.Kit, params *SyncBaseParams) ([]type
 		ResourceGroupName: params.ResourceGroupName,
 		CloudIDs:          params.CloudIDs,
 	}
+	common.RecordCloudApiCall(kt)
 	result, err := cli.cloudCli.ListVpcByID(kt, opt)
 	if err != nil {
 		logs.Errorf("[%s] list vpc from cloud failed, err: %v, account: %s, opt: %v, rid: %s", enumor.Azure, err,
@@ -393,3 +423,13 @@ func isVpcChange(item types.AzureVpc, info cloudcore.Vpc[cloudcore.AzureVpcExten
 
 	return false
 }
+
+// syncVpcTag 使用云上vpc标签覆盖db中的vpc标签
+func (cli *client) syncVpcTag(kt *kit.Kit, accountID string, vpcFromCloud []types.AzureVpc) error {
+	tagMap := make(map[string][]cloudcore.TagPair, len(vpcFromCloud))
+	for _, one := range vpcFromCloud {
+		tagMap[one.GetCloudID()] = one.Tags
+	}
+
+	return common.SyncResourceTag(kt, cli.dbCli, enumor.Azure, accountID, enumor.VpcCloudResType, tagMap)
+}
@@ -0,0 +1,461 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+package common
+
+import (
+	"errors"
+	"time"
+
+	"hcm/pkg/api/core"
+	coresync "hcm/pkg/api/core/cloud/sync"
+	dataservice "hcm/pkg/api/data-service"
+	protocloud "hcm/pkg/api/data-service/cloud"
+	dssync "hcm/pkg/api/data-service/cloud/sync"
+	"hcm/pkg/cc"
+	dataclient "hcm/pkg/client/data-service"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/runtime/filter"
+	"hcm/pkg/tools/slice"
+)
+
+// SyncDeletionGuardOption 同步删除保护参数。
+type SyncDeletionGuardOption struct {
+	Vendor    enumor.Vendor
+	ResType   enumor.CloudResourceType
+	AccountID string
+	Region    string
+	// RegionField 资源表中表示同步范围的字段，如 region、zone，为空时按账号统计资源总数。
+	RegionField string
+	CloudIDs    []string
+
+	// confirmedCloudIDs 删除已确认的资源云ID，资源删除成功后删除其隔离记录
+	confirmedCloudIDs []string
+}
+
+// Validate SyncDeletionGuardOption.
+func (opt *SyncDeletionGuardOption) Validate() error {
+	if len(opt.Vendor) == 0 {
+		return errors.New("vendor is required")
+	}
+
+	if len(opt.ResType) == 0 {
+		return errors.New("res_type is required")
+	}
+
+	if len(opt.AccountID) == 0 {
+		return errors.New("account_id is required")
+	}
+
+	if len(opt.RegionField) != 0 && len(opt.Region) == 0 {
+		return errors.New("region is required when region field is set")
+	}
+
+	return nil
+}
+
+// GuardSyncDeletion 同步删除保护，返回本次同步可以直接删除的资源云ID，预览模式下只记录待删除的资源，不做删除。
+//  1. 确认有效期内已审批，或在之前的同步中被隔离且未开启强制审批的资源，视为删除已确认，可以删除。
+//  2. 本次同步新发现需要删除的资源，以及确认超出有效期的资源，如果累计删除数量超过阈值，则将其隔离，
+//     在资源表中标记为云上不存在并告警，暂不删除。
+//
+// 资源删除成功后需要调用 ConfirmSyncDeletion 删除已确认资源的隔离记录。
+func GuardSyncDeletion(kt *kit.Kit, dbCli *dataclient.Client, opt *SyncDeletionGuardOption) ([]string, error) {
+	if RecordDryRunDelete(kt, opt.ResType, opt.CloudIDs) {
+		return make([]string, 0), nil
+	}
+
+	guard := cc.HCService().SyncDeletionGuard
+	if !guard.Enable || len(opt.CloudIDs) == 0 {
+		return opt.CloudIDs, nil
+	}
+
+	if err := opt.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	return guardSyncDeletion(kt, &dataQuarantineStore{dbCli: dbCli}, guard, opt, time.Now())
+}
+
+// ConfirmSyncDeletion 资源删除成功后，删除其中删除已确认资源的隔离记录。
+func ConfirmSyncDeletion(kt *kit.Kit, dbCli *dataclient.Client, opt *SyncDeletionGuardOption,
+	deletedCloudIDs []string) error {
+
+	return confirmSyncDeletion(kt, &dataQuarantineStore{dbCli: dbCli}, opt, deletedCloudIDs)
+}
+
+// ReleaseSyncQuarantine 解除本次同步中云上重新查询到的资源的删除隔离，删除其隔离记录并恢复资源的同步状态。
+func ReleaseSyncQuarantine(kt *kit.Kit, dbCli *dataclient.Client, accountID string) error {
+	stat := syncStatFromKit(kt)
+	if stat == nil || !cc.HCService().SyncDeletionGuard.Enable {
+		return nil
+	}
+
+	return releaseSyncQuarantine(kt, &dataQuarantineStore{dbCli: dbCli}, stat, accountID)
+}
+
+// needRecordSyncSeen 是否需要记录云上查询到的资源，只有开启同步删除保护时需要记录支持删除保护的资源。
+func needRecordSyncSeen(kt *kit.Kit, resType enumor.CloudResourceType) bool {
+	if syncStatFromKit(kt) == nil {
+		return false
+	}
+
+	if _, exist := enumor.SyncGuardResTypes[resType]; !exist {
+		return false
+	}
+
+	return cc.HCService().SyncDeletionGuard.Enable
+}
+
+// quarantineStore 同步删除保护依赖的数据读写操作。
+type quarantineStore interface {
+	// ListQuarantine 查询资源的隔离记录，cloudIDs 为空时查询账号下该类资源的全部隔离记录。
+	ListQuarantine(kt *kit.Kit, resType enumor.CloudResourceType, accountID string, cloudIDs []string) (
+		[]coresync.AccountSyncQuarantine, error)
+	CreateQuarantine(kt *kit.Kit, opt *SyncDeletionGuardOption, cloudIDs []string) error
+	DeleteQuarantine(kt *kit.Kit, resType enumor.CloudResourceType, accountID string, cloudIDs []string) error
+	CountResource(kt *kit.Kit, opt *SyncDeletionGuardOption) (uint64, error)
+	UpdateSyncStatus(kt *kit.Kit, resType enumor.CloudResourceType, accountID string, cloudIDs []string,
+		status enumor.ResSyncStatus) error
+}
+
+func guardSyncDeletion(kt *kit.Kit, store quarantineStore, guard cc.SyncDeletionGuard,
+	opt *SyncDeletionGuardOption, now time.Time) ([]string, error) {
+
+	quarantines, err := store.ListQuarantine(kt, opt.ResType, opt.AccountID, opt.CloudIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	quarantineMap := make(map[string]coresync.AccountSyncQuarantine, len(quarantines))
+	for _, one := range quarantines {
+		quarantineMap[one.CloudID] = one
+	}
+
+	delCloudIDs := make([]string, 0, len(opt.CloudIDs))
+	freshCloudIDs := make([]string, 0)
+	expiredCloudIDs := make([]string, 0)
+	opt.confirmedCloudIDs = make([]string, 0)
+	for _, cloudID := range opt.CloudIDs {
+		one, exist := quarantineMap[cloudID]
+		if !exist {
+			freshCloudIDs = append(freshCloudIDs, cloudID)
+			continue
+		}
+
+		if !isConfirmInWindow(one, guard, now) {
+			expiredCloudIDs = append(expiredCloudIDs, cloudID)
+			freshCloudIDs = append(freshCloudIDs, cloudID)
+			continue
+		}
+
+		if one.Status != enumor.SyncQuarantineApproved && (guard.RequireApproval || one.SyncRid == kt.Rid) {
+			continue
+		}
+
+		delCloudIDs = append(delCloudIDs, cloudID)
+		opt.confirmedCloudIDs = append(opt.confirmedCloudIDs, cloudID)
+	}
+
+	// 超出确认有效期的隔离记录不再作为删除确认，删除后按本次新发现的资源重新判断是否隔离
+	if len(expiredCloudIDs) != 0 {
+		if err = store.DeleteQuarantine(kt, opt.ResType, opt.AccountID, expiredCloudIDs); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(freshCloudIDs) == 0 {
+		return delCloudIDs, nil
+	}
+
+	exceeded, err := isDeletionExceeded(kt, store, guard, opt, len(freshCloudIDs))
+	if err != nil {
+		return nil, err
+	}
+
+	if !exceeded {
+		return append(delCloudIDs, freshCloudIDs...), nil
+	}
+
+	if err = store.CreateQuarantine(kt, opt, freshCloudIDs); err != nil {
+		return nil, err
+	}
+
+	err = store.UpdateSyncStatus(kt, opt.ResType, opt.AccountID, freshCloudIDs, enumor.ResMissingFromCloud)
+	if err != nil {
+		return nil, err
+	}
+
+	logs.Errorf("%s: [%s] sync %s would delete too many resources, quarantined, account: %s, region: %s, "+
+		"count: %d, rid: %s", constant.SyncMassDeletionQuarantined, opt.Vendor, opt.ResType, opt.AccountID,
+		opt.Region, len(freshCloudIDs), kt.Rid)
+	RecordSyncQuarantined(kt, opt.ResType, len(freshCloudIDs))
+
+	return delCloudIDs, nil
+}
+
+// isConfirmInWindow 隔离记录的确认是否在有效期内，隔离或审批时都会刷新记录的更新时间。
+func isConfirmInWindow(one coresync.AccountSyncQuarantine, guard cc.SyncDeletionGuard, now time.Time) bool {
+	updatedAt, err := time.Parse(constant.TimeStdFormat, string(one.UpdatedAt))
+	if err != nil {
+		return false
+	}
+
+	return now.Sub(updatedAt) <= time.Duration(guard.ConfirmWindowHours)*time.Hour
+}
+
+func confirmSyncDeletion(kt *kit.Kit, store quarantineStore, opt *SyncDeletionGuardOption,
+	deletedCloudIDs []string) error {
+
+	if len(opt.confirmedCloudIDs) == 0 || len(deletedCloudIDs) == 0 {
+		return nil
+	}
+
+	deletedMap := make(map[string]struct{}, len(deletedCloudIDs))
+	for _, cloudID := range deletedCloudIDs {
+		deletedMap[cloudID] = struct{}{}
+	}
+
+	confirmedIDs := make([]string, 0, len(opt.confirmedCloudIDs))
+	for _, cloudID := range opt.confirmedCloudIDs {
+		if _, exist := deletedMap[cloudID]; exist {
+			confirmedIDs = append(confirmedIDs, cloudID)
+		}
+	}
+
+	if len(confirmedIDs) == 0 {
+		return nil
+	}
+
+	return store.DeleteQuarantine(kt, opt.ResType, opt.AccountID, confirmedIDs)
+}
+
+func releaseSyncQuarantine(kt *kit.Kit, store quarantineStore, stat *SyncStat, accountID string) error {
+	for _, resType := range stat.SeenResTypes() {
+		quarantines, err := store.ListQuarantine(kt, resType, accountID, nil)
+		if err != nil {
+			return err
+		}
+
+		seen := stat.SeenCloudIDs(resType)
+		releaseIDs := make([]string, 0)
+		for _, one := range quarantines {
+			if _, exist := seen[one.CloudID]; exist {
+				releaseIDs = append(releaseIDs, one.CloudID)
+			}
+		}
+
+		if len(releaseIDs) == 0 {
+			continue
+		}
+
+		if err = store.DeleteQuarantine(kt, resType, accountID, releaseIDs); err != nil {
+			return err
+		}
+
+		if err = store.UpdateSyncStatus(kt, resType, accountID, releaseIDs, enumor.ResSyncNormal); err != nil {
+			return err
+		}
+
+		logs.Infof("sync %s found quarantined resources in cloud again, released, account: %s, count: %d, rid: %s",
+			resType, accountID, len(releaseIDs), kt.Rid)
+	}
+
+	return nil
+}
+
+// isDeletionExceeded 判断本次同步中该类资源累计删除（含已隔离）的数量是否超过阈值。
+func isDeletionExceeded(kt *kit.Kit, store quarantineStore, guard cc.SyncDeletionGuard,
+	opt *SyncDeletionGuardOption, freshCount int) (bool, error) {
+
+	var count SyncCount
+	if stat := syncStatFromKit(kt); stat != nil {
+		count = stat.Count(opt.ResType)
+	}
+	total := uint64(count.DeletedCount + count.QuarantinedCount + freshCount)
+
+	if guard.MaxDeleteCount != 0 && total > guard.MaxDeleteCount {
+		return true, nil
+	}
+
+	if guard.MaxDeletePercent == 0 {
+		return false, nil
+	}
+
+	resCount, err := store.CountResource(kt, opt)
+	if err != nil {
+		return false, err
+	}
+
+	// 本次同步已删除的资源已不在库中，需要计入资源总数
+	base := resCount + uint64(count.DeletedCount)
+	if base == 0 {
+		return false, nil
+	}
+
+	return total*100 > base*guard.MaxDeletePercent, nil
+}
+
+// dataQuarantineStore 通过 data-service 读写隔离记录和资源。
+type dataQuarantineStore struct {
+	dbCli *dataclient.Client
+}
+
+// ListQuarantine ...
+func (s *dataQuarantineStore) ListQuarantine(kt *kit.Kit, resType enumor.CloudResourceType, accountID string,
+	cloudIDs []string) ([]coresync.AccountSyncQuarantine, error) {
+
+	rules := []filter.RuleFactory{
+		filter.AtomRule{Field: "res_type", Op: filter.Equal.Factory(), Value: resType},
+		filter.AtomRule{Field: "account_id", Op: filter.Equal.Factory(), Value: accountID},
+	}
+
+	result := make([]coresync.AccountSyncQuarantine, 0)
+	if len(cloudIDs) == 0 {
+		page := core.NewDefaultBasePage()
+		for {
+			req := &core.ListReq{Filter: &filter.Expression{Op: filter.And, Rules: rules}, Page: page}
+			resp, err := s.dbCli.Global.AccountSyncQuarantine.List(kt, req)
+			if err != nil {
+				logs.Errorf("list %s sync quarantine failed, err: %v, rid: %s", resType, err, kt.Rid)
+				return nil, err
+			}
+
+			result = append(result, resp.Details...)
+			if len(resp.Details) < int(page.Limit) {
+				return result, nil
+			}
+			page.Start += uint32(page.Limit)
+		}
+	}
+
+	for _, ids := range slice.Split(cloudIDs, int(core.DefaultMaxPageLimit)) {
+		idRules := append([]filter.RuleFactory{
+			filter.AtomRule{Field: "cloud_id", Op: filter.In.Factory(), Value: ids}}, rules...)
+		req := &core.ListReq{
+			Filter: &filter.Expression{Op: filter.And, Rules: idRules},
+			Page:   core.NewDefaultBasePage(),
+		}
+		resp, err := s.dbCli.Global.AccountSyncQuarantine.List(kt, req)
+		if err != nil {
+			logs.Errorf("list %s sync quarantine failed, err: %v, rid: %s", resType, err, kt.Rid)
+			return nil, err
+		}
+
+		result = append(result, resp.Details...)
+	}
+
+	return result, nil
+}
+
+// CreateQuarantine 隔离待删除的资源。
+func (s *dataQuarantineStore) CreateQuarantine(kt *kit.Kit, opt *SyncDeletionGuardOption, cloudIDs []string) error {
+	for _, ids := range slice.Split(cloudIDs, constant.BatchOperationMaxLimit) {
+		items := make([]dssync.QuarantineCreateField, 0, len(ids))
+		for _, id := range ids {
+			items = append(items, dssync.QuarantineCreateField{
+				Vendor:    opt.Vendor,
+				AccountID: opt.AccountID,
+				Region:    opt.Region,
+				ResType:   opt.ResType,
+				CloudID:   id,
+				Status:    enumor.SyncQuarantined,
+				SyncRid:   kt.Rid,
+			})
+		}
+
+		if _, err := s.dbCli.Global.AccountSyncQuarantine.BatchCreate(kt, &dssync.QuarantineCreateReq{
+			Items: items}); err != nil {
+			logs.Errorf("[%s] create sync quarantine failed, err: %v, rid: %s", opt.Vendor, err, kt.Rid)
+			return err
+		}
+	}
+
+	return nil
+}
+
+// DeleteQuarantine ...
+func (s *dataQuarantineStore) DeleteQuarantine(kt *kit.Kit, resType enumor.CloudResourceType, accountID string,
+	cloudIDs []string) error {
+
+	for _, ids := range slice.Split(cloudIDs, constant.BatchOperationMaxLimit) {
+		req := &dataservice.BatchDeleteReq{
+			Filter: &filter.Expression{
+				Op: filter.And,
+				Rules: []filter.RuleFactory{
+					filter.AtomRule{Field: "res_type", Op: filter.Equal.Factory(), Value: resType},
+					filter.AtomRule{Field: "account_id", Op: filter.Equal.Factory(), Value: accountID},
+					filter.AtomRule{Field: "cloud_id", Op: filter.In.Factory(), Value: ids},
+				},
+			},
+		}
+		if err := s.dbCli.Global.AccountSyncQuarantine.BatchDelete(kt, req); err != nil {
+			logs.Errorf("delete %s sync quarantine failed, err: %v, cloud ids: %v, rid: %s", resType, err, ids,
+				kt.Rid)
+			return err
+		}
+	}
+
+	return nil
+}
+
+// CountResource 统计账号下同步范围内该类资源的数量。
+func (s *dataQuarantineStore) CountResource(kt *kit.Kit, opt *SyncDeletionGuardOption) (uint64, error) {
+	rules := []filter.RuleFactory{
+		filter.AtomRule{Field: "account_id", Op: filter.Equal.Factory(), Value: opt.AccountID},
+	}
+	if len(opt.RegionField) != 0 {
+		rules = append(rules, filter.AtomRule{Field: opt.RegionField, Op: filter.Equal.Factory(), Value: opt.Region})
+	}
+	req := &protocloud.CountResourceReq{
+		ResourceType: opt.ResType,
+		Filter:       &filter.Expression{Op: filter.And, Rules: rules},
+	}
+	result, err := s.dbCli.Global.Cloud.CountResource(kt, req)
+	if err != nil {
+		logs.Errorf("[%s] count %s resource failed, err: %v, rid: %s", opt.Vendor, opt.ResType, err, kt.Rid)
+		return 0, err
+	}
+
+	return result.Count, nil
+}
+
+// UpdateSyncStatus 在资源表中更新资源的同步状态。
+func (s *dataQuarantineStore) UpdateSyncStatus(kt *kit.Kit, resType enumor.CloudResourceType, accountID string,
+	cloudIDs []string, status enumor.ResSyncStatus) error {
+
+	for _, ids := range slice.Split(cloudIDs, constant.BatchOperationMaxLimit) {
+		req := &protocloud.UpdateResourceSyncStatusReq{
+			ResourceType: resType,
+			AccountID:    accountID,
+			CloudIDs:     ids,
+			SyncStatus:   status,
+		}
+		if err := s.dbCli.Global.Cloud.UpdateResourceSyncStatus(kt, req); err != nil {
+			logs.Errorf("update %s sync status to %s failed, err: %v, cloud ids: %v, rid: %s", resType, status, err,
+				ids, kt.Rid)
+			return err
+		}
+	}
+
+	return nil
+}
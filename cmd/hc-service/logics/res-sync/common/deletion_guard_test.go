@@ -0,0 +1,339 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package common
+
+import (
+	"errors"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	coresync "hcm/pkg/api/core/cloud/sync"
+	"hcm/pkg/cc"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/dal/table/types"
+	"hcm/pkg/kit"
+)
+
+// fakeQuarantineStore 内存中的隔离记录，记录资源同步状态的变更。
+type fakeQuarantineStore struct {
+	quarantines map[string]coresync.AccountSyncQuarantine
+	resCount    uint64
+	countErr    error
+	syncStatus  map[string]enumor.ResSyncStatus
+	created     []string
+	deleted     []string
+}
+
+func newFakeQuarantineStore(quarantines ...coresync.AccountSyncQuarantine) *fakeQuarantineStore {
+	store := &fakeQuarantineStore{
+		quarantines: make(map[string]coresync.AccountSyncQuarantine),
+		syncStatus:  make(map[string]enumor.ResSyncStatus),
+	}
+	for _, one := range quarantines {
+		store.quarantines[one.CloudID] = one
+	}
+	return store
+}
+
+func (s *fakeQuarantineStore) ListQuarantine(_ *kit.Kit, _ enumor.CloudResourceType, _ string, cloudIDs []string) (
+	[]coresync.AccountSyncQuarantine, error) {
+
+	result := make([]coresync.AccountSyncQuarantine, 0)
+	if len(cloudIDs) == 0 {
+		for _, one := range s.quarantines {
+			result = append(result, one)
+		}
+		return result, nil
+	}
+
+	for _, cloudID := range cloudIDs {
+		if one, exist := s.quarantines[cloudID]; exist {
+			result = append(result, one)
+		}
+	}
+	return result, nil
+}
+
+func (s *fakeQuarantineStore) CreateQuarantine(kt *kit.Kit, opt *SyncDeletionGuardOption, cloudIDs []string) error {
+	for _, cloudID := range cloudIDs {
+		s.quarantines[cloudID] = coresync.AccountSyncQuarantine{CloudID: cloudID, ResType: opt.ResType,
+			Status: enumor.SyncQuarantined, SyncRid: kt.Rid}
+		s.created = append(s.created, cloudID)
+	}
+	return nil
+}
+
+func (s *fakeQuarantineStore) DeleteQuarantine(_ *kit.Kit, _ enumor.CloudResourceType, _ string,
+	cloudIDs []string) error {
+
+	for _, cloudID := range cloudIDs {
+		delete(s.quarantines, cloudID)
+		s.deleted = append(s.deleted, cloudID)
+	}
+	return nil
+}
+
+func (s *fakeQuarantineStore) CountResource(_ *kit.Kit, _ *SyncDeletionGuardOption) (uint64, error) {
+	return s.resCount, s.countErr
+}
+
+func (s *fakeQuarantineStore) UpdateSyncStatus(_ *kit.Kit, _ enumor.CloudResourceType, _ string, cloudIDs []string,
+	status enumor.ResSyncStatus) error {
+
+	for _, cloudID := range cloudIDs {
+		s.syncStatus[cloudID] = status
+	}
+	return nil
+}
+
+func testQuarantine(cloudID string, status enumor.SyncQuarantineStatus, rid string,
+	updatedAt time.Time) coresync.AccountSyncQuarantine {
+
+	return coresync.AccountSyncQuarantine{
+		CloudID:   cloudID,
+		ResType:   enumor.CvmCloudResType,
+		Status:    status,
+		SyncRid:   rid,
+		UpdatedAt: types.Time(updatedAt.Format(constant.TimeStdFormat)),
+	}
+}
+
+func sortedIDs(ids []string) []string {
+	result := append([]string{}, ids...)
+	sort.Strings(result)
+	return result
+}
+
+func TestIsDeletionExceeded(t *testing.T) {
+	cases := []struct {
+		name       string
+		guard      cc.SyncDeletionGuard
+		deleted    int
+		freshCount int
+		resCount   uint64
+		countErr   error
+		exceeded   bool
+		wantErr    bool
+	}{
+		{name: "under max count", guard: cc.SyncDeletionGuard{MaxDeleteCount: 10}, freshCount: 10},
+		{name: "exceed max count", guard: cc.SyncDeletionGuard{MaxDeleteCount: 10}, freshCount: 11, exceeded: true},
+		{name: "deleted in this sync counted", guard: cc.SyncDeletionGuard{MaxDeleteCount: 10}, deleted: 8,
+			freshCount: 3, exceeded: true},
+		{name: "under max percent", guard: cc.SyncDeletionGuard{MaxDeletePercent: 30}, freshCount: 3,
+			resCount: 10},
+		{name: "exceed max percent", guard: cc.SyncDeletionGuard{MaxDeletePercent: 30}, freshCount: 4,
+			resCount: 10, exceeded: true},
+		{name: "deleted resources counted in base", guard: cc.SyncDeletionGuard{MaxDeletePercent: 50}, deleted: 5,
+			freshCount: 5, resCount: 15},
+		{name: "no resource in db", guard: cc.SyncDeletionGuard{MaxDeletePercent: 30}, freshCount: 1},
+		{name: "count resource failed", guard: cc.SyncDeletionGuard{MaxDeletePercent: 30}, freshCount: 1,
+			countErr: errors.New("count failed"), wantErr: true},
+	}
+
+	opt := &SyncDeletionGuardOption{Vendor: enumor.TCloud, ResType: enumor.CvmCloudResType, AccountID: "account"}
+	for _, c := range cases {
+		stat := NewSyncStat()
+		kt := WithSyncStat(kit.New(), stat)
+		RecordSyncDeleted(kt, enumor.CvmCloudResType, c.deleted)
+
+		store := &fakeQuarantineStore{resCount: c.resCount, countErr: c.countErr}
+		exceeded, err := isDeletionExceeded(kt, store, c.guard, opt, c.freshCount)
+		if (err != nil) != c.wantErr {
+			t.Errorf("%s: unexpected err: %v", c.name, err)
+			continue
+		}
+
+		if exceeded != c.exceeded {
+			t.Errorf("%s: expect exceeded %v, but got %v", c.name, c.exceeded, exceeded)
+		}
+	}
+}
+
+func TestGuardSyncDeletion(t *testing.T) {
+	now := time.Now()
+	window := cc.SyncDeletionGuard{Enable: true, MaxDeleteCount: 1, ConfirmWindowHours: 24}
+	approval := window
+	approval.RequireApproval = true
+
+	cases := []struct {
+		name        string
+		guard       cc.SyncDeletionGuard
+		quarantines []coresync.AccountSyncQuarantine
+		cloudIDs    []string
+		deleted     []string
+		confirmed   []string
+		quarantined []string
+		released    []string
+	}{
+		{
+			name:     "fresh deletion under threshold",
+			guard:    window,
+			cloudIDs: []string{"ins-1"},
+			deleted:  []string{"ins-1"},
+		},
+		{
+			name:        "fresh deletion exceeds threshold",
+			guard:       window,
+			cloudIDs:    []string{"ins-1", "ins-2"},
+			quarantined: []string{"ins-1", "ins-2"},
+		},
+		{
+			name:  "confirmed by a second sync",
+			guard: window,
+			quarantines: []coresync.AccountSyncQuarantine{
+				testQuarantine("ins-1", enumor.SyncQuarantined, "last-rid", now.Add(-time.Hour)),
+				testQuarantine("ins-2", enumor.SyncQuarantined, "last-rid", now.Add(-time.Hour)),
+			},
+			cloudIDs:  []string{"ins-1", "ins-2"},
+			deleted:   []string{"ins-1", "ins-2"},
+			confirmed: []string{"ins-1", "ins-2"},
+		},
+		{
+			name:  "quarantined in the same sync",
+			guard: window,
+			quarantines: []coresync.AccountSyncQuarantine{
+				testQuarantine("ins-1", enumor.SyncQuarantined, "rid", now),
+			},
+			cloudIDs: []string{"ins-1"},
+		},
+		{
+			name:  "require approval",
+			guard: approval,
+			quarantines: []coresync.AccountSyncQuarantine{
+				testQuarantine("ins-1", enumor.SyncQuarantined, "last-rid", now.Add(-time.Hour)),
+				testQuarantine("ins-2", enumor.SyncQuarantineApproved, "last-rid", now.Add(-time.Hour)),
+			},
+			cloudIDs:  []string{"ins-1", "ins-2"},
+			deleted:   []string{"ins-2"},
+			confirmed: []string{"ins-2"},
+		},
+		{
+			name:  "confirmation out of window",
+			guard: window,
+			quarantines: []coresync.AccountSyncQuarantine{
+				testQuarantine("ins-1", enumor.SyncQuarantined, "last-rid", now.Add(-48*time.Hour)),
+				testQuarantine("ins-2", enumor.SyncQuarantineApproved, "last-rid", now.Add(-48*time.Hour)),
+			},
+			cloudIDs:    []string{"ins-1", "ins-2"},
+			quarantined: []string{"ins-1", "ins-2"},
+			released:    []string{"ins-1", "ins-2"},
+		},
+	}
+
+	for _, c := range cases {
+		kt := WithSyncStat(kit.New(), NewSyncStat())
+		kt.Rid = "rid"
+		store := newFakeQuarantineStore(c.quarantines...)
+		opt := &SyncDeletionGuardOption{Vendor: enumor.TCloud, ResType: enumor.CvmCloudResType,
+			AccountID: "account", CloudIDs: c.cloudIDs}
+
+		deleted, err := guardSyncDeletion(kt, store, c.guard, opt, now)
+		if err != nil {
+			t.Errorf("%s: guard sync deletion failed, err: %v", c.name, err)
+			continue
+		}
+
+		if !reflect.DeepEqual(sortedIDs(deleted), sortedIDs(c.deleted)) {
+			t.Errorf("%s: expect deleted %v, but got %v", c.name, c.deleted, deleted)
+		}
+
+		if !reflect.DeepEqual(sortedIDs(opt.confirmedCloudIDs), sortedIDs(c.confirmed)) {
+			t.Errorf("%s: expect confirmed %v, but got %v", c.name, c.confirmed, opt.confirmedCloudIDs)
+		}
+
+		if !reflect.DeepEqual(sortedIDs(store.created), sortedIDs(c.quarantined)) {
+			t.Errorf("%s: expect quarantined %v, but got %v", c.name, c.quarantined, store.created)
+		}
+
+		for _, cloudID := range c.quarantined {
+			if store.syncStatus[cloudID] != enumor.ResMissingFromCloud {
+				t.Errorf("%s: quarantined %s should be marked missing from cloud", c.name, cloudID)
+			}
+		}
+
+		if !reflect.DeepEqual(sortedIDs(store.deleted), sortedIDs(c.released)) {
+			t.Errorf("%s: expect quarantine %v deleted, but got %v", c.name, c.released, store.deleted)
+		}
+
+		if count := syncStatFromKit(kt).Count(enumor.CvmCloudResType); count.QuarantinedCount != len(c.quarantined) {
+			t.Errorf("%s: expect quarantined count %d, but got %d", c.name, len(c.quarantined),
+				count.QuarantinedCount)
+		}
+	}
+}
+
+func TestConfirmSyncDeletion(t *testing.T) {
+	now := time.Now()
+	store := newFakeQuarantineStore(
+		testQuarantine("ins-1", enumor.SyncQuarantined, "last-rid", now),
+		testQuarantine("ins-2", enumor.SyncQuarantined, "last-rid", now),
+	)
+	opt := &SyncDeletionGuardOption{Vendor: enumor.TCloud, ResType: enumor.CvmCloudResType, AccountID: "account",
+		CloudIDs: []string{"ins-1", "ins-2", "ins-3"}}
+	kt := kit.New()
+
+	deleted, err := guardSyncDeletion(kt, store, cc.SyncDeletionGuard{Enable: true, MaxDeleteCount: 10,
+		ConfirmWindowHours: 24}, opt, now)
+	if err != nil {
+		t.Fatalf("guard sync deletion failed, err: %v", err)
+	}
+
+	if len(store.deleted) != 0 {
+		t.Fatalf("confirmed quarantine should not be deleted before resource deleted, deleted: %v", store.deleted)
+	}
+
+	// 只有删除成功的资源才删除其隔离记录
+	if err = confirmSyncDeletion(kt, store, opt, deleted[:1]); err != nil {
+		t.Fatalf("confirm sync deletion failed, err: %v", err)
+	}
+
+	if len(store.deleted) != 1 || len(store.quarantines) != 1 {
+		t.Errorf("only quarantine of deleted resource should be deleted, deleted: %v, left: %v", store.deleted,
+			store.quarantines)
+	}
+}
+
+func TestReleaseSyncQuarantine(t *testing.T) {
+	now := time.Now()
+	store := newFakeQuarantineStore(
+		testQuarantine("ins-1", enumor.SyncQuarantined, "last-rid", now),
+		testQuarantine("ins-2", enumor.SyncQuarantined, "last-rid", now),
+	)
+	stat := NewSyncStat()
+	kt := WithSyncStat(kit.New(), stat)
+	childKt, _ := ForkSyncStat(kt)
+	recordSyncSeen(childKt, enumor.CvmCloudResType, []string{"ins-1", "ins-3"})
+
+	if err := releaseSyncQuarantine(kt, store, stat, "account"); err != nil {
+		t.Fatalf("release sync quarantine failed, err: %v", err)
+	}
+
+	if !reflect.DeepEqual(store.deleted, []string{"ins-1"}) || store.syncStatus["ins-1"] != enumor.ResSyncNormal {
+		t.Errorf("quarantine of resource seen in cloud should be released, deleted: %v, status: %v", store.deleted,
+			store.syncStatus)
+	}
+
+	if _, exist := store.quarantines["ins-2"]; !exist {
+		t.Errorf("quarantine of resource not seen in cloud should be kept")
+	}
+}
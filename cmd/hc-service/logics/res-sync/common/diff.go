@@ -47,6 +47,8 @@ import (
 	corezone "hcm/pkg/api/core/cloud/zone"
 	corerecyclerecord "hcm/pkg/api/core/recycle-record"
 	dataeip "hcm/pkg/api/data-service/cloud/eip"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/kit"
 )
 
 type CloudResType interface {
@@ -224,8 +226,10 @@ type DBResType interface {
 }
 
 // Diff 对比云和db资源，划分出新增数据，更新数据，删除数据。
-func Diff[CloudType CloudResType, DBType DBResType](dataFromCloud []CloudType, dataFromDB []DBType,
-	isChange func(CloudType, DBType) bool) ([]CloudType, map[string]CloudType, []string) {
+// 预览模式下只记录对比出的差异，返回的新增、更新、删除数据均为空，调用方据此跳过写db操作。
+func Diff[CloudType CloudResType, DBType DBResType](kt *kit.Kit, resType enumor.CloudResourceType,
+	dataFromCloud []CloudType, dataFromDB []DBType, isChange func(CloudType, DBType) bool) ([]CloudType,
+	map[string]CloudType, []string) {
 
 	dbMap := make(map[string]DBType, len(dataFromDB))
 	for _, one := range dataFromDB {
@@ -234,6 +238,10 @@ func Diff[CloudType CloudResType, DBType DBResType](dataFromCloud []CloudType, d
 
 	newAddData := make([]CloudType, 0)
 	updateMap := make(map[string]CloudType, 0)
+	updateDBMap := make(map[string]DBType, 0)
+	// 开启同步删除保护时记录云上查询到的资源，用于解除已恢复资源的删除隔离
+	recordSeen := needRecordSyncSeen(kt, resType)
+	seenCloudIDs := make([]string, 0)
 	for _, oneFromCloud := range dataFromCloud {
 		oneFromDB, exist := dbMap[oneFromCloud.GetCloudID()]
 		if !exist {
@@ -242,8 +250,12 @@ func Diff[CloudType CloudResType, DBType DBResType](dataFromCloud []CloudType, d
 		}
 
 		delete(dbMap, oneFromCloud.GetCloudID())
+		if recordSeen {
+			seenCloudIDs = append(seenCloudIDs, oneFromCloud.GetCloudID())
+		}
 		if isChange(oneFromCloud, oneFromDB) {
 			updateMap[oneFromDB.GetID()] = oneFromCloud
+			updateDBMap[oneFromDB.GetID()] = oneFromDB
 		}
 	}
 
@@ -252,5 +264,13 @@ func Diff[CloudType CloudResType, DBType DBResType](dataFromCloud []CloudType, d
 		delCloudIDs = append(delCloudIDs, cloudID)
 	}
 
+	if recordDryRunDiff(kt, resType, newAddData, updateMap, updateDBMap, delCloudIDs) {
+		return make([]CloudType, 0), make(map[string]CloudType, 0), make([]string, 0)
+	}
+
+	if recordSeen {
+		recordSyncSeen(kt, resType, seenCloudIDs)
+	}
+
 	return newAddData, updateMap, delCloudIDs
 }
@@ -0,0 +1,240 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package common
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"sync"
+
+	hcsync "hcm/pkg/api/hc-service/sync"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/kit"
+)
+
+type dryRunCtxKey struct{}
+
+// DryRunResult 预览模式下记录同步过程中各类资源的差异，通过 kit 的上下文在同步流程中传递，携带该结果的同步流程只对比不写db。
+type DryRunResult struct {
+	lock  sync.Mutex
+	diffs hcsync.DryRunResult
+}
+
+// NewDryRunResult new dry run result.
+func NewDryRunResult() *DryRunResult {
+	return &DryRunResult{
+		diffs: make(hcsync.DryRunResult),
+	}
+}
+
+// WithDryRun 返回携带预览结果的子kit，后续使用该kit的同步操作只记录差异，不写db。
+func WithDryRun(kt *kit.Kit, result *DryRunResult) *kit.Kit {
+	newKit := *kt
+	newKit.Ctx = context.WithValue(kt.Ctx, dryRunCtxKey{}, result)
+	return &newKit
+}
+
+func dryRunFromKit(kt *kit.Kit) *DryRunResult {
+	if kt == nil || kt.Ctx == nil {
+		return nil
+	}
+
+	result, _ := kt.Ctx.Value(dryRunCtxKey{}).(*DryRunResult)
+	return result
+}
+
+// IsDryRun 判断当前同步是否为预览模式。
+func IsDryRun(kt *kit.Kit) bool {
+	return dryRunFromKit(kt) != nil
+}
+
+// MarshalJSON 以资源类型为key输出各类资源的同步差异。
+func (r *DryRunResult) MarshalJSON() ([]byte, error) {
+	if r == nil {
+		return []byte("null"), nil
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	return json.Marshal(r.diffs)
+}
+
+// Diff 返回指定资源类型的同步差异。
+func (r *DryRunResult) Diff(resType enumor.CloudResourceType) hcsync.DryRunDiff {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	diff, exist := r.diffs[resType]
+	if !exist {
+		return hcsync.DryRunDiff{}
+	}
+
+	return *diff
+}
+
+// Merge 合并另一次预览的同步差异，用于一个接口分多次执行同步的场景。
+func (r *DryRunResult) Merge(other *DryRunResult) {
+	if other == nil || other == r {
+		return
+	}
+
+	other.lock.Lock()
+	diffs := make(map[enumor.CloudResourceType]hcsync.DryRunDiff, len(other.diffs))
+	for resType, diff := range other.diffs {
+		diffs[resType] = *diff
+	}
+	other.lock.Unlock()
+
+	for resType, diff := range diffs {
+		r.add(resType, diff)
+	}
+}
+
+func (r *DryRunResult) add(resType enumor.CloudResourceType, delta hcsync.DryRunDiff) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	diff, exist := r.diffs[resType]
+	if !exist {
+		diff = &hcsync.DryRunDiff{
+			AddCloudIDs:    make([]string, 0),
+			Update:         make([]hcsync.DryRunUpdate, 0),
+			DeleteCloudIDs: make([]string, 0),
+		}
+		r.diffs[resType] = diff
+	}
+
+	diff.AddCloudIDs = append(diff.AddCloudIDs, delta.AddCloudIDs...)
+	diff.Update = append(diff.Update, delta.Update...)
+	diff.DeleteCloudIDs = append(diff.DeleteCloudIDs, delta.DeleteCloudIDs...)
+}
+
+// RecordDryRunDiff 预览模式下记录未经 Diff 对比出的资源差异，返回 true 表示当前为预览模式，调用方需要跳过写db操作。
+func RecordDryRunDiff(kt *kit.Kit, resType enumor.CloudResourceType, diff hcsync.DryRunDiff) bool {
+	result := dryRunFromKit(kt)
+	if result == nil {
+		return false
+	}
+
+	result.add(resType, diff)
+	return true
+}
+
+// RecordDryRunDelete 预览模式下记录需要删除的资源，返回 true 表示当前为预览模式，调用方需要跳过删除操作。
+func RecordDryRunDelete(kt *kit.Kit, resType enumor.CloudResourceType, delCloudIDs []string) bool {
+	result := dryRunFromKit(kt)
+	if result == nil {
+		return false
+	}
+
+	if len(delCloudIDs) != 0 {
+		result.add(resType, hcsync.DryRunDiff{DeleteCloudIDs: delCloudIDs})
+	}
+
+	return true
+}
+
+// recordDryRunDiff 预览模式下记录对比出的差异，返回 true 表示当前为预览模式。
+func recordDryRunDiff[CloudType CloudResType, DBType DBResType](kt *kit.Kit, resType enumor.CloudResourceType,
+	addData []CloudType, updateMap map[string]CloudType, dbMap map[string]DBType, delCloudIDs []string) bool {
+
+	result := dryRunFromKit(kt)
+	if result == nil {
+		return false
+	}
+
+	diff := hcsync.DryRunDiff{
+		AddCloudIDs:    make([]string, 0, len(addData)),
+		Update:         make([]hcsync.DryRunUpdate, 0, len(updateMap)),
+		DeleteCloudIDs: delCloudIDs,
+	}
+	for _, one := range addData {
+		diff.AddCloudIDs = append(diff.AddCloudIDs, one.GetCloudID())
+	}
+
+	for id, one := range updateMap {
+		diff.Update = append(diff.Update, hcsync.DryRunUpdate{
+			CloudID:       one.GetCloudID(),
+			ChangedFields: changedFields(one, dbMap[id]),
+		})
+	}
+	sort.Slice(diff.Update, func(i, j int) bool { return diff.Update[i].CloudID < diff.Update[j].CloudID })
+
+	result.add(resType, diff)
+	return true
+}
+
+// changedFields 将云上和db中的数据按json展开，返回同名字段中值不同的字段路径。
+func changedFields(cloud, db interface{}) []string {
+	cloudFields, dbFields := flattenJson(cloud), flattenJson(db)
+
+	fields := make([]string, 0)
+	for path, cloudValue := range cloudFields {
+		dbValue, exist := dbFields[path]
+		if !exist {
+			continue
+		}
+
+		if !reflect.DeepEqual(cloudValue, dbValue) {
+			fields = append(fields, path)
+		}
+	}
+	sort.Strings(fields)
+
+	return fields
+}
+
+func flattenJson(obj interface{}) map[string]interface{} {
+	result := make(map[string]interface{})
+
+	raw, err := json.Marshal(obj)
+	if err != nil {
+		return result
+	}
+
+	var value interface{}
+	if err = json.Unmarshal(raw, &value); err != nil {
+		return result
+	}
+
+	flatten("", value, result)
+	return result
+}
+
+func flatten(prefix string, value interface{}, result map[string]interface{}) {
+	fields, ok := value.(map[string]interface{})
+	if !ok {
+		if len(prefix) != 0 {
+			result[prefix] = value
+		}
+		return
+	}
+
+	for key, one := range fields {
+		path := key
+		if len(prefix) != 0 {
+			path = prefix + "." + key
+		}
+		flatten(path, one, result)
+	}
+}
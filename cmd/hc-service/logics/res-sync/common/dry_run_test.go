@@ -0,0 +1,80 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package common
+
+import (
+	"reflect"
+	"testing"
+
+	"hcm/pkg/adaptor/types"
+	cloudcore "hcm/pkg/api/core/cloud"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/kit"
+)
+
+func TestDiffDryRun(t *testing.T) {
+	fromCloud := []types.TCloudVpc{
+		{CloudID: "vpc-1", Name: "new", Region: "ap-guangzhou",
+			Extension: &cloudcore.TCloudVpcExtension{IsDefault: true}},
+		{CloudID: "vpc-2", Name: "vpc-2", Region: "ap-guangzhou"},
+	}
+	fromDB := []cloudcore.Vpc[cloudcore.TCloudVpcExtension]{
+		{BaseVpc: cloudcore.BaseVpc{ID: "1", CloudID: "vpc-1", Name: "old", Region: "ap-guangzhou"},
+			Extension: &cloudcore.TCloudVpcExtension{IsDefault: false}},
+		{BaseVpc: cloudcore.BaseVpc{ID: "3", CloudID: "vpc-3", Name: "vpc-3", Region: "ap-guangzhou"}},
+	}
+	isChange := func(cloud types.TCloudVpc, db cloudcore.Vpc[cloudcore.TCloudVpcExtension]) bool {
+		return cloud.Name != db.Name
+	}
+
+	adds, updateMap, delCloudIDs := Diff[types.TCloudVpc, cloudcore.Vpc[cloudcore.TCloudVpcExtension]](kit.New(),
+		enumor.VpcCloudResType, fromCloud, fromDB, isChange)
+	if len(adds) != 1 || len(updateMap) != 1 || len(delCloudIDs) != 1 {
+		t.Fatalf("unexpected diff without dry run, adds: %v, updates: %v, deletes: %v", adds, updateMap, delCloudIDs)
+	}
+
+	result := NewDryRunResult()
+	kt := WithDryRun(kit.New(), result)
+	adds, updateMap, delCloudIDs = Diff[types.TCloudVpc, cloudcore.Vpc[cloudcore.TCloudVpcExtension]](kt,
+		enumor.VpcCloudResType, fromCloud, fromDB, isChange)
+	if len(adds) != 0 || len(updateMap) != 0 || len(delCloudIDs) != 0 {
+		t.Fatalf("dry run should not return data to write, adds: %v, updates: %v, deletes: %v", adds, updateMap,
+			delCloudIDs)
+	}
+
+	diff := result.Diff(enumor.VpcCloudResType)
+	if !reflect.DeepEqual(diff.AddCloudIDs, []string{"vpc-2"}) {
+		t.Errorf("unexpected add cloud ids: %v", diff.AddCloudIDs)
+	}
+
+	if !reflect.DeepEqual(diff.DeleteCloudIDs, []string{"vpc-3"}) {
+		t.Errorf("unexpected delete cloud ids: %v", diff.DeleteCloudIDs)
+	}
+
+	if len(diff.Update) != 1 || diff.Update[0].CloudID != "vpc-1" ||
+		!reflect.DeepEqual(diff.Update[0].ChangedFields, []string{"extension.is_default", "name"}) {
+		t.Errorf("unexpected update: %+v", diff.Update)
+	}
+
+	if !RecordDryRunDelete(kt, enumor.VpcCloudResType, []string{"vpc-4"}) || RecordDryRunDelete(kit.New(),
+		enumor.VpcCloudResType, []string{"vpc-4"}) {
+		t.Errorf("record dry run delete should only take effect in dry run")
+	}
+}
@@ -0,0 +1,64 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package common
+
+import (
+	corecloud "hcm/pkg/api/core/cloud"
+	protocloud "hcm/pkg/api/data-service/cloud"
+	dataclient "hcm/pkg/client/data-service"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/tools/slice"
+)
+
+// SyncResourceTag 使用云上标签全量覆盖db中对应资源的标签，tagMap的key为资源云ID，标签为空时会清空该资源在db中的标签。
+func SyncResourceTag(kt *kit.Kit, dataCli *dataclient.Client, vendor enumor.Vendor, accountID string,
+	resType enumor.CloudResourceType, tagMap map[string][]corecloud.TagPair) error {
+
+	if len(tagMap) == 0 || IsDryRun(kt) {
+		return nil
+	}
+
+	resources := make([]protocloud.ResourceTagSyncResource, 0, len(tagMap))
+	for cloudID, tags := range tagMap {
+		resources = append(resources, protocloud.ResourceTagSyncResource{
+			ResCloudID: cloudID,
+			Tags:       tags,
+		})
+	}
+
+	for _, part := range slice.Split(resources, constant.CloudResourceSyncMaxLimit) {
+		req := &protocloud.ResourceTagSyncReq{
+			Vendor:    vendor,
+			AccountID: accountID,
+			ResType:   resType,
+			Resources: part,
+		}
+		if err := dataCli.Global.ResourceTag.Sync(kt, req); err != nil {
+			logs.Errorf("[%s] sync %s tag failed, err: %v, account: %s, rid: %s", vendor, resType, err,
+				accountID, kt.Rid)
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,260 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package common
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/kit"
+)
+
+type syncStatCtxKey struct{}
+
+// SyncCount 单类资源同步的新增、更新、删除以及删除被隔离的数量。
+type SyncCount struct {
+	CreatedCount     int `json:"created_count"`
+	UpdatedCount     int `json:"updated_count"`
+	DeletedCount     int `json:"deleted_count"`
+	QuarantinedCount int `json:"quarantined_count"`
+}
+
+// SyncStat 记录一次同步过程中各类资源的变更数量以及云API调用次数，通过 kit 的上下文在同步流程中传递。
+type SyncStat struct {
+	lock          sync.Mutex
+	counts        map[enumor.CloudResourceType]*SyncCount
+	cloudApiCount int
+	// parent 父级同步统计，记录到子统计的数量会同时累加到父级统计中
+	parent *SyncStat
+	// seen 同步过程中云上查询到且db中存在的资源云ID，只记录在根统计中，用于解除这些资源的删除隔离
+	seen map[enumor.CloudResourceType]map[string]struct{}
+}
+
+// NewSyncStat new sync stat.
+func NewSyncStat() *SyncStat {
+	return &SyncStat{
+		counts: make(map[enumor.CloudResourceType]*SyncCount),
+	}
+}
+
+// WithSyncStat 返回携带同步统计的子kit，后续使用该kit的同步操作都会被记录到 stat 中。
+func WithSyncStat(kt *kit.Kit, stat *SyncStat) *kit.Kit {
+	newKit := *kt
+	newKit.Ctx = context.WithValue(kt.Ctx, syncStatCtxKey{}, stat)
+	return &newKit
+}
+
+// ForkSyncStat 返回携带子同步统计的子kit以及该子统计，用于统计单次资源同步调用的结果，
+// 子统计记录的数量会同时累加到 kit 中原有的同步统计中，不影响整个同步流程的统计。
+func ForkSyncStat(kt *kit.Kit) (*kit.Kit, *SyncStat) {
+	stat := NewSyncStat()
+	stat.parent = syncStatFromKit(kt)
+	return WithSyncStat(kt, stat), stat
+}
+
+func syncStatFromKit(kt *kit.Kit) *SyncStat {
+	if kt == nil || kt.Ctx == nil {
+		return nil
+	}
+
+	stat, _ := kt.Ctx.Value(syncStatCtxKey{}).(*SyncStat)
+	return stat
+}
+
+// Count 返回指定资源类型的同步数量。
+func (s *SyncStat) Count(resType enumor.CloudResourceType) SyncCount {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	count, exist := s.counts[resType]
+	if !exist {
+		return SyncCount{}
+	}
+
+	return *count
+}
+
+// Counts 返回各类资源的同步数量。
+func (s *SyncStat) Counts() map[enumor.CloudResourceType]SyncCount {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	counts := make(map[enumor.CloudResourceType]SyncCount, len(s.counts))
+	for resType, count := range s.counts {
+		counts[resType] = *count
+	}
+
+	return counts
+}
+
+// ResTypes 返回有同步数量记录的资源类型，按资源类型排序。
+func (s *SyncStat) ResTypes() []enumor.CloudResourceType {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	resTypes := make([]enumor.CloudResourceType, 0, len(s.counts))
+	for resType := range s.counts {
+		resTypes = append(resTypes, resType)
+	}
+	sort.Slice(resTypes, func(i, j int) bool { return resTypes[i] < resTypes[j] })
+
+	return resTypes
+}
+
+// SeenCloudIDs 返回同步过程中云上查询到的指定类型资源的云ID。
+func (s *SyncStat) SeenCloudIDs(resType enumor.CloudResourceType) map[string]struct{} {
+	root := s.root()
+
+	root.lock.Lock()
+	defer root.lock.Unlock()
+
+	seen := make(map[string]struct{}, len(root.seen[resType]))
+	for cloudID := range root.seen[resType] {
+		seen[cloudID] = struct{}{}
+	}
+
+	return seen
+}
+
+// SeenResTypes 返回有云上查询记录的资源类型，按资源类型排序。
+func (s *SyncStat) SeenResTypes() []enumor.CloudResourceType {
+	root := s.root()
+
+	root.lock.Lock()
+	defer root.lock.Unlock()
+
+	resTypes := make([]enumor.CloudResourceType, 0, len(root.seen))
+	for resType := range root.seen {
+		resTypes = append(resTypes, resType)
+	}
+	sort.Slice(resTypes, func(i, j int) bool { return resTypes[i] < resTypes[j] })
+
+	return resTypes
+}
+
+func (s *SyncStat) root() *SyncStat {
+	root := s
+	for root.parent != nil {
+		root = root.parent
+	}
+
+	return root
+}
+
+// CloudApiCount 返回同步过程中调用云API的次数。
+func (s *SyncStat) CloudApiCount() int {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.cloudApiCount
+}
+
+func (s *SyncStat) add(resType enumor.CloudResourceType, delta SyncCount) {
+	s.lock.Lock()
+
+	count, exist := s.counts[resType]
+	if !exist {
+		count = new(SyncCount)
+		s.counts[resType] = count
+	}
+
+	count.CreatedCount += delta.CreatedCount
+	count.UpdatedCount += delta.UpdatedCount
+	count.DeletedCount += delta.DeletedCount
+	count.QuarantinedCount += delta.QuarantinedCount
+	s.lock.Unlock()
+
+	if s.parent != nil {
+		s.parent.add(resType, delta)
+	}
+}
+
+func (s *SyncStat) addCloudApiCall() {
+	s.lock.Lock()
+	s.cloudApiCount++
+	s.lock.Unlock()
+
+	if s.parent != nil {
+		s.parent.addCloudApiCall()
+	}
+}
+
+// RecordSyncCreated 记录同步新增的资源数量，kit 中未携带同步统计时忽略。
+func RecordSyncCreated(kt *kit.Kit, resType enumor.CloudResourceType, count int) {
+	if stat := syncStatFromKit(kt); stat != nil {
+		stat.add(resType, SyncCount{CreatedCount: count})
+	}
+}
+
+// RecordSyncUpdated 记录同步更新的资源数量，kit 中未携带同步统计时忽略。
+func RecordSyncUpdated(kt *kit.Kit, resType enumor.CloudResourceType, count int) {
+	if stat := syncStatFromKit(kt); stat != nil {
+		stat.add(resType, SyncCount{UpdatedCount: count})
+	}
+}
+
+// RecordSyncDeleted 记录同步删除的资源数量，kit 中未携带同步统计时忽略。
+func RecordSyncDeleted(kt *kit.Kit, resType enumor.CloudResourceType, count int) {
+	if stat := syncStatFromKit(kt); stat != nil {
+		stat.add(resType, SyncCount{DeletedCount: count})
+	}
+}
+
+// RecordSyncQuarantined 记录同步删除被隔离的资源数量，kit 中未携带同步统计时忽略。
+func RecordSyncQuarantined(kt *kit.Kit, resType enumor.CloudResourceType, count int) {
+	if stat := syncStatFromKit(kt); stat != nil {
+		stat.add(resType, SyncCount{QuarantinedCount: count})
+	}
+}
+
+// recordSyncSeen 记录云上查询到的资源云ID，kit 中未携带同步统计时忽略。
+func recordSyncSeen(kt *kit.Kit, resType enumor.CloudResourceType, cloudIDs []string) {
+	stat := syncStatFromKit(kt)
+	if stat == nil || len(cloudIDs) == 0 {
+		return
+	}
+
+	root := stat.root()
+	root.lock.Lock()
+	defer root.lock.Unlock()
+
+	if root.seen == nil {
+		root.seen = make(map[enumor.CloudResourceType]map[string]struct{})
+	}
+
+	seen, exist := root.seen[resType]
+	if !exist {
+		seen = make(map[string]struct{}, len(cloudIDs))
+		root.seen[resType] = seen
+	}
+
+	for _, cloudID := range cloudIDs {
+		seen[cloudID] = struct{}{}
+	}
+}
+
+// RecordCloudApiCall 记录一次云API调用，kit 中未携带同步统计时忽略。
+func RecordCloudApiCall(kt *kit.Kit) {
+	if stat := syncStatFromKit(kt); stat != nil {
+		stat.addCloudApiCall()
+	}
+}
@@ -0,0 +1,72 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package common
+
+import (
+	"reflect"
+	"testing"
+
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/kit"
+)
+
+func TestForkSyncStat(t *testing.T) {
+	stat := NewSyncStat()
+	kt := WithSyncStat(kit.New(), stat)
+
+	RecordSyncCreated(kt, enumor.CvmCloudResType, 2)
+	RecordCloudApiCall(kt)
+
+	childKt, child := ForkSyncStat(kt)
+	RecordSyncUpdated(childKt, enumor.CvmCloudResType, 3)
+	RecordSyncDeleted(childKt, enumor.DiskCloudResType, 1)
+	RecordSyncQuarantined(childKt, enumor.DiskCloudResType, 4)
+	RecordCloudApiCall(childKt)
+
+	expectChild := map[enumor.CloudResourceType]SyncCount{
+		enumor.CvmCloudResType:  {UpdatedCount: 3},
+		enumor.DiskCloudResType: {DeletedCount: 1, QuarantinedCount: 4},
+	}
+	if !reflect.DeepEqual(child.Counts(), expectChild) || child.CloudApiCount() != 1 {
+		t.Errorf("child stat should only count the forked sync, counts: %+v, cloud api count: %d", child.Counts(),
+			child.CloudApiCount())
+	}
+
+	expectParent := map[enumor.CloudResourceType]SyncCount{
+		enumor.CvmCloudResType:  {CreatedCount: 2, UpdatedCount: 3},
+		enumor.DiskCloudResType: {DeletedCount: 1, QuarantinedCount: 4},
+	}
+	if !reflect.DeepEqual(stat.Counts(), expectParent) || stat.CloudApiCount() != 2 {
+		t.Errorf("child stat should be added to parent, counts: %+v, cloud api count: %d", stat.Counts(),
+			stat.CloudApiCount())
+	}
+
+	if resTypes := stat.ResTypes(); !reflect.DeepEqual(resTypes,
+		[]enumor.CloudResourceType{enumor.CvmCloudResType, enumor.DiskCloudResType}) {
+		t.Errorf("unexpected res types: %v", resTypes)
+	}
+
+	// kit 中未携带同步统计时，fork 出的统计仍然可以单独使用
+	noStatKt, alone := ForkSyncStat(kit.New())
+	RecordSyncCreated(noStatKt, enumor.VpcCloudResType, 1)
+	if alone.Count(enumor.VpcCloudResType).CreatedCount != 1 {
+		t.Errorf("forked stat without parent should count, count: %+v", alone.Count(enumor.VpcCloudResType))
+	}
+}
@@ -24,12 +24,11 @@ import (
 	"strings"
 
 	"hcm/cmd/hc-service/logics/res-sync/common"
-	"hcm/pkg/api/core"
+	relmgr "hcm/cmd/hc-service/logics/res-sync/rel-manager"
 	dataservice "hcm/pkg/client/data-service"
 	"hcm/pkg/criteria/constant"
 	"hcm/pkg/criteria/enumor"
 	"hcm/pkg/criteria/validator"
-	"hcm/pkg/dal/dao/tools"
 	"hcm/pkg/kit"
 	"hcm/pkg/logs"
 	"hcm/pkg/tools/converter"
@@ -63,31 +62,27 @@ func (opt SyncRelOption) Validate() error {
 	return validator.Validate.Struct(opt)
 }
 
-// SyncRel 同步主机和关联资源关系表的关联关系
+// SyncRel 同步主机和关联资源关系表的关联关系，关联关系的存储及对账见 relmgr
 func (mgr *CvmRelManger) SyncRel(kt *kit.Kit, opt *SyncRelOption) error {
 	if err := opt.Validate(); err != nil {
 		return err
 	}
 
-	if err := mgr.validateSyncRelParams(opt.Vendor, opt.ResType); err != nil {
-		return err
+	rel, exist := relmgr.Find(enumor.CvmCloudResType, opt.ResType)
+	if !exist {
+		return fmt.Errorf("cvm and %s are not associated", opt.ResType)
 	}
 
-	cvmMap, err := mgr.getCvmMap(kt)
-	if err != nil {
-		logs.Errorf("get cvm map failed, err: %v, rid: %s", err, kt.Rid)
-		return err
+	cvmCloudIDs := make([]string, 0, len(mgr.cvmAssResMap))
+	pairs := make([]relmgr.Pair, 0)
+	for cvmCloudID, assResMap := range mgr.cvmAssResMap {
+		cvmCloudIDs = append(cvmCloudIDs, cvmCloudID)
+		for _, assResCloudID := range assResMap[opt.ResType] {
+			pairs = append(pairs, relmgr.Pair{SrcCloudID: cvmCloudID, DstCloudID: assResCloudID})
+		}
 	}
 
-	switch opt.ResType {
-	case enumor.DiskCloudResType:
-		err = mgr.syncCvmDiskRel(kt, cvmMap, opt)
-	case enumor.SecurityGroupCloudResType:
-		err = mgr.syncCvmSGRel(kt, cvmMap, opt)
-	case enumor.EipCloudResType:
-		err = mgr.syncCvmEipRel(kt, cvmMap, opt)
-	}
-	if err != nil {
+	if err := relmgr.Reconcile(kt, mgr.dataCli, opt.Vendor, rel, cvmCloudIDs, pairs); err != nil {
 		logs.Errorf("sync cvm_%s_rel failed, err: %v, rid: %s", opt.ResType, err, kt.Rid)
 		return err
 	}
@@ -254,112 +249,3 @@ func (mgr *CvmRelManger) getAllCvmAssResCloudIDs(resType enumor.CloudResourceTyp
 
 	return cloudIDs
 }
-
-type cvmRelInfo struct {
-	// RelID 关系表
-	RelID    uint64
-	AssResID string
-	CvmID    string
-}
-
-func (mgr *CvmRelManger) getCvmIDWithAssResIDMap(resType enumor.CloudResourceType,
-	cvmMap, assResMap map[string]string) ([]string, map[string][]string, error) {
-
-	result := make(map[string][]string)
-	cvmIDs := make([]string, 0, len(mgr.cvmAssResMap))
-	for cvmCloudID, valueMap := range mgr.cvmAssResMap {
-		cvmID, exist := cvmMap[cvmCloudID]
-		if !exist {
-			return nil, nil, fmt.Errorf("cvm: %s not found", cvmCloudID)
-		}
-
-		cvmIDs = append(cvmIDs, cvmID)
-		result[cvmID] = make([]string, 0)
-
-		assResCloudIDs, exist := valueMap[resType]
-		if !exist {
-			continue
-		}
-
-		for _, one := range assResCloudIDs {
-			id, exist := assResMap[one]
-			if !exist {
-				return nil, nil, fmt.Errorf("%s: %s not found", resType, one)
-			}
-
-			result[cvmID] = append(result[cvmID], id)
-		}
-	}
-
-	return cvmIDs, result, nil
-}
-
-// validateSyncRelParams 不同vendor的主机可关联的资源有所不同.
-func (mgr *CvmRelManger) validateSyncRelParams(vendor enumor.Vendor, resType enumor.CloudResourceType) error {
-	switch vendor {
-	case enumor.TCloud:
-		switch resType {
-		case enumor.SecurityGroupCloudResType, enumor.DiskCloudResType, enumor.EipCloudResType:
-		default:
-			return fmt.Errorf("vendor: %s cvm and %s are not associated", vendor, resType)
-		}
-	case enumor.Aws:
-		switch resType {
-		case enumor.SecurityGroupCloudResType, enumor.DiskCloudResType, enumor.EipCloudResType:
-		default:
-			return fmt.Errorf("vendor: %s cvm and %s are not associated", vendor, resType)
-		}
-	case enumor.Gcp:
-		switch resType {
-		case enumor.DiskCloudResType, enumor.EipCloudResType, enumor.NetworkInterfaceCloudResType:
-		default:
-			return fmt.Errorf("vendor: %s cvm and %s are not associated", vendor, resType)
-		}
-	case enumor.HuaWei:
-		switch resType {
-		case enumor.SecurityGroupCloudResType, enumor.DiskCloudResType, enumor.EipCloudResType,
-			enumor.NetworkInterfaceCloudResType:
-		default:
-			return fmt.Errorf("vendor: %s cvm and %s are not associated", vendor, resType)
-		}
-	case enumor.Azure:
-		switch resType {
-		case enumor.SecurityGroupCloudResType, enumor.DiskCloudResType, enumor.EipCloudResType,
-			enumor.NetworkInterfaceCloudResType:
-		default:
-			return fmt.Errorf("vendor: %s cvm and %s are not associated", vendor, resType)
-		}
-	default:
-		return fmt.Errorf("vendor: %s not support", vendor)
-	}
-
-	return nil
-}
-
-func (mgr *CvmRelManger) getCvmMap(kt *kit.Kit) (map[string]string, error) {
-	cloudIDs := make([]string, 0, len(mgr.cvmAssResMap))
-	for key := range mgr.cvmAssResMap {
-		cloudIDs = append(cloudIDs, key)
-	}
-
-	cvmMap := make(map[string]string)
-	split := slice.Split(cloudIDs, int(core.DefaultMaxPageLimit))
-	for _, partCloudIDs := range split {
-		req := &core.ListReq{
-			Fields: []string{"id", "cloud_id"},
-			Filter: tools.ContainersExpression("cloud_id", partCloudIDs),
-			Page:   core.NewDefaultBasePage(),
-		}
-		result, err := mgr.dataCli.Global.Cvm.ListCvm(kt, req)
-		if err != nil {
-			logs.Errorf("list cvm failed, err: %v, rid: %s", err, kt.Rid)
-			return nil, err
-		}
-
-		for _, one := range result.Details {
-			cvmMap[one.CloudID] = one.ID
-		}
-	}
-
-	return cvmMap, nil
-}
@@ -0,0 +1,244 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package eventsync
+
+import (
+	"strings"
+
+	"hcm/pkg/adaptor/types/audit-event"
+	"hcm/pkg/criteria/enumor"
+)
+
+// resChange 审计事件映射出的资源变更，除 CloudID 外字段相同的变更合并后进行一次定向同步。
+type resChange struct {
+	ResType           enumor.CloudResourceType
+	Region            string
+	Zone              string
+	ResourceGroupName string
+	CloudVpcID        string
+	CloudID           string
+}
+
+// target 返回变更的同步目标，即去掉云ID后的变更。
+func (c resChange) target() resChange {
+	c.CloudID = ""
+	return c
+}
+
+// syncOrder 定向同步的资源顺序，与全量同步保持一致，主机依赖网络等关联资源，最后同步。
+var syncOrder = map[enumor.CloudResourceType]int{
+	enumor.VpcCloudResType:             0,
+	enumor.SubnetCloudResType:          1,
+	enumor.SecurityGroupCloudResType:   2,
+	enumor.GcpFirewallRuleCloudResType: 2,
+	enumor.DiskCloudResType:            3,
+	enumor.EipCloudResType:             4,
+	enumor.CvmCloudResType:             5,
+}
+
+// mapEvent 将审计事件涉及的资源映射为资源变更，返回无法映射到可同步资源的数量。
+func mapEvent(vendor enumor.Vendor, event auditevent.AuditEvent) ([]resChange, int) {
+	changes := make([]resChange, 0, len(event.Resources))
+	ignored := 0
+	for _, res := range event.Resources {
+		var change resChange
+		var ok bool
+		switch vendor {
+		case enumor.TCloud:
+			change, ok = mapTCloudResource(event, res)
+		case enumor.Aws:
+			change, ok = mapAwsResource(event, res)
+		case enumor.HuaWei:
+			change, ok = mapHuaWeiResource(event, res)
+		case enumor.Gcp:
+			change, ok = mapGcpResource(event, res)
+		case enumor.Azure:
+			change, ok = mapAzureResource(res)
+		}
+
+		if !ok {
+			ignored++
+			continue
+		}
+		changes = append(changes, change)
+	}
+
+	return changes, ignored
+}
+
+// tcloudIDPrefix 腾讯云审计事件的资源类型不统一，通过资源云ID前缀判断资源类型
+var tcloudIDPrefix = map[string]enumor.CloudResourceType{
+	"ins-":    enumor.CvmCloudResType,
+	"vpc-":    enumor.VpcCloudResType,
+	"subnet-": enumor.SubnetCloudResType,
+	"sg-":     enumor.SecurityGroupCloudResType,
+	"disk-":   enumor.DiskCloudResType,
+	"eip-":    enumor.EipCloudResType,
+}
+
+func mapTCloudResource(event auditevent.AuditEvent, res auditevent.Resource) (resChange, bool) {
+	if len(event.Region) == 0 {
+		return resChange{}, false
+	}
+
+	for prefix, resType := range tcloudIDPrefix {
+		if strings.HasPrefix(res.ID, prefix) {
+			return resChange{ResType: resType, Region: event.Region, CloudID: res.ID}, true
+		}
+	}
+
+	return resChange{}, false
+}
+
+var awsResType = map[string]enumor.CloudResourceType{
+	"AWS::EC2::Instance":      enumor.CvmCloudResType,
+	"AWS::EC2::VPC":           enumor.VpcCloudResType,
+	"AWS::EC2::Subnet":        enumor.SubnetCloudResType,
+	"AWS::EC2::SecurityGroup": enumor.SecurityGroupCloudResType,
+	"AWS::EC2::Volume":        enumor.DiskCloudResType,
+	"AWS::EC2::EIP":           enumor.EipCloudResType,
+}
+
+func mapAwsResource(event auditevent.AuditEvent, res auditevent.Resource) (resChange, bool) {
+	resType, exists := awsResType[res.Type]
+	if !exists || len(event.Region) == 0 || len(res.ID) == 0 {
+		return resChange{}, false
+	}
+
+	return resChange{ResType: resType, Region: event.Region, CloudID: res.ID}, true
+}
+
+// huaweiResType 华为云审计事件的资源类型，不同服务的取值大小写和单复数不统一，统一转为小写匹配
+var huaweiResType = map[string]enumor.CloudResourceType{
+	"ecs":             enumor.CvmCloudResType,
+	"server":          enumor.CvmCloudResType,
+	"servers":         enumor.CvmCloudResType,
+	"vpc":             enumor.VpcCloudResType,
+	"vpcs":            enumor.VpcCloudResType,
+	"subnet":          enumor.SubnetCloudResType,
+	"subnets":         enumor.SubnetCloudResType,
+	"security_group":  enumor.SecurityGroupCloudResType,
+	"security_groups": enumor.SecurityGroupCloudResType,
+	"securitygroup":   enumor.SecurityGroupCloudResType,
+	"volume":          enumor.DiskCloudResType,
+	"volumes":         enumor.DiskCloudResType,
+	"evs":             enumor.DiskCloudResType,
+	"eip":             enumor.EipCloudResType,
+	"publicip":        enumor.EipCloudResType,
+	"publicips":       enumor.EipCloudResType,
+}
+
+func mapHuaWeiResource(event auditevent.AuditEvent, res auditevent.Resource) (resChange, bool) {
+	resType, exists := huaweiResType[strings.ToLower(res.Type)]
+	if !exists || len(event.Region) == 0 || len(res.ID) == 0 {
+		return resChange{}, false
+	}
+
+	change := resChange{ResType: resType, Region: event.Region, CloudID: res.ID}
+	// 华为云子网同步需要指定所属vpc
+	if resType == enumor.SubnetCloudResType {
+		if len(res.Parent) == 0 {
+			return resChange{}, false
+		}
+		change.CloudVpcID = res.Parent
+	}
+
+	return change, true
+}
+
+var gcpResType = map[string]enumor.CloudResourceType{
+	"gce_instance":      enumor.CvmCloudResType,
+	"gce_network":       enumor.VpcCloudResType,
+	"gce_subnetwork":    enumor.SubnetCloudResType,
+	"gce_firewall_rule": enumor.GcpFirewallRuleCloudResType,
+	"gce_disk":          enumor.DiskCloudResType,
+}
+
+func mapGcpResource(event auditevent.AuditEvent, res auditevent.Resource) (resChange, bool) {
+	resType, exists := gcpResType[res.Type]
+	if !exists || len(res.ID) == 0 {
+		return resChange{}, false
+	}
+
+	change := resChange{ResType: resType, CloudID: res.ID}
+	switch resType {
+	case enumor.CvmCloudResType:
+		if len(event.Region) == 0 || len(res.Zone) == 0 {
+			return resChange{}, false
+		}
+		change.Region, change.Zone = event.Region, res.Zone
+
+	case enumor.DiskCloudResType:
+		if len(res.Zone) == 0 {
+			return resChange{}, false
+		}
+		change.Zone = res.Zone
+
+	case enumor.SubnetCloudResType:
+		if len(event.Region) == 0 {
+			return resChange{}, false
+		}
+		change.Region = event.Region
+	}
+
+	return change, true
+}
+
+var azureResType = map[string]enumor.CloudResourceType{
+	"microsoft.compute/virtualmachines":         enumor.CvmCloudResType,
+	"microsoft.network/virtualnetworks":         enumor.VpcCloudResType,
+	"microsoft.network/virtualnetworks/subnets": enumor.SubnetCloudResType,
+	"microsoft.network/networksecuritygroups":   enumor.SecurityGroupCloudResType,
+	"microsoft.compute/disks":                   enumor.DiskCloudResType,
+	"microsoft.network/publicipaddresses":       enumor.EipCloudResType,
+}
+
+// mapAzureResource azure 资源云ID为小写的资源ID全路径，资源组从资源ID中解析，
+// 格式为 /subscriptions/{subscription}/resourcegroups/{resource group}/providers/...
+func mapAzureResource(res auditevent.Resource) (resChange, bool) {
+	resType, exists := azureResType[strings.ToLower(res.Type)]
+	if !exists {
+		return resChange{}, false
+	}
+
+	id := strings.ToLower(res.ID)
+	segments := strings.Split(strings.Trim(id, "/"), "/")
+	resGroup := ""
+	for i := 0; i+1 < len(segments); i++ {
+		if segments[i] == "resourcegroups" {
+			resGroup = segments[i+1]
+			break
+		}
+	}
+	if len(resGroup) == 0 {
+		return resChange{}, false
+	}
+
+	change := resChange{ResType: resType, ResourceGroupName: resGroup, CloudID: id}
+	// azure 子网同步需要指定所属虚拟网络
+	if resType == enumor.SubnetCloudResType {
+		if len(res.Parent) == 0 {
+			return resChange{}, false
+		}
+		change.CloudVpcID = strings.ToLower(res.Parent)
+	}
+
+	return change, true
+}
@@ -0,0 +1,224 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package eventsync 基于云上审计事件的增量同步，将审计事件映射为资源类型和云ID后触发定向同步。
+package eventsync
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+
+	cloudadaptor "hcm/cmd/hc-service/logics/cloud-adaptor"
+	"hcm/pkg/adaptor/types/audit-event"
+	"hcm/pkg/cc"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+)
+
+// Source 审计事件来源，默认通过云厂商审计服务拉取，也可以由本地文件或HTTP服务替代，便于测试。
+type Source interface {
+	ListEvent(kt *kit.Kit, vendor enumor.Vendor, accountID string, opt *auditevent.ListOption) (
+		[]auditevent.AuditEvent, error)
+}
+
+// NewSource new audit event source by config, the config should be validated.
+func NewSource(conf cc.AuditEventSource, ad *cloudadaptor.CloudAdaptorClient) Source {
+	switch conf.Type {
+	case cc.FileAuditEventSource:
+		return &fileSource{path: conf.FilePath}
+	case cc.HttpAuditEventSource:
+		return &httpSource{url: conf.URL, cli: http.DefaultClient}
+	default:
+		return &cloudSource{ad: ad}
+	}
+}
+
+// cloudSource 通过云厂商审计服务拉取审计事件。
+type cloudSource struct {
+	ad *cloudadaptor.CloudAdaptorClient
+}
+
+// ListEvent ...
+func (s *cloudSource) ListEvent(kt *kit.Kit, vendor enumor.Vendor, accountID string, opt *auditevent.ListOption) (
+	[]auditevent.AuditEvent, error) {
+
+	switch vendor {
+	case enumor.TCloud:
+		cli, err := s.ad.TCloud(kt, accountID)
+		if err != nil {
+			return nil, err
+		}
+		return cli.ListAuditEvent(kt, opt)
+
+	case enumor.Aws:
+		cli, err := s.ad.Aws(kt, accountID)
+		if err != nil {
+			return nil, err
+		}
+		return cli.ListAuditEvent(kt, opt)
+
+	case enumor.HuaWei:
+		cli, err := s.ad.HuaWei(kt, accountID)
+		if err != nil {
+			return nil, err
+		}
+		return cli.ListAuditEvent(kt, opt)
+
+	case enumor.Gcp:
+		cli, err := s.ad.Gcp(kt, accountID)
+		if err != nil {
+			return nil, err
+		}
+		return cli.ListAuditEvent(kt, opt)
+
+	case enumor.Azure:
+		cli, err := s.ad.Azure(kt, accountID)
+		if err != nil {
+			return nil, err
+		}
+		return cli.ListAuditEvent(kt, opt)
+
+	default:
+		return nil, fmt.Errorf("unsupported vendor: %s", vendor)
+	}
+}
+
+// SourceEvent 文件和HTTP来源使用的审计事件格式，在统一审计事件的基础上标记所属云厂商和账号。
+type SourceEvent struct {
+	Vendor    enumor.Vendor `json:"vendor"`
+	AccountID string        `json:"account_id"`
+	auditevent.AuditEvent
+}
+
+// match 判断事件是否属于查询的云厂商、账号、地域和时间窗口，未指定账号的事件匹配所有账号。
+func (e SourceEvent) match(vendor enumor.Vendor, accountID string, opt *auditevent.ListOption) bool {
+	if e.Vendor != vendor {
+		return false
+	}
+
+	if len(e.AccountID) != 0 && e.AccountID != accountID {
+		return false
+	}
+
+	if len(opt.Region) != 0 && e.Region != opt.Region {
+		return false
+	}
+
+	return !e.Time.Before(opt.StartTime) && e.Time.Before(opt.EndTime)
+}
+
+// fileSource 从本地文件读取审计事件，文件每行一个 JSON 格式的 SourceEvent，每次查询都会重新读取文件。
+type fileSource struct {
+	path string
+}
+
+// ListEvent ...
+func (s *fileSource) ListEvent(kt *kit.Kit, vendor enumor.Vendor, accountID string, opt *auditevent.ListOption) (
+	[]auditevent.AuditEvent, error) {
+
+	file, err := os.Open(s.path)
+	if err != nil {
+		logs.Errorf("open audit event file failed, err: %v, path: %s, rid: %s", err, s.path, kt.Rid)
+		return nil, err
+	}
+	defer file.Close()
+
+	events := make([]auditevent.AuditEvent, 0)
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		content := bytes.TrimSpace(scanner.Bytes())
+		if len(content) == 0 {
+			continue
+		}
+
+		event := new(SourceEvent)
+		if err = json.Unmarshal(content, event); err != nil {
+			return nil, fmt.Errorf("unmarshal audit event at line %d failed, err: %v", line, err)
+		}
+
+		if event.match(vendor, accountID, opt) {
+			events = append(events, event.AuditEvent)
+		}
+	}
+
+	if err = scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+// httpListEventReq 查询HTTP来源审计事件的请求体，服务端返回 SourceEvent 的 JSON 数组。
+type httpListEventReq struct {
+	Vendor    enumor.Vendor `json:"vendor"`
+	AccountID string        `json:"account_id"`
+	*auditevent.ListOption
+}
+
+// httpSource 从HTTP服务查询审计事件，返回的事件同样会按云厂商、账号、地域和时间窗口过滤。
+type httpSource struct {
+	url string
+	cli *http.Client
+}
+
+// ListEvent ...
+func (s *httpSource) ListEvent(kt *kit.Kit, vendor enumor.Vendor, accountID string, opt *auditevent.ListOption) (
+	[]auditevent.AuditEvent, error) {
+
+	body, err := json.Marshal(&httpListEventReq{Vendor: vendor, AccountID: accountID, ListOption: opt})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(kt.Ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.cli.Do(req)
+	if err != nil {
+		logs.Errorf("request audit event source failed, err: %v, url: %s, rid: %s", err, s.url, kt.Rid)
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("audit event source responded with status %d", resp.StatusCode)
+	}
+
+	result := make([]SourceEvent, 0)
+	if err = json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+
+	events := make([]auditevent.AuditEvent, 0, len(result))
+	for _, one := range result {
+		if one.match(vendor, accountID, opt) {
+			events = append(events, one.AuditEvent)
+		}
+	}
+
+	return events, nil
+}
@@ -0,0 +1,100 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package eventsync
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"hcm/pkg/adaptor/types/audit-event"
+	"hcm/pkg/cc"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/kit"
+)
+
+func TestFileSourceMapEvent(t *testing.T) {
+	content := `{"vendor":"tcloud","account_id":"00000001","id":"e1","region":"ap-guangzhou",` +
+		`"time":"2023-12-18T10:00:00Z","resources":[{"type":"cvm","id":"ins-1"},{"type":"clb","id":"lb-1"}]}
+{"vendor":"tcloud","account_id":"00000002","id":"e2","region":"ap-guangzhou",` +
+		`"time":"2023-12-18T10:00:00Z","resources":[{"type":"vpc","id":"vpc-1"}]}
+
+{"vendor":"tcloud","id":"e3","region":"ap-guangzhou","time":"2023-12-18T10:30:00Z",` +
+		`"resources":[{"type":"vpc","id":"vpc-2"}]}
+{"vendor":"tcloud","id":"e4","region":"ap-guangzhou","time":"2023-12-18T11:00:00Z",` +
+		`"resources":[{"type":"vpc","id":"vpc-3"}]}
+{"vendor":"aws","id":"e5","region":"ap-guangzhou","time":"2023-12-18T10:00:00Z",` +
+		`"resources":[{"type":"AWS::EC2::Instance","id":"i-1"}]}
+`
+	path := filepath.Join(t.TempDir(), "events.json")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	source := NewSource(cc.AuditEventSource{Type: cc.FileAuditEventSource, FilePath: path}, nil)
+	opt := &auditevent.ListOption{
+		Region:    "ap-guangzhou",
+		StartTime: time.Date(2023, 12, 18, 10, 0, 0, 0, time.UTC),
+		EndTime:   time.Date(2023, 12, 18, 11, 0, 0, 0, time.UTC),
+	}
+	events, err := source.ListEvent(kit.New(), enumor.TCloud, "00000001", opt)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(events) != 2 || events[0].ID != "e1" || events[1].ID != "e3" {
+		t.Fatalf("got events %+v, expect e1 and e3", events)
+	}
+
+	changes, ignored := mapEvent(enumor.TCloud, events[0])
+	if ignored != 1 || len(changes) != 1 {
+		t.Fatalf("got changes %+v, ignored %d, expect 1 change and 1 ignored", changes, ignored)
+	}
+
+	expect := resChange{ResType: enumor.CvmCloudResType, Region: "ap-guangzhou", CloudID: "ins-1"}
+	if changes[0] != expect {
+		t.Errorf("got change %+v, expect %+v", changes[0], expect)
+	}
+}
+
+func TestMapAzureResource(t *testing.T) {
+	vnet := "/subscriptions/s1/resourceGroups/RG1/providers/Microsoft.Network/virtualNetworks/vnet1"
+	event := auditevent.AuditEvent{Resources: []auditevent.Resource{
+		{Type: "Microsoft.Network/virtualNetworks/subnets", ID: vnet + "/subnets/sub1", Parent: vnet},
+		{Type: "Microsoft.Network/virtualNetworks/subnets", ID: vnet + "/subnets/sub2"},
+	}}
+
+	changes, ignored := mapEvent(enumor.Azure, event)
+	if ignored != 1 || len(changes) != 1 {
+		t.Fatalf("got changes %+v, ignored %d, expect 1 change and 1 ignored", changes, ignored)
+	}
+
+	expect := resChange{
+		ResType:           enumor.SubnetCloudResType,
+		ResourceGroupName: "rg1",
+		CloudVpcID:        "/subscriptions/s1/resourcegroups/rg1/providers/microsoft.network/virtualnetworks/vnet1",
+		CloudID: "/subscriptions/s1/resourcegroups/rg1/providers/microsoft.network/virtualnetworks/vnet1" +
+			"/subnets/sub1",
+	}
+	if changes[0] != expect {
+		t.Errorf("got change %+v, expect %+v", changes[0], expect)
+	}
+}
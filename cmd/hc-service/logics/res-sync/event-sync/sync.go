@@ -0,0 +1,303 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package eventsync
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	ressync "hcm/cmd/hc-service/logics/res-sync"
+	"hcm/cmd/hc-service/logics/res-sync/aws"
+	"hcm/cmd/hc-service/logics/res-sync/azure"
+	"hcm/cmd/hc-service/logics/res-sync/gcp"
+	"hcm/cmd/hc-service/logics/res-sync/huawei"
+	"hcm/cmd/hc-service/logics/res-sync/tcloud"
+	"hcm/pkg/adaptor/types/audit-event"
+	"hcm/pkg/api/hc-service/sync"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/tools/slice"
+)
+
+// Syncer 拉取审计事件并对事件涉及的资源进行定向同步。
+type Syncer struct {
+	source  Source
+	syncCli ressync.Interface
+}
+
+// NewSyncer new audit event syncer.
+func NewSyncer(source Source, syncCli ressync.Interface) *Syncer {
+	return &Syncer{source: source, syncCli: syncCli}
+}
+
+// Sync 拉取时间窗口内的审计事件，按资源类型和同步范围合并后，按云ID分批触发定向同步。
+// 同一资源在窗口内多次变更只会同步一次；资源已从云上删除时，定向同步会将其从本地删除。
+func (s *Syncer) Sync(kt *kit.Kit, vendor enumor.Vendor, req *sync.AuditEventSyncReq) (
+	*sync.AuditEventSyncResult, error) {
+
+	if err := req.Validate(vendor); err != nil {
+		return nil, err
+	}
+
+	start, _ := time.Parse(time.RFC3339, req.StartTime)
+	end, _ := time.Parse(time.RFC3339, req.EndTime)
+
+	regions := req.Regions
+	if vendor == enumor.Gcp || vendor == enumor.Azure {
+		regions = []string{""}
+	}
+
+	result := &sync.AuditEventSyncResult{SyncedCount: make(map[enumor.CloudResourceType]int)}
+	targetIDs := make(map[resChange]map[string]struct{})
+	for _, region := range regions {
+		opt := &auditevent.ListOption{Region: region, StartTime: start, EndTime: end}
+		events, err := s.source.ListEvent(kt, vendor, req.AccountID, opt)
+		if err != nil {
+			logs.Errorf("list %s audit event failed, err: %v, account: %s, region: %s, rid: %s", vendor, err,
+				req.AccountID, region, kt.Rid)
+			return nil, err
+		}
+
+		result.EventCount += len(events)
+		for _, event := range events {
+			changes, ignored := mapEvent(vendor, event)
+			result.IgnoredCount += ignored
+
+			for _, change := range changes {
+				target := change.target()
+				if _, exists := targetIDs[target]; !exists {
+					targetIDs[target] = make(map[string]struct{})
+				}
+				targetIDs[target][change.CloudID] = struct{}{}
+			}
+		}
+	}
+
+	targets := make([]resChange, 0, len(targetIDs))
+	for target := range targetIDs {
+		targets = append(targets, target)
+	}
+	sort.Slice(targets, func(i, j int) bool {
+		if syncOrder[targets[i].ResType] != syncOrder[targets[j].ResType] {
+			return syncOrder[targets[i].ResType] < syncOrder[targets[j].ResType]
+		}
+		return fmt.Sprint(targets[i]) < fmt.Sprint(targets[j])
+	})
+
+	for _, target := range targets {
+		cloudIDs := make([]string, 0, len(targetIDs[target]))
+		for id := range targetIDs[target] {
+			cloudIDs = append(cloudIDs, id)
+		}
+		sort.Strings(cloudIDs)
+
+		for _, batch := range slice.Split(cloudIDs, constant.CloudResourceSyncMaxLimit) {
+			if err := s.syncTarget(kt, vendor, req.AccountID, target, batch); err != nil {
+				logs.Errorf("sync %s %s by audit event failed, err: %v, account: %s, target: %+v, ids: %v, rid: %s",
+					vendor, target.ResType, err, req.AccountID, target, batch, kt.Rid)
+				return nil, err
+			}
+		}
+		result.SyncedCount[target.ResType] += len(cloudIDs)
+	}
+
+	return result, nil
+}
+
+// SyncByCloudIDs 按云ID分批定向同步一类资源，资源已从云上删除时，定向同步会将其从本地删除。
+func (s *Syncer) SyncByCloudIDs(kt *kit.Kit, vendor enumor.Vendor, req *sync.CloudIDSyncReq) error {
+	if err := req.Validate(vendor); err != nil {
+		return err
+	}
+
+	target := resChange{
+		ResType:           req.ResType,
+		Region:            req.Region,
+		Zone:              req.Zone,
+		ResourceGroupName: req.ResourceGroupName,
+		CloudVpcID:        req.CloudVpcID,
+	}
+	for _, batch := range slice.Split(slice.Unique(req.CloudIDs), constant.CloudResourceSyncMaxLimit) {
+		if err := s.syncTarget(kt, vendor, req.AccountID, target, batch); err != nil {
+			logs.Errorf("sync %s %s by cloud ids failed, err: %v, account: %s, target: %+v, ids: %v, rid: %s",
+				vendor, req.ResType, err, req.AccountID, target, batch, kt.Rid)
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *Syncer) syncTarget(kt *kit.Kit, vendor enumor.Vendor, accountID string, target resChange,
+	cloudIDs []string) error {
+
+	switch vendor {
+	case enumor.TCloud:
+		return s.syncTCloud(kt, accountID, target, cloudIDs)
+	case enumor.Aws:
+		return s.syncAws(kt, accountID, target, cloudIDs)
+	case enumor.HuaWei:
+		return s.syncHuaWei(kt, accountID, target, cloudIDs)
+	case enumor.Gcp:
+		return s.syncGcp(kt, accountID, target, cloudIDs)
+	case enumor.Azure:
+		return s.syncAzure(kt, accountID, target, cloudIDs)
+	default:
+		return fmt.Errorf("unsupported vendor: %s", vendor)
+	}
+}
+
+func (s *Syncer) syncTCloud(kt *kit.Kit, accountID string, target resChange, cloudIDs []string) error {
+	cli, err := s.syncCli.TCloud(kt, accountID)
+	if err != nil {
+		return err
+	}
+
+	params := &tcloud.SyncBaseParams{AccountID: accountID, Region: target.Region, CloudIDs: cloudIDs}
+	switch target.ResType {
+	case enumor.CvmCloudResType:
+		_, err = cli.CvmWithRelRes(kt, params, new(tcloud.SyncCvmWithRelResOption))
+	case enumor.VpcCloudResType:
+		_, err = cli.Vpc(kt, params, new(tcloud.SyncVpcOption))
+	case enumor.SubnetCloudResType:
+		_, err = cli.Subnet(kt, params, new(tcloud.SyncSubnetOption))
+	case enumor.SecurityGroupCloudResType:
+		_, err = cli.SecurityGroup(kt, params, new(tcloud.SyncSGOption))
+	case enumor.DiskCloudResType:
+		_, err = cli.Disk(kt, params, new(tcloud.SyncDiskOption))
+	case enumor.EipCloudResType:
+		_, err = cli.Eip(kt, params, new(tcloud.SyncEipOption))
+	default:
+		return fmt.Errorf("tcloud unsupported resource type: %s", target.ResType)
+	}
+
+	return err
+}
+
+func (s *Syncer) syncAws(kt *kit.Kit, accountID string, target resChange, cloudIDs []string) error {
+	cli, err := s.syncCli.Aws(kt, accountID)
+	if err != nil {
+		return err
+	}
+
+	params := &aws.SyncBaseParams{AccountID: accountID, Region: target.Region, CloudIDs: cloudIDs}
+	switch target.ResType {
+	case enumor.CvmCloudResType:
+		_, err = cli.CvmWithRelRes(kt, params, new(aws.SyncCvmWithRelResOption))
+	case enumor.VpcCloudResType:
+		_, err = cli.Vpc(kt, params, new(aws.SyncVpcOption))
+	case enumor.SubnetCloudResType:
+		_, err = cli.Subnet(kt, params, new(aws.SyncSubnetOption))
+	case enumor.SecurityGroupCloudResType:
+		_, err = cli.SecurityGroup(kt, params, new(aws.SyncSGOption))
+	case enumor.DiskCloudResType:
+		_, err = cli.Disk(kt, params, new(aws.SyncDiskOption))
+	case enumor.EipCloudResType:
+		_, err = cli.Eip(kt, params, new(aws.SyncEipOption))
+	default:
+		return fmt.Errorf("aws unsupported resource type: %s", target.ResType)
+	}
+
+	return err
+}
+
+func (s *Syncer) syncHuaWei(kt *kit.Kit, accountID string, target resChange, cloudIDs []string) error {
+	cli, err := s.syncCli.HuaWei(kt, accountID)
+	if err != nil {
+		return err
+	}
+
+	params := &huawei.SyncBaseParams{AccountID: accountID, Region: target.Region, CloudIDs: cloudIDs}
+	switch target.ResType {
+	case enumor.CvmCloudResType:
+		_, err = cli.CvmWithRelRes(kt, params, new(huawei.SyncCvmWithRelResOption))
+	case enumor.VpcCloudResType:
+		_, err = cli.Vpc(kt, params, new(huawei.SyncVpcOption))
+	case enumor.SubnetCloudResType:
+		_, err = cli.Subnet(kt, params, &huawei.SyncSubnetOption{CloudVpcID: target.CloudVpcID})
+	case enumor.SecurityGroupCloudResType:
+		_, err = cli.SecurityGroup(kt, params, new(huawei.SyncSGOption))
+	case enumor.DiskCloudResType:
+		_, err = cli.Disk(kt, params, new(huawei.SyncDiskOption))
+	case enumor.EipCloudResType:
+		_, err = cli.Eip(kt, params, new(huawei.SyncEipOption))
+	default:
+		return fmt.Errorf("huawei unsupported resource type: %s", target.ResType)
+	}
+
+	return err
+}
+
+func (s *Syncer) syncGcp(kt *kit.Kit, accountID string, target resChange, cloudIDs []string) error {
+	cli, err := s.syncCli.Gcp(kt, accountID)
+	if err != nil {
+		return err
+	}
+
+	params := &gcp.SyncBaseParams{AccountID: accountID, CloudIDs: cloudIDs}
+	switch target.ResType {
+	case enumor.CvmCloudResType:
+		opt := &gcp.SyncCvmWithRelResOption{Region: target.Region, Zone: target.Zone}
+		_, err = cli.CvmWithRelRes(kt, params, opt)
+	case enumor.VpcCloudResType:
+		_, err = cli.Vpc(kt, params, new(gcp.SyncVpcOption))
+	case enumor.SubnetCloudResType:
+		_, err = cli.Subnet(kt, params, &gcp.SyncSubnetOption{Region: target.Region})
+	case enumor.GcpFirewallRuleCloudResType:
+		_, err = cli.Firewall(kt, params, new(gcp.SyncFirewallOption))
+	case enumor.DiskCloudResType:
+		_, err = cli.Disk(kt, params, &gcp.SyncDiskOption{Zone: target.Zone})
+	default:
+		return fmt.Errorf("gcp unsupported resource type: %s", target.ResType)
+	}
+
+	return err
+}
+
+func (s *Syncer) syncAzure(kt *kit.Kit, accountID string, target resChange, cloudIDs []string) error {
+	cli, err := s.syncCli.Azure(kt, accountID)
+	if err != nil {
+		return err
+	}
+
+	params := &azure.SyncBaseParams{AccountID: accountID, ResourceGroupName: target.ResourceGroupName,
+		CloudIDs: cloudIDs}
+	switch target.ResType {
+	case enumor.CvmCloudResType:
+		_, err = cli.CvmWithRelRes(kt, params, new(azure.SyncCvmWithRelResOption))
+	case enumor.VpcCloudResType:
+		_, err = cli.Vpc(kt, params, new(azure.SyncVpcOption))
+	case enumor.SubnetCloudResType:
+		_, err = cli.Subnet(kt, params, &azure.SyncSubnetOption{CloudVpcID: target.CloudVpcID})
+	case enumor.SecurityGroupCloudResType:
+		_, err = cli.SecurityGroup(kt, params, new(azure.SyncSGOption))
+	case enumor.DiskCloudResType:
+		_, err = cli.Disk(kt, params, new(azure.SyncDiskOption))
+	case enumor.EipCloudResType:
+		_, err = cli.Eip(kt, params, new(azure.SyncEipOption))
+	default:
+		return fmt.Errorf("azure unsupported resource type: %s", target.ResType)
+	}
+
+	return err
+}
@@ -28,6 +28,7 @@ import (
 	adcore "hcm/pkg/adaptor/types/core"
 	typescvm "hcm/pkg/adaptor/types/cvm"
 	"hcm/pkg/api/core"
+	corecloud "hcm/pkg/api/core/cloud"
 	"hcm/pkg/api/core/cloud/cvm"
 	corecvm "hcm/pkg/api/core/cloud/cvm"
 	dataproto "hcm/pkg/api/data-service/cloud"
@@ -63,6 +64,8 @@ func (cli *client) Cvm(kt *kit.Kit, params *SyncBaseParams, opt *SyncCvmOption)
 		return nil, errf.NewFromErr(errf.InvalidParameter, err)
 	}
 
+	kt, stat := common.ForkSyncStat(kt)
+
 	cvmFromCloud, err := cli.listCvmFromCloud(kt, params, opt)
 	if err != nil {
 		return nil, err
@@ -74,11 +77,11 @@ func (cli *client) Cvm(kt *kit.Kit, params *SyncBaseParams, opt *SyncCvmOption)
 	}
 
 	if len(cvmFromCloud) == 0 && len(cvmFromDB) == 0 {
-		return new(SyncResult), nil
+		return &SyncResult{Counts: stat.Counts()}, nil
 	}
 
 	addSlice, updateMap, delCloudIDs := common.Diff[typescvm.GcpCvm, corecvm.Cvm[cvm.GcpCvmExtension]](
-		cvmFromCloud, cvmFromDB, isCvmChange)
+		kt, enumor.CvmCloudResType, cvmFromCloud, cvmFromDB, isCvmChange)
 
 	if len(delCloudIDs) > 0 {
 		if err = cli.deleteCvm(kt, params.AccountID, opt.Zone, delCloudIDs); err != nil {
@@ -98,7 +101,11 @@ func (cli *client) Cvm(kt *kit.Kit, params *SyncBaseParams, opt *SyncCvmOption)
 		}
 	}
 
-	return new(SyncResult), nil
+	if err = cli.syncCvmTag(kt, params.AccountID, cvmFromCloud); err != nil {
+		return nil, err
+	}
+
+	return &SyncResult{Counts: stat.Counts()}, nil
 }
 
 func (cli *client) createCvm(kt *kit.Kit, accountID string, region string, zone string,
@@ -255,6 +262,7 @@ func (cli *client) createCvm(kt *kit.Kit, accountID string, region string, zone
 
 	logs.Infof("[%s] sync cvm to create cvm success, accountID: %s, count: %d, rid: %s", enumor.Gcp,
 		accountID, len(addSlice), kt.Rid)
+	common.RecordSyncCreated(kt, enumor.CvmCloudResType, len(addSlice))
 
 	return nil
 }
@@ -403,6 +411,7 @@ func (cli *client) updateCvm(kt *kit.Kit, accountID string, region string, zone
 	}
 	logs.Infof("[%s] sync cvm to update cvm success, count: %d, ids: %v, rid: %s",
 		enumor.Gcp, len(updateMap), maps.Keys(updateMap), kt.Rid)
+	common.RecordSyncUpdated(kt, enumor.CvmCloudResType, len(updateMap))
 
 	return nil
 }
@@ -578,6 +587,23 @@ func (cli *client) deleteCvm(kt *kit.Kit, accountID string, zone string, delClou
 		return fmt.Errorf("validate cvm not exist failed, before delete")
 	}
 
+	guardOpt := &common.SyncDeletionGuardOption{
+		Vendor:      enumor.Gcp,
+		ResType:     enumor.CvmCloudResType,
+		AccountID:   accountID,
+		Region:      zone,
+		RegionField: "zone",
+		CloudIDs:    delCloudIDs,
+	}
+	delCloudIDs, err = common.GuardSyncDeletion(kt, cli.dbCli, guardOpt)
+	if err != nil {
+		return err
+	}
+
+	if len(delCloudIDs) == 0 {
+		return nil
+	}
+
 	deleteReq := &dataproto.CvmBatchDeleteReq{
 		Filter: tools.ContainersExpression("cloud_id", delCloudIDs),
 	}
@@ -589,6 +615,11 @@ func (cli *client) deleteCvm(kt *kit.Kit, accountID string, zone string, delClou
 
 	logs.Infof("[%s] sync cvm to delete cvm success, accountID: %s, count: %d, rid: %s", enumor.Gcp,
 		accountID, len(delCloudIDs), kt.Rid)
+	common.RecordSyncDeleted(kt, enumor.CvmCloudResType, len(delCloudIDs))
+
+	if err = common.ConfirmSyncDeletion(kt, cli.dbCli, guardOpt, delCloudIDs); err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -606,6 +637,7 @@ func (cli *client) listCvmFromCloud(kt *kit.Kit, params *SyncBaseParams, option
 			PageSize: adcore.GcpQueryLimit,
 		},
 	}
+	common.RecordCloudApiCall(kt)
 	result, _, err := cli.cloudCli.ListCvm(kt, opt)
 	if err != nil {
 		logs.Errorf("[%s] list cvm from cloud failed, err: %v, account: %s, opt: %v, rid: %s", enumor.Gcp,
@@ -902,3 +934,18 @@ func isCvmChange(cloud typescvm.GcpCvm, db corecvm.Cvm[cvm.GcpCvmExtension]) boo
 
 	return false
 }
+
+// syncCvmTag 使用云上主机标签覆盖db中的主机标签
+func (cli *client) syncCvmTag(kt *kit.Kit, accountID string, cvms []typescvm.GcpCvm) error {
+	tagMap := make(map[string][]corecloud.TagPair, len(cvms))
+	for _, one := range cvms {
+		// gcp 使用 labels 作为资源标签
+		tags := make([]corecloud.TagPair, 0, len(one.Labels))
+		for key, value := range one.Labels {
+			tags = append(tags, corecloud.TagPair{Key: key, Value: value})
+		}
+		tagMap[one.GetCloudID()] = tags
+	}
+
+	return common.SyncResourceTag(kt, cli.dbCli, enumor.Gcp, accountID, enumor.CvmCloudResType, tagMap)
+}
@@ -21,6 +21,7 @@ package gcp
 
 import (
 	"fmt"
+	"hcm/cmd/hc-service/logics/res-sync/common"
 
 	cvmrelmgr "hcm/cmd/hc-service/logics/res-sync/cvm-rel-manager"
 	"hcm/pkg/adaptor/types"
@@ -67,6 +68,8 @@ func (opt SyncCvmWithRelResOption) Validate() error {
 func (cli *client) CvmWithRelRes(kt *kit.Kit, params *SyncBaseParams, opt *SyncCvmWithRelResOption) (
 	*SyncResult, error) {
 
+	kt, stat := common.ForkSyncStat(kt)
+
 	syncCvmOption := &SyncCvmOption{
 		Region: opt.Region,
 		Zone:   opt.Zone,
@@ -82,7 +85,7 @@ func (cli *client) CvmWithRelRes(kt *kit.Kit, params *SyncBaseParams, opt *SyncC
 			return nil, err
 		}
 
-		return new(SyncResult), nil
+		return &SyncResult{Counts: stat.Counts()}, nil
 	}
 
 	// step2: 获取cvm和关联资源的关联关系
@@ -217,7 +220,7 @@ func (cli *client) CvmWithRelRes(kt *kit.Kit, params *SyncBaseParams, opt *SyncC
 		return nil, err
 	}
 
-	return new(SyncResult), nil
+	return &SyncResult{Counts: stat.Counts()}, nil
 }
 
 func (cli *client) getVpcMapFromCloud(kt *kit.Kit, cvmFromCloud []typecvm.GcpCvm) (map[string]string, error) {
@@ -245,6 +248,7 @@ func (cli *client) getVpcMapFromCloud(kt *kit.Kit, cvmFromCloud []typecvm.GcpCvm
 				PageSize: core.GcpSelfLinkMaxQueryLimit,
 			},
 		}
+		common.RecordCloudApiCall(kt)
 		vpcResult, err := cli.cloudCli.ListVpc(kt, opt)
 		if err != nil {
 			logs.Errorf("[%s] list vpc by self link from cloud failed, err: %v, account: %s, opt: %v, rid: %s",
@@ -288,6 +292,7 @@ func (cli *client) getDiskMapFromCloud(kt *kit.Kit, zone string, cvmFromCloud []
 				PageSize: core.GcpSelfLinkMaxQueryLimit,
 			},
 		}
+		common.RecordCloudApiCall(kt)
 		disks, _, err := cli.cloudCli.ListDisk(kt, opt)
 		if err != nil {
 			logs.Errorf("[%s] list disk by self link from cloud failed, err: %v, account: %s, opt: %v, rid: %s",
@@ -333,6 +338,7 @@ func (cli *client) getSubnetMapFromCloud(kt *kit.Kit, region string, cvmFromClou
 			},
 			Region: region,
 		}
+		common.RecordCloudApiCall(kt)
 		subnetResult, err := cli.cloudCli.ListSubnet(kt, opt)
 		if err != nil {
 			logs.Errorf("[%s] list subnet by self link from cloud failed, err: %v, account: %s, opt: %v, rid: %s",
@@ -453,6 +459,7 @@ func (cli *client) getEipMapFromCloud(kt *kit.Kit, cvmFromCloud []typecvm.GcpCvm
 		opt := &typeseip.GcpEipAggregatedListOption{
 			IPAddresses: partIPs,
 		}
+		common.RecordCloudApiCall(kt)
 		eips, err := cli.cloudCli.ListAggregatedEip(kt, opt)
 		if err != nil {
 			logs.Errorf("[%s] list eip by ip from cloud failed, err: %v, account: %s, opt: %v, rid: %s",
@@ -25,6 +25,7 @@ import (
 	"hcm/cmd/hc-service/logics/res-sync/common"
 	adaptordisk "hcm/pkg/adaptor/types/disk"
 	"hcm/pkg/api/core"
+	corecloud "hcm/pkg/api/core/cloud"
 	coredisk "hcm/pkg/api/core/cloud/disk"
 	"hcm/pkg/api/data-service/cloud/disk"
 	"hcm/pkg/criteria/constant"
@@ -55,6 +56,8 @@ func (cli *client) Disk(kt *kit.Kit, params *SyncBaseParams, opt *SyncDiskOption
 		return nil, errf.NewFromErr(errf.InvalidParameter, err)
 	}
 
+	kt, stat := common.ForkSyncStat(kt)
+
 	diskFromCloud, err := cli.listDiskFromCloud(kt, params, opt)
 	if err != nil {
 		return nil, err
@@ -66,7 +69,7 @@ func (cli *client) Disk(kt *kit.Kit, params *SyncBaseParams, opt *SyncDiskOption
 	}
 
 	if len(diskFromCloud) == 0 && len(diskFromDB) == 0 {
-		return new(SyncResult), nil
+		return &SyncResult{Counts: stat.Counts()}, nil
 	}
 
 	if opt.BootMap != nil {
@@ -79,7 +82,7 @@ func (cli *client) Disk(kt *kit.Kit, params *SyncBaseParams, opt *SyncDiskOption
 	}
 
 	addSlice, updateMap, delCloudIDs := common.Diff[adaptordisk.GcpDisk, *coredisk.Disk[coredisk.GcpExtension]](
-		diskFromCloud, diskFromDB, isDiskChange)
+		kt, enumor.DiskCloudResType, diskFromCloud, diskFromDB, isDiskChange)
 
 	if len(delCloudIDs) > 0 {
 		if err := cli.deleteDisk(kt, params.AccountID, opt.Zone, delCloudIDs); err != nil {
@@ -99,7 +102,11 @@ func (cli *client) Disk(kt *kit.Kit, params *SyncBaseParams, opt *SyncDiskOption
 		}
 	}
 
-	return new(SyncResult), nil
+	if err = cli.syncDiskTag(kt, params.AccountID, diskFromCloud); err != nil {
+		return nil, err
+	}
+
+	return &SyncResult{Counts: stat.Counts()}, nil
 }
 
 func (cli *client) listDiskFromCloud(kt *kit.Kit, params *SyncBaseParams,
@@ -113,6 +120,7 @@ func (cli *client) listDiskFromCloud(kt *kit.Kit, params *SyncBaseParams,
 		Zone:     syncOpt.Zone,
 		CloudIDs: params.CloudIDs,
 	}
+	common.RecordCloudApiCall(kt)
 	result, _, err := cli.cloudCli.ListDisk(kt, listOpt)
 	if err != nil {
 		logs.Errorf("[%s] list disk from cloud failed, err: %v, account: %s, listOpt: %v, rid: %s", enumor.Gcp,
@@ -242,6 +250,7 @@ func (cli *client) updateDisk(kt *kit.Kit, accountID string, updateMap map[strin
 
 	logs.Infof("[%s] sync disk to update disk success, accountID: %s, count: %d, rid: %s", enumor.Gcp,
 		accountID, len(updateMap), kt.Rid)
+	common.RecordSyncUpdated(kt, enumor.DiskCloudResType, len(updateMap))
 
 	return nil
 }
@@ -287,6 +296,7 @@ func (cli *client) createDisk(kt *kit.Kit, accountID string, zone string, addSli
 
 	logs.Infof("[%s] sync disk to create disk success, accountID: %s, count: %d, rid: %s", enumor.Gcp,
 		accountID, len(addSlice), kt.Rid)
+	common.RecordSyncCreated(kt, enumor.DiskCloudResType, len(addSlice))
 
 	return nil
 }
@@ -311,6 +321,23 @@ func (cli *client) deleteDisk(kt *kit.Kit, accountID string, zone string, delClo
 		return fmt.Errorf("validate disk not exist failed, before delete")
 	}
 
+	guardOpt := &common.SyncDeletionGuardOption{
+		Vendor:      enumor.Gcp,
+		ResType:     enumor.DiskCloudResType,
+		AccountID:   accountID,
+		Region:      zone,
+		RegionField: "zone",
+		CloudIDs:    delCloudIDs,
+	}
+	delCloudIDs, err = common.GuardSyncDeletion(kt, cli.dbCli, guardOpt)
+	if err != nil {
+		return err
+	}
+
+	if len(delCloudIDs) == 0 {
+		return nil
+	}
+
 	deleteReq := &disk.DiskDeleteReq{
 		Filter: tools.ContainersExpression("cloud_id", delCloudIDs),
 	}
@@ -322,6 +349,11 @@ func (cli *client) deleteDisk(kt *kit.Kit, accountID string, zone string, delClo
 
 	logs.Infof("[%s] sync disk to delete disk success, accountID: %s, count: %d, rid: %s", enumor.Gcp,
 		accountID, len(delCloudIDs), kt.Rid)
+	common.RecordSyncDeleted(kt, enumor.DiskCloudResType, len(delCloudIDs))
+
+	if err = common.ConfirmSyncDeletion(kt, cli.dbCli, guardOpt, delCloudIDs); err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -423,3 +455,17 @@ func isDiskChange(cloud adaptordisk.GcpDisk, db *coredisk.Disk[coredisk.GcpExten
 
 	return false
 }
+
+// syncDiskTag 使用云上硬盘标签覆盖db中的硬盘标签
+func (cli *client) syncDiskTag(kt *kit.Kit, accountID string, diskFromCloud []adaptordisk.GcpDisk) error {
+	tagMap := make(map[string][]corecloud.TagPair, len(diskFromCloud))
+	for _, one := range diskFromCloud {
+		tags := make([]corecloud.TagPair, 0, len(one.Labels))
+		for key, value := range one.Labels {
+			tags = append(tags, corecloud.TagPair{Key: key, Value: value})
+		}
+		tagMap[one.GetCloudID()] = tags
+	}
+
+	return common.SyncResourceTag(kt, cli.dbCli, enumor.Gcp, accountID, enumor.DiskCloudResType, tagMap)
+}
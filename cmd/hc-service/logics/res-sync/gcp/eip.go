@@ -26,6 +26,7 @@ import (
 	adcore "hcm/pkg/adaptor/types/core"
 	typeseip "hcm/pkg/adaptor/types/eip"
 	"hcm/pkg/api/core"
+	corecloud "hcm/pkg/api/core/cloud"
 	dataeip "hcm/pkg/api/data-service/cloud/eip"
 	"hcm/pkg/criteria/constant"
 	"hcm/pkg/criteria/enumor"
@@ -57,6 +58,8 @@ func (cli *client) Eip(kt *kit.Kit, params *SyncBaseParams, opt *SyncEipOption)
 		return nil, errf.NewFromErr(errf.InvalidParameter, err)
 	}
 
+	kt, stat := common.ForkSyncStat(kt)
+
 	eipFromCloud, err := cli.listEipFromCloud(kt, params, opt.Region)
 	if err != nil {
 		return nil, err
@@ -68,11 +71,12 @@ func (cli *client) Eip(kt *kit.Kit, params *SyncBaseParams, opt *SyncEipOption)
 	}
 
 	if len(eipFromCloud) == 0 && len(eipFromDB) == 0 {
-		return new(SyncResult), nil
+		return &SyncResult{Counts: stat.Counts()}, nil
 	}
 
 	addEip, updateMap, delCloudIDs := common.Diff[*typeseip.GcpEip,
-		*dataeip.EipExtResult[dataeip.GcpEipExtensionResult]](eipFromCloud, eipFromDB, isEipChange)
+		*dataeip.EipExtResult[dataeip.GcpEipExtensionResult]](
+		kt, enumor.EipCloudResType, eipFromCloud, eipFromDB, isEipChange)
 
 	if len(delCloudIDs) > 0 {
 		if err = cli.deleteEip(kt, params.AccountID, opt.Region, delCloudIDs); err != nil {
@@ -92,7 +96,11 @@ func (cli *client) Eip(kt *kit.Kit, params *SyncBaseParams, opt *SyncEipOption)
 		}
 	}
 
-	return new(SyncResult), nil
+	if err = cli.syncEipTag(kt, params.AccountID, eipFromCloud); err != nil {
+		return nil, err
+	}
+
+	return &SyncResult{Counts: stat.Counts()}, nil
 }
 
 // RemoveEipDeleteFromCloud ...
@@ -184,6 +192,23 @@ func (cli *client) deleteEip(kt *kit.Kit, accountID string, region string, delCl
 		return fmt.Errorf("validate eip not exist failed, before delete")
 	}
 
+	guardOpt := &common.SyncDeletionGuardOption{
+		Vendor:      enumor.Gcp,
+		ResType:     enumor.EipCloudResType,
+		AccountID:   accountID,
+		Region:      region,
+		RegionField: "region",
+		CloudIDs:    delCloudIDs,
+	}
+	delCloudIDs, err = common.GuardSyncDeletion(kt, cli.dbCli, guardOpt)
+	if err != nil {
+		return err
+	}
+
+	if len(delCloudIDs) == 0 {
+		return nil
+	}
+
 	deleteReq := &dataeip.EipDeleteReq{
 		Filter: tools.ContainersExpression("cloud_id", delCloudIDs),
 	}
@@ -194,6 +219,11 @@ func (cli *client) deleteEip(kt *kit.Kit, accountID string, region string, delCl
 
 	logs.Infof("[%s] sync eip to delete eip success, accountID: %s, count: %d, rid: %s", enumor.Gcp,
 		accountID, len(delCloudIDs), kt.Rid)
+	common.RecordSyncDeleted(kt, enumor.EipCloudResType, len(delCloudIDs))
+
+	if err = common.ConfirmSyncDeletion(kt, cli.dbCli, guardOpt, delCloudIDs); err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -233,6 +263,7 @@ func (cli *client) updateEip(kt *kit.Kit, accountID string, updateMap map[string
 
 	logs.Infof("[%s] sync eip to update eip success, accountID: %s, count: %d, rid: %s", enumor.Gcp,
 		accountID, len(updateMap), kt.Rid)
+	common.RecordSyncUpdated(kt, enumor.EipCloudResType, len(updateMap))
 
 	return nil
 }
@@ -277,6 +308,7 @@ func (cli *client) createEip(kt *kit.Kit, accountID string, addEip []*typeseip.G
 
 	logs.Infof("[%s] sync eip to create eip success, accountID: %s, count: %d, rid: %s", enumor.Gcp,
 		accountID, len(addEip), kt.Rid)
+	common.RecordSyncCreated(kt, enumor.EipCloudResType, len(addEip))
 
 	return nil
 }
@@ -293,6 +325,7 @@ func (cli *client) listEipFromCloud(kt *kit.Kit, params *SyncBaseParams, region
 			PageSize: adcore.GcpQueryLimit,
 		},
 	}
+	common.RecordCloudApiCall(kt)
 	result, err := cli.cloudCli.ListEip(kt, opt)
 	if err != nil {
 		logs.Errorf("[%s] list eip from cloud failed, err: %v, account: %s, opt: %v, rid: %s", enumor.Gcp, err,
@@ -390,3 +423,13 @@ func isEipChange(cloud *typeseip.GcpEip, db *dataeip.EipExtResult[dataeip.GcpEip
 
 	return false
 }
+
+// syncEipTag 使用云上eip标签覆盖db中的eip标签
+func (cli *client) syncEipTag(kt *kit.Kit, accountID string, eipFromCloud []*typeseip.GcpEip) error {
+	tagMap := make(map[string][]corecloud.TagPair, len(eipFromCloud))
+	for _, one := range eipFromCloud {
+		tagMap[one.GetCloudID()] = one.Tags
+	}
+
+	return common.SyncResourceTag(kt, cli.dbCli, enumor.Gcp, accountID, enumor.EipCloudResType, tagMap)
+}
@@ -54,6 +54,8 @@ func (cli *client) Firewall(kt *kit.Kit, params *SyncBaseParams, opt *SyncFirewa
 		return nil, errf.NewFromErr(errf.InvalidParameter, err)
 	}
 
+	kt, stat := common.ForkSyncStat(kt)
+
 	firewallFromCloud, err := cli.listFirewallFromCloud(kt, params)
 	if err != nil {
 		return nil, err
@@ -65,11 +67,11 @@ func (cli *client) Firewall(kt *kit.Kit, params *SyncBaseParams, opt *SyncFirewa
 	}
 
 	if len(firewallFromCloud) == 0 && len(firewallFromDB) == 0 {
-		return new(SyncResult), nil
+		return &SyncResult{Counts: stat.Counts()}, nil
 	}
 
 	addSlice, updateMap, delCloudIDs := common.Diff[firewallrule.GcpFirewall, cloudcore.GcpFirewallRule](
-		firewallFromCloud, firewallFromDB, isFirewallChange)
+		kt, enumor.GcpFirewallRuleCloudResType, firewallFromCloud, firewallFromDB, isFirewallChange)
 
 	if len(delCloudIDs) > 0 {
 		if err = cli.deleteFirewall(kt, params.AccountID, delCloudIDs); err != nil {
@@ -89,7 +91,7 @@ func (cli *client) Firewall(kt *kit.Kit, params *SyncBaseParams, opt *SyncFirewa
 		}
 	}
 
-	return new(SyncResult), nil
+	return &SyncResult{Counts: stat.Counts()}, nil
 }
 
 func (cli *client) createFirewall(kt *kit.Kit, accountID string,
@@ -175,6 +177,7 @@ func (cli *client) createFirewall(kt *kit.Kit, accountID string,
 
 	logs.Infof("[%s] sync firewall to create firewall success, accountID: %s, count: %d, rid: %s", enumor.Gcp,
 		accountID, len(addSlice), kt.Rid)
+	common.RecordSyncCreated(kt, enumor.GcpFirewallRuleCloudResType, len(addSlice))
 
 	return nil
 }
@@ -263,6 +266,7 @@ func (cli *client) updateFirewall(kt *kit.Kit, accountID string,
 
 	logs.Infof("[%s] sync firewall to update firewall success, accountID: %s, count: %d, rid: %s", enumor.Gcp,
 		accountID, len(updateMap), kt.Rid)
+	common.RecordSyncUpdated(kt, enumor.GcpFirewallRuleCloudResType, len(updateMap))
 
 	return nil
 }
@@ -287,6 +291,21 @@ func (cli *client) deleteFirewall(kt *kit.Kit, accountID string, delCloudIDs []s
 		return fmt.Errorf("validate firewall not exist failed, before delete")
 	}
 
+	guardOpt := &common.SyncDeletionGuardOption{
+		Vendor:    enumor.Gcp,
+		ResType:   enumor.GcpFirewallRuleCloudResType,
+		AccountID: accountID,
+		CloudIDs:  delCloudIDs,
+	}
+	delCloudIDs, err = common.GuardSyncDeletion(kt, cli.dbCli, guardOpt)
+	if err != nil {
+		return err
+	}
+
+	if len(delCloudIDs) == 0 {
+		return nil
+	}
+
 	deleteReq := &cloud.GcpFirewallRuleBatchDeleteReq{
 		Filter: tools.ContainersExpression("cloud_id", delCloudIDs),
 	}
@@ -298,6 +317,11 @@ func (cli *client) deleteFirewall(kt *kit.Kit, accountID string, delCloudIDs []s
 
 	logs.Infof("[%s] sync firewall to delete firewall success, accountID: %s, count: %d, rid: %s", enumor.Gcp,
 		accountID, len(delCloudIDs), kt.Rid)
+	common.RecordSyncDeleted(kt, enumor.GcpFirewallRuleCloudResType, len(delCloudIDs))
+
+	if err = common.ConfirmSyncDeletion(kt, cli.dbCli, guardOpt, delCloudIDs); err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -310,6 +334,7 @@ func (cli *client) listFirewallFromCloud(kt *kit.Kit, params *SyncBaseParams) ([
 	opt := &firewallrule.ListOption{
 		CloudIDs: converter.StringSliceToUint64Slice(params.CloudIDs),
 	}
+	common.RecordCloudApiCall(kt)
 	result, _, err := cli.cloudCli.ListFirewallRule(kt, opt)
 	if err != nil {
 		logs.Errorf("[%s] list firewall from cloud failed, err: %v, account: %s, opt: %v, rid: %s", enumor.Gcp,
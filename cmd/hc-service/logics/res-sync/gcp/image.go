@@ -55,6 +55,8 @@ func (cli *client) Image(kt *kit.Kit, params *SyncBaseParams, opt *SyncImageOpti
 		return nil, errf.NewFromErr(errf.InvalidParameter, err)
 	}
 
+	kt, stat := common.ForkSyncStat(kt)
+
 	imageFromCloud, err := cli.listImageFromCloud(kt, params, opt.ProjectID)
 	if err != nil {
 		return nil, err
@@ -66,11 +68,11 @@ func (cli *client) Image(kt *kit.Kit, params *SyncBaseParams, opt *SyncImageOpti
 	}
 
 	if len(imageFromCloud) == 0 && len(imageFromDB) == 0 {
-		return new(SyncResult), nil
+		return &SyncResult{Counts: stat.Counts()}, nil
 	}
 
 	addSlice, updateMap, delCloudIDs := common.Diff[typesimage.GcpImage, coreimage.Image[coreimage.GcpExtension]](
-		imageFromCloud, imageFromDB, isImageChange)
+		kt, enumor.ImageCloudResType, imageFromCloud, imageFromDB, isImageChange)
 
 	if len(delCloudIDs) > 0 {
 		if err = cli.deleteImage(kt, params.AccountID, opt.ProjectID, delCloudIDs); err != nil {
@@ -90,7 +92,7 @@ func (cli *client) Image(kt *kit.Kit, params *SyncBaseParams, opt *SyncImageOpti
 		}
 	}
 
-	return new(SyncResult), nil
+	return &SyncResult{Counts: stat.Counts()}, nil
 }
 
 func (cli *client) updateImage(kt *kit.Kit, accountID string, region string,
@@ -120,6 +122,7 @@ func (cli *client) updateImage(kt *kit.Kit, accountID string, region string,
 
 	logs.Infof("[%s] sync image to update image success, accountID: %s, count: %d, rid: %s", enumor.Gcp,
 		accountID, len(updateMap), kt.Rid)
+	common.RecordSyncUpdated(kt, enumor.ImageCloudResType, len(updateMap))
 
 	return nil
 }
@@ -161,6 +164,7 @@ func (cli *client) createImage(kt *kit.Kit, accountID, projectID, region string,
 
 	logs.Infof("[%s] sync image to create image success, accountID: %s, count: %d, rid: %s", enumor.Gcp,
 		accountID, len(addSlice), kt.Rid)
+	common.RecordSyncCreated(kt, enumor.ImageCloudResType, len(addSlice))
 
 	return nil
 }
@@ -170,6 +174,10 @@ func (cli *client) deleteImage(kt *kit.Kit, accountID string, projectID string,
 		return fmt.Errorf("image delCloudIDs is <= 0, not delete")
 	}
 
+	if common.RecordDryRunDelete(kt, enumor.ImageCloudResType, delCloudIDs) {
+		return nil
+	}
+
 	checkParams := &SyncBaseParams{
 		AccountID: accountID,
 		CloudIDs:  delCloudIDs,
@@ -195,6 +203,7 @@ func (cli *client) deleteImage(kt *kit.Kit, accountID string, projectID string,
 
 	logs.Infof("[%s] sync image to delete image success, accountID: %s, count: %d, rid: %s", enumor.Gcp,
 		accountID, len(delCloudIDs), kt.Rid)
+	common.RecordSyncDeleted(kt, enumor.ImageCloudResType, len(delCloudIDs))
 
 	return nil
 }
@@ -210,6 +219,7 @@ func (cli *client) listImageFromCloud(kt *kit.Kit, params *SyncBaseParams,
 		ProjectID: projectID,
 		CloudIDs:  params.CloudIDs,
 	}
+	common.RecordCloudApiCall(kt)
 	result, _, err := cli.cloudCli.ListImage(kt, opt)
 	if err != nil {
 		logs.Errorf("[%s] list image from cloud failed, err: %v, account: %s, opt: %v, rid: %s", enumor.Gcp,
@@ -63,6 +63,8 @@ func (cli *client) NetworkInterface(kt *kit.Kit, params *SyncBaseParams, opt *Sy
 		return nil, errf.NewFromErr(errf.InvalidParameter, err)
 	}
 
+	kt, stat := common.ForkSyncStat(kt)
+
 	err := concurrence.BaseExec(constant.SyncConcurrencyDefaultMaxLimit, params.CloudIDs, func(param string) error {
 		syncOpt := &syncNIOption{
 			AccountID:  params.AccountID,
@@ -81,7 +83,7 @@ func (cli *client) NetworkInterface(kt *kit.Kit, params *SyncBaseParams, opt *Sy
 		return nil, err
 	}
 
-	return new(SyncResult), nil
+	return &SyncResult{Counts: stat.Counts()}, nil
 }
 
 type syncNIOption struct {
@@ -116,7 +118,8 @@ func (cli *client) syncNetworkInterface(kt *kit.Kit, opt *syncNIOption) (*SyncRe
 	}
 
 	addSlice, updateMap, delCloudIDs := common.Diff[typesni.GcpNI, coreni.
-		NetworkInterface[coreni.GcpNIExtension]](networkInterfaceFromCloud, networkInterfaceFromDB, isNIChange)
+		NetworkInterface[coreni.GcpNIExtension]](
+		kt, enumor.NetworkInterfaceCloudResType, networkInterfaceFromCloud, networkInterfaceFromDB, isNIChange)
 
 	if len(delCloudIDs) > 0 {
 		if err = cli.deleteNetworkInterface(kt, delCloudIDs, opt); err != nil {
@@ -163,6 +166,23 @@ func (cli *client) deleteNetworkInterface(kt *kit.Kit, delCloudIDs []string, opt
 		}
 	}
 
+	guardOpt := &common.SyncDeletionGuardOption{
+		Vendor:      enumor.Gcp,
+		ResType:     enumor.NetworkInterfaceCloudResType,
+		AccountID:   opt.AccountID,
+		Region:      opt.Zone,
+		RegionField: "zone",
+		CloudIDs:    delCloudIDs,
+	}
+	delCloudIDs, err = common.GuardSyncDeletion(kt, cli.dbCli, guardOpt)
+	if err != nil {
+		return err
+	}
+
+	if len(delCloudIDs) == 0 {
+		return nil
+	}
+
 	deleteReq := &dataproto.BatchDeleteReq{
 		Filter: tools.ContainersExpression("cloud_id", delCloudIDs),
 	}
@@ -175,6 +195,10 @@ func (cli *client) deleteNetworkInterface(kt *kit.Kit, delCloudIDs []string, opt
 	logs.V(3).Infof("[%s] sync network interface to delete ni success, accountID: %s, count: %d, rid: %s",
 		enumor.Gcp, opt.AccountID, len(delCloudIDs), kt.Rid)
 
+	if err = common.ConfirmSyncDeletion(kt, cli.dbCli, guardOpt, delCloudIDs); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -430,6 +454,7 @@ func (cli *client) listNetworkInterfaceFromCloud(kt *kit.Kit, zone, cloudCvmID s
 		Zone:        zone,
 		CloudCvmIDs: []string{cloudCvmID},
 	}
+	common.RecordCloudApiCall(kt)
 	result, err := cli.cloudCli.ListNetworkInterfaceByCvmID(kt, listOpt)
 	if err != nil {
 		logs.Errorf("[%s] list networkInterface from cloud failed, err: %v, rid: %s", enumor.Gcp, err, kt.Rid)
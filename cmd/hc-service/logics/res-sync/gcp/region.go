@@ -58,6 +58,8 @@ func (cli *client) Region(kt *kit.Kit, params *SyncBaseParams, opt *SyncRegionOp
 		return nil, errf.NewFromErr(errf.InvalidParameter, err)
 	}
 
+	kt, stat := common.ForkSyncStat(kt)
+
 	regionFromCloud, err := cli.listRegionFromCloud(kt, params)
 	if err != nil {
 		return nil, err
@@ -69,11 +71,11 @@ func (cli *client) Region(kt *kit.Kit, params *SyncBaseParams, opt *SyncRegionOp
 	}
 
 	if len(regionFromCloud) == 0 && len(regionFromDB) == 0 {
-		return new(SyncResult), nil
+		return &SyncResult{Counts: stat.Counts()}, nil
 	}
 
 	addSlice, updateMap, delCloudIDs := common.Diff[typesregion.GcpRegion, cloudcore.GcpRegion](
-		regionFromCloud, regionFromDB, isRegionChange)
+		kt, enumor.RegionCloudResType, regionFromCloud, regionFromDB, isRegionChange)
 
 	if len(delCloudIDs) > 0 {
 		if err := cli.deleteRegion(kt, params.AccountID, delCloudIDs); err != nil {
@@ -93,7 +95,7 @@ func (cli *client) Region(kt *kit.Kit, params *SyncBaseParams, opt *SyncRegionOp
 		}
 	}
 
-	return new(SyncResult), nil
+	return &SyncResult{Counts: stat.Counts()}, nil
 }
 
 func (cli *client) listRegionFromCloud(kt *kit.Kit, params *SyncBaseParams) ([]adaptorregion.GcpRegion, error) {
@@ -107,6 +109,7 @@ func (cli *client) listRegionFromCloud(kt *kit.Kit, params *SyncBaseParams) ([]a
 		},
 		CloudIDs: params.CloudIDs,
 	}
+	common.RecordCloudApiCall(kt)
 	result, err := cli.cloudCli.ListRegion(kt, opt)
 	if err != nil {
 		logs.Errorf("[%s] list region from cloud failed, err: %v, account: %s, opt: %v, rid: %s", enumor.Gcp,
@@ -181,6 +184,7 @@ func (cli *client) createRegion(kt *kit.Kit, accountID string, addSlice []typesr
 
 	logs.Infof("[%s] sync region to create region success, accountID: %s, count: %d, rid: %s", enumor.Gcp,
 		accountID, len(addSlice), kt.Rid)
+	common.RecordSyncCreated(kt, enumor.RegionCloudResType, len(addSlice))
 
 	return nil
 }
@@ -215,6 +219,7 @@ func (cli *client) updateRegion(kt *kit.Kit, accountID string,
 
 	logs.Infof("[%s] sync region to update region success, accountID: %s, count: %d, rid: %s", enumor.Gcp,
 		accountID, len(updateMap), kt.Rid)
+	common.RecordSyncUpdated(kt, enumor.RegionCloudResType, len(updateMap))
 
 	return nil
 }
@@ -224,6 +229,10 @@ func (cli *client) deleteRegion(kt *kit.Kit, accountID string, delCloudIDs []str
 		return errors.New("region delCloudIDs is <= 0, not delete")
 	}
 
+	if common.RecordDryRunDelete(kt, enumor.RegionCloudResType, delCloudIDs) {
+		return nil
+	}
+
 	checkParams := &SyncBaseParams{
 		AccountID: accountID,
 		CloudIDs:  delCloudIDs,
@@ -253,6 +262,7 @@ func (cli *client) deleteRegion(kt *kit.Kit, accountID string, delCloudIDs []str
 
 	logs.Infof("[%s] sync region to delete region success, accountID: %s, count: %d, rid: %s", enumor.Gcp,
 		accountID, len(delCloudIDs), kt.Rid)
+	common.RecordSyncDeleted(kt, enumor.RegionCloudResType, len(delCloudIDs))
 
 	return nil
 }
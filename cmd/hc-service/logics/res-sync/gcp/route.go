@@ -55,6 +55,8 @@ func (cli *client) Route(kt *kit.Kit, params *SyncBaseParams, opt *SyncRouteOpti
 		return nil, errf.NewFromErr(errf.InvalidParameter, err)
 	}
 
+	kt, stat := common.ForkSyncStat(kt)
+
 	routeFromCloud, err := cli.listRouteFromCloud(kt, params)
 	if err != nil {
 		return nil, err
@@ -66,11 +68,11 @@ func (cli *client) Route(kt *kit.Kit, params *SyncBaseParams, opt *SyncRouteOpti
 	}
 
 	if len(routeFromCloud) == 0 && len(routeFromDB) == 0 {
-		return new(SyncResult), nil
+		return &SyncResult{Counts: stat.Counts()}, nil
 	}
 
 	addSlice, updateMap, delCloudIDs := common.Diff[typesroutetable.GcpRoute, cloudcoreroutetable.GcpRoute](
-		routeFromCloud, routeFromDB, isRouteChange)
+		kt, enumor.RouteCloudResType, routeFromCloud, routeFromDB, isRouteChange)
 
 	if len(delCloudIDs) > 0 {
 		if err := cli.deleteRoute(kt, params.AccountID, delCloudIDs, routeFromDB); err != nil {
@@ -90,7 +92,7 @@ func (cli *client) Route(kt *kit.Kit, params *SyncBaseParams, opt *SyncRouteOpti
 		}
 	}
 
-	return new(SyncResult), nil
+	return &SyncResult{Counts: stat.Counts()}, nil
 }
 
 func (cli *client) createRoute(kt *kit.Kit, accountID string,
@@ -136,6 +138,7 @@ func (cli *client) createRoute(kt *kit.Kit, accountID string,
 
 	logs.Infof("[%s] sync route to create route success, accountID: %s, count: %d, rid: %s", enumor.Gcp,
 		accountID, len(addSlice), kt.Rid)
+	common.RecordSyncCreated(kt, enumor.RouteCloudResType, len(addSlice))
 
 	return nil
 }
@@ -150,6 +153,7 @@ func (cli *client) updateRoute(kt *kit.Kit, accountID string,
 
 	logs.Infof("[%s] sync route to update route success, accountID: %s, count: %d, rid: %s", enumor.Gcp,
 		accountID, len(updateMap), kt.Rid)
+	common.RecordSyncUpdated(kt, enumor.RouteCloudResType, len(updateMap))
 
 	return nil
 }
@@ -161,6 +165,10 @@ func (cli *client) deleteRoute(kt *kit.Kit, accountID string, delCloudIDs []stri
 		return fmt.Errorf("route delCloudIDs is <= 0, not delete")
 	}
 
+	if common.RecordDryRunDelete(kt, enumor.RouteCloudResType, delCloudIDs) {
+		return nil
+	}
+
 	checkParams := &SyncBaseParams{
 		AccountID: accountID,
 		CloudIDs:  delCloudIDs,
@@ -195,6 +203,7 @@ func (cli *client) deleteRoute(kt *kit.Kit, accountID string, delCloudIDs []stri
 
 	logs.Infof("[%s] sync route to delete route success, accountID: %s, count: %d, rid: %s", enumor.Gcp,
 		accountID, len(delCloudIDs), kt.Rid)
+	common.RecordSyncDeleted(kt, enumor.RouteCloudResType, len(delCloudIDs))
 
 	return nil
 }
@@ -220,6 +229,7 @@ func (cli *client) listRouteFromCloud(kt *kit.Kit, params *SyncBaseParams) ([]ty
 			PageSize: adcore.GcpQueryLimit,
 		},
 	}
+	common.RecordCloudApiCall(kt)
 	results, err := cli.cloudCli.ListRoute(kt, opt)
 	if err != nil {
 		logs.Errorf("[%s] list route from cloud failed, err: %v, account: %s, opt: %v, rid: %s", enumor.Gcp,
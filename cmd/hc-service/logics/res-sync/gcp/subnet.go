@@ -57,6 +57,8 @@ func (cli *client) Subnet(kt *kit.Kit, params *SyncBaseParams, opt *SyncSubnetOp
 		return nil, errf.NewFromErr(errf.InvalidParameter, err)
 	}
 
+	kt, stat := common.ForkSyncStat(kt)
+
 	subnetFromCloud, err := cli.listSubnetFromCloud(kt, params, opt.Region)
 	if err != nil {
 		return nil, err
@@ -68,11 +70,11 @@ func (cli *client) Subnet(kt *kit.Kit, params *SyncBaseParams, opt *SyncSubnetOp
 	}
 
 	if len(subnetFromCloud) == 0 && len(subnetFromDB) == 0 {
-		return new(SyncResult), nil
+		return &SyncResult{Counts: stat.Counts()}, nil
 	}
 
 	addSubnet, updateMap, delCloudIDs := common.Diff[adtysubnet.GcpSubnet, cloudcore.Subnet[cloudcore.GcpSubnetExtension]](
-		subnetFromCloud, subnetFromDB, isGcpSubnetChange)
+		kt, enumor.SubnetCloudResType, subnetFromCloud, subnetFromDB, isGcpSubnetChange)
 
 	if len(delCloudIDs) > 0 {
 		if err = cli.deleteSubnet(kt, params.AccountID, opt.Region, delCloudIDs); err != nil {
@@ -92,7 +94,7 @@ func (cli *client) Subnet(kt *kit.Kit, params *SyncBaseParams, opt *SyncSubnetOp
 		}
 	}
 
-	return new(SyncResult), nil
+	return &SyncResult{Counts: stat.Counts()}, nil
 }
 
 // RemoveSubnetDeleteFromCloud ...
@@ -184,6 +186,23 @@ func (cli *client) deleteSubnet(kt *kit.Kit, accountID, region string, delCloudI
 		return fmt.Errorf("validate subnet not exist failed, before delete")
 	}
 
+	guardOpt := &common.SyncDeletionGuardOption{
+		Vendor:      enumor.Gcp,
+		ResType:     enumor.SubnetCloudResType,
+		AccountID:   accountID,
+		Region:      region,
+		RegionField: "region",
+		CloudIDs:    delCloudIDs,
+	}
+	delCloudIDs, err = common.GuardSyncDeletion(kt, cli.dbCli, guardOpt)
+	if err != nil {
+		return err
+	}
+
+	if len(delCloudIDs) == 0 {
+		return nil
+	}
+
 	deleteReq := &dataservice.BatchDeleteReq{
 		Filter: tools.ContainersExpression("cloud_id", delCloudIDs),
 	}
@@ -194,6 +213,11 @@ func (cli *client) deleteSubnet(kt *kit.Kit, accountID, region string, delCloudI
 
 	logs.Infof("[%s] sync subnet to delete subnet success, accountID: %s, count: %d, rid: %s", enumor.Gcp,
 		accountID, len(delCloudIDs), kt.Rid)
+	common.RecordSyncDeleted(kt, enumor.SubnetCloudResType, len(delCloudIDs))
+
+	if err = common.ConfirmSyncDeletion(kt, cli.dbCli, guardOpt, delCloudIDs); err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -237,6 +261,7 @@ func (cli *client) updateSubnet(kt *kit.Kit, accountID string, updateMap map[str
 
 	logs.Infof("[%s] sync subnet to update subnet success, accountID: %s, count: %d, rid: %s", enumor.Gcp,
 		accountID, len(updateMap), kt.Rid)
+	common.RecordSyncUpdated(kt, enumor.SubnetCloudResType, len(updateMap))
 
 	return nil
 }
@@ -310,6 +335,7 @@ func (cli *client) createSubnet(kt *kit.Kit, accountID string, addSubnet []adtys
 
 	logs.Infof("[%s] sync subnet to create subnet success, accountID: %s, count: %d, rid: %s", enumor.Gcp,
 		accountID, len(addSubnet), kt.Rid)
+	common.RecordSyncCreated(kt, enumor.SubnetCloudResType, len(addSubnet))
 
 	return nil
 }
@@ -329,6 +355,7 @@ func (cli *client) listSubnetFromCloud(kt *kit.Kit, params *SyncBaseParams, regi
 		},
 		Region: region,
 	}
+	common.RecordCloudApiCall(kt)
 	result, err := cli.cloudCli.ListSubnet(kt, opt)
 	if err != nil {
 		logs.Errorf("[%s] list subnet from cloud failed, err: %v, account: %s, opt: %v, rid: %s", enumor.Gcp, err,
@@ -56,6 +56,8 @@ func (cli *client) Vpc(kt *kit.Kit, params *SyncBaseParams, opt *SyncVpcOption)
 		return nil, errf.NewFromErr(errf.InvalidParameter, err)
 	}
 
+	kt, stat := common.ForkSyncStat(kt)
+
 	vpcFromCloud, err := cli.listVpcFromCloud(kt, params)
 	if err != nil {
 		return nil, err
@@ -67,11 +69,11 @@ func (cli *client) Vpc(kt *kit.Kit, params *SyncBaseParams, opt *SyncVpcOption)
 	}
 
 	if len(vpcFromCloud) == 0 && len(vpcFromDB) == 0 {
-		return new(SyncResult), nil
+		return &SyncResult{Counts: stat.Counts()}, nil
 	}
 
 	addVpc, updateMap, delCloudIDs := common.Diff[types.GcpVpc, cloudcore.Vpc[cloudcore.GcpVpcExtension]](
-		vpcFromCloud, vpcFromDB, isGcpVpcChange)
+		kt, enumor.VpcCloudResType, vpcFromCloud, vpcFromDB, isGcpVpcChange)
 
 	if len(delCloudIDs) > 0 {
 		if err = cli.deleteVpc(kt, params.AccountID, delCloudIDs); err != nil {
@@ -91,7 +93,7 @@ func (cli *client) Vpc(kt *kit.Kit, params *SyncBaseParams, opt *SyncVpcOption)
 		}
 	}
 
-	return new(SyncResult), nil
+	return &SyncResult{Counts: stat.Counts()}, nil
 }
 
 // RemoveVpcDeleteFromCloud ...
@@ -182,6 +184,21 @@ func (cli *client) deleteVpc(kt *kit.Kit, accountID string, delCloudIDs []string
 		return fmt.Errorf("validate vpc not exist failed, before delete")
 	}
 
+	guardOpt := &common.SyncDeletionGuardOption{
+		Vendor:    enumor.Gcp,
+		ResType:   enumor.VpcCloudResType,
+		AccountID: accountID,
+		CloudIDs:  delCloudIDs,
+	}
+	delCloudIDs, err = common.GuardSyncDeletion(kt, cli.dbCli, guardOpt)
+	if err != nil {
+		return err
+	}
+
+	if len(delCloudIDs) == 0 {
+		return nil
+	}
+
 	deleteReq := &dataservice.BatchDeleteReq{
 		Filter: tools.ContainersExpression("cloud_id", delCloudIDs),
 	}
@@ -192,6 +209,11 @@ func (cli *client) deleteVpc(kt *kit.Kit, accountID string, delCloudIDs []string
 
 	logs.Infof("[%s] sync vpc to delete vpc success, accountID: %s, count: %d, rid: %s", enumor.Gcp,
 		accountID, len(delCloudIDs), kt.Rid)
+	common.RecordSyncDeleted(kt, enumor.VpcCloudResType, len(delCloudIDs))
+
+	if err = common.ConfirmSyncDeletion(kt, cli.dbCli, guardOpt, delCloudIDs); err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -234,6 +256,7 @@ func (cli *client) updateVpc(kt *kit.Kit, accountID string, updateMap map[string
 
 	logs.Infof("[%s] sync vpc to update vpc success, accountID: %s, count: %d, rid: %s", enumor.Gcp,
 		accountID, len(updateMap), kt.Rid)
+	common.RecordSyncUpdated(kt, enumor.VpcCloudResType, len(updateMap))
 
 	return nil
 }
@@ -277,6 +300,7 @@ func (cli *client) createVpc(kt *kit.Kit, accountID string, addVpc []types.GcpVp
 
 	logs.Infof("[%s] sync vpc to create vpc success, accountID: %s, count: %d, rid: %s", enumor.Gcp,
 		accountID, len(addVpc), kt.Rid)
+	common.RecordSyncCreated(kt, enumor.VpcCloudResType, len(addVpc))
 
 	return nil
 }
@@ -292,6 +316,7 @@ func (cli *client) listVpcFromCloud(kt *kit.Kit, params *SyncBaseParams) ([]type
 			PageSize: adcore.GcpQueryLimit,
 		},
 	}
+	common.RecordCloudApiCall(kt)
 	result, err := cli.cloudCli.ListVpc(kt, opt)
 	if err != nil {
 		logs.Errorf("[%s] list vpc from cloud failed, err: %v, account: %s, opt: %v, rid: %s", enumor.Gcp, err,
@@ -313,6 +338,7 @@ func (cli *client) listVpcFromCloudBySelfLink(kt *kit.Kit, params *ListBySelfLin
 			PageSize: adcore.GcpQueryLimit,
 		},
 	}
+	common.RecordCloudApiCall(kt)
 	result, err := cli.cloudCli.ListVpc(kt, opt)
 	if err != nil {
 		logs.Errorf("[%s] list vpc from cloud by self link failed, err: %v, account: %s, opt: %v, rid: %s",
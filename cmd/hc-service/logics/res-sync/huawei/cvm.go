@@ -31,6 +31,7 @@ import (
 	typescvm "hcm/pkg/adaptor/types/cvm"
 	networkinterface "hcm/pkg/adaptor/types/network-interface"
 	"hcm/pkg/api/core"
+	corecloud "hcm/pkg/api/core/cloud"
 	"hcm/pkg/api/core/cloud/cvm"
 	corecvm "hcm/pkg/api/core/cloud/cvm"
 	dataproto "hcm/pkg/api/data-service/cloud"
@@ -65,6 +66,8 @@ func (cli *client) Cvm(kt *kit.Kit, params *SyncBaseParams, opt *SyncCvmOption)
 		return nil, errf.NewFromErr(errf.InvalidParameter, err)
 	}
 
+	kt, stat := common.ForkSyncStat(kt)
+
 	cvmFromCloud, err := cli.listCvmFromCloud(kt, params)
 	if err != nil {
 		return nil, err
@@ -76,11 +79,11 @@ func (cli *client) Cvm(kt *kit.Kit, params *SyncBaseParams, opt *SyncCvmOption)
 	}
 
 	if len(cvmFromCloud) == 0 && len(cvmFromDB) == 0 {
-		return new(SyncResult), nil
+		return &SyncResult{Counts: stat.Counts()}, nil
 	}
 
 	addSlice, updateMap, delCloudIDs := common.Diff[typescvm.HuaWeiCvm, corecvm.Cvm[cvm.HuaWeiCvmExtension]](
-		cvmFromCloud, cvmFromDB, cli.isCvmChange)
+		kt, enumor.CvmCloudResType, cvmFromCloud, cvmFromDB, cli.isCvmChange)
 
 	if len(delCloudIDs) > 0 {
 		if err := cli.deleteCvm(kt, params.AccountID, params.Region, delCloudIDs); err != nil {
@@ -100,7 +103,11 @@ func (cli *client) Cvm(kt *kit.Kit, params *SyncBaseParams, opt *SyncCvmOption)
 		}
 	}
 
-	return new(SyncResult), nil
+	if err = cli.syncCvmTag(kt, params.AccountID, cvmFromCloud); err != nil {
+		return nil, err
+	}
+
+	return &SyncResult{Counts: stat.Counts()}, nil
 }
 
 func (cli *client) updateCvm(kt *kit.Kit, accountID string, region string,
@@ -254,6 +261,7 @@ func (cli *client) updateCvm(kt *kit.Kit, accountID string, region string,
 
 	logs.Infof("[%s] sync cvm to update cvm success, accountID: %s, count: %d, rid: %s", enumor.HuaWei,
 		accountID, len(updateMap), kt.Rid)
+	common.RecordSyncUpdated(kt, enumor.CvmCloudResType, len(updateMap))
 
 	return nil
 }
@@ -418,6 +426,7 @@ func (cli *client) createCvm(kt *kit.Kit, accountID string, region string,
 
 	logs.Infof("[%s] sync cvm to create cvm success, accountID: %s, count: %d, rid: %s", enumor.HuaWei,
 		accountID, len(addSlice), kt.Rid)
+	common.RecordSyncCreated(kt, enumor.CvmCloudResType, len(addSlice))
 
 	return nil
 }
@@ -509,6 +518,7 @@ func (cli *client) getSubnets(kt *kit.Kit, accountID, region, serverID string,
 		Region:   region,
 		ServerID: serverID,
 	}
+	common.RecordCloudApiCall(kt)
 	netInterDatas, err := cli.cloudCli.ListNetworkInterface(kt, opt)
 	if err != nil {
 		logs.Errorf("[%s] request adaptor to list huawei network interface failed, err: %v, rid: %s", enumor.HuaWei,
@@ -569,6 +579,23 @@ func (cli *client) deleteCvm(kt *kit.Kit, accountID string, region string, delCl
 		return fmt.Errorf("validate cvm not exist failed, before delete")
 	}
 
+	guardOpt := &common.SyncDeletionGuardOption{
+		Vendor:      enumor.HuaWei,
+		ResType:     enumor.CvmCloudResType,
+		AccountID:   accountID,
+		Region:      region,
+		RegionField: "region",
+		CloudIDs:    delCloudIDs,
+	}
+	delCloudIDs, err = common.GuardSyncDeletion(kt, cli.dbCli, guardOpt)
+	if err != nil {
+		return err
+	}
+
+	if len(delCloudIDs) == 0 {
+		return nil
+	}
+
 	deleteReq := &dataproto.CvmBatchDeleteReq{
 		Filter: tools.ContainersExpression("cloud_id", delCloudIDs),
 	}
@@ -580,6 +607,11 @@ func (cli *client) deleteCvm(kt *kit.Kit, accountID string, region string, delCl
 
 	logs.Infof("[%s] sync cvm to delete cvm success, accountID: %s, count: %d, rid: %s", enumor.HuaWei,
 		accountID, len(delCloudIDs), kt.Rid)
+	common.RecordSyncDeleted(kt, enumor.CvmCloudResType, len(delCloudIDs))
+
+	if err = common.ConfirmSyncDeletion(kt, cli.dbCli, guardOpt, delCloudIDs); err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -597,6 +629,7 @@ func (cli *client) listCvmFromCloud(kt *kit.Kit, params *SyncBaseParams) ([]type
 			Limit:  int32(constant.CloudResourceSyncMaxLimit),
 		},
 	}
+	common.RecordCloudApiCall(kt)
 	result, err := cli.cloudCli.ListCvm(kt, opt)
 	if err != nil {
 		if strings.Contains(err.Error(), huawei.ErrDataNotFound) {
@@ -972,3 +1005,19 @@ func (cli *client) isCvmChange(cloud typescvm.HuaWeiCvm, db corecvm.Cvm[cvm.HuaW
 
 	return false
 }
+
+// syncCvmTag 使用云上主机标签覆盖db中的主机标签
+func (cli *client) syncCvmTag(kt *kit.Kit, accountID string, cvms []typescvm.HuaWeiCvm) error {
+	tagMap := make(map[string][]corecloud.TagPair, len(cvms))
+	for _, one := range cvms {
+		tags := make([]corecloud.TagPair, 0)
+		// 华为云服务器标签格式为 key=value
+		for _, tag := range converter.PtrToVal(one.Tags) {
+			key, value, _ := strings.Cut(tag, "=")
+			tags = append(tags, corecloud.TagPair{Key: key, Value: value})
+		}
+		tagMap[one.GetCloudID()] = tags
+	}
+
+	return common.SyncResourceTag(kt, cli.dbCli, enumor.HuaWei, accountID, enumor.CvmCloudResType, tagMap)
+}
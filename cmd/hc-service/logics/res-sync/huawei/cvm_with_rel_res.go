@@ -21,6 +21,7 @@ package huawei
 
 import (
 	"fmt"
+	"hcm/cmd/hc-service/logics/res-sync/common"
 
 	cvmrelmgr "hcm/cmd/hc-service/logics/res-sync/cvm-rel-manager"
 	typecvm "hcm/pkg/adaptor/types/cvm"
@@ -63,6 +64,8 @@ func (opt SyncCvmWithRelResOption) Validate() error {
 func (cli *client) CvmWithRelRes(kt *kit.Kit, params *SyncBaseParams, opt *SyncCvmWithRelResOption) (
 	*SyncResult, error) {
 
+	kt, stat := common.ForkSyncStat(kt)
+
 	cvmFromCloud, err := cli.listCvmFromCloud(kt, params)
 	if err != nil {
 		return nil, err
@@ -74,7 +77,7 @@ func (cli *client) CvmWithRelRes(kt *kit.Kit, params *SyncBaseParams, opt *SyncC
 			return nil, err
 		}
 
-		return new(SyncResult), nil
+		return &SyncResult{Counts: stat.Counts()}, nil
 	}
 
 	// step2: 获取cvm和关联资源的关联关系
@@ -243,7 +246,7 @@ func (cli *client) CvmWithRelRes(kt *kit.Kit, params *SyncBaseParams, opt *SyncC
 		return nil, err
 	}
 
-	return new(SyncResult), nil
+	return &SyncResult{Counts: stat.Counts()}, nil
 }
 
 func (cli *client) buildCvmRelManger(kt *kit.Kit, region string, cvmFromCloud []typecvm.HuaWeiCvm) (
@@ -358,6 +361,7 @@ func (cli *client) getEipMapFromCloudCvm(kt *kit.Kit, region string, cvmFromClou
 			Ips:    partIPs,
 			Limit:  converter.ValToPtr(int32(constant.CloudResourceSyncMaxLimit)),
 		}
+		common.RecordCloudApiCall(kt)
 		resp, err := cli.cloudCli.ListEip(kt, opt)
 		if err != nil {
 			logs.Errorf("[%s] list eip by ip from cloud failed, err: %v, account: %s, opt: %v, rid: %s",
@@ -26,6 +26,7 @@ import (
 	adcore "hcm/pkg/adaptor/types/core"
 	adaptordisk "hcm/pkg/adaptor/types/disk"
 	"hcm/pkg/api/core"
+	corecloud "hcm/pkg/api/core/cloud"
 	coredisk "hcm/pkg/api/core/cloud/disk"
 	"hcm/pkg/api/data-service/cloud/disk"
 	"hcm/pkg/criteria/constant"
@@ -57,6 +58,8 @@ func (cli *client) Disk(kt *kit.Kit, params *SyncBaseParams, opt *SyncDiskOption
 		return nil, errf.NewFromErr(errf.InvalidParameter, err)
 	}
 
+	kt, stat := common.ForkSyncStat(kt)
+
 	diskFromCloud, err := cli.listDiskFromCloud(kt, params)
 	if err != nil {
 		return nil, err
@@ -68,7 +71,7 @@ func (cli *client) Disk(kt *kit.Kit, params *SyncBaseParams, opt *SyncDiskOption
 	}
 
 	if len(diskFromCloud) == 0 && len(diskFromDB) == 0 {
-		return new(SyncResult), nil
+		return &SyncResult{Counts: stat.Counts()}, nil
 	}
 
 	if opt.BootMap != nil {
@@ -79,7 +82,7 @@ func (cli *client) Disk(kt *kit.Kit, params *SyncBaseParams, opt *SyncDiskOption
 	}
 
 	addSlice, updateMap, delCloudIDs := common.Diff[adaptordisk.HuaWeiDisk, *coredisk.Disk[coredisk.HuaWeiExtension]](
-		diskFromCloud, diskFromDB, isDiskChange)
+		kt, enumor.DiskCloudResType, diskFromCloud, diskFromDB, isDiskChange)
 
 	if len(delCloudIDs) > 0 {
 		if err := cli.deleteDisk(kt, params.AccountID, params.Region, delCloudIDs); err != nil {
@@ -99,7 +102,11 @@ func (cli *client) Disk(kt *kit.Kit, params *SyncBaseParams, opt *SyncDiskOption
 		}
 	}
 
-	return new(SyncResult), nil
+	if err = cli.syncDiskTag(kt, params.AccountID, diskFromCloud); err != nil {
+		return nil, err
+	}
+
+	return &SyncResult{Counts: stat.Counts()}, nil
 }
 
 func (cli *client) deleteDisk(kt *kit.Kit, accountID string, region string, delCloudIDs []string) error {
@@ -123,6 +130,23 @@ func (cli *client) deleteDisk(kt *kit.Kit, accountID string, region string, delC
 		return fmt.Errorf("validate disk not exist failed, before delete")
 	}
 
+	guardOpt := &common.SyncDeletionGuardOption{
+		Vendor:      enumor.HuaWei,
+		ResType:     enumor.DiskCloudResType,
+		AccountID:   accountID,
+		Region:      region,
+		RegionField: "region",
+		CloudIDs:    delCloudIDs,
+	}
+	delCloudIDs, err = common.GuardSyncDeletion(kt, cli.dbCli, guardOpt)
+	if err != nil {
+		return err
+	}
+
+	if len(delCloudIDs) == 0 {
+		return nil
+	}
+
 	deleteReq := &disk.DiskDeleteReq{
 		Filter: tools.ContainersExpression("cloud_id", delCloudIDs),
 	}
@@ -134,6 +158,11 @@ func (cli *client) deleteDisk(kt *kit.Kit, accountID string, region string, delC
 
 	logs.Infof("[%s] sync disk to delete disk success, accountID: %s, count: %d, rid: %s", enumor.HuaWei,
 		accountID, len(delCloudIDs), kt.Rid)
+	common.RecordSyncDeleted(kt, enumor.DiskCloudResType, len(delCloudIDs))
+
+	if err = common.ConfirmSyncDeletion(kt, cli.dbCli, guardOpt, delCloudIDs); err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -194,6 +223,7 @@ func (cli *client) updateDisk(kt *kit.Kit, accountID string, updateMap map[strin
 
 	logs.Infof("[%s] sync disk to update disk success, accountID: %s, count: %d, rid: %s", enumor.HuaWei,
 		accountID, len(updateMap), kt.Rid)
+	common.RecordSyncUpdated(kt, enumor.DiskCloudResType, len(updateMap))
 
 	return nil
 }
@@ -257,6 +287,7 @@ func (cli *client) createDisk(kt *kit.Kit, accountID string, region string,
 
 	logs.Infof("[%s] sync disk to create disk success, accountID: %s, count: %d, rid: %s", enumor.HuaWei,
 		accountID, len(addSlice), kt.Rid)
+	common.RecordSyncCreated(kt, enumor.DiskCloudResType, len(addSlice))
 
 	return nil
 }
@@ -277,6 +308,7 @@ func (cli *client) listDiskFromCloud(kt *kit.Kit, params *SyncBaseParams) ([]ada
 				Limit: converter.ValToPtr(int32(adcore.HuaWeiQueryLimit)),
 			},
 		}
+		common.RecordCloudApiCall(kt)
 		result, err := cli.cloudCli.ListDisk(kt, opt)
 		if err != nil {
 			logs.Errorf("[%s] list disk from cloud failed, err: %v, account: %s, opt: %v, rid: %s", enumor.HuaWei,
@@ -447,3 +479,17 @@ func isDiskChange(cloud adaptordisk.HuaWeiDisk, db *coredisk.Disk[coredisk.HuaWe
 
 	return false
 }
+
+// syncDiskTag 使用云上硬盘标签覆盖db中的硬盘标签
+func (cli *client) syncDiskTag(kt *kit.Kit, accountID string, diskFromCloud []adaptordisk.HuaWeiDisk) error {
+	tagMap := make(map[string][]corecloud.TagPair, len(diskFromCloud))
+	for _, one := range diskFromCloud {
+		tags := make([]corecloud.TagPair, 0, len(one.Tags))
+		for key, value := range one.Tags {
+			tags = append(tags, corecloud.TagPair{Key: key, Value: value})
+		}
+		tagMap[one.GetCloudID()] = tags
+	}
+
+	return common.SyncResourceTag(kt, cli.dbCli, enumor.HuaWei, accountID, enumor.DiskCloudResType, tagMap)
+}
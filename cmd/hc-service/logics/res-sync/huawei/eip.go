@@ -25,6 +25,7 @@ import (
 	"hcm/cmd/hc-service/logics/res-sync/common"
 	adcore "hcm/pkg/adaptor/types/core"
 	typeseip "hcm/pkg/adaptor/types/eip"
+	typetag "hcm/pkg/adaptor/types/tag"
 	"hcm/pkg/api/core"
 	dataeip "hcm/pkg/api/data-service/cloud/eip"
 	"hcm/pkg/criteria/constant"
@@ -56,6 +57,8 @@ func (cli *client) Eip(kt *kit.Kit, params *SyncBaseParams, opt *SyncEipOption)
 		return nil, errf.NewFromErr(errf.InvalidParameter, err)
 	}
 
+	kt, stat := common.ForkSyncStat(kt)
+
 	eipFromCloud, err := cli.listEipFromCloud(kt, params)
 	if err != nil {
 		return nil, err
@@ -67,11 +70,12 @@ func (cli *client) Eip(kt *kit.Kit, params *SyncBaseParams, opt *SyncEipOption)
 	}
 
 	if len(eipFromCloud) == 0 && len(eipFromDB) == 0 {
-		return new(SyncResult), nil
+		return &SyncResult{Counts: stat.Counts()}, nil
 	}
 
 	addEip, updateMap, delCloudIDs := common.Diff[*typeseip.HuaWeiEip,
-		*dataeip.EipExtResult[dataeip.HuaWeiEipExtensionResult]](eipFromCloud, eipFromDB, isEipChange)
+		*dataeip.EipExtResult[dataeip.HuaWeiEipExtensionResult]](
+		kt, enumor.EipCloudResType, eipFromCloud, eipFromDB, isEipChange)
 
 	if len(delCloudIDs) > 0 {
 		if err = cli.deleteEip(kt, params.AccountID, params.Region, delCloudIDs); err != nil {
@@ -91,7 +95,11 @@ func (cli *client) Eip(kt *kit.Kit, params *SyncBaseParams, opt *SyncEipOption)
 		}
 	}
 
-	return new(SyncResult), nil
+	if err = cli.syncEipTag(kt, params, eipFromCloud); err != nil {
+		return nil, err
+	}
+
+	return &SyncResult{Counts: stat.Counts()}, nil
 }
 
 // RemoveEipDeleteFromCloud ...
@@ -185,6 +193,23 @@ func (cli *client) deleteEip(kt *kit.Kit, accountID string, region string, delCl
 		return fmt.Errorf("validate eip not exist failed, before delete")
 	}
 
+	guardOpt := &common.SyncDeletionGuardOption{
+		Vendor:      enumor.HuaWei,
+		ResType:     enumor.EipCloudResType,
+		AccountID:   accountID,
+		Region:      region,
+		RegionField: "region",
+		CloudIDs:    delCloudIDs,
+	}
+	delCloudIDs, err = common.GuardSyncDeletion(kt, cli.dbCli, guardOpt)
+	if err != nil {
+		return err
+	}
+
+	if len(delCloudIDs) == 0 {
+		return nil
+	}
+
 	deleteReq := &dataeip.EipDeleteReq{
 		Filter: tools.ContainersExpression("cloud_id", delCloudIDs),
 	}
@@ -195,6 +220,11 @@ func (cli *client) deleteEip(kt *kit.Kit, accountID string, region string, delCl
 
 	logs.Infof("[%s] sync eip to delete eip success, accountID: %s, count: %d, rid: %s", enumor.HuaWei,
 		accountID, len(delCloudIDs), kt.Rid)
+	common.RecordSyncDeleted(kt, enumor.EipCloudResType, len(delCloudIDs))
+
+	if err = common.ConfirmSyncDeletion(kt, cli.dbCli, guardOpt, delCloudIDs); err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -233,6 +263,7 @@ func (cli *client) updateEip(kt *kit.Kit, accountID string, updateMap map[string
 
 	logs.Infof("[%s] sync eip to update eip success, accountID: %s, count: %d, rid: %s", enumor.HuaWei,
 		accountID, len(updateMap), kt.Rid)
+	common.RecordSyncUpdated(kt, enumor.EipCloudResType, len(updateMap))
 
 	return nil
 }
@@ -275,6 +306,7 @@ func (cli *client) createEip(kt *kit.Kit, accountID string, addEip []*typeseip.H
 
 	logs.Infof("[%s] sync eip to create eip success, accountID: %s, count: %d, rid: %s", enumor.HuaWei,
 		accountID, len(addEip), kt.Rid)
+	common.RecordSyncCreated(kt, enumor.EipCloudResType, len(addEip))
 
 	return nil
 }
@@ -289,6 +321,7 @@ func (cli *client) listEipFromCloud(kt *kit.Kit, params *SyncBaseParams) ([]*typ
 		CloudIDs: params.CloudIDs,
 		Limit:    converter.ValToPtr(int32(adcore.HuaWeiQueryLimit)),
 	}
+	common.RecordCloudApiCall(kt)
 	result, err := cli.cloudCli.ListEip(kt, opt)
 	if err != nil {
 		logs.Errorf("[%s] list eip from cloud failed, err: %v, account: %s, opt: %v, rid: %s", enumor.HuaWei, err,
@@ -383,3 +416,30 @@ func isEipChange(cloud *typeseip.HuaWeiEip, db *dataeip.EipExtResult[dataeip.Hua
 
 	return false
 }
+
+// syncEipTag 使用云上eip标签覆盖db中的eip标签，华为云eip列表接口不返回标签，需要单独查询
+func (cli *client) syncEipTag(kt *kit.Kit, params *SyncBaseParams, eipFromCloud []*typeseip.HuaWeiEip) error {
+	if len(eipFromCloud) == 0 {
+		return nil
+	}
+
+	cloudIDs := make([]string, 0, len(eipFromCloud))
+	for _, one := range eipFromCloud {
+		cloudIDs = append(cloudIDs, one.GetCloudID())
+	}
+
+	opt := &typetag.ListTagOption{
+		ResType:  enumor.EipCloudResType,
+		Region:   params.Region,
+		CloudIDs: cloudIDs,
+	}
+	common.RecordCloudApiCall(kt)
+	tagMap, err := cli.cloudCli.ListResourceTag(kt, opt)
+	if err != nil {
+		logs.Errorf("[%s] list eip tag from cloud failed, err: %v, account: %s, opt: %v, rid: %s", enumor.HuaWei,
+			err, params.AccountID, opt, kt.Rid)
+		return err
+	}
+
+	return common.SyncResourceTag(kt, cli.dbCli, enumor.HuaWei, params.AccountID, enumor.EipCloudResType, tagMap)
+}
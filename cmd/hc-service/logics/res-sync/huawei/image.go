@@ -56,6 +56,8 @@ func (cli *client) Image(kt *kit.Kit, params *SyncBaseParams, opt *SyncImageOpti
 		return nil, errf.NewFromErr(errf.InvalidParameter, err)
 	}
 
+	kt, stat := common.ForkSyncStat(kt)
+
 	imageFromCloud, err := cli.listImageFromCloud(kt, params, opt.Platform)
 	if err != nil {
 		return nil, err
@@ -67,11 +69,11 @@ func (cli *client) Image(kt *kit.Kit, params *SyncBaseParams, opt *SyncImageOpti
 	}
 
 	if len(imageFromCloud) == 0 && len(imageFromDB) == 0 {
-		return new(SyncResult), nil
+		return &SyncResult{Counts: stat.Counts()}, nil
 	}
 
 	addSlice, updateMap, delCloudIDs := common.Diff[typesimage.HuaWeiImage, coreimage.Image[coreimage.HuaWeiExtension]](
-		imageFromCloud, imageFromDB, isImageChange)
+		kt, enumor.ImageCloudResType, imageFromCloud, imageFromDB, isImageChange)
 
 	if len(delCloudIDs) > 0 {
 		if err := cli.deleteImage(kt, params.AccountID, params.Region, delCloudIDs, opt.Platform); err != nil {
@@ -91,7 +93,7 @@ func (cli *client) Image(kt *kit.Kit, params *SyncBaseParams, opt *SyncImageOpti
 		}
 	}
 
-	return new(SyncResult), nil
+	return &SyncResult{Counts: stat.Counts()}, nil
 }
 
 func (cli *client) updateImage(kt *kit.Kit, accountID string, region string,
@@ -121,6 +123,7 @@ func (cli *client) updateImage(kt *kit.Kit, accountID string, region string,
 
 	logs.Infof("[%s] sync image to update image success, accountID: %s, count: %d, rid: %s", enumor.HuaWei,
 		accountID, len(updateMap), kt.Rid)
+	common.RecordSyncUpdated(kt, enumor.ImageCloudResType, len(updateMap))
 
 	return nil
 }
@@ -160,6 +163,7 @@ func (cli *client) createImage(kt *kit.Kit, accountID string, region string,
 
 	logs.Infof("[%s] sync image to create image success, accountID: %s, count: %d, rid: %s", enumor.HuaWei,
 		accountID, len(addSlice), kt.Rid)
+	common.RecordSyncCreated(kt, enumor.ImageCloudResType, len(addSlice))
 
 	return nil
 }
@@ -171,6 +175,10 @@ func (cli *client) deleteImage(kt *kit.Kit, accountID string, region string, del
 		return fmt.Errorf("image delCloudIDs is <= 0, not delete")
 	}
 
+	if common.RecordDryRunDelete(kt, enumor.ImageCloudResType, delCloudIDs) {
+		return nil
+	}
+
 	checkParams := &SyncBaseParams{
 		AccountID: accountID,
 		Region:    region,
@@ -197,6 +205,7 @@ func (cli *client) deleteImage(kt *kit.Kit, accountID string, region string, del
 
 	logs.Infof("[%s] sync image to delete image success, accountID: %s, count: %d, rid: %s", enumor.HuaWei,
 		accountID, len(delCloudIDs), kt.Rid)
+	common.RecordSyncDeleted(kt, enumor.ImageCloudResType, len(delCloudIDs))
 
 	return nil
 }
@@ -216,6 +225,7 @@ func (cli *client) listImageFromCloud(kt *kit.Kit, params *SyncBaseParams,
 			Platform: platform,
 			CloudID:  id,
 		}
+		common.RecordCloudApiCall(kt)
 		image, err := cli.cloudCli.ListImage(kt, opt)
 		if err != nil {
 			logs.Errorf("[%s] list image from cloud failed, err: %v, account: %s, opt: %v, rid: %s", enumor.HuaWei,
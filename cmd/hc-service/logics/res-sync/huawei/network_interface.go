@@ -23,6 +23,7 @@ import (
 	"fmt"
 
 	"hcm/cmd/hc-service/logics/res-sync/common"
+	relmgr "hcm/cmd/hc-service/logics/res-sync/rel-manager"
 	typesni "hcm/pkg/adaptor/types/network-interface"
 	"hcm/pkg/api/core"
 	"hcm/pkg/api/core/cloud"
@@ -61,6 +62,8 @@ func (cli *client) NetworkInterface(kt *kit.Kit, params *SyncBaseParams, opt *Sy
 		return nil, errf.NewFromErr(errf.InvalidParameter, err)
 	}
 
+	kt, stat := common.ForkSyncStat(kt)
+
 	err := concurrence.BaseExec(constant.SyncConcurrencyDefaultMaxLimit, params.CloudIDs, func(param string) error {
 		syncOpt := &syncNIOption{
 			AccountID:  params.AccountID,
@@ -78,7 +81,7 @@ func (cli *client) NetworkInterface(kt *kit.Kit, params *SyncBaseParams, opt *Sy
 		return nil, err
 	}
 
-	return new(SyncResult), nil
+	return &SyncResult{Counts: stat.Counts()}, nil
 }
 
 type syncNIOption struct {
@@ -112,7 +115,8 @@ func (cli *client) syncNetworkInterface(kt *kit.Kit, opt *syncNIOption) (*SyncRe
 	}
 
 	addSlice, updateMap, delCloudIDs := common.Diff[typesni.HuaWeiNI, coreni.
-		NetworkInterface[coreni.HuaWeiNIExtension]](networkInterfaceFromCloud, networkInterfaceFromDB, isNIChange)
+		NetworkInterface[coreni.HuaWeiNIExtension]](
+		kt, enumor.NetworkInterfaceCloudResType, networkInterfaceFromCloud, networkInterfaceFromDB, isNIChange)
 
 	if len(delCloudIDs) > 0 {
 		if err = cli.deleteNetworkInterface(kt, delCloudIDs, opt); err != nil {
@@ -132,6 +136,12 @@ func (cli *client) syncNetworkInterface(kt *kit.Kit, opt *syncNIOption) (*SyncRe
 		}
 	}
 
+	// 以云上网络接口绑定的安全组为准，对账网络接口和安全组的关联关系
+	if err = relmgr.ReconcileFromCloud(kt, cli.dbCli, enumor.HuaWei, relmgr.NetworkInterfaceSecurityGroupRel, networkInterfaceFromCloud); err != nil {
+		logs.Errorf("[%s] reconcile network interface security group rel failed, err: %v, rid: %s", enumor.HuaWei, err, kt.Rid)
+		return nil, err
+	}
+
 	return nil, nil
 }
 
@@ -159,6 +169,29 @@ func (cli *client) deleteNetworkInterface(kt *kit.Kit, delCloudIDs []string, opt
 		}
 	}
 
+	guardOpt := &common.SyncDeletionGuardOption{
+		Vendor:      enumor.HuaWei,
+		ResType:     enumor.NetworkInterfaceCloudResType,
+		AccountID:   opt.AccountID,
+		Region:      opt.Region,
+		RegionField: "region",
+		CloudIDs:    delCloudIDs,
+	}
+	delCloudIDs, err = common.GuardSyncDeletion(kt, cli.dbCli, guardOpt)
+	if err != nil {
+		return err
+	}
+
+	if len(delCloudIDs) == 0 {
+		return nil
+	}
+
+	// 删除网络接口前清理网络接口和安全组的关联关系
+	if err = relmgr.Remove(kt, cli.dbCli, enumor.HuaWei, relmgr.NetworkInterfaceSecurityGroupRel, delCloudIDs); err != nil {
+		logs.Errorf("[%s] remove network interface security group rel failed, err: %v, rid: %s", enumor.HuaWei, err, kt.Rid)
+		return err
+	}
+
 	deleteReq := &dataproto.BatchDeleteReq{
 		Filter: tools.ContainersExpression("cloud_id", delCloudIDs),
 	}
@@ -171,6 +204,10 @@ func (cli *client) deleteNetworkInterface(kt *kit.Kit, delCloudIDs []string, opt
 	logs.V(3).Infof("[%s] sync network interface to delete ni success, accountID: %s, count: %d, rid: %s",
 		enumor.HuaWei, opt.AccountID, len(delCloudIDs), kt.Rid)
 
+	if err = common.ConfirmSyncDeletion(kt, cli.dbCli, guardOpt, delCloudIDs); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -451,6 +488,7 @@ func (cli *client) listNetworkInterfaceFromCloud(kt *kit.Kit, region, cloudCvmID
 		ServerID: cloudCvmID,
 		Region:   region,
 	}
+	common.RecordCloudApiCall(kt)
 	result, err := cli.cloudCli.ListNetworkInterface(kt, listOpt)
 	if err != nil {
 		logs.Errorf("[%s] list networkInterface from cloud failed, err: %v, rid: %s", enumor.HuaWei, err, kt.Rid)
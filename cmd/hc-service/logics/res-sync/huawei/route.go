@@ -55,6 +55,8 @@ func (cli *client) Route(kt *kit.Kit, params *SyncBaseParams, opt *SyncRouteOpti
 		return nil, errf.NewFromErr(errf.InvalidParameter, err)
 	}
 
+	kt, stat := common.ForkSyncStat(kt)
+
 	err := concurrence.BaseExec(constant.SyncConcurrencyDefaultMaxLimit, params.CloudIDs, func(param string) error {
 		syncOpt := &syncRouteOption{
 			AccountID:         params.AccountID,
@@ -73,7 +75,7 @@ func (cli *client) Route(kt *kit.Kit, params *SyncBaseParams, opt *SyncRouteOpti
 		return nil, err
 	}
 
-	return new(SyncResult), nil
+	return &SyncResult{Counts: stat.Counts()}, nil
 }
 
 type syncRouteOption struct {
@@ -122,7 +124,7 @@ func (cli *client) route(kt *kit.Kit, opt *syncRouteOption) (*SyncResult, error)
 	}
 
 	addSlice, updateMap, delCloudIDs := common.Diff[typesroutetable.HuaWeiRoute,
-		routetable.HuaWeiRoute](routeFromCloud, routeFromDB, isRouteChange)
+		routetable.HuaWeiRoute](kt, enumor.RouteCloudResType, routeFromCloud, routeFromDB, isRouteChange)
 
 	if len(delCloudIDs) > 0 {
 		if err = cli.deleteRoute(kt, opt.AccountID, opt.Region, opt.CloudRouteTableID, routeTable.ID, delCloudIDs,
@@ -180,6 +182,7 @@ func (cli *client) createRoute(kt *kit.Kit, accountID string, region string, rou
 
 	logs.Infof("[%s] sync route to create route success, accountID: %s, count: %d, rid: %s", enumor.HuaWei,
 		accountID, len(addSlice), kt.Rid)
+	common.RecordSyncCreated(kt, enumor.RouteCloudResType, len(addSlice))
 
 	return nil
 }
@@ -215,6 +218,7 @@ func (cli *client) updateRoute(kt *kit.Kit, accountID, region, routeTableID stri
 	}
 	logs.Infof("[%s] sync route to update route success, accountID: %s, count: %d, rid: %s", enumor.HuaWei,
 		accountID, len(updateMap), kt.Rid)
+	common.RecordSyncUpdated(kt, enumor.RouteCloudResType, len(updateMap))
 
 	return nil
 }
@@ -226,6 +230,10 @@ func (cli *client) deleteRoute(kt *kit.Kit, accountID, region, cloudRTID, rtID s
 		return fmt.Errorf("route delCloudIDs is <= 0, not delete")
 	}
 
+	if common.RecordDryRunDelete(kt, enumor.RouteCloudResType, delCloudIDs) {
+		return nil
+	}
+
 	checkParams := &syncRouteOption{
 		AccountID:         accountID,
 		Region:            region,
@@ -263,6 +271,7 @@ func (cli *client) deleteRoute(kt *kit.Kit, accountID, region, cloudRTID, rtID s
 
 	logs.Infof("[%s] sync route to delete route success, accountID: %s, count: %d, rid: %s", enumor.HuaWei,
 		accountID, len(delCloudIDs), kt.Rid)
+	common.RecordSyncDeleted(kt, enumor.RouteCloudResType, len(delCloudIDs))
 
 	return nil
 }
@@ -277,6 +286,7 @@ func (cli *client) listRouteFromCloud(kt *kit.Kit, opt *syncRouteOption) ([]type
 		Region: opt.Region,
 		ID:     opt.CloudRouteTableID,
 	}
+	common.RecordCloudApiCall(kt)
 	routeTable, err := cli.cloudCli.GetRouteTable(kt, routeOpt)
 	if err != nil {
 		logs.Errorf("[%s] list routeTable from cloud failed, err: %v, account: %s, opt: %v, rid: %s", enumor.HuaWei, err,
@@ -54,6 +54,8 @@ func (cli *client) SecurityGroup(kt *kit.Kit, params *SyncBaseParams, opt *SyncS
 		return nil, errf.NewFromErr(errf.InvalidParameter, err)
 	}
 
+	kt, stat := common.ForkSyncStat(kt)
+
 	sgFromCloud, err := cli.listSGFromCloud(kt, params)
 	if err != nil {
 		return nil, err
@@ -65,11 +67,12 @@ func (cli *client) SecurityGroup(kt *kit.Kit, params *SyncBaseParams, opt *SyncS
 	}
 
 	if len(sgFromCloud) == 0 && len(sgFromDB) == 0 {
-		return new(SyncResult), nil
+		return &SyncResult{Counts: stat.Counts()}, nil
 	}
 
 	addSlice, updateMap, delCloudIDs := common.Diff[securitygroup.HuaWeiSG,
-		cloudcore.SecurityGroup[cloudcore.HuaWeiSecurityGroupExtension]](sgFromCloud, sgFromDB, isSGChange)
+		cloudcore.SecurityGroup[cloudcore.HuaWeiSecurityGroupExtension]](
+		kt, enumor.SecurityGroupCloudResType, sgFromCloud, sgFromDB, isSGChange)
 
 	if len(delCloudIDs) > 0 {
 		if err := cli.deleteSG(kt, params.AccountID, params.Region, delCloudIDs); err != nil {
@@ -115,7 +118,7 @@ func (cli *client) SecurityGroup(kt *kit.Kit, params *SyncBaseParams, opt *SyncS
 		return nil, err
 	}
 
-	return new(SyncResult), nil
+	return &SyncResult{Counts: stat.Counts()}, nil
 }
 
 func (cli *client) updateSG(kt *kit.Kit, accountID string,
@@ -153,6 +156,7 @@ func (cli *client) updateSG(kt *kit.Kit, accountID string,
 
 	logs.Infof("[%s] sync sg to update sg success, accountID: %s, count: %d, rid: %s", enumor.HuaWei,
 		accountID, len(updateMap), kt.Rid)
+	common.RecordSyncUpdated(kt, enumor.SecurityGroupCloudResType, len(updateMap))
 
 	return nil
 }
@@ -193,6 +197,7 @@ func (cli *client) createSG(kt *kit.Kit, accountID string, region string,
 
 	logs.Infof("[%s] sync sg to create sg success, accountID: %s, count: %d, rid: %s", enumor.HuaWei,
 		accountID, len(addSlice), kt.Rid)
+	common.RecordSyncCreated(kt, enumor.SecurityGroupCloudResType, len(addSlice))
 
 	return results.IDs, nil
 }
@@ -218,6 +223,23 @@ func (cli *client) deleteSG(kt *kit.Kit, accountID string, region string, delClo
 		return fmt.Errorf("validate sg not exist failed, before delete")
 	}
 
+	guardOpt := &common.SyncDeletionGuardOption{
+		Vendor:      enumor.HuaWei,
+		ResType:     enumor.SecurityGroupCloudResType,
+		AccountID:   accountID,
+		Region:      region,
+		RegionField: "region",
+		CloudIDs:    delCloudIDs,
+	}
+	delCloudIDs, err = common.GuardSyncDeletion(kt, cli.dbCli, guardOpt)
+	if err != nil {
+		return err
+	}
+
+	if len(delCloudIDs) == 0 {
+		return nil
+	}
+
 	deleteReq := &protocloud.SecurityGroupBatchDeleteReq{
 		Filter: tools.ContainersExpression("cloud_id", delCloudIDs),
 	}
@@ -229,6 +251,11 @@ func (cli *client) deleteSG(kt *kit.Kit, accountID string, region string, delClo
 
 	logs.Infof("[%s] sync sg to delete sg success, accountID: %s, count: %d, rid: %s", enumor.HuaWei,
 		accountID, len(delCloudIDs), kt.Rid)
+	common.RecordSyncDeleted(kt, enumor.SecurityGroupCloudResType, len(delCloudIDs))
+
+	if err = common.ConfirmSyncDeletion(kt, cli.dbCli, guardOpt, delCloudIDs); err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -245,6 +272,7 @@ func (cli *client) listSGFromCloud(kt *kit.Kit, params *SyncBaseParams) ([]secur
 			Limit: converter.ValToPtr(int32(adcore.HuaWeiQueryLimit)),
 		},
 	}
+	common.RecordCloudApiCall(kt)
 	result, _, err := cli.cloudCli.ListSecurityGroup(kt, opt)
 	if err != nil {
 		logs.Errorf("[%s] list sg from cloud failed, err: %v, account: %s, opt: %v, rid: %s", enumor.HuaWei,
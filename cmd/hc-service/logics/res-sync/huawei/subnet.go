@@ -24,6 +24,7 @@ import (
 
 	"hcm/cmd/hc-service/logics/res-sync/common"
 	"hcm/pkg/adaptor/types/subnet"
+	typetag "hcm/pkg/adaptor/types/tag"
 	"hcm/pkg/api/core"
 	cloudcore "hcm/pkg/api/core/cloud"
 	dataservice "hcm/pkg/api/data-service"
@@ -56,6 +57,8 @@ func (cli *client) Subnet(kt *kit.Kit, params *SyncBaseParams, opt *SyncSubnetOp
 		return nil, errf.NewFromErr(errf.InvalidParameter, err)
 	}
 
+	kt, stat := common.ForkSyncStat(kt)
+
 	subnetFromCloud, err := cli.listSubnetFromCloud(kt, params, opt.CloudVpcID)
 	if err != nil {
 		return nil, err
@@ -67,11 +70,12 @@ func (cli *client) Subnet(kt *kit.Kit, params *SyncBaseParams, opt *SyncSubnetOp
 	}
 
 	if len(subnetFromCloud) == 0 && len(subnetFromDB) == 0 {
-		return new(SyncResult), nil
+		return &SyncResult{Counts: stat.Counts()}, nil
 	}
 
 	addSubnet, updateMap, delCloudIDs := common.Diff[adtysubnet.HuaWeiSubnet,
-		cloudcore.Subnet[cloudcore.HuaWeiSubnetExtension]](subnetFromCloud, subnetFromDB, isHuaWeiSubnetChange)
+		cloudcore.Subnet[cloudcore.HuaWeiSubnetExtension]](
+		kt, enumor.SubnetCloudResType, subnetFromCloud, subnetFromDB, isHuaWeiSubnetChange)
 
 	if len(delCloudIDs) > 0 {
 		if err = cli.deleteSubnet(kt, params.AccountID, params.Region, opt.CloudVpcID, delCloudIDs); err != nil {
@@ -91,7 +95,11 @@ func (cli *client) Subnet(kt *kit.Kit, params *SyncBaseParams, opt *SyncSubnetOp
 		}
 	}
 
-	return new(SyncResult), nil
+	if err = cli.syncSubnetTag(kt, params, subnetFromCloud); err != nil {
+		return nil, err
+	}
+
+	return &SyncResult{Counts: stat.Counts()}, nil
 }
 
 // RemoveSubnetDeleteFromCloud ...
@@ -186,6 +194,23 @@ func (cli *client) deleteSubnet(kt *kit.Kit, accountID, region, cloudVpcID strin
 		return fmt.Errorf("validate subnet not exist failed, before delete")
 	}
 
+	guardOpt := &common.SyncDeletionGuardOption{
+		Vendor:      enumor.HuaWei,
+		ResType:     enumor.SubnetCloudResType,
+		AccountID:   accountID,
+		Region:      region,
+		RegionField: "region",
+		CloudIDs:    delCloudIDs,
+	}
+	delCloudIDs, err = common.GuardSyncDeletion(kt, cli.dbCli, guardOpt)
+	if err != nil {
+		return err
+	}
+
+	if len(delCloudIDs) == 0 {
+		return nil
+	}
+
 	deleteReq := &dataservice.BatchDeleteReq{
 		Filter: tools.ContainersExpression("cloud_id", delCloudIDs),
 	}
@@ -197,6 +222,11 @@ func (cli *client) deleteSubnet(kt *kit.Kit, accountID, region, cloudVpcID strin
 
 	logs.Infof("[%s] sync subnet to delete subnet success, accountID: %s, count: %d, rid: %s", enumor.HuaWei,
 		accountID, len(delCloudIDs), kt.Rid)
+	common.RecordSyncDeleted(kt, enumor.SubnetCloudResType, len(delCloudIDs))
+
+	if err = common.ConfirmSyncDeletion(kt, cli.dbCli, guardOpt, delCloudIDs); err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -240,6 +270,7 @@ func (cli *client) updateSubnet(kt *kit.Kit, accountID string, updateMap map[str
 
 	logs.Infof("[%s] sync subnet to update subnet success, accountID: %s, count: %d, rid: %s", enumor.HuaWei,
 		accountID, len(updateMap), kt.Rid)
+	common.RecordSyncUpdated(kt, enumor.SubnetCloudResType, len(updateMap))
 
 	return nil
 }
@@ -301,6 +332,7 @@ func (cli *client) createSubnet(kt *kit.Kit, accountID, region, cloudVpcID strin
 
 	logs.Infof("[%s] sync subnet to create subnet success, accountID: %s, count: %d, rid: %s", enumor.HuaWei,
 		accountID, len(addSubnet), kt.Rid)
+	common.RecordSyncCreated(kt, enumor.SubnetCloudResType, len(addSubnet))
 
 	return nil
 }
@@ -317,6 +349,7 @@ func (cli *client) listSubnetFromCloud(kt *kit.Kit, params *SyncBaseParams, clou
 		CloudIDs:   params.CloudIDs,
 		CloudVpcID: cloudVpcID,
 	}
+	common.RecordCloudApiCall(kt)
 	result, err := cli.cloudCli.ListSubnetByID(kt, opt)
 	if err != nil {
 		logs.Errorf("[%s] list subnet from cloud failed, err: %v, account: %s, opt: %v, rid: %s", enumor.HuaWei, err,
@@ -403,3 +436,30 @@ func isHuaWeiSubnetChange(item adtysubnet.HuaWeiSubnet, info cloudcore.Subnet[cl
 
 	return false
 }
+
+// syncSubnetTag 使用云上子网标签覆盖db中的子网标签，华为云子网列表接口不返回标签，需要单独查询
+func (cli *client) syncSubnetTag(kt *kit.Kit, params *SyncBaseParams, subnetFromCloud []adtysubnet.HuaWeiSubnet) error {
+	if len(subnetFromCloud) == 0 {
+		return nil
+	}
+
+	cloudIDs := make([]string, 0, len(subnetFromCloud))
+	for _, one := range subnetFromCloud {
+		cloudIDs = append(cloudIDs, one.GetCloudID())
+	}
+
+	opt := &typetag.ListTagOption{
+		ResType:  enumor.SubnetCloudResType,
+		Region:   params.Region,
+		CloudIDs: cloudIDs,
+	}
+	common.RecordCloudApiCall(kt)
+	tagMap, err := cli.cloudCli.ListResourceTag(kt, opt)
+	if err != nil {
+		logs.Errorf("[%s] list subnet tag from cloud failed, err: %v, account: %s, opt: %v, rid: %s", enumor.HuaWei,
+			err, params.AccountID, opt, kt.Rid)
+		return err
+	}
+
+	return common.SyncResourceTag(kt, cli.dbCli, enumor.HuaWei, params.AccountID, enumor.SubnetCloudResType, tagMap)
+}
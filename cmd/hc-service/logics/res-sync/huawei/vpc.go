@@ -56,6 +56,8 @@ func (cli *client) Vpc(kt *kit.Kit, params *SyncBaseParams, opt *SyncVpcOption)
 		return nil, errf.NewFromErr(errf.InvalidParameter, err)
 	}
 
+	kt, stat := common.ForkSyncStat(kt)
+
 	vpcFromCloud, err := cli.listVpcFromCloud(kt, params)
 	if err != nil {
 		return nil, err
@@ -67,11 +69,11 @@ func (cli *client) Vpc(kt *kit.Kit, params *SyncBaseParams, opt *SyncVpcOption)
 	}
 
 	if len(vpcFromCloud) == 0 && len(vpcFromDB) == 0 {
-		return new(SyncResult), nil
+		return &SyncResult{Counts: stat.Counts()}, nil
 	}
 
 	addVpc, updateMap, delCloudIDs := common.Diff[types.HuaWeiVpc, cloudcore.Vpc[cloudcore.HuaWeiVpcExtension]](
-		vpcFromCloud, vpcFromDB, isHuaWeiVpcChange)
+		kt, enumor.VpcCloudResType, vpcFromCloud, vpcFromDB, isHuaWeiVpcChange)
 
 	if len(delCloudIDs) > 0 {
 		if err = cli.deleteVpc(kt, params.AccountID, params.Region, delCloudIDs); err != nil {
@@ -91,7 +93,11 @@ func (cli *client) Vpc(kt *kit.Kit, params *SyncBaseParams, opt *SyncVpcOption)
 		}
 	}
 
-	return new(SyncResult), nil
+	if err = cli.syncVpcTag(kt, params.AccountID, vpcFromCloud); err != nil {
+		return nil, err
+	}
+
+	return &SyncResult{Counts: stat.Counts()}, nil
 }
 
 // RemoveVpcDeleteFromCloud ...
@@ -185,6 +191,23 @@ func (cli *client) deleteVpc(kt *kit.Kit, accountID string, region string, delCl
 		return fmt.Errorf("validate vpc not exist failed, before delete")
 	}
 
+	guardOpt := &common.SyncDeletionGuardOption{
+		Vendor:      enumor.HuaWei,
+		ResType:     enumor.VpcCloudResType,
+		AccountID:   accountID,
+		Region:      region,
+		RegionField: "region",
+		CloudIDs:    delCloudIDs,
+	}
+	delCloudIDs, err = common.GuardSyncDeletion(kt, cli.dbCli, guardOpt)
+	if err != nil {
+		return err
+	}
+
+	if len(delCloudIDs) == 0 {
+		return nil
+	}
+
 	deleteReq := &dataservice.BatchDeleteReq{
 		Filter: tools.ContainersExpression("cloud_id", delCloudIDs),
 	}
@@ -195,6 +218,11 @@ func (cli *client) deleteVpc(kt *kit.Kit, accountID string, region string, delCl
 
 	logs.Infof("[%s] sync vpc to delete vpc success, accountID: %s, count: %d, rid: %s", enumor.HuaWei,
 		accountID, len(delCloudIDs), kt.Rid)
+	common.RecordSyncDeleted(kt, enumor.VpcCloudResType, len(delCloudIDs))
+
+	if err = common.ConfirmSyncDeletion(kt, cli.dbCli, guardOpt, delCloudIDs); err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -243,6 +271,7 @@ func (cli *client) updateVpc(kt *kit.Kit, accountID string, updateMap map[string
 
 	logs.Infof("[%s] sync vpc to update vpc success, accountID: %s, count: %d, rid: %s", enumor.HuaWei,
 		accountID, len(updateMap), kt.Rid)
+	common.RecordSyncUpdated(kt, enumor.VpcCloudResType, len(updateMap))
 
 	return nil
 }
@@ -293,6 +322,7 @@ func (cli *client) createVpc(kt *kit.Kit, accountID string, addVpc []types.HuaWe
 
 	logs.Infof("[%s] sync vpc to create vpc success, accountID: %s, count: %d, rid: %s", enumor.HuaWei,
 		accountID, len(addVpc), kt.Rid)
+	common.RecordSyncCreated(kt, enumor.VpcCloudResType, len(addVpc))
 
 	return nil
 }
@@ -311,6 +341,7 @@ func (cli *client) listVpcFromCloud(kt *kit.Kit, params *SyncBaseParams) ([]type
 			},
 		},
 	}
+	common.RecordCloudApiCall(kt)
 	result, err := cli.cloudCli.ListVpc(kt, opt)
 	if err != nil {
 		logs.Errorf("[%s] list vpc from cloud failed, err: %v, account: %s, opt: %v, rid: %s", enumor.HuaWei, err,
@@ -399,3 +430,13 @@ func isHuaWeiVpcChange(item types.HuaWeiVpc, info cloudcore.Vpc[cloudcore.HuaWei
 
 	return false
 }
+
+// syncVpcTag 使用云上vpc标签覆盖db中的vpc标签
+func (cli *client) syncVpcTag(kt *kit.Kit, accountID string, vpcFromCloud []types.HuaWeiVpc) error {
+	tagMap := make(map[string][]cloudcore.TagPair, len(vpcFromCloud))
+	for _, one := range vpcFromCloud {
+		tagMap[one.GetCloudID()] = one.Tags
+	}
+
+	return common.SyncResourceTag(kt, cli.dbCli, enumor.HuaWei, accountID, enumor.VpcCloudResType, tagMap)
+}
@@ -0,0 +1,175 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package relmgr
+
+import (
+	typesni "hcm/pkg/adaptor/types/network-interface"
+	typesroutetable "hcm/pkg/adaptor/types/route-table"
+	typessubnet "hcm/pkg/adaptor/types/subnet"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/tools/converter"
+)
+
+// 主机与关联资源的关联关系由 cvm-rel-manager 在同步主机时收集
+var (
+	// CvmDiskRel 主机和硬盘的关联关系
+	CvmDiskRel = &Relation{
+		Name: "cvm_disk_rel",
+		Src:  enumor.CvmCloudResType,
+		Dst:  enumor.DiskCloudResType,
+		Extractors: map[enumor.Vendor]interface{}{
+			enumor.TCloud: nil, enumor.Aws: nil, enumor.Gcp: nil, enumor.HuaWei: nil, enumor.Azure: nil,
+		},
+		NewStore: newDiskCvmRelStore,
+	}
+
+	// CvmEipRel 主机和弹性IP的关联关系
+	CvmEipRel = &Relation{
+		Name: "cvm_eip_rel",
+		Src:  enumor.CvmCloudResType,
+		Dst:  enumor.EipCloudResType,
+		Extractors: map[enumor.Vendor]interface{}{
+			enumor.TCloud: nil, enumor.Aws: nil, enumor.Gcp: nil, enumor.HuaWei: nil, enumor.Azure: nil,
+		},
+		NewStore: newEipCvmRelStore,
+	}
+
+	// CvmSecurityGroupRel 主机和安全组的关联关系
+	CvmSecurityGroupRel = &Relation{
+		Name: "cvm_security_group_rel",
+		Src:  enumor.CvmCloudResType,
+		Dst:  enumor.SecurityGroupCloudResType,
+		Extractors: map[enumor.Vendor]interface{}{
+			enumor.TCloud: nil, enumor.Aws: nil, enumor.HuaWei: nil, enumor.Azure: nil,
+		},
+		NewStore: newSGCvmRelStore,
+	}
+
+	// CvmNetworkInterfaceRel 主机和网络接口的关联关系
+	CvmNetworkInterfaceRel = &Relation{
+		Name: "cvm_network_interface_rel",
+		Src:  enumor.CvmCloudResType,
+		Dst:  enumor.NetworkInterfaceCloudResType,
+		Extractors: map[enumor.Vendor]interface{}{
+			enumor.Gcp: nil, enumor.HuaWei: nil, enumor.Azure: nil,
+		},
+		NewStore: newNICvmRelStore,
+	}
+)
+
+// RouteTableSubnetRel 路由表和子网的关联关系，gcp 路由不区分子网
+var RouteTableSubnetRel = &Relation{
+	Name: "route_table_subnet_rel",
+	Src:  enumor.RouteTableCloudResType,
+	Dst:  enumor.SubnetCloudResType,
+	Extractors: map[enumor.Vendor]interface{}{
+		enumor.TCloud: Extractor[typesroutetable.TCloudRouteTable](func(one typesroutetable.TCloudRouteTable) (
+			string, []string) {
+
+			if one.Extension == nil {
+				return one.CloudID, nil
+			}
+
+			subnetIDs := make([]string, 0, len(one.Extension.Associations))
+			for _, asst := range one.Extension.Associations {
+				subnetIDs = append(subnetIDs, asst.CloudSubnetID)
+			}
+			return one.CloudID, subnetIDs
+		}),
+		enumor.Aws: Extractor[typesroutetable.AwsRouteTable](func(one typesroutetable.AwsRouteTable) (
+			string, []string) {
+
+			if one.Extension == nil {
+				return one.CloudID, nil
+			}
+
+			subnetIDs := make([]string, 0, len(one.Extension.Associations))
+			for _, asst := range one.Extension.Associations {
+				subnetIDs = append(subnetIDs, converter.PtrToVal(asst.CloudSubnetID))
+			}
+			return one.CloudID, subnetIDs
+		}),
+		enumor.HuaWei: Extractor[typesroutetable.HuaWeiRouteTable](func(one typesroutetable.HuaWeiRouteTable) (
+			string, []string) {
+
+			if one.Extension == nil {
+				return one.CloudID, nil
+			}
+			return one.CloudID, one.Extension.CloudSubnetIDs
+		}),
+		enumor.Azure: Extractor[typesroutetable.AzureRouteTable](func(one typesroutetable.AzureRouteTable) (
+			string, []string) {
+
+			if one.Extension == nil {
+				return one.CloudID, nil
+			}
+			return one.CloudID, one.Extension.CloudSubnetIDs
+		}),
+	},
+	NewStore: newSubnetRouteTableStore,
+}
+
+// NetworkInterfaceSecurityGroupRel 网络接口和安全组的关联关系
+var NetworkInterfaceSecurityGroupRel = &Relation{
+	Name: "network_interface_security_group_rel",
+	Src:  enumor.NetworkInterfaceCloudResType,
+	Dst:  enumor.SecurityGroupCloudResType,
+	Extractors: map[enumor.Vendor]interface{}{
+		enumor.HuaWei: Extractor[typesni.HuaWeiNI](func(one typesni.HuaWeiNI) (string, []string) {
+			if one.Extension == nil {
+				return converter.PtrToVal(one.CloudID), nil
+			}
+			return converter.PtrToVal(one.CloudID), one.Extension.CloudSecurityGroupIDs
+		}),
+		enumor.Azure: Extractor[typesni.AzureNI](func(one typesni.AzureNI) (string, []string) {
+			if one.Extension == nil || one.Extension.CloudSecurityGroupID == nil {
+				return converter.PtrToVal(one.CloudID), nil
+			}
+			return converter.PtrToVal(one.CloudID), []string{*one.Extension.CloudSecurityGroupID}
+		}),
+	},
+	NewStore: newSGCommonRelStore(enumor.NetworkInterfaceCloudResType),
+}
+
+// SubnetSecurityGroupRel 子网和安全组的关联关系，仅 azure 支持子网绑定网络安全组
+var SubnetSecurityGroupRel = &Relation{
+	Name: "subnet_security_group_rel",
+	Src:  enumor.SubnetCloudResType,
+	Dst:  enumor.SecurityGroupCloudResType,
+	Extractors: map[enumor.Vendor]interface{}{
+		enumor.Azure: Extractor[typessubnet.AzureSubnet](func(one typessubnet.AzureSubnet) (string, []string) {
+			if one.Extension == nil {
+				return one.CloudID, nil
+			}
+			return one.CloudID, []string{one.Extension.NetworkSecurityGroup}
+		}),
+	},
+	NewStore: newSGCommonRelStore(enumor.SubnetCloudResType),
+}
+
+func init() {
+	Register(CvmDiskRel)
+	Register(CvmEipRel)
+	Register(CvmSecurityGroupRel)
+	Register(CvmNetworkInterfaceRel)
+	Register(RouteTableSubnetRel)
+	Register(NetworkInterfaceSecurityGroupRel)
+	Register(SubnetSecurityGroupRel)
+}
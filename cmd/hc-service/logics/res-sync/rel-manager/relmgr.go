@@ -0,0 +1,281 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package relmgr 资源关联关系管理，以声明的方式注册资源间的关联关系，并按云上数据对关联关系进行对账。
+package relmgr
+
+import (
+	"fmt"
+
+	"hcm/cmd/hc-service/logics/res-sync/common"
+	"hcm/pkg/api/core"
+	protocloud "hcm/pkg/api/data-service/cloud"
+	dataservice "hcm/pkg/client/data-service"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/runtime/filter"
+	"hcm/pkg/tools/slice"
+)
+
+// Extractor 从一个源资源的云上数据中提取其关联的目标资源云ID
+type Extractor[T any] func(one T) (srcCloudID string, dstCloudIDs []string)
+
+// Relation 声明一种资源间的关联关系
+type Relation struct {
+	// Name 关联关系名称，用于日志
+	Name string
+	// Src 源资源类型，对账以源资源为单位进行
+	Src enumor.CloudResourceType
+	// Dst 目标资源类型
+	Dst enumor.CloudResourceType
+	// Extractors 支持该关联关系的云厂商，以及从源资源云上数据中提取关联关系的方法(Extractor[T])，
+	// value 为nil代表关联关系由调用方自行收集
+	Extractors map[enumor.Vendor]interface{}
+	// NewStore 关联关系的存储
+	NewStore func(dataCli *dataservice.Client) Store
+}
+
+// Support 云厂商是否支持该关联关系
+func (rel *Relation) Support(vendor enumor.Vendor) bool {
+	_, exist := rel.Extractors[vendor]
+	return exist
+}
+
+// Pair 源资源与目标资源的关联关系
+type Pair struct {
+	SrcCloudID string
+	DstCloudID string
+}
+
+// Resource 资源的本地ID与云ID
+type Resource struct {
+	ID      string
+	CloudID string
+}
+
+// Record 关联关系记录
+type Record struct {
+	// RelID 关系表主键ID，非关系表存储的关联关系为空
+	RelID      uint64
+	SrcID      string
+	SrcCloudID string
+	DstID      string
+	DstCloudID string
+}
+
+// Store 关联关系存储
+type Store interface {
+	// List 查询源资源已有的关联关系
+	List(kt *kit.Kit, vendor enumor.Vendor, srcs []Resource) ([]Record, error)
+	// Create 创建关联关系
+	Create(kt *kit.Kit, vendor enumor.Vendor, records []Record) error
+	// Delete 删除关联关系
+	Delete(kt *kit.Kit, vendor enumor.Vendor, records []Record) error
+}
+
+var registry = make(map[enumor.CloudResourceType]map[enumor.CloudResourceType]*Relation)
+
+// Register 注册关联关系，同一对源资源和目标资源只能注册一次
+func Register(rel *Relation) {
+	if _, exist := registry[rel.Src]; !exist {
+		registry[rel.Src] = make(map[enumor.CloudResourceType]*Relation)
+	}
+
+	if _, exist := registry[rel.Src][rel.Dst]; exist {
+		panic(fmt.Sprintf("relation %s -> %s already registered", rel.Src, rel.Dst))
+	}
+
+	registry[rel.Src][rel.Dst] = rel
+}
+
+// Find 查询源资源和目标资源之间注册的关联关系
+func Find(src, dst enumor.CloudResourceType) (*Relation, bool) {
+	rel, exist := registry[src][dst]
+	return rel, exist
+}
+
+// ReconcileFromCloud 从源资源的云上数据中提取关联关系并对账，fromCloud为本次同步的全部源资源云上数据
+func ReconcileFromCloud[T any](kt *kit.Kit, dataCli *dataservice.Client, vendor enumor.Vendor, rel *Relation,
+	fromCloud []T) error {
+
+	extract, ok := rel.Extractors[vendor].(Extractor[T])
+	if !ok {
+		return fmt.Errorf("vendor: %s relation %s has no extractor for %T", vendor, rel.Name, fromCloud)
+	}
+
+	srcCloudIDs := make([]string, 0, len(fromCloud))
+	pairs := make([]Pair, 0)
+	for _, one := range fromCloud {
+		srcCloudID, dstCloudIDs := extract(one)
+		srcCloudIDs = append(srcCloudIDs, srcCloudID)
+		for _, dstCloudID := range dstCloudIDs {
+			if len(dstCloudID) == 0 {
+				continue
+			}
+			pairs = append(pairs, Pair{SrcCloudID: srcCloudID, DstCloudID: dstCloudID})
+		}
+	}
+
+	return Reconcile(kt, dataCli, vendor, rel, srcCloudIDs, pairs)
+}
+
+// Remove 清理源资源的全部关联关系，需要在源资源从db中删除前调用
+func Remove(kt *kit.Kit, dataCli *dataservice.Client, vendor enumor.Vendor, rel *Relation,
+	srcCloudIDs []string) error {
+
+	return Reconcile(kt, dataCli, vendor, rel, srcCloudIDs, nil)
+}
+
+// Reconcile 以云上关联关系为准，对源资源在db中的关联关系进行对账。srcCloudIDs为参与对账的源资源，
+// pairs为这些源资源在云上的全部关联关系，db中存在而云上不存在的关联关系会被删除。
+func Reconcile(kt *kit.Kit, dataCli *dataservice.Client, vendor enumor.Vendor, rel *Relation,
+	srcCloudIDs []string, pairs []Pair) error {
+
+	if !rel.Support(vendor) {
+		return fmt.Errorf("vendor: %s %s and %s are not associated", vendor, rel.Src, rel.Dst)
+	}
+
+	// 预览模式下资源均未写入db，跳过关联关系同步
+	if common.IsDryRun(kt) || len(srcCloudIDs) == 0 {
+		return nil
+	}
+
+	srcMap, err := listResIDMap(kt, dataCli, vendor, rel.Src, slice.Unique(srcCloudIDs))
+	if err != nil {
+		return err
+	}
+
+	dstCloudIDs := make([]string, 0, len(pairs))
+	for _, pair := range pairs {
+		dstCloudIDs = append(dstCloudIDs, pair.DstCloudID)
+	}
+	dstMap, err := listResIDMap(kt, dataCli, vendor, rel.Dst, slice.Unique(dstCloudIDs))
+	if err != nil {
+		return err
+	}
+
+	srcs := make([]Resource, 0, len(srcMap))
+	expects := make(map[string]map[string]Record, len(srcMap))
+	for cloudID, id := range srcMap {
+		srcs = append(srcs, Resource{ID: id, CloudID: cloudID})
+		expects[id] = make(map[string]Record)
+	}
+
+	for _, pair := range pairs {
+		srcID, exist := srcMap[pair.SrcCloudID]
+		if !exist {
+			continue
+		}
+
+		// 目标资源还未同步到db时无法建立关联关系，等待目标资源同步后的下一次对账
+		dstID, exist := dstMap[pair.DstCloudID]
+		if !exist {
+			logs.Warnf("[%s] %s: %s not found, skip rel with %s: %s, rid: %s", vendor, rel.Dst, pair.DstCloudID,
+				rel.Src, pair.SrcCloudID, kt.Rid)
+			continue
+		}
+
+		expects[srcID][dstID] = Record{SrcID: srcID, SrcCloudID: pair.SrcCloudID, DstID: dstID,
+			DstCloudID: pair.DstCloudID}
+	}
+
+	store := rel.NewStore(dataCli)
+	dels := make([]Record, 0)
+	for _, part := range slice.Split(srcs, int(core.DefaultMaxPageLimit)) {
+		records, err := store.List(kt, vendor, part)
+		if err != nil {
+			logs.Errorf("[%s] list %s from db failed, err: %v, rid: %s", vendor, rel.Name, err, kt.Rid)
+			return err
+		}
+
+		for _, record := range records {
+			if _, exist := expects[record.SrcID][record.DstID]; exist {
+				delete(expects[record.SrcID], record.DstID)
+				continue
+			}
+			dels = append(dels, record)
+		}
+	}
+
+	adds := make([]Record, 0)
+	for _, records := range expects {
+		for _, record := range records {
+			adds = append(adds, record)
+		}
+	}
+
+	// 先删除后创建，避免一对一的关联关系在变更时冲突
+	if len(dels) > 0 {
+		if err = store.Delete(kt, vendor, dels); err != nil {
+			logs.Errorf("[%s] delete %s failed, err: %v, rid: %s", vendor, rel.Name, err, kt.Rid)
+			return err
+		}
+		logs.Infof("[%s] delete %s success, count: %d, rid: %s", vendor, rel.Name, len(dels), kt.Rid)
+	}
+
+	if len(adds) > 0 {
+		if err = store.Create(kt, vendor, adds); err != nil {
+			logs.Errorf("[%s] create %s failed, err: %v, rid: %s", vendor, rel.Name, err, kt.Rid)
+			return err
+		}
+		logs.Infof("[%s] create %s success, count: %d, rid: %s", vendor, rel.Name, len(adds), kt.Rid)
+	}
+
+	return nil
+}
+
+// listResIDMap 查询资源云ID和本地ID的映射
+func listResIDMap(kt *kit.Kit, dataCli *dataservice.Client, vendor enumor.Vendor,
+	resType enumor.CloudResourceType, cloudIDs []string) (map[string]string, error) {
+
+	result := make(map[string]string, len(cloudIDs))
+	for _, part := range slice.Split(cloudIDs, int(core.DefaultMaxPageLimit)) {
+		req := &protocloud.ListResourceBasicInfoByFilterReq{
+			ResourceType: resType,
+			Filter: &filter.Expression{
+				Op: filter.And,
+				Rules: []filter.RuleFactory{
+					&filter.AtomRule{Field: "vendor", Op: filter.Equal.Factory(), Value: vendor},
+					&filter.AtomRule{Field: "cloud_id", Op: filter.In.Factory(), Value: part},
+				},
+			},
+			Fields: []string{"id", "cloud_id"},
+			Page:   core.NewDefaultBasePage(),
+		}
+		for {
+			resp, err := dataCli.Global.Cloud.ListResBasicInfoByFilter(kt, req)
+			if err != nil {
+				logs.Errorf("[%s] list %s basic info failed, err: %v, rid: %s", vendor, resType, err, kt.Rid)
+				return nil, err
+			}
+
+			for _, one := range resp.Details {
+				result[one.CloudID] = one.ID
+			}
+
+			if uint(len(resp.Details)) < req.Page.Limit {
+				break
+			}
+			req.Page.Start += uint32(req.Page.Limit)
+		}
+	}
+
+	return result, nil
+}
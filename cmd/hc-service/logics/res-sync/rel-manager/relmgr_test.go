@@ -0,0 +1,66 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package relmgr
+
+import (
+	"testing"
+
+	typesroutetable "hcm/pkg/adaptor/types/route-table"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/kit"
+)
+
+func TestFind(t *testing.T) {
+	rel, exist := Find(enumor.CvmCloudResType, enumor.NetworkInterfaceCloudResType)
+	if !exist || rel != CvmNetworkInterfaceRel {
+		t.Fatalf("cvm network interface rel should be registered")
+	}
+
+	if rel.Support(enumor.TCloud) {
+		t.Errorf("tcloud cvm and network interface should not be associated")
+	}
+
+	if _, exist = Find(enumor.SubnetCloudResType, enumor.CvmCloudResType); exist {
+		t.Errorf("subnet cvm rel should not be registered")
+	}
+}
+
+func TestRouteTableSubnetExtractor(t *testing.T) {
+	extract := RouteTableSubnetRel.Extractors[enumor.TCloud].(Extractor[typesroutetable.TCloudRouteTable])
+
+	src, dsts := extract(typesroutetable.TCloudRouteTable{
+		CloudID: "rtb-1",
+		Extension: &typesroutetable.TCloudRouteTableExtension{
+			Associations: []typesroutetable.TCloudRouteTableAsst{{CloudSubnetID: "subnet-1"},
+				{CloudSubnetID: "subnet-2"}},
+		},
+	})
+	if src != "rtb-1" || len(dsts) != 2 || dsts[0] != "subnet-1" || dsts[1] != "subnet-2" {
+		t.Errorf("unexpected extract result, src: %s, dsts: %v", src, dsts)
+	}
+}
+
+func TestReconcileFromCloudTypeMismatch(t *testing.T) {
+	err := ReconcileFromCloud(kit.New(), nil, enumor.Aws, RouteTableSubnetRel,
+		[]typesroutetable.TCloudRouteTable{{CloudID: "rtb-1"}})
+	if err == nil {
+		t.Errorf("reconcile with mismatched extractor type should fail")
+	}
+}
@@ -0,0 +1,385 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package relmgr
+
+import (
+	"hcm/pkg/api/core"
+	dataproto "hcm/pkg/api/data-service"
+	protocloud "hcm/pkg/api/data-service/cloud"
+	dataservice "hcm/pkg/client/data-service"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/kit"
+	"hcm/pkg/runtime/filter"
+	"hcm/pkg/tools/converter"
+	"hcm/pkg/tools/slice"
+)
+
+// tableStore 以关系表存储的关联关系
+type tableStore struct {
+	// srcField 关系表中源资源ID字段
+	srcField string
+	// rules 查询关系表的附加条件
+	rules  []filter.RuleFactory
+	list   func(kt *kit.Kit, req *core.ListReq) ([]Record, error)
+	create func(kt *kit.Kit, vendor enumor.Vendor, records []Record) error
+	delete func(kt *kit.Kit, expr *filter.Expression) error
+}
+
+// List ...
+func (s *tableStore) List(kt *kit.Kit, _ enumor.Vendor, srcs []Resource) ([]Record, error) {
+	srcIDs := make([]string, 0, len(srcs))
+	for _, one := range srcs {
+		srcIDs = append(srcIDs, one.ID)
+	}
+
+	rules := append([]filter.RuleFactory{
+		&filter.AtomRule{Field: s.srcField, Op: filter.In.Factory(), Value: srcIDs},
+	}, s.rules...)
+	req := &core.ListReq{
+		Filter: &filter.Expression{Op: filter.And, Rules: rules},
+		Page:   core.NewDefaultBasePage(),
+	}
+
+	result := make([]Record, 0)
+	for {
+		records, err := s.list(kt, req)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, records...)
+
+		if len(records) < int(core.DefaultMaxPageLimit) {
+			break
+		}
+		req.Page.Start += uint32(core.DefaultMaxPageLimit)
+	}
+
+	return result, nil
+}
+
+// Create ...
+func (s *tableStore) Create(kt *kit.Kit, vendor enumor.Vendor, records []Record) error {
+	for _, part := range slice.Split(records, constant.BatchOperationMaxLimit) {
+		if err := s.create(kt, vendor, part); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Delete ...
+func (s *tableStore) Delete(kt *kit.Kit, _ enumor.Vendor, records []Record) error {
+	relIDs := make([]uint64, 0, len(records))
+	for _, one := range records {
+		relIDs = append(relIDs, one.RelID)
+	}
+
+	for _, part := range slice.Split(relIDs, constant.BatchOperationMaxLimit) {
+		if err := s.delete(kt, tools.ContainersExpression("id", part)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// newDiskCvmRelStore 主机和硬盘的关联关系存储在 disk_cvm_rel 表
+func newDiskCvmRelStore(dataCli *dataservice.Client) Store {
+	return &tableStore{
+		srcField: "cvm_id",
+		list: func(kt *kit.Kit, req *core.ListReq) ([]Record, error) {
+			result, err := dataCli.Global.ListDiskCvmRel(kt, req)
+			if err != nil {
+				return nil, err
+			}
+
+			records := make([]Record, 0, len(result.Details))
+			for _, one := range result.Details {
+				records = append(records, Record{RelID: one.ID, SrcID: one.CvmID, DstID: one.DiskID})
+			}
+			return records, nil
+		},
+		create: func(kt *kit.Kit, _ enumor.Vendor, records []Record) error {
+			rels := make([]protocloud.DiskCvmRelCreateReq, 0, len(records))
+			for _, one := range records {
+				rels = append(rels, protocloud.DiskCvmRelCreateReq{DiskID: one.DstID, CvmID: one.SrcID})
+			}
+			req := &protocloud.DiskCvmRelBatchCreateReq{Rels: rels}
+			return dataCli.Global.BatchCreateDiskCvmRel(kt.Ctx, kt.Header(), req)
+		},
+		delete: func(kt *kit.Kit, expr *filter.Expression) error {
+			req := &protocloud.DiskCvmRelDeleteReq{Filter: expr}
+			return dataCli.Global.DeleteDiskCvmRel(kt.Ctx, kt.Header(), req)
+		},
+	}
+}
+
+// newEipCvmRelStore 主机和弹性IP的关联关系存储在 eip_cvm_rel 表
+func newEipCvmRelStore(dataCli *dataservice.Client) Store {
+	return &tableStore{
+		srcField: "cvm_id",
+		list: func(kt *kit.Kit, req *core.ListReq) ([]Record, error) {
+			result, err := dataCli.Global.ListEipCvmRel(kt, req)
+			if err != nil {
+				return nil, err
+			}
+
+			records := make([]Record, 0, len(result.Details))
+			for _, one := range result.Details {
+				records = append(records, Record{RelID: one.ID, SrcID: one.CvmID, DstID: one.EipID})
+			}
+			return records, nil
+		},
+		create: func(kt *kit.Kit, _ enumor.Vendor, records []Record) error {
+			rels := make([]protocloud.EipCvmRelCreateReq, 0, len(records))
+			for _, one := range records {
+				rels = append(rels, protocloud.EipCvmRelCreateReq{EipID: one.DstID, CvmID: one.SrcID})
+			}
+			req := &protocloud.EipCvmRelBatchCreateReq{Rels: rels}
+			return dataCli.Global.BatchCreateEipCvmRel(kt.Ctx, kt.Header(), req)
+		},
+		delete: func(kt *kit.Kit, expr *filter.Expression) error {
+			req := &protocloud.EipCvmRelDeleteReq{Filter: expr}
+			return dataCli.Global.DeleteEipCvmRel(kt.Ctx, kt.Header(), req)
+		},
+	}
+}
+
+// newSGCvmRelStore 主机和安全组的关联关系存储在 security_group_cvm_rel 表
+func newSGCvmRelStore(dataCli *dataservice.Client) Store {
+	return &tableStore{
+		srcField: "cvm_id",
+		list: func(kt *kit.Kit, req *core.ListReq) ([]Record, error) {
+			result, err := dataCli.Global.SGCvmRel.List(kt.Ctx, kt.Header(), req)
+			if err != nil {
+				return nil, err
+			}
+
+			records := make([]Record, 0, len(result.Details))
+			for _, one := range result.Details {
+				records = append(records, Record{RelID: one.ID, SrcID: one.CvmID, DstID: one.SecurityGroupID})
+			}
+			return records, nil
+		},
+		create: func(kt *kit.Kit, _ enumor.Vendor, records []Record) error {
+			rels := make([]protocloud.SGCvmRelCreate, 0, len(records))
+			for _, one := range records {
+				rels = append(rels, protocloud.SGCvmRelCreate{SecurityGroupID: one.DstID, CvmID: one.SrcID})
+			}
+			req := &protocloud.SGCvmRelBatchCreateReq{Rels: rels}
+			return dataCli.Global.SGCvmRel.BatchCreate(kt.Ctx, kt.Header(), req)
+		},
+		delete: func(kt *kit.Kit, expr *filter.Expression) error {
+			req := &dataproto.BatchDeleteReq{Filter: expr}
+			return dataCli.Global.SGCvmRel.BatchDelete(kt.Ctx, kt.Header(), req)
+		},
+	}
+}
+
+// newNICvmRelStore 主机和网络接口的关联关系存储在 network_interface_cvm_rel 表
+func newNICvmRelStore(dataCli *dataservice.Client) Store {
+	return &tableStore{
+		srcField: "cvm_id",
+		list: func(kt *kit.Kit, req *core.ListReq) ([]Record, error) {
+			result, err := dataCli.Global.NetworkInterfaceCvmRel.List(kt, req)
+			if err != nil {
+				return nil, err
+			}
+
+			records := make([]Record, 0, len(result.Details))
+			for _, one := range result.Details {
+				records = append(records, Record{RelID: one.ID, SrcID: one.CvmID, DstID: one.NetworkInterfaceID})
+			}
+			return records, nil
+		},
+		create: func(kt *kit.Kit, _ enumor.Vendor, records []Record) error {
+			rels := make([]protocloud.NetworkInterfaceCvmRelCreateReq, 0, len(records))
+			for _, one := range records {
+				rels = append(rels, protocloud.NetworkInterfaceCvmRelCreateReq{NetworkInterfaceID: one.DstID,
+					CvmID: one.SrcID})
+			}
+			req := &protocloud.NetworkInterfaceCvmRelBatchCreateReq{Rels: rels}
+			return dataCli.Global.NetworkInterfaceCvmRel.BatchCreate(kt.Ctx, kt.Header(), req)
+		},
+		delete: func(kt *kit.Kit, expr *filter.Expression) error {
+			req := &dataproto.BatchDeleteReq{Filter: expr}
+			return dataCli.Global.NetworkInterfaceCvmRel.BatchDelete(kt.Ctx, kt.Header(), req)
+		},
+	}
+}
+
+// newSGCommonRelStore 安全组和其他资源(子网、网络接口等)的关联关系存储在 security_group_common_rel 表，
+// 源资源为关联安全组的资源，目标资源为安全组
+func newSGCommonRelStore(resType enumor.CloudResourceType) func(dataCli *dataservice.Client) Store {
+	return func(dataCli *dataservice.Client) Store {
+		return &tableStore{
+			srcField: "res_id",
+			rules: []filter.RuleFactory{
+				&filter.AtomRule{Field: "res_type", Op: filter.Equal.Factory(), Value: resType},
+			},
+			list: func(kt *kit.Kit, req *core.ListReq) ([]Record, error) {
+				result, err := dataCli.Global.SGCommonRel.List(kt, req)
+				if err != nil {
+					return nil, err
+				}
+
+				records := make([]Record, 0, len(result.Details))
+				for _, one := range result.Details {
+					records = append(records, Record{RelID: one.ID, SrcID: one.ResID, DstID: one.SecurityGroupID})
+				}
+				return records, nil
+			},
+			create: func(kt *kit.Kit, vendor enumor.Vendor, records []Record) error {
+				rels := make([]protocloud.SGCommonRelCreate, 0, len(records))
+				for _, one := range records {
+					rels = append(rels, protocloud.SGCommonRelCreate{
+						SecurityGroupID: one.DstID,
+						Vendor:          vendor,
+						ResType:         resType,
+						ResID:           one.SrcID,
+					})
+				}
+				return dataCli.Global.SGCommonRel.BatchCreate(kt, &protocloud.SGCommonRelBatchCreateReq{Rels: rels})
+			},
+			delete: func(kt *kit.Kit, expr *filter.Expression) error {
+				return dataCli.Global.SGCommonRel.BatchDelete(kt, &dataproto.BatchDeleteReq{Filter: expr})
+			},
+		}
+	}
+}
+
+// subnetRouteTableStore 路由表和子网的关联关系存储在子网的 route_table_id、cloud_route_table_id 字段，
+// 源资源为路由表，目标资源为子网
+type subnetRouteTableStore struct {
+	dataCli *dataservice.Client
+}
+
+func newSubnetRouteTableStore(dataCli *dataservice.Client) Store {
+	return &subnetRouteTableStore{dataCli: dataCli}
+}
+
+// List 按云ID查询关联了路由表的子网，路由表创建晚于子网关联时子网上可能只有 cloud_route_table_id
+func (s *subnetRouteTableStore) List(kt *kit.Kit, vendor enumor.Vendor, srcs []Resource) ([]Record, error) {
+	srcCloudIDs := make([]string, 0, len(srcs))
+	for _, one := range srcs {
+		srcCloudIDs = append(srcCloudIDs, one.CloudID)
+	}
+
+	req := &core.ListReq{
+		Fields: []string{"id", "cloud_id", "route_table_id", "cloud_route_table_id"},
+		Filter: &filter.Expression{
+			Op: filter.And,
+			Rules: []filter.RuleFactory{
+				&filter.AtomRule{Field: "vendor", Op: filter.Equal.Factory(), Value: vendor},
+				&filter.AtomRule{Field: "cloud_route_table_id", Op: filter.In.Factory(), Value: srcCloudIDs},
+			},
+		},
+		Page: core.NewDefaultBasePage(),
+	}
+
+	result := make([]Record, 0)
+	for {
+		subnets, err := s.dataCli.Global.Subnet.List(kt.Ctx, kt.Header(), req)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, one := range subnets.Details {
+			// route_table_id 与 cloud_route_table_id 不一致时记录为待删除，由对账重新建立关联关系
+			result = append(result, Record{
+				SrcID:      one.RouteTableID,
+				SrcCloudID: one.CloudRouteTableID,
+				DstID:      one.ID,
+				DstCloudID: one.CloudID,
+			})
+		}
+
+		if len(subnets.Details) < int(core.DefaultMaxPageLimit) {
+			break
+		}
+		req.Page.Start += uint32(core.DefaultMaxPageLimit)
+	}
+
+	return result, nil
+}
+
+// Create ...
+func (s *subnetRouteTableStore) Create(kt *kit.Kit, _ enumor.Vendor, records []Record) error {
+	return s.update(kt, records, func(one Record) *protocloud.SubnetUpdateBaseInfo {
+		return &protocloud.SubnetUpdateBaseInfo{
+			CloudRouteTableID: converter.ValToPtr(one.SrcCloudID),
+			RouteTableID:      converter.ValToPtr(one.SrcID),
+		}
+	})
+}
+
+// Delete ...
+func (s *subnetRouteTableStore) Delete(kt *kit.Kit, _ enumor.Vendor, records []Record) error {
+	return s.update(kt, records, func(one Record) *protocloud.SubnetUpdateBaseInfo {
+		return &protocloud.SubnetUpdateBaseInfo{
+			CloudRouteTableID: converter.ValToPtr(""),
+			RouteTableID:      converter.ValToPtr(""),
+		}
+	})
+}
+
+// update 更新子网的路由表字段，ipv6_cidr 在子网更新时总会被写入，需要带上原值
+func (s *subnetRouteTableStore) update(kt *kit.Kit, records []Record,
+	data func(one Record) *protocloud.SubnetUpdateBaseInfo) error {
+
+	for _, part := range slice.Split(records, constant.BatchOperationMaxLimit) {
+		ids := make([]string, 0, len(part))
+		for _, one := range part {
+			ids = append(ids, one.DstID)
+		}
+
+		listReq := &core.ListReq{
+			Fields: []string{"id", "ipv6_cidr"},
+			Filter: tools.ContainersExpression("id", ids),
+			Page:   core.NewDefaultBasePage(),
+		}
+		list, err := s.dataCli.Global.Subnet.List(kt.Ctx, kt.Header(), listReq)
+		if err != nil {
+			return err
+		}
+
+		ipv6CidrMap := make(map[string][]string, len(list.Details))
+		for _, one := range list.Details {
+			ipv6CidrMap[one.ID] = one.Ipv6Cidr
+		}
+
+		subnets := make([]protocloud.SubnetBaseInfoUpdateReq, 0, len(part))
+		for _, one := range part {
+			info := data(one)
+			info.Ipv6Cidr = ipv6CidrMap[one.DstID]
+			subnets = append(subnets, protocloud.SubnetBaseInfoUpdateReq{IDs: []string{one.DstID}, Data: info})
+		}
+
+		req := &protocloud.SubnetBaseInfoBatchUpdateReq{Subnets: subnets}
+		if err := s.dataCli.Global.Subnet.BatchUpdateBaseInfo(kt.Ctx, kt.Header(), req); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -26,6 +26,7 @@ import (
 	adcore "hcm/pkg/adaptor/types/core"
 	typescvm "hcm/pkg/adaptor/types/cvm"
 	"hcm/pkg/api/core"
+	corecloud "hcm/pkg/api/core/cloud"
 	"hcm/pkg/api/core/cloud/cvm"
 	corecvm "hcm/pkg/api/core/cloud/cvm"
 	dataproto "hcm/pkg/api/data-service/cloud"
@@ -57,6 +58,8 @@ func (cli *client) Cvm(kt *kit.Kit, params *SyncBaseParams, opt *SyncCvmOption)
 		return nil, errf.NewFromErr(errf.InvalidParameter, err)
 	}
 
+	kt, stat := common.ForkSyncStat(kt)
+
 	cvmFromCloud, err := cli.listCvmFromCloud(kt, params)
 	if err != nil {
 		return nil, err
@@ -68,11 +71,11 @@ func (cli *client) Cvm(kt *kit.Kit, params *SyncBaseParams, opt *SyncCvmOption)
 	}
 
 	if len(cvmFromCloud) == 0 && len(cvmFromDB) == 0 {
-		return new(SyncResult), nil
+		return &SyncResult{Counts: stat.Counts()}, nil
 	}
 
 	addSlice, updateMap, delCloudIDs := common.Diff[typescvm.TCloudCvm, corecvm.Cvm[cvm.TCloudCvmExtension]](
-		cvmFromCloud, cvmFromDB, isCvmChange)
+		kt, enumor.CvmCloudResType, cvmFromCloud, cvmFromDB, isCvmChange)
 
 	if len(delCloudIDs) > 0 {
 		if err := cli.deleteCvm(kt, params.AccountID, params.Region, delCloudIDs); err != nil {
@@ -92,7 +95,11 @@ func (cli *client) Cvm(kt *kit.Kit, params *SyncBaseParams, opt *SyncCvmOption)
 		}
 	}
 
-	return new(SyncResult), nil
+	if err = cli.syncCvmTag(kt, params.AccountID, cvmFromCloud); err != nil {
+		return nil, err
+	}
+
+	return &SyncResult{Counts: stat.Counts()}, nil
 }
 
 func (cli *client) updateCvm(kt *kit.Kit, accountID string, region string,
@@ -193,6 +200,7 @@ func (cli *client) updateCvm(kt *kit.Kit, accountID string, region string,
 		}
 
 		if len(one.IPv6Addresses) != 0 {
+			common.RecordCloudApiCall(kt)
 			one.PublicIpAddresses, one.PrivateIpAddresses, err = cli.cloudCli.DetermineIPv6Type(kt,
 				region, one.IPv6Addresses)
 			if err != nil {
@@ -214,6 +222,7 @@ func (cli *client) updateCvm(kt *kit.Kit, accountID string, region string,
 
 	logs.Infof("[%s] sync cvm to update cvm success, accountID: %s, count: %d, rid: %s", enumor.TCloud,
 		accountID, len(updateMap), kt.Rid)
+	common.RecordSyncUpdated(kt, enumor.CvmCloudResType, len(updateMap))
 
 	return nil
 }
@@ -323,6 +332,7 @@ func (cli *client) createCvm(kt *kit.Kit, accountID string, region string,
 		}
 
 		if len(one.IPv6Addresses) != 0 {
+			common.RecordCloudApiCall(kt)
 			one.PublicIpAddresses, one.PrivateIpAddresses, err = cli.cloudCli.DetermineIPv6Type(kt,
 				region, one.IPv6Addresses)
 			if err != nil {
@@ -346,6 +356,7 @@ func (cli *client) createCvm(kt *kit.Kit, accountID string, region string,
 
 	logs.Infof("[%s] sync cvm to create cvm success, accountID: %s, count: %d, rid: %s", enumor.TCloud,
 		accountID, len(addSlice), kt.Rid)
+	common.RecordSyncCreated(kt, enumor.CvmCloudResType, len(addSlice))
 
 	return nil
 }
@@ -449,6 +460,23 @@ func (cli *client) deleteCvm(kt *kit.Kit, accountID string, region string, delCl
 		return fmt.Errorf("validate cvm not exist failed, before delete")
 	}
 
+	guardOpt := &common.SyncDeletionGuardOption{
+		Vendor:      enumor.TCloud,
+		ResType:     enumor.CvmCloudResType,
+		AccountID:   accountID,
+		Region:      region,
+		RegionField: "region",
+		CloudIDs:    delCloudIDs,
+	}
+	delCloudIDs, err = common.GuardSyncDeletion(kt, cli.dbCli, guardOpt)
+	if err != nil {
+		return err
+	}
+
+	if len(delCloudIDs) == 0 {
+		return nil
+	}
+
 	deleteReq := &dataproto.CvmBatchDeleteReq{
 		Filter: tools.ContainersExpression("cloud_id", delCloudIDs),
 	}
@@ -460,6 +488,11 @@ func (cli *client) deleteCvm(kt *kit.Kit, accountID string, region string, delCl
 
 	logs.Infof("[%s] sync cvm to delete cvm success, accountID: %s, count: %d, rid: %s", enumor.TCloud,
 		accountID, len(delCloudIDs), kt.Rid)
+	common.RecordSyncDeleted(kt, enumor.CvmCloudResType, len(delCloudIDs))
+
+	if err = common.ConfirmSyncDeletion(kt, cli.dbCli, guardOpt, delCloudIDs); err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -477,6 +510,7 @@ func (cli *client) listCvmFromCloud(kt *kit.Kit, params *SyncBaseParams) ([]type
 			Limit:  adcore.TCloudQueryLimit,
 		},
 	}
+	common.RecordCloudApiCall(kt)
 	result, err := cli.cloudCli.ListCvm(kt, opt)
 	if err != nil {
 		logs.Errorf("[%s] list cvm from cloud failed, err: %v, account: %s, opt: %v, rid: %s", enumor.TCloud,
@@ -745,3 +779,20 @@ func isCvmChange(cloud typescvm.TCloudCvm, db corecvm.Cvm[cvm.TCloudCvmExtension
 
 	return false
 }
+
+// syncCvmTag 使用云上主机标签覆盖db中的主机标签
+func (cli *client) syncCvmTag(kt *kit.Kit, accountID string, cvms []typescvm.TCloudCvm) error {
+	tagMap := make(map[string][]corecloud.TagPair, len(cvms))
+	for _, one := range cvms {
+		tags := make([]corecloud.TagPair, 0, len(one.Tags))
+		for _, tag := range one.Tags {
+			tags = append(tags, corecloud.TagPair{
+				Key:   converter.PtrToVal(tag.Key),
+				Value: converter.PtrToVal(tag.Value),
+			})
+		}
+		tagMap[one.GetCloudID()] = tags
+	}
+
+	return common.SyncResourceTag(kt, cli.dbCli, enumor.TCloud, accountID, enumor.CvmCloudResType, tagMap)
+}
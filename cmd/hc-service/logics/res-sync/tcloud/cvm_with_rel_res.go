@@ -21,6 +21,7 @@ package tcloud
 
 import (
 	"fmt"
+	"hcm/cmd/hc-service/logics/res-sync/common"
 	"strings"
 
 	cvmrelmgr "hcm/cmd/hc-service/logics/res-sync/cvm-rel-manager"
@@ -62,6 +63,8 @@ func (opt SyncCvmWithRelResOption) Validate() error {
 func (cli *client) CvmWithRelRes(kt *kit.Kit, params *SyncBaseParams, opt *SyncCvmWithRelResOption) (
 	*SyncResult, error) {
 
+	kt, stat := common.ForkSyncStat(kt)
+
 	cvmFromCloud, err := cli.listCvmFromCloud(kt, params)
 	if err != nil {
 		return nil, err
@@ -73,7 +76,7 @@ func (cli *client) CvmWithRelRes(kt *kit.Kit, params *SyncBaseParams, opt *SyncC
 			return nil, err
 		}
 
-		return new(SyncResult), nil
+		return &SyncResult{Counts: stat.Counts()}, nil
 	}
 
 	// step2: 获取cvm和关联资源的关联关系
@@ -210,7 +213,7 @@ func (cli *client) CvmWithRelRes(kt *kit.Kit, params *SyncBaseParams, opt *SyncC
 		return nil, err
 	}
 
-	return new(SyncResult), nil
+	return &SyncResult{Counts: stat.Counts()}, nil
 }
 
 func (cli *client) buildCvmRelManger(kt *kit.Kit, region string, cvmFromCloud []typecvm.TCloudCvm) (
@@ -315,6 +318,7 @@ func (cli *client) getEipMapFromCloudByCvm(kt *kit.Kit, region string, cvmFromCl
 				Limit:  adcore.TCloudQueryLimit,
 			},
 		}
+		common.RecordCloudApiCall(kt)
 		resp, err := cli.cloudCli.ListEip(kt, opt)
 		if err != nil {
 			logs.Errorf("[%s] list eip by ip from cloud failed, err: %v, account: %s, opt: %v, rid: %s",
@@ -59,6 +59,8 @@ func (cli *client) Route(kt *kit.Kit, params *SyncBaseParams, opt *SyncRouteOpti
 		return nil, errf.NewFromErr(errf.InvalidParameter, err)
 	}
 
+	kt, stat := common.ForkSyncStat(kt)
+
 	err := concurrence.BaseExec(constant.SyncConcurrencyDefaultMaxLimit, params.CloudIDs, func(param string) error {
 		syncOpt := &syncRouteOption{
 			AccountID:         params.AccountID,
@@ -77,7 +79,7 @@ func (cli *client) Route(kt *kit.Kit, params *SyncBaseParams, opt *SyncRouteOpti
 		return nil, err
 	}
 
-	return new(SyncResult), nil
+	return &SyncResult{Counts: stat.Counts()}, nil
 }
 
 type syncRouteOption struct {
@@ -126,7 +128,7 @@ func (cli *client) route(kt *kit.Kit, opt *syncRouteOption) (*SyncResult, error)
 	}
 
 	addSlice, updateMap, delCloudIDs := common.Diff[typesroutetable.TCloudRoute,
-		routetable.TCloudRoute](routeFromCloud, routeFromDB, isRouteChange)
+		routetable.TCloudRoute](kt, enumor.RouteCloudResType, routeFromCloud, routeFromDB, isRouteChange)
 
 	if len(delCloudIDs) > 0 {
 		if err = cli.deleteRoute(kt, opt.AccountID, opt.Region, opt.CloudRouteTableID, routeTable.ID,
@@ -192,6 +194,7 @@ func (cli *client) createRoute(kt *kit.Kit, accountID string, region string, rou
 	}
 	logs.Infof("[%s] sync route to create route success, accountID: %s, count: %d, rid: %s", enumor.TCloud,
 		accountID, len(addSlice), kt.Rid)
+	common.RecordSyncCreated(kt, enumor.RouteCloudResType, len(addSlice))
 	return nil
 }
 
@@ -234,6 +237,7 @@ func (cli *client) updateRoute(kt *kit.Kit, accountID, region, routeTableID stri
 
 	logs.Infof("[%s] sync route to update route success, accountID: %s, count: %d, rid: %s", enumor.TCloud,
 		accountID, len(updateMap), kt.Rid)
+	common.RecordSyncUpdated(kt, enumor.RouteCloudResType, len(updateMap))
 
 	return nil
 }
@@ -245,6 +249,10 @@ func (cli *client) deleteRoute(kt *kit.Kit, accountID, region, cloudRTID, rtID s
 		return fmt.Errorf("route delCloudIDs is <= 0, not delete")
 	}
 
+	if common.RecordDryRunDelete(kt, enumor.RouteCloudResType, delCloudIDs) {
+		return nil
+	}
+
 	checkParams := &syncRouteOption{
 		AccountID:         accountID,
 		Region:            region,
@@ -280,6 +288,7 @@ func (cli *client) deleteRoute(kt *kit.Kit, accountID, region, cloudRTID, rtID s
 
 	logs.Infof("[%s] sync route to delete route success, accountID: %s, count: %d, rid: %s", enumor.TCloud,
 		accountID, len(delCloudIDs), kt.Rid)
+	common.RecordSyncDeleted(kt, enumor.RouteCloudResType, len(delCloudIDs))
 
 	return nil
 }
@@ -298,6 +307,7 @@ func (cli *client) listRouteFromCloud(kt *kit.Kit, opt *syncRouteOption) ([]type
 			Limit:  adcore.TCloudQueryLimit,
 		},
 	}
+	common.RecordCloudApiCall(kt)
 	routeTables, err := cli.cloudCli.ListRouteTable(kt, routeOpt)
 	if err != nil {
 		if strings.Contains(err.Error(), tcloud.ErrNotFound) {
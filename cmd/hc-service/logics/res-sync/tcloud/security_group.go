@@ -56,6 +56,8 @@ func (cli *client) SecurityGroup(kt *kit.Kit, params *SyncBaseParams, opt *SyncS
 		return nil, errf.NewFromErr(errf.InvalidParameter, err)
 	}
 
+	kt, stat := common.ForkSyncStat(kt)
+
 	sgFromCloud, err := cli.listSGFromCloud(kt, params)
 	if err != nil {
 		return nil, err
@@ -67,11 +69,11 @@ func (cli *client) SecurityGroup(kt *kit.Kit, params *SyncBaseParams, opt *SyncS
 	}
 
 	if len(sgFromCloud) == 0 && len(sgFromDB) == 0 {
-		return new(SyncResult), nil
+		return &SyncResult{Counts: stat.Counts()}, nil
 	}
 
 	addSlice, updateMap, delCloudIDs := common.Diff[securitygroup.TCloudSG, cloudcore.SecurityGroup[cloudcore.TCloudSecurityGroupExtension]](
-		sgFromCloud, sgFromDB, isSGChange)
+		kt, enumor.SecurityGroupCloudResType, sgFromCloud, sgFromDB, isSGChange)
 
 	if len(delCloudIDs) > 0 {
 		if err = cli.deleteSG(kt, params.AccountID, params.Region, delCloudIDs); err != nil {
@@ -92,6 +94,10 @@ func (cli *client) SecurityGroup(kt *kit.Kit, params *SyncBaseParams, opt *SyncS
 		}
 	}
 
+	if err = cli.syncSGTag(kt, params.AccountID, sgFromCloud); err != nil {
+		return nil, err
+	}
+
 	// 同步安全组规则
 	sgFromDB, err = cli.listSGFromDB(kt, params)
 	if err != nil {
@@ -115,7 +121,7 @@ func (cli *client) SecurityGroup(kt *kit.Kit, params *SyncBaseParams, opt *SyncS
 		return nil, err
 	}
 
-	return new(SyncResult), nil
+	return &SyncResult{Counts: stat.Counts()}, nil
 }
 
 func (cli *client) updateSG(kt *kit.Kit, accountID string,
@@ -152,6 +158,7 @@ func (cli *client) updateSG(kt *kit.Kit, accountID string,
 
 	logs.Infof("[%s] sync sg to update sg success, accountID: %s, count: %d, rid: %s", enumor.TCloud,
 		accountID, len(updateMap), kt.Rid)
+	common.RecordSyncUpdated(kt, enumor.SecurityGroupCloudResType, len(updateMap))
 
 	return nil
 }
@@ -191,6 +198,7 @@ func (cli *client) createSG(kt *kit.Kit, accountID string, region string,
 
 	logs.Infof("[%s] sync sg to create sg success, accountID: %s, count: %d, rid: %s", enumor.TCloud,
 		accountID, len(addSlice), kt.Rid)
+	common.RecordSyncCreated(kt, enumor.SecurityGroupCloudResType, len(addSlice))
 
 	return results.IDs, nil
 }
@@ -216,6 +224,23 @@ func (cli *client) deleteSG(kt *kit.Kit, accountID string, region string, delClo
 		return fmt.Errorf("validate sg not exist failed, before delete")
 	}
 
+	guardOpt := &common.SyncDeletionGuardOption{
+		Vendor:      enumor.TCloud,
+		ResType:     enumor.SecurityGroupCloudResType,
+		AccountID:   accountID,
+		Region:      region,
+		RegionField: "region",
+		CloudIDs:    delCloudIDs,
+	}
+	delCloudIDs, err = common.GuardSyncDeletion(kt, cli.dbCli, guardOpt)
+	if err != nil {
+		return err
+	}
+
+	if len(delCloudIDs) == 0 {
+		return nil
+	}
+
 	deleteReq := &protocloud.SecurityGroupBatchDeleteReq{
 		Filter: tools.ContainersExpression("cloud_id", delCloudIDs),
 	}
@@ -227,6 +252,11 @@ func (cli *client) deleteSG(kt *kit.Kit, accountID string, region string, delClo
 
 	logs.Infof("[%s] sync sg to delete sg success, accountID: %s, count: %d, rid: %s", enumor.TCloud,
 		accountID, len(delCloudIDs), kt.Rid)
+	common.RecordSyncDeleted(kt, enumor.SecurityGroupCloudResType, len(delCloudIDs))
+
+	if err = common.ConfirmSyncDeletion(kt, cli.dbCli, guardOpt, delCloudIDs); err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -244,6 +274,7 @@ func (cli *client) listSGFromCloud(kt *kit.Kit, params *SyncBaseParams) ([]secur
 			Limit:  typecore.TCloudQueryLimit,
 		},
 	}
+	common.RecordCloudApiCall(kt)
 	result, err := cli.cloudCli.ListSecurityGroupNew(kt, opt)
 	if err != nil {
 		if strings.Contains(err.Error(), tcloud.ErrNotFound) {
@@ -377,6 +408,7 @@ func (cli *client) listRemoveSGID(kt *kit.Kit, params *SyncBaseParams) ([]string
 			Region:   params.Region,
 			CloudIDs: []string{one},
 		}
+		common.RecordCloudApiCall(kt)
 		_, err := cli.cloudCli.ListSecurityGroupNew(kt, opt)
 		if err != nil {
 			if strings.Contains(err.Error(), tcloud.ErrNotFound) {
@@ -404,3 +436,20 @@ func isSGChange(cloud securitygroup.TCloudSG, db cloudcore.SecurityGroup[cloudco
 
 	return false
 }
+
+// syncSGTag 使用云上安全组标签覆盖db中的安全组标签
+func (cli *client) syncSGTag(kt *kit.Kit, accountID string, sgFromCloud []securitygroup.TCloudSG) error {
+	tagMap := make(map[string][]cloudcore.TagPair, len(sgFromCloud))
+	for _, one := range sgFromCloud {
+		tags := make([]cloudcore.TagPair, 0, len(one.TagSet))
+		for _, tag := range one.TagSet {
+			tags = append(tags, cloudcore.TagPair{
+				Key:   converter.PtrToVal(tag.Key),
+				Value: converter.PtrToVal(tag.Value),
+			})
+		}
+		tagMap[one.GetCloudID()] = tags
+	}
+
+	return common.SyncResourceTag(kt, cli.dbCli, enumor.TCloud, accountID, enumor.SecurityGroupCloudResType, tagMap)
+}
@@ -20,10 +20,14 @@
 package tcloud
 
 import (
+	"fmt"
+
+	"hcm/cmd/hc-service/logics/res-sync/common"
 	securitygrouprule "hcm/pkg/adaptor/types/security-group-rule"
 	"hcm/pkg/api/core"
 	corecloud "hcm/pkg/api/core/cloud"
 	protocloud "hcm/pkg/api/data-service/cloud"
+	hcsync "hcm/pkg/api/hc-service/sync"
 	"hcm/pkg/criteria/constant"
 	"hcm/pkg/criteria/enumor"
 	"hcm/pkg/criteria/errf"
@@ -55,6 +59,8 @@ func (cli *client) SecurityGroupRule(kt *kit.Kit, params *SyncBaseParams, opt *S
 		return nil, errf.NewFromErr(errf.InvalidParameter, err)
 	}
 
+	kt, stat := common.ForkSyncStat(kt)
+
 	var syncResult *SyncResult
 	err := concurrence.BaseExec(constant.SyncConcurrencyDefaultMaxLimit, params.CloudIDs, func(param string) error {
 		syncOpt := &syncSGRuleOption{
@@ -74,6 +80,12 @@ func (cli *client) SecurityGroupRule(kt *kit.Kit, params *SyncBaseParams, opt *S
 	if err != nil {
 		return nil, err
 	}
+
+	if syncResult == nil {
+		syncResult = new(SyncResult)
+	}
+	syncResult.Counts = stat.Counts()
+
 	return syncResult, nil
 }
 
@@ -108,6 +120,7 @@ func (cli *client) securityGroupRule(kt *kit.Kit, opt *syncSGRuleOption) (*SyncR
 	}
 
 	updateRules := make(map[string]*corecloud.TCloudSecurityGroupRule)
+	deleteRules := make([]corecloud.TCloudSecurityGroupRule, 0)
 	deleteRuleIDs := make([]string, 0)
 	for _, one := range rulesFromDB {
 		var ruleMap map[int64]*vpc.SecurityGroupPolicy
@@ -123,6 +136,7 @@ func (cli *client) securityGroupRule(kt *kit.Kit, opt *syncSGRuleOption) (*SyncR
 		}
 		policy, exist := ruleMap[one.CloudPolicyIndex]
 		if !exist {
+			deleteRules = append(deleteRules, one)
 			deleteRuleIDs = append(deleteRuleIDs, one.ID)
 			continue
 		}
@@ -143,6 +157,10 @@ func (cli *client) securityGroupRule(kt *kit.Kit, opt *syncSGRuleOption) (*SyncR
 		createRules = append(createRules, *rule)
 	}
 
+	if recordSGRuleDryRun(kt, createRules, updateRules, deleteRules) {
+		return new(SyncResult), nil
+	}
+
 	if len(deleteRuleIDs) != 0 {
 		if err = cli.deleteSGRule(kt, sg.ID, deleteRuleIDs); err != nil {
 			return nil, err
@@ -166,6 +184,36 @@ func (cli *client) securityGroupRule(kt *kit.Kit, opt *syncSGRuleOption) (*SyncR
 	return syncResult, nil
 }
 
+// recordSGRuleDryRun 预览模式下记录安全组规则的差异，腾讯云安全组规则没有云ID，以 云安全组ID/规则类型/规则索引 作为规则标识。
+func recordSGRuleDryRun(kt *kit.Kit, createRules []corecloud.TCloudSecurityGroupRule,
+	updateRules map[string]*corecloud.TCloudSecurityGroupRule, deleteRules []corecloud.TCloudSecurityGroupRule) bool {
+
+	if !common.IsDryRun(kt) {
+		return false
+	}
+
+	ruleID := func(rule *corecloud.TCloudSecurityGroupRule) string {
+		return fmt.Sprintf("%s/%s/%d", rule.CloudSecurityGroupID, rule.Type, rule.CloudPolicyIndex)
+	}
+
+	diff := hcsync.DryRunDiff{
+		AddCloudIDs:    make([]string, 0, len(createRules)),
+		Update:         make([]hcsync.DryRunUpdate, 0, len(updateRules)),
+		DeleteCloudIDs: make([]string, 0, len(deleteRules)),
+	}
+	for i := range createRules {
+		diff.AddCloudIDs = append(diff.AddCloudIDs, ruleID(&createRules[i]))
+	}
+	for _, one := range updateRules {
+		diff.Update = append(diff.Update, hcsync.DryRunUpdate{CloudID: ruleID(one)})
+	}
+	for i := range deleteRules {
+		diff.DeleteCloudIDs = append(diff.DeleteCloudIDs, ruleID(&deleteRules[i]))
+	}
+
+	return common.RecordDryRunDiff(kt, enumor.SecurityGroupRuleCloudResType, diff)
+}
+
 // listSGRuleFromCloud list tcloud security group rule from database
 func (cli *client) listSGRuleFromDB(kt *kit.Kit, sgID string) (
 	[]corecloud.TCloudSecurityGroupRule, error) {
@@ -203,6 +251,7 @@ func (cli *client) listSGRuleFromCloud(kt *kit.Kit, region, cloudSGID string) (s
 		Region:               region,
 		CloudSecurityGroupID: cloudSGID,
 	}
+	common.RecordCloudApiCall(kt)
 	rules, err := cli.cloudCli.ListSecurityGroupRule(kt, listOpt)
 	if err != nil {
 		logs.Errorf("[%s] request adaptor to list tcloud security group rule failed, err: %v, rid: %s", enumor.TCloud,
@@ -56,6 +56,8 @@ func (cli *client) Subnet(kt *kit.Kit, params *SyncBaseParams, opt *SyncSubnetOp
 		return nil, errf.NewFromErr(errf.InvalidParameter, err)
 	}
 
+	kt, stat := common.ForkSyncStat(kt)
+
 	subnetFromCloud, err := cli.listSubnetFromCloud(kt, params)
 	if err != nil {
 		return nil, err
@@ -67,11 +69,12 @@ func (cli *client) Subnet(kt *kit.Kit, params *SyncBaseParams, opt *SyncSubnetOp
 	}
 
 	if len(subnetFromCloud) == 0 && len(subnetFromDB) == 0 {
-		return new(SyncResult), nil
+		return &SyncResult{Counts: stat.Counts()}, nil
 	}
 
 	addSubnet, updateMap, delCloudIDs := common.Diff[adtysubnet.TCloudSubnet,
-		cloudcore.Subnet[cloudcore.TCloudSubnetExtension]](subnetFromCloud, subnetFromDB, isTCloudSubnetChange)
+		cloudcore.Subnet[cloudcore.TCloudSubnetExtension]](
+		kt, enumor.SubnetCloudResType, subnetFromCloud, subnetFromDB, isTCloudSubnetChange)
 
 	if len(delCloudIDs) > 0 {
 		if err = cli.deleteSubnet(kt, params.AccountID, params.Region, delCloudIDs); err != nil {
@@ -91,7 +94,11 @@ func (cli *client) Subnet(kt *kit.Kit, params *SyncBaseParams, opt *SyncSubnetOp
 		}
 	}
 
-	return new(SyncResult), nil
+	if err = cli.syncSubnetTag(kt, params.AccountID, subnetFromCloud); err != nil {
+		return nil, err
+	}
+
+	return &SyncResult{Counts: stat.Counts()}, nil
 }
 
 func (cli *client) deleteSubnet(kt *kit.Kit, accountID string, region string, delCloudIDs []string) error {
@@ -115,6 +122,23 @@ func (cli *client) deleteSubnet(kt *kit.Kit, accountID string, region string, de
 		return fmt.Errorf("validate subnet not exist failed, before delete")
 	}
 
+	guardOpt := &common.SyncDeletionGuardOption{
+		Vendor:      enumor.TCloud,
+		ResType:     enumor.SubnetCloudResType,
+		AccountID:   accountID,
+		Region:      region,
+		RegionField: "region",
+		CloudIDs:    delCloudIDs,
+	}
+	delCloudIDs, err = common.GuardSyncDeletion(kt, cli.dbCli, guardOpt)
+	if err != nil {
+		return err
+	}
+
+	if len(delCloudIDs) == 0 {
+		return nil
+	}
+
 	deleteReq := &dataservice.BatchDeleteReq{
 		Filter: tools.ContainersExpression("cloud_id", delCloudIDs),
 	}
@@ -126,6 +150,11 @@ func (cli *client) deleteSubnet(kt *kit.Kit, accountID string, region string, de
 
 	logs.Infof("[%s] sync subnet to delete subnet success, accountID: %s, count: %d, rid: %s", enumor.TCloud,
 		accountID, len(delCloudIDs), kt.Rid)
+	common.RecordSyncDeleted(kt, enumor.SubnetCloudResType, len(delCloudIDs))
+
+	if err = common.ConfirmSyncDeletion(kt, cli.dbCli, guardOpt, delCloudIDs); err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -168,6 +197,7 @@ func (cli *client) updateSubnet(kt *kit.Kit, accountID string, updateMap map[str
 
 	logs.Infof("[%s] sync subnet to update subnet success, accountID: %s, count: %d, rid: %s", enumor.TCloud,
 		accountID, len(updateMap), kt.Rid)
+	common.RecordSyncUpdated(kt, enumor.SubnetCloudResType, len(updateMap))
 
 	return nil
 }
@@ -239,6 +269,7 @@ func (cli *client) createSubnet(kt *kit.Kit, accountID string, region string,
 
 	logs.Infof("[%s] sync subnet to create subnet success, accountID: %s, count: %d, rid: %s", enumor.TCloud,
 		accountID, len(addSubnet), kt.Rid)
+	common.RecordSyncCreated(kt, enumor.SubnetCloudResType, len(addSubnet))
 
 	return nil
 }
@@ -317,6 +348,7 @@ func (cli *client) listSubnetFromCloud(kt *kit.Kit, params *SyncBaseParams) ([]a
 			Limit:  adcore.TCloudQueryLimit,
 		},
 	}
+	common.RecordCloudApiCall(kt)
 	result, err := cli.cloudCli.ListSubnet(kt, opt)
 	if err != nil {
 		logs.Errorf("[%s] list subnet from cloud failed, err: %v, account: %s, opt: %v, rid: %s", enumor.TCloud, err,
@@ -396,3 +428,13 @@ func (cli *client) RemoveSubnetDeleteFromCloud(kt *kit.Kit, accountID string, re
 
 	return nil
 }
+
+// syncSubnetTag 使用云上子网标签覆盖db中的子网标签
+func (cli *client) syncSubnetTag(kt *kit.Kit, accountID string, subnetFromCloud []adtysubnet.TCloudSubnet) error {
+	tagMap := make(map[string][]cloudcore.TagPair, len(subnetFromCloud))
+	for _, one := range subnetFromCloud {
+		tagMap[one.GetCloudID()] = one.Tags
+	}
+
+	return common.SyncResourceTag(kt, cli.dbCli, enumor.TCloud, accountID, enumor.SubnetCloudResType, tagMap)
+}
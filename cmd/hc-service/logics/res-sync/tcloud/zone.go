@@ -57,6 +57,8 @@ func (cli *client) Zone(kt *kit.Kit, opt *SyncZoneOption) (*SyncResult, error) {
 		return nil, errf.NewFromErr(errf.InvalidParameter, err)
 	}
 
+	kt, stat := common.ForkSyncStat(kt)
+
 	zoneFromCloud, err := cli.listZoneFromCloud(kt, opt)
 	if err != nil {
 		return nil, err
@@ -68,11 +70,11 @@ func (cli *client) Zone(kt *kit.Kit, opt *SyncZoneOption) (*SyncResult, error) {
 	}
 
 	if len(zoneFromCloud) == 0 && len(zoneFromDB) == 0 {
-		return new(SyncResult), nil
+		return &SyncResult{Counts: stat.Counts()}, nil
 	}
 
 	addSlice, updateMap, delCloudIDs := common.Diff[typeszone.TCloudZone, corezone.BaseZone](
-		zoneFromCloud, zoneFromDB, isZoneChange)
+		kt, enumor.ZoneCloudResType, zoneFromCloud, zoneFromDB, isZoneChange)
 
 	if len(delCloudIDs) > 0 {
 		if err := cli.deleteZone(kt, opt, delCloudIDs); err != nil {
@@ -92,7 +94,7 @@ func (cli *client) Zone(kt *kit.Kit, opt *SyncZoneOption) (*SyncResult, error) {
 		}
 	}
 
-	return new(SyncResult), nil
+	return &SyncResult{Counts: stat.Counts()}, nil
 }
 
 func (cli *client) createZone(kt *kit.Kit, opt *SyncZoneOption,
@@ -128,6 +130,7 @@ func (cli *client) createZone(kt *kit.Kit, opt *SyncZoneOption,
 
 	logs.Infof("[%s] sync zone to create zone success, accountID: %s, count: %d, rid: %s", enumor.TCloud,
 		opt.AccountID, len(addSlice), kt.Rid)
+	common.RecordSyncCreated(kt, enumor.ZoneCloudResType, len(addSlice))
 
 	return nil
 }
@@ -160,6 +163,7 @@ func (cli *client) updateZone(kt *kit.Kit, opt *SyncZoneOption,
 
 	logs.Infof("[%s] sync zone to update zone success, accountID: %s, count: %d, rid: %s", enumor.TCloud,
 		opt.AccountID, len(updateMap), kt.Rid)
+	common.RecordSyncUpdated(kt, enumor.ZoneCloudResType, len(updateMap))
 
 	return nil
 }
@@ -169,6 +173,10 @@ func (cli *client) deleteZone(kt *kit.Kit, opt *SyncZoneOption, delCloudIDs []st
 		return errors.New("zone delCloudIDs is <= 0, not delete")
 	}
 
+	if common.RecordDryRunDelete(kt, enumor.ZoneCloudResType, delCloudIDs) {
+		return nil
+	}
+
 	delZoneFromCloud, err := cli.listZoneFromCloud(kt, opt)
 	if err != nil {
 		return err
@@ -199,6 +207,7 @@ func (cli *client) deleteZone(kt *kit.Kit, opt *SyncZoneOption, delCloudIDs []st
 
 	logs.Infof("[%s] sync zone to delete zone success, accountID: %s, count: %d, rid: %s", enumor.TCloud,
 		opt.AccountID, len(delCloudIDs), kt.Rid)
+	common.RecordSyncDeleted(kt, enumor.ZoneCloudResType, len(delCloudIDs))
 
 	return nil
 }
@@ -211,6 +220,7 @@ func (cli *client) listZoneFromCloud(kt *kit.Kit, opt *SyncZoneOption) ([]typesz
 	zoneOpt := &typeszone.TCloudZoneListOption{
 		Region: opt.Region,
 	}
+	common.RecordCloudApiCall(kt)
 	results, err := cli.cloudCli.ListZone(kt, zoneOpt)
 	if err != nil {
 		logs.Errorf("[%s] list zone from cloud failed, err: %v, account: %s, opt: %v, rid: %s", enumor.TCloud,
@@ -22,6 +22,7 @@ package logicsrt
 import (
 	ressync "hcm/cmd/hc-service/logics/res-sync"
 	"hcm/cmd/hc-service/logics/res-sync/aws"
+	"hcm/cmd/hc-service/logics/res-sync/common"
 	"hcm/cmd/hc-service/service/sync/handler"
 	adcore "hcm/pkg/adaptor/types/core"
 	typecore "hcm/pkg/adaptor/types/core"
@@ -87,6 +88,7 @@ func (hd *AwsRouteTableHandler) Next(kt *kit.Kit) ([]string, error) {
 		},
 	}
 
+	common.RecordCloudApiCall(kt)
 	routeTableResult, err := hd.SyncCli.CloudCli().ListRouteTable(kt, listOpt)
 	if err != nil {
 		logs.Errorf("Request adaptor list aws routeTable failed, err: %v, opt: %v, rid: %s", err, listOpt, kt.Rid)
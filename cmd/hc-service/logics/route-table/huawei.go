@@ -21,6 +21,7 @@ package logicsrt
 
 import (
 	ressync "hcm/cmd/hc-service/logics/res-sync"
+	"hcm/cmd/hc-service/logics/res-sync/common"
 	"hcm/cmd/hc-service/logics/res-sync/huawei"
 	"hcm/cmd/hc-service/service/sync/handler"
 	"hcm/pkg/adaptor/types/core"
@@ -84,6 +85,7 @@ func (hd *HuaWeiRouteTableHandler) Next(kt *kit.Kit) ([]string, error) {
 			Marker: hd.Marker,
 		},
 	}
+	common.RecordCloudApiCall(kt)
 	routeTableResult, err := hd.SyncCli.CloudCli().ListRouteTables(kt, listOpt)
 	if err != nil {
 		logs.Errorf("Request adaptor list huawei routeTable failed, err: %v, opt: %v, rid: %s", err, listOpt, kt.Rid)
@@ -75,6 +75,7 @@ func (svc *cvmSvc) BatchCreateAwsCvm(cts *rest.Contexts) (interface{}, error) {
 		CloudSubnetID:         req.CloudSubnetID,
 		BlockDeviceMapping:    req.BlockDeviceMapping,
 		PublicIPAssigned:      req.PublicIPAssigned,
+		UserData:              req.UserData,
 	}
 	result, err := awsCli.CreateCvm(cts.Kit, createOpt)
 	if err != nil {
@@ -143,6 +143,7 @@ func (svc *cvmSvc) createAzureCvm(kt *kit.Kit, azureCli *azure.Azure, req *proto
 		},
 		DataDisk:         make([]typecvm.AzureDataDisk, len(req.DataDisk)),
 		PublicIPAssigned: req.PublicIPAssigned,
+		UserData:         req.UserData,
 	}
 	for j, one := range req.DataDisk {
 		createOpt.DataDisk[j] = typecvm.AzureDataDisk{
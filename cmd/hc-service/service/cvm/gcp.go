@@ -100,6 +100,7 @@ func (svc *cvmSvc) BatchCreateGcpCvm(cts *rest.Contexts) (interface{}, error) {
 		ImageProjectType:    platform,
 		SystemDisk:          req.SystemDisk,
 		DataDisk:            req.DataDisk,
+		UserData:            req.UserData,
 	}
 	result, err := gcpCli.CreateCvm(cts.Kit, createOpt)
 	if err != nil {
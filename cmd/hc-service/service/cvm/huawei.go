@@ -133,6 +133,7 @@ func (svc *cvmSvc) BatchCreateHuaWeiCvm(cts *rest.Contexts) (interface{}, error)
 		InstanceCharge:        req.InstanceCharge,
 		PublicIPAssigned:      req.PublicIPAssigned,
 		Eip:                   req.Eip,
+		UserData:              req.UserData,
 	}
 	result, err := huawei.CreateCvm(cts.Kit, createOpt)
 	if err != nil {
@@ -128,6 +128,7 @@ func (svc *cvmSvc) BatchCreateTCloudCvm(cts *rest.Contexts) (interface{}, error)
 		DataDisk:                req.DataDisk,
 		PublicIPAssigned:        req.PublicIPAssigned,
 		InternetMaxBandwidthOut: req.InternetMaxBandwidthOut,
+		UserData:                req.UserData,
 	}
 	result, err := tcloud.CreateCvm(cts.Kit, createOpt)
 	if err != nil {
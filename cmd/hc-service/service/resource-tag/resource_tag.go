@@ -0,0 +1,270 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package resourcetag ...
+package resourcetag
+
+import (
+	"fmt"
+	"net/http"
+
+	"hcm/cmd/hc-service/logics/cloud-adaptor"
+	"hcm/cmd/hc-service/service/capability"
+	typetag "hcm/pkg/adaptor/types/tag"
+	protocloud "hcm/pkg/api/data-service/cloud"
+	hctag "hcm/pkg/api/hc-service/tag"
+	dataservice "hcm/pkg/client/data-service"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/types"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+)
+
+// InitResourceTagService initial the resource tag service.
+func InitResourceTagService(cap *capability.Capability) {
+	svc := &resTagSvc{
+		ad:      cap.CloudAdaptor,
+		dataCli: cap.ClientSet.DataService(),
+	}
+
+	h := rest.NewHandler()
+
+	h.Add("BatchUpsertResourceTag", http.MethodPost, "/vendors/{vendor}/resource_tags/batch/upsert",
+		svc.BatchUpsertResourceTag)
+	h.Add("BatchDeleteResourceTag", http.MethodDelete, "/vendors/{vendor}/resource_tags/batch",
+		svc.BatchDeleteResourceTag)
+
+	h.Load(cap.WebService)
+}
+
+type resTagSvc struct {
+	ad      *cloudadaptor.CloudAdaptorClient
+	dataCli *dataservice.Client
+}
+
+// resTagger 各云厂商资源标签操作接口
+type resTagger interface {
+	TagResource(kt *kit.Kit, opt *typetag.TagOption) error
+	UnTagResource(kt *kit.Kit, opt *typetag.UnTagOption) error
+}
+
+func (svc *resTagSvc) getResTagger(kt *kit.Kit, vendor enumor.Vendor, accountID string) (resTagger, error) {
+	switch vendor {
+	case enumor.TCloud:
+		return svc.ad.TCloud(kt, accountID)
+	case enumor.Aws:
+		return svc.ad.Aws(kt, accountID)
+	case enumor.HuaWei:
+		return svc.ad.HuaWei(kt, accountID)
+	case enumor.Azure:
+		return svc.ad.Azure(kt, accountID)
+	case enumor.Gcp:
+		return svc.ad.Gcp(kt, accountID)
+	default:
+		return nil, fmt.Errorf("vendor: %s not support tag resource", vendor)
+	}
+}
+
+// BatchUpsertResourceTag 添加或覆盖云上资源标签，并写入db。
+func (svc *resTagSvc) BatchUpsertResourceTag(cts *rest.Contexts) (interface{}, error) {
+	vendor := enumor.Vendor(cts.Request.PathParameter("vendor"))
+	if err := vendor.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	req := new(hctag.BatchUpsertTagReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	tagger, err := svc.getResTagger(cts.Kit, vendor, req.AccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	groups, err := svc.groupResForTag(cts.Kit, vendor, req.ResType, req.AccountID, req.IDs)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, group := range groups {
+		opt := &typetag.TagOption{
+			ResType:  req.ResType,
+			Region:   group.region,
+			Zone:     group.zone,
+			CloudIDs: group.cloudIDs,
+			Tags:     req.Tags,
+		}
+		if err = tagger.TagResource(cts.Kit, opt); err != nil {
+			logs.Errorf("request adaptor to tag %s %s failed, err: %v, opt: %+v, rid: %s", vendor, req.ResType,
+				err, opt, cts.Kit.Rid)
+			return nil, err
+		}
+	}
+
+	upsertReq := &protocloud.ResourceTagBatchUpsertReq{
+		ResType: req.ResType,
+		ResIDs:  req.IDs,
+		Tags:    req.Tags,
+	}
+	if err = svc.dataCli.Global.ResourceTag.BatchUpsert(cts.Kit, upsertReq); err != nil {
+		logs.Errorf("request dataservice to upsert %s tag failed, err: %v, rid: %s", req.ResType, err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// BatchDeleteResourceTag 删除云上资源标签，并同步删除db中的标签。
+func (svc *resTagSvc) BatchDeleteResourceTag(cts *rest.Contexts) (interface{}, error) {
+	vendor := enumor.Vendor(cts.Request.PathParameter("vendor"))
+	if err := vendor.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	req := new(hctag.BatchDeleteTagReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	tagger, err := svc.getResTagger(cts.Kit, vendor, req.AccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	groups, err := svc.groupResForTag(cts.Kit, vendor, req.ResType, req.AccountID, req.IDs)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, group := range groups {
+		opt := &typetag.UnTagOption{
+			ResType:  req.ResType,
+			Region:   group.region,
+			Zone:     group.zone,
+			CloudIDs: group.cloudIDs,
+			TagKeys:  req.TagKeys,
+		}
+		if err = tagger.UnTagResource(cts.Kit, opt); err != nil {
+			logs.Errorf("request adaptor to untag %s %s failed, err: %v, opt: %+v, rid: %s", vendor, req.ResType,
+				err, opt, cts.Kit.Rid)
+			return nil, err
+		}
+	}
+
+	deleteReq := &protocloud.ResourceTagBatchDeleteReq{
+		ResType: req.ResType,
+		ResIDs:  req.IDs,
+		TagKeys: req.TagKeys,
+	}
+	if err = svc.dataCli.Global.ResourceTag.BatchDelete(cts.Kit, deleteReq); err != nil {
+		logs.Errorf("request dataservice to delete %s tag failed, err: %v, rid: %s", req.ResType, err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+type resTagGroup struct {
+	region   string
+	zone     string
+	cloudIDs []string
+}
+
+// zonalResTypes 需要按可用区操作标签的资源类型（gcp 的主机和硬盘）
+var zonalResTypes = map[enumor.CloudResourceType]struct{}{
+	enumor.CvmCloudResType:  {},
+	enumor.DiskCloudResType: {},
+}
+
+// groupResForTag 按地域对资源进行分组，gcp 需要使用资源名称进行操作，且主机和硬盘需要按可用区分组。
+func (svc *resTagSvc) groupResForTag(kt *kit.Kit, vendor enumor.Vendor, resType enumor.CloudResourceType,
+	accountID string, ids []string) ([]*resTagGroup, error) {
+
+	_, zonal := zonalResTypes[resType]
+	fields := []string{"id", "cloud_id", "name", "vendor", "account_id", "region"}
+	if zonal {
+		fields = append(fields, "zone")
+	}
+
+	listReq := protocloud.ListResourceBasicInfoReq{
+		ResourceType: resType,
+		IDs:          ids,
+		Fields:       fields,
+	}
+	infoMap, err := svc.dataCli.Global.Cloud.ListResBasicInfo(kt, listReq)
+	if err != nil {
+		logs.Errorf("request dataservice list %s basic info failed, err: %v, ids: %v, rid: %s", resType, err, ids,
+			kt.Rid)
+		return nil, err
+	}
+
+	groupMap := make(map[string]*resTagGroup)
+	groups := make([]*resTagGroup, 0)
+	for _, id := range ids {
+		one, exist := infoMap[id]
+		if !exist {
+			return nil, errf.Newf(errf.RecordNotFound, "%s %s not found", resType, id)
+		}
+
+		if err = validateResForTag(one, vendor, accountID); err != nil {
+			return nil, errf.NewFromErr(errf.InvalidParameter, err)
+		}
+
+		key, cloudID := one.Region, one.CloudID
+		if vendor == enumor.Gcp {
+			cloudID = one.Name
+			if zonal {
+				key = one.Zone
+			}
+		}
+
+		group, exist := groupMap[key]
+		if !exist {
+			group = &resTagGroup{region: one.Region, zone: one.Zone, cloudIDs: make([]string, 0)}
+			groupMap[key] = group
+			groups = append(groups, group)
+		}
+		group.cloudIDs = append(group.cloudIDs, cloudID)
+	}
+
+	return groups, nil
+}
+
+func validateResForTag(one types.CloudResourceBasicInfo, vendor enumor.Vendor, accountID string) error {
+	if one.Vendor != vendor {
+		return fmt.Errorf("%s %s vendor is %s, not %s", one.ResType, one.ID, one.Vendor, vendor)
+	}
+
+	if one.AccountID != accountID {
+		return fmt.Errorf("%s %s not belong to account %s", one.ResType, one.ID, accountID)
+	}
+
+	return nil
+}
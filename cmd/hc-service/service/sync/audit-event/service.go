@@ -0,0 +1,102 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package auditevent 基于云上审计事件的增量同步服务，以及按云ID的定向同步服务
+package auditevent
+
+import (
+	"net/http"
+
+	eventsync "hcm/cmd/hc-service/logics/res-sync/event-sync"
+	"hcm/cmd/hc-service/service/capability"
+	"hcm/pkg/api/hc-service/sync"
+	"hcm/pkg/cc"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+)
+
+// InitService initial audit event sync service
+func InitService(cap *capability.Capability) {
+	source := eventsync.NewSource(cc.HCService().AuditEventSync.Source, cap.CloudAdaptor)
+	svc := &service{
+		syncer: eventsync.NewSyncer(source, cap.ResSyncCli),
+	}
+
+	h := rest.NewHandler()
+
+	h.Add("SyncByAuditEvent", http.MethodPost, "/vendors/{vendor}/audit_events/sync", svc.SyncByAuditEvent)
+	h.Add("SyncByCloudIDs", http.MethodPost, "/vendors/{vendor}/resources/sync_by_cloud_ids", svc.SyncByCloudIDs)
+
+	h.Load(cap.WebService)
+}
+
+type service struct {
+	syncer *eventsync.Syncer
+}
+
+// SyncByAuditEvent 拉取时间窗口内的云上审计事件，对事件涉及的资源进行定向同步。
+func (svc *service) SyncByAuditEvent(cts *rest.Contexts) (interface{}, error) {
+	vendor := enumor.Vendor(cts.Request.PathParameter("vendor"))
+	if err := vendor.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	req := new(sync.AuditEventSyncReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(vendor); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	result, err := svc.syncer.Sync(cts.Kit, vendor, req)
+	if err != nil {
+		logs.Errorf("sync %s by audit event failed, err: %v, req: %+v, rid: %s", vendor, err, req, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// SyncByCloudIDs 按云ID定向同步一类资源。
+func (svc *service) SyncByCloudIDs(cts *rest.Contexts) (interface{}, error) {
+	vendor := enumor.Vendor(cts.Request.PathParameter("vendor"))
+	if err := vendor.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	req := new(sync.CloudIDSyncReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(vendor); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	if err := svc.syncer.SyncByCloudIDs(cts.Kit, vendor, req); err != nil {
+		logs.Errorf("sync %s by cloud ids failed, err: %v, req: %+v, rid: %s", vendor, err, req, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
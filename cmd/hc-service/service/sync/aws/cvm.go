@@ -22,6 +22,7 @@ package aws
 import (
 	ressync "hcm/cmd/hc-service/logics/res-sync"
 	"hcm/cmd/hc-service/logics/res-sync/aws"
+	"hcm/cmd/hc-service/logics/res-sync/common"
 	"hcm/cmd/hc-service/service/sync/handler"
 	typecore "hcm/pkg/adaptor/types/core"
 	"hcm/pkg/adaptor/types/cvm"
@@ -36,7 +37,7 @@ import (
 
 // SyncCvmWithRelRes ....
 func (svc *service) SyncCvmWithRelRes(cts *rest.Contexts) (interface{}, error) {
-	return nil, handler.ResourceSync(cts, &cvmHandler{cli: svc.syncCli})
+	return handler.ResourceSync(cts, enumor.Aws, svc.dataCli, &cvmHandler{cli: svc.syncCli})
 }
 
 // cvmHandler cvm sync handler.
@@ -74,6 +75,7 @@ func (hd *cvmHandler) Next(kt *kit.Kit) ([]string, error) {
 		},
 	}
 
+	common.RecordCloudApiCall(kt)
 	cvmResult, resp, err := hd.syncCli.CloudCli().ListCvm(kt, listOpt)
 	if err != nil {
 		logs.Errorf("request adaptor list aws cvm failed, err: %v, opt: %v, rid: %s", err, listOpt, kt.Rid)
@@ -22,6 +22,7 @@ package aws
 import (
 	ressync "hcm/cmd/hc-service/logics/res-sync"
 	"hcm/cmd/hc-service/logics/res-sync/aws"
+	"hcm/cmd/hc-service/logics/res-sync/common"
 	"hcm/cmd/hc-service/service/sync/handler"
 	typecore "hcm/pkg/adaptor/types/core"
 	"hcm/pkg/adaptor/types/disk"
@@ -36,7 +37,7 @@ import (
 
 // SyncDisk ....
 func (svc *service) SyncDisk(cts *rest.Contexts) (interface{}, error) {
-	return nil, handler.ResourceSync(cts, &diskHandler{cli: svc.syncCli})
+	return handler.ResourceSync(cts, enumor.Aws, svc.dataCli, &diskHandler{cli: svc.syncCli})
 }
 
 // diskHandler disk sync handler.
@@ -74,6 +75,7 @@ func (hd *diskHandler) Next(kt *kit.Kit) ([]string, error) {
 		},
 	}
 
+	common.RecordCloudApiCall(kt)
 	diskResult, token, err := hd.syncCli.CloudCli().ListDisk(kt, listOpt)
 	if err != nil {
 		logs.Errorf("request adaptor list aws disk failed, err: %v, opt: %v, rid: %s", err, listOpt, kt.Rid)
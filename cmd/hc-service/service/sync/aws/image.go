@@ -22,6 +22,7 @@ package aws
 import (
 	ressync "hcm/cmd/hc-service/logics/res-sync"
 	"hcm/cmd/hc-service/logics/res-sync/aws"
+	"hcm/cmd/hc-service/logics/res-sync/common"
 	"hcm/cmd/hc-service/service/sync/handler"
 	typecore "hcm/pkg/adaptor/types/core"
 	"hcm/pkg/adaptor/types/image"
@@ -36,7 +37,7 @@ import (
 
 // SyncImage ....
 func (svc *service) SyncImage(cts *rest.Contexts) (interface{}, error) {
-	return nil, handler.ResourceSync(cts, &imageHandler{cli: svc.syncCli})
+	return handler.ResourceSync(cts, enumor.Aws, svc.dataCli, &imageHandler{cli: svc.syncCli})
 }
 
 // imageHandler image sync handler.
@@ -74,6 +75,7 @@ func (hd *imageHandler) Next(kt *kit.Kit) ([]string, error) {
 		},
 	}
 
+	common.RecordCloudApiCall(kt)
 	imageResult, err := hd.syncCli.CloudCli().ListImage(kt, listOpt)
 	if err != nil {
 		logs.Errorf("request adaptor list aws image failed, err: %v, opt: %v, rid: %s", err, listOpt, kt.Rid)
@@ -22,10 +22,11 @@ package aws
 import (
 	logicsrt "hcm/cmd/hc-service/logics/route-table"
 	"hcm/cmd/hc-service/service/sync/handler"
+	"hcm/pkg/criteria/enumor"
 	"hcm/pkg/rest"
 )
 
 // SyncRouteTable ....
 func (svc *service) SyncRouteTable(cts *rest.Contexts) (interface{}, error) {
-	return nil, handler.ResourceSync(cts, &logicsrt.AwsRouteTableHandler{Cli: svc.syncCli})
+	return handler.ResourceSync(cts, enumor.Aws, svc.dataCli, &logicsrt.AwsRouteTableHandler{Cli: svc.syncCli})
 }
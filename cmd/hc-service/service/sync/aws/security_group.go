@@ -22,6 +22,7 @@ package aws
 import (
 	ressync "hcm/cmd/hc-service/logics/res-sync"
 	"hcm/cmd/hc-service/logics/res-sync/aws"
+	"hcm/cmd/hc-service/logics/res-sync/common"
 	"hcm/cmd/hc-service/service/sync/handler"
 	typecore "hcm/pkg/adaptor/types/core"
 	securitygroup "hcm/pkg/adaptor/types/security-group"
@@ -36,7 +37,7 @@ import (
 
 // SyncSecurityGroup ....
 func (svc *service) SyncSecurityGroup(cts *rest.Contexts) (interface{}, error) {
-	return nil, handler.ResourceSync(cts, &sgHandler{cli: svc.syncCli})
+	return handler.ResourceSync(cts, enumor.Aws, svc.dataCli, &sgHandler{cli: svc.syncCli})
 }
 
 // sgHandler sg sync handler.
@@ -74,6 +75,7 @@ func (hd *sgHandler) Next(kt *kit.Kit) ([]string, error) {
 		},
 	}
 
+	common.RecordCloudApiCall(kt)
 	sgResult, resp, err := hd.syncCli.CloudCli().ListSecurityGroup(kt, listOpt)
 	if err != nil {
 		logs.Errorf("request adaptor list aws sg failed, err: %v, opt: %v, rid: %s", err, listOpt, kt.Rid)
@@ -21,6 +21,7 @@ package azure
 
 import (
 	"fmt"
+	"hcm/cmd/hc-service/logics/res-sync/common"
 
 	ressync "hcm/cmd/hc-service/logics/res-sync"
 	"hcm/cmd/hc-service/logics/res-sync/azure"
@@ -41,7 +42,7 @@ import (
 
 // SyncCvmWithRelRes ....
 func (svc *service) SyncCvmWithRelRes(cts *rest.Contexts) (interface{}, error) {
-	return nil, handler.ResourceSync(cts, &cvmHandler{cli: svc.syncCli})
+	return handler.ResourceSync(cts, enumor.Azure, svc.dataCli, &cvmHandler{cli: svc.syncCli})
 }
 
 // cvmHandler cvm sync handler.
@@ -88,6 +89,7 @@ func (hd *cvmHandler) Next(kt *kit.Kit) ([]string, error) {
 
 	total := make([]typecvm.AzureCvm, 0)
 	for hd.pager.More() && len(total) < constant.CloudResourceSyncMaxLimit {
+		common.RecordCloudApiCall(kt)
 		cvms, err := hd.pager.NextPage(kt)
 		if err != nil {
 			logs.Errorf("list cvm next page failed, err: %v, rid: %s", err, kt.Rid)
@@ -21,6 +21,7 @@ package azure
 
 import (
 	"fmt"
+	"hcm/cmd/hc-service/logics/res-sync/common"
 
 	ressync "hcm/cmd/hc-service/logics/res-sync"
 	"hcm/cmd/hc-service/logics/res-sync/azure"
@@ -40,7 +41,7 @@ import (
 
 // SyncNetworkInterface ....
 func (svc *service) SyncNetworkInterface(cts *rest.Contexts) (interface{}, error) {
-	return nil, handler.ResourceSync(cts, &networkInterfaceHandler{cli: svc.syncCli})
+	return handler.ResourceSync(cts, enumor.Azure, svc.dataCli, &networkInterfaceHandler{cli: svc.syncCli})
 }
 
 // networkInterfaceHandler networkInterface sync handler.
@@ -84,6 +85,7 @@ func (hd *networkInterfaceHandler) Next(kt *kit.Kit) ([]string, error) {
 
 	total := make([]typesni.AzureNI, 0)
 	for hd.pager.More() && len(total) < constant.CloudResourceSyncMaxLimit {
+		common.RecordCloudApiCall(kt)
 		result, err := hd.pager.NextPage(kt)
 		if err != nil {
 			logs.Errorf("list networkInterface next page failed, err: %v, rid: %s", err, kt.Rid)
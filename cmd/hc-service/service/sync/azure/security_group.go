@@ -21,6 +21,7 @@ package azure
 
 import (
 	"fmt"
+	"hcm/cmd/hc-service/logics/res-sync/common"
 
 	ressync "hcm/cmd/hc-service/logics/res-sync"
 	"hcm/cmd/hc-service/logics/res-sync/azure"
@@ -41,7 +42,7 @@ import (
 
 // SyncSecurityGroup ....
 func (svc *service) SyncSecurityGroup(cts *rest.Contexts) (interface{}, error) {
-	return nil, handler.ResourceSync(cts, &sgHandler{cli: svc.syncCli})
+	return handler.ResourceSync(cts, enumor.Azure, svc.dataCli, &sgHandler{cli: svc.syncCli})
 }
 
 // sgHandler sg sync handler.
@@ -88,6 +89,7 @@ func (hd *sgHandler) Next(kt *kit.Kit) ([]string, error) {
 
 	total := make([]securitygroup.AzureSecurityGroup, 0)
 	for hd.pager.More() && len(total) < constant.CloudResourceSyncMaxLimit {
+		common.RecordCloudApiCall(kt)
 		result, err := hd.pager.NextPage(kt)
 		if err != nil {
 			logs.Errorf("list sg next page failed, err: %v, rid: %s", err, kt.Rid)
@@ -21,6 +21,7 @@ package gcp
 
 import (
 	"fmt"
+	"hcm/cmd/hc-service/logics/res-sync/common"
 
 	ressync "hcm/cmd/hc-service/logics/res-sync"
 	"hcm/cmd/hc-service/logics/res-sync/gcp"
@@ -38,7 +39,7 @@ import (
 
 // SyncCvmWithRelRes ....
 func (svc *service) SyncCvmWithRelRes(cts *rest.Contexts) (interface{}, error) {
-	return nil, handler.ResourceSync(cts, &cvmHandler{cli: svc.syncCli})
+	return handler.ResourceSync(cts, enumor.Gcp, svc.dataCli, &cvmHandler{cli: svc.syncCli})
 }
 
 // cvmHandler cvm sync handler.
@@ -85,6 +86,7 @@ func (hd *cvmHandler) Next(kt *kit.Kit) ([]string, error) {
 		},
 	}
 
+	common.RecordCloudApiCall(kt)
 	cvmResult, token, err := hd.syncCli.CloudCli().ListCvm(kt, listOpt)
 	if err != nil {
 		logs.Errorf("request adaptor list gcp cvm failed, err: %v, opt: %v, rid: %s", err, listOpt, kt.Rid)
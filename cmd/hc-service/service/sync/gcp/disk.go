@@ -21,6 +21,7 @@ package gcp
 
 import (
 	"fmt"
+	"hcm/cmd/hc-service/logics/res-sync/common"
 
 	ressync "hcm/cmd/hc-service/logics/res-sync"
 	"hcm/cmd/hc-service/logics/res-sync/gcp"
@@ -38,7 +39,7 @@ import (
 
 // SyncDisk ....
 func (svc *service) SyncDisk(cts *rest.Contexts) (interface{}, error) {
-	return nil, handler.ResourceSync(cts, &diskHandler{cli: svc.syncCli})
+	return handler.ResourceSync(cts, enumor.Gcp, svc.dataCli, &diskHandler{cli: svc.syncCli})
 }
 
 // diskHandler disk sync handler.
@@ -85,6 +86,7 @@ func (hd *diskHandler) Next(kt *kit.Kit) ([]string, error) {
 		},
 	}
 
+	common.RecordCloudApiCall(kt)
 	diskResult, token, err := hd.syncCli.CloudCli().ListDisk(kt, listOpt)
 	if err != nil {
 		logs.Errorf("request adaptor list gcp disk failed, err: %v, opt: %v, rid: %s", err, listOpt, kt.Rid)
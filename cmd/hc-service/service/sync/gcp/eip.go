@@ -21,6 +21,7 @@ package gcp
 
 import (
 	ressync "hcm/cmd/hc-service/logics/res-sync"
+	"hcm/cmd/hc-service/logics/res-sync/common"
 	"hcm/cmd/hc-service/logics/res-sync/gcp"
 	"hcm/cmd/hc-service/service/sync/handler"
 	typecore "hcm/pkg/adaptor/types/core"
@@ -35,7 +36,7 @@ import (
 
 // SyncEip ....
 func (svc *service) SyncEip(cts *rest.Contexts) (interface{}, error) {
-	return nil, handler.ResourceSync(cts, &eipHandler{cli: svc.syncCli})
+	return handler.ResourceSync(cts, enumor.Gcp, svc.dataCli, &eipHandler{cli: svc.syncCli})
 }
 
 // eipHandler eip sync handler.
@@ -73,6 +74,7 @@ func (hd *eipHandler) Next(kt *kit.Kit) ([]string, error) {
 		},
 	}
 
+	common.RecordCloudApiCall(kt)
 	eipResult, err := hd.syncCli.CloudCli().ListEip(kt, listOpt)
 	if err != nil {
 		logs.Errorf("request adaptor list gcp eip failed, err: %v, opt: %v, rid: %s", err, listOpt, kt.Rid)
@@ -21,6 +21,7 @@ package gcp
 
 import (
 	"fmt"
+	"hcm/cmd/hc-service/logics/res-sync/common"
 
 	ressync "hcm/cmd/hc-service/logics/res-sync"
 	"hcm/cmd/hc-service/logics/res-sync/gcp"
@@ -38,7 +39,7 @@ import (
 
 // SyncFirewallRule ....
 func (svc *service) SyncFirewallRule(cts *rest.Contexts) (interface{}, error) {
-	return nil, handler.ResourceSync(cts, &firewallHandler{cli: svc.syncCli})
+	return handler.ResourceSync(cts, enumor.Gcp, svc.dataCli, &firewallHandler{cli: svc.syncCli})
 }
 
 // firewallHandler firewall sync handler.
@@ -84,6 +85,7 @@ func (hd *firewallHandler) Next(kt *kit.Kit) ([]string, error) {
 		},
 	}
 
+	common.RecordCloudApiCall(kt)
 	firewallResult, token, err := hd.syncCli.CloudCli().ListFirewallRule(kt, listOpt)
 	if err != nil {
 		logs.Errorf("request adaptor list gcp firewall failed, err: %v, opt: %v, rid: %s", err, listOpt, kt.Rid)
@@ -21,6 +21,7 @@ package gcp
 
 import (
 	ressync "hcm/cmd/hc-service/logics/res-sync"
+	"hcm/cmd/hc-service/logics/res-sync/common"
 	"hcm/cmd/hc-service/logics/res-sync/gcp"
 	"hcm/cmd/hc-service/service/sync/handler"
 	adaptorgcp "hcm/pkg/adaptor/gcp"
@@ -38,16 +39,23 @@ import (
 // SyncImage ....
 func (svc *service) SyncImage(cts *rest.Contexts) (interface{}, error) {
 	imageHandler := &imageHandler{cli: svc.syncCli}
+	var dryRun *common.DryRunResult
 	for index, projectID := range adaptorgcp.PublicImagePlatforms {
 		imageHandler.index = index
 		imageHandler.projectID = projectID
-		err := handler.ResourceSync(cts, imageHandler)
+		result, err := handler.ResourceSync(cts, enumor.Gcp, svc.dataCli, imageHandler)
 		if err != nil {
 			return nil, err
 		}
+
+		if dryRun == nil {
+			dryRun = result
+			continue
+		}
+		dryRun.Merge(result)
 	}
 
-	return nil, nil
+	return dryRun, nil
 }
 
 // imageHandler image sync handler.
@@ -99,6 +107,7 @@ func (hd *imageHandler) Next(kt *kit.Kit) ([]string, error) {
 			PageSize:  constant.CloudResourceSyncMaxLimit,
 		},
 	}
+	common.RecordCloudApiCall(kt)
 	imageResult, token, err := hd.syncCli.CloudCli().ListImage(kt, listOpt)
 	if err != nil {
 		logs.Errorf("request adaptor list gcp disk failed, err: %v, opt: %v, rid: %s", err, listOpt, kt.Rid)
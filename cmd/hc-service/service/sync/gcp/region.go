@@ -21,6 +21,7 @@ package gcp
 
 import (
 	ressync "hcm/cmd/hc-service/logics/res-sync"
+	"hcm/cmd/hc-service/logics/res-sync/common"
 	"hcm/cmd/hc-service/logics/res-sync/gcp"
 	"hcm/cmd/hc-service/service/sync/handler"
 	typecore "hcm/pkg/adaptor/types/core"
@@ -35,7 +36,7 @@ import (
 
 // SyncRegion ....
 func (svc *service) SyncRegion(cts *rest.Contexts) (interface{}, error) {
-	return nil, handler.ResourceSync(cts, &regionHandler{cli: svc.syncCli})
+	return handler.ResourceSync(cts, enumor.Gcp, svc.dataCli, &regionHandler{cli: svc.syncCli})
 }
 
 // regionHandler region sync handler.
@@ -81,6 +82,7 @@ func (hd *regionHandler) Next(kt *kit.Kit) ([]string, error) {
 		},
 	}
 
+	common.RecordCloudApiCall(kt)
 	regionResult, err := hd.syncCli.CloudCli().ListRegion(kt, listOpt)
 	if err != nil {
 		logs.Errorf("request adaptor list gcp region failed, err: %v, opt: %v, rid: %s", err, listOpt, kt.Rid)
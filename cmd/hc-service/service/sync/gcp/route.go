@@ -21,6 +21,7 @@ package gcp
 
 import (
 	ressync "hcm/cmd/hc-service/logics/res-sync"
+	"hcm/cmd/hc-service/logics/res-sync/common"
 	"hcm/cmd/hc-service/logics/res-sync/gcp"
 	"hcm/cmd/hc-service/service/sync/handler"
 	typecore "hcm/pkg/adaptor/types/core"
@@ -36,7 +37,7 @@ import (
 
 // SyncRoute ....
 func (svc *service) SyncRoute(cts *rest.Contexts) (interface{}, error) {
-	return nil, handler.ResourceSync(cts, &routeHandler{cli: svc.syncCli})
+	return handler.ResourceSync(cts, enumor.Gcp, svc.dataCli, &routeHandler{cli: svc.syncCli})
 }
 
 // routeHandler route sync handler.
@@ -81,6 +82,7 @@ func (hd *routeHandler) Next(kt *kit.Kit) ([]string, error) {
 			PageSize:  constant.CloudResourceSyncMaxLimit,
 		},
 	}
+	common.RecordCloudApiCall(kt)
 	routeResult, err := hd.syncCli.CloudCli().ListRoute(kt, listOpt)
 	if err != nil {
 		logs.Errorf("request adaptor list gcp route failed, err: %v, opt: %v, rid: %s", err, listOpt, kt.Rid)
@@ -21,11 +21,19 @@
 package handler
 
 import (
+	"time"
+
+	"hcm/cmd/hc-service/logics/res-sync/common"
+	dssync "hcm/pkg/api/data-service/cloud/sync"
+	dataservice "hcm/pkg/client/data-service"
 	"hcm/pkg/criteria/constant"
 	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/table/types"
 	"hcm/pkg/kit"
 	"hcm/pkg/logs"
 	"hcm/pkg/rest"
+	ttimes "hcm/pkg/tools/times"
 )
 
 // Handler 定义了全量同步操作函数。
@@ -42,26 +50,57 @@ type Handler interface {
 	Name() enumor.CloudResourceType
 }
 
-// ResourceSync 资源同步流程。
-func ResourceSync(cts *rest.Contexts, handler Handler) error {
-	kt := cts.Kit
+// ResourceSync 资源同步流程，同步结束后将本次同步的资源变更数量、耗时、云API调用次数记录到同步历史中。
+// 请求体中 dry_run 为 true 时为预览模式，只对比云上和db数据，不写db也不记录同步历史，返回对比出的资源差异。
+func ResourceSync(cts *rest.Contexts, vendor enumor.Vendor, dataCli *dataservice.Client, handler Handler) (
+	dryRun *common.DryRunResult, err error) {
+
+	scope := new(syncScope)
+	if err = cts.ReDecodeInto(scope); err != nil {
+		return nil, err
+	}
+
+	stat := common.NewSyncStat()
+	kt := common.WithSyncStat(cts.Kit, stat)
+	if scope.DryRun {
+		dryRun = common.NewDryRunResult()
+		kt = common.WithDryRun(kt, dryRun)
+	}
+
+	start := time.Now()
+	defer func() {
+		if scope.DryRun {
+			return
+		}
+
+		// 解除本次同步中云上重新查询到的资源的删除隔离，失败时等待下次同步重试，不影响同步结果
+		if len(scope.AccountID) != 0 {
+			if releaseErr := common.ReleaseSyncQuarantine(kt, dataCli, scope.AccountID); releaseErr != nil {
+				logs.Errorf("%s release sync quarantine failed, err: %v, account: %s, rid: %s", handler.Name(),
+					releaseErr, scope.AccountID, kt.Rid)
+			}
+		}
+
+		recordSyncHistory(kt, dataCli, vendor, scope, handler.Name(), stat, start, err)
+	}()
 
 	// 解析请求参数到handler实现中，构建同步需要的客户端
-	if err := handler.Prepare(cts); err != nil {
+	if err = handler.Prepare(cts); err != nil {
 		logs.Errorf("%s sync handler to prepare failed, err: %v, rid: %s", handler.Name(), err, kt.Rid)
-		return err
+		return nil, err
 	}
 
-	if err := handler.RemoveDeleteFromCloud(kt); err != nil {
+	if err = handler.RemoveDeleteFromCloud(kt); err != nil {
 		logs.Errorf("%s sync handler to removeDeleteFromCloud failed, err: %v, rid: %s", handler.Name(), err, kt.Rid)
-		return err
+		return nil, err
 	}
 
 	for {
-		cloudIDs, err := handler.Next(kt)
+		var cloudIDs []string
+		cloudIDs, err = handler.Next(kt)
 		if err != nil {
 			logs.Errorf("%s sync handler to next failed, err: %v, rid: %s", handler.Name(), err, kt.Rid)
-			return err
+			return nil, err
 		}
 
 		if len(cloudIDs) == 0 {
@@ -70,7 +109,7 @@ func ResourceSync(cts *rest.Contexts, handler Handler) error {
 
 		if err = handler.Sync(kt, cloudIDs); err != nil {
 			logs.Errorf("%s sync handler to sync failed, err: %v, rid: %s", handler.Name(), err, kt.Rid)
-			return err
+			return nil, err
 		}
 
 		if len(cloudIDs) < constant.CloudResourceSyncMaxLimit {
@@ -78,5 +117,91 @@ func ResourceSync(cts *rest.Contexts, handler Handler) error {
 		}
 	}
 
-	return nil
+	return dryRun, nil
+}
+
+// syncScope 同步请求中的账号、地域信息以及是否为预览模式，不同云的同步请求以 region、zone 或 resource_group_name 划分同步范围。
+type syncScope struct {
+	AccountID         string `json:"account_id"`
+	Region            string `json:"region"`
+	Zone              string `json:"zone"`
+	ResourceGroupName string `json:"resource_group_name"`
+	DryRun            bool   `json:"dry_run"`
+}
+
+// region 返回同步范围对应的地域。
+func (s *syncScope) region() string {
+	switch {
+	case len(s.Region) != 0:
+		return s.Region
+	case len(s.Zone) != 0:
+		return s.Zone
+	default:
+		return s.ResourceGroupName
+	}
+}
+
+// recordSyncHistory 记录同步历史，记录失败不影响同步结果。
+func recordSyncHistory(kt *kit.Kit, dataCli *dataservice.Client, vendor enumor.Vendor, scope *syncScope,
+	resType enumor.CloudResourceType, stat *common.SyncStat, start time.Time, syncErr error) {
+
+	if len(scope.AccountID) == 0 {
+		return
+	}
+
+	items := buildSyncHistory(kt, vendor, scope, resType, stat, start, syncErr)
+	req := &dssync.HistoryCreateReq{Items: items}
+	if _, err := dataCli.Global.AccountSyncHistory.BatchCreate(kt, req); err != nil {
+		logs.Errorf("%s create account sync history failed, err: %v, items: %+v, rid: %s", resType, err, items,
+			kt.Rid)
+	}
+}
+
+// buildSyncHistory 按资源类型构建同步历史，同步的资源类型以及同步过程中关联同步的其他资源类型各记录一条，
+// 云API调用次数只记录在同步的资源类型上，避免重复统计。
+func buildSyncHistory(kt *kit.Kit, vendor enumor.Vendor, scope *syncScope, resType enumor.CloudResourceType,
+	stat *common.SyncStat, start time.Time, syncErr error) []dssync.HistoryCreateField {
+
+	resTypes := []enumor.CloudResourceType{resType}
+	for _, one := range stat.ResTypes() {
+		if one != resType {
+			resTypes = append(resTypes, one)
+		}
+	}
+
+	base := dssync.HistoryCreateField{
+		Vendor:    vendor,
+		AccountID: scope.AccountID,
+		Region:    scope.region(),
+		SyncRid:   kt.Rid,
+		Status:    enumor.SyncSuccess,
+		CostMs:    time.Since(start).Milliseconds(),
+		StartTime: ttimes.ConvStdTimeFormat(start),
+	}
+
+	if syncErr != nil {
+		base.Status = enumor.SyncFailed
+		if ef := errf.Error(syncErr); ef != nil && ef.Code == errf.Unknown {
+			base.FailedReason, _ = types.NewJsonField(ef.Message)
+		} else {
+			base.FailedReason, _ = types.NewJsonField(syncErr)
+		}
+	}
+
+	items := make([]dssync.HistoryCreateField, 0, len(resTypes))
+	for _, one := range resTypes {
+		count := stat.Count(one)
+		item := base
+		item.ResName = string(one)
+		item.CreatedCount = int64(count.CreatedCount)
+		item.UpdatedCount = int64(count.UpdatedCount)
+		item.DeletedCount = int64(count.DeletedCount)
+		item.QuarantinedCount = int64(count.QuarantinedCount)
+		if one == resType {
+			item.CloudApiCount = int64(stat.CloudApiCount())
+		}
+		items = append(items, item)
+	}
+
+	return items
 }
@@ -0,0 +1,79 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package handler
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"hcm/cmd/hc-service/logics/res-sync/common"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/kit"
+)
+
+func TestBuildSyncHistory(t *testing.T) {
+	stat := common.NewSyncStat()
+	kt := common.WithSyncStat(kit.New(), stat)
+	common.RecordSyncCreated(kt, enumor.DiskCloudResType, 2)
+	common.RecordSyncUpdated(kt, enumor.CvmCloudResType, 1)
+	common.RecordSyncDeleted(kt, enumor.EipCloudResType, 3)
+	common.RecordCloudApiCall(kt)
+	common.RecordCloudApiCall(kt)
+
+	scope := &syncScope{AccountID: "account", Zone: "us-central1-a"}
+	items := buildSyncHistory(kt, enumor.Gcp, scope, enumor.CvmCloudResType, stat, time.Now(), nil)
+	if len(items) != 3 {
+		t.Fatalf("one history should be built for each res type, items: %+v", items)
+	}
+
+	expects := []struct {
+		resName  enumor.CloudResourceType
+		created  int64
+		updated  int64
+		deleted  int64
+		apiCount int64
+	}{
+		{resName: enumor.CvmCloudResType, updated: 1, apiCount: 2},
+		{resName: enumor.DiskCloudResType, created: 2},
+		{resName: enumor.EipCloudResType, deleted: 3},
+	}
+	for idx, expect := range expects {
+		item := items[idx]
+		if item.ResName != string(expect.resName) || item.CreatedCount != expect.created ||
+			item.UpdatedCount != expect.updated || item.DeletedCount != expect.deleted ||
+			item.CloudApiCount != expect.apiCount {
+			t.Errorf("unexpected history %d: %+v", idx, item)
+		}
+
+		if item.AccountID != "account" || item.Region != "us-central1-a" || item.SyncRid != kt.Rid ||
+			item.Status != enumor.SyncSuccess {
+			t.Errorf("history %d should share the sync scope, item: %+v", idx, item)
+		}
+	}
+
+	// 同步的资源类型没有变更时也需要记录一条同步历史
+	failed := buildSyncHistory(kit.New(), enumor.Gcp, scope, enumor.VpcCloudResType, common.NewSyncStat(),
+		time.Now(), errors.New("list vpc failed"))
+	if len(failed) != 1 || failed[0].ResName != string(enumor.VpcCloudResType) ||
+		failed[0].Status != enumor.SyncFailed || len(failed[0].FailedReason) == 0 {
+		t.Errorf("unexpected failed history: %+v", failed)
+	}
+}
@@ -21,6 +21,7 @@ package huawei
 
 import (
 	ressync "hcm/cmd/hc-service/logics/res-sync"
+	"hcm/cmd/hc-service/logics/res-sync/common"
 	"hcm/cmd/hc-service/logics/res-sync/huawei"
 	"hcm/cmd/hc-service/service/sync/handler"
 	"hcm/pkg/adaptor/types/core"
@@ -35,7 +36,7 @@ import (
 
 // SyncCvmWithRelRes ....
 func (svc *service) SyncCvmWithRelRes(cts *rest.Contexts) (interface{}, error) {
-	return nil, handler.ResourceSync(cts, &cvmHandler{cli: svc.syncCli})
+	return handler.ResourceSync(cts, enumor.HuaWei, svc.dataCli, &cvmHandler{cli: svc.syncCli})
 }
 
 // cvmHandler cvm sync handler.
@@ -73,6 +74,7 @@ func (hd *cvmHandler) Next(kt *kit.Kit) ([]string, error) {
 			Limit:  int32(constant.CloudResourceSyncMaxLimit),
 		},
 	}
+	common.RecordCloudApiCall(kt)
 	cvmResult, err := hd.syncCli.CloudCli().ListCvm(kt, listOpt)
 	if err != nil {
 		logs.Errorf("request adaptor list huawei cvm failed, err: %v, opt: %v, rid: %s", err, listOpt, kt.Rid)
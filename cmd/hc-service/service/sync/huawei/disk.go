@@ -21,6 +21,7 @@ package huawei
 
 import (
 	ressync "hcm/cmd/hc-service/logics/res-sync"
+	"hcm/cmd/hc-service/logics/res-sync/common"
 	"hcm/cmd/hc-service/logics/res-sync/huawei"
 	"hcm/cmd/hc-service/service/sync/handler"
 	"hcm/pkg/adaptor/types/core"
@@ -36,7 +37,7 @@ import (
 
 // SyncDisk ....
 func (svc *service) SyncDisk(cts *rest.Contexts) (interface{}, error) {
-	return nil, handler.ResourceSync(cts, &diskHandler{cli: svc.syncCli})
+	return handler.ResourceSync(cts, enumor.HuaWei, svc.dataCli, &diskHandler{cli: svc.syncCli})
 }
 
 // diskHandler disk sync handler.
@@ -75,6 +76,7 @@ func (hd *diskHandler) Next(kt *kit.Kit) ([]string, error) {
 		},
 	}
 
+	common.RecordCloudApiCall(kt)
 	diskResult, err := hd.syncCli.CloudCli().ListDisk(kt, listOpt)
 	if err != nil {
 		logs.Errorf("request adaptor list huawei disk failed, err: %v, opt: %v, rid: %s", err, listOpt, kt.Rid)
@@ -21,6 +21,7 @@ package huawei
 
 import (
 	ressync "hcm/cmd/hc-service/logics/res-sync"
+	"hcm/cmd/hc-service/logics/res-sync/common"
 	"hcm/cmd/hc-service/logics/res-sync/huawei"
 	"hcm/cmd/hc-service/service/sync/handler"
 	adaptorhuawei "hcm/pkg/adaptor/huawei"
@@ -40,16 +41,23 @@ import (
 // SyncImage ....
 func (svc *service) SyncImage(cts *rest.Contexts) (interface{}, error) {
 	imageHandler := &imageHandler{cli: svc.syncCli}
+	var dryRun *common.DryRunResult
 	for index, platform := range adaptorhuawei.PublicImagePlatforms {
 		imageHandler.index = index
 		imageHandler.platform = platform
-		err := handler.ResourceSync(cts, imageHandler)
+		result, err := handler.ResourceSync(cts, enumor.HuaWei, svc.dataCli, imageHandler)
 		if err != nil {
 			return nil, err
 		}
+
+		if dryRun == nil {
+			dryRun = result
+			continue
+		}
+		dryRun.Merge(result)
 	}
 
-	return nil, nil
+	return dryRun, nil
 }
 
 // imageHandler image sync handler.
@@ -96,6 +104,7 @@ func (hd *imageHandler) Next(kt *kit.Kit) ([]string, error) {
 		},
 	}
 
+	common.RecordCloudApiCall(kt)
 	imageResult, err := hd.syncCli.CloudCli().ListImage(kt, listOpt)
 	if err != nil {
 		logs.Errorf("request adaptor list huawei image failed, err: %v, opt: %v, rid: %s", err, listOpt, kt.Rid)
@@ -21,6 +21,7 @@ package huawei
 
 import (
 	ressync "hcm/cmd/hc-service/logics/res-sync"
+	"hcm/cmd/hc-service/logics/res-sync/common"
 	"hcm/cmd/hc-service/logics/res-sync/huawei"
 	"hcm/cmd/hc-service/service/sync/handler"
 	"hcm/pkg/adaptor/types/core"
@@ -36,7 +37,7 @@ import (
 
 // SyncSecurityGroup ....
 func (svc *service) SyncSecurityGroup(cts *rest.Contexts) (interface{}, error) {
-	return nil, handler.ResourceSync(cts, &sgHandler{cli: svc.syncCli})
+	return handler.ResourceSync(cts, enumor.HuaWei, svc.dataCli, &sgHandler{cli: svc.syncCli})
 }
 
 // sgHandler sg sync handler.
@@ -75,6 +76,7 @@ func (hd *sgHandler) Next(kt *kit.Kit) ([]string, error) {
 		},
 	}
 
+	common.RecordCloudApiCall(kt)
 	sgResult, _, err := hd.syncCli.CloudCli().ListSecurityGroup(kt, listOpt)
 	if err != nil {
 		logs.Errorf("request adaptor list huawei sg failed, err: %v, opt: %v, rid: %s", err, listOpt, kt.Rid)
@@ -21,6 +21,7 @@ package sync
 
 import (
 	"hcm/cmd/hc-service/service/capability"
+	auditevent "hcm/cmd/hc-service/service/sync/audit-event"
 	"hcm/cmd/hc-service/service/sync/aws"
 	"hcm/cmd/hc-service/service/sync/azure"
 	"hcm/cmd/hc-service/service/sync/gcp"
@@ -35,4 +36,5 @@ func InitService(cap *capability.Capability) {
 	gcp.InitService(cap)
 	huawei.InitService(cap)
 	azure.InitService(cap)
+	auditevent.InitService(cap)
 }
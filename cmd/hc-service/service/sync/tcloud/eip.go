@@ -21,6 +21,7 @@ package tcloud
 
 import (
 	ressync "hcm/cmd/hc-service/logics/res-sync"
+	"hcm/cmd/hc-service/logics/res-sync/common"
 	"hcm/cmd/hc-service/logics/res-sync/tcloud"
 	"hcm/cmd/hc-service/service/sync/handler"
 	typecore "hcm/pkg/adaptor/types/core"
@@ -35,7 +36,7 @@ import (
 
 // SyncEip ....
 func (svc *service) SyncEip(cts *rest.Contexts) (interface{}, error) {
-	return nil, handler.ResourceSync(cts, &eipHandler{cli: svc.syncCli})
+	return handler.ResourceSync(cts, enumor.TCloud, svc.dataCli, &eipHandler{cli: svc.syncCli})
 }
 
 // eipHandler eip sync handler.
@@ -72,6 +73,7 @@ func (hd *eipHandler) Next(kt *kit.Kit) ([]string, error) {
 			Limit:  constant.CloudResourceSyncMaxLimit,
 		},
 	}
+	common.RecordCloudApiCall(kt)
 	eipResult, err := hd.syncCli.CloudCli().ListEip(kt, listOpt)
 	if err != nil {
 		logs.Errorf("request adaptor list tcloud eip failed, err: %v, opt: %v, rid: %s", err, listOpt, kt.Rid)
@@ -21,6 +21,7 @@ package tcloud
 
 import (
 	ressync "hcm/cmd/hc-service/logics/res-sync"
+	"hcm/cmd/hc-service/logics/res-sync/common"
 	"hcm/cmd/hc-service/logics/res-sync/tcloud"
 	"hcm/cmd/hc-service/service/sync/handler"
 	typecore "hcm/pkg/adaptor/types/core"
@@ -34,7 +35,7 @@ import (
 
 // SyncImage ....
 func (svc *service) SyncImage(cts *rest.Contexts) (interface{}, error) {
-	return nil, handler.ResourceSync(cts, &imageHandler{cli: svc.syncCli})
+	return handler.ResourceSync(cts, enumor.TCloud, svc.dataCli, &imageHandler{cli: svc.syncCli})
 }
 
 // imageHandler image sync handler.
@@ -72,6 +73,7 @@ func (hd *imageHandler) Next(kt *kit.Kit) ([]string, error) {
 		},
 	}
 
+	common.RecordCloudApiCall(kt)
 	imageResult, err := hd.syncCli.CloudCli().ListImage(kt, listOpt)
 	if err != nil {
 		logs.Errorf("request adaptor list tcloud image failed, err: %v, opt: %v, rid: %s", err, listOpt, kt.Rid)
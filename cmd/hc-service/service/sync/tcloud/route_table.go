@@ -21,6 +21,7 @@ package tcloud
 
 import (
 	ressync "hcm/cmd/hc-service/logics/res-sync"
+	"hcm/cmd/hc-service/logics/res-sync/common"
 	"hcm/cmd/hc-service/logics/res-sync/tcloud"
 	"hcm/cmd/hc-service/service/sync/handler"
 	typecore "hcm/pkg/adaptor/types/core"
@@ -33,7 +34,7 @@ import (
 
 // SyncRouteTable ....
 func (svc *service) SyncRouteTable(cts *rest.Contexts) (interface{}, error) {
-	return nil, handler.ResourceSync(cts, &routeTableHandler{cli: svc.syncCli})
+	return handler.ResourceSync(cts, enumor.TCloud, svc.dataCli, &routeTableHandler{cli: svc.syncCli})
 }
 
 // routeTableHandler routeTable sync handler.
@@ -71,6 +72,7 @@ func (hd *routeTableHandler) Next(kt *kit.Kit) ([]string, error) {
 		},
 	}
 
+	common.RecordCloudApiCall(kt)
 	routeTableResult, err := hd.syncCli.CloudCli().ListRouteTable(kt, listOpt)
 	if err != nil {
 		logs.Errorf("request adaptor list tcloud routeTable failed, err: %v, opt: %v, rid: %s", err, listOpt, kt.Rid)
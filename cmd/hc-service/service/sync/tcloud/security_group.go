@@ -21,6 +21,7 @@ package tcloud
 
 import (
 	ressync "hcm/cmd/hc-service/logics/res-sync"
+	"hcm/cmd/hc-service/logics/res-sync/common"
 	"hcm/cmd/hc-service/logics/res-sync/tcloud"
 	"hcm/cmd/hc-service/service/sync/handler"
 	typecore "hcm/pkg/adaptor/types/core"
@@ -35,7 +36,7 @@ import (
 
 // SyncSecurityGroup ....
 func (svc *service) SyncSecurityGroup(cts *rest.Contexts) (interface{}, error) {
-	return nil, handler.ResourceSync(cts, &sgHandler{cli: svc.syncCli})
+	return handler.ResourceSync(cts, enumor.TCloud, svc.dataCli, &sgHandler{cli: svc.syncCli})
 }
 
 // sgHandler sg sync handler.
@@ -73,6 +74,7 @@ func (hd *sgHandler) Next(kt *kit.Kit) ([]string, error) {
 		},
 	}
 
+	common.RecordCloudApiCall(kt)
 	sgResult, err := hd.syncCli.CloudCli().ListSecurityGroupNew(kt, listOpt)
 	if err != nil {
 		logs.Errorf("request adaptor list tcloud sg failed, err: %v, opt: %v, rid: %s", err, listOpt, kt.Rid)
@@ -33,6 +33,7 @@ import (
 	"hcm/pkg/api/hc-service/sync"
 	hcservice "hcm/pkg/api/hc-service/vpc"
 	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/enumor"
 	"hcm/pkg/criteria/errf"
 	"hcm/pkg/dal/dao/tools"
 	"hcm/pkg/logs"
@@ -105,7 +106,7 @@ func (v vpc) AwsVpcCreate(cts *rest.Contexts) (interface{}, error) {
 		return nil, err
 	}
 
-	err = handler.ResourceSync(cts, &logicsrt.AwsRouteTableHandler{
+	_, err = handler.ResourceSync(cts, enumor.Aws, v.cs.DataService(), &logicsrt.AwsRouteTableHandler{
 		DisablePrepare: true,
 		Cli:            v.syncCli,
 		Request: &sync.AwsSyncReq{
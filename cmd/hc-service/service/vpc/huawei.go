@@ -123,7 +123,7 @@ func (v vpc) HuaWeiVpcCreate(cts *rest.Contexts) (interface{}, error) {
 		return nil, err
 	}
 
-	err = handler.ResourceSync(cts, &logicsrt.HuaWeiRouteTableHandler{
+	_, err = handler.ResourceSync(cts, enumor.HuaWei, v.cs.DataService(), &logicsrt.HuaWeiRouteTableHandler{
 		DisablePrepare: true,
 		Cli:            v.syncCli,
 		Request: &sync.HuaWeiSyncReq{
@@ -45,14 +45,16 @@ var _ action.ParameterAction = new(CreateCvmAction)
 // CreateCvmAction define create cvm action.
 type CreateCvmAction struct{}
 
-// CreateOption define create cvm option.
+// CreateOption define create cvm option, only the request of Vendor takes effect.
+// 各云厂商的请求不使用内嵌结构体，避免同名字段的json标签冲突，序列化时由 MarshalJSON/UnmarshalJSON
+// 根据 Vendor 平铺对应云厂商的请求。
 type CreateOption struct {
-	Vendor                     enumor.Vendor `json:"vendor" validate:"required"`
-	hccvm.TCloudBatchCreateReq `json:",inline"`
-	hccvm.AwsBatchCreateReq    `json:",inline"`
-	hccvm.HuaWeiBatchCreateReq `json:",inline"`
-	hccvm.GcpBatchCreateReq    `json:",inline"`
-	hccvm.AzureCreateReq       `json:",inline"`
+	Vendor               enumor.Vendor              `json:"vendor" validate:"required"`
+	TCloudBatchCreateReq hccvm.TCloudBatchCreateReq `json:"-"`
+	AwsBatchCreateReq    hccvm.AwsBatchCreateReq    `json:"-"`
+	HuaWeiBatchCreateReq hccvm.HuaWeiBatchCreateReq `json:"-"`
+	GcpBatchCreateReq    hccvm.GcpBatchCreateReq    `json:"-"`
+	AzureCreateReq       hccvm.AzureCreateReq       `json:"-"`
 }
 
 // MarshalJSON CreateOption.
@@ -26,6 +26,7 @@ import (
 	actionfirewall "hcm/cmd/task-server/logics/action/firewall"
 	actionsg "hcm/cmd/task-server/logics/action/security-group"
 	actionsubnet "hcm/cmd/task-server/logics/action/subnet"
+	actionsync "hcm/cmd/task-server/logics/action/sync"
 	"hcm/pkg/async/action"
 	"hcm/pkg/client"
 )
@@ -52,4 +53,8 @@ func register() {
 	action.RegisterAction(actionsg.CreateHuaweiSGRuleAction{})
 	action.RegisterAction(actioneip.DeleteEIPAction{})
 
+	action.RegisterAction(actionsync.SyncResourceAction{})
+	action.RegisterAction(actionsync.SyncByCloudIDsAction{})
+	action.RegisterTpl(actionsync.SyncAccountTpl)
+
 }
@@ -0,0 +1,105 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package actionsync
+
+import (
+	"fmt"
+
+	actcli "hcm/cmd/task-server/logics/action/cli"
+	hcsync "hcm/pkg/api/hc-service/sync"
+	"hcm/pkg/async/action"
+	"hcm/pkg/async/action/run"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/criteria/validator"
+	"hcm/pkg/logs"
+)
+
+var _ action.Action = new(SyncByCloudIDsAction)
+var _ action.ParameterAction = new(SyncByCloudIDsAction)
+var _ action.RollbackAction = new(SyncByCloudIDsAction)
+
+// SyncByCloudIDsAction 按云ID定向同步账号下的一类资源
+type SyncByCloudIDsAction struct{}
+
+// SyncByCloudIDsOption sync resource by cloud ids option.
+type SyncByCloudIDsOption struct {
+	Vendor                enumor.Vendor `json:"vendor" validate:"required"`
+	hcsync.CloudIDSyncReq `json:",inline"`
+}
+
+// Validate SyncByCloudIDsOption.
+func (opt SyncByCloudIDsOption) Validate() error {
+	if err := validator.Validate.Struct(opt); err != nil {
+		return err
+	}
+
+	return opt.CloudIDSyncReq.Validate(opt.Vendor)
+}
+
+// ParameterNew return sync resource by cloud ids params.
+func (act SyncByCloudIDsAction) ParameterNew() (params interface{}) {
+	return new(SyncByCloudIDsOption)
+}
+
+// Name return action name.
+func (act SyncByCloudIDsAction) Name() enumor.ActionName {
+	return enumor.ActionSyncResourceByCloudIDs
+}
+
+// Run sync resource by cloud ids.
+func (act SyncByCloudIDsAction) Run(kt run.ExecuteKit, params interface{}) (interface{}, error) {
+	opt, ok := params.(*SyncByCloudIDsOption)
+	if !ok {
+		return nil, errf.New(errf.InvalidParameter, "params type mismatch")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return nil, err
+	}
+
+	hcCli := actcli.GetHCService()
+	var err error
+	switch opt.Vendor {
+	case enumor.TCloud:
+		err = hcCli.TCloud.ResourceSync.SyncByCloudIDs(kt.Kit(), &opt.CloudIDSyncReq)
+	case enumor.Aws:
+		err = hcCli.Aws.ResourceSync.SyncByCloudIDs(kt.Kit(), &opt.CloudIDSyncReq)
+	case enumor.HuaWei:
+		err = hcCli.HuaWei.ResourceSync.SyncByCloudIDs(kt.Kit(), &opt.CloudIDSyncReq)
+	case enumor.Gcp:
+		err = hcCli.Gcp.ResourceSync.SyncByCloudIDs(kt.Kit(), &opt.CloudIDSyncReq)
+	case enumor.Azure:
+		err = hcCli.Azure.ResourceSync.SyncByCloudIDs(kt.Kit(), &opt.CloudIDSyncReq)
+	default:
+		return nil, fmt.Errorf("vendor: %s not support", opt.Vendor)
+	}
+	if err != nil {
+		logs.Errorf("sync resource by cloud ids failed, err: %v, opt: %+v, rid: %s", err, opt, kt.Kit().Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// Rollback 定向同步是幂等操作，重试前无需回滚。
+func (act SyncByCloudIDsAction) Rollback(_ run.ExecuteKit, _ interface{}) error {
+	return nil
+}
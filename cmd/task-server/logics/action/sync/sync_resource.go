@@ -0,0 +1,124 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+package actionsync
+
+import (
+	"fmt"
+
+	"hcm/cmd/cloud-server/service/sync/aws"
+	"hcm/cmd/cloud-server/service/sync/azure"
+	"hcm/cmd/cloud-server/service/sync/detail"
+	"hcm/cmd/cloud-server/service/sync/gcp"
+	"hcm/cmd/cloud-server/service/sync/huawei"
+	"hcm/cmd/cloud-server/service/sync/tcloud"
+	actcli "hcm/cmd/task-server/logics/action/cli"
+	"hcm/pkg/async/action"
+	"hcm/pkg/async/action/run"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/criteria/validator"
+	"hcm/pkg/logs"
+	"hcm/pkg/tools/converter"
+)
+
+var _ action.Action = new(SyncResourceAction)
+var _ action.ParameterAction = new(SyncResourceAction)
+var _ action.RollbackAction = new(SyncResourceAction)
+
+// SyncResourceAction 同步账号下指定地域的一类资源
+type SyncResourceAction struct{}
+
+// SyncResourceOption sync resource option.
+type SyncResourceOption struct {
+	Vendor    enumor.Vendor            `json:"vendor" validate:"required"`
+	AccountID string                   `json:"account_id" validate:"required"`
+	ResType   enumor.CloudResourceType `json:"res_type" validate:"required"`
+	// Region 同步的地域，azure 为资源组名称，为空表示该资源不区分地域
+	Region string `json:"region" validate:"omitempty"`
+	// FencingToken 创建任务流时持有的账号同步锁 fencing token，同步请求会携带该 token
+	FencingToken string `json:"fencing_token,omitempty" validate:"omitempty"`
+}
+
+// Validate SyncResourceOption.
+func (opt SyncResourceOption) Validate() error {
+	return validator.Validate.Struct(opt)
+}
+
+// ParameterNew return sync resource params.
+func (act SyncResourceAction) ParameterNew() (params interface{}) {
+	return new(SyncResourceOption)
+}
+
+// Name return action name.
+func (act SyncResourceAction) Name() enumor.ActionName {
+	return enumor.ActionSyncAccountResource
+}
+
+// Run sync resource.
+func (act SyncResourceAction) Run(kt run.ExecuteKit, params interface{}) (interface{}, error) {
+	opt, ok := params.(*SyncResourceOption)
+	if !ok {
+		return nil, errf.New(errf.InvalidParameter, "params type mismatch")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return nil, err
+	}
+
+	syncKt := kt.Kit()
+	if len(opt.FencingToken) != 0 {
+		syncKt = converter.ValToPtr(*syncKt)
+		syncKt.SyncFencingToken = opt.FencingToken
+	}
+
+	cliSet := actcli.GetClientSet()
+	var err error
+	switch opt.Vendor {
+	case enumor.TCloud:
+		err = tcloud.SyncResource(syncKt, cliSet, opt.AccountID, opt.ResType, opt.Region)
+	case enumor.Aws:
+		err = aws.SyncResource(syncKt, cliSet, opt.AccountID, opt.ResType, opt.Region)
+	case enumor.HuaWei:
+		err = huawei.SyncResource(syncKt, cliSet, opt.AccountID, opt.ResType, opt.Region)
+	case enumor.Gcp:
+		err = gcp.SyncResource(syncKt, cliSet, opt.AccountID, opt.ResType, opt.Region)
+	case enumor.Azure:
+		err = azure.SyncResource(syncKt, cliSet, opt.AccountID, opt.ResType, opt.Region)
+	default:
+		return nil, fmt.Errorf("vendor: %s not support", opt.Vendor)
+	}
+	if err != nil {
+		logs.Errorf("sync account resource failed, err: %v, opt: %+v, rid: %s", err, opt, kt.Kit().Rid)
+
+		sd := &detail.SyncDetail{Kt: kt.Kit(), DataCli: cliSet.DataService(), AccountID: opt.AccountID,
+			Vendor: string(opt.Vendor)}
+		if err := sd.ResSyncStatusFailed(opt.ResType, err); err != nil {
+			logs.Errorf("update %s sync detail failed, err: %v, account: %s, rid: %s", opt.ResType, err,
+				opt.AccountID, kt.Kit().Rid)
+		}
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// Rollback 资源同步是幂等操作，重试前无需回滚。
+func (act SyncResourceAction) Rollback(_ run.ExecuteKit, _ interface{}) error {
+	return nil
+}
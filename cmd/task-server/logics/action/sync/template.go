@@ -0,0 +1,194 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+// Package actionsync 以异步任务流的方式同步账号下的云资源
+package actionsync
+
+import (
+	"fmt"
+	"sort"
+
+	hcsync "hcm/pkg/api/hc-service/sync"
+	ts "hcm/pkg/api/task-server"
+	"hcm/pkg/async/action"
+	"hcm/pkg/criteria/enumor"
+	tableasync "hcm/pkg/dal/table/async"
+)
+
+// syncRetry 资源同步任务失败后的重试策略
+var syncRetry = &tableasync.Retry{
+	Enable: true,
+	Policy: &tableasync.RetryPolicy{
+		Count:        3,
+		SleepRangeMS: [2]uint{5000, 30000},
+	},
+}
+
+// SyncAccountTpl 账号同步任务流模版，每类资源一个任务，ActionID 为资源类型，按资源间的关联关系确定依赖：
+// vpc -> subnet、security_group、gcp_firewall_rule -> route_table、network_interface -> cvm，disk、eip -> cvm。
+// 主机同步时会同步其关联的子网、安全组、硬盘、EIP、网络接口，因此在这些资源同步完成之后执行。
+// 创建任务流时通过 BuildSyncAccountTasks 将每类资源按地域展开为多个任务。
+var SyncAccountTpl = action.FlowTemplate{
+	Name: enumor.FlowSyncAccount,
+	Tasks: []action.TaskTemplate{
+		syncTaskTpl(enumor.VpcCloudResType),
+		syncTaskTpl(enumor.SubnetCloudResType, enumor.VpcCloudResType),
+		syncTaskTpl(enumor.SecurityGroupCloudResType, enumor.VpcCloudResType),
+		syncTaskTpl(enumor.GcpFirewallRuleCloudResType, enumor.VpcCloudResType),
+		syncTaskTpl(enumor.RouteTableCloudResType, enumor.VpcCloudResType, enumor.SubnetCloudResType),
+		syncTaskTpl(enumor.DiskCloudResType),
+		syncTaskTpl(enumor.EipCloudResType),
+		syncTaskTpl(enumor.NetworkInterfaceCloudResType, enumor.SubnetCloudResType,
+			enumor.SecurityGroupCloudResType),
+		syncTaskTpl(enumor.CvmCloudResType, enumor.SubnetCloudResType, enumor.SecurityGroupCloudResType,
+			enumor.DiskCloudResType, enumor.EipCloudResType, enumor.NetworkInterfaceCloudResType),
+		syncTaskTpl(enumor.SubAccountCloudResType),
+	},
+}
+
+func syncTaskTpl(resType enumor.CloudResourceType, dependOn ...enumor.CloudResourceType) action.TaskTemplate {
+	tpl := action.TaskTemplate{
+		ActionID:   action.ActIDType(resType),
+		ActionName: enumor.ActionSyncAccountResource,
+		Params:     &action.Params{Type: SyncResourceOption{}},
+		Retry:      syncRetry,
+	}
+
+	for _, one := range dependOn {
+		tpl.DependOn = append(tpl.DependOn, action.ActIDType(one))
+	}
+
+	return tpl
+}
+
+// BuildSyncAccountTasks 根据账号同步任务流模版，将每类资源按地域展开为任务，scopes 为各类资源需要同步的地域，
+// 地域为空的资源只生成一个任务。依赖的资源在同一地域有任务时只依赖该地域的任务，否则依赖该资源的全部任务；
+// 依赖的资源无需同步时，继承该资源的依赖。
+func BuildSyncAccountTasks(vendor enumor.Vendor, accountID string,
+	scopes map[enumor.CloudResourceType][]string) []ts.CustomFlowTask {
+
+	tplMap := make(map[action.ActIDType]action.TaskTemplate, len(SyncAccountTpl.Tasks))
+	for _, one := range SyncAccountTpl.Tasks {
+		tplMap[one.ActionID] = one
+	}
+
+	// resTaskIDs 各类资源按地域生成的任务ID，不区分地域的资源 key 为空
+	resTaskIDs := make(map[enumor.CloudResourceType]map[string]action.ActIDType)
+	tasks := make([]ts.CustomFlowTask, 0)
+	for _, tpl := range SyncAccountTpl.Tasks {
+		resType := enumor.CloudResourceType(tpl.ActionID)
+		regions, exist := scopes[resType]
+		if !exist {
+			continue
+		}
+
+		if len(regions) == 0 {
+			regions = []string{""}
+		}
+
+		resTaskIDs[resType] = make(map[string]action.ActIDType, len(regions))
+		for index, region := range regions {
+			actionID := tpl.ActionID
+			if len(region) != 0 {
+				// 资源组名称可能较长，使用序号作为任务ID，地域记录在任务参数中
+				actionID = action.ActIDType(fmt.Sprintf("%s-%d", resType, index+1))
+			}
+			resTaskIDs[resType][region] = actionID
+
+			tasks = append(tasks, ts.CustomFlowTask{
+				ActionID:   actionID,
+				ActionName: tpl.ActionName,
+				Params: &SyncResourceOption{
+					Vendor:    vendor,
+					AccountID: accountID,
+					ResType:   resType,
+					Region:    region,
+				},
+				DependOn: dependTaskIDs(tplMap, resTaskIDs, tpl.DependOn, region),
+				Retry:    tpl.Retry,
+			})
+		}
+	}
+
+	return tasks
+}
+
+// BuildSyncByCloudIDsTasks 生成按云ID定向同步的任务，每个同步目标一个任务，任务之间没有依赖。
+func BuildSyncByCloudIDsTasks(vendor enumor.Vendor, targets []hcsync.CloudIDSyncReq) []ts.CustomFlowTask {
+	tasks := make([]ts.CustomFlowTask, 0, len(targets))
+	for index, target := range targets {
+		tasks = append(tasks, ts.CustomFlowTask{
+			ActionID:   action.ActIDType(fmt.Sprintf("%s-%d", target.ResType, index+1)),
+			ActionName: enumor.ActionSyncResourceByCloudIDs,
+			Params: &SyncByCloudIDsOption{
+				Vendor:         vendor,
+				CloudIDSyncReq: target,
+			},
+			Retry: syncRetry,
+		})
+	}
+
+	return tasks
+}
+
+// dependTaskIDs 返回地域 region 的任务依赖的任务ID。
+func dependTaskIDs(tplMap map[action.ActIDType]action.TaskTemplate,
+	resTaskIDs map[enumor.CloudResourceType]map[string]action.ActIDType, dependOn []action.ActIDType,
+	region string) []action.ActIDType {
+
+	ids := make([]action.ActIDType, 0)
+	exists := make(map[action.ActIDType]struct{})
+	add := func(id action.ActIDType) {
+		if _, ok := exists[id]; !ok {
+			exists[id] = struct{}{}
+			ids = append(ids, id)
+		}
+	}
+
+	for _, one := range dependOn {
+		taskIDs, ok := resTaskIDs[enumor.CloudResourceType(one)]
+		if !ok {
+			// 依赖的资源无需同步，继承其依赖
+			for _, id := range dependTaskIDs(tplMap, resTaskIDs, tplMap[one].DependOn, region) {
+				add(id)
+			}
+			continue
+		}
+
+		if id, ok := taskIDs[region]; ok {
+			add(id)
+			continue
+		}
+
+		if id, ok := taskIDs[""]; ok {
+			add(id)
+			continue
+		}
+
+		all := make([]action.ActIDType, 0, len(taskIDs))
+		for _, id := range taskIDs {
+			all = append(all, id)
+		}
+		sort.Slice(all, func(i, j int) bool { return all[i] < all[j] })
+		for _, id := range all {
+			add(id)
+		}
+	}
+
+	return ids
+}
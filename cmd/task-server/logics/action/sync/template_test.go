@@ -0,0 +1,163 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+package actionsync
+
+import (
+	"testing"
+
+	hcsync "hcm/pkg/api/hc-service/sync"
+	"hcm/pkg/async/action"
+	"hcm/pkg/criteria/enumor"
+)
+
+func TestBuildSyncAccountTasks(t *testing.T) {
+	scopes := map[enumor.CloudResourceType][]string{
+		enumor.VpcCloudResType:           nil,
+		enumor.SubnetCloudResType:        {"r1", "r2"},
+		enumor.SecurityGroupCloudResType: {"r1", "r2"},
+		enumor.CvmCloudResType:           {"r2", "r3"},
+	}
+
+	tasks := BuildSyncAccountTasks(enumor.Gcp, "0001", scopes)
+	if len(tasks) != 7 {
+		t.Fatalf("expect 7 tasks, got %d", len(tasks))
+	}
+
+	depends := make(map[action.ActIDType][]action.ActIDType, len(tasks))
+	for _, one := range tasks {
+		depends[one.ActionID] = one.DependOn
+	}
+
+	expects := map[action.ActIDType][]action.ActIDType{
+		"vpc":              {},
+		"subnet-1":         {"vpc"},
+		"security_group-2": {"vpc"},
+		// 同一地域的依赖只依赖该地域的任务，network_interface 无需同步时继承其依赖
+		"cvm-1": {"subnet-2", "security_group-2"},
+		// 依赖资源在该地域没有任务时依赖该资源的全部任务
+		"cvm-2": {"subnet-1", "subnet-2", "security_group-1", "security_group-2"},
+	}
+	for id, expect := range expects {
+		got, exist := depends[id]
+		if !exist {
+			t.Fatalf("task %s not found", id)
+		}
+
+		if len(got) != len(expect) {
+			t.Fatalf("task %s expect depend on %v, got %v", id, expect, got)
+		}
+		for i := range expect {
+			if got[i] != expect[i] {
+				t.Fatalf("task %s expect depend on %v, got %v", id, expect, got)
+			}
+		}
+	}
+}
+
+func TestBuildSyncAccountTasksParams(t *testing.T) {
+	scopes := map[enumor.CloudResourceType][]string{
+		enumor.SubAccountCloudResType: nil,
+		enumor.CvmCloudResType:        {"ap-guangzhou", "ap-shanghai"},
+	}
+
+	tasks := BuildSyncAccountTasks(enumor.TCloud, "0001", scopes)
+	expects := map[action.ActIDType]SyncResourceOption{
+		"cvm-1": {
+			Vendor:    enumor.TCloud,
+			AccountID: "0001",
+			ResType:   enumor.CvmCloudResType,
+			Region:    "ap-guangzhou",
+		},
+		"cvm-2": {
+			Vendor:    enumor.TCloud,
+			AccountID: "0001",
+			ResType:   enumor.CvmCloudResType,
+			Region:    "ap-shanghai",
+		},
+		// 不区分地域的资源只生成一个任务
+		"sub_account": {
+			Vendor:    enumor.TCloud,
+			AccountID: "0001",
+			ResType:   enumor.SubAccountCloudResType,
+		},
+	}
+	if len(tasks) != len(expects) {
+		t.Fatalf("expect %d tasks, got %d", len(expects), len(tasks))
+	}
+
+	for _, one := range tasks {
+		expect, exist := expects[one.ActionID]
+		if !exist {
+			t.Fatalf("unexpected task %s", one.ActionID)
+		}
+
+		if one.ActionName != enumor.ActionSyncAccountResource {
+			t.Errorf("task %s expect action %s, got %s", one.ActionID, enumor.ActionSyncAccountResource, one.ActionName)
+		}
+
+		opt, ok := one.Params.(*SyncResourceOption)
+		if !ok {
+			t.Fatalf("task %s params type %T is invalid", one.ActionID, one.Params)
+		}
+		if *opt != expect {
+			t.Errorf("task %s expect params %+v, got %+v", one.ActionID, expect, *opt)
+		}
+	}
+
+	if tasks := BuildSyncAccountTasks(enumor.TCloud, "0001", nil); len(tasks) != 0 {
+		t.Errorf("expect no task for empty scopes, got %d", len(tasks))
+	}
+}
+
+func TestBuildSyncByCloudIDsTasks(t *testing.T) {
+	targets := []hcsync.CloudIDSyncReq{
+		{AccountID: "0001", ResType: enumor.CvmCloudResType, Region: "ap-guangzhou", CloudIDs: []string{"ins-1"}},
+		{AccountID: "0001", ResType: enumor.CvmCloudResType, Region: "ap-shanghai", CloudIDs: []string{"ins-2"}},
+	}
+
+	tasks := BuildSyncByCloudIDsTasks(enumor.TCloud, targets)
+	if len(tasks) != len(targets) {
+		t.Fatalf("expect %d tasks, got %d", len(targets), len(tasks))
+	}
+
+	expectIDs := []action.ActIDType{"cvm-1", "cvm-2"}
+	for i, one := range tasks {
+		if one.ActionID != expectIDs[i] {
+			t.Errorf("task %d expect id %s, got %s", i, expectIDs[i], one.ActionID)
+		}
+
+		if one.ActionName != enumor.ActionSyncResourceByCloudIDs {
+			t.Errorf("task %s expect action %s, got %s", one.ActionID, enumor.ActionSyncResourceByCloudIDs,
+				one.ActionName)
+		}
+
+		// 按云ID同步的任务之间相互独立
+		if len(one.DependOn) != 0 {
+			t.Errorf("task %s expect no depend, got %v", one.ActionID, one.DependOn)
+		}
+
+		opt, ok := one.Params.(*SyncByCloudIDsOption)
+		if !ok {
+			t.Fatalf("task %s params type %T is invalid", one.ActionID, one.Params)
+		}
+		if opt.Vendor != enumor.TCloud || opt.Region != targets[i].Region || opt.CloudIDs[0] != targets[i].CloudIDs[0] {
+			t.Errorf("task %s expect target %+v, got %+v", one.ActionID, targets[i], opt.CloudIDSyncReq)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package commander 任务流取消、重试、继续执行，人工设置任务状态及定时任务流管理相关接口
+package commander
+
+import (
+	"hcm/cmd/task-server/service/capability"
+	"hcm/pkg/async/consumer"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/dal/dao"
+	tableaudit "hcm/pkg/dal/table/audit"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+)
+
+// Init initial the commander service
+func Init(cap *capability.Capability) {
+	svc := &service{
+		cmd: cap.Async.GetConsumer().GetCommander(),
+		dao: cap.Dao,
+	}
+
+	h := rest.NewHandler()
+
+	h.Add("CancelFlow", "POST", "/flows/{id}/cancel", svc.CancelFlow)
+	h.Add("RetryFlow", "POST", "/flows/{id}/retry", svc.RetryFlow)
+	h.Add("ResumeFlow", "POST", "/flows/{id}/resume", svc.ResumeFlow)
+	h.Add("UpdateTaskState", "PATCH", "/tasks/{id}/state", svc.UpdateTaskState)
+	h.Add("PauseFlowSchedule", "POST", "/flow_schedules/{id}/pause", svc.PauseFlowSchedule)
+	h.Add("ResumeFlowSchedule", "POST", "/flow_schedules/{id}/resume", svc.ResumeFlowSchedule)
+	h.Add("DeleteFlowSchedule", "DELETE", "/flow_schedules/{id}", svc.DeleteFlowSchedule)
+
+	h.Load(cap.WebService)
+}
+
+type service struct {
+	cmd consumer.Commander
+	dao dao.Set
+}
+
+// createAudit 记录任务流、任务、定时任务流的人工操作审计，审计记录失败不影响已完成的状态变更。
+func (svc *service) createAudit(kt *kit.Kit, resType enumor.AuditResourceType, resID string,
+	action enumor.AuditAction, data interface{}) {
+
+	audit := tableaudit.AuditTable{
+		ResID:    resID,
+		ResName:  resID,
+		ResType:  resType,
+		Action:   action,
+		Operator: kt.User,
+		Source:   kt.GetRequestSource(),
+		Rid:      kt.Rid,
+		AppCode:  kt.AppCode,
+		Detail: &tableaudit.BasicDetail{
+			Data: data,
+		},
+	}
+	if err := svc.dao.Audit().BatchCreate(kt, []*tableaudit.AuditTable{&audit}); err != nil {
+		logs.Errorf("create audit failed, err: %v, resType: %s, resID: %s, action: %s, rid: %s", err, resType,
+			resID, action, kt.Rid)
+	}
+}
@@ -0,0 +1,78 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package commander
+
+import (
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+)
+
+// PauseFlowSchedule pause flow schedule.
+func (svc *service) PauseFlowSchedule(cts *rest.Contexts) (interface{}, error) {
+	id := cts.PathParameter("id").String()
+	if len(id) == 0 {
+		return nil, errf.New(errf.InvalidParameter, "id is required")
+	}
+
+	if err := svc.cmd.PauseFlowSchedule(cts.Kit, id); err != nil {
+		logs.Errorf("pause flow schedule failed, err: %v, id: %s, rid: %s", err, id, cts.Kit.Rid)
+		return nil, err
+	}
+
+	svc.createAudit(cts.Kit, enumor.AsyncFlowScheduleAuditResType, id, enumor.Pause, nil)
+
+	return nil, nil
+}
+
+// ResumeFlowSchedule resume paused flow schedule.
+func (svc *service) ResumeFlowSchedule(cts *rest.Contexts) (interface{}, error) {
+	id := cts.PathParameter("id").String()
+	if len(id) == 0 {
+		return nil, errf.New(errf.InvalidParameter, "id is required")
+	}
+
+	if err := svc.cmd.ResumeFlowSchedule(cts.Kit, id); err != nil {
+		logs.Errorf("resume flow schedule failed, err: %v, id: %s, rid: %s", err, id, cts.Kit.Rid)
+		return nil, err
+	}
+
+	svc.createAudit(cts.Kit, enumor.AsyncFlowScheduleAuditResType, id, enumor.Resume, nil)
+
+	return nil, nil
+}
+
+// DeleteFlowSchedule delete flow schedule.
+func (svc *service) DeleteFlowSchedule(cts *rest.Contexts) (interface{}, error) {
+	id := cts.PathParameter("id").String()
+	if len(id) == 0 {
+		return nil, errf.New(errf.InvalidParameter, "id is required")
+	}
+
+	if err := svc.cmd.DeleteFlowSchedule(cts.Kit, id); err != nil {
+		logs.Errorf("delete flow schedule failed, err: %v, id: %s, rid: %s", err, id, cts.Kit.Rid)
+		return nil, err
+	}
+
+	svc.createAudit(cts.Kit, enumor.AsyncFlowScheduleAuditResType, id, enumor.Delete, nil)
+
+	return nil, nil
+}
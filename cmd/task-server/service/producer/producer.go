@@ -41,6 +41,7 @@ func Init(cap *capability.Capability) {
 
 	h.Add("CreateTemplateFlow", "POST", "/template_flows/create", svc.CreateTemplateFlow)
 	h.Add("CreateCustomFlow", "POST", "/custom_flows/create", svc.CreateCustomFlow)
+	h.Add("CreateFlowSchedule", "POST", "/flow_schedules/create", svc.CreateFlowSchedule)
 
 	h.Load(cap.WebService)
 }
@@ -99,3 +100,26 @@ func (p service) CreateCustomFlow(cts *rest.Contexts) (interface{}, error) {
 
 	return &core.CreateResult{ID: id}, nil
 }
+
+// CreateFlowSchedule add flow schedule
+func (p service) CreateFlowSchedule(cts *rest.Contexts) (interface{}, error) {
+
+	// 1. 解析请求体，与 CreateTemplateFlow 相同，使用 producer.AddFlowScheduleOption 解析以自动序列化 task.Params。
+	opt := new(producer.AddFlowScheduleOption)
+	if err := cts.DecodeInto(opt); err != nil {
+		return nil, err
+	}
+
+	if err := opt.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	// 2. 添加定时任务流
+	id, err := p.pro.AddFlowSchedule(cts.Kit, opt)
+	if err != nil {
+		logs.Errorf("add flow schedule failed, err: %v, opt: %+v, rid: %s", err, opt, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return &core.CreateResult{ID: id}, nil
+}
@@ -30,12 +30,14 @@ import (
 
 	logicsaction "hcm/cmd/task-server/logics/action"
 	"hcm/cmd/task-server/service/capability"
+	"hcm/cmd/task-server/service/commander"
 	"hcm/cmd/task-server/service/producer"
 	"hcm/cmd/task-server/service/viewer"
 	"hcm/pkg/async"
 	"hcm/pkg/async/backend"
 	"hcm/pkg/async/consumer"
 	"hcm/pkg/async/consumer/leader"
+	asyncproducer "hcm/pkg/async/producer"
 	"hcm/pkg/cc"
 	"hcm/pkg/client"
 	"hcm/pkg/criteria/enumor"
@@ -51,6 +53,8 @@ import (
 	"hcm/pkg/tools/ssl"
 
 	"github.com/emicklei/go-restful/v3"
+	"github.com/jmoiron/sqlx"
+	_ "github.com/mattn/go-sqlite3" // import sqlite drive, used by sqlite async backend.
 )
 
 // Service do all the task server's work
@@ -105,33 +109,60 @@ func NewService(sd serviced.ServiceDiscover, shutdownWaitTimeSec int) (*Service,
 }
 
 func createAndStartAsync(sd serviced.ServiceDiscover, dao dao.Set, shutdownWaitTimeSec int) (async.Async, error) {
-	// 创建async框架使用的backend
-	bd, err := backend.Factory(enumor.BackendMysql, dao)
+	cfg := cc.TaskServer().Async
+	bd, err := newAsyncBackend(cfg.Backend, dao)
 	if err != nil {
 		return nil, err
 	}
 
 	leader := leader.NewLeader(sd)
-	cfg := cc.TaskServer().Async
 	opt := &async.Option{
 		Register: metrics.Register(),
+		ProducerOption: &asyncproducer.Option{
+			IdempotencyKeyTTLSec: cfg.Producer.IdempotencyKeyTTLSec,
+		},
 		ConsumerOption: &consumer.Option{
 			Scheduler: &consumer.SchedulerOption{
 				WatchIntervalSec: cfg.Scheduler.WatchIntervalSec,
 				WorkerNumber:     cfg.Scheduler.WorkerNumber,
 			},
 			Executor: &consumer.ExecutorOption{
-				WorkerNumber:       cfg.Executor.WorkerNumber,
-				TaskExecTimeoutSec: cfg.Executor.TaskExecTimeoutSec,
+				WorkerNumber:         cfg.Executor.WorkerNumber,
+				TaskExecTimeoutSec:   cfg.Executor.TaskExecTimeoutSec,
+				HeartbeatIntervalSec: cfg.Executor.HeartbeatIntervalSec,
 			},
 			Dispatcher: &consumer.DispatcherOption{
 				WatchIntervalSec: cfg.Dispatcher.WatchIntervalSec,
+				Queues:           convDispatchQueues(cfg.Dispatcher.Queues),
 			},
 			WatchDog: &consumer.WatchDogOption{
 				WatchIntervalSec:    cfg.WatchDog.WatchIntervalSec,
-				TaskRunTimeoutSec:   cfg.WatchDog.TaskTimeoutSec,
+				HeartbeatTimeoutSec: cfg.WatchDog.HeartbeatTimeoutSec,
 				ShutdownWaitTimeSec: uint(shutdownWaitTimeSec),
 			},
+			Timer: &consumer.TimerOption{
+				WatchIntervalSec: cfg.Timer.WatchIntervalSec,
+			},
+			Notifier: &consumer.NotifierOption{
+				WatchIntervalSec: cfg.Notifier.WatchIntervalSec,
+				TimeoutSec:       cfg.Notifier.TimeoutSec,
+				MaxAttempts:      cfg.Notifier.MaxAttempts,
+				BackoffBaseSec:   cfg.Notifier.BackoffBaseSec,
+				Secret:           cfg.Notifier.Secret,
+				Subscriptions:    convWebhookSubscriptions(cfg.Notifier.Subscriptions),
+			},
+			Archiver: &consumer.ArchiverOption{
+				WatchIntervalSec: cfg.Archiver.WatchIntervalSec,
+				RetentionDays:    cfg.Archiver.RetentionDays,
+				BatchSize:        cfg.Archiver.BatchSize,
+			},
+			IdempotencyCleaner: &consumer.IdempotencyCleanerOption{
+				WatchIntervalSec: cfg.IdempotencyCleaner.WatchIntervalSec,
+			},
+			Compensator: &consumer.CompensatorOption{
+				WatchIntervalSec: cfg.Compensator.WatchIntervalSec,
+				WorkerNumber:     cfg.Compensator.WorkerNumber,
+			},
 		},
 	}
 	async, err := async.NewAsync(bd, leader, opt)
@@ -157,6 +188,56 @@ func createAndStartAsync(sd serviced.ServiceDiscover, dao dao.Set, shutdownWaitT
 	return async, nil
 }
 
+// newAsyncBackend 根据配置创建async框架使用的backend
+func newAsyncBackend(cfg cc.AsyncBackend, dao dao.Set) (backend.Backend, error) {
+	switch cfg.Type {
+	case enumor.BackendSqlite:
+		db, err := sqlx.Open("sqlite3", cfg.Sqlite.Path)
+		if err != nil {
+			return nil, fmt.Errorf("open async sqlite db %s failed, err: %v", cfg.Sqlite.Path, err)
+		}
+		return backend.Factory(enumor.BackendSqlite, db)
+	case enumor.BackendMemory:
+		return backend.Factory(enumor.BackendMemory, nil)
+	default:
+		return backend.Factory(enumor.BackendMysql, dao)
+	}
+}
+
+// convDispatchQueues 将配置中的派发队列转换为异步任务框架的派发队列选项
+func convDispatchQueues(queues []cc.DispatchQueue) []consumer.DispatchQueueOption {
+	result := make([]consumer.DispatchQueueOption, 0, len(queues))
+	for _, one := range queues {
+		result = append(result, consumer.DispatchQueueOption{
+			Priority:          enumor.FlowPriority(one.Priority),
+			MaxConcurrency:    one.MaxConcurrency,
+			TenantConcurrency: one.TenantConcurrency,
+		})
+	}
+
+	return result
+}
+
+// convWebhookSubscriptions 将配置中的应用 webhook 订阅转换为异步任务框架的订阅选项
+func convWebhookSubscriptions(subscriptions []cc.WebhookSubscription) []consumer.WebhookSubscription {
+	result := make([]consumer.WebhookSubscription, 0, len(subscriptions))
+	for _, one := range subscriptions {
+		events := make([]enumor.FlowEventType, 0, len(one.Events))
+		for _, event := range one.Events {
+			events = append(events, enumor.FlowEventType(event))
+		}
+
+		result = append(result, consumer.WebhookSubscription{
+			AppCode: one.AppCode,
+			URL:     one.URL,
+			Secret:  one.Secret,
+			Events:  events,
+		})
+	}
+
+	return result
+}
+
 // ListenAndServeRest listen and serve the restful server
 func (s *Service) ListenAndServeRest() error {
 	root := http.NewServeMux()
@@ -227,6 +308,7 @@ func (s *Service) apiSet() *restful.Container {
 
 	producer.Init(c)
 	viewer.Init(c)
+	commander.Init(c)
 
 	return restful.NewContainer().Add(c.WebService)
 }
@@ -0,0 +1,125 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package viewer
+
+import (
+	"time"
+
+	"hcm/pkg/api/core"
+	ts "hcm/pkg/api/task-server"
+	"hcm/pkg/async/backend"
+	"hcm/pkg/async/backend/model"
+	"hcm/pkg/async/consumer"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+)
+
+const (
+	// dagFormatJson 以 JSON 格式返回任务流有向无环图
+	dagFormatJson = "json"
+	// dagFormatDot 以 Graphviz DOT 格式返回任务流有向无环图
+	dagFormatDot = "dot"
+)
+
+// GetFlowDAG get flow dag with task state, duration, retry count, progress and eta.
+func (svc *service) GetFlowDAG(cts *rest.Contexts) (interface{}, error) {
+	id := cts.PathParameter("id").String()
+	format := cts.Request.QueryParameter("format")
+	if len(format) == 0 {
+		format = dagFormatJson
+	}
+
+	if format != dagFormatJson && format != dagFormatDot {
+		return nil, errf.Newf(errf.InvalidParameter, "format: %s not support, only support json and dot", format)
+	}
+
+	flows, err := svc.bd.ListFlow(cts.Kit, &backend.ListInput{
+		Filter: tools.EqualExpression("id", id),
+		Page:   core.NewDefaultBasePage(),
+	})
+	if err != nil {
+		logs.Errorf("list flow failed, err: %v, id: %s, rid: %s", err, id, cts.Kit.Rid)
+		return nil, err
+	}
+
+	if len(flows) == 0 {
+		return nil, errf.Newf(errf.RecordNotFound, "flow: %s not found", id)
+	}
+
+	tasks, err := svc.listFlowTask(cts, id)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]enumor.ActionName, 0, len(tasks))
+	for _, one := range tasks {
+		names = append(names, one.ActionName)
+	}
+	history, err := consumer.ListActionAvgDuration(cts.Kit, svc.bd, names)
+	if err != nil {
+		logs.Errorf("list action avg duration failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	dag, err := consumer.BuildFlowDAG(&flows[0], tasks, history, time.Now())
+	if err != nil {
+		logs.Errorf("build flow dag failed, err: %v, id: %s, rid: %s", err, id, cts.Kit.Rid)
+		return nil, err
+	}
+
+	if format == dagFormatDot {
+		return &ts.GetFlowDAGResult{Dot: consumer.FlowDAGToDot(dag)}, nil
+	}
+
+	return &ts.GetFlowDAGResult{AsyncFlowDAG: dag}, nil
+}
+
+// listFlowTask 查询任务流全部的任务
+func (svc *service) listFlowTask(cts *rest.Contexts, flowID string) ([]model.Task, error) {
+	input := &backend.ListInput{
+		Filter: tools.EqualExpression("flow_id", flowID),
+		Page: &core.BasePage{
+			Start: 0,
+			Limit: core.DefaultMaxPageLimit,
+		},
+	}
+
+	tasks := make([]model.Task, 0)
+	for {
+		result, err := svc.bd.ListTask(cts.Kit, input)
+		if err != nil {
+			logs.Errorf("list task failed, err: %v, flow: %s, rid: %s", err, flowID, cts.Kit.Rid)
+			return nil, err
+		}
+
+		tasks = append(tasks, result...)
+
+		if len(result) < int(core.DefaultMaxPageLimit) {
+			break
+		}
+
+		input.Page.Start += uint32(input.Page.Limit)
+	}
+
+	return tasks, nil
+}
@@ -23,12 +23,12 @@ import (
 	"hcm/pkg/api/core"
 	coreasync "hcm/pkg/api/core/async"
 	ts "hcm/pkg/api/task-server"
-	"hcm/pkg/criteria/errf"
 	"hcm/pkg/dal/dao/tools"
 	"hcm/pkg/dal/dao/types"
 	tableasync "hcm/pkg/dal/table/async"
 	"hcm/pkg/logs"
 	"hcm/pkg/rest"
+	"hcm/pkg/tools/times"
 )
 
 // ListFlow list flow.
@@ -67,13 +67,19 @@ func (svc *service) ListFlow(cts *rest.Contexts) (interface{}, error) {
 
 func convCoreFlow(one tableasync.AsyncFlowTable) coreasync.AsyncFlow {
 	return coreasync.AsyncFlow{
-		ID:        one.ID,
-		Name:      one.Name,
-		State:     one.State,
-		Reason:    one.Reason,
-		ShareData: one.ShareData,
-		Memo:      one.Memo,
-		Worker:    one.Worker,
+		ID:             one.ID,
+		Name:           one.Name,
+		State:          one.State,
+		Reason:         one.Reason,
+		ShareData:      one.ShareData,
+		Memo:           one.Memo,
+		Worker:         one.Worker,
+		RunAt:          times.ConvStdTimeFormat(one.RunAt),
+		RollbackPolicy: one.RollbackPolicy,
+		Priority:       one.Priority,
+		Tenant:         one.Tenant,
+		AppCode:        one.AppCode,
+		Webhooks:       one.Webhooks.WithoutSecret(),
 		Revision: core.Revision{
 			Creator:   one.Creator,
 			Reviser:   one.Reviser,
@@ -96,10 +102,18 @@ func (svc *service) GetFlow(cts *rest.Contexts) (interface{}, error) {
 		return nil, err
 	}
 
-	if len(result.Details) == 0 {
-		return nil, errf.Newf(errf.RecordNotFound, "flow: %s not found", id)
+	if len(result.Details) != 0 {
+		flow := convCoreFlow(result.Details[0])
+		return &flow, nil
 	}
 
-	flow := convCoreFlow(result.Details[0])
+	// 任务流不存在时，可能已经被归档，从归档表中查询
+	archive, err := svc.getFlowArchive(cts.Kit, id)
+	if err != nil {
+		return nil, err
+	}
+
+	flow := convCoreFlow(archive.Detail.Flow)
+	flow.ArchivedAt = times.ConvStdTimeFormat(archive.ArchivedAt)
 	return &flow, nil
 }
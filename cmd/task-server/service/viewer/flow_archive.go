@@ -0,0 +1,131 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package viewer
+
+import (
+	"hcm/pkg/api/core"
+	coreasync "hcm/pkg/api/core/async"
+	ts "hcm/pkg/api/task-server"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/dal/dao/types"
+	tableasync "hcm/pkg/dal/table/async"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+	"hcm/pkg/tools/times"
+)
+
+// ListFlowArchive list archived flow.
+func (svc *service) ListFlowArchive(cts *rest.Contexts) (interface{}, error) {
+	req := new(core.ListReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, err
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	opt := &types.ListOption{
+		Fields: req.Fields,
+		Filter: req.Filter,
+		Page:   req.Page,
+	}
+	result, err := svc.dao.AsyncFlowArchive().List(cts.Kit, opt)
+	if err != nil {
+		logs.Errorf("list flow archive failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	if req.Page.Count {
+		return &ts.ListFlowArchiveResult{Count: result.Count}, nil
+	}
+
+	archives := make([]coreasync.AsyncFlowArchive, 0, len(result.Details))
+	for _, one := range result.Details {
+		archives = append(archives, convCoreFlowArchive(one))
+	}
+
+	return &ts.ListFlowArchiveResult{Details: archives}, nil
+}
+
+// GetFlowArchive get archived flow with its tasks and events.
+func (svc *service) GetFlowArchive(cts *rest.Contexts) (interface{}, error) {
+	id := cts.PathParameter("id").String()
+	archive, err := svc.getFlowArchive(cts.Kit, id)
+	if err != nil {
+		return nil, err
+	}
+
+	result := convCoreFlowArchive(*archive)
+	return &result, nil
+}
+
+func (svc *service) getFlowArchive(kt *kit.Kit, id string) (*tableasync.AsyncFlowArchiveTable, error) {
+	opt := &types.ListOption{
+		Filter: tools.EqualExpression("id", id),
+		Page:   core.NewDefaultBasePage(),
+	}
+	result, err := svc.dao.AsyncFlowArchive().List(kt, opt)
+	if err != nil {
+		logs.Errorf("list flow archive failed, err: %v, id: %s, rid: %s", err, id, kt.Rid)
+		return nil, err
+	}
+
+	if len(result.Details) == 0 || result.Details[0].Detail == nil {
+		return nil, errf.Newf(errf.RecordNotFound, "flow: %s not found", id)
+	}
+
+	return &result.Details[0], nil
+}
+
+func convCoreFlowArchive(one tableasync.AsyncFlowArchiveTable) coreasync.AsyncFlowArchive {
+	archive := coreasync.AsyncFlowArchive{
+		ID:            one.ID,
+		Name:          one.Name,
+		State:         one.State,
+		Tenant:        one.Tenant,
+		AppCode:       one.AppCode,
+		FlowCreatedAt: times.ConvStdTimeFormat(one.FlowCreatedAt),
+		FinishedAt:    times.ConvStdTimeFormat(one.FinishedAt),
+		ArchivedAt:    times.ConvStdTimeFormat(one.ArchivedAt),
+	}
+
+	if one.Detail == nil {
+		return archive
+	}
+
+	flow := convCoreFlow(one.Detail.Flow)
+	flow.ArchivedAt = archive.ArchivedAt
+	archive.Flow = &flow
+
+	archive.Tasks = make([]coreasync.AsyncFlowTask, 0, len(one.Detail.Tasks))
+	for _, task := range one.Detail.Tasks {
+		archive.Tasks = append(archive.Tasks, convCoreTask(task))
+	}
+
+	archive.Events = make([]coreasync.AsyncFlowEvent, 0, len(one.Detail.Events))
+	for _, event := range one.Detail.Events {
+		archive.Events = append(archive.Events, convCoreFlowEvent(event))
+	}
+
+	return archive
+}
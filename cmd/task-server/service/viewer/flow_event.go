@@ -0,0 +1,79 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package viewer
+
+import (
+	"hcm/pkg/api/core"
+	coreasync "hcm/pkg/api/core/async"
+	ts "hcm/pkg/api/task-server"
+	"hcm/pkg/dal/dao/types"
+	tableasync "hcm/pkg/dal/table/async"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+	"hcm/pkg/tools/times"
+)
+
+// ListFlowEvent list flow event.
+func (svc *service) ListFlowEvent(cts *rest.Contexts) (interface{}, error) {
+	req := new(core.ListReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, err
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	opt := &types.ListOption{
+		Fields: req.Fields,
+		Filter: req.Filter,
+		Page:   req.Page,
+	}
+	result, err := svc.dao.AsyncFlowEvent().List(cts.Kit, opt)
+	if err != nil {
+		logs.Errorf("list flow event failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	if req.Page.Count {
+		return &ts.ListFlowEventResult{Count: result.Count}, nil
+	}
+
+	events := make([]coreasync.AsyncFlowEvent, 0, len(result.Details))
+	for _, one := range result.Details {
+		events = append(events, convCoreFlowEvent(one))
+	}
+
+	return &ts.ListFlowEventResult{Details: events}, nil
+}
+
+func convCoreFlowEvent(one tableasync.AsyncFlowEventTable) coreasync.AsyncFlowEvent {
+	return coreasync.AsyncFlowEvent{
+		ID:            one.ID,
+		FlowID:        one.FlowID,
+		Event:         one.Event,
+		State:         one.State,
+		Attempts:      one.Attempts,
+		NextAttemptAt: times.ConvStdTimeFormat(one.NextAttemptAt),
+		Deliveries:    one.Deliveries,
+		CreatedAt:     one.CreatedAt.String(),
+		UpdatedAt:     one.UpdatedAt.String(),
+	}
+}
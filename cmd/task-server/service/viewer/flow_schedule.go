@@ -0,0 +1,134 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package viewer
+
+import (
+	"hcm/pkg/api/core"
+	coreasync "hcm/pkg/api/core/async"
+	ts "hcm/pkg/api/task-server"
+	"hcm/pkg/dal/dao/types"
+	tableasync "hcm/pkg/dal/table/async"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+	"hcm/pkg/tools/times"
+)
+
+// ListFlowSchedule list flow schedule.
+func (svc *service) ListFlowSchedule(cts *rest.Contexts) (interface{}, error) {
+	req := new(core.ListReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, err
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	opt := &types.ListOption{
+		Fields: req.Fields,
+		Filter: req.Filter,
+		Page:   req.Page,
+	}
+	result, err := svc.dao.AsyncFlowSchedule().List(cts.Kit, opt)
+	if err != nil {
+		logs.Errorf("list flow schedule failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	if req.Page.Count {
+		return &ts.ListFlowScheduleResult{Count: result.Count}, nil
+	}
+
+	schedules := make([]coreasync.AsyncFlowSchedule, 0, len(result.Details))
+	for _, one := range result.Details {
+		schedules = append(schedules, convCoreFlowSchedule(one))
+	}
+
+	return &ts.ListFlowScheduleResult{Details: schedules}, nil
+}
+
+func convCoreFlowSchedule(one tableasync.AsyncFlowScheduleTable) coreasync.AsyncFlowSchedule {
+	// 定时任务流生成的任务流使用的 webhook 签名密钥不对外返回
+	if one.Flow != nil {
+		flow := *one.Flow
+		flow.Webhooks = flow.Webhooks.WithoutSecret()
+		one.Flow = &flow
+	}
+
+	return coreasync.AsyncFlowSchedule{
+		ID:           one.ID,
+		Name:         one.Name,
+		Cron:         one.Cron,
+		State:        one.State,
+		MissedPolicy: one.MissedPolicy,
+		Flow:         one.Flow,
+		NextRunAt:    times.ConvStdTimeFormat(one.NextRunAt),
+		Memo:         one.Memo,
+		Revision: core.Revision{
+			Creator:   one.Creator,
+			Reviser:   one.Reviser,
+			CreatedAt: one.CreatedAt.String(),
+			UpdatedAt: one.UpdatedAt.String(),
+		},
+	}
+}
+
+// ListFlowScheduleRun list flow schedule run.
+func (svc *service) ListFlowScheduleRun(cts *rest.Contexts) (interface{}, error) {
+	req := new(core.ListReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, err
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	opt := &types.ListOption{
+		Fields: req.Fields,
+		Filter: req.Filter,
+		Page:   req.Page,
+	}
+	result, err := svc.dao.AsyncFlowScheduleRun().List(cts.Kit, opt)
+	if err != nil {
+		logs.Errorf("list flow schedule run failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	if req.Page.Count {
+		return &ts.ListFlowScheduleRunResult{Count: result.Count}, nil
+	}
+
+	runs := make([]coreasync.AsyncFlowScheduleRun, 0, len(result.Details))
+	for _, one := range result.Details {
+		runs = append(runs, coreasync.AsyncFlowScheduleRun{
+			ID:         one.ID,
+			ScheduleID: one.ScheduleID,
+			PlanAt:     times.ConvStdTimeFormat(one.PlanAt),
+			State:      one.State,
+			FlowID:     one.FlowID,
+			Reason:     one.Reason,
+			Creator:    one.Creator,
+			CreatedAt:  one.CreatedAt.String(),
+		})
+	}
+
+	return &ts.ListFlowScheduleRunResult{Details: runs}, nil
+}
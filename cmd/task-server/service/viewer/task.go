@@ -29,6 +29,7 @@ import (
 	tableasync "hcm/pkg/dal/table/async"
 	"hcm/pkg/logs"
 	"hcm/pkg/rest"
+	"hcm/pkg/tools/times"
 )
 
 // ListTask list task.
@@ -67,17 +68,20 @@ func (svc *service) ListTask(cts *rest.Contexts) (interface{}, error) {
 
 func convCoreTask(one tableasync.AsyncFlowTaskTable) coreasync.AsyncFlowTask {
 	return coreasync.AsyncFlowTask{
-		ID:         one.ID,
-		FlowID:     one.FlowID,
-		FlowName:   one.FlowName,
-		ActionID:   one.ActionID,
-		ActionName: one.ActionName,
-		Params:     one.Params,
-		Result:     one.Result,
-		Retry:      one.Retry,
-		DependOn:   one.DependOn,
-		State:      one.State,
-		Reason:     one.Reason,
+		ID:          one.ID,
+		FlowID:      one.FlowID,
+		FlowName:    one.FlowName,
+		ActionID:    one.ActionID,
+		ActionName:  one.ActionName,
+		Params:      one.Params,
+		Result:      one.Result,
+		Retry:       one.Retry,
+		DependOn:    one.DependOn,
+		State:       one.State,
+		Reason:      one.Reason,
+		Stat:        one.Stat,
+		Progress:    one.Progress,
+		HeartbeatAt: times.ConvStdTimeFormat(one.HeartbeatAt),
 		Revision: core.Revision{
 			Creator:   one.Creator,
 			Reviser:   one.Reviser,
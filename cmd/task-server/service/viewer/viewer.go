@@ -22,6 +22,7 @@ package viewer
 
 import (
 	"hcm/cmd/task-server/service/capability"
+	"hcm/pkg/async/backend"
 	"hcm/pkg/client"
 	"hcm/pkg/dal/dao"
 	"hcm/pkg/rest"
@@ -32,14 +33,21 @@ func Init(cap *capability.Capability) {
 	svc := &service{
 		cs:  cap.ApiClient,
 		dao: cap.Dao,
+		bd:  backend.NewMysql(cap.Dao),
 	}
 
 	h := rest.NewHandler()
 
 	h.Add("ListFlow", "POST", "/flows/list", svc.ListFlow)
 	h.Add("GetFlow", "GET", "/flows/{id}", svc.GetFlow)
+	h.Add("GetFlowDAG", "GET", "/flows/{id}/dag", svc.GetFlowDAG)
 	h.Add("ListTask", "POST", "/tasks/list", svc.ListTask)
 	h.Add("GetTask", "GET", "/tasks/{id}", svc.GetTask)
+	h.Add("ListFlowSchedule", "POST", "/flow_schedules/list", svc.ListFlowSchedule)
+	h.Add("ListFlowScheduleRun", "POST", "/flow_schedule_runs/list", svc.ListFlowScheduleRun)
+	h.Add("ListFlowEvent", "POST", "/flow_events/list", svc.ListFlowEvent)
+	h.Add("ListFlowArchive", "POST", "/flow_archives/list", svc.ListFlowArchive)
+	h.Add("GetFlowArchive", "GET", "/flow_archives/{id}", svc.GetFlowArchive)
 
 	h.Load(cap.WebService)
 }
@@ -47,4 +55,5 @@ func Init(cap *capability.Capability) {
 type service struct {
 	cs  *client.ClientSet
 	dao dao.Set
+	bd  backend.Backend
 }
@@ -27,6 +27,7 @@ require (
 	github.com/huaweicloud/huaweicloud-sdk-go-v3 v0.1.40
 	github.com/jmoiron/sqlx v1.3.5
 	github.com/json-iterator/go v1.1.12
+	github.com/mattn/go-sqlite3 v1.14.16
 	github.com/microsoftgraph/msgraph-sdk-go v1.13.0
 	github.com/pborman/uuid v1.2.1
 	github.com/prometheus/client_golang v1.14.0
@@ -0,0 +1,92 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package aws
+
+import (
+	"hcm/pkg/adaptor/types/audit-event"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/tools/converter"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudtrail"
+)
+
+// ListAuditEvent list write management events recorded by CloudTrail in the region.
+// reference: https://docs.aws.amazon.com/awscloudtrail/latest/APIReference/API_LookupEvents.html
+func (a *Aws) ListAuditEvent(kt *kit.Kit, opt *auditevent.ListOption) ([]auditevent.AuditEvent, error) {
+	if opt == nil {
+		return nil, errf.New(errf.InvalidParameter, "list audit event option is required")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	if len(opt.Region) == 0 {
+		return nil, errf.New(errf.InvalidParameter, "region is required")
+	}
+
+	client, err := a.clientSet.cloudTrailClient(opt.Region)
+	if err != nil {
+		return nil, err
+	}
+
+	req := &cloudtrail.LookupEventsInput{
+		StartTime: aws.Time(opt.StartTime),
+		EndTime:   aws.Time(opt.EndTime),
+		LookupAttributes: []*cloudtrail.LookupAttribute{{
+			AttributeKey:   aws.String(cloudtrail.LookupAttributeKeyReadOnly),
+			AttributeValue: aws.String("false"),
+		}},
+	}
+
+	events := make([]auditevent.AuditEvent, 0)
+	err = client.LookupEventsPagesWithContext(kt.Ctx, req, func(page *cloudtrail.LookupEventsOutput, _ bool) bool {
+		for _, one := range page.Events {
+			if one == nil || len(one.Resources) == 0 {
+				continue
+			}
+
+			event := auditevent.AuditEvent{
+				ID:        converter.PtrToVal(one.EventId),
+				Name:      converter.PtrToVal(one.EventName),
+				Region:    opt.Region,
+				Time:      converter.PtrToVal(one.EventTime),
+				Resources: make([]auditevent.Resource, 0, len(one.Resources)),
+			}
+			for _, res := range one.Resources {
+				event.Resources = append(event.Resources, auditevent.Resource{
+					Type: converter.PtrToVal(res.ResourceType),
+					ID:   converter.PtrToVal(res.ResourceName),
+				})
+			}
+			events = append(events, event)
+		}
+		return true
+	})
+	if err != nil {
+		logs.Errorf("lookup aws cloudtrail events failed, err: %v, region: %s, rid: %s", err, opt.Region, kt.Rid)
+		return nil, err
+	}
+
+	return events, nil
+}
@@ -284,7 +284,7 @@ func (a *Aws) CreateCvm(kt *kit.Kit, opt *typecvm.AwsCreateOption) (*poller.Base
 		return nil, err
 	}
 
-	userData, err := genCvmBase64UserData(kt, client, opt.CloudImageID, opt.Password)
+	userData, err := genCvmBase64UserData(kt, client, opt.CloudImageID, opt.Password, opt.UserData)
 	if err != nil {
 		return nil, fmt.Errorf("gen cvm base64 user data failed, err: %v", err)
 	}
@@ -526,7 +526,17 @@ func (h *createCvmPollingHandler) Poll(client *Aws, kt *kit.Kit, cloudIDs []*str
 
 var _ poller.PollingHandler[*Aws, []*ec2.Instance, poller.BaseDoneResult] = new(createCvmPollingHandler)
 
-func genCvmBase64UserData(kt *kit.Kit, ec2Client *ec2.EC2, imageID string, passwd string) (string, error) {
+// genCvmBase64UserData 生成设置密码的脚本，如果指定了自定义数据(base64 编码)，则与其合并后返回。
+func genCvmBase64UserData(kt *kit.Kit, ec2Client *ec2.EC2, imageID, passwd string, custom *string) (string, error) {
+	customData := make([]byte, 0)
+	if custom != nil && len(*custom) != 0 {
+		decoded, err := base64.StdEncoding.DecodeString(*custom)
+		if err != nil {
+			return "", fmt.Errorf("decode custom user data failed, err: %v", err)
+		}
+		customData = decoded
+	}
+
 	req := new(ec2.DescribeImagesInput)
 	req.ImageIds = aws.StringSlice([]string{imageID})
 	resp, err := ec2Client.DescribeImagesWithContext(kt.Ctx, req)
@@ -549,6 +559,11 @@ func genCvmBase64UserData(kt *kit.Kit, ec2Client *ec2.EC2, imageID string, passw
 net user administrator %s
 </script>`, passwd)
 
+		// windows 机器支持多个 <script>/<powershell> 块，直接追加自定义数据即可
+		if len(customData) != 0 {
+			script = script + "\n" + string(customData)
+		}
+
 		return base64.StdEncoding.EncodeToString([]byte(script)), nil
 	}
 
@@ -560,5 +575,39 @@ sed -i '20 a PasswordAuthentication yes' /etc/ssh/sshd_config
 sed -i '20 a PermitRootLogin yes' /etc/ssh/sshd_config
 systemctl restart sshd`, passwd)
 
+	if len(customData) != 0 {
+		script = genMultipartUserData(script, string(customData))
+	}
+
 	return base64.StdEncoding.EncodeToString([]byte(script)), nil
 }
+
+const userDataMimeBoundary = "==HCM_USER_DATA_BOUNDARY=="
+
+// genMultipartUserData 生成 cloud-init 支持的 MIME multipart 格式自定义数据，按顺序执行设置密码脚本和用户自定义数据。
+func genMultipartUserData(passwdScript string, custom string) string {
+	customType := "text/x-shellscript"
+	if strings.HasPrefix(custom, "#cloud-config") {
+		customType = "text/cloud-config"
+	}
+
+	builder := new(strings.Builder)
+	builder.WriteString("Content-Type: multipart/mixed; boundary=\"" + userDataMimeBoundary + "\"\n")
+	builder.WriteString("MIME-Version: 1.0\n\n")
+	parts := []struct {
+		contentType string
+		content     string
+	}{
+		{contentType: "text/x-shellscript", content: passwdScript},
+		{contentType: customType, content: custom},
+	}
+	for _, part := range parts {
+		builder.WriteString("--" + userDataMimeBoundary + "\n")
+		builder.WriteString("Content-Type: " + part.contentType + "; charset=\"us-ascii\"\n")
+		builder.WriteString("MIME-Version: 1.0\n\n")
+		builder.WriteString(part.content + "\n")
+	}
+	builder.WriteString("--" + userDataMimeBoundary + "--\n")
+
+	return builder.String()
+}
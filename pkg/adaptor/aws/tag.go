@@ -0,0 +1,97 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package aws
+
+import (
+	typetag "hcm/pkg/adaptor/types/tag"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+)
+
+// TagResource add or overwrite tags of ec2 resource(instance、volume、eip、vpc、subnet).
+// reference: https://docs.aws.amazon.com/AWSEC2/latest/APIReference/API_CreateTags.html
+func (a *Aws) TagResource(kt *kit.Kit, opt *typetag.TagOption) error {
+	if opt == nil {
+		return errf.New(errf.InvalidParameter, "tag resource option is required")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	client, err := a.clientSet.ec2Client(opt.Region)
+	if err != nil {
+		return err
+	}
+
+	tags := make([]*ec2.Tag, 0, len(opt.Tags))
+	for _, tag := range opt.Tags {
+		tags = append(tags, &ec2.Tag{Key: aws.String(tag.Key), Value: aws.String(tag.Value)})
+	}
+
+	req := &ec2.CreateTagsInput{
+		Resources: aws.StringSlice(opt.CloudIDs),
+		Tags:      tags,
+	}
+	if _, err = client.CreateTagsWithContext(kt.Ctx, req); err != nil {
+		logs.Errorf("tag %s failed, err: %v, ids: %v, rid: %s", opt.ResType, err, opt.CloudIDs, kt.Rid)
+		return err
+	}
+
+	return nil
+}
+
+// UnTagResource remove tags of ec2 resource(instance、volume、eip、vpc、subnet).
+// reference: https://docs.aws.amazon.com/AWSEC2/latest/APIReference/API_DeleteTags.html
+func (a *Aws) UnTagResource(kt *kit.Kit, opt *typetag.UnTagOption) error {
+	if opt == nil {
+		return errf.New(errf.InvalidParameter, "untag resource option is required")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	client, err := a.clientSet.ec2Client(opt.Region)
+	if err != nil {
+		return err
+	}
+
+	// 不指定标签值时，删除对应键的标签
+	tags := make([]*ec2.Tag, 0, len(opt.TagKeys))
+	for _, key := range opt.TagKeys {
+		tags = append(tags, &ec2.Tag{Key: aws.String(key)})
+	}
+
+	req := &ec2.DeleteTagsInput{
+		Resources: aws.StringSlice(opt.CloudIDs),
+		Tags:      tags,
+	}
+	if _, err = client.DeleteTagsWithContext(kt.Ctx, req); err != nil {
+		logs.Errorf("untag %s failed, err: %v, ids: %v, rid: %s", opt.ResType, err, opt.CloudIDs, kt.Rid)
+		return err
+	}
+
+	return nil
+}
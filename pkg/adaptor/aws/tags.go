@@ -20,6 +20,7 @@
 package aws
 
 import (
+	corecloud "hcm/pkg/api/core/cloud"
 	"hcm/pkg/tools/converter"
 
 	"github.com/aws/aws-sdk-go/aws"
@@ -69,3 +70,20 @@ func parseTags(tags []*ec2.Tag) (string, []*ec2.Tag) {
 
 	return "", tags
 }
+
+// convertTags convert ec2 tags to resource tags, include the tag that define resource name.
+func convertTags(tags []*ec2.Tag) []corecloud.TagPair {
+	result := make([]corecloud.TagPair, 0, len(tags))
+	for _, tag := range tags {
+		if tag == nil {
+			continue
+		}
+
+		result = append(result, corecloud.TagPair{
+			Key:   converter.PtrToVal(tag.Key),
+			Value: converter.PtrToVal(tag.Value),
+		})
+	}
+
+	return result
+}
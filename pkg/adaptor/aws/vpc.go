@@ -241,6 +241,7 @@ func convertVpc(data *ec2.Vpc, region string) *types.AwsVpc {
 	v := &types.AwsVpc{
 		CloudID: converter.PtrToVal(data.VpcId),
 		Region:  region,
+		Tags:    convertTags(data.Tags),
 		Extension: &cloud.AwsVpcExtension{
 			State:           converter.PtrToVal(data.State),
 			InstanceTenancy: converter.PtrToVal(data.InstanceTenancy),
@@ -0,0 +1,136 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package azure
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"hcm/pkg/adaptor/types/audit-event"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+)
+
+const (
+	armClientVersion      = "v1.0.0"
+	activityLogApiVersion = "2015-04-01"
+	activityLogSucceeded  = "Succeeded"
+)
+
+// activityLogListResult is the response of activity log list.
+type activityLogListResult struct {
+	Value []struct {
+		EventDataID   string `json:"eventDataId"`
+		OperationName struct {
+			Value string `json:"value"`
+		} `json:"operationName"`
+		ResourceID   string `json:"resourceId"`
+		ResourceType struct {
+			Value string `json:"value"`
+		} `json:"resourceType"`
+		Status struct {
+			Value string `json:"value"`
+		} `json:"status"`
+		EventTimestamp time.Time `json:"eventTimestamp"`
+	} `json:"value"`
+	NextLink string `json:"nextLink"`
+}
+
+// ListAuditEvent list succeeded write and delete operations recorded by activity log in the subscription.
+// reference: https://learn.microsoft.com/en-us/rest/api/monitor/activity-logs/list
+func (az *Azure) ListAuditEvent(kt *kit.Kit, opt *auditevent.ListOption) ([]auditevent.AuditEvent, error) {
+	if opt == nil {
+		return nil, errf.New(errf.InvalidParameter, "list audit event option is required")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	client, err := az.clientSet.armClient("armmonitor.ActivityLogsClient")
+	if err != nil {
+		return nil, err
+	}
+
+	filter := fmt.Sprintf("eventTimestamp ge '%s' and eventTimestamp le '%s'",
+		opt.StartTime.UTC().Format(time.RFC3339), opt.EndTime.UTC().Format(time.RFC3339))
+	query := url.Values{}
+	query.Set("api-version", activityLogApiVersion)
+	query.Set("$filter", filter)
+	query.Set("$select", "eventDataId,operationName,resourceId,resourceType,status,eventTimestamp")
+	nextLink := runtime.JoinPaths(client.Endpoint(), "/subscriptions", url.PathEscape(az.clientSet.credential.CloudSubscriptionID),
+		"/providers/Microsoft.Insights/eventtypes/management/values") + "?" + query.Encode()
+
+	events := make([]auditevent.AuditEvent, 0)
+	for len(nextLink) != 0 {
+		req, err := runtime.NewRequest(kt.Ctx, http.MethodGet, nextLink)
+		if err != nil {
+			return nil, err
+		}
+		req.Raw().Header["Accept"] = []string{"application/json"}
+
+		resp, err := client.Pipeline().Do(req)
+		if err != nil {
+			logs.Errorf("list azure activity log failed, err: %v, rid: %s", err, kt.Rid)
+			return nil, err
+		}
+		if !runtime.HasStatusCode(resp, http.StatusOK) {
+			return nil, runtime.NewResponseError(resp)
+		}
+
+		result := new(activityLogListResult)
+		if err = runtime.UnmarshalAsJSON(resp, result); err != nil {
+			return nil, err
+		}
+
+		for _, one := range result.Value {
+			if one.Status.Value != activityLogSucceeded || len(one.ResourceID) == 0 {
+				continue
+			}
+
+			id := strings.ToLower(one.ResourceID)
+			res := auditevent.Resource{
+				Type: strings.ToLower(one.ResourceType.Value),
+				ID:   id,
+			}
+			// 子网资源ID格式为 {vnet id}/subnets/{name}，父资源为所属虚拟网络
+			if idx := strings.Index(id, "/subnets/"); idx > 0 {
+				res.Parent = id[:idx]
+			}
+
+			events = append(events, auditevent.AuditEvent{
+				ID:        one.EventDataID,
+				Name:      one.OperationName.Value,
+				Time:      one.EventTimestamp,
+				Resources: []auditevent.Resource{res},
+			})
+		}
+
+		nextLink = result.NextLink
+	}
+
+	return events, nil
+}
@@ -26,6 +26,7 @@ import (
 	"hcm/pkg/kit"
 	"hcm/pkg/logs"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
 	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v5"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v2"
@@ -151,6 +152,21 @@ func (c *clientSet) newClientSecretCredential() (*azidentity.ClientSecretCredent
 		c.credential.CloudClientSecretKey, nil)
 }
 
+// armClient 活动日志暂未引入独立的sdk，使用通用的 arm 客户端调用。
+func (c *clientSet) armClient(clientName string) (*arm.Client, error) {
+	credential, err := c.newClientSecretCredential()
+	if err != nil {
+		return nil, fmt.Errorf("init azure credential failed, err: %v", err)
+	}
+
+	client, err := arm.NewClient(clientName, armClientVersion, credential, nil)
+	if err != nil {
+		return nil, fmt.Errorf("init azure arm client failed, err: %v", err)
+	}
+
+	return client, nil
+}
+
 // securityGroupClient ...
 func (c *clientSet) securityGroupClient() (*armnetwork.SecurityGroupsClient, error) {
 	credential, err := c.newClientSecretCredential()
@@ -226,6 +242,21 @@ func (c *clientSet) resourceGroupsClient() (*armresources.ResourceGroupsClient,
 	return client, nil
 }
 
+// tagsClient ...
+func (c *clientSet) tagsClient() (*armresources.TagsClient, error) {
+	credential, err := c.newClientSecretCredential()
+	if err != nil {
+		return nil, fmt.Errorf("init azure credential failed, err: %v", err)
+	}
+
+	client, err := armresources.NewTagsClient(c.credential.CloudSubscriptionID, credential, nil)
+	if err != nil {
+		return nil, fmt.Errorf("init tags client failed, err: %v", err)
+	}
+
+	return client, nil
+}
+
 // regionClient ...
 func (c *clientSet) regionClient() (*armsubscriptions.Client, error) {
 	credential, err := c.newClientSecretCredential()
@@ -263,6 +263,7 @@ func converterToAzureCvm(vms []*armcompute.VirtualMachine) []*typecvm.AzureCvm {
 			Location: SPtrToLowerNoSpaceSPtr(v.Location),
 			Type:     v.Type,
 			Zones:    v.Zones,
+			Tags:     v.Tags,
 		}
 
 		if v.Properties == nil {
@@ -535,6 +536,7 @@ func (az *Azure) CreateCvm(kt *kit.Kit, opt *typecvm.AzureCreateOption) (string,
 				AdminPassword: to.Ptr(opt.Password),
 				AdminUsername: to.Ptr(opt.Username),
 				ComputerName:  to.Ptr(opt.Name),
+				CustomData:    opt.UserData,
 			},
 			StorageProfile: &armcompute.StorageProfile{
 				DataDisks: dataDisk,
@@ -275,6 +275,7 @@ func converterDisk(disks []*armcompute.Disk) []*disk.AzureDisk {
 			OSType:   (*string)(v.Properties.OSType),
 			SKUName:  (*string)(v.SKU.Name),
 			SKUTier:  v.SKU.Tier,
+			Tags:     convertTags(v.Tags),
 		}
 		typesDisk = append(typesDisk, tmp)
 	}
@@ -0,0 +1,149 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package azure
+
+import (
+	"fmt"
+
+	typetag "hcm/pkg/adaptor/types/tag"
+	corecloud "hcm/pkg/api/core/cloud"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/tools/converter"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
+)
+
+// tagResTypes azure 支持标签的资源类型，子网不支持标签
+var tagResTypes = map[enumor.CloudResourceType]struct{}{
+	enumor.CvmCloudResType:  {},
+	enumor.DiskCloudResType: {},
+	enumor.EipCloudResType:  {},
+	enumor.VpcCloudResType:  {},
+}
+
+// TagResource add or overwrite tags of resource.
+// reference: https://learn.microsoft.com/en-us/rest/api/resources/tags/update-at-scope
+func (az *Azure) TagResource(kt *kit.Kit, opt *typetag.TagOption) error {
+	if opt == nil {
+		return errf.New(errf.InvalidParameter, "tag resource option is required")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	if _, exist := tagResTypes[opt.ResType]; !exist {
+		return errf.Newf(errf.InvalidParameter, "azure %s not support tag", opt.ResType)
+	}
+
+	client, err := az.clientSet.tagsClient()
+	if err != nil {
+		return fmt.Errorf("new tags client failed, err: %v", err)
+	}
+
+	tags := make(map[string]*string, len(opt.Tags))
+	for _, tag := range opt.Tags {
+		tags[tag.Key] = to.Ptr(tag.Value)
+	}
+
+	params := armresources.TagsPatchResource{
+		Operation:  to.Ptr(armresources.TagsPatchOperationMerge),
+		Properties: &armresources.Tags{Tags: tags},
+	}
+	// azure 资源云ID即为资源的scope
+	for _, id := range opt.CloudIDs {
+		if _, err = client.UpdateAtScope(kt.Ctx, id, params, nil); err != nil {
+			logs.Errorf("tag azure %s failed, err: %v, id: %s, rid: %s", opt.ResType, err, id, kt.Rid)
+			return err
+		}
+	}
+
+	return nil
+}
+
+// UnTagResource remove tags of resource.
+// reference: https://learn.microsoft.com/en-us/rest/api/resources/tags/update-at-scope
+func (az *Azure) UnTagResource(kt *kit.Kit, opt *typetag.UnTagOption) error {
+	if opt == nil {
+		return errf.New(errf.InvalidParameter, "untag resource option is required")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	if _, exist := tagResTypes[opt.ResType]; !exist {
+		return errf.Newf(errf.InvalidParameter, "azure %s not support tag", opt.ResType)
+	}
+
+	client, err := az.clientSet.tagsClient()
+	if err != nil {
+		return fmt.Errorf("new tags client failed, err: %v", err)
+	}
+
+	for _, id := range opt.CloudIDs {
+		resp, err := client.GetAtScope(kt.Ctx, id, nil)
+		if err != nil {
+			logs.Errorf("get azure %s tags failed, err: %v, id: %s, rid: %s", opt.ResType, err, id, kt.Rid)
+			return err
+		}
+
+		if resp.Properties == nil || len(resp.Properties.Tags) == 0 {
+			continue
+		}
+
+		// delete 操作需要指定标签值，所以使用云上的标签值进行删除
+		tags := make(map[string]*string)
+		for _, key := range opt.TagKeys {
+			if value, exist := resp.Properties.Tags[key]; exist {
+				tags[key] = value
+			}
+		}
+
+		if len(tags) == 0 {
+			continue
+		}
+
+		params := armresources.TagsPatchResource{
+			Operation:  to.Ptr(armresources.TagsPatchOperationDelete),
+			Properties: &armresources.Tags{Tags: tags},
+		}
+		if _, err = client.UpdateAtScope(kt.Ctx, id, params, nil); err != nil {
+			logs.Errorf("untag azure %s failed, err: %v, id: %s, rid: %s", opt.ResType, err, id, kt.Rid)
+			return err
+		}
+	}
+
+	return nil
+}
+
+// convertTags convert azure resource tags.
+func convertTags(tags map[string]*string) []corecloud.TagPair {
+	result := make([]corecloud.TagPair, 0, len(tags))
+	for key, value := range tags {
+		result = append(result, corecloud.TagPair{Key: key, Value: converter.PtrToVal(value)})
+	}
+
+	return result
+}
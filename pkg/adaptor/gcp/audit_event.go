@@ -0,0 +1,136 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package gcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"hcm/pkg/adaptor/types/audit-event"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+
+	logging "google.golang.org/api/logging/v2"
+)
+
+// auditLogQueryLimit 审计日志单次查询的最大条数
+const auditLogQueryLimit = 1000
+
+// auditResourceIDLabel 审计日志中各类型资源的资源ID所在的标签
+var auditResourceIDLabel = map[string]string{
+	"gce_instance":      "instance_id",
+	"gce_network":       "network_id",
+	"gce_subnetwork":    "subnetwork_id",
+	"gce_firewall_rule": "firewall_rule_id",
+	"gce_disk":          "disk_id",
+}
+
+// ListAuditEvent list admin activity audit logs of compute resources in the project.
+// reference: https://cloud.google.com/logging/docs/reference/v2/rest/v2/entries/list
+func (g *Gcp) ListAuditEvent(kt *kit.Kit, opt *auditevent.ListOption) ([]auditevent.AuditEvent, error) {
+	if opt == nil {
+		return nil, errf.New(errf.InvalidParameter, "list audit event option is required")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	client, err := g.clientSet.loggingClient(kt)
+	if err != nil {
+		return nil, err
+	}
+
+	resTypes := make([]string, 0, len(auditResourceIDLabel))
+	for resType := range auditResourceIDLabel {
+		resTypes = append(resTypes, resType)
+	}
+	sort.Strings(resTypes)
+
+	projectID := g.CloudProjectID()
+	req := &logging.ListLogEntriesRequest{
+		ResourceNames: []string{"projects/" + projectID},
+		Filter: fmt.Sprintf(`logName="projects/%s/logs/cloudaudit.googleapis.com%%2Factivity" AND `+
+			`timestamp>="%s" AND timestamp<"%s" AND resource.type=(%s)`, projectID,
+			opt.StartTime.UTC().Format(time.RFC3339), opt.EndTime.UTC().Format(time.RFC3339),
+			strings.Join(resTypes, " OR ")),
+		OrderBy:  "timestamp asc",
+		PageSize: auditLogQueryLimit,
+	}
+
+	events := make([]auditevent.AuditEvent, 0)
+	err = client.Entries.List(req).Pages(kt.Ctx, func(resp *logging.ListLogEntriesResponse) error {
+		for _, entry := range resp.Entries {
+			if event, ok := convAuditLogEntry(entry); ok {
+				events = append(events, event)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		logs.Errorf("list gcp audit log entries failed, err: %v, project: %s, rid: %s", err, projectID, kt.Rid)
+		return nil, err
+	}
+
+	return events, nil
+}
+
+func convAuditLogEntry(entry *logging.LogEntry) (auditevent.AuditEvent, bool) {
+	if entry == nil || entry.Resource == nil {
+		return auditevent.AuditEvent{}, false
+	}
+
+	idLabel, exists := auditResourceIDLabel[entry.Resource.Type]
+	if !exists || len(entry.Resource.Labels[idLabel]) == 0 {
+		return auditevent.AuditEvent{}, false
+	}
+
+	zone := entry.Resource.Labels["zone"]
+	region := entry.Resource.Labels["location"]
+	// 可用区格式为 {region}-{a-z}，可用区级资源的地域由可用区推导
+	if len(region) == 0 && strings.LastIndex(zone, "-") > 0 {
+		region = zone[:strings.LastIndex(zone, "-")]
+	}
+
+	event := auditevent.AuditEvent{
+		ID:     entry.InsertId,
+		Region: region,
+		Resources: []auditevent.Resource{{
+			Type: entry.Resource.Type,
+			ID:   entry.Resource.Labels[idLabel],
+			Zone: zone,
+		}},
+	}
+	payload := struct {
+		MethodName string `json:"methodName"`
+	}{}
+	if err := json.Unmarshal(entry.ProtoPayload, &payload); err == nil {
+		event.Name = payload.MethodName
+	}
+	if t, err := time.Parse(time.RFC3339Nano, entry.Timestamp); err == nil {
+		event.Time = t
+	}
+
+	return event, true
+}
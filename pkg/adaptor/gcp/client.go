@@ -31,6 +31,7 @@ import (
 	res "google.golang.org/api/cloudresourcemanager/v3"
 	"google.golang.org/api/compute/v1"
 	iam "google.golang.org/api/iam/v1"
+	logging "google.golang.org/api/logging/v2"
 	"google.golang.org/api/option"
 )
 
@@ -103,3 +104,13 @@ func (c *clientSet) iamServiceClient(kt *kit.Kit) (*iam.Service, error) {
 
 	return service, nil
 }
+
+func (c *clientSet) loggingClient(kt *kit.Kit) (*logging.Service, error) {
+	opt := option.WithCredentialsJSON(c.credential.Json)
+	service, err := logging.NewService(kt.Ctx, opt)
+	if err != nil {
+		return nil, err
+	}
+
+	return service, nil
+}
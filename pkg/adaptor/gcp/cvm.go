@@ -326,6 +326,11 @@ func (g *Gcp) CreateCvm(kt *kit.Kit, opt *typecvm.GcpCreateOption) (*poller.Base
 		NamePattern: opt.NamePrefix + "-####",
 	}
 
+	if opt.UserData != nil && len(*opt.UserData) != 0 {
+		req.InstanceProperties.Metadata.Items = append(req.InstanceProperties.Metadata.Items,
+			&compute.MetadataItems{Key: "user-data", Value: opt.UserData})
+	}
+
 	if opt.PublicIPAssigned {
 		req.InstanceProperties.NetworkInterfaces = []*compute.NetworkInterface{
 			{
@@ -400,9 +405,20 @@ func (g *Gcp) deleteCvmMetadataStartScript(kt *kit.Kit, client *compute.Service,
 	}
 
 	for _, one := range resp.Items {
-		one.Metadata = &compute.Metadata{
-			Items: []*compute.MetadataItems{},
+		// 仅删除设置密码的启动脚本，保留用户自定义数据等其他元数据
+		items := make([]*compute.MetadataItems, 0)
+		if one.Metadata != nil {
+			for _, item := range one.Metadata.Items {
+				if item.Key != "startup-script" {
+					items = append(items, item)
+				}
+			}
+		}
+
+		if one.Metadata == nil {
+			one.Metadata = new(compute.Metadata)
 		}
+		one.Metadata.Items = items
 
 		_, err := client.Instances.Update(g.CloudProjectID(), zone, one.Name, one).Do()
 		if err != nil {
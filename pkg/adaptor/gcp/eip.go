@@ -340,6 +340,7 @@ func convert(resp *compute.AddressList, region string) []*eip.GcpEip {
 			Subnetwork:   item.Subnetwork,
 			SelfLink:     item.SelfLink,
 			Users:        item.Users,
+			Tags:         convertLabels(item.Labels),
 		}
 		switch item.AddressType {
 		case "EXTERNAL":
@@ -0,0 +1,182 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package gcp
+
+import (
+	typetag "hcm/pkg/adaptor/types/tag"
+	corecloud "hcm/pkg/api/core/cloud"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+
+	"google.golang.org/api/compute/v1"
+)
+
+// TagResource add or overwrite labels of resource(instance、disk、address), gcp use labels as tags.
+// reference: https://cloud.google.com/compute/docs/labeling-resources
+func (g *Gcp) TagResource(kt *kit.Kit, opt *typetag.TagOption) error {
+	if opt == nil {
+		return errf.New(errf.InvalidParameter, "tag resource option is required")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	return g.updateLabels(kt, opt.ResType, opt.Region, opt.Zone, opt.CloudIDs, func(labels map[string]string) {
+		for _, tag := range opt.Tags {
+			labels[tag.Key] = tag.Value
+		}
+	})
+}
+
+// UnTagResource remove labels of resource(instance、disk、address), gcp use labels as tags.
+// reference: https://cloud.google.com/compute/docs/labeling-resources
+func (g *Gcp) UnTagResource(kt *kit.Kit, opt *typetag.UnTagOption) error {
+	if opt == nil {
+		return errf.New(errf.InvalidParameter, "untag resource option is required")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	return g.updateLabels(kt, opt.ResType, opt.Region, opt.Zone, opt.CloudIDs, func(labels map[string]string) {
+		for _, key := range opt.TagKeys {
+			delete(labels, key)
+		}
+	})
+}
+
+// labelAccessor 读取和设置某类资源的标签，setLabels 为全量覆盖且需要携带 labelFingerprint。
+type labelAccessor struct {
+	get func(name string) (labels map[string]string, fingerprint string, err error)
+	set func(name string, labels map[string]string, fingerprint string) error
+}
+
+func (g *Gcp) labelAccessor(kt *kit.Kit, resType enumor.CloudResourceType, region, zone string) (*labelAccessor,
+	error) {
+
+	// 主机和硬盘为可用区资源，eip为地域资源
+	if (resType == enumor.CvmCloudResType || resType == enumor.DiskCloudResType) && len(zone) == 0 {
+		return nil, errf.New(errf.InvalidParameter, "zone is required")
+	}
+
+	client, err := g.clientSet.computeClient(kt)
+	if err != nil {
+		return nil, err
+	}
+
+	project := g.CloudProjectID()
+	switch resType {
+	case enumor.CvmCloudResType:
+		return &labelAccessor{
+			get: func(name string) (map[string]string, string, error) {
+				one, err := client.Instances.Get(project, zone, name).Context(kt.Ctx).Do()
+				if err != nil {
+					return nil, "", err
+				}
+				return one.Labels, one.LabelFingerprint, nil
+			},
+			set: func(name string, labels map[string]string, fingerprint string) error {
+				req := &compute.InstancesSetLabelsRequest{Labels: labels, LabelFingerprint: fingerprint}
+				_, err := client.Instances.SetLabels(project, zone, name, req).Context(kt.Ctx).Do()
+				return err
+			},
+		}, nil
+
+	case enumor.DiskCloudResType:
+		return &labelAccessor{
+			get: func(name string) (map[string]string, string, error) {
+				one, err := client.Disks.Get(project, zone, name).Context(kt.Ctx).Do()
+				if err != nil {
+					return nil, "", err
+				}
+				return one.Labels, one.LabelFingerprint, nil
+			},
+			set: func(name string, labels map[string]string, fingerprint string) error {
+				req := &compute.ZoneSetLabelsRequest{Labels: labels, LabelFingerprint: fingerprint}
+				_, err := client.Disks.SetLabels(project, zone, name, req).Context(kt.Ctx).Do()
+				return err
+			},
+		}, nil
+
+	case enumor.EipCloudResType:
+		return &labelAccessor{
+			get: func(name string) (map[string]string, string, error) {
+				one, err := client.Addresses.Get(project, region, name).Context(kt.Ctx).Do()
+				if err != nil {
+					return nil, "", err
+				}
+				return one.Labels, one.LabelFingerprint, nil
+			},
+			set: func(name string, labels map[string]string, fingerprint string) error {
+				req := &compute.RegionSetLabelsRequest{Labels: labels, LabelFingerprint: fingerprint}
+				_, err := client.Addresses.SetLabels(project, region, name, req).Context(kt.Ctx).Do()
+				return err
+			},
+		}, nil
+
+	default:
+		return nil, errf.Newf(errf.InvalidParameter, "gcp %s not support tag", resType)
+	}
+}
+
+// updateLabels 先查询资源当前的标签，修改后再全量覆盖。
+func (g *Gcp) updateLabels(kt *kit.Kit, resType enumor.CloudResourceType, region, zone string, names []string,
+	modify func(labels map[string]string)) error {
+
+	accessor, err := g.labelAccessor(kt, resType, region, zone)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range names {
+		current, fingerprint, err := accessor.get(name)
+		if err != nil {
+			logs.Errorf("get gcp %s failed, err: %v, name: %s, rid: %s", resType, err, name, kt.Rid)
+			return err
+		}
+
+		labels := make(map[string]string, len(current))
+		for key, value := range current {
+			labels[key] = value
+		}
+		modify(labels)
+
+		if err = accessor.set(name, labels, fingerprint); err != nil {
+			logs.Errorf("set gcp %s labels failed, err: %v, name: %s, rid: %s", resType, err, name, kt.Rid)
+			return err
+		}
+	}
+
+	return nil
+}
+
+// convertLabels convert gcp labels to resource tags.
+func convertLabels(labels map[string]string) []corecloud.TagPair {
+	result := make([]corecloud.TagPair, 0, len(labels))
+	for key, value := range labels {
+		result = append(result, corecloud.TagPair{Key: key, Value: value})
+	}
+
+	return result
+}
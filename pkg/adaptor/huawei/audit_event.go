@@ -0,0 +1,121 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package huawei
+
+import (
+	"encoding/json"
+	"regexp"
+	"time"
+
+	"hcm/pkg/adaptor/types/audit-event"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/tools/converter"
+
+	"github.com/huaweicloud/huaweicloud-sdk-go-v3/services/cts/v3/model"
+)
+
+// ctsQueryLimit 云审计单次查询的最大事件数量
+const ctsQueryLimit = 200
+
+// subnetVpcRegexp 从子网操作的资源URL中解析所属vpc，如 /v1/{project_id}/vpcs/{vpc_id}/subnets/{subnet_id}
+var subnetVpcRegexp = regexp.MustCompile(`/vpcs/([^/]+)/subnets`)
+
+// ListAuditEvent list management traces recorded by cloud trace service in the region.
+// reference: https://support.huaweicloud.com/api-cts/ListTraces.html
+func (h *HuaWei) ListAuditEvent(kt *kit.Kit, opt *auditevent.ListOption) ([]auditevent.AuditEvent, error) {
+	if opt == nil {
+		return nil, errf.New(errf.InvalidParameter, "list audit event option is required")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	if len(opt.Region) == 0 {
+		return nil, errf.New(errf.InvalidParameter, "region is required")
+	}
+
+	client, err := h.clientSet.ctsClient(opt.Region)
+	if err != nil {
+		return nil, err
+	}
+
+	req := &model.ListTracesRequest{
+		TraceType: model.GetListTracesRequestTraceTypeEnum().SYSTEM,
+		Limit:     converter.ValToPtr(int32(ctsQueryLimit)),
+		From:      converter.ValToPtr(opt.StartTime.UnixMilli()),
+		To:        converter.ValToPtr(opt.EndTime.UnixMilli()),
+	}
+
+	events := make([]auditevent.AuditEvent, 0)
+	for {
+		resp, err := client.ListTraces(req)
+		if err != nil {
+			logs.Errorf("list huawei cts traces failed, err: %v, region: %s, rid: %s", err, opt.Region, kt.Rid)
+			return nil, err
+		}
+
+		for _, one := range converter.PtrToVal(resp.Traces) {
+			resID := converter.PtrToVal(one.ResourceId)
+			if len(resID) == 0 {
+				continue
+			}
+
+			events = append(events, auditevent.AuditEvent{
+				ID:     converter.PtrToVal(one.TraceId),
+				Name:   converter.PtrToVal(one.TraceName),
+				Region: opt.Region,
+				Time:   time.UnixMilli(converter.PtrToVal(one.Time)),
+				Resources: []auditevent.Resource{{
+					Type:   converter.PtrToVal(one.ResourceType),
+					ID:     resID,
+					Parent: parseTraceVpcID(one),
+				}},
+			})
+		}
+
+		if resp.MetaData == nil || len(converter.PtrToVal(resp.MetaData.Marker)) == 0 {
+			break
+		}
+		req.Next = resp.MetaData.Marker
+	}
+
+	return events, nil
+}
+
+// parseTraceVpcID 解析子网等vpc下资源的所属vpc，优先从资源URL解析，其次从请求体解析。
+func parseTraceVpcID(trace model.Traces) string {
+	if match := subnetVpcRegexp.FindStringSubmatch(converter.PtrToVal(trace.ResourceUrl)); len(match) == 2 {
+		return match[1]
+	}
+
+	body := struct {
+		Subnet struct {
+			VpcID string `json:"vpc_id"`
+		} `json:"subnet"`
+	}{}
+	if err := json.Unmarshal([]byte(converter.PtrToVal(trace.Request)), &body); err != nil {
+		return ""
+	}
+
+	return body.Subnet.VpcID
+}
@@ -30,6 +30,8 @@ import (
 	"github.com/huaweicloud/huaweicloud-sdk-go-v3/core/region"
 	bssintl "github.com/huaweicloud/huaweicloud-sdk-go-v3/services/bssintl/v2"
 	bssintlv2region "github.com/huaweicloud/huaweicloud-sdk-go-v3/services/bssintl/v2/region"
+	cts "github.com/huaweicloud/huaweicloud-sdk-go-v3/services/cts/v3"
+	ctsregion "github.com/huaweicloud/huaweicloud-sdk-go-v3/services/cts/v3/region"
 	dcs "github.com/huaweicloud/huaweicloud-sdk-go-v3/services/dcs/v2"
 	dcsregion "github.com/huaweicloud/huaweicloud-sdk-go-v3/services/dcs/v2/region"
 	ecs "github.com/huaweicloud/huaweicloud-sdk-go-v3/services/ecs/v2"
@@ -149,6 +151,23 @@ func (c *clientSet) evsClient(region string) (client *evs.EvsClient, err error)
 	return client, nil
 }
 
+func (c *clientSet) ctsClient(region string) (cli *cts.CtsClient, err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("huawei error recovered, err: %v", p)
+		}
+	}()
+
+	cli = cts.NewCtsClient(
+		cts.CtsClientBuilder().
+			WithRegion(ctsregion.ValueOf(region)).
+			WithCredential(c.credentials()).
+			WithHttpConfig(config.DefaultHttpConfig()).
+			Build())
+
+	return cli, nil
+}
+
 func (c *clientSet) vpcClient(regionID string) (cli *vpc.VpcClient, err error) {
 	defer func() {
 		if p := recover(); p != nil {
@@ -594,6 +594,7 @@ func (h *HuaWei) CreateCvm(kt *kit.Kit, opt *typecvm.HuaWeiCreateOption) (*polle
 				Count:            converter.ValToPtr(opt.RequiredCount),
 				AvailabilityZone: converter.ValToPtr(opt.Zone),
 				Description:      opt.Description,
+				UserData:         opt.UserData,
 				Extendparam: &model.PrePaidServerExtendParam{
 					ChargingMode: converter.ValToPtr(chargingMode),
 					IsAutoRenew:  converter.ValToPtr(model.GetPrePaidServerExtendParamIsAutoRenewEnum().TRUE),
@@ -0,0 +1,387 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package huawei
+
+import (
+	"fmt"
+
+	typetag "hcm/pkg/adaptor/types/tag"
+	corecloud "hcm/pkg/api/core/cloud"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/tools/converter"
+
+	"github.com/huaweicloud/huaweicloud-sdk-go-v3/services/ecs/v2/model"
+	eipmodel "github.com/huaweicloud/huaweicloud-sdk-go-v3/services/eip/v2/model"
+	evsmodel "github.com/huaweicloud/huaweicloud-sdk-go-v3/services/evs/v2/model"
+	vpcmodel "github.com/huaweicloud/huaweicloud-sdk-go-v3/services/vpc/v2/model"
+	vpcv3model "github.com/huaweicloud/huaweicloud-sdk-go-v3/services/vpc/v3/model"
+)
+
+// TagResource add or overwrite tags of resource, huawei only support tag one resource per request.
+// reference: https://support.huaweicloud.com/api-ecs/ecs_02_1002.html
+func (h *HuaWei) TagResource(kt *kit.Kit, opt *typetag.TagOption) error {
+	if opt == nil {
+		return errf.New(errf.InvalidParameter, "tag resource option is required")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	tagger, err := h.resTagger(opt.ResType, opt.Region)
+	if err != nil {
+		return err
+	}
+
+	for _, id := range opt.CloudIDs {
+		if err = tagger.create(id, opt.Tags); err != nil {
+			logs.Errorf("tag huawei %s failed, err: %v, id: %s, rid: %s", opt.ResType, err, id, kt.Rid)
+			return err
+		}
+	}
+
+	return nil
+}
+
+// UnTagResource remove tags of resource, huawei only support untag one resource per request.
+// reference: https://support.huaweicloud.com/api-ecs/ecs_02_1003.html
+func (h *HuaWei) UnTagResource(kt *kit.Kit, opt *typetag.UnTagOption) error {
+	if opt == nil {
+		return errf.New(errf.InvalidParameter, "untag resource option is required")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	tagger, err := h.resTagger(opt.ResType, opt.Region)
+	if err != nil {
+		return err
+	}
+
+	for _, id := range opt.CloudIDs {
+		if err = tagger.delete(id, opt.TagKeys); err != nil {
+			logs.Errorf("untag huawei %s failed, err: %v, id: %s, rid: %s", opt.ResType, err, id, kt.Rid)
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ListResourceTag list tags of resource, huawei only support list tags of one resource per request.
+// the list result of eip and subnet do not contain tags, so the tags need to be queried separately.
+func (h *HuaWei) ListResourceTag(kt *kit.Kit, opt *typetag.ListTagOption) (map[string][]corecloud.TagPair, error) {
+	if opt == nil {
+		return nil, errf.New(errf.InvalidParameter, "list resource tag option is required")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	tagger, err := h.resTagger(opt.ResType, opt.Region)
+	if err != nil {
+		return nil, err
+	}
+
+	if tagger.list == nil {
+		return nil, errf.Newf(errf.InvalidParameter, "huawei %s not support list tag", opt.ResType)
+	}
+
+	result := make(map[string][]corecloud.TagPair, len(opt.CloudIDs))
+	for _, id := range opt.CloudIDs {
+		tags, err := tagger.list(id)
+		if err != nil {
+			logs.Errorf("list huawei %s tag failed, err: %v, id: %s, rid: %s", opt.ResType, err, id, kt.Rid)
+			return nil, err
+		}
+		result[id] = tags
+	}
+
+	return result, nil
+}
+
+// resTagger 华为云各产品的标签接口相互独立，按资源类型封装单个资源的标签添加和删除操作。
+type resTagger struct {
+	create func(id string, tags []corecloud.TagPair) error
+	delete func(id string, keys []string) error
+	// list 查询单个资源的标签，仅列表接口不返回标签的资源(eip、子网)实现
+	list func(id string) ([]corecloud.TagPair, error)
+}
+
+func (h *HuaWei) resTagger(resType enumor.CloudResourceType, region string) (*resTagger, error) {
+	switch resType {
+	case enumor.CvmCloudResType:
+		return h.cvmTagger(region)
+	case enumor.DiskCloudResType:
+		return h.diskTagger(region)
+	case enumor.EipCloudResType:
+		return h.eipTagger(region)
+	case enumor.VpcCloudResType, enumor.SubnetCloudResType:
+		return h.vpcTagger(resType, region)
+	default:
+		return nil, errf.Newf(errf.InvalidParameter, "huawei %s not support tag", resType)
+	}
+}
+
+// cvmTagger 使用云服务器批量添加、删除标签接口
+func (h *HuaWei) cvmTagger(region string) (*resTagger, error) {
+	client, err := h.clientSet.ecsClient(region)
+	if err != nil {
+		return nil, fmt.Errorf("new ecs client failed, err: %v", err)
+	}
+
+	return &resTagger{
+		create: func(id string, tags []corecloud.TagPair) error {
+			serverTags := make([]model.ServerTag, 0, len(tags))
+			for _, tag := range tags {
+				serverTags = append(serverTags, model.ServerTag{Key: tag.Key, Value: tag.Value})
+			}
+			req := &model.BatchCreateServerTagsRequest{
+				ServerId: id,
+				Body: &model.BatchCreateServerTagsRequestBody{
+					Action: model.GetBatchCreateServerTagsRequestBodyActionEnum().CREATE,
+					Tags:   serverTags,
+				},
+			}
+			_, err := client.BatchCreateServerTags(req)
+			return err
+		},
+		delete: func(id string, keys []string) error {
+			serverTags := make([]model.ServerTag, 0, len(keys))
+			for _, key := range keys {
+				serverTags = append(serverTags, model.ServerTag{Key: key})
+			}
+			req := &model.BatchDeleteServerTagsRequest{
+				ServerId: id,
+				Body: &model.BatchDeleteServerTagsRequestBody{
+					Action: model.GetBatchDeleteServerTagsRequestBodyActionEnum().DELETE,
+					Tags:   serverTags,
+				},
+			}
+			_, err := client.BatchDeleteServerTags(req)
+			return err
+		},
+	}, nil
+}
+
+// diskTagger 使用云硬盘批量添加、删除标签接口
+func (h *HuaWei) diskTagger(region string) (*resTagger, error) {
+	client, err := h.clientSet.evsClient(region)
+	if err != nil {
+		return nil, fmt.Errorf("new evs client failed, err: %v", err)
+	}
+
+	return &resTagger{
+		create: func(id string, tags []corecloud.TagPair) error {
+			volumeTags := make([]evsmodel.Tag, 0, len(tags))
+			for _, tag := range tags {
+				volumeTags = append(volumeTags, evsmodel.Tag{Key: tag.Key, Value: tag.Value})
+			}
+			req := &evsmodel.BatchCreateVolumeTagsRequest{
+				VolumeId: id,
+				Body: &evsmodel.BatchCreateVolumeTagsRequestBody{
+					Action: evsmodel.GetBatchCreateVolumeTagsRequestBodyActionEnum().CREATE,
+					Tags:   volumeTags,
+				},
+			}
+			_, err := client.BatchCreateVolumeTags(req)
+			return err
+		},
+		delete: func(id string, keys []string) error {
+			volumeTags := make([]evsmodel.DeleteTagsOption, 0, len(keys))
+			for _, key := range keys {
+				volumeTags = append(volumeTags, evsmodel.DeleteTagsOption{Key: key})
+			}
+			req := &evsmodel.BatchDeleteVolumeTagsRequest{
+				VolumeId: id,
+				Body: &evsmodel.BatchDeleteVolumeTagsRequestBody{
+					Action: evsmodel.GetBatchDeleteVolumeTagsRequestBodyActionEnum().DELETE,
+					Tags:   volumeTags,
+				},
+			}
+			_, err := client.BatchDeleteVolumeTags(req)
+			return err
+		},
+	}, nil
+}
+
+// eipTagger 使用弹性公网IP批量添加、删除标签接口
+func (h *HuaWei) eipTagger(region string) (*resTagger, error) {
+	client, err := h.clientSet.eipClient(region)
+	if err != nil {
+		return nil, fmt.Errorf("new eip client failed, err: %v", err)
+	}
+
+	return &resTagger{
+		create: func(id string, tags []corecloud.TagPair) error {
+			eipTags := make([]eipmodel.ResourceTagOption, 0, len(tags))
+			for _, tag := range tags {
+				eipTags = append(eipTags, eipmodel.ResourceTagOption{Key: tag.Key, Value: tag.Value})
+			}
+			req := &eipmodel.BatchCreatePublicipTagsRequest{
+				PublicipId: id,
+				Body: &eipmodel.BatchCreatePublicipTagsRequestBody{
+					Action: eipmodel.GetBatchCreatePublicipTagsRequestBodyActionEnum().CREATE,
+					Tags:   eipTags,
+				},
+			}
+			_, err := client.BatchCreatePublicipTags(req)
+			return err
+		},
+		delete: func(id string, keys []string) error {
+			eipTags := make([]eipmodel.ResourceTagOption, 0, len(keys))
+			for _, key := range keys {
+				eipTags = append(eipTags, eipmodel.ResourceTagOption{Key: key})
+			}
+			req := &eipmodel.BatchDeletePublicipTagsRequest{
+				PublicipId: id,
+				Body: &eipmodel.BatchDeletePublicipTagsRequestBody{
+					Action: eipmodel.GetBatchDeletePublicipTagsRequestBodyActionEnum().DELETE,
+					Tags:   eipTags,
+				},
+			}
+			_, err := client.BatchDeletePublicipTags(req)
+			return err
+		},
+		list: func(id string) ([]corecloud.TagPair, error) {
+			resp, err := client.ShowPublicipTags(&eipmodel.ShowPublicipTagsRequest{PublicipId: id})
+			if err != nil {
+				return nil, err
+			}
+
+			tags := make([]corecloud.TagPair, 0)
+			if resp.Tags == nil {
+				return tags, nil
+			}
+			for _, tag := range *resp.Tags {
+				tags = append(tags, corecloud.TagPair{
+					Key:   converter.PtrToVal(tag.Key),
+					Value: converter.PtrToVal(tag.Value),
+				})
+			}
+			return tags, nil
+		},
+	}, nil
+}
+
+// vpcTagger 使用虚拟私有云、子网批量添加、删除标签接口
+func (h *HuaWei) vpcTagger(resType enumor.CloudResourceType, region string) (*resTagger, error) {
+	client, err := h.clientSet.vpcClientV2(region)
+	if err != nil {
+		return nil, fmt.Errorf("new vpc client failed, err: %v", err)
+	}
+
+	toResourceTags := func(tags []corecloud.TagPair) []vpcmodel.ResourceTag {
+		result := make([]vpcmodel.ResourceTag, 0, len(tags))
+		for _, tag := range tags {
+			result = append(result, vpcmodel.ResourceTag{Key: tag.Key, Value: tag.Value})
+		}
+		return result
+	}
+	toKeyTags := func(keys []string) []vpcmodel.ResourceTag {
+		result := make([]vpcmodel.ResourceTag, 0, len(keys))
+		for _, key := range keys {
+			result = append(result, vpcmodel.ResourceTag{Key: key})
+		}
+		return result
+	}
+
+	if resType == enumor.SubnetCloudResType {
+		return &resTagger{
+			create: func(id string, tags []corecloud.TagPair) error {
+				req := &vpcmodel.BatchCreateSubnetTagsRequest{
+					SubnetId: id,
+					Body: &vpcmodel.BatchCreateSubnetTagsRequestBody{
+						Action: vpcmodel.GetBatchCreateSubnetTagsRequestBodyActionEnum().CREATE,
+						Tags:   toResourceTags(tags),
+					},
+				}
+				_, err := client.BatchCreateSubnetTags(req)
+				return err
+			},
+			delete: func(id string, keys []string) error {
+				req := &vpcmodel.BatchDeleteSubnetTagsRequest{
+					SubnetId: id,
+					Body: &vpcmodel.BatchDeleteSubnetTagsRequestBody{
+						Action: vpcmodel.GetBatchDeleteSubnetTagsRequestBodyActionEnum().DELETE,
+						Tags:   toKeyTags(keys),
+					},
+				}
+				_, err := client.BatchDeleteSubnetTags(req)
+				return err
+			},
+			list: func(id string) ([]corecloud.TagPair, error) {
+				resp, err := client.ShowSubnetTags(&vpcmodel.ShowSubnetTagsRequest{SubnetId: id})
+				if err != nil {
+					return nil, err
+				}
+
+				tags := make([]corecloud.TagPair, 0)
+				if resp.Tags == nil {
+					return tags, nil
+				}
+				for _, tag := range *resp.Tags {
+					tags = append(tags, corecloud.TagPair{Key: tag.Key, Value: tag.Value})
+				}
+				return tags, nil
+			},
+		}, nil
+	}
+
+	return &resTagger{
+		create: func(id string, tags []corecloud.TagPair) error {
+			req := &vpcmodel.BatchCreateVpcTagsRequest{
+				VpcId: id,
+				Body: &vpcmodel.BatchCreateVpcTagsRequestBody{
+					Action: vpcmodel.GetBatchCreateVpcTagsRequestBodyActionEnum().CREATE,
+					Tags:   toResourceTags(tags),
+				},
+			}
+			_, err := client.BatchCreateVpcTags(req)
+			return err
+		},
+		delete: func(id string, keys []string) error {
+			req := &vpcmodel.BatchDeleteVpcTagsRequest{
+				VpcId: id,
+				Body: &vpcmodel.BatchDeleteVpcTagsRequestBody{
+					Action: vpcmodel.GetBatchDeleteVpcTagsRequestBodyActionEnum().DELETE,
+					Tags:   toKeyTags(keys),
+				},
+			}
+			_, err := client.BatchDeleteVpcTags(req)
+			return err
+		},
+	}, nil
+}
+
+// convertVpcTags convert huawei vpc tags to resource tags.
+func convertVpcTags(tags []vpcv3model.Tag) []corecloud.TagPair {
+	result := make([]corecloud.TagPair, 0, len(tags))
+	for _, tag := range tags {
+		result = append(result, corecloud.TagPair{Key: tag.Key, Value: tag.Value})
+	}
+
+	return result
+}
@@ -230,6 +230,7 @@ func convertVpc(data *model.Vpc, region string) *types.HuaWeiVpc {
 		Name:    data.Name,
 		Region:  region,
 		Memo:    converter.ValToPtr(data.Description),
+		Tags:    convertVpcTags(data.Tags),
 		Extension: &cloud.HuaWeiVpcExtension{
 			Cidr:                nil,
 			Status:              data.Status,
@@ -8,6 +8,7 @@ import (
 	poller "hcm/pkg/adaptor/poller"
 	types "hcm/pkg/adaptor/types"
 	account "hcm/pkg/adaptor/types/account"
+	auditevent "hcm/pkg/adaptor/types/audit-event"
 	bill "hcm/pkg/adaptor/types/bill"
 	core "hcm/pkg/adaptor/types/core"
 	cvm "hcm/pkg/adaptor/types/cvm"
@@ -20,6 +21,7 @@ import (
 	securitygroup "hcm/pkg/adaptor/types/security-group"
 	securitygrouprule "hcm/pkg/adaptor/types/security-group-rule"
 	adtysubnet "hcm/pkg/adaptor/types/subnet"
+	tag "hcm/pkg/adaptor/types/tag"
 	zone "hcm/pkg/adaptor/types/zone"
 	cloud "hcm/pkg/api/core/cloud"
 	kit "hcm/pkg/kit"
@@ -1407,6 +1409,45 @@ func (c *TCloudListAccountCall) DoAndReturn(f func(*kit.Kit) ([]account.TCloudAc
 	return c
 }
 
+// ListAuditEvent mocks base method.
+func (m *MockTCloud) ListAuditEvent(kt *kit.Kit, opt *auditevent.ListOption) ([]auditevent.AuditEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAuditEvent", kt, opt)
+	ret0, _ := ret[0].([]auditevent.AuditEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAuditEvent indicates an expected call of ListAuditEvent.
+func (mr *MockTCloudMockRecorder) ListAuditEvent(kt, opt interface{}) *TCloudListAuditEventCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAuditEvent", reflect.TypeOf((*MockTCloud)(nil).ListAuditEvent), kt, opt)
+	return &TCloudListAuditEventCall{Call: call}
+}
+
+// TCloudListAuditEventCall wrap *gomock.Call
+type TCloudListAuditEventCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *TCloudListAuditEventCall) Return(arg0 []auditevent.AuditEvent, arg1 error) *TCloudListAuditEventCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *TCloudListAuditEventCall) Do(f func(*kit.Kit, *auditevent.ListOption) ([]auditevent.AuditEvent, error)) *TCloudListAuditEventCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *TCloudListAuditEventCall) DoAndReturn(f func(*kit.Kit, *auditevent.ListOption) ([]auditevent.AuditEvent, error)) *TCloudListAuditEventCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
 // ListCvm mocks base method.
 func (m *MockTCloud) ListCvm(kt *kit.Kit, opt *cvm.TCloudListOption) ([]cvm.TCloudCvm, error) {
 	m.ctrl.T.Helper()
@@ -2142,6 +2183,82 @@ func (c *TCloudStopCvmCall) DoAndReturn(f func(*kit.Kit, *cvm.TCloudStopOption)
 	return c
 }
 
+// TagResource mocks base method.
+func (m *MockTCloud) TagResource(kt *kit.Kit, opt *tag.TagOption) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TagResource", kt, opt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// TagResource indicates an expected call of TagResource.
+func (mr *MockTCloudMockRecorder) TagResource(kt, opt interface{}) *TCloudTagResourceCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TagResource", reflect.TypeOf((*MockTCloud)(nil).TagResource), kt, opt)
+	return &TCloudTagResourceCall{Call: call}
+}
+
+// TCloudTagResourceCall wrap *gomock.Call
+type TCloudTagResourceCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *TCloudTagResourceCall) Return(arg0 error) *TCloudTagResourceCall {
+	c.Call = c.Call.Return(arg0)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *TCloudTagResourceCall) Do(f func(*kit.Kit, *tag.TagOption) error) *TCloudTagResourceCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *TCloudTagResourceCall) DoAndReturn(f func(*kit.Kit, *tag.TagOption) error) *TCloudTagResourceCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// UnTagResource mocks base method.
+func (m *MockTCloud) UnTagResource(kt *kit.Kit, opt *tag.UnTagOption) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnTagResource", kt, opt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UnTagResource indicates an expected call of UnTagResource.
+func (mr *MockTCloudMockRecorder) UnTagResource(kt, opt interface{}) *TCloudUnTagResourceCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnTagResource", reflect.TypeOf((*MockTCloud)(nil).UnTagResource), kt, opt)
+	return &TCloudUnTagResourceCall{Call: call}
+}
+
+// TCloudUnTagResourceCall wrap *gomock.Call
+type TCloudUnTagResourceCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *TCloudUnTagResourceCall) Return(arg0 error) *TCloudUnTagResourceCall {
+	c.Call = c.Call.Return(arg0)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *TCloudUnTagResourceCall) Do(f func(*kit.Kit, *tag.UnTagOption) error) *TCloudUnTagResourceCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *TCloudUnTagResourceCall) DoAndReturn(f func(*kit.Kit, *tag.UnTagOption) error) *TCloudUnTagResourceCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
 // UpdateRouteTable mocks base method.
 func (m *MockTCloud) UpdateRouteTable(arg0 *kit.Kit, arg1 *routetable.TCloudRouteTableUpdateOption) error {
 	m.ctrl.T.Helper()
@@ -0,0 +1,131 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package tcloud
+
+import (
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"hcm/pkg/adaptor/types/audit-event"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+
+	tchttp "github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common/http"
+)
+
+const (
+	cloudAuditService = "cloudaudit"
+	cloudAuditVersion = "2019-03-19"
+	// cloudAuditQueryLimit 云审计单次查询的最大事件数量
+	cloudAuditQueryLimit = 50
+)
+
+// lookUpEventsResp is the response of cloud audit LookUpEvents.
+type lookUpEventsResp struct {
+	Response struct {
+		Events []struct {
+			EventId     string `json:"EventId"`
+			EventName   string `json:"EventName"`
+			EventTime   string `json:"EventTime"`
+			EventRegion string `json:"EventRegion"`
+			Resources   *struct {
+				ResourceType string `json:"ResourceType"`
+				ResourceName string `json:"ResourceName"`
+			} `json:"Resources"`
+		} `json:"Events"`
+		ListOver  bool   `json:"ListOver"`
+		NextToken string `json:"NextToken"`
+	} `json:"Response"`
+}
+
+// ListAuditEvent list write events recorded by cloud audit in the region.
+// reference: https://cloud.tencent.com/document/api/629/50952
+func (t *TCloudImpl) ListAuditEvent(kt *kit.Kit, opt *auditevent.ListOption) ([]auditevent.AuditEvent, error) {
+	if opt == nil {
+		return nil, errf.New(errf.InvalidParameter, "list audit event option is required")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	if len(opt.Region) == 0 {
+		return nil, errf.New(errf.InvalidParameter, "region is required")
+	}
+
+	params := map[string]interface{}{
+		"StartTime":        opt.StartTime.Unix(),
+		"EndTime":          opt.EndTime.Unix(),
+		"MaxResults":       cloudAuditQueryLimit,
+		"LookupAttributes": []map[string]string{{"AttributeKey": "ReadOnly", "AttributeValue": "false"}},
+	}
+
+	events := make([]auditevent.AuditEvent, 0)
+	for {
+		req := tchttp.NewCommonRequest(cloudAuditService, cloudAuditVersion, "LookUpEvents")
+		if err := req.SetActionParameters(params); err != nil {
+			return nil, err
+		}
+		req.SetContext(kt.Ctx)
+
+		resp := tchttp.NewCommonResponse()
+		if err := t.clientSet.cloudAuditClient(opt.Region).Send(req, resp); err != nil {
+			logs.Errorf("look up tcloud audit events failed, err: %v, region: %s, rid: %s", err, opt.Region, kt.Rid)
+			return nil, err
+		}
+
+		result := new(lookUpEventsResp)
+		if err := json.Unmarshal(resp.GetBody(), result); err != nil {
+			return nil, err
+		}
+
+		for _, one := range result.Response.Events {
+			if one.Resources == nil || len(one.Resources.ResourceName) == 0 {
+				continue
+			}
+
+			event := auditevent.AuditEvent{
+				ID:     one.EventId,
+				Name:   one.EventName,
+				Region: one.EventRegion,
+				Resources: []auditevent.Resource{{
+					Type: one.Resources.ResourceType,
+					ID:   one.Resources.ResourceName,
+				}},
+			}
+			if len(event.Region) == 0 {
+				event.Region = opt.Region
+			}
+			if sec, err := strconv.ParseInt(one.EventTime, 10, 64); err == nil {
+				event.Time = time.Unix(sec, 0)
+			}
+			events = append(events, event)
+		}
+
+		if result.Response.ListOver || len(result.Response.NextToken) == 0 {
+			break
+		}
+		params["NextToken"] = result.Response.NextToken
+	}
+
+	return events, nil
+}
@@ -91,3 +91,13 @@ func (c *clientSet) billClient() (*billing.Client, error) {
 
 	return client, nil
 }
+
+// tagClient 标签服务暂未引入独立的sdk，使用通用客户端调用。
+func (c *clientSet) tagClient(region string) *common.Client {
+	return common.NewCommonClient(c.credential, region, c.profile)
+}
+
+// cloudAuditClient 云审计服务暂未引入独立的sdk，使用通用客户端调用。
+func (c *clientSet) cloudAuditClient(region string) *common.Client {
+	return common.NewCommonClient(c.credential, region, c.profile)
+}
@@ -326,6 +326,7 @@ func (t *TCloudImpl) CreateCvm(kt *kit.Kit, opt *typecvm.TCloudCreateOption) (*p
 		InternetMaxBandwidthOut: common.Int64Ptr(opt.InternetMaxBandwidthOut),
 		PublicIpAssigned:        common.BoolPtr(opt.PublicIPAssigned),
 	}
+	req.UserData = opt.UserData
 
 	req.SystemDisk = &cvm.SystemDisk{
 		DiskId:   opt.SystemDisk.CloudDiskID,
@@ -25,6 +25,7 @@ import (
 	"hcm/pkg/adaptor/poller"
 	"hcm/pkg/adaptor/types"
 	"hcm/pkg/adaptor/types/account"
+	"hcm/pkg/adaptor/types/audit-event"
 	typesBill "hcm/pkg/adaptor/types/bill"
 	"hcm/pkg/adaptor/types/core"
 	"hcm/pkg/adaptor/types/cvm"
@@ -37,6 +38,7 @@ import (
 	"hcm/pkg/adaptor/types/security-group"
 	"hcm/pkg/adaptor/types/security-group-rule"
 	"hcm/pkg/adaptor/types/subnet"
+	"hcm/pkg/adaptor/types/tag"
 	"hcm/pkg/adaptor/types/zone"
 	"hcm/pkg/api/core/cloud"
 	"hcm/pkg/kit"
@@ -120,6 +122,9 @@ type TCloud interface {
 	CreateCvm(kt *kit.Kit, opt *cvm.TCloudCreateOption) (*poller.BaseDoneResult, error)
 	InquiryPriceCvm(kt *kit.Kit, opt *cvm.TCloudCreateOption) (
 		*cvm.InquiryPriceResult, error)
+	TagResource(kt *kit.Kit, opt *tag.TagOption) error
+	UnTagResource(kt *kit.Kit, opt *tag.UnTagOption) error
+	ListAuditEvent(kt *kit.Kit, opt *auditevent.ListOption) ([]auditevent.AuditEvent, error)
 	ListPoliciesGrantingServiceAccess(kt *kit.Kit, opt *account.TCloudListPolicyOption) (
 		[]*v20190116.ListGrantServiceAccessNode, error)
 }
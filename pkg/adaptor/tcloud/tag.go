@@ -0,0 +1,160 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package tcloud
+
+import (
+	"fmt"
+
+	typetag "hcm/pkg/adaptor/types/tag"
+	corecloud "hcm/pkg/api/core/cloud"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/tools/converter"
+
+	tchttp "github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common/http"
+	vpc "github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/vpc/v20170312"
+)
+
+const (
+	tagService = "tag"
+	tagVersion = "2018-08-13"
+)
+
+// tagResourcePrefix 各资源在资源六段式中的服务类型和资源前缀
+var tagResourcePrefix = map[enumor.CloudResourceType][2]string{
+	enumor.CvmCloudResType:    {"cvm", "instance"},
+	enumor.DiskCloudResType:   {"cvm", "volume"},
+	enumor.EipCloudResType:    {"cvm", "eip"},
+	enumor.VpcCloudResType:    {"vpc", "vpc"},
+	enumor.SubnetCloudResType: {"vpc", "subnet"},
+}
+
+// TagResource add or overwrite tags of resource.
+// reference: https://cloud.tencent.com/document/api/651/72275
+func (t *TCloudImpl) TagResource(kt *kit.Kit, opt *typetag.TagOption) error {
+	if opt == nil {
+		return errf.New(errf.InvalidParameter, "tag resource option is required")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	resources, err := t.tagResources(kt, opt.ResType, opt.Region, opt.CloudIDs)
+	if err != nil {
+		return err
+	}
+
+	tags := make([]map[string]string, 0, len(opt.Tags))
+	for _, tag := range opt.Tags {
+		tags = append(tags, map[string]string{"TagKey": tag.Key, "TagValue": tag.Value})
+	}
+
+	params := map[string]interface{}{
+		"ResourceList": resources,
+		"Tags":         tags,
+	}
+	if err = t.sendTagRequest(kt, opt.Region, "TagResources", params); err != nil {
+		logs.Errorf("tag %s failed, err: %v, ids: %v, rid: %s", opt.ResType, err, opt.CloudIDs, kt.Rid)
+		return err
+	}
+
+	return nil
+}
+
+// UnTagResource remove tags of resource.
+// reference: https://cloud.tencent.com/document/api/651/72274
+func (t *TCloudImpl) UnTagResource(kt *kit.Kit, opt *typetag.UnTagOption) error {
+	if opt == nil {
+		return errf.New(errf.InvalidParameter, "untag resource option is required")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	resources, err := t.tagResources(kt, opt.ResType, opt.Region, opt.CloudIDs)
+	if err != nil {
+		return err
+	}
+
+	params := map[string]interface{}{
+		"ResourceList": resources,
+		"TagKeys":      opt.TagKeys,
+	}
+	if err = t.sendTagRequest(kt, opt.Region, "UnTagResources", params); err != nil {
+		logs.Errorf("untag %s failed, err: %v, ids: %v, rid: %s", opt.ResType, err, opt.CloudIDs, kt.Rid)
+		return err
+	}
+
+	return nil
+}
+
+// tagResources 生成标签接口需要的资源六段式，格式为 qcs::{service}:{region}:uin/{uin}:{prefix}/{id}
+func (t *TCloudImpl) tagResources(kt *kit.Kit, resType enumor.CloudResourceType, region string,
+	cloudIDs []string) ([]string, error) {
+
+	prefix, exist := tagResourcePrefix[resType]
+	if !exist {
+		return nil, errf.Newf(errf.InvalidParameter, "tcloud %s not support tag", resType)
+	}
+
+	info, err := t.GetAccountInfoBySecret(kt)
+	if err != nil {
+		return nil, err
+	}
+
+	resources := make([]string, 0, len(cloudIDs))
+	for _, id := range cloudIDs {
+		resources = append(resources, fmt.Sprintf("qcs::%s:%s:uin/%s:%s/%s", prefix[0], region,
+			info.CloudMainAccountID, prefix[1], id))
+	}
+
+	return resources, nil
+}
+
+func (t *TCloudImpl) sendTagRequest(kt *kit.Kit, region, action string, params map[string]interface{}) error {
+	req := tchttp.NewCommonRequest(tagService, tagVersion, action)
+	if err := req.SetActionParameters(params); err != nil {
+		return err
+	}
+	req.SetContext(kt.Ctx)
+
+	return t.clientSet.tagClient(region).Send(req, tchttp.NewCommonResponse())
+}
+
+// convertVpcTags 转换私有网络产品(vpc、子网、eip)的标签
+func convertVpcTags(tags []*vpc.Tag) []corecloud.TagPair {
+	result := make([]corecloud.TagPair, 0, len(tags))
+	for _, tag := range tags {
+		if tag == nil {
+			continue
+		}
+
+		result = append(result, corecloud.TagPair{
+			Key:   converter.PtrToVal(tag.Key),
+			Value: converter.PtrToVal(tag.Value),
+		})
+	}
+
+	return result
+}
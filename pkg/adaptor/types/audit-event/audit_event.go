@@ -0,0 +1,71 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package auditevent defines cloud audit event types.
+package auditevent
+
+import (
+	"errors"
+	"time"
+
+	"hcm/pkg/criteria/validator"
+)
+
+// ListOption defines options to list cloud audit events.
+type ListOption struct {
+	// Region 地域，tcloud/aws/huawei 审计事件按地域查询，必填；gcp/azure 审计日志为全局，无需指定
+	Region    string    `json:"region" validate:"omitempty"`
+	StartTime time.Time `json:"start_time" validate:"required"`
+	EndTime   time.Time `json:"end_time" validate:"required"`
+}
+
+// Validate audit event list option.
+func (opt ListOption) Validate() error {
+	if err := validator.Validate.Struct(opt); err != nil {
+		return err
+	}
+
+	if !opt.EndTime.After(opt.StartTime) {
+		return errors.New("end_time must be after start_time")
+	}
+
+	return nil
+}
+
+// AuditEvent 云上审计事件，各云厂商的审计事件（CloudTrail、CloudAudit、CTS、Activity Log、Audit Logs）统一转换为该结构。
+// 仅保留增量同步需要的信息，资源类型保留云上原始取值，由使用方按云厂商映射。
+type AuditEvent struct {
+	ID        string     `json:"id"`
+	Name      string     `json:"name"`
+	Region    string     `json:"region"`
+	Time      time.Time  `json:"time"`
+	Resources []Resource `json:"resources"`
+}
+
+// Resource 审计事件涉及的云资源。
+type Resource struct {
+	// Type 云上资源类型，如 aws 为 AWS::EC2::Instance，gcp 为 gce_instance，azure 为 microsoft.compute/virtualmachines
+	Type string `json:"type"`
+	// ID 资源云ID，azure 为资源ID全路径
+	ID string `json:"id"`
+	// Zone 资源所在可用区，仅gcp可用区级资源需要
+	Zone string `json:"zone,omitempty"`
+	// Parent 父资源云ID，如子网所属的vpc云ID
+	Parent string `json:"parent,omitempty"`
+}
@@ -135,6 +135,8 @@ type AwsCreateOption struct {
 	CloudSubnetID         string                  `json:"cloud_subnet_id" validate:"required"`
 	BlockDeviceMapping    []AwsBlockDeviceMapping `json:"block_device_mapping" validate:"required"`
 	PublicIPAssigned      bool                    `json:"public_ip_assigned" validate:"omitempty"`
+	// UserData 自定义数据(base64 编码)，会与设置密码的脚本合并后下发
+	UserData *string `json:"user_data" validate:"omitempty"`
 }
 
 // AwsBlockDeviceMapping ...
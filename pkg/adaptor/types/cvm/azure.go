@@ -118,6 +118,8 @@ type AzureCreateOption struct {
 	OSDisk               *AzureOSDisk    `json:"os_disk" validate:"required"`
 	DataDisk             []AzureDataDisk `json:"data_disk" validate:"omitempty"`
 	PublicIPAssigned     bool            `json:"public_ip_assigned" validate:"omitempty"`
+	// UserData 自定义数据，需为 base64 编码
+	UserData *string `json:"user_data" validate:"omitempty"`
 }
 
 // AzureImage ...
@@ -204,6 +206,7 @@ type AzureCvm struct {
 	VCPUsPerCore        *int32                                        `json:"vcpus_per_core"`
 	TimeCreated         *time.Time                                    `json:"time_created"`
 	StorageProfile      *armcompute.StorageProfile                    `json:"storage_profile"`
+	Tags                map[string]*string                            `json:"tags"`
 }
 
 // GetCloudID ...
@@ -131,6 +131,8 @@ type GcpCreateOption struct {
 	SystemDisk       *GcpOsDisk          `json:"system_disk" validate:"required"`
 	DataDisk         []GcpDataDisk       `json:"data_disk" validate:"omitempty"`
 	PublicIPAssigned bool                `json:"public_ip_assigned" validate:"omitempty"`
+	// UserData 自定义数据(明文)，通过实例元数据 user-data 下发
+	UserData *string `json:"user_data" validate:"omitempty"`
 }
 
 // Validate gcp cvm operation option.
@@ -144,6 +144,8 @@ type HuaWeiCreateOption struct {
 	InstanceCharge        *HuaWeiInstanceCharge `json:"instance_charge" validate:"required"`
 	PublicIPAssigned      bool                  `json:"public_ip_assigned" validate:"omitempty"`
 	Eip                   *HuaWeiEip            `json:"eip" validate:"omitempty"`
+	// UserData 自定义数据，需为 base64 编码
+	UserData *string `json:"user_data" validate:"omitempty"`
 }
 
 // Validate aws cvm operation option.
@@ -180,6 +180,8 @@ type TCloudCreateOption struct {
 	DataDisk                []TCloudDataDisk             `json:"data_disk" validate:"omitempty"`
 	PublicIPAssigned        bool                         `json:"public_ip_assigned" validate:"omitempty"`
 	InternetMaxBandwidthOut int64                        `json:"internet_max_bandwidth_out" validate:"omitempty"`
+	// UserData 自定义数据，需为 base64 编码
+	UserData *string `json:"user_data" validate:"omitempty"`
 }
 
 // Validate aws cvm operation option.
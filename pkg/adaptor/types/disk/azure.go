@@ -20,6 +20,7 @@
 package disk
 
 import (
+	corecloud "hcm/pkg/api/core/cloud"
 	"hcm/pkg/criteria/validator"
 	"hcm/pkg/tools/converter"
 
@@ -144,6 +145,7 @@ type AzureDisk struct {
 	SKUName  *string   `json:"sku_name"`
 	SKUTier  *string   `json:"sku_tier"`
 	Boot     *bool
+	Tags     []corecloud.TagPair `json:"tags,omitempty"`
 }
 
 // GetCloudID ...
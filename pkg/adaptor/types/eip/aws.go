@@ -20,6 +20,7 @@
 package eip
 
 import (
+	corecloud "hcm/pkg/api/core/cloud"
 	"hcm/pkg/criteria/validator"
 
 	"github.com/aws/aws-sdk-go/aws"
@@ -59,6 +60,7 @@ type AwsEip struct {
 	NetworkBorderGroup      *string
 	NetworkInterfaceId      *string
 	NetworkInterfaceOwnerId *string
+	Tags                    []corecloud.TagPair
 }
 
 // GetCloudID ...
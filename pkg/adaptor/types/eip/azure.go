@@ -20,6 +20,7 @@
 package eip
 
 import (
+	corecloud "hcm/pkg/api/core/cloud"
 	"hcm/pkg/criteria/validator"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
@@ -79,6 +80,7 @@ type AzureEip struct {
 	Fqdn                   *string
 	Zones                  []*string
 	PublicIPAddressVersion *string
+	Tags                   []corecloud.TagPair
 }
 
 // GetCloudID ...
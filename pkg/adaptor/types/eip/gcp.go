@@ -21,6 +21,7 @@ package eip
 
 import (
 	"hcm/pkg/adaptor/types/core"
+	corecloud "hcm/pkg/api/core/cloud"
 	"hcm/pkg/criteria/validator"
 
 	"google.golang.org/api/compute/v1"
@@ -80,6 +81,8 @@ type GcpEip struct {
 	Subnetwork   string
 	SelfLink     string
 	Users        []string
+	// Tags gcp 使用 labels 作为资源标签
+	Tags []corecloud.TagPair
 }
 
 // GetCloudID ...
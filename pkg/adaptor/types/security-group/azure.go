@@ -20,6 +20,7 @@
 package securitygroup
 
 import (
+	corecloud "hcm/pkg/api/core/cloud"
 	"hcm/pkg/criteria/validator"
 	"hcm/pkg/tools/converter"
 
@@ -108,6 +109,7 @@ type AzureSecurityGroup struct {
 	FlushConnection *bool                      `json:"flush_connection"`
 	ResourceGUID    *string                    `json:"resource_guid"`
 	SecurityRules   []*armnetwork.SecurityRule `json:"security_rules"`
+	Tags            []corecloud.TagPair        `json:"tags,omitempty"`
 }
 
 // GetCloudID ...
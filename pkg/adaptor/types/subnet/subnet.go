@@ -20,6 +20,7 @@
 package adtysubnet
 
 import (
+	corecloud "hcm/pkg/api/core/cloud"
 	"hcm/pkg/criteria/errf"
 )
 
@@ -73,6 +74,8 @@ type Subnet[T SubnetExtension] struct {
 	Ipv6Cidr   []string `json:"ipv6_cidr,omitempty"`
 	Memo       *string  `json:"memo,omitempty"`
 	Extension  *T       `json:"extension"`
+	// Tags 云上标签，不支持标签或列表接口不返回标签的云厂商为空
+	Tags []corecloud.TagPair `json:"tags,omitempty"`
 }
 
 // SubnetExtension defines subnet extensional info.
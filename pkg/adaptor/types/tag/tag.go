@@ -0,0 +1,104 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package tag defines resource tag operation options.
+package tag
+
+import (
+	"fmt"
+
+	corecloud "hcm/pkg/api/core/cloud"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+)
+
+// -------------------------- Tag --------------------------
+
+// TagOption defines options to add or overwrite tags of cloud resources.
+type TagOption struct {
+	ResType enumor.CloudResourceType `json:"res_type" validate:"required"`
+	Region  string                   `json:"region" validate:"required"`
+	// Zone 仅gcp可用区资源(主机、硬盘)需要
+	Zone string `json:"zone" validate:"omitempty"`
+	// CloudIDs 资源云ID，gcp为资源名称
+	CloudIDs []string            `json:"cloud_ids" validate:"required,min=1"`
+	Tags     []corecloud.TagPair `json:"tags" validate:"required,min=1,dive"`
+}
+
+// Validate tag option.
+func (opt TagOption) Validate() error {
+	if err := validator.Validate.Struct(opt); err != nil {
+		return err
+	}
+
+	if len(opt.CloudIDs) > constant.BatchOperationMaxLimit {
+		return fmt.Errorf("cloud_ids should <= %d", constant.BatchOperationMaxLimit)
+	}
+
+	return nil
+}
+
+// -------------------------- UnTag --------------------------
+
+// UnTagOption defines options to remove tags of cloud resources.
+type UnTagOption struct {
+	ResType enumor.CloudResourceType `json:"res_type" validate:"required"`
+	Region  string                   `json:"region" validate:"required"`
+	// Zone 仅gcp可用区资源(主机、硬盘)需要
+	Zone string `json:"zone" validate:"omitempty"`
+	// CloudIDs 资源云ID，gcp为资源名称
+	CloudIDs []string `json:"cloud_ids" validate:"required,min=1"`
+	TagKeys  []string `json:"tag_keys" validate:"required,min=1"`
+}
+
+// Validate untag option.
+func (opt UnTagOption) Validate() error {
+	if err := validator.Validate.Struct(opt); err != nil {
+		return err
+	}
+
+	if len(opt.CloudIDs) > constant.BatchOperationMaxLimit {
+		return fmt.Errorf("cloud_ids should <= %d", constant.BatchOperationMaxLimit)
+	}
+
+	return nil
+}
+
+// -------------------------- List --------------------------
+
+// ListTagOption defines options to list tags of cloud resources.
+type ListTagOption struct {
+	ResType  enumor.CloudResourceType `json:"res_type" validate:"required"`
+	Region   string                   `json:"region" validate:"required"`
+	CloudIDs []string                 `json:"cloud_ids" validate:"required,min=1"`
+}
+
+// Validate list tag option.
+func (opt ListTagOption) Validate() error {
+	if err := validator.Validate.Struct(opt); err != nil {
+		return err
+	}
+
+	if len(opt.CloudIDs) > constant.BatchOperationMaxLimit {
+		return fmt.Errorf("cloud_ids should <= %d", constant.BatchOperationMaxLimit)
+	}
+
+	return nil
+}
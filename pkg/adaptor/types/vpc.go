@@ -276,6 +276,8 @@ type Vpc[T VpcExtension] struct {
 	Region    string  `json:"region"`
 	Memo      *string `json:"memo,omitempty"`
 	Extension *T      `json:"extension"`
+	// Tags 云上标签，不支持标签或列表接口不返回标签的云厂商为空
+	Tags []cloud.TagPair `json:"tags,omitempty"`
 }
 
 // AzureVpcExtension defines azure vpc extensional info.
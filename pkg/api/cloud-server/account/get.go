@@ -21,12 +21,15 @@ package account
 
 import (
 	"hcm/pkg/api/core/cloud"
+	coresync "hcm/pkg/api/core/cloud/sync"
 	"hcm/pkg/rest"
 )
 
 // SyncDetailRsp ...
 type SyncDetailRsp struct {
 	IassRes []IassResItem `json:"iass_res"`
+	// History 账号最近的资源同步历史，按同步时间倒序排列。
+	History []coresync.AccountSyncHistory `json:"history"`
 }
 
 // IassResItem ...
@@ -35,6 +38,21 @@ type IassResItem struct {
 	ResStatus       string `json:"res_status"`
 	ResFailedReason string `json:"res_failed_reason"`
 	ResEndTime      string `json:"res_end_time"`
+	// LastSync 该资源最近一次同步的统计，为各地域同步结果的汇总。
+	LastSync *SyncStatistic `json:"last_sync,omitempty"`
+}
+
+// SyncStatistic 资源同步统计。
+type SyncStatistic struct {
+	SyncRid      string `json:"sync_rid"`
+	RegionCount  int    `json:"region_count"`
+	CreatedCount int64  `json:"created_count"`
+	UpdatedCount int64  `json:"updated_count"`
+	DeletedCount int64  `json:"deleted_count"`
+	// QuarantinedCount 因删除数量超过阈值而被隔离、待确认删除的资源数量。
+	QuarantinedCount int64 `json:"quarantined_count"`
+	CloudApiCount    int64 `json:"cloud_api_count"`
+	CostMs           int64 `json:"cost_ms"`
 }
 
 // BySecretResp 根据秘钥获取的字段
@@ -0,0 +1,88 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package account
+
+import (
+	"errors"
+
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+)
+
+// SelectiveSyncReq 账号定向同步请求，按地域、资源类型同步，或同步一类资源中指定云ID、名称的资源。
+type SelectiveSyncReq struct {
+	// Regions 同步的地域，azure 为资源组名称，为空时同步全部地域；指定云ID或名称时最多只能指定一个地域
+	Regions []string `json:"regions" validate:"omitempty,max=100"`
+	// ResTypes 同步的资源类型，为空时同步全部资源；指定云ID或名称时必须且只能指定一类资源
+	ResTypes []enumor.CloudResourceType `json:"res_types" validate:"omitempty,max=20"`
+	// CloudIDs 同步的资源云ID
+	CloudIDs []string `json:"cloud_ids" validate:"omitempty,max=100"`
+	// Names 同步的资源名称，根据已同步到本地的资源解析为云ID，尚未同步到本地的资源需要通过云ID同步
+	Names []string `json:"names" validate:"omitempty,max=100"`
+	// Zone 资源所在可用区，按云ID同步 gcp 的主机和硬盘时必填
+	Zone string `json:"zone" validate:"omitempty"`
+	// CloudVpcID 子网所属的VPC云ID，按云ID同步 huawei/azure 的子网时必填
+	CloudVpcID string `json:"cloud_vpc_id" validate:"omitempty"`
+}
+
+// Validate SelectiveSyncReq.
+func (req *SelectiveSyncReq) Validate() error {
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	if len(req.CloudIDs) == 0 && len(req.Names) == 0 {
+		return nil
+	}
+
+	if len(req.ResTypes) != 1 {
+		return errors.New("one and only one res_type should be specified when cloud_ids or names is set")
+	}
+
+	if len(req.Regions) > 1 {
+		return errors.New("at most one region can be specified when cloud_ids or names is set")
+	}
+
+	return nil
+}
+
+// SelectiveSyncResult 账号定向同步结果
+type SelectiveSyncResult struct {
+	// FlowID 定向同步任务流ID，用于查询同步进度
+	FlowID string `json:"flow_id"`
+	// UnresolvedNames 未找到对应资源的名称
+	UnresolvedNames []string `json:"unresolved_names,omitempty"`
+}
+
+// SelectiveSyncStatus 账号定向同步进度
+type SelectiveSyncStatus struct {
+	FlowID string                 `json:"flow_id"`
+	State  enumor.FlowState       `json:"state"`
+	Tasks  []SelectiveSyncTaskRun `json:"tasks"`
+}
+
+// SelectiveSyncTaskRun 定向同步任务流中单个任务的执行情况
+type SelectiveSyncTaskRun struct {
+	ActionID string                   `json:"action_id"`
+	ResType  enumor.CloudResourceType `json:"res_type"`
+	Region   string                   `json:"region"`
+	State    enumor.TaskState         `json:"state"`
+	Reason   string                   `json:"reason,omitempty"`
+}
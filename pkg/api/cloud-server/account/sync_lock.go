@@ -0,0 +1,49 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package account
+
+import "hcm/pkg/criteria/validator"
+
+// ListSyncLockReq 查询账号同步锁请求，账号ID为空时查询全部有权限账号的同步锁
+type ListSyncLockReq struct {
+	AccountIDs []string `json:"account_ids" validate:"omitempty,max=100"`
+}
+
+// Validate ...
+func (req *ListSyncLockReq) Validate() error {
+	return validator.Validate.Struct(req)
+}
+
+// ListSyncLockResult 账号同步锁列表
+type ListSyncLockResult struct {
+	Details []SyncLock `json:"details"`
+}
+
+// SyncLock 账号同步锁信息
+type SyncLock struct {
+	AccountID string `json:"account_id"`
+	// Holder 持有锁的 cloud-server 实例
+	Holder    string `json:"holder"`
+	StartTime string `json:"start_time"`
+	// FencingToken 锁的 fencing token，每次加锁单调递增，data-service 据此拒绝已失去锁的同步写入
+	FencingToken int64 `json:"fencing_token"`
+	// TTL 锁租约剩余有效时间，单位秒，锁持有期间会自动续约
+	TTL int64 `json:"ttl"`
+}
@@ -0,0 +1,78 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+package account
+
+import (
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+)
+
+// SyncScheduleStatus 账号同步调度状态
+type SyncScheduleStatus string
+
+const (
+	// SyncScheduleWaiting 等待调度
+	SyncScheduleWaiting SyncScheduleStatus = "waiting"
+	// SyncScheduleRunning 同步中
+	SyncScheduleRunning SyncScheduleStatus = "running"
+	// SyncScheduleBackoff 同步失败，退避等待重试
+	SyncScheduleBackoff SyncScheduleStatus = "backoff"
+)
+
+// ListSyncScheduleReq 查询账号同步调度队列请求
+type ListSyncScheduleReq struct {
+	Vendor     enumor.Vendor `json:"vendor" validate:"omitempty"`
+	AccountIDs []string      `json:"account_ids" validate:"omitempty,max=100"`
+}
+
+// Validate ...
+func (req *ListSyncScheduleReq) Validate() error {
+	return validator.Validate.Struct(req)
+}
+
+// ListSyncScheduleResult 账号同步调度队列，按下次同步时间排序。调度器仅在主节点运行，非主节点返回空队列。
+type ListSyncScheduleResult struct {
+	IsMaster bool            `json:"is_master"`
+	Details  []*SyncSchedule `json:"details"`
+}
+
+// SyncSchedule 账号同步调度信息
+type SyncSchedule struct {
+	AccountID       string             `json:"account_id"`
+	Vendor          enumor.Vendor      `json:"vendor"`
+	Priority        int                `json:"priority"`
+	IntervalMin     uint64             `json:"interval_min"`
+	Status          SyncScheduleStatus `json:"status"`
+	NextRunTime     string             `json:"next_run_time"`
+	LastRunTime     string             `json:"last_run_time,omitempty"`
+	LastSuccessTime string             `json:"last_success_time,omitempty"`
+	// FailedCount 连续同步失败的次数，同步成功后清零
+	FailedCount uint   `json:"failed_count"`
+	LastError   string `json:"last_error,omitempty"`
+	// ResTypes 账号下各类资源的同步间隔和下次同步时间
+	ResTypes []ResTypeSchedule `json:"res_types"`
+}
+
+// ResTypeSchedule 资源类型同步调度信息
+type ResTypeSchedule struct {
+	ResType      enumor.CloudResourceType `json:"res_type"`
+	IntervalMin  uint64                   `json:"interval_min"`
+	LastSyncTime string                   `json:"last_sync_time,omitempty"`
+	NextSyncTime string                   `json:"next_sync_time"`
+}
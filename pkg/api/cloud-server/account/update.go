@@ -214,3 +214,13 @@ type AccountCheckByIDReq struct {
 func (req *AccountCheckByIDReq) Validate() error {
 	return validator.Validate.Struct(req)
 }
+
+// SyncQuarantineOperateReq 审批或撤销被同步删除保护隔离的资源。
+type SyncQuarantineOperateReq struct {
+	IDs []string `json:"ids" validate:"required,min=1,max=100"`
+}
+
+// Validate SyncQuarantineOperateReq.
+func (req *SyncQuarantineOperateReq) Validate() error {
+	return validator.Validate.Struct(req)
+}
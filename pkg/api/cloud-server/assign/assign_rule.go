@@ -0,0 +1,86 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package assign
+
+import (
+	"errors"
+	"fmt"
+
+	protocloud "hcm/pkg/api/data-service/cloud"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/validator"
+)
+
+// CreateBizAssignRuleReq create biz assign rule request.
+type CreateBizAssignRuleReq struct {
+	protocloud.BizAssignRuleCreate `json:",inline"`
+}
+
+// Validate CreateBizAssignRuleReq.
+func (req *CreateBizAssignRuleReq) Validate() error {
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	return protocloud.ValidateBizAssignRuleCondition(req.ResTypes, req.CloudVpcIDs, req.NamePattern)
+}
+
+// UpdateBizAssignRuleReq update biz assign rule request.
+type UpdateBizAssignRuleReq struct {
+	protocloud.BizAssignRuleUpdateReq `json:",inline"`
+}
+
+// BatchDeleteBizAssignRuleReq batch delete biz assign rule request.
+type BatchDeleteBizAssignRuleReq struct {
+	IDs []string `json:"ids" validate:"required,min=1"`
+}
+
+// Validate BatchDeleteBizAssignRuleReq.
+func (req *BatchDeleteBizAssignRuleReq) Validate() error {
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	if len(req.IDs) > constant.BatchOperationMaxLimit {
+		return fmt.Errorf("ids should <= %d", constant.BatchOperationMaxLimit)
+	}
+
+	return nil
+}
+
+// EvaluateBizAssignRuleReq evaluate biz assign rule request, dry run only returns the matched resources.
+type EvaluateBizAssignRuleReq struct {
+	AccountID string   `json:"account_id" validate:"omitempty"`
+	RuleIDs   []string `json:"rule_ids" validate:"omitempty,max=100"`
+	DryRun    bool     `json:"dry_run"`
+}
+
+// Validate EvaluateBizAssignRuleReq.
+func (req *EvaluateBizAssignRuleReq) Validate() error {
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	if len(req.AccountID) == 0 && len(req.RuleIDs) == 0 {
+		return errors.New("one of account_id and rule_ids must be set")
+	}
+
+	return nil
+}
@@ -25,6 +25,7 @@ import (
 	"strings"
 
 	typecvm "hcm/pkg/adaptor/types/cvm"
+	corecloud "hcm/pkg/api/core/cloud"
 	"hcm/pkg/criteria/constant"
 	"hcm/pkg/criteria/enumor"
 	"hcm/pkg/criteria/errf"
@@ -77,6 +78,11 @@ type TCloudCvmCreateReq struct {
 	AutoRenew                *bool `json:"auto_renew" validate:"required"`
 	RequiredCount            int64 `json:"required_count" validate:"required,min=1,max=500"`
 
+	// LaunchTemplate 主机启动模版，请求中指定的参数会覆盖模版中的配置，需要同时指定bk_biz_id
+	LaunchTemplate *corecloud.LaunchTemplateRef `json:"launch_template" validate:"omitempty"`
+	// UserDataTemplate 主机自定义数据模版，模版属于业务，需要同时指定bk_biz_id
+	UserDataTemplate *corecloud.UserDataTemplateRef `json:"user_data_template" validate:"omitempty"`
+
 	Memo *string `json:"memo" validate:"omitempty"`
 }
 
@@ -90,6 +96,14 @@ func (req *TCloudCvmCreateReq) Validate(bizRequired bool) error {
 		return errors.New("bk_biz_id is required")
 	}
 
+	if req.LaunchTemplate != nil && req.BkBizID <= 0 {
+		return errors.New("bk_biz_id is required when launch_template is set")
+	}
+
+	if req.UserDataTemplate != nil && req.BkBizID <= 0 {
+		return errors.New("bk_biz_id is required when user_data_template is set")
+	}
+
 	if req.RequiredCount > constant.BatchOperationMaxLimit {
 		return fmt.Errorf("required count should <= %d", constant.BatchOperationMaxLimit)
 	}
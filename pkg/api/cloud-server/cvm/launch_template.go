@@ -0,0 +1,129 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+package cscvm
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	protocloud "hcm/pkg/api/data-service/cloud"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+)
+
+// launchTemplateSpecExcludedFields 不允许在启动模版中配置的字段，这些字段由模版本身确定或需要在每次创建主机时指定。
+var launchTemplateSpecExcludedFields = map[string]struct{}{
+	"bk_biz_id":          {},
+	"account_id":         {},
+	"region":             {},
+	"name":               {},
+	"password":           {},
+	"confirmed_password": {},
+	"required_count":     {},
+	"memo":               {},
+	"launch_template":    {},
+	"user_data_template": {},
+}
+
+// ValidateLaunchTemplateSpec validate launch template spec fields according to vendor, spec fields should be the
+// same as the vendor's cvm create request.
+func ValidateLaunchTemplateSpec(vendor enumor.Vendor, spec json.RawMessage) error {
+	if err := protocloud.ValidateLaunchTemplateSpec(spec); err != nil {
+		return err
+	}
+
+	fields := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(spec, &fields); err != nil {
+		return err
+	}
+
+	for name := range fields {
+		if _, exists := launchTemplateSpecExcludedFields[name]; exists {
+			return fmt.Errorf("field %s can not be set in launch template spec", name)
+		}
+	}
+
+	var req interface{}
+	switch vendor {
+	case enumor.TCloud:
+		req = new(TCloudCvmCreateReq)
+	case enumor.Aws:
+		req = new(AwsCvmCreateReq)
+	case enumor.HuaWei:
+		req = new(HuaWeiCvmCreateReq)
+	case enumor.Gcp:
+		req = new(GcpCvmCreateReq)
+	case enumor.Azure:
+		req = new(AzureCvmCreateReq)
+	default:
+		return fmt.Errorf("vendor: %s not support launch template", vendor)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(spec))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(req); err != nil {
+		return fmt.Errorf("spec is invalid for %s, err: %v", vendor, err)
+	}
+
+	return nil
+}
+
+// CreateLaunchTemplateReq create launch template request.
+type CreateLaunchTemplateReq struct {
+	Name      string `json:"name" validate:"required,max=255"`
+	AccountID string `json:"account_id" validate:"required"`
+	Region    string `json:"region" validate:"required"`
+	// Spec 主机创建参数，字段与对应云厂商的创建主机请求一致
+	Spec json.RawMessage `json:"spec" validate:"required"`
+	Memo string          `json:"memo" validate:"omitempty,max=255"`
+}
+
+// Validate CreateLaunchTemplateReq.
+func (req *CreateLaunchTemplateReq) Validate() error {
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	return protocloud.ValidateLaunchTemplateSpec(req.Spec)
+}
+
+// UpdateLaunchTemplateReq update launch template request.
+type UpdateLaunchTemplateReq struct {
+	protocloud.LaunchTemplateUpdateReq `json:",inline"`
+}
+
+// BatchDeleteLaunchTemplateReq batch delete launch template request.
+type BatchDeleteLaunchTemplateReq struct {
+	IDs []string `json:"ids" validate:"required,min=1"`
+}
+
+// Validate BatchDeleteLaunchTemplateReq.
+func (req *BatchDeleteLaunchTemplateReq) Validate() error {
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	if len(req.IDs) > constant.BatchOperationMaxLimit {
+		return fmt.Errorf("ids should <= %d", constant.BatchOperationMaxLimit)
+	}
+
+	return nil
+}
@@ -0,0 +1,89 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package cscvm
+
+import (
+	"fmt"
+
+	corecloud "hcm/pkg/api/core/cloud"
+	protocloud "hcm/pkg/api/data-service/cloud"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+)
+
+// CreateUserDataTemplateReq create user data template request.
+type CreateUserDataTemplateReq struct {
+	Name      string                       `json:"name" validate:"required,max=255"`
+	Content   string                       `json:"content" validate:"required"`
+	Variables []corecloud.UserDataVariable `json:"variables" validate:"omitempty,max=50,dive"`
+	Memo      string                       `json:"memo" validate:"omitempty,max=255"`
+}
+
+// Validate CreateUserDataTemplateReq.
+func (req *CreateUserDataTemplateReq) Validate() error {
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	return protocloud.ValidateUserDataTemplateContent(req.Content, req.Variables)
+}
+
+// UpdateUserDataTemplateReq update user data template request.
+type UpdateUserDataTemplateReq struct {
+	protocloud.UserDataTemplateUpdateReq `json:",inline"`
+}
+
+// BatchDeleteUserDataTemplateReq batch delete user data template request.
+type BatchDeleteUserDataTemplateReq struct {
+	IDs []string `json:"ids" validate:"required,min=1"`
+}
+
+// Validate BatchDeleteUserDataTemplateReq.
+func (req *BatchDeleteUserDataTemplateReq) Validate() error {
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	if len(req.IDs) > constant.BatchOperationMaxLimit {
+		return fmt.Errorf("ids should <= %d", constant.BatchOperationMaxLimit)
+	}
+
+	return nil
+}
+
+// PreviewUserDataTemplateReq preview the rendered user data template request.
+type PreviewUserDataTemplateReq struct {
+	Vendor enumor.Vendor `json:"vendor" validate:"required"`
+	// Version 模版版本，为0时使用最新版本
+	Version   int64             `json:"version" validate:"omitempty,min=0"`
+	Variables map[string]string `json:"variables" validate:"omitempty"`
+	// Builtin 预览时使用的内置变量值，如 hostname、region 等
+	Builtin map[string]string `json:"builtin" validate:"omitempty"`
+}
+
+// Validate PreviewUserDataTemplateReq.
+func (req *PreviewUserDataTemplateReq) Validate() error {
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	return req.Vendor.Validate()
+}
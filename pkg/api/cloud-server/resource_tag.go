@@ -0,0 +1,86 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package cloudserver
+
+import (
+	"errors"
+	"fmt"
+
+	corecloud "hcm/pkg/api/core/cloud"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/validator"
+)
+
+// BatchUpsertResTagReq batch add or overwrite resource tags request.
+type BatchUpsertResTagReq struct {
+	IDs  []string            `json:"ids" validate:"required,min=1"`
+	Tags []corecloud.TagPair `json:"tags" validate:"required,min=1,dive"`
+}
+
+// Validate BatchUpsertResTagReq.
+func (req *BatchUpsertResTagReq) Validate() error {
+	if len(req.IDs) > constant.BatchOperationMaxLimit {
+		return fmt.Errorf("ids should <= %d", constant.BatchOperationMaxLimit)
+	}
+
+	keys := make(map[string]struct{}, len(req.Tags))
+	for _, tag := range req.Tags {
+		if _, exist := keys[tag.Key]; exist {
+			return fmt.Errorf("tag key %s is duplicated", tag.Key)
+		}
+		keys[tag.Key] = struct{}{}
+	}
+
+	return validator.Validate.Struct(req)
+}
+
+// BatchDeleteResTagReq batch remove resource tags request.
+type BatchDeleteResTagReq struct {
+	IDs     []string `json:"ids" validate:"required,min=1"`
+	TagKeys []string `json:"tag_keys" validate:"required,min=1"`
+}
+
+// Validate BatchDeleteResTagReq.
+func (req *BatchDeleteResTagReq) Validate() error {
+	if len(req.IDs) > constant.BatchOperationMaxLimit {
+		return fmt.Errorf("ids should <= %d", constant.BatchOperationMaxLimit)
+	}
+
+	for _, key := range req.TagKeys {
+		if len(key) == 0 {
+			return errors.New("tag key can not be empty")
+		}
+	}
+
+	return validator.Validate.Struct(req)
+}
+
+// ListResTagReq list tags of resource request.
+type ListResTagReq struct {
+	IDs []string `json:"ids" validate:"min=1,max=100"`
+}
+
+// Validate ListResTagReq.
+func (req *ListResTagReq) Validate() error {
+	return validator.Validate.Struct(req)
+}
+
+// ListResTagResult list tags of resource result, key is resource id.
+type ListResTagResult map[string][]corecloud.TagPair
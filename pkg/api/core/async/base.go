@@ -28,28 +28,128 @@ import (
 
 // AsyncFlow ...
 type AsyncFlow struct {
-	ID            string                `json:"id"`
-	Name          enumor.FlowName       `json:"name"`
-	State         enumor.FlowState      `json:"state"`
-	Reason        *tableasync.Reason    `json:"reason"`
-	ShareData     *tableasync.ShareData `json:"share_data"`
-	Memo          string                `json:"memo"`
-	Worker        *string               `json:"worker"`
+	ID             string                    `json:"id"`
+	Name           enumor.FlowName           `json:"name"`
+	State          enumor.FlowState          `json:"state"`
+	Reason         *tableasync.Reason        `json:"reason"`
+	ShareData      *tableasync.ShareData     `json:"share_data"`
+	Memo           string                    `json:"memo"`
+	Worker         *string                   `json:"worker"`
+	RunAt          string                    `json:"run_at"`
+	RollbackPolicy enumor.FlowRollbackPolicy `json:"rollback_policy"`
+	Priority       enumor.FlowPriority       `json:"priority"`
+	Tenant         string                    `json:"tenant"`
+	AppCode        string                    `json:"app_code"`
+	Webhooks       tableasync.WebhookTargets `json:"webhooks"`
+	// ArchivedAt 任务流归档时间，任务流未归档时为空
+	ArchivedAt    string `json:"archived_at,omitempty"`
 	core.Revision `json:",inline"`
 }
 
 // AsyncFlowTask ...
 type AsyncFlowTask struct {
-	ID            string             `json:"id"`
-	FlowID        string             `json:"flow_id"`
-	FlowName      enumor.FlowName    `json:"flow_name"`
-	ActionID      string             `json:"action_id"`
-	ActionName    enumor.ActionName  `json:"action_name"`
-	Params        types.JsonField    `json:"params"`
-	Result        types.JsonField    `json:"result"`
-	Retry         *tableasync.Retry  `json:"retry"`
-	DependOn      types.StringArray  `json:"depend_on"`
-	State         enumor.TaskState   `json:"state"`
-	Reason        *tableasync.Reason `json:"reason"`
+	ID            string                   `json:"id"`
+	FlowID        string                   `json:"flow_id"`
+	FlowName      enumor.FlowName          `json:"flow_name"`
+	ActionID      string                   `json:"action_id"`
+	ActionName    enumor.ActionName        `json:"action_name"`
+	Params        types.JsonField          `json:"params"`
+	Result        types.JsonField          `json:"result"`
+	Retry         *tableasync.Retry        `json:"retry"`
+	DependOn      types.StringArray        `json:"depend_on"`
+	State         enumor.TaskState         `json:"state"`
+	Reason        *tableasync.Reason       `json:"reason"`
+	Stat          *tableasync.TaskStat     `json:"stat"`
+	Progress      *tableasync.TaskProgress `json:"progress"`
+	HeartbeatAt   string                   `json:"heartbeat_at"`
 	core.Revision `json:",inline"`
 }
+
+// AsyncFlowSchedule ...
+type AsyncFlowSchedule struct {
+	ID            string                   `json:"id"`
+	Name          string                   `json:"name"`
+	Cron          string                   `json:"cron"`
+	State         enumor.FlowScheduleState `json:"state"`
+	MissedPolicy  enumor.MissedRunPolicy   `json:"missed_policy"`
+	Flow          *tableasync.ScheduleFlow `json:"flow"`
+	NextRunAt     string                   `json:"next_run_at"`
+	Memo          string                   `json:"memo"`
+	core.Revision `json:",inline"`
+}
+
+// AsyncFlowScheduleRun ...
+type AsyncFlowScheduleRun struct {
+	ID         string                      `json:"id"`
+	ScheduleID string                      `json:"schedule_id"`
+	PlanAt     string                      `json:"plan_at"`
+	State      enumor.FlowScheduleRunState `json:"state"`
+	FlowID     string                      `json:"flow_id"`
+	Reason     *tableasync.Reason          `json:"reason"`
+	Creator    string                      `json:"creator"`
+	CreatedAt  string                      `json:"created_at"`
+}
+
+// AsyncFlowEvent 任务流生命周期事件及其 webhook 投递日志
+type AsyncFlowEvent struct {
+	ID            string                       `json:"id"`
+	FlowID        string                       `json:"flow_id"`
+	Event         enumor.FlowEventType         `json:"event"`
+	State         enumor.FlowEventState        `json:"state"`
+	Attempts      uint                         `json:"attempts"`
+	NextAttemptAt string                       `json:"next_attempt_at"`
+	Deliveries    tableasync.WebhookDeliveries `json:"deliveries"`
+	CreatedAt     string                       `json:"created_at"`
+	UpdatedAt     string                       `json:"updated_at"`
+}
+
+// AsyncFlowArchive 已归档的任务流，查询时未选择 detail 字段则 Flow、Tasks、Events 为空
+type AsyncFlowArchive struct {
+	ID            string           `json:"id"`
+	Name          enumor.FlowName  `json:"name"`
+	State         enumor.FlowState `json:"state"`
+	Tenant        string           `json:"tenant"`
+	AppCode       string           `json:"app_code"`
+	FlowCreatedAt string           `json:"flow_created_at"`
+	FinishedAt    string           `json:"finished_at"`
+	ArchivedAt    string           `json:"archived_at"`
+	Flow          *AsyncFlow       `json:"flow,omitempty"`
+	Tasks         []AsyncFlowTask  `json:"tasks,omitempty"`
+	Events        []AsyncFlowEvent `json:"events,omitempty"`
+}
+
+// AsyncFlowDAG 任务流的有向无环图，节点为任务，边为任务之间的依赖关系。
+type AsyncFlowDAG struct {
+	FlowID string           `json:"flow_id"`
+	State  enumor.FlowState `json:"state"`
+	// Progress 任务流执行进度百分比，即执行成功或被跳过的任务占全部任务的比例
+	Progress float64 `json:"progress"`
+	// EtaSec 任务流预计剩余执行时间（秒），根据各 ActionName 的历史平均执行耗时沿关键路径估算，
+	// 任务流已结束或缺少历史执行耗时时为空
+	EtaSec *int64             `json:"eta_sec"`
+	Nodes  []AsyncFlowDAGNode `json:"nodes"`
+	Edges  []AsyncFlowDAGEdge `json:"edges"`
+}
+
+// AsyncFlowDAGNode 任务流有向无环图的节点
+type AsyncFlowDAGNode struct {
+	TaskID     string             `json:"task_id"`
+	ActionID   string             `json:"action_id"`
+	ActionName enumor.ActionName  `json:"action_name"`
+	State      enumor.TaskState   `json:"state"`
+	Reason     *tableasync.Reason `json:"reason"`
+	StartedAt  string             `json:"started_at"`
+	EndedAt    string             `json:"ended_at"`
+	// DurationSec 任务执行耗时（秒），执行中的任务为截至当前的耗时
+	DurationSec int64 `json:"duration_sec"`
+	// RetryCount 任务重试次数
+	RetryCount uint `json:"retry_count"`
+	// EstimateSec 任务按照历史平均执行耗时估算的执行时间（秒），缺少历史执行耗时时为空
+	EstimateSec *int64 `json:"estimate_sec"`
+}
+
+// AsyncFlowDAGEdge 任务流有向无环图的边，由前置任务指向依赖它的后置任务
+type AsyncFlowDAGEdge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
@@ -0,0 +1,74 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package cloud
+
+import (
+	"hcm/pkg/api/core"
+	"hcm/pkg/criteria/enumor"
+)
+
+// BizAssignRule define biz assign rule, 未分配的云资源满足规则的全部匹配条件时，会被自动分配到规则指定的业务下。
+type BizAssignRule struct {
+	ID             string                     `json:"id"`
+	Name           string                     `json:"name"`
+	Priority       int64                      `json:"priority"`
+	Enabled        bool                       `json:"enabled"`
+	Vendor         enumor.Vendor              `json:"vendor"`
+	AccountID      string                     `json:"account_id"`
+	Regions        []string                   `json:"regions"`
+	CloudVpcIDs    []string                   `json:"cloud_vpc_ids"`
+	NamePattern    string                     `json:"name_pattern"`
+	Tags           []TagPair                  `json:"tags"`
+	ResTypes       []enumor.CloudResourceType `json:"res_types"`
+	BkBizID        int64                      `json:"bk_biz_id"`
+	BkCloudID      int64                      `json:"bk_cloud_id"`
+	Memo           string                     `json:"memo"`
+	*core.Revision `json:",inline"`
+}
+
+// BizAssignRecord define biz assign record, 记录由规则触发的一次资源分配。
+type BizAssignRecord struct {
+	ID                    string                   `json:"id"`
+	RuleID                string                   `json:"rule_id"`
+	Vendor                enumor.Vendor            `json:"vendor"`
+	AccountID             string                   `json:"account_id"`
+	ResType               enumor.CloudResourceType `json:"res_type"`
+	ResID                 string                   `json:"res_id"`
+	BkBizID               int64                    `json:"bk_biz_id"`
+	BkCloudID             int64                    `json:"bk_cloud_id"`
+	*core.CreatedRevision `json:",inline"`
+}
+
+// BizAssignRuleSupportedResTypes defines the resource types that can be assigned by biz assign rule.
+var BizAssignRuleSupportedResTypes = map[enumor.CloudResourceType]struct{}{
+	enumor.VpcCloudResType:           {},
+	enumor.SubnetCloudResType:        {},
+	enumor.SecurityGroupCloudResType: {},
+	enumor.EipCloudResType:           {},
+	enumor.DiskCloudResType:          {},
+	enumor.CvmCloudResType:           {},
+}
+
+// BizAssignRuleVpcResTypes defines the resource types that can be matched by vpc.
+var BizAssignRuleVpcResTypes = map[enumor.CloudResourceType]struct{}{
+	enumor.VpcCloudResType:    {},
+	enumor.SubnetCloudResType: {},
+	enumor.CvmCloudResType:    {},
+}
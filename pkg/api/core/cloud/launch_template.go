@@ -0,0 +1,57 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+package cloud
+
+import (
+	"encoding/json"
+
+	"hcm/pkg/api/core"
+	"hcm/pkg/criteria/enumor"
+)
+
+// LaunchTemplate define launch template, 业务下的主机启动模版，模版绑定账号和地域，创建主机时可引用模版并覆盖部分参数。
+type LaunchTemplate struct {
+	ID             string        `json:"id"`
+	Name           string        `json:"name"`
+	Vendor         enumor.Vendor `json:"vendor"`
+	BkBizID        int64         `json:"bk_biz_id"`
+	AccountID      string        `json:"account_id"`
+	Region         string        `json:"region"`
+	LatestVersion  int64         `json:"latest_version"`
+	Memo           string        `json:"memo"`
+	*core.Revision `json:",inline"`
+}
+
+// LaunchTemplateVersion define launch template version, 模版配置每次变更都会生成新的版本。
+type LaunchTemplateVersion struct {
+	ID         string `json:"id"`
+	TemplateID string `json:"template_id"`
+	Version    int64  `json:"version"`
+	// Spec 主机创建参数，字段与对应云厂商的创建主机请求一致，如 zone、instance_type、cloud_image_id 等
+	Spec                  json.RawMessage `json:"spec"`
+	Memo                  string          `json:"memo"`
+	*core.CreatedRevision `json:",inline"`
+}
+
+// LaunchTemplateRef define the launch template used when create cvm.
+type LaunchTemplateRef struct {
+	TemplateID string `json:"template_id" validate:"required"`
+	// Version 模版版本，为0时使用最新版本
+	Version int64 `json:"version" validate:"omitempty,min=0"`
+}
@@ -0,0 +1,44 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+package cloud
+
+import (
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/dal/table/types"
+)
+
+// ResChangeHistory define resource change history, 每条记录为资源的一个版本快照。
+type ResChangeHistory struct {
+	ID        string                   `json:"id"`
+	ResType   enumor.CloudResourceType `json:"res_type"`
+	ResID     string                   `json:"res_id"`
+	CloudID   string                   `json:"cloud_id"`
+	Vendor    enumor.Vendor            `json:"vendor"`
+	AccountID string                   `json:"account_id"`
+	BkBizID   int64                    `json:"bk_biz_id"`
+	Version   uint64                   `json:"version"`
+	Action    enumor.AuditAction       `json:"action"`
+	// Snapshot 该版本资源的完整快照
+	Snapshot types.JsonField `json:"snapshot"`
+	// ChangedFields 相对上一版本变更的字段，格式为 {"field": {"old": xx, "new": xx}}
+	ChangedFields types.JsonField `json:"changed_fields"`
+	Rid           string          `json:"rid"`
+	Creator       string          `json:"creator"`
+	CreatedAt     string          `json:"created_at"`
+}
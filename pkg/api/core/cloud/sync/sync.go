@@ -37,3 +37,40 @@ type AccountSyncDetailTable struct {
 	CreatedAt       types.Time      `json:"created_at"`
 	UpdatedAt       types.Time      `json:"updated_at"`
 }
+
+// AccountSyncHistory 账号下某个地域某类资源的一次同步记录。
+type AccountSyncHistory struct {
+	ID               string          `json:"id"`
+	Vendor           enumor.Vendor   `json:"vendor"`
+	AccountID        string          `json:"account_id"`
+	Region           string          `json:"region"`
+	ResName          string          `json:"res_name"`
+	SyncRid          string          `json:"sync_rid"`
+	Status           string          `json:"status"`
+	CreatedCount     int64           `json:"created_count"`
+	UpdatedCount     int64           `json:"updated_count"`
+	DeletedCount     int64           `json:"deleted_count"`
+	QuarantinedCount int64           `json:"quarantined_count"`
+	CloudApiCount    int64           `json:"cloud_api_count"`
+	CostMs           int64           `json:"cost_ms"`
+	StartTime        string          `json:"start_time"`
+	FailedReason     types.JsonField `json:"failed_reason"`
+	Creator          string          `json:"creator"`
+	CreatedAt        types.Time      `json:"created_at"`
+}
+
+// AccountSyncQuarantine 同步时因删除数量超过阈值而被隔离的资源。
+type AccountSyncQuarantine struct {
+	ID        string                      `json:"id"`
+	Vendor    enumor.Vendor               `json:"vendor"`
+	AccountID string                      `json:"account_id"`
+	Region    string                      `json:"region"`
+	ResType   enumor.CloudResourceType    `json:"res_type"`
+	CloudID   string                      `json:"cloud_id"`
+	Status    enumor.SyncQuarantineStatus `json:"status"`
+	SyncRid   string                      `json:"sync_rid"`
+	Creator   string                      `json:"creator"`
+	Reviser   string                      `json:"reviser"`
+	CreatedAt types.Time                  `json:"created_at"`
+	UpdatedAt types.Time                  `json:"updated_at"`
+}
@@ -0,0 +1,66 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package cloud
+
+import (
+	"hcm/pkg/api/core"
+)
+
+// UserDataTemplate define user data template, 业务下的主机自定义数据(cloud-init)模版。
+type UserDataTemplate struct {
+	ID             string `json:"id"`
+	Name           string `json:"name"`
+	BkBizID        int64  `json:"bk_biz_id"`
+	LatestVersion  int64  `json:"latest_version"`
+	Memo           string `json:"memo"`
+	*core.Revision `json:",inline"`
+}
+
+// UserDataTemplateVersion define user data template version, 模版内容每次变更都会生成新的版本。
+type UserDataTemplateVersion struct {
+	ID                    string             `json:"id"`
+	TemplateID            string             `json:"template_id"`
+	Version               int64              `json:"version"`
+	Content               string             `json:"content"`
+	Variables             []UserDataVariable `json:"variables"`
+	Memo                  string             `json:"memo"`
+	*core.CreatedRevision `json:",inline"`
+}
+
+// UserDataVariable define user data template custom variable, 模版内容中通过 ${name} 引用。
+type UserDataVariable struct {
+	Name string `json:"name" validate:"required,max=64"`
+	// Default 变量默认值，创建主机时未指定变量值则使用默认值
+	Default string `json:"default" validate:"omitempty"`
+	// Required 是否必须在创建主机时指定变量值
+	Required bool `json:"required"`
+	// Secret 是否为敏感变量，敏感变量的值在审计中会被脱敏
+	Secret bool   `json:"secret"`
+	Memo   string `json:"memo" validate:"omitempty,max=255"`
+}
+
+// UserDataTemplateRef define the user data template used when create cvm.
+type UserDataTemplateRef struct {
+	TemplateID string `json:"template_id" validate:"required"`
+	// Version 模版版本，为0时使用最新版本
+	Version int64 `json:"version" validate:"omitempty,min=0"`
+	// Variables 自定义变量的值
+	Variables map[string]string `json:"variables" validate:"omitempty"`
+}
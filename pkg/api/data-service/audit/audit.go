@@ -140,6 +140,10 @@ func (o *OperationAction) ConvAuditAction() (enumor.AuditAction, error) {
 		return enumor.Associate, nil
 	case Disassociate:
 		return enumor.Disassociate, nil
+	case Tag:
+		return enumor.Tag, nil
+	case UnTag:
+		return enumor.UnTag, nil
 
 	default:
 		return "", fmt.Errorf("action is not corresponding audit action")
@@ -155,6 +159,10 @@ const (
 	Associate OperationAction = "associate"
 	// Disassociate 解绑、解挂载等操作
 	Disassociate OperationAction = "disassociate"
+	// Tag 添加、覆盖标签
+	Tag OperationAction = "tag"
+	// UnTag 删除标签
+	UnTag OperationAction = "untag"
 )
 
 // CloudResourceOperationAuditReq define cloud resource operation audit req.
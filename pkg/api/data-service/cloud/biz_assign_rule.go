@@ -0,0 +1,174 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package cloud
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+
+	corecloud "hcm/pkg/api/core/cloud"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+)
+
+// -------------------------- Create --------------------------
+
+// BizAssignRuleBatchCreateReq define biz assign rule batch create req.
+type BizAssignRuleBatchCreateReq struct {
+	Rules []BizAssignRuleCreate `json:"rules" validate:"required,min=1,max=100,dive,required"`
+}
+
+// BizAssignRuleCreate define biz assign rule create info.
+type BizAssignRuleCreate struct {
+	Name        string                     `json:"name" validate:"required,max=255"`
+	Priority    int64                      `json:"priority" validate:"min=0"`
+	Enabled     bool                       `json:"enabled"`
+	AccountID   string                     `json:"account_id" validate:"required"`
+	Regions     []string                   `json:"regions" validate:"omitempty"`
+	CloudVpcIDs []string                   `json:"cloud_vpc_ids" validate:"omitempty"`
+	NamePattern string                     `json:"name_pattern" validate:"omitempty,max=255"`
+	Tags        []corecloud.TagPair        `json:"tags" validate:"omitempty,dive"`
+	ResTypes    []enumor.CloudResourceType `json:"res_types" validate:"required,min=1"`
+	BkBizID     int64                      `json:"bk_biz_id" validate:"min=1"`
+	BkCloudID   int64                      `json:"bk_cloud_id" validate:"omitempty"`
+	Memo        string                     `json:"memo" validate:"omitempty,max=255"`
+}
+
+// Validate BizAssignRuleBatchCreateReq.
+func (req *BizAssignRuleBatchCreateReq) Validate() error {
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	for _, rule := range req.Rules {
+		if err := ValidateBizAssignRuleCondition(rule.ResTypes, rule.CloudVpcIDs, rule.NamePattern); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ValidateBizAssignRuleCondition validate biz assign rule's match conditions.
+func ValidateBizAssignRuleCondition(resTypes []enumor.CloudResourceType, cloudVpcIDs []string,
+	namePattern string) error {
+
+	for _, resType := range resTypes {
+		if _, exists := corecloud.BizAssignRuleSupportedResTypes[resType]; !exists {
+			return fmt.Errorf("resource type %s can not be assigned by rule", resType)
+		}
+
+		if _, exists := corecloud.BizAssignRuleVpcResTypes[resType]; len(cloudVpcIDs) != 0 && !exists {
+			return fmt.Errorf("resource type %s can not be matched by vpc", resType)
+		}
+	}
+
+	if len(namePattern) != 0 {
+		if _, err := regexp.Compile(namePattern); err != nil {
+			return fmt.Errorf("name_pattern is invalid, err: %v", err)
+		}
+	}
+
+	return nil
+}
+
+// -------------------------- Update --------------------------
+
+// BizAssignRuleUpdateReq define biz assign rule update req, 匹配的资源类型与vpc需要同时更新。
+type BizAssignRuleUpdateReq struct {
+	Name        string                     `json:"name" validate:"omitempty,max=255"`
+	Priority    *int64                     `json:"priority" validate:"omitempty,min=0"`
+	Enabled     *bool                      `json:"enabled" validate:"omitempty"`
+	Regions     []string                   `json:"regions" validate:"omitempty"`
+	CloudVpcIDs []string                   `json:"cloud_vpc_ids" validate:"omitempty"`
+	NamePattern *string                    `json:"name_pattern" validate:"omitempty,max=255"`
+	Tags        []corecloud.TagPair        `json:"tags" validate:"omitempty,dive"`
+	ResTypes    []enumor.CloudResourceType `json:"res_types" validate:"omitempty"`
+	BkBizID     int64                      `json:"bk_biz_id" validate:"omitempty,min=1"`
+	BkCloudID   int64                      `json:"bk_cloud_id" validate:"omitempty"`
+	Memo        *string                    `json:"memo" validate:"omitempty,max=255"`
+}
+
+// Validate BizAssignRuleUpdateReq.
+func (req *BizAssignRuleUpdateReq) Validate() error {
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	if len(req.CloudVpcIDs) != 0 && len(req.ResTypes) == 0 {
+		return errors.New("res_types is required when cloud_vpc_ids is set")
+	}
+
+	namePattern := ""
+	if req.NamePattern != nil {
+		namePattern = *req.NamePattern
+	}
+
+	return ValidateBizAssignRuleCondition(req.ResTypes, req.CloudVpcIDs, namePattern)
+}
+
+// -------------------------- List --------------------------
+
+// BizAssignRuleListResult define biz assign rule list result.
+type BizAssignRuleListResult struct {
+	Count   uint64                    `json:"count"`
+	Details []corecloud.BizAssignRule `json:"details"`
+}
+
+// BizAssignRecordListResult define biz assign record list result.
+type BizAssignRecordListResult struct {
+	Count   uint64                      `json:"count"`
+	Details []corecloud.BizAssignRecord `json:"details"`
+}
+
+// -------------------------- Evaluate --------------------------
+
+// BizAssignRuleEvaluateReq define biz assign rule evaluate req.
+type BizAssignRuleEvaluateReq struct {
+	// AccountID 只评估该账号下的规则，为空时评估所有账号的规则
+	AccountID string `json:"account_id" validate:"omitempty"`
+	// RuleIDs 只评估指定的规则，为空时评估全部启用的规则
+	RuleIDs []string `json:"rule_ids" validate:"omitempty,max=100"`
+	// DryRun 为true时只返回规则匹配到的资源，不进行分配
+	DryRun bool `json:"dry_run"`
+}
+
+// Validate BizAssignRuleEvaluateReq.
+func (req *BizAssignRuleEvaluateReq) Validate() error {
+	return validator.Validate.Struct(req)
+}
+
+// BizAssignRuleEvaluateResult define biz assign rule evaluate result.
+type BizAssignRuleEvaluateResult struct {
+	DryRun  bool                         `json:"dry_run"`
+	Details []BizAssignRuleEvaluateMatch `json:"details"`
+}
+
+// BizAssignRuleEvaluateMatch define the resources matched by one rule.
+type BizAssignRuleEvaluateMatch struct {
+	RuleID    string                   `json:"rule_id"`
+	RuleName  string                   `json:"rule_name"`
+	AccountID string                   `json:"account_id"`
+	ResType   enumor.CloudResourceType `json:"res_type"`
+	ResIDs    []string                 `json:"res_ids"`
+	BkBizID   int64                    `json:"bk_biz_id"`
+	BkCloudID int64                    `json:"bk_cloud_id"`
+}
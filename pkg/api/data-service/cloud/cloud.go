@@ -21,10 +21,15 @@
 package cloud
 
 import (
+	"errors"
+	"fmt"
+
+	"hcm/pkg/api/core"
 	"hcm/pkg/criteria/enumor"
 	"hcm/pkg/criteria/validator"
 	"hcm/pkg/dal/dao/types"
 	"hcm/pkg/rest"
+	"hcm/pkg/runtime/filter"
 )
 
 // -------------------------- Get --------------------------
@@ -86,6 +91,60 @@ type ListResourceBasicInfoResp struct {
 	Data          map[string]types.CloudResourceBasicInfo `json:"data"`
 }
 
+// ListResourceBasicInfoByFilterReq define list resource basic info by filter req.
+type ListResourceBasicInfoByFilterReq struct {
+	ResourceType enumor.CloudResourceType `json:"resource_type" validate:"required"`
+	Filter       *filter.Expression       `json:"filter" validate:"required"`
+	Fields       []string                 `json:"fields" validate:"omitempty"`
+	Page         *core.BasePage           `json:"page" validate:"required"`
+}
+
+// basicInfoFields 资源基础信息允许查询的字段
+var basicInfoFields = map[string]struct{}{
+	"id": {}, "vendor": {}, "account_id": {}, "bk_biz_id": {}, "region": {}, "recycle_status": {}, "cloud_id": {},
+	"name": {}, "bk_cloud_id": {},
+}
+
+// Validate list resource basic info by filter req.
+func (req *ListResourceBasicInfoByFilterReq) Validate() error {
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	for _, field := range req.Fields {
+		if _, exists := basicInfoFields[field]; !exists {
+			return fmt.Errorf("field: %s not support", field)
+		}
+	}
+
+	if req.Page.Count {
+		return errors.New("page.count is not supported")
+	}
+
+	return req.Page.Validate(core.NewDefaultPageOption())
+}
+
+// ListResourceBasicInfoByFilterResult list resource basic info by filter result.
+type ListResourceBasicInfoByFilterResult struct {
+	Details []types.CloudResourceBasicInfo `json:"details"`
+}
+
+// CountResourceReq define count resource req.
+type CountResourceReq struct {
+	ResourceType enumor.CloudResourceType `json:"resource_type" validate:"required"`
+	Filter       *filter.Expression       `json:"filter" validate:"required"`
+}
+
+// Validate count resource req.
+func (req *CountResourceReq) Validate() error {
+	return validator.Validate.Struct(req)
+}
+
+// CountResourceResult count resource result.
+type CountResourceResult struct {
+	Count uint64 `json:"count"`
+}
+
 // ------------------------- Assign -------------------------
 
 // AssignResourceToBizReq assign cloud resource to biz request.
@@ -99,3 +158,26 @@ type AssignResourceToBizReq struct {
 func (a AssignResourceToBizReq) Validate() error {
 	return validator.Validate.Struct(a)
 }
+
+// ------------------------- Sync Status -------------------------
+
+// UpdateResourceSyncStatusReq update cloud resource sync status request.
+type UpdateResourceSyncStatusReq struct {
+	ResourceType enumor.CloudResourceType `json:"resource_type" validate:"required"`
+	AccountID    string                   `json:"account_id" validate:"required"`
+	CloudIDs     []string                 `json:"cloud_ids" validate:"required,min=1,max=500"`
+	SyncStatus   enumor.ResSyncStatus     `json:"sync_status" validate:"omitempty"`
+}
+
+// Validate UpdateResourceSyncStatusReq.
+func (req *UpdateResourceSyncStatusReq) Validate() error {
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	if _, exist := enumor.SyncGuardResTypes[req.ResourceType]; !exist {
+		return fmt.Errorf("%s does not support sync status", req.ResourceType)
+	}
+
+	return req.SyncStatus.Validate()
+}
@@ -0,0 +1,115 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+package cloud
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	corecloud "hcm/pkg/api/core/cloud"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+)
+
+// LaunchTemplateMaxSpecSize 模版配置大小上限。
+const LaunchTemplateMaxSpecSize = 64 * 1024
+
+// -------------------------- Create --------------------------
+
+// LaunchTemplateCreateReq define launch template create req.
+type LaunchTemplateCreateReq struct {
+	Name      string          `json:"name" validate:"required,max=255"`
+	Vendor    enumor.Vendor   `json:"vendor" validate:"required"`
+	BkBizID   int64           `json:"bk_biz_id" validate:"min=1"`
+	AccountID string          `json:"account_id" validate:"required"`
+	Region    string          `json:"region" validate:"required"`
+	Spec      json.RawMessage `json:"spec" validate:"required"`
+	Memo      string          `json:"memo" validate:"omitempty,max=255"`
+}
+
+// Validate LaunchTemplateCreateReq.
+func (req *LaunchTemplateCreateReq) Validate() error {
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	if err := req.Vendor.Validate(); err != nil {
+		return err
+	}
+
+	return ValidateLaunchTemplateSpec(req.Spec)
+}
+
+// ValidateLaunchTemplateSpec validate launch template spec is a json object, fields of spec are validated by caller
+// according to vendor.
+func ValidateLaunchTemplateSpec(spec json.RawMessage) error {
+	if len(spec) > LaunchTemplateMaxSpecSize {
+		return fmt.Errorf("spec size should <= %d bytes", LaunchTemplateMaxSpecSize)
+	}
+
+	fields := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(spec, &fields); err != nil {
+		return fmt.Errorf("spec should be a json object, err: %v", err)
+	}
+
+	if len(fields) == 0 {
+		return errors.New("spec can not be empty")
+	}
+
+	return nil
+}
+
+// -------------------------- Update --------------------------
+
+// LaunchTemplateUpdateReq define launch template update req, 配置变更时生成新的版本。
+type LaunchTemplateUpdateReq struct {
+	Name string          `json:"name" validate:"omitempty,max=255"`
+	Spec json.RawMessage `json:"spec" validate:"omitempty"`
+	Memo *string         `json:"memo" validate:"omitempty,max=255"`
+	// VersionMemo 新版本的备注
+	VersionMemo string `json:"version_memo" validate:"omitempty,max=255"`
+}
+
+// Validate LaunchTemplateUpdateReq.
+func (req *LaunchTemplateUpdateReq) Validate() error {
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	if len(req.Spec) == 0 {
+		return nil
+	}
+
+	return ValidateLaunchTemplateSpec(req.Spec)
+}
+
+// -------------------------- List --------------------------
+
+// LaunchTemplateListResult define launch template list result.
+type LaunchTemplateListResult struct {
+	Count   uint64                     `json:"count"`
+	Details []corecloud.LaunchTemplate `json:"details"`
+}
+
+// LaunchTemplateVersionListResult define launch template version list result.
+type LaunchTemplateVersionListResult struct {
+	Count   uint64                            `json:"count"`
+	Details []corecloud.LaunchTemplateVersion `json:"details"`
+}
@@ -0,0 +1,88 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+package cloud
+
+import (
+	"errors"
+	"time"
+
+	"hcm/pkg/api/core"
+	corecloud "hcm/pkg/api/core/cloud"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+	"hcm/pkg/runtime/filter"
+)
+
+// -------------------------- List --------------------------
+
+// ResChangeHistoryListResult define resource change history list result.
+type ResChangeHistoryListResult struct {
+	Count   uint64                       `json:"count"`
+	Details []corecloud.ResChangeHistory `json:"details"`
+}
+
+// ResChangeHistoryAsOfListReq define list resource as of a timestamp req.
+type ResChangeHistoryAsOfListReq struct {
+	ResType enumor.CloudResourceType `json:"res_type" validate:"required"`
+	// AsOf 查询的时间点，格式为 RFC3339，返回该时间点时资源的快照
+	AsOf   string             `json:"as_of" validate:"required"`
+	Filter *filter.Expression `json:"filter" validate:"required"`
+	Page   *core.BasePage     `json:"page" validate:"required"`
+	Fields []string           `json:"fields" validate:"omitempty"`
+}
+
+// Validate ResChangeHistoryAsOfListReq.
+func (req *ResChangeHistoryAsOfListReq) Validate() error {
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	if _, err := time.Parse(constant.TimeStdFormat, req.AsOf); err != nil {
+		return errors.New("as_of should be in RFC3339 format")
+	}
+
+	return nil
+}
+
+// -------------------------- Delete --------------------------
+
+// ResChangeHistoryDeleteExpiredReq define delete expired resource change history req.
+type ResChangeHistoryDeleteExpiredReq struct {
+	// Before 删除该时间之前已过期的历史版本，格式为 RFC3339
+	Before string `json:"before" validate:"required"`
+}
+
+// Validate ResChangeHistoryDeleteExpiredReq.
+func (req *ResChangeHistoryDeleteExpiredReq) Validate() error {
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	if _, err := time.Parse(constant.TimeStdFormat, req.Before); err != nil {
+		return errors.New("before should be in RFC3339 format")
+	}
+
+	return nil
+}
+
+// ResChangeHistoryDeleteExpiredResult define delete expired resource change history result.
+type ResChangeHistoryDeleteExpiredResult struct {
+	DeletedCount uint64 `json:"deleted_count"`
+}
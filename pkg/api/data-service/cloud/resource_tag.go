@@ -0,0 +1,130 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package cloud
+
+import (
+	"errors"
+	"fmt"
+
+	corecloud "hcm/pkg/api/core/cloud"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+)
+
+// -------------------------- Sync --------------------------
+
+// ResourceTagSyncReq define resource tag sync req, 使用云上标签全量覆盖对应资源在db中的标签。
+type ResourceTagSyncReq struct {
+	Vendor    enumor.Vendor             `json:"vendor" validate:"required"`
+	AccountID string                    `json:"account_id" validate:"required"`
+	ResType   enumor.CloudResourceType  `json:"res_type" validate:"required"`
+	Resources []ResourceTagSyncResource `json:"resources" validate:"required,min=1,dive,required"`
+}
+
+// ResourceTagSyncResource define resource tag sync resource.
+type ResourceTagSyncResource struct {
+	ResCloudID string              `json:"res_cloud_id" validate:"required"`
+	Tags       []corecloud.TagPair `json:"tags" validate:"omitempty,dive"`
+}
+
+// Validate ResourceTagSyncReq.
+func (req *ResourceTagSyncReq) Validate() error {
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	if err := req.Vendor.Validate(); err != nil {
+		return err
+	}
+
+	if len(req.Resources) > constant.CloudResourceSyncMaxLimit {
+		return fmt.Errorf("resources should <= %d", constant.CloudResourceSyncMaxLimit)
+	}
+
+	return nil
+}
+
+// -------------------------- Upsert --------------------------
+
+// ResourceTagBatchUpsertReq define resource tag batch upsert req, 资源已有的同名标签会被覆盖。
+type ResourceTagBatchUpsertReq struct {
+	ResType enumor.CloudResourceType `json:"res_type" validate:"required"`
+	ResIDs  []string                 `json:"res_ids" validate:"required,min=1"`
+	Tags    []corecloud.TagPair      `json:"tags" validate:"required,min=1,dive"`
+}
+
+// Validate ResourceTagBatchUpsertReq.
+func (req *ResourceTagBatchUpsertReq) Validate() error {
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	if len(req.ResIDs) > constant.BatchOperationMaxLimit {
+		return fmt.Errorf("res_ids should <= %d", constant.BatchOperationMaxLimit)
+	}
+
+	keys := make(map[string]struct{}, len(req.Tags))
+	for _, tag := range req.Tags {
+		if _, exist := keys[tag.Key]; exist {
+			return fmt.Errorf("tag key %s is duplicated", tag.Key)
+		}
+		keys[tag.Key] = struct{}{}
+	}
+
+	return nil
+}
+
+// -------------------------- Delete --------------------------
+
+// ResourceTagBatchDeleteReq define resource tag batch delete req.
+type ResourceTagBatchDeleteReq struct {
+	ResType enumor.CloudResourceType `json:"res_type" validate:"required"`
+	ResIDs  []string                 `json:"res_ids" validate:"required,min=1"`
+	// TagKeys 需要删除的标签键，为空时删除资源的全部标签
+	TagKeys []string `json:"tag_keys" validate:"omitempty"`
+}
+
+// Validate ResourceTagBatchDeleteReq.
+func (req *ResourceTagBatchDeleteReq) Validate() error {
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	if len(req.ResIDs) > constant.BatchOperationMaxLimit {
+		return fmt.Errorf("res_ids should <= %d", constant.BatchOperationMaxLimit)
+	}
+
+	for _, key := range req.TagKeys {
+		if len(key) == 0 {
+			return errors.New("tag key can not be empty")
+		}
+	}
+
+	return nil
+}
+
+// -------------------------- List --------------------------
+
+// ResourceTagListResult define resource tag list result.
+type ResourceTagListResult struct {
+	Count   uint64                  `json:"count"`
+	Details []corecloud.ResourceTag `json:"details"`
+}
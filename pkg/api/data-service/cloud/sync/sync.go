@@ -20,7 +20,11 @@
 package dssync
 
 import (
+	"errors"
+	"time"
+
 	coresync "hcm/pkg/api/core/cloud/sync"
+	"hcm/pkg/criteria/constant"
 	"hcm/pkg/criteria/enumor"
 	"hcm/pkg/criteria/validator"
 	"hcm/pkg/dal/table/types"
@@ -103,3 +107,165 @@ type ListResult struct {
 	Count   uint64                            `json:"count"`
 	Details []coresync.AccountSyncDetailTable `json:"details"`
 }
+
+// -------------------------- History --------------------------
+
+// HistoryCreateReq define create account sync history request.
+type HistoryCreateReq struct {
+	Items []HistoryCreateField `json:"items" validate:"required,min=1,max=100"`
+}
+
+// Validate HistoryCreateReq.
+func (req HistoryCreateReq) Validate() error {
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	for _, item := range req.Items {
+		if err := item.Validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// HistoryCreateField define account sync history create field.
+type HistoryCreateField struct {
+	Vendor           enumor.Vendor     `json:"vendor" validate:"required"`
+	AccountID        string            `json:"account_id" validate:"required"`
+	Region           string            `json:"region" validate:"omitempty"`
+	ResName          string            `json:"res_name" validate:"required"`
+	SyncRid          string            `json:"sync_rid" validate:"required"`
+	Status           enumor.SyncStatus `json:"status" validate:"required"`
+	CreatedCount     int64             `json:"created_count" validate:"min=0"`
+	UpdatedCount     int64             `json:"updated_count" validate:"min=0"`
+	DeletedCount     int64             `json:"deleted_count" validate:"min=0"`
+	QuarantinedCount int64             `json:"quarantined_count" validate:"min=0"`
+	CloudApiCount    int64             `json:"cloud_api_count" validate:"min=0"`
+	CostMs           int64             `json:"cost_ms" validate:"min=0"`
+	StartTime        string            `json:"start_time" validate:"omitempty"`
+	FailedReason     types.JsonField   `json:"failed_reason" validate:"omitempty"`
+}
+
+// Validate HistoryCreateField.
+func (req HistoryCreateField) Validate() error {
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	return req.Status.Validate()
+}
+
+// HistoryListResult defines list account sync history result.
+type HistoryListResult struct {
+	Count   uint64                        `json:"count"`
+	Details []coresync.AccountSyncHistory `json:"details"`
+}
+
+// HistoryDeleteExpiredReq define delete expired account sync history req.
+type HistoryDeleteExpiredReq struct {
+	// Before 删除该时间之前创建的同步历史，格式为 RFC3339
+	Before string `json:"before" validate:"required"`
+}
+
+// Validate HistoryDeleteExpiredReq.
+func (req *HistoryDeleteExpiredReq) Validate() error {
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	if _, err := time.Parse(constant.TimeStdFormat, req.Before); err != nil {
+		return errors.New("before should be in RFC3339 format")
+	}
+
+	return nil
+}
+
+// HistoryDeleteExpiredResult define delete expired account sync history result.
+type HistoryDeleteExpiredResult struct {
+	DeletedCount uint64 `json:"deleted_count"`
+}
+
+// -------------------------- Quarantine --------------------------
+
+// QuarantineCreateReq define create account sync quarantine request.
+type QuarantineCreateReq struct {
+	Items []QuarantineCreateField `json:"items" validate:"required,min=1,max=100"`
+}
+
+// Validate QuarantineCreateReq.
+func (req QuarantineCreateReq) Validate() error {
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	for _, item := range req.Items {
+		if err := item.Validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// QuarantineCreateField define account sync quarantine create field.
+type QuarantineCreateField struct {
+	Vendor    enumor.Vendor               `json:"vendor" validate:"required"`
+	AccountID string                      `json:"account_id" validate:"required"`
+	Region    string                      `json:"region" validate:"omitempty"`
+	ResType   enumor.CloudResourceType    `json:"res_type" validate:"required"`
+	CloudID   string                      `json:"cloud_id" validate:"required"`
+	Status    enumor.SyncQuarantineStatus `json:"status" validate:"required"`
+	SyncRid   string                      `json:"sync_rid" validate:"required"`
+}
+
+// Validate QuarantineCreateField.
+func (req QuarantineCreateField) Validate() error {
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	return req.Status.Validate()
+}
+
+// QuarantineUpdateReq define update account sync quarantine request.
+type QuarantineUpdateReq struct {
+	Items []QuarantineUpdateField `json:"items" validate:"required,min=1,max=100"`
+}
+
+// Validate QuarantineUpdateReq.
+func (req QuarantineUpdateReq) Validate() error {
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	for _, item := range req.Items {
+		if err := item.Validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// QuarantineUpdateField define account sync quarantine update field.
+type QuarantineUpdateField struct {
+	ID     string                      `json:"id" validate:"required"`
+	Status enumor.SyncQuarantineStatus `json:"status" validate:"required"`
+}
+
+// Validate QuarantineUpdateField.
+func (req QuarantineUpdateField) Validate() error {
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	return req.Status.Validate()
+}
+
+// QuarantineListResult defines list account sync quarantine result.
+type QuarantineListResult struct {
+	Count   uint64                           `json:"count"`
+	Details []coresync.AccountSyncQuarantine `json:"details"`
+}
@@ -0,0 +1,170 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package cloud
+
+import (
+	"errors"
+	"fmt"
+
+	corecloud "hcm/pkg/api/core/cloud"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+	"hcm/pkg/tools/userdata"
+)
+
+// UserDataTemplateMaxContentSize 模版内容大小上限，各云厂商的具体限制在渲染时校验。
+const UserDataTemplateMaxContentSize = 256 * 1024
+
+// -------------------------- Create --------------------------
+
+// UserDataTemplateCreateReq define user data template create req.
+type UserDataTemplateCreateReq struct {
+	Name      string                       `json:"name" validate:"required,max=255"`
+	BkBizID   int64                        `json:"bk_biz_id" validate:"min=1"`
+	Content   string                       `json:"content" validate:"required"`
+	Variables []corecloud.UserDataVariable `json:"variables" validate:"omitempty,max=50,dive"`
+	Memo      string                       `json:"memo" validate:"omitempty,max=255"`
+}
+
+// Validate UserDataTemplateCreateReq.
+func (req *UserDataTemplateCreateReq) Validate() error {
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	return ValidateUserDataTemplateContent(req.Content, req.Variables)
+}
+
+// ValidateUserDataTemplateContent validate user data template content and custom variables.
+func ValidateUserDataTemplateContent(content string, variables []corecloud.UserDataVariable) error {
+	if len(content) > UserDataTemplateMaxContentSize {
+		return fmt.Errorf("content size should <= %d bytes", UserDataTemplateMaxContentSize)
+	}
+
+	names := make(map[string]struct{}, len(variables))
+	for _, one := range variables {
+		if err := userdata.ValidateVarName(one.Name); err != nil {
+			return err
+		}
+
+		if _, exists := names[one.Name]; exists {
+			return fmt.Errorf("variable %s is duplicated", one.Name)
+		}
+		names[one.Name] = struct{}{}
+	}
+
+	return nil
+}
+
+// -------------------------- Update --------------------------
+
+// UserDataTemplateUpdateReq define user data template update req, 内容或变量变更时生成新的版本，两者需要同时指定。
+type UserDataTemplateUpdateReq struct {
+	Name      string                       `json:"name" validate:"omitempty,max=255"`
+	Content   string                       `json:"content" validate:"omitempty"`
+	Variables []corecloud.UserDataVariable `json:"variables" validate:"omitempty,max=50,dive"`
+	Memo      *string                      `json:"memo" validate:"omitempty,max=255"`
+	// VersionMemo 新版本的备注
+	VersionMemo string `json:"version_memo" validate:"omitempty,max=255"`
+}
+
+// Validate UserDataTemplateUpdateReq.
+func (req *UserDataTemplateUpdateReq) Validate() error {
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	if len(req.Content) == 0 {
+		if len(req.Variables) != 0 {
+			return errors.New("content is required when variables is set")
+		}
+		return nil
+	}
+
+	return ValidateUserDataTemplateContent(req.Content, req.Variables)
+}
+
+// -------------------------- List --------------------------
+
+// UserDataTemplateListResult define user data template list result.
+type UserDataTemplateListResult struct {
+	Count   uint64                       `json:"count"`
+	Details []corecloud.UserDataTemplate `json:"details"`
+}
+
+// UserDataTemplateVersionListResult define user data template version list result.
+type UserDataTemplateVersionListResult struct {
+	Count   uint64                              `json:"count"`
+	Details []corecloud.UserDataTemplateVersion `json:"details"`
+}
+
+// -------------------------- Render --------------------------
+
+// UserDataTemplateRenderReq define user data template render req.
+type UserDataTemplateRenderReq struct {
+	corecloud.UserDataTemplateRef `json:",inline"`
+
+	BkBizID int64         `json:"bk_biz_id" validate:"min=1"`
+	Vendor  enumor.Vendor `json:"vendor" validate:"required"`
+	// Builtin 内置变量的值，如 hostname、region 等，由调用方根据创建主机的参数填充
+	Builtin map[string]string `json:"builtin" validate:"omitempty"`
+	// Apply 不为空时表示模版被实际应用于创建主机，会记录审计
+	Apply *UserDataTemplateApply `json:"apply" validate:"omitempty"`
+}
+
+// UserDataTemplateApply define the cvm creation that user data template applied to.
+type UserDataTemplateApply struct {
+	AccountID string `json:"account_id" validate:"required"`
+	// ResName 创建的主机名称
+	ResName string `json:"res_name" validate:"omitempty"`
+	// Source 模版应用的来源，如 create_cvm、application
+	Source string `json:"source" validate:"omitempty"`
+}
+
+// Validate UserDataTemplateRenderReq.
+func (req *UserDataTemplateRenderReq) Validate() error {
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	if err := req.Vendor.Validate(); err != nil {
+		return err
+	}
+
+	for name := range req.Builtin {
+		if _, exists := userdata.BuiltinVars[name]; !exists {
+			return fmt.Errorf("builtin variable %s not supported", name)
+		}
+	}
+
+	return nil
+}
+
+// UserDataTemplateRenderResult define user data template render result.
+type UserDataTemplateRenderResult struct {
+	TemplateID string `json:"template_id"`
+	Version    int64  `json:"version"`
+	// Content 渲染后的内容
+	Content string `json:"content"`
+	// UserData 按云厂商要求编码后的内容
+	UserData string `json:"user_data"`
+	// Variables 渲染所使用的变量，敏感变量已脱敏
+	Variables map[string]string `json:"variables"`
+}
@@ -129,6 +129,8 @@ type AwsBatchCreateReq struct {
 	Password              string                          `json:"password" validate:"required"`
 	RequiredCount         int64                           `json:"required_count" validate:"required"`
 	ClientToken           *string                         `json:"client_token" validate:"omitempty"`
+	// UserData 自定义数据(base64 编码)
+	UserData *string `json:"user_data" validate:"omitempty"`
 }
 
 // Validate request.
@@ -84,6 +84,8 @@ type AzureCreateReq struct {
 	OSDisk               *typecvm.AzureOSDisk    `json:"os_disk" validate:"required"`
 	DataDisk             []typecvm.AzureDataDisk `json:"data_disk" validate:"omitempty"`
 	PublicIPAssigned     bool                    `json:"public_ip_assigned" validate:"omitempty"`
+	// UserData 自定义数据(base64 编码)
+	UserData *string `json:"user_data" validate:"omitempty"`
 }
 
 // Validate request.
@@ -66,6 +66,8 @@ type GcpBatchCreateReq struct {
 	SystemDisk       *typecvm.GcpOsDisk    `json:"system_disk" validate:"required"`
 	DataDisk         []typecvm.GcpDataDisk `json:"data_disk" validate:"omitempty"`
 	PublicIPAssigned bool                  `json:"public_ip_assigned" validate:"omitempty"`
+	// UserData 自定义数据(明文)
+	UserData *string `json:"user_data" validate:"omitempty"`
 }
 
 // Validate request.
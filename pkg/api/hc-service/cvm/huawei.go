@@ -153,6 +153,8 @@ type HuaWeiBatchCreateReq struct {
 	InstanceCharge        *typecvm.HuaWeiInstanceCharge `json:"instance_charge" validate:"required"`
 	PublicIPAssigned      bool                          `json:"public_ip_assigned" validate:"omitempty"`
 	Eip                   *typecvm.HuaWeiEip            `json:"eip" validate:"omitempty"`
+	// UserData 自定义数据(base64 编码)
+	UserData *string `json:"user_data" validate:"omitempty"`
 }
 
 // Validate request.
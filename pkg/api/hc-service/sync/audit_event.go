@@ -0,0 +1,79 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package sync
+
+import (
+	"errors"
+	"time"
+
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+)
+
+// AuditEventSyncReq 基于云上审计事件的增量同步请求，拉取时间窗口内的变更事件，仅同步事件涉及的资源。
+type AuditEventSyncReq struct {
+	AccountID string `json:"account_id" validate:"required"`
+	// Regions 拉取审计事件的地域，tcloud/aws/huawei 审计事件按地域查询，必填；gcp/azure 审计日志为全局，无需指定
+	Regions []string `json:"regions" validate:"omitempty"`
+	// StartTime 时间窗口开始时间，RFC3339 格式
+	StartTime string `json:"start_time" validate:"required"`
+	// EndTime 时间窗口结束时间，RFC3339 格式
+	EndTime string `json:"end_time" validate:"required"`
+}
+
+// Validate audit event sync request.
+func (req *AuditEventSyncReq) Validate(vendor enumor.Vendor) error {
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	start, err := time.Parse(time.RFC3339, req.StartTime)
+	if err != nil {
+		return errors.New("start_time should be in RFC3339 format")
+	}
+
+	end, err := time.Parse(time.RFC3339, req.EndTime)
+	if err != nil {
+		return errors.New("end_time should be in RFC3339 format")
+	}
+
+	if !end.After(start) {
+		return errors.New("end_time must be after start_time")
+	}
+
+	switch vendor {
+	case enumor.TCloud, enumor.Aws, enumor.HuaWei:
+		if len(req.Regions) == 0 {
+			return errors.New("regions is required")
+		}
+	}
+
+	return nil
+}
+
+// AuditEventSyncResult 基于云上审计事件的增量同步结果。
+type AuditEventSyncResult struct {
+	// EventCount 拉取到的审计事件数量
+	EventCount int `json:"event_count"`
+	// IgnoredCount 无法映射到可同步资源的事件资源数量
+	IgnoredCount int `json:"ignored_count"`
+	// SyncedCount 各类资源触发定向同步的资源数量
+	SyncedCount map[enumor.CloudResourceType]int `json:"synced_count"`
+}
@@ -0,0 +1,112 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package sync
+
+import (
+	"errors"
+	"fmt"
+
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+)
+
+// CloudIDSyncReq 按云ID定向同步一类资源的请求，只同步指定的资源，云上已删除的资源会从本地删除。
+type CloudIDSyncReq struct {
+	AccountID string                   `json:"account_id" validate:"required"`
+	ResType   enumor.CloudResourceType `json:"res_type" validate:"required"`
+	// Region 资源所在地域，tcloud/aws/huawei 必填，gcp 的主机和子网必填
+	Region string `json:"region" validate:"omitempty"`
+	// Zone 资源所在可用区，gcp 的主机和硬盘必填
+	Zone string `json:"zone" validate:"omitempty"`
+	// ResourceGroupName 资源所在资源组，azure 必填
+	ResourceGroupName string `json:"resource_group_name" validate:"omitempty"`
+	// CloudVpcID 子网所属的VPC云ID，huawei/azure 的子网必填
+	CloudVpcID string   `json:"cloud_vpc_id" validate:"omitempty"`
+	CloudIDs   []string `json:"cloud_ids" validate:"required,min=1,max=500"`
+}
+
+// Validate cloud id sync request.
+func (req *CloudIDSyncReq) Validate(vendor enumor.Vendor) error {
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	supported, exists := CloudIDSyncResTypes[vendor]
+	if !exists {
+		return fmt.Errorf("vendor: %s not support sync by cloud ids", vendor)
+	}
+
+	if _, ok := supported[req.ResType]; !ok {
+		return fmt.Errorf("%s not support sync %s by cloud ids", vendor, req.ResType)
+	}
+
+	switch vendor {
+	case enumor.TCloud, enumor.Aws, enumor.HuaWei:
+		if len(req.Region) == 0 {
+			return errors.New("region is required")
+		}
+
+	case enumor.Gcp:
+		if (req.ResType == enumor.CvmCloudResType || req.ResType == enumor.SubnetCloudResType) &&
+			len(req.Region) == 0 {
+			return errors.New("region is required")
+		}
+
+		if (req.ResType == enumor.CvmCloudResType || req.ResType == enumor.DiskCloudResType) && len(req.Zone) == 0 {
+			return errors.New("zone is required")
+		}
+
+	case enumor.Azure:
+		if len(req.ResourceGroupName) == 0 {
+			return errors.New("resource_group_name is required")
+		}
+	}
+
+	if (vendor == enumor.HuaWei || vendor == enumor.Azure) && req.ResType == enumor.SubnetCloudResType &&
+		len(req.CloudVpcID) == 0 {
+		return errors.New("cloud_vpc_id is required")
+	}
+
+	return nil
+}
+
+// CloudIDSyncResTypes 各云厂商支持按云ID定向同步的资源类型。
+var CloudIDSyncResTypes = map[enumor.Vendor]map[enumor.CloudResourceType]struct{}{
+	enumor.TCloud: {
+		enumor.CvmCloudResType: {}, enumor.VpcCloudResType: {}, enumor.SubnetCloudResType: {},
+		enumor.SecurityGroupCloudResType: {}, enumor.DiskCloudResType: {}, enumor.EipCloudResType: {},
+	},
+	enumor.Aws: {
+		enumor.CvmCloudResType: {}, enumor.VpcCloudResType: {}, enumor.SubnetCloudResType: {},
+		enumor.SecurityGroupCloudResType: {}, enumor.DiskCloudResType: {}, enumor.EipCloudResType: {},
+	},
+	enumor.HuaWei: {
+		enumor.CvmCloudResType: {}, enumor.VpcCloudResType: {}, enumor.SubnetCloudResType: {},
+		enumor.SecurityGroupCloudResType: {}, enumor.DiskCloudResType: {}, enumor.EipCloudResType: {},
+	},
+	enumor.Gcp: {
+		enumor.CvmCloudResType: {}, enumor.VpcCloudResType: {}, enumor.SubnetCloudResType: {},
+		enumor.GcpFirewallRuleCloudResType: {}, enumor.DiskCloudResType: {},
+	},
+	enumor.Azure: {
+		enumor.CvmCloudResType: {}, enumor.VpcCloudResType: {}, enumor.SubnetCloudResType: {},
+		enumor.SecurityGroupCloudResType: {}, enumor.DiskCloudResType: {}, enumor.EipCloudResType: {},
+	},
+}
@@ -0,0 +1,40 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package sync
+
+import "hcm/pkg/criteria/enumor"
+
+// DryRunResult 同步预览结果，key为资源类型，请求体中 dry_run 为 true 时同步接口只对比云上和db数据，不写db，返回该结果。
+type DryRunResult map[enumor.CloudResourceType]*DryRunDiff
+
+// DryRunDiff 单类资源的同步差异。
+type DryRunDiff struct {
+	AddCloudIDs    []string       `json:"add_cloud_ids"`
+	Update         []DryRunUpdate `json:"update"`
+	DeleteCloudIDs []string       `json:"delete_cloud_ids"`
+}
+
+// DryRunUpdate 需要更新的资源以及发生变化的字段。
+type DryRunUpdate struct {
+	CloudID string `json:"cloud_id"`
+	// ChangedFields 云上与db中同名字段值不同的字段路径，如 name、extension.cidr，由于云上和db的数据结构并不完全一致，
+	// 该字段仅作为参考，是否更新以各资源的变更判断为准。
+	ChangedFields []string `json:"changed_fields,omitempty"`
+}
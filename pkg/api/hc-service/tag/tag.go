@@ -0,0 +1,104 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package hctag defines hc-service resource tag api.
+package hctag
+
+import (
+	"errors"
+	"fmt"
+
+	corecloud "hcm/pkg/api/core/cloud"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+)
+
+// TagResTypes 支持标签操作的资源类型
+var TagResTypes = map[enumor.CloudResourceType]struct{}{
+	enumor.CvmCloudResType:    {},
+	enumor.DiskCloudResType:   {},
+	enumor.EipCloudResType:    {},
+	enumor.VpcCloudResType:    {},
+	enumor.SubnetCloudResType: {},
+}
+
+func validateTagResType(resType enumor.CloudResourceType) error {
+	if _, exist := TagResTypes[resType]; !exist {
+		return fmt.Errorf("res_type %s not support tag", resType)
+	}
+
+	return nil
+}
+
+// BatchUpsertTagReq batch add or overwrite resource tags request.
+type BatchUpsertTagReq struct {
+	ResType   enumor.CloudResourceType `json:"res_type" validate:"required"`
+	AccountID string                   `json:"account_id" validate:"required"`
+	IDs       []string                 `json:"ids" validate:"required,min=1"`
+	Tags      []corecloud.TagPair      `json:"tags" validate:"required,min=1,dive"`
+}
+
+// Validate BatchUpsertTagReq.
+func (req *BatchUpsertTagReq) Validate() error {
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	if err := validateTagResType(req.ResType); err != nil {
+		return err
+	}
+
+	if len(req.IDs) > constant.BatchOperationMaxLimit {
+		return fmt.Errorf("ids should <= %d", constant.BatchOperationMaxLimit)
+	}
+
+	return nil
+}
+
+// BatchDeleteTagReq batch remove resource tags request.
+type BatchDeleteTagReq struct {
+	ResType   enumor.CloudResourceType `json:"res_type" validate:"required"`
+	AccountID string                   `json:"account_id" validate:"required"`
+	IDs       []string                 `json:"ids" validate:"required,min=1"`
+	TagKeys   []string                 `json:"tag_keys" validate:"required,min=1"`
+}
+
+// Validate BatchDeleteTagReq.
+func (req *BatchDeleteTagReq) Validate() error {
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	if err := validateTagResType(req.ResType); err != nil {
+		return err
+	}
+
+	if len(req.IDs) > constant.BatchOperationMaxLimit {
+		return fmt.Errorf("ids should <= %d", constant.BatchOperationMaxLimit)
+	}
+
+	for _, key := range req.TagKeys {
+		if len(key) == 0 {
+			return errors.New("tag key can not be empty")
+		}
+	}
+
+	return nil
+}
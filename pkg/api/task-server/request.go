@@ -20,6 +20,9 @@
 package taskserver
 
 import (
+	"errors"
+	"fmt"
+
 	"hcm/pkg/async/action"
 	"hcm/pkg/criteria/enumor"
 	"hcm/pkg/criteria/validator"
@@ -32,6 +35,16 @@ type AddTemplateFlowReq struct {
 	Name enumor.FlowName `json:"name" validate:"required"`
 	// Memo 备注
 	Memo string `json:"memo" validate:"omitempty"`
+	// RunAt 任务流最早执行时间，格式为 constant.TimeStdFormat，为空时立即执行
+	RunAt string `json:"run_at" validate:"omitempty"`
+	// RollbackPolicy 任务流失败后的回滚策略，为空时使用任务流模版的回滚策略
+	RollbackPolicy enumor.FlowRollbackPolicy `json:"rollback_policy" validate:"omitempty"`
+	// Priority 任务流优先级，为空时使用任务流模版的优先级
+	Priority enumor.FlowPriority `json:"priority" validate:"omitempty"`
+	// Tenant 任务流所属租户，用于按租户限制并发，建议使用 bk_biz_id 或 app_code，为空时为请求的 app_code
+	Tenant string `json:"tenant" validate:"omitempty,max=64"`
+	// Webhooks 任务流生命周期事件回调地址，任务流创建、开始执行、成功、失败、回滚完成、取消时会回调该地址
+	Webhooks tableasync.WebhookTargets `json:"webhooks" validate:"omitempty,max=5"`
 	// Tasks 任务私有化参数设置
 	Tasks []TemplateFlowTask `json:"tasks" validate:"required, min=1"`
 }
@@ -73,6 +86,16 @@ type AddCustomFlowReq struct {
 	Memo string `json:"memo" validate:"omitempty"`
 	// ShareData 共享数据
 	ShareData *tableasync.ShareData `json:"share_data" validate:"omitempty"`
+	// RunAt 任务流最早执行时间，格式为 constant.TimeStdFormat，为空时立即执行
+	RunAt string `json:"run_at" validate:"omitempty"`
+	// RollbackPolicy 任务流失败后的回滚策略，为空时不回滚
+	RollbackPolicy enumor.FlowRollbackPolicy `json:"rollback_policy" validate:"omitempty"`
+	// Priority 任务流优先级，为空时为 normal
+	Priority enumor.FlowPriority `json:"priority" validate:"omitempty"`
+	// Tenant 任务流所属租户，用于按租户限制并发，建议使用 bk_biz_id 或 app_code，为空时为请求的 app_code
+	Tenant string `json:"tenant" validate:"omitempty,max=64"`
+	// Webhooks 任务流生命周期事件回调地址，任务流创建、开始执行、成功、失败、回滚完成、取消时会回调该地址
+	Webhooks tableasync.WebhookTargets `json:"webhooks" validate:"omitempty,max=5"`
 	// Tasks 任务私有化参数设置
 	Tasks []CustomFlowTask `json:"tasks" validate:"omitempty"`
 }
@@ -112,3 +135,76 @@ type CustomFlowTask struct {
 func (task *CustomFlowTask) Validate() error {
 	return validator.Validate.Struct(task)
 }
+
+// CancelFlowReq define cancel flow request.
+type CancelFlowReq struct {
+	// Reason 取消原因
+	Reason string `json:"reason" validate:"omitempty,max=1024"`
+}
+
+// Validate CancelFlowReq
+func (req *CancelFlowReq) Validate() error {
+	return validator.Validate.Struct(req)
+}
+
+// UpdateTaskStateReq define manually update task state request.
+type UpdateTaskStateReq struct {
+	// State 任务目标状态，仅支持 success、skipped
+	State enumor.TaskState `json:"state" validate:"required"`
+	// Reason 人工设置任务状态的原因
+	Reason string `json:"reason" validate:"required,max=1024"`
+}
+
+// Validate UpdateTaskStateReq
+func (req *UpdateTaskStateReq) Validate() error {
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	switch req.State {
+	case enumor.TaskSuccess, enumor.TaskSkipped:
+	default:
+		return fmt.Errorf("state only support %s, %s", enumor.TaskSuccess, enumor.TaskSkipped)
+	}
+
+	return nil
+}
+
+// AddFlowScheduleReq define add flow schedule request.
+type AddFlowScheduleReq struct {
+	// Name 定时任务流名称，全局唯一
+	Name string `json:"name" validate:"required,lte=64"`
+	// Cron 5段式 cron 表达式（分 时 日 月 周）
+	Cron string `json:"cron" validate:"required,lte=64"`
+	// MissedPolicy 错过执行策略，不设置时跳过错过的执行
+	MissedPolicy enumor.MissedRunPolicy `json:"missed_policy" validate:"omitempty"`
+	// Memo 备注
+	Memo string `json:"memo" validate:"omitempty,lte=255"`
+	// TemplateFlow 按照任务流模版生成任务流，与 CustomFlow 二选一
+	TemplateFlow *AddTemplateFlowReq `json:"template_flow" validate:"omitempty"`
+	// CustomFlow 按照自定义任务流生成任务流，与 TemplateFlow 二选一
+	CustomFlow *AddCustomFlowReq `json:"custom_flow" validate:"omitempty"`
+}
+
+// Validate AddFlowScheduleReq
+func (req *AddFlowScheduleReq) Validate() error {
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	if len(req.MissedPolicy) != 0 {
+		if err := req.MissedPolicy.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if (req.TemplateFlow == nil) == (req.CustomFlow == nil) {
+		return errors.New("one of template_flow and custom_flow is required")
+	}
+
+	if req.TemplateFlow != nil {
+		return req.TemplateFlow.Validate()
+	}
+
+	return req.CustomFlow.Validate()
+}
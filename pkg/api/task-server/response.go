@@ -32,3 +32,33 @@ type ListTaskResult struct {
 	Count   uint64                    `json:"count"`
 	Details []coreasync.AsyncFlowTask `json:"details"`
 }
+
+// ListFlowScheduleResult ...
+type ListFlowScheduleResult struct {
+	Count   uint64                        `json:"count"`
+	Details []coreasync.AsyncFlowSchedule `json:"details"`
+}
+
+// ListFlowScheduleRunResult ...
+type ListFlowScheduleRunResult struct {
+	Count   uint64                           `json:"count"`
+	Details []coreasync.AsyncFlowScheduleRun `json:"details"`
+}
+
+// ListFlowEventResult ...
+type ListFlowEventResult struct {
+	Count   uint64                     `json:"count"`
+	Details []coreasync.AsyncFlowEvent `json:"details"`
+}
+
+// ListFlowArchiveResult ...
+type ListFlowArchiveResult struct {
+	Count   uint64                       `json:"count"`
+	Details []coreasync.AsyncFlowArchive `json:"details"`
+}
+
+// GetFlowDAGResult 任务流有向无环图，format 为 dot 时仅返回 Graphviz DOT 格式的内容。
+type GetFlowDAGResult struct {
+	*coreasync.AsyncFlowDAG `json:",inline"`
+	Dot                     string `json:"dot,omitempty"`
+}
@@ -23,6 +23,7 @@ package action
 import (
 	"hcm/pkg/async/action/run"
 	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/dal/table/types"
 )
 
 // Action 异步任务必须实现的运行接口。
@@ -39,8 +40,21 @@ type RollbackAction interface {
 	Rollback(kt run.ExecuteKit, params interface{}) error
 }
 
+// CompensateAction Action如果支持补偿操作，实现该接口。回滚策略为 saga 的任务流失败后，会按照依赖关系的逆序，
+// 调用已执行成功任务的补偿操作，result 为任务执行成功时保存的运行结果。
+// State: success -> compensated / compensate_failed
+type CompensateAction interface {
+	Compensate(kt run.ExecuteKit, params interface{}, result types.JsonField) error
+}
+
 // ParameterAction 如果任务运行需要依赖请求参数，需要通过该接口返回参数结构，会将任务实例中的参数内容解析到这个返回参数上。
 type ParameterAction interface {
 	// ParameterNew 返回新的参数结构。返回参数可以实现 Decoder 接口，自定义解码方式。
 	ParameterNew() (params interface{})
 }
+
+// OutputAction 如果任务运行结果需要被后置任务通过参数表达式引用，可以通过该接口返回结果结构，任务执行成功后会校验运行结果是否符合该结构。
+type OutputAction interface {
+	// OutputNew 返回新的结果结构。
+	OutputNew() (output interface{})
+}
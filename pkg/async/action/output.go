@@ -0,0 +1,70 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package action
+
+import (
+	"fmt"
+	"reflect"
+
+	"hcm/pkg/criteria/validator"
+	"hcm/pkg/tools/json"
+)
+
+// ValidateOutput 校验任务运行结果是否符合 OutputAction 声明的结果结构，未实现 OutputAction 的任务不做校验。
+func ValidateOutput(act Action, result interface{}) error {
+	outputAct, ok := act.(OutputAction)
+	if !ok {
+		return nil
+	}
+
+	output := outputAct.OutputNew()
+	if output == nil {
+		return nil
+	}
+
+	if result == nil {
+		return fmt.Errorf("action: %s declares output %s, but result is nil", act.Name(),
+			reflect.TypeOf(output).String())
+	}
+
+	raw, err := json.Marshal(result)
+	if err != nil {
+		return fmt.Errorf("marshal action: %s result failed, err: %v", act.Name(), err)
+	}
+
+	if err = json.Unmarshal(raw, output); err != nil {
+		return fmt.Errorf("action: %s result not match output %s, err: %v", act.Name(),
+			reflect.TypeOf(output).String(), err)
+	}
+
+	value := reflect.ValueOf(output)
+	for value.Kind() == reflect.Ptr && !value.IsNil() {
+		value = value.Elem()
+	}
+	if value.Kind() != reflect.Struct {
+		return nil
+	}
+
+	if err = validator.Validate.Struct(output); err != nil {
+		return fmt.Errorf("action: %s result validate failed, err: %v", act.Name(), err)
+	}
+
+	return nil
+}
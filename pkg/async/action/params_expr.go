@@ -0,0 +1,205 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package action
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"hcm/pkg/dal/table/types"
+	"hcm/pkg/tools/json"
+)
+
+/*
+参数表达式：任务参数中的字符串值可以通过 text/template 表达式引用 DependOn 中前置任务的执行结果，
+表达式在执行器解析参数（ParameterNew）前渲染，引用数据结构为 {"tasks": {"<action_id>": {"result": <执行结果>}}}。
+	1. 字符串值仅包含一个表达式时（如 "{{ .tasks.create_vpc.result.vpc_id }}"），替换为引用值本身，保留引用值的类型
+	2. 字符串值中包含文本和表达式时（如 "vpc-{{ .tasks.create_vpc.result.name }}"），渲染为字符串
+	3. action_id 不是合法标识符时，使用 index 引用，如 {{ index .tasks "1" "result" "vpc_id" }}
+*/
+
+const (
+	paramsExprLeftDelim  = "{{"
+	paramsExprRightDelim = "}}"
+	paramsExprTasksKey   = "tasks"
+	paramsExprResultKey  = "result"
+)
+
+var paramsExprRefRegexp = regexp.MustCompile(`\.tasks\.([A-Za-z0-9_]+)|index\s+\.tasks\s+"([^"]+)"`)
+
+var paramsExprFuncs = template.FuncMap{
+	"json": func(v interface{}) (string, error) {
+		return json.MarshalToString(v)
+	},
+}
+
+// HasParamsExpr 判断任务参数中是否包含参数表达式
+func HasParamsExpr(params types.JsonField) bool {
+	return strings.Contains(string(params), paramsExprLeftDelim)
+}
+
+// ParamsExprRefs 解析任务参数中的参数表达式，返回表达式引用的前置任务 ActionID
+func ParamsExprRefs(params types.JsonField) ([]ActIDType, error) {
+	if !HasParamsExpr(params) {
+		return nil, nil
+	}
+
+	var value interface{}
+	if err := json.UnmarshalFromString(string(params), &value); err != nil {
+		return nil, fmt.Errorf("params is not a valid json, err: %v", err)
+	}
+
+	refs := make([]ActIDType, 0)
+	exist := make(map[ActIDType]struct{})
+	_, err := walkParamsString(value, func(str string) (interface{}, error) {
+		if !strings.Contains(str, paramsExprLeftDelim) {
+			return str, nil
+		}
+
+		if _, err := template.New("params").Funcs(paramsExprFuncs).Parse(str); err != nil {
+			return nil, fmt.Errorf("parse params expression %s failed, err: %v", str, err)
+		}
+
+		matches := paramsExprRefRegexp.FindAllStringSubmatch(str, -1)
+		if len(matches) == 0 {
+			return nil, fmt.Errorf("params expression %s should reference .%s", str, paramsExprTasksKey)
+		}
+
+		for _, match := range matches {
+			id := ActIDType(match[1] + match[2])
+			if _, ok := exist[id]; !ok {
+				exist[id] = struct{}{}
+				refs = append(refs, id)
+			}
+		}
+
+		return str, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return refs, nil
+}
+
+// RenderParams 使用前置任务的执行结果渲染任务参数中的参数表达式，results 的 key 为前置任务的 ActionID。
+func RenderParams(params types.JsonField, results map[ActIDType]types.JsonField) (types.JsonField, error) {
+	if !HasParamsExpr(params) {
+		return params, nil
+	}
+
+	tasks := make(map[string]interface{}, len(results))
+	for id, result := range results {
+		var value interface{}
+		if len(result) != 0 {
+			if err := json.UnmarshalFromString(string(result), &value); err != nil {
+				return "", fmt.Errorf("task %s result is not a valid json, err: %v", id, err)
+			}
+		}
+		tasks[string(id)] = map[string]interface{}{paramsExprResultKey: value}
+	}
+	data := map[string]interface{}{paramsExprTasksKey: tasks}
+
+	var value interface{}
+	if err := json.UnmarshalFromString(string(params), &value); err != nil {
+		return "", fmt.Errorf("params is not a valid json, err: %v", err)
+	}
+
+	rendered, err := walkParamsString(value, func(str string) (interface{}, error) {
+		return renderParamsExpr(str, data)
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return types.NewJsonField(rendered)
+}
+
+// renderParamsExpr 渲染单个字符串值中的参数表达式
+func renderParamsExpr(str string, data map[string]interface{}) (interface{}, error) {
+	if !strings.Contains(str, paramsExprLeftDelim) {
+		return str, nil
+	}
+
+	text := str
+	trimmed := strings.TrimSpace(str)
+	whole := strings.HasPrefix(trimmed, paramsExprLeftDelim) && strings.HasSuffix(trimmed, paramsExprRightDelim) &&
+		strings.Count(trimmed, paramsExprLeftDelim) == 1
+	if whole {
+		// 仅包含一个表达式时，将引用值序列化为 json 后再解析，保留引用值的类型
+		expr := strings.TrimSuffix(strings.TrimPrefix(trimmed, paramsExprLeftDelim), paramsExprRightDelim)
+		text = fmt.Sprintf("%s json (%s) %s", paramsExprLeftDelim, strings.TrimSpace(expr), paramsExprRightDelim)
+	}
+
+	tpl, err := template.New("params").Funcs(paramsExprFuncs).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("parse params expression %s failed, err: %v", str, err)
+	}
+
+	buf := new(bytes.Buffer)
+	if err = tpl.Execute(buf, data); err != nil {
+		return nil, fmt.Errorf("render params expression %s failed, err: %v", str, err)
+	}
+
+	if !whole {
+		return buf.String(), nil
+	}
+
+	var value interface{}
+	if err = json.UnmarshalFromString(buf.String(), &value); err != nil {
+		return nil, fmt.Errorf("decode params expression %s value failed, err: %v", str, err)
+	}
+
+	return value, nil
+}
+
+// walkParamsString 遍历 json 值中的所有字符串值，并使用 do 的返回值替换
+func walkParamsString(value interface{}, do func(str string) (interface{}, error)) (interface{}, error) {
+	switch v := value.(type) {
+	case string:
+		return do(v)
+
+	case map[string]interface{}:
+		for key, one := range v {
+			replaced, err := walkParamsString(one, do)
+			if err != nil {
+				return nil, err
+			}
+			v[key] = replaced
+		}
+		return v, nil
+
+	case []interface{}:
+		for index, one := range v {
+			replaced, err := walkParamsString(one, do)
+			if err != nil {
+				return nil, err
+			}
+			v[index] = replaced
+		}
+		return v, nil
+
+	default:
+		return v, nil
+	}
+}
@@ -0,0 +1,90 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package action
+
+import (
+	"testing"
+
+	"hcm/pkg/dal/table/types"
+)
+
+func TestParamsExprRefs(t *testing.T) {
+	params := types.JsonField(`{"vpc_id":"{{ .tasks.create_vpc.result.id }}",` +
+		`"items":[{"name":"sub-{{ index .tasks \"1\" \"result\" \"name\" }}"}],"count":1}`)
+
+	refs, err := ParamsExprRefs(params)
+	if err != nil {
+		t.Fatalf("parse params expression refs failed, err: %v", err)
+	}
+
+	if len(refs) != 2 {
+		t.Fatalf("refs should be 2, but got: %v", refs)
+	}
+
+	exist := map[ActIDType]bool{"create_vpc": false, "1": false}
+	for _, one := range refs {
+		exist[one] = true
+	}
+	for id, found := range exist {
+		if !found {
+			t.Errorf("ref %s not found in %v", id, refs)
+		}
+	}
+
+	if _, err = ParamsExprRefs(`{"vpc_id":"{{ .tasks.create_vpc.result.id "}`); err == nil {
+		t.Errorf("unclosed params expression should be failed")
+	}
+
+	if _, err = ParamsExprRefs(`{"vpc_id":"{{ .other }}"}`); err == nil {
+		t.Errorf("params expression not reference tasks should be failed")
+	}
+}
+
+func TestRenderParams(t *testing.T) {
+	params := types.JsonField(`{"vpc_id":"{{ .tasks.create_vpc.result.id }}",` +
+		`"size":"{{ .tasks.create_vpc.result.size }}","tags":"{{ .tasks.create_vpc.result.tags }}",` +
+		`"name":"sub-{{ .tasks.create_vpc.result.id }}","count":1}`)
+	results := map[ActIDType]types.JsonField{
+		"create_vpc": `{"id":"vpc-1","size":10,"tags":["a","b"]}`,
+	}
+
+	rendered, err := RenderParams(params, results)
+	if err != nil {
+		t.Fatalf("render params failed, err: %v", err)
+	}
+
+	expect := `{"count":1,"name":"sub-vpc-1","size":10,"tags":["a","b"],"vpc_id":"vpc-1"}`
+	if string(rendered) != expect {
+		t.Errorf("rendered params should be %s, but got: %s", expect, rendered)
+	}
+
+	if _, err = RenderParams(`{"vpc_id":"{{ .tasks.create_vpc.result.not_exist }}"}`, results); err == nil {
+		t.Errorf("render missing result field should be failed")
+	}
+
+	if _, err = RenderParams(`{"vpc_id":"{{ .tasks.other.result.id }}"}`, results); err == nil {
+		t.Errorf("render not depend task should be failed")
+	}
+
+	plain := types.JsonField(`{"vpc_id":"vpc-1"}`)
+	if rendered, err = RenderParams(plain, results); err != nil || rendered != plain {
+		t.Errorf("params without expression should not be changed, rendered: %s, err: %v", rendered, err)
+	}
+}
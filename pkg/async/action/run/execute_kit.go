@@ -25,6 +25,7 @@ import "hcm/pkg/kit"
 type ExecuteKit interface {
 	Kit() *kit.Kit
 	ShareData() ShareDataOperator
+	Progress() ProgressOperator
 }
 
 // ShareDataOperator used to operate share data
@@ -34,11 +35,22 @@ type ShareDataOperator interface {
 	AppendIDs(kt *kit.Kit, key string, ids ...string) error
 }
 
+// ProgressOperator used to report task heartbeat and progress, the progress is persisted on the task.
+type ProgressOperator interface {
+	// Heartbeat 上报任务心跳，表明任务执行节点存活。执行器在任务执行期间会定期自动上报心跳。
+	Heartbeat() error
+	// Report 上报任务执行进度及断点数据，同时视为一次心跳。checkpoint 为 nil 时保留上次上报的断点数据。
+	Report(percent uint, message string, checkpoint interface{}) error
+	// Checkpoint 将最近一次上报的断点数据解析到 v 中，任务重试时可以据此从断点处继续执行，没有断点数据时返回 false。
+	Checkpoint(v interface{}) (bool, error)
+}
+
 // NewExecuteContext new execute context for task exec.
-func NewExecuteContext(kt *kit.Kit, shareData ShareDataOperator) ExecuteKit {
+func NewExecuteContext(kt *kit.Kit, shareData ShareDataOperator, progress ProgressOperator) ExecuteKit {
 	return &DefExecuteContext{
 		kit:       kt,
 		shareData: shareData,
+		progress:  progress,
 	}
 }
 
@@ -46,6 +58,7 @@ func NewExecuteContext(kt *kit.Kit, shareData ShareDataOperator) ExecuteKit {
 type DefExecuteContext struct {
 	kit       *kit.Kit
 	shareData ShareDataOperator
+	progress  ProgressOperator
 }
 
 // Kit return kit.
@@ -57,3 +70,8 @@ func (ctx *DefExecuteContext) Kit() *kit.Kit {
 func (ctx *DefExecuteContext) ShareData() ShareDataOperator {
 	return ctx.shareData
 }
+
+// Progress return progress operator.
+func (ctx *DefExecuteContext) Progress() ProgressOperator {
+	return ctx.progress
+}
@@ -30,6 +30,10 @@ type FlowTemplate struct {
 	Name      enumor.FlowName       `json:"name" validate:"required"`
 	ShareData *tableasync.ShareData `json:"share_data"`
 	Tasks     []TaskTemplate        `json:"tasks" validate:"required,min=1"`
+	// RollbackPolicy 任务流失败后的回滚策略，创建任务流时未指定回滚策略则使用该策略，为空时不回滚
+	RollbackPolicy enumor.FlowRollbackPolicy `json:"rollback_policy" validate:"omitempty"`
+	// Priority 任务流优先级，创建任务流时未指定优先级则使用该优先级，为空时为 normal
+	Priority enumor.FlowPriority `json:"priority" validate:"omitempty"`
 }
 
 // Validate FlowTemplate.
@@ -38,6 +42,18 @@ func (tpl *FlowTemplate) Validate() error {
 		return err
 	}
 
+	if len(tpl.RollbackPolicy) != 0 {
+		if err := tpl.RollbackPolicy.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if len(tpl.Priority) != 0 {
+		if err := tpl.Priority.Validate(); err != nil {
+			return err
+		}
+	}
+
 	for _, one := range tpl.Tasks {
 		if err := one.Validate(); err != nil {
 			return err
@@ -50,7 +50,7 @@ func NewAsync(bd backend.Backend, ld leader.Leader, opt *Option) (Async, error)
 		return nil, err
 	}
 
-	pdr, err := producer.NewProducer(bd, opt.Register)
+	pdr, err := producer.NewProducer(bd, opt.Register, opt.ProducerOption)
 	if err != nil {
 		logs.Errorf("new producer failed, err: %v", err)
 		return nil, err
@@ -23,6 +23,7 @@ package backend
 import (
 	"hcm/pkg/api/core"
 	"hcm/pkg/async/backend/model"
+	"hcm/pkg/criteria/enumor"
 	"hcm/pkg/criteria/validator"
 	typesasync "hcm/pkg/dal/dao/types/async"
 	"hcm/pkg/kit"
@@ -35,12 +36,19 @@ type Backend interface {
 	*/
 	// CreateFlow 创建任务流
 	CreateFlow(kt *kit.Kit, flow *model.Flow) (string, error)
+	// CreateFlowIdempotently 创建任务流并在同一事务中保存幂等键，幂等键已存在且未过期时不创建任务流，
+	// 请求内容摘要相同时返回首次创建的任务流ID，不同时返回 errf.IdempotencyKeyConflict 错误
+	CreateFlowIdempotently(kt *kit.Kit, flow *model.Flow, idem *model.FlowIdempotency) (string, error)
+	// DeleteExpiredFlowIdempotency 删除已过期的幂等键
+	DeleteExpiredFlowIdempotency(kt *kit.Kit) error
 	// BatchUpdateFlow 批量更新任务流
 	BatchUpdateFlow(kt *kit.Kit, flows []model.Flow) error
 	// ListFlow 查询任务流
 	ListFlow(kt *kit.Kit, input *ListInput) ([]model.Flow, error)
 	// BatchUpdateFlowStateByCAS CAS批量更新Flow状态
 	BatchUpdateFlowStateByCAS(kt *kit.Kit, infos []UpdateFlowInfo) error
+	// RestartFlow 在同一事务中将任务流由 Source 状态CAS重置为Pending，并将其中处于 TaskSource 状态的任务重置为Pending
+	RestartFlow(kt *kit.Kit, info *RestartFlowInfo) error
 
 	/*
 		Task 相关接口
@@ -53,8 +61,50 @@ type Backend interface {
 	UpdateTaskStateByCAS(kt *kit.Kit, info *UpdateTaskInfo) error
 	// ListTask 查询任务
 	ListTask(kt *kit.Kit, input *ListInput) ([]model.Task, error)
+
+	/*
+		FlowSchedule 相关接口
+	*/
+	// CreateFlowSchedule 创建定时任务流
+	CreateFlowSchedule(kt *kit.Kit, schedule *model.FlowSchedule) (string, error)
+	// UpdateFlowSchedule 更新定时任务流
+	UpdateFlowSchedule(kt *kit.Kit, schedule *model.FlowSchedule) error
+	// UpdateFlowScheduleNextRunByCAS CAS更新处于启用状态的定时任务流的下次执行时间，Pause 为true时同时暂停定时任务流
+	UpdateFlowScheduleNextRunByCAS(kt *kit.Kit, info *UpdateScheduleNextRunInfo) error
+	// ListFlowSchedule 查询定时任务流
+	ListFlowSchedule(kt *kit.Kit, input *ListInput) ([]model.FlowSchedule, error)
+	// DeleteFlowSchedule 删除定时任务流及其执行记录
+	DeleteFlowSchedule(kt *kit.Kit, id string) error
+	// BatchCreateFlowScheduleRun 批量创建定时任务流执行记录
+	BatchCreateFlowScheduleRun(kt *kit.Kit, runs []model.FlowScheduleRun) error
+	// ListFlowScheduleRun 查询定时任务流执行记录
+	ListFlowScheduleRun(kt *kit.Kit, input *ListInput) ([]model.FlowScheduleRun, error)
+
+	/*
+		FlowEvent 相关接口，任务流事件在创建任务流、任务流状态切换到 running、success、failed、cancel 时，
+		与任务流在同一事务中生成，不需要单独创建。
+	*/
+	// ListFlowEvent 查询任务流事件
+	ListFlowEvent(kt *kit.Kit, input *ListInput) ([]model.FlowEvent, error)
+	// UpdateFlowEvent 更新任务流事件的投递结果
+	UpdateFlowEvent(kt *kit.Kit, event *model.FlowEvent) error
+
+	/*
+		FlowArchive 相关接口
+	*/
+	// ArchiveFlow 在同一事务中将任务流及其任务、事件保存到归档表后删除，不处于 ArchivableFlowStates 的任务流会被忽略
+	ArchiveFlow(kt *kit.Kit, ids []string) error
+	// ListFlowArchive 查询已归档的任务流
+	ListFlowArchive(kt *kit.Kit, input *ListInput) ([]model.FlowArchive, error)
 }
 
+// ArchivableFlowStates 可以归档的任务流状态，任务流处于这些状态时已经结束，不会再被调度执行
+var ArchivableFlowStates = []enumor.FlowState{enumor.FlowSuccess, enumor.FlowFailed, enumor.FlowCancel,
+	enumor.FlowRolledBack}
+
+// maxArchiveFlowNum 单次最多归档的任务流数量
+const maxArchiveFlowNum = 100
+
 // ListInput 查询输入参数
 type ListInput core.ListReq
 
@@ -66,6 +116,14 @@ func (info *UpdateFlowInfo) Validate() error {
 	return validator.Validate.Struct(info)
 }
 
+// RestartFlowInfo define restart flow info.
+type RestartFlowInfo typesasync.RestartFlowInfo
+
+// Validate RestartFlowInfo
+func (info *RestartFlowInfo) Validate() error {
+	return validator.Validate.Struct(info)
+}
+
 // UpdateTaskInfo define update task info.
 type UpdateTaskInfo typesasync.UpdateTaskInfo
 
@@ -73,3 +131,11 @@ type UpdateTaskInfo typesasync.UpdateTaskInfo
 func (info *UpdateTaskInfo) Validate() error {
 	return validator.Validate.Struct(info)
 }
+
+// UpdateScheduleNextRunInfo define update flow schedule next run info.
+type UpdateScheduleNextRunInfo typesasync.UpdateScheduleNextRunInfo
+
+// Validate UpdateScheduleNextRunInfo
+func (info *UpdateScheduleNextRunInfo) Validate() error {
+	return validator.Validate.Struct(info)
+}
@@ -0,0 +1,1188 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package backendtest 提供 backend.Backend 的一致性测试集，所有后端实现（包括 mysql）都需要通过该测试集。
+package backendtest
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"hcm/pkg/api/core"
+	"hcm/pkg/async/action"
+	"hcm/pkg/async/backend"
+	"hcm/pkg/async/backend/model"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/tools"
+	tableasync "hcm/pkg/dal/table/async"
+	"hcm/pkg/kit"
+	"hcm/pkg/runtime/filter"
+	"hcm/pkg/tools/converter"
+	"hcm/pkg/tools/json"
+	"hcm/pkg/tools/times"
+)
+
+const testFlowName enumor.FlowName = "backend_conformance"
+
+// Run 对 newBackend 返回的后端执行一致性测试，测试数据通过唯一的 memo 隔离，可以在已有数据的库上执行。
+func Run(t *testing.T, newBackend func(t *testing.T) backend.Backend) {
+	cases := []struct {
+		name string
+		run  func(t *testing.T, bd backend.Backend)
+	}{
+		{name: "CreateFlow", run: testCreateFlow},
+		{name: "BatchUpdateFlow", run: testBatchUpdateFlow},
+		{name: "BatchUpdateFlowStateByCAS", run: testBatchUpdateFlowStateByCAS},
+		{name: "ConcurrentFlowStateByCAS", run: testConcurrentFlowStateByCAS},
+		{name: "TaskCRUD", run: testTaskCRUD},
+		{name: "UpdateTaskStateByCAS", run: testUpdateTaskStateByCAS},
+		{name: "RestartFlow", run: testRestartFlow},
+		{name: "ListFilterAndPage", run: testListFilterAndPage},
+		{name: "FlowRunAt", run: testFlowRunAt},
+		{name: "FlowRollbackPolicy", run: testFlowRollbackPolicy},
+		{name: "FlowPriority", run: testFlowPriority},
+		{name: "FlowSchedule", run: testFlowSchedule},
+		{name: "FlowScheduleRun", run: testFlowScheduleRun},
+		{name: "FlowEvent", run: testFlowEvent},
+		{name: "FlowArchive", run: testFlowArchive},
+		{name: "FlowIdempotency", run: testFlowIdempotency},
+	}
+
+	for _, c := range cases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			c.run(t, newBackend(t))
+		})
+	}
+}
+
+func newKit() *kit.Kit {
+	kt := kit.New()
+	kt.User = "conformance"
+	return kt
+}
+
+func uniqueMemo() string {
+	return fmt.Sprintf("conformance-%d", time.Now().UnixNano())
+}
+
+func newFlow(memo string, taskNum int) *model.Flow {
+	shareData := tableasync.NewShareData()
+	shareData.Dict["key"] = "value"
+
+	tasks := make([]model.Task, 0, taskNum)
+	for i := 0; i < taskNum; i++ {
+		task := model.Task{
+			FlowName:   testFlowName,
+			ActionID:   action.ActIDType(fmt.Sprintf("%d", i+1)),
+			ActionName: "test",
+			Params:     `{"index":1}`,
+			Retry:      &tableasync.Retry{Enable: false},
+		}
+		if i > 0 {
+			task.DependOn = []action.ActIDType{action.ActIDType(fmt.Sprintf("%d", i))}
+		}
+		tasks = append(tasks, task)
+	}
+
+	return &model.Flow{
+		Name:      testFlowName,
+		ShareData: shareData,
+		Memo:      memo,
+		Tasks:     tasks,
+	}
+}
+
+func mustCreateFlow(t *testing.T, bd backend.Backend, flow *model.Flow) string {
+	id, err := bd.CreateFlow(newKit(), flow)
+	if err != nil {
+		t.Fatalf("create flow failed, err: %v", err)
+	}
+
+	if len(id) == 0 {
+		t.Fatalf("create flow return empty id")
+	}
+
+	return id
+}
+
+func getFlow(t *testing.T, bd backend.Backend, id string) model.Flow {
+	flows, err := bd.ListFlow(newKit(), &backend.ListInput{
+		Filter: tools.EqualExpression("id", id),
+		Page:   core.NewDefaultBasePage(),
+	})
+	if err != nil {
+		t.Fatalf("list flow failed, err: %v", err)
+	}
+
+	if len(flows) != 1 {
+		t.Fatalf("flow %s should be found once, but got %d", id, len(flows))
+	}
+
+	return flows[0]
+}
+
+func listFlowTasks(t *testing.T, bd backend.Backend, flowID string) []model.Task {
+	tasks, err := bd.ListTask(newKit(), &backend.ListInput{
+		Filter: tools.EqualExpression("flow_id", flowID),
+		Page:   core.NewDefaultBasePage(),
+	})
+	if err != nil {
+		t.Fatalf("list task failed, err: %v", err)
+	}
+
+	return tasks
+}
+
+func getTask(t *testing.T, bd backend.Backend, id string) model.Task {
+	tasks, err := bd.ListTask(newKit(), &backend.ListInput{
+		Filter: tools.EqualExpression("id", id),
+		Page:   core.NewDefaultBasePage(),
+	})
+	if err != nil {
+		t.Fatalf("list task failed, err: %v", err)
+	}
+
+	if len(tasks) != 1 {
+		t.Fatalf("task %s should be found once, but got %d", id, len(tasks))
+	}
+
+	return tasks[0]
+}
+
+func testCreateFlow(t *testing.T, bd backend.Backend) {
+	memo := uniqueMemo()
+	id := mustCreateFlow(t, bd, newFlow(memo, 2))
+
+	flow := getFlow(t, bd, id)
+	if flow.Name != testFlowName || flow.Memo != memo || flow.State != enumor.FlowPending {
+		t.Errorf("unexpected flow: %+v", flow)
+	}
+
+	if flow.Worker == nil || *flow.Worker != "" {
+		t.Errorf("new flow's worker should be empty, but got %v", flow.Worker)
+	}
+
+	if val, ok := flow.ShareData.Get("key"); !ok || val != "value" {
+		t.Errorf("share data not saved, got: %v", flow.ShareData.Dict)
+	}
+
+	if flow.Creator != "conformance" || len(flow.CreatedAt) == 0 || len(flow.UpdatedAt) == 0 {
+		t.Errorf("flow creator or time not set: %+v", flow)
+	}
+
+	tasks := listFlowTasks(t, bd, id)
+	if len(tasks) != 2 {
+		t.Fatalf("flow should have 2 tasks, but got %d", len(tasks))
+	}
+
+	for _, one := range tasks {
+		if one.State != enumor.TaskPending || one.FlowID != id || one.FlowName != testFlowName {
+			t.Errorf("unexpected task: %+v", one)
+		}
+
+		if one.ActionID == "2" && (len(one.DependOn) != 1 || one.DependOn[0] != "1") {
+			t.Errorf("task depend on not saved, got: %v", one.DependOn)
+		}
+	}
+
+	if tasks[0].ID == tasks[1].ID {
+		t.Errorf("task id should be unique, got: %s", tasks[0].ID)
+	}
+}
+
+func testBatchUpdateFlow(t *testing.T, bd backend.Backend) {
+	id := mustCreateFlow(t, bd, newFlow(uniqueMemo(), 1))
+
+	update := []model.Flow{{
+		ID:     id,
+		State:  enumor.FlowScheduled,
+		Worker: converter.ValToPtr("worker-1"),
+		Reason: &tableasync.Reason{Message: "scheduled"},
+	}}
+	if err := bd.BatchUpdateFlow(newKit(), update); err != nil {
+		t.Fatalf("update flow failed, err: %v", err)
+	}
+
+	flow := getFlow(t, bd, id)
+	if flow.State != enumor.FlowScheduled || *flow.Worker != "worker-1" || flow.Reason.Message != "scheduled" {
+		t.Errorf("flow not updated: %+v", flow)
+	}
+
+	if val, _ := flow.ShareData.Get("key"); val != "value" {
+		t.Errorf("share data should not be updated, got: %v", flow.ShareData.Dict)
+	}
+
+	// worker 允许更新为空值
+	update = []model.Flow{{ID: id, State: enumor.FlowPending, Worker: converter.ValToPtr("")}}
+	if err := bd.BatchUpdateFlow(newKit(), update); err != nil {
+		t.Fatalf("update flow failed, err: %v", err)
+	}
+
+	flow = getFlow(t, bd, id)
+	if flow.State != enumor.FlowPending || *flow.Worker != "" {
+		t.Errorf("flow worker should be reset, got: %+v", flow)
+	}
+
+	update = []model.Flow{{ID: "not-exist-flow", State: enumor.FlowRunning}}
+	if err := bd.BatchUpdateFlow(newKit(), update); err == nil {
+		t.Errorf("update not exist flow should be failed")
+	}
+}
+
+func testBatchUpdateFlowStateByCAS(t *testing.T, bd backend.Backend) {
+	memo := uniqueMemo()
+	first := mustCreateFlow(t, bd, newFlow(memo, 1))
+	second := mustCreateFlow(t, bd, newFlow(memo, 1))
+
+	infos := []backend.UpdateFlowInfo{
+		{ID: first, Source: enumor.FlowPending, Target: enumor.FlowScheduled, Worker: "worker-1"},
+		{ID: second, Source: enumor.FlowPending, Target: enumor.FlowScheduled, Worker: "worker-2"},
+	}
+	if err := bd.BatchUpdateFlowStateByCAS(newKit(), infos); err != nil {
+		t.Fatalf("cas update flow failed, err: %v", err)
+	}
+
+	flow := getFlow(t, bd, first)
+	if flow.State != enumor.FlowScheduled || *flow.Worker != "worker-1" {
+		t.Errorf("flow not updated by cas: %+v", flow)
+	}
+
+	// 其中一个任务流状态不符合预期时，整批更新都不能生效
+	infos = []backend.UpdateFlowInfo{
+		{ID: first, Source: enumor.FlowScheduled, Target: enumor.FlowRunning},
+		{ID: second, Source: enumor.FlowPending, Target: enumor.FlowRunning},
+	}
+	if err := bd.BatchUpdateFlowStateByCAS(newKit(), infos); err == nil {
+		t.Fatalf("cas update flow with unexpected source state should be failed")
+	}
+
+	if flow = getFlow(t, bd, first); flow.State != enumor.FlowScheduled {
+		t.Errorf("cas batch should be rollback, but flow state is %s", flow.State)
+	}
+
+	infos = []backend.UpdateFlowInfo{{ID: first, Source: enumor.FlowScheduled, Target: enumor.FlowFailed,
+		Reason: &tableasync.Reason{Message: "failed"}}}
+	if err := bd.BatchUpdateFlowStateByCAS(newKit(), infos); err != nil {
+		t.Fatalf("cas update flow failed, err: %v", err)
+	}
+
+	flow = getFlow(t, bd, first)
+	if flow.State != enumor.FlowFailed || flow.Reason.Message != "failed" || *flow.Worker != "worker-1" {
+		t.Errorf("flow not updated by cas: %+v", flow)
+	}
+
+	if err := bd.BatchUpdateFlowStateByCAS(newKit(), []backend.UpdateFlowInfo{{ID: first}}); err == nil {
+		t.Errorf("cas update flow with invalid info should be failed")
+	}
+
+	infos = []backend.UpdateFlowInfo{{ID: second, Source: enumor.FlowScheduled, Target: enumor.FlowPending,
+		ResetWorker: true}}
+	if err := bd.BatchUpdateFlowStateByCAS(newKit(), infos); err != nil {
+		t.Fatalf("cas update flow failed, err: %v", err)
+	}
+
+	if flow = getFlow(t, bd, second); flow.State != enumor.FlowPending || len(*flow.Worker) != 0 {
+		t.Errorf("flow worker should be reset by cas: %+v", flow)
+	}
+}
+
+func testConcurrentFlowStateByCAS(t *testing.T, bd backend.Backend) {
+	id := mustCreateFlow(t, bd, newFlow(uniqueMemo(), 1))
+
+	const workers = 10
+	var wg sync.WaitGroup
+	var lock sync.Mutex
+	success := 0
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			infos := []backend.UpdateFlowInfo{{ID: id, Source: enumor.FlowPending, Target: enumor.FlowScheduled,
+				Worker: fmt.Sprintf("worker-%d", i)}}
+			if err := bd.BatchUpdateFlowStateByCAS(newKit(), infos); err == nil {
+				lock.Lock()
+				success++
+				lock.Unlock()
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if success != 1 {
+		t.Errorf("only one cas update should be success, but got %d", success)
+	}
+}
+
+func testTaskCRUD(t *testing.T, bd backend.Backend) {
+	flowID := mustCreateFlow(t, bd, newFlow(uniqueMemo(), 1))
+
+	tasks := []model.Task{{
+		FlowID:     flowID,
+		FlowName:   testFlowName,
+		ActionID:   "100",
+		ActionName: "test",
+		Params:     `{"index":100}`,
+		Retry:      &tableasync.Retry{Enable: true, Policy: &tableasync.RetryPolicy{Count: 1, SleepRangeMS: [2]uint{1, 2}}},
+		Reason:     new(tableasync.Reason),
+		Creator:    "conformance",
+		Reviser:    "conformance",
+	}}
+	ids, err := bd.BatchCreateTask(newKit(), tasks)
+	if err != nil {
+		t.Fatalf("create task failed, err: %v", err)
+	}
+
+	if len(ids) != 1 {
+		t.Fatalf("create task should return 1 id, but got %d", len(ids))
+	}
+
+	if got := listFlowTasks(t, bd, flowID); len(got) != 2 {
+		t.Errorf("flow should have 2 tasks, but got %d", len(got))
+	}
+
+	update := &model.Task{
+		ID:     ids[0],
+		State:  enumor.TaskSuccess,
+		Result: `{"ok":true}`,
+		Reason: &tableasync.Reason{Message: "done"},
+		Stat: &tableasync.TaskStat{StartedAt: "2023-12-18T11:00:00+08:00", EndedAt: "2023-12-18T11:00:10+08:00",
+			RunCount: 2},
+		Progress:    &tableasync.TaskProgress{Percent: 60, Message: "polling", Checkpoint: `{"page":2}`},
+		HeartbeatAt: times.ConvStdTimeFormat(time.Date(2023, 12, 18, 11, 0, 10, 0, time.Local)),
+	}
+	if err = bd.UpdateTask(newKit(), update); err != nil {
+		t.Fatalf("update task failed, err: %v", err)
+	}
+
+	task := getTask(t, bd, ids[0])
+	if task.State != enumor.TaskSuccess || task.Reason.Message != "done" {
+		t.Errorf("task not updated: %+v", task)
+	}
+
+	if task.Retry == nil || !task.Retry.Enable || task.Params != `{"index":100}` {
+		t.Errorf("task fields not updated should be kept, got: %+v", task)
+	}
+
+	if task.Result != `{"ok":true}` {
+		t.Errorf("task result not updated, got: %s", task.Result)
+	}
+
+	if task.Stat == nil || *task.Stat != *update.Stat {
+		t.Errorf("task stat not updated, got: %+v", task.Stat)
+	}
+
+	if task.Progress == nil || task.Progress.Percent != 60 || task.Progress.Message != "polling" {
+		t.Errorf("task progress not updated, got: %+v", task.Progress)
+	}
+
+	checkpoint := make(map[string]int)
+	if err = json.UnmarshalFromString(string(task.Progress.Checkpoint), &checkpoint); err != nil ||
+		checkpoint["page"] != 2 {
+		t.Errorf("task progress checkpoint not updated, got: %s, err: %v", task.Progress.Checkpoint, err)
+	}
+
+	if task.HeartbeatAt != update.HeartbeatAt {
+		t.Errorf("task heartbeat_at not updated, expect: %s, got: %s", update.HeartbeatAt, task.HeartbeatAt)
+	}
+
+	// 更新不存在的任务返回记录不存在
+	err = bd.UpdateTask(newKit(), &model.Task{ID: "not-exist-task", State: enumor.TaskSuccess})
+	if ef := errf.Error(err); ef == nil || ef.Code != errf.RecordNotFound {
+		t.Errorf("update not exist task should return record not found, but got: %v", err)
+	}
+}
+
+func testUpdateTaskStateByCAS(t *testing.T, bd backend.Backend) {
+	flowID := mustCreateFlow(t, bd, newFlow(uniqueMemo(), 1))
+	tasks := listFlowTasks(t, bd, flowID)
+	if len(tasks) != 1 {
+		t.Fatalf("flow should have 1 task, but got %d", len(tasks))
+	}
+	id := tasks[0].ID
+
+	info := &backend.UpdateTaskInfo{ID: id, Source: enumor.TaskPending, Target: enumor.TaskRunning}
+	if err := bd.UpdateTaskStateByCAS(newKit(), info); err != nil {
+		t.Fatalf("cas update task failed, err: %v", err)
+	}
+
+	if task := getTask(t, bd, id); task.State != enumor.TaskRunning {
+		t.Errorf("task not updated by cas, state: %s", task.State)
+	}
+
+	// 源状态不符合预期时更新失败
+	if err := bd.UpdateTaskStateByCAS(newKit(), info); err == nil {
+		t.Errorf("cas update task with unexpected source state should be failed")
+	}
+
+	info = &backend.UpdateTaskInfo{ID: id, Source: enumor.TaskRunning, Target: enumor.TaskFailed,
+		Reason: &tableasync.Reason{Message: "failed"}}
+	if err := bd.UpdateTaskStateByCAS(newKit(), info); err != nil {
+		t.Fatalf("cas update task failed, err: %v", err)
+	}
+
+	task := getTask(t, bd, id)
+	if task.State != enumor.TaskFailed || task.Reason.Message != "failed" {
+		t.Errorf("task not updated by cas: %+v", task)
+	}
+}
+
+func testRestartFlow(t *testing.T, bd backend.Backend) {
+	id := mustCreateFlow(t, bd, newFlow(uniqueMemo(), 2))
+	tasks := listFlowTasks(t, bd, id)
+	failed, succeeded := tasks[0].ID, tasks[1].ID
+
+	kt := newKit()
+	if err := bd.UpdateTask(kt, &model.Task{ID: failed, State: enumor.TaskFailed,
+		Reason: &tableasync.Reason{Message: "failed"}}); err != nil {
+		t.Fatalf("update task failed, err: %v", err)
+	}
+	if err := bd.UpdateTask(kt, &model.Task{ID: succeeded, State: enumor.TaskSuccess}); err != nil {
+		t.Fatalf("update task failed, err: %v", err)
+	}
+	if err := bd.BatchUpdateFlow(kt, []model.Flow{{ID: id, State: enumor.FlowFailed}}); err != nil {
+		t.Fatalf("update flow failed, err: %v", err)
+	}
+
+	info := &backend.RestartFlowInfo{ID: id, Source: enumor.FlowFailed, TaskSource: enumor.TaskFailed}
+	if err := bd.RestartFlow(kt, info); err != nil {
+		t.Fatalf("restart flow failed, err: %v", err)
+	}
+
+	if flow := getFlow(t, bd, id); flow.State != enumor.FlowPending {
+		t.Errorf("restarted flow should be pending, but got %s", flow.State)
+	}
+
+	if task := getTask(t, bd, failed); task.State != enumor.TaskPending || len(task.Reason.Message) != 0 {
+		t.Errorf("failed task should be reset to pending, got: %+v", task)
+	}
+
+	if task := getTask(t, bd, succeeded); task.State != enumor.TaskSuccess {
+		t.Errorf("succeeded task should be kept, but got %s", task.State)
+	}
+
+	// 任务流状态不符合预期时，任务不能被重置
+	if err := bd.UpdateTask(kt, &model.Task{ID: failed, State: enumor.TaskFailed}); err != nil {
+		t.Fatalf("update task failed, err: %v", err)
+	}
+	if err := bd.RestartFlow(kt, info); err == nil {
+		t.Fatalf("restart flow with unexpected source state should be failed")
+	}
+
+	if task := getTask(t, bd, failed); task.State != enumor.TaskFailed {
+		t.Errorf("task should not be reset when restart flow failed, but got %s", task.State)
+	}
+}
+
+func testListFilterAndPage(t *testing.T, bd backend.Backend) {
+	memo := uniqueMemo()
+	ids := make([]string, 0, 3)
+	for i := 0; i < 3; i++ {
+		ids = append(ids, mustCreateFlow(t, bd, newFlow(memo, 1)))
+	}
+
+	update := []model.Flow{{ID: ids[2], State: enumor.FlowRunning}}
+	if err := bd.BatchUpdateFlow(newKit(), update); err != nil {
+		t.Fatalf("update flow failed, err: %v", err)
+	}
+
+	list := func(state []enumor.FlowState, page *core.BasePage) []model.Flow {
+		input := &backend.ListInput{
+			Filter: &filter.Expression{
+				Op: filter.And,
+				Rules: []filter.RuleFactory{
+					&filter.AtomRule{Field: "memo", Op: filter.Equal.Factory(), Value: memo},
+					&filter.AtomRule{Field: "state", Op: filter.In.Factory(), Value: state},
+				},
+			},
+			Page: page,
+		}
+		flows, err := bd.ListFlow(newKit(), input)
+		if err != nil {
+			t.Fatalf("list flow failed, err: %v", err)
+		}
+		return flows
+	}
+
+	states := []enumor.FlowState{enumor.FlowPending, enumor.FlowRunning}
+	first := list(states, &core.BasePage{Start: 0, Limit: 2})
+	second := list(states, &core.BasePage{Start: 2, Limit: 2})
+	if len(first) != 2 || len(second) != 1 {
+		t.Fatalf("page result not right, first: %d, second: %d", len(first), len(second))
+	}
+
+	// 默认按照 id 升序排列
+	if first[0].ID != ids[0] || first[1].ID != ids[1] || second[0].ID != ids[2] {
+		t.Errorf("page result should sort by id, got: %s, %s, %s", first[0].ID, first[1].ID, second[0].ID)
+	}
+
+	desc := list(states, &core.BasePage{Start: 0, Limit: 1, Sort: "id", Order: core.Descending})
+	if len(desc) != 1 || desc[0].ID != ids[2] {
+		t.Errorf("page result should sort by id desc, got: %+v", desc)
+	}
+
+	if pending := list([]enumor.FlowState{enumor.FlowPending}, core.NewDefaultBasePage()); len(pending) != 2 {
+		t.Errorf("filter by state should return 2 flows, but got %d", len(pending))
+	}
+
+	if counted := list(states, &core.BasePage{Count: true}); len(counted) != 0 {
+		t.Errorf("count page should return empty list, but got %d", len(counted))
+	}
+
+	// 时间字段按照时间比较，watch dog 依赖该能力查询超时任务
+	future := times.ConvStdTimeFormat(times.ConvStdTimeNow().Add(time.Hour))
+	for op, expect := range map[filter.OpType]int{filter.LessThan: 3, filter.GreaterThan: 0} {
+		input := &backend.ListInput{
+			Filter: &filter.Expression{
+				Op: filter.And,
+				Rules: []filter.RuleFactory{
+					&filter.AtomRule{Field: "memo", Op: filter.Equal.Factory(), Value: memo},
+					&filter.AtomRule{Field: "updated_at", Op: op.Factory(), Value: future},
+				},
+			},
+			Page: core.NewDefaultBasePage(),
+		}
+		flows, err := bd.ListFlow(newKit(), input)
+		if err != nil {
+			t.Fatalf("list flow by updated_at failed, err: %v", err)
+		}
+
+		if len(flows) != expect {
+			t.Errorf("list flow by updated_at %s should return %d flows, but got %d", op, expect, len(flows))
+		}
+	}
+
+	input := &backend.ListInput{
+		Filter: tools.EqualExpression("not_exist_field", memo),
+		Page:   core.NewDefaultBasePage(),
+	}
+	if _, err := bd.ListFlow(newKit(), input); err == nil {
+		t.Errorf("list flow with unknown field should be failed")
+	}
+}
+
+func testFlowRunAt(t *testing.T, bd backend.Backend) {
+	memo := uniqueMemo()
+	nowID := mustCreateFlow(t, bd, newFlow(memo, 1))
+
+	delayed := newFlow(memo, 1)
+	delayed.RunAt = times.ConvStdTimeFormat(times.ConvStdTimeNow().Add(time.Hour))
+	delayedID := mustCreateFlow(t, bd, delayed)
+
+	if flow := getFlow(t, bd, delayedID); flow.RunAt != delayed.RunAt {
+		t.Errorf("flow run_at should be %s, but got %s", delayed.RunAt, flow.RunAt)
+	}
+
+	if flow := getFlow(t, bd, nowID); len(flow.RunAt) == 0 {
+		t.Errorf("flow run_at should default to now")
+	}
+
+	// dispatcher 只派发已经到达执行时间的任务流
+	input := &backend.ListInput{
+		Filter: &filter.Expression{
+			Op: filter.And,
+			Rules: []filter.RuleFactory{
+				&filter.AtomRule{Field: "memo", Op: filter.Equal.Factory(), Value: memo},
+				&filter.AtomRule{Field: "run_at", Op: filter.LessThanEqual.Factory(),
+					Value: times.ConvStdTimeFormat(time.Now())},
+			},
+		},
+		Page: core.NewDefaultBasePage(),
+	}
+	flows, err := bd.ListFlow(newKit(), input)
+	if err != nil {
+		t.Fatalf("list flow by run_at failed, err: %v", err)
+	}
+
+	if len(flows) != 1 || flows[0].ID != nowID {
+		t.Errorf("list flow by run_at should only return %s, but got %+v", nowID, flows)
+	}
+
+	invalid := newFlow(memo, 1)
+	invalid.RunAt = "2006-01-02 15:04:05"
+	if _, err = bd.CreateFlow(newKit(), invalid); err == nil {
+		t.Errorf("create flow with invalid run_at should be failed")
+	}
+}
+
+func testFlowRollbackPolicy(t *testing.T, bd backend.Backend) {
+	memo := uniqueMemo()
+	defaultID := mustCreateFlow(t, bd, newFlow(memo, 1))
+	if flow := getFlow(t, bd, defaultID); flow.RollbackPolicy != enumor.FlowRollbackNone {
+		t.Errorf("flow rollback_policy should default to %s, but got %s", enumor.FlowRollbackNone,
+			flow.RollbackPolicy)
+	}
+
+	saga := newFlow(memo, 1)
+	saga.RollbackPolicy = enumor.FlowRollbackSaga
+	sagaID := mustCreateFlow(t, bd, saga)
+	if flow := getFlow(t, bd, sagaID); flow.RollbackPolicy != enumor.FlowRollbackSaga {
+		t.Errorf("flow rollback_policy should be %s, but got %s", enumor.FlowRollbackSaga, flow.RollbackPolicy)
+	}
+
+	invalid := newFlow(memo, 1)
+	invalid.RollbackPolicy = "invalid"
+	if _, err := bd.CreateFlow(newKit(), invalid); err == nil {
+		t.Errorf("create flow with invalid rollback_policy should be failed")
+	}
+}
+
+func testFlowPriority(t *testing.T, bd backend.Backend) {
+	memo := uniqueMemo()
+	defaultID := mustCreateFlow(t, bd, newFlow(memo, 1))
+	if flow := getFlow(t, bd, defaultID); flow.Priority != enumor.FlowPriorityNormal {
+		t.Errorf("flow priority should default to %s, but got %s", enumor.FlowPriorityNormal, flow.Priority)
+	}
+
+	high := newFlow(memo, 1)
+	high.Priority = enumor.FlowPriorityHigh
+	high.Tenant = "biz-2"
+	highID := mustCreateFlow(t, bd, high)
+	if flow := getFlow(t, bd, highID); flow.Priority != high.Priority || flow.Tenant != high.Tenant {
+		t.Errorf("flow priority and tenant should be %s/%s, but got %s/%s", high.Priority, high.Tenant,
+			flow.Priority, flow.Tenant)
+	}
+
+	// dispatcher 按照优先级分别查询待派发的任务流
+	input := &backend.ListInput{
+		Filter: &filter.Expression{
+			Op: filter.And,
+			Rules: []filter.RuleFactory{
+				&filter.AtomRule{Field: "memo", Op: filter.Equal.Factory(), Value: memo},
+				&filter.AtomRule{Field: "priority", Op: filter.Equal.Factory(), Value: enumor.FlowPriorityHigh},
+			},
+		},
+		Page: core.NewDefaultBasePage(),
+	}
+	flows, err := bd.ListFlow(newKit(), input)
+	if err != nil {
+		t.Fatalf("list flow by priority failed, err: %v", err)
+	}
+
+	if len(flows) != 1 || flows[0].ID != highID {
+		t.Errorf("list flow by priority should only return %s, but got %+v", highID, flows)
+	}
+
+	invalid := newFlow(memo, 1)
+	invalid.Priority = "invalid"
+	if _, err = bd.CreateFlow(newKit(), invalid); err == nil {
+		t.Errorf("create flow with invalid priority should be failed")
+	}
+}
+
+func newFlowSchedule(name string, nextRunAt time.Time) *model.FlowSchedule {
+	flow := newFlow(name, 2)
+	tasks := make([]tableasync.ScheduleFlowTask, 0, len(flow.Tasks))
+	for _, one := range flow.Tasks {
+		dependOn := make([]string, 0, len(one.DependOn))
+		for _, id := range one.DependOn {
+			dependOn = append(dependOn, string(id))
+		}
+
+		tasks = append(tasks, tableasync.ScheduleFlowTask{
+			ActionID:   string(one.ActionID),
+			ActionName: one.ActionName,
+			Params:     one.Params,
+			Retry:      one.Retry,
+			DependOn:   dependOn,
+		})
+	}
+
+	return &model.FlowSchedule{
+		Name:         name,
+		Cron:         "*/5 * * * *",
+		State:        enumor.FlowScheduleEnabled,
+		MissedPolicy: enumor.MissedRunSkip,
+		Flow: &tableasync.ScheduleFlow{
+			Name:      flow.Name,
+			Memo:      name,
+			ShareData: flow.ShareData,
+			Tasks:     tasks,
+		},
+		NextRunAt: times.ConvStdTimeFormat(nextRunAt),
+		Memo:      name,
+	}
+}
+
+func getFlowSchedule(t *testing.T, bd backend.Backend, id string) model.FlowSchedule {
+	schedules, err := bd.ListFlowSchedule(newKit(), &backend.ListInput{
+		Filter: tools.EqualExpression("id", id),
+		Page:   core.NewDefaultBasePage(),
+	})
+	if err != nil {
+		t.Fatalf("list flow schedule failed, err: %v", err)
+	}
+
+	if len(schedules) != 1 {
+		t.Fatalf("flow schedule %s should be found once, but got %d", id, len(schedules))
+	}
+
+	return schedules[0]
+}
+
+func testFlowSchedule(t *testing.T, bd backend.Backend) {
+	name := uniqueMemo()
+	nextRunAt := times.ConvStdTimeNow().Truncate(time.Second).Add(-time.Minute)
+	id, err := bd.CreateFlowSchedule(newKit(), newFlowSchedule(name, nextRunAt))
+	if err != nil {
+		t.Fatalf("create flow schedule failed, err: %v", err)
+	}
+
+	if _, err = bd.CreateFlowSchedule(newKit(), newFlowSchedule(name, nextRunAt)); err == nil {
+		t.Errorf("create flow schedule with duplicate name should be failed")
+	}
+
+	schedule := getFlowSchedule(t, bd, id)
+	if schedule.Name != name || schedule.State != enumor.FlowScheduleEnabled ||
+		schedule.NextRunAt != times.ConvStdTimeFormat(nextRunAt) || schedule.Creator != "conformance" {
+		t.Errorf("unexpected flow schedule: %+v", schedule)
+	}
+
+	if schedule.Flow == nil || len(schedule.Flow.Tasks) != 2 || len(schedule.Flow.Tasks[1].DependOn) != 1 {
+		t.Fatalf("flow schedule's flow not saved, got: %+v", schedule.Flow)
+	}
+
+	// 到期查询，定时器依赖该能力查询需要执行的定时任务流
+	input := &backend.ListInput{
+		Filter: &filter.Expression{
+			Op: filter.And,
+			Rules: []filter.RuleFactory{
+				&filter.AtomRule{Field: "memo", Op: filter.Equal.Factory(), Value: name},
+				&filter.AtomRule{Field: "next_run_at", Op: filter.LessThanEqual.Factory(),
+					Value: times.ConvStdTimeFormat(time.Now())},
+			},
+		},
+		Page: core.NewDefaultBasePage(),
+	}
+	due, err := bd.ListFlowSchedule(newKit(), input)
+	if err != nil {
+		t.Fatalf("list flow schedule by next_run_at failed, err: %v", err)
+	}
+
+	if len(due) != 1 {
+		t.Errorf("list due flow schedule should return 1, but got %d", len(due))
+	}
+
+	target := nextRunAt.Add(5 * time.Minute)
+	info := &backend.UpdateScheduleNextRunInfo{ID: id, Source: nextRunAt, Target: target}
+	if err = bd.UpdateFlowScheduleNextRunByCAS(newKit(), info); err != nil {
+		t.Fatalf("update next run by cas failed, err: %v", err)
+	}
+
+	if err = bd.UpdateFlowScheduleNextRunByCAS(newKit(), info); err == nil {
+		t.Errorf("update next run by cas with stale source should be failed")
+	}
+
+	if schedule = getFlowSchedule(t, bd, id); schedule.NextRunAt != times.ConvStdTimeFormat(target) {
+		t.Errorf("next_run_at should be %s, but got %s", times.ConvStdTimeFormat(target), schedule.NextRunAt)
+	}
+
+	// 暂停时同样通过下次执行时间CAS
+	info = &backend.UpdateScheduleNextRunInfo{ID: id, Source: nextRunAt, Target: target, Pause: true}
+	if err = bd.UpdateFlowScheduleNextRunByCAS(newKit(), info); err == nil {
+		t.Errorf("pause flow schedule by cas with stale source should be failed")
+	}
+
+	info = &backend.UpdateScheduleNextRunInfo{ID: id, Source: target, Target: target, Pause: true}
+	if err = bd.UpdateFlowScheduleNextRunByCAS(newKit(), info); err != nil {
+		t.Fatalf("pause flow schedule by cas failed, err: %v", err)
+	}
+
+	if schedule = getFlowSchedule(t, bd, id); schedule.State != enumor.FlowSchedulePaused {
+		t.Errorf("flow schedule should be paused by cas, but got %s", schedule.State)
+	}
+
+	update := &model.FlowSchedule{ID: id, Cron: "*/10 * * * *"}
+	if err = bd.UpdateFlowSchedule(newKit(), update); err != nil {
+		t.Fatalf("update flow schedule failed, err: %v", err)
+	}
+
+	schedule = getFlowSchedule(t, bd, id)
+	if schedule.State != enumor.FlowSchedulePaused || schedule.Cron != "*/10 * * * *" {
+		t.Errorf("flow schedule not updated: %+v", schedule)
+	}
+
+	// 暂停的定时任务流不允许更新下次执行时间
+	info = &backend.UpdateScheduleNextRunInfo{ID: id, Source: target, Target: target.Add(5 * time.Minute)}
+	if err = bd.UpdateFlowScheduleNextRunByCAS(newKit(), info); err == nil {
+		t.Errorf("update paused flow schedule's next run should be failed")
+	}
+
+	if err = bd.DeleteFlowSchedule(newKit(), id); err != nil {
+		t.Fatalf("delete flow schedule failed, err: %v", err)
+	}
+
+	schedules, err := bd.ListFlowSchedule(newKit(), &backend.ListInput{
+		Filter: tools.EqualExpression("id", id),
+		Page:   core.NewDefaultBasePage(),
+	})
+	if err != nil {
+		t.Fatalf("list flow schedule failed, err: %v", err)
+	}
+
+	if len(schedules) != 0 {
+		t.Errorf("flow schedule should be deleted, but got %d", len(schedules))
+	}
+}
+
+func testFlowScheduleRun(t *testing.T, bd backend.Backend) {
+	name := uniqueMemo()
+	id, err := bd.CreateFlowSchedule(newKit(), newFlowSchedule(name, times.ConvStdTimeNow().Add(time.Minute)))
+	if err != nil {
+		t.Fatalf("create flow schedule failed, err: %v", err)
+	}
+
+	planAt := times.ConvStdTimeNow().Truncate(time.Second)
+	runs := []model.FlowScheduleRun{
+		{
+			ScheduleID: id,
+			PlanAt:     times.ConvStdTimeFormat(planAt.Add(-time.Minute)),
+			State:      enumor.FlowScheduleRunSkipped,
+			Reason:     &tableasync.Reason{Message: "skipped"},
+		},
+		{
+			ScheduleID: id,
+			PlanAt:     times.ConvStdTimeFormat(planAt),
+			State:      enumor.FlowScheduleRunTriggered,
+			FlowID:     "flow-1",
+		},
+	}
+	if err = bd.BatchCreateFlowScheduleRun(newKit(), runs); err != nil {
+		t.Fatalf("batch create flow schedule run failed, err: %v", err)
+	}
+
+	input := &backend.ListInput{
+		Filter: tools.EqualExpression("schedule_id", id),
+		Page:   core.NewDefaultBasePage(),
+	}
+	list, err := bd.ListFlowScheduleRun(newKit(), input)
+	if err != nil {
+		t.Fatalf("list flow schedule run failed, err: %v", err)
+	}
+
+	if len(list) != 2 {
+		t.Fatalf("flow schedule should have 2 runs, but got %d", len(list))
+	}
+
+	if list[0].State != enumor.FlowScheduleRunSkipped || list[0].Reason == nil ||
+		list[0].Reason.Message != "skipped" || list[0].PlanAt != runs[0].PlanAt {
+		t.Errorf("unexpected skipped run: %+v", list[0])
+	}
+
+	if list[1].State != enumor.FlowScheduleRunTriggered || list[1].FlowID != "flow-1" ||
+		list[1].PlanAt != runs[1].PlanAt || list[1].Creator != "conformance" {
+		t.Errorf("unexpected triggered run: %+v", list[1])
+	}
+
+	if err = bd.DeleteFlowSchedule(newKit(), id); err != nil {
+		t.Fatalf("delete flow schedule failed, err: %v", err)
+	}
+
+	if list, err = bd.ListFlowScheduleRun(newKit(), input); err != nil || len(list) != 0 {
+		t.Errorf("flow schedule runs should be deleted with schedule, got: %d, err: %v", len(list), err)
+	}
+}
+
+func listFlowEvents(t *testing.T, bd backend.Backend, flowID string) map[enumor.FlowEventType]model.FlowEvent {
+	events, err := bd.ListFlowEvent(newKit(), &backend.ListInput{
+		Filter: tools.EqualExpression("flow_id", flowID),
+		Page:   core.NewDefaultBasePage(),
+	})
+	if err != nil {
+		t.Fatalf("list flow event failed, err: %v", err)
+	}
+
+	result := make(map[enumor.FlowEventType]model.FlowEvent, len(events))
+	for _, one := range events {
+		if _, exist := result[one.Event]; exist {
+			t.Errorf("flow %s event %s should be generated once", flowID, one.Event)
+		}
+		result[one.Event] = one
+	}
+
+	return result
+}
+
+func testFlowEvent(t *testing.T, bd backend.Backend) {
+	flow := newFlow(uniqueMemo(), 1)
+	flow.Webhooks = tableasync.WebhookTargets{{URL: "http://127.0.0.1/callback",
+		Events: []enumor.FlowEventType{enumor.FlowEventFailed}}}
+	id := mustCreateFlow(t, bd, flow)
+
+	saved := getFlow(t, bd, id)
+	if saved.AppCode != newKit().AppCode || len(saved.Webhooks) != 1 || saved.Webhooks[0].URL != flow.Webhooks[0].URL {
+		t.Errorf("flow app_code or webhooks not saved: %+v", saved)
+	}
+
+	events := listFlowEvents(t, bd, id)
+	created, exist := events[enumor.FlowEventCreated]
+	if len(events) != 1 || !exist {
+		t.Fatalf("create flow should generate created event, but got %+v", events)
+	}
+
+	if created.State != enumor.FlowEventStatePending || created.Attempts != 0 || len(created.NextAttemptAt) == 0 {
+		t.Errorf("unexpected created event: %+v", created)
+	}
+
+	// 调度状态不产生事件，执行中、失败状态产生对应事件
+	infos := []backend.UpdateFlowInfo{{ID: id, Source: enumor.FlowPending, Target: enumor.FlowScheduled}}
+	if err := bd.BatchUpdateFlowStateByCAS(newKit(), infos); err != nil {
+		t.Fatalf("cas update flow failed, err: %v", err)
+	}
+
+	infos = []backend.UpdateFlowInfo{{ID: id, Source: enumor.FlowScheduled, Target: enumor.FlowRunning}}
+	if err := bd.BatchUpdateFlowStateByCAS(newKit(), infos); err != nil {
+		t.Fatalf("cas update flow failed, err: %v", err)
+	}
+
+	update := []model.Flow{{ID: id, State: enumor.FlowFailed, Reason: &tableasync.Reason{Message: "failed"}}}
+	if err := bd.BatchUpdateFlow(newKit(), update); err != nil {
+		t.Fatalf("update flow failed, err: %v", err)
+	}
+
+	events = listFlowEvents(t, bd, id)
+	if len(events) != 3 {
+		t.Fatalf("flow should have created, running and failed events, but got %+v", events)
+	}
+
+	for _, event := range []enumor.FlowEventType{enumor.FlowEventRunning, enumor.FlowEventFailed} {
+		if one, exist := events[event]; !exist || one.State != enumor.FlowEventStatePending {
+			t.Errorf("flow %s event should be pending, but got %+v", event, one)
+		}
+	}
+
+	nextAttemptAt := times.ConvStdTimeFormat(time.Now().Add(time.Minute))
+	retry := &model.FlowEvent{
+		ID:            created.ID,
+		Attempts:      1,
+		NextAttemptAt: nextAttemptAt,
+		Deliveries: tableasync.WebhookDeliveries{{URL: "http://127.0.0.1/callback", Attempt: 1, StatusCode: 500,
+			Message: "internal error", DeliveredAt: times.ConvStdTimeFormat(time.Now())}},
+	}
+	if err := bd.UpdateFlowEvent(newKit(), retry); err != nil {
+		t.Fatalf("update flow event failed, err: %v", err)
+	}
+
+	created = listFlowEvents(t, bd, id)[enumor.FlowEventCreated]
+	if created.State != enumor.FlowEventStatePending || created.Attempts != 1 ||
+		created.NextAttemptAt != nextAttemptAt || len(created.Deliveries) != 1 ||
+		created.Deliveries[0].StatusCode != 500 {
+		t.Errorf("flow event not updated: %+v", created)
+	}
+
+	delivered := &model.FlowEvent{ID: created.ID, State: enumor.FlowEventStateDelivered, Attempts: 2}
+	if err := bd.UpdateFlowEvent(newKit(), delivered); err != nil {
+		t.Fatalf("update flow event failed, err: %v", err)
+	}
+
+	created = listFlowEvents(t, bd, id)[enumor.FlowEventCreated]
+	if created.State != enumor.FlowEventStateDelivered || created.Attempts != 2 || len(created.Deliveries) != 1 {
+		t.Errorf("flow event state not updated: %+v", created)
+	}
+
+	if err := bd.UpdateFlowEvent(newKit(), &model.FlowEvent{ID: "not-exist-event", Attempts: 1}); err == nil {
+		t.Errorf("update not exist flow event should be failed")
+	}
+}
+
+func testFlowArchive(t *testing.T, bd backend.Backend) {
+	finishedID := mustCreateFlow(t, bd, newFlow(uniqueMemo(), 2))
+	runningID := mustCreateFlow(t, bd, newFlow(uniqueMemo(), 1))
+
+	update := []model.Flow{
+		{ID: finishedID, State: enumor.FlowSuccess},
+		{ID: runningID, State: enumor.FlowRunning},
+	}
+	if err := bd.BatchUpdateFlow(newKit(), update); err != nil {
+		t.Fatalf("update flow failed, err: %v", err)
+	}
+
+	finished := getFlow(t, bd, finishedID)
+	eventNum := len(listFlowEvents(t, bd, finishedID))
+
+	// 未结束的任务流不会被归档
+	if err := bd.ArchiveFlow(newKit(), []string{finishedID, runningID}); err != nil {
+		t.Fatalf("archive flow failed, err: %v", err)
+	}
+
+	flows, err := bd.ListFlow(newKit(), &backend.ListInput{
+		Filter: tools.EqualExpression("id", finishedID),
+		Page:   core.NewDefaultBasePage(),
+	})
+	if err != nil {
+		t.Fatalf("list flow failed, err: %v", err)
+	}
+
+	if len(flows) != 0 || len(listFlowTasks(t, bd, finishedID)) != 0 || len(listFlowEvents(t, bd, finishedID)) != 0 {
+		t.Errorf("archived flow, tasks and events should be deleted")
+	}
+
+	if running := getFlow(t, bd, runningID); running.State != enumor.FlowRunning ||
+		len(listFlowTasks(t, bd, runningID)) != 1 {
+		t.Errorf("running flow should not be archived: %+v", running)
+	}
+
+	archives, err := bd.ListFlowArchive(newKit(), &backend.ListInput{
+		Filter: tools.ContainersExpression("id", []string{finishedID, runningID}),
+		Page:   core.NewDefaultBasePage(),
+	})
+	if err != nil {
+		t.Fatalf("list flow archive failed, err: %v", err)
+	}
+
+	if len(archives) != 1 {
+		t.Fatalf("only finished flow should be archived, but got %+v", archives)
+	}
+
+	archive := archives[0]
+	if archive.ID != finishedID || archive.Name != finished.Name || archive.State != enumor.FlowSuccess ||
+		archive.AppCode != newKit().AppCode || len(archive.ArchivedAt) == 0 || len(archive.FinishedAt) == 0 {
+		t.Errorf("unexpected flow archive: %+v", archive)
+	}
+
+	if archive.Flow == nil || archive.Flow.ID != finishedID || archive.Flow.ShareData == nil ||
+		archive.Flow.ShareData.Dict["key"] != "value" ||
+		len(archive.Flow.Tasks) != 2 || len(archive.Events) != eventNum {
+		t.Fatalf("flow archive should keep flow, tasks and events: %+v", archive)
+	}
+
+	for _, task := range archive.Flow.Tasks {
+		if task.FlowID != finishedID {
+			t.Errorf("archived task should belong to flow %s, but got %+v", finishedID, task)
+		}
+	}
+
+	// 归档后再次归档同一任务流不会报错
+	if err = bd.ArchiveFlow(newKit(), []string{finishedID}); err != nil {
+		t.Errorf("archive flow again failed, err: %v", err)
+	}
+
+	ids := make([]string, 101)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("flow-%d", i)
+	}
+	if err = bd.ArchiveFlow(newKit(), ids); err == nil {
+		t.Errorf("archive flow with more than 100 ids should be failed")
+	}
+}
+
+func listFlowsByMemo(t *testing.T, bd backend.Backend, memo string) []model.Flow {
+	flows, err := bd.ListFlow(newKit(), &backend.ListInput{
+		Filter: tools.EqualExpression("memo", memo),
+		Page:   core.NewDefaultBasePage(),
+	})
+	if err != nil {
+		t.Fatalf("list flow failed, err: %v", err)
+	}
+
+	return flows
+}
+
+func testFlowIdempotency(t *testing.T, bd backend.Backend) {
+	memo := uniqueMemo()
+	appCode := "conformance"
+	expiredAt := times.ConvStdTimeFormat(time.Now().Add(time.Hour))
+	idem := &model.FlowIdempotency{AppCode: appCode, IdempotencyKey: memo, PayloadHash: "hash-a",
+		ExpiredAt: expiredAt}
+
+	id, err := bd.CreateFlowIdempotently(newKit(), newFlow(memo, 2), idem)
+	if err != nil {
+		t.Fatalf("create flow idempotently failed, err: %v", err)
+	}
+
+	// 相同幂等键、相同内容摘要的重复请求返回首次创建的任务流，不会重复创建
+	duplicateID, err := bd.CreateFlowIdempotently(newKit(), newFlow(memo, 2), idem)
+	if err != nil {
+		t.Fatalf("create duplicate flow idempotently failed, err: %v", err)
+	}
+
+	if duplicateID != id {
+		t.Errorf("duplicate request should return flow %s, but got %s", id, duplicateID)
+	}
+
+	if flows := listFlowsByMemo(t, bd, memo); len(flows) != 1 || len(listFlowTasks(t, bd, id)) != 2 {
+		t.Errorf("flow should be created once with 2 tasks, but got %d flows", len(flows))
+	}
+
+	// 相同幂等键、不同内容摘要的请求被拒绝
+	conflict := *idem
+	conflict.PayloadHash = "hash-b"
+	_, err = bd.CreateFlowIdempotently(newKit(), newFlow(memo, 1), &conflict)
+	if err == nil || errf.Error(err).Code != errf.IdempotencyKeyConflict {
+		t.Errorf("conflicting payload should be rejected with idempotency key conflict, but got err: %v", err)
+	}
+
+	// 幂等键按照应用隔离
+	otherApp := *idem
+	otherApp.AppCode = "other-app"
+	otherID, err := bd.CreateFlowIdempotently(newKit(), newFlow(memo, 1), &otherApp)
+	if err != nil {
+		t.Fatalf("create flow with same key of other app failed, err: %v", err)
+	}
+
+	if otherID == id || len(listFlowsByMemo(t, bd, memo)) != 2 {
+		t.Errorf("same key of other app should create new flow, but got %s", otherID)
+	}
+
+	// 过期的幂等键可以被重新使用
+	expiredMemo := uniqueMemo()
+	expired := &model.FlowIdempotency{AppCode: appCode, IdempotencyKey: expiredMemo, PayloadHash: "hash-a",
+		ExpiredAt: times.ConvStdTimeFormat(time.Now().Add(-time.Hour))}
+	expiredID, err := bd.CreateFlowIdempotently(newKit(), newFlow(expiredMemo, 1), expired)
+	if err != nil {
+		t.Fatalf("create flow with expired key failed, err: %v", err)
+	}
+
+	if err = bd.DeleteExpiredFlowIdempotency(newKit()); err != nil {
+		t.Fatalf("delete expired flow idempotency failed, err: %v", err)
+	}
+
+	reused := *expired
+	reused.PayloadHash = "hash-b"
+	reused.ExpiredAt = expiredAt
+	reusedID, err := bd.CreateFlowIdempotently(newKit(), newFlow(expiredMemo, 1), &reused)
+	if err != nil {
+		t.Fatalf("reuse expired key failed, err: %v", err)
+	}
+
+	if reusedID == expiredID || len(listFlowsByMemo(t, bd, expiredMemo)) != 2 {
+		t.Errorf("expired key should be reused to create new flow, but got %s", reusedID)
+	}
+
+	// 未过期的幂等键不会被清理
+	if err = bd.DeleteExpiredFlowIdempotency(newKit()); err != nil {
+		t.Fatalf("delete expired flow idempotency failed, err: %v", err)
+	}
+
+	if duplicateID, err = bd.CreateFlowIdempotently(newKit(), newFlow(memo, 2), idem); err != nil ||
+		duplicateID != id {
+		t.Errorf("unexpired key should be kept, but got flow %s, err: %v", duplicateID, err)
+	}
+
+	tooLong := *idem
+	tooLong.IdempotencyKey = strings.Repeat("k", 129)
+	if _, err = bd.CreateFlowIdempotently(newKit(), newFlow(memo, 1), &tooLong); err == nil {
+		t.Errorf("idempotency key longer than 128 should be rejected")
+	}
+}
@@ -23,6 +23,8 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/jmoiron/sqlx"
+
 	"hcm/pkg/criteria/enumor"
 	"hcm/pkg/dal/dao"
 )
@@ -36,7 +38,15 @@ func Factory(typ enumor.BackendType, client interface{}) (Backend, error) {
 			return nil, errors.New("client is not mysql dao set")
 		}
 		return NewMysql(cli), nil
+	case enumor.BackendMemory:
+		return NewMemory(), nil
+	case enumor.BackendSqlite:
+		db, ok := client.(*sqlx.DB)
+		if !ok {
+			return nil, errors.New("client is not sqlite db")
+		}
+		return NewSqlite(db)
 	default:
-		return nil, fmt.Errorf("unsupported mysql type: %s", typ)
+		return nil, fmt.Errorf("unsupported backend type: %s", typ)
 	}
 }
@@ -0,0 +1,713 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package backend
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"hcm/pkg/api/core"
+	"hcm/pkg/async/action"
+	"hcm/pkg/async/backend/model"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/types"
+	tableasync "hcm/pkg/dal/table/async"
+	tabletypes "hcm/pkg/dal/table/types"
+	"hcm/pkg/dal/table/utils"
+	"hcm/pkg/kit"
+	"hcm/pkg/runtime/filter"
+	"hcm/pkg/tools/converter"
+	"hcm/pkg/tools/times"
+)
+
+// validateListInput 与 dao 层 List 保持一致的查询参数校验
+func validateListInput(input *ListInput, columns *utils.Columns) error {
+	if input == nil {
+		return fmt.Errorf("list input is nil")
+	}
+
+	opt := types.ListOption{
+		Fields: input.Fields,
+		Filter: input.Filter,
+		Page:   input.Page,
+	}
+	return opt.Validate(filter.NewExprOption(filter.RuleFields(columns.ColumnTypes())), core.NewDefaultPageOption())
+}
+
+// newFlowTable 创建任务流时，生成待落库的任务流，未设置执行时间的任务流立即执行
+func newFlowTable(kt *kit.Kit, flow *model.Flow) (*tableasync.AsyncFlowTable, error) {
+	runAt := times.ConvStdTimeNow()
+	if len(flow.RunAt) != 0 {
+		var err error
+		if runAt, err = parseTime(flow.RunAt); err != nil {
+			return nil, errf.Newf(errf.InvalidParameter, "run_at is invalid, err: %v", err)
+		}
+	}
+
+	policy := flow.RollbackPolicy
+	if len(policy) == 0 {
+		policy = enumor.FlowRollbackNone
+	}
+
+	priority := flow.Priority
+	if len(priority) == 0 {
+		priority = enumor.FlowPriorityNormal
+	}
+
+	tenant := flow.Tenant
+	if len(tenant) == 0 {
+		tenant = kt.AppCode
+	}
+
+	appCode := flow.AppCode
+	if len(appCode) == 0 {
+		appCode = kt.AppCode
+	}
+
+	return &tableasync.AsyncFlowTable{
+		Name:           flow.Name,
+		State:          enumor.FlowPending,
+		Reason:         new(tableasync.Reason),
+		ShareData:      flow.ShareData,
+		Memo:           flow.Memo,
+		Worker:         converter.ValToPtr(""),
+		RunAt:          runAt.Truncate(time.Second),
+		RollbackPolicy: policy,
+		Priority:       priority,
+		Tenant:         tenant,
+		AppCode:        appCode,
+		Webhooks:       flow.Webhooks,
+		Creator:        kt.User,
+		Reviser:        kt.User,
+	}, nil
+}
+
+// parseTime 解析 constant.TimeStdFormat 格式的时间
+func parseTime(t string) (time.Time, error) {
+	return time.ParseInLocation(constant.TimeStdFormat, t, time.Local)
+}
+
+// newFlowTaskTables 创建任务流时，生成待落库的任务
+func newFlowTaskTables(kt *kit.Kit, flowID string, tasks []model.Task) []tableasync.AsyncFlowTaskTable {
+	now := time.Now().Truncate(time.Second)
+	mds := make([]tableasync.AsyncFlowTaskTable, 0, len(tasks))
+	for _, one := range tasks {
+		mds = append(mds, tableasync.AsyncFlowTaskTable{
+			FlowID:      flowID,
+			FlowName:    one.FlowName,
+			ActionID:    string(one.ActionID),
+			ActionName:  one.ActionName,
+			Params:      one.Params,
+			Retry:       one.Retry,
+			DependOn:    dependOnToStringArray(one.DependOn),
+			State:       enumor.TaskPending,
+			Reason:      new(tableasync.Reason),
+			Stat:        new(tableasync.TaskStat),
+			Progress:    new(tableasync.TaskProgress),
+			HeartbeatAt: now,
+			Creator:     kt.User,
+			Reviser:     kt.User,
+		})
+	}
+
+	return mds
+}
+
+// newTaskTables 批量创建任务时，生成待落库的任务
+func newTaskTables(tasks []model.Task) []tableasync.AsyncFlowTaskTable {
+	now := time.Now().Truncate(time.Second)
+	mds := make([]tableasync.AsyncFlowTaskTable, 0, len(tasks))
+	for _, one := range tasks {
+		mds = append(mds, tableasync.AsyncFlowTaskTable{
+			FlowID:      one.FlowID,
+			FlowName:    one.FlowName,
+			ActionID:    string(one.ActionID),
+			ActionName:  one.ActionName,
+			Params:      one.Params,
+			Retry:       one.Retry,
+			DependOn:    dependOnToStringArray(one.DependOn),
+			State:       enumor.TaskPending,
+			Reason:      one.Reason,
+			Stat:        new(tableasync.TaskStat),
+			Progress:    new(tableasync.TaskProgress),
+			HeartbeatAt: now,
+			Creator:     one.Creator,
+			Reviser:     one.Reviser,
+		})
+	}
+
+	return mds
+}
+
+// flowUpdateTable 生成任务流的更新字段
+func flowUpdateTable(flow model.Flow) *tableasync.AsyncFlowTable {
+	return &tableasync.AsyncFlowTable{
+		State:     flow.State,
+		Reason:    flow.Reason,
+		ShareData: flow.ShareData,
+		Memo:      flow.Memo,
+		Worker:    flow.Worker,
+		Reviser:   flow.Reviser,
+	}
+}
+
+// taskUpdateTable 生成任务的更新字段
+func taskUpdateTable(kt *kit.Kit, task *model.Task) (*tableasync.AsyncFlowTaskTable, error) {
+	md := &tableasync.AsyncFlowTaskTable{
+		Retry:    task.Retry,
+		DependOn: dependOnToStringArray(task.DependOn),
+		State:    task.State,
+		Result:   task.Result,
+		Reason:   task.Reason,
+		Stat:     task.Stat,
+		Progress: task.Progress,
+		Reviser:  kt.User,
+	}
+
+	if len(task.HeartbeatAt) != 0 {
+		heartbeatAt, err := parseTime(task.HeartbeatAt)
+		if err != nil {
+			return nil, errf.Newf(errf.InvalidParameter, "heartbeat_at is invalid, err: %v", err)
+		}
+		md.HeartbeatAt = heartbeatAt.Truncate(time.Second)
+	}
+
+	return md, nil
+}
+
+// mergeFlowUpdate 将更新字段合并到任务流中，与 dao 层更新语义一致：只更新非空字段，worker 允许更新为空值。
+func mergeFlowUpdate(dst *tableasync.AsyncFlowTable, md *tableasync.AsyncFlowTable) {
+	if len(md.State) != 0 {
+		dst.State = md.State
+	}
+
+	if md.Reason != nil {
+		dst.Reason = md.Reason
+	}
+
+	if md.ShareData != nil {
+		dst.ShareData = md.ShareData
+	}
+
+	if len(md.Memo) != 0 {
+		dst.Memo = md.Memo
+	}
+
+	if md.Worker != nil {
+		dst.Worker = md.Worker
+	}
+
+	if len(md.Reviser) != 0 {
+		dst.Reviser = md.Reviser
+	}
+}
+
+// mergeTaskUpdate 将更新字段合并到任务中，与 dao 层更新语义一致：只更新非空字段。
+func mergeTaskUpdate(dst *tableasync.AsyncFlowTaskTable, md *tableasync.AsyncFlowTaskTable) {
+	if md.Retry != nil {
+		dst.Retry = md.Retry
+	}
+
+	if len(md.DependOn) != 0 {
+		dst.DependOn = md.DependOn
+	}
+
+	if len(md.State) != 0 {
+		dst.State = md.State
+	}
+
+	if len(md.Result) != 0 {
+		dst.Result = md.Result
+	}
+
+	if md.Reason != nil {
+		dst.Reason = md.Reason
+	}
+
+	if md.Stat != nil {
+		dst.Stat = md.Stat
+	}
+
+	if md.Progress != nil {
+		dst.Progress = md.Progress
+	}
+
+	if !md.HeartbeatAt.IsZero() {
+		dst.HeartbeatAt = md.HeartbeatAt
+	}
+
+	if len(md.Reviser) != 0 {
+		dst.Reviser = md.Reviser
+	}
+}
+
+func flowTableToModel(one tableasync.AsyncFlowTable) model.Flow {
+	return model.Flow{
+		ID:             one.ID,
+		Name:           one.Name,
+		State:          one.State,
+		Reason:         one.Reason,
+		ShareData:      one.ShareData,
+		Memo:           one.Memo,
+		Worker:         one.Worker,
+		RunAt:          times.ConvStdTimeFormat(one.RunAt),
+		RollbackPolicy: one.RollbackPolicy,
+		Priority:       one.Priority,
+		Tenant:         one.Tenant,
+		AppCode:        one.AppCode,
+		Webhooks:       one.Webhooks,
+		Creator:        one.Creator,
+		Reviser:        one.Reviser,
+		CreatedAt:      one.CreatedAt.String(),
+		UpdatedAt:      one.UpdatedAt.String(),
+	}
+}
+
+func taskTableToModel(one tableasync.AsyncFlowTaskTable) model.Task {
+	return model.Task{
+		ID:          one.ID,
+		FlowID:      one.FlowID,
+		FlowName:    one.FlowName,
+		ActionID:    action.ActIDType(one.ActionID),
+		ActionName:  one.ActionName,
+		Params:      one.Params,
+		Retry:       one.Retry,
+		DependOn:    dependOnToActIDArray(one.DependOn),
+		State:       one.State,
+		Reason:      one.Reason,
+		Result:      one.Result,
+		Stat:        one.Stat,
+		Progress:    one.Progress,
+		HeartbeatAt: times.ConvStdTimeFormat(one.HeartbeatAt),
+		Creator:     one.Creator,
+		Reviser:     one.Reviser,
+		CreatedAt:   one.CreatedAt.String(),
+		UpdatedAt:   one.UpdatedAt.String(),
+	}
+}
+
+// newFlowScheduleTable 创建定时任务流时，生成待落库的定时任务流
+func newFlowScheduleTable(kt *kit.Kit, schedule *model.FlowSchedule) (*tableasync.AsyncFlowScheduleTable, error) {
+	nextRunAt, err := parseTime(schedule.NextRunAt)
+	if err != nil {
+		return nil, errf.Newf(errf.InvalidParameter, "next_run_at is invalid, err: %v", err)
+	}
+
+	return &tableasync.AsyncFlowScheduleTable{
+		Name:         schedule.Name,
+		Cron:         schedule.Cron,
+		State:        schedule.State,
+		MissedPolicy: schedule.MissedPolicy,
+		Flow:         schedule.Flow,
+		NextRunAt:    nextRunAt.Truncate(time.Second),
+		Memo:         schedule.Memo,
+		Creator:      kt.User,
+		Reviser:      kt.User,
+	}, nil
+}
+
+// flowScheduleUpdateTable 生成定时任务流的更新字段
+func flowScheduleUpdateTable(kt *kit.Kit, schedule *model.FlowSchedule) (*tableasync.AsyncFlowScheduleTable, error) {
+	md := &tableasync.AsyncFlowScheduleTable{
+		Cron:         schedule.Cron,
+		State:        schedule.State,
+		MissedPolicy: schedule.MissedPolicy,
+		Memo:         schedule.Memo,
+		Reviser:      kt.User,
+	}
+
+	if len(schedule.NextRunAt) != 0 {
+		nextRunAt, err := parseTime(schedule.NextRunAt)
+		if err != nil {
+			return nil, errf.Newf(errf.InvalidParameter, "next_run_at is invalid, err: %v", err)
+		}
+		md.NextRunAt = nextRunAt.Truncate(time.Second)
+	}
+
+	return md, nil
+}
+
+// mergeFlowScheduleUpdate 将更新字段合并到定时任务流中，与 dao 层更新语义一致：只更新非空字段。
+func mergeFlowScheduleUpdate(dst *tableasync.AsyncFlowScheduleTable, md *tableasync.AsyncFlowScheduleTable) {
+	if len(md.Cron) != 0 {
+		dst.Cron = md.Cron
+	}
+
+	if len(md.State) != 0 {
+		dst.State = md.State
+	}
+
+	if len(md.MissedPolicy) != 0 {
+		dst.MissedPolicy = md.MissedPolicy
+	}
+
+	if !md.NextRunAt.IsZero() {
+		dst.NextRunAt = md.NextRunAt
+	}
+
+	if len(md.Memo) != 0 {
+		dst.Memo = md.Memo
+	}
+
+	if len(md.Reviser) != 0 {
+		dst.Reviser = md.Reviser
+	}
+}
+
+// newFlowScheduleRunTables 生成待落库的定时任务流执行记录
+func newFlowScheduleRunTables(kt *kit.Kit, runs []model.FlowScheduleRun) (
+	[]tableasync.AsyncFlowScheduleRunTable, error) {
+
+	mds := make([]tableasync.AsyncFlowScheduleRunTable, 0, len(runs))
+	for _, one := range runs {
+		planAt, err := parseTime(one.PlanAt)
+		if err != nil {
+			return nil, errf.Newf(errf.InvalidParameter, "plan_at is invalid, err: %v", err)
+		}
+
+		reason := one.Reason
+		if reason == nil {
+			reason = new(tableasync.Reason)
+		}
+
+		mds = append(mds, tableasync.AsyncFlowScheduleRunTable{
+			ScheduleID: one.ScheduleID,
+			PlanAt:     planAt.Truncate(time.Second),
+			State:      one.State,
+			FlowID:     one.FlowID,
+			Reason:     reason,
+			Creator:    kt.User,
+		})
+	}
+
+	return mds, nil
+}
+
+func flowScheduleTableToModel(one tableasync.AsyncFlowScheduleTable) model.FlowSchedule {
+	return model.FlowSchedule{
+		ID:           one.ID,
+		Name:         one.Name,
+		Cron:         one.Cron,
+		State:        one.State,
+		MissedPolicy: one.MissedPolicy,
+		Flow:         one.Flow,
+		NextRunAt:    times.ConvStdTimeFormat(one.NextRunAt),
+		Memo:         one.Memo,
+		Creator:      one.Creator,
+		Reviser:      one.Reviser,
+		CreatedAt:    one.CreatedAt.String(),
+		UpdatedAt:    one.UpdatedAt.String(),
+	}
+}
+
+func flowScheduleRunTableToModel(one tableasync.AsyncFlowScheduleRunTable) model.FlowScheduleRun {
+	return model.FlowScheduleRun{
+		ID:         one.ID,
+		ScheduleID: one.ScheduleID,
+		PlanAt:     times.ConvStdTimeFormat(one.PlanAt),
+		State:      one.State,
+		FlowID:     one.FlowID,
+		Reason:     one.Reason,
+		Creator:    one.Creator,
+		CreatedAt:  one.CreatedAt.String(),
+	}
+}
+
+// flowStateEvents 任务流切换到这些状态时，生成对应的任务流事件
+var flowStateEvents = map[enumor.FlowState]enumor.FlowEventType{
+	enumor.FlowRunning:    enumor.FlowEventRunning,
+	enumor.FlowSuccess:    enumor.FlowEventSuccess,
+	enumor.FlowFailed:     enumor.FlowEventFailed,
+	enumor.FlowCancel:     enumor.FlowEventCancelled,
+	enumor.FlowRolledBack: enumor.FlowEventRolledBack,
+}
+
+// newFlowEventTable 生成待落库的任务流事件，事件生成后立即等待投递
+func newFlowEventTable(flowID string, event enumor.FlowEventType) tableasync.AsyncFlowEventTable {
+	return tableasync.AsyncFlowEventTable{
+		FlowID:        flowID,
+		Event:         event,
+		State:         enumor.FlowEventStatePending,
+		NextAttemptAt: time.Now().Truncate(time.Second),
+		Deliveries:    make(tableasync.WebhookDeliveries, 0),
+	}
+}
+
+// flowStateEventTables 根据任务流状态变更生成任务流事件，ids 与 states 一一对应，states 为变更后的状态
+func flowStateEventTables(ids []string, states []enumor.FlowState) []tableasync.AsyncFlowEventTable {
+	mds := make([]tableasync.AsyncFlowEventTable, 0, len(ids))
+	for index, id := range ids {
+		event, exist := flowStateEvents[states[index]]
+		if !exist {
+			continue
+		}
+		mds = append(mds, newFlowEventTable(id, event))
+	}
+
+	return mds
+}
+
+// flowEventUpdateTable 生成任务流事件的更新字段
+func flowEventUpdateTable(event *model.FlowEvent) (*tableasync.AsyncFlowEventTable, error) {
+	md := &tableasync.AsyncFlowEventTable{
+		State:      event.State,
+		Attempts:   event.Attempts,
+		Deliveries: event.Deliveries,
+	}
+
+	if len(event.NextAttemptAt) != 0 {
+		nextAttemptAt, err := parseTime(event.NextAttemptAt)
+		if err != nil {
+			return nil, errf.Newf(errf.InvalidParameter, "next_attempt_at is invalid, err: %v", err)
+		}
+		md.NextAttemptAt = nextAttemptAt.Truncate(time.Second)
+	}
+
+	return md, nil
+}
+
+// mergeFlowEventUpdate 将更新字段合并到任务流事件中，与 dao 层更新语义一致：只更新非空字段。
+func mergeFlowEventUpdate(dst *tableasync.AsyncFlowEventTable, md *tableasync.AsyncFlowEventTable) {
+	if len(md.State) != 0 {
+		dst.State = md.State
+	}
+
+	if md.Attempts != 0 {
+		dst.Attempts = md.Attempts
+	}
+
+	if !md.NextAttemptAt.IsZero() {
+		dst.NextAttemptAt = md.NextAttemptAt
+	}
+
+	if len(md.Deliveries) != 0 {
+		dst.Deliveries = md.Deliveries
+	}
+}
+
+func flowEventTableToModel(one tableasync.AsyncFlowEventTable) model.FlowEvent {
+	return model.FlowEvent{
+		ID:            one.ID,
+		FlowID:        one.FlowID,
+		Event:         one.Event,
+		State:         one.State,
+		Attempts:      one.Attempts,
+		NextAttemptAt: times.ConvStdTimeFormat(one.NextAttemptAt),
+		Deliveries:    one.Deliveries,
+		CreatedAt:     one.CreatedAt.String(),
+		UpdatedAt:     one.UpdatedAt.String(),
+	}
+}
+
+// validateArchiveIDs 校验待归档的任务流ID
+func validateArchiveIDs(ids []string) error {
+	if len(ids) > maxArchiveFlowNum {
+		return errf.Newf(errf.InvalidParameter, "archive flow ids should <= %d", maxArchiveFlowNum)
+	}
+
+	return nil
+}
+
+// archivableFlowExpr 查询可以归档的任务流的过滤条件
+func archivableFlowExpr(ids []string) *filter.Expression {
+	return &filter.Expression{
+		Op: filter.And,
+		Rules: []filter.RuleFactory{
+			&filter.AtomRule{Field: "id", Op: filter.In.Factory(), Value: ids},
+			&filter.AtomRule{Field: "state", Op: filter.In.Factory(), Value: ArchivableFlowStates},
+		},
+	}
+}
+
+// newFlowArchiveTables 生成任务流的归档记录，任务、事件按照任务流ID归入对应任务流的归档记录中
+func newFlowArchiveTables(flows []tableasync.AsyncFlowTable, tasks []tableasync.AsyncFlowTaskTable,
+	events []tableasync.AsyncFlowEventTable) ([]tableasync.AsyncFlowArchiveTable, error) {
+
+	details := make(map[string]*tableasync.FlowArchiveDetail, len(flows))
+	for _, one := range flows {
+		details[one.ID] = &tableasync.FlowArchiveDetail{
+			Flow:   one,
+			Tasks:  make([]tableasync.AsyncFlowTaskTable, 0),
+			Events: make([]tableasync.AsyncFlowEventTable, 0),
+		}
+	}
+
+	for _, one := range tasks {
+		if detail, exist := details[one.FlowID]; exist {
+			detail.Tasks = append(detail.Tasks, one)
+		}
+	}
+
+	for _, one := range events {
+		if detail, exist := details[one.FlowID]; exist {
+			detail.Events = append(detail.Events, one)
+		}
+	}
+
+	now := time.Now().Truncate(time.Second)
+	mds := make([]tableasync.AsyncFlowArchiveTable, 0, len(flows))
+	for _, one := range flows {
+		createdAt, err := parseTime(string(one.CreatedAt))
+		if err != nil {
+			return nil, fmt.Errorf("parse flow: %s created_at failed, err: %v", one.ID, err)
+		}
+
+		finishedAt, err := parseTime(string(one.UpdatedAt))
+		if err != nil {
+			return nil, fmt.Errorf("parse flow: %s updated_at failed, err: %v", one.ID, err)
+		}
+
+		mds = append(mds, tableasync.AsyncFlowArchiveTable{
+			ID:            one.ID,
+			Name:          one.Name,
+			State:         one.State,
+			Tenant:        one.Tenant,
+			AppCode:       one.AppCode,
+			Detail:        details[one.ID],
+			FlowCreatedAt: createdAt,
+			FinishedAt:    finishedAt,
+			ArchivedAt:    now,
+		})
+	}
+
+	return mds, nil
+}
+
+func flowArchiveTableToModel(one tableasync.AsyncFlowArchiveTable) model.FlowArchive {
+	archive := model.FlowArchive{
+		ID:            one.ID,
+		Name:          one.Name,
+		State:         one.State,
+		Tenant:        one.Tenant,
+		AppCode:       one.AppCode,
+		FlowCreatedAt: times.ConvStdTimeFormat(one.FlowCreatedAt),
+		FinishedAt:    times.ConvStdTimeFormat(one.FinishedAt),
+		ArchivedAt:    times.ConvStdTimeFormat(one.ArchivedAt),
+	}
+
+	if one.Detail == nil {
+		return archive
+	}
+
+	flow := flowTableToModel(one.Detail.Flow)
+	flow.Tasks = make([]model.Task, 0, len(one.Detail.Tasks))
+	for _, task := range one.Detail.Tasks {
+		flow.Tasks = append(flow.Tasks, taskTableToModel(task))
+	}
+	archive.Flow = &flow
+
+	archive.Events = make([]model.FlowEvent, 0, len(one.Detail.Events))
+	for _, event := range one.Detail.Events {
+		archive.Events = append(archive.Events, flowEventTableToModel(event))
+	}
+
+	return archive
+}
+
+// maxIdempotencyKeyLength 幂等键最大长度，与 async_flow_idempotency 表的 idempotency_key 字段长度保持一致
+const maxIdempotencyKeyLength = 128
+
+// newFlowIdempotencyTable 校验任务流幂等键，并生成与任务流在同一事务中落库的幂等键记录，ID 为任务流ID
+func newFlowIdempotencyTable(idem *model.FlowIdempotency) (*tableasync.AsyncFlowIdempotencyTable, error) {
+	if idem == nil {
+		return nil, errf.New(errf.InvalidParameter, "flow idempotency is required")
+	}
+
+	if len(idem.AppCode) == 0 || len(idem.IdempotencyKey) == 0 || len(idem.PayloadHash) == 0 {
+		return nil, errf.New(errf.InvalidParameter, "app_code, idempotency_key and payload_hash are required")
+	}
+
+	if len(idem.IdempotencyKey) > maxIdempotencyKeyLength {
+		return nil, errf.Newf(errf.InvalidParameter, "idempotency key length should <= %d", maxIdempotencyKeyLength)
+	}
+
+	expiredAt, err := parseTime(idem.ExpiredAt)
+	if err != nil {
+		return nil, errf.Newf(errf.InvalidParameter, "expired_at should be %s format, err: %v",
+			constant.TimeStdFormat, err)
+	}
+
+	return &tableasync.AsyncFlowIdempotencyTable{
+		AppCode:        idem.AppCode,
+		IdempotencyKey: idem.IdempotencyKey,
+		PayloadHash:    idem.PayloadHash,
+		ExpiredAt:      expiredAt,
+	}, nil
+}
+
+// flowIdempotencyExpr 查询应用下指定幂等键的过滤条件
+func flowIdempotencyExpr(appCode, key string) *filter.Expression {
+	return &filter.Expression{
+		Op: filter.And,
+		Rules: []filter.RuleFactory{
+			&filter.AtomRule{Field: "app_code", Op: filter.Equal.Factory(), Value: appCode},
+			&filter.AtomRule{Field: "idempotency_key", Op: filter.Equal.Factory(), Value: key},
+		},
+	}
+}
+
+// expiredFlowIdempotencyExpr 查询已过期幂等键的过滤条件，expr 不为空时只查询其中已过期的幂等键
+func expiredFlowIdempotencyExpr(expr *filter.Expression) *filter.Expression {
+	expired := &filter.AtomRule{Field: "expired_at", Op: filter.LessThanEqual.Factory(),
+		Value: times.ConvStdTimeFormat(time.Now())}
+	if expr == nil {
+		return &filter.Expression{Op: filter.And, Rules: []filter.RuleFactory{expired}}
+	}
+
+	rules := make([]filter.RuleFactory, 0, len(expr.Rules)+1)
+	rules = append(rules, expr.Rules...)
+	return &filter.Expression{Op: filter.And, Rules: append(rules, expired)}
+}
+
+// idempotentFlowID 幂等键已存在且未过期时，请求内容摘要相同返回首次创建的任务流ID，不同则拒绝创建
+func idempotentFlowID(exist tableasync.AsyncFlowIdempotencyTable, hash string) (string, error) {
+	if exist.PayloadHash != hash {
+		return "", errf.Newf(errf.IdempotencyKeyConflict, "idempotency key: %s is already used by flow: %s "+
+			"with different payload", exist.IdempotencyKey, exist.ID)
+	}
+
+	return exist.ID, nil
+}
+
+// formatID 与 id_generator 生成的 id 格式保持一致
+func formatID(id uint64) string {
+	return fmt.Sprintf("%08s", strconv.FormatUint(id, 36))
+}
+
+func dependOnToStringArray(d []action.ActIDType) tabletypes.StringArray {
+	result := make(tabletypes.StringArray, 0, len(d))
+	for _, one := range d {
+		result = append(result, string(one))
+	}
+
+	return result
+}
+
+func dependOnToActIDArray(d tabletypes.StringArray) []action.ActIDType {
+	result := make([]action.ActIDType, 0, len(d))
+	for _, one := range d {
+		result = append(result, action.ActIDType(one))
+	}
+
+	return result
+}
@@ -0,0 +1,317 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package backend
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"hcm/pkg/api/core"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/runtime/filter"
+)
+
+// record 以表字段名为 key 的记录值，用于非 sql 类后端在内存中执行过滤、排序。
+type record map[string]interface{}
+
+// matchExpression 判断记录是否满足过滤表达式，语义与 filter.Expression 生成的 sql 条件保持一致。
+func matchExpression(expr *filter.Expression, rd record) (bool, error) {
+	if expr == nil || len(expr.Rules) == 0 {
+		return true, nil
+	}
+
+	for _, rule := range expr.Rules {
+		hit, err := matchRule(rule, rd)
+		if err != nil {
+			return false, err
+		}
+
+		switch expr.Op {
+		case filter.And:
+			if !hit {
+				return false, nil
+			}
+		case filter.Or:
+			if hit {
+				return true, nil
+			}
+		default:
+			return false, fmt.Errorf("unsupported expression op: %s", expr.Op)
+		}
+	}
+
+	return expr.Op == filter.And, nil
+}
+
+func matchRule(rule filter.RuleFactory, rd record) (bool, error) {
+	switch r := rule.(type) {
+	case *filter.Expression:
+		return matchExpression(r, rd)
+	case *filter.AtomRule:
+		return matchAtomRule(r, rd)
+	case filter.AtomRule:
+		return matchAtomRule(&r, rd)
+	default:
+		return false, fmt.Errorf("unsupported rule type: %T", rule)
+	}
+}
+
+func matchAtomRule(rule *filter.AtomRule, rd record) (bool, error) {
+	field, exist := rd[rule.Field]
+	if !exist {
+		return false, fmt.Errorf("field %s not exist", rule.Field)
+	}
+
+	switch op := rule.Op.Operator().Name(); op {
+	case filter.Equal:
+		return equalValue(rule.Field, field, rule.Value)
+
+	case filter.NotEqual:
+		hit, err := equalValue(rule.Field, field, rule.Value)
+		return !hit, err
+
+	case filter.GreaterThan, filter.GreaterThanEqual, filter.LessThan, filter.LessThanEqual:
+		result, err := compareValue(rule.Field, field, rule.Value)
+		if err != nil {
+			return false, err
+		}
+
+		switch op {
+		case filter.GreaterThan:
+			return result > 0, nil
+		case filter.GreaterThanEqual:
+			return result >= 0, nil
+		case filter.LessThan:
+			return result < 0, nil
+		default:
+			return result <= 0, nil
+		}
+
+	case filter.In, filter.NotIn:
+		hit, err := inValues(rule.Field, field, rule.Value)
+		if err != nil {
+			return false, err
+		}
+
+		if op == filter.NotIn {
+			return !hit, nil
+		}
+		return hit, nil
+
+	case filter.ContainsSensitive:
+		return strings.Contains(fmt.Sprint(field), fmt.Sprint(rule.Value)), nil
+
+	case filter.ContainsInsensitive:
+		return strings.Contains(strings.ToLower(fmt.Sprint(field)), strings.ToLower(fmt.Sprint(rule.Value))), nil
+
+	default:
+		return false, fmt.Errorf("unsupported operator: %s", op)
+	}
+}
+
+func inValues(field string, src interface{}, values interface{}) (bool, error) {
+	rv := reflect.ValueOf(values)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return false, fmt.Errorf("%s's value should be an array", field)
+	}
+
+	for i := 0; i < rv.Len(); i++ {
+		hit, err := equalValue(field, src, rv.Index(i).Interface())
+		if err != nil {
+			return false, err
+		}
+
+		if hit {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func equalValue(field string, src interface{}, dst interface{}) (bool, error) {
+	result, err := compareValue(field, src, dst)
+	if err != nil {
+		return false, err
+	}
+
+	return result == 0, nil
+}
+
+// compareValue 比较记录值与过滤值，返回 -1、0、1，时间字段按照时间比较，数值按照数值比较，其余按照字符串比较。
+func compareValue(field string, src interface{}, dst interface{}) (int, error) {
+	if _, ok := timeFields[field]; ok {
+		srcTime, err := parseStdTime(src)
+		if err != nil {
+			return 0, err
+		}
+
+		dstTime, err := parseStdTime(dst)
+		if err != nil {
+			return 0, err
+		}
+
+		switch {
+		case srcTime.Before(dstTime):
+			return -1, nil
+		case srcTime.After(dstTime):
+			return 1, nil
+		default:
+			return 0, nil
+		}
+	}
+
+	srcVal, dstVal := normalizeValue(src), normalizeValue(dst)
+	switch s := srcVal.(type) {
+	case float64:
+		d, ok := dstVal.(float64)
+		if !ok {
+			return 0, fmt.Errorf("%s's value type %T not match %T", field, dst, src)
+		}
+
+		switch {
+		case s < d:
+			return -1, nil
+		case s > d:
+			return 1, nil
+		default:
+			return 0, nil
+		}
+
+	case bool:
+		d, ok := dstVal.(bool)
+		if !ok {
+			return 0, fmt.Errorf("%s's value type %T not match %T", field, dst, src)
+		}
+
+		switch {
+		case s == d:
+			return 0, nil
+		case !s:
+			return -1, nil
+		default:
+			return 1, nil
+		}
+
+	default:
+		return strings.Compare(fmt.Sprint(srcVal), fmt.Sprint(dstVal)), nil
+	}
+}
+
+// normalizeValue 将自定义的字符串、数值类型统一转换为基础类型
+func normalizeValue(v interface{}) interface{} {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Ptr:
+		if rv.IsNil() {
+			return ""
+		}
+		return normalizeValue(rv.Elem().Interface())
+	case reflect.String:
+		return rv.String()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(rv.Uint())
+	case reflect.Float32, reflect.Float64:
+		return rv.Float()
+	case reflect.Bool:
+		return rv.Bool()
+	default:
+		return v
+	}
+}
+
+var timeFields = map[string]struct{}{
+	"created_at":      {},
+	"updated_at":      {},
+	"run_at":          {},
+	"heartbeat_at":    {},
+	"next_run_at":     {},
+	"plan_at":         {},
+	"next_attempt_at": {},
+	"flow_created_at": {},
+	"finished_at":     {},
+	"archived_at":     {},
+}
+
+func parseStdTime(v interface{}) (time.Time, error) {
+	switch t := v.(type) {
+	case time.Time:
+		return t, nil
+	case string:
+		return time.ParseInLocation(constant.TimeStdFormat, t, time.Local)
+	default:
+		return time.Time{}, fmt.Errorf("time value should be string, but got %T", v)
+	}
+}
+
+// pageRecords 按照分页参数对记录进行排序、分页，返回命中记录的下标，语义与 dao 层分页保持一致。
+func pageRecords(rds []record, page *core.BasePage) ([]int, error) {
+	if page.Count {
+		return make([]int, 0), nil
+	}
+
+	idx := make([]int, len(rds))
+	for i := range idx {
+		idx[i] = i
+	}
+
+	sortField := page.Sort
+	if len(sortField) == 0 {
+		sortField = "id"
+	}
+
+	var sortErr error
+	sort.SliceStable(idx, func(i, j int) bool {
+		result, err := compareValue(sortField, rds[idx[i]][sortField], rds[idx[j]][sortField])
+		if err != nil {
+			sortErr = err
+			return false
+		}
+
+		if page.Order.Order() == core.Descending {
+			return result > 0
+		}
+		return result < 0
+	})
+	if sortErr != nil {
+		return nil, sortErr
+	}
+
+	if page.Start == 0 && page.Limit == 0 {
+		return idx, nil
+	}
+
+	start := int(page.Start)
+	if start >= len(idx) {
+		return make([]int, 0), nil
+	}
+
+	end := start + int(page.Limit)
+	if end > len(idx) {
+		end = len(idx)
+	}
+
+	return idx[start:end], nil
+}
@@ -0,0 +1,1108 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package backend
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"hcm/pkg/async/backend/model"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	tableasync "hcm/pkg/dal/table/async"
+	tabletypes "hcm/pkg/dal/table/types"
+	"hcm/pkg/kit"
+	"hcm/pkg/tools/json"
+	"hcm/pkg/tools/times"
+)
+
+// NewMemory create memory instance, data is only kept in process memory, used for test and standalone scene.
+func NewMemory() Backend {
+	return &memory{
+		flows:     make(map[string]*tableasync.AsyncFlowTable),
+		tasks:     make(map[string]*tableasync.AsyncFlowTaskTable),
+		schedules: make(map[string]*tableasync.AsyncFlowScheduleTable),
+		runs:      make(map[string]*tableasync.AsyncFlowScheduleRunTable),
+		events:    make(map[string]*tableasync.AsyncFlowEventTable),
+		archives:  make(map[string]*tableasync.AsyncFlowArchiveTable),
+		idems:     make(map[idempotencyKey]*tableasync.AsyncFlowIdempotencyTable),
+	}
+}
+
+// memory 内存后端，所有写操作在同一把锁内完成，批量操作要么全部成功，要么全部失败。
+type memory struct {
+	lock      sync.Mutex
+	maxID     uint64
+	flows     map[string]*tableasync.AsyncFlowTable
+	tasks     map[string]*tableasync.AsyncFlowTaskTable
+	schedules map[string]*tableasync.AsyncFlowScheduleTable
+	runs      map[string]*tableasync.AsyncFlowScheduleRunTable
+	events    map[string]*tableasync.AsyncFlowEventTable
+	archives  map[string]*tableasync.AsyncFlowArchiveTable
+	idems     map[idempotencyKey]*tableasync.AsyncFlowIdempotencyTable
+}
+
+// idempotencyKey 应用下的幂等键
+type idempotencyKey struct {
+	appCode string
+	key     string
+}
+
+var _ Backend = new(memory)
+
+// genIDs 生成 id，调用方需持有锁。
+func (m *memory) genIDs(num int) []string {
+	ids := make([]string, 0, num)
+	for i := 0; i < num; i++ {
+		m.maxID++
+		ids = append(ids, formatID(m.maxID))
+	}
+
+	return ids
+}
+
+// CreateFlow 创建任务流
+func (m *memory) CreateFlow(kt *kit.Kit, flow *model.Flow) (string, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	return m.createFlow(kt, flow)
+}
+
+// createFlow 创建任务流、任务及任务流创建事件，调用方需持有锁。
+func (m *memory) createFlow(kt *kit.Kit, flow *model.Flow) (string, error) {
+	md, err := newFlowTable(kt, flow)
+	if err != nil {
+		return "", err
+	}
+
+	now := nowTime()
+	mds := newFlowTaskTables(kt, "", flow.Tasks)
+
+	ids := m.genIDs(len(mds) + 1)
+	md.ID = ids[0]
+	if err := md.InsertValidate(); err != nil {
+		return "", err
+	}
+	md.CreatedAt, md.UpdatedAt = now, now
+
+	for index := range mds {
+		mds[index].ID = ids[index+1]
+		mds[index].FlowID = md.ID
+		if err := mds[index].InsertValidate(); err != nil {
+			return "", err
+		}
+		mds[index].CreatedAt, mds[index].UpdatedAt = now, now
+	}
+
+	flowCopy, err := cloneFlow(md)
+	if err != nil {
+		return "", err
+	}
+	taskCopies := make([]*tableasync.AsyncFlowTaskTable, 0, len(mds))
+	for index := range mds {
+		taskCopy, err := cloneTask(&mds[index])
+		if err != nil {
+			return "", err
+		}
+		taskCopies = append(taskCopies, taskCopy)
+	}
+
+	m.flows[flowCopy.ID] = flowCopy
+	for _, one := range taskCopies {
+		m.tasks[one.ID] = one
+	}
+
+	// 生成任务流创建事件
+	m.addFlowEvents([]tableasync.AsyncFlowEventTable{newFlowEventTable(md.ID, enumor.FlowEventCreated)}, now)
+
+	return md.ID, nil
+}
+
+// CreateFlowIdempotently 按照幂等键创建任务流
+func (m *memory) CreateFlowIdempotently(kt *kit.Kit, flow *model.Flow, idem *model.FlowIdempotency) (string,
+	error) {
+
+	idemMd, err := newFlowIdempotencyTable(idem)
+	if err != nil {
+		return "", err
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	key := idempotencyKey{appCode: idem.AppCode, key: idem.IdempotencyKey}
+	// 过期的幂等键可以被重新使用
+	if exist, ok := m.idems[key]; ok && exist.ExpiredAt.After(time.Now()) {
+		return idempotentFlowID(*exist, idem.PayloadHash)
+	}
+
+	flowID, err := m.createFlow(kt, flow)
+	if err != nil {
+		return "", err
+	}
+
+	idemMd.ID = flowID
+	idemMd.CreatedAt = nowTime()
+	m.idems[key] = idemMd
+
+	return flowID, nil
+}
+
+// DeleteExpiredFlowIdempotency 删除已过期的幂等键
+func (m *memory) DeleteExpiredFlowIdempotency(kt *kit.Kit) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	now := time.Now()
+	for key, one := range m.idems {
+		if !one.ExpiredAt.After(now) {
+			delete(m.idems, key)
+		}
+	}
+
+	return nil
+}
+
+// BatchUpdateFlow 批量更新任务流
+func (m *memory) BatchUpdateFlow(kt *kit.Kit, flows []model.Flow) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	now := nowTime()
+	updated := make([]*tableasync.AsyncFlowTable, 0, len(flows))
+	for _, one := range flows {
+		if len(one.ID) == 0 {
+			return errf.New(errf.InvalidParameter, "id is required")
+		}
+
+		md := flowUpdateTable(one)
+		if err := md.UpdateValidate(); err != nil {
+			return err
+		}
+
+		exist, ok := m.flows[one.ID]
+		if !ok {
+			return errf.New(errf.RecordNotUpdate, "record not update")
+		}
+
+		dst, err := cloneFlow(exist)
+		if err != nil {
+			return err
+		}
+		if err = mergeFlow(dst, md); err != nil {
+			return err
+		}
+		dst.UpdatedAt = now
+		updated = append(updated, dst)
+	}
+
+	for _, one := range updated {
+		m.flows[one.ID] = one
+	}
+
+	ids := make([]string, 0, len(flows))
+	states := make([]enumor.FlowState, 0, len(flows))
+	for _, one := range flows {
+		ids = append(ids, one.ID)
+		states = append(states, one.State)
+	}
+
+	// 生成任务流状态变更事件
+	m.addFlowEvents(flowStateEventTables(ids, states), now)
+
+	return nil
+}
+
+// ListFlow 查询任务流
+func (m *memory) ListFlow(kt *kit.Kit, input *ListInput) ([]model.Flow, error) {
+	if err := validateListInput(input, tableasync.AsyncFlowColumns); err != nil {
+		return nil, err
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	hits := make([]*tableasync.AsyncFlowTable, 0)
+	rds := make([]record, 0)
+	for _, one := range m.flows {
+		rd := flowRecord(one)
+		hit, err := matchExpression(input.Filter, rd)
+		if err != nil {
+			return nil, errf.NewFromErr(errf.InvalidParameter, err)
+		}
+
+		if hit {
+			hits = append(hits, one)
+			rds = append(rds, rd)
+		}
+	}
+
+	idx, err := pageRecords(rds, input.Page)
+	if err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	flows := make([]model.Flow, 0, len(idx))
+	for _, i := range idx {
+		one, err := cloneFlow(hits[i])
+		if err != nil {
+			return nil, err
+		}
+		flows = append(flows, flowTableToModel(*one))
+	}
+
+	return flows, nil
+}
+
+// BatchUpdateFlowStateByCAS CAS批量更新流状态，任一任务流状态不符合预期时，全部不更新。
+func (m *memory) BatchUpdateFlowStateByCAS(kt *kit.Kit, infos []UpdateFlowInfo) error {
+	for _, one := range infos {
+		if err := one.Validate(); err != nil {
+			return err
+		}
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	for _, one := range infos {
+		exist, ok := m.flows[one.ID]
+		if !ok || exist.State != one.Source {
+			return errf.Newf(errf.RecordNotUpdate, "flow[%s: %s] update state: %s, worker: %s failed",
+				one.ID, one.Source, one.Target, one.Worker)
+		}
+	}
+
+	now := nowTime()
+	ids := make([]string, 0, len(infos))
+	states := make([]enumor.FlowState, 0, len(infos))
+	for _, one := range infos {
+		ids = append(ids, one.ID)
+		states = append(states, one.Target)
+
+		exist := m.flows[one.ID]
+		exist.State = one.Target
+		if one.ResetWorker {
+			worker := ""
+			exist.Worker = &worker
+		} else if len(one.Worker) != 0 {
+			worker := one.Worker
+			exist.Worker = &worker
+		}
+
+		if one.Reason != nil {
+			reason := *one.Reason
+			exist.Reason = &reason
+		}
+		exist.UpdatedAt = now
+	}
+
+	// 生成任务流状态变更事件
+	m.addFlowEvents(flowStateEventTables(ids, states), now)
+
+	return nil
+}
+
+// BatchCreateTask 批量创建任务
+func (m *memory) BatchCreateTask(kt *kit.Kit, tasks []model.Task) ([]string, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	now := nowTime()
+	mds := newTaskTables(tasks)
+	ids := m.genIDs(len(mds))
+	copies := make([]*tableasync.AsyncFlowTaskTable, 0, len(mds))
+	for index := range mds {
+		mds[index].ID = ids[index]
+		if err := mds[index].InsertValidate(); err != nil {
+			return nil, err
+		}
+		mds[index].CreatedAt, mds[index].UpdatedAt = now, now
+
+		one, err := cloneTask(&mds[index])
+		if err != nil {
+			return nil, err
+		}
+		copies = append(copies, one)
+	}
+
+	for _, one := range copies {
+		m.tasks[one.ID] = one
+	}
+
+	return ids, nil
+}
+
+// UpdateTask 更新任务
+func (m *memory) UpdateTask(kt *kit.Kit, task *model.Task) error {
+	if len(task.ID) == 0 {
+		return errf.New(errf.InvalidParameter, "id is required")
+	}
+
+	md, err := taskUpdateTable(kt, task)
+	if err != nil {
+		return err
+	}
+
+	if err = md.UpdateValidate(); err != nil {
+		return err
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	exist, ok := m.tasks[task.ID]
+	if !ok {
+		return errf.Newf(errf.RecordNotFound, "task: %s not found", task.ID)
+	}
+
+	dst, err := cloneTask(exist)
+	if err != nil {
+		return err
+	}
+	if err = mergeTask(dst, md); err != nil {
+		return err
+	}
+	dst.UpdatedAt = nowTime()
+	m.tasks[dst.ID] = dst
+
+	return nil
+}
+
+// RestartFlow 在同一事务中CAS重置任务流和任务状态
+func (m *memory) RestartFlow(kt *kit.Kit, info *RestartFlowInfo) error {
+	if err := info.Validate(); err != nil {
+		return err
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	flow, ok := m.flows[info.ID]
+	if !ok || flow.State != info.Source {
+		return errf.Newf(errf.RecordNotUpdate, "flow[%s: %s] update state: %s failed", info.ID, info.Source,
+			enumor.FlowPending)
+	}
+
+	now := nowTime()
+	flow.State = enumor.FlowPending
+	flow.Reason = new(tableasync.Reason)
+	flow.UpdatedAt = now
+
+	for _, task := range m.tasks {
+		if task.FlowID != info.ID || task.State != info.TaskSource {
+			continue
+		}
+
+		task.State = enumor.TaskPending
+		task.Reason = new(tableasync.Reason)
+		task.Reviser = kt.User
+		task.UpdatedAt = now
+	}
+
+	// 生成任务流状态变更事件
+	m.addFlowEvents(flowStateEventTables([]string{info.ID}, []enumor.FlowState{enumor.FlowPending}), now)
+
+	return nil
+}
+
+// UpdateTaskStateByCAS CAS更新任务状态
+func (m *memory) UpdateTaskStateByCAS(kt *kit.Kit, info *UpdateTaskInfo) error {
+	if err := info.Validate(); err != nil {
+		return err
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	exist, ok := m.tasks[info.ID]
+	if !ok || exist.State != info.Source {
+		return errf.Newf(errf.RecordNotUpdate, "task[%s: %s] update state to %s failed", info.ID, info.Source,
+			info.Target)
+	}
+
+	exist.State = info.Target
+	if info.Reason != nil {
+		reason := *info.Reason
+		exist.Reason = &reason
+	}
+	exist.UpdatedAt = nowTime()
+
+	return nil
+}
+
+// ListTask 查询任务
+func (m *memory) ListTask(kt *kit.Kit, input *ListInput) ([]model.Task, error) {
+	if err := validateListInput(input, tableasync.AsyncFlowTaskColumns); err != nil {
+		return nil, err
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	hits := make([]*tableasync.AsyncFlowTaskTable, 0)
+	rds := make([]record, 0)
+	for _, one := range m.tasks {
+		rd := taskRecord(one)
+		hit, err := matchExpression(input.Filter, rd)
+		if err != nil {
+			return nil, errf.NewFromErr(errf.InvalidParameter, err)
+		}
+
+		if hit {
+			hits = append(hits, one)
+			rds = append(rds, rd)
+		}
+	}
+
+	idx, err := pageRecords(rds, input.Page)
+	if err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	tasks := make([]model.Task, 0, len(idx))
+	for _, i := range idx {
+		one, err := cloneTask(hits[i])
+		if err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, taskTableToModel(*one))
+	}
+
+	return tasks, nil
+}
+
+// CreateFlowSchedule 创建定时任务流
+func (m *memory) CreateFlowSchedule(kt *kit.Kit, schedule *model.FlowSchedule) (string, error) {
+	md, err := newFlowScheduleTable(kt, schedule)
+	if err != nil {
+		return "", err
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	for _, one := range m.schedules {
+		if one.Name == md.Name {
+			return "", errf.Newf(errf.InvalidParameter, "flow schedule %s already exists", md.Name)
+		}
+	}
+
+	md.ID = m.genIDs(1)[0]
+	if err = md.InsertValidate(); err != nil {
+		return "", err
+	}
+	now := nowTime()
+	md.CreatedAt, md.UpdatedAt = now, now
+
+	one, err := cloneValue(md)
+	if err != nil {
+		return "", err
+	}
+	m.schedules[one.ID] = one
+
+	return md.ID, nil
+}
+
+// UpdateFlowSchedule 更新定时任务流
+func (m *memory) UpdateFlowSchedule(kt *kit.Kit, schedule *model.FlowSchedule) error {
+	if len(schedule.ID) == 0 {
+		return errf.New(errf.InvalidParameter, "id is required")
+	}
+
+	md, err := flowScheduleUpdateTable(kt, schedule)
+	if err != nil {
+		return err
+	}
+
+	if err = md.UpdateValidate(); err != nil {
+		return err
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	exist, ok := m.schedules[schedule.ID]
+	if !ok {
+		return errf.New(errf.RecordNotUpdate, "record not update")
+	}
+
+	mergeFlowScheduleUpdate(exist, md)
+	exist.UpdatedAt = nowTime()
+
+	return nil
+}
+
+// UpdateFlowScheduleNextRunByCAS CAS更新处于启用状态的定时任务流的下次执行时间
+func (m *memory) UpdateFlowScheduleNextRunByCAS(kt *kit.Kit, info *UpdateScheduleNextRunInfo) error {
+	if err := info.Validate(); err != nil {
+		return err
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	exist, ok := m.schedules[info.ID]
+	if !ok || exist.State != enumor.FlowScheduleEnabled || !exist.NextRunAt.Equal(info.Source) {
+		return errf.Newf(errf.RecordNotUpdate, "flow schedule[%s] update next run time from %s to %s failed",
+			info.ID, times.ConvStdTimeFormat(info.Source), times.ConvStdTimeFormat(info.Target))
+	}
+
+	exist.NextRunAt = info.Target
+	if info.Pause {
+		exist.State = enumor.FlowSchedulePaused
+	}
+	exist.UpdatedAt = nowTime()
+
+	return nil
+}
+
+// ListFlowSchedule 查询定时任务流
+func (m *memory) ListFlowSchedule(kt *kit.Kit, input *ListInput) ([]model.FlowSchedule, error) {
+	if err := validateListInput(input, tableasync.AsyncFlowScheduleColumns); err != nil {
+		return nil, err
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	hits := make([]*tableasync.AsyncFlowScheduleTable, 0)
+	rds := make([]record, 0)
+	for _, one := range m.schedules {
+		rd := flowScheduleRecord(one)
+		hit, err := matchExpression(input.Filter, rd)
+		if err != nil {
+			return nil, errf.NewFromErr(errf.InvalidParameter, err)
+		}
+
+		if hit {
+			hits = append(hits, one)
+			rds = append(rds, rd)
+		}
+	}
+
+	idx, err := pageRecords(rds, input.Page)
+	if err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	schedules := make([]model.FlowSchedule, 0, len(idx))
+	for _, i := range idx {
+		one, err := cloneValue(hits[i])
+		if err != nil {
+			return nil, err
+		}
+		schedules = append(schedules, flowScheduleTableToModel(*one))
+	}
+
+	return schedules, nil
+}
+
+// DeleteFlowSchedule 删除定时任务流及其执行记录，已生成的任务流不受影响
+func (m *memory) DeleteFlowSchedule(kt *kit.Kit, id string) error {
+	if len(id) == 0 {
+		return errf.New(errf.InvalidParameter, "id is required")
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	for runID, one := range m.runs {
+		if one.ScheduleID == id {
+			delete(m.runs, runID)
+		}
+	}
+	delete(m.schedules, id)
+
+	return nil
+}
+
+// BatchCreateFlowScheduleRun 批量创建定时任务流执行记录
+func (m *memory) BatchCreateFlowScheduleRun(kt *kit.Kit, runs []model.FlowScheduleRun) error {
+	mds, err := newFlowScheduleRunTables(kt, runs)
+	if err != nil {
+		return err
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	now := nowTime()
+	ids := m.genIDs(len(mds))
+	copies := make([]*tableasync.AsyncFlowScheduleRunTable, 0, len(mds))
+	for index := range mds {
+		mds[index].ID = ids[index]
+		if err = mds[index].InsertValidate(); err != nil {
+			return err
+		}
+		mds[index].CreatedAt = now
+
+		one, err := cloneValue(&mds[index])
+		if err != nil {
+			return err
+		}
+		copies = append(copies, one)
+	}
+
+	for _, one := range copies {
+		m.runs[one.ID] = one
+	}
+
+	return nil
+}
+
+// ListFlowScheduleRun 查询定时任务流执行记录
+func (m *memory) ListFlowScheduleRun(kt *kit.Kit, input *ListInput) ([]model.FlowScheduleRun, error) {
+	if err := validateListInput(input, tableasync.AsyncFlowScheduleRunColumns); err != nil {
+		return nil, err
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	hits := make([]*tableasync.AsyncFlowScheduleRunTable, 0)
+	rds := make([]record, 0)
+	for _, one := range m.runs {
+		rd := flowScheduleRunRecord(one)
+		hit, err := matchExpression(input.Filter, rd)
+		if err != nil {
+			return nil, errf.NewFromErr(errf.InvalidParameter, err)
+		}
+
+		if hit {
+			hits = append(hits, one)
+			rds = append(rds, rd)
+		}
+	}
+
+	idx, err := pageRecords(rds, input.Page)
+	if err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	runs := make([]model.FlowScheduleRun, 0, len(idx))
+	for _, i := range idx {
+		one, err := cloneValue(hits[i])
+		if err != nil {
+			return nil, err
+		}
+		runs = append(runs, flowScheduleRunTableToModel(*one))
+	}
+
+	return runs, nil
+}
+
+// addFlowEvents 保存任务流事件，事件由后端生成，字段一定合法，调用方需持有锁。
+func (m *memory) addFlowEvents(mds []tableasync.AsyncFlowEventTable, now tabletypes.Time) {
+	ids := m.genIDs(len(mds))
+	for index := range mds {
+		one := mds[index]
+		one.ID = ids[index]
+		one.CreatedAt, one.UpdatedAt = now, now
+		m.events[one.ID] = &one
+	}
+}
+
+// ListFlowEvent 查询任务流事件
+func (m *memory) ListFlowEvent(kt *kit.Kit, input *ListInput) ([]model.FlowEvent, error) {
+	if err := validateListInput(input, tableasync.AsyncFlowEventColumns); err != nil {
+		return nil, err
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	hits := make([]*tableasync.AsyncFlowEventTable, 0)
+	rds := make([]record, 0)
+	for _, one := range m.events {
+		rd := flowEventRecord(one)
+		hit, err := matchExpression(input.Filter, rd)
+		if err != nil {
+			return nil, errf.NewFromErr(errf.InvalidParameter, err)
+		}
+
+		if hit {
+			hits = append(hits, one)
+			rds = append(rds, rd)
+		}
+	}
+
+	idx, err := pageRecords(rds, input.Page)
+	if err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	events := make([]model.FlowEvent, 0, len(idx))
+	for _, i := range idx {
+		one, err := cloneValue(hits[i])
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, flowEventTableToModel(*one))
+	}
+
+	return events, nil
+}
+
+// UpdateFlowEvent 更新任务流事件的投递结果
+func (m *memory) UpdateFlowEvent(kt *kit.Kit, event *model.FlowEvent) error {
+	if len(event.ID) == 0 {
+		return errf.New(errf.InvalidParameter, "id is required")
+	}
+
+	md, err := flowEventUpdateTable(event)
+	if err != nil {
+		return err
+	}
+
+	if err = md.UpdateValidate(); err != nil {
+		return err
+	}
+
+	src, err := cloneValue(md)
+	if err != nil {
+		return err
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	exist, ok := m.events[event.ID]
+	if !ok {
+		return errf.New(errf.RecordNotUpdate, "record not update")
+	}
+
+	mergeFlowEventUpdate(exist, src)
+	exist.UpdatedAt = nowTime()
+
+	return nil
+}
+
+// ArchiveFlow 归档任务流
+func (m *memory) ArchiveFlow(kt *kit.Kit, ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	if err := validateArchiveIDs(ids); err != nil {
+		return err
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	expr := archivableFlowExpr(ids)
+	archived := make(map[string]struct{}, len(ids))
+	flows := make([]tableasync.AsyncFlowTable, 0, len(ids))
+	for _, id := range ids {
+		one, exist := m.flows[id]
+		if _, ok := archived[id]; ok || !exist {
+			continue
+		}
+
+		hit, err := matchExpression(expr, flowRecord(one))
+		if err != nil {
+			return err
+		}
+
+		if hit {
+			archived[id] = struct{}{}
+			flows = append(flows, *one)
+		}
+	}
+
+	if len(flows) == 0 {
+		return nil
+	}
+
+	tasks := make([]tableasync.AsyncFlowTaskTable, 0)
+	for _, one := range m.tasks {
+		if _, ok := archived[one.FlowID]; ok {
+			tasks = append(tasks, *one)
+		}
+	}
+	sort.Slice(tasks, func(i, j int) bool { return tasks[i].ID < tasks[j].ID })
+
+	events := make([]tableasync.AsyncFlowEventTable, 0)
+	for _, one := range m.events {
+		if _, ok := archived[one.FlowID]; ok {
+			events = append(events, *one)
+		}
+	}
+	sort.Slice(events, func(i, j int) bool { return events[i].ID < events[j].ID })
+
+	mds, err := newFlowArchiveTables(flows, tasks, events)
+	if err != nil {
+		return err
+	}
+
+	copies := make([]*tableasync.AsyncFlowArchiveTable, 0, len(mds))
+	for index := range mds {
+		if err = mds[index].InsertValidate(); err != nil {
+			return err
+		}
+
+		one, err := cloneValue(&mds[index])
+		if err != nil {
+			return err
+		}
+		copies = append(copies, one)
+	}
+
+	for _, one := range copies {
+		m.archives[one.ID] = one
+	}
+
+	for _, one := range tasks {
+		delete(m.tasks, one.ID)
+	}
+
+	for _, one := range events {
+		delete(m.events, one.ID)
+	}
+
+	for id := range archived {
+		delete(m.flows, id)
+	}
+
+	return nil
+}
+
+// ListFlowArchive 查询已归档的任务流
+func (m *memory) ListFlowArchive(kt *kit.Kit, input *ListInput) ([]model.FlowArchive, error) {
+	if err := validateListInput(input, tableasync.AsyncFlowArchiveColumns); err != nil {
+		return nil, err
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	hits := make([]*tableasync.AsyncFlowArchiveTable, 0)
+	rds := make([]record, 0)
+	for _, one := range m.archives {
+		rd := flowArchiveRecord(one)
+		hit, err := matchExpression(input.Filter, rd)
+		if err != nil {
+			return nil, errf.NewFromErr(errf.InvalidParameter, err)
+		}
+
+		if hit {
+			hits = append(hits, one)
+			rds = append(rds, rd)
+		}
+	}
+
+	idx, err := pageRecords(rds, input.Page)
+	if err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	archives := make([]model.FlowArchive, 0, len(idx))
+	for _, i := range idx {
+		one, err := cloneValue(hits[i])
+		if err != nil {
+			return nil, err
+		}
+		archives = append(archives, flowArchiveTableToModel(*one))
+	}
+
+	return archives, nil
+}
+
+// mergeFlow 合并更新字段，ShareData 等引用类型需要拷贝，避免与调用方共享内存。
+func mergeFlow(dst *tableasync.AsyncFlowTable, md *tableasync.AsyncFlowTable) error {
+	src, err := cloneFlow(md)
+	if err != nil {
+		return err
+	}
+	mergeFlowUpdate(dst, src)
+	return nil
+}
+
+// mergeTask 合并更新字段，Retry 等引用类型需要拷贝，避免与调用方共享内存。
+func mergeTask(dst *tableasync.AsyncFlowTaskTable, md *tableasync.AsyncFlowTaskTable) error {
+	src, err := cloneTask(md)
+	if err != nil {
+		return err
+	}
+	mergeTaskUpdate(dst, src)
+	return nil
+}
+
+func cloneFlow(src *tableasync.AsyncFlowTable) (*tableasync.AsyncFlowTable, error) {
+	raw, err := json.Marshal(src)
+	if err != nil {
+		return nil, err
+	}
+
+	dst := new(tableasync.AsyncFlowTable)
+	if err = json.Unmarshal(raw, dst); err != nil {
+		return nil, err
+	}
+
+	return dst, nil
+}
+
+func cloneTask(src *tableasync.AsyncFlowTaskTable) (*tableasync.AsyncFlowTaskTable, error) {
+	raw, err := json.Marshal(src)
+	if err != nil {
+		return nil, err
+	}
+
+	dst := new(tableasync.AsyncFlowTaskTable)
+	if err = json.Unmarshal(raw, dst); err != nil {
+		return nil, err
+	}
+
+	return dst, nil
+}
+
+// cloneValue 通过 json 深拷贝表数据，避免与调用方共享内存。
+func cloneValue[T any](src *T) (*T, error) {
+	raw, err := json.Marshal(src)
+	if err != nil {
+		return nil, err
+	}
+
+	dst := new(T)
+	if err = json.Unmarshal(raw, dst); err != nil {
+		return nil, err
+	}
+
+	return dst, nil
+}
+
+func flowRecord(one *tableasync.AsyncFlowTable) record {
+	return record{
+		"id":              one.ID,
+		"name":            one.Name,
+		"state":           one.State,
+		"reason":          jsonString(one.Reason),
+		"memo":            one.Memo,
+		"share_data":      jsonString(one.ShareData),
+		"worker":          one.Worker,
+		"run_at":          one.RunAt,
+		"rollback_policy": one.RollbackPolicy,
+		"priority":        one.Priority,
+		"tenant":          one.Tenant,
+		"app_code":        one.AppCode,
+		"webhooks":        jsonString(one.Webhooks),
+		"creator":         one.Creator,
+		"reviser":         one.Reviser,
+		"created_at":      one.CreatedAt.String(),
+		"updated_at":      one.UpdatedAt.String(),
+	}
+}
+
+func taskRecord(one *tableasync.AsyncFlowTaskTable) record {
+	return record{
+		"id":           one.ID,
+		"flow_id":      one.FlowID,
+		"flow_name":    one.FlowName,
+		"action_id":    one.ActionID,
+		"action_name":  one.ActionName,
+		"params":       one.Params,
+		"retry":        jsonString(one.Retry),
+		"depend_on":    jsonString(one.DependOn),
+		"state":        one.State,
+		"reason":       jsonString(one.Reason),
+		"result":       one.Result,
+		"stat":         jsonString(one.Stat),
+		"progress":     jsonString(one.Progress),
+		"heartbeat_at": one.HeartbeatAt,
+		"creator":      one.Creator,
+		"reviser":      one.Reviser,
+		"created_at":   one.CreatedAt.String(),
+		"updated_at":   one.UpdatedAt.String(),
+	}
+}
+
+func flowScheduleRecord(one *tableasync.AsyncFlowScheduleTable) record {
+	return record{
+		"id":            one.ID,
+		"name":          one.Name,
+		"cron":          one.Cron,
+		"state":         one.State,
+		"missed_policy": one.MissedPolicy,
+		"flow":          jsonString(one.Flow),
+		"next_run_at":   one.NextRunAt,
+		"memo":          one.Memo,
+		"creator":       one.Creator,
+		"reviser":       one.Reviser,
+		"created_at":    one.CreatedAt.String(),
+		"updated_at":    one.UpdatedAt.String(),
+	}
+}
+
+func flowScheduleRunRecord(one *tableasync.AsyncFlowScheduleRunTable) record {
+	return record{
+		"id":          one.ID,
+		"schedule_id": one.ScheduleID,
+		"plan_at":     one.PlanAt,
+		"state":       one.State,
+		"flow_id":     one.FlowID,
+		"reason":      jsonString(one.Reason),
+		"creator":     one.Creator,
+		"created_at":  one.CreatedAt.String(),
+	}
+}
+
+func flowEventRecord(one *tableasync.AsyncFlowEventTable) record {
+	return record{
+		"id":              one.ID,
+		"flow_id":         one.FlowID,
+		"event":           one.Event,
+		"state":           one.State,
+		"attempts":        one.Attempts,
+		"next_attempt_at": one.NextAttemptAt,
+		"deliveries":      jsonString(one.Deliveries),
+		"created_at":      one.CreatedAt.String(),
+		"updated_at":      one.UpdatedAt.String(),
+	}
+}
+
+func flowArchiveRecord(one *tableasync.AsyncFlowArchiveTable) record {
+	return record{
+		"id":              one.ID,
+		"name":            one.Name,
+		"state":           one.State,
+		"tenant":          one.Tenant,
+		"app_code":        one.AppCode,
+		"detail":          jsonString(one.Detail),
+		"flow_created_at": one.FlowCreatedAt,
+		"finished_at":     one.FinishedAt,
+		"archived_at":     one.ArchivedAt,
+	}
+}
+
+func jsonString(v interface{}) string {
+	str, err := json.MarshalToString(v)
+	if err != nil {
+		return ""
+	}
+
+	return str
+}
+
+func nowTime() tabletypes.Time {
+	return tabletypes.Time(times.ConvStdTimeFormat(times.ConvStdTimeNow()))
+}
@@ -31,6 +31,18 @@ type Flow struct {
 	Name      enumor.FlowName       `json:"name"`
 	ShareData *tableasync.ShareData `json:"share_data"`
 	Memo      string                `json:"memo"`
+	// RunAt 任务流最早执行时间，格式为 constant.TimeStdFormat，为空时表示立即执行
+	RunAt string `json:"run_at"`
+	// RollbackPolicy 任务流失败后的回滚策略，为空时不回滚
+	RollbackPolicy enumor.FlowRollbackPolicy `json:"rollback_policy"`
+	// Priority 任务流优先级，为空时为 normal
+	Priority enumor.FlowPriority `json:"priority"`
+	// Tenant 任务流所属租户，用于按租户限制并发，建议使用 bk_biz_id 或 app_code，为空时为请求的 app_code
+	Tenant string `json:"tenant"`
+	// AppCode 创建任务流的应用，为空时为请求的 app_code，任务流事件会推送给该应用订阅的 webhook
+	AppCode string `json:"app_code"`
+	// Webhooks 任务流自定义的 webhook 订阅，与应用订阅的 webhook 一起接收任务流事件
+	Webhooks tableasync.WebhookTargets `json:"webhooks"`
 
 	ID        string             `json:"id"`
 	State     enumor.FlowState   `json:"state"`
@@ -0,0 +1,41 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package model
+
+import (
+	"hcm/pkg/criteria/enumor"
+)
+
+// FlowArchive 已归档的任务流，任务流结束且超过保留时间后，连同任务、任务流事件一起归档
+type FlowArchive struct {
+	// ID 任务流ID
+	ID      string           `json:"id"`
+	Name    enumor.FlowName  `json:"name"`
+	State   enumor.FlowState `json:"state"`
+	Tenant  string           `json:"tenant"`
+	AppCode string           `json:"app_code"`
+	// Flow 归档时的任务流，包含任务，查询时未选择 detail 字段则为空
+	Flow *Flow `json:"flow"`
+	// Events 归档时的任务流事件，查询时未选择 detail 字段则为空
+	Events        []FlowEvent `json:"events"`
+	FlowCreatedAt string      `json:"flow_created_at"`
+	FinishedAt    string      `json:"finished_at"`
+	ArchivedAt    string      `json:"archived_at"`
+}
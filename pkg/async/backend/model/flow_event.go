@@ -0,0 +1,42 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package model
+
+import (
+	"hcm/pkg/criteria/enumor"
+	tableasync "hcm/pkg/dal/table/async"
+)
+
+// FlowEvent 任务流生命周期事件，任务流创建及状态变更时由后端生成，并记录 webhook 投递日志
+type FlowEvent struct {
+	ID     string               `json:"id"`
+	FlowID string               `json:"flow_id"`
+	Event  enumor.FlowEventType `json:"event"`
+	// State 事件投递状态
+	State enumor.FlowEventState `json:"state"`
+	// Attempts 已投递次数
+	Attempts uint `json:"attempts"`
+	// NextAttemptAt 下次投递时间，格式为 constant.TimeStdFormat
+	NextAttemptAt string `json:"next_attempt_at"`
+	// Deliveries 每次投递每个 webhook 的投递日志
+	Deliveries tableasync.WebhookDeliveries `json:"deliveries"`
+	CreatedAt  string                       `json:"created_at"`
+	UpdatedAt  string                       `json:"updated_at"`
+}
@@ -0,0 +1,32 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package model
+
+// FlowIdempotency 创建任务流时使用的幂等键，同一应用下幂等键未过期时，重复创建任务流返回首次创建的任务流
+type FlowIdempotency struct {
+	// FlowID 幂等键对应的任务流ID，创建任务流时不需要指定
+	FlowID         string `json:"flow_id"`
+	AppCode        string `json:"app_code"`
+	IdempotencyKey string `json:"idempotency_key"`
+	// PayloadHash 创建任务流请求内容的摘要，幂等键相同但摘要不同的请求会被拒绝
+	PayloadHash string `json:"payload_hash"`
+	// ExpiredAt 幂等键过期时间，格式为 constant.TimeStdFormat
+	ExpiredAt string `json:"expired_at"`
+}
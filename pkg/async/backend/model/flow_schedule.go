@@ -0,0 +1,55 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package model
+
+import (
+	"hcm/pkg/criteria/enumor"
+	tableasync "hcm/pkg/dal/table/async"
+)
+
+// FlowSchedule 定时任务流，主节点按照 Cron 在计划时间生成任务流实例。
+type FlowSchedule struct {
+	ID           string                   `json:"id"`
+	Name         string                   `json:"name"`
+	Cron         string                   `json:"cron"`
+	State        enumor.FlowScheduleState `json:"state"`
+	MissedPolicy enumor.MissedRunPolicy   `json:"missed_policy"`
+	Flow         *tableasync.ScheduleFlow `json:"flow"`
+	// NextRunAt 下次计划执行时间，格式为 constant.TimeStdFormat
+	NextRunAt string `json:"next_run_at"`
+	Memo      string `json:"memo"`
+	Creator   string `json:"creator"`
+	Reviser   string `json:"reviser"`
+	CreatedAt string `json:"created_at"`
+	UpdatedAt string `json:"updated_at"`
+}
+
+// FlowScheduleRun 定时任务流执行记录
+type FlowScheduleRun struct {
+	ID         string `json:"id"`
+	ScheduleID string `json:"schedule_id"`
+	// PlanAt 计划执行时间，格式为 constant.TimeStdFormat
+	PlanAt    string                      `json:"plan_at"`
+	State     enumor.FlowScheduleRunState `json:"state"`
+	FlowID    string                      `json:"flow_id"`
+	Reason    *tableasync.Reason          `json:"reason"`
+	Creator   string                      `json:"creator"`
+	CreatedAt string                      `json:"created_at"`
+}
@@ -41,10 +41,16 @@ type Task struct {
 	State      enumor.TaskState   `json:"state"`
 	Reason     *tableasync.Reason `json:"reason"`
 	Result     types.JsonField    `json:"result"`
-	Creator    string             `json:"creator"`
-	Reviser    string             `json:"reviser"`
-	CreatedAt  string             `json:"created_at"`
-	UpdatedAt  string             `json:"updated_at"`
+	// Stat 任务执行统计，包括执行开始、结束时间和执行次数
+	Stat *tableasync.TaskStat `json:"stat"`
+	// Progress 任务上报的执行进度及断点数据
+	Progress *tableasync.TaskProgress `json:"progress"`
+	// HeartbeatAt 任务最近一次上报心跳的时间
+	HeartbeatAt string `json:"heartbeat_at"`
+	Creator     string `json:"creator"`
+	Reviser     string `json:"reviser"`
+	CreatedAt   string `json:"created_at"`
+	UpdatedAt   string `json:"updated_at"`
 }
 
 // CreateValidate Task create validate.
@@ -25,17 +25,17 @@ import (
 
 	"github.com/jmoiron/sqlx"
 
-	"hcm/pkg/async/action"
+	"hcm/pkg/api/core"
 	"hcm/pkg/async/backend/model"
 	"hcm/pkg/criteria/enumor"
 	"hcm/pkg/dal/dao"
 	"hcm/pkg/dal/dao/orm"
+	"hcm/pkg/dal/dao/tools"
 	"hcm/pkg/dal/dao/types"
 	"hcm/pkg/dal/dao/types/async"
 	tableasync "hcm/pkg/dal/table/async"
-	tabletypes "hcm/pkg/dal/table/types"
 	"hcm/pkg/kit"
-	"hcm/pkg/tools/converter"
+	"hcm/pkg/runtime/filter"
 )
 
 // NewMysql create mysql instance
@@ -59,6 +59,13 @@ func (db *mysql) BatchUpdateFlowStateByCAS(kt *kit.Kit, infos []UpdateFlowInfo)
 		}
 	}
 
+	ids := make([]string, 0, len(infos))
+	states := make([]enumor.FlowState, 0, len(infos))
+	for _, one := range infos {
+		ids = append(ids, one.ID)
+		states = append(states, one.Target)
+	}
+
 	_, err := db.dao.Txn().AutoTxn(kt, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
 		for _, one := range infos {
 			info := &typesasync.UpdateFlowInfo{
@@ -73,6 +80,12 @@ func (db *mysql) BatchUpdateFlowStateByCAS(kt *kit.Kit, infos []UpdateFlowInfo)
 			}
 		}
 
+		// 生成任务流状态变更事件
+		if _, err := db.dao.AsyncFlowEvent().BatchCreateWithTx(kt, txn, flowStateEventTables(ids,
+			states)); err != nil {
+			return nil, err
+		}
+
 		return nil, nil
 	})
 	if err != nil {
@@ -82,6 +95,47 @@ func (db *mysql) BatchUpdateFlowStateByCAS(kt *kit.Kit, infos []UpdateFlowInfo)
 	return nil
 }
 
+// RestartFlow 在同一事务中CAS重置任务流和任务状态
+func (db *mysql) RestartFlow(kt *kit.Kit, info *RestartFlowInfo) error {
+	if err := info.Validate(); err != nil {
+		return err
+	}
+
+	_, err := db.dao.Txn().AutoTxn(kt, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		flowInfo := &typesasync.UpdateFlowInfo{
+			ID:     info.ID,
+			Source: info.Source,
+			Target: enumor.FlowPending,
+			Reason: new(tableasync.Reason),
+		}
+		if err := db.dao.AsyncFlow().UpdateStateByCAS(kt, txn, flowInfo); err != nil {
+			return nil, err
+		}
+
+		expr := tools.EqualWithOpExpression(filter.And, map[string]interface{}{
+			"flow_id": info.ID,
+			"state":   info.TaskSource,
+		})
+		md := &tableasync.AsyncFlowTaskTable{
+			State:   enumor.TaskPending,
+			Reason:  new(tableasync.Reason),
+			Reviser: kt.User,
+		}
+		if err := db.dao.AsyncFlowTask().UpdateWithTx(kt, txn, expr, md); err != nil {
+			return nil, err
+		}
+
+		// 生成任务流状态变更事件
+		events := flowStateEventTables([]string{info.ID}, []enumor.FlowState{enumor.FlowPending})
+		if _, err := db.dao.AsyncFlowEvent().BatchCreateWithTx(kt, txn, events); err != nil {
+			return nil, err
+		}
+
+		return nil, nil
+	})
+	return err
+}
+
 // UpdateTaskStateByCAS CAS更新任务状态
 func (db *mysql) UpdateTaskStateByCAS(kt *kit.Kit, info *UpdateTaskInfo) error {
 	update := &typesasync.UpdateTaskInfo{
@@ -98,49 +152,103 @@ var _ Backend = new(mysql)
 // CreateFlow 创建任务流
 func (db *mysql) CreateFlow(kt *kit.Kit, flow *model.Flow) (string, error) {
 
+	md, err := newFlowTable(kt, flow)
+	if err != nil {
+		return "", err
+	}
+
+	result, err := db.dao.Txn().AutoTxn(kt, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		return db.createFlowWithTx(kt, txn, md, flow.Tasks)
+	})
+	if err != nil {
+		return "", err
+	}
+
+	flowID, ok := result.(string)
+	if !ok {
+		return "", fmt.Errorf("return result not string type, type: %s", reflect.TypeOf(result).String())
+	}
+
+	return flowID, nil
+}
+
+// createFlowWithTx 在事务中创建任务流、任务及任务流创建事件
+func (db *mysql) createFlowWithTx(kt *kit.Kit, txn *sqlx.Tx, md *tableasync.AsyncFlowTable,
+	tasks []model.Task) (string, error) {
+
+	// 创建任务流
+	flowID, err := db.dao.AsyncFlow().Create(kt, txn, md)
+	if err != nil {
+		return "", err
+	}
+
+	// 创建任务
+	mds := newFlowTaskTables(kt, flowID, tasks)
+	if _, err = db.dao.AsyncFlowTask().BatchCreateWithTx(kt, txn, mds); err != nil {
+		return "", err
+	}
+
+	// 生成任务流创建事件
+	events := []tableasync.AsyncFlowEventTable{newFlowEventTable(flowID, enumor.FlowEventCreated)}
+	if _, err = db.dao.AsyncFlowEvent().BatchCreateWithTx(kt, txn, events); err != nil {
+		return "", err
+	}
+
+	return flowID, nil
+}
+
+// CreateFlowIdempotently 按照幂等键创建任务流
+func (db *mysql) CreateFlowIdempotently(kt *kit.Kit, flow *model.Flow, idem *model.FlowIdempotency) (string,
+	error) {
+
+	idemMd, err := newFlowIdempotencyTable(idem)
+	if err != nil {
+		return "", err
+	}
+
+	md, err := newFlowTable(kt, flow)
+	if err != nil {
+		return "", err
+	}
+
+	keyExpr := flowIdempotencyExpr(idem.AppCode, idem.IdempotencyKey)
 	result, err := db.dao.Txn().AutoTxn(kt, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
-		// 创建任务流
-		md := &tableasync.AsyncFlowTable{
-			Name:      flow.Name,
-			State:     enumor.FlowPending,
-			Reason:    new(tableasync.Reason),
-			ShareData: flow.ShareData,
-			Memo:      flow.Memo,
-			Worker:    converter.ValToPtr(""),
-			Creator:   kt.User,
-			Reviser:   kt.User,
-		}
-		flowID, err := db.dao.AsyncFlow().Create(kt, txn, md)
+		// 过期的幂等键可以被重新使用
+		err := db.dao.AsyncFlowIdempotency().DeleteWithTx(kt, txn, expiredFlowIdempotencyExpr(keyExpr))
+		if err != nil {
+			return nil, err
+		}
+
+		listOpt := &types.ListOption{Filter: keyExpr, Page: core.NewDefaultBasePage()}
+		list, err := db.dao.AsyncFlowIdempotency().ListWithTx(kt, txn, listOpt)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(list.Details) != 0 {
+			return idempotentFlowID(list.Details[0], idem.PayloadHash)
+		}
+
+		flowID, err := db.createFlowWithTx(kt, txn, md, flow.Tasks)
 		if err != nil {
 			return nil, err
 		}
 
-		// 创建任务
-		tasks := flow.Tasks
-		mds := make([]tableasync.AsyncFlowTaskTable, 0, len(tasks))
-		for _, one := range tasks {
-			mds = append(mds, tableasync.AsyncFlowTaskTable{
-				FlowID:     flowID,
-				FlowName:   one.FlowName,
-				ActionID:   string(one.ActionID),
-				ActionName: one.ActionName,
-				Params:     one.Params,
-				Retry:      one.Retry,
-				DependOn:   dependOnToStringArray(one.DependOn),
-				State:      enumor.TaskPending,
-				Reason:     new(tableasync.Reason),
-				Creator:    kt.User,
-				Reviser:    kt.User,
-			})
-		}
-		if _, err = db.dao.AsyncFlowTask().BatchCreateWithTx(kt, txn, mds); err != nil {
+		// 并发使用相同幂等键创建任务流时，只有一个请求可以写入幂等键，其余请求的事务会因唯一索引冲突回滚
+		idemMd.ID = flowID
+		if err = db.dao.AsyncFlowIdempotency().CreateWithTx(kt, txn, idemMd); err != nil {
 			return nil, err
 		}
 
 		return flowID, nil
 	})
 	if err != nil {
-		return "", err
+		// 唯一索引冲突时，返回并发请求创建的任务流
+		flowID, getErr := db.getIdempotentFlowID(kt, keyExpr, idem.PayloadHash)
+		if getErr != nil || len(flowID) == 0 {
+			return "", err
+		}
+		return flowID, nil
 	}
 
 	flowID, ok := result.(string)
@@ -151,25 +259,49 @@ func (db *mysql) CreateFlow(kt *kit.Kit, flow *model.Flow) (string, error) {
 	return flowID, nil
 }
 
+// getIdempotentFlowID 查询幂等键对应的任务流ID，幂等键不存在时返回空
+func (db *mysql) getIdempotentFlowID(kt *kit.Kit, keyExpr *filter.Expression, hash string) (string, error) {
+	listOpt := &types.ListOption{Filter: keyExpr, Page: core.NewDefaultBasePage()}
+	list, err := db.dao.AsyncFlowIdempotency().List(kt, listOpt)
+	if err != nil {
+		return "", err
+	}
+
+	if len(list.Details) == 0 {
+		return "", nil
+	}
+
+	return idempotentFlowID(list.Details[0], hash)
+}
+
+// DeleteExpiredFlowIdempotency 删除已过期的幂等键
+func (db *mysql) DeleteExpiredFlowIdempotency(kt *kit.Kit) error {
+	return db.dao.AsyncFlowIdempotency().Delete(kt, expiredFlowIdempotencyExpr(nil))
+}
+
 // BatchUpdateFlow 批量更新任务流
 func (db *mysql) BatchUpdateFlow(kt *kit.Kit, flows []model.Flow) error {
 
+	ids := make([]string, 0, len(flows))
+	states := make([]enumor.FlowState, 0, len(flows))
+	for _, one := range flows {
+		ids = append(ids, one.ID)
+		states = append(states, one.State)
+	}
+
 	_, err := db.dao.Txn().AutoTxn(kt, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
 		for _, one := range flows {
-			md := &tableasync.AsyncFlowTable{
-				State:     one.State,
-				Reason:    one.Reason,
-				ShareData: one.ShareData,
-				Memo:      one.Memo,
-				Worker:    one.Worker,
-				Reviser:   one.Reviser,
-			}
-
-			if err := db.dao.AsyncFlow().UpdateByIDWithTx(kt, txn, one.ID, md); err != nil {
+			if err := db.dao.AsyncFlow().UpdateByIDWithTx(kt, txn, one.ID, flowUpdateTable(one)); err != nil {
 				return nil, err
 			}
 		}
 
+		// 生成任务流状态变更事件
+		if _, err := db.dao.AsyncFlowEvent().BatchCreateWithTx(kt, txn, flowStateEventTables(ids,
+			states)); err != nil {
+			return nil, err
+		}
+
 		return nil, nil
 	})
 	if err != nil {
@@ -194,19 +326,7 @@ func (db *mysql) ListFlow(kt *kit.Kit, input *ListInput) ([]model.Flow, error) {
 
 	flows := make([]model.Flow, 0, len(list.Details))
 	for _, one := range list.Details {
-		flows = append(flows, model.Flow{
-			ID:        one.ID,
-			Name:      one.Name,
-			State:     one.State,
-			Reason:    one.Reason,
-			ShareData: one.ShareData,
-			Memo:      one.Memo,
-			Worker:    one.Worker,
-			Creator:   one.Creator,
-			Reviser:   one.Reviser,
-			CreatedAt: one.CreatedAt.String(),
-			UpdatedAt: one.UpdatedAt.String(),
-		})
+		flows = append(flows, flowTableToModel(one))
 	}
 
 	return flows, nil
@@ -215,36 +335,16 @@ func (db *mysql) ListFlow(kt *kit.Kit, input *ListInput) ([]model.Flow, error) {
 // BatchCreateTask 批量创建任务
 func (db *mysql) BatchCreateTask(kt *kit.Kit, tasks []model.Task) ([]string, error) {
 
-	mds := make([]tableasync.AsyncFlowTaskTable, 0, len(tasks))
-	for _, one := range tasks {
-		mds = append(mds, tableasync.AsyncFlowTaskTable{
-			FlowID:     one.FlowID,
-			FlowName:   one.FlowName,
-			ActionID:   string(one.ActionID),
-			ActionName: one.ActionName,
-			Params:     one.Params,
-			Retry:      one.Retry,
-			DependOn:   dependOnToStringArray(one.DependOn),
-			State:      enumor.TaskPending,
-			Reason:     one.Reason,
-			Creator:    one.Creator,
-			Reviser:    one.Reviser,
-		})
-	}
-
+	mds := newTaskTables(tasks)
 	return db.dao.AsyncFlowTask().BatchCreate(kt, mds)
 }
 
 // UpdateTask 更新任务
 func (db *mysql) UpdateTask(kt *kit.Kit, task *model.Task) error {
 
-	md := &tableasync.AsyncFlowTaskTable{
-		Retry:    task.Retry,
-		DependOn: dependOnToStringArray(task.DependOn),
-		State:    task.State,
-		Result:   task.Result,
-		Reason:   task.Reason,
-		Reviser:  kt.User,
+	md, err := taskUpdateTable(kt, task)
+	if err != nil {
+		return err
 	}
 
 	return db.dao.AsyncFlowTask().UpdateByID(kt, task.ID, md)
@@ -265,42 +365,277 @@ func (db *mysql) ListTask(kt *kit.Kit, input *ListInput) ([]model.Task, error) {
 
 	tasks := make([]model.Task, 0, len(list.Details))
 	for _, one := range list.Details {
-		tasks = append(tasks, model.Task{
-			ID:         one.ID,
-			FlowID:     one.FlowID,
-			FlowName:   one.FlowName,
-			ActionID:   action.ActIDType(one.ActionID),
-			ActionName: one.ActionName,
-			Params:     one.Params,
-			Retry:      one.Retry,
-			DependOn:   dependOnToActIDArray(one.DependOn),
-			State:      one.State,
-			Reason:     one.Reason,
-			Result:     one.Result,
-			Creator:    one.Creator,
-			Reviser:    one.Reviser,
-			CreatedAt:  one.CreatedAt.String(),
-			UpdatedAt:  one.UpdatedAt.String(),
-		})
+		tasks = append(tasks, taskTableToModel(one))
 	}
 
 	return tasks, nil
 }
 
-func dependOnToStringArray(d []action.ActIDType) tabletypes.StringArray {
-	result := make(tabletypes.StringArray, 0, len(d))
-	for _, one := range d {
-		result = append(result, string(one))
+// CreateFlowSchedule 创建定时任务流
+func (db *mysql) CreateFlowSchedule(kt *kit.Kit, schedule *model.FlowSchedule) (string, error) {
+
+	md, err := newFlowScheduleTable(kt, schedule)
+	if err != nil {
+		return "", err
+	}
+
+	return db.dao.AsyncFlowSchedule().Create(kt, md)
+}
+
+// UpdateFlowSchedule 更新定时任务流
+func (db *mysql) UpdateFlowSchedule(kt *kit.Kit, schedule *model.FlowSchedule) error {
+
+	md, err := flowScheduleUpdateTable(kt, schedule)
+	if err != nil {
+		return err
+	}
+
+	return db.dao.AsyncFlowSchedule().UpdateByID(kt, schedule.ID, md)
+}
+
+// UpdateFlowScheduleNextRunByCAS CAS更新处于启用状态的定时任务流的下次执行时间
+func (db *mysql) UpdateFlowScheduleNextRunByCAS(kt *kit.Kit, info *UpdateScheduleNextRunInfo) error {
+
+	update := &typesasync.UpdateScheduleNextRunInfo{
+		ID:     info.ID,
+		Source: info.Source,
+		Target: info.Target,
+		Pause:  info.Pause,
+	}
+	return db.dao.AsyncFlowSchedule().UpdateNextRunByCAS(kt, update)
+}
+
+// ListFlowSchedule 查询定时任务流
+func (db *mysql) ListFlowSchedule(kt *kit.Kit, input *ListInput) ([]model.FlowSchedule, error) {
+
+	opt := &types.ListOption{
+		Fields: input.Fields,
+		Filter: input.Filter,
+		Page:   input.Page,
+	}
+	list, err := db.dao.AsyncFlowSchedule().List(kt, opt)
+	if err != nil {
+		return nil, err
+	}
+
+	schedules := make([]model.FlowSchedule, 0, len(list.Details))
+	for _, one := range list.Details {
+		schedules = append(schedules, flowScheduleTableToModel(one))
 	}
 
-	return result
+	return schedules, nil
 }
 
-func dependOnToActIDArray(d tabletypes.StringArray) []action.ActIDType {
-	result := make([]action.ActIDType, 0, len(d))
-	for _, one := range d {
-		result = append(result, action.ActIDType(one))
+// DeleteFlowSchedule 删除定时任务流及其执行记录，已生成的任务流不受影响
+func (db *mysql) DeleteFlowSchedule(kt *kit.Kit, id string) error {
+
+	_, err := db.dao.Txn().AutoTxn(kt, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		if err := db.dao.AsyncFlowScheduleRun().DeleteWithTx(kt, txn,
+			tools.EqualExpression("schedule_id", id)); err != nil {
+			return nil, err
+		}
+
+		if err := db.dao.AsyncFlowSchedule().DeleteWithTx(kt, txn, tools.EqualExpression("id", id)); err != nil {
+			return nil, err
+		}
+
+		return nil, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// BatchCreateFlowScheduleRun 批量创建定时任务流执行记录
+func (db *mysql) BatchCreateFlowScheduleRun(kt *kit.Kit, runs []model.FlowScheduleRun) error {
+
+	mds, err := newFlowScheduleRunTables(kt, runs)
+	if err != nil {
+		return err
+	}
+
+	_, err = db.dao.AsyncFlowScheduleRun().BatchCreate(kt, mds)
+	return err
+}
+
+// ListFlowScheduleRun 查询定时任务流执行记录
+func (db *mysql) ListFlowScheduleRun(kt *kit.Kit, input *ListInput) ([]model.FlowScheduleRun, error) {
+
+	opt := &types.ListOption{
+		Fields: input.Fields,
+		Filter: input.Filter,
+		Page:   input.Page,
+	}
+	list, err := db.dao.AsyncFlowScheduleRun().List(kt, opt)
+	if err != nil {
+		return nil, err
+	}
+
+	runs := make([]model.FlowScheduleRun, 0, len(list.Details))
+	for _, one := range list.Details {
+		runs = append(runs, flowScheduleRunTableToModel(one))
+	}
+
+	return runs, nil
+}
+
+// ListFlowEvent 查询任务流事件
+func (db *mysql) ListFlowEvent(kt *kit.Kit, input *ListInput) ([]model.FlowEvent, error) {
+
+	opt := &types.ListOption{
+		Fields: input.Fields,
+		Filter: input.Filter,
+		Page:   input.Page,
+	}
+	list, err := db.dao.AsyncFlowEvent().List(kt, opt)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]model.FlowEvent, 0, len(list.Details))
+	for _, one := range list.Details {
+		events = append(events, flowEventTableToModel(one))
+	}
+
+	return events, nil
+}
+
+// UpdateFlowEvent 更新任务流事件的投递结果
+func (db *mysql) UpdateFlowEvent(kt *kit.Kit, event *model.FlowEvent) error {
+
+	md, err := flowEventUpdateTable(event)
+	if err != nil {
+		return err
+	}
+
+	return db.dao.AsyncFlowEvent().UpdateByID(kt, event.ID, md)
+}
+
+// ArchiveFlow 归档任务流
+func (db *mysql) ArchiveFlow(kt *kit.Kit, ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	if err := validateArchiveIDs(ids); err != nil {
+		return err
+	}
+
+	_, err := db.dao.Txn().AutoTxn(kt, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		listOpt := &types.ListOption{Filter: archivableFlowExpr(ids), Page: core.NewDefaultBasePage()}
+		flows, err := db.dao.AsyncFlow().ListWithTx(kt, txn, listOpt)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(flows.Details) == 0 {
+			return nil, nil
+		}
+
+		flowIDs := make([]string, 0, len(flows.Details))
+		for _, one := range flows.Details {
+			flowIDs = append(flowIDs, one.ID)
+		}
+
+		// 任务流已结束，任务和事件不会再变更，可以在事务外分页查询
+		tasks, err := db.listAllFlowTasks(kt, flowIDs)
+		if err != nil {
+			return nil, err
+		}
+
+		events, err := db.listAllFlowEvents(kt, flowIDs)
+		if err != nil {
+			return nil, err
+		}
+
+		mds, err := newFlowArchiveTables(flows.Details, tasks, events)
+		if err != nil {
+			return nil, err
+		}
+
+		if err = db.dao.AsyncFlowArchive().BatchCreateWithTx(kt, txn, mds); err != nil {
+			return nil, err
+		}
+
+		flowIDExpr := tools.ContainersExpression("flow_id", flowIDs)
+		if err = db.dao.AsyncFlowEvent().DeleteWithTx(kt, txn, flowIDExpr); err != nil {
+			return nil, err
+		}
+
+		if err = db.dao.AsyncFlowTask().DeleteWithTx(kt, txn, flowIDExpr); err != nil {
+			return nil, err
+		}
+
+		if err = db.dao.AsyncFlow().DeleteWithTx(kt, txn, tools.ContainersExpression("id", flowIDs)); err != nil {
+			return nil, err
+		}
+
+		return nil, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// listAllFlowTasks 分页查询任务流的全部任务
+func (db *mysql) listAllFlowTasks(kt *kit.Kit, flowIDs []string) ([]tableasync.AsyncFlowTaskTable, error) {
+	result := make([]tableasync.AsyncFlowTaskTable, 0)
+	page := core.NewDefaultBasePage()
+	for {
+		opt := &types.ListOption{Filter: tools.ContainersExpression("flow_id", flowIDs), Page: page}
+		list, err := db.dao.AsyncFlowTask().List(kt, opt)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, list.Details...)
+		if uint(len(list.Details)) < page.Limit {
+			return result, nil
+		}
+		page.Start += uint32(page.Limit)
+	}
+}
+
+// listAllFlowEvents 分页查询任务流的全部事件
+func (db *mysql) listAllFlowEvents(kt *kit.Kit, flowIDs []string) ([]tableasync.AsyncFlowEventTable, error) {
+	result := make([]tableasync.AsyncFlowEventTable, 0)
+	page := core.NewDefaultBasePage()
+	for {
+		opt := &types.ListOption{Filter: tools.ContainersExpression("flow_id", flowIDs), Page: page}
+		list, err := db.dao.AsyncFlowEvent().List(kt, opt)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, list.Details...)
+		if uint(len(list.Details)) < page.Limit {
+			return result, nil
+		}
+		page.Start += uint32(page.Limit)
+	}
+}
+
+// ListFlowArchive 查询已归档的任务流
+func (db *mysql) ListFlowArchive(kt *kit.Kit, input *ListInput) ([]model.FlowArchive, error) {
+
+	opt := &types.ListOption{
+		Fields: input.Fields,
+		Filter: input.Filter,
+		Page:   input.Page,
+	}
+	list, err := db.dao.AsyncFlowArchive().List(kt, opt)
+	if err != nil {
+		return nil, err
+	}
+
+	archives := make([]model.FlowArchive, 0, len(list.Details))
+	for _, one := range list.Details {
+		archives = append(archives, flowArchiveTableToModel(one))
 	}
 
-	return result
+	return archives, nil
 }
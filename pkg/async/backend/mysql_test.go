@@ -0,0 +1,74 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package backend_test
+
+import (
+	"os"
+	"testing"
+
+	"hcm/pkg/async/backend"
+	"hcm/pkg/async/backend/backendtest"
+	"hcm/pkg/cc"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/dal/dao"
+)
+
+// TestMysqlConformance 需要通过环境变量 HCM_ASYNC_MYSQL_ENDPOINT 指定已初始化 hcm 表结构的 mysql，未指定时跳过。
+func TestMysqlConformance(t *testing.T) {
+	endpoint := os.Getenv("HCM_ASYNC_MYSQL_ENDPOINT")
+	if len(endpoint) == 0 {
+		t.Skip("HCM_ASYNC_MYSQL_ENDPOINT is not set, skip mysql backend conformance test")
+	}
+
+	opt := cc.DataBase{
+		Resource: cc.ResourceDB{
+			Endpoints:       []string{endpoint},
+			Database:        envOrDefault("HCM_ASYNC_MYSQL_DATABASE", "hcm"),
+			User:            envOrDefault("HCM_ASYNC_MYSQL_USER", "root"),
+			Password:        os.Getenv("HCM_ASYNC_MYSQL_PASSWORD"),
+			DialTimeoutSec:  15,
+			ReadTimeoutSec:  10,
+			WriteTimeoutSec: 10,
+			MaxOpenConn:     20,
+			MaxIdleConn:     5,
+		},
+		Limiter: &cc.Limiter{QPS: 500, Burst: 500},
+	}
+	daoSet, err := dao.NewDaoSet(opt)
+	if err != nil {
+		t.Fatalf("create dao set failed, err: %v", err)
+	}
+
+	backendtest.Run(t, func(t *testing.T) backend.Backend {
+		bd, err := backend.Factory(enumor.BackendMysql, daoSet)
+		if err != nil {
+			t.Fatalf("create mysql backend failed, err: %v", err)
+		}
+		return bd
+	})
+}
+
+func envOrDefault(key, def string) string {
+	if val := os.Getenv(key); len(val) != 0 {
+		return val
+	}
+
+	return def
+}
@@ -0,0 +1,1301 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package backend
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"hcm/pkg/async/backend/model"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/dal/dao/types"
+	"hcm/pkg/dal/table"
+	tableasync "hcm/pkg/dal/table/async"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/tools/times"
+)
+
+// sqliteSchema sqlite 后端使用的表结构，与 mysql 中 async 相关表字段保持一致。
+var sqliteSchema = []string{
+	`create table if not exists async_flow
+(
+    id              varchar(64) not null primary key,
+    name            varchar(64) not null,
+    state           varchar(16) not null,
+    reason          text        default null,
+    share_data      text        default null,
+    memo            varchar(64) not null,
+    worker          varchar(64) not null,
+    run_at          datetime    not null,
+    rollback_policy varchar(16) not null,
+    priority        varchar(16) not null,
+    tenant          varchar(64) not null,
+    app_code        varchar(64) not null,
+    webhooks        text        default null,
+    creator         varchar(64) not null,
+    reviser         varchar(64) not null,
+    created_at      datetime    not null,
+    updated_at      datetime    not null
+)`,
+	`create table if not exists async_flow_task
+(
+    id          varchar(64) not null primary key,
+    flow_id     varchar(64) not null,
+    flow_name   varchar(64) not null,
+    action_id   varchar(64) not null,
+    action_name varchar(64) not null,
+    params      text                 default null,
+    retry       text        not null,
+    depend_on   varchar(64)          default '',
+    state       varchar(32) not null,
+    reason      text                 default null,
+    result      text                 default null,
+    stat        text                 default null,
+    progress    text                 default null,
+    heartbeat_at datetime    not null,
+    creator     varchar(64) not null,
+    reviser     varchar(64) not null,
+    created_at  datetime    not null,
+    updated_at  datetime    not null
+)`,
+	`create index if not exists idx_async_flow_task_flow_id on async_flow_task (flow_id)`,
+	`create index if not exists idx_async_flow_task_state_heartbeat_at on async_flow_task (state, heartbeat_at)`,
+	`create index if not exists idx_async_flow_state_run_at on async_flow (state, run_at)`,
+	`create index if not exists idx_async_flow_state_priority on async_flow (state, priority)`,
+	`create table if not exists async_flow_schedule
+(
+    id            varchar(64)  not null primary key,
+    name          varchar(64)  not null unique,
+    cron          varchar(64)  not null,
+    state         varchar(16)  not null,
+    missed_policy varchar(16)  not null,
+    flow          text         not null,
+    next_run_at   datetime     not null,
+    memo          varchar(255) not null,
+    creator       varchar(64)  not null,
+    reviser       varchar(64)  not null,
+    created_at    datetime     not null,
+    updated_at    datetime     not null
+)`,
+	`create index if not exists idx_async_flow_schedule_state_next_run_at on async_flow_schedule (state, next_run_at)`,
+	`create table if not exists async_flow_schedule_run
+(
+    id          varchar(64) not null primary key,
+    schedule_id varchar(64) not null,
+    plan_at     datetime    not null,
+    state       varchar(16) not null,
+    flow_id     varchar(64) not null,
+    reason      text                 default null,
+    creator     varchar(64) not null,
+    created_at  datetime    not null
+)`,
+	`create index if not exists idx_async_flow_schedule_run_schedule_id on async_flow_schedule_run (schedule_id)`,
+	`create table if not exists async_flow_event
+(
+    id              varchar(64) not null primary key,
+    flow_id         varchar(64) not null,
+    event           varchar(16) not null,
+    state           varchar(16) not null,
+    attempts        integer     not null default 0,
+    next_attempt_at datetime    not null,
+    deliveries      text                 default null,
+    created_at      datetime    not null,
+    updated_at      datetime    not null
+)`,
+	`create index if not exists idx_async_flow_event_flow_id on async_flow_event (flow_id)`,
+	`create index if not exists idx_async_flow_event_state_next_attempt_at on async_flow_event (state, next_attempt_at)`,
+	`create index if not exists idx_async_flow_state_updated_at on async_flow (state, updated_at)`,
+	`create table if not exists async_flow_archive
+(
+    id              varchar(64) not null primary key,
+    name            varchar(64) not null,
+    state           varchar(16) not null,
+    tenant          varchar(64) not null,
+    app_code        varchar(64) not null,
+    detail          text        not null,
+    flow_created_at datetime    not null,
+    finished_at     datetime    not null,
+    archived_at     datetime    not null
+)`,
+	`create index if not exists idx_async_flow_archive_name_finished_at on async_flow_archive (name, finished_at)`,
+	`create index if not exists idx_async_flow_archive_archived_at on async_flow_archive (archived_at)`,
+	`create table if not exists async_flow_idempotency
+(
+    id              varchar(64)  not null primary key,
+    app_code        varchar(64)  not null,
+    idempotency_key varchar(128) not null,
+    payload_hash    varchar(64)  not null,
+    expired_at      datetime     not null,
+    created_at      datetime     not null default current_timestamp,
+    unique (app_code, idempotency_key)
+)`,
+	`create index if not exists idx_async_flow_idempotency_expired_at on async_flow_idempotency (expired_at)`,
+	`create table if not exists id_generator
+(
+    resource varchar(64) not null primary key,
+    max_id   integer     not null default 0
+)`,
+}
+
+// NewSqlite create sqlite instance. db must be opened with a registered sqlite driver by caller,
+// such as github.com/mattn/go-sqlite3, and the async tables will be created if not exist.
+func NewSqlite(db *sqlx.DB) (Backend, error) {
+	if db == nil {
+		return nil, errors.New("sqlite db is nil")
+	}
+
+	// sqlite 同一时间只允许一个写事务，限制为单连接，避免并发写时出现 database is locked 错误
+	db.SetMaxOpenConns(1)
+
+	for _, one := range sqliteSchema {
+		if _, err := db.Exec(one); err != nil {
+			return nil, fmt.Errorf("init sqlite async schema failed, err: %v", err)
+		}
+	}
+
+	return &sqlite{db: db}, nil
+}
+
+// sqlite sqlite backend
+type sqlite struct {
+	db *sqlx.DB
+}
+
+var _ Backend = new(sqlite)
+
+// autoTxn 在事务中执行 do，do 返回错误时回滚事务
+func (db *sqlite) autoTxn(kt *kit.Kit, do func(tx *sqlx.Tx) error) error {
+	tx, err := db.db.BeginTxx(kt.Ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin sqlite transaction failed, err: %v", err)
+	}
+
+	if err = do(tx); err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			logs.Errorf("rollback sqlite transaction failed, err: %v, rid: %s", rbErr, kt.Rid)
+		}
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("commit sqlite transaction failed, err: %v", err)
+	}
+
+	return nil
+}
+
+// genIDs 按照资源生成 id，与 mysql 后端 id_generator 的使用方式保持一致。
+func (db *sqlite) genIDs(kt *kit.Kit, tx *sqlx.Tx, name table.Name, num int) ([]string, error) {
+	resource := string(name)
+	if _, err := tx.ExecContext(kt.Ctx, `insert or ignore into id_generator (resource, max_id) values (?, 0)`,
+		resource); err != nil {
+		return nil, err
+	}
+
+	if _, err := tx.ExecContext(kt.Ctx, `update id_generator set max_id = max_id + ? where resource = ?`, num,
+		resource); err != nil {
+		return nil, err
+	}
+
+	var maxID uint64
+	if err := tx.GetContext(kt.Ctx, &maxID, `select max_id from id_generator where resource = ?`,
+		resource); err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, 0, num)
+	for id := maxID - uint64(num) + 1; id <= maxID; id++ {
+		ids = append(ids, formatID(id))
+	}
+
+	return ids, nil
+}
+
+// exec 执行带命名参数的 sql，返回影响行数
+func (db *sqlite) exec(kt *kit.Kit, tx *sqlx.Tx, expr string, arg interface{}) (int64, error) {
+	query, args, err := sqlx.Named(expr, arg)
+	if err != nil {
+		return 0, err
+	}
+
+	query, args, err = sqlx.In(query, args...)
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := tx.ExecContext(kt.Ctx, tx.Rebind(query), args...)
+	if err != nil {
+		logs.Errorf("sqlite exec failed, err: %v, sql: %s, rid: %s", err, expr, kt.Rid)
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
+// selectRows 执行带命名参数的查询
+func (db *sqlite) selectRows(kt *kit.Kit, dest interface{}, expr string, arg interface{}) error {
+	query, args, err := sqlx.Named(expr, arg)
+	if err != nil {
+		return err
+	}
+
+	query, args, err = sqlx.In(query, args...)
+	if err != nil {
+		return err
+	}
+
+	if err = db.db.SelectContext(kt.Ctx, dest, db.db.Rebind(query), args...); err != nil {
+		logs.Errorf("sqlite select failed, err: %v, sql: %s, rid: %s", err, expr, kt.Rid)
+		return err
+	}
+
+	return nil
+}
+
+// txSelectRows 在事务中执行带命名参数的查询
+func (db *sqlite) txSelectRows(kt *kit.Kit, tx *sqlx.Tx, dest interface{}, expr string, arg interface{}) error {
+	query, args, err := sqlx.Named(expr, arg)
+	if err != nil {
+		return err
+	}
+
+	query, args, err = sqlx.In(query, args...)
+	if err != nil {
+		return err
+	}
+
+	if err = tx.SelectContext(kt.Ctx, dest, tx.Rebind(query), args...); err != nil {
+		logs.Errorf("sqlite select failed, err: %v, sql: %s, rid: %s", err, expr, kt.Rid)
+		return err
+	}
+
+	return nil
+}
+
+const insertFlowSql = `insert into async_flow (id, name, state, reason, share_data, memo, worker, run_at,
+rollback_policy, priority, tenant, app_code, webhooks, creator, reviser, created_at, updated_at) values (:id, :name,
+:state, :reason, :share_data, :memo, :worker, :run_at, :rollback_policy, :priority, :tenant, :app_code, :webhooks,
+:creator, :reviser, :created_at, :updated_at)`
+
+const insertTaskSql = `insert into async_flow_task (id, flow_id, flow_name, action_id, action_name, params, retry,
+depend_on, state, reason, result, stat, progress, heartbeat_at, creator, reviser, created_at, updated_at) values
+(:id, :flow_id, :flow_name, :action_id, :action_name, :params, :retry, :depend_on, :state, :reason, :result, :stat,
+:progress, :heartbeat_at, :creator, :reviser, :created_at, :updated_at)`
+
+func flowArgs(md *tableasync.AsyncFlowTable, createdAt, updatedAt time.Time) map[string]interface{} {
+	return map[string]interface{}{
+		"id":              md.ID,
+		"name":            md.Name,
+		"state":           md.State,
+		"reason":          md.Reason,
+		"share_data":      md.ShareData,
+		"memo":            md.Memo,
+		"worker":          md.Worker,
+		"run_at":          sqliteTime(md.RunAt),
+		"rollback_policy": md.RollbackPolicy,
+		"priority":        md.Priority,
+		"tenant":          md.Tenant,
+		"app_code":        md.AppCode,
+		"webhooks":        md.Webhooks,
+		"creator":         md.Creator,
+		"reviser":         md.Reviser,
+		"created_at":      sqliteTime(createdAt),
+		"updated_at":      sqliteTime(updatedAt),
+	}
+}
+
+func taskArgs(md *tableasync.AsyncFlowTaskTable, createdAt, updatedAt time.Time) map[string]interface{} {
+	return map[string]interface{}{
+		"id":           md.ID,
+		"flow_id":      md.FlowID,
+		"flow_name":    md.FlowName,
+		"action_id":    md.ActionID,
+		"action_name":  md.ActionName,
+		"params":       md.Params,
+		"retry":        md.Retry,
+		"depend_on":    md.DependOn,
+		"state":        md.State,
+		"reason":       md.Reason,
+		"result":       md.Result,
+		"stat":         md.Stat,
+		"progress":     md.Progress,
+		"heartbeat_at": sqliteTime(md.HeartbeatAt),
+		"creator":      md.Creator,
+		"reviser":      md.Reviser,
+		"created_at":   sqliteTime(createdAt),
+		"updated_at":   sqliteTime(updatedAt),
+	}
+}
+
+// sqliteNow sqlite 中时间按照秒级精度存储，与 mysql timestamp 保持一致
+func sqliteNow() time.Time {
+	return time.Now().In(time.Local).Truncate(time.Second)
+}
+
+// sqliteTime 时间统一转换为本地时区后存储，sqlite 按照字符串比较时间，时区不一致时比较结果与 mysql 不同
+func sqliteTime(t time.Time) time.Time {
+	return t.In(time.Local)
+}
+
+// CreateFlow 创建任务流
+func (db *sqlite) CreateFlow(kt *kit.Kit, flow *model.Flow) (string, error) {
+	md, err := newFlowTable(kt, flow)
+	if err != nil {
+		return "", err
+	}
+
+	err = db.autoTxn(kt, func(tx *sqlx.Tx) error {
+		return db.createFlowWithTx(kt, tx, md, flow.Tasks)
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return md.ID, nil
+}
+
+// createFlowWithTx 在事务中创建任务流、任务及任务流创建事件，任务流ID回填到 md 中
+func (db *sqlite) createFlowWithTx(kt *kit.Kit, tx *sqlx.Tx, md *tableasync.AsyncFlowTable,
+	tasks []model.Task) error {
+
+	mds := newFlowTaskTables(kt, "", tasks)
+	ids, err := db.genIDs(kt, tx, table.AsyncFlowTable, len(mds)+1)
+	if err != nil {
+		return err
+	}
+
+	now := sqliteNow()
+	md.ID = ids[0]
+	if err = md.InsertValidate(); err != nil {
+		return err
+	}
+
+	if _, err = db.exec(kt, tx, insertFlowSql, flowArgs(md, now, now)); err != nil {
+		return fmt.Errorf("insert %s failed, err: %v", table.AsyncFlowTable, err)
+	}
+
+	for index := range mds {
+		mds[index].ID = ids[index+1]
+		mds[index].FlowID = md.ID
+		if err = mds[index].InsertValidate(); err != nil {
+			return err
+		}
+
+		if _, err = db.exec(kt, tx, insertTaskSql, taskArgs(&mds[index], now, now)); err != nil {
+			return fmt.Errorf("insert %s failed, err: %v", table.AsyncFlowTaskTable, err)
+		}
+	}
+
+	// 生成任务流创建事件
+	events := []tableasync.AsyncFlowEventTable{newFlowEventTable(md.ID, enumor.FlowEventCreated)}
+	return db.insertFlowEvents(kt, tx, events)
+}
+
+const insertFlowIdempotencySql = `insert into async_flow_idempotency (id, app_code, idempotency_key, payload_hash,
+expired_at, created_at) values (:id, :app_code, :idempotency_key, :payload_hash, :expired_at, :created_at)`
+
+// CreateFlowIdempotently 按照幂等键创建任务流
+func (db *sqlite) CreateFlowIdempotently(kt *kit.Kit, flow *model.Flow, idem *model.FlowIdempotency) (string,
+	error) {
+
+	idemMd, err := newFlowIdempotencyTable(idem)
+	if err != nil {
+		return "", err
+	}
+
+	md, err := newFlowTable(kt, flow)
+	if err != nil {
+		return "", err
+	}
+
+	var flowID string
+	err = db.autoTxn(kt, func(tx *sqlx.Tx) error {
+		now := sqliteNow()
+		arg := map[string]interface{}{"app_code": idem.AppCode, "idempotency_key": idem.IdempotencyKey, "now": now}
+
+		// 过期的幂等键可以被重新使用
+		if _, err := db.exec(kt, tx, `delete from async_flow_idempotency where app_code = :app_code and
+idempotency_key = :idempotency_key and expired_at <= :now`, arg); err != nil {
+			return err
+		}
+
+		exists := make([]tableasync.AsyncFlowIdempotencyTable, 0)
+		if err := db.txSelectRows(kt, tx, &exists, `select * from async_flow_idempotency where app_code = :app_code
+and idempotency_key = :idempotency_key`, arg); err != nil {
+			return err
+		}
+
+		if len(exists) != 0 {
+			id, err := idempotentFlowID(exists[0], idem.PayloadHash)
+			flowID = id
+			return err
+		}
+
+		if err := db.createFlowWithTx(kt, tx, md, flow.Tasks); err != nil {
+			return err
+		}
+
+		idemMd.ID = md.ID
+		if err := idemMd.InsertValidate(); err != nil {
+			return err
+		}
+
+		idemArg := map[string]interface{}{
+			"id":              idemMd.ID,
+			"app_code":        idemMd.AppCode,
+			"idempotency_key": idemMd.IdempotencyKey,
+			"payload_hash":    idemMd.PayloadHash,
+			"expired_at":      sqliteTime(idemMd.ExpiredAt),
+			"created_at":      now,
+		}
+		if _, err := db.exec(kt, tx, insertFlowIdempotencySql, idemArg); err != nil {
+			return fmt.Errorf("insert %s failed, err: %v", table.AsyncFlowIdempotencyTable, err)
+		}
+
+		flowID = md.ID
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return flowID, nil
+}
+
+// DeleteExpiredFlowIdempotency 删除已过期的幂等键
+func (db *sqlite) DeleteExpiredFlowIdempotency(kt *kit.Kit) error {
+	return db.autoTxn(kt, func(tx *sqlx.Tx) error {
+		_, err := db.exec(kt, tx, "delete from async_flow_idempotency where expired_at <= :now",
+			map[string]interface{}{"now": sqliteNow()})
+		return err
+	})
+}
+
+// BatchUpdateFlow 批量更新任务流
+func (db *sqlite) BatchUpdateFlow(kt *kit.Kit, flows []model.Flow) error {
+	return db.autoTxn(kt, func(tx *sqlx.Tx) error {
+		ids := make([]string, 0, len(flows))
+		states := make([]enumor.FlowState, 0, len(flows))
+		for _, one := range flows {
+			if len(one.ID) == 0 {
+				return errf.New(errf.InvalidParameter, "id is required")
+			}
+
+			md := flowUpdateTable(one)
+			if err := md.UpdateValidate(); err != nil {
+				return err
+			}
+
+			exist := new(tableasync.AsyncFlowTable)
+			err := tx.GetContext(kt.Ctx, exist, `select * from async_flow where id = ?`, one.ID)
+			if err != nil {
+				if errors.Is(err, sql.ErrNoRows) {
+					return errf.New(errf.RecordNotUpdate, "record not update")
+				}
+				return err
+			}
+
+			mergeFlowUpdate(exist, md)
+			updateSql := `update async_flow set state = :state, reason = :reason, share_data = :share_data,
+memo = :memo, worker = :worker, reviser = :reviser, updated_at = :updated_at where id = :id`
+			if _, err = db.exec(kt, tx, updateSql, flowArgs(exist, time.Time{}, sqliteNow())); err != nil {
+				return err
+			}
+
+			ids = append(ids, one.ID)
+			states = append(states, one.State)
+		}
+
+		// 生成任务流状态变更事件
+		return db.insertFlowEvents(kt, tx, flowStateEventTables(ids, states))
+	})
+}
+
+// ListFlow 查询任务流
+func (db *sqlite) ListFlow(kt *kit.Kit, input *ListInput) ([]model.Flow, error) {
+	if err := validateListInput(input, tableasync.AsyncFlowColumns); err != nil {
+		return nil, err
+	}
+
+	if input.Page.Count {
+		return make([]model.Flow, 0), nil
+	}
+
+	whereExpr, whereValue, err := input.Filter.SQLWhereExpr(tools.DefaultSqlWhereOption)
+	if err != nil {
+		return nil, err
+	}
+
+	pageExpr, err := types.PageSQLExpr(input.Page, types.DefaultPageSQLOption)
+	if err != nil {
+		return nil, err
+	}
+
+	expr := fmt.Sprintf(`select %s from %s %s %s`, tableasync.AsyncFlowColumns.FieldsNamedExpr(input.Fields),
+		table.AsyncFlowTable, whereExpr, pageExpr)
+	details := make([]tableasync.AsyncFlowTable, 0)
+	if err = db.selectRows(kt, &details, expr, whereValue); err != nil {
+		return nil, err
+	}
+
+	flows := make([]model.Flow, 0, len(details))
+	for _, one := range details {
+		flows = append(flows, flowTableToModel(one))
+	}
+
+	return flows, nil
+}
+
+// BatchUpdateFlowStateByCAS CAS批量更新流状态
+func (db *sqlite) BatchUpdateFlowStateByCAS(kt *kit.Kit, infos []UpdateFlowInfo) error {
+	for _, one := range infos {
+		if err := one.Validate(); err != nil {
+			return err
+		}
+	}
+
+	return db.autoTxn(kt, func(tx *sqlx.Tx) error {
+		ids := make([]string, 0, len(infos))
+		states := make([]enumor.FlowState, 0, len(infos))
+		for _, one := range infos {
+			setSql := "set state = :target"
+			if one.ResetWorker {
+				setSql += ", worker = ''"
+			} else if len(one.Worker) != 0 {
+				setSql += ", worker = :worker"
+			}
+
+			if one.Reason != nil {
+				setSql += ", reason = :reason"
+			}
+
+			expr := fmt.Sprintf(`update %s %s, updated_at = :updated_at where id = :id and state = :source`,
+				table.AsyncFlowTable, setSql)
+			values := map[string]interface{}{
+				"id":         one.ID,
+				"source":     one.Source,
+				"target":     one.Target,
+				"worker":     one.Worker,
+				"reason":     one.Reason,
+				"updated_at": sqliteTime(sqliteNow()),
+			}
+			effected, err := db.exec(kt, tx, expr, values)
+			if err != nil {
+				return err
+			}
+
+			if effected == 0 {
+				return errf.Newf(errf.RecordNotUpdate, "flow[%s: %s] update state: %s, worker: %s failed",
+					one.ID, one.Source, one.Target, one.Worker)
+			}
+
+			ids = append(ids, one.ID)
+			states = append(states, one.Target)
+		}
+
+		// 生成任务流状态变更事件
+		return db.insertFlowEvents(kt, tx, flowStateEventTables(ids, states))
+	})
+}
+
+// BatchCreateTask 批量创建任务
+func (db *sqlite) BatchCreateTask(kt *kit.Kit, tasks []model.Task) ([]string, error) {
+	mds := newTaskTables(tasks)
+
+	var ids []string
+	err := db.autoTxn(kt, func(tx *sqlx.Tx) error {
+		var err error
+		ids, err = db.genIDs(kt, tx, table.AsyncFlowTable, len(mds))
+		if err != nil {
+			return err
+		}
+
+		now := sqliteNow()
+		for index := range mds {
+			mds[index].ID = ids[index]
+			if err = mds[index].InsertValidate(); err != nil {
+				return err
+			}
+
+			if _, err = db.exec(kt, tx, insertTaskSql, taskArgs(&mds[index], now, now)); err != nil {
+				return fmt.Errorf("insert %s failed, err: %v", table.AsyncFlowTaskTable, err)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+// UpdateTask 更新任务
+func (db *sqlite) UpdateTask(kt *kit.Kit, task *model.Task) error {
+	if len(task.ID) == 0 {
+		return errf.New(errf.InvalidParameter, "id is required")
+	}
+
+	md, err := taskUpdateTable(kt, task)
+	if err != nil {
+		return err
+	}
+
+	if err = md.UpdateValidate(); err != nil {
+		return err
+	}
+
+	return db.autoTxn(kt, func(tx *sqlx.Tx) error {
+		exist := new(tableasync.AsyncFlowTaskTable)
+		err := tx.GetContext(kt.Ctx, exist, `select * from async_flow_task where id = ?`, task.ID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return errf.Newf(errf.RecordNotFound, "task: %s not found", task.ID)
+			}
+			return err
+		}
+
+		mergeTaskUpdate(exist, md)
+		updateSql := `update async_flow_task set retry = :retry, depend_on = :depend_on, state = :state,
+result = :result, reason = :reason, stat = :stat, progress = :progress,
+heartbeat_at = :heartbeat_at, reviser = :reviser, updated_at = :updated_at where id = :id`
+		_, err = db.exec(kt, tx, updateSql, taskArgs(exist, time.Time{}, sqliteNow()))
+		return err
+	})
+}
+
+// RestartFlow 在同一事务中CAS重置任务流和任务状态
+func (db *sqlite) RestartFlow(kt *kit.Kit, info *RestartFlowInfo) error {
+	if err := info.Validate(); err != nil {
+		return err
+	}
+
+	return db.autoTxn(kt, func(tx *sqlx.Tx) error {
+		updatedAt := sqliteTime(sqliteNow())
+		flowExpr := fmt.Sprintf(`update %s set state = :target, reason = :reason, updated_at = :updated_at
+where id = :id and state = :source`, table.AsyncFlowTable)
+		values := map[string]interface{}{
+			"id":         info.ID,
+			"source":     info.Source,
+			"target":     enumor.FlowPending,
+			"reason":     new(tableasync.Reason),
+			"updated_at": updatedAt,
+		}
+		effected, err := db.exec(kt, tx, flowExpr, values)
+		if err != nil {
+			return err
+		}
+
+		if effected == 0 {
+			return errf.Newf(errf.RecordNotUpdate, "flow[%s: %s] update state: %s failed", info.ID, info.Source,
+				enumor.FlowPending)
+		}
+
+		taskExpr := fmt.Sprintf(`update %s set state = :target, reason = :reason, reviser = :reviser,
+updated_at = :updated_at where flow_id = :flow_id and state = :source`, table.AsyncFlowTaskTable)
+		values = map[string]interface{}{
+			"flow_id":    info.ID,
+			"source":     info.TaskSource,
+			"target":     enumor.TaskPending,
+			"reason":     new(tableasync.Reason),
+			"reviser":    kt.User,
+			"updated_at": updatedAt,
+		}
+		if _, err = db.exec(kt, tx, taskExpr, values); err != nil {
+			return err
+		}
+
+		// 生成任务流状态变更事件
+		events := flowStateEventTables([]string{info.ID}, []enumor.FlowState{enumor.FlowPending})
+		return db.insertFlowEvents(kt, tx, events)
+	})
+}
+
+// UpdateTaskStateByCAS CAS更新任务状态
+func (db *sqlite) UpdateTaskStateByCAS(kt *kit.Kit, info *UpdateTaskInfo) error {
+	if err := info.Validate(); err != nil {
+		return err
+	}
+
+	return db.autoTxn(kt, func(tx *sqlx.Tx) error {
+		setSql := "set state = :target"
+		if info.Reason != nil {
+			setSql += ", reason = :reason"
+		}
+
+		expr := fmt.Sprintf(`update %s %s, updated_at = :updated_at where id = :id and state = :source`,
+			table.AsyncFlowTaskTable, setSql)
+		values := map[string]interface{}{
+			"id":         info.ID,
+			"source":     info.Source,
+			"target":     info.Target,
+			"reason":     info.Reason,
+			"updated_at": sqliteTime(sqliteNow()),
+		}
+		effected, err := db.exec(kt, tx, expr, values)
+		if err != nil {
+			return err
+		}
+
+		if effected == 0 {
+			return errf.Newf(errf.RecordNotUpdate, "task[%s: %s] update state to %s failed", info.ID, info.Source,
+				info.Target)
+		}
+
+		return nil
+	})
+}
+
+// ListTask 查询任务
+func (db *sqlite) ListTask(kt *kit.Kit, input *ListInput) ([]model.Task, error) {
+	if err := validateListInput(input, tableasync.AsyncFlowTaskColumns); err != nil {
+		return nil, err
+	}
+
+	if input.Page.Count {
+		return make([]model.Task, 0), nil
+	}
+
+	whereExpr, whereValue, err := input.Filter.SQLWhereExpr(tools.DefaultSqlWhereOption)
+	if err != nil {
+		return nil, err
+	}
+
+	pageExpr, err := types.PageSQLExpr(input.Page, types.DefaultPageSQLOption)
+	if err != nil {
+		return nil, err
+	}
+
+	expr := fmt.Sprintf(`select %s from %s %s %s`, tableasync.AsyncFlowTaskColumns.FieldsNamedExpr(input.Fields),
+		table.AsyncFlowTaskTable, whereExpr, pageExpr)
+	details := make([]tableasync.AsyncFlowTaskTable, 0)
+	if err = db.selectRows(kt, &details, expr, whereValue); err != nil {
+		return nil, err
+	}
+
+	tasks := make([]model.Task, 0, len(details))
+	for _, one := range details {
+		tasks = append(tasks, taskTableToModel(one))
+	}
+
+	return tasks, nil
+}
+
+const insertFlowScheduleSql = `insert into async_flow_schedule (id, name, cron, state, missed_policy, flow,
+next_run_at, memo, creator, reviser, created_at, updated_at) values (:id, :name, :cron, :state, :missed_policy, :flow,
+:next_run_at, :memo, :creator, :reviser, :created_at, :updated_at)`
+
+const insertFlowScheduleRunSql = `insert into async_flow_schedule_run (id, schedule_id, plan_at, state, flow_id,
+reason, creator, created_at) values (:id, :schedule_id, :plan_at, :state, :flow_id, :reason, :creator, :created_at)`
+
+func flowScheduleArgs(md *tableasync.AsyncFlowScheduleTable, createdAt, updatedAt time.Time) map[string]interface{} {
+	return map[string]interface{}{
+		"id":            md.ID,
+		"name":          md.Name,
+		"cron":          md.Cron,
+		"state":         md.State,
+		"missed_policy": md.MissedPolicy,
+		"flow":          md.Flow,
+		"next_run_at":   sqliteTime(md.NextRunAt),
+		"memo":          md.Memo,
+		"creator":       md.Creator,
+		"reviser":       md.Reviser,
+		"created_at":    sqliteTime(createdAt),
+		"updated_at":    sqliteTime(updatedAt),
+	}
+}
+
+func flowScheduleRunArgs(md *tableasync.AsyncFlowScheduleRunTable, createdAt time.Time) map[string]interface{} {
+	return map[string]interface{}{
+		"id":          md.ID,
+		"schedule_id": md.ScheduleID,
+		"plan_at":     sqliteTime(md.PlanAt),
+		"state":       md.State,
+		"flow_id":     md.FlowID,
+		"reason":      md.Reason,
+		"creator":     md.Creator,
+		"created_at":  sqliteTime(createdAt),
+	}
+}
+
+// CreateFlowSchedule 创建定时任务流
+func (db *sqlite) CreateFlowSchedule(kt *kit.Kit, schedule *model.FlowSchedule) (string, error) {
+	md, err := newFlowScheduleTable(kt, schedule)
+	if err != nil {
+		return "", err
+	}
+
+	err = db.autoTxn(kt, func(tx *sqlx.Tx) error {
+		ids, err := db.genIDs(kt, tx, table.AsyncFlowScheduleTable, 1)
+		if err != nil {
+			return err
+		}
+
+		md.ID = ids[0]
+		if err = md.InsertValidate(); err != nil {
+			return err
+		}
+
+		now := sqliteNow()
+		if _, err = db.exec(kt, tx, insertFlowScheduleSql, flowScheduleArgs(md, now, now)); err != nil {
+			return fmt.Errorf("insert %s failed, err: %v", table.AsyncFlowScheduleTable, err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return md.ID, nil
+}
+
+// UpdateFlowSchedule 更新定时任务流
+func (db *sqlite) UpdateFlowSchedule(kt *kit.Kit, schedule *model.FlowSchedule) error {
+	if len(schedule.ID) == 0 {
+		return errf.New(errf.InvalidParameter, "id is required")
+	}
+
+	md, err := flowScheduleUpdateTable(kt, schedule)
+	if err != nil {
+		return err
+	}
+
+	if err = md.UpdateValidate(); err != nil {
+		return err
+	}
+
+	return db.autoTxn(kt, func(tx *sqlx.Tx) error {
+		exist := new(tableasync.AsyncFlowScheduleTable)
+		err := tx.GetContext(kt.Ctx, exist, `select * from async_flow_schedule where id = ?`, schedule.ID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return errf.New(errf.RecordNotUpdate, "record not update")
+			}
+			return err
+		}
+
+		mergeFlowScheduleUpdate(exist, md)
+		updateSql := `update async_flow_schedule set cron = :cron, state = :state, missed_policy = :missed_policy,
+next_run_at = :next_run_at, memo = :memo, reviser = :reviser, updated_at = :updated_at where id = :id`
+		_, err = db.exec(kt, tx, updateSql, flowScheduleArgs(exist, time.Time{}, sqliteNow()))
+		return err
+	})
+}
+
+// UpdateFlowScheduleNextRunByCAS CAS更新处于启用状态的定时任务流的下次执行时间
+func (db *sqlite) UpdateFlowScheduleNextRunByCAS(kt *kit.Kit, info *UpdateScheduleNextRunInfo) error {
+	if err := info.Validate(); err != nil {
+		return err
+	}
+
+	return db.autoTxn(kt, func(tx *sqlx.Tx) error {
+		setSql := "set next_run_at = :target"
+		if info.Pause {
+			setSql += ", state = :paused"
+		}
+
+		expr := fmt.Sprintf(`update %s %s, updated_at = :updated_at where id = :id and state = :state and `+
+			`next_run_at = :source`, table.AsyncFlowScheduleTable, setSql)
+		values := map[string]interface{}{
+			"id":         info.ID,
+			"state":      enumor.FlowScheduleEnabled,
+			"paused":     enumor.FlowSchedulePaused,
+			"source":     sqliteTime(info.Source),
+			"target":     sqliteTime(info.Target),
+			"updated_at": sqliteTime(sqliteNow()),
+		}
+		effected, err := db.exec(kt, tx, expr, values)
+		if err != nil {
+			return err
+		}
+
+		if effected == 0 {
+			return errf.Newf(errf.RecordNotUpdate, "flow schedule[%s] update next run time from %s to %s failed",
+				info.ID, times.ConvStdTimeFormat(info.Source), times.ConvStdTimeFormat(info.Target))
+		}
+
+		return nil
+	})
+}
+
+// ListFlowSchedule 查询定时任务流
+func (db *sqlite) ListFlowSchedule(kt *kit.Kit, input *ListInput) ([]model.FlowSchedule, error) {
+	if err := validateListInput(input, tableasync.AsyncFlowScheduleColumns); err != nil {
+		return nil, err
+	}
+
+	if input.Page.Count {
+		return make([]model.FlowSchedule, 0), nil
+	}
+
+	whereExpr, whereValue, err := input.Filter.SQLWhereExpr(tools.DefaultSqlWhereOption)
+	if err != nil {
+		return nil, err
+	}
+
+	pageExpr, err := types.PageSQLExpr(input.Page, types.DefaultPageSQLOption)
+	if err != nil {
+		return nil, err
+	}
+
+	expr := fmt.Sprintf(`select %s from %s %s %s`,
+		tableasync.AsyncFlowScheduleColumns.FieldsNamedExpr(input.Fields), table.AsyncFlowScheduleTable, whereExpr,
+		pageExpr)
+	details := make([]tableasync.AsyncFlowScheduleTable, 0)
+	if err = db.selectRows(kt, &details, expr, whereValue); err != nil {
+		return nil, err
+	}
+
+	schedules := make([]model.FlowSchedule, 0, len(details))
+	for _, one := range details {
+		schedules = append(schedules, flowScheduleTableToModel(one))
+	}
+
+	return schedules, nil
+}
+
+// DeleteFlowSchedule 删除定时任务流及其执行记录，已生成的任务流不受影响
+func (db *sqlite) DeleteFlowSchedule(kt *kit.Kit, id string) error {
+	if len(id) == 0 {
+		return errf.New(errf.InvalidParameter, "id is required")
+	}
+
+	return db.autoTxn(kt, func(tx *sqlx.Tx) error {
+		if _, err := tx.ExecContext(kt.Ctx, `delete from async_flow_schedule_run where schedule_id = ?`,
+			id); err != nil {
+			return err
+		}
+
+		if _, err := tx.ExecContext(kt.Ctx, `delete from async_flow_schedule where id = ?`, id); err != nil {
+			return err
+		}
+
+		return nil
+	})
+}
+
+// BatchCreateFlowScheduleRun 批量创建定时任务流执行记录
+func (db *sqlite) BatchCreateFlowScheduleRun(kt *kit.Kit, runs []model.FlowScheduleRun) error {
+	mds, err := newFlowScheduleRunTables(kt, runs)
+	if err != nil {
+		return err
+	}
+
+	return db.autoTxn(kt, func(tx *sqlx.Tx) error {
+		ids, err := db.genIDs(kt, tx, table.AsyncFlowScheduleRunTable, len(mds))
+		if err != nil {
+			return err
+		}
+
+		now := sqliteNow()
+		for index := range mds {
+			mds[index].ID = ids[index]
+			if err = mds[index].InsertValidate(); err != nil {
+				return err
+			}
+
+			if _, err = db.exec(kt, tx, insertFlowScheduleRunSql, flowScheduleRunArgs(&mds[index], now)); err != nil {
+				return fmt.Errorf("insert %s failed, err: %v", table.AsyncFlowScheduleRunTable, err)
+			}
+		}
+
+		return nil
+	})
+}
+
+// ListFlowScheduleRun 查询定时任务流执行记录
+func (db *sqlite) ListFlowScheduleRun(kt *kit.Kit, input *ListInput) ([]model.FlowScheduleRun, error) {
+	if err := validateListInput(input, tableasync.AsyncFlowScheduleRunColumns); err != nil {
+		return nil, err
+	}
+
+	if input.Page.Count {
+		return make([]model.FlowScheduleRun, 0), nil
+	}
+
+	whereExpr, whereValue, err := input.Filter.SQLWhereExpr(tools.DefaultSqlWhereOption)
+	if err != nil {
+		return nil, err
+	}
+
+	pageExpr, err := types.PageSQLExpr(input.Page, types.DefaultPageSQLOption)
+	if err != nil {
+		return nil, err
+	}
+
+	expr := fmt.Sprintf(`select %s from %s %s %s`,
+		tableasync.AsyncFlowScheduleRunColumns.FieldsNamedExpr(input.Fields), table.AsyncFlowScheduleRunTable,
+		whereExpr, pageExpr)
+	details := make([]tableasync.AsyncFlowScheduleRunTable, 0)
+	if err = db.selectRows(kt, &details, expr, whereValue); err != nil {
+		return nil, err
+	}
+
+	runs := make([]model.FlowScheduleRun, 0, len(details))
+	for _, one := range details {
+		runs = append(runs, flowScheduleRunTableToModel(one))
+	}
+
+	return runs, nil
+}
+
+const insertFlowEventSql = `insert into async_flow_event (id, flow_id, event, state, attempts, next_attempt_at,
+deliveries, created_at, updated_at) values (:id, :flow_id, :event, :state, :attempts, :next_attempt_at, :deliveries,
+:created_at, :updated_at)`
+
+func flowEventArgs(md *tableasync.AsyncFlowEventTable, createdAt, updatedAt time.Time) map[string]interface{} {
+	return map[string]interface{}{
+		"id":              md.ID,
+		"flow_id":         md.FlowID,
+		"event":           md.Event,
+		"state":           md.State,
+		"attempts":        md.Attempts,
+		"next_attempt_at": sqliteTime(md.NextAttemptAt),
+		"deliveries":      md.Deliveries,
+		"created_at":      sqliteTime(createdAt),
+		"updated_at":      sqliteTime(updatedAt),
+	}
+}
+
+// insertFlowEvents 在事务中保存任务流事件
+func (db *sqlite) insertFlowEvents(kt *kit.Kit, tx *sqlx.Tx, mds []tableasync.AsyncFlowEventTable) error {
+	if len(mds) == 0 {
+		return nil
+	}
+
+	ids, err := db.genIDs(kt, tx, table.AsyncFlowEventTable, len(mds))
+	if err != nil {
+		return err
+	}
+
+	now := sqliteNow()
+	for index := range mds {
+		mds[index].ID = ids[index]
+		if err = mds[index].InsertValidate(); err != nil {
+			return err
+		}
+
+		if _, err = db.exec(kt, tx, insertFlowEventSql, flowEventArgs(&mds[index], now, now)); err != nil {
+			return fmt.Errorf("insert %s failed, err: %v", table.AsyncFlowEventTable, err)
+		}
+	}
+
+	return nil
+}
+
+// ListFlowEvent 查询任务流事件
+func (db *sqlite) ListFlowEvent(kt *kit.Kit, input *ListInput) ([]model.FlowEvent, error) {
+	if err := validateListInput(input, tableasync.AsyncFlowEventColumns); err != nil {
+		return nil, err
+	}
+
+	if input.Page.Count {
+		return make([]model.FlowEvent, 0), nil
+	}
+
+	whereExpr, whereValue, err := input.Filter.SQLWhereExpr(tools.DefaultSqlWhereOption)
+	if err != nil {
+		return nil, err
+	}
+
+	pageExpr, err := types.PageSQLExpr(input.Page, types.DefaultPageSQLOption)
+	if err != nil {
+		return nil, err
+	}
+
+	expr := fmt.Sprintf(`select %s from %s %s %s`, tableasync.AsyncFlowEventColumns.FieldsNamedExpr(input.Fields),
+		table.AsyncFlowEventTable, whereExpr, pageExpr)
+	details := make([]tableasync.AsyncFlowEventTable, 0)
+	if err = db.selectRows(kt, &details, expr, whereValue); err != nil {
+		return nil, err
+	}
+
+	events := make([]model.FlowEvent, 0, len(details))
+	for _, one := range details {
+		events = append(events, flowEventTableToModel(one))
+	}
+
+	return events, nil
+}
+
+// UpdateFlowEvent 更新任务流事件的投递结果
+func (db *sqlite) UpdateFlowEvent(kt *kit.Kit, event *model.FlowEvent) error {
+	if len(event.ID) == 0 {
+		return errf.New(errf.InvalidParameter, "id is required")
+	}
+
+	md, err := flowEventUpdateTable(event)
+	if err != nil {
+		return err
+	}
+
+	if err = md.UpdateValidate(); err != nil {
+		return err
+	}
+
+	return db.autoTxn(kt, func(tx *sqlx.Tx) error {
+		exist := new(tableasync.AsyncFlowEventTable)
+		err := tx.GetContext(kt.Ctx, exist, `select * from async_flow_event where id = ?`, event.ID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return errf.New(errf.RecordNotUpdate, "record not update")
+			}
+			return err
+		}
+
+		mergeFlowEventUpdate(exist, md)
+		updateSql := `update async_flow_event set state = :state, attempts = :attempts,
+next_attempt_at = :next_attempt_at, deliveries = :deliveries, updated_at = :updated_at where id = :id`
+		_, err = db.exec(kt, tx, updateSql, flowEventArgs(exist, time.Time{}, sqliteNow()))
+		return err
+	})
+}
+
+const insertFlowArchiveSql = `insert into async_flow_archive (id, name, state, tenant, app_code, detail,
+flow_created_at, finished_at, archived_at) values (:id, :name, :state, :tenant, :app_code, :detail, :flow_created_at,
+:finished_at, :archived_at)`
+
+func flowArchiveArgs(md *tableasync.AsyncFlowArchiveTable) map[string]interface{} {
+	return map[string]interface{}{
+		"id":              md.ID,
+		"name":            md.Name,
+		"state":           md.State,
+		"tenant":          md.Tenant,
+		"app_code":        md.AppCode,
+		"detail":          md.Detail,
+		"flow_created_at": sqliteTime(md.FlowCreatedAt),
+		"finished_at":     sqliteTime(md.FinishedAt),
+		"archived_at":     sqliteTime(md.ArchivedAt),
+	}
+}
+
+// ArchiveFlow 归档任务流
+func (db *sqlite) ArchiveFlow(kt *kit.Kit, ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	if err := validateArchiveIDs(ids); err != nil {
+		return err
+	}
+
+	whereExpr, whereValue, err := archivableFlowExpr(ids).SQLWhereExpr(tools.DefaultSqlWhereOption)
+	if err != nil {
+		return err
+	}
+
+	return db.autoTxn(kt, func(tx *sqlx.Tx) error {
+		flows := make([]tableasync.AsyncFlowTable, 0)
+		if err := db.txSelectRows(kt, tx, &flows, "select * from async_flow "+whereExpr, whereValue); err != nil {
+			return err
+		}
+
+		if len(flows) == 0 {
+			return nil
+		}
+
+		flowIDs := make([]string, 0, len(flows))
+		for _, one := range flows {
+			flowIDs = append(flowIDs, one.ID)
+		}
+		arg := map[string]interface{}{"ids": flowIDs}
+
+		tasks := make([]tableasync.AsyncFlowTaskTable, 0)
+		err := db.txSelectRows(kt, tx, &tasks, "select * from async_flow_task where flow_id in (:ids) order by id", arg)
+		if err != nil {
+			return err
+		}
+
+		events := make([]tableasync.AsyncFlowEventTable, 0)
+		err = db.txSelectRows(kt, tx, &events, "select * from async_flow_event where flow_id in (:ids) order by id",
+			arg)
+		if err != nil {
+			return err
+		}
+
+		mds, err := newFlowArchiveTables(flows, tasks, events)
+		if err != nil {
+			return err
+		}
+
+		for index := range mds {
+			if err = mds[index].InsertValidate(); err != nil {
+				return err
+			}
+
+			if _, err = db.exec(kt, tx, insertFlowArchiveSql, flowArchiveArgs(&mds[index])); err != nil {
+				return fmt.Errorf("insert %s failed, err: %v", table.AsyncFlowArchiveTable, err)
+			}
+		}
+
+		for _, expr := range []string{"delete from async_flow_event where flow_id in (:ids)",
+			"delete from async_flow_task where flow_id in (:ids)", "delete from async_flow where id in (:ids)"} {
+			if _, err = db.exec(kt, tx, expr, arg); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// ListFlowArchive 查询已归档的任务流
+func (db *sqlite) ListFlowArchive(kt *kit.Kit, input *ListInput) ([]model.FlowArchive, error) {
+	if err := validateListInput(input, tableasync.AsyncFlowArchiveColumns); err != nil {
+		return nil, err
+	}
+
+	if input.Page.Count {
+		return make([]model.FlowArchive, 0), nil
+	}
+
+	whereExpr, whereValue, err := input.Filter.SQLWhereExpr(tools.DefaultSqlWhereOption)
+	if err != nil {
+		return nil, err
+	}
+
+	pageExpr, err := types.PageSQLExpr(input.Page, types.DefaultPageSQLOption)
+	if err != nil {
+		return nil, err
+	}
+
+	expr := fmt.Sprintf(`select %s from %s %s %s`, tableasync.AsyncFlowArchiveColumns.FieldsNamedExpr(input.Fields),
+		table.AsyncFlowArchiveTable, whereExpr, pageExpr)
+	details := make([]tableasync.AsyncFlowArchiveTable, 0)
+	if err = db.selectRows(kt, &details, expr, whereValue); err != nil {
+		return nil, err
+	}
+
+	archives := make([]model.FlowArchive, 0, len(details))
+	for _, one := range details {
+		archives = append(archives, flowArchiveTableToModel(one))
+	}
+
+	return archives, nil
+}
@@ -0,0 +1,63 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package backend_test
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+	_ "github.com/mattn/go-sqlite3" // import sqlite drive, used by sqlite backend conformance test.
+
+	"hcm/pkg/async/backend"
+	"hcm/pkg/async/backend/backendtest"
+	"hcm/pkg/criteria/enumor"
+)
+
+// TestSqliteConformance 使用 HCM_ASYNC_SQLITE_DRIVER 指定的驱动（默认 sqlite3）运行 sqlite 后端一致性测试，
+// 未注册该驱动时跳过。
+func TestSqliteConformance(t *testing.T) {
+	driver := envOrDefault("HCM_ASYNC_SQLITE_DRIVER", "sqlite3")
+	registered := false
+	for _, one := range sql.Drivers() {
+		if one == driver {
+			registered = true
+			break
+		}
+	}
+	if !registered {
+		t.Skipf("sqlite driver %s is not registered, skip sqlite backend conformance test", driver)
+	}
+
+	backendtest.Run(t, func(t *testing.T) backend.Backend {
+		db, err := sqlx.Open(driver, filepath.Join(t.TempDir(), "async.db"))
+		if err != nil {
+			t.Fatalf("open sqlite db failed, err: %v", err)
+		}
+		t.Cleanup(func() { _ = db.Close() })
+
+		bd, err := backend.Factory(enumor.BackendSqlite, db)
+		if err != nil {
+			t.Fatalf("create sqlite backend failed, err: %v", err)
+		}
+		return bd
+	})
+}
@@ -0,0 +1,155 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package consumer
+
+import (
+	"sync"
+	"time"
+
+	"hcm/pkg/api/core"
+	"hcm/pkg/async/backend"
+	"hcm/pkg/async/compctrl"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/runtime/filter"
+	"hcm/pkg/tools/times"
+)
+
+/*
+Archiver （归档器）:
+ 1. 查询已结束，且结束时间（最后更新时间）超过保留时间的任务流
+ 2. 分批将任务流连同任务、任务流事件保存到归档表后删除，避免任务流表无限增长影响调度、巡检的查询性能
+*/
+type Archiver interface {
+	compctrl.Closer
+	// Start 启动归档器，按照保留时间归档已结束的任务流。
+	Start()
+}
+
+// maxArchiveBatchesPerRound 单轮最多归档的批次数，剩余的任务流在下一轮归档
+const maxArchiveBatchesPerRound = 10
+
+// archiver 归档器
+type archiver struct {
+	bd backend.Backend
+
+	watchIntervalSec time.Duration
+	retention        time.Duration
+	batchSize        uint
+
+	wg      sync.WaitGroup
+	closeCh chan struct{}
+}
+
+// NewArchiver 创建一个归档器
+func NewArchiver(bd backend.Backend, opt *ArchiverOption) Archiver {
+	return &archiver{
+		bd:               bd,
+		watchIntervalSec: time.Duration(opt.WatchIntervalSec) * time.Second,
+		retention:        time.Duration(opt.RetentionDays) * 24 * time.Hour,
+		batchSize:        opt.BatchSize,
+		wg:               sync.WaitGroup{},
+		closeCh:          make(chan struct{}),
+	}
+}
+
+// Start 启动归档器
+func (a *archiver) Start() {
+	a.wg.Add(1)
+	go a.watch()
+}
+
+func (a *archiver) watch() {
+	defer a.wg.Done()
+
+	for {
+		select {
+		case <-a.closeCh:
+			return
+		default:
+		}
+
+		kt := NewKit()
+		if err := a.Do(kt); err != nil {
+			logs.Errorf("%s: archiver do failed, err: %v, rid: %s", constant.AsyncTaskWarnSign, err, kt.Rid)
+		}
+
+		time.Sleep(a.watchIntervalSec)
+	}
+}
+
+// Do 分批归档已结束且超过保留时间的任务流
+func (a *archiver) Do(kt *kit.Kit) error {
+	expireAt := times.ConvStdTimeFormat(time.Now().Add(-a.retention))
+	input := &backend.ListInput{
+		Filter: &filter.Expression{
+			Op: filter.And,
+			Rules: []filter.RuleFactory{
+				&filter.AtomRule{Field: "state", Op: filter.In.Factory(), Value: backend.ArchivableFlowStates},
+				&filter.AtomRule{Field: "updated_at", Op: filter.LessThanEqual.Factory(), Value: expireAt},
+			},
+		},
+		Fields: []string{"id"},
+		Page:   &core.BasePage{Start: 0, Limit: a.batchSize},
+	}
+
+	for i := 0; i < maxArchiveBatchesPerRound; i++ {
+		select {
+		case <-a.closeCh:
+			return nil
+		default:
+		}
+
+		flows, err := a.bd.ListFlow(kt, input)
+		if err != nil {
+			logs.Errorf("list expired flow failed, err: %v, rid: %s", err, kt.Rid)
+			return err
+		}
+
+		if len(flows) == 0 {
+			return nil
+		}
+
+		ids := make([]string, 0, len(flows))
+		for _, one := range flows {
+			ids = append(ids, one.ID)
+		}
+
+		if err = a.bd.ArchiveFlow(kt, ids); err != nil {
+			logs.Errorf("archive flow failed, err: %v, ids: %v, rid: %s", err, ids, kt.Rid)
+			return err
+		}
+
+		logs.Infof("archive %d expired flows success, rid: %s", len(ids), kt.Rid)
+
+		if uint(len(flows)) < a.batchSize {
+			return nil
+		}
+	}
+
+	return nil
+}
+
+// Close 关闭归档器
+func (a *archiver) Close() {
+	close(a.closeCh)
+	a.wg.Wait()
+}
@@ -0,0 +1,91 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package consumer
+
+import (
+	"testing"
+
+	"hcm/pkg/api/core"
+	"hcm/pkg/async/backend"
+	"hcm/pkg/async/backend/model"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/dal/dao/tools"
+	tableasync "hcm/pkg/dal/table/async"
+)
+
+func TestArchiverDo(t *testing.T) {
+	bd := backend.NewMemory()
+	kt := newTestKit()
+
+	states := []enumor.FlowState{enumor.FlowSuccess, enumor.FlowFailed, enumor.FlowRunning}
+	ids := make(map[enumor.FlowState]string, len(states))
+	for _, state := range states {
+		id, err := bd.CreateFlow(kt, &model.Flow{
+			Name:      "archiver_test",
+			ShareData: tableasync.NewShareData(),
+			Tasks: []model.Task{{FlowName: "archiver_test", ActionID: "1", ActionName: "test",
+				Retry: new(tableasync.Retry)}},
+		})
+		if err != nil {
+			t.Fatalf("create flow failed, err: %v", err)
+		}
+
+		if err = bd.BatchUpdateFlow(kt, []model.Flow{{ID: id, State: state}}); err != nil {
+			t.Fatalf("update flow failed, err: %v", err)
+		}
+		ids[state] = id
+	}
+
+	// 保留时间为0时归档所有已结束的任务流，批次大小为1时分多批归档
+	ac := NewArchiver(bd, &ArchiverOption{WatchIntervalSec: 1, RetentionDays: 0, BatchSize: 1}).(*archiver)
+	if err := ac.Do(kt); err != nil {
+		t.Fatalf("archiver do failed, err: %v", err)
+	}
+
+	flows, err := bd.ListFlow(kt, &backend.ListInput{
+		Filter: tools.AllExpression(),
+		Page:   core.NewDefaultBasePage(),
+	})
+	if err != nil {
+		t.Fatalf("list flow failed, err: %v", err)
+	}
+
+	if len(flows) != 1 || flows[0].ID != ids[enumor.FlowRunning] {
+		t.Errorf("only running flow should be kept, but got %+v", flows)
+	}
+
+	archives, err := bd.ListFlowArchive(kt, &backend.ListInput{
+		Filter: tools.AllExpression(),
+		Page:   core.NewDefaultBasePage(),
+	})
+	if err != nil {
+		t.Fatalf("list flow archive failed, err: %v", err)
+	}
+
+	archived := make(map[string]enumor.FlowState, len(archives))
+	for _, one := range archives {
+		archived[one.ID] = one.State
+	}
+
+	if len(archived) != 2 || archived[ids[enumor.FlowSuccess]] != enumor.FlowSuccess ||
+		archived[ids[enumor.FlowFailed]] != enumor.FlowFailed {
+		t.Errorf("success and failed flow should be archived, but got %+v", archives)
+	}
+}
@@ -19,23 +19,62 @@
 
 package consumer
 
+import (
+	"time"
+
+	"hcm/pkg/api/core"
+	"hcm/pkg/async/backend"
+	"hcm/pkg/async/backend/model"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/tools"
+	tableasync "hcm/pkg/dal/table/async"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/runtime/filter"
+	"hcm/pkg/tools/cron"
+	"hcm/pkg/tools/slice"
+	"hcm/pkg/tools/times"
+)
+
 /*
 Commander （指挥者）
 		1. 强制关闭处于执行中的任务
+		2. 取消任务流、从失败处重试任务流、继续执行已取消的任务流
+		3. 人工设置任务为成功或跳过
+		4. 暂停、恢复、删除定时任务流
+任务流、任务的状态变更均通过CAS进行，并发操作时只有一个会成功。
 */
 type Commander interface {
+	// CancelTasks 强制关闭当前节点上处于执行中的任务
 	CancelTasks(taskIDs []string) error
+	// CancelFlow 取消任务流，任务流及其未结束的任务置为取消状态，执行中的任务会被强制关闭。
+	CancelFlow(kt *kit.Kit, flowID string, reason string) error
+	// RetryFlow 从失败的任务处重试失败的任务流，已成功的任务不会重新执行。
+	RetryFlow(kt *kit.Kit, flowID string) error
+	// ResumeFlow 从取消的任务处继续执行已取消的任务流。
+	ResumeFlow(kt *kit.Kit, flowID string) error
+	// MarkTask 人工将任务设置为成功或跳过，仅允许操作未在执行中的任务流。
+	MarkTask(kt *kit.Kit, taskID string, state enumor.TaskState, reason string) error
+	// PauseFlowSchedule 暂停定时任务流，暂停期间不会生成任务流。
+	PauseFlowSchedule(kt *kit.Kit, scheduleID string) error
+	// ResumeFlowSchedule 恢复定时任务流，从当前时间重新计算下次执行时间，暂停期间的计划执行不会补偿。
+	ResumeFlowSchedule(kt *kit.Kit, scheduleID string) error
+	// DeleteFlowSchedule 删除定时任务流及其执行记录，已生成的任务流不受影响。
+	DeleteFlowSchedule(kt *kit.Kit, scheduleID string) error
 }
 
 // NewCommander new commander.
-func NewCommander(exec Executor) Commander {
+func NewCommander(bd backend.Backend, exec Executor) Commander {
 	return &commander{
+		backend:  bd,
 		executor: exec,
 	}
 }
 
 // commander ...
 type commander struct {
+	backend  backend.Backend
 	executor Executor
 }
 
@@ -43,3 +82,316 @@ type commander struct {
 func (cmd *commander) CancelTasks(taskIDs []string) error {
 	return cmd.executor.CancelTasks(taskIDs)
 }
+
+// CancelFlow 取消任务流。
+// 先将任务流置为取消状态，阻止调度器继续下发任务，再将未结束的任务置为取消状态，最后关闭当前节点上执行中的任务，
+// 其他节点上执行中的任务由该节点的调度器感知任务流取消后关闭。
+func (cmd *commander) CancelFlow(kt *kit.Kit, flowID string, reason string) error {
+	flow, err := getFlow(kt, cmd.backend, flowID)
+	if err != nil {
+		return err
+	}
+
+	switch flow.State {
+	case enumor.FlowPending, enumor.FlowScheduled, enumor.FlowRunning:
+	default:
+		return errf.Newf(errf.InvalidParameter, "flow: %s state is %s, can not cancel", flowID, flow.State)
+	}
+
+	info := backend.UpdateFlowInfo{
+		ID:     flowID,
+		Source: flow.State,
+		Target: enumor.FlowCancel,
+		Reason: &tableasync.Reason{Message: reason},
+	}
+	if err = cmd.backend.BatchUpdateFlowStateByCAS(kt, []backend.UpdateFlowInfo{info}); err != nil {
+		logs.Errorf("cancel flow failed, err: %v, id: %s, rid: %s", err, flowID, kt.Rid)
+		return err
+	}
+
+	tasks, err := listTaskByFlowID(kt, cmd.backend, flowID)
+	if err != nil {
+		return err
+	}
+
+	execIDs := make([]string, 0)
+	for _, one := range tasks {
+		switch one.State {
+		case enumor.TaskPending, enumor.TaskRunning, enumor.TaskRollback:
+		default:
+			continue
+		}
+
+		info := &backend.UpdateTaskInfo{
+			ID:     one.ID,
+			Source: one.State,
+			Target: enumor.TaskCancel,
+			Reason: &tableasync.Reason{Message: reason},
+		}
+		if err = cmd.backend.UpdateTaskStateByCAS(kt, info); err != nil {
+			// 任务状态在查询后已经发生变化（如执行完成），任务流已取消，不会再下发后续任务，忽略即可
+			logs.Warnf("cancel task failed, err: %v, id: %s, rid: %s", err, one.ID, kt.Rid)
+			continue
+		}
+
+		if one.State != enumor.TaskPending {
+			execIDs = append(execIDs, one.ID)
+		}
+	}
+
+	if len(execIDs) != 0 && cmd.executor != nil {
+		if err = cmd.executor.CancelTasks(execIDs); err != nil {
+			logs.Errorf("cancel executing tasks failed, err: %v, ids: %v, rid: %s", err, execIDs, kt.Rid)
+			return err
+		}
+	}
+
+	return nil
+}
+
+// RetryFlow 从失败的任务处重试任务流
+func (cmd *commander) RetryFlow(kt *kit.Kit, flowID string) error {
+	return cmd.restartFlow(kt, flowID, enumor.FlowFailed, enumor.TaskFailed)
+}
+
+// ResumeFlow 从取消的任务处继续执行任务流
+func (cmd *commander) ResumeFlow(kt *kit.Kit, flowID string) error {
+	return cmd.restartFlow(kt, flowID, enumor.FlowCancel, enumor.TaskCancel)
+}
+
+// restartFlow 将任务流由 flowState 重置为 Pending，并将处于 taskState 状态的任务重置为 Pending，交由派发器重新派发，
+// 已成功或跳过的任务在构建任务流树时不会被再次执行。
+func (cmd *commander) restartFlow(kt *kit.Kit, flowID string, flowState enumor.FlowState,
+	taskState enumor.TaskState) error {
+
+	flow, err := getFlow(kt, cmd.backend, flowID)
+	if err != nil {
+		return err
+	}
+
+	if flow.State != flowState {
+		return errf.Newf(errf.InvalidParameter, "flow: %s state is %s, only %s flow can be restarted", flowID,
+			flow.State, flowState)
+	}
+
+	// 任务流与任务在同一事务中重置，任务流状态已被其他操作变更时任务保持不变
+	info := &backend.RestartFlowInfo{
+		ID:         flowID,
+		Source:     flowState,
+		TaskSource: taskState,
+	}
+	if err = cmd.backend.RestartFlow(kt, info); err != nil {
+		logs.Errorf("reset flow to pending failed, err: %v, id: %s, rid: %s", err, flowID, kt.Rid)
+		return err
+	}
+
+	return nil
+}
+
+// MarkTask 人工设置任务状态
+func (cmd *commander) MarkTask(kt *kit.Kit, taskID string, state enumor.TaskState, reason string) error {
+	switch state {
+	case enumor.TaskSuccess, enumor.TaskSkipped:
+	default:
+		return errf.Newf(errf.InvalidParameter, "task can only be marked as %s or %s", enumor.TaskSuccess,
+			enumor.TaskSkipped)
+	}
+
+	if len(reason) == 0 {
+		return errf.New(errf.InvalidParameter, "reason is required")
+	}
+
+	tasks, err := cmd.backend.ListTask(kt, &backend.ListInput{
+		Filter: tools.EqualExpression("id", taskID),
+		Page:   core.NewDefaultBasePage(),
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(tasks) == 0 {
+		return errf.Newf(errf.RecordNotFound, "task: %s not found", taskID)
+	}
+	task := tasks[0]
+
+	switch task.State {
+	case enumor.TaskPending, enumor.TaskFailed, enumor.TaskCancel:
+	default:
+		return errf.Newf(errf.InvalidParameter, "task: %s state is %s, can not be marked", taskID, task.State)
+	}
+
+	flow, err := getFlow(kt, cmd.backend, task.FlowID)
+	if err != nil {
+		return err
+	}
+
+	switch flow.State {
+	case enumor.FlowPending, enumor.FlowFailed, enumor.FlowCancel:
+	default:
+		return errf.Newf(errf.InvalidParameter, "flow: %s state is %s, task can not be marked", flow.ID,
+			flow.State)
+	}
+
+	info := &backend.UpdateTaskInfo{
+		ID:     taskID,
+		Source: task.State,
+		Target: state,
+		Reason: &tableasync.Reason{Message: reason},
+	}
+	if err = cmd.backend.UpdateTaskStateByCAS(kt, info); err != nil {
+		logs.Errorf("mark task state failed, err: %v, id: %s, state: %s, rid: %s", err, taskID, state, kt.Rid)
+		return err
+	}
+
+	return nil
+}
+
+// PauseFlowSchedule 暂停定时任务流
+func (cmd *commander) PauseFlowSchedule(kt *kit.Kit, scheduleID string) error {
+	schedule, err := getFlowSchedule(kt, cmd.backend, scheduleID)
+	if err != nil {
+		return err
+	}
+
+	if schedule.State != enumor.FlowScheduleEnabled {
+		return errf.Newf(errf.InvalidParameter, "flow schedule: %s state is %s, can not pause", scheduleID,
+			schedule.State)
+	}
+
+	update := &model.FlowSchedule{
+		ID:    scheduleID,
+		State: enumor.FlowSchedulePaused,
+	}
+	if err = cmd.backend.UpdateFlowSchedule(kt, update); err != nil {
+		logs.Errorf("pause flow schedule failed, err: %v, id: %s, rid: %s", err, scheduleID, kt.Rid)
+		return err
+	}
+
+	return nil
+}
+
+// ResumeFlowSchedule 恢复定时任务流
+func (cmd *commander) ResumeFlowSchedule(kt *kit.Kit, scheduleID string) error {
+	schedule, err := getFlowSchedule(kt, cmd.backend, scheduleID)
+	if err != nil {
+		return err
+	}
+
+	if schedule.State != enumor.FlowSchedulePaused {
+		return errf.Newf(errf.InvalidParameter, "flow schedule: %s state is %s, can not resume", scheduleID,
+			schedule.State)
+	}
+
+	sched, err := cron.Parse(schedule.Cron)
+	if err != nil {
+		return err
+	}
+
+	nextRunAt := sched.Next(time.Now())
+	if nextRunAt.IsZero() {
+		return errf.Newf(errf.InvalidParameter, "flow schedule: %s cron will never be satisfied", scheduleID)
+	}
+
+	update := &model.FlowSchedule{
+		ID:        scheduleID,
+		State:     enumor.FlowScheduleEnabled,
+		NextRunAt: times.ConvStdTimeFormat(nextRunAt),
+	}
+	if err = cmd.backend.UpdateFlowSchedule(kt, update); err != nil {
+		logs.Errorf("resume flow schedule failed, err: %v, id: %s, rid: %s", err, scheduleID, kt.Rid)
+		return err
+	}
+
+	return nil
+}
+
+// DeleteFlowSchedule 删除定时任务流
+func (cmd *commander) DeleteFlowSchedule(kt *kit.Kit, scheduleID string) error {
+	if _, err := getFlowSchedule(kt, cmd.backend, scheduleID); err != nil {
+		return err
+	}
+
+	if err := cmd.backend.DeleteFlowSchedule(kt, scheduleID); err != nil {
+		logs.Errorf("delete flow schedule failed, err: %v, id: %s, rid: %s", err, scheduleID, kt.Rid)
+		return err
+	}
+
+	return nil
+}
+
+// getFlowSchedule 根据ID查询定时任务流
+func getFlowSchedule(kt *kit.Kit, bd backend.Backend, scheduleID string) (*model.FlowSchedule, error) {
+	if len(scheduleID) == 0 {
+		return nil, errf.New(errf.InvalidParameter, "flow schedule id is required")
+	}
+
+	schedules, err := bd.ListFlowSchedule(kt, &backend.ListInput{
+		Filter: tools.EqualExpression("id", scheduleID),
+		Page:   core.NewDefaultBasePage(),
+	})
+	if err != nil {
+		logs.Errorf("list flow schedule failed, err: %v, id: %s, rid: %s", err, scheduleID, kt.Rid)
+		return nil, err
+	}
+
+	if len(schedules) == 0 {
+		return nil, errf.Newf(errf.RecordNotFound, "flow schedule: %s not found", scheduleID)
+	}
+
+	return &schedules[0], nil
+}
+
+// getFlow 根据ID查询任务流
+func getFlow(kt *kit.Kit, bd backend.Backend, flowID string) (*model.Flow, error) {
+	if len(flowID) == 0 {
+		return nil, errf.New(errf.InvalidParameter, "flow id is required")
+	}
+
+	flows, err := bd.ListFlow(kt, &backend.ListInput{
+		Filter: tools.EqualExpression("id", flowID),
+		Page:   core.NewDefaultBasePage(),
+	})
+	if err != nil {
+		logs.Errorf("list flow failed, err: %v, id: %s, rid: %s", err, flowID, kt.Rid)
+		return nil, err
+	}
+
+	if len(flows) == 0 {
+		return nil, errf.Newf(errf.RecordNotFound, "flow: %s not found", flowID)
+	}
+
+	return &flows[0], nil
+}
+
+// listFlowByIDs 批量查询指定状态的任务流
+func listFlowByIDs(kt *kit.Kit, bd backend.Backend, ids []string, state enumor.FlowState) ([]model.Flow, error) {
+	flows := make([]model.Flow, 0)
+	for _, partIDs := range slice.Split(ids, int(core.DefaultMaxPageLimit)) {
+		input := &backend.ListInput{
+			Filter: &filter.Expression{
+				Op: filter.And,
+				Rules: []filter.RuleFactory{
+					&filter.AtomRule{
+						Field: "id",
+						Op:    filter.In.Factory(),
+						Value: partIDs,
+					},
+					&filter.AtomRule{
+						Field: "state",
+						Op:    filter.Equal.Factory(),
+						Value: state,
+					},
+				},
+			},
+			Page: core.NewDefaultBasePage(),
+		}
+		result, err := bd.ListFlow(kt, input)
+		if err != nil {
+			logs.Errorf("list flow failed, err: %v, ids: %v, rid: %s", err, partIDs, kt.Rid)
+			return nil, err
+		}
+		flows = append(flows, result...)
+	}
+
+	return flows, nil
+}
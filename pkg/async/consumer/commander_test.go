@@ -0,0 +1,184 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package consumer
+
+import (
+	"fmt"
+	"testing"
+
+	"hcm/pkg/async/action"
+	"hcm/pkg/async/backend"
+	"hcm/pkg/async/backend/model"
+	"hcm/pkg/criteria/enumor"
+	tableasync "hcm/pkg/dal/table/async"
+	"hcm/pkg/kit"
+)
+
+func newTestKit() *kit.Kit {
+	kt := kit.New()
+	kt.User = "commander_test"
+	return kt
+}
+
+// prepareFlow 创建包含串行任务的任务流，并将任务流、任务设置为指定状态
+func prepareFlow(t *testing.T, bd backend.Backend, flowState enumor.FlowState,
+	taskStates ...enumor.TaskState) (string, []string) {
+
+	kt := newTestKit()
+	tasks := make([]model.Task, 0, len(taskStates))
+	for i := range taskStates {
+		task := model.Task{
+			FlowName:   "commander_test",
+			ActionID:   action.ActIDType(fmt.Sprintf("%d", i+1)),
+			ActionName: "test",
+			Retry:      &tableasync.Retry{Enable: false},
+		}
+		if i > 0 {
+			task.DependOn = []action.ActIDType{action.ActIDType(fmt.Sprintf("%d", i))}
+		}
+		tasks = append(tasks, task)
+	}
+
+	flowID, err := bd.CreateFlow(kt, &model.Flow{
+		Name:      "commander_test",
+		ShareData: tableasync.NewShareData(),
+		Tasks:     tasks,
+	})
+	if err != nil {
+		t.Fatalf("create flow failed, err: %v", err)
+	}
+
+	taskList, err := listTaskByFlowID(kt, bd, flowID)
+	if err != nil {
+		t.Fatalf("list task failed, err: %v", err)
+	}
+
+	taskIDs := make([]string, 0, len(taskList))
+	for _, one := range taskList {
+		state := taskStates[len(taskIDs)]
+		if err = bd.UpdateTask(kt, &model.Task{ID: one.ID, State: state}); err != nil {
+			t.Fatalf("update task failed, err: %v", err)
+		}
+		taskIDs = append(taskIDs, one.ID)
+	}
+
+	if err = bd.BatchUpdateFlow(kt, []model.Flow{{ID: flowID, State: flowState}}); err != nil {
+		t.Fatalf("update flow failed, err: %v", err)
+	}
+
+	return flowID, taskIDs
+}
+
+func assertStates(t *testing.T, bd backend.Backend, flowID string, flowState enumor.FlowState,
+	taskStates ...enumor.TaskState) {
+
+	t.Helper()
+
+	kt := newTestKit()
+	flow, err := getFlow(kt, bd, flowID)
+	if err != nil {
+		t.Fatalf("get flow failed, err: %v", err)
+	}
+
+	if flow.State != flowState {
+		t.Errorf("flow state should be %s, but got %s", flowState, flow.State)
+	}
+
+	tasks, err := listTaskByFlowID(kt, bd, flowID)
+	if err != nil {
+		t.Fatalf("list task failed, err: %v", err)
+	}
+
+	for i, one := range tasks {
+		if one.State != taskStates[i] {
+			t.Errorf("task %s state should be %s, but got %s", one.ActionID, taskStates[i], one.State)
+		}
+	}
+}
+
+func TestCommanderRetryFlow(t *testing.T) {
+	bd := backend.NewMemory()
+	cmd := NewCommander(bd, NewExecutor(bd, &ExecutorOption{}))
+
+	flowID, _ := prepareFlow(t, bd, enumor.FlowFailed, enumor.TaskSuccess, enumor.TaskFailed, enumor.TaskPending)
+
+	if err := cmd.RetryFlow(newTestKit(), flowID); err != nil {
+		t.Fatalf("retry flow failed, err: %v", err)
+	}
+	assertStates(t, bd, flowID, enumor.FlowPending, enumor.TaskSuccess, enumor.TaskPending, enumor.TaskPending)
+
+	// 非失败状态的任务流不允许重试
+	if err := cmd.RetryFlow(newTestKit(), flowID); err == nil {
+		t.Errorf("retry pending flow should be failed")
+	}
+}
+
+func TestCommanderCancelAndResumeFlow(t *testing.T) {
+	bd := backend.NewMemory()
+	cmd := NewCommander(bd, NewExecutor(bd, &ExecutorOption{}))
+
+	flowID, _ := prepareFlow(t, bd, enumor.FlowRunning, enumor.TaskSuccess, enumor.TaskRunning,
+		enumor.TaskPending)
+
+	if err := cmd.CancelFlow(newTestKit(), flowID, "manual cancel"); err != nil {
+		t.Fatalf("cancel flow failed, err: %v", err)
+	}
+	assertStates(t, bd, flowID, enumor.FlowCancel, enumor.TaskSuccess, enumor.TaskCancel, enumor.TaskCancel)
+
+	if err := cmd.CancelFlow(newTestKit(), flowID, "manual cancel"); err == nil {
+		t.Errorf("cancel canceled flow should be failed")
+	}
+
+	if err := cmd.ResumeFlow(newTestKit(), flowID); err != nil {
+		t.Fatalf("resume flow failed, err: %v", err)
+	}
+	assertStates(t, bd, flowID, enumor.FlowPending, enumor.TaskSuccess, enumor.TaskPending, enumor.TaskPending)
+}
+
+func TestCommanderMarkTask(t *testing.T) {
+	bd := backend.NewMemory()
+	cmd := NewCommander(bd, NewExecutor(bd, &ExecutorOption{}))
+
+	flowID, taskIDs := prepareFlow(t, bd, enumor.FlowFailed, enumor.TaskSuccess, enumor.TaskFailed)
+
+	if err := cmd.MarkTask(newTestKit(), taskIDs[1], enumor.TaskRunning, "invalid"); err == nil {
+		t.Errorf("mark task as running should be failed")
+	}
+
+	if err := cmd.MarkTask(newTestKit(), taskIDs[1], enumor.TaskSkipped, ""); err == nil {
+		t.Errorf("mark task without reason should be failed")
+	}
+
+	if err := cmd.MarkTask(newTestKit(), taskIDs[0], enumor.TaskSkipped, "skip"); err == nil {
+		t.Errorf("mark success task should be failed")
+	}
+
+	if err := cmd.MarkTask(newTestKit(), taskIDs[1], enumor.TaskSkipped, "skip"); err != nil {
+		t.Fatalf("mark task failed, err: %v", err)
+	}
+	assertStates(t, bd, flowID, enumor.FlowFailed, enumor.TaskSuccess, enumor.TaskSkipped)
+
+	// 执行中的任务流不允许人工设置任务状态
+	runningID, runningTaskIDs := prepareFlow(t, bd, enumor.FlowRunning, enumor.TaskFailed)
+	if err := cmd.MarkTask(newTestKit(), runningTaskIDs[0], enumor.TaskSuccess, "done"); err == nil {
+		t.Errorf("mark task of running flow should be failed")
+	}
+	assertStates(t, bd, runningID, enumor.FlowRunning, enumor.TaskFailed)
+}
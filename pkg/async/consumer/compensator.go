@@ -0,0 +1,160 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package consumer
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"hcm/pkg/api/core"
+	"hcm/pkg/async/backend"
+	"hcm/pkg/async/compctrl"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+)
+
+/*
+Compensator （补偿器）:
+ 1. 定期查询 compensating 状态的任务流，按照依赖关系的逆序补偿任务流中执行成功的任务
+ 2. 补偿在补偿器自己的协程池中执行，不占用调度器处理任务流的协程
+ 3. 补偿完成后任务流状态更新为 rolled_back，任一任务补偿失败时任务流状态更新为 failed
+*/
+type Compensator interface {
+	compctrl.Closer
+	// Start 启动补偿器，定期补偿 compensating 状态的任务流。
+	Start()
+}
+
+// compensator 补偿器
+type compensator struct {
+	bd backend.Backend
+
+	watchIntervalSec time.Duration
+	workerNumber     uint
+
+	wg      sync.WaitGroup
+	closeCh chan struct{}
+}
+
+// NewCompensator 创建一个补偿器
+func NewCompensator(bd backend.Backend, opt *CompensatorOption) Compensator {
+	return &compensator{
+		bd:               bd,
+		watchIntervalSec: time.Duration(opt.WatchIntervalSec) * time.Second,
+		workerNumber:     opt.WorkerNumber,
+		wg:               sync.WaitGroup{},
+		closeCh:          make(chan struct{}),
+	}
+}
+
+// Start 启动补偿器
+func (c *compensator) Start() {
+	c.wg.Add(1)
+	go c.watch()
+}
+
+func (c *compensator) watch() {
+	defer c.wg.Done()
+
+	for {
+		select {
+		case <-c.closeCh:
+			return
+		default:
+		}
+
+		kt := NewKit()
+		if err := c.Do(kt); err != nil {
+			logs.Errorf("%s: compensator do failed, err: %v, rid: %s", constant.AsyncTaskWarnSign, err, kt.Rid)
+		}
+
+		select {
+		case <-c.closeCh:
+			return
+		case <-time.After(c.watchIntervalSec):
+		}
+	}
+}
+
+// Do 查询 compensating 状态的任务流，并在协程池中并发补偿，等待本批任务流全部补偿结束后返回
+func (c *compensator) Do(kt *kit.Kit) error {
+	input := &backend.ListInput{
+		Filter: tools.EqualExpression("state", enumor.FlowCompensating),
+		Page:   &core.BasePage{Start: 0, Limit: listCompensatingFlowLimit},
+	}
+	flows, err := c.bd.ListFlow(kt, input)
+	if err != nil {
+		logs.Errorf("list compensating flow failed, err: %v, rid: %s", err, kt.Rid)
+		return err
+	}
+
+	pipeline := make(chan struct{}, c.workerNumber)
+	wg := sync.WaitGroup{}
+	for i := range flows {
+		select {
+		case <-c.closeCh:
+			wg.Wait()
+			return nil
+		case pipeline <- struct{}{}:
+		}
+
+		wg.Add(1)
+		go func(flow *Flow) {
+			defer func() {
+				<-pipeline
+				wg.Done()
+			}()
+
+			if err := c.compensate(flow.Kit, flow); err != nil {
+				logs.Errorf("%s: compensate flow failed, err: %v, flow: %s, rid: %s", constant.AsyncTaskWarnSign,
+					err, flow.ID, flow.Kit.Rid)
+			}
+		}(&Flow{Flow: flows[i], Kit: kt.NewSubKit()})
+	}
+	wg.Wait()
+
+	return nil
+}
+
+// compensate 补偿任务流，并根据补偿结果将任务流状态由 compensating 更新为 rolled_back 或 failed
+func (c *compensator) compensate(kt *kit.Kit, flow *Flow) error {
+	state, reason := enumor.FlowRolledBack, ErrSomeTaskExecFailed
+	if err := compensateFlow(kt, c.bd, flow); err != nil {
+		logs.Errorf("compensate flow failed, err: %v, flow: %s, rid: %s", err, flow.ID, kt.Rid)
+		state, reason = enumor.FlowFailed, fmt.Sprintf("%s, %v", ErrSomeTaskExecFailed, err)
+	}
+
+	if err := updateFlowStateAndReason(kt, c.bd, flow.ID, enumor.FlowCompensating, state, reason); err != nil {
+		logs.Errorf("update flow state to %s failed, err: %v, rid: %s", state, err, kt.Rid)
+		return err
+	}
+
+	return nil
+}
+
+// Close 关闭补偿器，等待正在补偿的任务流补偿结束
+func (c *compensator) Close() {
+	close(c.closeCh)
+	c.wg.Wait()
+}
@@ -49,11 +49,14 @@ Consumer 异步任务消费者。组件分为两类，公共组件、主节点
 	- executor（执行器）: 准备任务执行所需要的超时控制，共享数据等工具，并执行任务。
 	- commander（指挥者）:
 		1. 强制关闭处于执行中的任务
+		2. 取消、重试、继续执行任务流，人工设置任务状态
 */
 type Consumer interface {
 	compctrl.Closer
 	// Start 启动消费者，开始消费异步任务。
 	Start() error
+	// GetCommander 获取指挥者，需要在消费者启动后获取。
+	GetCommander() Commander
 }
 
 var _ Consumer = new(consumer)
@@ -106,10 +109,15 @@ func (csm *consumer) Start() error {
 	return nil
 }
 
+// GetCommander 获取指挥者。
+func (csm *consumer) GetCommander() Commander {
+	return csm.cmd
+}
+
 // initLeaderComponent 初始化主节点私有组件并启动，同时设置关闭函数
 func (csm *consumer) initLeaderComponent(opt *Option) {
 
-	handler := NewLeaderChangeHandler(csm.backend, csm.leader, opt)
+	handler := NewLeaderChangeHandler(csm.backend, csm.leader, csm.mc, opt)
 	handler.Start()
 	csm.closers = append(csm.closers, handler)
 
@@ -137,7 +145,7 @@ func (csm *consumer) initCommonComponent(opt *Option) {
 	csm.closers = append(csm.closers, csm.scheduler)
 
 	// 设置命令工具
-	csm.cmd = NewCommander(csm.executor)
+	csm.cmd = NewCommander(csm.backend, csm.executor)
 }
 
 // Close 执行异步任务框架所有组件的关闭函数
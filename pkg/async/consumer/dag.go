@@ -0,0 +1,340 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package consumer
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"hcm/pkg/api/core"
+	coreasync "hcm/pkg/api/core/async"
+	"hcm/pkg/async/action"
+	"hcm/pkg/async/backend"
+	"hcm/pkg/async/backend/model"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/runtime/filter"
+)
+
+// durationSampleLimit 计算 ActionName 历史平均执行耗时时，采样的最近执行成功的任务数量
+const durationSampleLimit = 100
+
+// BuildFlowDAG 构建任务流的有向无环图，节点来自任务树，边来自任务的依赖关系。history 为各 ActionName 的历史平均
+// 执行耗时，用于估算任务流的剩余执行时间。
+func BuildFlowDAG(flow *model.Flow, tasks []model.Task, history map[enumor.ActionName]time.Duration,
+	now time.Time) (*coreasync.AsyncFlowDAG, error) {
+
+	list := make([]*Task, 0, len(tasks))
+	for index := range tasks {
+		list = append(list, &Task{Task: tasks[index]})
+	}
+
+	if _, err := BuildTaskRoot(list); err != nil {
+		return nil, err
+	}
+
+	sorted, err := topologicalOrder(list)
+	if err != nil {
+		return nil, err
+	}
+
+	dag := &coreasync.AsyncFlowDAG{
+		FlowID: flow.ID,
+		State:  flow.State,
+		Nodes:  make([]coreasync.AsyncFlowDAGNode, 0, len(sorted)),
+		Edges:  make([]coreasync.AsyncFlowDAGEdge, 0),
+	}
+
+	done := 0
+	for _, task := range sorted {
+		node := convDAGNode(task, history, now)
+		dag.Nodes = append(dag.Nodes, node)
+
+		for _, parent := range task.DependOn {
+			dag.Edges = append(dag.Edges, coreasync.AsyncFlowDAGEdge{From: string(parent), To: string(task.ActionID)})
+		}
+
+		if task.State == enumor.TaskSuccess || task.State == enumor.TaskSkipped {
+			done++
+		}
+	}
+
+	if len(sorted) != 0 {
+		dag.Progress = math.Round(float64(done)*10000/float64(len(sorted))) / 100
+	}
+
+	dag.EtaSec = estimateFlowEta(flow.State, sorted, dag.Nodes, now)
+
+	return dag, nil
+}
+
+func convDAGNode(task *Task, history map[enumor.ActionName]time.Duration, now time.Time) coreasync.AsyncFlowDAGNode {
+	node := coreasync.AsyncFlowDAGNode{
+		TaskID:     task.ID,
+		ActionID:   string(task.ActionID),
+		ActionName: task.ActionName,
+		State:      task.State,
+		Reason:     task.Reason,
+	}
+
+	if estimate, exist := history[task.ActionName]; exist {
+		sec := int64(estimate.Seconds())
+		node.EstimateSec = &sec
+	}
+
+	if task.Stat == nil {
+		return node
+	}
+
+	node.StartedAt = task.Stat.StartedAt
+	node.EndedAt = task.Stat.EndedAt
+	if task.Stat.RunCount > 1 {
+		node.RetryCount = task.Stat.RunCount - 1
+	}
+
+	startedAt, err := parseStatTime(task.Stat.StartedAt)
+	if err != nil {
+		return node
+	}
+
+	switch {
+	case len(task.Stat.EndedAt) != 0:
+		if endedAt, err := parseStatTime(task.Stat.EndedAt); err == nil {
+			node.DurationSec = int64(endedAt.Sub(startedAt).Seconds())
+		}
+	case task.State == enumor.TaskRunning || task.State == enumor.TaskRollback:
+		node.DurationSec = int64(now.Sub(startedAt).Seconds())
+	}
+
+	return node
+}
+
+// estimateFlowEta 沿关键路径估算任务流的剩余执行时间，任务的完成时间为其前置任务最晚完成时间加上其剩余执行时间，
+// 执行中任务的剩余执行时间为估算执行时间减去已执行时间。任务流已结束或存在缺少历史执行耗时的未完成任务时无法估算。
+func estimateFlowEta(state enumor.FlowState, sorted []*Task, nodes []coreasync.AsyncFlowDAGNode, now time.Time) *int64 {
+	switch state {
+	case enumor.FlowPending, enumor.FlowScheduled, enumor.FlowRunning:
+	default:
+		return nil
+	}
+
+	finish := make(map[action.ActIDType]int64, len(sorted))
+	var eta int64
+	for index, task := range sorted {
+		var start int64
+		for _, parent := range task.DependOn {
+			if finish[parent] > start {
+				start = finish[parent]
+			}
+		}
+
+		var remain int64
+		switch task.State {
+		case enumor.TaskSuccess, enumor.TaskSkipped:
+		case enumor.TaskPending, enumor.TaskRunning, enumor.TaskRollback:
+			if nodes[index].EstimateSec == nil {
+				return nil
+			}
+
+			remain = *nodes[index].EstimateSec
+			if task.State != enumor.TaskPending {
+				remain -= nodes[index].DurationSec
+			}
+
+			if remain < 0 {
+				remain = 0
+			}
+		default:
+			// 存在失败或取消的任务，任务流无法按照预期结束
+			return nil
+		}
+
+		finish[task.ActionID] = start + remain
+		if finish[task.ActionID] > eta {
+			eta = finish[task.ActionID]
+		}
+	}
+
+	return &eta
+}
+
+func parseStatTime(t string) (time.Time, error) {
+	if len(t) == 0 {
+		return time.Time{}, errors.New("time is empty")
+	}
+
+	return time.ParseInLocation(constant.TimeStdFormat, t, time.Local)
+}
+
+// ListActionAvgDuration 查询 ActionName 的历史平均执行耗时，根据最近执行成功的任务的执行统计计算，
+// 没有执行统计的 ActionName 不返回。
+func ListActionAvgDuration(kt *kit.Kit, bd backend.Backend, names []enumor.ActionName) (
+	map[enumor.ActionName]time.Duration, error) {
+
+	result := make(map[enumor.ActionName]time.Duration, len(names))
+	for _, name := range names {
+		if _, exist := result[name]; exist {
+			continue
+		}
+
+		input := &backend.ListInput{
+			Filter: &filter.Expression{
+				Op: filter.And,
+				Rules: []filter.RuleFactory{
+					&filter.AtomRule{Field: "action_name", Op: filter.Equal.Factory(), Value: name},
+					&filter.AtomRule{Field: "state", Op: filter.Equal.Factory(), Value: enumor.TaskSuccess},
+				},
+			},
+			Page: &core.BasePage{
+				Start: 0,
+				Limit: durationSampleLimit,
+				Sort:  "id",
+				Order: core.Descending,
+			},
+		}
+		tasks, err := bd.ListTask(kt, input)
+		if err != nil {
+			logs.Errorf("list %s success task failed, err: %v, rid: %s", name, err, kt.Rid)
+			return nil, err
+		}
+
+		var total time.Duration
+		count := 0
+		for _, one := range tasks {
+			if one.Stat == nil {
+				continue
+			}
+
+			startedAt, err := parseStatTime(one.Stat.StartedAt)
+			if err != nil {
+				continue
+			}
+
+			endedAt, err := parseStatTime(one.Stat.EndedAt)
+			if err != nil {
+				continue
+			}
+
+			total += endedAt.Sub(startedAt)
+			count++
+		}
+
+		if count != 0 {
+			result[name] = total / time.Duration(count)
+		}
+	}
+
+	return result, nil
+}
+
+// dagStateColors 任务状态在 Graphviz DOT 中对应的节点颜色
+var dagStateColors = map[enumor.TaskState]string{
+	enumor.TaskPending:          "white",
+	enumor.TaskRunning:          "lightblue",
+	enumor.TaskRollback:         "orange",
+	enumor.TaskCancel:           "lightgrey",
+	enumor.TaskSuccess:          "palegreen",
+	enumor.TaskFailed:           "lightcoral",
+	enumor.TaskSkipped:          "khaki",
+	enumor.TaskCompensated:      "plum",
+	enumor.TaskCompensateFailed: "red",
+}
+
+// FlowDAGToDot 将任务流的有向无环图转换为 Graphviz DOT 格式。
+func FlowDAGToDot(dag *coreasync.AsyncFlowDAG) string {
+	buf := new(strings.Builder)
+	buf.WriteString(fmt.Sprintf("digraph %s {\n", strconv.Quote("flow_"+dag.FlowID)))
+	buf.WriteString(fmt.Sprintf("  label=%s;\n", strconv.Quote(fmt.Sprintf("flow: %s, state: %s, progress: %.2f%%",
+		dag.FlowID, dag.State, dag.Progress))))
+	buf.WriteString("  rankdir=LR;\n")
+	buf.WriteString("  node [shape=box, style=\"rounded,filled\"];\n")
+
+	for _, node := range dag.Nodes {
+		label := fmt.Sprintf("%s\n%s\n%s %ds", node.ActionID, node.ActionName, node.State, node.DurationSec)
+		if node.RetryCount != 0 {
+			label += fmt.Sprintf("\nretry: %d", node.RetryCount)
+		}
+
+		if node.Reason != nil && len(node.Reason.Message) != 0 {
+			label += "\n" + node.Reason.Message
+		}
+
+		color, exist := dagStateColors[node.State]
+		if !exist {
+			color = "white"
+		}
+
+		buf.WriteString(fmt.Sprintf("  %s [label=%s, fillcolor=%s];\n", strconv.Quote(node.ActionID),
+			strconv.Quote(label), color))
+	}
+
+	for _, edge := range dag.Edges {
+		buf.WriteString(fmt.Sprintf("  %s -> %s;\n", strconv.Quote(edge.From), strconv.Quote(edge.To)))
+	}
+
+	buf.WriteString("}\n")
+
+	return buf.String()
+}
+
+// topologicalOrder 按照依赖关系返回任务的拓扑序，即前置任务先于依赖它的后置任务。
+func topologicalOrder(tasks []*Task) ([]*Task, error) {
+	indegree := make(map[action.ActIDType]int, len(tasks))
+	children := make(map[action.ActIDType][]*Task, len(tasks))
+	for _, task := range tasks {
+		indegree[task.ActionID] = len(task.DependOn)
+		for _, parent := range task.DependOn {
+			children[parent] = append(children[parent], task)
+		}
+	}
+
+	queue := make([]*Task, 0, len(tasks))
+	for _, task := range tasks {
+		if indegree[task.ActionID] == 0 {
+			queue = append(queue, task)
+		}
+	}
+
+	sorted := make([]*Task, 0, len(tasks))
+	for len(queue) != 0 {
+		task := queue[0]
+		queue = queue[1:]
+		sorted = append(sorted, task)
+
+		for _, child := range children[task.ActionID] {
+			indegree[child.ActionID]--
+			if indegree[child.ActionID] == 0 {
+				queue = append(queue, child)
+			}
+		}
+	}
+
+	if len(sorted) != len(tasks) {
+		return nil, errors.New("tasks has cycle or not exist depend")
+	}
+
+	return sorted, nil
+}
@@ -0,0 +1,99 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package consumer
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"hcm/pkg/async/action"
+	"hcm/pkg/async/backend/model"
+	"hcm/pkg/criteria/enumor"
+	tableasync "hcm/pkg/dal/table/async"
+	"hcm/pkg/tools/times"
+)
+
+func TestBuildFlowDAG(t *testing.T) {
+	now := time.Now()
+	statAt := func(sec int) string {
+		return times.ConvStdTimeFormat(now.Add(-time.Duration(sec) * time.Second))
+	}
+
+	// 任务依赖关系为 1 -> (2, 3) -> 4，任务 1 执行成功，任务 2 重试一次后执行中，任务 3、4 等待执行
+	tasks := []model.Task{
+		{ID: "t1", ActionID: "1", ActionName: "a", State: enumor.TaskSuccess,
+			Stat: &tableasync.TaskStat{StartedAt: statAt(20), EndedAt: statAt(10), RunCount: 1}},
+		{ID: "t2", ActionID: "2", ActionName: "b", State: enumor.TaskRunning, DependOn: []action.ActIDType{"1"},
+			Stat: &tableasync.TaskStat{StartedAt: statAt(5), RunCount: 2}},
+		{ID: "t3", ActionID: "3", ActionName: "c", State: enumor.TaskPending, DependOn: []action.ActIDType{"1"}},
+		{ID: "t4", ActionID: "4", ActionName: "a", State: enumor.TaskPending, DependOn: []action.ActIDType{"2", "3"}},
+	}
+	history := map[enumor.ActionName]time.Duration{"a": 10 * time.Second, "b": 20 * time.Second,
+		"c": 30 * time.Second}
+
+	flow := &model.Flow{ID: "f1", State: enumor.FlowRunning}
+	dag, err := BuildFlowDAG(flow, tasks, history, now)
+	if err != nil {
+		t.Fatalf("build flow dag failed, err: %v", err)
+	}
+
+	if len(dag.Nodes) != 4 || len(dag.Edges) != 4 {
+		t.Fatalf("flow dag should have 4 nodes and 4 edges, but got %d nodes and %d edges", len(dag.Nodes),
+			len(dag.Edges))
+	}
+
+	if dag.Progress != 25 {
+		t.Errorf("flow progress should be 25, but got %v", dag.Progress)
+	}
+
+	// 关键路径为 1 -> 3 -> 4，剩余执行时间为任务 3 的 30s 加上任务 4 的 10s
+	if dag.EtaSec == nil || *dag.EtaSec != 40 {
+		t.Errorf("flow eta should be 40s, but got %v", dag.EtaSec)
+	}
+
+	for _, node := range dag.Nodes {
+		if node.ActionID == "1" && node.DurationSec != 10 {
+			t.Errorf("task 1 duration should be 10s, but got %d", node.DurationSec)
+		}
+
+		if node.ActionID == "2" && (node.RetryCount != 1 || node.DurationSec != 5) {
+			t.Errorf("task 2 retry count and duration should be 1 and 5s, but got %d and %d", node.RetryCount,
+				node.DurationSec)
+		}
+	}
+
+	dot := FlowDAGToDot(dag)
+	for _, expect := range []string{`digraph "flow_f1"`, `"1" -> "2";`, `"3" -> "4";`, "fillcolor=lightblue"} {
+		if !strings.Contains(dot, expect) {
+			t.Errorf("flow dag dot should contain %s, but got %s", expect, dot)
+		}
+	}
+
+	// 任务流失败后无法估算剩余执行时间
+	flow.State = enumor.FlowFailed
+	if dag, err = BuildFlowDAG(flow, tasks, history, now); err != nil {
+		t.Fatalf("build flow dag failed, err: %v", err)
+	}
+
+	if dag.EtaSec != nil {
+		t.Errorf("failed flow eta should be nil, but got %d", *dag.EtaSec)
+	}
+}
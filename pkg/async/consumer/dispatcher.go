@@ -26,31 +26,44 @@ import (
 
 	"hcm/pkg/api/core"
 	"hcm/pkg/async/backend"
+	"hcm/pkg/async/backend/model"
 	"hcm/pkg/async/consumer/leader"
 	"hcm/pkg/criteria/constant"
 	"hcm/pkg/criteria/enumor"
-	"hcm/pkg/dal/dao/tools"
 	"hcm/pkg/kit"
 	"hcm/pkg/logs"
+	"hcm/pkg/runtime/filter"
+	"hcm/pkg/tools/times"
 )
 
 // NewDispatcher new dispatcher.
-func NewDispatcher(bd backend.Backend, ld leader.Leader, opt *DispatcherOption) *Dispatcher {
+func NewDispatcher(bd backend.Backend, ld leader.Leader, mc *metric, opt *DispatcherOption) *Dispatcher {
+	queues := make(map[enumor.FlowPriority]DispatchQueueOption, len(opt.Queues))
+	for _, one := range opt.Queues {
+		queues[one.Priority] = one
+	}
+
 	return &Dispatcher{
 		watchIntervalSec: time.Duration(opt.WatchIntervalSec) * time.Second,
+		queues:           queues,
 		bd:               bd,
 		ld:               ld,
+		mc:               mc,
 		closeCh:          make(chan struct{}),
 		wg:               new(sync.WaitGroup),
 	}
 }
 
 // Dispatcher 派发器，负责将Pending状态的任务流，派发到指定节点去执行，并将Flow状态改为Scheduled。。
+// 任务流按照优先级从高到低派发，并受各优先级队列及租户的并发配额限制。
 type Dispatcher struct {
 	watchIntervalSec time.Duration
+	// queues 各优先级队列的并发配额，未配置的优先级不限制并发
+	queues map[enumor.FlowPriority]DispatchQueueOption
 
 	bd backend.Backend
 	ld leader.Leader
+	mc *metric
 
 	wg      *sync.WaitGroup
 	closeCh chan struct{}
@@ -82,18 +95,36 @@ func (d *Dispatcher) WatchPendingFlow() {
 	d.wg.Done()
 }
 
-// Do 监听处于Pending状态的流，并派发到指定节点。
+// Do 监听处于Pending状态的流，按照优先级从高到低，在并发配额内派发到指定节点。
 func (d *Dispatcher) Do(kt *kit.Kit) error {
-	input := &backend.ListInput{
-		Filter: tools.EqualExpression("state", enumor.FlowPending),
-		Page:   core.NewDefaultBasePage(),
-	}
-	flows, err := d.bd.ListFlow(kt, input)
+	active, err := d.countActiveFlow(kt)
 	if err != nil {
-		logs.Errorf("list flow failed, err: %v, rid: %s", err, kt.Rid)
 		return err
 	}
 
+	flows := make([]model.Flow, 0)
+	for _, priority := range enumor.FlowPriorities {
+		pending, err := d.listDuePendingFlow(kt, priority)
+		if err != nil {
+			return err
+		}
+		d.mc.queuePendingFlows.WithLabelValues(string(priority)).Set(float64(len(pending)))
+
+		for _, one := range pending {
+			if !active.allow(d.queues[priority], one) {
+				d.mc.queueThrottledFlows.WithLabelValues(string(priority)).Inc()
+				continue
+			}
+
+			active.add(one)
+			flows = append(flows, one)
+		}
+	}
+
+	for _, priority := range enumor.FlowPriorities {
+		d.mc.queueActiveFlows.WithLabelValues(string(priority)).Set(float64(active.queue[priority]))
+	}
+
 	if len(flows) == 0 {
 		logs.V(3).Infof("currently no task flows to assign, skip handleRunningFlow, rid: %s", kt.Rid)
 		return nil
@@ -126,6 +157,106 @@ func (d *Dispatcher) Do(kt *kit.Kit) error {
 	return nil
 }
 
+// listDuePendingFlow 查询指定优先级下已经到达执行时间的Pending状态任务流，延时任务流在 run_at 到达前保持 Pending 状态。
+func (d *Dispatcher) listDuePendingFlow(kt *kit.Kit, priority enumor.FlowPriority) ([]model.Flow, error) {
+	input := &backend.ListInput{
+		Filter: &filter.Expression{
+			Op: filter.And,
+			Rules: []filter.RuleFactory{
+				&filter.AtomRule{Field: "state", Op: filter.Equal.Factory(), Value: enumor.FlowPending},
+				&filter.AtomRule{Field: "priority", Op: filter.Equal.Factory(), Value: priority},
+				&filter.AtomRule{Field: "run_at", Op: filter.LessThanEqual.Factory(),
+					Value: times.ConvStdTimeFormat(time.Now())},
+			},
+		},
+		Page: core.NewDefaultBasePage(),
+	}
+	flows, err := d.bd.ListFlow(kt, input)
+	if err != nil {
+		logs.Errorf("list %s priority pending flow failed, err: %v, rid: %s", priority, err, kt.Rid)
+		return nil, err
+	}
+
+	return flows, nil
+}
+
+// countActiveFlow 统计处于调度中和执行中的任务流在各优先级队列及各租户下的数量。
+func (d *Dispatcher) countActiveFlow(kt *kit.Kit) (*activeFlowCounter, error) {
+	input := &backend.ListInput{
+		Filter: &filter.Expression{
+			Op: filter.And,
+			Rules: []filter.RuleFactory{
+				&filter.AtomRule{Field: "state", Op: filter.In.Factory(),
+					Value: []enumor.FlowState{enumor.FlowScheduled, enumor.FlowRunning}},
+			},
+		},
+		Fields: []string{"id", "priority", "tenant"},
+		Page: &core.BasePage{
+			Start: 0,
+			Limit: core.DefaultMaxPageLimit,
+		},
+	}
+
+	counter := newActiveFlowCounter()
+	for {
+		result, err := d.bd.ListFlow(kt, input)
+		if err != nil {
+			logs.Errorf("list active flow failed, err: %v, rid: %s", err, kt.Rid)
+			return nil, err
+		}
+
+		for _, one := range result {
+			counter.add(one)
+		}
+
+		if len(result) < int(core.DefaultMaxPageLimit) {
+			break
+		}
+
+		input.Page.Start += uint32(input.Page.Limit)
+	}
+
+	return counter, nil
+}
+
+func newActiveFlowCounter() *activeFlowCounter {
+	return &activeFlowCounter{
+		queue:  make(map[enumor.FlowPriority]uint),
+		tenant: make(map[enumor.FlowPriority]map[string]uint),
+	}
+}
+
+// activeFlowCounter 处于调度中和执行中的任务流计数器
+type activeFlowCounter struct {
+	// queue 各优先级队列的任务流数量
+	queue map[enumor.FlowPriority]uint
+	// tenant 各优先级队列中各租户的任务流数量
+	tenant map[enumor.FlowPriority]map[string]uint
+}
+
+// allow 判断任务流派发后是否会超出队列或租户的并发配额，并发配额为0表示不限制，未设置租户的任务流不受租户配额限制。
+func (c *activeFlowCounter) allow(queue DispatchQueueOption, flow model.Flow) bool {
+	if queue.MaxConcurrency != 0 && c.queue[flow.Priority] >= queue.MaxConcurrency {
+		return false
+	}
+
+	if queue.TenantConcurrency != 0 && len(flow.Tenant) != 0 &&
+		c.tenant[flow.Priority][flow.Tenant] >= queue.TenantConcurrency {
+		return false
+	}
+
+	return true
+}
+
+func (c *activeFlowCounter) add(flow model.Flow) {
+	c.queue[flow.Priority]++
+
+	if _, exist := c.tenant[flow.Priority]; !exist {
+		c.tenant[flow.Priority] = make(map[string]uint)
+	}
+	c.tenant[flow.Priority][flow.Tenant]++
+}
+
 // Close dispatcher
 func (d *Dispatcher) Close() {
 
@@ -0,0 +1,120 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package consumer
+
+import (
+	"testing"
+
+	"hcm/pkg/async/backend"
+	"hcm/pkg/async/backend/model"
+	"hcm/pkg/criteria/enumor"
+	tableasync "hcm/pkg/dal/table/async"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+// fakeLeader 固定为主节点的测试用 leader
+type fakeLeader struct{}
+
+// IsLeader ...
+func (ld fakeLeader) IsLeader() bool {
+	return true
+}
+
+// AliveNodes ...
+func (ld fakeLeader) AliveNodes() ([]string, error) {
+	return []string{"node-1", "node-2"}, nil
+}
+
+// CurrNode ...
+func (ld fakeLeader) CurrNode() string {
+	return "node-1"
+}
+
+func createPriorityFlow(t *testing.T, bd backend.Backend, priority enumor.FlowPriority, tenant string) string {
+	flowID, err := bd.CreateFlow(newTestKit(), &model.Flow{
+		Name:      "priority_test",
+		ShareData: tableasync.NewShareData(),
+		Priority:  priority,
+		Tenant:    tenant,
+		Tasks: []model.Task{{
+			FlowName:   "priority_test",
+			ActionID:   "1",
+			ActionName: enumor.ActionCreateFactoryTest,
+			Retry:      &tableasync.Retry{Enable: false},
+		}},
+	})
+	if err != nil {
+		t.Fatalf("create flow failed, err: %v", err)
+	}
+
+	return flowID
+}
+
+func TestDispatcherQueueQuota(t *testing.T) {
+	bd := backend.NewMemory()
+	mc := initMetric(prometheus.NewRegistry())
+	dis := NewDispatcher(bd, fakeLeader{}, mc, &DispatcherOption{
+		WatchIntervalSec: 1,
+		Queues: []DispatchQueueOption{
+			{Priority: enumor.FlowPriorityLow, MaxConcurrency: 2, TenantConcurrency: 1},
+		},
+	})
+
+	// 低优先级队列中已有租户 a 的任务流在执行
+	running := createPriorityFlow(t, bd, enumor.FlowPriorityLow, "a")
+	if err := bd.BatchUpdateFlow(newTestKit(), []model.Flow{{ID: running, State: enumor.FlowRunning}}); err != nil {
+		t.Fatalf("update flow failed, err: %v", err)
+	}
+
+	expects := map[string]enumor.FlowState{
+		createPriorityFlow(t, bd, enumor.FlowPriorityLow, "a"):    enumor.FlowPending,
+		createPriorityFlow(t, bd, enumor.FlowPriorityLow, "b"):    enumor.FlowScheduled,
+		createPriorityFlow(t, bd, enumor.FlowPriorityLow, "c"):    enumor.FlowPending,
+		createPriorityFlow(t, bd, enumor.FlowPriorityHigh, "a"):   enumor.FlowScheduled,
+		createPriorityFlow(t, bd, enumor.FlowPriorityHigh, "a"):   enumor.FlowScheduled,
+		createPriorityFlow(t, bd, enumor.FlowPriorityNormal, "a"): enumor.FlowScheduled,
+	}
+
+	if err := dis.Do(newTestKit()); err != nil {
+		t.Fatalf("dispatcher do failed, err: %v", err)
+	}
+
+	for id, state := range expects {
+		flow, err := getFlow(newTestKit(), bd, id)
+		if err != nil {
+			t.Fatalf("get flow failed, err: %v", err)
+		}
+
+		if flow.State != state {
+			t.Errorf("flow %s(%s, %s) state should be %s, but got %s", id, flow.Priority, flow.Tenant, state,
+				flow.State)
+		}
+	}
+
+	if cnt := testutil.ToFloat64(mc.queueThrottledFlows.WithLabelValues(string(enumor.FlowPriorityLow))); cnt != 2 {
+		t.Errorf("low priority queue throttled flows should be 2, but got %v", cnt)
+	}
+
+	if cnt := testutil.ToFloat64(mc.queueActiveFlows.WithLabelValues(string(enumor.FlowPriorityHigh))); cnt != 2 {
+		t.Errorf("high priority queue active flows should be 2, but got %v", cnt)
+	}
+}
@@ -22,6 +22,7 @@ package consumer
 import (
 	"context"
 	"sync"
+	"time"
 
 	"hcm/pkg/async/action/run"
 	"hcm/pkg/async/backend"
@@ -54,6 +55,7 @@ var _ Executor = new(executor)
 type executor struct {
 	workerNumber       uint
 	taskExecTimeoutSec uint
+	heartbeatInterval  time.Duration
 
 	cancelMap   sync.Map
 	workerWg    sync.WaitGroup
@@ -83,6 +85,7 @@ func NewExecutor(bd backend.Backend, opt *ExecutorOption) Executor {
 		closeCh:            make(chan struct{}, 1),
 		workerNumber:       opt.WorkerNumber,
 		taskExecTimeoutSec: opt.TaskExecTimeoutSec,
+		heartbeatInterval:  time.Duration(opt.HeartbeatIntervalSec) * time.Second,
 	}
 }
 
@@ -128,9 +131,10 @@ func (exec *executor) initWorkerTask(flow *Flow, task *Task) {
 	}
 
 	// 设置task执行所需要的 kit，更新Task函数，所属流
-	task.InitDep(run.NewExecuteContext(task.Kit, flow.ShareData), func(kt *kit.Kit, task *model.Task) error {
-		return exec.backend.UpdateTask(kt, task)
-	}, flow)
+	task.InitDep(run.NewExecuteContext(task.Kit, flow.ShareData, newTaskProgress(task)),
+		func(kt *kit.Kit, task *model.Task) error {
+			return exec.backend.UpdateTask(kt, task)
+		}, flow, listDependResults(exec.backend))
 
 	// cancel存储到cancelMap中
 	exec.cancelMap.Store(task.ID, cancel)
@@ -157,12 +161,17 @@ func (exec *executor) workerDo(task *Task) (err error) {
 	// cancelMap清理执行成功/失败的任务
 	defer exec.cancelMap.Delete(task.ID)
 
+	// 任务执行期间定期上报心跳，看门狗依据心跳判断执行节点是否存活
+	stopCh := make(chan struct{})
+	go exec.keepHeartbeat(task, stopCh)
+
 	// 执行任务
 	if err = task.Run(); err != nil {
 		logs.Errorf("task run failed, err: %v, task: %+v, rid: %s", err, task, task.Kit.Rid)
 
 		// 无论任务成功还是失败，都需要交给调度器分析任务流的状态
 	}
+	close(stopCh)
 
 	// 执行完的任务回写到调度器用于获取待执行的任务
 	exec.GetSchedulerFunc().EntryTask(task)
@@ -170,6 +179,23 @@ func (exec *executor) workerDo(task *Task) (err error) {
 	return err
 }
 
+// keepHeartbeat 按照心跳周期上报任务心跳，直到任务执行结束
+func (exec *executor) keepHeartbeat(task *Task, stopCh <-chan struct{}) {
+	ticker := time.NewTicker(exec.heartbeatInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stopCh:
+			return
+		case <-ticker.C:
+			if err := task.ExecuteKit.Progress().Heartbeat(); err != nil {
+				logs.Errorf("task report heartbeat failed, err: %v, id: %s, rid: %s", err, task.ID, task.Kit.Rid)
+			}
+		}
+	}
+}
+
 // Push 任务写入到initQueue
 func (exec *executor) Push(flow *Flow, task *Task) {
 
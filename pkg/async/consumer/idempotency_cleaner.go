@@ -0,0 +1,107 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package consumer
+
+import (
+	"sync"
+	"time"
+
+	"hcm/pkg/async/backend"
+	"hcm/pkg/async/compctrl"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+)
+
+/*
+IdempotencyCleaner （幂等键清理器）:
+ 1. 定期删除创建任务流时使用的已过期幂等键，过期的幂等键可以被重新使用，清理只用于控制幂等键表的大小
+*/
+type IdempotencyCleaner interface {
+	compctrl.Closer
+	// Start 启动幂等键清理器，定期删除过期的幂等键。
+	Start()
+}
+
+// idempotencyCleaner 幂等键清理器
+type idempotencyCleaner struct {
+	bd backend.Backend
+
+	watchIntervalSec time.Duration
+
+	wg      sync.WaitGroup
+	closeCh chan struct{}
+}
+
+// NewIdempotencyCleaner 创建一个幂等键清理器
+func NewIdempotencyCleaner(bd backend.Backend, opt *IdempotencyCleanerOption) IdempotencyCleaner {
+	return &idempotencyCleaner{
+		bd:               bd,
+		watchIntervalSec: time.Duration(opt.WatchIntervalSec) * time.Second,
+		wg:               sync.WaitGroup{},
+		closeCh:          make(chan struct{}),
+	}
+}
+
+// Start 启动幂等键清理器
+func (c *idempotencyCleaner) Start() {
+	c.wg.Add(1)
+	go c.watch()
+}
+
+func (c *idempotencyCleaner) watch() {
+	defer c.wg.Done()
+
+	for {
+		select {
+		case <-c.closeCh:
+			return
+		default:
+		}
+
+		kt := NewKit()
+		if err := c.Do(kt); err != nil {
+			logs.Errorf("%s: idempotency cleaner do failed, err: %v, rid: %s", constant.AsyncTaskWarnSign, err,
+				kt.Rid)
+		}
+
+		select {
+		case <-c.closeCh:
+			return
+		case <-time.After(c.watchIntervalSec):
+		}
+	}
+}
+
+// Do 删除已过期的幂等键
+func (c *idempotencyCleaner) Do(kt *kit.Kit) error {
+	if err := c.bd.DeleteExpiredFlowIdempotency(kt); err != nil {
+		logs.Errorf("delete expired flow idempotency failed, err: %v, rid: %s", err, kt.Rid)
+		return err
+	}
+
+	return nil
+}
+
+// Close 关闭幂等键清理器
+func (c *idempotencyCleaner) Close() {
+	close(c.closeCh)
+	c.wg.Wait()
+}
@@ -30,11 +30,12 @@ import (
 )
 
 // NewLeaderChangeHandler new leader change handler.
-func NewLeaderChangeHandler(bd backend.Backend, ld leader.Leader, opt *Option) *LeaderChangeHandler {
+func NewLeaderChangeHandler(bd backend.Backend, ld leader.Leader, mc *metric, opt *Option) *LeaderChangeHandler {
 	return &LeaderChangeHandler{
 		opt:     opt,
 		ld:      ld,
 		bd:      bd,
+		mc:      mc,
 		closeCh: make(chan struct{}),
 		closers: make([]compctrl.Closer, 0),
 		wg:      sync.WaitGroup{},
@@ -47,9 +48,15 @@ type LeaderChangeHandler struct {
 
 	ld leader.Leader
 	bd backend.Backend
+	mc *metric
 
-	dispatcher *Dispatcher
-	watchDog   WatchDog
+	dispatcher  *Dispatcher
+	watchDog    WatchDog
+	timer       Timer
+	notifier    Notifier
+	archiver    Archiver
+	cleaner     IdempotencyCleaner
+	compensator Compensator
 
 	closeCh chan struct{}
 
@@ -103,7 +110,7 @@ func (handler *LeaderChangeHandler) Do() {
 }
 
 func (handler *LeaderChangeHandler) startLeaderComponent() {
-	dis := NewDispatcher(handler.bd, handler.ld, handler.opt.Dispatcher)
+	dis := NewDispatcher(handler.bd, handler.ld, handler.mc, handler.opt.Dispatcher)
 	dis.Start()
 	handler.closers = append(handler.closers, dis)
 	handler.dispatcher = dis
@@ -113,6 +120,38 @@ func (handler *LeaderChangeHandler) startLeaderComponent() {
 	wd.Start()
 	handler.closers = append(handler.closers, wd)
 	handler.watchDog = wd
+
+	// 初始化定时器，按照定时任务流的 cron 表达式生成任务流
+	tm := NewTimer(handler.bd, handler.opt.Timer)
+	tm.Start()
+	handler.closers = append(handler.closers, tm)
+	handler.timer = tm
+
+	// 初始化事件通知器，将任务流生命周期事件投递到订阅的 webhook
+	nt := NewNotifier(handler.bd, handler.opt.Notifier)
+	nt.Start()
+	handler.closers = append(handler.closers, nt)
+	handler.notifier = nt
+
+	// 初始化归档器，保留天数为0时不归档
+	if handler.opt.Archiver.RetentionDays > 0 {
+		ac := NewArchiver(handler.bd, handler.opt.Archiver)
+		ac.Start()
+		handler.closers = append(handler.closers, ac)
+		handler.archiver = ac
+	}
+
+	// 初始化幂等键清理器，删除创建任务流时使用的已过期幂等键
+	ic := NewIdempotencyCleaner(handler.bd, handler.opt.IdempotencyCleaner)
+	ic.Start()
+	handler.closers = append(handler.closers, ic)
+	handler.cleaner = ic
+
+	// 初始化补偿器，补偿回滚策略为 saga 的失败任务流中执行成功的任务
+	cp := NewCompensator(handler.bd, handler.opt.Compensator)
+	cp.Start()
+	handler.closers = append(handler.closers, cp)
+	handler.compensator = cp
 }
 
 // Close 主从切换处理器
@@ -20,6 +20,8 @@
 package consumer
 
 import (
+	"hcm/pkg/metrics"
+
 	"github.com/prometheus/client_golang/prometheus"
 )
 
@@ -28,8 +30,41 @@ import (
 func initMetric(register prometheus.Registerer) *metric {
 	m := new(metric)
 
+	m.queuePendingFlows = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: metrics.AsyncSubSys,
+		Name:      "queue_pending_flows",
+		Help:      "the number of due pending flows waiting to be dispatched in each priority queue",
+	}, []string{labelPriority})
+	register.MustRegister(m.queuePendingFlows)
+
+	m.queueActiveFlows = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: metrics.AsyncSubSys,
+		Name:      "queue_active_flows",
+		Help:      "the number of scheduled or running flows in each priority queue",
+	}, []string{labelPriority})
+	register.MustRegister(m.queueActiveFlows)
+
+	m.queueThrottledFlows = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: metrics.AsyncSubSys,
+		Name:      "queue_throttled_flows_total",
+		Help:      "the total times of flows skipped by dispatcher because of queue or tenant concurrency quota",
+	}, []string{labelPriority})
+	register.MustRegister(m.queueThrottledFlows)
+
 	return m
 }
 
+// labelPriority 任务流优先级标签
+const labelPriority = "priority"
+
 type metric struct {
+	// queuePendingFlows 各优先级队列中已到执行时间、等待派发的任务流数量
+	queuePendingFlows *prometheus.GaugeVec
+	// queueActiveFlows 各优先级队列中处于调度中和执行中的任务流数量
+	queueActiveFlows *prometheus.GaugeVec
+	// queueThrottledFlows 各优先级队列中因并发配额限制而未被派发的任务流次数
+	queueThrottledFlows *prometheus.CounterVec
 }
@@ -0,0 +1,435 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package consumer
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"hcm/pkg/api/core"
+	"hcm/pkg/async/backend"
+	"hcm/pkg/async/backend/model"
+	"hcm/pkg/async/compctrl"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/enumor"
+	tableasync "hcm/pkg/dal/table/async"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/runtime/filter"
+	"hcm/pkg/tools/json"
+	"hcm/pkg/tools/times"
+)
+
+/*
+Notifier （事件通知器）:
+ 1. 查询待投递，且下次投递时间已经到达的任务流事件
+ 2. 根据任务流所属应用订阅的 webhook 及任务流自定义的 webhook，确定事件需要投递的地址
+ 3. 使用 HMAC-SHA256 对请求签名后投递事件，webhook 未配置签名密钥时使用默认密钥，已投递成功的地址不再重复投递
+ 4. 投递失败时按照指数退避重新投递，超过最大投递次数后事件置为投递失败
+*/
+type Notifier interface {
+	compctrl.Closer
+	// Start 启动事件通知器，将任务流生命周期事件投递到订阅的 webhook。
+	Start()
+}
+
+const (
+	// maxEventsPerRound 单轮最多处理的事件数量，剩余的事件在下一轮处理
+	maxEventsPerRound = 100
+	// maxBackoff 投递失败后的最大重试间隔
+	maxBackoff = time.Hour
+	// maxDeliveryMessageLen 投递日志中记录的失败原因最大长度
+	maxDeliveryMessageLen = 256
+)
+
+const (
+	// WebhookEventHeader 回调请求头，值为事件类型
+	WebhookEventHeader = "X-Hcm-Event"
+	// WebhookEventIDHeader 回调请求头，值为事件ID，接收方可以用于去重
+	WebhookEventIDHeader = "X-Hcm-Event-Id"
+	// WebhookTimestampHeader 回调请求头，值为请求签名时的 unix 时间戳
+	WebhookTimestampHeader = "X-Hcm-Timestamp"
+	// WebhookSignatureHeader 回调请求头，值为 sha256=hex(HMAC-SHA256(secret, timestamp + "." + body))
+	WebhookSignatureHeader = "X-Hcm-Signature"
+)
+
+// eventFlowStates 任务流事件对应的任务流状态，回调请求中的任务流状态为事件发生时的状态，而不是投递时任务流的当前状态
+var eventFlowStates = map[enumor.FlowEventType]enumor.FlowState{
+	enumor.FlowEventCreated:    enumor.FlowPending,
+	enumor.FlowEventRunning:    enumor.FlowRunning,
+	enumor.FlowEventSuccess:    enumor.FlowSuccess,
+	enumor.FlowEventFailed:     enumor.FlowFailed,
+	enumor.FlowEventCancelled:  enumor.FlowCancel,
+	enumor.FlowEventRolledBack: enumor.FlowRolledBack,
+}
+
+// WebhookPayload 回调请求体
+type WebhookPayload struct {
+	EventID    string               `json:"event_id"`
+	Event      enumor.FlowEventType `json:"event"`
+	FlowID     string               `json:"flow_id"`
+	FlowName   enumor.FlowName      `json:"flow_name"`
+	FlowState  enumor.FlowState     `json:"flow_state"`
+	AppCode    string               `json:"app_code"`
+	Tenant     string               `json:"tenant"`
+	Reason     *tableasync.Reason   `json:"reason,omitempty"`
+	OccurredAt string               `json:"occurred_at"`
+}
+
+// SignWebhook 计算回调请求签名
+func SignWebhook(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// notifier 事件通知器
+type notifier struct {
+	bd  backend.Backend
+	cli *http.Client
+
+	watchIntervalSec time.Duration
+	maxAttempts      uint
+	backoffBase      time.Duration
+	secret           string
+	// subscriptions 应用编码与其订阅的 webhook 的映射
+	subscriptions map[string][]WebhookSubscription
+
+	wg      sync.WaitGroup
+	closeCh chan struct{}
+}
+
+// NewNotifier 创建一个事件通知器
+func NewNotifier(bd backend.Backend, opt *NotifierOption) Notifier {
+	subscriptions := make(map[string][]WebhookSubscription)
+	for _, one := range opt.Subscriptions {
+		subscriptions[one.AppCode] = append(subscriptions[one.AppCode], one)
+	}
+
+	return &notifier{
+		bd:               bd,
+		cli:              &http.Client{Timeout: time.Duration(opt.TimeoutSec) * time.Second},
+		watchIntervalSec: time.Duration(opt.WatchIntervalSec) * time.Second,
+		maxAttempts:      opt.MaxAttempts,
+		backoffBase:      time.Duration(opt.BackoffBaseSec) * time.Second,
+		secret:           opt.Secret,
+		subscriptions:    subscriptions,
+		wg:               sync.WaitGroup{},
+		closeCh:          make(chan struct{}),
+	}
+}
+
+// Start 启动事件通知器
+func (n *notifier) Start() {
+	n.wg.Add(1)
+	go n.watch()
+}
+
+func (n *notifier) watch() {
+	defer n.wg.Done()
+
+	for {
+		select {
+		case <-n.closeCh:
+			return
+		default:
+		}
+
+		kt := NewKit()
+		if err := n.Do(kt); err != nil {
+			logs.Errorf("%s: notifier do failed, err: %v, rid: %s", constant.AsyncTaskWarnSign, err, kt.Rid)
+		}
+
+		time.Sleep(n.watchIntervalSec)
+	}
+}
+
+// Do 投递到期的任务流事件
+func (n *notifier) Do(kt *kit.Kit) error {
+	now := time.Now()
+	input := &backend.ListInput{
+		Filter: &filter.Expression{
+			Op: filter.And,
+			Rules: []filter.RuleFactory{
+				&filter.AtomRule{Field: "state", Op: filter.Equal.Factory(), Value: enumor.FlowEventStatePending},
+				&filter.AtomRule{Field: "next_attempt_at", Op: filter.LessThanEqual.Factory(),
+					Value: times.ConvStdTimeFormat(now)},
+			},
+		},
+		Page: &core.BasePage{Start: 0, Limit: maxEventsPerRound},
+	}
+	events, err := n.bd.ListFlowEvent(kt, input)
+	if err != nil {
+		logs.Errorf("list flow event failed, err: %v, rid: %s", err, kt.Rid)
+		return err
+	}
+
+	if len(events) == 0 {
+		return nil
+	}
+
+	flows, err := n.listFlows(kt, events)
+	if err != nil {
+		return err
+	}
+
+	for index := range events {
+		if err = n.deliver(kt, &events[index], flows[events[index].FlowID], now); err != nil {
+			logs.Errorf("%s: deliver flow event failed, err: %v, id: %s, rid: %s", constant.AsyncTaskWarnSign,
+				err, events[index].ID, kt.Rid)
+			continue
+		}
+	}
+
+	return nil
+}
+
+// listFlows 查询事件所属的任务流
+func (n *notifier) listFlows(kt *kit.Kit, events []model.FlowEvent) (map[string]*model.Flow, error) {
+	ids := make([]string, 0, len(events))
+	exist := make(map[string]struct{}, len(events))
+	for _, one := range events {
+		if _, ok := exist[one.FlowID]; ok {
+			continue
+		}
+		exist[one.FlowID] = struct{}{}
+		ids = append(ids, one.FlowID)
+	}
+
+	input := &backend.ListInput{
+		Filter: &filter.Expression{
+			Op: filter.And,
+			Rules: []filter.RuleFactory{
+				&filter.AtomRule{Field: "id", Op: filter.In.Factory(), Value: ids},
+			},
+		},
+		Page: core.NewDefaultBasePage(),
+	}
+	flows, err := n.bd.ListFlow(kt, input)
+	if err != nil {
+		logs.Errorf("list flow failed, err: %v, ids: %v, rid: %s", err, ids, kt.Rid)
+		return nil, err
+	}
+
+	result := make(map[string]*model.Flow, len(flows))
+	for index := range flows {
+		result[flows[index].ID] = &flows[index]
+	}
+
+	return result, nil
+}
+
+// webhook 事件需要投递的地址及签名密钥
+type webhook struct {
+	url    string
+	secret string
+}
+
+// resolveWebhooks 获取任务流事件需要投递的地址，按地址去重，应用订阅的 webhook 优先
+func (n *notifier) resolveWebhooks(flow *model.Flow, event enumor.FlowEventType) []webhook {
+	result := make([]webhook, 0)
+	exist := make(map[string]struct{})
+
+	for _, one := range n.subscriptions[flow.AppCode] {
+		target := tableasync.WebhookTarget{URL: one.URL, Events: one.Events}
+		if _, ok := exist[one.URL]; ok || !target.Subscribed(event) {
+			continue
+		}
+		exist[one.URL] = struct{}{}
+
+		secret := one.Secret
+		if len(secret) == 0 {
+			secret = n.secret
+		}
+		result = append(result, webhook{url: one.URL, secret: secret})
+	}
+
+	for _, one := range flow.Webhooks {
+		if _, ok := exist[one.URL]; ok || !one.Subscribed(event) {
+			continue
+		}
+		exist[one.URL] = struct{}{}
+
+		secret := one.Secret
+		if len(secret) == 0 {
+			secret = n.secret
+		}
+		result = append(result, webhook{url: one.URL, secret: secret})
+	}
+
+	return result
+}
+
+// deliver 投递单个任务流事件，并记录投递结果
+func (n *notifier) deliver(kt *kit.Kit, event *model.FlowEvent, flow *model.Flow, now time.Time) error {
+	update := &model.FlowEvent{
+		ID:         event.ID,
+		Attempts:   event.Attempts + 1,
+		Deliveries: event.Deliveries,
+	}
+
+	if flow == nil {
+		update.State = enumor.FlowEventStateFailed
+		update.Deliveries = append(update.Deliveries, tableasync.WebhookDelivery{
+			Attempt:     update.Attempts,
+			Message:     fmt.Sprintf("flow: %s not found", event.FlowID),
+			DeliveredAt: times.ConvStdTimeFormat(now),
+		})
+		return n.bd.UpdateFlowEvent(kt, update)
+	}
+
+	// 已经投递成功的地址不再重复投递
+	delivered := make(map[string]struct{})
+	for _, one := range event.Deliveries {
+		if one.Success {
+			delivered[one.URL] = struct{}{}
+		}
+	}
+
+	var body []byte
+	allSuccess := true
+	for _, one := range n.resolveWebhooks(flow, event.Event) {
+		if _, ok := delivered[one.url]; ok {
+			continue
+		}
+
+		if body == nil {
+			var err error
+			if body, err = json.Marshal(buildWebhookPayload(event, flow)); err != nil {
+				return err
+			}
+		}
+
+		delivery := n.post(kt, one, event, body)
+		delivery.Attempt = update.Attempts
+		delivery.DeliveredAt = times.ConvStdTimeFormat(now)
+		update.Deliveries = append(update.Deliveries, delivery)
+
+		if !delivery.Success {
+			allSuccess = false
+			logs.Warnf("deliver flow event failed, id: %s, url: %s, attempt: %d, message: %s, rid: %s", event.ID,
+				one.url, update.Attempts, delivery.Message, kt.Rid)
+		}
+	}
+
+	switch {
+	case allSuccess:
+		update.State = enumor.FlowEventStateDelivered
+	case update.Attempts >= n.maxAttempts:
+		update.State = enumor.FlowEventStateFailed
+	default:
+		update.NextAttemptAt = times.ConvStdTimeFormat(now.Add(n.backoff(update.Attempts)))
+	}
+
+	return n.bd.UpdateFlowEvent(kt, update)
+}
+
+// backoff 第 attempt 次投递失败后的重试间隔
+func (n *notifier) backoff(attempt uint) time.Duration {
+	interval := n.backoffBase
+	for i := uint(1); i < attempt; i++ {
+		interval *= 2
+		if interval >= maxBackoff {
+			return maxBackoff
+		}
+	}
+
+	return interval
+}
+
+// post 向 webhook 发送签名后的回调请求
+func (n *notifier) post(kt *kit.Kit, hook webhook, event *model.FlowEvent, body []byte) tableasync.WebhookDelivery {
+	delivery := tableasync.WebhookDelivery{URL: hook.url}
+
+	if len(hook.secret) == 0 {
+		delivery.Message = "webhook secret is not configured"
+		return delivery
+	}
+
+	req, err := http.NewRequestWithContext(kt.Ctx, http.MethodPost, hook.url, bytes.NewReader(body))
+	if err != nil {
+		delivery.Message = truncateMessage(err.Error())
+		return delivery
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookEventHeader, string(event.Event))
+	req.Header.Set(WebhookEventIDHeader, event.ID)
+	req.Header.Set(WebhookTimestampHeader, timestamp)
+	req.Header.Set(WebhookSignatureHeader, SignWebhook(hook.secret, timestamp, body))
+	req.Header.Set(constant.RidKey, kt.Rid)
+
+	resp, err := n.cli.Do(req)
+	if err != nil {
+		delivery.Message = truncateMessage(err.Error())
+		return delivery
+	}
+	defer resp.Body.Close()
+
+	delivery.StatusCode = resp.StatusCode
+	if resp.StatusCode >= http.StatusOK && resp.StatusCode < http.StatusMultipleChoices {
+		delivery.Success = true
+		return delivery
+	}
+
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, maxDeliveryMessageLen))
+	delivery.Message = truncateMessage(fmt.Sprintf("unexpected status code: %d, body: %s", resp.StatusCode,
+		respBody))
+	return delivery
+}
+
+func buildWebhookPayload(event *model.FlowEvent, flow *model.Flow) *WebhookPayload {
+	return &WebhookPayload{
+		EventID:    event.ID,
+		Event:      event.Event,
+		FlowID:     flow.ID,
+		FlowName:   flow.Name,
+		FlowState:  eventFlowStates[event.Event],
+		AppCode:    flow.AppCode,
+		Tenant:     flow.Tenant,
+		Reason:     flow.Reason,
+		OccurredAt: event.CreatedAt,
+	}
+}
+
+func truncateMessage(msg string) string {
+	if len(msg) <= maxDeliveryMessageLen {
+		return msg
+	}
+
+	return msg[:maxDeliveryMessageLen]
+}
+
+// Close 关闭事件通知器
+func (n *notifier) Close() {
+	close(n.closeCh)
+	n.wg.Wait()
+}
@@ -0,0 +1,248 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package consumer
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"hcm/pkg/api/core"
+	"hcm/pkg/async/backend"
+	"hcm/pkg/async/backend/model"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/dal/dao/tools"
+	tableasync "hcm/pkg/dal/table/async"
+	"hcm/pkg/tools/json"
+	"hcm/pkg/tools/times"
+)
+
+// webhookReceiver 记录收到的回调请求，前 failTimes 次请求返回 500
+type webhookReceiver struct {
+	lock      sync.Mutex
+	secret    string
+	failTimes int
+	events    []string
+	badSign   int
+	// states 事件类型与回调请求中的任务流状态的映射
+	states map[string]enumor.FlowState
+}
+
+func (r *webhookReceiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	body, _ := io.ReadAll(req.Body)
+	sign := SignWebhook(r.secret, req.Header.Get(WebhookTimestampHeader), body)
+	if sign != req.Header.Get(WebhookSignatureHeader) {
+		r.badSign++
+	}
+	r.events = append(r.events, req.Header.Get(WebhookEventHeader))
+
+	payload := new(WebhookPayload)
+	if err := json.Unmarshal(body, payload); err == nil {
+		if r.states == nil {
+			r.states = make(map[string]enumor.FlowState)
+		}
+		r.states[string(payload.Event)] = payload.FlowState
+	}
+
+	if len(r.events) <= r.failTimes {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func listTestFlowEvent(t *testing.T, bd backend.Backend, flowID string) model.FlowEvent {
+	events, err := bd.ListFlowEvent(newTestKit(), &backend.ListInput{
+		Filter: tools.EqualExpression("flow_id", flowID),
+		Page:   core.NewDefaultBasePage(),
+	})
+	if err != nil {
+		t.Fatalf("list flow event failed, err: %v", err)
+	}
+
+	if len(events) != 1 {
+		t.Fatalf("flow should have 1 event, but got %+v", events)
+	}
+
+	return events[0]
+}
+
+func TestNotifierRetryAndSignature(t *testing.T) {
+	receiver := &webhookReceiver{secret: "app-secret", failTimes: 1}
+	server := httptest.NewServer(receiver)
+	defer server.Close()
+
+	bd := backend.NewMemory()
+	kt := newTestKit()
+	kt.AppCode = "notifier-test"
+	// 应用订阅与任务流自定义的 webhook 地址相同时只投递一次，只订阅失败事件的 webhook 不投递创建事件
+	flowID, err := bd.CreateFlow(kt, &model.Flow{
+		Name:      "notifier_test",
+		ShareData: tableasync.NewShareData(),
+		Webhooks: tableasync.WebhookTargets{
+			{URL: server.URL},
+			{URL: server.URL + "/failed", Events: []enumor.FlowEventType{enumor.FlowEventFailed}},
+		},
+		Tasks: []model.Task{{FlowName: "notifier_test", ActionID: "1", ActionName: "test",
+			Retry: new(tableasync.Retry)}},
+	})
+	if err != nil {
+		t.Fatalf("create flow failed, err: %v", err)
+	}
+
+	nt := NewNotifier(bd, &NotifierOption{
+		WatchIntervalSec: 1,
+		TimeoutSec:       5,
+		MaxAttempts:      3,
+		BackoffBaseSec:   30,
+		Subscriptions:    []WebhookSubscription{{AppCode: kt.AppCode, URL: server.URL, Secret: "app-secret"}},
+	}).(*notifier)
+
+	now := time.Now()
+	if err = nt.Do(kt); err != nil {
+		t.Fatalf("notifier do failed, err: %v", err)
+	}
+
+	event := listTestFlowEvent(t, bd, flowID)
+	if event.State != enumor.FlowEventStatePending || event.Attempts != 1 || len(event.Deliveries) != 1 ||
+		event.Deliveries[0].StatusCode != http.StatusInternalServerError {
+		t.Fatalf("first delivery should be failed and wait for retry, but got %+v", event)
+	}
+
+	nextAttemptAt, err := time.ParseInLocation(constant.TimeStdFormat, event.NextAttemptAt, time.Local)
+	if err != nil {
+		t.Fatalf("parse next_attempt_at failed, err: %v", err)
+	}
+
+	if backoff := nextAttemptAt.Sub(now); backoff < 29*time.Second || backoff > 31*time.Second {
+		t.Errorf("first retry should backoff 30s, but got %s", backoff)
+	}
+
+	// 未到重试时间时不投递
+	if err = nt.Do(kt); err != nil {
+		t.Fatalf("notifier do failed, err: %v", err)
+	}
+
+	if len(receiver.events) != 1 {
+		t.Fatalf("event should not be delivered before next_attempt_at, got %d requests", len(receiver.events))
+	}
+
+	retry := &model.FlowEvent{ID: event.ID, NextAttemptAt: times.ConvStdTimeFormat(now.Add(-time.Minute))}
+	if err = bd.UpdateFlowEvent(kt, retry); err != nil {
+		t.Fatalf("update flow event failed, err: %v", err)
+	}
+
+	if err = nt.Do(kt); err != nil {
+		t.Fatalf("notifier do failed, err: %v", err)
+	}
+
+	event = listTestFlowEvent(t, bd, flowID)
+	if event.State != enumor.FlowEventStateDelivered || event.Attempts != 2 || len(event.Deliveries) != 2 ||
+		!event.Deliveries[1].Success {
+		t.Errorf("second delivery should be success, but got %+v", event)
+	}
+
+	if len(receiver.events) != 2 || receiver.events[0] != string(enumor.FlowEventCreated) || receiver.badSign != 0 {
+		t.Errorf("unexpected received events: %v, bad signature: %d", receiver.events, receiver.badSign)
+	}
+}
+
+func TestNotifierMaxAttempts(t *testing.T) {
+	receiver := &webhookReceiver{secret: "default-secret", failTimes: 10}
+	server := httptest.NewServer(receiver)
+	defer server.Close()
+
+	bd := backend.NewMemory()
+	kt := newTestKit()
+	flowID, err := bd.CreateFlow(kt, &model.Flow{
+		Name:      "notifier_test",
+		ShareData: tableasync.NewShareData(),
+		Webhooks:  tableasync.WebhookTargets{{URL: server.URL}},
+		Tasks: []model.Task{{FlowName: "notifier_test", ActionID: "1", ActionName: "test",
+			Retry: new(tableasync.Retry)}},
+	})
+	if err != nil {
+		t.Fatalf("create flow failed, err: %v", err)
+	}
+
+	nt := NewNotifier(bd, &NotifierOption{WatchIntervalSec: 1, TimeoutSec: 5, MaxAttempts: 1, BackoffBaseSec: 1,
+		Secret: "default-secret"}).(*notifier)
+	if err = nt.Do(kt); err != nil {
+		t.Fatalf("notifier do failed, err: %v", err)
+	}
+
+	event := listTestFlowEvent(t, bd, flowID)
+	if event.State != enumor.FlowEventStateFailed || event.Attempts != 1 || receiver.badSign != 0 {
+		t.Errorf("event should be failed after max attempts, but got %+v", event)
+	}
+}
+
+func TestNotifierRolledBackEventWithFlowSecret(t *testing.T) {
+	receiver := &webhookReceiver{secret: "flow-secret"}
+	server := httptest.NewServer(receiver)
+	defer server.Close()
+
+	bd := backend.NewMemory()
+	kt := newTestKit()
+	flowID, err := bd.CreateFlow(kt, &model.Flow{
+		Name:      "notifier_test",
+		ShareData: tableasync.NewShareData(),
+		Webhooks:  tableasync.WebhookTargets{{URL: server.URL, Secret: "flow-secret"}},
+		Tasks: []model.Task{{FlowName: "notifier_test", ActionID: "1", ActionName: "test",
+			Retry: new(tableasync.Retry)}},
+	})
+	if err != nil {
+		t.Fatalf("create flow failed, err: %v", err)
+	}
+
+	// saga 任务流失败后经过补偿中状态回滚完成，补偿中状态不生成事件
+	states := []enumor.FlowState{enumor.FlowPending, enumor.FlowRunning, enumor.FlowCompensating,
+		enumor.FlowRolledBack}
+	for i := 1; i < len(states); i++ {
+		info := backend.UpdateFlowInfo{ID: flowID, Source: states[i-1], Target: states[i]}
+		if err = bd.BatchUpdateFlowStateByCAS(kt, []backend.UpdateFlowInfo{info}); err != nil {
+			t.Fatalf("update flow state to %s failed, err: %v", states[i], err)
+		}
+	}
+
+	nt := NewNotifier(bd, &NotifierOption{WatchIntervalSec: 1, TimeoutSec: 5, MaxAttempts: 1, BackoffBaseSec: 1,
+		Secret: "default-secret"}).(*notifier)
+	if err = nt.Do(kt); err != nil {
+		t.Fatalf("notifier do failed, err: %v", err)
+	}
+
+	expected := map[string]enumor.FlowState{
+		string(enumor.FlowEventCreated):    enumor.FlowPending,
+		string(enumor.FlowEventRunning):    enumor.FlowRunning,
+		string(enumor.FlowEventRolledBack): enumor.FlowRolledBack,
+	}
+	if !reflect.DeepEqual(receiver.states, expected) || receiver.badSign != 0 {
+		t.Errorf("received flow states should be %v, but got %v, bad signature: %d", expected, receiver.states,
+			receiver.badSign)
+	}
+}
@@ -19,7 +19,14 @@
 
 package consumer
 
-import "hcm/pkg/criteria/validator"
+import (
+	"errors"
+	"fmt"
+
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+	tableasync "hcm/pkg/dal/table/async"
+)
 
 // Option defines consumer run option.
 type Option struct {
@@ -27,11 +34,42 @@ type Option struct {
 	Executor   *ExecutorOption   `json:"executor" validate:"required"`
 	Dispatcher *DispatcherOption `json:"dispatcher" validate:"required"`
 	WatchDog   *WatchDogOption   `json:"watch_dog" validate:"required"`
+	Timer      *TimerOption      `json:"timer" validate:"required"`
+	Notifier   *NotifierOption   `json:"notifier" validate:"required"`
+	Archiver   *ArchiverOption   `json:"archiver" validate:"required"`
+	// IdempotencyCleaner 幂等键清理器配置
+	IdempotencyCleaner *IdempotencyCleanerOption `json:"idempotency_cleaner" validate:"required"`
+	// Compensator 补偿器配置
+	Compensator *CompensatorOption `json:"compensator" validate:"required"`
 }
 
 // Validate Option
 func (opt Option) Validate() error {
-	return validator.Validate.Struct(opt)
+	if err := validator.Validate.Struct(opt); err != nil {
+		return err
+	}
+
+	if opt.Executor.HeartbeatIntervalSec >= opt.WatchDog.HeartbeatTimeoutSec {
+		return errors.New("executor heartbeat_interval_sec should be less than watch dog heartbeat_timeout_sec")
+	}
+
+	if err := opt.Dispatcher.Validate(); err != nil {
+		return err
+	}
+
+	if err := opt.Notifier.Validate(); err != nil {
+		return err
+	}
+
+	if err := opt.Archiver.Validate(); err != nil {
+		return err
+	}
+
+	if err := opt.IdempotencyCleaner.Validate(); err != nil {
+		return err
+	}
+
+	return opt.Compensator.Validate()
 }
 
 // SchedulerOption 公共组件，负责获取分配给当前节点的任务流，并解析成任务树后，派发当前要执行的任务给executor执行
@@ -49,6 +87,8 @@ func (opt SchedulerOption) Validate() error {
 type ExecutorOption struct {
 	WorkerNumber       uint `json:"worker_number" validate:"required"`
 	TaskExecTimeoutSec uint `json:"task_exec_timeout_sec" validate:"required"`
+	// HeartbeatIntervalSec 任务执行期间自动上报任务心跳的周期
+	HeartbeatIntervalSec uint `json:"heartbeat_interval_sec" validate:"required"`
 }
 
 // Validate ExecutorOption
@@ -59,17 +99,42 @@ func (opt ExecutorOption) Validate() error {
 // DispatcherOption 主节点组件，负责派发任务
 type DispatcherOption struct {
 	WatchIntervalSec uint `json:"watch_interval_sec" validate:"required"`
+	// Queues 按优先级划分的派发队列，未配置的优先级不限制并发
+	Queues []DispatchQueueOption `json:"queues" validate:"omitempty"`
 }
 
 // Validate DispatcherOption
 func (opt DispatcherOption) Validate() error {
-	return validator.Validate.Struct(opt)
+	if err := validator.Validate.Struct(opt); err != nil {
+		return err
+	}
+
+	exist := make(map[enumor.FlowPriority]struct{}, len(opt.Queues))
+	for _, one := range opt.Queues {
+		if err := one.Priority.Validate(); err != nil {
+			return err
+		}
+
+		if _, ok := exist[one.Priority]; ok {
+			return fmt.Errorf("dispatch queue priority: %s is duplicated", one.Priority)
+		}
+		exist[one.Priority] = struct{}{}
+	}
+
+	return nil
+}
+
+// DispatchQueueOption 派发队列配置，并发数为0表示不限制
+type DispatchQueueOption struct {
+	Priority          enumor.FlowPriority `json:"priority"`
+	MaxConcurrency    uint                `json:"max_concurrency"`
+	TenantConcurrency uint                `json:"tenant_concurrency"`
 }
 
 // WatchDogOption 主节点组件，负责异常任务修正（超时任务，任务处理节点已经挂掉的任务等）
 type WatchDogOption struct {
 	WatchIntervalSec    uint `json:"watch_interval_sec" validate:"required"`
-	TaskRunTimeoutSec   uint `json:"task_run_timeout_sec" validate:"required"`
+	HeartbeatTimeoutSec uint `json:"heartbeat_timeout_sec" validate:"required"`
 	ShutdownWaitTimeSec uint `json:"shutdown_wait_time_sec" validate:"required"`
 }
 
@@ -77,3 +142,97 @@ type WatchDogOption struct {
 func (opt WatchDogOption) Validate() error {
 	return validator.Validate.Struct(opt)
 }
+
+// TimerOption 主节点组件，负责按照定时任务流的 cron 表达式生成任务流
+type TimerOption struct {
+	WatchIntervalSec uint `json:"watch_interval_sec" validate:"required"`
+}
+
+// Validate TimerOption
+func (opt TimerOption) Validate() error {
+	return validator.Validate.Struct(opt)
+}
+
+// ArchiverOption 主节点组件，负责将已结束且超过保留时间的任务流归档
+type ArchiverOption struct {
+	WatchIntervalSec uint `json:"watch_interval_sec" validate:"required"`
+	// RetentionDays 已结束的任务流保留天数，超过后归档，为0时不归档
+	RetentionDays uint `json:"retention_days" validate:"omitempty"`
+	// BatchSize 单批次归档的任务流数量
+	BatchSize uint `json:"batch_size" validate:"required,max=100"`
+}
+
+// Validate ArchiverOption
+func (opt ArchiverOption) Validate() error {
+	return validator.Validate.Struct(opt)
+}
+
+// IdempotencyCleanerOption 主节点组件，负责删除创建任务流时使用的已过期幂等键
+type IdempotencyCleanerOption struct {
+	WatchIntervalSec uint `json:"watch_interval_sec" validate:"required"`
+}
+
+// Validate IdempotencyCleanerOption
+func (opt IdempotencyCleanerOption) Validate() error {
+	return validator.Validate.Struct(opt)
+}
+
+// CompensatorOption 主节点组件，负责补偿回滚策略为 saga 的失败任务流中执行成功的任务
+type CompensatorOption struct {
+	WatchIntervalSec uint `json:"watch_interval_sec" validate:"required"`
+	// WorkerNumber 同时补偿的任务流数量
+	WorkerNumber uint `json:"worker_number" validate:"required"`
+}
+
+// Validate CompensatorOption
+func (opt CompensatorOption) Validate() error {
+	return validator.Validate.Struct(opt)
+}
+
+// NotifierOption 主节点组件，负责将任务流生命周期事件投递到订阅的 webhook
+type NotifierOption struct {
+	WatchIntervalSec uint `json:"watch_interval_sec" validate:"required"`
+	// TimeoutSec 单次回调请求的超时时间
+	TimeoutSec uint `json:"timeout_sec" validate:"required"`
+	// MaxAttempts 单个事件最大投递次数，超过后事件置为投递失败
+	MaxAttempts uint `json:"max_attempts" validate:"required"`
+	// BackoffBaseSec 投递失败后的重试间隔基数，第n次重试间隔为 BackoffBaseSec * 2^(n-1)
+	BackoffBaseSec uint `json:"backoff_base_sec" validate:"required"`
+	// Secret 回调请求签名默认使用的密钥，订阅未配置密钥时使用
+	Secret string `json:"secret"`
+	// Subscriptions 按应用编码订阅的 webhook
+	Subscriptions []WebhookSubscription `json:"subscriptions" validate:"omitempty"`
+}
+
+// Validate NotifierOption
+func (opt NotifierOption) Validate() error {
+	if err := validator.Validate.Struct(opt); err != nil {
+		return err
+	}
+
+	for _, one := range opt.Subscriptions {
+		if err := one.Validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// WebhookSubscription 应用订阅的 webhook，应用创建的任务流事件会投递到该地址
+type WebhookSubscription struct {
+	AppCode string                 `json:"app_code" validate:"required"`
+	URL     string                 `json:"url" validate:"required"`
+	Secret  string                 `json:"secret"`
+	Events  []enumor.FlowEventType `json:"events"`
+}
+
+// Validate WebhookSubscription
+func (sub WebhookSubscription) Validate() error {
+	if err := validator.Validate.Struct(sub); err != nil {
+		return err
+	}
+
+	target := tableasync.WebhookTarget{URL: sub.URL, Events: sub.Events}
+	return target.Validate()
+}
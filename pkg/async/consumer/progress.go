@@ -0,0 +1,128 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package consumer
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"hcm/pkg/async/action/run"
+	"hcm/pkg/async/backend/model"
+	tableasync "hcm/pkg/dal/table/async"
+	"hcm/pkg/dal/table/types"
+	"hcm/pkg/tools/json"
+	"hcm/pkg/tools/times"
+)
+
+// heartbeatMinInterval 心跳最小上报间隔，避免任务频繁上报心跳对存储造成压力
+const heartbeatMinInterval = time.Second
+
+var _ run.ProgressOperator = new(taskProgress)
+
+// newTaskProgress 创建任务进度操作器，心跳及执行进度通过任务的 Patch 函数持久化到任务上。
+func newTaskProgress(task *Task) run.ProgressOperator {
+	return &taskProgress{task: task}
+}
+
+// taskProgress 任务进度操作器
+type taskProgress struct {
+	task *Task
+
+	lock          sync.Mutex
+	lastHeartbeat time.Time
+}
+
+// Heartbeat 上报任务心跳，距离上次上报不足 heartbeatMinInterval 时跳过。
+func (p *taskProgress) Heartbeat() error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	now := times.ConvStdTimeNow()
+	if now.Sub(p.lastHeartbeat) < heartbeatMinInterval {
+		return nil
+	}
+
+	md := &model.Task{
+		ID:          p.task.ID,
+		HeartbeatAt: times.ConvStdTimeFormat(now),
+	}
+	if err := p.task.Patch(p.task.Kit, md); err != nil {
+		return err
+	}
+
+	p.lastHeartbeat = now
+	return nil
+}
+
+// Report 上报任务执行进度及断点数据，同时视为一次心跳。
+func (p *taskProgress) Report(percent uint, message string, checkpoint interface{}) error {
+	if percent > 100 {
+		return errors.New("progress percent should <= 100")
+	}
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	progress := &tableasync.TaskProgress{
+		Percent: percent,
+		Message: message,
+	}
+
+	if checkpoint != nil {
+		field, err := types.NewJsonField(checkpoint)
+		if err != nil {
+			return err
+		}
+		progress.Checkpoint = field
+	} else if p.task.Progress != nil {
+		progress.Checkpoint = p.task.Progress.Checkpoint
+	}
+
+	now := times.ConvStdTimeNow()
+	md := &model.Task{
+		ID:          p.task.ID,
+		Progress:    progress,
+		HeartbeatAt: times.ConvStdTimeFormat(now),
+	}
+	if err := p.task.Patch(p.task.Kit, md); err != nil {
+		return err
+	}
+
+	p.task.Progress = progress
+	p.lastHeartbeat = now
+	return nil
+}
+
+// Checkpoint 将最近一次上报的断点数据解析到 v 中。
+func (p *taskProgress) Checkpoint(v interface{}) (bool, error) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if p.task.Progress == nil || len(p.task.Progress.Checkpoint) == 0 {
+		return false, nil
+	}
+
+	if err := json.UnmarshalFromString(string(p.task.Progress.Checkpoint), v); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
@@ -0,0 +1,229 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package consumer
+
+import (
+	"testing"
+	"time"
+
+	"hcm/pkg/async/action"
+	"hcm/pkg/async/action/run"
+	"hcm/pkg/async/backend"
+	"hcm/pkg/async/backend/model"
+	"hcm/pkg/criteria/enumor"
+	tableasync "hcm/pkg/dal/table/async"
+	"hcm/pkg/kit"
+	"hcm/pkg/tools/converter"
+	"hcm/pkg/tools/times"
+)
+
+type progressTestCheckpoint struct {
+	Page int `json:"page"`
+}
+
+// prepareProgressTask 创建仅包含一个运行中任务的任务流，并初始化任务执行依赖
+func prepareProgressTask(t *testing.T, bd backend.Backend, retry bool) *Task {
+	kt := newTestKit()
+	flowID, err := bd.CreateFlow(kt, &model.Flow{
+		Name:      "progress_test",
+		ShareData: tableasync.NewShareData(),
+		Tasks: []model.Task{
+			{
+				FlowName:   "progress_test",
+				ActionID:   "1",
+				ActionName: enumor.ActionCreateFactoryTest,
+				Retry: &tableasync.Retry{Enable: retry, Policy: &tableasync.RetryPolicy{Count: 1,
+					SleepRangeMS: [2]uint{100, 200}}},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("create flow failed, err: %v", err)
+	}
+
+	if err = bd.BatchUpdateFlow(kt, []model.Flow{{ID: flowID, State: enumor.FlowRunning}}); err != nil {
+		t.Fatalf("update flow failed, err: %v", err)
+	}
+
+	tasks, err := listTaskByFlowID(kt, bd, flowID)
+	if err != nil || len(tasks) != 1 {
+		t.Fatalf("list task failed, err: %v, count: %d", err, len(tasks))
+	}
+
+	task := tasks[0]
+	task.InitDep(run.NewExecuteContext(task.Kit, tableasync.NewShareData(), newTaskProgress(task)),
+		func(kt *kit.Kit, task *model.Task) error {
+			return bd.UpdateTask(kt, task)
+		}, nil, listDependResults(bd))
+
+	return task
+}
+
+func TestTaskProgressReport(t *testing.T) {
+	bd := backend.NewMemory()
+	task := prepareProgressTask(t, bd, true)
+	progress := task.ExecuteKit.Progress()
+
+	if exist, err := progress.Checkpoint(new(progressTestCheckpoint)); err != nil || exist {
+		t.Fatalf("checkpoint should not exist before report, exist: %v, err: %v", exist, err)
+	}
+
+	if err := progress.Report(101, "overflow", nil); err == nil {
+		t.Errorf("report percent greater than 100 should return error")
+	}
+
+	if err := progress.Report(30, "polling", &progressTestCheckpoint{Page: 3}); err != nil {
+		t.Fatalf("report progress failed, err: %v", err)
+	}
+
+	// 未携带断点数据时保留上一次上报的断点
+	if err := progress.Report(60, "still polling", nil); err != nil {
+		t.Fatalf("report progress failed, err: %v", err)
+	}
+
+	// 模拟任务重试，从存储中重新加载任务后可以获取到断点数据
+	tasks, err := listTaskByIDs(newTestKit(), bd, []string{task.ID})
+	if err != nil || len(tasks) != 1 {
+		t.Fatalf("list task failed, err: %v, count: %d", err, len(tasks))
+	}
+
+	retried := tasks[0]
+	if retried.Progress == nil || retried.Progress.Percent != 60 || retried.Progress.Message != "still polling" {
+		t.Errorf("task progress not persisted, got: %+v", retried.Progress)
+	}
+
+	checkpoint := new(progressTestCheckpoint)
+	exist, err := newTaskProgress(retried).Checkpoint(checkpoint)
+	if err != nil || !exist || checkpoint.Page != 3 {
+		t.Errorf("checkpoint not available on retry, exist: %v, err: %v, got: %+v", exist, err, checkpoint)
+	}
+}
+
+func TestWatchDogHeartbeatTimeout(t *testing.T) {
+	bd := backend.NewMemory()
+	task := prepareProgressTask(t, bd, false)
+
+	kt := newTestKit()
+	md := &model.Task{
+		ID:          task.ID,
+		State:       enumor.TaskRunning,
+		HeartbeatAt: times.ConvStdTimeFormat(times.ConvStdTimeNow().Add(-2 * time.Minute)),
+	}
+	if err := bd.UpdateTask(kt, md); err != nil {
+		t.Fatalf("update task failed, err: %v", err)
+	}
+
+	wd := &watchDog{bd: bd, heartbeatTimeout: time.Minute}
+	if err := wd.handleExpiredTasks(kt); err != nil {
+		t.Fatalf("handle expired tasks failed, err: %v", err)
+	}
+
+	tasks, err := listTaskByIDs(kt, bd, []string{task.ID})
+	if err != nil || len(tasks) != 1 {
+		t.Fatalf("list task failed, err: %v, count: %d", err, len(tasks))
+	}
+
+	if tasks[0].State != enumor.TaskFailed || tasks[0].Reason.Message != ErrTaskHeartbeatTimeout {
+		t.Errorf("heartbeat timeout task should be failed, state: %s, reason: %+v", tasks[0].State,
+			tasks[0].Reason)
+	}
+
+	flow, err := getFlow(kt, bd, task.FlowID)
+	if err != nil {
+		t.Fatalf("get flow failed, err: %v", err)
+	}
+
+	if flow.State != enumor.FlowFailed || flow.Reason.Message != ErrTaskHeartbeatTimeout {
+		t.Errorf("flow of heartbeat timeout task should be failed, state: %s, reason: %+v", flow.State, flow.Reason)
+	}
+}
+
+// heartbeatTestAction 支持回滚的测试任务，用于验证心跳超时任务的重试
+type heartbeatTestAction struct{}
+
+// Name return action name
+func (act heartbeatTestAction) Name() enumor.ActionName {
+	return enumor.ActionCreateFactoryTest
+}
+
+// Run action
+func (act heartbeatTestAction) Run(kt run.ExecuteKit, params interface{}) (interface{}, error) {
+	return nil, nil
+}
+
+// Rollback action
+func (act heartbeatTestAction) Rollback(kt run.ExecuteKit, params interface{}) error {
+	return nil
+}
+
+func TestWatchDogHeartbeatTimeoutRetry(t *testing.T) {
+	action.RegisterAction(heartbeatTestAction{})
+
+	cases := []struct {
+		name      string
+		flowState enumor.FlowState
+		expect    enumor.FlowState
+	}{
+		{name: "running flow is reset to pending", flowState: enumor.FlowRunning, expect: enumor.FlowPending},
+		{name: "canceled flow is kept", flowState: enumor.FlowCancel, expect: enumor.FlowCancel},
+	}
+
+	for _, c := range cases {
+		bd := backend.NewMemory()
+		task := prepareProgressTask(t, bd, true)
+
+		kt := newTestKit()
+		flowMd := model.Flow{ID: task.FlowID, State: c.flowState, Worker: converter.ValToPtr("worker-1")}
+		if err := bd.BatchUpdateFlow(kt, []model.Flow{flowMd}); err != nil {
+			t.Fatalf("[%s] update flow failed, err: %v", c.name, err)
+		}
+
+		md := &model.Task{
+			ID:          task.ID,
+			State:       enumor.TaskRunning,
+			HeartbeatAt: times.ConvStdTimeFormat(times.ConvStdTimeNow().Add(-2 * time.Minute)),
+		}
+		if err := bd.UpdateTask(kt, md); err != nil {
+			t.Fatalf("[%s] update task failed, err: %v", c.name, err)
+		}
+
+		wd := &watchDog{bd: bd, heartbeatTimeout: time.Minute}
+		if err := wd.handleExpiredTasks(kt); err != nil {
+			t.Fatalf("[%s] handle expired tasks failed, err: %v", c.name, err)
+		}
+
+		flow, err := getFlow(kt, bd, task.FlowID)
+		if err != nil {
+			t.Fatalf("[%s] get flow failed, err: %v", c.name, err)
+		}
+
+		if flow.State != c.expect {
+			t.Errorf("[%s] flow state should be %s, but got %s", c.name, c.expect, flow.State)
+		}
+
+		worker := converter.PtrToVal(flow.Worker)
+		if c.expect == enumor.FlowPending && len(worker) != 0 {
+			t.Errorf("[%s] worker of pending flow should be reset, but got %s", c.name, worker)
+		}
+		if c.expect != enumor.FlowPending && worker != "worker-1" {
+			t.Errorf("[%s] worker of flow not reset should be kept, but got %s", c.name, worker)
+		}
+	}
+}
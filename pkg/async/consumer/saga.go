@@ -0,0 +1,130 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package consumer
+
+import (
+	"fmt"
+
+	"hcm/pkg/async/action/run"
+	"hcm/pkg/async/backend"
+	"hcm/pkg/async/backend/model"
+	"hcm/pkg/criteria/enumor"
+	tableasync "hcm/pkg/dal/table/async"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+)
+
+/*
+Saga 回滚：任务流回滚策略为 saga 时，任务流失败后按照依赖关系的逆序补偿已执行成功的任务。
+	1. 任务流失败后状态更新为 compensating，由主节点的补偿器（Compensator）异步补偿，不占用调度器的处理协程。
+	2. 所有已成功任务补偿完成（未实现 CompensateAction 的任务无需补偿），任务流状态更新为 rolled_back。
+	3. 任一任务补偿失败时停止补偿，该任务状态更新为 compensate_failed 并记录失败原因，任务流状态更新为 failed。
+	4. 补偿中主节点切换时，新的主节点继续补偿 compensating 状态的任务流，状态为 compensated 的任务不会被重复补偿。
+*/
+
+// failFlow 任务流存在失败任务且没有可执行的任务时，结束任务流。回滚策略为 saga 的任务流交由补偿器补偿已成功的任务。
+func failFlow(kt *kit.Kit, bd backend.Backend, flow *Flow) error {
+	state := enumor.FlowFailed
+	if flow.RollbackPolicy == enumor.FlowRollbackSaga {
+		state = enumor.FlowCompensating
+	}
+
+	if err := updateFlowStateAndReason(kt, bd, flow.ID, enumor.FlowRunning, state, ErrSomeTaskExecFailed); err != nil {
+		logs.Errorf("update flow state to %s failed, err: %v, rid: %s", state, err, kt.Rid)
+		return err
+	}
+
+	return nil
+}
+
+// compensateFlow 按照依赖关系的逆序，依次补偿任务流中执行成功的任务。
+func compensateFlow(kt *kit.Kit, bd backend.Backend, flow *Flow) error {
+	tasks, err := listTaskByFlowID(kt, bd, flow.ID)
+	if err != nil {
+		return fmt.Errorf("list flow tasks failed, err: %v", err)
+	}
+
+	order, err := compensationOrder(tasks)
+	if err != nil {
+		return err
+	}
+
+	shareData := flow.ShareData
+	if shareData == nil {
+		shareData = new(tableasync.ShareData)
+	}
+	shareData.Save = func(kt *kit.Kit, data *tableasync.ShareData) error {
+		return bd.BatchUpdateFlow(kt, []model.Flow{{ID: flow.ID, ShareData: data}})
+	}
+
+	for _, task := range order {
+		task.InitDep(run.NewExecuteContext(task.Kit, shareData, newTaskProgress(task)),
+			func(kt *kit.Kit, task *model.Task) error {
+				return bd.UpdateTask(kt, task)
+			}, flow, listDependResults(bd))
+
+		compensated, err := task.Compensate()
+		if err != nil {
+			logs.Errorf("compensate task failed, err: %v, task: %s, rid: %s", err, task.ID, kt.Rid)
+
+			md := &model.Task{
+				ID:     task.ID,
+				State:  enumor.TaskCompensateFailed,
+				Reason: &tableasync.Reason{Message: fmt.Sprintf("%s, %v", ErrTaskCompensateFailed, err)},
+			}
+			if updateErr := bd.UpdateTask(kt, md); updateErr != nil {
+				logs.Errorf("update task state to compensate_failed failed, err: %v, task: %s, rid: %s", updateErr,
+					task.ID, kt.Rid)
+			}
+
+			return fmt.Errorf("compensate task: %s failed, err: %v", task.ActionID, err)
+		}
+
+		if compensated {
+			logs.Infof("flow: %s task: %s compensated, rid: %s", flow.ID, task.ActionID, kt.Rid)
+		}
+	}
+
+	return nil
+}
+
+// compensationOrder 按照依赖关系的逆序返回需要补偿的任务，即后置任务先于其依赖的前置任务补偿，只包含执行成功的任务。
+// 存在补偿失败的任务时不再继续补偿，避免主节点切换后跳过补偿失败的任务，将任务流误更新为 rolled_back。
+func compensationOrder(tasks []*Task) ([]*Task, error) {
+	for _, task := range tasks {
+		if task.State == enumor.TaskCompensateFailed {
+			return nil, fmt.Errorf("compensate task: %s failed before", task.ActionID)
+		}
+	}
+
+	sorted, err := topologicalOrder(tasks)
+	if err != nil {
+		return nil, fmt.Errorf("flow tasks has cycle or not exist depend, can not compensate")
+	}
+
+	order := make([]*Task, 0, len(sorted))
+	for index := len(sorted) - 1; index >= 0; index-- {
+		if sorted[index].State == enumor.TaskSuccess {
+			order = append(order, sorted[index])
+		}
+	}
+
+	return order, nil
+}
@@ -0,0 +1,198 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package consumer
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"hcm/pkg/async/action"
+	"hcm/pkg/async/action/run"
+	"hcm/pkg/async/backend"
+	"hcm/pkg/async/backend/model"
+	"hcm/pkg/criteria/enumor"
+	tableasync "hcm/pkg/dal/table/async"
+	"hcm/pkg/dal/table/types"
+	"hcm/pkg/tools/json"
+)
+
+// sagaTestAction 记录补偿顺序的测试任务，补偿 failOn 任务时返回错误
+type sagaTestAction struct {
+	compensated []string
+	failOn      string
+}
+
+// Name return action name
+func (act *sagaTestAction) Name() enumor.ActionName {
+	return enumor.ActionCreateFactoryTest
+}
+
+// Run action
+func (act *sagaTestAction) Run(kt run.ExecuteKit, params interface{}) (interface{}, error) {
+	return nil, nil
+}
+
+// Compensate action
+func (act *sagaTestAction) Compensate(kt run.ExecuteKit, params interface{}, result types.JsonField) error {
+	output := make(map[string]string)
+	if err := json.UnmarshalFromString(string(result), &output); err != nil {
+		return err
+	}
+
+	if output["id"] == act.failOn {
+		return errors.New("release resource failed")
+	}
+
+	act.compensated = append(act.compensated, output["id"])
+	return nil
+}
+
+// prepareSagaFlow 创建运行中的任务流，任务依赖关系为 1 -> (2, 3) -> 4，任务 1、2、3 执行成功，任务 4 执行失败
+func prepareSagaFlow(t *testing.T, bd backend.Backend, policy enumor.FlowRollbackPolicy) *Flow {
+	kt := newTestKit()
+	dependOn := map[string][]action.ActIDType{"2": {"1"}, "3": {"1"}, "4": {"2", "3"}}
+	tasks := make([]model.Task, 0, 4)
+	for _, id := range []string{"1", "2", "3", "4"} {
+		tasks = append(tasks, model.Task{
+			FlowName:   "saga_test",
+			ActionID:   action.ActIDType(id),
+			ActionName: enumor.ActionCreateFactoryTest,
+			Retry:      &tableasync.Retry{Enable: false},
+			DependOn:   dependOn[id],
+		})
+	}
+
+	flowID, err := bd.CreateFlow(kt, &model.Flow{
+		Name:           "saga_test",
+		ShareData:      tableasync.NewShareData(),
+		RollbackPolicy: policy,
+		Tasks:          tasks,
+	})
+	if err != nil {
+		t.Fatalf("create flow failed, err: %v", err)
+	}
+
+	taskList, err := listTaskByFlowID(kt, bd, flowID)
+	if err != nil {
+		t.Fatalf("list task failed, err: %v", err)
+	}
+
+	for _, one := range taskList {
+		md := &model.Task{ID: one.ID, State: enumor.TaskSuccess, Result: types.JsonField(
+			`{"id":"` + string(one.ActionID) + `"}`)}
+		if one.ActionID == "4" {
+			md = &model.Task{ID: one.ID, State: enumor.TaskFailed}
+		}
+		if err = bd.UpdateTask(kt, md); err != nil {
+			t.Fatalf("update task failed, err: %v", err)
+		}
+	}
+
+	if err = bd.BatchUpdateFlow(kt, []model.Flow{{ID: flowID, State: enumor.FlowRunning}}); err != nil {
+		t.Fatalf("update flow failed, err: %v", err)
+	}
+
+	flow, err := getFlow(kt, bd, flowID)
+	if err != nil {
+		t.Fatalf("get flow failed, err: %v", err)
+	}
+
+	return &Flow{Flow: *flow, Kit: kt}
+}
+
+func TestSagaCompensateFlow(t *testing.T) {
+	cases := []struct {
+		name        string
+		policy      enumor.FlowRollbackPolicy
+		failOn      string
+		flowState   enumor.FlowState
+		taskStates  []enumor.TaskState
+		compensated []string
+	}{
+		{
+			name:        "none",
+			policy:      enumor.FlowRollbackNone,
+			flowState:   enumor.FlowFailed,
+			taskStates:  []enumor.TaskState{enumor.TaskSuccess, enumor.TaskSuccess, enumor.TaskSuccess, enumor.TaskFailed},
+			compensated: nil,
+		},
+		{
+			name:      "saga",
+			policy:    enumor.FlowRollbackSaga,
+			flowState: enumor.FlowRolledBack,
+			taskStates: []enumor.TaskState{enumor.TaskCompensated, enumor.TaskCompensated, enumor.TaskCompensated,
+				enumor.TaskFailed},
+			compensated: []string{"3", "2", "1"},
+		},
+		{
+			name:      "saga compensate failed",
+			policy:    enumor.FlowRollbackSaga,
+			failOn:    "2",
+			flowState: enumor.FlowFailed,
+			taskStates: []enumor.TaskState{enumor.TaskSuccess, enumor.TaskCompensateFailed, enumor.TaskCompensated,
+				enumor.TaskFailed},
+			compensated: []string{"3"},
+		},
+	}
+
+	for _, c := range cases {
+		act := &sagaTestAction{failOn: c.failOn}
+		action.RegisterAction(act)
+
+		bd := backend.NewMemory()
+		flow := prepareSagaFlow(t, bd, c.policy)
+		if err := failFlow(newTestKit(), bd, flow); err != nil {
+			t.Fatalf("%s: fail flow failed, err: %v", c.name, err)
+		}
+
+		// saga 任务流由补偿器异步补偿，失败时只更新任务流状态为 compensating
+		if c.policy == enumor.FlowRollbackSaga {
+			assertStates(t, bd, flow.ID, enumor.FlowCompensating, enumor.TaskSuccess, enumor.TaskSuccess,
+				enumor.TaskSuccess, enumor.TaskFailed)
+
+			cp := NewCompensator(bd, &CompensatorOption{WatchIntervalSec: 1, WorkerNumber: 2})
+			if err := cp.(*compensator).Do(newTestKit()); err != nil {
+				t.Fatalf("%s: compensator do failed, err: %v", c.name, err)
+			}
+		}
+
+		assertStates(t, bd, flow.ID, c.flowState, c.taskStates...)
+		if !reflect.DeepEqual(act.compensated, c.compensated) {
+			t.Errorf("%s: compensate order should be %v, but got %v", c.name, c.compensated, act.compensated)
+		}
+
+		if len(c.failOn) == 0 {
+			continue
+		}
+
+		tasks, err := listTaskByFlowID(newTestKit(), bd, flow.ID)
+		if err != nil {
+			t.Fatalf("list task failed, err: %v", err)
+		}
+		for _, one := range tasks {
+			if string(one.ActionID) == c.failOn && !strings.Contains(one.Reason.Message, "release resource failed") {
+				t.Errorf("%s: task %s reason should record compensate error, but got %s", c.name, one.ActionID,
+					one.Reason.Message)
+			}
+		}
+	}
+}
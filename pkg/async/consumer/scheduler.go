@@ -94,6 +94,10 @@ func (sch *scheduler) Start() {
 	sch.workerWg.Add(1)
 	go sch.startWatcher(sch.watchScheduledFlow)
 
+	// 定期检查当前节点执行中的任务流是否被取消
+	sch.workerWg.Add(1)
+	go sch.startWatcher(sch.watchCanceledFlow)
+
 	// 启动workerNumber个协程进行任务流解析
 	for i := 0; i < int(sch.workerNumber); i++ {
 		sch.workerWg.Add(1)
@@ -121,46 +125,57 @@ func (sch *scheduler) startWatcher(do func(kt *kit.Kit) error) {
 	sch.workerWg.Done()
 }
 
-// queryCurrNodeFlow 查询主节点分配给当前节点处于 Scheduled 状态的任务流。
+// queryCurrNodeFlow 查询主节点分配给当前节点处于 Scheduled 状态的任务流，优先级高的任务流优先返回。
 func (sch *scheduler) queryCurrNodeFlow(kt *kit.Kit, limit int32) ([]*Flow, error) {
 
 	if limit > int32(core.DefaultMaxPageLimit) {
 		return nil, fmt.Errorf("limit should <= %d", core.DefaultMaxPageLimit)
 	}
 
-	input := &backend.ListInput{
-		Filter: &filter.Expression{
-			Op: filter.And,
-			Rules: []filter.RuleFactory{
-				&filter.AtomRule{
-					Field: "state",
-					Op:    filter.Equal.Factory(),
-					Value: enumor.FlowScheduled,
-				},
-				&filter.AtomRule{
-					Field: "worker",
-					Op:    filter.Equal.Factory(),
-					Value: sch.leader.CurrNode(),
+	flows := make([]*Flow, 0)
+	for _, priority := range enumor.FlowPriorities {
+		if len(flows) >= int(limit) {
+			break
+		}
+
+		input := &backend.ListInput{
+			Filter: &filter.Expression{
+				Op: filter.And,
+				Rules: []filter.RuleFactory{
+					&filter.AtomRule{
+						Field: "state",
+						Op:    filter.Equal.Factory(),
+						Value: enumor.FlowScheduled,
+					},
+					&filter.AtomRule{
+						Field: "worker",
+						Op:    filter.Equal.Factory(),
+						Value: sch.leader.CurrNode(),
+					},
+					&filter.AtomRule{
+						Field: "priority",
+						Op:    filter.Equal.Factory(),
+						Value: priority,
+					},
 				},
 			},
-		},
-		Page: &core.BasePage{
-			Start: 0,
-			Limit: uint(limit),
-		},
-	}
-	result, err := sch.backend.ListFlow(kt, input)
-	if err != nil {
-		logs.Errorf("list flows failed, err: %v, rid: %s", err, kt.Rid)
-		return nil, err
-	}
+			Page: &core.BasePage{
+				Start: 0,
+				Limit: uint(int(limit) - len(flows)),
+			},
+		}
+		result, err := sch.backend.ListFlow(kt, input)
+		if err != nil {
+			logs.Errorf("list %s priority flows failed, err: %v, rid: %s", priority, err, kt.Rid)
+			return nil, err
+		}
 
-	flows := make([]*Flow, 0)
-	for _, one := range result {
-		flows = append(flows, &Flow{
-			Flow: one,
-			Kit:  kt.NewSubKit(),
-		})
+		for _, one := range result {
+			flows = append(flows, &Flow{
+				Flow: one,
+				Kit:  kt.NewSubKit(),
+			})
+		}
 	}
 
 	return flows, nil
@@ -277,10 +292,7 @@ func (sch *scheduler) parseFlowAndPushTask(kt *kit.Kit, flow *Flow) error {
 		}
 
 		if state == enumor.FlowFailed {
-			if err = updateFlowStateAndReason(kt, sch.backend, flow.ID, enumor.FlowRunning, state,
-				ErrSomeTaskExecFailed); err != nil {
-
-				logs.Errorf("update flow state to %s failed, err: %v, rid: %s", state, err, kt.Rid)
+			if err = failFlow(kt, sch.backend, flow); err != nil {
 				return err
 			}
 		}
@@ -369,6 +381,45 @@ func (sch *scheduler) watchScheduledFlow(kt *kit.Kit) error {
 	return nil
 }
 
+// watchCanceledFlow 检查当前节点执行中的任务流是否已被取消，如果已取消，关闭执行中的任务并清理任务流树。
+// 任务流可能在其他节点被取消，所以需要由执行该任务流的节点自行感知。
+func (sch *scheduler) watchCanceledFlow(kt *kit.Kit) error {
+	flowIDs := make([]string, 0)
+	sch.taskTrees.Range(func(key, value any) bool {
+		flowIDs = append(flowIDs, key.(string))
+		return true
+	})
+
+	if len(flowIDs) == 0 {
+		return nil
+	}
+
+	flows, err := listFlowByIDs(kt, sch.backend, flowIDs, enumor.FlowCancel)
+	if err != nil {
+		return err
+	}
+
+	for _, flow := range flows {
+		tree, ok := sch.getTaskTree(flow.ID)
+		if !ok {
+			continue
+		}
+
+		ids := tree.Root.GetExecStateTasks()
+		if len(ids) != 0 {
+			if err = sch.executor.CancelTasks(ids); err != nil {
+				logs.Errorf("cancel tasks failed, err: %v, flow: %s, ids: %v, rid: %s", err, flow.ID, ids, kt.Rid)
+				return err
+			}
+		}
+
+		sch.taskTrees.Delete(flow.ID)
+		logs.Infof("flow: %s is canceled, clean task tree, canceled tasks: %v, rid: %s", flow.ID, ids, kt.Rid)
+	}
+
+	return nil
+}
+
 // 任务流解析协程
 func (sch *scheduler) goWorker() {
 	for task := range sch.workerQueue {
@@ -404,10 +455,7 @@ func (sch *scheduler) executeNext(kt *kit.Kit, task *Task) error {
 		}
 
 		if state == enumor.FlowFailed {
-			if err := updateFlowStateAndReason(kt, sch.backend, task.FlowID, enumor.FlowRunning, state,
-				ErrSomeTaskExecFailed); err != nil {
-
-				logs.Errorf("update flow state to %s failed, err: %v, rid: %s", state, err, kt.Rid)
+			if err := failFlow(kt, sch.backend, tree.Flow); err != nil {
 				return err
 			}
 
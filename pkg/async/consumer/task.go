@@ -20,19 +20,24 @@
 package consumer
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"reflect"
 
+	"hcm/pkg/api/core"
 	"hcm/pkg/async/action"
 	"hcm/pkg/async/action/run"
+	"hcm/pkg/async/backend"
 	"hcm/pkg/async/backend/model"
 	"hcm/pkg/criteria/enumor"
 	tableasync "hcm/pkg/dal/table/async"
 	"hcm/pkg/dal/table/types"
 	"hcm/pkg/kit"
 	"hcm/pkg/logs"
+	"hcm/pkg/runtime/filter"
 	"hcm/pkg/tools/retry"
+	"hcm/pkg/tools/times"
 )
 
 // Task 异步任务执行体，包含了任务运行流程、回滚流程。
@@ -44,6 +49,8 @@ type Task struct {
 	ExecuteKit run.ExecuteKit `json:"-"`
 	Patch      func(kt *kit.Kit, task *model.Task) error
 	Flow       *Flow
+	// DependResults 查询前置任务的执行结果，用于渲染任务参数中的参数表达式
+	DependResults func(kt *kit.Kit, task *model.Task) (map[action.ActIDType]types.JsonField, error) `json:"-"`
 }
 
 // ValidateBeforeExec task validate before execute.
@@ -93,10 +100,73 @@ func (task *Task) ValidateBeforeRollback(act action.Action) error {
 }
 
 // InitDep init task for exec.
-func (task *Task) InitDep(kt run.ExecuteKit, patch func(kt *kit.Kit, task *model.Task) error, flow *Flow) {
+func (task *Task) InitDep(kt run.ExecuteKit, patch func(kt *kit.Kit, task *model.Task) error, flow *Flow,
+	dependResults func(kt *kit.Kit, task *model.Task) (map[action.ActIDType]types.JsonField, error)) {
+
 	task.ExecuteKit = kt
 	task.Patch = patch
 	task.Flow = flow
+	task.DependResults = dependResults
+}
+
+// listDependResults 查询任务在 DependOn 中声明的前置任务的执行结果。
+func listDependResults(bd backend.Backend) func(kt *kit.Kit, task *model.Task) (
+	map[action.ActIDType]types.JsonField, error) {
+
+	return func(kt *kit.Kit, task *model.Task) (map[action.ActIDType]types.JsonField, error) {
+		results := make(map[action.ActIDType]types.JsonField, len(task.DependOn))
+		if len(task.DependOn) == 0 {
+			return results, nil
+		}
+
+		input := &backend.ListInput{
+			Filter: &filter.Expression{
+				Op: filter.And,
+				Rules: []filter.RuleFactory{
+					&filter.AtomRule{Field: "flow_id", Op: filter.Equal.Factory(), Value: task.FlowID},
+					&filter.AtomRule{Field: "action_id", Op: filter.In.Factory(), Value: task.DependOn},
+				},
+			},
+			Page: core.NewDefaultBasePage(),
+		}
+		tasks, err := bd.ListTask(kt, input)
+		if err != nil {
+			logs.Errorf("list depend tasks failed, err: %v, flowID: %s, dependOn: %v, rid: %s", err, task.FlowID,
+				task.DependOn, kt.Rid)
+			return nil, err
+		}
+
+		for _, one := range tasks {
+			results[one.ActionID] = one.Result
+		}
+
+		return results, nil
+	}
+}
+
+// resolveParams 使用前置任务的执行结果渲染任务参数中的参数表达式，任务中保存的参数不变，重试时会重新渲染。
+func (task *Task) resolveParams() (types.JsonField, error) {
+	if !action.HasParamsExpr(task.Params) {
+		return task.Params, nil
+	}
+
+	if task.DependResults == nil {
+		return "", errors.New("task params has expression, but depend results getter not set")
+	}
+
+	results, err := task.DependResults(task.ExecuteKit.Kit(), &task.Task)
+	if err != nil {
+		return "", fmt.Errorf("get depend task results failed, err: %v", err)
+	}
+
+	params, err := action.RenderParams(task.Params, results)
+	if err != nil {
+		logs.Errorf("task render params failed, err: %v, params: %s, rid: %s", err, task.Params,
+			task.ExecuteKit.Kit().Rid)
+		return "", fmt.Errorf("task render params failed, err: %v", err)
+	}
+
+	return params, nil
 }
 
 // Run 任务执行。
@@ -139,6 +209,11 @@ func (task *Task) Run() error {
 		logs.Errorf("task run failed, err: %v, task: %+v, result: %+v, rid: %s", runErr, task, failedResult,
 			task.ExecuteKit.Kit().Rid)
 
+		// 任务被主动取消时，任务状态已由取消方设置，不能覆盖为失败
+		if task.isCanceled() {
+			return runErr
+		}
+
 		if patchErr := task.UpdateTask(enumor.TaskFailed, runErr.Error(), failedResult); patchErr != nil {
 			logs.Errorf("task set failed state failed, after run failed, err: %v, patchErr: %v, rid: %s",
 				runErr, patchErr, task.ExecuteKit.Kit().Rid)
@@ -178,8 +253,13 @@ func (task *Task) Rollback() error {
 		return task.rollback(nil, act)
 	}
 
-	if err := action.Decode(task.Params, p); err != nil {
-		logs.Errorf("task decode params failed, params: %s, type: %s, rid: %s", task.Params,
+	params, err := task.resolveParams()
+	if err != nil {
+		return err
+	}
+
+	if err = action.Decode(params, p); err != nil {
+		logs.Errorf("task decode params failed, params: %s, type: %s, rid: %s", params,
 			reflect.TypeOf(p).String(), task.ExecuteKit.Kit().Rid)
 		return fmt.Errorf("task decode params failed, err: %v", err)
 	}
@@ -187,6 +267,50 @@ func (task *Task) Rollback() error {
 	return task.rollback(p, act)
 }
 
+// Compensate 任务补偿，仅执行成功且实现了 CompensateAction 的任务会被补偿，补偿成功后任务状态更新为 compensated。
+func (task *Task) Compensate() (compensated bool, err error) {
+
+	act, exist := action.GetAction(task.ActionName)
+	if !exist {
+		return false, fmt.Errorf("action: %s not found", task.ActionName)
+	}
+
+	if task.State != enumor.TaskSuccess {
+		return false, fmt.Errorf("task can not compensate，state: %s", task.State)
+	}
+
+	compensateAct, ok := act.(action.CompensateAction)
+	if !ok {
+		return false, nil
+	}
+
+	var p interface{}
+	if paramAct, ok := act.(action.ParameterAction); ok && len(task.Params) != 0 {
+		if p = paramAct.ParameterNew(); p != nil {
+			params, err := task.resolveParams()
+			if err != nil {
+				return false, err
+			}
+
+			if err = action.Decode(params, p); err != nil {
+				logs.Errorf("task decode params failed, params: %s, type: %s, rid: %s", params,
+					reflect.TypeOf(p).String(), task.ExecuteKit.Kit().Rid)
+				return false, fmt.Errorf("task decode params failed, err: %v", err)
+			}
+		}
+	}
+
+	if err = compensateAct.Compensate(task.ExecuteKit, p, task.Result); err != nil {
+		return false, fmt.Errorf("compensate failed, err: %v", err)
+	}
+
+	if err = task.UpdateTask(enumor.TaskCompensated, ErrTaskCompensated, nil); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
 func (task *Task) runOnce(act action.Action) (needRetry bool, failedResult interface{}, err error) {
 	if len(task.Params) == 0 {
 		return task.runAction(nil, act)
@@ -202,8 +326,13 @@ func (task *Task) runOnce(act action.Action) (needRetry bool, failedResult inter
 		return task.runAction(nil, act)
 	}
 
-	if err = action.Decode(task.Params, p); err != nil {
-		logs.Errorf("task decode params failed, params: %s, type: %s, rid: %s", task.Params,
+	params, err := task.resolveParams()
+	if err != nil {
+		return false, nil, err
+	}
+
+	if err = action.Decode(params, p); err != nil {
+		logs.Errorf("task decode params failed, params: %s, type: %s, rid: %s", params,
 			reflect.TypeOf(p).String(), task.ExecuteKit.Kit().Rid)
 		return false, nil, fmt.Errorf("task decode params failed, err: %v", err)
 	}
@@ -253,10 +382,19 @@ func (task *Task) runAction(params interface{}, act action.Action) (retry bool,
 		}
 
 		result, err := act.Run(task.ExecuteKit, params)
+		if task.isCanceled() {
+			return false, result, errors.New(ErrTaskCanceled)
+		}
+
 		if err != nil {
 			return true, result, fmt.Errorf("run failed, err: %v", err)
 		}
 
+		// 运行结果不符合声明的结果结构属于逻辑错误，不进行重试
+		if err = action.ValidateOutput(act, result); err != nil {
+			return false, result, err
+		}
+
 		// 如果执行成功，返回 result 属于成功结果，设置成功状态时，同时设置成功结果。如果执行失败，
 		// 结果属于失败结果，交与上层更新失败或回滚等操作，更新失败结果。
 		if err = task.UpdateStateResult(enumor.TaskSuccess, result); err != nil {
@@ -267,6 +405,11 @@ func (task *Task) runAction(params interface{}, act action.Action) (retry bool,
 	return false, nil, nil
 }
 
+// isCanceled 任务是否被主动取消，超时不属于主动取消。
+func (task *Task) isCanceled() bool {
+	return task.Kit != nil && task.Kit.Ctx != nil && errors.Is(task.Kit.Ctx.Err(), context.Canceled)
+}
+
 // UpdateState update task state.
 func (task *Task) UpdateState(state enumor.TaskState) error {
 	return task.UpdateTask(state, "", nil)
@@ -285,6 +428,8 @@ func (task *Task) UpdateTask(state enumor.TaskState, reason string, result inter
 		Reason: &tableasync.Reason{
 			Message: reason,
 		},
+		Stat:        task.nextStat(state),
+		HeartbeatAt: times.ConvStdTimeFormat(times.ConvStdTimeNow()),
 	}
 
 	if result != nil {
@@ -308,6 +453,37 @@ func (task *Task) UpdateTask(state enumor.TaskState, reason string, result inter
 	}
 
 	task.State = state
+	if md.Stat != nil {
+		task.Stat = md.Stat
+	}
 
 	return nil
 }
+
+// nextStat 计算任务切换到目标状态后的执行统计，进入执行状态时记录开始时间和执行次数，执行结束时记录结束时间，
+// 其他状态不更新执行统计。重试时保留首次开始时间，使执行耗时包含全部重试。
+func (task *Task) nextStat(state enumor.TaskState) *tableasync.TaskStat {
+	stat := new(tableasync.TaskStat)
+	if task.Stat != nil {
+		*stat = *task.Stat
+	}
+
+	now := times.ConvStdTimeFormat(times.ConvStdTimeNow())
+	switch state {
+	case enumor.TaskRunning:
+		if len(stat.StartedAt) == 0 || len(stat.EndedAt) != 0 {
+			stat.StartedAt = now
+			stat.EndedAt = ""
+		}
+		stat.RunCount++
+	case enumor.TaskSuccess, enumor.TaskFailed, enumor.TaskCancel:
+		if len(stat.StartedAt) == 0 {
+			return nil
+		}
+		stat.EndedAt = now
+	default:
+		return nil
+	}
+
+	return stat
+}
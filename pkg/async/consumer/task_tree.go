@@ -74,9 +74,9 @@ func (t *TaskNode) GetParents() []*TaskNode {
 	return t.parents
 }
 
-// CanExecuteChild can execute child
+// CanExecuteChild can execute child, skipped task is regarded as success.
 func (t *TaskNode) CanExecuteChild() bool {
-	return t.State == enumor.TaskSuccess
+	return t.State == enumor.TaskSuccess || t.State == enumor.TaskSkipped
 }
 
 // CanBeExecuted check whether task could be executed
@@ -120,8 +120,12 @@ func (t *TaskNode) ComputeState() (state enumor.FlowState) {
 		case enumor.TaskFailed:
 			state = enumor.FlowFailed
 			return false
-		// 如果当前节点运行成功，继续遍历当前节点子节点。
-		case enumor.TaskSuccess:
+		// 存在已补偿或补偿失败的节点，说明任务流已经失败并在补偿中，无法继续遍历当前节点子节点。
+		case enumor.TaskCompensated, enumor.TaskCompensateFailed:
+			state = enumor.FlowFailed
+			return false
+		// 如果当前节点运行成功或被人工跳过，继续遍历当前节点子节点。
+		case enumor.TaskSuccess, enumor.TaskSkipped:
 			state = enumor.FlowSuccess
 			return true
 		// 如果当前节点处于其他运行中间状态，无法继续遍历当前节点子节点。
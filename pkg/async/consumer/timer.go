@@ -0,0 +1,308 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package consumer
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"hcm/pkg/api/core"
+	"hcm/pkg/async/action"
+	"hcm/pkg/async/backend"
+	"hcm/pkg/async/backend/model"
+	"hcm/pkg/async/compctrl"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/enumor"
+	tableasync "hcm/pkg/dal/table/async"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/runtime/filter"
+	"hcm/pkg/tools/cron"
+	"hcm/pkg/tools/times"
+)
+
+/*
+Timer （定时器）:
+ 1. 查询处于启用状态，且下次执行时间已经到达的定时任务流
+ 2. 通过 CAS 更新下次执行时间抢占本次执行，避免主从切换时重复生成任务流
+ 3. 按照错过执行策略生成任务流，并记录每次计划执行的结果
+*/
+type Timer interface {
+	compctrl.Closer
+	// Start 启动定时器，按照定时任务流的 cron 表达式生成任务流。
+	Start()
+}
+
+const (
+	// maxDueRunsPerRound 单个定时任务流单轮最多处理的计划执行次数，剩余的计划执行在下一轮处理
+	maxDueRunsPerRound = 100
+	// minMissedGracePeriod 计划执行时间与当前时间相差超过宽限期时，视为错过执行
+	minMissedGracePeriod = time.Minute
+)
+
+// timer 定时器
+type timer struct {
+	bd backend.Backend
+
+	watchIntervalSec time.Duration
+	gracePeriod      time.Duration
+
+	wg      sync.WaitGroup
+	closeCh chan struct{}
+}
+
+// NewTimer 创建一个定时器
+func NewTimer(bd backend.Backend, opt *TimerOption) Timer {
+	interval := time.Duration(opt.WatchIntervalSec) * time.Second
+
+	gracePeriod := 2 * interval
+	if gracePeriod < minMissedGracePeriod {
+		gracePeriod = minMissedGracePeriod
+	}
+
+	return &timer{
+		bd:               bd,
+		watchIntervalSec: interval,
+		gracePeriod:      gracePeriod,
+		wg:               sync.WaitGroup{},
+		closeCh:          make(chan struct{}),
+	}
+}
+
+// Start 启动定时器
+func (t *timer) Start() {
+	t.wg.Add(1)
+	go t.watch()
+}
+
+func (t *timer) watch() {
+	defer t.wg.Done()
+
+	for {
+		select {
+		case <-t.closeCh:
+			return
+		default:
+		}
+
+		kt := NewKit()
+		if err := t.Do(kt); err != nil {
+			logs.Errorf("%s: timer do failed, err: %v, rid: %s", constant.AsyncTaskWarnSign, err, kt.Rid)
+		}
+
+		time.Sleep(t.watchIntervalSec)
+	}
+}
+
+// Do 处理到期的定时任务流
+func (t *timer) Do(kt *kit.Kit) error {
+	now := time.Now()
+	input := &backend.ListInput{
+		Filter: &filter.Expression{
+			Op: filter.And,
+			Rules: []filter.RuleFactory{
+				&filter.AtomRule{Field: "state", Op: filter.Equal.Factory(), Value: enumor.FlowScheduleEnabled},
+				&filter.AtomRule{Field: "next_run_at", Op: filter.LessThanEqual.Factory(),
+					Value: times.ConvStdTimeFormat(now)},
+			},
+		},
+		Page: core.NewDefaultBasePage(),
+	}
+	schedules, err := t.bd.ListFlowSchedule(kt, input)
+	if err != nil {
+		logs.Errorf("list flow schedule failed, err: %v, rid: %s", err, kt.Rid)
+		return err
+	}
+
+	for _, one := range schedules {
+		if err = t.handleSchedule(kt, one, now); err != nil {
+			logs.Errorf("%s: handle flow schedule failed, err: %v, id: %s, rid: %s", constant.AsyncTaskWarnSign,
+				err, one.ID, kt.Rid)
+			continue
+		}
+	}
+
+	return nil
+}
+
+// handleSchedule 计算定时任务流所有到期的计划执行时间，抢占成功后按照错过执行策略生成任务流。
+func (t *timer) handleSchedule(kt *kit.Kit, schedule model.FlowSchedule, now time.Time) error {
+	sched, err := cron.Parse(schedule.Cron)
+	if err != nil {
+		return err
+	}
+
+	nextRunAt, err := time.ParseInLocation(constant.TimeStdFormat, schedule.NextRunAt, time.Local)
+	if err != nil {
+		return fmt.Errorf("parse next_run_at failed, err: %v", err)
+	}
+
+	dues, lastMissed, next := t.dueRuns(sched, schedule.MissedPolicy, nextRunAt, now)
+
+	info := &backend.UpdateScheduleNextRunInfo{ID: schedule.ID, Source: nextRunAt, Target: next}
+	if next.IsZero() {
+		// cron 表达式不会再被满足，执行完本轮后暂停定时任务流，同样通过 CAS 抢占本轮执行
+		info.Target = dues[len(dues)-1]
+		info.Pause = true
+	}
+	if err = t.bd.UpdateFlowScheduleNextRunByCAS(kt, info); err != nil {
+		return err
+	}
+
+	runs := make([]model.FlowScheduleRun, 0, len(dues))
+	for _, due := range dues {
+		run := model.FlowScheduleRun{
+			ScheduleID: schedule.ID,
+			PlanAt:     times.ConvStdTimeFormat(due),
+		}
+
+		if !t.shouldTrigger(schedule.MissedPolicy, due, now, due.Equal(lastMissed)) {
+			run.State = enumor.FlowScheduleRunSkipped
+			run.Reason = &tableasync.Reason{
+				Message: fmt.Sprintf("missed run skipped by policy: %s", schedule.MissedPolicy),
+			}
+			runs = append(runs, run)
+			continue
+		}
+
+		flowID, err := t.bd.CreateFlow(kt, scheduleFlowToFlow(schedule.Flow, due))
+		if err != nil {
+			logs.Errorf("create flow of schedule failed, err: %v, id: %s, plan_at: %s, rid: %s", err, schedule.ID,
+				run.PlanAt, kt.Rid)
+			run.State = enumor.FlowScheduleRunFailed
+			run.Reason = &tableasync.Reason{Message: err.Error()}
+			runs = append(runs, run)
+			continue
+		}
+
+		run.State = enumor.FlowScheduleRunTriggered
+		run.FlowID = flowID
+		runs = append(runs, run)
+	}
+
+	if err = t.bd.BatchCreateFlowScheduleRun(kt, runs); err != nil {
+		logs.Errorf("batch create flow schedule run failed, err: %v, id: %s, rid: %s", err, schedule.ID, kt.Rid)
+		return err
+	}
+
+	return nil
+}
+
+// dueRuns 返回从 nextRunAt 开始到当前时间为止需要处理的计划执行时间、最近一次错过执行的时间，以及之后的下次执行时间。
+// 全部执行策略下单轮最多处理 maxDueRunsPerRound 次，剩余的计划执行在下一轮处理；其他策略下错过执行不会生成任务流，
+// 直接根据 cron 表达式推算到当前时间，只保留最近 maxDueRunsPerRound 次错过执行用于记录。
+func (t *timer) dueRuns(sched *cron.Schedule, policy enumor.MissedRunPolicy, nextRunAt, now time.Time) (
+	dues []time.Time, lastMissed time.Time, next time.Time) {
+
+	missed := make([]time.Time, 0)
+	dues = make([]time.Time, 0)
+	next = nextRunAt
+	for !next.IsZero() && !next.After(now) {
+		if policy != enumor.MissedRunAll && now.Sub(next) > t.gracePeriod {
+			if len(missed) >= maxDueRunsPerRound {
+				missed = missed[1:]
+			}
+			missed = append(missed, next)
+			next = sched.Next(next)
+			continue
+		}
+
+		if len(dues) >= maxDueRunsPerRound {
+			break
+		}
+		dues = append(dues, next)
+		next = sched.Next(next)
+	}
+
+	if len(missed) != 0 {
+		lastMissed = missed[len(missed)-1]
+	}
+
+	// 错过执行的时间都早于宽限期内的计划执行时间
+	return append(missed, dues...), lastMissed, next
+}
+
+// shouldTrigger 判断计划执行是否需要生成任务流，超过宽限期的计划执行视为错过执行，按照错过执行策略处理。
+func (t *timer) shouldTrigger(policy enumor.MissedRunPolicy, due, now time.Time, lastMissed bool) bool {
+	if now.Sub(due) <= t.gracePeriod {
+		return true
+	}
+
+	switch policy {
+	case enumor.MissedRunAll:
+		return true
+	case enumor.MissedRunOnce:
+		// 多次错过执行合并为一次，只执行最近的一次
+		return lastMissed
+	default:
+		return false
+	}
+}
+
+// scheduleFlowToFlow 根据定时任务流的任务流定义生成任务流实例
+func scheduleFlowToFlow(flow *tableasync.ScheduleFlow, runAt time.Time) *model.Flow {
+	shareData := flow.ShareData
+	if shareData == nil {
+		shareData = new(tableasync.ShareData)
+	}
+
+	result := &model.Flow{
+		Name:           flow.Name,
+		ShareData:      shareData,
+		Memo:           flow.Memo,
+		RunAt:          times.ConvStdTimeFormat(runAt),
+		RollbackPolicy: flow.RollbackPolicy,
+		Priority:       flow.Priority,
+		Tenant:         flow.Tenant,
+		AppCode:        flow.AppCode,
+		Webhooks:       flow.Webhooks,
+		Tasks:          make([]model.Task, 0, len(flow.Tasks)),
+	}
+
+	for _, one := range flow.Tasks {
+		retry := one.Retry
+		if retry == nil {
+			retry = new(tableasync.Retry)
+		}
+
+		dependOn := make([]action.ActIDType, 0, len(one.DependOn))
+		for _, id := range one.DependOn {
+			dependOn = append(dependOn, action.ActIDType(id))
+		}
+
+		result.Tasks = append(result.Tasks, model.Task{
+			FlowName:   flow.Name,
+			ActionID:   action.ActIDType(one.ActionID),
+			ActionName: one.ActionName,
+			Params:     one.Params,
+			Retry:      retry,
+			DependOn:   dependOn,
+		})
+	}
+
+	return result
+}
+
+// Close 等待当前执行体执行完成后再关闭
+func (t *timer) Close() {
+	close(t.closeCh)
+	t.wg.Wait()
+}
@@ -0,0 +1,291 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package consumer
+
+import (
+	"testing"
+	"time"
+
+	"hcm/pkg/api/core"
+	"hcm/pkg/async/backend"
+	"hcm/pkg/async/backend/model"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/dal/dao/tools"
+	tableasync "hcm/pkg/dal/table/async"
+	"hcm/pkg/tools/times"
+)
+
+func TestTimerMissedRunPolicy(t *testing.T) {
+	now := time.Now()
+	// 每年执行一次，从三年前开始计算，除当年外的计划执行均已错过
+	start := time.Date(now.Year()-3, 1, 1, 0, 0, 0, 0, time.Local)
+	expectNext := time.Date(now.Year()+1, 1, 1, 0, 0, 0, 0, time.Local)
+
+	cases := []struct {
+		policy    enumor.MissedRunPolicy
+		triggered int
+		skipped   int
+	}{
+		{policy: enumor.MissedRunSkip, triggered: 0, skipped: 4},
+		{policy: enumor.MissedRunOnce, triggered: 1, skipped: 3},
+		{policy: enumor.MissedRunAll, triggered: 4, skipped: 0},
+	}
+
+	for _, c := range cases {
+		bd := backend.NewMemory()
+		kt := newTestKit()
+		id, err := bd.CreateFlowSchedule(kt, &model.FlowSchedule{
+			Name:         string(c.policy),
+			Cron:         "@yearly",
+			State:        enumor.FlowScheduleEnabled,
+			MissedPolicy: c.policy,
+			Flow: &tableasync.ScheduleFlow{
+				Name:  "timer_test",
+				Tasks: []tableasync.ScheduleFlowTask{{ActionID: "1", ActionName: "test"}},
+			},
+			NextRunAt: times.ConvStdTimeFormat(start),
+		})
+		if err != nil {
+			t.Fatalf("create flow schedule failed, err: %v", err)
+		}
+
+		tm := NewTimer(bd, &TimerOption{WatchIntervalSec: 1}).(*timer)
+		if err = tm.Do(kt); err != nil {
+			t.Fatalf("timer do failed, err: %v", err)
+		}
+
+		input := &backend.ListInput{
+			Filter: tools.EqualExpression("schedule_id", id),
+			Page:   core.NewDefaultBasePage(),
+		}
+		runs, err := bd.ListFlowScheduleRun(kt, input)
+		if err != nil {
+			t.Fatalf("list flow schedule run failed, err: %v", err)
+		}
+
+		triggered, skipped := 0, 0
+		for _, one := range runs {
+			switch one.State {
+			case enumor.FlowScheduleRunTriggered:
+				triggered++
+			case enumor.FlowScheduleRunSkipped:
+				skipped++
+			}
+		}
+		if triggered != c.triggered || skipped != c.skipped {
+			t.Errorf("policy %s should trigger %d and skip %d runs, but got %d, %d", c.policy, c.triggered,
+				c.skipped, triggered, skipped)
+		}
+
+		flows, err := bd.ListFlow(kt, &backend.ListInput{
+			Filter: tools.EqualExpression("name", "timer_test"),
+			Page:   core.NewDefaultBasePage(),
+		})
+		if err != nil {
+			t.Fatalf("list flow failed, err: %v", err)
+		}
+		if len(flows) != c.triggered {
+			t.Errorf("policy %s should create %d flows, but got %d", c.policy, c.triggered, len(flows))
+		}
+
+		schedules, err := bd.ListFlowSchedule(kt, &backend.ListInput{
+			Filter: tools.EqualExpression("id", id),
+			Page:   core.NewDefaultBasePage(),
+		})
+		if err != nil {
+			t.Fatalf("list flow schedule failed, err: %v", err)
+		}
+		if schedules[0].NextRunAt != times.ConvStdTimeFormat(expectNext) {
+			t.Errorf("next_run_at should be %s, but got %s", times.ConvStdTimeFormat(expectNext),
+				schedules[0].NextRunAt)
+		}
+
+		// 下次执行时间未到达，再次执行不会生成任务流
+		if err = tm.Do(kt); err != nil {
+			t.Fatalf("timer do failed, err: %v", err)
+		}
+		if runs, err = bd.ListFlowScheduleRun(kt, input); err != nil || len(runs) != 4 {
+			t.Errorf("flow schedule should not run again, got: %d, err: %v", len(runs), err)
+		}
+	}
+}
+
+func TestTimerPausedSchedule(t *testing.T) {
+	bd := backend.NewMemory()
+	kt := newTestKit()
+	id, err := bd.CreateFlowSchedule(kt, &model.FlowSchedule{
+		Name:         "paused",
+		Cron:         "* * * * *",
+		State:        enumor.FlowSchedulePaused,
+		MissedPolicy: enumor.MissedRunAll,
+		Flow: &tableasync.ScheduleFlow{
+			Name:  "timer_test",
+			Tasks: []tableasync.ScheduleFlowTask{{ActionID: "1", ActionName: "test"}},
+		},
+		NextRunAt: times.ConvStdTimeFormat(time.Now().Add(-time.Hour)),
+	})
+	if err != nil {
+		t.Fatalf("create flow schedule failed, err: %v", err)
+	}
+
+	if err = NewTimer(bd, &TimerOption{WatchIntervalSec: 1}).(*timer).Do(kt); err != nil {
+		t.Fatalf("timer do failed, err: %v", err)
+	}
+
+	runs, err := bd.ListFlowScheduleRun(kt, &backend.ListInput{
+		Filter: tools.EqualExpression("schedule_id", id),
+		Page:   core.NewDefaultBasePage(),
+	})
+	if err != nil {
+		t.Fatalf("list flow schedule run failed, err: %v", err)
+	}
+	if len(runs) != 0 {
+		t.Errorf("paused flow schedule should not run, but got %d runs", len(runs))
+	}
+}
+
+func TestTimerMissedRunOnceBeyondRoundLimit(t *testing.T) {
+	now := time.Now()
+	// 每小时执行一次，错过执行次数超过单轮处理上限
+	start := now.Truncate(time.Hour).Add(-time.Duration(maxDueRunsPerRound+50) * time.Hour)
+	latest := now.Truncate(time.Hour)
+
+	bd := backend.NewMemory()
+	kt := newTestKit()
+	id, err := bd.CreateFlowSchedule(kt, &model.FlowSchedule{
+		Name:         "run_once",
+		Cron:         "0 * * * *",
+		State:        enumor.FlowScheduleEnabled,
+		MissedPolicy: enumor.MissedRunOnce,
+		Flow: &tableasync.ScheduleFlow{
+			Name:  "timer_test",
+			Tasks: []tableasync.ScheduleFlowTask{{ActionID: "1", ActionName: "test"}},
+		},
+		NextRunAt: times.ConvStdTimeFormat(start),
+	})
+	if err != nil {
+		t.Fatalf("create flow schedule failed, err: %v", err)
+	}
+
+	tm := NewTimer(bd, &TimerOption{WatchIntervalSec: 1}).(*timer)
+	// 宽限期置为0，所有已到期的计划执行均视为错过执行
+	tm.gracePeriod = 0
+	if err = tm.handleSchedule(kt, getTestFlowSchedule(t, bd, id), now); err != nil {
+		t.Fatalf("handle flow schedule failed, err: %v", err)
+	}
+
+	runs, err := bd.ListFlowScheduleRun(kt, &backend.ListInput{
+		Filter: tools.EqualExpression("schedule_id", id),
+		Page:   core.NewDefaultBasePage(),
+	})
+	if err != nil {
+		t.Fatalf("list flow schedule run failed, err: %v", err)
+	}
+
+	triggered := make([]string, 0)
+	for _, one := range runs {
+		if one.State == enumor.FlowScheduleRunTriggered {
+			triggered = append(triggered, one.PlanAt)
+		}
+	}
+	if len(triggered) != 1 || triggered[0] != times.ConvStdTimeFormat(latest) {
+		t.Errorf("run_once should only trigger the latest missed run %s, but got %v",
+			times.ConvStdTimeFormat(latest), triggered)
+	}
+
+	expectNext := times.ConvStdTimeFormat(latest.Add(time.Hour))
+	if schedule := getTestFlowSchedule(t, bd, id); schedule.NextRunAt != expectNext {
+		t.Errorf("next_run_at should be %s, but got %s", expectNext, schedule.NextRunAt)
+	}
+}
+
+func TestTimerPauseScheduleByCAS(t *testing.T) {
+	bd := backend.NewMemory()
+	kt := newTestKit()
+	newSchedule := func(name string) string {
+		id, err := bd.CreateFlowSchedule(kt, &model.FlowSchedule{
+			Name:         name,
+			Cron:         "0 0 30 2 *",
+			State:        enumor.FlowScheduleEnabled,
+			MissedPolicy: enumor.MissedRunAll,
+			Flow: &tableasync.ScheduleFlow{
+				Name:  "timer_test",
+				Tasks: []tableasync.ScheduleFlowTask{{ActionID: "1", ActionName: "test"}},
+			},
+			NextRunAt: times.ConvStdTimeFormat(time.Now().Add(-time.Hour)),
+		})
+		if err != nil {
+			t.Fatalf("create flow schedule failed, err: %v", err)
+		}
+		return id
+	}
+
+	tm := NewTimer(bd, &TimerOption{WatchIntervalSec: 1}).(*timer)
+
+	// cron 表达式不会再被满足，执行本轮后暂停
+	id := newSchedule("never")
+	if err := tm.handleSchedule(kt, getTestFlowSchedule(t, bd, id), time.Now()); err != nil {
+		t.Fatalf("handle flow schedule failed, err: %v", err)
+	}
+	if schedule := getTestFlowSchedule(t, bd, id); schedule.State != enumor.FlowSchedulePaused {
+		t.Errorf("flow schedule should be paused, but got %s", schedule.State)
+	}
+
+	// 下次执行时间已被其他节点更新时抢占失败，定时任务流保持不变
+	id = newSchedule("never_stale")
+	stale := getTestFlowSchedule(t, bd, id)
+	other := time.Now().Add(-time.Minute)
+	source, err := time.ParseInLocation(constant.TimeStdFormat, stale.NextRunAt, time.Local)
+	if err != nil {
+		t.Fatalf("parse next_run_at failed, err: %v", err)
+	}
+	info := &backend.UpdateScheduleNextRunInfo{ID: id, Source: source, Target: other}
+	if err = bd.UpdateFlowScheduleNextRunByCAS(kt, info); err != nil {
+		t.Fatalf("update next run failed, err: %v", err)
+	}
+
+	if err = tm.handleSchedule(kt, stale, time.Now()); err == nil {
+		t.Errorf("handle flow schedule with stale next_run_at should be failed")
+	}
+	if schedule := getTestFlowSchedule(t, bd, id); schedule.State != enumor.FlowScheduleEnabled {
+		t.Errorf("flow schedule should not be paused when cas failed, but got %s", schedule.State)
+	}
+
+	runs, err := bd.ListFlowScheduleRun(kt, &backend.ListInput{
+		Filter: tools.EqualExpression("schedule_id", id),
+		Page:   core.NewDefaultBasePage(),
+	})
+	if err != nil || len(runs) != 0 {
+		t.Errorf("flow schedule should not run when cas failed, got: %d, err: %v", len(runs), err)
+	}
+}
+
+// getTestFlowSchedule 查询定时任务流
+func getTestFlowSchedule(t *testing.T, bd backend.Backend, id string) model.FlowSchedule {
+	schedules, err := bd.ListFlowSchedule(newTestKit(), &backend.ListInput{
+		Filter: tools.EqualExpression("id", id),
+		Page:   core.NewDefaultBasePage(),
+	})
+	if err != nil || len(schedules) != 1 {
+		t.Fatalf("list flow schedule failed, err: %v, count: %d", err, len(schedules))
+	}
+	return schedules[0]
+}
@@ -40,16 +40,27 @@ var (
 const (
 	// ErrTaskExecTimeout 任务执行超时
 	ErrTaskExecTimeout = "task exec timeout"
+	// ErrTaskHeartbeatTimeout 任务心跳超时
+	ErrTaskHeartbeatTimeout = "task heartbeat timeout"
 	// ErrTaskNodeShutdown 任务节点关闭
 	ErrTaskNodeShutdown = "task node shutdown"
 	// ErrSomeTaskExecFailed 部分任务执行失败
 	ErrSomeTaskExecFailed = "some tasks failed to be executed"
+	// ErrTaskCanceled 任务被取消
+	ErrTaskCanceled = "task canceled"
+	// ErrTaskCompensated 任务流失败后，任务已被补偿
+	ErrTaskCompensated = "task compensated after flow failed"
+	// ErrTaskCompensateFailed 任务流失败后，任务补偿失败
+	ErrTaskCompensateFailed = "task compensate failed after flow failed"
 
 	//  listScheduledFlowLimit 每次调度器查询分配给当前节点的任务流数量
 	listScheduledFlowLimit = 10
 
 	// listExpiredTasksLimit 每次WatchDog查询超时任务的数量
 	listExpiredTasksLimit = 100
+
+	// listCompensatingFlowLimit 每次补偿器查询待补偿的任务流数量
+	listCompensatingFlowLimit = 100
 )
 
 // Flow 消费所需的异步任务流。
@@ -32,6 +32,7 @@ import (
 	"hcm/pkg/async/consumer/leader"
 	"hcm/pkg/criteria/constant"
 	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
 	tableasync "hcm/pkg/dal/table/async"
 	"hcm/pkg/kit"
 	"hcm/pkg/logs"
@@ -42,7 +43,7 @@ import (
 
 /*
 WatchDog （看门狗）:
-	1. 处理超时任务
+	1. 处理心跳超时的任务，可重试的任务回滚后重新调度，否则置为失败
 	2. 处理处于Scheduled状态，但执行节点已经挂掉的任务流
 	3. 处理处于Running状态，但执行节点正在Shutdown或者已经挂掉的任务流
 */
@@ -57,7 +58,7 @@ type watchDog struct {
 	bd backend.Backend
 	ld leader.Leader
 
-	taskTimeoutSec      time.Duration
+	heartbeatTimeout    time.Duration
 	shutdownWaitTimeSec time.Duration
 	watchIntervalSec    time.Duration
 
@@ -73,7 +74,7 @@ func NewWatchDog(bd backend.Backend, ld leader.Leader, opt *WatchDogOption) Watc
 	return &watchDog{
 		bd:                  bd,
 		ld:                  ld,
-		taskTimeoutSec:      time.Duration(opt.TaskRunTimeoutSec) * time.Second,
+		heartbeatTimeout:    time.Duration(opt.HeartbeatTimeoutSec) * time.Second,
 		shutdownWaitTimeSec: time.Duration(opt.ShutdownWaitTimeSec) * time.Second,
 		watchIntervalSec:    time.Duration(opt.WatchIntervalSec) * time.Second,
 		wg:                  sync.WaitGroup{},
@@ -118,7 +119,8 @@ func (wd *watchDog) Close() {
 	wd.wg.Wait()
 }
 
-// handleExpiredTasks 将超时任务和所属的任务流，设置为失败状态，失败原因：ErrTaskExecTimeout
+// handleExpiredTasks 处理心跳超时的任务，心跳超时说明执行节点已经挂掉或任务卡死。可重试的任务回滚后将任务流重新
+// 置为Pending等待调度，不可重试的任务和所属的任务流设置为失败状态，失败原因：ErrTaskHeartbeatTimeout
 func (wd *watchDog) handleExpiredTasks(kt *kit.Kit) error {
 
 	input := &backend.ListInput{
@@ -131,9 +133,9 @@ func (wd *watchDog) handleExpiredTasks(kt *kit.Kit) error {
 					Value: []enumor.TaskState{enumor.TaskRunning, enumor.TaskRollback},
 				},
 				&filter.AtomRule{
-					Field: "updated_at",
+					Field: "heartbeat_at",
 					Op:    filter.LessThan.Factory(),
-					Value: times.ConvStdTimeFormat(times.ConvStdTimeNow().Add(-wd.taskTimeoutSec)),
+					Value: times.ConvStdTimeFormat(times.ConvStdTimeNow().Add(-wd.heartbeatTimeout)),
 				},
 			},
 		},
@@ -156,21 +158,11 @@ func (wd *watchDog) handleExpiredTasks(kt *kit.Kit) error {
 	ids := make([]string, 0, len(tasks))
 	for _, one := range tasks {
 		ids = append(ids, one.ID)
-		if err = wd.updateTimeoutTask(kt, one.ID); err != nil {
-			return err
+		task := &Task{
+			Task: one,
+			Kit:  kt.NewSubKit(),
 		}
-
-		flows := []model.Flow{
-			{
-				ID:    one.FlowID,
-				State: enumor.FlowFailed,
-				Reason: &tableasync.Reason{
-					Message: ErrTaskExecTimeout,
-				},
-			},
-		}
-		if err = wd.bd.BatchUpdateFlow(kt, flows); err != nil {
-			logs.Errorf("update flow to failed state failed, err: %v, rid: %s", err, kt.Rid)
+		if err = wd.handleHeartbeatTimeoutTask(kt, task); err != nil {
 			return err
 		}
 	}
@@ -180,12 +172,86 @@ func (wd *watchDog) handleExpiredTasks(kt *kit.Kit) error {
 	return nil
 }
 
-func (wd *watchDog) updateTimeoutTask(kt *kit.Kit, id string) error {
+// handleHeartbeatTimeoutTask 处理心跳超时的任务，任务可重试则回滚任务并将任务流重新置于Pending状态，
+// 回滚后的任务可以通过 Checkpoint 获取上次上报的断点数据继续执行。
+func (wd *watchDog) handleHeartbeatTimeoutTask(kt *kit.Kit, task *Task) error {
+	if !task.Retry.IsEnable() {
+		return wd.failTimeoutTask(kt, task.ID, task.FlowID, ErrTaskHeartbeatTimeout)
+	}
+
+	flow, err := getFlow(kt, wd.bd, task.FlowID)
+	if err != nil {
+		return err
+	}
+
+	// 任务流已被取消、重启等不再处于运行中，由对应的操作处理任务，不需要重试
+	if flow.State != enumor.FlowRunning {
+		logs.Infof("heartbeat timeout task's flow is not running, skip, flow: %s, state: %s, task: %s, rid: %s",
+			flow.ID, flow.State, task.ID, kt.Rid)
+		return nil
+	}
+
+	task.InitDep(run.NewExecuteContext(task.Kit, flow.ShareData, newTaskProgress(task)),
+		func(kt *kit.Kit, task *model.Task) error {
+			return wd.bd.UpdateTask(kt, task)
+		}, &Flow{Flow: *flow}, listDependResults(wd.bd))
+
+	if err = task.Rollback(); err != nil {
+		logs.Errorf("rollback heartbeat timeout task failed, err: %v, id: %s, rid: %s", err, task.ID, kt.Rid)
+		return wd.failTimeoutTask(kt, task.ID, task.FlowID,
+			fmt.Sprintf("rollback heartbeat timeout task failed, err: %v", err))
+	}
+
+	// 通过CAS将任务流从运行中重置为待调度，任务流状态已被其他操作变更时保持不变
+	info := backend.UpdateFlowInfo{
+		ID:          flow.ID,
+		Source:      enumor.FlowRunning,
+		Target:      enumor.FlowPending,
+		ResetWorker: true,
+	}
+	if err = wd.bd.BatchUpdateFlowStateByCAS(kt, []backend.UpdateFlowInfo{info}); err != nil {
+		if ef := errf.Error(err); ef != nil && ef.Code == errf.RecordNotUpdate {
+			logs.Infof("heartbeat timeout task's flow state changed, skip reset it to pending, flow: %s, rid: %s",
+				flow.ID, kt.Rid)
+			return nil
+		}
+
+		logs.Errorf("update flow to pending state failed, err: %v, id: %s, rid: %s", err, flow.ID, kt.Rid)
+		return err
+	}
+
+	return nil
+}
+
+// failTimeoutTask 将超时任务和所属的任务流设置为失败状态
+func (wd *watchDog) failTimeoutTask(kt *kit.Kit, id, flowID, reason string) error {
+	if err := wd.updateTimeoutTask(kt, id, reason); err != nil {
+		return err
+	}
+
+	flows := []model.Flow{
+		{
+			ID:    flowID,
+			State: enumor.FlowFailed,
+			Reason: &tableasync.Reason{
+				Message: reason,
+			},
+		},
+	}
+	if err := wd.bd.BatchUpdateFlow(kt, flows); err != nil {
+		logs.Errorf("update flow to failed state failed, err: %v, rid: %s", err, kt.Rid)
+		return err
+	}
+
+	return nil
+}
+
+func (wd *watchDog) updateTimeoutTask(kt *kit.Kit, id, reason string) error {
 	task := &model.Task{
 		ID:    id,
 		State: enumor.TaskFailed,
 		Reason: &tableasync.Reason{
-			Message: ErrTaskExecTimeout,
+			Message: reason,
 		},
 	}
 	if err := wd.bd.UpdateTask(kt, task); err != nil {
@@ -317,9 +383,9 @@ func (wd *watchDog) handleRunningFlow(kt *kit.Kit, flow model.Flow) error {
 		return err
 	}
 
-	// 如果树已经处于结束状态，则直接更新
+	// 如果树已经处于结束状态，则直接更新。回滚策略为 saga 的失败任务流交由补偿器补偿已成功的任务。
 	state := root.ComputeState()
-	if state == enumor.FlowSuccess || state == enumor.FlowFailed {
+	if state == enumor.FlowSuccess {
 		if err = updateFlowState(kt, wd.bd, flow.ID, enumor.FlowRunning, state); err != nil {
 			logs.Errorf("update flow state to %s failed, err: %v, rid: %s", state, err, kt.Rid)
 			return err
@@ -328,6 +394,10 @@ func (wd *watchDog) handleRunningFlow(kt *kit.Kit, flow model.Flow) error {
 		return nil
 	}
 
+	if state == enumor.FlowFailed {
+		return failFlow(kt, wd.bd, &Flow{Flow: flow, Kit: kt})
+	}
+
 	ids := root.GetExecStateTasks()
 	// 如果没有处于执行中的节点，将Flow置于Pending状态，等待重新被调度
 	if len(ids) == 0 {
@@ -363,9 +433,9 @@ func (wd *watchDog) handleRunningTasks(kt *kit.Kit, flow model.Flow, ids []strin
 	}
 
 	for _, task := range tasks {
-		// 如果任务已经超时，更新为失败状态，失败原因超时
-		if task.UpdatedAt < times.ConvStdTimeFormat(times.ConvStdTimeNow().Add(-wd.taskTimeoutSec)) {
-			if err = wd.updateTimeoutTask(kt, task.ID); err != nil {
+		// 如果任务心跳已经超时，更新为失败状态，失败原因心跳超时
+		if task.HeartbeatAt < times.ConvStdTimeFormat(times.ConvStdTimeNow().Add(-wd.heartbeatTimeout)) {
+			if err = wd.updateTimeoutTask(kt, task.ID, ErrTaskHeartbeatTimeout); err != nil {
 				return err
 			}
 			continue
@@ -386,9 +456,10 @@ func (wd *watchDog) handleRunningTasks(kt *kit.Kit, flow model.Flow, ids []strin
 			}
 		}
 
-		task.InitDep(run.NewExecuteContext(task.Kit, flow.ShareData), func(kt *kit.Kit, task *model.Task) error {
-			return wd.bd.UpdateTask(kt, task)
-		}, &Flow{Flow: flow})
+		task.InitDep(run.NewExecuteContext(task.Kit, flow.ShareData, newTaskProgress(task)),
+			func(kt *kit.Kit, task *model.Task) error {
+				return wd.bd.UpdateTask(kt, task)
+			}, &Flow{Flow: flow}, listDependResults(wd.bd))
 
 		// 如果任务可以重试，将任务回滚
 		if err = task.Rollback(); err != nil {
@@ -23,12 +23,14 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 
 	"hcm/pkg/async/consumer"
+	"hcm/pkg/async/producer"
 	"hcm/pkg/criteria/validator"
 )
 
 // Option define async option.
 type Option struct {
 	Register       prometheus.Registerer `json:"Register" validate:"required"`
+	ProducerOption *producer.Option      `json:"producer_option" validate:"required"`
 	ConsumerOption *consumer.Option      `json:"consumer_option" validate:"required"`
 }
 
@@ -41,5 +43,13 @@ func (opt *Option) tryDefaultValue() {
 
 // Validate define Option.
 func (opt *Option) Validate() error {
-	return validator.Validate.Struct(opt)
+	if err := validator.Validate.Struct(opt); err != nil {
+		return err
+	}
+
+	if err := opt.ProducerOption.Validate(); err != nil {
+		return err
+	}
+
+	return opt.ConsumerOption.Validate()
 }
@@ -25,6 +25,7 @@ import (
 	"hcm/pkg/async/action"
 	"hcm/pkg/async/backend/model"
 	tableasync "hcm/pkg/dal/table/async"
+	"hcm/pkg/dal/table/types"
 	"hcm/pkg/kit"
 	"hcm/pkg/logs"
 )
@@ -42,7 +43,7 @@ func (p *producer) AddCustomFlow(kt *kit.Kit, opt *AddCustomFlowOption) (id stri
 
 	flow := buildCustomFlow(opt)
 
-	id, err = p.backend.CreateFlow(kt, flow)
+	id, err = p.createFlow(kt, flow)
 	if err != nil {
 		logs.Errorf("create flow failed, err: %v, rid: %s", err, kt.Rid)
 		return "", err
@@ -75,8 +76,14 @@ func validateCustomFlowParam(kt *kit.Kit, opt *AddCustomFlowOption) error {
 				return fmt.Errorf("action: %s need params, but not impl ParameterAction", task.ActionName)
 			}
 
+			// 参数中包含参数表达式时，参数在任务执行前才能渲染，仅校验表达式引用的前置任务
+			hasExpr, err := validateParamsExpr(task.Params, task.DependOn)
+			if err != nil {
+				return fmt.Errorf("action: %s params expression is invalid, err: %v", task.ActionID, err)
+			}
+
 			params := paramAct.ParameterNew()
-			if err := action.Decode(task.Params, params); err != nil {
+			if err = action.Decode(task.Params, params); !hasExpr && err != nil {
 				logs.Errorf("action: %s can not decode params, err: %v, field: %s, type: %T, rid: %s", task.ActionName,
 					err, task.Params, params, kt.Rid)
 				return fmt.Errorf("action: %s can not decode param, err: %v", task.ActionName, err)
@@ -102,16 +109,46 @@ func validateCustomFlowParam(kt *kit.Kit, opt *AddCustomFlowOption) error {
 	return nil
 }
 
+// validateParamsExpr 校验参数表达式引用的任务都在 DependOn 中，返回参数中是否包含参数表达式。
+func validateParamsExpr(params types.JsonField, dependOn []action.ActIDType) (bool, error) {
+	if !action.HasParamsExpr(params) {
+		return false, nil
+	}
+
+	refs, err := action.ParamsExprRefs(params)
+	if err != nil {
+		return true, err
+	}
+
+	dependMap := make(map[action.ActIDType]bool, len(dependOn))
+	for _, one := range dependOn {
+		dependMap[one] = true
+	}
+
+	for _, ref := range refs {
+		if !dependMap[ref] {
+			return true, fmt.Errorf("referenced actionID: %s not in dependOn", ref)
+		}
+	}
+
+	return true, nil
+}
+
 func buildCustomFlow(opt *AddCustomFlowOption) *model.Flow {
 	if opt.ShareData == nil {
 		opt.ShareData = new(tableasync.ShareData)
 	}
 
 	flow := &model.Flow{
-		Name:      opt.Name,
-		ShareData: opt.ShareData,
-		Memo:      opt.Memo,
-		Tasks:     make([]model.Task, 0, len(opt.Tasks)),
+		Name:           opt.Name,
+		ShareData:      opt.ShareData,
+		Memo:           opt.Memo,
+		RunAt:          opt.RunAt,
+		RollbackPolicy: opt.RollbackPolicy,
+		Priority:       opt.Priority,
+		Tenant:         opt.Tenant,
+		Webhooks:       opt.Webhooks,
+		Tasks:          make([]model.Task, 0, len(opt.Tasks)),
 	}
 
 	for _, one := range opt.Tasks {
@@ -0,0 +1,131 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package producer
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"hcm/pkg/async/action"
+	"hcm/pkg/async/backend/model"
+	"hcm/pkg/criteria/enumor"
+	tableasync "hcm/pkg/dal/table/async"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/tools/cron"
+	"hcm/pkg/tools/times"
+)
+
+// AddFlowSchedule add flow schedule
+func (p *producer) AddFlowSchedule(kt *kit.Kit, opt *AddFlowScheduleOption) (id string, err error) {
+	if err = opt.Validate(); err != nil {
+		return "", err
+	}
+
+	var flow *model.Flow
+	if opt.TemplateFlow != nil {
+		tpl, exist := action.GetTpl(opt.TemplateFlow.Name)
+		if !exist {
+			return "", fmt.Errorf("flow tempalte: %s not found", opt.TemplateFlow.Name)
+		}
+
+		if err = validateTplUseParam(kt, tpl, opt.TemplateFlow); err != nil {
+			logs.Errorf("validate flow template use param failed, err: %v, rid: %s", err, kt.Rid)
+			return "", err
+		}
+
+		flow = buildFlow(tpl, opt.TemplateFlow)
+	} else {
+		if err = validateCustomFlowParam(kt, opt.CustomFlow); err != nil {
+			logs.Errorf("validate custom flow param failed, err: %v, rid: %s", err, kt.Rid)
+			return "", err
+		}
+
+		flow = buildCustomFlow(opt.CustomFlow)
+	}
+
+	// 定时任务流由 Timer 创建，需要记录创建定时任务流的 app_code，用于投递任务流事件
+	flow.AppCode = kt.AppCode
+
+	sched, err := cron.Parse(opt.Cron)
+	if err != nil {
+		return "", err
+	}
+
+	nextRunAt := sched.Next(time.Now())
+	if nextRunAt.IsZero() {
+		return "", errors.New("cron will never be satisfied")
+	}
+
+	policy := opt.MissedPolicy
+	if len(policy) == 0 {
+		policy = enumor.MissedRunSkip
+	}
+
+	schedule := &model.FlowSchedule{
+		Name:         opt.Name,
+		Cron:         opt.Cron,
+		State:        enumor.FlowScheduleEnabled,
+		MissedPolicy: policy,
+		Flow:         buildScheduleFlow(flow),
+		NextRunAt:    times.ConvStdTimeFormat(nextRunAt),
+		Memo:         opt.Memo,
+	}
+	id, err = p.backend.CreateFlowSchedule(kt, schedule)
+	if err != nil {
+		logs.Errorf("create flow schedule failed, err: %v, rid: %s", err, kt.Rid)
+		return "", err
+	}
+
+	return id, nil
+}
+
+// buildScheduleFlow 将任务流转换为定时任务流保存的任务流定义
+func buildScheduleFlow(flow *model.Flow) *tableasync.ScheduleFlow {
+	result := &tableasync.ScheduleFlow{
+		Name:           flow.Name,
+		Memo:           flow.Memo,
+		ShareData:      flow.ShareData,
+		RollbackPolicy: flow.RollbackPolicy,
+		Priority:       flow.Priority,
+		Tenant:         flow.Tenant,
+		AppCode:        flow.AppCode,
+		Webhooks:       flow.Webhooks,
+		Tasks:          make([]tableasync.ScheduleFlowTask, 0, len(flow.Tasks)),
+	}
+
+	for _, one := range flow.Tasks {
+		dependOn := make([]string, 0, len(one.DependOn))
+		for _, id := range one.DependOn {
+			dependOn = append(dependOn, string(id))
+		}
+
+		result.Tasks = append(result.Tasks, tableasync.ScheduleFlowTask{
+			ActionID:   string(one.ActionID),
+			ActionName: one.ActionName,
+			Params:     one.Params,
+			Retry:      one.Retry,
+			DependOn:   dependOn,
+		})
+	}
+
+	return result
+}
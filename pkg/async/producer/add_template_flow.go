@@ -48,7 +48,7 @@ func (p *producer) AddTemplateFlow(kt *kit.Kit, opt *AddTemplateFlowOption) (id
 
 	flow := buildFlow(tpl, opt)
 
-	id, err = p.backend.CreateFlow(kt, flow)
+	id, err = p.createFlow(kt, flow)
 	if err != nil {
 		logs.Errorf("create flow failed, err: %v, rid: %s", err, kt.Rid)
 		return "", err
@@ -58,11 +58,26 @@ func (p *producer) AddTemplateFlow(kt *kit.Kit, opt *AddTemplateFlowOption) (id
 }
 
 func buildFlow(tpl action.FlowTemplate, opt *AddTemplateFlowOption) *model.Flow {
+	policy := opt.RollbackPolicy
+	if len(policy) == 0 {
+		policy = tpl.RollbackPolicy
+	}
+
+	priority := opt.Priority
+	if len(priority) == 0 {
+		priority = tpl.Priority
+	}
+
 	flow := &model.Flow{
-		Name:      tpl.Name,
-		ShareData: tpl.ShareData,
-		Memo:      opt.Memo,
-		Tasks:     make([]model.Task, 0, len(tpl.Tasks)),
+		Name:           tpl.Name,
+		ShareData:      tpl.ShareData,
+		Memo:           opt.Memo,
+		RunAt:          opt.RunAt,
+		RollbackPolicy: policy,
+		Priority:       priority,
+		Tenant:         opt.Tenant,
+		Webhooks:       opt.Webhooks,
+		Tasks:          make([]model.Task, 0, len(tpl.Tasks)),
 	}
 
 	m := make(map[action.ActIDType]types.JsonField, len(opt.Tasks))
@@ -117,8 +132,13 @@ func validateTplUseParam(kt *kit.Kit, template action.FlowTemplate, opt *AddTemp
 				return fmt.Errorf("action: %s need params, but not impl ParameterAction", task.ActionName)
 			}
 
+			hasExpr, err := validateParamsExpr(fields, task.DependOn)
+			if err != nil {
+				return fmt.Errorf("action: %s params expression is invalid, err: %v", task.ActionID, err)
+			}
+
 			params := paramAct.ParameterNew()
-			if err := action.Decode(fields, params); err != nil {
+			if err = action.Decode(fields, params); !hasExpr && err != nil {
 				logs.Errorf("action: %s can not decode params, err: %v, field: %s, type: %T, rid: %s", task.ActionName,
 					err, fields, params, kt.Rid)
 				return fmt.Errorf("action: %s can not decode param, err: %v", task.ActionName, err)
@@ -0,0 +1,140 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package producer
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"time"
+
+	"hcm/pkg/async/action"
+	"hcm/pkg/async/backend/model"
+	"hcm/pkg/criteria/enumor"
+	tableasync "hcm/pkg/dal/table/async"
+	"hcm/pkg/dal/table/types"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/tools/json"
+	"hcm/pkg/tools/times"
+)
+
+// createFlow 创建任务流，请求携带幂等键时按照幂等键创建，相同幂等键和相同任务流内容的重复请求返回首次创建的任务流ID。
+func (p *producer) createFlow(kt *kit.Kit, flow *model.Flow) (string, error) {
+	if len(kt.IdempotencyKey) == 0 {
+		return p.backend.CreateFlow(kt, flow)
+	}
+
+	hash, err := flowPayloadHash(flow)
+	if err != nil {
+		logs.Errorf("calculate flow payload hash failed, err: %v, rid: %s", err, kt.Rid)
+		return "", err
+	}
+
+	idem := &model.FlowIdempotency{
+		AppCode:        kt.AppCode,
+		IdempotencyKey: kt.IdempotencyKey,
+		PayloadHash:    hash,
+		ExpiredAt:      times.ConvStdTimeFormat(time.Now().Add(p.idempotencyKeyTTL)),
+	}
+	return p.backend.CreateFlowIdempotently(kt, flow, idem)
+}
+
+// flowPayload 参与幂等摘要计算的任务流内容，只包含创建请求中指定的字段。
+type flowPayload struct {
+	Name           enumor.FlowName           `json:"name"`
+	ShareData      map[string]string         `json:"share_data"`
+	Memo           string                    `json:"memo"`
+	RunAt          string                    `json:"run_at"`
+	RollbackPolicy enumor.FlowRollbackPolicy `json:"rollback_policy"`
+	Priority       enumor.FlowPriority       `json:"priority"`
+	Tenant         string                    `json:"tenant"`
+	Webhooks       tableasync.WebhookTargets `json:"webhooks"`
+	Tasks          []taskPayload             `json:"tasks"`
+}
+
+// taskPayload 参与幂等摘要计算的任务内容。
+type taskPayload struct {
+	ActionID   action.ActIDType   `json:"action_id"`
+	ActionName enumor.ActionName  `json:"action_name"`
+	Params     interface{}        `json:"params"`
+	Retry      *tableasync.Retry  `json:"retry"`
+	DependOn   []action.ActIDType `json:"depend_on"`
+}
+
+// flowPayloadHash 计算待创建任务流内容的 sha256 摘要。摘要基于任务流内容的规范形式计算：任务按照 ActionID 排序，
+// 依赖按照 ActionID 排序，任务参数解析后按照 key 排序重新序列化，保证内容相同的请求摘要一致。
+func flowPayloadHash(flow *model.Flow) (string, error) {
+	payload := flowPayload{
+		Name:           flow.Name,
+		Memo:           flow.Memo,
+		RunAt:          flow.RunAt,
+		RollbackPolicy: flow.RollbackPolicy,
+		Priority:       flow.Priority,
+		Tenant:         flow.Tenant,
+		Webhooks:       flow.Webhooks,
+		Tasks:          make([]taskPayload, 0, len(flow.Tasks)),
+	}
+	if flow.ShareData != nil {
+		payload.ShareData = flow.ShareData.Dict
+	}
+
+	for _, one := range flow.Tasks {
+		params, err := canonicalParams(one.Params)
+		if err != nil {
+			return "", err
+		}
+
+		dependOn := append([]action.ActIDType{}, one.DependOn...)
+		sort.Slice(dependOn, func(i, j int) bool { return dependOn[i] < dependOn[j] })
+
+		payload.Tasks = append(payload.Tasks, taskPayload{
+			ActionID:   one.ActionID,
+			ActionName: one.ActionName,
+			Params:     params,
+			Retry:      one.Retry,
+			DependOn:   dependOn,
+		})
+	}
+	sort.Slice(payload.Tasks, func(i, j int) bool { return payload.Tasks[i].ActionID < payload.Tasks[j].ActionID })
+
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(raw)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// canonicalParams 解析任务参数，重新序列化时按照 key 排序，忽略参数中的空白和字段顺序差异。
+func canonicalParams(params types.JsonField) (interface{}, error) {
+	if params.IsEmpty() {
+		return nil, nil
+	}
+
+	var val interface{}
+	if err := json.UnmarshalFromString(string(params), &val); err != nil {
+		return nil, fmt.Errorf("unmarshal task params failed, err: %v", err)
+	}
+
+	return val, nil
+}
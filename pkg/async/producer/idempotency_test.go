@@ -0,0 +1,95 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package producer
+
+import (
+	"testing"
+
+	"hcm/pkg/async/action"
+	"hcm/pkg/async/backend/model"
+	"hcm/pkg/criteria/enumor"
+	tableasync "hcm/pkg/dal/table/async"
+	"hcm/pkg/dal/table/types"
+)
+
+func TestFlowPayloadHash(t *testing.T) {
+	newFlow := func(tasks ...model.Task) *model.Flow {
+		return &model.Flow{
+			Name:      enumor.FlowSleepTest,
+			ShareData: &tableasync.ShareData{Dict: map[string]string{"b": "2", "a": "1"}},
+			Tasks:     tasks,
+		}
+	}
+	task := func(id action.ActIDType, params string, dependOn ...action.ActIDType) model.Task {
+		return model.Task{ActionID: id, ActionName: enumor.ActionSleep, Params: types.JsonField(params),
+			Retry: new(tableasync.Retry), DependOn: dependOn}
+	}
+
+	base := newFlow(task("1", `{"sleep_sec":1,"memo":"a"}`), task("2", `{"sleep_sec":2}`),
+		task("3", `{"sleep_sec":3}`, "1", "2"))
+
+	cases := []struct {
+		name  string
+		flow  *model.Flow
+		equal bool
+	}{
+		{
+			name: "params key order and blank",
+			flow: newFlow(task("1", `{ "memo": "a", "sleep_sec": 1 }`), task("2", `{"sleep_sec":2}`),
+				task("3", `{"sleep_sec":3}`, "1", "2")),
+			equal: true,
+		},
+		{
+			name: "task and depend order",
+			flow: newFlow(task("3", `{"sleep_sec":3}`, "2", "1"), task("2", `{"sleep_sec":2}`),
+				task("1", `{"sleep_sec":1,"memo":"a"}`)),
+			equal: true,
+		},
+		{
+			name: "params value",
+			flow: newFlow(task("1", `{"sleep_sec":1,"memo":"b"}`), task("2", `{"sleep_sec":2}`),
+				task("3", `{"sleep_sec":3}`, "1", "2")),
+			equal: false,
+		},
+		{
+			name: "depend on",
+			flow: newFlow(task("1", `{"sleep_sec":1,"memo":"a"}`), task("2", `{"sleep_sec":2}`),
+				task("3", `{"sleep_sec":3}`, "1")),
+			equal: false,
+		},
+	}
+
+	expect, err := flowPayloadHash(base)
+	if err != nil {
+		t.Fatalf("calculate base flow hash failed, err: %v", err)
+	}
+
+	for _, c := range cases {
+		got, err := flowPayloadHash(c.flow)
+		if err != nil {
+			t.Errorf("case %s calculate flow hash failed, err: %v", c.name, err)
+			continue
+		}
+
+		if (got == expect) != c.equal {
+			t.Errorf("case %s expect hash equal: %v, got %s, base %s", c.name, c.equal, got, expect)
+		}
+	}
+}
@@ -0,0 +1,33 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package producer
+
+import "hcm/pkg/criteria/validator"
+
+// Option defines producer option.
+type Option struct {
+	// IdempotencyKeyTTLSec 幂等键有效期，有效期内同一应用使用相同幂等键重复创建任务流时返回首次创建的任务流
+	IdempotencyKeyTTLSec uint `json:"idempotency_key_ttl_sec" validate:"required"`
+}
+
+// Validate Option
+func (opt Option) Validate() error {
+	return validator.Validate.Struct(opt)
+}
@@ -22,6 +22,7 @@ package producer
 
 import (
 	"errors"
+	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 
@@ -33,12 +34,13 @@ import (
 type Producer interface {
 	AddTemplateFlow(kt *kit.Kit, opt *AddTemplateFlowOption) (id string, err error)
 	AddCustomFlow(kt *kit.Kit, opt *AddCustomFlowOption) (id string, err error)
+	AddFlowSchedule(kt *kit.Kit, opt *AddFlowScheduleOption) (id string, err error)
 }
 
 var _ Producer = new(producer)
 
 // NewProducer new producer.
-func NewProducer(bd backend.Backend, register prometheus.Registerer) (Producer, error) {
+func NewProducer(bd backend.Backend, register prometheus.Registerer, opt *Option) (Producer, error) {
 	if bd == nil {
 		return nil, errors.New("backend is required")
 	}
@@ -47,9 +49,18 @@ func NewProducer(bd backend.Backend, register prometheus.Registerer) (Producer,
 		return nil, errors.New("metrics register is required")
 	}
 
+	if opt == nil {
+		return nil, errors.New("producer option is required")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return nil, err
+	}
+
 	return &producer{
-		backend: bd,
-		mc:      initMetric(register),
+		backend:           bd,
+		mc:                initMetric(register),
+		idempotencyKeyTTL: time.Duration(opt.IdempotencyKeyTTLSec) * time.Second,
 	}, nil
 }
 
@@ -57,4 +68,6 @@ func NewProducer(bd backend.Backend, register prometheus.Registerer) (Producer,
 type producer struct {
 	backend backend.Backend
 	mc      *metric
+	// idempotencyKeyTTL 幂等键有效期
+	idempotencyKeyTTL time.Duration
 }
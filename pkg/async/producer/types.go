@@ -21,12 +21,16 @@ package producer
 
 import (
 	"errors"
+	"fmt"
+	"time"
 
 	"hcm/pkg/async/action"
+	"hcm/pkg/criteria/constant"
 	"hcm/pkg/criteria/enumor"
 	"hcm/pkg/criteria/validator"
 	tableasync "hcm/pkg/dal/table/async"
 	"hcm/pkg/dal/table/types"
+	"hcm/pkg/tools/cron"
 )
 
 // AddTemplateFlowOption define add flow option.
@@ -35,6 +39,16 @@ type AddTemplateFlowOption struct {
 	Name enumor.FlowName `json:"name" validate:"required"`
 	// Memo 备注
 	Memo string `json:"memo" validate:"omitempty"`
+	// RunAt 任务流最早执行时间，格式为 constant.TimeStdFormat，为空时立即执行
+	RunAt string `json:"run_at" validate:"omitempty"`
+	// RollbackPolicy 任务流失败后的回滚策略，为空时使用任务流模版的回滚策略
+	RollbackPolicy enumor.FlowRollbackPolicy `json:"rollback_policy" validate:"omitempty"`
+	// Priority 任务流优先级，为空时使用任务流模版的优先级
+	Priority enumor.FlowPriority `json:"priority" validate:"omitempty"`
+	// Tenant 任务流所属租户，用于按租户限制并发，建议使用 bk_biz_id 或 app_code，为空时为请求的 app_code
+	Tenant string `json:"tenant" validate:"omitempty,max=64"`
+	// Webhooks 任务流生命周期事件回调地址，任务流创建、开始执行、成功、失败、回滚完成、取消时会回调该地址
+	Webhooks tableasync.WebhookTargets `json:"webhooks" validate:"omitempty,max=5"`
 	// Tasks 任务私有化参数设置
 	Tasks []TemplateFlowTask `json:"tasks" validate:"omitempty"`
 }
@@ -50,6 +64,22 @@ func (opt *AddTemplateFlowOption) Validate() error {
 		return err
 	}
 
+	if err := validateRunAt(opt.RunAt); err != nil {
+		return err
+	}
+
+	if err := validateRollbackPolicy(opt.RollbackPolicy); err != nil {
+		return err
+	}
+
+	if err := validatePriority(opt.Priority); err != nil {
+		return err
+	}
+
+	if err := validateWebhooks(opt.Webhooks); err != nil {
+		return err
+	}
+
 	for index := range opt.Tasks {
 		if err := opt.Tasks[index].Validate(); err != nil {
 			return err
@@ -80,6 +110,16 @@ type AddCustomFlowOption struct {
 	Memo string `json:"memo" validate:"omitempty"`
 	// ShareData 共享数据
 	ShareData *tableasync.ShareData `json:"share_data" validate:"omitempty"`
+	// RunAt 任务流最早执行时间，格式为 constant.TimeStdFormat，为空时立即执行
+	RunAt string `json:"run_at" validate:"omitempty"`
+	// RollbackPolicy 任务流失败后的回滚策略，为空时不回滚
+	RollbackPolicy enumor.FlowRollbackPolicy `json:"rollback_policy" validate:"omitempty"`
+	// Priority 任务流优先级，为空时为 normal
+	Priority enumor.FlowPriority `json:"priority" validate:"omitempty"`
+	// Tenant 任务流所属租户，用于按租户限制并发，建议使用 bk_biz_id 或 app_code，为空时为请求的 app_code
+	Tenant string `json:"tenant" validate:"omitempty,max=64"`
+	// Webhooks 任务流生命周期事件回调地址，任务流创建、开始执行、成功、失败、回滚完成、取消时会回调该地址
+	Webhooks tableasync.WebhookTargets `json:"webhooks" validate:"omitempty,max=5"`
 	// Tasks 任务私有化参数设置
 	Tasks []CustomFlowTask `json:"tasks" validate:"required"`
 }
@@ -95,6 +135,22 @@ func (opt *AddCustomFlowOption) Validate() error {
 		return errors.New("tasks is required")
 	}
 
+	if err := validateRunAt(opt.RunAt); err != nil {
+		return err
+	}
+
+	if err := validateRollbackPolicy(opt.RollbackPolicy); err != nil {
+		return err
+	}
+
+	if err := validatePriority(opt.Priority); err != nil {
+		return err
+	}
+
+	if err := validateWebhooks(opt.Webhooks); err != nil {
+		return err
+	}
+
 	for _, task := range opt.Tasks {
 		if err := task.Validate(); err != nil {
 			return err
@@ -123,10 +179,101 @@ func (task *CustomFlowTask) Validate() error {
 	if err := validator.Validate.Struct(task); err != nil {
 		return err
 	}
-	
+
 	if err := task.ActionName.Validate(); err != nil {
 		return err
 	}
 
 	return nil
 }
+
+// validateRollbackPolicy 校验任务流回滚策略
+func validateRollbackPolicy(policy enumor.FlowRollbackPolicy) error {
+	if len(policy) == 0 {
+		return nil
+	}
+
+	return policy.Validate()
+}
+
+// validatePriority 校验任务流优先级
+func validatePriority(priority enumor.FlowPriority) error {
+	if len(priority) == 0 {
+		return nil
+	}
+
+	return priority.Validate()
+}
+
+// validateWebhooks 校验任务流生命周期事件回调地址
+func validateWebhooks(webhooks tableasync.WebhookTargets) error {
+	for index := range webhooks {
+		if err := webhooks[index].Validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// validateRunAt 校验任务流执行时间格式
+func validateRunAt(runAt string) error {
+	if len(runAt) == 0 {
+		return nil
+	}
+
+	if _, err := time.Parse(constant.TimeStdFormat, runAt); err != nil {
+		return fmt.Errorf("run_at should be %s format, err: %v", constant.TimeStdFormat, err)
+	}
+
+	return nil
+}
+
+// AddFlowScheduleOption define add flow schedule option.
+type AddFlowScheduleOption struct {
+	// Name 定时任务流名称，全局唯一
+	Name string `json:"name" validate:"required,lte=64"`
+	// Cron 5段式 cron 表达式（分 时 日 月 周），按照服务所在时区计算执行时间
+	Cron string `json:"cron" validate:"required,lte=64"`
+	// MissedPolicy 错过执行策略，不设置时跳过错过的执行
+	MissedPolicy enumor.MissedRunPolicy `json:"missed_policy" validate:"omitempty"`
+	// Memo 备注
+	Memo string `json:"memo" validate:"omitempty,lte=255"`
+	// TemplateFlow 按照任务流模版生成任务流，与 CustomFlow 二选一
+	TemplateFlow *AddTemplateFlowOption `json:"template_flow" validate:"omitempty"`
+	// CustomFlow 按照自定义任务流生成任务流，与 TemplateFlow 二选一
+	CustomFlow *AddCustomFlowOption `json:"custom_flow" validate:"omitempty"`
+}
+
+// Validate AddFlowScheduleOption
+func (opt *AddFlowScheduleOption) Validate() error {
+	if err := validator.Validate.Struct(opt); err != nil {
+		return err
+	}
+
+	if _, err := cron.Parse(opt.Cron); err != nil {
+		return err
+	}
+
+	if len(opt.MissedPolicy) != 0 {
+		if err := opt.MissedPolicy.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if (opt.TemplateFlow == nil) == (opt.CustomFlow == nil) {
+		return errors.New("one of template_flow and custom_flow is required")
+	}
+
+	if opt.TemplateFlow != nil {
+		if len(opt.TemplateFlow.RunAt) != 0 {
+			return errors.New("template_flow.run_at can not set, it is decided by cron")
+		}
+		return opt.TemplateFlow.Validate()
+	}
+
+	if len(opt.CustomFlow.RunAt) != 0 {
+		return errors.New("custom_flow.run_at can not set, it is decided by cron")
+	}
+	return opt.CustomFlow.Validate()
+}
@@ -120,6 +120,8 @@ type CloudServerSetting struct {
 	Recycle       Recycle       `yaml:"recycle"`
 	BillConfig    BillConfig    `yaml:"billConfig"`
 	Itsm          ApiGateway    `yaml:"itsm"`
+	// ResChangeHistory 资源变更历史相关配置
+	ResChangeHistory ResChangeHistory `yaml:"resChangeHistory"`
 }
 
 // trySetFlagBindIP try set flag bind ip.
@@ -132,6 +134,7 @@ func (s *CloudServerSetting) trySetDefault() {
 	s.Network.trySetDefault()
 	s.Service.trySetDefault()
 	s.Log.trySetDefault()
+	s.CloudResource.Sync.trySetDefault()
 
 	return
 }
@@ -225,9 +228,11 @@ func (s DataServiceSetting) Validate() error {
 
 // HCServiceSetting defines hc service used setting options.
 type HCServiceSetting struct {
-	Network Network   `yaml:"network"`
-	Service Service   `yaml:"service"`
-	Log     LogOption `yaml:"log"`
+	Network           Network           `yaml:"network"`
+	Service           Service           `yaml:"service"`
+	Log               LogOption         `yaml:"log"`
+	SyncDeletionGuard SyncDeletionGuard `yaml:"syncDeletionGuard"`
+	AuditEventSync    AuditEventSync    `yaml:"auditEventSync"`
 }
 
 // trySetFlagBindIP try set flag bind ip.
@@ -240,6 +245,8 @@ func (s *HCServiceSetting) trySetDefault() {
 	s.Network.trySetDefault()
 	s.Service.trySetDefault()
 	s.Log.trySetDefault()
+	s.AuditEventSync.trySetDefault()
+	s.SyncDeletionGuard.trySetDefault()
 
 	return
 }
@@ -255,6 +262,14 @@ func (s HCServiceSetting) Validate() error {
 		return err
 	}
 
+	if err := s.SyncDeletionGuard.validate(); err != nil {
+		return err
+	}
+
+	if err := s.AuditEventSync.validate(); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -373,6 +388,7 @@ func (s *TaskServerSetting) trySetDefault() {
 	s.Service.trySetDefault()
 	s.Database.trySetDefault()
 	s.Log.trySetDefault()
+	s.Async.trySetDefault()
 
 	return
 }
@@ -392,5 +408,9 @@ func (s TaskServerSetting) Validate() error {
 		return err
 	}
 
+	if err := s.Async.Validate(); err != nil {
+		return err
+	}
+
 	return nil
 }
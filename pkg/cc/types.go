@@ -27,6 +27,7 @@ import (
 	"os"
 	"time"
 
+	"hcm/pkg/criteria/enumor"
 	"hcm/pkg/logs"
 	"hcm/pkg/tools/ssl"
 	"hcm/pkg/version"
@@ -158,18 +159,92 @@ func (lm *Limiter) trySetDefault() {
 
 // Async defines async relating.
 type Async struct {
-	Scheduler  Parser     `yaml:"scheduler"`
-	Executor   Executor   `yaml:"executor"`
-	Dispatcher Dispatcher `yaml:"dispatcher"`
-	WatchDog   WatchDog   `yaml:"watchDog"`
+	Backend    AsyncBackend `yaml:"backend"`
+	Producer   Producer     `yaml:"producer"`
+	Scheduler  Parser       `yaml:"scheduler"`
+	Executor   Executor     `yaml:"executor"`
+	Dispatcher Dispatcher   `yaml:"dispatcher"`
+	WatchDog   WatchDog     `yaml:"watchDog"`
+	Timer      Timer        `yaml:"timer"`
+	Notifier   Notifier     `yaml:"notifier"`
+	Archiver   Archiver     `yaml:"archiver"`
+	// IdempotencyCleaner 幂等键清理器配置
+	IdempotencyCleaner IdempotencyCleaner `yaml:"idempotencyCleaner"`
+	// Compensator 补偿器配置
+	Compensator Compensator `yaml:"compensator"`
+}
+
+// trySetDefault set the Async default value if user not configured.
+func (a *Async) trySetDefault() {
+	if a.Executor.HeartbeatIntervalSec == 0 {
+		a.Executor.HeartbeatIntervalSec = 10
+	}
+
+	if a.WatchDog.HeartbeatTimeoutSec == 0 {
+		a.WatchDog.HeartbeatTimeoutSec = 60
+	}
+
+	if a.Producer.IdempotencyKeyTTLSec == 0 {
+		a.Producer.IdempotencyKeyTTLSec = 86400
+	}
+
+	a.Backend.trySetDefault()
+	a.Notifier.trySetDefault()
+	a.Archiver.trySetDefault()
+	a.IdempotencyCleaner.trySetDefault()
+	a.Compensator.trySetDefault()
 }
 
 // Validate Async
 func (a Async) Validate() error {
-	// 这里不进行校验，统一由异步任务框架进行校验
+	if err := a.Backend.Validate(); err != nil {
+		return err
+	}
+
+	// 其余配置这里不进行校验，统一由异步任务框架进行校验
+	return nil
+}
+
+// AsyncBackend 异步任务框架使用的存储后端
+type AsyncBackend struct {
+	// Type 存储后端类型，可选值：mysql、sqlite、memory，默认为 mysql
+	Type enumor.BackendType `yaml:"type"`
+	// Sqlite sqlite 存储后端配置，仅 Type 为 sqlite 时生效
+	Sqlite SqliteBackend `yaml:"sqlite"`
+}
+
+// SqliteBackend sqlite 存储后端配置
+type SqliteBackend struct {
+	// Path sqlite 数据库文件路径
+	Path string `yaml:"path"`
+}
+
+// trySetDefault set the AsyncBackend default value if user not configured.
+func (b *AsyncBackend) trySetDefault() {
+	if len(b.Type) == 0 {
+		b.Type = enumor.BackendMysql
+	}
+}
+
+// Validate AsyncBackend
+func (b AsyncBackend) Validate() error {
+	if err := b.Type.Validate(); err != nil {
+		return err
+	}
+
+	if b.Type == enumor.BackendSqlite && len(b.Sqlite.Path) == 0 {
+		return errors.New("async sqlite backend path is not set")
+	}
+
 	return nil
 }
 
+// Producer 公共组件，负责创建任务流
+type Producer struct {
+	// IdempotencyKeyTTLSec 创建任务流使用的幂等键有效期，有效期内同一应用使用相同幂等键重复创建任务流时返回首次创建的任务流
+	IdempotencyKeyTTLSec uint `yaml:"idempotencyKeyTTLSec"`
+}
+
 // Parser 公共组件，负责获取分配给当前节点的任务流，并解析成任务树后，派发当前要执行的任务给executor执行
 type Parser struct {
 	WatchIntervalSec uint `yaml:"watchIntervalSec"`
@@ -180,17 +255,133 @@ type Parser struct {
 type Executor struct {
 	WorkerNumber       uint `yaml:"workerNumber"`
 	TaskExecTimeoutSec uint `yaml:"taskExecTimeoutSec"`
+	// HeartbeatIntervalSec 任务执行期间执行器自动上报任务心跳的周期
+	HeartbeatIntervalSec uint `yaml:"heartbeatIntervalSec"`
 }
 
 // Dispatcher 主节点组件，负责派发任务
 type Dispatcher struct {
 	WatchIntervalSec uint `yaml:"watchIntervalSec"`
+	// Queues 按任务流优先级划分的派发队列配置，未配置的优先级不限制并发
+	Queues []DispatchQueue `yaml:"queues"`
+}
+
+// DispatchQueue 派发队列配置，并发数为0表示不限制
+type DispatchQueue struct {
+	// Priority 队列对应的任务流优先级，可选值：high、normal、low
+	Priority string `yaml:"priority"`
+	// MaxConcurrency 该队列同时处于调度中和执行中的任务流最大数量
+	MaxConcurrency uint `yaml:"maxConcurrency"`
+	// TenantConcurrency 该队列中单个租户（业务ID或应用编码）同时处于调度中和执行中的任务流最大数量
+	TenantConcurrency uint `yaml:"tenantConcurrency"`
 }
 
 // WatchDog 主节点组件，负责异常任务修正（超时任务，任务处理节点已经挂掉的任务等）
 type WatchDog struct {
 	WatchIntervalSec uint `yaml:"watchIntervalSec"`
-	TaskTimeoutSec   uint `yaml:"taskTimeoutSec"`
+	// HeartbeatTimeoutSec 执行中的任务超过该时间未上报心跳，认为任务执行节点已经异常
+	HeartbeatTimeoutSec uint `yaml:"heartbeatTimeoutSec"`
+}
+
+// Timer 主节点组件，负责按照定时任务流的 cron 表达式生成任务流
+type Timer struct {
+	WatchIntervalSec uint `yaml:"watchIntervalSec"`
+}
+
+// Notifier 主节点组件，负责将任务流生命周期事件投递到订阅的 webhook
+type Notifier struct {
+	WatchIntervalSec uint `yaml:"watchIntervalSec"`
+	// TimeoutSec 单次回调请求的超时时间
+	TimeoutSec uint `yaml:"timeoutSec"`
+	// MaxAttempts 单个事件最大投递次数，超过后事件置为投递失败
+	MaxAttempts uint `yaml:"maxAttempts"`
+	// BackoffBaseSec 投递失败后的重试间隔基数，第n次重试间隔为 BackoffBaseSec * 2^(n-1)
+	BackoffBaseSec uint `yaml:"backoffBaseSec"`
+	// Secret 回调请求签名默认使用的密钥，订阅未配置密钥时使用
+	Secret string `yaml:"secret"`
+	// Subscriptions 按应用编码订阅的 webhook，应用创建的任务流事件会投递到这些地址
+	Subscriptions []WebhookSubscription `yaml:"subscriptions"`
+}
+
+// trySetDefault set the Notifier default value if user not configured.
+func (n *Notifier) trySetDefault() {
+	if n.WatchIntervalSec == 0 {
+		n.WatchIntervalSec = 5
+	}
+
+	if n.TimeoutSec == 0 {
+		n.TimeoutSec = 10
+	}
+
+	if n.MaxAttempts == 0 {
+		n.MaxAttempts = 5
+	}
+
+	if n.BackoffBaseSec == 0 {
+		n.BackoffBaseSec = 10
+	}
+}
+
+// Archiver 主节点组件，负责将已结束且超过保留时间的任务流连同任务、任务流事件归档后删除
+type Archiver struct {
+	WatchIntervalSec uint `yaml:"watchIntervalSec"`
+	// RetentionDays 已结束（成功、失败、取消、已回滚）的任务流保留天数，超过后归档，为0时不归档
+	RetentionDays uint `yaml:"retentionDays"`
+	// BatchSize 单批次归档的任务流数量，最大为100
+	BatchSize uint `yaml:"batchSize"`
+}
+
+// trySetDefault set the Archiver default value if user not configured.
+func (a *Archiver) trySetDefault() {
+	if a.WatchIntervalSec == 0 {
+		a.WatchIntervalSec = 60
+	}
+
+	if a.BatchSize == 0 {
+		a.BatchSize = 100
+	}
+}
+
+// IdempotencyCleaner 主节点组件，负责删除创建任务流时使用的已过期幂等键
+type IdempotencyCleaner struct {
+	WatchIntervalSec uint `yaml:"watchIntervalSec"`
+}
+
+// trySetDefault set the IdempotencyCleaner default value if user not configured.
+func (c *IdempotencyCleaner) trySetDefault() {
+	if c.WatchIntervalSec == 0 {
+		c.WatchIntervalSec = 600
+	}
+}
+
+// Compensator 主节点组件，负责补偿回滚策略为 saga 的失败任务流中执行成功的任务
+type Compensator struct {
+	WatchIntervalSec uint `yaml:"watchIntervalSec"`
+	// WorkerNumber 同时补偿的任务流数量
+	WorkerNumber uint `yaml:"workerNumber"`
+}
+
+// trySetDefault set the Compensator default value if user not configured.
+func (c *Compensator) trySetDefault() {
+	if c.WatchIntervalSec == 0 {
+		c.WatchIntervalSec = 5
+	}
+
+	if c.WorkerNumber == 0 {
+		c.WorkerNumber = 10
+	}
+}
+
+// WebhookSubscription 应用订阅的 webhook
+type WebhookSubscription struct {
+	// AppCode 订阅事件的应用编码
+	AppCode string `yaml:"appCode"`
+	// URL 回调地址
+	URL string `yaml:"url"`
+	// Secret 回调请求签名使用的密钥，为空时使用 Notifier 的默认密钥
+	Secret string `yaml:"secret"`
+	// Events 订阅的事件类型，可选值：created、running、success、failed、cancelled，为空时订阅全部事件
+	Events []string `yaml:"events"`
 }
 
 // DataBase defines database related runtime
@@ -600,7 +791,8 @@ func (c Crypto) validate() error {
 
 // CloudResource 云资源配置
 type CloudResource struct {
-	Sync CloudResourceSync `yaml:"sync"`
+	Sync            CloudResourceSync            `yaml:"sync"`
+	IncrementalSync CloudResourceIncrementalSync `yaml:"incrementalSync"`
 }
 
 func (c CloudResource) validate() error {
@@ -608,6 +800,10 @@ func (c CloudResource) validate() error {
 		return err
 	}
 
+	if err := c.IncrementalSync.validate(); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -616,6 +812,23 @@ type CloudResourceSync struct {
 	Enable                       bool   `yaml:"enable"`
 	SyncIntervalMin              uint64 `yaml:"syncIntervalMin"`
 	SyncFrequencyLimitingTimeMin uint64 `yaml:"syncFrequencyLimitingTimeMin"`
+	// RunAsFlow 是否以异步任务流的方式在 task-server 中执行账号同步，每个地域的每类资源一个任务，失败的任务单独重试，
+	// 同步进度可以通过 task-server 的任务流和任务查看。
+	RunAsFlow bool `yaml:"runAsFlow"`
+	// Scheduler 同步调度配置，开启后按账号调度同步，替代按固定间隔依次同步全部账号。
+	Scheduler SyncScheduler `yaml:"scheduler"`
+	// SelectiveSyncLimitingTimeSec 同一账号两次定向同步之间的最小间隔，单位：秒，与全量同步的频率限制相互独立。
+	SelectiveSyncLimitingTimeSec uint64 `yaml:"selectiveSyncLimitingTimeSec"`
+	// HistoryRetentionDays 账号资源同步历史保留天数，过期的同步历史会被定期清理，为 0 时不清理。
+	HistoryRetentionDays uint `yaml:"historyRetentionDays"`
+}
+
+func (c *CloudResourceSync) trySetDefault() {
+	if c.SelectiveSyncLimitingTimeSec == 0 {
+		c.SelectiveSyncLimitingTimeSec = 60
+	}
+
+	c.Scheduler.trySetDefault()
 }
 
 func (c CloudResourceSync) validate() error {
@@ -625,6 +838,189 @@ func (c CloudResourceSync) validate() error {
 		}
 	}
 
+	if err := c.Scheduler.validate(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// SyncScheduler 云资源同步调度配置。
+type SyncScheduler struct {
+	Enable bool `yaml:"enable"`
+	// ConcurrencyPerVendor 每个云厂商同时同步的账号数量上限
+	ConcurrencyPerVendor uint `yaml:"concurrencyPerVendor"`
+	// DefaultIntervalMin 账号默认同步间隔，单位：分钟
+	DefaultIntervalMin uint64 `yaml:"defaultIntervalMin"`
+	// BackoffBaseMin 账号同步失败后的退避基数，第n次连续失败后等待 BackoffBaseMin*2^(n-1) 分钟再重试
+	BackoffBaseMin uint64 `yaml:"backoffBaseMin"`
+	// BackoffMaxMin 账号同步失败后的最大退避时间，单位：分钟
+	BackoffMaxMin uint64 `yaml:"backoffMaxMin"`
+	// ResTypeIntervalMin 各类资源的同步间隔，单位：分钟，未配置的资源跟随账号同步间隔。
+	// 如 image: 1440 表示镜像等公共资源每天同步一次。
+	ResTypeIntervalMin map[string]uint64 `yaml:"resTypeIntervalMin"`
+	// Accounts 账号级别的调度配置，覆盖默认同步间隔和优先级
+	Accounts []AccountSyncSchedule `yaml:"accounts"`
+}
+
+// AccountSyncSchedule 账号同步调度配置。
+type AccountSyncSchedule struct {
+	AccountID string `yaml:"accountID"`
+	// IntervalMin 账号同步间隔，为0时使用默认同步间隔，单位：分钟
+	IntervalMin uint64 `yaml:"intervalMin"`
+	// Priority 账号同步优先级，数值越大越优先同步，默认为0
+	Priority int `yaml:"priority"`
+}
+
+func (s *SyncScheduler) trySetDefault() {
+	if s.ConcurrencyPerVendor == 0 {
+		s.ConcurrencyPerVendor = 1
+	}
+
+	if s.DefaultIntervalMin == 0 {
+		s.DefaultIntervalMin = 360
+	}
+
+	if s.BackoffBaseMin == 0 {
+		s.BackoffBaseMin = 10
+	}
+
+	if s.BackoffMaxMin == 0 {
+		s.BackoffMaxMin = 720
+	}
+}
+
+func (s SyncScheduler) validate() error {
+	if !s.Enable {
+		return nil
+	}
+
+	if s.BackoffMaxMin < s.BackoffBaseMin {
+		return errors.New("scheduler.backoffMaxMin must >= backoffBaseMin")
+	}
+
+	for resType, interval := range s.ResTypeIntervalMin {
+		if interval == 0 {
+			return fmt.Errorf("scheduler.resTypeIntervalMin of %s must > 0", resType)
+		}
+	}
+
+	accountIDs := make(map[string]struct{}, len(s.Accounts))
+	for _, one := range s.Accounts {
+		if len(one.AccountID) == 0 {
+			return errors.New("scheduler.accounts.accountID is required")
+		}
+
+		if _, exist := accountIDs[one.AccountID]; exist {
+			return fmt.Errorf("scheduler.accounts.accountID %s is duplicated", one.AccountID)
+		}
+		accountIDs[one.AccountID] = struct{}{}
+	}
+
+	return nil
+}
+
+// CloudResourceIncrementalSync 基于云上审计事件的增量同步配置，定时拉取账号下的变更事件，仅同步发生变更的资源。
+type CloudResourceIncrementalSync struct {
+	Enable bool `yaml:"enable"`
+	// IntervalMin 增量同步间隔，单位：分钟
+	IntervalMin uint64 `yaml:"intervalMin"`
+	// DelayMin 云上审计事件的投递延迟，每次只拉取截止到当前时间减去该延迟之前的事件，单位：分钟
+	DelayMin uint64 `yaml:"delayMin"`
+}
+
+func (c CloudResourceIncrementalSync) validate() error {
+	if c.Enable && c.IntervalMin == 0 {
+		return errors.New("incrementalSync.intervalMin must > 0")
+	}
+
+	return nil
+}
+
+// SyncDeletionGuard 资源同步删除保护配置，单次同步删除的资源数量超过阈值时，待删除的资源会被隔离，
+// 需要再次同步确认（RequireApproval 为 false 时）或人工审批后才会删除。
+type SyncDeletionGuard struct {
+	Enable bool `yaml:"enable"`
+	// MaxDeleteCount 单次同步账号下某个地域某类资源允许直接删除的最大数量，为0时不限制。
+	MaxDeleteCount uint64 `yaml:"maxDeleteCount"`
+	// MaxDeletePercent 单次同步账号下某个地域某类资源允许直接删除的最大百分比，为0时不限制。
+	MaxDeletePercent uint64 `yaml:"maxDeletePercent"`
+	// RequireApproval 被隔离的资源是否必须人工审批后才能删除。
+	RequireApproval bool `yaml:"requireApproval"`
+	// ConfirmWindowHours 删除确认的有效期，隔离或审批超过该时间后，再次同步不再视为确认删除，需要重新判断是否隔离，
+	// 单位：小时，默认24小时。
+	ConfirmWindowHours uint64 `yaml:"confirmWindowHours"`
+}
+
+func (g *SyncDeletionGuard) trySetDefault() {
+	if g.ConfirmWindowHours == 0 {
+		g.ConfirmWindowHours = 24
+	}
+}
+
+func (g SyncDeletionGuard) validate() error {
+	if !g.Enable {
+		return nil
+	}
+
+	if g.MaxDeletePercent > 100 {
+		return errors.New("syncDeletionGuard.maxDeletePercent must <= 100")
+	}
+
+	if g.MaxDeleteCount == 0 && g.MaxDeletePercent == 0 {
+		return errors.New("syncDeletionGuard.maxDeleteCount or maxDeletePercent is required when enabled")
+	}
+
+	return nil
+}
+
+// AuditEventSourceType is the source type of cloud audit events.
+type AuditEventSourceType string
+
+const (
+	// CloudAuditEventSource 通过云厂商审计服务拉取审计事件
+	CloudAuditEventSource AuditEventSourceType = "cloud"
+	// FileAuditEventSource 从本地文件读取审计事件，每行一个 JSON 格式的事件，用于本地调试和测试
+	FileAuditEventSource AuditEventSourceType = "file"
+	// HttpAuditEventSource 从HTTP服务查询审计事件，用于替代云上审计服务进行测试
+	HttpAuditEventSource AuditEventSourceType = "http"
+)
+
+// AuditEventSync 基于云上审计事件的增量同步配置。
+type AuditEventSync struct {
+	Source AuditEventSource `yaml:"source"`
+}
+
+// AuditEventSource 审计事件来源配置。
+type AuditEventSource struct {
+	Type AuditEventSourceType `yaml:"type"`
+	// FilePath file 类型的事件文件路径
+	FilePath string `yaml:"filePath"`
+	// URL http 类型的事件查询地址
+	URL string `yaml:"url"`
+}
+
+func (s *AuditEventSync) trySetDefault() {
+	if len(s.Source.Type) == 0 {
+		s.Source.Type = CloudAuditEventSource
+	}
+}
+
+func (s AuditEventSync) validate() error {
+	switch s.Source.Type {
+	case CloudAuditEventSource:
+	case FileAuditEventSource:
+		if len(s.Source.FilePath) == 0 {
+			return errors.New("auditEventSync.source.filePath is required when source type is file")
+		}
+	case HttpAuditEventSource:
+		if len(s.Source.URL) == 0 {
+			return errors.New("auditEventSync.source.url is required when source type is http")
+		}
+	default:
+		return fmt.Errorf("unsupported auditEventSync.source.type: %s", s.Source.Type)
+	}
+
 	return nil
 }
 
@@ -641,6 +1037,12 @@ func (a Recycle) validate() error {
 	return nil
 }
 
+// ResChangeHistory resource change history configuration.
+type ResChangeHistory struct {
+	// RetentionDays 变更历史保留天数，过期的历史版本会被定期清理，为 0 时不清理。
+	RetentionDays uint `yaml:"retentionDays"`
+}
+
 // BillConfig 账号账单配置
 type BillConfig struct {
 	Enable          bool   `yaml:"enable"`
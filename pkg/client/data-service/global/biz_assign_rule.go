@@ -0,0 +1,83 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package global
+
+import (
+	"hcm/pkg/api/core"
+	dataservice "hcm/pkg/api/data-service"
+	protocloud "hcm/pkg/api/data-service/cloud"
+	"hcm/pkg/client/common"
+	"hcm/pkg/kit"
+	"hcm/pkg/rest"
+)
+
+// BizAssignRuleClient is data service biz assign rule api client.
+type BizAssignRuleClient struct {
+	client rest.ClientInterface
+}
+
+// NewBizAssignRuleClient create a new biz assign rule api client.
+func NewBizAssignRuleClient(client rest.ClientInterface) *BizAssignRuleClient {
+	return &BizAssignRuleClient{
+		client: client,
+	}
+}
+
+// BatchCreate biz assign rule.
+func (b *BizAssignRuleClient) BatchCreate(kt *kit.Kit, req *protocloud.BizAssignRuleBatchCreateReq) (
+	*core.BatchCreateResult, error) {
+
+	return common.Request[protocloud.BizAssignRuleBatchCreateReq, core.BatchCreateResult](b.client, rest.POST, kt,
+		req, "/cloud/biz_assign_rules/batch/create")
+}
+
+// Update biz assign rule.
+func (b *BizAssignRuleClient) Update(kt *kit.Kit, id string, req *protocloud.BizAssignRuleUpdateReq) error {
+	return common.RequestNoResp[protocloud.BizAssignRuleUpdateReq](b.client, rest.PATCH, kt, req,
+		"/cloud/biz_assign_rules/%s", id)
+}
+
+// List biz assign rule.
+func (b *BizAssignRuleClient) List(kt *kit.Kit, req *core.ListReq) (*protocloud.BizAssignRuleListResult, error) {
+	return common.Request[core.ListReq, protocloud.BizAssignRuleListResult](b.client, rest.POST, kt, req,
+		"/cloud/biz_assign_rules/list")
+}
+
+// BatchDelete biz assign rule.
+func (b *BizAssignRuleClient) BatchDelete(kt *kit.Kit, req *dataservice.BatchDeleteReq) error {
+	return common.RequestNoResp[dataservice.BatchDeleteReq](b.client, rest.DELETE, kt, req,
+		"/cloud/biz_assign_rules/batch")
+}
+
+// Evaluate biz assign rules, the matched unassigned resources will be assigned to biz if not dry run.
+func (b *BizAssignRuleClient) Evaluate(kt *kit.Kit, req *protocloud.BizAssignRuleEvaluateReq) (
+	*protocloud.BizAssignRuleEvaluateResult, error) {
+
+	return common.Request[protocloud.BizAssignRuleEvaluateReq, protocloud.BizAssignRuleEvaluateResult](b.client,
+		rest.POST, kt, req, "/cloud/biz_assign_rules/evaluate")
+}
+
+// ListRecord list biz assign record.
+func (b *BizAssignRuleClient) ListRecord(kt *kit.Kit, req *core.ListReq) (*protocloud.BizAssignRecordListResult,
+	error) {
+
+	return common.Request[core.ListReq, protocloud.BizAssignRecordListResult](b.client, rest.POST, kt, req,
+		"/cloud/biz_assign_records/list")
+}
@@ -37,10 +37,18 @@ type Client struct {
 	Cvm                    *CvmClient
 	RouteTable             *RouteTableClient
 	SGCvmRel               *SGCvmRelClient
+	SGCommonRel            *SGCommonRelClient
 	NetworkInterface       *NetworkInterfaceClient
 	NetworkInterfaceCvmRel *NetworkInterfaceCvmRelClient
 	SubAccount             *SubAccountClient
 	AccountSyncDetail      *AccountSyncDetailClient
+	AccountSyncHistory     *AccountSyncHistoryClient
+	AccountSyncQuarantine  *AccountSyncQuarantineClient
+	ResourceTag            *ResourceTagClient
+	BizAssignRule          *BizAssignRuleClient
+	UserDataTemplate       *UserDataTemplateClient
+	LaunchTemplate         *LaunchTemplateClient
+	ResChangeHistory       *ResChangeHistoryClient
 
 	Auth          *AuthClient
 	Account       *AccountClient
@@ -72,10 +80,18 @@ func NewClient(client rest.ClientInterface) *Client {
 		Cvm:                    NewCloudCvmClient(client),
 		RouteTable:             NewRouteTableClient(client),
 		SGCvmRel:               NewCloudSGCvmRelClient(client),
+		SGCommonRel:            NewCloudSGCommonRelClient(client),
 		NetworkInterface:       NewNetworkInterfaceClient(client),
 		NetworkInterfaceCvmRel: NewNetworkInterfaceCvmRelClient(client),
 		SubAccount:             NewSubAccountClient(client),
 		AccountSyncDetail:      NewAccountSyncDetailClient(client),
+		AccountSyncHistory:     NewAccountSyncHistoryClient(client),
+		AccountSyncQuarantine:  NewAccountSyncQuarantineClient(client),
+		ResourceTag:            NewResourceTagClient(client),
+		BizAssignRule:          NewBizAssignRuleClient(client),
+		UserDataTemplate:       NewUserDataTemplateClient(client),
+		LaunchTemplate:         NewLaunchTemplateClient(client),
+		ResChangeHistory:       NewResChangeHistoryClient(client),
 
 		Auth:          NewAuthClient(client),
 		Account:       NewAccountClient(client),
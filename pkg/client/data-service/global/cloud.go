@@ -92,6 +92,82 @@ func (cli *CloudClient) ListResBasicInfo(kt *kit.Kit, req protocloud.ListResourc
 	return resp.Data, nil
 }
 
+// CountResource count cloud resource by filter.
+func (cli *CloudClient) CountResource(kt *kit.Kit, req *protocloud.CountResourceReq) (
+	*protocloud.CountResourceResult, error) {
+
+	resp := &struct {
+		rest.BaseResp `json:",inline"`
+		Data          *protocloud.CountResourceResult `json:"data"`
+	}{}
+
+	err := cli.client.Post().
+		WithContext(kt.Ctx).
+		Body(req).
+		SubResourcef("/cloud/resources/count").
+		WithHeaders(kt.Header()).
+		Do().
+		Into(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.Code != errf.OK {
+		return nil, errf.New(resp.Code, resp.Message)
+	}
+
+	return resp.Data, nil
+}
+
+// UpdateResourceSyncStatus update cloud resource sync status.
+func (cli *CloudClient) UpdateResourceSyncStatus(kt *kit.Kit, req *protocloud.UpdateResourceSyncStatusReq) error {
+	resp := new(rest.BaseResp)
+
+	err := cli.client.Patch().
+		WithContext(kt.Ctx).
+		Body(req).
+		SubResourcef("/cloud/resources/sync_status/update").
+		WithHeaders(kt.Header()).
+		Do().
+		Into(resp)
+	if err != nil {
+		return err
+	}
+
+	if resp.Code != errf.OK {
+		return errf.New(resp.Code, resp.Message)
+	}
+
+	return nil
+}
+
+// ListResBasicInfoByFilter list cloud resource basic info by filter.
+func (cli *CloudClient) ListResBasicInfoByFilter(kt *kit.Kit, req *protocloud.ListResourceBasicInfoByFilterReq) (
+	*protocloud.ListResourceBasicInfoByFilterResult, error) {
+
+	resp := &struct {
+		rest.BaseResp `json:",inline"`
+		Data          *protocloud.ListResourceBasicInfoByFilterResult `json:"data"`
+	}{}
+
+	err := cli.client.Post().
+		WithContext(kt.Ctx).
+		Body(req).
+		SubResourcef("/cloud/resources/basics/list_by_filter").
+		WithHeaders(kt.Header()).
+		Do().
+		Into(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.Code != errf.OK {
+		return nil, errf.New(resp.Code, resp.Message)
+	}
+
+	return resp.Data, nil
+}
+
 // BatchListResBasicInfo batch list cloud resource basic info.
 func (cli *CloudClient) BatchListResBasicInfo(kt *kit.Kit, req *protocloud.BatchListResourceBasicInfoReq) (
 	map[string]types.CloudResourceBasicInfo, error) {
@@ -0,0 +1,76 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+package global
+
+import (
+	"hcm/pkg/api/core"
+	dataservice "hcm/pkg/api/data-service"
+	protocloud "hcm/pkg/api/data-service/cloud"
+	"hcm/pkg/client/common"
+	"hcm/pkg/kit"
+	"hcm/pkg/rest"
+)
+
+// LaunchTemplateClient is data service launch template api client.
+type LaunchTemplateClient struct {
+	client rest.ClientInterface
+}
+
+// NewLaunchTemplateClient create a new launch template api client.
+func NewLaunchTemplateClient(client rest.ClientInterface) *LaunchTemplateClient {
+	return &LaunchTemplateClient{
+		client: client,
+	}
+}
+
+// Create launch template.
+func (u *LaunchTemplateClient) Create(kt *kit.Kit, req *protocloud.LaunchTemplateCreateReq) (
+	*core.CreateResult, error) {
+
+	return common.Request[protocloud.LaunchTemplateCreateReq, core.CreateResult](u.client, rest.POST, kt, req,
+		"/cloud/launch_templates/create")
+}
+
+// Update launch template, a new version will be created if spec is changed.
+func (u *LaunchTemplateClient) Update(kt *kit.Kit, id string, req *protocloud.LaunchTemplateUpdateReq) error {
+	return common.RequestNoResp[protocloud.LaunchTemplateUpdateReq](u.client, rest.PATCH, kt, req,
+		"/cloud/launch_templates/%s", id)
+}
+
+// List launch template.
+func (u *LaunchTemplateClient) List(kt *kit.Kit, req *core.ListReq) (*protocloud.LaunchTemplateListResult,
+	error) {
+
+	return common.Request[core.ListReq, protocloud.LaunchTemplateListResult](u.client, rest.POST, kt, req,
+		"/cloud/launch_templates/list")
+}
+
+// BatchDelete launch template.
+func (u *LaunchTemplateClient) BatchDelete(kt *kit.Kit, req *dataservice.BatchDeleteReq) error {
+	return common.RequestNoResp[dataservice.BatchDeleteReq](u.client, rest.DELETE, kt, req,
+		"/cloud/launch_templates/batch")
+}
+
+// ListVersion list launch template version.
+func (u *LaunchTemplateClient) ListVersion(kt *kit.Kit, req *core.ListReq) (
+	*protocloud.LaunchTemplateVersionListResult, error) {
+
+	return common.Request[core.ListReq, protocloud.LaunchTemplateVersionListResult](u.client, rest.POST, kt, req,
+		"/cloud/launch_templates/versions/list")
+}
@@ -0,0 +1,64 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+package global
+
+import (
+	"hcm/pkg/api/core"
+	protocloud "hcm/pkg/api/data-service/cloud"
+	"hcm/pkg/client/common"
+	"hcm/pkg/kit"
+	"hcm/pkg/rest"
+)
+
+// ResChangeHistoryClient is data service resource change history api client.
+type ResChangeHistoryClient struct {
+	client rest.ClientInterface
+}
+
+// NewResChangeHistoryClient create a new resource change history api client.
+func NewResChangeHistoryClient(client rest.ClientInterface) *ResChangeHistoryClient {
+	return &ResChangeHistoryClient{
+		client: client,
+	}
+}
+
+// List resource change history.
+func (r *ResChangeHistoryClient) List(kt *kit.Kit, req *core.ListReq) (*protocloud.ResChangeHistoryListResult,
+	error) {
+
+	return common.Request[core.ListReq, protocloud.ResChangeHistoryListResult](r.client, rest.POST, kt, req,
+		"/cloud/res_change_histories/list")
+}
+
+// ListAsOf list the snapshot of resources as of a timestamp.
+func (r *ResChangeHistoryClient) ListAsOf(kt *kit.Kit, req *protocloud.ResChangeHistoryAsOfListReq) (
+	*protocloud.ResChangeHistoryListResult, error) {
+
+	return common.Request[protocloud.ResChangeHistoryAsOfListReq, protocloud.ResChangeHistoryListResult](r.client,
+		rest.POST, kt, req, "/cloud/res_change_histories/as_of/list")
+}
+
+// DeleteExpired delete expired resource change history.
+func (r *ResChangeHistoryClient) DeleteExpired(kt *kit.Kit, req *protocloud.ResChangeHistoryDeleteExpiredReq) (
+	*protocloud.ResChangeHistoryDeleteExpiredResult, error) {
+
+	return common.Request[protocloud.ResChangeHistoryDeleteExpiredReq,
+		protocloud.ResChangeHistoryDeleteExpiredResult](r.client, rest.DELETE, kt, req,
+		"/cloud/res_change_histories/expired")
+}
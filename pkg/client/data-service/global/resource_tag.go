@@ -0,0 +1,64 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package global
+
+import (
+	"hcm/pkg/api/core"
+	protocloud "hcm/pkg/api/data-service/cloud"
+	"hcm/pkg/client/common"
+	"hcm/pkg/kit"
+	"hcm/pkg/rest"
+)
+
+// ResourceTagClient is data service resource tag api client.
+type ResourceTagClient struct {
+	client rest.ClientInterface
+}
+
+// NewResourceTagClient create a new resource tag api client.
+func NewResourceTagClient(client rest.ClientInterface) *ResourceTagClient {
+	return &ResourceTagClient{
+		client: client,
+	}
+}
+
+// List resource tag.
+func (r *ResourceTagClient) List(kt *kit.Kit, req *core.ListReq) (*protocloud.ResourceTagListResult, error) {
+	return common.Request[core.ListReq, protocloud.ResourceTagListResult](r.client, rest.POST, kt, req,
+		"/cloud/resource_tags/list")
+}
+
+// Sync resource tag by cloud id, all tags of the given resources will be replaced.
+func (r *ResourceTagClient) Sync(kt *kit.Kit, req *protocloud.ResourceTagSyncReq) error {
+	return common.RequestNoResp[protocloud.ResourceTagSyncReq](r.client, rest.POST, kt, req,
+		"/cloud/resource_tags/sync")
+}
+
+// BatchUpsert resource tag.
+func (r *ResourceTagClient) BatchUpsert(kt *kit.Kit, req *protocloud.ResourceTagBatchUpsertReq) error {
+	return common.RequestNoResp[protocloud.ResourceTagBatchUpsertReq](r.client, rest.POST, kt, req,
+		"/cloud/resource_tags/batch/upsert")
+}
+
+// BatchDelete resource tag.
+func (r *ResourceTagClient) BatchDelete(kt *kit.Kit, req *protocloud.ResourceTagBatchDeleteReq) error {
+	return common.RequestNoResp[protocloud.ResourceTagBatchDeleteReq](r.client, rest.DELETE, kt, req,
+		"/cloud/resource_tags/batch")
+}
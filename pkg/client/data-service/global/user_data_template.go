@@ -0,0 +1,85 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package global
+
+import (
+	"hcm/pkg/api/core"
+	dataservice "hcm/pkg/api/data-service"
+	protocloud "hcm/pkg/api/data-service/cloud"
+	"hcm/pkg/client/common"
+	"hcm/pkg/kit"
+	"hcm/pkg/rest"
+)
+
+// UserDataTemplateClient is data service user data template api client.
+type UserDataTemplateClient struct {
+	client rest.ClientInterface
+}
+
+// NewUserDataTemplateClient create a new user data template api client.
+func NewUserDataTemplateClient(client rest.ClientInterface) *UserDataTemplateClient {
+	return &UserDataTemplateClient{
+		client: client,
+	}
+}
+
+// Create user data template.
+func (u *UserDataTemplateClient) Create(kt *kit.Kit, req *protocloud.UserDataTemplateCreateReq) (
+	*core.CreateResult, error) {
+
+	return common.Request[protocloud.UserDataTemplateCreateReq, core.CreateResult](u.client, rest.POST, kt, req,
+		"/cloud/user_data_templates/create")
+}
+
+// Update user data template, a new version will be created if content is changed.
+func (u *UserDataTemplateClient) Update(kt *kit.Kit, id string, req *protocloud.UserDataTemplateUpdateReq) error {
+	return common.RequestNoResp[protocloud.UserDataTemplateUpdateReq](u.client, rest.PATCH, kt, req,
+		"/cloud/user_data_templates/%s", id)
+}
+
+// List user data template.
+func (u *UserDataTemplateClient) List(kt *kit.Kit, req *core.ListReq) (*protocloud.UserDataTemplateListResult,
+	error) {
+
+	return common.Request[core.ListReq, protocloud.UserDataTemplateListResult](u.client, rest.POST, kt, req,
+		"/cloud/user_data_templates/list")
+}
+
+// BatchDelete user data template.
+func (u *UserDataTemplateClient) BatchDelete(kt *kit.Kit, req *dataservice.BatchDeleteReq) error {
+	return common.RequestNoResp[dataservice.BatchDeleteReq](u.client, rest.DELETE, kt, req,
+		"/cloud/user_data_templates/batch")
+}
+
+// ListVersion list user data template version.
+func (u *UserDataTemplateClient) ListVersion(kt *kit.Kit, req *core.ListReq) (
+	*protocloud.UserDataTemplateVersionListResult, error) {
+
+	return common.Request[core.ListReq, protocloud.UserDataTemplateVersionListResult](u.client, rest.POST, kt, req,
+		"/cloud/user_data_templates/versions/list")
+}
+
+// Render user data template.
+func (u *UserDataTemplateClient) Render(kt *kit.Kit, req *protocloud.UserDataTemplateRenderReq) (
+	*protocloud.UserDataTemplateRenderResult, error) {
+
+	return common.Request[protocloud.UserDataTemplateRenderReq, protocloud.UserDataTemplateRenderResult](u.client,
+		rest.POST, kt, req, "/cloud/user_data_templates/render")
+}
@@ -38,6 +38,9 @@ type Client struct {
 	RouteTable    *RouteTableClient
 	InstanceType  *InstanceTypeClient
 	Bill          *BillClient
+	AuditEvent    *AuditEventClient
+	ResourceSync  *ResourceSyncClient
+	ResourceTag   *ResourceTagClient
 }
 
 // NewClient create a new aws api client.
@@ -56,5 +59,8 @@ func NewClient(client rest.ClientInterface) *Client {
 		RouteTable:    NewRouteTableClient(client),
 		InstanceType:  NewInstanceTypeClient(client),
 		Bill:          NewBillClient(client),
+		AuditEvent:    NewAuditEventClient(client),
+		ResourceSync:  NewResourceSyncClient(client),
+		ResourceTag:   NewResourceTagClient(client),
 	}
 }
@@ -39,6 +39,9 @@ type Client struct {
 	InstanceType     *InstanceTypeClient
 	NetworkInterface *NetworkInterfaceClient
 	Bill             *BillClient
+	AuditEvent       *AuditEventClient
+	ResourceSync     *ResourceSyncClient
+	ResourceTag      *ResourceTagClient
 }
 
 // NewClient create a new azure api client.
@@ -58,5 +61,8 @@ func NewClient(client rest.ClientInterface) *Client {
 		InstanceType:     NewInstanceTypeClient(client),
 		NetworkInterface: NewNetworkInterfaceClient(client),
 		Bill:             NewBillClient(client),
+		AuditEvent:       NewAuditEventClient(client),
+		ResourceSync:     NewResourceSyncClient(client),
+		ResourceTag:      NewResourceTagClient(client),
 	}
 }
@@ -0,0 +1,47 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package gcp
+
+import (
+	"hcm/pkg/api/hc-service/sync"
+	"hcm/pkg/client/common"
+	"hcm/pkg/kit"
+	"hcm/pkg/rest"
+)
+
+// NewAuditEventClient create a new audit event api client.
+func NewAuditEventClient(client rest.ClientInterface) *AuditEventClient {
+	return &AuditEventClient{
+		client: client,
+	}
+}
+
+// AuditEventClient is hc service audit event api client.
+type AuditEventClient struct {
+	client rest.ClientInterface
+}
+
+// SyncByAuditEvent sync resources changed in cloud audit events.
+func (cli *AuditEventClient) SyncByAuditEvent(kt *kit.Kit, req *sync.AuditEventSyncReq) (
+	*sync.AuditEventSyncResult, error) {
+
+	return common.Request[sync.AuditEventSyncReq, sync.AuditEventSyncResult](cli.client, rest.POST, kt, req,
+		"/audit_events/sync")
+}
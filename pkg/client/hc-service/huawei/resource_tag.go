@@ -0,0 +1,51 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package huawei
+
+import (
+	hctag "hcm/pkg/api/hc-service/tag"
+	"hcm/pkg/client/common"
+	"hcm/pkg/kit"
+	"hcm/pkg/rest"
+)
+
+// NewResourceTagClient create a new resource tag api client.
+func NewResourceTagClient(client rest.ClientInterface) *ResourceTagClient {
+	return &ResourceTagClient{
+		client: client,
+	}
+}
+
+// ResourceTagClient is hc service resource tag api client.
+type ResourceTagClient struct {
+	client rest.ClientInterface
+}
+
+// BatchUpsert batch add or overwrite resource tags.
+func (cli *ResourceTagClient) BatchUpsert(kt *kit.Kit, req *hctag.BatchUpsertTagReq) error {
+	return common.RequestNoResp[hctag.BatchUpsertTagReq](cli.client, rest.POST, kt, req,
+		"/resource_tags/batch/upsert")
+}
+
+// BatchDelete batch remove resource tags.
+func (cli *ResourceTagClient) BatchDelete(kt *kit.Kit, req *hctag.BatchDeleteTagReq) error {
+	return common.RequestNoResp[hctag.BatchDeleteTagReq](cli.client, rest.DELETE, kt, req,
+		"/resource_tags/batch")
+}
@@ -119,6 +119,25 @@ func (c *Client) GetFlow(kt *kit.Kit, id string) (*coreasync.AsyncFlow, error) {
 	return resp.Data, err
 }
 
+// GetFlowDAG get flow dag, format support json and dot.
+func (c *Client) GetFlowDAG(kt *kit.Kit, id string, format string) (*apits.GetFlowDAGResult, error) {
+	resp := new(core.BaseResp[*apits.GetFlowDAGResult])
+
+	err := c.client.Get().
+		WithContext(kt.Ctx).
+		SubResourcef("/flows/%s/dag", id).
+		WithParam("format", format).
+		WithHeaders(kt.Header()).
+		Do().
+		Into(resp)
+
+	if resp.Code != errf.OK {
+		return nil, errf.New(resp.Code, resp.Message)
+	}
+
+	return resp.Data, err
+}
+
 // ListTask list task.
 func (c *Client) ListTask(kt *kit.Kit, req *core.ListReq) (*apits.ListTaskResult, error) {
 	resp := new(core.BaseResp[*apits.ListTaskResult])
@@ -155,3 +174,265 @@ func (c *Client) GetTask(kt *kit.Kit, id string) (*coreasync.AsyncFlowTask, erro
 
 	return resp.Data, err
 }
+
+// CancelFlow cancel flow.
+func (c *Client) CancelFlow(kt *kit.Kit, id string, req *apits.CancelFlowReq) error {
+	resp := new(core.BaseResp[interface{}])
+
+	err := c.client.Post().
+		WithContext(kt.Ctx).
+		Body(req).
+		SubResourcef("/flows/%s/cancel", id).
+		WithHeaders(kt.Header()).
+		Do().
+		Into(resp)
+	if err != nil {
+		return err
+	}
+
+	if resp.Code != errf.OK {
+		return errf.New(resp.Code, resp.Message)
+	}
+
+	return nil
+}
+
+// RetryFlow retry failed flow from failed tasks.
+func (c *Client) RetryFlow(kt *kit.Kit, id string) error {
+	resp := new(core.BaseResp[interface{}])
+
+	err := c.client.Post().
+		WithContext(kt.Ctx).
+		SubResourcef("/flows/%s/retry", id).
+		WithHeaders(kt.Header()).
+		Do().
+		Into(resp)
+	if err != nil {
+		return err
+	}
+
+	if resp.Code != errf.OK {
+		return errf.New(resp.Code, resp.Message)
+	}
+
+	return nil
+}
+
+// ResumeFlow resume canceled flow.
+func (c *Client) ResumeFlow(kt *kit.Kit, id string) error {
+	resp := new(core.BaseResp[interface{}])
+
+	err := c.client.Post().
+		WithContext(kt.Ctx).
+		SubResourcef("/flows/%s/resume", id).
+		WithHeaders(kt.Header()).
+		Do().
+		Into(resp)
+	if err != nil {
+		return err
+	}
+
+	if resp.Code != errf.OK {
+		return errf.New(resp.Code, resp.Message)
+	}
+
+	return nil
+}
+
+// UpdateTaskState manually update task state to success or skipped.
+func (c *Client) UpdateTaskState(kt *kit.Kit, id string, req *apits.UpdateTaskStateReq) error {
+	resp := new(core.BaseResp[interface{}])
+
+	err := c.client.Patch().
+		WithContext(kt.Ctx).
+		Body(req).
+		SubResourcef("/tasks/%s/state", id).
+		WithHeaders(kt.Header()).
+		Do().
+		Into(resp)
+	if err != nil {
+		return err
+	}
+
+	if resp.Code != errf.OK {
+		return errf.New(resp.Code, resp.Message)
+	}
+
+	return nil
+}
+
+// CreateFlowSchedule add flow schedule.
+func (c *Client) CreateFlowSchedule(kt *kit.Kit, request *apits.AddFlowScheduleReq) (*core.CreateResult, error) {
+	resp := new(core.CreateResp)
+
+	err := c.client.Post().
+		WithContext(kt.Ctx).
+		Body(request).
+		SubResourcef("/flow_schedules/create").
+		WithHeaders(kt.Header()).
+		Do().
+		Into(resp)
+
+	if resp.Code != errf.OK {
+		return nil, errf.New(resp.Code, resp.Message)
+	}
+
+	return resp.Data, err
+}
+
+// ListFlowSchedule list flow schedule.
+func (c *Client) ListFlowSchedule(kt *kit.Kit, req *core.ListReq) (*apits.ListFlowScheduleResult, error) {
+	resp := new(core.BaseResp[*apits.ListFlowScheduleResult])
+
+	err := c.client.Post().
+		WithContext(kt.Ctx).
+		Body(req).
+		SubResourcef("/flow_schedules/list").
+		WithHeaders(kt.Header()).
+		Do().
+		Into(resp)
+
+	if resp.Code != errf.OK {
+		return nil, errf.New(resp.Code, resp.Message)
+	}
+
+	return resp.Data, err
+}
+
+// ListFlowScheduleRun list flow schedule run.
+func (c *Client) ListFlowScheduleRun(kt *kit.Kit, req *core.ListReq) (*apits.ListFlowScheduleRunResult, error) {
+	resp := new(core.BaseResp[*apits.ListFlowScheduleRunResult])
+
+	err := c.client.Post().
+		WithContext(kt.Ctx).
+		Body(req).
+		SubResourcef("/flow_schedule_runs/list").
+		WithHeaders(kt.Header()).
+		Do().
+		Into(resp)
+
+	if resp.Code != errf.OK {
+		return nil, errf.New(resp.Code, resp.Message)
+	}
+
+	return resp.Data, err
+}
+
+// ListFlowEvent list flow event.
+func (c *Client) ListFlowEvent(kt *kit.Kit, req *core.ListReq) (*apits.ListFlowEventResult, error) {
+	resp := new(core.BaseResp[*apits.ListFlowEventResult])
+
+	err := c.client.Post().
+		WithContext(kt.Ctx).
+		Body(req).
+		SubResourcef("/flow_events/list").
+		WithHeaders(kt.Header()).
+		Do().
+		Into(resp)
+
+	if resp.Code != errf.OK {
+		return nil, errf.New(resp.Code, resp.Message)
+	}
+
+	return resp.Data, err
+}
+
+// ListFlowArchive list archived flow.
+func (c *Client) ListFlowArchive(kt *kit.Kit, req *core.ListReq) (*apits.ListFlowArchiveResult, error) {
+	resp := new(core.BaseResp[*apits.ListFlowArchiveResult])
+
+	err := c.client.Post().
+		WithContext(kt.Ctx).
+		Body(req).
+		SubResourcef("/flow_archives/list").
+		WithHeaders(kt.Header()).
+		Do().
+		Into(resp)
+
+	if resp.Code != errf.OK {
+		return nil, errf.New(resp.Code, resp.Message)
+	}
+
+	return resp.Data, err
+}
+
+// GetFlowArchive get archived flow with its tasks and events.
+func (c *Client) GetFlowArchive(kt *kit.Kit, id string) (*coreasync.AsyncFlowArchive, error) {
+	resp := new(core.BaseResp[*coreasync.AsyncFlowArchive])
+
+	err := c.client.Get().
+		WithContext(kt.Ctx).
+		SubResourcef("/flow_archives/%s", id).
+		WithHeaders(kt.Header()).
+		Do().
+		Into(resp)
+
+	if resp.Code != errf.OK {
+		return nil, errf.New(resp.Code, resp.Message)
+	}
+
+	return resp.Data, err
+}
+
+// PauseFlowSchedule pause flow schedule.
+func (c *Client) PauseFlowSchedule(kt *kit.Kit, id string) error {
+	resp := new(core.BaseResp[interface{}])
+
+	err := c.client.Post().
+		WithContext(kt.Ctx).
+		SubResourcef("/flow_schedules/%s/pause", id).
+		WithHeaders(kt.Header()).
+		Do().
+		Into(resp)
+	if err != nil {
+		return err
+	}
+
+	if resp.Code != errf.OK {
+		return errf.New(resp.Code, resp.Message)
+	}
+
+	return nil
+}
+
+// ResumeFlowSchedule resume paused flow schedule.
+func (c *Client) ResumeFlowSchedule(kt *kit.Kit, id string) error {
+	resp := new(core.BaseResp[interface{}])
+
+	err := c.client.Post().
+		WithContext(kt.Ctx).
+		SubResourcef("/flow_schedules/%s/resume", id).
+		WithHeaders(kt.Header()).
+		Do().
+		Into(resp)
+	if err != nil {
+		return err
+	}
+
+	if resp.Code != errf.OK {
+		return errf.New(resp.Code, resp.Message)
+	}
+
+	return nil
+}
+
+// DeleteFlowSchedule delete flow schedule.
+func (c *Client) DeleteFlowSchedule(kt *kit.Kit, id string) error {
+	resp := new(core.BaseResp[interface{}])
+
+	err := c.client.Delete().
+		WithContext(kt.Ctx).
+		SubResourcef("/flow_schedules/%s", id).
+		WithHeaders(kt.Header()).
+		Do().
+		Into(resp)
+	if err != nil {
+		return err
+	}
+
+	if resp.Code != errf.OK {
+		return errf.New(resp.Code, resp.Message)
+	}
+
+	return nil
+}
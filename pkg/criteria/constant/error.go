@@ -40,4 +40,6 @@ const (
 	AsyncTaskWarnSign = "async_task_exec_exception"
 	// ApplicationDeliverFailed 申请单交付失败告警
 	ApplicationDeliverFailed WarnSign = "application_deliver_failed"
+	// SyncMassDeletionQuarantined 资源同步删除数量超过阈值，待删除资源被隔离告警
+	SyncMassDeletionQuarantined WarnSign = "sync_mass_deletion_quarantined"
 )
@@ -44,6 +44,13 @@ const (
 	// RequestSourceKey is blueking hcm request source header key.
 	RequestSourceKey = "X-Bkhcm-Request-Source"
 
+	// SyncFencingTokenKey is blueking hcm account sync lock fencing token header key.
+	SyncFencingTokenKey = "X-Bkhcm-Sync-Fencing-Token"
+
+	// IdempotencyKeyKey is blueking hcm idempotency key header key, flow creation with the same key
+	// and payload under the same app code returns the originally created flow.
+	IdempotencyKeyKey = "X-Bkhcm-Idempotency-Key"
+
 	// BKGWAuthKey is blueking api gateway authorization header key.
 	BKGWAuthKey = "X-Bkapi-Authorization"
 )
@@ -37,6 +37,12 @@ const (
 	TaskSuccess TaskState = "success"
 	// TaskFailed task state is failed
 	TaskFailed TaskState = "failed"
+	// TaskSkipped task state is skipped, only set by manual, downstream tasks can continue as success.
+	TaskSkipped TaskState = "skipped"
+	// TaskCompensated task state is compensated, succeeded task has been compensated after flow failed.
+	TaskCompensated TaskState = "compensated"
+	// TaskCompensateFailed task state is compensate_failed, succeeded task failed to be compensated after flow failed.
+	TaskCompensateFailed TaskState = "compensate_failed"
 )
 
 // FlowState is flow state.
@@ -55,8 +61,63 @@ const (
 	FlowSuccess FlowState = "success"
 	// FlowFailed flow state is failed
 	FlowFailed FlowState = "failed"
+	// FlowCompensating flow state is compensating, flow failed and succeeded tasks are waiting to be compensated.
+	FlowCompensating FlowState = "compensating"
+	// FlowRolledBack flow state is rolled_back, flow failed and all succeeded tasks have been compensated.
+	FlowRolledBack FlowState = "rolled_back"
 )
 
+// FlowRollbackPolicy is the rollback policy of flow when flow failed.
+type FlowRollbackPolicy string
+
+// Validate FlowRollbackPolicy.
+func (v FlowRollbackPolicy) Validate() error {
+	switch v {
+	case FlowRollbackNone:
+	case FlowRollbackSaga:
+	default:
+		return fmt.Errorf("unsupported flow rollback policy: %s", v)
+	}
+
+	return nil
+}
+
+const (
+	// FlowRollbackNone flow failed directly, succeeded tasks keep the resources they created.
+	FlowRollbackNone FlowRollbackPolicy = "none"
+	// FlowRollbackSaga compensate succeeded tasks in reverse dependency order after flow failed.
+	FlowRollbackSaga FlowRollbackPolicy = "saga"
+)
+
+// FlowPriority is the priority of flow, dispatcher dispatches flows in priority order, and each priority is a
+// dispatch queue with its own concurrency quota.
+type FlowPriority string
+
+// Validate FlowPriority.
+func (v FlowPriority) Validate() error {
+	switch v {
+	case FlowPriorityHigh:
+	case FlowPriorityNormal:
+	case FlowPriorityLow:
+	default:
+		return fmt.Errorf("unsupported flow priority: %s", v)
+	}
+
+	return nil
+}
+
+const (
+	// FlowPriorityHigh high priority, used for interactive operations, such as start/stop cvm.
+	FlowPriorityHigh FlowPriority = "high"
+	// FlowPriorityNormal normal priority, it is the default priority.
+	FlowPriorityNormal FlowPriority = "normal"
+	// FlowPriorityLow low priority, used for bulk jobs, such as batch create cvm, sync resource.
+	FlowPriorityLow FlowPriority = "low"
+)
+
+// FlowPriorities all flow priorities, sorted from high to low.
+var FlowPriorities = []FlowPriority{FlowPriorityHigh, FlowPriorityNormal, FlowPriorityLow}
+
 // BackendType is backend type.
 type BackendType string
 
@@ -64,6 +125,8 @@ type BackendType string
 func (v BackendType) Validate() error {
 	switch v {
 	case BackendMysql:
+	case BackendMemory:
+	case BackendSqlite:
 	default:
 		return fmt.Errorf("unsupported backend type: %s", v)
 	}
@@ -74,4 +137,114 @@ func (v BackendType) Validate() error {
 const (
 	// BackendMysql mysql backend
 	BackendMysql BackendType = "mysql"
+	// BackendMemory memory backend, data is lost after process exit, used for test and standalone scene.
+	BackendMemory BackendType = "memory"
+	// BackendSqlite sqlite backend
+	BackendSqlite BackendType = "sqlite"
+)
+
+// FlowScheduleState is flow schedule state.
+type FlowScheduleState string
+
+// Validate FlowScheduleState.
+func (v FlowScheduleState) Validate() error {
+	switch v {
+	case FlowScheduleEnabled:
+	case FlowSchedulePaused:
+	default:
+		return fmt.Errorf("unsupported flow schedule state: %s", v)
+	}
+
+	return nil
+}
+
+const (
+	// FlowScheduleEnabled flow schedule is enabled, flows will be created on time.
+	FlowScheduleEnabled FlowScheduleState = "enabled"
+	// FlowSchedulePaused flow schedule is paused, no flow will be created until resumed.
+	FlowSchedulePaused FlowScheduleState = "paused"
+)
+
+// MissedRunPolicy is the policy of handling missed runs of flow schedule, runs are missed when the leader node
+// is down or busy at the planned time.
+type MissedRunPolicy string
+
+// Validate MissedRunPolicy.
+func (v MissedRunPolicy) Validate() error {
+	switch v {
+	case MissedRunSkip:
+	case MissedRunOnce:
+	case MissedRunAll:
+	default:
+		return fmt.Errorf("unsupported missed run policy: %s", v)
+	}
+
+	return nil
+}
+
+const (
+	// MissedRunSkip skip all missed runs, only on time runs will be triggered.
+	MissedRunSkip MissedRunPolicy = "skip"
+	// MissedRunOnce merge all missed runs into one run.
+	MissedRunOnce MissedRunPolicy = "run_once"
+	// MissedRunAll trigger all missed runs.
+	MissedRunAll MissedRunPolicy = "run_all"
+)
+
+// FlowScheduleRunState is flow schedule run state.
+type FlowScheduleRunState string
+
+const (
+	// FlowScheduleRunTriggered flow of this run has been created.
+	FlowScheduleRunTriggered FlowScheduleRunState = "triggered"
+	// FlowScheduleRunSkipped this run is skipped by missed run policy.
+	FlowScheduleRunSkipped FlowScheduleRunState = "skipped"
+	// FlowScheduleRunFailed create flow of this run failed.
+	FlowScheduleRunFailed FlowScheduleRunState = "failed"
+)
+
+// FlowEventType is flow lifecycle event type.
+type FlowEventType string
+
+// Validate FlowEventType.
+func (v FlowEventType) Validate() error {
+	switch v {
+	case FlowEventCreated:
+	case FlowEventRunning:
+	case FlowEventSuccess:
+	case FlowEventFailed:
+	case FlowEventCancelled:
+	case FlowEventRolledBack:
+	default:
+		return fmt.Errorf("unsupported flow event type: %s", v)
+	}
+
+	return nil
+}
+
+const (
+	// FlowEventCreated flow is created.
+	FlowEventCreated FlowEventType = "created"
+	// FlowEventRunning flow starts running.
+	FlowEventRunning FlowEventType = "running"
+	// FlowEventSuccess flow is finished successfully.
+	FlowEventSuccess FlowEventType = "success"
+	// FlowEventFailed flow is failed.
+	FlowEventFailed FlowEventType = "failed"
+	// FlowEventCancelled flow is cancelled.
+	FlowEventCancelled FlowEventType = "cancelled"
+	// FlowEventRolledBack flow is failed and all succeeded tasks have been compensated.
+	FlowEventRolledBack FlowEventType = "rolled_back"
+)
+
+// FlowEventState is webhook delivery state of flow event.
+type FlowEventState string
+
+const (
+	// FlowEventStatePending flow event is waiting to be delivered or retried.
+	FlowEventStatePending FlowEventState = "pending"
+	// FlowEventStateDelivered flow event has been delivered to all webhook targets.
+	FlowEventStateDelivered FlowEventState = "delivered"
+	// FlowEventStateFailed flow event delivery failed after max attempts.
+	FlowEventStateFailed FlowEventState = "failed"
 )
@@ -33,6 +33,7 @@ func (v FlowName) Validate() error {
 	case FlowNormalTest, FlowSleepTest:
 	case FlowDeleteSecurityGroup, FlowCreateHuaweiSGRule:
 	case FlowDeleteEIP:
+	case FlowSyncAccount, FlowSelectiveSyncAccount:
 
 	default:
 		return fmt.Errorf("unsupported tpl: %s", v)
@@ -79,3 +80,11 @@ const (
 	// FlowDeleteEIP ...
 	FlowDeleteEIP FlowName = "delete_eip"
 )
+
+// 资源同步相关Flow
+const (
+	// FlowSyncAccount 同步账号下的云资源
+	FlowSyncAccount FlowName = "sync_account"
+	// FlowSelectiveSyncAccount 按地域、资源类型、云ID定向同步账号下的云资源
+	FlowSelectiveSyncAccount FlowName = "selective_sync_account"
+)
@@ -35,6 +35,7 @@ func (v ActionName) Validate() error {
 	case ActionDeleteSubnet:
 	case ActionDeleteSecurityGroup, ActionCreateHuaweiSGRule:
 	case ActionDeleteEIP:
+	case ActionSyncAccountResource, ActionSyncResourceByCloudIDs:
 
 	case VirRoot:
 	case ActionCreateFactoryTest, ActionProduceTest, ActionAssembleTest, ActionSleep:
@@ -92,3 +93,11 @@ const (
 	// ActionDeleteEIP ...
 	ActionDeleteEIP ActionName = "delete_eip"
 )
+
+// 资源同步相关Action
+const (
+	// ActionSyncAccountResource 同步账号下指定地域的一类资源
+	ActionSyncAccountResource ActionName = "sync_account_resource"
+	// ActionSyncResourceByCloudIDs 按云ID定向同步账号下的一类资源
+	ActionSyncResourceByCloudIDs ActionName = "sync_resource_by_cloud_ids"
+)
@@ -40,6 +40,11 @@ const (
 	EipAuditResType               AuditResourceType = "eip"
 	GcpFirewallRuleAuditResType   AuditResourceType = "gcp_firewall_rule"
 	NetworkInterfaceAuditResType  AuditResourceType = "network_interface"
+	UserDataTemplateAuditResType  AuditResourceType = "user_data_template"
+	LaunchTemplateAuditResType    AuditResourceType = "launch_template"
+	AsyncFlowAuditResType         AuditResourceType = "async_flow"
+	AsyncFlowTaskAuditResType     AuditResourceType = "async_flow_task"
+	AsyncFlowScheduleAuditResType AuditResourceType = "async_flow_schedule"
 )
 
 // AuditResourceTypeEnums resource type map.
@@ -56,6 +61,11 @@ var AuditResourceTypeEnums = map[AuditResourceType]struct{}{
 	EipAuditResType:               {},
 	GcpFirewallRuleAuditResType:   {},
 	NetworkInterfaceAuditResType:  {},
+	UserDataTemplateAuditResType:  {},
+	LaunchTemplateAuditResType:    {},
+	AsyncFlowAuditResType:         {},
+	AsyncFlowTaskAuditResType:     {},
+	AsyncFlowScheduleAuditResType: {},
 }
 
 // Exist judge enum value exist.
@@ -96,6 +106,20 @@ const (
 	Bind AuditAction = "bind"
 	// Deliver 交付
 	Deliver AuditAction = "deliver"
+	// Tag 添加标签
+	Tag AuditAction = "tag"
+	// UnTag 删除标签
+	UnTag AuditAction = "untag"
+	// Apply 应用模版
+	Apply AuditAction = "apply"
+	// Cancel 取消
+	Cancel AuditAction = "cancel"
+	// Retry 重试
+	Retry AuditAction = "retry"
+	// Resume 继续执行
+	Resume AuditAction = "resume"
+	// Pause 暂停
+	Pause AuditAction = "pause"
 )
 
 // AuditActionEnums op type map.
@@ -114,6 +138,13 @@ var AuditActionEnums = map[AuditAction]struct{}{
 	Disassociate: {},
 	Bind:         {},
 	Deliver:      {},
+	Tag:          {},
+	UnTag:        {},
+	Apply:        {},
+	Cancel:       {},
+	Retry:        {},
+	Resume:       {},
+	Pause:        {},
 }
 
 // Exist judge enum value exist.
@@ -47,3 +47,63 @@ const (
 	// Syncing status
 	Syncing SyncStatus = "syncing"
 )
+
+// IncrementalSyncCheckpoint 账号同步详情中记录增量同步检查点的资源名称，该记录的 res_end_time 为下次增量同步的开始时间。
+const IncrementalSyncCheckpoint = "incremental_sync_checkpoint"
+
+// SyncQuarantineStatus 同步删除保护中被隔离资源的状态。
+type SyncQuarantineStatus string
+
+// Validate SyncQuarantineStatus.
+func (s SyncQuarantineStatus) Validate() error {
+	switch s {
+	case SyncQuarantined:
+	case SyncQuarantineApproved:
+	default:
+		return fmt.Errorf("unsupported sync quarantine status: %s", s)
+	}
+
+	return nil
+}
+
+const (
+	// SyncQuarantined 资源在云上查询不到，删除数量超过阈值被隔离，等待再次同步确认或人工审批后删除。
+	SyncQuarantined SyncQuarantineStatus = "quarantined"
+	// SyncQuarantineApproved 已人工审批，下次同步时删除。
+	SyncQuarantineApproved SyncQuarantineStatus = "approved"
+)
+
+// ResSyncStatus 资源的同步状态，记录在支持同步删除保护的资源表的 sync_status 字段中。
+type ResSyncStatus string
+
+// Validate ResSyncStatus.
+func (s ResSyncStatus) Validate() error {
+	switch s {
+	case ResSyncNormal:
+	case ResMissingFromCloud:
+	default:
+		return fmt.Errorf("unsupported resource sync status: %s", s)
+	}
+
+	return nil
+}
+
+const (
+	// ResSyncNormal 资源正常同步
+	ResSyncNormal ResSyncStatus = ""
+	// ResMissingFromCloud 资源在云上查询不到，删除被同步删除保护隔离，等待确认后删除
+	ResMissingFromCloud ResSyncStatus = "missing_from_cloud"
+)
+
+// SyncGuardResTypes 支持同步删除保护的资源类型，这些资源表通过 sync_status 字段标记资源的同步状态。
+var SyncGuardResTypes = map[CloudResourceType]struct{}{
+	CvmCloudResType:              {},
+	DiskCloudResType:             {},
+	EipCloudResType:              {},
+	VpcCloudResType:              {},
+	SubnetCloudResType:           {},
+	SecurityGroupCloudResType:    {},
+	GcpFirewallRuleCloudResType:  {},
+	RouteTableCloudResType:       {},
+	NetworkInterfaceCloudResType: {},
+}
@@ -59,4 +59,6 @@ const (
 	UserNoAppAccess int32 = 2000009
 	// RecordNotUpdate DB数据一行都没有被更新
 	RecordNotUpdate int32 = 2000010
+	// IdempotencyKeyConflict means the idempotency key is already used by a request with different payload.
+	IdempotencyKeyConflict int32 = 2000011
 )
@@ -170,7 +170,9 @@ func (dao *AsyncFlowDao) UpdateStateByCAS(kt *kit.Kit, tx *sqlx.Tx, info *typesa
 	}
 
 	setSql := "set state = :target"
-	if len(info.Worker) != 0 {
+	if info.ResetWorker {
+		setSql += ", worker = ''"
+	} else if len(info.Worker) != 0 {
 		setSql += ", worker = :worker"
 	}
 
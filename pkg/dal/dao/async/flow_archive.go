@@ -0,0 +1,125 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package daoasync
+
+import (
+	"fmt"
+
+	"hcm/pkg/api/core"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/orm"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/dal/dao/types"
+	typesasync "hcm/pkg/dal/dao/types/async"
+	"hcm/pkg/dal/table"
+	tableasync "hcm/pkg/dal/table/async"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/runtime/filter"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// AsyncFlowArchive only used archived async flow.
+type AsyncFlowArchive interface {
+	BatchCreateWithTx(kt *kit.Kit, tx *sqlx.Tx, models []tableasync.AsyncFlowArchiveTable) error
+	List(kt *kit.Kit, opt *types.ListOption) (*typesasync.ListAsyncFlowArchives, error)
+}
+
+var _ AsyncFlowArchive = new(AsyncFlowArchiveDao)
+
+// AsyncFlowArchiveDao async flow archive dao, id of archive is the id of archived flow.
+type AsyncFlowArchiveDao struct {
+	Orm orm.Interface
+}
+
+// BatchCreateWithTx async flow archive with tx.
+func (dao *AsyncFlowArchiveDao) BatchCreateWithTx(kt *kit.Kit, tx *sqlx.Tx,
+	models []tableasync.AsyncFlowArchiveTable) error {
+
+	if len(models) == 0 {
+		return nil
+	}
+
+	for index := range models {
+		if err := models[index].InsertValidate(); err != nil {
+			return err
+		}
+	}
+
+	sql := fmt.Sprintf(`INSERT INTO %s (%s)	VALUES(%s)`, table.AsyncFlowArchiveTable,
+		tableasync.AsyncFlowArchiveColumns.ColumnExpr(), tableasync.AsyncFlowArchiveColumns.ColonNameExpr())
+
+	if err := dao.Orm.Txn(tx).BulkInsert(kt.Ctx, sql, models); err != nil {
+		logs.Errorf("insert %s failed, err: %v, sql: %s, rid: %s", table.AsyncFlowArchiveTable, err, sql, kt.Rid)
+		return fmt.Errorf("insert %s failed, err: %v", table.AsyncFlowArchiveTable, err)
+	}
+
+	return nil
+}
+
+// List async flow archive.
+func (dao *AsyncFlowArchiveDao) List(kt *kit.Kit, opt *types.ListOption) (*typesasync.ListAsyncFlowArchives, error) {
+
+	if opt == nil {
+		return nil, errf.New(errf.InvalidParameter, "list async flow archive options is nil")
+	}
+
+	columnTypes := tableasync.AsyncFlowArchiveColumns.ColumnTypes()
+	if err := opt.Validate(filter.NewExprOption(filter.RuleFields(columnTypes)),
+		core.NewDefaultPageOption()); err != nil {
+		return nil, err
+	}
+
+	whereExpr, whereValue, err := opt.Filter.SQLWhereExpr(tools.DefaultSqlWhereOption)
+	if err != nil {
+		return nil, err
+	}
+
+	if opt.Page.Count {
+		// this is dao count request, then do count operation only.
+		sql := fmt.Sprintf(`SELECT COUNT(*) FROM %s %s`, table.AsyncFlowArchiveTable, whereExpr)
+
+		count, err := dao.Orm.Do().Count(kt.Ctx, sql, whereValue)
+		if err != nil {
+			logs.ErrorJson("count async flow archive failed, err: %v, filter: %s, rid: %s", err, opt.Filter, kt.Rid)
+			return nil, err
+		}
+
+		return &typesasync.ListAsyncFlowArchives{Count: count}, nil
+	}
+
+	pageExpr, err := types.PageSQLExpr(opt.Page, types.DefaultPageSQLOption)
+	if err != nil {
+		return nil, err
+	}
+
+	sql := fmt.Sprintf(`SELECT %s FROM %s %s %s`, tableasync.AsyncFlowArchiveColumns.FieldsNamedExpr(opt.Fields),
+		table.AsyncFlowArchiveTable, whereExpr, pageExpr)
+
+	details := make([]tableasync.AsyncFlowArchiveTable, 0)
+	if err = dao.Orm.Do().Select(kt.Ctx, &details, sql, whereValue); err != nil {
+		logs.ErrorJson("select async flow archive failed, err: %v, sql: %s, filter: %v, rid: %s", err, sql,
+			opt.Filter, kt.Rid)
+		return nil, err
+	}
+
+	return &typesasync.ListAsyncFlowArchives{Count: 0, Details: details}, nil
+}
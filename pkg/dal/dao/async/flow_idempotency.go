@@ -0,0 +1,187 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package daoasync
+
+import (
+	"fmt"
+
+	"hcm/pkg/api/core"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/orm"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/dal/dao/types"
+	typesasync "hcm/pkg/dal/dao/types/async"
+	"hcm/pkg/dal/table"
+	tableasync "hcm/pkg/dal/table/async"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/runtime/filter"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// AsyncFlowIdempotency only used for async flow idempotency key.
+type AsyncFlowIdempotency interface {
+	CreateWithTx(kt *kit.Kit, tx *sqlx.Tx, model *tableasync.AsyncFlowIdempotencyTable) error
+	ListWithTx(kt *kit.Kit, tx *sqlx.Tx, opt *types.ListOption) (*typesasync.ListAsyncFlowIdempotencies, error)
+	List(kt *kit.Kit, opt *types.ListOption) (*typesasync.ListAsyncFlowIdempotencies, error)
+	DeleteWithTx(kt *kit.Kit, tx *sqlx.Tx, expr *filter.Expression) error
+	Delete(kt *kit.Kit, expr *filter.Expression) error
+}
+
+var _ AsyncFlowIdempotency = new(AsyncFlowIdempotencyDao)
+
+// AsyncFlowIdempotencyDao async flow idempotency dao, id of idempotency key is the id of created flow.
+type AsyncFlowIdempotencyDao struct {
+	Orm orm.Interface
+}
+
+// CreateWithTx async flow idempotency key with tx.
+func (dao *AsyncFlowIdempotencyDao) CreateWithTx(kt *kit.Kit, tx *sqlx.Tx,
+	model *tableasync.AsyncFlowIdempotencyTable) error {
+
+	if err := model.InsertValidate(); err != nil {
+		return err
+	}
+
+	sql := fmt.Sprintf(`INSERT INTO %s (%s)	VALUES(%s)`, table.AsyncFlowIdempotencyTable,
+		tableasync.AsyncFlowIdempotencyColumns.ColumnExpr(), tableasync.AsyncFlowIdempotencyColumns.ColonNameExpr())
+
+	if err := dao.Orm.Txn(tx).Insert(kt.Ctx, sql, model); err != nil {
+		logs.Errorf("insert %s failed, err: %v, sql: %s, rid: %s", table.AsyncFlowIdempotencyTable, err, sql, kt.Rid)
+		return fmt.Errorf("insert %s failed, err: %v", table.AsyncFlowIdempotencyTable, err)
+	}
+
+	return nil
+}
+
+// ListWithTx async flow idempotency key with tx.
+func (dao *AsyncFlowIdempotencyDao) ListWithTx(kt *kit.Kit, tx *sqlx.Tx,
+	opt *types.ListOption) (*typesasync.ListAsyncFlowIdempotencies, error) {
+
+	if opt == nil {
+		return nil, errf.New(errf.InvalidParameter, "list async flow idempotency options is nil")
+	}
+
+	columnTypes := tableasync.AsyncFlowIdempotencyColumns.ColumnTypes()
+	if err := opt.Validate(filter.NewExprOption(filter.RuleFields(columnTypes)),
+		core.NewDefaultPageOption()); err != nil {
+		return nil, err
+	}
+
+	whereExpr, whereValue, err := opt.Filter.SQLWhereExpr(tools.DefaultSqlWhereOption)
+	if err != nil {
+		return nil, err
+	}
+
+	pageExpr, err := types.PageSQLExpr(opt.Page, types.DefaultPageSQLOption)
+	if err != nil {
+		return nil, err
+	}
+
+	sql := fmt.Sprintf(`SELECT %s FROM %s %s %s`, tableasync.AsyncFlowIdempotencyColumns.FieldsNamedExpr(opt.Fields),
+		table.AsyncFlowIdempotencyTable, whereExpr, pageExpr)
+
+	details := make([]tableasync.AsyncFlowIdempotencyTable, 0)
+	if err = dao.Orm.Txn(tx).Select(kt.Ctx, &details, sql, whereValue); err != nil {
+		logs.ErrorJson("select async flow idempotency failed, err: %v, sql: %s, filter: %v, rid: %s", err, sql,
+			opt.Filter, kt.Rid)
+		return nil, err
+	}
+
+	return &typesasync.ListAsyncFlowIdempotencies{Count: 0, Details: details}, nil
+}
+
+// List async flow idempotency key.
+func (dao *AsyncFlowIdempotencyDao) List(kt *kit.Kit,
+	opt *types.ListOption) (*typesasync.ListAsyncFlowIdempotencies, error) {
+
+	if opt == nil {
+		return nil, errf.New(errf.InvalidParameter, "list async flow idempotency options is nil")
+	}
+
+	columnTypes := tableasync.AsyncFlowIdempotencyColumns.ColumnTypes()
+	if err := opt.Validate(filter.NewExprOption(filter.RuleFields(columnTypes)),
+		core.NewDefaultPageOption()); err != nil {
+		return nil, err
+	}
+
+	whereExpr, whereValue, err := opt.Filter.SQLWhereExpr(tools.DefaultSqlWhereOption)
+	if err != nil {
+		return nil, err
+	}
+
+	pageExpr, err := types.PageSQLExpr(opt.Page, types.DefaultPageSQLOption)
+	if err != nil {
+		return nil, err
+	}
+
+	sql := fmt.Sprintf(`SELECT %s FROM %s %s %s`, tableasync.AsyncFlowIdempotencyColumns.FieldsNamedExpr(opt.Fields),
+		table.AsyncFlowIdempotencyTable, whereExpr, pageExpr)
+
+	details := make([]tableasync.AsyncFlowIdempotencyTable, 0)
+	if err = dao.Orm.Do().Select(kt.Ctx, &details, sql, whereValue); err != nil {
+		logs.ErrorJson("select async flow idempotency failed, err: %v, sql: %s, filter: %v, rid: %s", err, sql,
+			opt.Filter, kt.Rid)
+		return nil, err
+	}
+
+	return &typesasync.ListAsyncFlowIdempotencies{Count: 0, Details: details}, nil
+}
+
+// DeleteWithTx async flow idempotency key with tx.
+func (dao *AsyncFlowIdempotencyDao) DeleteWithTx(kt *kit.Kit, tx *sqlx.Tx, filterExpr *filter.Expression) error {
+	if filterExpr == nil {
+		return errf.New(errf.InvalidParameter, "filter expr is required")
+	}
+
+	whereExpr, whereValue, err := filterExpr.SQLWhereExpr(tools.DefaultSqlWhereOption)
+	if err != nil {
+		return err
+	}
+
+	sql := fmt.Sprintf(`DELETE FROM %s %s`, table.AsyncFlowIdempotencyTable, whereExpr)
+	if _, err = dao.Orm.Txn(tx).Delete(kt.Ctx, sql, whereValue); err != nil {
+		logs.ErrorJson("delete async flow idempotency failed, err: %v, filter: %s, rid: %s", err, filterExpr, kt.Rid)
+		return err
+	}
+
+	return nil
+}
+
+// Delete async flow idempotency key.
+func (dao *AsyncFlowIdempotencyDao) Delete(kt *kit.Kit, filterExpr *filter.Expression) error {
+	if filterExpr == nil {
+		return errf.New(errf.InvalidParameter, "filter expr is required")
+	}
+
+	whereExpr, whereValue, err := filterExpr.SQLWhereExpr(tools.DefaultSqlWhereOption)
+	if err != nil {
+		return err
+	}
+
+	sql := fmt.Sprintf(`DELETE FROM %s %s`, table.AsyncFlowIdempotencyTable, whereExpr)
+	if _, err = dao.Orm.Do().Delete(kt.Ctx, sql, whereValue); err != nil {
+		logs.ErrorJson("delete async flow idempotency failed, err: %v, filter: %s, rid: %s", err, filterExpr, kt.Rid)
+		return err
+	}
+
+	return nil
+}
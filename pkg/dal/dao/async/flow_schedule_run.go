@@ -0,0 +1,155 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package daoasync
+
+import (
+	"fmt"
+
+	"hcm/pkg/api/core"
+	"hcm/pkg/criteria/errf"
+	idgenerator "hcm/pkg/dal/dao/id-generator"
+	"hcm/pkg/dal/dao/orm"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/dal/dao/types"
+	typesasync "hcm/pkg/dal/dao/types/async"
+	"hcm/pkg/dal/table"
+	tableasync "hcm/pkg/dal/table/async"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/runtime/filter"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// AsyncFlowScheduleRun only used async flow schedule run history.
+type AsyncFlowScheduleRun interface {
+	BatchCreate(kt *kit.Kit, models []tableasync.AsyncFlowScheduleRunTable) ([]string, error)
+	List(kt *kit.Kit, opt *types.ListOption) (*typesasync.ListAsyncFlowScheduleRuns, error)
+	DeleteWithTx(kt *kit.Kit, tx *sqlx.Tx, expr *filter.Expression) error
+}
+
+var _ AsyncFlowScheduleRun = new(AsyncFlowScheduleRunDao)
+
+// AsyncFlowScheduleRunDao async flow schedule run dao.
+type AsyncFlowScheduleRunDao struct {
+	Orm   orm.Interface
+	IDGen idgenerator.IDGenInterface
+}
+
+// BatchCreate async flow schedule run.
+func (dao *AsyncFlowScheduleRunDao) BatchCreate(kt *kit.Kit, models []tableasync.AsyncFlowScheduleRunTable) (
+	[]string, error) {
+
+	ids, err := dao.IDGen.Batch(kt, table.AsyncFlowScheduleRunTable, len(models))
+	if err != nil {
+		return nil, err
+	}
+
+	for index := range models {
+		models[index].ID = ids[index]
+
+		if err = models[index].InsertValidate(); err != nil {
+			return nil, err
+		}
+	}
+
+	sql := fmt.Sprintf(`INSERT INTO %s (%s)	VALUES(%s)`, table.AsyncFlowScheduleRunTable,
+		tableasync.AsyncFlowScheduleRunColumns.ColumnExpr(), tableasync.AsyncFlowScheduleRunColumns.ColonNameExpr())
+
+	if err = dao.Orm.Do().BulkInsert(kt.Ctx, sql, models); err != nil {
+		logs.Errorf("insert %s failed, err: %v, sql: %s, rid: %s", table.AsyncFlowScheduleRunTable, err, sql,
+			kt.Rid)
+		return nil, fmt.Errorf("insert %s failed, err: %v", table.AsyncFlowScheduleRunTable, err)
+	}
+
+	return ids, nil
+}
+
+// List async flow schedule run.
+func (dao *AsyncFlowScheduleRunDao) List(kt *kit.Kit, opt *types.ListOption) (
+	*typesasync.ListAsyncFlowScheduleRuns, error) {
+
+	if opt == nil {
+		return nil, errf.New(errf.InvalidParameter, "list async flow schedule run options is nil")
+	}
+
+	columnTypes := tableasync.AsyncFlowScheduleRunColumns.ColumnTypes()
+	if err := opt.Validate(filter.NewExprOption(filter.RuleFields(columnTypes)),
+		core.NewDefaultPageOption()); err != nil {
+		return nil, err
+	}
+
+	whereExpr, whereValue, err := opt.Filter.SQLWhereExpr(tools.DefaultSqlWhereOption)
+	if err != nil {
+		return nil, err
+	}
+
+	if opt.Page.Count {
+		// this is dao count request, then do count operation only.
+		sql := fmt.Sprintf(`SELECT COUNT(*) FROM %s %s`, table.AsyncFlowScheduleRunTable, whereExpr)
+
+		count, err := dao.Orm.Do().Count(kt.Ctx, sql, whereValue)
+		if err != nil {
+			logs.ErrorJson("count async flow schedule run failed, err: %v, filter: %s, rid: %s", err,
+				opt.Filter, kt.Rid)
+			return nil, err
+		}
+
+		return &typesasync.ListAsyncFlowScheduleRuns{Count: count}, nil
+	}
+
+	pageExpr, err := types.PageSQLExpr(opt.Page, types.DefaultPageSQLOption)
+	if err != nil {
+		return nil, err
+	}
+
+	sql := fmt.Sprintf(`SELECT %s FROM %s %s %s`, tableasync.AsyncFlowScheduleRunColumns.FieldsNamedExpr(opt.Fields),
+		table.AsyncFlowScheduleRunTable, whereExpr, pageExpr)
+
+	details := make([]tableasync.AsyncFlowScheduleRunTable, 0)
+	if err = dao.Orm.Do().Select(kt.Ctx, &details, sql, whereValue); err != nil {
+		logs.ErrorJson("select async flow schedule run failed, err: %v, sql: %s, filter: %v, rid: %s", err, sql,
+			opt.Filter, kt.Rid)
+		return nil, err
+	}
+
+	return &typesasync.ListAsyncFlowScheduleRuns{Count: 0, Details: details}, nil
+}
+
+// DeleteWithTx async flow schedule run with tx.
+func (dao *AsyncFlowScheduleRunDao) DeleteWithTx(kt *kit.Kit, tx *sqlx.Tx, filterExpr *filter.Expression) error {
+	if filterExpr == nil {
+		return errf.New(errf.InvalidParameter, "filter expr is required")
+	}
+
+	whereExpr, whereValue, err := filterExpr.SQLWhereExpr(tools.DefaultSqlWhereOption)
+	if err != nil {
+		return err
+	}
+
+	sql := fmt.Sprintf(`DELETE FROM %s %s`, table.AsyncFlowScheduleRunTable, whereExpr)
+	if _, err = dao.Orm.Txn(tx).Delete(kt.Ctx, sql, whereValue); err != nil {
+		logs.ErrorJson("delete async flow schedule run failed, err: %v, filter: %s, rid: %s", err, filterExpr,
+			kt.Rid)
+		return err
+	}
+
+	return nil
+}
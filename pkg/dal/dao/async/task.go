@@ -44,6 +44,7 @@ type AsyncFlowTask interface {
 	BatchCreate(kt *kit.Kit, models []tableasync.AsyncFlowTaskTable) ([]string, error)
 	BatchCreateWithTx(kt *kit.Kit, tx *sqlx.Tx, models []tableasync.AsyncFlowTaskTable) ([]string, error)
 	Update(kt *kit.Kit, expr *filter.Expression, model *tableasync.AsyncFlowTaskTable) error
+	UpdateWithTx(kt *kit.Kit, tx *sqlx.Tx, expr *filter.Expression, model *tableasync.AsyncFlowTaskTable) error
 	UpdateByID(kt *kit.Kit, id string, model *tableasync.AsyncFlowTaskTable) error
 	UpdateStateByCAS(kt *kit.Kit, info *typesasync.UpdateTaskInfo) error
 	List(kt *kit.Kit, opt *types.ListOption) (*typesasync.ListAsyncFlowTasks, error)
@@ -130,6 +131,38 @@ func (dao *AsyncFlowTaskDao) Update(kt *kit.Kit, expr *filter.Expression, model
 	return nil
 }
 
+// UpdateWithTx async flow task with tx, no error is returned when no task matched.
+func (dao *AsyncFlowTaskDao) UpdateWithTx(kt *kit.Kit, tx *sqlx.Tx, expr *filter.Expression,
+	model *tableasync.AsyncFlowTaskTable) error {
+
+	if expr == nil {
+		return errf.New(errf.InvalidParameter, "filter expr is nil")
+	}
+
+	if err := model.UpdateValidate(); err != nil {
+		return err
+	}
+
+	whereExpr, whereValue, err := expr.SQLWhereExpr(tools.DefaultSqlWhereOption)
+	if err != nil {
+		return err
+	}
+
+	opts := utils.NewFieldOptions().AddIgnoredFields(types.DefaultIgnoredFields...)
+	setExpr, toUpdate, err := utils.RearrangeSQLDataWithOption(model, opts)
+	if err != nil {
+		return fmt.Errorf("prepare parsed sql set filter expr failed, err: %v", err)
+	}
+
+	sql := fmt.Sprintf(`UPDATE %s %s %s`, model.TableName(), setExpr, whereExpr)
+	if _, err = dao.Orm.Txn(tx).Update(kt.Ctx, sql, tools.MapMerge(toUpdate, whereValue)); err != nil {
+		logs.ErrorJson("update async flow task failed, err: %v, filter: %s, rid: %v", err, expr, kt.Rid)
+		return err
+	}
+
+	return nil
+}
+
 // UpdateStateByCAS update async flow task state by cas.
 func (dao *AsyncFlowTaskDao) UpdateStateByCAS(kt *kit.Kit, info *typesasync.UpdateTaskInfo) error {
 
@@ -184,12 +217,28 @@ func (dao *AsyncFlowTaskDao) UpdateByID(kt *kit.Kit, id string, model *tableasyn
 	sql := fmt.Sprintf(`UPDATE %s %s where id = :id`, model.TableName(), setExpr)
 
 	toUpdate["id"] = id
-	_, err = dao.Orm.Do().Update(kt.Ctx, sql, toUpdate)
+	effected, err := dao.Orm.Do().Update(kt.Ctx, sql, toUpdate)
 	if err != nil {
 		logs.Errorf("update async flow task failed, err: %v, id: %s, sql: %s, rid: %v", err, id, sql, kt.Rid)
 		return err
 	}
 
+	if effected != 0 {
+		return nil
+	}
+
+	// 字段值未变化时影响行数也为0，需要确认任务是否存在
+	countSql := fmt.Sprintf(`SELECT COUNT(*) FROM %s WHERE id = :id`, table.AsyncFlowTaskTable)
+	count, err := dao.Orm.Do().Count(kt.Ctx, countSql, map[string]interface{}{"id": id})
+	if err != nil {
+		logs.Errorf("count async flow task failed, err: %v, id: %s, rid: %v", err, id, kt.Rid)
+		return err
+	}
+
+	if count == 0 {
+		return errf.New(errf.RecordNotFound, orm.ErrRecordNotFound.Error())
+	}
+
 	return nil
 }
 
@@ -24,6 +24,7 @@ import (
 	"fmt"
 	"strings"
 
+	"hcm/pkg/api/core"
 	"hcm/pkg/criteria/enumor"
 	"hcm/pkg/criteria/errf"
 	"hcm/pkg/dal/dao/orm"
@@ -42,8 +43,19 @@ type Cloud interface {
 	ListResourceBasicInfo(kt *kit.Kit, resType enumor.CloudResourceType, ids []string, fields ...string) (
 		[]types.CloudResourceBasicInfo, error)
 	ListResourceIDs(kt *kit.Kit, resType enumor.CloudResourceType, expr *filter.Expression) ([]string, error)
+	CountResource(kt *kit.Kit, resType enumor.CloudResourceType, expr *filter.Expression) (uint64, error)
+	ListResourceCloudIDMap(kt *kit.Kit, resType enumor.CloudResourceType, accountID string, cloudIDs []string) (
+		map[string]string, error)
+	ListResourceBasicInfoByFilter(kt *kit.Kit, resType enumor.CloudResourceType, expr *filter.Expression,
+		page *core.BasePage, fields ...string) ([]types.CloudResourceBasicInfo, error)
+	ListResourceIDsForUpdateWithTx(kt *kit.Kit, tx *sqlx.Tx, resType enumor.CloudResourceType,
+		expr *filter.Expression) ([]string, error)
 	AssignResourceToBiz(kt *kit.Kit, tx *sqlx.Tx, resType enumor.CloudResourceType, expr *filter.Expression,
 		bizID int64) error
+	BindResourceCloudArea(kt *kit.Kit, tx *sqlx.Tx, resType enumor.CloudResourceType, expr *filter.Expression,
+		cloudID int64) error
+	UpdateResourceSyncStatus(kt *kit.Kit, resType enumor.CloudResourceType, expr *filter.Expression,
+		status enumor.ResSyncStatus) error
 }
 
 var _ Cloud = new(CloudDao)
@@ -131,6 +143,160 @@ func (dao CloudDao) ListResourceIDs(kt *kit.Kit, resType enumor.CloudResourceTyp
 	return ids, nil
 }
 
+// CountResource count cloud resource by filter.
+func (dao CloudDao) CountResource(kt *kit.Kit, resType enumor.CloudResourceType, expr *filter.Expression) (uint64,
+	error) {
+
+	tableName, err := resType.ConvTableName()
+	if err != nil {
+		return 0, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	if expr == nil {
+		return 0, errf.New(errf.InvalidParameter, "filter is required")
+	}
+
+	whereExpr, whereValue, err := expr.SQLWhereExpr(tools.DefaultSqlWhereOption)
+	if err != nil {
+		return 0, err
+	}
+
+	sql := fmt.Sprintf("select count(*) from %s %s", tableName, whereExpr)
+	count, err := dao.Orm.Do().Count(kt.Ctx, sql, whereValue)
+	if err != nil {
+		logs.Errorf("count %s resource failed, err: %v, expr: %v, rid: %s", resType, err, expr, kt.Rid)
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// ListResourceCloudIDMap list account's cloud resource cloud id to id map.
+func (dao CloudDao) ListResourceCloudIDMap(kt *kit.Kit, resType enumor.CloudResourceType, accountID string,
+	cloudIDs []string) (map[string]string, error) {
+
+	tableName, err := resType.ConvTableName()
+	if err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	if len(accountID) == 0 {
+		return nil, errf.New(errf.InvalidParameter, "account id is required")
+	}
+
+	if len(cloudIDs) == 0 {
+		return nil, errf.New(errf.InvalidParameter, "cloud ids is required")
+	}
+
+	sql := fmt.Sprintf("select id, cloud_id from %s where account_id = :account_id and cloud_id in (:cloud_ids)",
+		tableName)
+	args := map[string]interface{}{
+		"account_id": accountID,
+		"cloud_ids":  cloudIDs,
+	}
+
+	list := make([]types.CloudResourceBasicInfo, 0)
+	if err := dao.Orm.Do().Select(kt.Ctx, &list, sql, args); err != nil {
+		logs.Errorf("select %s resource cloud id failed, err: %v, account: %s, cloud ids: %v, rid: %s", resType, err,
+			accountID, cloudIDs, kt.Rid)
+		return nil, err
+	}
+
+	cloudIDMap := make(map[string]string, len(list))
+	for _, info := range list {
+		cloudIDMap[info.CloudID] = info.ID
+	}
+
+	return cloudIDMap, nil
+}
+
+// ListResourceBasicInfoByFilter list cloud resource basic info by filter and page, filter supports resource tag
+// rules. page is required, the results are sorted by id if page.sort is not set.
+func (dao CloudDao) ListResourceBasicInfoByFilter(kt *kit.Kit, resType enumor.CloudResourceType,
+	expr *filter.Expression, page *core.BasePage, fields ...string) ([]types.CloudResourceBasicInfo, error) {
+
+	tableName, err := resType.ConvTableName()
+	if err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	if expr == nil {
+		return nil, errf.New(errf.InvalidParameter, "filter expr is required")
+	}
+
+	// if fields are not set, select common fields.
+	if len(fields) == 0 {
+		fields = types.CommonBasicInfoFields
+	}
+
+	if page == nil {
+		return nil, errf.New(errf.InvalidParameter, "page is required")
+	}
+
+	if err = page.Validate(core.NewDefaultPageOption()); err != nil {
+		return nil, err
+	}
+
+	whereExpr, whereValue, err := expr.SQLWhereExpr(tools.TagSqlWhereOption(resType))
+	if err != nil {
+		return nil, err
+	}
+
+	pageExpr, err := types.PageSQLExpr(page, types.DefaultPageSQLOption)
+	if err != nil {
+		return nil, err
+	}
+
+	sql := fmt.Sprintf("select %s from %s %s %s", strings.Join(fields, ", "), tableName, whereExpr, pageExpr)
+
+	list := make([]types.CloudResourceBasicInfo, 0)
+	if err := dao.Orm.Do().Select(kt.Ctx, &list, sql, whereValue); err != nil {
+		logs.Errorf("select %s resource basic info failed, err: %v, expr: %v, rid: %s", resType, err, expr, kt.Rid)
+		return nil, err
+	}
+
+	for index := range list {
+		list[index].ResType = resType
+	}
+
+	return list, nil
+}
+
+// ListResourceIDsForUpdateWithTx list cloud resource ids by filter in transaction, and lock the selected rows
+// until the transaction ends, so that the following updates in the transaction take effect on exactly these rows.
+func (dao CloudDao) ListResourceIDsForUpdateWithTx(kt *kit.Kit, tx *sqlx.Tx, resType enumor.CloudResourceType,
+	expr *filter.Expression) ([]string, error) {
+
+	tableName, err := resType.ConvTableName()
+	if err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	if expr == nil {
+		return nil, errf.New(errf.InvalidParameter, "filter expr is required")
+	}
+
+	whereExpr, whereValue, err := expr.SQLWhereExpr(tools.DefaultSqlWhereOption)
+	if err != nil {
+		return nil, err
+	}
+
+	sql := fmt.Sprintf("select id from %s %s for update", tableName, whereExpr)
+
+	list := make([]types.CloudResourceBasicInfo, 0)
+	if err = dao.Orm.Txn(tx).Select(kt.Ctx, &list, sql, whereValue); err != nil {
+		logs.Errorf("select %s resource id for update failed, err: %v, expr: %v, rid: %s", resType, err, expr, kt.Rid)
+		return nil, err
+	}
+
+	ids := make([]string, len(list))
+	for idx, info := range list {
+		ids[idx] = info.ID
+	}
+
+	return ids, nil
+}
+
 // AssignResourceToBiz assign an account's cloud resource to biz, **only for ui**.
 func (dao CloudDao) AssignResourceToBiz(kt *kit.Kit, tx *sqlx.Tx, resType enumor.CloudResourceType,
 	expr *filter.Expression, bizID int64) error {
@@ -160,3 +326,67 @@ func (dao CloudDao) AssignResourceToBiz(kt *kit.Kit, tx *sqlx.Tx, resType enumor
 
 	return nil
 }
+
+// BindResourceCloudArea bind cloud resource with cloud area, only resource has bk_cloud_id field is supported.
+func (dao CloudDao) BindResourceCloudArea(kt *kit.Kit, tx *sqlx.Tx, resType enumor.CloudResourceType,
+	expr *filter.Expression, cloudID int64) error {
+
+	tableName, err := resType.ConvTableName()
+	if err != nil {
+		return errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	whereExpr, whereValue, err := expr.SQLWhereExpr(tools.DefaultSqlWhereOption)
+	if err != nil {
+		return err
+	}
+
+	sql := fmt.Sprintf(`update %s set bk_cloud_id = :bk_cloud_id %s`, tableName, whereExpr)
+
+	updateData := map[string]interface{}{
+		"bk_cloud_id": cloudID,
+	}
+
+	_, err = dao.Orm.Txn(tx).Update(kt.Ctx, sql, tools.MapMerge(updateData, whereValue))
+	if err != nil {
+		logs.ErrorJson("bind %s resource with cloud area failed, err: %v, cloud id: %d, filter: %+v, rid: %v",
+			resType, err, cloudID, expr, kt.Rid)
+		return err
+	}
+
+	return nil
+}
+
+// UpdateResourceSyncStatus update cloud resource sync status, only resource supports sync deletion guard is supported.
+func (dao CloudDao) UpdateResourceSyncStatus(kt *kit.Kit, resType enumor.CloudResourceType, expr *filter.Expression,
+	status enumor.ResSyncStatus) error {
+
+	if _, exist := enumor.SyncGuardResTypes[resType]; !exist {
+		return errf.Newf(errf.InvalidParameter, "%s does not support sync status", resType)
+	}
+
+	tableName, err := resType.ConvTableName()
+	if err != nil {
+		return errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	whereExpr, whereValue, err := expr.SQLWhereExpr(tools.DefaultSqlWhereOption)
+	if err != nil {
+		return err
+	}
+
+	sql := fmt.Sprintf(`update %s set sync_status = :sync_status %s`, tableName, whereExpr)
+
+	updateData := map[string]interface{}{
+		"sync_status": status,
+	}
+
+	_, err = dao.Orm.Do().Update(kt.Ctx, sql, tools.MapMerge(updateData, whereValue))
+	if err != nil {
+		logs.ErrorJson("update %s resource sync status failed, err: %v, status: %s, filter: %+v, rid: %v", resType,
+			err, status, expr, kt.Rid)
+		return err
+	}
+
+	return nil
+}
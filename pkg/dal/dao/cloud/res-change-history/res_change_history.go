@@ -0,0 +1,520 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+// Package reschangehistory 资源变更历史，记录资源每次变更后的版本快照。
+package reschangehistory
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"hcm/pkg/api/core"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	idgenerator "hcm/pkg/dal/dao/id-generator"
+	"hcm/pkg/dal/dao/orm"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/dal/dao/types"
+	typescloud "hcm/pkg/dal/dao/types/cloud"
+	"hcm/pkg/dal/table"
+	tablecloud "hcm/pkg/dal/table/cloud"
+	tablecvm "hcm/pkg/dal/table/cloud/cvm"
+	tabledisk "hcm/pkg/dal/table/cloud/disk"
+	tableeip "hcm/pkg/dal/table/cloud/eip"
+	tabletype "hcm/pkg/dal/table/types"
+	"hcm/pkg/dal/table/utils"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/runtime/filter"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// ResChangeHistory only used for resource change history.
+type ResChangeHistory interface {
+	RecordWithTx(kt *kit.Kit, tx *sqlx.Tx, resType enumor.CloudResourceType, action enumor.AuditAction,
+		resIDs []string) error
+	RecordByFieldWithTx(kt *kit.Kit, tx *sqlx.Tx, resType enumor.CloudResourceType, action enumor.AuditAction,
+		field string, values []string) error
+	Record(kt *kit.Kit, resType enumor.CloudResourceType, action enumor.AuditAction, resIDs []string) error
+	List(kt *kit.Kit, opt *types.ListOption) (*typescloud.ResChangeHistoryListResult, error)
+	ListAsOf(kt *kit.Kit, resType enumor.CloudResourceType, asOf string, opt *types.ListOption) (
+		*typescloud.ResChangeHistoryListResult, error)
+	ListExpiredID(kt *kit.Kit, before time.Time, limit uint) ([]string, error)
+	DeleteWithTx(kt *kit.Kit, tx *sqlx.Tx, expr *filter.Expression) error
+}
+
+var _ ResChangeHistory = new(Dao)
+
+// Dao resource change history dao.
+type Dao struct {
+	Orm   orm.Interface
+	IDGen idgenerator.IDGenInterface
+}
+
+// recordColumns 记录变更历史的资源类型及其表字段。
+var recordColumns = map[enumor.CloudResourceType]*utils.Columns{
+	enumor.CvmCloudResType:           tablecvm.TableColumns,
+	enumor.VpcCloudResType:           tablecloud.VpcColumns,
+	enumor.SubnetCloudResType:        tablecloud.SubnetColumns,
+	enumor.SecurityGroupCloudResType: tablecloud.SecurityGroupColumns,
+	enumor.DiskCloudResType:          tabledisk.DiskColumns,
+	enumor.EipCloudResType:           tableeip.EipColumns,
+}
+
+// diffIgnoredFields 比较版本差异时忽略的字段，只有这些字段变化时不生成新版本。
+var diffIgnoredFields = map[string]struct{}{
+	"updated_at": {},
+	"reviser":    {},
+}
+
+// maxVersionConflictRetry 并发记录同一资源的变更历史导致版本号冲突时的最大重试次数。
+const maxVersionConflictRetry = 3
+
+// errVersionConflict 写入的历史版本与已有版本冲突，即 (res_type, res_id, version) 唯一键冲突。
+var errVersionConflict = errors.New("resource change history version conflict")
+
+// Record resource change history with a new transaction.
+func (dao Dao) Record(kt *kit.Kit, resType enumor.CloudResourceType, action enumor.AuditAction,
+	resIDs []string) error {
+
+	_, err := dao.Orm.AutoTxn(kt, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		return nil, dao.RecordWithTx(kt, txn, resType, action, resIDs)
+	})
+	return err
+}
+
+// RecordWithTx 在同一事务中读取资源当前的数据作为新版本快照，新增、更新后调用，删除前调用。
+// 更新时如果相对上一版本没有变化，则不生成新版本。
+func (dao Dao) RecordWithTx(kt *kit.Kit, tx *sqlx.Tx, resType enumor.CloudResourceType, action enumor.AuditAction,
+	resIDs []string) error {
+
+	return dao.RecordByFieldWithTx(kt, tx, resType, action, "id", resIDs)
+}
+
+// RecordByFieldWithTx 同 RecordWithTx，按资源指定字段的值查询需要记录的资源，用于按关联关系批量变更资源的场景。
+func (dao Dao) RecordByFieldWithTx(kt *kit.Kit, tx *sqlx.Tx, resType enumor.CloudResourceType,
+	action enumor.AuditAction, field string, values []string) error {
+
+	if len(values) == 0 {
+		return nil
+	}
+
+	columns, exist := recordColumns[resType]
+	if !exist {
+		return errf.Newf(errf.InvalidParameter, "%s not support change history", resType)
+	}
+
+	if _, exist = columns.ColumnTypes()[field]; !exist {
+		return errf.Newf(errf.InvalidParameter, "%s has no field %s", resType, field)
+	}
+
+	snapshots, err := dao.listResSnapshot(kt, tx, resType, columns, field, values)
+	if err != nil {
+		return err
+	}
+
+	if len(snapshots) == 0 {
+		return nil
+	}
+
+	// 其他事务并发写入了同一资源的版本时唯一键冲突，重新读取最新版本后重试
+	for retry := 0; ; retry++ {
+		models, err := dao.buildHistoryModels(kt, tx, resType, action, snapshots, retry > 0)
+		if err != nil {
+			return err
+		}
+
+		err = dao.batchCreateWithTx(kt, tx, models)
+		if err != errVersionConflict || retry >= maxVersionConflictRetry {
+			return err
+		}
+
+		logs.Warnf("record %s change history version conflict, retry count: %d, rid: %s", resType, retry+1,
+			kt.Rid)
+	}
+}
+
+// buildHistoryModels 根据资源当前的快照和最新的历史版本生成新版本，forUpdate 为 true 时以加锁读的方式读取最新版本，
+// 用于版本冲突后读取其他事务已提交的版本。
+func (dao Dao) buildHistoryModels(kt *kit.Kit, tx *sqlx.Tx, resType enumor.CloudResourceType,
+	action enumor.AuditAction, snapshots []resSnapshot, forUpdate bool) ([]tablecloud.ResChangeHistoryTable, error) {
+
+	resIDs := make([]string, 0, len(snapshots))
+	for _, one := range snapshots {
+		resIDs = append(resIDs, one.ID)
+	}
+
+	latestMap, err := dao.listLatestVersion(kt, tx, resType, resIDs, forUpdate)
+	if err != nil {
+		return nil, err
+	}
+
+	models := make([]tablecloud.ResChangeHistoryTable, 0, len(snapshots))
+	for _, one := range snapshots {
+		current := make(map[string]interface{})
+		if err = json.Unmarshal([]byte(one.Snapshot), &current); err != nil {
+			return nil, fmt.Errorf("unmarshal %s %s snapshot failed, err: %v", resType, one.ID, err)
+		}
+
+		model := tablecloud.ResChangeHistoryTable{
+			ResType:   resType,
+			ResID:     one.ID,
+			CloudID:   fieldString(current, "cloud_id"),
+			Vendor:    enumor.Vendor(fieldString(current, "vendor")),
+			AccountID: fieldString(current, "account_id"),
+			BkBizID:   fieldInt64(current, "bk_biz_id"),
+			Version:   1,
+			Action:    action,
+			Snapshot:  one.Snapshot,
+			Rid:       kt.Rid,
+			Creator:   kt.User,
+		}
+
+		latest, exist := latestMap[one.ID]
+		if exist {
+			model.Version = latest.Version + 1
+		}
+
+		if exist && action == enumor.Update {
+			changed, err := diffSnapshot(latest.Snapshot, current)
+			if err != nil {
+				return nil, err
+			}
+
+			if len(changed) == 0 {
+				continue
+			}
+
+			model.ChangedFields, err = tabletype.NewJsonField(changed)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		models = append(models, model)
+	}
+
+	return models, nil
+}
+
+type resSnapshot struct {
+	ID       string              `db:"id"`
+	Version  uint64              `db:"version"`
+	Snapshot tabletype.JsonField `db:"snapshot"`
+}
+
+// listResSnapshot 查询资源当前数据，以 json 对象的形式返回。
+func (dao Dao) listResSnapshot(kt *kit.Kit, tx *sqlx.Tx, resType enumor.CloudResourceType, columns *utils.Columns,
+	field string, values []string) ([]resSnapshot, error) {
+
+	tableName, err := resType.ConvTableName()
+	if err != nil {
+		return nil, err
+	}
+
+	pairs := make([]string, 0)
+	for _, column := range columns.Columns() {
+		pairs = append(pairs, fmt.Sprintf("'%s', %s", column, column))
+	}
+
+	sql := fmt.Sprintf(`SELECT id, JSON_OBJECT(%s) AS snapshot FROM %s WHERE %s IN (:values)`,
+		strings.Join(pairs, ", "), tableName, field)
+
+	snapshots := make([]resSnapshot, 0)
+	if err = dao.Orm.Txn(tx).Select(kt.Ctx, &snapshots, sql, map[string]interface{}{"values": values}); err != nil {
+		logs.Errorf("select %s snapshot failed, err: %v, %s: %v, rid: %s", resType, err, field, values, kt.Rid)
+		return nil, err
+	}
+
+	return snapshots, nil
+}
+
+// listLatestVersion 查询资源最新的历史版本。
+func (dao Dao) listLatestVersion(kt *kit.Kit, tx *sqlx.Tx, resType enumor.CloudResourceType, resIDs []string,
+	forUpdate bool) (map[string]resSnapshot, error) {
+
+	sql := fmt.Sprintf(`SELECT res_id AS id, version, snapshot FROM %s WHERE (res_type, res_id, version) IN `+
+		`(SELECT res_type, res_id, MAX(version) FROM %s WHERE res_type = :res_type AND res_id IN (:res_ids) `+
+		`GROUP BY res_type, res_id)`, table.ResChangeHistoryTable, table.ResChangeHistoryTable)
+	if forUpdate {
+		sql += " FOR UPDATE"
+	}
+
+	latest := make([]resSnapshot, 0)
+	args := map[string]interface{}{"res_type": resType, "res_ids": resIDs}
+	if err := dao.Orm.Txn(tx).Select(kt.Ctx, &latest, sql, args); err != nil {
+		logs.Errorf("select %s latest change history failed, err: %v, ids: %v, rid: %s", resType, err, resIDs,
+			kt.Rid)
+		return nil, err
+	}
+
+	result := make(map[string]resSnapshot, len(latest))
+	for _, one := range latest {
+		result[one.ID] = one
+	}
+
+	return result, nil
+}
+
+// diffSnapshot 比较两个版本的快照，返回变更的字段。
+func diffSnapshot(previous tabletype.JsonField, current map[string]interface{}) (
+	map[string]map[string]interface{}, error) {
+
+	old := make(map[string]interface{})
+	if err := json.Unmarshal([]byte(previous), &old); err != nil {
+		return nil, fmt.Errorf("unmarshal previous snapshot failed, err: %v", err)
+	}
+
+	changed := make(map[string]map[string]interface{})
+	for field, value := range current {
+		if _, ignored := diffIgnoredFields[field]; ignored {
+			continue
+		}
+
+		if !reflect.DeepEqual(old[field], value) {
+			changed[field] = map[string]interface{}{"old": old[field], "new": value}
+		}
+	}
+
+	return changed, nil
+}
+
+func fieldString(snapshot map[string]interface{}, field string) string {
+	value, ok := snapshot[field].(string)
+	if !ok {
+		return ""
+	}
+
+	return value
+}
+
+func fieldInt64(snapshot map[string]interface{}, field string) int64 {
+	value, ok := snapshot[field].(float64)
+	if !ok {
+		return 0
+	}
+
+	return int64(value)
+}
+
+// batchCreateWithTx batch create resource change history with tx.
+func (dao Dao) batchCreateWithTx(kt *kit.Kit, tx *sqlx.Tx, models []tablecloud.ResChangeHistoryTable) error {
+	if len(models) == 0 {
+		return nil
+	}
+
+	ids, err := dao.IDGen.Batch(kt, table.ResChangeHistoryTable, len(models))
+	if err != nil {
+		return err
+	}
+
+	for index := range models {
+		models[index].ID = ids[index]
+
+		if err = models[index].InsertValidate(); err != nil {
+			return errf.NewFromErr(errf.InvalidParameter, err)
+		}
+	}
+
+	sql := fmt.Sprintf(`INSERT INTO %s (%s)	VALUES(%s)`, table.ResChangeHistoryTable,
+		tablecloud.ResChangeHistoryColumns.ColumnExpr(), tablecloud.ResChangeHistoryColumns.ColonNameExpr())
+
+	if err = dao.Orm.Txn(tx).BulkInsert(kt.Ctx, sql, models); err != nil {
+		if orm.IsDuplicateEntry(err) {
+			return errVersionConflict
+		}
+
+		logs.Errorf("insert %s failed, err: %v, rid: %s", table.ResChangeHistoryTable, err, kt.Rid)
+		return fmt.Errorf("insert %s failed, err: %v", table.ResChangeHistoryTable, err)
+	}
+
+	return nil
+}
+
+// List resource change history.
+func (dao Dao) List(kt *kit.Kit, opt *types.ListOption) (*typescloud.ResChangeHistoryListResult, error) {
+	if opt == nil {
+		return nil, errf.New(errf.InvalidParameter, "list resource change history options is nil")
+	}
+
+	if err := opt.Validate(filter.NewExprOption(filter.RuleFields(tablecloud.ResChangeHistoryColumns.ColumnTypes())),
+		core.NewDefaultPageOption()); err != nil {
+		return nil, err
+	}
+
+	whereExpr, whereValue, err := opt.Filter.SQLWhereExpr(tools.DefaultSqlWhereOption)
+	if err != nil {
+		return nil, err
+	}
+
+	if opt.Page.Count {
+		// this is dao count request, then do count operation only.
+		sql := fmt.Sprintf(`SELECT COUNT(*) FROM %s %s`, table.ResChangeHistoryTable, whereExpr)
+
+		count, err := dao.Orm.Do().Count(kt.Ctx, sql, whereValue)
+		if err != nil {
+			logs.ErrorJson("count resource change history failed, err: %v, filter: %s, rid: %s", err, opt.Filter,
+				kt.Rid)
+			return nil, err
+		}
+
+		return &typescloud.ResChangeHistoryListResult{Count: count}, nil
+	}
+
+	pageExpr, err := types.PageSQLExpr(opt.Page, types.DefaultPageSQLOption)
+	if err != nil {
+		return nil, err
+	}
+
+	sql := fmt.Sprintf(`SELECT %s FROM %s %s %s`, tablecloud.ResChangeHistoryColumns.FieldsNamedExpr(opt.Fields),
+		table.ResChangeHistoryTable, whereExpr, pageExpr)
+
+	details := make([]tablecloud.ResChangeHistoryTable, 0)
+	if err = dao.Orm.Do().Select(kt.Ctx, &details, sql, whereValue); err != nil {
+		logs.ErrorJson("select resource change history failed, err: %v, filter: %s, rid: %s", err, opt.Filter,
+			kt.Rid)
+		return nil, err
+	}
+
+	return &typescloud.ResChangeHistoryListResult{Details: details}, nil
+}
+
+// ListAsOf 查询指定时间点资源的状态，即每个资源在该时间点之前的最新版本，已删除的资源不返回。
+// opt.Filter 作用于每个资源在该时间点的版本上，用于筛选账号、业务等。
+func (dao Dao) ListAsOf(kt *kit.Kit, resType enumor.CloudResourceType, asOf string, opt *types.ListOption) (
+	*typescloud.ResChangeHistoryListResult, error) {
+
+	if opt == nil {
+		return nil, errf.New(errf.InvalidParameter, "list resource as of options is nil")
+	}
+
+	if err := opt.Validate(filter.NewExprOption(filter.RuleFields(tablecloud.ResChangeHistoryColumns.ColumnTypes())),
+		core.NewDefaultPageOption()); err != nil {
+		return nil, err
+	}
+
+	asOfTime, err := time.ParseInLocation(constant.TimeStdFormat, asOf, time.Local)
+	if err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	whereExpr, whereValue, err := opt.Filter.SQLWhereExpr(tools.DefaultSqlWhereOption)
+	if err != nil {
+		return nil, err
+	}
+
+	if whereValue == nil {
+		whereValue = make(map[string]interface{})
+	}
+	whereValue["as_of_res_type"] = resType
+	whereValue["as_of_time"] = asOfTime
+	whereValue["as_of_deleted_action"] = enumor.Delete
+
+	latestExpr := fmt.Sprintf(`FROM (SELECT * FROM %s WHERE (res_type, res_id, version) IN (SELECT res_type, `+
+		`res_id, MAX(version) FROM %s WHERE res_type = :as_of_res_type AND created_at <= :as_of_time GROUP BY `+
+		`res_type, res_id) AND action != :as_of_deleted_action) AS latest %s`, table.ResChangeHistoryTable,
+		table.ResChangeHistoryTable, whereExpr)
+
+	if opt.Page.Count {
+		sql := fmt.Sprintf(`SELECT COUNT(*) %s`, latestExpr)
+
+		count, err := dao.Orm.Do().Count(kt.Ctx, sql, whereValue)
+		if err != nil {
+			logs.ErrorJson("count %s as of %s failed, err: %v, filter: %s, rid: %s", resType, asOf, err,
+				opt.Filter, kt.Rid)
+			return nil, err
+		}
+
+		return &typescloud.ResChangeHistoryListResult{Count: count}, nil
+	}
+
+	pageExpr, err := types.PageSQLExpr(opt.Page, types.DefaultPageSQLOption)
+	if err != nil {
+		return nil, err
+	}
+
+	sql := fmt.Sprintf(`SELECT %s %s %s`, tablecloud.ResChangeHistoryColumns.FieldsNamedExpr(opt.Fields),
+		latestExpr, pageExpr)
+
+	details := make([]tablecloud.ResChangeHistoryTable, 0)
+	if err = dao.Orm.Do().Select(kt.Ctx, &details, sql, whereValue); err != nil {
+		logs.ErrorJson("select %s as of %s failed, err: %v, filter: %s, rid: %s", resType, asOf, err, opt.Filter,
+			kt.Rid)
+		return nil, err
+	}
+
+	return &typescloud.ResChangeHistoryListResult{Details: details}, nil
+}
+
+// ListExpiredID 查询指定时间之前已过期的历史版本，即在该时间之前已被新版本覆盖的版本，以及该时间之前已删除资源的删除版本。
+// 未删除资源的最新版本始终保留，保证该时间之后的按时间点查询结果不受影响。
+func (dao Dao) ListExpiredID(kt *kit.Kit, before time.Time, limit uint) ([]string, error) {
+	if before.IsZero() {
+		return nil, errf.New(errf.InvalidParameter, "before is required")
+	}
+
+	if limit == 0 || limit > core.DefaultMaxPageLimit {
+		return nil, errf.Newf(errf.InvalidParameter, "limit should between 1 and %d", core.DefaultMaxPageLimit)
+	}
+
+	sql := fmt.Sprintf(`SELECT id FROM %s AS h WHERE h.created_at < :before AND (h.action = :deleted_action OR `+
+		`EXISTS (SELECT 1 FROM %s AS n WHERE n.res_type = h.res_type AND n.res_id = h.res_id AND `+
+		`n.version > h.version AND n.created_at < :before)) LIMIT %d`,
+		table.ResChangeHistoryTable, table.ResChangeHistoryTable, limit)
+
+	args := map[string]interface{}{"before": before, "deleted_action": enumor.Delete}
+	details := make([]tablecloud.ResChangeHistoryTable, 0)
+	if err := dao.Orm.Do().Select(kt.Ctx, &details, sql, args); err != nil {
+		logs.Errorf("select expired resource change history failed, err: %v, before: %s, rid: %s", err, before,
+			kt.Rid)
+		return nil, err
+	}
+
+	ids := make([]string, 0, len(details))
+	for _, one := range details {
+		ids = append(ids, one.ID)
+	}
+
+	return ids, nil
+}
+
+// DeleteWithTx resource change history with tx.
+func (dao Dao) DeleteWithTx(kt *kit.Kit, tx *sqlx.Tx, expr *filter.Expression) error {
+	if expr == nil {
+		return errf.New(errf.InvalidParameter, "filter expr is required")
+	}
+
+	whereExpr, whereValue, err := expr.SQLWhereExpr(tools.DefaultSqlWhereOption)
+	if err != nil {
+		return err
+	}
+
+	sql := fmt.Sprintf(`DELETE FROM %s %s`, table.ResChangeHistoryTable, whereExpr)
+	if _, err = dao.Orm.Txn(tx).Delete(kt.Ctx, sql, whereValue); err != nil {
+		logs.ErrorJson("delete resource change history failed, err: %v, filter: %s, rid: %s", err, expr, kt.Rid)
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,223 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package reschangehistory
+
+import (
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"hcm/pkg/api/core"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/dal/dao/orm"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/dal/dao/types"
+	"hcm/pkg/dal/table"
+	"hcm/pkg/kit"
+	"hcm/pkg/runtime/filter"
+
+	"github.com/jmoiron/sqlx"
+	_ "github.com/mattn/go-sqlite3" // import sqlite drive, used to run change history sql in test.
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// testSchema 与 mysql 中 res_change_history 表字段保持一致的 sqlite 表结构。
+var testSchema = fmt.Sprintf(`create table %s
+(
+    id             varchar(64)  not null primary key,
+    res_type       varchar(64)  not null,
+    res_id         varchar(64)  not null,
+    cloud_id       varchar(255) default '',
+    vendor         varchar(16)  default '',
+    account_id     varchar(64)  default '',
+    bk_biz_id      bigint       default -1,
+    version        bigint       not null,
+    action         varchar(16)  not null,
+    snapshot       text         not null,
+    changed_fields text         not null,
+    rid            varchar(64)  default '',
+    creator        varchar(64)  not null,
+    created_at     timestamp    not null,
+    unique (res_type, res_id, version)
+)`, table.ResChangeHistoryTable)
+
+type testHistory struct {
+	id        string
+	resType   enumor.CloudResourceType
+	resID     string
+	accountID string
+	version   uint64
+	action    enumor.AuditAction
+	createdAt time.Time
+}
+
+// base 测试数据的基准时间，sqlite 按字符串比较时间，测试数据只使用整秒的本地时间。
+var base = time.Date(2023, 1, 1, 10, 0, 0, 0, time.Local)
+
+// testHistories cvm-1 创建后更新两次，cvm-2 创建后删除，cvm-3 晚于其他资源创建，disk-1 为其他类型资源。
+var testHistories = []testHistory{
+	{id: "h-01", resType: enumor.CvmCloudResType, resID: "cvm-1", accountID: "acc-1", version: 1,
+		action: enumor.Create, createdAt: base},
+	{id: "h-02", resType: enumor.CvmCloudResType, resID: "cvm-1", accountID: "acc-1", version: 2,
+		action: enumor.Update, createdAt: base.Add(2 * time.Hour)},
+	{id: "h-03", resType: enumor.CvmCloudResType, resID: "cvm-1", accountID: "acc-1", version: 3,
+		action: enumor.Update, createdAt: base.Add(4 * time.Hour)},
+	{id: "h-04", resType: enumor.CvmCloudResType, resID: "cvm-2", accountID: "acc-1", version: 1,
+		action: enumor.Create, createdAt: base},
+	{id: "h-05", resType: enumor.CvmCloudResType, resID: "cvm-2", accountID: "acc-1", version: 2,
+		action: enumor.Delete, createdAt: base.Add(3 * time.Hour)},
+	{id: "h-06", resType: enumor.CvmCloudResType, resID: "cvm-3", accountID: "acc-2", version: 1,
+		action: enumor.Create, createdAt: base.Add(5 * time.Hour)},
+	{id: "h-07", resType: enumor.DiskCloudResType, resID: "disk-1", accountID: "acc-1", version: 1,
+		action: enumor.Create, createdAt: base},
+}
+
+func newTestDao(t *testing.T) Dao {
+	db, err := sqlx.Open("sqlite3", filepath.Join(t.TempDir(), "history.db"))
+	if err != nil {
+		t.Fatalf("open sqlite db failed, err: %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+
+	if _, err = db.Exec(testSchema); err != nil {
+		t.Fatalf("create table failed, err: %v", err)
+	}
+
+	sql := fmt.Sprintf(`INSERT INTO %s (id, res_type, res_id, account_id, version, action, snapshot, changed_fields, `+
+		`creator, created_at) VALUES (?, ?, ?, ?, ?, ?, '{}', '{}', 'test', ?)`, table.ResChangeHistoryTable)
+	for _, one := range testHistories {
+		_, err = db.Exec(sql, one.id, one.resType, one.resID, one.accountID, one.version, one.action, one.createdAt)
+		if err != nil {
+			t.Fatalf("insert history %s failed, err: %v", one.id, err)
+		}
+	}
+
+	return Dao{Orm: orm.InitOrm(db, orm.MetricsRegisterer(prometheus.NewRegistry()))}
+}
+
+func TestListAsOf(t *testing.T) {
+	dao := newTestDao(t)
+
+	cases := []struct {
+		name   string
+		asOf   time.Time
+		filter *filter.Expression
+		// expect 该时间点存在的资源及其版本
+		expect map[string]uint64
+	}{
+		{name: "before all resources created", asOf: base.Add(-time.Hour), filter: tools.AllExpression(),
+			expect: map[string]uint64{}},
+		{name: "all created", asOf: base.Add(time.Hour), filter: tools.AllExpression(),
+			expect: map[string]uint64{"cvm-1": 1, "cvm-2": 1}},
+		{name: "at version created time", asOf: base.Add(2 * time.Hour), filter: tools.AllExpression(),
+			expect: map[string]uint64{"cvm-1": 2, "cvm-2": 1}},
+		{name: "deleted resource excluded", asOf: base.Add(3 * time.Hour), filter: tools.AllExpression(),
+			expect: map[string]uint64{"cvm-1": 2}},
+		{name: "latest", asOf: base.Add(6 * time.Hour), filter: tools.AllExpression(),
+			expect: map[string]uint64{"cvm-1": 3, "cvm-3": 1}},
+		{name: "filter on version as of time", asOf: base.Add(6 * time.Hour),
+			filter: tools.EqualExpression("account_id", "acc-2"), expect: map[string]uint64{"cvm-3": 1}},
+	}
+
+	for _, c := range cases {
+		asOf := c.asOf.Format(constant.TimeStdFormat)
+		opt := &types.ListOption{Filter: c.filter, Page: &core.BasePage{Limit: core.DefaultMaxPageLimit}}
+		result, err := dao.ListAsOf(kit.New(), enumor.CvmCloudResType, asOf, opt)
+		if err != nil {
+			t.Errorf("%s: list as of failed, err: %v", c.name, err)
+			continue
+		}
+
+		versions := make(map[string]uint64, len(result.Details))
+		for _, one := range result.Details {
+			if one.ResType != enumor.CvmCloudResType {
+				t.Errorf("%s: list as of returned %s history", c.name, one.ResType)
+			}
+			versions[one.ResID] = one.Version
+		}
+
+		if !reflect.DeepEqual(versions, c.expect) {
+			t.Errorf("%s: expect %v, but got %v", c.name, c.expect, versions)
+		}
+
+		opt.Page = &core.BasePage{Count: true}
+		countResult, err := dao.ListAsOf(kit.New(), enumor.CvmCloudResType, asOf, opt)
+		if err != nil {
+			t.Errorf("%s: count as of failed, err: %v", c.name, err)
+			continue
+		}
+
+		if countResult.Count != uint64(len(c.expect)) {
+			t.Errorf("%s: expect count %d, but got %d", c.name, len(c.expect), countResult.Count)
+		}
+	}
+
+	opt := &types.ListOption{Filter: tools.AllExpression(), Page: &core.BasePage{Limit: core.DefaultMaxPageLimit}}
+	if _, err := dao.ListAsOf(kit.New(), enumor.CvmCloudResType, "2023-01-01", opt); err == nil {
+		t.Errorf("list as of with invalid time should be failed")
+	}
+}
+
+func TestListExpiredID(t *testing.T) {
+	dao := newTestDao(t)
+
+	cases := []struct {
+		name   string
+		before time.Time
+		expect []string
+	}{
+		{name: "no version expired", before: base.Add(time.Hour), expect: []string{}},
+		// cvm-1 的 v1 已被 v2 覆盖，cvm-2 已删除，cvm-1 的 v2 在该时间点仍为最新版本需要保留
+		{name: "overridden and deleted", before: base.Add(3*time.Hour + 30*time.Minute),
+			expect: []string{"h-01", "h-04", "h-05"}},
+		{name: "latest versions kept", before: base.Add(10 * time.Hour),
+			expect: []string{"h-01", "h-02", "h-04", "h-05"}},
+	}
+
+	for _, c := range cases {
+		ids, err := dao.ListExpiredID(kit.New(), c.before, core.DefaultMaxPageLimit)
+		if err != nil {
+			t.Errorf("%s: list expired id failed, err: %v", c.name, err)
+			continue
+		}
+
+		sort.Strings(ids)
+		if !reflect.DeepEqual(ids, c.expect) {
+			t.Errorf("%s: expect %v, but got %v", c.name, c.expect, ids)
+		}
+	}
+
+	ids, err := dao.ListExpiredID(kit.New(), base.Add(10*time.Hour), 1)
+	if err != nil {
+		t.Fatalf("list expired id with limit failed, err: %v", err)
+	}
+
+	if len(ids) != 1 {
+		t.Errorf("expect 1 expired id with limit, but got %v", ids)
+	}
+
+	if _, err = dao.ListExpiredID(kit.New(), time.Time{}, 1); err == nil {
+		t.Errorf("list expired id without before time should be failed")
+	}
+}
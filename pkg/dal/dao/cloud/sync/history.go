@@ -0,0 +1,182 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package daosync
+
+import (
+	"fmt"
+	"time"
+
+	"hcm/pkg/api/core"
+	"hcm/pkg/criteria/errf"
+	idgenerator "hcm/pkg/dal/dao/id-generator"
+	"hcm/pkg/dal/dao/orm"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/dal/dao/types"
+	typessync "hcm/pkg/dal/dao/types/sync"
+	"hcm/pkg/dal/table"
+	tablessync "hcm/pkg/dal/table/cloud/sync"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/runtime/filter"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// AccountSyncHistory only used account sync history.
+type AccountSyncHistory interface {
+	BatchCreateWithTx(kt *kit.Kit, tx *sqlx.Tx, models []tablessync.AccountSyncHistoryTable) ([]string, error)
+	List(kt *kit.Kit, opt *types.ListOption) (*typessync.ListAccountSyncHistories, error)
+	ListExpiredID(kt *kit.Kit, before time.Time, limit uint) ([]string, error)
+	DeleteWithTx(kt *kit.Kit, tx *sqlx.Tx, expr *filter.Expression) error
+}
+
+var _ AccountSyncHistory = new(AccountSyncHistoryDao)
+
+// AccountSyncHistoryDao account sync history dao.
+type AccountSyncHistoryDao struct {
+	Orm   orm.Interface
+	IDGen idgenerator.IDGenInterface
+}
+
+// BatchCreateWithTx account sync history with tx.
+func (dao *AccountSyncHistoryDao) BatchCreateWithTx(kt *kit.Kit, tx *sqlx.Tx,
+	models []tablessync.AccountSyncHistoryTable) ([]string, error) {
+
+	ids, err := dao.IDGen.Batch(kt, table.AccountSyncHistoryTable, len(models))
+	if err != nil {
+		return nil, err
+	}
+	for index := range models {
+		models[index].ID = ids[index]
+
+		if err = models[index].InsertValidate(); err != nil {
+			return nil, err
+		}
+	}
+
+	sql := fmt.Sprintf(`INSERT INTO %s (%s)	VALUES(%s)`, table.AccountSyncHistoryTable,
+		tablessync.AccountSyncHistoryColumns.ColumnExpr(), tablessync.AccountSyncHistoryColumns.ColonNameExpr())
+
+	err = dao.Orm.Txn(tx).BulkInsert(kt.Ctx, sql, models)
+	if err != nil {
+		logs.Errorf("insert %s failed, err: %v, sql: %s, rid: %s", table.AccountSyncHistoryTable, err, sql, kt.Rid)
+		return nil, fmt.Errorf("insert %s failed, err: %v", table.AccountSyncHistoryTable, err)
+	}
+
+	return ids, nil
+}
+
+// List account sync history.
+func (dao *AccountSyncHistoryDao) List(kt *kit.Kit, opt *types.ListOption) (*typessync.ListAccountSyncHistories,
+	error) {
+
+	if opt == nil {
+		return nil, errf.New(errf.InvalidParameter, "list account sync history options is nil")
+	}
+
+	if err := opt.Validate(filter.NewExprOption(filter.RuleFields(tablessync.AccountSyncHistoryColumns.ColumnTypes())),
+		core.NewDefaultPageOption()); err != nil {
+		return nil, err
+	}
+
+	whereExpr, whereValue, err := opt.Filter.SQLWhereExpr(tools.DefaultSqlWhereOption)
+	if err != nil {
+		return nil, err
+	}
+
+	if opt.Page.Count {
+		// this is dao count request, then do count operation only.
+		sql := fmt.Sprintf(`SELECT COUNT(*) FROM %s %s`, table.AccountSyncHistoryTable, whereExpr)
+
+		count, err := dao.Orm.Do().Count(kt.Ctx, sql, whereValue)
+		if err != nil {
+			logs.ErrorJson("count account sync history failed, err: %v, filter: %s, rid: %s", err,
+				opt.Filter, kt.Rid)
+			return nil, err
+		}
+
+		return &typessync.ListAccountSyncHistories{Count: count}, nil
+	}
+
+	pageExpr, err := types.PageSQLExpr(opt.Page, types.DefaultPageSQLOption)
+	if err != nil {
+		return nil, err
+	}
+
+	sql := fmt.Sprintf(`SELECT %s FROM %s %s %s`, tablessync.AccountSyncHistoryColumns.FieldsNamedExpr(opt.Fields),
+		table.AccountSyncHistoryTable, whereExpr, pageExpr)
+
+	details := make([]tablessync.AccountSyncHistoryTable, 0)
+	if err = dao.Orm.Do().Select(kt.Ctx, &details, sql, whereValue); err != nil {
+		logs.ErrorJson("select account sync history failed, err: %v, sql: %s, filter: %v, rid: %s", err, sql,
+			opt.Filter, kt.Rid)
+		return nil, err
+	}
+
+	return &typessync.ListAccountSyncHistories{Count: 0, Details: details}, nil
+}
+
+// ListExpiredID 查询指定时间之前创建的同步历史。
+func (dao *AccountSyncHistoryDao) ListExpiredID(kt *kit.Kit, before time.Time, limit uint) ([]string, error) {
+	if before.IsZero() {
+		return nil, errf.New(errf.InvalidParameter, "before is required")
+	}
+
+	if limit == 0 || limit > core.DefaultMaxPageLimit {
+		return nil, errf.Newf(errf.InvalidParameter, "limit should between 1 and %d", core.DefaultMaxPageLimit)
+	}
+
+	sql := fmt.Sprintf(`SELECT id FROM %s WHERE created_at < :before LIMIT %d`, table.AccountSyncHistoryTable,
+		limit)
+
+	details := make([]tablessync.AccountSyncHistoryTable, 0)
+	if err := dao.Orm.Do().Select(kt.Ctx, &details, sql, map[string]interface{}{"before": before}); err != nil {
+		logs.Errorf("list expired account sync history failed, err: %v, before: %s, rid: %s", err, before,
+			kt.Rid)
+		return nil, err
+	}
+
+	ids := make([]string, 0, len(details))
+	for _, one := range details {
+		ids = append(ids, one.ID)
+	}
+
+	return ids, nil
+}
+
+// DeleteWithTx account sync history with tx.
+func (dao *AccountSyncHistoryDao) DeleteWithTx(kt *kit.Kit, tx *sqlx.Tx, filterExpr *filter.Expression) error {
+	if filterExpr == nil {
+		return errf.New(errf.InvalidParameter, "filter expr is required")
+	}
+
+	whereExpr, whereValue, err := filterExpr.SQLWhereExpr(tools.DefaultSqlWhereOption)
+	if err != nil {
+		return err
+	}
+
+	sql := fmt.Sprintf(`DELETE FROM %s %s`, table.AccountSyncHistoryTable, whereExpr)
+	if _, err = dao.Orm.Txn(tx).Delete(kt.Ctx, sql, whereValue); err != nil {
+		logs.ErrorJson("delete account sync history failed, err: %v, filter: %s, rid: %s", err, filterExpr, kt.Rid)
+		return err
+	}
+
+	return nil
+}
@@ -32,21 +32,27 @@ import (
 	"hcm/pkg/dal/dao/auth"
 	"hcm/pkg/dal/dao/cloud"
 	"hcm/pkg/dal/dao/cloud/bill"
+	bizassignrule "hcm/pkg/dal/dao/cloud/biz-assign-rule"
 	"hcm/pkg/dal/dao/cloud/cvm"
 	"hcm/pkg/dal/dao/cloud/disk"
 	diskcvmrel "hcm/pkg/dal/dao/cloud/disk-cvm-rel"
 	"hcm/pkg/dal/dao/cloud/eip"
 	eipcvmrel "hcm/pkg/dal/dao/cloud/eip-cvm-rel"
 	cimage "hcm/pkg/dal/dao/cloud/image"
+	launchtemplate "hcm/pkg/dal/dao/cloud/launch-template"
 	networkinterface "hcm/pkg/dal/dao/cloud/network-interface"
 	nicvmrel "hcm/pkg/dal/dao/cloud/network-interface-cvm-rel"
 	"hcm/pkg/dal/dao/cloud/region"
+	reschangehistory "hcm/pkg/dal/dao/cloud/res-change-history"
 	resourcegroup "hcm/pkg/dal/dao/cloud/resource-group"
+	resourcetag "hcm/pkg/dal/dao/cloud/resource-tag"
 	routetable "hcm/pkg/dal/dao/cloud/route-table"
 	securitygroup "hcm/pkg/dal/dao/cloud/security-group"
+	sgcomrel "hcm/pkg/dal/dao/cloud/security-group-common-rel"
 	sgcvmrel "hcm/pkg/dal/dao/cloud/security-group-cvm-rel"
 	daosubaccount "hcm/pkg/dal/dao/cloud/sub-account"
 	daosync "hcm/pkg/dal/dao/cloud/sync"
+	userdatatemplate "hcm/pkg/dal/dao/cloud/user-data-template"
 	"hcm/pkg/dal/dao/cloud/zone"
 	idgenerator "hcm/pkg/dal/dao/id-generator"
 	"hcm/pkg/dal/dao/orm"
@@ -67,6 +73,7 @@ type Set interface {
 	SubAccount() daosubaccount.SubAccount
 	SecurityGroup() securitygroup.SecurityGroup
 	SGCvmRel() sgcvmrel.Interface
+	SGCommonRel() sgcomrel.Interface
 	TCloudSGRule() securitygroup.TCloudSGRule
 	AwsSGRule() securitygroup.AwsSGRule
 	HuaWeiSGRule() securitygroup.HuaWeiSGRule
@@ -81,6 +88,8 @@ type Set interface {
 	AzureRegion() region.AzureRegion
 	Zone() zone.Zone
 	AccountSyncDetail() daosync.AccountSyncDetail
+	AccountSyncHistory() daosync.AccountSyncHistory
+	AccountSyncQuarantine() daosync.AccountSyncQuarantine
 	TCloudRegion() region.TCloudRegion
 	AwsRegion() region.AwsRegion
 	GcpRegion() region.GcpRegion
@@ -100,7 +109,20 @@ type Set interface {
 	AccountBillConfig() bill.Interface
 	AsyncFlow() daoasync.AsyncFlow
 	AsyncFlowTask() daoasync.AsyncFlowTask
+	AsyncFlowSchedule() daoasync.AsyncFlowSchedule
+	AsyncFlowScheduleRun() daoasync.AsyncFlowScheduleRun
+	AsyncFlowEvent() daoasync.AsyncFlowEvent
+	AsyncFlowArchive() daoasync.AsyncFlowArchive
+	AsyncFlowIdempotency() daoasync.AsyncFlowIdempotency
 	UserCollection() daouser.Interface
+	ResourceTag() resourcetag.ResourceTag
+	BizAssignRule() bizassignrule.BizAssignRule
+	BizAssignRecord() bizassignrule.BizAssignRecord
+	UserDataTemplate() userdatatemplate.UserDataTemplate
+	UserDataTemplateVersion() userdatatemplate.UserDataTemplateVersion
+	LaunchTemplate() launchtemplate.LaunchTemplate
+	LaunchTemplateVersion() launchtemplate.LaunchTemplateVersion
+	ResChangeHistory() reschangehistory.ResChangeHistory
 
 	Txn() *Txn
 }
@@ -233,6 +255,22 @@ func (s *set) AccountSyncDetail() daosync.AccountSyncDetail {
 	}
 }
 
+// AccountSyncHistory return AccountSyncHistory dao.
+func (s *set) AccountSyncHistory() daosync.AccountSyncHistory {
+	return &daosync.AccountSyncHistoryDao{
+		Orm:   s.orm,
+		IDGen: s.idGen,
+	}
+}
+
+// AccountSyncQuarantine return AccountSyncQuarantine dao.
+func (s *set) AccountSyncQuarantine() daosync.AccountSyncQuarantine {
+	return &daosync.AccountSyncQuarantineDao{
+		Orm:   s.orm,
+		IDGen: s.idGen,
+	}
+}
+
 // AzureRegion return AzureRegion dao.
 func (s *set) AzureRegion() region.AzureRegion {
 	return &region.AzureRegionDao{
@@ -322,6 +360,13 @@ func (s *set) SGCvmRel() sgcvmrel.Interface {
 	}
 }
 
+// SGCommonRel return security group common rel dao.
+func (s *set) SGCommonRel() sgcomrel.Interface {
+	return &sgcomrel.Dao{
+		Orm: s.orm,
+	}
+}
+
 // TCloudSGRule return tcloud security group rule dao.
 func (s *set) TCloudSGRule() securitygroup.TCloudSGRule {
 	return &securitygroup.TCloudSGRuleDao{
@@ -470,6 +515,62 @@ func (s *set) UserCollection() daouser.Interface {
 	}
 }
 
+// ResourceTag returns resource tag dao.
+func (s *set) ResourceTag() resourcetag.ResourceTag {
+	return &resourcetag.Dao{
+		Orm:   s.orm,
+		IDGen: s.idGen,
+	}
+}
+
+// BizAssignRule returns biz assign rule dao.
+func (s *set) BizAssignRule() bizassignrule.BizAssignRule {
+	return &bizassignrule.Dao{
+		Orm:   s.orm,
+		IDGen: s.idGen,
+	}
+}
+
+// BizAssignRecord returns biz assign record dao.
+func (s *set) BizAssignRecord() bizassignrule.BizAssignRecord {
+	return &bizassignrule.RecordDao{
+		Orm:   s.orm,
+		IDGen: s.idGen,
+	}
+}
+
+// UserDataTemplate returns user data template dao.
+func (s *set) UserDataTemplate() userdatatemplate.UserDataTemplate {
+	return &userdatatemplate.Dao{
+		Orm:   s.orm,
+		IDGen: s.idGen,
+	}
+}
+
+// UserDataTemplateVersion returns user data template version dao.
+func (s *set) UserDataTemplateVersion() userdatatemplate.UserDataTemplateVersion {
+	return &userdatatemplate.VersionDao{
+		Orm:   s.orm,
+		IDGen: s.idGen,
+	}
+}
+
+// LaunchTemplate returns launch template dao.
+func (s *set) LaunchTemplate() launchtemplate.LaunchTemplate {
+	return &launchtemplate.Dao{
+		Orm:   s.orm,
+		IDGen: s.idGen,
+	}
+}
+
+// LaunchTemplateVersion returns launch template version dao.
+func (s *set) LaunchTemplateVersion() launchtemplate.LaunchTemplateVersion {
+	return &launchtemplate.VersionDao{
+		Orm:   s.orm,
+		IDGen: s.idGen,
+	}
+}
+
 // AsyncFlow return AsyncFlow dao.
 func (s *set) AsyncFlow() daoasync.AsyncFlow {
 	return &daoasync.AsyncFlowDao{
@@ -485,3 +586,49 @@ func (s *set) AsyncFlowTask() daoasync.AsyncFlowTask {
 		IDGen: s.idGen,
 	}
 }
+
+// AsyncFlowSchedule return AsyncFlowSchedule dao.
+func (s *set) AsyncFlowSchedule() daoasync.AsyncFlowSchedule {
+	return &daoasync.AsyncFlowScheduleDao{
+		Orm:   s.orm,
+		IDGen: s.idGen,
+	}
+}
+
+// AsyncFlowScheduleRun return AsyncFlowScheduleRun dao.
+func (s *set) AsyncFlowScheduleRun() daoasync.AsyncFlowScheduleRun {
+	return &daoasync.AsyncFlowScheduleRunDao{
+		Orm:   s.orm,
+		IDGen: s.idGen,
+	}
+}
+
+// AsyncFlowEvent return AsyncFlowEvent dao.
+func (s *set) AsyncFlowEvent() daoasync.AsyncFlowEvent {
+	return &daoasync.AsyncFlowEventDao{
+		Orm:   s.orm,
+		IDGen: s.idGen,
+	}
+}
+
+// AsyncFlowArchive return AsyncFlowArchive dao.
+func (s *set) AsyncFlowArchive() daoasync.AsyncFlowArchive {
+	return &daoasync.AsyncFlowArchiveDao{
+		Orm: s.orm,
+	}
+}
+
+// AsyncFlowIdempotency return AsyncFlowIdempotency dao.
+func (s *set) AsyncFlowIdempotency() daoasync.AsyncFlowIdempotency {
+	return &daoasync.AsyncFlowIdempotencyDao{
+		Orm: s.orm,
+	}
+}
+
+// ResChangeHistory returns resource change history dao.
+func (s *set) ResChangeHistory() reschangehistory.ResChangeHistory {
+	return &reschangehistory.Dao{
+		Orm:   s.orm,
+		IDGen: s.idGen,
+	}
+}
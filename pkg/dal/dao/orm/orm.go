@@ -33,6 +33,7 @@ import (
 	"hcm/pkg/kit"
 	"hcm/pkg/logs"
 
+	"github.com/go-sql-driver/mysql"
 	"github.com/jmoiron/sqlx"
 	prm "github.com/prometheus/client_golang/prometheus"
 	"golang.org/x/time/rate"
@@ -162,6 +163,19 @@ type TxnOption struct{}
 // ErrRetryTransaction defines errors that need to retry transaction, like deadlock error in upsert scenario
 var ErrRetryTransaction = errors.New("RETRY TRANSACTION ERROR")
 
+// mysqlErrDupEntry mysql duplicate entry error number, returned when insert violates an unique key.
+const mysqlErrDupEntry = 1062
+
+// IsDuplicateEntry returns if the error is caused by inserting data that violates an unique key.
+func IsDuplicateEntry(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	if !errors.As(err, &mysqlErr) {
+		return false
+	}
+
+	return mysqlErr.Number == mysqlErrDupEntry
+}
+
 // AutoTxn is a wrapper to do all the transaction operations as follows:
 // 1. auto launch the transaction
 // 2. process the logics, which is a callback run function
@@ -22,6 +22,8 @@ package tools
 import (
 	"fmt"
 
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/dal/table"
 	"hcm/pkg/runtime/filter"
 )
 
@@ -57,6 +59,17 @@ func ContainersExpression(fieldName string, values interface{}) *filter.Expressi
 	}
 }
 
+// ResourceTagExpression 生成资源标签的过滤条件，即res_type=resType and res_id in resIDs
+func ResourceTagExpression(resType enumor.CloudResourceType, resIDs []string) *filter.Expression {
+	return &filter.Expression{
+		Op: filter.And,
+		Rules: []filter.RuleFactory{
+			filter.AtomRule{Field: "res_type", Op: filter.Equal.Factory(), Value: resType},
+			filter.AtomRule{Field: "res_id", Op: filter.In.Factory(), Value: resIDs},
+		},
+	}
+}
+
 // AllExpression 生成全量查询filter。
 func AllExpression() *filter.Expression {
 	return &filter.Expression{
@@ -70,6 +83,17 @@ var DefaultSqlWhereOption = &filter.SQLWhereOption{
 	Priority: filter.Priority{"id"},
 }
 
+// TagSqlWhereOption define sql where option which support resource tag rules("tags.xxx").
+func TagSqlWhereOption(resType enumor.CloudResourceType) *filter.SQLWhereOption {
+	return &filter.SQLWhereOption{
+		Priority: filter.Priority{"id"},
+		TagOption: &filter.TagOption{
+			Table:   string(table.ResourceTagTable),
+			ResType: string(resType),
+		},
+	}
+}
+
 // And merge expressions using 'and' operation.
 func And(rules ...filter.RuleFactory) (*filter.Expression, error) {
 	if len(rules) == 0 {
@@ -20,6 +20,8 @@
 package typesasync
 
 import (
+	"time"
+
 	"hcm/pkg/criteria/enumor"
 	"hcm/pkg/criteria/validator"
 	tableasync "hcm/pkg/dal/table/async"
@@ -38,9 +40,68 @@ type UpdateFlowInfo struct {
 	Target enumor.FlowState   `json:"target" validate:"required"`
 	Reason *tableasync.Reason `json:"reason" validate:"omitempty"`
 	Worker string             `json:"worker" validate:"omitempty"`
+	// ResetWorker 为true时清空任务流的执行节点，此时忽略Worker
+	ResetWorker bool `json:"reset_worker" validate:"omitempty"`
 }
 
 // Validate UpdateFlowInfo.
 func (info *UpdateFlowInfo) Validate() error {
 	return validator.Validate.Struct(info)
 }
+
+// RestartFlowInfo define restart flow info.
+type RestartFlowInfo struct {
+	ID         string           `json:"id" validate:"required"`
+	Source     enumor.FlowState `json:"source" validate:"required"`
+	TaskSource enumor.TaskState `json:"task_source" validate:"required"`
+}
+
+// Validate RestartFlowInfo.
+func (info *RestartFlowInfo) Validate() error {
+	return validator.Validate.Struct(info)
+}
+
+// ListAsyncFlowSchedules list async flow schedules.
+type ListAsyncFlowSchedules struct {
+	Count   uint64                              `json:"count,omitempty"`
+	Details []tableasync.AsyncFlowScheduleTable `json:"details,omitempty"`
+}
+
+// ListAsyncFlowScheduleRuns list async flow schedule runs.
+type ListAsyncFlowScheduleRuns struct {
+	Count   uint64                                 `json:"count,omitempty"`
+	Details []tableasync.AsyncFlowScheduleRunTable `json:"details,omitempty"`
+}
+
+// ListAsyncFlowArchives list async flow archives.
+type ListAsyncFlowArchives struct {
+	Count   uint64                             `json:"count,omitempty"`
+	Details []tableasync.AsyncFlowArchiveTable `json:"details,omitempty"`
+}
+
+// ListAsyncFlowIdempotencies list async flow idempotency keys.
+type ListAsyncFlowIdempotencies struct {
+	Count   uint64                                 `json:"count,omitempty"`
+	Details []tableasync.AsyncFlowIdempotencyTable `json:"details,omitempty"`
+}
+
+// ListAsyncFlowEvents list async flow events.
+type ListAsyncFlowEvents struct {
+	Count   uint64                           `json:"count,omitempty"`
+	Details []tableasync.AsyncFlowEventTable `json:"details,omitempty"`
+}
+
+// UpdateScheduleNextRunInfo define update flow schedule next run time info, only enabled schedule which next run
+// time equal to source can be updated.
+type UpdateScheduleNextRunInfo struct {
+	ID     string    `json:"id" validate:"required"`
+	Source time.Time `json:"source" validate:"required"`
+	Target time.Time `json:"target" validate:"required"`
+	// Pause 为true时同时暂停定时任务流，用于 cron 表达式不会再被满足的场景
+	Pause bool `json:"pause" validate:"omitempty"`
+}
+
+// Validate UpdateScheduleNextRunInfo.
+func (info *UpdateScheduleNextRunInfo) Validate() error {
+	return validator.Validate.Struct(info)
+}
@@ -32,6 +32,12 @@ type CloudResourceBasicInfo struct {
 	// these fields are basic info for some resource, needs to be specified explicitly.
 	Region        string `json:"region" db:"region"`
 	RecycleStatus string `json:"recycle_status" db:"recycle_status"`
+	CloudID       string `json:"cloud_id" db:"cloud_id"`
+	Name          string `json:"name" db:"name"`
+	BkCloudID     int64  `json:"bk_cloud_id" db:"bk_cloud_id"`
+	Zone          string `json:"zone" db:"zone"`
+	// SyncStatus 资源的同步状态，只有支持同步删除保护的资源有该字段
+	SyncStatus string `json:"sync_status" db:"sync_status"`
 }
 
 // CommonBasicInfoFields defines common cloud resource basic info fields.
@@ -28,3 +28,15 @@ type ListAccountSyncDetails struct {
 	Count   uint64                              `json:"count,omitempty"`
 	Details []tablessync.AccountSyncDetailTable `json:"details,omitempty"`
 }
+
+// ListAccountSyncHistories list account sync histories.
+type ListAccountSyncHistories struct {
+	Count   uint64                               `json:"count,omitempty"`
+	Details []tablessync.AccountSyncHistoryTable `json:"details,omitempty"`
+}
+
+// ListAccountSyncQuarantines list account sync quarantines.
+type ListAccountSyncQuarantines struct {
+	Count   uint64                                  `json:"count,omitempty"`
+	Details []tablessync.AccountSyncQuarantineTable `json:"details,omitempty"`
+}
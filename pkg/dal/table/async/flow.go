@@ -21,6 +21,7 @@ package tableasync
 
 import (
 	"errors"
+	"time"
 
 	"hcm/pkg/criteria/enumor"
 	"hcm/pkg/criteria/validator"
@@ -41,6 +42,12 @@ var AsyncFlowTableColumnDescriptor = utils.ColumnDescriptors{
 	{Column: "memo", NamedC: "memo", Type: enumor.String},
 	{Column: "share_data", NamedC: "share_data", Type: enumor.Json},
 	{Column: "worker", NamedC: "worker", Type: enumor.String},
+	{Column: "run_at", NamedC: "run_at", Type: enumor.Time},
+	{Column: "rollback_policy", NamedC: "rollback_policy", Type: enumor.String},
+	{Column: "priority", NamedC: "priority", Type: enumor.String},
+	{Column: "tenant", NamedC: "tenant", Type: enumor.String},
+	{Column: "app_code", NamedC: "app_code", Type: enumor.String},
+	{Column: "webhooks", NamedC: "webhooks", Type: enumor.Json},
 	{Column: "creator", NamedC: "creator", Type: enumor.String},
 	{Column: "reviser", NamedC: "reviser", Type: enumor.String},
 	{Column: "created_at", NamedC: "created_at", Type: enumor.Time},
@@ -49,17 +56,23 @@ var AsyncFlowTableColumnDescriptor = utils.ColumnDescriptors{
 
 // AsyncFlowTable define async_flow table.
 type AsyncFlowTable struct {
-	ID        string           `db:"id" json:"id" validate:"lte=64"`
-	Name      enumor.FlowName  `db:"name" json:"name"`
-	State     enumor.FlowState `db:"state" json:"state"`
-	Reason    *Reason          `db:"reason" json:"reason"`
-	ShareData *ShareData       `db:"share_data" json:"share_data"`
-	Memo      string           `db:"memo" json:"memo"`
-	Worker    *string          `db:"worker" json:"worker"`
-	Creator   string           `db:"creator" json:"creator" validate:"lte=64"`
-	Reviser   string           `db:"reviser" json:"reviser" validate:"lte=64"`
-	CreatedAt types.Time       `db:"created_at" json:"created_at" validate:"excluded_unless"`
-	UpdatedAt types.Time       `db:"updated_at" json:"updated_at" validate:"excluded_unless"`
+	ID             string                    `db:"id" json:"id" validate:"lte=64"`
+	Name           enumor.FlowName           `db:"name" json:"name"`
+	State          enumor.FlowState          `db:"state" json:"state"`
+	Reason         *Reason                   `db:"reason" json:"reason"`
+	ShareData      *ShareData                `db:"share_data" json:"share_data"`
+	Memo           string                    `db:"memo" json:"memo"`
+	Worker         *string                   `db:"worker" json:"worker"`
+	RunAt          time.Time                 `db:"run_at" json:"run_at"`
+	RollbackPolicy enumor.FlowRollbackPolicy `db:"rollback_policy" json:"rollback_policy"`
+	Priority       enumor.FlowPriority       `db:"priority" json:"priority"`
+	Tenant         string                    `db:"tenant" json:"tenant" validate:"lte=64"`
+	AppCode        string                    `db:"app_code" json:"app_code" validate:"lte=64"`
+	Webhooks       WebhookTargets            `db:"webhooks" json:"webhooks"`
+	Creator        string                    `db:"creator" json:"creator" validate:"lte=64"`
+	Reviser        string                    `db:"reviser" json:"reviser" validate:"lte=64"`
+	CreatedAt      types.Time                `db:"created_at" json:"created_at" validate:"excluded_unless"`
+	UpdatedAt      types.Time                `db:"updated_at" json:"updated_at" validate:"excluded_unless"`
 }
 
 // TableName return async_flow table name.
@@ -86,6 +99,18 @@ func (a AsyncFlowTable) InsertValidate() error {
 		return errors.New("state is required")
 	}
 
+	if a.RunAt.IsZero() {
+		return errors.New("run_at is required")
+	}
+
+	if err := a.RollbackPolicy.Validate(); err != nil {
+		return err
+	}
+
+	if err := a.Priority.Validate(); err != nil {
+		return err
+	}
+
 	if len(a.Creator) == 0 {
 		return errors.New("creator is required")
 	}
@@ -112,5 +137,29 @@ func (a AsyncFlowTable) UpdateValidate() error {
 		return errors.New("creator can not update")
 	}
 
+	if !a.RunAt.IsZero() {
+		return errors.New("run_at can not update")
+	}
+
+	if len(a.RollbackPolicy) != 0 {
+		return errors.New("rollback_policy can not update")
+	}
+
+	if len(a.Priority) != 0 {
+		return errors.New("priority can not update")
+	}
+
+	if len(a.Tenant) != 0 {
+		return errors.New("tenant can not update")
+	}
+
+	if len(a.AppCode) != 0 {
+		return errors.New("app_code can not update")
+	}
+
+	if len(a.Webhooks) != 0 {
+		return errors.New("webhooks can not update")
+	}
+
 	return nil
 }
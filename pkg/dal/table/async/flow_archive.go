@@ -0,0 +1,118 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package tableasync
+
+import (
+	"database/sql/driver"
+	"errors"
+	"time"
+
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+	"hcm/pkg/dal/table"
+	"hcm/pkg/dal/table/types"
+	"hcm/pkg/dal/table/utils"
+)
+
+// AsyncFlowArchiveColumns defines all the async_flow_archive table's columns.
+var AsyncFlowArchiveColumns = utils.MergeColumns(nil, AsyncFlowArchiveTableColumnDescriptor)
+
+// AsyncFlowArchiveTableColumnDescriptor is async_flow_archive's column descriptors.
+var AsyncFlowArchiveTableColumnDescriptor = utils.ColumnDescriptors{
+	{Column: "id", NamedC: "id", Type: enumor.String},
+	{Column: "name", NamedC: "name", Type: enumor.String},
+	{Column: "state", NamedC: "state", Type: enumor.String},
+	{Column: "tenant", NamedC: "tenant", Type: enumor.String},
+	{Column: "app_code", NamedC: "app_code", Type: enumor.String},
+	{Column: "detail", NamedC: "detail", Type: enumor.Json},
+	{Column: "flow_created_at", NamedC: "flow_created_at", Type: enumor.Time},
+	{Column: "finished_at", NamedC: "finished_at", Type: enumor.Time},
+	{Column: "archived_at", NamedC: "archived_at", Type: enumor.Time},
+}
+
+// AsyncFlowArchiveTable define async_flow_archive table, 保存已结束且超过保留时间的任务流快照，
+// 任务流归档后会从 async_flow、async_flow_task、async_flow_event 表中删除。
+type AsyncFlowArchiveTable struct {
+	// ID 任务流ID
+	ID      string           `db:"id" json:"id" validate:"lte=64"`
+	Name    enumor.FlowName  `db:"name" json:"name"`
+	State   enumor.FlowState `db:"state" json:"state"`
+	Tenant  string           `db:"tenant" json:"tenant" validate:"lte=64"`
+	AppCode string           `db:"app_code" json:"app_code" validate:"lte=64"`
+	// Detail 归档时的任务流、任务及任务流事件
+	Detail *FlowArchiveDetail `db:"detail" json:"detail" validate:"-"`
+	// FlowCreatedAt 任务流创建时间
+	FlowCreatedAt time.Time `db:"flow_created_at" json:"flow_created_at"`
+	// FinishedAt 任务流结束时间，即任务流最后更新时间
+	FinishedAt time.Time `db:"finished_at" json:"finished_at"`
+	ArchivedAt time.Time `db:"archived_at" json:"archived_at"`
+}
+
+// TableName return async_flow_archive table name.
+func (a AsyncFlowArchiveTable) TableName() table.Name {
+	return table.AsyncFlowArchiveTable
+}
+
+// InsertValidate async_flow_archive table when insert.
+func (a AsyncFlowArchiveTable) InsertValidate() error {
+	// length validate.
+	if err := validator.Validate.Struct(a); err != nil {
+		return err
+	}
+
+	if len(a.ID) == 0 {
+		return errors.New("id is required")
+	}
+
+	if len(a.Name) == 0 {
+		return errors.New("name is required")
+	}
+
+	if len(a.State) == 0 {
+		return errors.New("state is required")
+	}
+
+	if a.Detail == nil {
+		return errors.New("detail is required")
+	}
+
+	if a.ArchivedAt.IsZero() {
+		return errors.New("archived_at is required")
+	}
+
+	return nil
+}
+
+// FlowArchiveDetail define archived flow with its tasks and events.
+type FlowArchiveDetail struct {
+	Flow   AsyncFlowTable        `json:"flow"`
+	Tasks  []AsyncFlowTaskTable  `json:"tasks"`
+	Events []AsyncFlowEventTable `json:"events"`
+}
+
+// Scan is used to decode raw message which is read from db into FlowArchiveDetail.
+func (d *FlowArchiveDetail) Scan(raw interface{}) error {
+	return types.Scan(raw, d)
+}
+
+// Value encode the FlowArchiveDetail to a json raw, so that it can be stored to db with json raw.
+func (d FlowArchiveDetail) Value() (driver.Value, error) {
+	return types.Value(d)
+}
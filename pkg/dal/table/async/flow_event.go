@@ -0,0 +1,113 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package tableasync
+
+import (
+	"errors"
+	"time"
+
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+	"hcm/pkg/dal/table"
+	"hcm/pkg/dal/table/types"
+	"hcm/pkg/dal/table/utils"
+)
+
+// AsyncFlowEventColumns defines all the async_flow_event table's columns.
+var AsyncFlowEventColumns = utils.MergeColumns(nil, AsyncFlowEventTableColumnDescriptor)
+
+// AsyncFlowEventTableColumnDescriptor is async_flow_event's column descriptors.
+var AsyncFlowEventTableColumnDescriptor = utils.ColumnDescriptors{
+	{Column: "id", NamedC: "id", Type: enumor.String},
+	{Column: "flow_id", NamedC: "flow_id", Type: enumor.String},
+	{Column: "event", NamedC: "event", Type: enumor.String},
+	{Column: "state", NamedC: "state", Type: enumor.String},
+	{Column: "attempts", NamedC: "attempts", Type: enumor.Numeric},
+	{Column: "next_attempt_at", NamedC: "next_attempt_at", Type: enumor.Time},
+	{Column: "deliveries", NamedC: "deliveries", Type: enumor.Json},
+	{Column: "created_at", NamedC: "created_at", Type: enumor.Time},
+	{Column: "updated_at", NamedC: "updated_at", Type: enumor.Time},
+}
+
+// AsyncFlowEventTable define async_flow_event table, 记录任务流生命周期事件及其 webhook 投递日志。
+type AsyncFlowEventTable struct {
+	ID            string                `db:"id" json:"id" validate:"lte=64"`
+	FlowID        string                `db:"flow_id" json:"flow_id" validate:"lte=64"`
+	Event         enumor.FlowEventType  `db:"event" json:"event"`
+	State         enumor.FlowEventState `db:"state" json:"state"`
+	Attempts      uint                  `db:"attempts" json:"attempts"`
+	NextAttemptAt time.Time             `db:"next_attempt_at" json:"next_attempt_at"`
+	Deliveries    WebhookDeliveries     `db:"deliveries" json:"deliveries"`
+	CreatedAt     types.Time            `db:"created_at" json:"created_at" validate:"excluded_unless"`
+	UpdatedAt     types.Time            `db:"updated_at" json:"updated_at" validate:"excluded_unless"`
+}
+
+// TableName return async_flow_event table name.
+func (a AsyncFlowEventTable) TableName() table.Name {
+	return table.AsyncFlowEventTable
+}
+
+// InsertValidate async_flow_event table when insert.
+func (a AsyncFlowEventTable) InsertValidate() error {
+	// length validate.
+	if err := validator.Validate.Struct(a); err != nil {
+		return err
+	}
+
+	if len(a.ID) == 0 {
+		return errors.New("id is required")
+	}
+
+	if len(a.FlowID) == 0 {
+		return errors.New("flow_id is required")
+	}
+
+	if err := a.Event.Validate(); err != nil {
+		return err
+	}
+
+	if len(a.State) == 0 {
+		return errors.New("state is required")
+	}
+
+	if a.NextAttemptAt.IsZero() {
+		return errors.New("next_attempt_at is required")
+	}
+
+	return nil
+}
+
+// UpdateValidate async_flow_event table when update.
+func (a AsyncFlowEventTable) UpdateValidate() error {
+	// length validate.
+	if err := validator.Validate.Struct(a); err != nil {
+		return err
+	}
+
+	if len(a.FlowID) != 0 {
+		return errors.New("flow_id can not update")
+	}
+
+	if len(a.Event) != 0 {
+		return errors.New("event can not update")
+	}
+
+	return nil
+}
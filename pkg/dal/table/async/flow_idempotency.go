@@ -0,0 +1,92 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package tableasync
+
+import (
+	"errors"
+	"time"
+
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+	"hcm/pkg/dal/table"
+	"hcm/pkg/dal/table/types"
+	"hcm/pkg/dal/table/utils"
+)
+
+// AsyncFlowIdempotencyColumns defines all the async_flow_idempotency table's columns.
+var AsyncFlowIdempotencyColumns = utils.MergeColumns(nil, AsyncFlowIdempotencyTableColumnDescriptor)
+
+// AsyncFlowIdempotencyTableColumnDescriptor is async_flow_idempotency's column descriptors.
+var AsyncFlowIdempotencyTableColumnDescriptor = utils.ColumnDescriptors{
+	{Column: "id", NamedC: "id", Type: enumor.String},
+	{Column: "app_code", NamedC: "app_code", Type: enumor.String},
+	{Column: "idempotency_key", NamedC: "idempotency_key", Type: enumor.String},
+	{Column: "payload_hash", NamedC: "payload_hash", Type: enumor.String},
+	{Column: "expired_at", NamedC: "expired_at", Type: enumor.Time},
+	{Column: "created_at", NamedC: "created_at", Type: enumor.Time},
+}
+
+// AsyncFlowIdempotencyTable define async_flow_idempotency table, 记录创建任务流时使用的幂等键，
+// 同一应用下幂等键唯一，过期后可以被重新使用。
+type AsyncFlowIdempotencyTable struct {
+	// ID 幂等键对应的任务流ID
+	ID             string `db:"id" json:"id" validate:"lte=64"`
+	AppCode        string `db:"app_code" json:"app_code" validate:"lte=64"`
+	IdempotencyKey string `db:"idempotency_key" json:"idempotency_key" validate:"lte=128"`
+	// PayloadHash 创建任务流请求内容的 sha256 摘要
+	PayloadHash string     `db:"payload_hash" json:"payload_hash" validate:"lte=64"`
+	ExpiredAt   time.Time  `db:"expired_at" json:"expired_at"`
+	CreatedAt   types.Time `db:"created_at" json:"created_at" validate:"excluded_unless"`
+}
+
+// TableName return async_flow_idempotency table name.
+func (a AsyncFlowIdempotencyTable) TableName() table.Name {
+	return table.AsyncFlowIdempotencyTable
+}
+
+// InsertValidate async_flow_idempotency table when insert.
+func (a AsyncFlowIdempotencyTable) InsertValidate() error {
+	// length validate.
+	if err := validator.Validate.Struct(a); err != nil {
+		return err
+	}
+
+	if len(a.ID) == 0 {
+		return errors.New("id is required")
+	}
+
+	if len(a.AppCode) == 0 {
+		return errors.New("app_code is required")
+	}
+
+	if len(a.IdempotencyKey) == 0 {
+		return errors.New("idempotency_key is required")
+	}
+
+	if len(a.PayloadHash) == 0 {
+		return errors.New("payload_hash is required")
+	}
+
+	if a.ExpiredAt.IsZero() {
+		return errors.New("expired_at is required")
+	}
+
+	return nil
+}
@@ -0,0 +1,251 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package tableasync
+
+import (
+	"database/sql/driver"
+	"errors"
+	"time"
+
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+	"hcm/pkg/dal/table"
+	"hcm/pkg/dal/table/types"
+	"hcm/pkg/dal/table/utils"
+)
+
+// AsyncFlowScheduleColumns defines all the async_flow_schedule table's columns.
+var AsyncFlowScheduleColumns = utils.MergeColumns(nil, AsyncFlowScheduleTableColumnDescriptor)
+
+// AsyncFlowScheduleTableColumnDescriptor is async_flow_schedule's column descriptors.
+var AsyncFlowScheduleTableColumnDescriptor = utils.ColumnDescriptors{
+	{Column: "id", NamedC: "id", Type: enumor.String},
+	{Column: "name", NamedC: "name", Type: enumor.String},
+	{Column: "cron", NamedC: "cron", Type: enumor.String},
+	{Column: "state", NamedC: "state", Type: enumor.String},
+	{Column: "missed_policy", NamedC: "missed_policy", Type: enumor.String},
+	{Column: "flow", NamedC: "flow", Type: enumor.Json},
+	{Column: "next_run_at", NamedC: "next_run_at", Type: enumor.Time},
+	{Column: "memo", NamedC: "memo", Type: enumor.String},
+	{Column: "creator", NamedC: "creator", Type: enumor.String},
+	{Column: "reviser", NamedC: "reviser", Type: enumor.String},
+	{Column: "created_at", NamedC: "created_at", Type: enumor.Time},
+	{Column: "updated_at", NamedC: "updated_at", Type: enumor.Time},
+}
+
+// AsyncFlowScheduleTable define async_flow_schedule table, 定时任务流，由主节点按照 cron 表达式生成任务流实例。
+type AsyncFlowScheduleTable struct {
+	ID           string                   `db:"id" json:"id" validate:"lte=64"`
+	Name         string                   `db:"name" json:"name" validate:"lte=64"`
+	Cron         string                   `db:"cron" json:"cron" validate:"lte=64"`
+	State        enumor.FlowScheduleState `db:"state" json:"state"`
+	MissedPolicy enumor.MissedRunPolicy   `db:"missed_policy" json:"missed_policy"`
+	Flow         *ScheduleFlow            `db:"flow" json:"flow"`
+	NextRunAt    time.Time                `db:"next_run_at" json:"next_run_at"`
+	Memo         string                   `db:"memo" json:"memo" validate:"lte=255"`
+	Creator      string                   `db:"creator" json:"creator" validate:"lte=64"`
+	Reviser      string                   `db:"reviser" json:"reviser" validate:"lte=64"`
+	CreatedAt    types.Time               `db:"created_at" json:"created_at" validate:"excluded_unless"`
+	UpdatedAt    types.Time               `db:"updated_at" json:"updated_at" validate:"excluded_unless"`
+}
+
+// TableName return async_flow_schedule table name.
+func (a AsyncFlowScheduleTable) TableName() table.Name {
+	return table.AsyncFlowScheduleTable
+}
+
+// InsertValidate async_flow_schedule table when insert.
+func (a AsyncFlowScheduleTable) InsertValidate() error {
+	// length validate.
+	if err := validator.Validate.Struct(a); err != nil {
+		return err
+	}
+
+	if len(a.ID) == 0 {
+		return errors.New("id is required")
+	}
+
+	if len(a.Name) == 0 {
+		return errors.New("name is required")
+	}
+
+	if len(a.Cron) == 0 {
+		return errors.New("cron is required")
+	}
+
+	if err := a.State.Validate(); err != nil {
+		return err
+	}
+
+	if err := a.MissedPolicy.Validate(); err != nil {
+		return err
+	}
+
+	if a.Flow == nil {
+		return errors.New("flow is required")
+	}
+
+	if a.NextRunAt.IsZero() {
+		return errors.New("next_run_at is required")
+	}
+
+	if len(a.Creator) == 0 {
+		return errors.New("creator is required")
+	}
+
+	if len(a.Reviser) == 0 {
+		return errors.New("reviser is required")
+	}
+
+	return nil
+}
+
+// UpdateValidate async_flow_schedule table when update.
+func (a AsyncFlowScheduleTable) UpdateValidate() error {
+	// length validate.
+	if err := validator.Validate.Struct(a); err != nil {
+		return err
+	}
+
+	if len(a.Name) != 0 {
+		return errors.New("name can not update")
+	}
+
+	if a.Flow != nil {
+		return errors.New("flow can not update")
+	}
+
+	if len(a.State) != 0 {
+		if err := a.State.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if len(a.MissedPolicy) != 0 {
+		if err := a.MissedPolicy.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if len(a.Creator) != 0 {
+		return errors.New("creator can not update")
+	}
+
+	return nil
+}
+
+// ScheduleFlow 定时任务流每次生成任务流实例时使用的任务流定义
+type ScheduleFlow struct {
+	Name      enumor.FlowName    `json:"name"`
+	Memo      string             `json:"memo"`
+	ShareData *ShareData         `json:"share_data"`
+	Tasks     []ScheduleFlowTask `json:"tasks"`
+	// RollbackPolicy 任务流失败后的回滚策略
+	RollbackPolicy enumor.FlowRollbackPolicy `json:"rollback_policy"`
+	// Priority 任务流优先级
+	Priority enumor.FlowPriority `json:"priority"`
+	// Tenant 任务流所属租户
+	Tenant string `json:"tenant"`
+	// AppCode 创建定时任务流的应用，生成的任务流按照该应用订阅的 webhook 推送事件
+	AppCode string `json:"app_code"`
+	// Webhooks 任务流自定义的 webhook 订阅
+	Webhooks WebhookTargets `json:"webhooks,omitempty"`
+}
+
+// ScheduleFlowTask 定时任务流的任务定义
+type ScheduleFlowTask struct {
+	ActionID   string            `json:"action_id"`
+	ActionName enumor.ActionName `json:"action_name"`
+	Params     types.JsonField   `json:"params"`
+	Retry      *Retry            `json:"retry"`
+	DependOn   []string          `json:"depend_on"`
+}
+
+// Scan is used to decode raw message which is read from db into ScheduleFlow.
+func (f *ScheduleFlow) Scan(raw interface{}) error {
+	return types.Scan(raw, f)
+}
+
+// Value encode the ScheduleFlow to a json raw, so that it can be stored to db with json raw.
+func (f ScheduleFlow) Value() (driver.Value, error) {
+	return types.Value(f)
+}
+
+// AsyncFlowScheduleRunColumns defines all the async_flow_schedule_run table's columns.
+var AsyncFlowScheduleRunColumns = utils.MergeColumns(nil, AsyncFlowScheduleRunTableColumnDescriptor)
+
+// AsyncFlowScheduleRunTableColumnDescriptor is async_flow_schedule_run's column descriptors.
+var AsyncFlowScheduleRunTableColumnDescriptor = utils.ColumnDescriptors{
+	{Column: "id", NamedC: "id", Type: enumor.String},
+	{Column: "schedule_id", NamedC: "schedule_id", Type: enumor.String},
+	{Column: "plan_at", NamedC: "plan_at", Type: enumor.Time},
+	{Column: "state", NamedC: "state", Type: enumor.String},
+	{Column: "flow_id", NamedC: "flow_id", Type: enumor.String},
+	{Column: "reason", NamedC: "reason", Type: enumor.Json},
+	{Column: "creator", NamedC: "creator", Type: enumor.String},
+	{Column: "created_at", NamedC: "created_at", Type: enumor.Time},
+}
+
+// AsyncFlowScheduleRunTable define async_flow_schedule_run table, 记录定时任务流每次计划执行的结果。
+type AsyncFlowScheduleRunTable struct {
+	ID         string                      `db:"id" json:"id" validate:"lte=64"`
+	ScheduleID string                      `db:"schedule_id" json:"schedule_id" validate:"lte=64"`
+	PlanAt     time.Time                   `db:"plan_at" json:"plan_at"`
+	State      enumor.FlowScheduleRunState `db:"state" json:"state"`
+	FlowID     string                      `db:"flow_id" json:"flow_id" validate:"lte=64"`
+	Reason     *Reason                     `db:"reason" json:"reason"`
+	Creator    string                      `db:"creator" json:"creator" validate:"lte=64"`
+	CreatedAt  types.Time                  `db:"created_at" json:"created_at" validate:"excluded_unless"`
+}
+
+// TableName return async_flow_schedule_run table name.
+func (a AsyncFlowScheduleRunTable) TableName() table.Name {
+	return table.AsyncFlowScheduleRunTable
+}
+
+// InsertValidate async_flow_schedule_run table when insert.
+func (a AsyncFlowScheduleRunTable) InsertValidate() error {
+	// length validate.
+	if err := validator.Validate.Struct(a); err != nil {
+		return err
+	}
+
+	if len(a.ID) == 0 {
+		return errors.New("id is required")
+	}
+
+	if len(a.ScheduleID) == 0 {
+		return errors.New("schedule_id is required")
+	}
+
+	if a.PlanAt.IsZero() {
+		return errors.New("plan_at is required")
+	}
+
+	if len(a.State) == 0 {
+		return errors.New("state is required")
+	}
+
+	if len(a.Creator) == 0 {
+		return errors.New("creator is required")
+	}
+
+	return nil
+}
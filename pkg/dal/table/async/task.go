@@ -21,6 +21,7 @@ package tableasync
 
 import (
 	"errors"
+	"time"
 
 	"hcm/pkg/criteria/enumor"
 	"hcm/pkg/criteria/validator"
@@ -45,6 +46,9 @@ var AsyncFlowTaskTableColumnDescriptor = utils.ColumnDescriptors{
 	{Column: "state", NamedC: "state", Type: enumor.String},
 	{Column: "reason", NamedC: "reason", Type: enumor.Json},
 	{Column: "result", NamedC: "result", Type: enumor.Json},
+	{Column: "stat", NamedC: "stat", Type: enumor.Json},
+	{Column: "progress", NamedC: "progress", Type: enumor.Json},
+	{Column: "heartbeat_at", NamedC: "heartbeat_at", Type: enumor.Time},
 	{Column: "creator", NamedC: "creator", Type: enumor.String},
 	{Column: "reviser", NamedC: "reviser", Type: enumor.String},
 	{Column: "created_at", NamedC: "created_at", Type: enumor.Time},
@@ -64,10 +68,14 @@ type AsyncFlowTaskTable struct {
 	State      enumor.TaskState  `db:"state" json:"state"`
 	Reason     *Reason           `db:"reason" json:"reason"`
 	Result     types.JsonField   `db:"result" json:"result"`
-	Creator    string            `db:"creator" json:"creator" validate:"lte=64"`
-	Reviser    string            `db:"reviser" json:"reviser" validate:"lte=64"`
-	CreatedAt  types.Time        `db:"created_at" json:"created_at" validate:"excluded_unless"`
-	UpdatedAt  types.Time        `db:"updated_at" json:"updated_at" validate:"excluded_unless"`
+	Stat       *TaskStat         `db:"stat" json:"stat"`
+	Progress   *TaskProgress     `db:"progress" json:"progress"`
+	// HeartbeatAt 任务最近一次上报心跳的时间，执行中的任务长时间未上报心跳时，认为任务执行节点已经异常
+	HeartbeatAt time.Time  `db:"heartbeat_at" json:"heartbeat_at"`
+	Creator     string     `db:"creator" json:"creator" validate:"lte=64"`
+	Reviser     string     `db:"reviser" json:"reviser" validate:"lte=64"`
+	CreatedAt   types.Time `db:"created_at" json:"created_at" validate:"excluded_unless"`
+	UpdatedAt   types.Time `db:"updated_at" json:"updated_at" validate:"excluded_unless"`
 }
 
 // TableName return async_flow_task table name.
@@ -98,6 +106,10 @@ func (a AsyncFlowTaskTable) InsertValidate() error {
 		return errors.New("action_name is required")
 	}
 
+	if a.HeartbeatAt.IsZero() {
+		return errors.New("heartbeat_at is required")
+	}
+
 	if len(a.Creator) == 0 {
 		return errors.New("creator is required")
 	}
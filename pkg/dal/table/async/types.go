@@ -21,7 +21,11 @@ package tableasync
 
 import (
 	"database/sql/driver"
+	"fmt"
+	"strings"
 
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
 	"hcm/pkg/dal/table/types"
 )
 
@@ -39,3 +43,144 @@ func (d *Reason) Scan(raw interface{}) error {
 func (d Reason) Value() (driver.Value, error) {
 	return types.Value(d)
 }
+
+// TaskStat define async task execute statistics.
+type TaskStat struct {
+	// StartedAt 任务本次开始执行的时间
+	StartedAt string `json:"started_at,omitempty"`
+	// EndedAt 任务本次执行结束的时间，任务未结束时为空
+	EndedAt string `json:"ended_at,omitempty"`
+	// RunCount 任务执行次数，包括首次执行和重试
+	RunCount uint `json:"run_count"`
+}
+
+// Scan is used to decode raw message which is read from db into TaskStat.
+func (d *TaskStat) Scan(raw interface{}) error {
+	return types.Scan(raw, d)
+}
+
+// Value encode the TaskStat to a json raw, so that it can be stored to db with json raw.
+func (d TaskStat) Value() (driver.Value, error) {
+	return types.Value(d)
+}
+
+// TaskProgress define async task progress reported by task itself.
+type TaskProgress struct {
+	// Percent 任务执行进度百分比
+	Percent uint `json:"percent"`
+	// Message 任务执行进度描述
+	Message string `json:"message"`
+	// Checkpoint 任务自定义的断点数据，任务重试时可以据此从断点处继续执行
+	Checkpoint types.JsonField `json:"checkpoint,omitempty"`
+}
+
+// Scan is used to decode raw message which is read from db into TaskProgress.
+func (d *TaskProgress) Scan(raw interface{}) error {
+	return types.Scan(raw, d)
+}
+
+// Value encode the TaskProgress to a json raw, so that it can be stored to db with json raw.
+func (d TaskProgress) Value() (driver.Value, error) {
+	return types.Value(d)
+}
+
+// WebhookTarget define webhook target which subscribes flow lifecycle events.
+type WebhookTarget struct {
+	// URL 接收任务流事件的地址，只支持 http 和 https 协议
+	URL string `json:"url" validate:"required,url,max=255"`
+	// Events 订阅的任务流事件，为空时订阅全部事件
+	Events []enumor.FlowEventType `json:"events" validate:"omitempty"`
+	// Secret 回调请求签名使用的密钥，为空时使用 Notifier 的默认密钥，查询任务流时不返回
+	Secret string `json:"secret,omitempty" validate:"omitempty,max=128"`
+}
+
+// Validate WebhookTarget.
+func (t WebhookTarget) Validate() error {
+	if err := validator.Validate.Struct(t); err != nil {
+		return err
+	}
+
+	if !strings.HasPrefix(t.URL, "http://") && !strings.HasPrefix(t.URL, "https://") {
+		return fmt.Errorf("webhook url: %s should be http or https protocol", t.URL)
+	}
+
+	for _, one := range t.Events {
+		if err := one.Validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Subscribed 判断是否订阅了该任务流事件
+func (t WebhookTarget) Subscribed(event enumor.FlowEventType) bool {
+	if len(t.Events) == 0 {
+		return true
+	}
+
+	for _, one := range t.Events {
+		if one == event {
+			return true
+		}
+	}
+
+	return false
+}
+
+// WebhookTargets define webhook targets of flow.
+type WebhookTargets []WebhookTarget
+
+// WithoutSecret 返回去掉签名密钥的 webhook 订阅，用于对外返回任务流详情
+func (t WebhookTargets) WithoutSecret() WebhookTargets {
+	if t == nil {
+		return nil
+	}
+
+	result := make(WebhookTargets, 0, len(t))
+	for _, one := range t {
+		one.Secret = ""
+		result = append(result, one)
+	}
+
+	return result
+}
+
+// Scan is used to decode raw message which is read from db into WebhookTargets.
+func (t *WebhookTargets) Scan(raw interface{}) error {
+	return types.Scan(raw, t)
+}
+
+// Value encode the WebhookTargets to a json raw, so that it can be stored to db with json raw.
+func (t WebhookTargets) Value() (driver.Value, error) {
+	return types.Value(t)
+}
+
+// WebhookDelivery define one webhook delivery attempt of flow event.
+type WebhookDelivery struct {
+	// URL 投递地址
+	URL string `json:"url"`
+	// Attempt 第几次投递
+	Attempt uint `json:"attempt"`
+	// Success 是否投递成功，接收方返回 2xx 状态码视为投递成功
+	Success bool `json:"success"`
+	// StatusCode 接收方返回的 http 状态码，请求失败时为 0
+	StatusCode int `json:"status_code"`
+	// Message 投递失败原因
+	Message string `json:"message,omitempty"`
+	// DeliveredAt 投递时间
+	DeliveredAt string `json:"delivered_at"`
+}
+
+// WebhookDeliveries define webhook delivery log of flow event.
+type WebhookDeliveries []WebhookDelivery
+
+// Scan is used to decode raw message which is read from db into WebhookDeliveries.
+func (d *WebhookDeliveries) Scan(raw interface{}) error {
+	return types.Scan(raw, d)
+}
+
+// Value encode the WebhookDeliveries to a json raw, so that it can be stored to db with json raw.
+func (d WebhookDeliveries) Value() (driver.Value, error) {
+	return types.Value(d)
+}
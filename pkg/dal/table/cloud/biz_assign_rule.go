@@ -0,0 +1,224 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package cloud
+
+import (
+	"errors"
+
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+	"hcm/pkg/dal/table"
+	"hcm/pkg/dal/table/types"
+	"hcm/pkg/dal/table/utils"
+)
+
+// BizAssignRuleColumns defines all the biz assign rule table's columns.
+var BizAssignRuleColumns = utils.MergeColumns(nil, BizAssignRuleColumnDescriptor)
+
+// BizAssignRuleColumnDescriptor is biz assign rule's column descriptors.
+var BizAssignRuleColumnDescriptor = utils.ColumnDescriptors{
+	{Column: "id", NamedC: "id", Type: enumor.String},
+	{Column: "name", NamedC: "name", Type: enumor.String},
+	{Column: "priority", NamedC: "priority", Type: enumor.Numeric},
+	{Column: "enabled", NamedC: "enabled", Type: enumor.Boolean},
+	{Column: "vendor", NamedC: "vendor", Type: enumor.String},
+	{Column: "account_id", NamedC: "account_id", Type: enumor.String},
+	{Column: "regions", NamedC: "regions", Type: enumor.Json},
+	{Column: "cloud_vpc_ids", NamedC: "cloud_vpc_ids", Type: enumor.Json},
+	{Column: "name_pattern", NamedC: "name_pattern", Type: enumor.String},
+	{Column: "tags", NamedC: "tags", Type: enumor.Json},
+	{Column: "res_types", NamedC: "res_types", Type: enumor.Json},
+	{Column: "bk_biz_id", NamedC: "bk_biz_id", Type: enumor.Numeric},
+	{Column: "bk_cloud_id", NamedC: "bk_cloud_id", Type: enumor.Numeric},
+	{Column: "memo", NamedC: "memo", Type: enumor.String},
+	{Column: "creator", NamedC: "creator", Type: enumor.String},
+	{Column: "reviser", NamedC: "reviser", Type: enumor.String},
+	{Column: "created_at", NamedC: "created_at", Type: enumor.Time},
+	{Column: "updated_at", NamedC: "updated_at", Type: enumor.Time},
+}
+
+// BizAssignRuleTable define biz assign rule table, 根据规则将未分配的云资源自动分配到业务。
+type BizAssignRuleTable struct {
+	ID          string            `db:"id" validate:"lte=64" json:"id"`
+	Name        string            `db:"name" validate:"lte=255" json:"name"`
+	Priority    *int64            `db:"priority" json:"priority"`
+	Enabled     *bool             `db:"enabled" json:"enabled"`
+	Vendor      enumor.Vendor     `db:"vendor" validate:"lte=16" json:"vendor"`
+	AccountID   string            `db:"account_id" validate:"lte=64" json:"account_id"`
+	Regions     types.StringArray `db:"regions" json:"regions"`
+	CloudVpcIDs types.StringArray `db:"cloud_vpc_ids" json:"cloud_vpc_ids"`
+	NamePattern *string           `db:"name_pattern" validate:"omitempty,lte=255" json:"name_pattern"`
+	Tags        types.JsonField   `db:"tags" json:"tags"`
+	ResTypes    types.StringArray `db:"res_types" json:"res_types"`
+	BkBizID     int64             `db:"bk_biz_id" json:"bk_biz_id"`
+	BkCloudID   int64             `db:"bk_cloud_id" json:"bk_cloud_id"`
+	Memo        *string           `db:"memo" validate:"omitempty,lte=255" json:"memo"`
+	Creator     string            `db:"creator" validate:"lte=64" json:"creator"`
+	Reviser     string            `db:"reviser" validate:"lte=64" json:"reviser"`
+	CreatedAt   types.Time        `db:"created_at" validate:"excluded_unless" json:"created_at"`
+	UpdatedAt   types.Time        `db:"updated_at" validate:"excluded_unless" json:"updated_at"`
+}
+
+// TableName return biz assign rule table name.
+func (t BizAssignRuleTable) TableName() table.Name {
+	return table.BizAssignRuleTable
+}
+
+// InsertValidate biz assign rule table when insert.
+func (t BizAssignRuleTable) InsertValidate() error {
+	// length validate.
+	if err := validator.Validate.Struct(t); err != nil {
+		return err
+	}
+
+	if len(t.ID) == 0 {
+		return errors.New("id is required")
+	}
+
+	if len(t.Name) == 0 {
+		return errors.New("name is required")
+	}
+
+	if err := t.Vendor.Validate(); err != nil {
+		return err
+	}
+
+	if len(t.AccountID) == 0 {
+		return errors.New("account_id is required")
+	}
+
+	if len(t.ResTypes) == 0 {
+		return errors.New("res_types is required")
+	}
+
+	if t.BkBizID <= 0 {
+		return errors.New("bk_biz_id should be > 0")
+	}
+
+	if len(t.Creator) == 0 {
+		return errors.New("creator is required")
+	}
+
+	if len(t.Reviser) == 0 {
+		return errors.New("reviser is required")
+	}
+
+	if len(t.CreatedAt) != 0 {
+		return errors.New("created_at can not set")
+	}
+
+	if len(t.UpdatedAt) != 0 {
+		return errors.New("updated_at can not set")
+	}
+
+	return nil
+}
+
+// UpdateValidate biz assign rule table when update.
+func (t BizAssignRuleTable) UpdateValidate() error {
+	// length validate.
+	if err := validator.Validate.Struct(t); err != nil {
+		return err
+	}
+
+	if len(t.Vendor) != 0 {
+		return errors.New("vendor can not update")
+	}
+
+	if len(t.AccountID) != 0 {
+		return errors.New("account_id can not update")
+	}
+
+	if len(t.Creator) != 0 {
+		return errors.New("creator can not update")
+	}
+
+	return nil
+}
+
+// BizAssignRecordColumns defines all the biz assign record table's columns.
+var BizAssignRecordColumns = utils.MergeColumns(nil, BizAssignRecordColumnDescriptor)
+
+// BizAssignRecordColumnDescriptor is biz assign record's column descriptors.
+var BizAssignRecordColumnDescriptor = utils.ColumnDescriptors{
+	{Column: "id", NamedC: "id", Type: enumor.String},
+	{Column: "rule_id", NamedC: "rule_id", Type: enumor.String},
+	{Column: "vendor", NamedC: "vendor", Type: enumor.String},
+	{Column: "account_id", NamedC: "account_id", Type: enumor.String},
+	{Column: "res_type", NamedC: "res_type", Type: enumor.String},
+	{Column: "res_id", NamedC: "res_id", Type: enumor.String},
+	{Column: "bk_biz_id", NamedC: "bk_biz_id", Type: enumor.Numeric},
+	{Column: "bk_cloud_id", NamedC: "bk_cloud_id", Type: enumor.Numeric},
+	{Column: "creator", NamedC: "creator", Type: enumor.String},
+	{Column: "created_at", NamedC: "created_at", Type: enumor.Time},
+}
+
+// BizAssignRecordTable define biz assign record table, 记录由规则触发的资源分配。
+type BizAssignRecordTable struct {
+	ID        string                   `db:"id" validate:"lte=64" json:"id"`
+	RuleID    string                   `db:"rule_id" validate:"lte=64" json:"rule_id"`
+	Vendor    enumor.Vendor            `db:"vendor" validate:"lte=16" json:"vendor"`
+	AccountID string                   `db:"account_id" validate:"lte=64" json:"account_id"`
+	ResType   enumor.CloudResourceType `db:"res_type" validate:"lte=64" json:"res_type"`
+	ResID     string                   `db:"res_id" validate:"lte=64" json:"res_id"`
+	BkBizID   int64                    `db:"bk_biz_id" json:"bk_biz_id"`
+	BkCloudID int64                    `db:"bk_cloud_id" json:"bk_cloud_id"`
+	Creator   string                   `db:"creator" validate:"lte=64" json:"creator"`
+	CreatedAt types.Time               `db:"created_at" validate:"excluded_unless" json:"created_at"`
+}
+
+// TableName return biz assign record table name.
+func (t BizAssignRecordTable) TableName() table.Name {
+	return table.BizAssignRecordTable
+}
+
+// InsertValidate biz assign record table when insert.
+func (t BizAssignRecordTable) InsertValidate() error {
+	// length validate.
+	if err := validator.Validate.Struct(t); err != nil {
+		return err
+	}
+
+	if len(t.ID) == 0 {
+		return errors.New("id is required")
+	}
+
+	if len(t.RuleID) == 0 {
+		return errors.New("rule_id is required")
+	}
+
+	if len(t.ResType) == 0 {
+		return errors.New("res_type is required")
+	}
+
+	if len(t.ResID) == 0 {
+		return errors.New("res_id is required")
+	}
+
+	if len(t.Creator) == 0 {
+		return errors.New("creator is required")
+	}
+
+	if len(t.CreatedAt) != 0 {
+		return errors.New("created_at can not set")
+	}
+
+	return nil
+}
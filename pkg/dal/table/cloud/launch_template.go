@@ -0,0 +1,217 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+package cloud
+
+import (
+	"errors"
+
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+	"hcm/pkg/dal/table"
+	"hcm/pkg/dal/table/types"
+	"hcm/pkg/dal/table/utils"
+)
+
+// LaunchTemplateColumns defines all the launch template table's columns.
+var LaunchTemplateColumns = utils.MergeColumns(nil, LaunchTemplateColumnDescriptor)
+
+// LaunchTemplateColumnDescriptor is launch template's column descriptors.
+var LaunchTemplateColumnDescriptor = utils.ColumnDescriptors{
+	{Column: "id", NamedC: "id", Type: enumor.String},
+	{Column: "name", NamedC: "name", Type: enumor.String},
+	{Column: "vendor", NamedC: "vendor", Type: enumor.String},
+	{Column: "bk_biz_id", NamedC: "bk_biz_id", Type: enumor.Numeric},
+	{Column: "account_id", NamedC: "account_id", Type: enumor.String},
+	{Column: "region", NamedC: "region", Type: enumor.String},
+	{Column: "latest_version", NamedC: "latest_version", Type: enumor.Numeric},
+	{Column: "memo", NamedC: "memo", Type: enumor.String},
+	{Column: "creator", NamedC: "creator", Type: enumor.String},
+	{Column: "reviser", NamedC: "reviser", Type: enumor.String},
+	{Column: "created_at", NamedC: "created_at", Type: enumor.Time},
+	{Column: "updated_at", NamedC: "updated_at", Type: enumor.Time},
+}
+
+// LaunchTemplateTable define launch template table, 业务下的主机启动模版，模版绑定账号和地域。
+type LaunchTemplateTable struct {
+	ID            string        `db:"id" validate:"lte=64" json:"id"`
+	Name          string        `db:"name" validate:"lte=255" json:"name"`
+	Vendor        enumor.Vendor `db:"vendor" validate:"lte=16" json:"vendor"`
+	BkBizID       int64         `db:"bk_biz_id" json:"bk_biz_id"`
+	AccountID     string        `db:"account_id" validate:"lte=64" json:"account_id"`
+	Region        string        `db:"region" validate:"lte=255" json:"region"`
+	LatestVersion int64         `db:"latest_version" json:"latest_version"`
+	Memo          *string       `db:"memo" validate:"omitempty,lte=255" json:"memo"`
+	Creator       string        `db:"creator" validate:"lte=64" json:"creator"`
+	Reviser       string        `db:"reviser" validate:"lte=64" json:"reviser"`
+	CreatedAt     types.Time    `db:"created_at" validate:"excluded_unless" json:"created_at"`
+	UpdatedAt     types.Time    `db:"updated_at" validate:"excluded_unless" json:"updated_at"`
+}
+
+// TableName return launch template table name.
+func (t LaunchTemplateTable) TableName() table.Name {
+	return table.LaunchTemplateTable
+}
+
+// InsertValidate launch template table when insert.
+func (t LaunchTemplateTable) InsertValidate() error {
+	// length validate.
+	if err := validator.Validate.Struct(t); err != nil {
+		return err
+	}
+
+	if len(t.ID) == 0 {
+		return errors.New("id is required")
+	}
+
+	if len(t.Name) == 0 {
+		return errors.New("name is required")
+	}
+
+	if err := t.Vendor.Validate(); err != nil {
+		return err
+	}
+
+	if t.BkBizID <= 0 {
+		return errors.New("bk_biz_id should be > 0")
+	}
+
+	if len(t.AccountID) == 0 {
+		return errors.New("account_id is required")
+	}
+
+	if len(t.Region) == 0 {
+		return errors.New("region is required")
+	}
+
+	if t.LatestVersion <= 0 {
+		return errors.New("latest_version should be > 0")
+	}
+
+	if len(t.Creator) == 0 {
+		return errors.New("creator is required")
+	}
+
+	if len(t.Reviser) == 0 {
+		return errors.New("reviser is required")
+	}
+
+	if len(t.CreatedAt) != 0 {
+		return errors.New("created_at can not set")
+	}
+
+	if len(t.UpdatedAt) != 0 {
+		return errors.New("updated_at can not set")
+	}
+
+	return nil
+}
+
+// UpdateValidate launch template table when update.
+func (t LaunchTemplateTable) UpdateValidate() error {
+	// length validate.
+	if err := validator.Validate.Struct(t); err != nil {
+		return err
+	}
+
+	if len(t.Vendor) != 0 {
+		return errors.New("vendor can not update")
+	}
+
+	if t.BkBizID != 0 {
+		return errors.New("bk_biz_id can not update")
+	}
+
+	if len(t.AccountID) != 0 {
+		return errors.New("account_id can not update")
+	}
+
+	if len(t.Region) != 0 {
+		return errors.New("region can not update")
+	}
+
+	if len(t.Creator) != 0 {
+		return errors.New("creator can not update")
+	}
+
+	return nil
+}
+
+// LaunchTemplateVersionColumns defines all the launch template version table's columns.
+var LaunchTemplateVersionColumns = utils.MergeColumns(nil, LaunchTemplateVersionColumnDescriptor)
+
+// LaunchTemplateVersionColumnDescriptor is launch template version's column descriptors.
+var LaunchTemplateVersionColumnDescriptor = utils.ColumnDescriptors{
+	{Column: "id", NamedC: "id", Type: enumor.String},
+	{Column: "template_id", NamedC: "template_id", Type: enumor.String},
+	{Column: "version", NamedC: "version", Type: enumor.Numeric},
+	{Column: "spec", NamedC: "spec", Type: enumor.Json},
+	{Column: "memo", NamedC: "memo", Type: enumor.String},
+	{Column: "creator", NamedC: "creator", Type: enumor.String},
+	{Column: "created_at", NamedC: "created_at", Type: enumor.Time},
+}
+
+// LaunchTemplateVersionTable define launch template version table, 模版配置每次变更都会生成新的版本，版本内容不可修改。
+type LaunchTemplateVersionTable struct {
+	ID         string          `db:"id" validate:"lte=64" json:"id"`
+	TemplateID string          `db:"template_id" validate:"lte=64" json:"template_id"`
+	Version    int64           `db:"version" json:"version"`
+	Spec       types.JsonField `db:"spec" json:"spec"`
+	Memo       *string         `db:"memo" validate:"omitempty,lte=255" json:"memo"`
+	Creator    string          `db:"creator" validate:"lte=64" json:"creator"`
+	CreatedAt  types.Time      `db:"created_at" validate:"excluded_unless" json:"created_at"`
+}
+
+// TableName return launch template version table name.
+func (t LaunchTemplateVersionTable) TableName() table.Name {
+	return table.LaunchTemplateVersionTable
+}
+
+// InsertValidate launch template version table when insert.
+func (t LaunchTemplateVersionTable) InsertValidate() error {
+	// length validate.
+	if err := validator.Validate.Struct(t); err != nil {
+		return err
+	}
+
+	if len(t.ID) == 0 {
+		return errors.New("id is required")
+	}
+
+	if len(t.TemplateID) == 0 {
+		return errors.New("template_id is required")
+	}
+
+	if t.Version <= 0 {
+		return errors.New("version should be > 0")
+	}
+
+	if t.Spec.IsEmpty() {
+		return errors.New("spec is required")
+	}
+
+	if len(t.Creator) == 0 {
+		return errors.New("creator is required")
+	}
+
+	if len(t.CreatedAt) != 0 {
+		return errors.New("created_at can not set")
+	}
+
+	return nil
+}
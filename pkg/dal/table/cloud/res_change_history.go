@@ -0,0 +1,112 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+package cloud
+
+import (
+	"errors"
+
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+	"hcm/pkg/dal/table"
+	"hcm/pkg/dal/table/types"
+	"hcm/pkg/dal/table/utils"
+)
+
+// ResChangeHistoryColumns defines all the res_change_history table's columns.
+var ResChangeHistoryColumns = utils.MergeColumns(nil, ResChangeHistoryColumnDescriptor)
+
+// ResChangeHistoryColumnDescriptor is res_change_history's column descriptors.
+var ResChangeHistoryColumnDescriptor = utils.ColumnDescriptors{
+	{Column: "id", NamedC: "id", Type: enumor.String},
+	{Column: "res_type", NamedC: "res_type", Type: enumor.String},
+	{Column: "res_id", NamedC: "res_id", Type: enumor.String},
+	{Column: "cloud_id", NamedC: "cloud_id", Type: enumor.String},
+	{Column: "vendor", NamedC: "vendor", Type: enumor.String},
+	{Column: "account_id", NamedC: "account_id", Type: enumor.String},
+	{Column: "bk_biz_id", NamedC: "bk_biz_id", Type: enumor.Numeric},
+	{Column: "version", NamedC: "version", Type: enumor.Numeric},
+	{Column: "action", NamedC: "action", Type: enumor.String},
+	{Column: "snapshot", NamedC: "snapshot", Type: enumor.Json},
+	{Column: "changed_fields", NamedC: "changed_fields", Type: enumor.Json},
+	{Column: "rid", NamedC: "rid", Type: enumor.String},
+	{Column: "creator", NamedC: "creator", Type: enumor.String},
+	{Column: "created_at", NamedC: "created_at", Type: enumor.Time},
+}
+
+// ResChangeHistoryTable define res_change_history table, 每条记录为资源的一个版本快照，资源每次新增、变更、删除都会生成一个新版本。
+type ResChangeHistoryTable struct {
+	ID        string                   `db:"id" validate:"lte=64" json:"id"`
+	ResType   enumor.CloudResourceType `db:"res_type" validate:"lte=64" json:"res_type"`
+	ResID     string                   `db:"res_id" validate:"lte=64" json:"res_id"`
+	CloudID   string                   `db:"cloud_id" validate:"lte=255" json:"cloud_id"`
+	Vendor    enumor.Vendor            `db:"vendor" validate:"lte=16" json:"vendor"`
+	AccountID string                   `db:"account_id" validate:"lte=64" json:"account_id"`
+	BkBizID   int64                    `db:"bk_biz_id" json:"bk_biz_id"`
+	Version   uint64                   `db:"version" json:"version"`
+	Action    enumor.AuditAction       `db:"action" validate:"lte=16" json:"action"`
+	// Snapshot 该版本资源的完整快照。
+	Snapshot types.JsonField `db:"snapshot" json:"snapshot"`
+	// ChangedFields 相对上一版本变更的字段，格式为 {"field": {"old": xx, "new": xx}}，新增和删除时为空。
+	ChangedFields types.JsonField `db:"changed_fields" json:"changed_fields"`
+	Rid           string          `db:"rid" validate:"lte=64" json:"rid"`
+	Creator       string          `db:"creator" validate:"lte=64" json:"creator"`
+	CreatedAt     types.Time      `db:"created_at" validate:"excluded_unless" json:"created_at"`
+}
+
+// TableName return res_change_history table name.
+func (t ResChangeHistoryTable) TableName() table.Name {
+	return table.ResChangeHistoryTable
+}
+
+// InsertValidate res_change_history table when insert.
+func (t ResChangeHistoryTable) InsertValidate() error {
+	if err := validator.Validate.Struct(t); err != nil {
+		return err
+	}
+
+	if len(t.ID) == 0 {
+		return errors.New("id is required")
+	}
+
+	if len(t.ResType) == 0 {
+		return errors.New("res_type is required")
+	}
+
+	if len(t.ResID) == 0 {
+		return errors.New("res_id is required")
+	}
+
+	if t.Version == 0 {
+		return errors.New("version is required")
+	}
+
+	if len(t.Action) == 0 {
+		return errors.New("action is required")
+	}
+
+	if len(t.Snapshot) == 0 {
+		return errors.New("snapshot is required")
+	}
+
+	if len(t.Creator) == 0 {
+		return errors.New("creator is required")
+	}
+
+	return nil
+}
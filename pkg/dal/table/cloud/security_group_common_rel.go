@@ -0,0 +1,64 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package cloud
+
+import (
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+	"hcm/pkg/dal/table"
+	"hcm/pkg/dal/table/types"
+	"hcm/pkg/dal/table/utils"
+)
+
+// SecurityGroupCommonRelColumns defines all the security group common rel table's columns.
+var SecurityGroupCommonRelColumns = utils.MergeColumns(utils.InsertWithoutPrimaryID,
+	SecurityGroupCommonRelColumnDescriptor)
+
+// SecurityGroupCommonRelColumnDescriptor is security group common rel table column descriptors.
+var SecurityGroupCommonRelColumnDescriptor = utils.ColumnDescriptors{
+	{Column: "id", NamedC: "id", Type: enumor.Numeric},
+	{Column: "vendor", NamedC: "vendor", Type: enumor.String},
+	{Column: "res_type", NamedC: "res_type", Type: enumor.String},
+	{Column: "res_id", NamedC: "res_id", Type: enumor.String},
+	{Column: "security_group_id", NamedC: "security_group_id", Type: enumor.String},
+	{Column: "creator", NamedC: "creator", Type: enumor.String},
+	{Column: "created_at", NamedC: "created_at", Type: enumor.Time},
+}
+
+// SecurityGroupCommonRelTable define security group and common resource (subnet, network interface...) rel table.
+type SecurityGroupCommonRelTable struct {
+	ID              uint64                   `db:"id" validate:"required" json:"id"`
+	Vendor          enumor.Vendor            `db:"vendor" validate:"required,lte=16" json:"vendor"`
+	ResType         enumor.CloudResourceType `db:"res_type" validate:"required,lte=64" json:"res_type"`
+	ResID           string                   `db:"res_id" validate:"required,lte=64" json:"res_id"`
+	SecurityGroupID string                   `db:"security_group_id" validate:"required,lte=64" json:"security_group_id"`
+	Creator         string                   `db:"creator" validate:"required,lte=64" json:"creator"`
+	CreatedAt       types.Time               `db:"created_at" validate:"excluded_unless" json:"created_at"`
+}
+
+// TableName return security group common rel table name.
+func (t SecurityGroupCommonRelTable) TableName() table.Name {
+	return table.SecurityGroupCommonRelTable
+}
+
+// InsertValidate security group common rel table when insert.
+func (t SecurityGroupCommonRelTable) InsertValidate() error {
+	return validator.Validate.Struct(t)
+}
@@ -0,0 +1,120 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package tablessync
+
+import (
+	"errors"
+
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+	"hcm/pkg/dal/table"
+	"hcm/pkg/dal/table/types"
+	"hcm/pkg/dal/table/utils"
+)
+
+// AccountSyncHistoryColumns defines all the account_sync_history table's columns.
+var AccountSyncHistoryColumns = utils.MergeColumns(nil, AccountSyncHistoryColumnDescriptor)
+
+// AccountSyncHistoryColumnDescriptor is account_sync_history's column descriptors.
+var AccountSyncHistoryColumnDescriptor = utils.ColumnDescriptors{
+	{Column: "id", NamedC: "id", Type: enumor.String},
+	{Column: "vendor", NamedC: "vendor", Type: enumor.String},
+	{Column: "account_id", NamedC: "account_id", Type: enumor.String},
+	{Column: "region", NamedC: "region", Type: enumor.String},
+	{Column: "res_name", NamedC: "res_name", Type: enumor.String},
+	{Column: "sync_rid", NamedC: "sync_rid", Type: enumor.String},
+	{Column: "status", NamedC: "status", Type: enumor.String},
+	{Column: "created_count", NamedC: "created_count", Type: enumor.Numeric},
+	{Column: "updated_count", NamedC: "updated_count", Type: enumor.Numeric},
+	{Column: "deleted_count", NamedC: "deleted_count", Type: enumor.Numeric},
+	{Column: "quarantined_count", NamedC: "quarantined_count", Type: enumor.Numeric},
+	{Column: "cloud_api_count", NamedC: "cloud_api_count", Type: enumor.Numeric},
+	{Column: "cost_ms", NamedC: "cost_ms", Type: enumor.Numeric},
+	{Column: "start_time", NamedC: "start_time", Type: enumor.String},
+	{Column: "failed_reason", NamedC: "failed_reason", Type: enumor.Json},
+	{Column: "creator", NamedC: "creator", Type: enumor.String},
+	{Column: "created_at", NamedC: "created_at", Type: enumor.Time},
+}
+
+// AccountSyncHistoryTable define account_sync_history table, 每条记录对应账号下某个地域某类资源的一次同步。
+type AccountSyncHistoryTable struct {
+	ID        string        `db:"id" json:"id" validate:"lte=64"`
+	Vendor    enumor.Vendor `db:"vendor" json:"vendor"`
+	AccountID string        `db:"account_id" json:"account_id" validate:"lte=64"`
+	Region    string        `db:"region" json:"region" validate:"lte=255"`
+	ResName   string        `db:"res_name" json:"res_name" validate:"lte=64"`
+	// SyncRid 同一次资源同步的请求ID，多地域的同步记录可以通过该字段聚合。
+	SyncRid      string `db:"sync_rid" json:"sync_rid" validate:"lte=64"`
+	Status       string `db:"status" json:"status" validate:"lte=64"`
+	CreatedCount int64  `db:"created_count" json:"created_count"`
+	UpdatedCount int64  `db:"updated_count" json:"updated_count"`
+	DeletedCount int64  `db:"deleted_count" json:"deleted_count"`
+	// QuarantinedCount 因删除数量超过阈值而被隔离的资源数量。
+	QuarantinedCount int64           `db:"quarantined_count" json:"quarantined_count"`
+	CloudApiCount    int64           `db:"cloud_api_count" json:"cloud_api_count"`
+	CostMs           int64           `db:"cost_ms" json:"cost_ms"`
+	StartTime        string          `db:"start_time" json:"start_time"`
+	FailedReason     types.JsonField `db:"failed_reason" json:"failed_reason"`
+	Creator          string          `db:"creator" json:"creator" validate:"lte=64"`
+	CreatedAt        types.Time      `db:"created_at" json:"created_at" validate:"excluded_unless"`
+}
+
+// TableName return account_sync_history table name.
+func (a AccountSyncHistoryTable) TableName() table.Name {
+	return table.AccountSyncHistoryTable
+}
+
+// InsertValidate account_sync_history table when insert.
+func (a AccountSyncHistoryTable) InsertValidate() error {
+	// length validate.
+	if err := validator.Validate.Struct(a); err != nil {
+		return err
+	}
+
+	if len(a.ID) == 0 {
+		return errors.New("id is required")
+	}
+
+	if len(a.Vendor) == 0 {
+		return errors.New("vendor is required")
+	}
+
+	if len(a.AccountID) == 0 {
+		return errors.New("account_id is required")
+	}
+
+	if len(a.ResName) == 0 {
+		return errors.New("res_name is required")
+	}
+
+	if len(a.SyncRid) == 0 {
+		return errors.New("sync_rid is required")
+	}
+
+	if len(a.Status) == 0 {
+		return errors.New("status is required")
+	}
+
+	if len(a.Creator) == 0 {
+		return errors.New("creator is required")
+	}
+
+	return nil
+}
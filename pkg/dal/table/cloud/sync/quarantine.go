@@ -0,0 +1,147 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+package tablessync
+
+import (
+	"errors"
+
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+	"hcm/pkg/dal/table"
+	"hcm/pkg/dal/table/types"
+	"hcm/pkg/dal/table/utils"
+)
+
+// AccountSyncQuarantineColumns defines all the account_sync_quarantine table's columns.
+var AccountSyncQuarantineColumns = utils.MergeColumns(nil, AccountSyncQuarantineColumnDescriptor)
+
+// AccountSyncQuarantineColumnDescriptor is account_sync_quarantine's column descriptors.
+var AccountSyncQuarantineColumnDescriptor = utils.ColumnDescriptors{
+	{Column: "id", NamedC: "id", Type: enumor.String},
+	{Column: "vendor", NamedC: "vendor", Type: enumor.String},
+	{Column: "account_id", NamedC: "account_id", Type: enumor.String},
+	{Column: "region", NamedC: "region", Type: enumor.String},
+	{Column: "res_type", NamedC: "res_type", Type: enumor.String},
+	{Column: "cloud_id", NamedC: "cloud_id", Type: enumor.String},
+	{Column: "status", NamedC: "status", Type: enumor.String},
+	{Column: "sync_rid", NamedC: "sync_rid", Type: enumor.String},
+	{Column: "creator", NamedC: "creator", Type: enumor.String},
+	{Column: "reviser", NamedC: "reviser", Type: enumor.String},
+	{Column: "created_at", NamedC: "created_at", Type: enumor.Time},
+	{Column: "updated_at", NamedC: "updated_at", Type: enumor.Time},
+}
+
+// AccountSyncQuarantineTable define account_sync_quarantine table, 同步时云上已不存在，但因为删除数量超过阈值而被隔离、
+// 暂未从本地删除的资源。
+type AccountSyncQuarantineTable struct {
+	ID        string                      `db:"id" json:"id" validate:"lte=64"`
+	Vendor    enumor.Vendor               `db:"vendor" json:"vendor"`
+	AccountID string                      `db:"account_id" json:"account_id" validate:"lte=64"`
+	Region    string                      `db:"region" json:"region" validate:"lte=255"`
+	ResType   enumor.CloudResourceType    `db:"res_type" json:"res_type" validate:"lte=64"`
+	CloudID   string                      `db:"cloud_id" json:"cloud_id" validate:"lte=255"`
+	Status    enumor.SyncQuarantineStatus `db:"status" json:"status" validate:"lte=32"`
+	// SyncRid 隔离该资源的同步请求ID，用于判断后续同步是否为确认同步。
+	SyncRid   string     `db:"sync_rid" json:"sync_rid" validate:"lte=64"`
+	Creator   string     `db:"creator" json:"creator" validate:"lte=64"`
+	Reviser   string     `db:"reviser" json:"reviser" validate:"lte=64"`
+	CreatedAt types.Time `db:"created_at" json:"created_at" validate:"excluded_unless"`
+	UpdatedAt types.Time `db:"updated_at" json:"updated_at" validate:"excluded_unless"`
+}
+
+// TableName return account_sync_quarantine table name.
+func (a AccountSyncQuarantineTable) TableName() table.Name {
+	return table.AccountSyncQuarantineTable
+}
+
+// InsertValidate account_sync_quarantine table when insert.
+func (a AccountSyncQuarantineTable) InsertValidate() error {
+	// length validate.
+	if err := validator.Validate.Struct(a); err != nil {
+		return err
+	}
+
+	if len(a.ID) == 0 {
+		return errors.New("id is required")
+	}
+
+	if len(a.Vendor) == 0 {
+		return errors.New("vendor is required")
+	}
+
+	if len(a.AccountID) == 0 {
+		return errors.New("account_id is required")
+	}
+
+	if len(a.ResType) == 0 {
+		return errors.New("res_type is required")
+	}
+
+	if len(a.CloudID) == 0 {
+		return errors.New("cloud_id is required")
+	}
+
+	if len(a.Status) == 0 {
+		return errors.New("status is required")
+	}
+
+	if len(a.SyncRid) == 0 {
+		return errors.New("sync_rid is required")
+	}
+
+	if len(a.Creator) == 0 {
+		return errors.New("creator is required")
+	}
+
+	if len(a.Reviser) == 0 {
+		return errors.New("reviser is required")
+	}
+
+	return nil
+}
+
+// UpdateValidate account_sync_quarantine table when update.
+func (a AccountSyncQuarantineTable) UpdateValidate() error {
+	// length validate.
+	if err := validator.Validate.Struct(a); err != nil {
+		return err
+	}
+
+	if len(a.Vendor) != 0 {
+		return errors.New("vendor can not update")
+	}
+
+	if len(a.AccountID) != 0 {
+		return errors.New("account_id can not update")
+	}
+
+	if len(a.ResType) != 0 {
+		return errors.New("res_type can not update")
+	}
+
+	if len(a.CloudID) != 0 {
+		return errors.New("cloud_id can not update")
+	}
+
+	if len(a.Creator) != 0 {
+		return errors.New("creator can not update")
+	}
+
+	return nil
+}
@@ -0,0 +1,190 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package cloud
+
+import (
+	"errors"
+
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+	"hcm/pkg/dal/table"
+	"hcm/pkg/dal/table/types"
+	"hcm/pkg/dal/table/utils"
+)
+
+// UserDataTemplateColumns defines all the user data template table's columns.
+var UserDataTemplateColumns = utils.MergeColumns(nil, UserDataTemplateColumnDescriptor)
+
+// UserDataTemplateColumnDescriptor is user data template's column descriptors.
+var UserDataTemplateColumnDescriptor = utils.ColumnDescriptors{
+	{Column: "id", NamedC: "id", Type: enumor.String},
+	{Column: "name", NamedC: "name", Type: enumor.String},
+	{Column: "bk_biz_id", NamedC: "bk_biz_id", Type: enumor.Numeric},
+	{Column: "latest_version", NamedC: "latest_version", Type: enumor.Numeric},
+	{Column: "memo", NamedC: "memo", Type: enumor.String},
+	{Column: "creator", NamedC: "creator", Type: enumor.String},
+	{Column: "reviser", NamedC: "reviser", Type: enumor.String},
+	{Column: "created_at", NamedC: "created_at", Type: enumor.Time},
+	{Column: "updated_at", NamedC: "updated_at", Type: enumor.Time},
+}
+
+// UserDataTemplateTable define user data template table, 业务下的主机自定义数据(cloud-init)模版。
+type UserDataTemplateTable struct {
+	ID            string     `db:"id" validate:"lte=64" json:"id"`
+	Name          string     `db:"name" validate:"lte=255" json:"name"`
+	BkBizID       int64      `db:"bk_biz_id" json:"bk_biz_id"`
+	LatestVersion int64      `db:"latest_version" json:"latest_version"`
+	Memo          *string    `db:"memo" validate:"omitempty,lte=255" json:"memo"`
+	Creator       string     `db:"creator" validate:"lte=64" json:"creator"`
+	Reviser       string     `db:"reviser" validate:"lte=64" json:"reviser"`
+	CreatedAt     types.Time `db:"created_at" validate:"excluded_unless" json:"created_at"`
+	UpdatedAt     types.Time `db:"updated_at" validate:"excluded_unless" json:"updated_at"`
+}
+
+// TableName return user data template table name.
+func (t UserDataTemplateTable) TableName() table.Name {
+	return table.UserDataTemplateTable
+}
+
+// InsertValidate user data template table when insert.
+func (t UserDataTemplateTable) InsertValidate() error {
+	// length validate.
+	if err := validator.Validate.Struct(t); err != nil {
+		return err
+	}
+
+	if len(t.ID) == 0 {
+		return errors.New("id is required")
+	}
+
+	if len(t.Name) == 0 {
+		return errors.New("name is required")
+	}
+
+	if t.BkBizID <= 0 {
+		return errors.New("bk_biz_id should be > 0")
+	}
+
+	if t.LatestVersion <= 0 {
+		return errors.New("latest_version should be > 0")
+	}
+
+	if len(t.Creator) == 0 {
+		return errors.New("creator is required")
+	}
+
+	if len(t.Reviser) == 0 {
+		return errors.New("reviser is required")
+	}
+
+	if len(t.CreatedAt) != 0 {
+		return errors.New("created_at can not set")
+	}
+
+	if len(t.UpdatedAt) != 0 {
+		return errors.New("updated_at can not set")
+	}
+
+	return nil
+}
+
+// UpdateValidate user data template table when update.
+func (t UserDataTemplateTable) UpdateValidate() error {
+	// length validate.
+	if err := validator.Validate.Struct(t); err != nil {
+		return err
+	}
+
+	if t.BkBizID != 0 {
+		return errors.New("bk_biz_id can not update")
+	}
+
+	if len(t.Creator) != 0 {
+		return errors.New("creator can not update")
+	}
+
+	return nil
+}
+
+// UserDataTemplateVersionColumns defines all the user data template version table's columns.
+var UserDataTemplateVersionColumns = utils.MergeColumns(nil, UserDataTemplateVersionColumnDescriptor)
+
+// UserDataTemplateVersionColumnDescriptor is user data template version's column descriptors.
+var UserDataTemplateVersionColumnDescriptor = utils.ColumnDescriptors{
+	{Column: "id", NamedC: "id", Type: enumor.String},
+	{Column: "template_id", NamedC: "template_id", Type: enumor.String},
+	{Column: "version", NamedC: "version", Type: enumor.Numeric},
+	{Column: "content", NamedC: "content", Type: enumor.String},
+	{Column: "variables", NamedC: "variables", Type: enumor.Json},
+	{Column: "memo", NamedC: "memo", Type: enumor.String},
+	{Column: "creator", NamedC: "creator", Type: enumor.String},
+	{Column: "created_at", NamedC: "created_at", Type: enumor.Time},
+}
+
+// UserDataTemplateVersionTable define user data template version table, 模版内容每次变更都会生成新的版本，版本内容不可修改。
+type UserDataTemplateVersionTable struct {
+	ID         string          `db:"id" validate:"lte=64" json:"id"`
+	TemplateID string          `db:"template_id" validate:"lte=64" json:"template_id"`
+	Version    int64           `db:"version" json:"version"`
+	Content    string          `db:"content" json:"content"`
+	Variables  types.JsonField `db:"variables" json:"variables"`
+	Memo       *string         `db:"memo" validate:"omitempty,lte=255" json:"memo"`
+	Creator    string          `db:"creator" validate:"lte=64" json:"creator"`
+	CreatedAt  types.Time      `db:"created_at" validate:"excluded_unless" json:"created_at"`
+}
+
+// TableName return user data template version table name.
+func (t UserDataTemplateVersionTable) TableName() table.Name {
+	return table.UserDataTemplateVersionTable
+}
+
+// InsertValidate user data template version table when insert.
+func (t UserDataTemplateVersionTable) InsertValidate() error {
+	// length validate.
+	if err := validator.Validate.Struct(t); err != nil {
+		return err
+	}
+
+	if len(t.ID) == 0 {
+		return errors.New("id is required")
+	}
+
+	if len(t.TemplateID) == 0 {
+		return errors.New("template_id is required")
+	}
+
+	if t.Version <= 0 {
+		return errors.New("version should be > 0")
+	}
+
+	if len(t.Content) == 0 {
+		return errors.New("content is required")
+	}
+
+	if len(t.Creator) == 0 {
+		return errors.New("creator is required")
+	}
+
+	if len(t.CreatedAt) != 0 {
+		return errors.New("created_at can not set")
+	}
+
+	return nil
+}
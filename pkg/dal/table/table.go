@@ -56,6 +56,8 @@ const (
 	SecurityGroupSubnetTable Name = "security_group_subnet_rel"
 	// SecurityGroupCvmTable is security group cvm table's name.
 	SecurityGroupCvmTable Name = "security_group_cvm_rel"
+	// SecurityGroupCommonRelTable is security group and common resource rel table's name.
+	SecurityGroupCommonRelTable Name = "security_group_common_rel"
 	// SGSecurityGroupRuleTable is security group and rule rel table's name.
 	SGSecurityGroupRuleTable = "security_group_security_group_rule"
 	// TCloudSecurityGroupRuleTable is tcloud security group rule table's name.
@@ -115,6 +117,10 @@ const (
 
 	// AccountSyncDetailTable is account_sync_detail table's name.
 	AccountSyncDetailTable Name = "account_sync_detail"
+	// AccountSyncHistoryTable is account_sync_history table's name.
+	AccountSyncHistoryTable Name = "account_sync_history"
+	// AccountSyncQuarantineTable is account_sync_quarantine table's name.
+	AccountSyncQuarantineTable Name = "account_sync_quarantine"
 
 	// ApplicationTable is application table name
 	ApplicationTable Name = "application"
@@ -138,6 +144,34 @@ const (
 	AsyncFlowTable Name = "async_flow"
 	// AsyncFlowTaskTable is async flow task table's name.
 	AsyncFlowTaskTable Name = "async_flow_task"
+	// AsyncFlowScheduleTable is async flow schedule table's name.
+	AsyncFlowScheduleTable Name = "async_flow_schedule"
+	// AsyncFlowScheduleRunTable is async flow schedule run history table's name.
+	AsyncFlowScheduleRunTable Name = "async_flow_schedule_run"
+	// AsyncFlowEventTable is async flow lifecycle event table's name.
+	AsyncFlowEventTable Name = "async_flow_event"
+	// AsyncFlowArchiveTable is archived async flow table's name.
+	AsyncFlowArchiveTable Name = "async_flow_archive"
+	// AsyncFlowIdempotencyTable is async flow idempotency key table's name.
+	AsyncFlowIdempotencyTable Name = "async_flow_idempotency"
+
+	// ResourceTagTable is resource tag table's name.
+	ResourceTagTable Name = "resource_tag"
+	// ResChangeHistoryTable is resource change history table's name.
+	ResChangeHistoryTable Name = "res_change_history"
+
+	// BizAssignRuleTable is biz assign rule table's name.
+	BizAssignRuleTable Name = "biz_assign_rule"
+	// BizAssignRecordTable is biz assign record table's name.
+	BizAssignRecordTable Name = "biz_assign_record"
+	// UserDataTemplateTable is user data template table's name.
+	UserDataTemplateTable Name = "user_data_template"
+	// UserDataTemplateVersionTable is user data template version table's name.
+	UserDataTemplateVersionTable Name = "user_data_template_version"
+	// LaunchTemplateTable is launch template table's name.
+	LaunchTemplateTable Name = "launch_template"
+	// LaunchTemplateVersionTable is launch template version table's name.
+	LaunchTemplateVersionTable Name = "launch_template_version"
 )
 
 // Validate whether the table name is valid or not.
@@ -167,6 +201,7 @@ var TableMap = map[Name]struct{}{
 	VpcSecurityGroupRelTable:     {},
 	SecurityGroupTagTable:        {},
 	SecurityGroupSubnetTable:     {},
+	SecurityGroupCommonRelTable:  {},
 	SGSecurityGroupRuleTable:     {},
 	TCloudSecurityGroupRuleTable: {},
 	AwsSecurityGroupRuleTable:    {},
@@ -201,12 +236,28 @@ var TableMap = map[Name]struct{}{
 	AccountBillConfigTable:       {},
 	UserCollectionTable:          {},
 	AccountSyncDetailTable:       {},
+	AccountSyncHistoryTable:      {},
+	AccountSyncQuarantineTable:   {},
 
 	// TODO: 临时方案
 	RecycleRecordTableTaskID: {},
 
-	AsyncFlowTable:     {},
-	AsyncFlowTaskTable: {},
+	AsyncFlowTable:            {},
+	AsyncFlowTaskTable:        {},
+	AsyncFlowScheduleTable:    {},
+	AsyncFlowScheduleRunTable: {},
+	AsyncFlowEventTable:       {},
+	AsyncFlowArchiveTable:     {},
+	AsyncFlowIdempotencyTable: {},
+
+	ResourceTagTable:             {},
+	ResChangeHistoryTable:        {},
+	BizAssignRuleTable:           {},
+	BizAssignRecordTable:         {},
+	UserDataTemplateTable:        {},
+	UserDataTemplateVersionTable: {},
+	LaunchTemplateTable:          {},
+	LaunchTemplateVersionTable:   {},
 }
 
 // Register 注册表名
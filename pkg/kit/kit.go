@@ -64,6 +64,14 @@ type Kit struct {
 	// 因为来自前端和第三方系统调用的请求均为 ApiCall，所以没必要将该字段暴漏出去，仅同步请求需要设
 	// 置该字段为 BackgroundSync。
 	RequestSource enumor.RequestSourceType
+
+	// SyncFencingToken 账号同步锁的 fencing token，格式为 <账号ID>:<锁版本>，内部使用字段，
+	// 仅持有账号同步锁的同步请求需要设置，data-service 据此拒绝已经失去同步锁的同步写入。
+	SyncFencingToken string
+
+	// IdempotencyKey 请求方指定的幂等键，同一应用使用相同幂等键和相同请求内容重复创建任务流时，返回首次创建的任务流ID，
+	// 请求内容不同时拒绝创建。通过请求头透传，cloud-server 创建任务流的批量操作可以直接复用调用方的幂等键。
+	IdempotencyKey string
 }
 
 // NewSubKit 在当前kit后缀加上6位随机字符串
@@ -135,11 +143,13 @@ func (kt *Kit) Validate() error {
 // Header generate header by kit
 func (kt *Kit) Header() http.Header {
 	return http.Header{
-		constant.UserKey:          []string{kt.User},
-		constant.RidKey:           []string{kt.Rid},
-		constant.AppCodeKey:       []string{kt.AppCode},
-		constant.TenantIDKey:      []string{kt.TenantID},
-		constant.RequestSourceKey: []string{string(kt.RequestSource)},
+		constant.UserKey:             []string{kt.User},
+		constant.RidKey:              []string{kt.Rid},
+		constant.AppCodeKey:          []string{kt.AppCode},
+		constant.TenantIDKey:         []string{kt.TenantID},
+		constant.RequestSourceKey:    []string{string(kt.RequestSource)},
+		constant.SyncFencingTokenKey: []string{kt.SyncFencingToken},
+		constant.IdempotencyKeyKey:   []string{kt.IdempotencyKey},
 	}
 }
 
@@ -150,12 +160,14 @@ func FromHeader(ctx context.Context, header http.Header) (*Kit, error) {
 	}
 
 	kt := &Kit{
-		Ctx:           ctx,
-		User:          header.Get(constant.UserKey),
-		Rid:           header.Get(constant.RidKey),
-		AppCode:       header.Get(constant.AppCodeKey),
-		TenantID:      header.Get(constant.TenantIDKey),
-		RequestSource: enumor.RequestSourceType(header.Get(constant.RequestSourceKey)),
+		Ctx:              ctx,
+		User:             header.Get(constant.UserKey),
+		Rid:              header.Get(constant.RidKey),
+		AppCode:          header.Get(constant.AppCodeKey),
+		TenantID:         header.Get(constant.TenantIDKey),
+		RequestSource:    enumor.RequestSourceType(header.Get(constant.RequestSourceKey)),
+		SyncFencingToken: header.Get(constant.SyncFencingTokenKey),
+		IdempotencyKey:   header.Get(constant.IdempotencyKeyKey),
 	}
 
 	if kt.Ctx.Value(constant.RidKey) == nil {
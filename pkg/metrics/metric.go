@@ -62,6 +62,9 @@ const (
 
 	// OrmCmdSubSys defines all the orm command related sub system.
 	OrmCmdSubSys = "orm"
+
+	// AsyncSubSys defines all the async task framework related sub system.
+	AsyncSubSys = "async"
 )
 
 // labels
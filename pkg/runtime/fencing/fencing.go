@@ -0,0 +1,87 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package fencing 账号同步锁的 fencing token，cloud-server 持有账号同步锁期间，将锁的 fencing token 随请求透传到下游服务，
+// data-service 据此拒绝已经失去同步锁的同步请求写入数据。
+package fencing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"hcm/pkg/cc"
+
+	etcd3 "go.etcd.io/etcd/client/v3"
+)
+
+// ErrStaleToken fencing token 对应的同步锁已经释放或被其他同步持有。
+var ErrStaleToken = errors.New("sync fencing token is stale, the account sync lock has been lost")
+
+// Token 账号同步锁的 fencing token，Revision 为同步锁在etcd中的创建版本，每次加锁单调递增。
+type Token struct {
+	AccountID string
+	Revision  int64
+}
+
+// String 返回 <账号ID>:<锁版本> 格式的 token。
+func (t Token) String() string {
+	return fmt.Sprintf("%s:%d", t.AccountID, t.Revision)
+}
+
+// Parse 解析 <账号ID>:<锁版本> 格式的 token。
+func Parse(value string) (*Token, error) {
+	index := strings.LastIndex(value, ":")
+	if index <= 0 {
+		return nil, fmt.Errorf("invalid sync fencing token: %s", value)
+	}
+
+	revision, err := strconv.ParseInt(value[index+1:], 10, 64)
+	if err != nil || revision <= 0 {
+		return nil, fmt.Errorf("invalid sync fencing token: %s", value)
+	}
+
+	return &Token{AccountID: value[:index], Revision: revision}, nil
+}
+
+// SyncLockPrefix 账号同步锁在etcd中的key前缀。
+func SyncLockPrefix() string {
+	return fmt.Sprintf("/hcm/lock/%s/sync/", cc.CloudServerName)
+}
+
+// SyncLockKey 账号同步锁在etcd中的key。
+func SyncLockKey(accountID string) string {
+	return SyncLockPrefix() + accountID
+}
+
+// Check 校验 token 是否为账号同步锁的当前持有者，锁已释放或已被重新获取时返回 ErrStaleToken。
+func Check(ctx context.Context, cli *etcd3.Client, token *Token) error {
+	resp, err := cli.Get(ctx, SyncLockKey(token.AccountID))
+	if err != nil {
+		return fmt.Errorf("get account sync lock failed, err: %v", err)
+	}
+
+	if len(resp.Kvs) == 0 || resp.Kvs[0].CreateRevision != token.Revision {
+		return ErrStaleToken
+	}
+
+	return nil
+}
@@ -0,0 +1,39 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package fencing
+
+import "testing"
+
+func TestParse(t *testing.T) {
+	token, err := Parse(Token{AccountID: "00000001", Revision: 12}.String())
+	if err != nil {
+		t.Fatalf("parse token failed, err: %v", err)
+	}
+
+	if token.AccountID != "00000001" || token.Revision != 12 {
+		t.Errorf("unexpected token: %+v", token)
+	}
+
+	for _, value := range []string{"", "00000001", ":12", "00000001:", "00000001:abc", "00000001:0"} {
+		if _, err = Parse(value); err == nil {
+			t.Errorf("parse invalid token %q should failed", value)
+		}
+	}
+}
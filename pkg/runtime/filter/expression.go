@@ -149,7 +149,7 @@ func (exp Expression) Validate(opt *ExprOption) (hitErr error) {
 	for _, r := range exp.Rules {
 		switch r.WithType() {
 		case AtomType:
-			fieldsReminder[r.RuleField()] = true
+			fieldsReminder[ruleFieldName(r.RuleField())] = true
 		case ExpressionType:
 			exprCountReminder++
 		default:
@@ -447,8 +447,14 @@ func (ar AtomRule) Validate(opt *ExprOption) error {
 		return errors.New("rule value can not be nil")
 	}
 
+	if IsTagRuleField(ar.Field) {
+		if err := validateTagRule(ar); err != nil {
+			return err
+		}
+	}
+
 	if opt != nil {
-		typ, exist := opt.RuleFields[ar.Field]
+		typ, exist := opt.RuleFields[ruleFieldName(ar.Field)]
 		if !exist {
 			return fmt.Errorf("rule field: %s is not exist in the expr option", ar.Field)
 		}
@@ -537,6 +543,10 @@ func (ar AtomRule) RuleField() string {
 
 // SQLExprAndValue convert this atom rule to a mysql's sub query expression, and field's value.
 func (ar AtomRule) SQLExprAndValue(opt *SQLWhereOption) (string, map[string]interface{}, error) {
+	if IsTagRuleField(ar.Field) {
+		return tagSQLExprAndValue(ar, opt)
+	}
+
 	expr, value, err := ar.Op.Operator().SQLExprAndValue(ar.Field, ar.Value)
 	if err != nil {
 		return "", nil, err
@@ -479,3 +479,66 @@ func TestNestedSqlWhereExpr(t *testing.T) {
 		return
 	}
 }
+
+func TestTagRuleSQLWhereExpr(t *testing.T) {
+	expr := &Expression{
+		Op: And,
+		Rules: []RuleFactory{
+			&AtomRule{
+				Field: "name",
+				Op:    Equal.Factory(),
+				Value: "hcm",
+			},
+			&AtomRule{
+				Field: "tags.owner",
+				Op:    In.Factory(),
+				Value: []interface{}{"jim", "jon"},
+			},
+		},
+	}
+
+	exprOpt := NewExprOption(RuleFields(map[string]enumor.ColumnType{
+		"name":       enumor.String,
+		TagRuleField: enumor.String,
+	}))
+	if err := expr.Validate(exprOpt); err != nil {
+		t.Errorf("validate tag rule expression failed, err: %v", err)
+		return
+	}
+
+	// tag rule is not supported without tag option.
+	opt := &SQLWhereOption{Priority: []string{"id"}}
+	if _, _, err := expr.SQLWhereExpr(opt); err == nil {
+		t.Errorf("generate tag rule SQL Where expression without tag option should failed")
+		return
+	}
+
+	opt.TagOption = &TagOption{Table: "resource_tag", ResType: "cvm"}
+	where, value, err := expr.SQLWhereExpr(opt)
+	if err != nil {
+		t.Errorf("generate tag rule SQL Where expression failed, err: %v", err)
+		return
+	}
+
+	fmt.Printf("where tag rule expr: %s, value: %v\n", where, value)
+	if !strings.HasPrefix(where, "WHERE name = :name_") ||
+		!strings.Contains(where, "AND id IN (SELECT res_id FROM resource_tag WHERE res_type = :res_type_") ||
+		!strings.Contains(where, "AND tag_key = :tag_key_") || !strings.Contains(where, "AND tag_value IN (:tag_value_") {
+		t.Errorf("generate tag rule SQL Where expression failed, sql: %v", where)
+		return
+	}
+
+	if len(value) != 4 {
+		t.Errorf("generate tag rule SQL Where expression value failed, value: %v", value)
+		return
+	}
+
+	invalid := &Expression{
+		Op:    And,
+		Rules: []RuleFactory{&AtomRule{Field: "tags.owner", Op: GreaterThan.Factory(), Value: "jim"}},
+	}
+	if err := invalid.Validate(exprOpt); err == nil {
+		t.Errorf("validate tag rule with unsupported operator should failed")
+		return
+	}
+}
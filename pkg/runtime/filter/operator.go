@@ -32,8 +32,11 @@ import (
 
 // timeFields 因为mysql在8.0.19之后才支持了带时区的时间字符串查询能力，所以，需要将带时区的时间字符串转成时UTC时间去查询。
 var timeFields = map[string]struct{}{
-	"created_at": {},
-	"updated_at": {},
+	"created_at":  {},
+	"updated_at":  {},
+	"run_at":      {},
+	"next_run_at": {},
+	"plan_at":     {},
 }
 
 var opFactory map[OpFactory]Operator
@@ -0,0 +1,135 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package filter
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	// TagRuleField 资源标签过滤字段，ExprOption.RuleFields 中声明该字段表示支持标签过滤，
+	// 标签过滤规则的字段格式为 "tags.{tag_key}"，如："tags.owner"，规则的值为标签值。
+	TagRuleField = "tags"
+	// tagFieldPrefix tag rule field prefix.
+	tagFieldPrefix = TagRuleField + "."
+)
+
+// TagOption defines the tag rules' sql expression options.
+type TagOption struct {
+	// Table is the resource tag table's name.
+	Table string
+	// ResType is the resource type of the tags to be filtered.
+	ResType string
+}
+
+// Validate TagOption.
+func (opt *TagOption) Validate() error {
+	if opt == nil {
+		return errors.New("tag option is nil, tag rule is not supported")
+	}
+
+	if len(opt.Table) == 0 {
+		return errors.New("tag option table is required")
+	}
+
+	if len(opt.ResType) == 0 {
+		return errors.New("tag option resource type is required")
+	}
+
+	return nil
+}
+
+// IsTagRuleField check if the rule's field is a tag rule field.
+func IsTagRuleField(field string) bool {
+	return strings.HasPrefix(field, tagFieldPrefix)
+}
+
+// TagRuleFieldKey get tag key from tag rule field, returns "" if the field is not a tag rule field.
+func TagRuleFieldKey(field string) string {
+	if !IsTagRuleField(field) {
+		return ""
+	}
+
+	return strings.TrimPrefix(field, tagFieldPrefix)
+}
+
+// ruleFieldName 获取规则字段对应在 ExprOption.RuleFields 中的字段名，标签规则统一为 TagRuleField。
+func ruleFieldName(field string) string {
+	if IsTagRuleField(field) {
+		return TagRuleField
+	}
+
+	return field
+}
+
+// tagRuleOperators defines the operators that tag rule supported.
+var tagRuleOperators = map[OpFactory]struct{}{
+	Equal.Factory():               {},
+	NotEqual.Factory():            {},
+	In.Factory():                  {},
+	NotIn.Factory():               {},
+	ContainsSensitive.Factory():   {},
+	ContainsInsensitive.Factory(): {},
+}
+
+func validateTagRule(ar AtomRule) error {
+	if len(TagRuleFieldKey(ar.Field)) == 0 {
+		return fmt.Errorf("tag rule field(%s) key is empty", ar.Field)
+	}
+
+	if _, exist := tagRuleOperators[ar.Op]; !exist {
+		return fmt.Errorf("tag rule field(%s) not support operator: %s", ar.Field, ar.Op)
+	}
+
+	return nil
+}
+
+// tagSQLExprAndValue 将标签规则转换为资源标签表的子查询，如：
+// id IN (SELECT res_id FROM resource_tag WHERE res_type = :res_type AND tag_key = :tag_key AND tag_value = :tag_value)
+func tagSQLExprAndValue(ar AtomRule, opt *SQLWhereOption) (string, map[string]interface{}, error) {
+	if opt == nil {
+		return "", nil, errors.New("SQLWhereOption is nil")
+	}
+
+	if err := opt.TagOption.Validate(); err != nil {
+		return "", nil, err
+	}
+
+	if err := validateTagRule(ar); err != nil {
+		return "", nil, err
+	}
+
+	valueExpr, value, err := ar.Op.Operator().SQLExprAndValue("tag_value", ar.Value)
+	if err != nil {
+		return "", nil, err
+	}
+
+	resTypePlaceholder := fieldPlaceholderName("res_type")
+	keyPlaceholder := fieldPlaceholderName("tag_key")
+	value[resTypePlaceholder] = opt.TagOption.ResType
+	value[keyPlaceholder] = TagRuleFieldKey(ar.Field)
+
+	expr := fmt.Sprintf(`id IN (SELECT res_id FROM %s WHERE res_type = %s%s AND tag_key = %s%s AND %s)`,
+		opt.TagOption.Table, SqlPlaceholder, resTypePlaceholder, SqlPlaceholder, keyPlaceholder, valueExpr)
+
+	return expr, value, nil
+}
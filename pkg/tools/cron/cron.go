@@ -0,0 +1,246 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package cron 解析标准的5段式 cron 表达式（分 时 日 月 周），并计算下次执行时间。
+package cron
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule 解析后的 cron 表达式
+type Schedule struct {
+	minute uint64
+	hour   uint64
+	dom    uint64
+	month  uint64
+	dow    uint64
+
+	// domStar、dowStar 日、周字段是否为 *，都不为 * 时，满足任一字段即可，与 crontab 语义保持一致
+	domStar bool
+	dowStar bool
+}
+
+type bounds struct {
+	min   uint
+	max   uint
+	names map[string]uint
+}
+
+var (
+	minuteBounds = bounds{min: 0, max: 59}
+	hourBounds   = bounds{min: 0, max: 23}
+	domBounds    = bounds{min: 1, max: 31}
+	monthBounds  = bounds{min: 1, max: 12, names: map[string]uint{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}}
+	// 周字段允许 7 表示周日
+	dowBounds = bounds{min: 0, max: 7, names: map[string]uint{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}}
+)
+
+var descriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// maxSearchYears 查找下次执行时间的最大年份跨度，避免 2月30日 这类永远无法满足的表达式死循环
+const maxSearchYears = 5
+
+// Parse 解析 cron 表达式，支持 *、数字、范围（1-5）、步长（*/5、1-10/2）、列表（1,3,5）、月份和星期的英文缩写，
+// 以及 @yearly、@monthly、@weekly、@daily、@hourly 等描述符。
+func Parse(expr string) (*Schedule, error) {
+	expr = strings.TrimSpace(expr)
+	if desc, ok := descriptors[strings.ToLower(expr)]; ok {
+		expr = desc
+	}
+
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("cron expression: %s should have 5 fields, but got %d", expr, len(fields))
+	}
+
+	sch := new(Schedule)
+	var err error
+	if sch.minute, err = parseField(fields[0], minuteBounds); err != nil {
+		return nil, fmt.Errorf("invalid minute field, err: %v", err)
+	}
+
+	if sch.hour, err = parseField(fields[1], hourBounds); err != nil {
+		return nil, fmt.Errorf("invalid hour field, err: %v", err)
+	}
+
+	if sch.dom, err = parseField(fields[2], domBounds); err != nil {
+		return nil, fmt.Errorf("invalid day of month field, err: %v", err)
+	}
+
+	if sch.month, err = parseField(fields[3], monthBounds); err != nil {
+		return nil, fmt.Errorf("invalid month field, err: %v", err)
+	}
+
+	if sch.dow, err = parseField(fields[4], dowBounds); err != nil {
+		return nil, fmt.Errorf("invalid day of week field, err: %v", err)
+	}
+
+	// 7 和 0 都表示周日
+	if sch.dow&(1<<7) != 0 {
+		sch.dow |= 1
+	}
+
+	sch.domStar = fields[2] == "*" || fields[2] == "?"
+	sch.dowStar = fields[4] == "*" || fields[4] == "?"
+
+	return sch, nil
+}
+
+func parseField(field string, b bounds) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		one, err := parsePart(part, b)
+		if err != nil {
+			return 0, err
+		}
+		bits |= one
+	}
+
+	return bits, nil
+}
+
+// parsePart 解析单个列表项，格式为 *、n、n-m，均可追加 /step
+func parsePart(part string, b bounds) (uint64, error) {
+	rangeAndStep := strings.Split(part, "/")
+	if len(rangeAndStep) > 2 {
+		return 0, fmt.Errorf("invalid expression: %s", part)
+	}
+
+	var start, end uint
+	var err error
+	switch lowAndHigh := strings.Split(rangeAndStep[0], "-"); {
+	case rangeAndStep[0] == "*" || rangeAndStep[0] == "?":
+		start, end = b.min, b.max
+	case len(lowAndHigh) == 1:
+		if start, err = parseValue(lowAndHigh[0], b); err != nil {
+			return 0, err
+		}
+		end = start
+		// n/step 表示从 n 开始到最大值，每隔 step 执行
+		if len(rangeAndStep) == 2 {
+			end = b.max
+		}
+	case len(lowAndHigh) == 2:
+		if start, err = parseValue(lowAndHigh[0], b); err != nil {
+			return 0, err
+		}
+		if end, err = parseValue(lowAndHigh[1], b); err != nil {
+			return 0, err
+		}
+	default:
+		return 0, fmt.Errorf("invalid expression: %s", part)
+	}
+
+	if start > end {
+		return 0, fmt.Errorf("invalid range: %s, start should <= end", part)
+	}
+
+	step := uint(1)
+	if len(rangeAndStep) == 2 {
+		val, err := strconv.ParseUint(rangeAndStep[1], 10, 32)
+		if err != nil || val == 0 {
+			return 0, fmt.Errorf("invalid step: %s", part)
+		}
+		step = uint(val)
+	}
+
+	var bits uint64
+	for i := start; i <= end; i += step {
+		bits |= 1 << i
+	}
+
+	return bits, nil
+}
+
+func parseValue(val string, b bounds) (uint, error) {
+	if num, ok := b.names[strings.ToLower(val)]; ok {
+		return num, nil
+	}
+
+	num, err := strconv.ParseUint(val, 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value: %s", val)
+	}
+
+	if uint(num) < b.min || uint(num) > b.max {
+		return 0, fmt.Errorf("value: %d out of range [%d, %d]", num, b.min, b.max)
+	}
+
+	return uint(num), nil
+}
+
+// Next 返回晚于 t 的下一个执行时间，时区与 t 保持一致，找不到时返回零值。
+func (s *Schedule) Next(t time.Time) time.Time {
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	yearLimit := t.Year() + maxSearchYears
+
+	for t.Year() <= yearLimit {
+		if s.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+
+		if !s.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+
+		if s.hour&(1<<uint(t.Hour())) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+			continue
+		}
+
+		if s.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+
+		return t
+	}
+
+	return time.Time{}
+}
+
+func (s *Schedule) dayMatches(t time.Time) bool {
+	domMatch := s.dom&(1<<uint(t.Day())) != 0
+	dowMatch := s.dow&(1<<uint(t.Weekday())) != 0
+
+	if s.domStar || s.dowStar {
+		return domMatch && dowMatch
+	}
+
+	return domMatch || dowMatch
+}
@@ -0,0 +1,91 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package cron
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseInvalid(t *testing.T) {
+	cases := []string{
+		"",
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"5-1 * * * *",
+		"*/0 * * * *",
+		"a * * * *",
+	}
+
+	for _, one := range cases {
+		if _, err := Parse(one); err == nil {
+			t.Errorf("parse %q should be failed", one)
+		}
+	}
+}
+
+func TestNext(t *testing.T) {
+	base := time.Date(2024, 1, 31, 10, 29, 30, 0, time.UTC)
+
+	cases := []struct {
+		expr   string
+		expect time.Time
+	}{
+		{expr: "* * * * *", expect: time.Date(2024, 1, 31, 10, 30, 0, 0, time.UTC)},
+		{expr: "*/15 * * * *", expect: time.Date(2024, 1, 31, 10, 30, 0, 0, time.UTC)},
+		{expr: "0 * * * *", expect: time.Date(2024, 1, 31, 11, 0, 0, 0, time.UTC)},
+		{expr: "0 2 * * *", expect: time.Date(2024, 2, 1, 2, 0, 0, 0, time.UTC)},
+		{expr: "@daily", expect: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{expr: "0 0 1 * *", expect: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{expr: "0 0 29 feb *", expect: time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{expr: "0 9 * * mon-fri", expect: time.Date(2024, 2, 1, 9, 0, 0, 0, time.UTC)},
+		{expr: "0 9 * * 7", expect: time.Date(2024, 2, 4, 9, 0, 0, 0, time.UTC)},
+		// 日、周字段都不为 * 时，满足任一即可：2月1日是周四，早于 2月3日（周六）
+		{expr: "0 0 3 * 4", expect: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{expr: "10,40 8-10/2 * * *", expect: time.Date(2024, 1, 31, 10, 40, 0, 0, time.UTC)},
+	}
+
+	for _, one := range cases {
+		sch, err := Parse(one.expr)
+		if err != nil {
+			t.Errorf("parse %q failed, err: %v", one.expr, err)
+			continue
+		}
+
+		if got := sch.Next(base); !got.Equal(one.expect) {
+			t.Errorf("next of %q should be %v, but got %v", one.expr, one.expect, got)
+		}
+	}
+}
+
+func TestNextNeverMatch(t *testing.T) {
+	sch, err := Parse("0 0 30 2 *")
+	if err != nil {
+		t.Fatalf("parse failed, err: %v", err)
+	}
+
+	if got := sch.Next(time.Now()); !got.IsZero() {
+		t.Errorf("next of impossible date should be zero, but got %v", got)
+	}
+}
@@ -0,0 +1,145 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package userdata 提供主机自定义数据(user data/cloud-init)的变量渲染、编码和脱敏能力。
+package userdata
+
+import (
+	"encoding/base64"
+	"fmt"
+	"regexp"
+
+	"hcm/pkg/criteria/enumor"
+)
+
+// 内置变量，创建主机时由平台根据请求参数自动填充。
+const (
+	HostnameVar     = "hostname"
+	BkBizIDVar      = "bk_biz_id"
+	RegionVar       = "region"
+	ZoneVar         = "zone"
+	AccountIDVar    = "account_id"
+	VendorVar       = "vendor"
+	InstanceTypeVar = "instance_type"
+)
+
+// BuiltinVars 内置变量集合，自定义变量不能与内置变量重名。
+var BuiltinVars = map[string]struct{}{
+	HostnameVar:     {},
+	BkBizIDVar:      {},
+	RegionVar:       {},
+	ZoneVar:         {},
+	AccountIDVar:    {},
+	VendorVar:       {},
+	InstanceTypeVar: {},
+}
+
+// RedactedValue 敏感变量脱敏后的值
+const RedactedValue = "******"
+
+var (
+	// varRegexp 变量占位符，格式为 ${name}。
+	varRegexp = regexp.MustCompile(`\$\{([a-zA-Z_][a-zA-Z0-9_]*)\}`)
+	// varNameRegexp 变量名称格式。
+	varNameRegexp = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]{0,63}$`)
+)
+
+// vendorMaxSize 各云厂商自定义数据原始内容大小上限(字节)。
+var vendorMaxSize = map[enumor.Vendor]int{
+	enumor.TCloud: 16 * 1024,
+	enumor.Aws:    16 * 1024,
+	enumor.HuaWei: 32 * 1024,
+	enumor.Gcp:    256 * 1024,
+	// azure 限制的是 base64 编码后的长度不能超过 64KB
+	enumor.Azure: 64 * 1024 * 3 / 4,
+}
+
+// ValidateVarName validate custom variable name.
+func ValidateVarName(name string) error {
+	if !varNameRegexp.MatchString(name) {
+		return fmt.Errorf("variable name: %s is invalid, should match %s", name, varNameRegexp.String())
+	}
+
+	if _, exists := BuiltinVars[name]; exists {
+		return fmt.Errorf("variable name: %s conflicts with builtin variable", name)
+	}
+
+	return nil
+}
+
+// ReferencedVars 返回内容中引用的变量名称，按首次出现顺序去重。
+func ReferencedVars(content string) []string {
+	matches := varRegexp.FindAllStringSubmatch(content, -1)
+	names := make([]string, 0, len(matches))
+	exists := make(map[string]struct{}, len(matches))
+	for _, match := range matches {
+		if _, ok := exists[match[1]]; ok {
+			continue
+		}
+		exists[match[1]] = struct{}{}
+		names = append(names, match[1])
+	}
+
+	return names
+}
+
+// Render 使用 values 替换内容中的 ${name} 占位符。values 中不存在的占位符保持原样，
+// 以免误替换 shell 脚本中的环境变量。
+func Render(content string, values map[string]string) string {
+	return varRegexp.ReplaceAllStringFunc(content, func(placeholder string) string {
+		name := varRegexp.FindStringSubmatch(placeholder)[1]
+		value, exists := values[name]
+		if !exists {
+			return placeholder
+		}
+		return value
+	})
+}
+
+// Redact 返回脱敏后的变量，secrets 中的变量值会被替换为 RedactedValue。
+func Redact(values map[string]string, secrets map[string]struct{}) map[string]string {
+	redacted := make(map[string]string, len(values))
+	for name, value := range values {
+		if _, exists := secrets[name]; exists {
+			redacted[name] = RedactedValue
+			continue
+		}
+		redacted[name] = value
+	}
+
+	return redacted
+}
+
+// Encode 按云厂商要求对渲染后的自定义数据进行编码，gcp 通过元数据下发明文，其余云厂商需要 base64 编码。
+func Encode(vendor enumor.Vendor, content string) (string, error) {
+	maxSize, exists := vendorMaxSize[vendor]
+	if !exists {
+		return "", fmt.Errorf("vendor: %s not support user data", vendor)
+	}
+
+	if len(content) > maxSize {
+		return "", fmt.Errorf("%s user data size %d exceeds limit %d bytes", vendor, len(content), maxSize)
+	}
+
+	if vendor == enumor.Gcp {
+		return content, nil
+	}
+
+	return base64.StdEncoding.EncodeToString([]byte(content)), nil
+}
@@ -0,0 +1,109 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package userdata
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"hcm/pkg/criteria/enumor"
+)
+
+func TestRender(t *testing.T) {
+	content := "#!/bin/bash\nhostnamectl set-hostname ${hostname}\necho ${token} > /etc/token\necho ${HOME}\n"
+	values := map[string]string{
+		HostnameVar: "web-01",
+		"token":     "abc",
+	}
+
+	rendered := Render(content, values)
+	expected := "#!/bin/bash\nhostnamectl set-hostname web-01\necho abc > /etc/token\necho ${HOME}\n"
+	if rendered != expected {
+		t.Errorf("render result(%s) not matched expected(%s)", rendered, expected)
+		return
+	}
+
+	refs := ReferencedVars(content + "${hostname}")
+	if strings.Join(refs, ",") != "hostname,token,HOME" {
+		t.Errorf("referenced vars(%v) not matched expected", refs)
+		return
+	}
+}
+
+func TestRedact(t *testing.T) {
+	values := map[string]string{"token": "abc", "env": "prod"}
+	redacted := Redact(values, map[string]struct{}{"token": {}})
+	if redacted["token"] != RedactedValue || redacted["env"] != "prod" {
+		t.Errorf("redact result(%v) not matched expected", redacted)
+		return
+	}
+
+	if values["token"] != "abc" {
+		t.Errorf("redact should not modify source values")
+		return
+	}
+}
+
+func TestEncode(t *testing.T) {
+	content := "#cloud-config\nhostname: web-01\n"
+
+	encoded, err := Encode(enumor.TCloud, content)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if encoded != base64.StdEncoding.EncodeToString([]byte(content)) {
+		t.Errorf("tcloud user data should be base64 encoded")
+		return
+	}
+
+	encoded, err = Encode(enumor.Gcp, content)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if encoded != content {
+		t.Errorf("gcp user data should be plain text")
+		return
+	}
+
+	if _, err = Encode(enumor.TCloud, strings.Repeat("a", 16*1024+1)); err == nil {
+		t.Errorf("oversize user data should be rejected")
+		return
+	}
+}
+
+func TestValidateVarName(t *testing.T) {
+	if err := ValidateVarName("token"); err != nil {
+		t.Error(err)
+		return
+	}
+
+	if err := ValidateVarName(HostnameVar); err == nil {
+		t.Errorf("builtin variable name should be rejected")
+		return
+	}
+
+	if err := ValidateVarName("1abc"); err == nil {
+		t.Errorf("invalid variable name should be rejected")
+		return
+	}
+}
@@ -0,0 +1,412 @@
+/*
+    SQLVER=0013,HCMVER=v1.2.2
+
+    Notes:
+        1. 添加资源标签表，资源标签由资源同步从云上写入，历史版本未创建过security_group_tag表，无存量标签需要迁移
+        2. 添加业务自动分配规则表、规则分配记录表
+        3. 添加主机自定义数据模版表、模版版本表
+        4. 添加主机启动模版表、模版版本表
+        5. 添加账号资源同步历史表
+        6. 添加账号资源同步删除隔离表，支持同步删除保护的资源表增加同步状态sync_status字段
+        7. 添加资源变更历史表
+        8. 任务流增加最早执行时间run_at字段，添加定时任务流表、定时任务流执行记录表
+        9. 任务流增加回滚策略rollback_policy字段，任务状态state字段长度调整为32，支持补偿失败状态compensate_failed
+        10. 任务流增加优先级priority、租户tenant字段
+        11. 任务增加执行统计stat字段，记录任务执行开始、结束时间和执行次数
+        12. 任务增加执行进度progress、心跳时间heartbeat_at字段，WatchDog根据心跳判断任务执行节点是否异常
+        13. 任务流增加应用编码app_code、回调地址webhooks字段，添加任务流事件表，用于投递任务流生命周期回调
+        14. 添加任务流归档表，已结束且超过保留时间的任务流连同任务、任务流事件一起归档后删除
+        15. 添加任务流幂等键表，同一应用使用相同幂等键重复创建任务流时返回首次创建的任务流，幂等键过期后删除
+*/
+start transaction;
+
+CREATE OR REPLACE VIEW `hcm_version`(`hcm_ver`, `sql_ver`) AS
+SELECT 'v1.2.2' as `hcm_ver`, '0013' as `sql_ver`;
+
+-- 1. 添加资源标签表
+create table if not exists `resource_tag`
+(
+    `id`           varchar(64)  not null,
+    `vendor`       varchar(16)  not null,
+    `account_id`   varchar(64)  not null,
+    `res_type`     varchar(64)  not null,
+    `res_id`       varchar(64)  not null,
+    `res_cloud_id` varchar(255) not null,
+    `tag_key`      varchar(255) not null,
+    `tag_value`    varchar(255)          default '',
+    `creator`      varchar(64)  not null,
+    `reviser`      varchar(64)  not null,
+    `created_at`   timestamp    not null default current_timestamp,
+    `updated_at`   timestamp    not null default current_timestamp on update current_timestamp,
+    primary key (`id`),
+    unique key `idx_uk_res_type_res_id_tag_key` (`res_type`, `res_id`, `tag_key`),
+    key `idx_res_type_tag_key_tag_value` (`res_type`, `tag_key`, `tag_value`)
+) engine = innodb
+  default charset = utf8mb4
+  collate utf8mb4_bin;
+
+-- 2. 添加业务自动分配规则表、规则分配记录表
+create table if not exists `biz_assign_rule`
+(
+    `id`            varchar(64)  not null,
+    `name`          varchar(255) not null,
+    `priority`      bigint       not null default 0,
+    `enabled`       boolean      not null default true,
+    `vendor`        varchar(16)  not null,
+    `account_id`    varchar(64)  not null,
+    `regions`       json         not null,
+    `cloud_vpc_ids` json         not null,
+    `name_pattern`  varchar(255)          default '',
+    `tags`          json         not null,
+    `res_types`     json         not null,
+    `bk_biz_id`     bigint       not null,
+    `bk_cloud_id`   bigint       not null default -1,
+    `memo`          varchar(255)          default '',
+    `creator`       varchar(64)  not null,
+    `reviser`       varchar(64)  not null,
+    `created_at`    timestamp    not null default current_timestamp,
+    `updated_at`    timestamp    not null default current_timestamp on update current_timestamp,
+    primary key (`id`),
+    unique key `idx_uk_name` (`name`),
+    key `idx_account_id` (`account_id`)
+) engine = innodb
+  default charset = utf8mb4
+  collate utf8mb4_bin;
+
+create table if not exists `biz_assign_record`
+(
+    `id`          varchar(64) not null,
+    `rule_id`     varchar(64) not null,
+    `vendor`      varchar(16) not null,
+    `account_id`  varchar(64) not null,
+    `res_type`    varchar(64) not null,
+    `res_id`      varchar(64) not null,
+    `bk_biz_id`   bigint      not null,
+    `bk_cloud_id` bigint      not null default -1,
+    `creator`     varchar(64) not null,
+    `created_at`  timestamp   not null default current_timestamp,
+    primary key (`id`),
+    key `idx_rule_id` (`rule_id`),
+    key `idx_res_type_res_id` (`res_type`, `res_id`)
+) engine = innodb
+  default charset = utf8mb4
+  collate utf8mb4_bin;
+
+-- 3. 添加主机自定义数据模版表、模版版本表
+create table if not exists `user_data_template`
+(
+    `id`             varchar(64)  not null,
+    `name`           varchar(255) not null,
+    `bk_biz_id`      bigint       not null,
+    `latest_version` bigint       not null default 1,
+    `memo`           varchar(255)          default '',
+    `creator`        varchar(64)  not null,
+    `reviser`        varchar(64)  not null,
+    `created_at`     timestamp    not null default current_timestamp,
+    `updated_at`     timestamp    not null default current_timestamp on update current_timestamp,
+    primary key (`id`),
+    unique key `idx_uk_bk_biz_id_name` (`bk_biz_id`, `name`)
+) engine = innodb
+  default charset = utf8mb4
+  collate utf8mb4_bin;
+
+create table if not exists `user_data_template_version`
+(
+    `id`          varchar(64)  not null,
+    `template_id` varchar(64)  not null,
+    `version`     bigint       not null,
+    `content`     mediumtext   not null,
+    `variables`   json         not null,
+    `memo`        varchar(255)          default '',
+    `creator`     varchar(64)  not null,
+    `created_at`  timestamp    not null default current_timestamp,
+    primary key (`id`),
+    unique key `idx_uk_template_id_version` (`template_id`, `version`)
+) engine = innodb
+  default charset = utf8mb4
+  collate utf8mb4_bin;
+
+-- 4. 添加主机启动模版表、模版版本表
+create table if not exists `launch_template`
+(
+    `id`             varchar(64)  not null,
+    `name`           varchar(255) not null,
+    `vendor`         varchar(16)  not null,
+    `bk_biz_id`      bigint       not null,
+    `account_id`     varchar(64)  not null,
+    `region`         varchar(255) not null,
+    `latest_version` bigint       not null default 1,
+    `memo`           varchar(255)          default '',
+    `creator`        varchar(64)  not null,
+    `reviser`        varchar(64)  not null,
+    `created_at`     timestamp    not null default current_timestamp,
+    `updated_at`     timestamp    not null default current_timestamp on update current_timestamp,
+    primary key (`id`),
+    unique key `idx_uk_bk_biz_id_name` (`bk_biz_id`, `name`),
+    key `idx_account_id` (`account_id`)
+) engine = innodb
+  default charset = utf8mb4
+  collate utf8mb4_bin;
+
+create table if not exists `launch_template_version`
+(
+    `id`          varchar(64)  not null,
+    `template_id` varchar(64)  not null,
+    `version`     bigint       not null,
+    `spec`        json         not null,
+    `memo`        varchar(255)          default '',
+    `creator`     varchar(64)  not null,
+    `created_at`  timestamp    not null default current_timestamp,
+    primary key (`id`),
+    unique key `idx_uk_template_id_version` (`template_id`, `version`)
+) engine = innodb
+  default charset = utf8mb4
+  collate utf8mb4_bin;
+
+-- 5. 添加账号资源同步历史表
+create table if not exists `account_sync_history`
+(
+    `id`                varchar(64)  not null,
+    `vendor`            varchar(16)  not null,
+    `account_id`        varchar(64)  not null,
+    `region`            varchar(255)          default '',
+    `res_name`          varchar(64)  not null,
+    `sync_rid`          varchar(64)  not null,
+    `status`            varchar(64)  not null,
+    `created_count`     bigint       not null default 0,
+    `updated_count`     bigint       not null default 0,
+    `deleted_count`     bigint       not null default 0,
+    `quarantined_count` bigint       not null default 0,
+    `cloud_api_count`   bigint       not null default 0,
+    `cost_ms`           bigint       not null default 0,
+    `start_time`        varchar(64)           default '',
+    `failed_reason`     json                  default null,
+    `creator`           varchar(64)  not null,
+    `created_at`        timestamp    not null default current_timestamp,
+    primary key (`id`),
+    key `idx_account_id_res_name` (`account_id`, `res_name`),
+    key `idx_sync_rid` (`sync_rid`),
+    key `idx_created_at` (`created_at`)
+) engine = innodb
+  default charset = utf8mb4
+  collate utf8mb4_bin;
+
+-- 6. 添加账号资源同步删除隔离表
+create table if not exists `account_sync_quarantine`
+(
+    `id`         varchar(64)  not null,
+    `vendor`     varchar(16)  not null,
+    `account_id` varchar(64)  not null,
+    `region`     varchar(255)          default '',
+    `res_type`   varchar(64)  not null,
+    `cloud_id`   varchar(255) not null,
+    `status`     varchar(32)  not null,
+    `sync_rid`   varchar(64)  not null,
+    `creator`    varchar(64)  not null,
+    `reviser`    varchar(64)  not null,
+    `created_at` timestamp    not null default current_timestamp,
+    `updated_at` timestamp    not null default current_timestamp on update current_timestamp,
+    primary key (`id`),
+    unique key `idx_uk_res_type_account_id_cloud_id` (`res_type`, `account_id`, `cloud_id`)
+) engine = innodb
+  default charset = utf8mb4
+  collate utf8mb4_bin;
+
+alter table `cvm` add column `sync_status` varchar(32) default '';
+alter table `disk` add column `sync_status` varchar(32) default '';
+alter table `eip` add column `sync_status` varchar(32) default '';
+alter table `vpc` add column `sync_status` varchar(32) default '';
+alter table `subnet` add column `sync_status` varchar(32) default '';
+alter table `security_group` add column `sync_status` varchar(32) default '';
+alter table `gcp_firewall_rule` add column `sync_status` varchar(32) default '';
+alter table `route_table` add column `sync_status` varchar(32) default '';
+alter table `network_interface` add column `sync_status` varchar(32) default '';
+
+-- 7. 添加资源变更历史表
+create table if not exists `res_change_history`
+(
+    `id`             varchar(64)     not null,
+    `res_type`       varchar(64)     not null,
+    `res_id`         varchar(64)     not null,
+    `cloud_id`       varchar(255)             default '',
+    `vendor`         varchar(16)              default '',
+    `account_id`     varchar(64)              default '',
+    `bk_biz_id`      bigint                   default -1,
+    `version`        bigint unsigned not null,
+    `action`         varchar(16)     not null,
+    `snapshot`       json            not null,
+    `changed_fields` json            not null,
+    `rid`            varchar(64)              default '',
+    `creator`        varchar(64)     not null,
+    `created_at`     timestamp       not null default current_timestamp,
+    primary key (`id`),
+    unique key `idx_uk_res_type_res_id_version` (`res_type`, `res_id`, `version`),
+    key `idx_created_at` (`created_at`)
+) engine = innodb
+  default charset = utf8mb4
+  collate utf8mb4_bin;
+
+create table if not exists `security_group_common_rel`
+(
+    `id`                bigint(1) unsigned not null auto_increment,
+    `vendor`            varchar(16)        not null,
+    `res_type`          varchar(64)        not null,
+    `res_id`            varchar(64)        not null,
+    `security_group_id` varchar(64)        not null,
+    `creator`           varchar(64)        not null,
+    `created_at`        timestamp          not null default current_timestamp,
+    primary key (`id`),
+    unique key `idx_uk_res_type_res_id_security_group_id` (`res_type`, `res_id`, `security_group_id`),
+    key `idx_security_group_id` (`security_group_id`)
+) engine = innodb
+  default charset = utf8mb4
+  collate utf8mb4_bin;
+
+-- 8. 任务流增加最早执行时间run_at字段，添加定时任务流表、定时任务流执行记录表
+alter table async_flow
+    add column `run_at` timestamp not null default current_timestamp after `worker`;
+
+alter table async_flow
+    add key `idx_state_run_at` (`state`, `run_at`);
+
+create table if not exists `async_flow_schedule`
+(
+    `id`            varchar(64)  not null,
+    `name`          varchar(64)  not null,
+    `cron`          varchar(64)  not null,
+    `state`         varchar(16)  not null,
+    `missed_policy` varchar(16)  not null,
+    `flow`          json         not null,
+    `next_run_at`   timestamp    not null default current_timestamp,
+    `memo`          varchar(255)          default '',
+    `creator`       varchar(64)  not null,
+    `reviser`       varchar(64)  not null,
+    `created_at`    timestamp    not null default current_timestamp,
+    `updated_at`    timestamp    not null default current_timestamp on update current_timestamp,
+    primary key (`id`),
+    unique key `idx_uk_name` (`name`),
+    key `idx_state_next_run_at` (`state`, `next_run_at`)
+) engine = innodb
+  default charset = utf8mb4
+  collate utf8mb4_bin;
+
+create table if not exists `async_flow_schedule_run`
+(
+    `id`          varchar(64) not null,
+    `schedule_id` varchar(64) not null,
+    `plan_at`     timestamp   not null default current_timestamp,
+    `state`       varchar(16) not null,
+    `flow_id`     varchar(64)          default '',
+    `reason`      json                 default null,
+    `creator`     varchar(64) not null,
+    `created_at`  timestamp   not null default current_timestamp,
+    primary key (`id`),
+    key `idx_schedule_id_plan_at` (`schedule_id`, `plan_at`)
+) engine = innodb
+  default charset = utf8mb4
+  collate utf8mb4_bin;
+
+-- 9. 任务流增加回滚策略rollback_policy字段，任务状态state字段长度调整为32，支持补偿失败状态compensate_failed
+alter table async_flow
+    add column `rollback_policy` varchar(16) not null default 'none' after `run_at`;
+
+alter table async_flow_task
+    modify column `state` varchar(32) not null;
+
+-- 10. 任务流增加优先级priority、租户tenant字段
+alter table async_flow
+    add column `priority` varchar(16) not null default 'normal' after `rollback_policy`,
+    add column `tenant`   varchar(64) not null default '' after `priority`;
+
+alter table async_flow
+    add key `idx_state_priority` (`state`, `priority`);
+
+-- 11. 任务增加执行统计stat字段，记录任务执行开始、结束时间和执行次数
+alter table async_flow_task
+    add column `stat` json default null after `result`;
+
+-- 12. 任务增加执行进度progress、心跳时间heartbeat_at字段，WatchDog根据心跳判断任务执行节点是否异常
+alter table async_flow_task
+    add column `progress`     json               default null after `stat`,
+    add column `heartbeat_at` timestamp not null default current_timestamp after `progress`;
+
+alter table async_flow_task
+    add key `idx_state_heartbeat_at` (`state`, `heartbeat_at`);
+
+-- 13. 任务流增加应用编码app_code、回调地址webhooks字段，添加任务流事件表，用于投递任务流生命周期回调
+alter table async_flow
+    add column `app_code` varchar(64) not null default '' after `tenant`,
+    add column `webhooks` json                 default null after `app_code`;
+
+create table if not exists `async_flow_event`
+(
+    `id`              varchar(64) not null,
+    `flow_id`         varchar(64) not null,
+    `event`           varchar(16) not null,
+    `state`           varchar(16) not null,
+    `attempts`        int unsigned         default 0,
+    `next_attempt_at` timestamp   not null default current_timestamp,
+    `deliveries`      json                 default null,
+    `created_at`      timestamp   not null default current_timestamp,
+    `updated_at`      timestamp   not null default current_timestamp on update current_timestamp,
+    primary key (`id`),
+    key `idx_state_next_attempt_at` (`state`, `next_attempt_at`),
+    key `idx_flow_id` (`flow_id`)
+) engine = innodb
+  default charset = utf8mb4
+  collate utf8mb4_bin;
+
+-- 14. 添加任务流归档表，已结束且超过保留时间的任务流连同任务、任务流事件一起归档后删除
+alter table async_flow
+    add key `idx_state_updated_at` (`state`, `updated_at`);
+
+create table if not exists `async_flow_archive`
+(
+    `id`              varchar(64) not null,
+    `name`            varchar(64) not null,
+    `state`           varchar(16) not null,
+    `tenant`          varchar(64) not null default '',
+    `app_code`        varchar(64) not null default '',
+    `detail`          json        not null,
+    `flow_created_at` timestamp   not null default current_timestamp,
+    `finished_at`     timestamp   not null default current_timestamp,
+    `archived_at`     timestamp   not null default current_timestamp,
+    primary key (`id`),
+    key `idx_name_finished_at` (`name`, `finished_at`),
+    key `idx_archived_at` (`archived_at`)
+) engine = innodb
+  default charset = utf8mb4
+  collate utf8mb4_bin;
+
+-- 15. 添加任务流幂等键表，同一应用使用相同幂等键重复创建任务流时返回首次创建的任务流，幂等键过期后删除
+create table if not exists `async_flow_idempotency`
+(
+    `id`              varchar(64)  not null,
+    `app_code`        varchar(64)  not null,
+    `idempotency_key` varchar(128) not null,
+    `payload_hash`    varchar(64)  not null,
+    `expired_at`      timestamp    not null default current_timestamp,
+    `created_at`      timestamp    not null default current_timestamp,
+    primary key (`id`),
+    unique key `idx_uk_app_code_idempotency_key` (`app_code`, `idempotency_key`),
+    key `idx_expired_at` (`expired_at`)
+) engine = innodb
+  default charset = utf8mb4
+  collate utf8mb4_bin;
+
+insert into id_generator(`resource`, `max_id`)
+values ('resource_tag', '0'),
+       ('biz_assign_rule', '0'),
+       ('biz_assign_record', '0'),
+       ('user_data_template', '0'),
+       ('user_data_template_version', '0'),
+       ('launch_template', '0'),
+       ('launch_template_version', '0'),
+       ('account_sync_history', '0'),
+       ('account_sync_quarantine', '0'),
+       ('res_change_history', '0'),
+       ('async_flow_schedule', '0'),
+       ('async_flow_schedule_run', '0'),
+       ('async_flow_event', '0');
+
+commit;